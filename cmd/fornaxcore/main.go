@@ -18,10 +18,12 @@ package main
 
 import (
 	"context"
+	"net/http"
 	"os"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	apiadmission "k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/server"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/apiserver-runtime/pkg/builder"
@@ -29,14 +31,28 @@ import (
 	// +kubebuilder:scaffold:resource-imports
 
 	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	fornaxv1beta2 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1beta2"
+	"centaurusinfra.io/fornax-serverless/pkg/config"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/admission"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/analytics"
 	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/application"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/auth"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/debug"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/event"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/extension"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/gateway"
 	grpc_server "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc/server"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/ha"
 	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/node"
 	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/nodemonitor"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/placement"
 	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/pod"
 	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/podscheduler"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/replay"
 	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/session"
+	"centaurusinfra.io/fornax-serverless/pkg/pki"
 	"centaurusinfra.io/fornax-serverless/pkg/store/factory"
+	"centaurusinfra.io/fornax-serverless/pkg/watchdog"
 )
 
 var (
@@ -50,8 +66,31 @@ func init() {
 func main() {
 	// initialize fornax resource memory store
 	ctx := context.Background()
+	if err := factory.InitFornaxStorageConfiguration(ctx, config.DefaultFornaxCoreStorageConfigFile); err != nil {
+		klog.Fatal(err)
+	}
+	if err := factory.InitRevisionGuard(config.DefaultFornaxCoreRevisionFile); err != nil {
+		klog.Fatal(err)
+	}
 	appStatusStore := factory.NewFornaxApplicationStatusStorage(ctx)
 	appSessionStore := factory.NewFornaxApplicationSessionStorage(ctx)
+	factory.NewFornaxQuotaStorage(ctx)
+	nodeOperationStore := factory.NewNodeOperationStorage(ctx)
+	nodeLeaseStore := factory.NewNodeLeaseStorage(ctx)
+	nodeConfigProfileStore := factory.NewNodeConfigProfileStorage(ctx)
+	sessionUsageStore := factory.NewSessionUsageStorage(ctx)
+	eventStore := factory.NewFornaxEventStorage(ctx)
+	eventRecorder := event.NewRecorder(ctx, eventStore)
+	eventRecorder.Run()
+	event.SetRecorder(eventRecorder)
+	extension.NewExtensionStorages(ctx)
+	conversionConfig, err := extension.LoadConversionWebhookConfiguration(config.DefaultFornaxCoreExtensionConversionConfigFile)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	if err := extension.SetConversionWebhooks(conversionConfig); err != nil {
+		klog.Fatal(err)
+	}
 
 	// new fornaxcore grpc grpcServer which implement node agent proxy
 	grpcServer := grpc_server.NewGrpcServer()
@@ -59,39 +98,180 @@ func main() {
 	// start internal managers and pod scheduler
 	podManager := pod.NewPodManager(ctx, grpcServer)
 	sessionManager := session.NewSessionManager(ctx, grpcServer, appSessionStore)
+	session.RegisterSessionValidators(podManager)
+	grpcServer.SetSessionWatchSource(appSessionStore, podManager)
 	nodeManager := node.NewNodeManager(ctx, grpcServer, podManager, sessionManager)
+	sessionUsageAggregator := session.NewSessionUsageAggregator(ctx, sessionManager, sessionUsageStore, nodeManager)
+	if err := factory.LoadAccessPartitions(config.DefaultFornaxCoreAccessPartitionConfigFile); err != nil {
+		klog.Fatal(err)
+	}
+	extenderConfig, err := podscheduler.LoadSchedulerExtenderConfiguration(config.DefaultFornaxCoreSchedulerExtenderConfigFile)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	extenders, err := podscheduler.NewGrpcSchedulerExtenders(extenderConfig)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	placementAuditLog, err := placement.NewAuditLog(config.DefaultFornaxCorePlacementAuditLogFile)
+	if err != nil {
+		klog.ErrorS(err, "Failed to create placement audit log, placement decisions are not recorded")
+	} else {
+		go placementAuditLog.Run(ctx)
+	}
+	recorderConfig, err := replay.LoadRecorderConfiguration(config.DefaultFornaxCoreEventRecorderConfigFile)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	if recorderConfig.Enabled {
+		recorder, err := replay.NewFileRecorder(recorderConfig.File)
+		if err != nil {
+			klog.Fatal(err)
+		}
+		klog.InfoS("Recording watch events and node messages", "file", recorderConfig.File)
+		replay.SetRecorder(recorder)
+		defer recorder.Close()
+	}
+	costConfig, err := podscheduler.LoadSchedulerCostConfiguration(config.DefaultFornaxCoreSchedulerCostConfigFile)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	nodeSortingMethod := podscheduler.NodeSortingMethodMoreMemory
+	if costConfig.Enabled {
+		nodeSortingMethod = podscheduler.NodeSortingMethodCostAware
+	}
 	podScheduler := podscheduler.NewPodScheduler(ctx, grpcServer, nodeManager, podManager,
 		&podscheduler.SchedulePolicy{
 			NumOfEvaluatedNodes: 100,
 			BackoffDuration:     10 * time.Second,
-			NodeSortingMethod:   podscheduler.NodeSortingMethodMoreMemory,
+			NodeSortingMethod:   nodeSortingMethod,
+			CostWeight:          costConfig.CostWeight,
+			Extenders:           extenders,
+			AuditLog:            placementAuditLog,
 		})
-	podScheduler.Run()
-	podManager.Run(podScheduler)
-	nodeManager.Run()
+	nodeOperationController := node.NewNodeOperationController(ctx, nodeManager, nodeOperationStore)
+	nodeLeaseConfig, err := node.LoadNodeLeaseConfiguration(config.DefaultFornaxCoreNodeLeaseConfigFile)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	nodeLeaseController := node.NewNodeLeaseController(ctx, nodeManager, nodeLeaseStore, nodeLeaseConfig)
+	nodeConfigProfileController := node.NewNodeConfigProfileController(ctx, nodeManager, nodeConfigProfileStore)
+	debugProxy := debug.NewDebugProxy(podManager, nodeManager)
+	fornaxv1.SetContainerLogsFunc(debugProxy.ContainerLogs)
+	fornaxv1.SetContainerStreamFunc(debugProxy.ContainerStream)
 
-	// start application manager at last as it require api server
-	klog.Info("starting application manager")
-	appManager := application.NewApplicationManager(ctx, podManager, sessionManager, appStatusStore)
-	appManager.Run(ctx)
+	secretBox, err := pki.LoadOrCreateSecretBox(config.DefaultFornaxCoreSecretKeyFile)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	fornaxv1.SetSecretSealFunc(secretBox.Seal)
+
+	appManager := application.NewApplicationManager(ctx, podManager, sessionManager, podScheduler, appStatusStore)
+	appManager.SetSecretBox(secretBox)
+	appManager.SetNodeManager(nodeManager)
+	appManager.SetPlacementAuditLog(placementAuditLog)
+	gatewayConfig, err := gateway.LoadSessionGatewayConfiguration(config.DefaultFornaxCoreSessionGatewayConfigFile)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	if gatewayConfig.Enabled {
+		klog.InfoS("Routing sessions through session gateway", "addresses", gatewayConfig.AdvertiseAddresses, "sni port", gatewayConfig.SNIPort)
+		sessionGateway := gateway.NewSessionGateway(gatewayConfig)
+		if err := sessionGateway.Start(); err != nil {
+			klog.Fatal(err)
+		}
+		appManager.SetSessionGateway(sessionGateway)
+	}
+	factory.SetSessionBackpressureFunc(appManager.SessionBackpressure)
+
+	watchdogConfig, err := watchdog.LoadConfiguration(config.DefaultFornaxCoreWatchdogConfigFile)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	wd := watchdog.New("fornaxcore", watchdogConfig)
+	factory.AddStoreWatchdogChecks(wd)
+	go wd.Run(ctx)
+	go factory.RunMemoryPressureCompaction(ctx, factory.DefaultMemoryPressureCheckInterval)
 
-	// start fornaxcore grpc server to listen nodes
-	klog.Info("starting fornaxcore grpc node agent server")
 	port := 18001
 	// we are using k8s api server, command line flags are only parsed when apiserver started
 	// TODO, parse flags before start api server and get certificates from command line flags,
 	certFile := ""
 	keyFile := ""
-	err := grpcServer.RunGrpcServer(ctx, nodemonitor.NewNodeMonitor(nodeManager), port, certFile, keyFile)
+	nodePKIConfig, err := grpc_server.LoadNodePKIConfiguration(config.DefaultFornaxCoreNodePKIConfigFile)
 	if err != nil {
 		klog.Fatal(err)
 	}
-	klog.Info("Fornaxcore grpc server started")
+	if nodePKIConfig.Enabled {
+		nodePKI, err := grpc_server.NewNodePKI(nodePKIConfig)
+		if err != nil {
+			klog.Fatal(err)
+		}
+		klog.InfoS("Node agents are required to use mtls", "ca", nodePKIConfig.CACertFile, "server names", nodePKIConfig.ServerNames)
+		// certificates are rotated on all replicas, so, a follower is ready to serve nodes and proxy debug requests after it become leader
+		nodePKI.Run(ctx)
+		grpcServer.SetNodePKI(nodePKI)
+		debugProxy.SetNodeCredentials(nodePKI.NodeTransportCredentials)
+	}
+	nodeVersionSkewConfig, err := nodemonitor.LoadNodeVersionSkewConfiguration(config.DefaultFornaxCoreNodeVersionSkewConfigFile)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	nodeMonitor := nodemonitor.NewNodeMonitor(nodeManager)
+	nodeMonitor.SetNodeVersionSkewConfiguration(nodeVersionSkewConfig)
+
+	// controllers and node agent grpc server only run on leader replica in ha mode, followers replicate leader memory stores
+	startControllers := func() {
+		sessionUsageAggregator.Run()
+		podScheduler.Run()
+		podManager.Run(podScheduler)
+		nodeManager.Run()
+		nodeOperationController.Run()
+		nodeLeaseController.Run()
+		nodeConfigProfileController.Run()
 
-	// TODO, wait for all known nodes are registered
+		// start application manager at last as it require api server
+		klog.Info("starting application manager")
+		appManager.Run(ctx)
+
+		// start fornaxcore grpc server to listen nodes
+		klog.Info("starting fornaxcore grpc node agent server")
+		if err := grpcServer.RunGrpcServer(ctx, nodeMonitor, port, certFile, keyFile); err != nil {
+			klog.Fatal(err)
+		}
+		klog.Info("Fornaxcore grpc server started")
+
+		// TODO, wait for all known nodes are registered
+	}
+	haConfig, err := ha.LoadHAConfiguration(config.DefaultFornaxCoreHAConfigFile)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	var elector *ha.Elector
+	if haConfig.Enabled {
+		elector = ha.NewElector(haConfig)
+		if err := elector.Run(ctx, startControllers); err != nil {
+			klog.Fatal(err)
+		}
+	} else {
+		startControllers()
+	}
 
 	// start api server to listen to clients
 	klog.Info("starting fornaxcore rest api server")
+	authConfig, err := auth.LoadAuthConfiguration(config.DefaultFornaxCoreAuthConfigFile)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	admissionConfig, err := admission.LoadAdmissionWebhookConfiguration(config.DefaultFornaxCoreAdmissionWebhookConfigFile)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	webhookAdmission, err := admission.NewWebhookAdmission(admissionConfig)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	apiUsage := analytics.NewAPIUsage()
 	// +kubebuilder:scaffold:resource-register
 	apiserver := builder.APIServer.
 		WithLocalDebugExtension().
@@ -100,16 +280,60 @@ func main() {
 			config.RESTOptionsGetter = &factory.FornaxRestOptionsFactory{
 				OptionsGetter: optionsGetter,
 			}
+			if err := auth.ApplyTo(authConfig, config); err != nil {
+				klog.Fatal(err)
+			}
+			buildHandlerChain := config.BuildHandlerChainFunc
+			config.BuildHandlerChainFunc = func(apiHandler http.Handler, c *server.Config) http.Handler {
+				if elector != nil {
+					apiHandler = ha.WithLeaderRequests(apiHandler, elector)
+				}
+				return buildHandlerChain(analytics.WithAPIUsage(apiHandler, apiUsage), c)
+			}
+			// admission plugins are disabled in standalone mode, webhooks still work
+			if len(admissionConfig.Webhooks) > 0 {
+				if config.AdmissionControl != nil {
+					config.AdmissionControl = apiadmission.NewChainHandler(config.AdmissionControl, webhookAdmission)
+				} else {
+					config.AdmissionControl = webhookAdmission
+				}
+			}
 			return config
 		}).
 		WithOptionsFns(func(options *builder.ServerOptions) *builder.ServerOptions {
 			return options
 		}).
 		WithServerFns(func(server *builder.GenericAPIServer) *builder.GenericAPIServer {
+			server.Handler.NonGoRestfulMux.Handle(application.ClusterStatusPath, application.NewClusterStatusHandler(appManager))
+			server.Handler.NonGoRestfulMux.Handle(application.ApplicationResumePath, application.NewApplicationResumeHandler(appManager))
+			server.Handler.NonGoRestfulMux.Handle(application.ApplicationReconcilePath, application.NewApplicationReconcileHandler(appManager))
+			server.Handler.NonGoRestfulMux.Handle(podscheduler.ScheduleExplainPath, podscheduler.NewScheduleExplainHandler(podScheduler, appSessionStore))
+			server.Handler.NonGoRestfulMux.Handle(factory.StoreHistoryPath, factory.NewStoreHistoryHandler())
+			server.Handler.NonGoRestfulMux.Handle(factory.StoreCompactionPath, factory.NewStoreCompactionHandler())
+			server.Handler.NonGoRestfulMux.Handle(placement.PlacementAuditPath, placement.NewAuditLogHandler(placementAuditLog))
+			server.Handler.NonGoRestfulMux.Handle(session.SessionEndpointsPath, session.NewSessionEndpointsHandler(appSessionStore))
+			server.Handler.NonGoRestfulMux.Handle(session.SessionBulkOperationPath, session.NewSessionBulkOperationHandler(appSessionStore))
+			server.Handler.NonGoRestfulMux.Handle(event.EventsPath, event.NewEventsHandler(eventStore))
+			server.Handler.NonGoRestfulMux.Handle(analytics.APIUsagePath, analytics.NewAPIUsageHandler(apiUsage))
+			server.Handler.NonGoRestfulMux.Handle(debug.NodeDiagnosticsPath, debug.NewNodeDiagnosticsHandler(debugProxy))
+			server.Handler.NonGoRestfulMux.Handle(nodemonitor.NodeVersionReportPath, nodeMonitor.NewNodeVersionReportHandler())
 			return server
 		}).
+		WithAdditionalSchemeInstallers(fornaxv1beta2.AddConversionFuncs, extension.AddConversionFuncs).
 		WithResource(&fornaxv1.Application{}).
-		WithResource(&fornaxv1.ApplicationSession{})
+		// v1beta2 applications share storage of v1 and are converted to v1, v1 must be registered first
+		WithResource(&fornaxv1beta2.Application{}).
+		WithResource(&fornaxv1.ApplicationSession{}).
+		WithResource(&fornaxv1.FornaxQuota{}).
+		WithResource(&fornaxv1.NodeOperation{}).
+		WithResource(&fornaxv1.NodeLease{}).
+		WithResource(&fornaxv1.NodeConfigProfile{}).
+		WithResource(&fornaxv1.SessionUsage{}).
+		WithResource(&fornaxv1.FornaxEvent{})
+	// extensions are served from same api server, storage version of a extension is registered first
+	for _, obj := range extension.Resources() {
+		apiserver = apiserver.WithResource(obj)
+	}
 	err = apiserver.Execute()
 	if err != nil {
 		klog.Fatal(err)
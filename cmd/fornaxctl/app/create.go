@@ -0,0 +1,220 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	fornaxclient "centaurusinfra.io/fornax-serverless/pkg/client/clientset/versioned"
+	"centaurusinfra.io/fornax-serverless/pkg/client/clientset/versioned/scheme"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+func newCreateCommand(o *options) *cobra.Command {
+	file, application, sessionData := "", "", ""
+	cmd := &cobra.Command{
+		Use:   "create -f FILE | create session NAME --application APP",
+		Short: "create applications and sessions from a yaml or json file, or create a session of a application",
+		Example: `  fornaxctl create -f echoserver.yaml
+  fornaxctl create session s1 --application echoserver --session-data hello`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := o.client()
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if len(file) > 0 {
+				if len(args) > 0 {
+					return fmt.Errorf("resource arguments can not be used with -f")
+				}
+				objs, err := readObjects(file)
+				if err != nil {
+					return err
+				}
+				for _, obj := range objs {
+					name, err := createObject(cmd.Context(), client, o.ns(), obj)
+					if err != nil {
+						return err
+					}
+					fmt.Fprintf(out, "%s created\n", name)
+				}
+				return nil
+			}
+
+			kind, names, err := parseResource(args)
+			if err != nil {
+				return err
+			}
+			if kind != sessionKind || len(names) != 1 || len(application) == 0 {
+				return fmt.Errorf("only a session can be created without -f, use create session NAME --application APP")
+			}
+			session := &fornaxv1.ApplicationSession{
+				ObjectMeta: metav1.ObjectMeta{Name: names[0], Namespace: o.ns()},
+				Spec:       fornaxv1.ApplicationSessionSpec{ApplicationName: application, SessionData: sessionData},
+			}
+			name, err := createObject(cmd.Context(), client, o.ns(), session)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "%s created\n", name)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&file, "filename", "f", "", "yaml or json file of applications and sessions, - read from stdin")
+	cmd.Flags().StringVar(&application, "application", "", "application of session")
+	cmd.Flags().StringVar(&sessionData, "session-data", "", "session data passed to application when session is open")
+	return cmd
+}
+
+func newDeleteCommand(o *options) *cobra.Command {
+	file := ""
+	cmd := &cobra.Command{
+		Use:   "delete (application|session)[/name] name... | delete -f FILE",
+		Short: "delete applications or sessions, a session is closed before it's deleted",
+		Example: `  fornaxctl delete session s1 s2
+  fornaxctl delete -f echoserver.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := o.client()
+			if err != nil {
+				return err
+			}
+			type target struct {
+				kind      resourceKind
+				namespace string
+				name      string
+			}
+			targets := []target{}
+			if len(file) > 0 {
+				objs, err := readObjects(file)
+				if err != nil {
+					return err
+				}
+				for _, obj := range objs {
+					meta := obj.(metav1.Object)
+					namespace := meta.GetNamespace()
+					if len(namespace) == 0 {
+						namespace = o.ns()
+					}
+					targets = append(targets, target{kind: resourceKind(obj.GetObjectKind().GroupVersionKind().Kind), namespace: namespace, name: meta.GetName()})
+				}
+			} else {
+				kind, names, err := parseResource(args)
+				if err != nil {
+					return err
+				}
+				if len(names) == 0 {
+					return fmt.Errorf("name of %s to delete is required", kind)
+				}
+				for _, name := range names {
+					targets = append(targets, target{kind: kind, namespace: o.ns(), name: name})
+				}
+			}
+			for _, t := range targets {
+				if err := deleteObject(cmd.Context(), client, t.kind, t.namespace, t.name); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s/%s deleted\n", t.kind, t.name)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&file, "filename", "f", "", "yaml or json file of applications and sessions to delete, - read from stdin")
+	return cmd
+}
+
+// readObjects decode applications and sessions in a file, a yaml file could have multiple documents
+func readObjects(file string) ([]runtime.Object, error) {
+	var in io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		in = f
+	}
+	objs := []runtime.Object{}
+	reader := yamlutil.NewYAMLReader(bufio.NewReader(in))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		obj, gvk, err := scheme.Codecs.UniversalDeserializer().Decode(doc, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		switch obj.(type) {
+		case *fornaxv1.Application, *fornaxv1.ApplicationSession:
+		default:
+			return nil, fmt.Errorf("%s: %s is not a application or session", file, gvk.Kind)
+		}
+		obj.GetObjectKind().SetGroupVersionKind(*gvk)
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// createObject create a application or session, namespace of object is used if it's set, it return kind/name of created object
+func createObject(ctx context.Context, client fornaxclient.Interface, namespace string, obj runtime.Object) (string, error) {
+	switch o := obj.(type) {
+	case *fornaxv1.Application:
+		if len(o.Namespace) > 0 {
+			namespace = o.Namespace
+		}
+		created, err := client.CoreV1().Applications(namespace).Create(ctx, o, metav1.CreateOptions{})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s/%s", applicationKind, created.Name), nil
+	case *fornaxv1.ApplicationSession:
+		if len(o.Namespace) > 0 {
+			namespace = o.Namespace
+		}
+		created, err := client.CoreV1().ApplicationSessions(namespace).Create(ctx, o, metav1.CreateOptions{})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s/%s", sessionKind, created.Name), nil
+	}
+	return "", fmt.Errorf("%T is not a application or session", obj)
+}
+
+func deleteObject(ctx context.Context, client fornaxclient.Interface, kind resourceKind, namespace, name string) error {
+	switch kind {
+	case applicationKind:
+		return client.CoreV1().Applications(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	case sessionKind:
+		return client.CoreV1().ApplicationSessions(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	}
+	return fmt.Errorf("unknown resource type %q", kind)
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/podscheduler"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newExplainCommand(o *options) *cobra.Command {
+	output := ""
+	cmd := &cobra.Command{
+		Use:   "explain SESSION",
+		Short: "explain why a session is pending or how its pod was scheduled, using last schedule decision of pod kept by fornaxcore",
+		Example: `  fornaxctl explain session/s1
+  fornaxctl explain s1 -o json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, name, err := parseSessionArg(args[0])
+			if err != nil {
+				return err
+			}
+			client, err := o.client()
+			if err != nil {
+				return err
+			}
+			data, err := client.CoreV1().RESTClient().Get().
+				AbsPath(podscheduler.ScheduleExplainPath).
+				Param("session", fmt.Sprintf("%s/%s", o.ns(), name)).
+				DoRaw(cmd.Context())
+			if err != nil {
+				if len(data) > 0 {
+					return fmt.Errorf("%s", strings.TrimSpace(string(data)))
+				}
+				return err
+			}
+			out := cmd.OutOrStdout()
+			switch output {
+			case "json":
+				_, err = fmt.Fprintln(out, strings.TrimSuffix(string(data), "\n"))
+				return err
+			case "":
+			default:
+				return fmt.Errorf("unknown output format %q, only json is supported", output)
+			}
+			explanation := &podscheduler.ScheduleExplanation{}
+			if err := json.Unmarshal(data, explanation); err != nil {
+				return err
+			}
+			describeExplanation(out, explanation, time.Now())
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "", "output format, json print explanation returned by fornaxcore")
+	return cmd
+}
+
+func describeExplanation(out io.Writer, e *podscheduler.ScheduleExplanation, now time.Time) {
+	tw := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	field := func(name string, format string, a ...interface{}) {
+		fmt.Fprintf(tw, "%s:\t%s\n", name, fmt.Sprintf(format, a...))
+	}
+	field("Session", "%s", e.Session)
+	if len(e.Pod) > 0 {
+		field("Pod", "%s", e.Pod)
+	}
+	field("Result", "%s", e.Result)
+	if len(e.Message) > 0 {
+		field("Message", "%s", e.Message)
+	}
+	if len(e.Node) > 0 {
+		field("Node", "%s", e.Node)
+	}
+	if !e.AttemptTime.IsZero() {
+		field("Attempt", "%d, %s (%s ago)", e.Attempt, e.AttemptTime.Format(time.RFC3339), age(metav1.Time{Time: e.AttemptTime}, now))
+		field("Evaluated Nodes", "%d", e.EvaluatedNodes)
+	}
+	tw.Flush()
+
+	if len(e.RejectedReasons) > 0 {
+		fmt.Fprintln(out, "Rejected Reasons:")
+		reasons := []string{}
+		for k := range e.RejectedReasons {
+			reasons = append(reasons, k)
+		}
+		// most common reason first
+		sort.Slice(reasons, func(i, j int) bool {
+			if e.RejectedReasons[reasons[i]] != e.RejectedReasons[reasons[j]] {
+				return e.RejectedReasons[reasons[i]] > e.RejectedReasons[reasons[j]]
+			}
+			return reasons[i] < reasons[j]
+		})
+		tw = tabwriter.NewWriter(out, 0, 8, 3, ' ', 0)
+		for _, v := range reasons {
+			fmt.Fprintf(tw, "  %s\t%d node(s)\n", v, e.RejectedReasons[v])
+		}
+		tw.Flush()
+	}
+
+	if len(e.Nodes) > 0 {
+		fmt.Fprintln(out, "Nodes:")
+		tw = tabwriter.NewWriter(out, 0, 8, 3, ' ', 0)
+		fmt.Fprintln(tw, "  NODE\tCPU\tMEMORY\tRESULT\tSCORE\tEXTENDER SCORE\tREASON")
+		for _, v := range e.Nodes {
+			result := "Accepted"
+			if v.Rejected {
+				result = "Rejected"
+			} else if v.Selected {
+				result = "Selected"
+			}
+			reason := v.Reason
+			if len(reason) == 0 {
+				reason = "<none>"
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%d\t%d\t%s\n", v.Node, quantity(v.Allocatable, v1.ResourceCPU), quantity(v.Allocatable, v1.ResourceMemory),
+				result, v.Score, v.ExtenderScore, reason)
+		}
+		tw.Flush()
+	}
+}
+
+func quantity(resources v1.ResourceList, name v1.ResourceName) string {
+	if q, found := resources[name]; found {
+		return q.String()
+	}
+	return "<unknown>"
+}
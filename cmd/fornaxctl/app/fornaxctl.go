@@ -0,0 +1,136 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	fornaxclient "centaurusinfra.io/fornax-serverless/pkg/client/clientset/versioned"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	FornaxCtl = "fornaxctl"
+	// fornax tools use kubeconfig in working dir when KUBECONFIG is not set
+	DefaultKubeConfigFile = "kubeconfig"
+)
+
+type resourceKind string
+
+const (
+	applicationKind resourceKind = "Application"
+	sessionKind     resourceKind = "ApplicationSession"
+)
+
+var resourceAliases = map[string]resourceKind{
+	"application":         applicationKind,
+	"applications":        applicationKind,
+	"app":                 applicationKind,
+	"apps":                applicationKind,
+	"applicationsession":  sessionKind,
+	"applicationsessions": sessionKind,
+	"session":             sessionKind,
+	"sessions":            sessionKind,
+}
+
+// options are global flags of all commands
+type options struct {
+	kubeConfig string
+	namespace  string
+}
+
+func (o *options) restConfig() (*rest.Config, error) {
+	return o.clientConfig().ClientConfig()
+}
+
+func (o *options) clientConfig() clientcmd.ClientConfig {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = o.kubeConfig
+	if len(rules.ExplicitPath) == 0 && len(os.Getenv(clientcmd.RecommendedConfigPathEnvVar)) == 0 {
+		if _, err := os.Stat(DefaultKubeConfigFile); err == nil {
+			rules.ExplicitPath = DefaultKubeConfigFile
+		}
+	}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{})
+}
+
+func (o *options) client() (*fornaxclient.Clientset, error) {
+	config, err := o.restConfig()
+	if err != nil {
+		return nil, err
+	}
+	return fornaxclient.NewForConfig(config)
+}
+
+// ns return namespace flag, or namespace of current kubeconfig context if flag is not set
+func (o *options) ns() string {
+	if len(o.namespace) > 0 {
+		return o.namespace
+	}
+	if ns, _, err := o.clientConfig().Namespace(); err == nil && len(ns) > 0 {
+		return ns
+	}
+	return "default"
+}
+
+// parseResource parse a resource argument, it's either a resource type, or type/name when name is not a separate argument
+func parseResource(args []string) (kind resourceKind, names []string, err error) {
+	if len(args) == 0 {
+		return "", nil, fmt.Errorf("resource type is required, one of application or session")
+	}
+	resource := args[0]
+	names = args[1:]
+	if parts := strings.SplitN(resource, "/", 2); len(parts) == 2 {
+		resource = parts[0]
+		names = append([]string{parts[1]}, names...)
+	}
+	kind, found := resourceAliases[strings.ToLower(resource)]
+	if !found {
+		return "", nil, fmt.Errorf("unknown resource type %q, one of application or session", resource)
+	}
+	return kind, names, nil
+}
+
+func NewCommand() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:           FornaxCtl,
+		Short:         "fornaxctl manage fornax applications and sessions",
+		Long:          `fornaxctl get, describe, create and delete fornax applications and sessions, read logs of session containers, execute command in them, wait for resource conditions, explain schedule decisions of sessions and collect a support bundle for bug reports`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.PersistentFlags().StringVar(&o.kubeConfig, "kubeconfig", "", "path of kubeconfig file of fornaxcore, default is KUBECONFIG or ./kubeconfig")
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "", "namespace of resources, default is namespace of kubeconfig context")
+
+	cmd.AddCommand(
+		newGetCommand(o),
+		newDescribeCommand(o),
+		newCreateCommand(o),
+		newDeleteCommand(o),
+		newLogsCommand(o),
+		newExecCommand(o),
+		newWaitCommand(o),
+		newExplainCommand(o),
+		newSupportBundleCommand(o),
+	)
+	return cmd
+}
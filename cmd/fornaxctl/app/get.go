@@ -0,0 +1,350 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	fornaxclient "centaurusinfra.io/fornax-serverless/pkg/client/clientset/versioned"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/event"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"sigs.k8s.io/yaml"
+)
+
+func newGetCommand(o *options) *cobra.Command {
+	output := ""
+	cmd := &cobra.Command{
+		Use:   "get (application|session)[/name] [name...]",
+		Short: "list applications or sessions, or get them by name",
+		Example: `  fornaxctl get applications
+  fornaxctl get session s1 -o yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kind, names, err := parseResource(args)
+			if err != nil {
+				return err
+			}
+			client, err := o.client()
+			if err != nil {
+				return err
+			}
+			objs, err := getObjects(cmd.Context(), client, o.ns(), kind, names)
+			if err != nil {
+				return err
+			}
+			return printObjects(cmd.OutOrStdout(), kind, objs, output, time.Now())
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "", "output format, one of yaml, json or name, default is a table")
+	return cmd
+}
+
+func newDescribeCommand(o *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "describe (application|session)[/name] [name...]",
+		Short:   "show details and recent events of applications or sessions",
+		Example: `  fornaxctl describe session s1`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kind, names, err := parseResource(args)
+			if err != nil {
+				return err
+			}
+			client, err := o.client()
+			if err != nil {
+				return err
+			}
+			objs, err := getObjects(cmd.Context(), client, o.ns(), kind, names)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			for i, obj := range objs {
+				if i > 0 {
+					fmt.Fprintln(out)
+				}
+				meta := obj.(metav1.Object)
+				events, err := getEvents(cmd.Context(), client, kind, meta.GetNamespace(), meta.GetName())
+				describeObject(out, obj, events, err, time.Now())
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+// getObjects get named objects, or all objects in namespace if no name is given, returned objects have type meta set
+func getObjects(ctx context.Context, client fornaxclient.Interface, namespace string, kind resourceKind, names []string) ([]runtime.Object, error) {
+	objs := []runtime.Object{}
+	switch kind {
+	case applicationKind:
+		apps := client.CoreV1().Applications(namespace)
+		if len(names) == 0 {
+			list, err := apps.List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			for i := range list.Items {
+				objs = append(objs, &list.Items[i])
+			}
+		}
+		for _, name := range names {
+			app, err := apps.Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return nil, err
+			}
+			objs = append(objs, app)
+		}
+	case sessionKind:
+		sessions := client.CoreV1().ApplicationSessions(namespace)
+		if len(names) == 0 {
+			list, err := sessions.List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			for i := range list.Items {
+				objs = append(objs, &list.Items[i])
+			}
+		}
+		for _, name := range names {
+			session, err := sessions.Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return nil, err
+			}
+			objs = append(objs, session)
+		}
+	}
+	for _, obj := range objs {
+		obj.GetObjectKind().SetGroupVersionKind(fornaxv1.SchemeGroupVersion.WithKind(string(kind)))
+	}
+	return objs, nil
+}
+
+func printObjects(out io.Writer, kind resourceKind, objs []runtime.Object, output string, now time.Time) error {
+	switch output {
+	case "json", "yaml":
+		var obj interface{} = &metav1.List{TypeMeta: metav1.TypeMeta{Kind: "List", APIVersion: "v1"}, Items: []runtime.RawExtension{}}
+		if len(objs) == 1 {
+			obj = objs[0]
+		} else {
+			for _, v := range objs {
+				raw, err := json.Marshal(v)
+				if err != nil {
+					return err
+				}
+				obj.(*metav1.List).Items = append(obj.(*metav1.List).Items, runtime.RawExtension{Raw: raw})
+			}
+		}
+		data, err := json.MarshalIndent(obj, "", "  ")
+		if err != nil {
+			return err
+		}
+		if output == "yaml" {
+			if data, err = yaml.JSONToYAML(data); err != nil {
+				return err
+			}
+		}
+		_, err = fmt.Fprintln(out, strings.TrimSuffix(string(data), "\n"))
+		return err
+	case "name":
+		for _, obj := range objs {
+			fmt.Fprintf(out, "%s/%s\n", strings.ToLower(string(kind)), obj.(metav1.Object).GetName())
+		}
+		return nil
+	case "":
+	default:
+		return fmt.Errorf("unknown output format %q, one of yaml, json or name", output)
+	}
+
+	if len(objs) == 0 {
+		fmt.Fprintln(out, "No resources found")
+		return nil
+	}
+	tw := tabwriter.NewWriter(out, 0, 8, 3, ' ', 0)
+	defer tw.Flush()
+	switch kind {
+	case applicationKind:
+		fmt.Fprintln(tw, "NAME\tDESIRED\tTOTAL\tIDLE\tALLOCATED\tPENDING\tSTATUS\tAGE")
+		for _, obj := range objs {
+			app := obj.(*fornaxv1.Application)
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\t%s\t%s\n", app.Name, app.Status.DesiredInstances, app.Status.TotalInstances, app.Status.IdleInstances,
+				app.Status.AllocatedInstances, app.Status.PendingInstances, applicationStatus(app), age(app.CreationTimestamp, now))
+		}
+	case sessionKind:
+		fmt.Fprintln(tw, "NAME\tAPPLICATION\tSTATUS\tPOD\tCLIENTS\tENDPOINT\tAGE")
+		for _, obj := range objs {
+			session := obj.(*fornaxv1.ApplicationSession)
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", session.Name, session.Spec.ApplicationName, sessionStatus(session), sessionPod(session),
+				session.Status.ClientSessionCount, sessionEndpoint(session), age(session.CreationTimestamp, now))
+		}
+	}
+	return nil
+}
+
+func age(t metav1.Time, now time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(now.Sub(t.Time))
+}
+
+func applicationStatus(app *fornaxv1.Application) string {
+	if app.DeletionTimestamp != nil {
+		return "Deleting"
+	}
+	if app.Status.Suspended {
+		return "Suspended"
+	}
+	if len(app.Status.DeploymentStatus) == 0 {
+		return "<none>"
+	}
+	return string(app.Status.DeploymentStatus)
+}
+
+func sessionStatus(session *fornaxv1.ApplicationSession) string {
+	if session.DeletionTimestamp != nil && !isSessionTerminal(session) {
+		return "Closing"
+	}
+	if len(session.Status.SessionStatus) == 0 {
+		return string(fornaxv1.SessionStatusPending)
+	}
+	return string(session.Status.SessionStatus)
+}
+
+func isSessionTerminal(session *fornaxv1.ApplicationSession) bool {
+	switch session.Status.SessionStatus {
+	case fornaxv1.SessionStatusClosed, fornaxv1.SessionStatusTimeout, fornaxv1.SessionStatusFailed:
+		return true
+	}
+	return false
+}
+
+func sessionPod(session *fornaxv1.ApplicationSession) string {
+	if session.Status.PodReference == nil || len(session.Status.PodReference.Name) == 0 {
+		return "<none>"
+	}
+	return session.Status.PodReference.Name
+}
+
+func sessionEndpoint(session *fornaxv1.ApplicationSession) string {
+	if len(session.Status.AccessEndPoints) == 0 {
+		return "<none>"
+	}
+	endpoints := []string{}
+	for _, v := range session.Status.AccessEndPoints {
+		endpoints = append(endpoints, fmt.Sprintf("%s:%d", v.IPAddress, v.Port))
+	}
+	return strings.Join(endpoints, ",")
+}
+
+// getEvents read recent events of object from fornaxcore events endpoint
+func getEvents(ctx context.Context, client fornaxclient.Interface, kind resourceKind, namespace, name string) ([]fornaxv1.FornaxEvent, error) {
+	data, err := client.CoreV1().RESTClient().Get().
+		AbsPath(event.EventsPath).
+		Param("kind", string(kind)).
+		Param("object", fmt.Sprintf("%s/%s", namespace, name)).
+		Param("output", "json").
+		DoRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list := &fornaxv1.FornaxEventList{}
+	if err := json.Unmarshal(data, list); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func describeObject(out io.Writer, obj runtime.Object, events []fornaxv1.FornaxEvent, eventsErr error, now time.Time) {
+	tw := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	field := func(name string, format string, a ...interface{}) {
+		fmt.Fprintf(tw, "%s:\t%s\n", name, fmt.Sprintf(format, a...))
+	}
+	meta := obj.(metav1.Object)
+	field("Name", "%s", meta.GetName())
+	field("Namespace", "%s", meta.GetNamespace())
+	field("Labels", "%s", describeLabels(meta.GetLabels()))
+	field("Created", "%s (%s ago)", meta.GetCreationTimestamp().Format(time.RFC3339), age(meta.GetCreationTimestamp(), now))
+	switch o := obj.(type) {
+	case *fornaxv1.Application:
+		field("Status", "%s", applicationStatus(o))
+		for _, c := range o.Spec.Containers {
+			field("Container", "%s (%s)", c.Name, c.Image)
+		}
+		field("Node Session Service", "%t", o.Spec.UsingNodeSessionService)
+		p := o.Spec.ScalingPolicy
+		field("Scaling Policy", "%s, min %d, max %d, burst %d", p.ScalingPolicyType, p.MinimumInstance, p.MaximumInstance, p.Burst)
+		field("Instances", "%d desired, %d total, %d idle, %d allocated, %d pending, %d deleting", o.Status.DesiredInstances, o.Status.TotalInstances,
+			o.Status.IdleInstances, o.Status.AllocatedInstances, o.Status.PendingInstances, o.Status.DeletingInstances)
+		if !o.Status.DeploymentTime.IsZero() {
+			field("Deployed", "%s", o.Status.DeploymentTime.Format(time.RFC3339))
+		}
+	case *fornaxv1.ApplicationSession:
+		field("Application", "%s", o.Spec.ApplicationName)
+		field("Status", "%s", sessionStatus(o))
+		if len(o.Status.Reason) > 0 || len(o.Status.Message) > 0 {
+			field("Reason", "%s %s", o.Status.Reason, o.Status.Message)
+		}
+		field("Pod", "%s", sessionPod(o))
+		field("Endpoints", "%s", sessionEndpoint(o))
+		if len(o.Status.HealthStatus) > 0 {
+			field("Health", "%s", o.Status.HealthStatus)
+		}
+		if o.Status.AvailableTime != nil {
+			field("Available", "%s", o.Status.AvailableTime.Format(time.RFC3339))
+		}
+		if o.Status.CloseTime != nil {
+			field("Closed", "%s", o.Status.CloseTime.Format(time.RFC3339))
+		}
+		field("Clients", "%d", o.Status.ClientSessionCount)
+		for _, c := range o.Status.ClientSessions {
+			if c.LeaveTime == nil {
+				field("  "+c.Name, "%s", c.SourceAddress)
+			}
+		}
+		if o.Status.Migration != nil {
+			field("Migration", "%s, from %s to %s", o.Status.Migration.Phase, o.Status.Migration.SourcePod, o.Status.Migration.TargetPod)
+		}
+	}
+	tw.Flush()
+	if eventsErr != nil {
+		fmt.Fprintf(out, "Events:     <unknown, %v>\n", eventsErr)
+		return
+	}
+	fmt.Fprint(out, event.DescribeEvents(events, now))
+}
+
+func describeLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "<none>"
+	}
+	pairs := []string{}
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
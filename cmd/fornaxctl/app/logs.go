@@ -0,0 +1,193 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8sscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+func newLogsCommand(o *options) *cobra.Command {
+	logOptions := &corev1.PodLogOptions{}
+	tail, since := int64(-1), time.Duration(0)
+	cmd := &cobra.Command{
+		Use:   "logs SESSION",
+		Short: "print container logs of pod a session is on, logs are read by node agent and proxied through fornaxcore",
+		Example: `  fornaxctl logs s1
+  fornaxctl logs s1 -c echoserver -f --tail 100`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, name, err := parseSessionArg(args[0])
+			if err != nil {
+				return err
+			}
+			client, err := o.client()
+			if err != nil {
+				return err
+			}
+			if tail >= 0 {
+				logOptions.TailLines = &tail
+			}
+			if since > 0 {
+				seconds := int64(since.Seconds())
+				logOptions.SinceSeconds = &seconds
+			}
+			stream, err := client.CoreV1().ApplicationSessions(o.ns()).GetLogs(name, logOptions).Stream(cmd.Context())
+			if err != nil {
+				return subresourceError(err, "logs")
+			}
+			defer stream.Close()
+			_, err = io.Copy(cmd.OutOrStdout(), stream)
+			return err
+		},
+	}
+	cmd.Flags().StringVarP(&logOptions.Container, "container", "c", "", "container name, default is first container of application")
+	cmd.Flags().BoolVarP(&logOptions.Follow, "follow", "f", false, "stream new logs until session is closed")
+	cmd.Flags().Int64Var(&tail, "tail", tail, "number of recent lines to print, -1 print all lines")
+	cmd.Flags().DurationVar(&since, "since", since, "only print logs newer than a duration, e.g. 5m")
+	cmd.Flags().BoolVar(&logOptions.Timestamps, "timestamps", false, "prefix each line with its timestamp")
+	cmd.Flags().BoolVarP(&logOptions.Previous, "previous", "p", false, "print logs of previous container instance if container restarted")
+	return cmd
+}
+
+func newExecCommand(o *options) *cobra.Command {
+	execOptions := &corev1.PodExecOptions{Stdout: true, Stderr: true}
+	cmd := &cobra.Command{
+		Use:   "exec SESSION [-c CONTAINER] [-i] [-t] -- COMMAND [args...]",
+		Short: "execute a command in container of pod a session is on, stream is proxied through fornaxcore to node agent",
+		Example: `  fornaxctl exec s1 -- cat /etc/hosts
+  fornaxctl exec -it s1 -- sh`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.ArgsLenAtDash() != 1 {
+				return fmt.Errorf("session and command must be separated by --, e.g. fornaxctl exec s1 -- sh")
+			}
+			_, name, err := parseSessionArg(args[0])
+			if err != nil {
+				return err
+			}
+			config, err := o.restConfig()
+			if err != nil {
+				return err
+			}
+			client, err := o.client()
+			if err != nil {
+				return err
+			}
+			execOptions.Command = args[1:]
+
+			stdin, stdout, stderr := cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr()
+			var sizeQueue remotecommand.TerminalSizeQueue
+			if execOptions.TTY {
+				fd := int(os.Stdin.Fd())
+				if !execOptions.Stdin || !term.IsTerminal(fd) {
+					return fmt.Errorf("-t requires -i and stdin to be a terminal")
+				}
+				state, err := term.MakeRaw(fd)
+				if err != nil {
+					return err
+				}
+				defer term.Restore(fd, state)
+				// tty combine stderr into stdout
+				execOptions.Stderr, stderr = false, nil
+				sizeQueue = newTerminalSize(int(os.Stdout.Fd()))
+			}
+			if !execOptions.Stdin {
+				stdin = nil
+			}
+
+			req := client.CoreV1().RESTClient().Post().
+				Namespace(o.ns()).
+				Resource("applicationsessions").
+				Name(name).
+				SubResource("exec").
+				VersionedParams(execOptions, k8sscheme.ParameterCodec)
+			executor, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+			if err != nil {
+				return err
+			}
+			err = executor.Stream(remotecommand.StreamOptions{
+				Stdin:             stdin,
+				Stdout:            stdout,
+				Stderr:            stderr,
+				Tty:               execOptions.TTY,
+				TerminalSizeQueue: sizeQueue,
+			})
+			return subresourceError(err, "exec")
+		},
+	}
+	cmd.Flags().StringVarP(&execOptions.Container, "container", "c", "", "container name, default is first container of application")
+	cmd.Flags().BoolVarP(&execOptions.Stdin, "stdin", "i", false, "pass stdin to command")
+	cmd.Flags().BoolVarP(&execOptions.TTY, "tty", "t", false, "allocate a tty for command, requires -i")
+	return cmd
+}
+
+// parseSessionArg accept a session name or session/name
+func parseSessionArg(arg string) (resourceKind, string, error) {
+	kind, names, err := parseResource([]string{arg})
+	if err != nil || len(names) == 0 {
+		// a bare session name
+		return sessionKind, arg, nil
+	}
+	if kind != sessionKind {
+		return "", "", fmt.Errorf("%s is not a session", arg)
+	}
+	return kind, names[0], nil
+}
+
+// subresourceError explain a not found error of subresource served by a fornaxcore without it
+func subresourceError(err error, subresource string) error {
+	if err != nil && apierrors.IsNotFound(err) {
+		if status, ok := err.(apierrors.APIStatus); ok && status.Status().Details != nil && len(status.Status().Details.Name) > 0 {
+			return err
+		}
+		return fmt.Errorf("session %s is not served by fornaxcore, %v", subresource, err)
+	}
+	return err
+}
+
+// terminalSize send initial size of local terminal, terminal resize is not followed
+type terminalSize struct {
+	sent bool
+	fd   int
+}
+
+func newTerminalSize(fd int) *terminalSize {
+	return &terminalSize{fd: fd}
+}
+
+func (t *terminalSize) Next() *remotecommand.TerminalSize {
+	if t.sent {
+		// block until stream is done, executor stop reading size queue
+		select {}
+	}
+	t.sent = true
+	width, height, err := term.GetSize(t.fd)
+	if err != nil {
+		return nil
+	}
+	return &remotecommand.TerminalSize{Width: uint16(width), Height: uint16(height)}
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	fornaxclient "centaurusinfra.io/fornax-serverless/pkg/client/clientset/versioned"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/application"
+	fornaxdebug "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/debug"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/event"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/nodemonitor"
+	debuggrpc "centaurusinfra.io/fornax-serverless/pkg/nodeagent/debugservice/grpc"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// value of sensitive fields in support bundle
+	redacted = "<redacted>"
+	// annotation of kubectl apply which carry whole object
+	lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// supportBundle collect files into a gzipped tar archive, collection failures are recorded in bundle and do not fail bundle
+type supportBundle struct {
+	dir    string
+	files  map[string][]byte
+	order  []string
+	errors []string
+}
+
+func (b *supportBundle) add(name string, data []byte) {
+	if _, found := b.files[name]; !found {
+		b.order = append(b.order, name)
+	}
+	b.files[name] = data
+}
+
+func (b *supportBundle) collect(name string, f func() ([]byte, error)) {
+	data, err := f()
+	if err != nil {
+		b.errors = append(b.errors, fmt.Sprintf("%s: %v", name, err))
+		return
+	}
+	b.add(name, data)
+}
+
+func (b *supportBundle) write(file string, now time.Time) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	for _, name := range b.order {
+		data := b.files[name]
+		if err := tw.WriteHeader(&tar.Header{Name: path.Join(b.dir, name), Mode: 0644, Size: int64(len(data)), ModTime: now}); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func newSupportBundleCommand(o *options) *cobra.Command {
+	output := ""
+	eventsLimit := event.DefaultRecentEventsLimit
+	nodeGoroutines := true
+	cmd := &cobra.Command{
+		Use:   "support-bundle",
+		Short: "collect fornaxcore and node agent diagnostics into a archive to attach to a bug report",
+		Long: `collect fornaxcore and node agent diagnostics into a gzipped tar archive, it include
+  applications and sessions, env values, config data, secret data and session data are redacted
+  recent events, cluster status, metrics snapshot, versions and goroutine dump of fornaxcore
+  process, runtime and pod states, versions and goroutine dumps of node agents
+applications and sessions of all namespaces are collected unless namespace is set, a part that failed is listed in errors.txt`,
+		Example: `  fornaxctl support-bundle
+  fornaxctl support-bundle -o bundle.tar.gz -n team1`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := o.client()
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+			name := fmt.Sprintf("fornax-support-bundle-%s", now.Format("20060102-150405"))
+			if len(output) == 0 {
+				output = name + ".tar.gz"
+			}
+			bundle := collectSupportBundle(cmd.Context(), client, o.namespace, name, eventsLimit, nodeGoroutines)
+			for _, e := range bundle.errors {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: failed to collect %s\n", e)
+			}
+			if err := bundle.write(output, now); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "support bundle written to %s\n", output)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "", "archive file, default fornax-support-bundle-<time>.tar.gz in working dir")
+	cmd.Flags().IntVar(&eventsLimit, "events", eventsLimit, "number of recent events to collect")
+	cmd.Flags().BoolVar(&nodeGoroutines, "node-goroutines", nodeGoroutines, "collect goroutine dumps of node agents")
+	return cmd
+}
+
+// collectSupportBundle collect every part of bundle, namespace empty mean all namespaces
+func collectSupportBundle(ctx context.Context, client fornaxclient.Interface, namespace, dir string, eventsLimit int, nodeGoroutines bool) *supportBundle {
+	b := &supportBundle{dir: dir, files: map[string][]byte{}}
+	raw := func(p string, params map[string]string) func() ([]byte, error) {
+		return func() ([]byte, error) {
+			request := client.CoreV1().RESTClient().Get().AbsPath(p)
+			for k, v := range params {
+				request = request.Param(k, v)
+			}
+			return request.DoRaw(ctx)
+		}
+	}
+
+	b.collect("version.json", func() ([]byte, error) {
+		versions := map[string]interface{}{}
+		server, err := client.Discovery().ServerVersion()
+		if err != nil {
+			return nil, err
+		}
+		versions["fornaxcore"] = server
+		if info, ok := debug.ReadBuildInfo(); ok {
+			versions["fornaxctl"] = map[string]string{"version": info.Main.Version, "goVersion": info.GoVersion}
+		}
+		return json.MarshalIndent(versions, "", "  ")
+	})
+	for _, kind := range []resourceKind{applicationKind, sessionKind} {
+		kind := kind
+		b.collect(fmt.Sprintf("%ss.yaml", strings.ToLower(string(kind))), func() ([]byte, error) {
+			objs, err := getObjects(ctx, client, namespace, kind, nil)
+			if err != nil {
+				return nil, err
+			}
+			for _, obj := range objs {
+				sanitizeObject(obj)
+			}
+			buf := &bytes.Buffer{}
+			err = printObjects(buf, kind, objs, "yaml", time.Now())
+			return buf.Bytes(), err
+		})
+	}
+	b.collect("events.json", raw(event.EventsPath, map[string]string{"output": "json", "limit": strconv.Itoa(eventsLimit)}))
+	b.collect("cluster-status.json", raw(application.ClusterStatusPath, nil))
+	b.collect("nodes/versions.json", raw(nodemonitor.NodeVersionReportPath, nil))
+	b.collect("fornaxcore/metrics.txt", raw("/metrics", nil))
+	b.collect("fornaxcore/goroutines.txt", raw("/debug/pprof/goroutine", map[string]string{"debug": "2"}))
+
+	data, err := raw(fornaxdebug.NodeDiagnosticsPath, map[string]string{"goroutines": strconv.FormatBool(nodeGoroutines)})()
+	if err != nil {
+		b.errors = append(b.errors, fmt.Sprintf("node diagnostics: %v", err))
+	} else {
+		b.collectNodeDiagnostics(data)
+	}
+
+	if len(b.errors) > 0 {
+		errs := bytes.Buffer{}
+		for _, e := range b.errors {
+			fmt.Fprintln(&errs, e)
+		}
+		b.add("errors.txt", errs.Bytes())
+	}
+	return b
+}
+
+// collectNodeDiagnostics split goroutine dumps out of node diagnostics, so, they can be read as text files
+func (b *supportBundle) collectNodeDiagnostics(data []byte) {
+	nodes := []*fornaxdebug.NodeDiagnostics{}
+	if err := json.Unmarshal(data, &nodes); err != nil {
+		b.errors = append(b.errors, fmt.Sprintf("node diagnostics: %v", err))
+		return
+	}
+	for _, node := range nodes {
+		if len(node.Diagnostics) == 0 {
+			continue
+		}
+		diagnostics := &debuggrpc.Diagnostics{}
+		if err := protojson.Unmarshal(node.Diagnostics, diagnostics); err != nil {
+			b.errors = append(b.errors, fmt.Sprintf("node %s diagnostics: %v", node.Node, err))
+			continue
+		}
+		if len(diagnostics.GoroutineDump) > 0 {
+			b.add(fmt.Sprintf("nodes/%s/goroutines.txt", node.Node), []byte(diagnostics.GoroutineDump))
+			diagnostics.GoroutineDump = ""
+		}
+		node.Diagnostics, _ = protojson.Marshal(diagnostics)
+	}
+	b.collect("nodes/diagnostics.json", func() ([]byte, error) {
+		return json.MarshalIndent(nodes, "", "  ")
+	})
+}
+
+// sanitizeObject redact values which may be credentials of users, keys of maps are kept as they help debugging
+func sanitizeObject(obj runtime.Object) {
+	switch o := obj.(type) {
+	case *fornaxv1.Application:
+		o.ManagedFields = nil
+		delete(o.Annotations, lastAppliedAnnotation)
+		for i := range o.Spec.Containers {
+			for j := range o.Spec.Containers[i].Env {
+				if len(o.Spec.Containers[i].Env[j].Value) > 0 {
+					o.Spec.Containers[i].Env[j].Value = redacted
+				}
+			}
+		}
+		for k := range o.Spec.ConfigData {
+			o.Spec.ConfigData[k] = redacted
+		}
+		if o.Spec.Secret != nil {
+			for k := range o.Spec.Secret.Data {
+				o.Spec.Secret.Data[k] = []byte(redacted)
+			}
+		}
+	case *fornaxv1.ApplicationSession:
+		o.ManagedFields = nil
+		delete(o.Annotations, lastAppliedAnnotation)
+		if len(o.Spec.SessionData) > 0 {
+			o.Spec.SessionData = redacted
+		}
+	}
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	fornaxclient "centaurusinfra.io/fornax-serverless/pkg/client/clientset/versioned"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// errNeverMet is returned by a condition check when object reached a state from which condition can not be met anymore
+var errNeverMet = errors.New("condition will never be met")
+
+// waitCondition is a parsed --for expression
+type waitCondition struct {
+	expr    string
+	deleted bool
+	check   func(obj runtime.Object) (bool, error)
+}
+
+// sessionConditions are derived from session status, session status has no condition list
+var sessionConditions = map[string]func(session *fornaxv1.ApplicationSession) bool{
+	"open": func(session *fornaxv1.ApplicationSession) bool {
+		status := sessionStatus(session)
+		return status == string(fornaxv1.SessionStatusAvailable) || status == string(fornaxv1.SessionStatusInUse)
+	},
+	"terminated": isSessionTerminal,
+	"bound": func(session *fornaxv1.ApplicationSession) bool {
+		return len(sessionPod(session)) > 0
+	},
+}
+
+func init() {
+	for _, v := range []fornaxv1.SessionStatus{fornaxv1.SessionStatusPending, fornaxv1.SessionStatusStarting, fornaxv1.SessionStatusAvailable,
+		fornaxv1.SessionStatusInUse, fornaxv1.SessionStatusClosing, fornaxv1.SessionStatusClosed, fornaxv1.SessionStatusTimeout, fornaxv1.SessionStatusFailed} {
+		status := string(v)
+		sessionConditions["session"+strings.ToLower(status)] = func(session *fornaxv1.ApplicationSession) bool {
+			return sessionStatus(session) == status
+		}
+	}
+}
+
+// applicationConditions are derived from application status
+var applicationConditions = map[string]func(app *fornaxv1.Application) bool{
+	"deployed": func(app *fornaxv1.Application) bool {
+		return app.Status.DeploymentStatus == fornaxv1.DeploymentStatusSuccess
+	},
+	"ready": func(app *fornaxv1.Application) bool {
+		return app.Status.DeploymentStatus == fornaxv1.DeploymentStatusSuccess && app.Status.PendingInstances == 0 &&
+			app.Status.TotalInstances >= app.Status.DesiredInstances
+	},
+	"suspended": func(app *fornaxv1.Application) bool {
+		return app.Status.Suspended
+	},
+	"rolloutcomplete": func(app *fornaxv1.Application) bool {
+		return app.Status.Rollout == nil || app.Status.Rollout.Phase == fornaxv1.RolloutPhaseComplete
+	},
+}
+
+func newWaitCommand(o *options) *cobra.Command {
+	var forExpr string
+	timeout := 30 * time.Second
+	cmd := &cobra.Command{
+		Use:   "wait (application|session)[/NAME] [NAME...] --for=EXPR",
+		Short: "wait until resources meet a condition, resources are watched from resource version of first list",
+		Long: `wait until resources meet a condition, --for is one of
+  condition=NAME[=true|false]  session conditions are SessionPending, SessionStarting, SessionAvailable, SessionInUse, SessionClosing,
+                               SessionClosed, SessionTimeout, SessionFailed, Open, Terminated and Bound,
+                               application conditions are Deployed, Ready, Suspended and RolloutComplete
+  jsonpath={PATH}[=VALUE]      value of path equal VALUE, or path exist if VALUE is not set
+  delete                       resource is deleted
+waiting a session condition fail early when session is terminated and condition can not be met anymore`,
+		Example: `  fornaxctl wait --for=condition=SessionAvailable session/s1 --timeout=30s
+  fornaxctl wait --for=condition=Ready app/echo
+  fornaxctl wait --for=jsonpath='{.status.clientSessionCount}'=0 session/s1
+  fornaxctl wait --for=delete session s1 s2`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kind, names, err := parseResource(args)
+			if err != nil {
+				return err
+			}
+			if len(names) == 0 {
+				return fmt.Errorf("resource name is required")
+			}
+			condition, err := parseWaitCondition(kind, forExpr)
+			if err != nil {
+				return err
+			}
+			client, err := o.client()
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+			for _, name := range names {
+				if err := waitFor(ctx, client, o.ns(), kind, name, condition); err != nil {
+					return err
+				}
+				result := "condition met"
+				if condition.deleted {
+					result = "deleted"
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s/%s %s\n", strings.ToLower(string(kind)), name, result)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&forExpr, "for", "", "condition to wait for, condition=NAME[=true|false], jsonpath={PATH}[=VALUE] or delete")
+	cmd.Flags().DurationVar(&timeout, "timeout", timeout, "time to wait for all resources, 0 wait forever")
+	cmd.MarkFlagRequired("for")
+	return cmd
+}
+
+// parseWaitCondition parse --for expression of a resource kind
+func parseWaitCondition(kind resourceKind, expr string) (*waitCondition, error) {
+	switch {
+	case strings.ToLower(expr) == "delete":
+		return &waitCondition{expr: expr, deleted: true}, nil
+	case strings.HasPrefix(expr, "condition="):
+		return parseConditionExpr(kind, expr)
+	case strings.HasPrefix(expr, "jsonpath="):
+		return parseJSONPathExpr(expr)
+	}
+	return nil, fmt.Errorf("unknown wait condition %q, one of condition=NAME, jsonpath={PATH}=VALUE or delete", expr)
+}
+
+func parseConditionExpr(kind resourceKind, expr string) (*waitCondition, error) {
+	name := strings.TrimPrefix(expr, "condition=")
+	want := true
+	if i := strings.Index(name, "="); i >= 0 {
+		v, err := strconv.ParseBool(name[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("condition value of %q must be true or false", expr)
+		}
+		name, want = name[:i], v
+	}
+	key := strings.ToLower(name)
+	switch kind {
+	case sessionKind:
+		met, found := sessionConditions[key]
+		if !found {
+			return nil, fmt.Errorf("unknown session condition %q", name)
+		}
+		return &waitCondition{expr: expr, check: func(obj runtime.Object) (bool, error) {
+			session := obj.(*fornaxv1.ApplicationSession)
+			if met(session) == want {
+				return true, nil
+			}
+			// a terminated session do not change status anymore
+			if isSessionTerminal(session) {
+				return false, fmt.Errorf("session %s is %s, %w", session.Name, sessionStatus(session), errNeverMet)
+			}
+			return false, nil
+		}}, nil
+	case applicationKind:
+		met, found := applicationConditions[key]
+		if !found {
+			return nil, fmt.Errorf("unknown application condition %q", name)
+		}
+		return &waitCondition{expr: expr, check: func(obj runtime.Object) (bool, error) {
+			return met(obj.(*fornaxv1.Application)) == want, nil
+		}}, nil
+	}
+	return nil, fmt.Errorf("unknown resource type %q", kind)
+}
+
+func parseJSONPathExpr(expr string) (*waitCondition, error) {
+	path, value, hasValue := strings.TrimPrefix(expr, "jsonpath="), "", false
+	// value follow last closing brace, path itself may have = in filter
+	if i := strings.LastIndex(path, "}="); i >= 0 {
+		path, value, hasValue = path[:i+1], path[i+2:], true
+	}
+	j := jsonpath.New("wait").AllowMissingKeys(true)
+	if err := j.Parse(path); err != nil {
+		return nil, fmt.Errorf("invalid jsonpath %q, %v", path, err)
+	}
+	return &waitCondition{expr: expr, check: func(obj runtime.Object) (bool, error) {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return false, err
+		}
+		results, err := j.FindResults(content)
+		if err != nil {
+			return false, nil
+		}
+		for _, r := range results {
+			for _, v := range r {
+				if !hasValue || fmt.Sprint(v.Interface()) == value {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	}}, nil
+}
+
+// waitFor list object by name and watch it from list resource version until condition is met,
+// informer re-watch from last seen resource version when watch is closed and list again when resource version is expired
+func waitFor(ctx context.Context, client fornaxclient.Interface, namespace string, kind resourceKind, name string, condition *waitCondition) error {
+	fieldSelector := fields.OneTermEqualSelector("metadata.name", name).String()
+	lw := &cache.ListWatch{}
+	var objType runtime.Object
+	switch kind {
+	case applicationKind:
+		objType = &fornaxv1.Application{}
+		lw.ListFunc = func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = fieldSelector
+			return client.CoreV1().Applications(namespace).List(ctx, options)
+		}
+		lw.WatchFunc = func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = fieldSelector
+			return client.CoreV1().Applications(namespace).Watch(ctx, options)
+		}
+	case sessionKind:
+		objType = &fornaxv1.ApplicationSession{}
+		lw.ListFunc = func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = fieldSelector
+			return client.CoreV1().ApplicationSessions(namespace).List(ctx, options)
+		}
+		lw.WatchFunc = func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = fieldSelector
+			return client.CoreV1().ApplicationSessions(namespace).Watch(ctx, options)
+		}
+	}
+
+	key := namespace + "/" + name
+	precondition := func(store cache.Store) (bool, error) {
+		obj, exists, err := store.GetByKey(key)
+		if err != nil {
+			return false, err
+		}
+		if !exists {
+			// object does not exist yet, wait for it to be created unless waiting for deletion
+			return condition.deleted, nil
+		}
+		if condition.deleted {
+			return false, nil
+		}
+		return condition.check(obj.(runtime.Object))
+	}
+	_, err := watchtools.UntilWithSync(ctx, lw, objType, precondition, func(event watch.Event) (bool, error) {
+		switch event.Type {
+		case watch.Deleted:
+			if condition.deleted {
+				return true, nil
+			}
+			return false, fmt.Errorf("%s/%s was deleted, %w", strings.ToLower(string(kind)), name, errNeverMet)
+		case watch.Added, watch.Modified:
+			if condition.deleted {
+				return false, nil
+			}
+			return condition.check(event.Object)
+		}
+		return false, nil
+	})
+	if errors.Is(err, wait.ErrWaitTimeout) || errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("timed out waiting for %s on %s/%s", condition.expr, strings.ToLower(string(kind)), name)
+	}
+	return err
+}
@@ -23,8 +23,11 @@ import (
 	"os"
 
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/config"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/debugservice"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/dependency"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/node"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/standalone"
+	"centaurusinfra.io/fornax-serverless/pkg/watchdog"
 	"github.com/coreos/go-systemd/v22/daemon"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -117,6 +120,7 @@ func NewCommand() *cobra.Command {
 func Run(ctx context.Context, nodeConfig config.NodeConfiguration) error {
 	klog.InfoS("Golang settings", "GOGC", os.Getenv("GOGC"), "GOMAXPROCS", os.Getenv("GOMAXPROCS"), "GOTRACEBACK", os.Getenv("GOTRACEBACK"))
 
+	config.DetectCgroupFeatures(&nodeConfig)
 	if err := config.ValidateNodeConfiguration(nodeConfig); len(err) != 0 {
 		return fmt.Errorf("invalidate nodeagent configuration, errors: %v, configuration: %v", err, nodeConfig)
 	}
@@ -138,13 +142,37 @@ func Run(ctx context.Context, nodeConfig config.NodeConfiguration) error {
 func run(ctx context.Context, nodeConfig config.NodeConfiguration, dependencies *dependency.Dependencies) error {
 	go daemon.SdNotify(false, "READY=1")
 
+	// debug service is served using node certificate, node does not report debug service port if it does not have one
+	if nodeConfig.DebugServicePort > 0 && dependencies.NodeCredentials == nil {
+		klog.InfoS("Debug service is disabled as node agent does not use mtls, set fornaxcore-ca-file to enable it")
+		nodeConfig.DebugServicePort = 0
+	}
+
 	fornaxNode, err := node.NewFornaxNode(nodeConfig, dependencies)
 	if err != nil {
 		klog.ErrorS(err, "Can not initialize node")
 	}
-	nodeActor, err := node.NewNodeActor(fornaxNode)
-	if err != nil {
-		klog.ErrorS(err, "Can not initialize node actor")
+	var nodeActor *node.FornaxNodeActor
+	if len(nodeConfig.StandaloneAPIAddress) > 0 {
+		// standalone node is configured and commanded by a local fornaxcore serving local api
+		localCore := standalone.NewLocalCore(nodeConfig.StandalonePodCIDR)
+		if nodeActor, err = node.NewStandaloneNodeActor(fornaxNode, localCore); err != nil {
+			klog.ErrorS(err, "Can not initialize standalone node actor")
+		}
+		if err := standalone.NewAPIServer(localCore).Run(ctx, nodeConfig.StandaloneAPIAddress); err != nil {
+			return fmt.Errorf("failed to run standalone api server: %w", err)
+		}
+	} else {
+		if nodeActor, err = node.NewNodeActor(fornaxNode); err != nil {
+			klog.ErrorS(err, "Can not initialize node actor")
+		}
+	}
+
+	if nodeConfig.DebugServicePort > 0 {
+		debugServer := debugservice.NewDebugServer(fornaxNode.Pods, dependencies.RuntimeService, dependencies.NodeCredentials.ServerTLSConfig())
+		if err := debugServer.Run(ctx, nodeConfig.DebugServicePort); err != nil {
+			return fmt.Errorf("failed to run debug service: %w", err)
+		}
 	}
 
 	klog.Info("Starting FornaxNode")
@@ -154,6 +182,14 @@ func run(ctx context.Context, nodeConfig config.NodeConfiguration, dependencies
 	}
 	klog.Info("FornaxNode started")
 
+	watchdogConfig := watchdog.DefaultConfiguration()
+	if len(nodeConfig.WatchdogConfigFile) > 0 {
+		if watchdogConfig, err = watchdog.LoadConfiguration(nodeConfig.WatchdogConfigFile); err != nil {
+			return fmt.Errorf("failed to load watchdog configuration: %w", err)
+		}
+	}
+	go watchdog.New(NodeAgent, watchdogConfig).Run(ctx)
+
 	// wait until shutdown signal is received
 	select {
 	case <-ctx.Done():
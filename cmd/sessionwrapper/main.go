@@ -18,11 +18,16 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sync"
 	"syscall"
 	"time"
 
@@ -32,6 +37,9 @@ import (
 	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"k8s.io/klog/v2"
 )
 
@@ -42,6 +50,7 @@ func main() {
 	config := &SessionConfig{
 		endpoint: fmt.Sprintf("%s:%d", endpoint, 1022),
 		openCmd:  opensession_cmd,
+		tlsDir:   os.Getenv(sessiongrpc.SessionServiceTLSDirEnv),
 	}
 
 	instanceId := os.Getenv(fornaxv1.LabelFornaxCorePod)
@@ -73,11 +82,26 @@ const (
 	DefaultConnTimeout                = 5 * time.Second
 	DefaultCallTimeout                = 5 * time.Second
 	DefaultMaxRecvMsgSize             = 16 * 1024
+
+	// session process find drain notification in file of this env, it's written before process get SIGUSR1
+	SessionDrainFileEnv = "FORNAX_SESSION_DRAIN_FILE"
 )
 
+// sessionDrain is written into drain file as json when session is ending soon
+type sessionDrain struct {
+	Reason   string    `json:"reason"`
+	Deadline time.Time `json:"deadline"`
+}
+
+func sessionDrainFile(sessionId string) string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("fornax-session-%s-drain.json", filepath.Base(sessionId)))
+}
+
 type SessionConfig struct {
 	endpoint string
 	openCmd  string
+	// dir of pod certificate and session service CA, connection is insecure if empty
+	tlsDir string
 }
 
 type Session struct {
@@ -100,6 +124,24 @@ type sessionServiceClient struct {
 	service          sessiongrpc.SessionServiceClient
 	getMessageClient sessiongrpc.SessionService_GetMessageClient
 	sessions         map[string]*Session
+
+	// v2 bidirectional stream, messages are sent on stream when it's connected,
+	// legacy is set when node agent does not support stream, then get message and put message are used
+	streamMu sync.Mutex
+	stream   sessiongrpc.SessionService_ConnectClient
+	legacy   bool
+}
+
+func (f *sessionServiceClient) getStream() sessiongrpc.SessionService_ConnectClient {
+	f.streamMu.Lock()
+	defer f.streamMu.Unlock()
+	return f.stream
+}
+
+func (f *sessionServiceClient) setStream(stream sessiongrpc.SessionService_ConnectClient) {
+	f.streamMu.Lock()
+	defer f.streamMu.Unlock()
+	f.stream = stream
 }
 
 func (f *sessionServiceClient) PutMessage(message *sessiongrpc.SessionMessage) error {
@@ -107,6 +149,17 @@ func (f *sessionServiceClient) PutMessage(message *sessiongrpc.SessionMessage) e
 	if f.service == nil {
 		return errors.New("FornaxCore connection is not initialized yet")
 	}
+	f.streamMu.Lock()
+	if f.stream != nil {
+		// grpc stream does not allow concurrent send, hold lock until message is sent
+		defer f.streamMu.Unlock()
+		if err := f.stream.Send(message); err != nil {
+			klog.ErrorS(err, "Failed to send message via session stream", "endpoint", f.config.endpoint)
+			return err
+		}
+		return nil
+	}
+	f.streamMu.Unlock()
 
 	ctx, cancel := context.WithTimeout(context.Background(), DefaultCallTimeout)
 	defer cancel()
@@ -132,11 +185,20 @@ func (f *sessionServiceClient) connect() error {
 			grpc_retry.WithBackoff(grpc_retry.BackoffLinear(100 * time.Millisecond)),
 			grpc_retry.WithCodes(codes.NotFound, codes.Aborted, codes.Unavailable, codes.DataLoss, codes.Unknown),
 		}
+		transport := grpc.WithInsecure()
+		if len(f.config.tlsDir) > 0 {
+			creds, err := sessionServiceCredentials(f.config.tlsDir)
+			if err != nil {
+				klog.ErrorS(err, "Failed to load session service tls credentials", "dir", f.config.tlsDir)
+				return err
+			}
+			transport = grpc.WithTransportCredentials(creds)
+		}
 		conn, err := grpc.DialContext(
 			ctx,
 			f.config.endpoint,
 			grpc.WithBlock(),
-			grpc.WithInsecure(),
+			transport,
 			grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(DefaultMaxRecvMsgSize)),
 			grpc.WithStreamInterceptor(grpc_retry.StreamClientInterceptor(opts...)),
 			grpc.WithUnaryInterceptor(grpc_retry.UnaryClientInterceptor(opts...)),
@@ -155,6 +217,30 @@ func (f *sessionServiceClient) connect() error {
 	return err
 }
 
+// sessionServiceCredentials is mtls credentials using pod certificate, certificate files are read on every handshake,
+// node agent renew pod certificate in same dir
+func sessionServiceCredentials(dir string) (credentials.TransportCredentials, error) {
+	caPEM, err := os.ReadFile(filepath.Join(dir, sessiongrpc.SessionServiceTLSCAFile))
+	if err != nil {
+		return nil, err
+	}
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(caPEM) {
+		return nil, errors.New("no valid session service CA certificate")
+	}
+	return credentials.NewTLS(&tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    rootCAs,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, err := tls.LoadX509KeyPair(filepath.Join(dir, sessiongrpc.SessionServiceTLSCertFile), filepath.Join(dir, sessiongrpc.SessionServiceTLSKeyFile))
+			if err != nil {
+				return nil, err
+			}
+			return &cert, nil
+		},
+	}), nil
+}
+
 func (f *sessionServiceClient) initGetMessageClient(ctx context.Context, identifier *sessiongrpc.PodIdentifier) error {
 	if f.conn == nil {
 		klog.InfoS("Connecting to FornaxCore", "endpoint", f.config.endpoint)
@@ -172,11 +258,86 @@ func (f *sessionServiceClient) initGetMessageClient(ctx context.Context, identif
 	return nil
 }
 
+// initStreamClient open v2 stream and identify pod with a heartbeat
+func (f *sessionServiceClient) initStreamClient(ctx context.Context) error {
+	if f.conn == nil {
+		klog.InfoS("Connecting to FornaxCore", "endpoint", f.config.endpoint)
+		err := f.connect()
+		if err != nil {
+			return err
+		}
+	}
+	klog.InfoS("Init session stream client", "endpoint", f.config.endpoint)
+	stream, err := f.service.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(f.buildHeartbeat()); err != nil {
+		return err
+	}
+	f.setStream(stream)
+	return nil
+}
+
+// buildHeartbeat return heartbeat with sessions which are not closed
+func (f *sessionServiceClient) buildHeartbeat() *sessiongrpc.SessionMessage {
+	openSessions := []string{}
+	for _, v := range f.sessions {
+		if v.state != sessiongrpc.SessionState_STATE_CLOSED {
+			openSessions = append(openSessions, v.id)
+		}
+	}
+	return &sessiongrpc.SessionMessage{
+		SessionIdentifier: &sessiongrpc.SessionIdentifier{PodId: f.identifier},
+		MessageType:       sessiongrpc.MessageType_HEARTBEAT,
+		MessageBody: &sessiongrpc.SessionMessage_Heartbeat{
+			Heartbeat: &sessiongrpc.Heartbeat{
+				Timestamp:    timestamppb.Now(),
+				OpenSessions: openSessions,
+			},
+		},
+	}
+}
+
+// recvStreamMessage receive message from v2 stream, it fall back to get message if node agent does not support stream
+func (f *sessionServiceClient) recvStreamMessage(ctx context.Context) (*sessiongrpc.SessionMessage, error) {
+	stream := f.getStream()
+	if stream == nil {
+		if err := f.initStreamClient(ctx); err != nil {
+			return nil, err
+		}
+		stream = f.getStream()
+	}
+	msg, err := stream.Recv()
+	if err != nil {
+		f.setStream(nil)
+		if status.Code(err) == codes.Unimplemented {
+			klog.InfoS("Node agent does not support session stream, use get message", "endpoint", f.config.endpoint)
+			f.legacy = true
+		}
+		return nil, err
+	}
+	return msg, nil
+}
+
 // should exec in a go routine, fornaxCoreClient recvMessage loop forever until it's old stop
 // it receive message and dispatch it to receivers' channel registered by GetMessage
 func (f *sessionServiceClient) recvMessage(ctx context.Context) {
 	klog.InfoS("Receiving message from FornaxCore", "endpoint", f.config.endpoint)
 	for {
+		if !f.legacy {
+			msg, err := f.recvStreamMessage(ctx)
+			if err != nil {
+				klog.ErrorS(err, "Failed to receive message from session stream, reset to get a new stream client", "endpoint", f.config.endpoint)
+				time.Sleep(2 * time.Second)
+				continue
+			}
+			if err = f.handleSessionCommand(msg); err != nil {
+				klog.ErrorS(err, "Failed to handle session")
+			}
+			continue
+		}
+
 		if f.getMessageClient == nil {
 			err := f.initGetMessageClient(ctx, &sessiongrpc.PodIdentifier{
 				PodId: f.identifier,
@@ -215,6 +376,8 @@ func (f *sessionServiceClient) recvMessage(ctx context.Context) {
 func (f *sessionServiceClient) handleSessionCommand(msg *sessiongrpc.SessionMessage) error {
 	sessionId := msg.GetSessionIdentifier()
 	switch msg.GetMessageType() {
+	case sessiongrpc.MessageType_HEARTBEAT:
+		return f.PutMessage(f.buildHeartbeat())
 	case sessiongrpc.MessageType_PING_SESSION:
 		if f.stopping {
 			return errors.New("instance is terminating")
@@ -274,6 +437,26 @@ func (f *sessionServiceClient) handleSessionCommand(msg *sessiongrpc.SessionMess
 			}
 			return nil
 		}
+	case sessiongrpc.MessageType_DRAIN_SESSION:
+		// relay drain notification to session process, which can tell its clients to leave before deadline
+		session, found := f.sessions[sessionId.GetIdentifier()]
+		if !found {
+			klog.InfoS("Session NotFound", "sessionId", sessionId)
+			return nil
+		}
+		if session.state != sessiongrpc.SessionState_STATE_OPEN || session.pid == 0 {
+			return nil
+		}
+		drainSession := msg.GetDrainSession()
+		data, err := json.Marshal(&sessionDrain{Reason: drainSession.GetReason(), Deadline: drainSession.GetDeadline().AsTime()})
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(sessionDrainFile(session.id), data, 0644); err != nil {
+			return err
+		}
+		klog.InfoS("Session is draining", "sessionId", sessionId, "reason", drainSession.GetReason(), "deadline", drainSession.GetDeadline().AsTime())
+		return syscall.Kill(session.pid, syscall.SIGUSR1)
 	case sessiongrpc.MessageType_OPEN_SESSION:
 		if f.stopping {
 			return errors.New("instance is terminating")
@@ -291,6 +474,7 @@ func (f *sessionServiceClient) handleSessionCommand(msg *sessiongrpc.SessionMess
 		cmd := f.config.openCmd
 		procAttr := os.ProcAttr{}
 		procAttr.Files = []*os.File{os.Stdin, os.Stdout, os.Stderr}
+		procAttr.Env = append(os.Environ(), fmt.Sprintf("%s=%s", SessionDrainFileEnv, sessionDrainFile(session.id)))
 		proc, err := os.StartProcess(cmd, []string{}, &procAttr)
 		if err != nil {
 			session.state = sessiongrpc.SessionState_STATE_CLOSED
@@ -303,6 +487,8 @@ func (f *sessionServiceClient) handleSessionCommand(msg *sessiongrpc.SessionMess
 				s, _ := proc.Wait()
 				klog.InfoS("Session process exit", "code", s.ExitCode())
 				session.state = sessiongrpc.SessionState_STATE_CLOSED
+				// report closed session right away instead of waiting for next house keeping
+				f.sendHeartbeat(session)
 			}()
 		}
 		f.sessions[sessionId.GetIdentifier()] = session
@@ -321,7 +507,7 @@ func (f *sessionServiceClient) sendHeartbeat(session *Session) error {
 	msgBody := &sessiongrpc.SessionMessage_SessionStatus{
 		SessionStatus: &sessiongrpc.SessionStatus{
 			SessionState:  sessionState,
-			ClientSession: []*sessiongrpc.ClientSession{},
+			ClientSession: session.clients,
 		},
 	}
 	msg := &sessiongrpc.SessionMessage{
@@ -349,7 +535,10 @@ func (f *sessionServiceClient) houseKeeping() {
 				syscall.Kill(v.pid, syscall.SIGKILL)
 			}
 		}
-		f.sendHeartbeat(v)
+		// open sessions are kept alive by stream heartbeat, state changes are sent when they happen
+		if f.getStream() == nil || v.state != sessiongrpc.SessionState_STATE_OPEN {
+			f.sendHeartbeat(v)
+		}
 	}
 
 	if f.stopping {
@@ -29,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/component-base/version"
 )
 
 func InitV1Node(hostIp, hostName string) (*v1.Node, error) {
@@ -63,7 +64,7 @@ func InitV1Node(hostIp, hostName string) (*v1.Node, error) {
 			Conditions:      []v1.NodeCondition{},
 			Addresses:       []v1.NodeAddress{},
 			DaemonEndpoints: v1.NodeDaemonEndpoints{},
-			NodeInfo:        v1.NodeSystemInfo{},
+			NodeInfo:        v1.NodeSystemInfo{KubeletVersion: version.Get().GitVersion},
 			Images:          []v1.ContainerImage{},
 			VolumesInUse:    []v1.UniqueVolumeName{},
 			VolumesAttached: []v1.AttachedVolume{},
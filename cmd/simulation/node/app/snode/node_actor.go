@@ -473,7 +473,7 @@ func NewNodeActor(hostIp, hostName string, nodeConfig *config.SimulationNodeConf
 	actor.innerActor = message.NewLocalChannelActor(fpnode.V1Node.GetName(), actor.actorMessageProcess)
 
 	klog.InfoS("Starting FornaxCore actor", "node", hostName)
-	fornaxCoreActor := fornaxcore.NewFornaxCoreActor(fpnode.NodeConfig.NodeIP, util.Name(fpnode.V1Node), nodeConfig.FornaxCoreUrls)
+	fornaxCoreActor := fornaxcore.NewFornaxCoreActor(fpnode.NodeConfig.NodeIP, util.Name(fpnode.V1Node), nodeConfig.FornaxCoreUrls, nil)
 	actor.fornoxCoreRef = fornaxCoreActor.Reference()
 	err = fornaxCoreActor.Start(actor.innerActor.Reference())
 	if err != nil {
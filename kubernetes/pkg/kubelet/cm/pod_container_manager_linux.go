@@ -49,6 +49,10 @@ type PodContainerManagerImpl struct {
 	CgroupManager CgroupManager
 	// Maximum number of pids in a pod
 	PodPidsLimit int64
+	// PodPidsLimitFunc returns pids limit of a pod overriding PodPidsLimit, if it returns true
+	PodPidsLimitFunc func(pod *v1.Pod) (int64, bool)
+	// PodUnifiedResourcesFunc returns cgroup v2 parameters of a pod added to what are derived from pod resources
+	PodUnifiedResourcesFunc func(pod *v1.Pod) map[string]string
 	// enforceCPULimits controls whether cfs quota is enforced or not
 	EnforceCPULimits bool
 	// cpuCFSQuotaPeriod is the cfs period value, cfs_period_us, setting per
@@ -85,6 +89,21 @@ func (m *PodContainerManagerImpl) EnsureExists(pod *v1.Pod) error {
 		if m.PodPidsLimit > 0 {
 			podCgroupConfig.ResourceParameters.PidsLimit = &m.PodPidsLimit
 		}
+		if m.PodPidsLimitFunc != nil {
+			if limit, found := m.PodPidsLimitFunc(pod); found {
+				podCgroupConfig.ResourceParameters.PidsLimit = &limit
+			}
+		}
+		if enforceMemoryQoS && m.PodUnifiedResourcesFunc != nil {
+			if unified := m.PodUnifiedResourcesFunc(pod); len(unified) > 0 {
+				if podCgroupConfig.ResourceParameters.Unified == nil {
+					podCgroupConfig.ResourceParameters.Unified = map[string]string{}
+				}
+				for k, v := range unified {
+					podCgroupConfig.ResourceParameters.Unified[k] = v
+				}
+			}
+		}
 		if enforceMemoryQoS {
 			klog.InfoS("MemoryQoS config for pod", "pod", klog.KObj(pod), "unified", podCgroupConfig.ResourceParameters.Unified)
 		}
@@ -0,0 +1,80 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"encoding/json"
+	"sync"
+
+	"k8s.io/klog/v2"
+)
+
+// SecretSealFunc encrypt secret data, it's set by fornaxcore with its secret key
+type SecretSealFunc func(data []byte) ([]byte, error)
+
+var (
+	_SecretSealMutex = &sync.RWMutex{}
+	_SecretSealFunc  SecretSealFunc
+)
+
+// SetSecretSealFunc register a func to seal application secret data, application with secret data is rejected until it's set
+func SetSecretSealFunc(f SecretSealFunc) {
+	_SecretSealMutex.Lock()
+	defer _SecretSealMutex.Unlock()
+	_SecretSealFunc = f
+}
+
+// sealSecret move plain secret data into EncryptedData, data is kept if it can not be sealed and application is rejected by validation
+func (in *Application) sealSecret() {
+	if in.Spec.Secret == nil || len(in.Spec.Secret.Data) == 0 {
+		return
+	}
+	_SecretSealMutex.RLock()
+	seal := _SecretSealFunc
+	_SecretSealMutex.RUnlock()
+	if seal == nil {
+		return
+	}
+	data, err := json.Marshal(in.Spec.Secret.Data)
+	if err != nil {
+		klog.ErrorS(err, "Failed to encode application secret data", "application", in.Name)
+		return
+	}
+	sealed, err := seal(data)
+	if err != nil {
+		klog.ErrorS(err, "Failed to seal application secret data", "application", in.Name)
+		return
+	}
+	in.Spec.Secret.EncryptedData = sealed
+	in.Spec.Secret.Data = nil
+}
+
+// OpenSecretData decrypt EncryptedData of secret with open func, plain Data of a secret saved before data was sealed is returned as is
+func OpenSecretData(secret *ApplicationSecret, open func(sealed []byte) ([]byte, error)) (map[string][]byte, error) {
+	if len(secret.EncryptedData) == 0 {
+		return secret.Data, nil
+	}
+	data, err := open(secret.EncryptedData)
+	if err != nil {
+		return nil, err
+	}
+	secretData := map[string][]byte{}
+	if err := json.Unmarshal(data, &secretData); err != nil {
+		return nil, err
+	}
+	return secretData, nil
+}
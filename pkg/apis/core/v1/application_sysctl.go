@@ -0,0 +1,56 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"strings"
+)
+
+// SafeSysctls are sysctls namespaced by pod network or ipc namespace, setting them in a pod does not affect
+// other pods or the node, node agents also reject a pod using sysctls not in this list
+var SafeSysctls = []string{
+	"kernel.shm_rmid_forced",
+	"kernel.msgmax",
+	"kernel.msgmnb",
+	"kernel.msgmni",
+	"kernel.sem",
+	"kernel.shmall",
+	"kernel.shmmax",
+	"kernel.shmmni",
+	"net.core.somaxconn",
+	"net.ipv4.ip_local_port_range",
+	"net.ipv4.ip_local_reserved_ports",
+	"net.ipv4.ip_unprivileged_port_start",
+	"net.ipv4.ping_group_range",
+	"net.ipv4.tcp_fin_timeout",
+	"net.ipv4.tcp_keepalive_intvl",
+	"net.ipv4.tcp_keepalive_probes",
+	"net.ipv4.tcp_keepalive_time",
+	"net.ipv4.tcp_syncookies",
+	"net.ipv4.tcp_tw_reuse",
+}
+
+// IsSafeSysctl tell if a sysctl is in safe list, names can use slash as separator like runc accept
+func IsSafeSysctl(name string) bool {
+	name = strings.ReplaceAll(name, "/", ".")
+	for _, v := range SafeSysctls {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
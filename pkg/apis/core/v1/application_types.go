@@ -18,11 +18,17 @@ package v1
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/apiserver-runtime/pkg/builder/resource"
 	"sigs.k8s.io/apiserver-runtime/pkg/builder/resource/resourcestrategy"
@@ -55,10 +61,11 @@ type ApplicationSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
-	// runtime image and resource requirement of a application container
+	// runtime image and resource requirement of a application container, a container request gpus using nvidia.com/gpu resource
 	Containers []corev1.Container `json:"containers,omitempty"`
 
-	// container will use grpc session service on node agent to start application session
+	// container will use grpc session service on node agent to start application session,
+	// or a websocket sidecar if application has websocket session runtime annotation
 	UsingNodeSessionService bool `json:"usingNodeSessionService,omitempty"`
 
 	// Data contains the configuration data.
@@ -69,8 +76,390 @@ type ApplicationSpec struct {
 
 	// application scaling policy
 	ScalingPolicy ScalingPolicy `json:"scalingPolicy,omitempty"`
+
+	// external dependencies which must be ready before sessions are routed to application pods
+	// +optional
+	ReadinessGates []ApplicationReadinessGate `json:"readinessGates,omitempty"`
+
+	// health check fornaxcore run against session access endpoints after session is open
+	// +optional
+	SessionHealthCheck *SessionHealthCheck `json:"sessionHealthCheck,omitempty"`
+
+	// secret data delivered to application pods, change version to rotate secret on running pods
+	// +optional
+	Secret *ApplicationSecret `json:"secret,omitempty"`
+
+	// fornaxcore issue a certificate of application and deliver it to pods with application secret, so session endpoints can serve tls
+	// +optional
+	TLS *ApplicationTLS `json:"tls,omitempty"`
+
+	// suspend application when it does not have session for a long time, nil means never suspend
+	// +optional
+	SuspensionPolicy *ApplicationSuspensionPolicy `json:"suspensionPolicy,omitempty"`
+
+	// team owning application, team is also set as application label, so access partitions and alerts can select applications by team
+	// +optional
+	Ownership *ApplicationOwnership `json:"ownership,omitempty"`
+
+	// discover public endpoints of udp ports of pods, so real time clients behind nat can connect sessions directly
+	// +optional
+	NATTraversal *ApplicationNATTraversal `json:"natTraversal,omitempty"`
+
+	// keep some started standby pods, so a session is bound to a warm pod instead of waiting for pod start
+	// +optional
+	WarmPool *ApplicationWarmPool `json:"warmPool,omitempty"`
+
+	// run by node agent after pod containers are ready, pod is not used by sessions or counted as warm pod until it succeed
+	// +optional
+	WarmUp *ApplicationWarmUp `json:"warmUp,omitempty"`
+
+	// watch health of new pods after container spec change, pause or roll back a failing rollout
+	// +optional
+	RolloutPolicy *ApplicationRolloutPolicy `json:"rolloutPolicy,omitempty"`
+
+	// kernel parameters set in pod network and ipc namespaces, only sysctls in SafeSysctls are allowed,
+	// pod fail to start on a node whose kernel does not support a sysctl
+	// +optional
+	Sysctls []corev1.Sysctl `json:"sysctls,omitempty"`
+
+	// restart exited containers in place on node, pod fail when its first container exit by default
+	// +optional
+	RestartPolicy *ApplicationRestartPolicy `json:"restartPolicy,omitempty"`
+
+	// default time limit of sessions which do not set their own, 0 mean sessions are not time limited
+	// +optional
+	MaxSessionDurationSeconds uint32 `json:"maxSessionDurationSeconds,omitempty"`
+
+	// default expiry warning of time limited sessions which do not set their own
+	// +optional, default 60
+	SessionExpiryWarningSeconds uint32 `json:"sessionExpiryWarningSeconds,omitempty"`
+
+	// bound processes and oom kill preference of application pods, node agent default is used if not set
+	// +optional
+	ProcessLimits *ApplicationProcessLimits `json:"processLimits,omitempty"`
+
+	// allowed ingress and egress traffic of application pods, node agent drop other traffic in pod network namespace,
+	// so sessions of tenants sharing a node can not reach each other, nil means pod traffic is not restricted
+	// +optional
+	NetworkPolicy *ApplicationNetworkPolicy `json:"networkPolicy,omitempty"`
+
+	// how application pods are named, pods get random names by default,
+	// ordinal naming give pods stable identities which sessions and persistent volumes are bound to
+	// +optional
+	InstanceNaming *ApplicationInstanceNaming `json:"instanceNaming,omitempty"`
+}
+
+type InstanceNamingStrategy string
+
+const (
+	// pods are named <application>-<random string>-<clock sequence>, a replaced pod get a new name
+	InstanceNamingStrategyRandom InstanceNamingStrategy = "Random"
+	// pods are named <application>-<ordinal> using lowest free ordinal, a replaced pod get same name back,
+	// identities demanded by pending sessions are created first
+	InstanceNamingStrategyOrdinal InstanceNamingStrategy = "Ordinal"
+)
+
+// ApplicationInstanceNaming is naming strategy of application pods, volume claims are only allowed with ordinal naming
+type ApplicationInstanceNaming struct {
+	// Random or Ordinal
+	// +optional, default Random
+	Strategy InstanceNamingStrategy `json:"strategy,omitempty"`
+
+	// each instance identity get its own volume of a template, volume is kept when pod is deleted and reused by next pod of same identity,
+	// pod of identity is placed on node of its volumes, volumes are not deleted with application
+	// +optional
+	VolumeClaimTemplates []ApplicationVolumeClaimTemplate `json:"volumeClaimTemplates,omitempty"`
+}
+
+// ApplicationVolumeClaimTemplate is a persistent volume claimed by each instance identity, claim of a instance is named <name>-<instance name>
+type ApplicationVolumeClaimTemplate struct {
+	Name string `json:"name"`
+
+	// absolute path volume is mounted at in every application container
+	MountPath string `json:"mountPath"`
+}
+
+// ApplicationNetworkPolicy isolate both directions of pod traffic, only traffic matching a rule is allowed,
+// an empty rule list deny all traffic of that direction, a rule without cidrs and ports allow all traffic of that direction,
+// loopback, replies of allowed connections and traffic with node are always allowed, so session service and probes keep working
+type ApplicationNetworkPolicy struct {
+	// traffic from remote cidrs to pod ports
+	// +optional
+	Ingress []NetworkPolicyRule `json:"ingress,omitempty"`
+
+	// traffic from pod to remote cidrs and ports, dns server need to be allowed if application resolve names
+	// +optional
+	Egress []NetworkPolicyRule `json:"egress,omitempty"`
+}
+
+// NetworkPolicyRule allow traffic between any of cidrs and any of ports
+type NetworkPolicyRule struct {
+	// ipv4 or ipv6 cidrs of remote peer, empty means any address
+	// +optional
+	CIDRs []string `json:"cidrs,omitempty"`
+
+	// destination ports, pod ports for ingress, remote ports for egress, empty means any port
+	// +optional
+	Ports []NetworkPolicyPort `json:"ports,omitempty"`
 }
 
+type NetworkPolicyPort struct {
+	// +optional, default TCP
+	Protocol corev1.Protocol `json:"protocol,omitempty"`
+
+	// 0 means all ports of protocol
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// allow a port range from port to end port
+	// +optional
+	EndPort int32 `json:"endPort,omitempty"`
+}
+
+// ApplicationProcessLimits apply to every pod of application, so a runaway session can not exhaust pids of a node shared by tenants
+type ApplicationProcessLimits struct {
+	// max number of processes and threads in pod cgroup, 0 use node pod pids limit
+	// +optional
+	PodPidsLimit int64 `json:"podPidsLimit,omitempty"`
+
+	// oom_score_adj of application containers, -1000 to 1000, a higher value make containers killed first when node is out of memory
+	// +optional
+	OOMScoreAdj *int32 `json:"oomScoreAdj,omitempty"`
+}
+
+// ApplicationRestartPolicy tell node agent if it restart a exited container in same pod sandbox, restart is delayed by a backoff
+// doubled after each restart up to MaxBackoffSeconds, backoff is reset when container ran longer than 10 minutes,
+// restart count and last termination reason are reported in pod container status
+type ApplicationRestartPolicy struct {
+	// Never, OnFailure or Always, OnFailure restart containers exited with non zero code or failed probe
+	// +optional, default Never
+	Policy corev1.RestartPolicy `json:"policy,omitempty"`
+
+	// +optional, default 10
+	InitialBackoffSeconds int32 `json:"initialBackoffSeconds,omitempty"`
+
+	// +optional, default 300
+	MaxBackoffSeconds int32 `json:"maxBackoffSeconds,omitempty"`
+}
+
+// ResourceNvidiaGPU is resource name of nvidia gpus, same as nvidia device plugin of kubernetes, gpus are not shared by containers
+const ResourceNvidiaGPU corev1.ResourceName = "nvidia.com/gpu"
+
+type RolloutFailureAction string
+
+const (
+	// stop replacing old pods, sessions keep using them until spec change again
+	RolloutFailureActionPause RolloutFailureAction = "Pause"
+
+	// restore container spec of last revision
+	RolloutFailureActionRollback RolloutFailureAction = "Rollback"
+)
+
+// ApplicationRolloutPolicy define when a rollout is failing, sessions and pods of new container spec are watched in bake window
+// after rollout start, a check is disabled when its threshold is 0
+type ApplicationRolloutPolicy struct {
+	// +optional, default 300
+	BakeSeconds int32 `json:"bakeSeconds,omitempty"`
+
+	// rollout fail when more percent of sessions on new pods failed or timed out
+	// +optional
+	MaxSessionFailurePercent int32 `json:"maxSessionFailurePercent,omitempty"`
+
+	// session failure percent is checked after new pods got at least this many sessions
+	// +optional, default 5
+	MinSessions int32 `json:"minSessions,omitempty"`
+
+	// rollout fail when more new pods terminated without being deleted by fornaxcore, e.g. crashed
+	// +optional
+	MaxPodFailures int32 `json:"maxPodFailures,omitempty"`
+
+	// +optional, default Pause
+	FailureAction RolloutFailureAction `json:"failureAction,omitempty"`
+}
+
+// ApplicationWarmPool keep at least Size unoccupied pods, image of warm pods are pulled and pods are started,
+// warm pods are hibernated if application does not use node session service,
+// warm pool is limited by free capacity of nodes and maximum instances, and it's not kept when application is suspended or scaled to zero
+type ApplicationWarmPool struct {
+	// number of warm pods
+	Size uint32 `json:"size"`
+
+	// warm pool use at most this percent of free node capacity
+	// +optional, default 50
+	MaxCapacityPercent uint32 `json:"maxCapacityPercent,omitempty"`
+}
+
+// ApplicationWarmUp is a command run in a container or a http get against pod, e.g. load model or fill cache,
+// pod stay pending until warm up succeed, and fail if every attempt failed
+type ApplicationWarmUp struct {
+	// exec or http get, same as container lifecycle hook, tcp socket is not supported
+	Handler corev1.LifecycleHandler `json:"handler"`
+
+	// container to exec command in and resolve named http port, first container if not set
+	// +optional
+	Container string `json:"container,omitempty"`
+
+	// timeout of each attempt
+	// +optional, default 60
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+
+	// +optional, default 3
+	MaxAttempts int32 `json:"maxAttempts,omitempty"`
+}
+
+// ApplicationNATTraversal configure how node discover public endpoint of pod udp ports, node send a stun binding request
+// from each allocated host port, mapped address is published in session status as public endpoint,
+// discovery is skipped if stun servers are empty, sessions still get relay
+type ApplicationNATTraversal struct {
+	// stun servers, host:port, tried in order until one answer
+	// +optional
+	STUNServers []string `json:"stunServers,omitempty"`
+
+	// relay clients fallback to when direct connection fail, e.g. a turn server
+	// +optional
+	Relay *SessionRelay `json:"relay,omitempty"`
+}
+
+type SessionRelay struct {
+	// relay address, host:port
+	Address string `json:"address"`
+
+	// relay protocol, e.g. turn, or a application specific relay
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+}
+
+type ApplicationOwnership struct {
+	// team name, must be a valid label value
+	Team string `json:"team"`
+
+	// users or emails responsible for application
+	// +optional
+	Owners []string `json:"owners,omitempty"`
+
+	// where alerts of application are routed, e.g. a pager service or chat channel of team
+	// +optional
+	AlertChannel string `json:"alertChannel,omitempty"`
+}
+
+// ApplicationSuspensionPolicy reclaim resources of abandoned applications, a suspended application does not have any pod,
+// minimum instance is ignored until it's resumed by a new session or resume api
+type ApplicationSuspensionPolicy struct {
+	// application is suspended after it does not have any session in this period
+	IdleSeconds int32 `json:"idleSeconds"`
+}
+
+// ApplicationTLS configure certificate issued for application, certificate and key are added into application secret data
+// as tls.crt, tls.key and ca.crt, they are rotated like secret before certificate expire
+type ApplicationTLS struct {
+	// dns names in certificate, default <name>.<namespace>.<cluster domain>
+	// +optional
+	DNSNames []string `json:"dnsNames,omitempty"`
+
+	// +optional, default 720 hours
+	ValidityHours int32 `json:"validityHours,omitempty"`
+
+	// certificate is renewed when it expire in this duration, must be less than validity
+	// +optional, default 240 hours
+	RenewBeforeHours int32 `json:"renewBeforeHours,omitempty"`
+}
+
+// ApplicationSecret is pushed to application pods and open sessions, when version is changed,
+// new version is distributed to pods, previous version is kept valid on pods until RevokeGracePeriodSeconds passed
+type ApplicationSecret struct {
+	// version of secret data, must be changed when data is changed
+	Version string `json:"version"`
+
+	// plain secret data, it's sealed into EncryptedData when application is saved, so, it's never stored or returned by api
+	// +optional
+	Data map[string][]byte `json:"data,omitempty"`
+
+	// data sealed by fornaxcore secret key, only fornaxcore can open it
+	// +optional
+	EncryptedData []byte `json:"encryptedData,omitempty"`
+
+	// +optional, default 300 seconds
+	RevokeGracePeriodSeconds int32 `json:"revokeGracePeriodSeconds,omitempty"`
+}
+
+type SessionHealthCheckType string
+
+const (
+	// send a http get request to session access endpoint, 2xx and 3xx status code mean healthy
+	SessionHealthCheckTypeHTTP SessionHealthCheckType = "http"
+
+	// open a tcp connection to session access endpoint
+	SessionHealthCheckTypeTCP SessionHealthCheckType = "tcp"
+)
+
+// SessionHealthCheck is executed by fornaxcore prober pool against open session access endpoints,
+// it give a external view health signal of session in addition of probes on node agent
+type SessionHealthCheck struct {
+	Type SessionHealthCheckType `json:"type"`
+
+	// http path to request, only used by http check
+	// +optional, default "/"
+	Path string `json:"path,omitempty"`
+
+	// +optional, default 10 seconds
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+
+	// +optional, default 1 second
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+
+	// session is marked unhealthy after this many consecutive failures
+	// +optional, default 3
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+}
+
+// ApplicationReadinessGate reference a external dependency of application, e.g. a database,
+// fornaxcore check it and only assign sessions to application pods when all gates pass,
+// one and only one of HTTPGet and GRPC must be set
+type ApplicationReadinessGate struct {
+	// name of gate, must be unique in a application
+	Name string `json:"name"`
+
+	// check dependency using a http get request, 2xx and 3xx status code mean ready
+	// +optional
+	HTTPGet *HTTPReadinessCheck `json:"httpGet,omitempty"`
+
+	// check dependency using grpc health check protocol
+	// +optional
+	GRPC *GRPCReadinessCheck `json:"grpc,omitempty"`
+
+	// timeout of a single check
+	// +optional, default 1 second
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+
+	// check result is cached for this period, dependency is checked again after cache expired
+	// +optional, default 10 seconds
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+}
+
+type HTTPReadinessCheck struct {
+	// full url of dependency, e.g. http://db-proxy:8080/healthz
+	URL string `json:"url"`
+}
+
+type GRPCReadinessCheck struct {
+	// address of grpc server, host:port
+	Address string `json:"address"`
+
+	// service name used in grpc health check request, empty means server overall health
+	// +optional
+	Service string `json:"service,omitempty"`
+}
+
+type SessionRuntime string
+
+const (
+	// container link fornax session sdk and talk to grpc session service of node agent
+	SessionRuntimeGrpc SessionRuntime = "grpc"
+
+	// a sidecar in pod speak websocket protocol of node agent for container can not link session sdk
+	SessionRuntimeWebSocket SessionRuntime = "websocket"
+)
+
 type ScalingPolicyType string
 
 const (
@@ -79,6 +468,9 @@ const (
 
 	// scaling according idle session percent
 	ScalingPolicyTypeIdleSessionNum ScalingPolicyType = "idle_session_number"
+
+	// scaling according pending sessions, and scale to zero after idle timeout
+	ScalingPolicyTypeSessionDemand ScalingPolicyType = "session_demand"
 )
 
 type ScalingPolicy struct {
@@ -94,6 +486,38 @@ type ScalingPolicy struct {
 
 	// +optional, must set if ScalingPolicyType == "idle_session_percent"
 	IdleSessionPercentThreshold *IdelSessionPercentThreshold `json:"idleSessionPercentThreshold,omitempty"`
+
+	// +optional, used if ScalingPolicyType == "session_demand", nil means default session demand policy
+	SessionDemand *SessionDemandScalingPolicy `json:"sessionDemand,omitempty"`
+}
+
+// SessionDemandScalingPolicy create a pod for each pending session plus a few idle pods for coming sessions,
+// when application does not have any session longer than idle timeout, all idle pods are deleted, application scale to zero,
+// minimum instance is ignored until a new session come
+type SessionDemandScalingPolicy struct {
+	// idle pods kept for coming sessions when application is active
+	// +optional, default 0
+	MinIdleInstances uint32 `json:"minIdleInstances,omitempty"`
+
+	// maximum number of pods, it override ScalingPolicy.MaximumInstance
+	// +optional, default ScalingPolicy.MaximumInstance
+	MaxInstances uint32 `json:"maxInstances,omitempty"`
+
+	// scale to zero when application does not have session in this period
+	// +optional, default 0, never scale to zero
+	IdleTimeoutSeconds int32 `json:"idleTimeoutSeconds,omitempty"`
+
+	// maximum pods created in one sync
+	// +optional, default ScalingPolicy.Burst
+	ScaleUpBurst uint32 `json:"scaleUpBurst,omitempty"`
+
+	// allow creating this percent of active pods in one sync when it's more than ScaleUpBurst, so a large application scale up faster
+	// +optional, default 0
+	ScaleUpPercent uint32 `json:"scaleUpPercent,omitempty"`
+
+	// maximum idle pods deleted in one sync
+	// +optional, default ScalingPolicy.Burst
+	ScaleDownBurst uint32 `json:"scaleDownBurst,omitempty"`
 }
 
 // high watermark should > low watermark, if both are 0, then no auto scaling for idle buffer,
@@ -129,6 +553,12 @@ const (
 
 	// delete instance
 	DeploymentActionDeleteInstance DeploymentAction = "DeleteInstance"
+
+	// rollout failed and was paused
+	DeploymentActionPauseRollout DeploymentAction = "PauseRollout"
+
+	// rollout failed and container spec was rolled back
+	DeploymentActionRollbackRollout DeploymentAction = "RollbackRollout"
 )
 
 type DeploymentStatus string
@@ -197,6 +627,129 @@ type ApplicationStatus struct {
 	// +patchStrategy=merge
 	// +listType=set
 	History []DeploymentHistory `json:"history,omitempty" patchStrategy:"merge" patchMergeKey:"updateTime"`
+
+	// rotation status of application secret
+	// +optional
+	SecretStatus *ApplicationSecretStatus `json:"secretStatus,omitempty"`
+
+	// certificate currently delivered to application pods
+	// +optional
+	TLSStatus *ApplicationTLSStatus `json:"tlsStatus,omitempty"`
+
+	// application is suspended by suspension policy, all pods are deleted
+	// +optional
+	Suspended bool `json:"suspended,omitempty"`
+
+	// when application was suspended
+	// +optional
+	SuspendedTime *metav1.Time `json:"suspendedTime,omitempty"`
+
+	// progress of replacing pods created from a old container spec
+	// +optional
+	Rollout *ApplicationRolloutStatus `json:"rollout,omitempty"`
+
+	// node holding persistent volumes of each ordinal instance identity, pod of identity is only placed on this node
+	// +optional
+	InstanceNodes map[string]string `json:"instanceNodes,omitempty"`
+}
+
+type RolloutPhase string
+
+const (
+	// there are pods created from old container spec
+	RolloutPhaseProgressing RolloutPhase = "Progressing"
+
+	// all pods are created from current container spec
+	RolloutPhaseComplete RolloutPhase = "Complete"
+
+	// new pods failed health check of rollout policy, old pods are not replaced anymore
+	RolloutPhasePaused RolloutPhase = "Paused"
+
+	// new pods failed health check of rollout policy, container spec was restored to last revision
+	RolloutPhaseRolledBack RolloutPhase = "RolledBack"
+)
+
+// ApplicationRolloutStatus report rollout of a container spec change, idle pods of old spec are deleted,
+// sessions on occupied pods of old spec are drained, then closed after their close grace period
+type ApplicationRolloutStatus struct {
+	// hash of container spec of current pods
+	TemplateHash string `json:"templateHash,omitempty"`
+
+	Phase RolloutPhase `json:"phase,omitempty"`
+
+	// pods created from current container spec
+	UpdatedInstances int32 `json:"updatedInstances,omitempty"`
+
+	// occupied pods created from old container spec
+	OutdatedInstances int32 `json:"outdatedInstances,omitempty"`
+
+	// open sessions on outdated pods being drained
+	DrainingSessions int32 `json:"drainingSessions,omitempty"`
+
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// hash and container spec of last revision, rollout policy restore them when rollout fail
+	// +optional
+	PreviousTemplateHash string `json:"previousTemplateHash,omitempty"`
+
+	// +optional
+	PreviousContainers []corev1.Container `json:"previousContainers,omitempty"`
+
+	// hash of revision this rollout rolled back from, health of a rollback rollout is not watched
+	// +optional
+	RollbackOf string `json:"rollbackOf,omitempty"`
+
+	// sessions bound to new pods and how many of them failed in bake window
+	// +optional
+	Sessions int32 `json:"sessions,omitempty"`
+
+	// +optional
+	FailedSessions int32 `json:"failedSessions,omitempty"`
+
+	// new pods terminated unexpectedly in bake window
+	// +optional
+	FailedPods int32 `json:"failedPods,omitempty"`
+
+	// why rollout is paused or rolled back
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type ApplicationTLSStatus struct {
+	SerialNumber string `json:"serialNumber,omitempty"`
+
+	// +optional
+	DNSNames []string `json:"dnsNames,omitempty"`
+
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type ApplicationSecretStatus struct {
+	// secret version in application spec
+	CurrentVersion string `json:"currentVersion,omitempty"`
+
+	// previous secret version still valid on pods, it's empty after previous version is revoked
+	// +optional
+	PreviousVersion string `json:"previousVersion,omitempty"`
+
+	// when current version was rotated
+	// +optional
+	RotationTime *metav1.Time `json:"rotationTime,omitempty"`
+
+	// number of running pods which have picked up current version
+	UpdatedInstances int32 `json:"updatedInstances,omitempty"`
+
+	// running pods which have not picked up current version
+	// +optional
+	OutdatedInstances []string `json:"outdatedInstances,omitempty"`
 }
 
 var _ resource.Object = &Application{}
@@ -232,9 +785,36 @@ func (in *Application) IsStorageVersion() bool {
 	return true
 }
 
+// ValidPortProtocol tell if a port protocol can be mapped on node, empty protocol is tcp
+func ValidPortProtocol(protocol corev1.Protocol) bool {
+	return protocol == "" || protocol == corev1.ProtocolTCP || protocol == corev1.ProtocolUDP || protocol == corev1.ProtocolSCTP
+}
+
 func (in *Application) Validate(ctx context.Context) field.ErrorList {
 	errorList := make(field.ErrorList, 0)
 
+	if runtime, found := in.Annotations[AnnotationFornaxCoreSessionRuntime]; found &&
+		SessionRuntime(runtime) != SessionRuntimeGrpc && SessionRuntime(runtime) != SessionRuntimeWebSocket {
+		err := field.Error{
+			Type:     field.ErrorTypeNotSupported,
+			Field:    "Metadata.Annotations",
+			BadValue: runtime,
+			Detail:   "Session runtime must be grpc or websocket",
+		}
+		errorList = append(errorList, &err)
+	}
+	if port, found := in.Annotations[AnnotationFornaxCoreSessionSidecarPort]; found {
+		if v, err := strconv.Atoi(port); err != nil || v <= 0 || v > 65535 {
+			err := field.Error{
+				Type:     field.ErrorTypeInvalid,
+				Field:    "Metadata.Annotations",
+				BadValue: port,
+				Detail:   "Session sidecar port must be a number between 1 and 65535",
+			}
+			errorList = append(errorList, &err)
+		}
+	}
+
 	if len(in.Spec.Containers) == 0 {
 		err := field.Error{
 			Type:  field.ErrorTypeRequired,
@@ -243,6 +823,57 @@ func (in *Application) Validate(ctx context.Context) field.ErrorList {
 		errorList = append(errorList, &err)
 	}
 
+	ports := map[string]bool{}
+	for _, cont := range in.Spec.Containers {
+		request, hasRequest := cont.Resources.Requests[ResourceNvidiaGPU]
+		limit, hasLimit := cont.Resources.Limits[ResourceNvidiaGPU]
+		for _, gpus := range []corev1.ResourceList{cont.Resources.Requests, cont.Resources.Limits} {
+			if quantity, found := gpus[ResourceNvidiaGPU]; found && (quantity.Sign() < 0 || quantity.MilliValue()%1000 != 0) {
+				err := field.Error{
+					Type:     field.ErrorTypeInvalid,
+					Field:    "Spec.Containers.Resources",
+					BadValue: quantity.String(),
+					Detail:   "GPU quantity must be a non negative whole number",
+				}
+				errorList = append(errorList, &err)
+			}
+		}
+		if hasRequest && hasLimit && request.Cmp(limit) != 0 {
+			err := field.Error{
+				Type:     field.ErrorTypeInvalid,
+				Field:    "Spec.Containers.Resources",
+				BadValue: request.String(),
+				Detail:   "GPU request must be equal to limit, gpus can not be overcommitted",
+			}
+			errorList = append(errorList, &err)
+		}
+		for _, port := range cont.Ports {
+			if !ValidPortProtocol(port.Protocol) {
+				err := field.Error{
+					Type:     field.ErrorTypeNotSupported,
+					Field:    "Spec.Containers.Ports.Protocol",
+					BadValue: port.Protocol,
+					Detail:   "Protocol must be TCP, UDP or SCTP",
+				}
+				errorList = append(errorList, &err)
+			}
+			protocol := port.Protocol
+			if len(protocol) == 0 {
+				protocol = corev1.ProtocolTCP
+			}
+			key := fmt.Sprintf("%d/%s", port.ContainerPort, protocol)
+			if ports[key] {
+				err := field.Error{
+					Type:     field.ErrorTypeDuplicate,
+					Field:    "Spec.Containers.Ports",
+					BadValue: key,
+				}
+				errorList = append(errorList, &err)
+			}
+			ports[key] = true
+		}
+	}
+
 	if in.Spec.ScalingPolicy.MaximumInstance == 0 {
 		err := field.Error{
 			Type:   field.ErrorTypeInvalid,
@@ -309,6 +940,387 @@ func (in *Application) Validate(ctx context.Context) field.ErrorList {
 		errorList = append(errorList, &err)
 	}
 
+	gateNames := map[string]bool{}
+	for _, gate := range in.Spec.ReadinessGates {
+		if len(gate.Name) == 0 {
+			err := field.Error{
+				Type:  field.ErrorTypeRequired,
+				Field: "Spec.ReadinessGates.Name",
+			}
+			errorList = append(errorList, &err)
+		} else if gateNames[gate.Name] {
+			err := field.Error{
+				Type:     field.ErrorTypeDuplicate,
+				Field:    "Spec.ReadinessGates.Name",
+				BadValue: gate.Name,
+			}
+			errorList = append(errorList, &err)
+		}
+		gateNames[gate.Name] = true
+
+		if (gate.HTTPGet == nil) == (gate.GRPC == nil) {
+			err := field.Error{
+				Type:     field.ErrorTypeInvalid,
+				Field:    "Spec.ReadinessGates",
+				BadValue: gate.Name,
+				Detail:   "One and only one of HTTPGet and GRPC must be set",
+			}
+			errorList = append(errorList, &err)
+		}
+
+		if gate.HTTPGet != nil && len(gate.HTTPGet.URL) == 0 {
+			err := field.Error{
+				Type:     field.ErrorTypeRequired,
+				Field:    "Spec.ReadinessGates.HTTPGet.URL",
+				BadValue: gate.Name,
+			}
+			errorList = append(errorList, &err)
+		}
+
+		if gate.GRPC != nil && len(gate.GRPC.Address) == 0 {
+			err := field.Error{
+				Type:     field.ErrorTypeRequired,
+				Field:    "Spec.ReadinessGates.GRPC.Address",
+				BadValue: gate.Name,
+			}
+			errorList = append(errorList, &err)
+		}
+
+		if gate.TimeoutSeconds < 0 || gate.PeriodSeconds < 0 {
+			err := field.Error{
+				Type:     field.ErrorTypeInvalid,
+				Field:    "Spec.ReadinessGates",
+				BadValue: gate.Name,
+				Detail:   "TimeoutSeconds and PeriodSeconds must not be negative",
+			}
+			errorList = append(errorList, &err)
+		}
+	}
+
+	if in.Spec.SessionHealthCheck != nil &&
+		in.Spec.SessionHealthCheck.Type != SessionHealthCheckTypeHTTP && in.Spec.SessionHealthCheck.Type != SessionHealthCheckTypeTCP {
+		err := field.Error{
+			Type:     field.ErrorTypeNotSupported,
+			Field:    "Spec.SessionHealthCheck.Type",
+			BadValue: in.Spec.SessionHealthCheck.Type,
+			Detail:   "Type must be http or tcp",
+		}
+		errorList = append(errorList, &err)
+	}
+
+	if in.Spec.Secret != nil && len(in.Spec.Secret.Version) == 0 {
+		err := field.Error{
+			Type:     field.ErrorTypeRequired,
+			Field:    "Spec.Secret.Version",
+			BadValue: in.Spec.Secret.Version,
+		}
+		errorList = append(errorList, &err)
+	}
+
+	if in.Spec.Secret != nil && len(in.Spec.Secret.Data) > 0 {
+		err := field.Error{
+			Type:     field.ErrorTypeForbidden,
+			Field:    "Spec.Secret.Data",
+			BadValue: "",
+			Detail:   "secret data is not sealed, fornaxcore secret key is not available",
+		}
+		errorList = append(errorList, &err)
+	}
+
+	if in.Spec.Secret != nil && in.Spec.Secret.RevokeGracePeriodSeconds < 0 {
+		err := field.Error{
+			Type:     field.ErrorTypeInvalid,
+			Field:    "Spec.Secret.RevokeGracePeriodSeconds",
+			BadValue: in.Spec.Secret.RevokeGracePeriodSeconds,
+			Detail:   "RevokeGracePeriodSeconds must not be negative",
+		}
+		errorList = append(errorList, &err)
+	}
+
+	if in.Spec.TLS != nil && (in.Spec.TLS.ValidityHours < 0 || in.Spec.TLS.RenewBeforeHours < 0 ||
+		(in.Spec.TLS.ValidityHours > 0 && in.Spec.TLS.RenewBeforeHours >= in.Spec.TLS.ValidityHours)) {
+		err := field.Error{
+			Type:     field.ErrorTypeInvalid,
+			Field:    "Spec.TLS.RenewBeforeHours",
+			BadValue: in.Spec.TLS.RenewBeforeHours,
+			Detail:   "ValidityHours and RenewBeforeHours must not be negative, and RenewBeforeHours must be less than ValidityHours",
+		}
+		errorList = append(errorList, &err)
+	}
+
+	if in.Spec.ScalingPolicy.SessionDemand != nil {
+		demand := in.Spec.ScalingPolicy.SessionDemand
+		if demand.IdleTimeoutSeconds < 0 {
+			err := field.Error{
+				Type:     field.ErrorTypeInvalid,
+				Field:    "Spec.ScalingPolicy.SessionDemand.IdleTimeoutSeconds",
+				BadValue: demand.IdleTimeoutSeconds,
+				Detail:   "IdleTimeoutSeconds must not be negative",
+			}
+			errorList = append(errorList, &err)
+		}
+		maxInstances := in.Spec.ScalingPolicy.MaximumInstance
+		if demand.MaxInstances > 0 {
+			maxInstances = demand.MaxInstances
+		}
+		if demand.MinIdleInstances > maxInstances {
+			err := field.Error{
+				Type:     field.ErrorTypeInvalid,
+				Field:    "Spec.ScalingPolicy.SessionDemand.MinIdleInstances",
+				BadValue: demand.MinIdleInstances,
+				Detail:   "MinIdleInstances must not be more than maximum instances",
+			}
+			errorList = append(errorList, &err)
+		}
+		if demand.MaxInstances > 0 && demand.MaxInstances < in.Spec.ScalingPolicy.MinimumInstance {
+			err := field.Error{
+				Type:     field.ErrorTypeInvalid,
+				Field:    "Spec.ScalingPolicy.SessionDemand.MaxInstances",
+				BadValue: demand.MaxInstances,
+				Detail:   "MaxInstances must not be less than Spec.ScalingPolicy.MinimumInstance",
+			}
+			errorList = append(errorList, &err)
+		}
+		if demand.ScaleUpPercent > 100 {
+			err := field.Error{
+				Type:     field.ErrorTypeInvalid,
+				Field:    "Spec.ScalingPolicy.SessionDemand.ScaleUpPercent",
+				BadValue: demand.ScaleUpPercent,
+				Detail:   "ScaleUpPercent must not be more than 100",
+			}
+			errorList = append(errorList, &err)
+		}
+	}
+
+	if in.Spec.NATTraversal != nil {
+		for i, v := range in.Spec.NATTraversal.STUNServers {
+			if _, _, err := net.SplitHostPort(v); err != nil {
+				err := field.Error{
+					Type:     field.ErrorTypeInvalid,
+					Field:    fmt.Sprintf("Spec.NATTraversal.STUNServers[%d]", i),
+					BadValue: v,
+					Detail:   "stun server must be host:port",
+				}
+				errorList = append(errorList, &err)
+			}
+		}
+		if relay := in.Spec.NATTraversal.Relay; relay != nil {
+			if _, _, err := net.SplitHostPort(relay.Address); err != nil {
+				err := field.Error{
+					Type:     field.ErrorTypeInvalid,
+					Field:    "Spec.NATTraversal.Relay.Address",
+					BadValue: relay.Address,
+					Detail:   "relay address must be host:port",
+				}
+				errorList = append(errorList, &err)
+			}
+		}
+	}
+
+	if in.Spec.WarmPool != nil && in.Spec.WarmPool.Size > in.Spec.ScalingPolicy.MaximumInstance {
+		err := field.Error{
+			Type:     field.ErrorTypeInvalid,
+			Field:    "Spec.WarmPool.Size",
+			BadValue: in.Spec.WarmPool.Size,
+			Detail:   "Size must not be more than Spec.ScalingPolicy.MaximumInstance",
+		}
+		errorList = append(errorList, &err)
+	}
+
+	if in.Spec.WarmPool != nil && in.Spec.WarmPool.MaxCapacityPercent > 100 {
+		err := field.Error{
+			Type:     field.ErrorTypeInvalid,
+			Field:    "Spec.WarmPool.MaxCapacityPercent",
+			BadValue: in.Spec.WarmPool.MaxCapacityPercent,
+			Detail:   "MaxCapacityPercent must not be more than 100",
+		}
+		errorList = append(errorList, &err)
+	}
+
+	if warmUp := in.Spec.WarmUp; warmUp != nil {
+		if (warmUp.Handler.Exec == nil) == (warmUp.Handler.HTTPGet == nil) {
+			err := field.Error{
+				Type:   field.ErrorTypeInvalid,
+				Field:  "Spec.WarmUp.Handler",
+				Detail: "Warm up must have either exec or httpGet",
+			}
+			errorList = append(errorList, &err)
+		}
+		if warmUp.TimeoutSeconds < 0 || warmUp.MaxAttempts < 0 {
+			err := field.Error{
+				Type:   field.ErrorTypeInvalid,
+				Field:  "Spec.WarmUp",
+				Detail: "Warm up timeout and max attempts must not be negative",
+			}
+			errorList = append(errorList, &err)
+		}
+		found := len(warmUp.Container) == 0
+		for _, v := range in.Spec.Containers {
+			found = found || v.Name == warmUp.Container
+		}
+		if !found {
+			err := field.Error{
+				Type:     field.ErrorTypeNotFound,
+				Field:    "Spec.WarmUp.Container",
+				BadValue: warmUp.Container,
+				Detail:   "Warm up container is not a application container",
+			}
+			errorList = append(errorList, &err)
+		}
+	}
+
+	if in.Spec.RolloutPolicy != nil {
+		policy := in.Spec.RolloutPolicy
+		if policy.BakeSeconds < 0 || policy.MinSessions < 0 || policy.MaxPodFailures < 0 || policy.MaxSessionFailurePercent < 0 || policy.MaxSessionFailurePercent > 100 {
+			err := field.Error{
+				Type:     field.ErrorTypeInvalid,
+				Field:    "Spec.RolloutPolicy",
+				BadValue: *policy,
+				Detail:   "Thresholds must not be negative, and MaxSessionFailurePercent must not be more than 100",
+			}
+			errorList = append(errorList, &err)
+		}
+		if len(policy.FailureAction) > 0 && policy.FailureAction != RolloutFailureActionPause && policy.FailureAction != RolloutFailureActionRollback {
+			err := field.Error{
+				Type:     field.ErrorTypeNotSupported,
+				Field:    "Spec.RolloutPolicy.FailureAction",
+				BadValue: policy.FailureAction,
+				Detail:   "FailureAction must be Pause or Rollback",
+			}
+			errorList = append(errorList, &err)
+		}
+	}
+
+	if in.Spec.SuspensionPolicy != nil && in.Spec.SuspensionPolicy.IdleSeconds <= 0 {
+		err := field.Error{
+			Type:     field.ErrorTypeInvalid,
+			Field:    "Spec.SuspensionPolicy.IdleSeconds",
+			BadValue: in.Spec.SuspensionPolicy.IdleSeconds,
+			Detail:   "IdleSeconds must be greater than 0",
+		}
+		errorList = append(errorList, &err)
+	}
+
+	if in.Spec.Ownership != nil {
+		for _, msg := range validation.IsValidLabelValue(in.Spec.Ownership.Team) {
+			err := field.Error{
+				Type:     field.ErrorTypeInvalid,
+				Field:    "Spec.Ownership.Team",
+				BadValue: in.Spec.Ownership.Team,
+				Detail:   msg,
+			}
+			errorList = append(errorList, &err)
+		}
+		if len(in.Spec.Ownership.Team) == 0 {
+			err := field.Error{
+				Type:  field.ErrorTypeRequired,
+				Field: "Spec.Ownership.Team",
+			}
+			errorList = append(errorList, &err)
+		}
+		for _, owner := range in.Spec.Ownership.Owners {
+			if len(owner) == 0 {
+				err := field.Error{
+					Type:   field.ErrorTypeInvalid,
+					Field:  "Spec.Ownership.Owners",
+					Detail: "Owner must not be empty",
+				}
+				errorList = append(errorList, &err)
+			}
+		}
+	}
+
+	sysctls := map[string]bool{}
+	for _, v := range in.Spec.Sysctls {
+		if !IsSafeSysctl(v.Name) {
+			err := field.Error{
+				Type:     field.ErrorTypeNotSupported,
+				Field:    "Spec.Sysctls.Name",
+				BadValue: v.Name,
+				Detail:   fmt.Sprintf("Sysctl must be one of %s", strings.Join(SafeSysctls, ", ")),
+			}
+			errorList = append(errorList, &err)
+		}
+		if sysctls[v.Name] {
+			err := field.Error{
+				Type:     field.ErrorTypeDuplicate,
+				Field:    "Spec.Sysctls.Name",
+				BadValue: v.Name,
+			}
+			errorList = append(errorList, &err)
+		}
+		sysctls[v.Name] = true
+		if len(strings.TrimSpace(v.Value)) == 0 || strings.ContainsAny(v.Value, "\n") {
+			err := field.Error{
+				Type:     field.ErrorTypeInvalid,
+				Field:    "Spec.Sysctls.Value",
+				BadValue: v.Value,
+				Detail:   "Sysctl value must be a non empty single line",
+			}
+			errorList = append(errorList, &err)
+		}
+	}
+
+	if restart := in.Spec.RestartPolicy; restart != nil {
+		switch restart.Policy {
+		case "", corev1.RestartPolicyNever, corev1.RestartPolicyOnFailure, corev1.RestartPolicyAlways:
+		default:
+			err := field.Error{
+				Type:     field.ErrorTypeNotSupported,
+				Field:    "Spec.RestartPolicy.Policy",
+				BadValue: restart.Policy,
+				Detail:   "Restart policy must be one of Never, OnFailure, Always",
+			}
+			errorList = append(errorList, &err)
+		}
+		if restart.InitialBackoffSeconds < 0 || restart.MaxBackoffSeconds < 0 {
+			err := field.Error{
+				Type:   field.ErrorTypeInvalid,
+				Field:  "Spec.RestartPolicy",
+				Detail: "Restart backoff seconds must not be negative",
+			}
+			errorList = append(errorList, &err)
+		} else if restart.InitialBackoffSeconds > 0 && restart.MaxBackoffSeconds > 0 && restart.InitialBackoffSeconds > restart.MaxBackoffSeconds {
+			err := field.Error{
+				Type:     field.ErrorTypeInvalid,
+				Field:    "Spec.RestartPolicy.MaxBackoffSeconds",
+				BadValue: restart.MaxBackoffSeconds,
+				Detail:   "Max backoff seconds must not be less than initial backoff seconds",
+			}
+			errorList = append(errorList, &err)
+		}
+	}
+
+	if limits := in.Spec.ProcessLimits; limits != nil {
+		if limits.PodPidsLimit < 0 {
+			err := field.Error{
+				Type:     field.ErrorTypeInvalid,
+				Field:    "Spec.ProcessLimits.PodPidsLimit",
+				BadValue: limits.PodPidsLimit,
+				Detail:   "Pod pids limit must not be negative",
+			}
+			errorList = append(errorList, &err)
+		}
+		if limits.OOMScoreAdj != nil && (*limits.OOMScoreAdj < -1000 || *limits.OOMScoreAdj > 1000) {
+			err := field.Error{
+				Type:     field.ErrorTypeInvalid,
+				Field:    "Spec.ProcessLimits.OOMScoreAdj",
+				BadValue: *limits.OOMScoreAdj,
+				Detail:   "OOM score adj must be between -1000 and 1000",
+			}
+			errorList = append(errorList, &err)
+		}
+	}
+
+	if policy := in.Spec.NetworkPolicy; policy != nil {
+		errorList = append(errorList, validateNetworkPolicyRules("Spec.NetworkPolicy.Ingress", policy.Ingress)...)
+		errorList = append(errorList, validateNetworkPolicyRules("Spec.NetworkPolicy.Egress", policy.Egress)...)
+	}
+
+	if naming := in.Spec.InstanceNaming; naming != nil {
+		errorList = append(errorList, validateInstanceNaming(naming, field.NewPath("Spec.InstanceNaming"))...)
+	}
+
 	if len(errorList) > 0 {
 		return errorList
 	} else {
@@ -316,6 +1328,97 @@ func (in *Application) Validate(ctx context.Context) field.ErrorList {
 	}
 }
 
+func validateInstanceNaming(naming *ApplicationInstanceNaming, path *field.Path) field.ErrorList {
+	errorList := make(field.ErrorList, 0)
+	switch naming.Strategy {
+	case "", InstanceNamingStrategyRandom:
+		if len(naming.VolumeClaimTemplates) > 0 {
+			errorList = append(errorList, field.Forbidden(path.Child("VolumeClaimTemplates"), "volume claims require Ordinal instance naming"))
+		}
+	case InstanceNamingStrategyOrdinal:
+	default:
+		errorList = append(errorList, field.NotSupported(path.Child("Strategy"), naming.Strategy, []string{string(InstanceNamingStrategyRandom), string(InstanceNamingStrategyOrdinal)}))
+	}
+	names := map[string]bool{}
+	for i, v := range naming.VolumeClaimTemplates {
+		for _, msg := range validation.IsDNS1123Label(v.Name) {
+			errorList = append(errorList, field.Invalid(path.Child("VolumeClaimTemplates").Index(i).Child("Name"), v.Name, msg))
+		}
+		if names[v.Name] {
+			errorList = append(errorList, field.Duplicate(path.Child("VolumeClaimTemplates").Index(i).Child("Name"), v.Name))
+		}
+		names[v.Name] = true
+		if !filepath.IsAbs(v.MountPath) {
+			errorList = append(errorList, field.Invalid(path.Child("VolumeClaimTemplates").Index(i).Child("MountPath"), v.MountPath, "must be a absolute path"))
+		}
+	}
+	return errorList
+}
+
+func validateNetworkPolicyRules(fieldName string, rules []NetworkPolicyRule) field.ErrorList {
+	errorList := make(field.ErrorList, 0)
+	for i, rule := range rules {
+		for _, cidr := range rule.CIDRs {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				err := field.Error{
+					Type:     field.ErrorTypeInvalid,
+					Field:    fmt.Sprintf("%s[%d].CIDRs", fieldName, i),
+					BadValue: cidr,
+					Detail:   "CIDR must be a valid ipv4 or ipv6 cidr",
+				}
+				errorList = append(errorList, &err)
+			}
+		}
+		for _, port := range rule.Ports {
+			if !ValidPortProtocol(port.Protocol) {
+				err := field.Error{
+					Type:     field.ErrorTypeNotSupported,
+					Field:    fmt.Sprintf("%s[%d].Ports.Protocol", fieldName, i),
+					BadValue: port.Protocol,
+					Detail:   "Protocol must be one of TCP, UDP, SCTP",
+				}
+				errorList = append(errorList, &err)
+			}
+			if port.Port < 0 || port.Port > 65535 || port.EndPort < 0 || port.EndPort > 65535 || (port.EndPort > 0 && (port.Port == 0 || port.EndPort < port.Port)) {
+				err := field.Error{
+					Type:     field.ErrorTypeInvalid,
+					Field:    fmt.Sprintf("%s[%d].Ports", fieldName, i),
+					BadValue: fmt.Sprintf("%d-%d", port.Port, port.EndPort),
+					Detail:   "Port must be between 0 and 65535, end port must not be less than port",
+				}
+				errorList = append(errorList, &err)
+			}
+		}
+	}
+	return errorList
+}
+
+var _ resourcestrategy.PrepareForCreater = &Application{}
+var _ resourcestrategy.PrepareForUpdater = &Application{}
+
+// PrepareForCreate set team label from ownership and seal secret data
+func (in *Application) PrepareForCreate(ctx context.Context) {
+	in.syncTeamLabel()
+	in.sealSecret()
+}
+
+// PrepareForUpdate set team label from ownership, team label is removed if ownership is removed, new secret data is sealed
+func (in *Application) PrepareForUpdate(ctx context.Context, old runtime.Object) {
+	in.syncTeamLabel()
+	in.sealSecret()
+}
+
+func (in *Application) syncTeamLabel() {
+	if in.Spec.Ownership == nil || len(in.Spec.Ownership.Team) == 0 {
+		delete(in.Labels, LabelFornaxCoreTeam)
+		return
+	}
+	if in.Labels == nil {
+		in.Labels = map[string]string{}
+	}
+	in.Labels[LabelFornaxCoreTeam] = in.Spec.Ownership.Team
+}
+
 var _ resource.ObjectList = &ApplicationList{}
 
 func (in *ApplicationList) GetListMeta() *metav1.ListMeta {
@@ -0,0 +1,80 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateInstanceNaming(t *testing.T) {
+	data := ApplicationVolumeClaimTemplate{Name: "data", MountPath: "/data"}
+	tests := []struct {
+		name   string
+		naming *ApplicationInstanceNaming
+		errors []string
+	}{
+		{name: "default random", naming: &ApplicationInstanceNaming{}},
+		{name: "ordinal", naming: &ApplicationInstanceNaming{Strategy: InstanceNamingStrategyOrdinal}},
+		{name: "ordinal with volumes", naming: &ApplicationInstanceNaming{Strategy: InstanceNamingStrategyOrdinal, VolumeClaimTemplates: []ApplicationVolumeClaimTemplate{data}}},
+		{
+			name:   "random with volumes",
+			naming: &ApplicationInstanceNaming{Strategy: InstanceNamingStrategyRandom, VolumeClaimTemplates: []ApplicationVolumeClaimTemplate{data}},
+			errors: []string{"Spec.InstanceNaming.VolumeClaimTemplates"},
+		},
+		{
+			name:   "default strategy with volumes",
+			naming: &ApplicationInstanceNaming{VolumeClaimTemplates: []ApplicationVolumeClaimTemplate{data}},
+			errors: []string{"Spec.InstanceNaming.VolumeClaimTemplates"},
+		},
+		{
+			name:   "unknown strategy",
+			naming: &ApplicationInstanceNaming{Strategy: "Sequential"},
+			errors: []string{"Spec.InstanceNaming.Strategy"},
+		},
+		{
+			name: "invalid volume claims",
+			naming: &ApplicationInstanceNaming{
+				Strategy: InstanceNamingStrategyOrdinal,
+				VolumeClaimTemplates: []ApplicationVolumeClaimTemplate{
+					data,
+					{Name: "Data_1", MountPath: "/data1"},
+					{Name: "data", MountPath: "relative"},
+				},
+			},
+			errors: []string{
+				"Spec.InstanceNaming.VolumeClaimTemplates[1].Name",
+				"Spec.InstanceNaming.VolumeClaimTemplates[2].Name",
+				"Spec.InstanceNaming.VolumeClaimTemplates[2].MountPath",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateInstanceNaming(tt.naming, field.NewPath("Spec.InstanceNaming"))
+			if len(errs) != len(tt.errors) {
+				t.Fatalf("validateInstanceNaming() = %v, want errors of %v", errs, tt.errors)
+			}
+			for i, err := range errs {
+				if err.Field != tt.errors[i] {
+					t.Errorf("error %d field = %s, want %s", i, err.Field, tt.errors[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"sigs.k8s.io/apiserver-runtime/pkg/builder/resource"
+	contextutil "sigs.k8s.io/apiserver-runtime/pkg/util/context"
+)
+
+// ContainerLogsFunc open a log stream of a container of pod, pod name is namespaced name of pod, errors should be api errors
+type ContainerLogsFunc func(ctx context.Context, podName string, opts *corev1.PodLogOptions) (io.ReadCloser, error)
+
+var (
+	_ContainerLogsMutex = &sync.RWMutex{}
+	_ContainerLogsFunc  ContainerLogsFunc
+)
+
+// SetContainerLogsFunc register a func to stream container logs from node agent, logs subresource is unavailable until it's set
+func SetContainerLogsFunc(f ContainerLogsFunc) {
+	_ContainerLogsMutex.Lock()
+	defer _ContainerLogsMutex.Unlock()
+	_ContainerLogsFunc = f
+}
+
+func getContainerLogsFunc() ContainerLogsFunc {
+	_ContainerLogsMutex.RLock()
+	defer _ContainerLogsMutex.RUnlock()
+	return _ContainerLogsFunc
+}
+
+var _ resource.ConnectorSubResource = &ApplicationSessionLogs{}
+
+// ApplicationSessionLogs is logs subresource of ApplicationSession, a get of it stream container logs of pod session is bound to,
+// query parameters are same as pod logs, e.g. container, follow, tailLines, sinceSeconds, sinceTime, timestamps and limitBytes
+// +k8s:deepcopy-gen=false
+type ApplicationSessionLogs struct{}
+
+func (l *ApplicationSessionLogs) SubResourceName() string {
+	return "logs"
+}
+
+func (l *ApplicationSessionLogs) New() runtime.Object {
+	return &ApplicationSession{}
+}
+
+// NewConnectOptions return nil options, api server do not know how to convert query to pod log options, handler parse it
+func (l *ApplicationSessionLogs) NewConnectOptions() (runtime.Object, bool, string) {
+	return nil, false, ""
+}
+
+func (l *ApplicationSessionLogs) ConnectMethods() []string {
+	return []string{http.MethodGet}
+}
+
+func (l *ApplicationSessionLogs) Connect(ctx context.Context, name string, options runtime.Object, responder rest.Responder) (http.Handler, error) {
+	parentStorage, ok := contextutil.GetParentStorageGetter(ctx)
+	if !ok {
+		return nil, fmt.Errorf("no parent storage found in context")
+	}
+	obj, err := parentStorage.Get(ctx, name, &metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	session := obj.(*ApplicationSession)
+	if session.Status.PodReference == nil || len(session.Status.PodReference.Name) == 0 {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("session %s is not bound to a pod, status %s", name, session.Status.SessionStatus))
+	}
+	podName := session.Status.PodReference.Name
+	containerLogs := getContainerLogsFunc()
+	if containerLogs == nil {
+		return nil, apierrors.NewServiceUnavailable("container logs are not served")
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		opts, err := ParsePodLogOptions(r.URL.Query())
+		if err != nil {
+			responder.Error(apierrors.NewBadRequest(err.Error()))
+			return
+		}
+		logs, err := containerLogs(r.Context(), podName, opts)
+		if err != nil {
+			responder.Error(err)
+			return
+		}
+		defer logs.Close()
+
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		out := io.Writer(w)
+		if flusher, ok := w.(http.Flusher); ok && opts.Follow {
+			out = &flushWriter{w: w, flusher: flusher}
+		}
+		io.Copy(out, logs)
+	}), nil
+}
+
+// flushWriter flush each write, so, followed logs are sent to client as soon as they're read
+type flushWriter struct {
+	w       io.Writer
+	flusher http.Flusher
+}
+
+func (f *flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	f.flusher.Flush()
+	return n, err
+}
+
+// ParsePodLogOptions parse pod log options from query parameters, options of previous container are not supported
+func ParsePodLogOptions(query url.Values) (*corev1.PodLogOptions, error) {
+	opts := &corev1.PodLogOptions{Container: query.Get("container")}
+	parseBool := func(key string) (bool, error) {
+		if v := query.Get(key); len(v) > 0 {
+			return strconv.ParseBool(v)
+		}
+		return false, nil
+	}
+	parseInt := func(key string) (*int64, error) {
+		v := query.Get(key)
+		if len(v) == 0 {
+			return nil, nil
+		}
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %s", key, v)
+		}
+		if i < 0 {
+			return nil, fmt.Errorf("%s must not be negative", key)
+		}
+		return &i, nil
+	}
+
+	var err error
+	if opts.Follow, err = parseBool("follow"); err != nil {
+		return nil, fmt.Errorf("invalid follow, %v", err)
+	}
+	if opts.Timestamps, err = parseBool("timestamps"); err != nil {
+		return nil, fmt.Errorf("invalid timestamps, %v", err)
+	}
+	if opts.Previous, err = parseBool("previous"); err != nil || opts.Previous {
+		return nil, fmt.Errorf("logs of previous container are not supported")
+	}
+	if opts.TailLines, err = parseInt("tailLines"); err != nil {
+		return nil, err
+	}
+	if opts.LimitBytes, err = parseInt("limitBytes"); err != nil {
+		return nil, err
+	}
+	if opts.SinceSeconds, err = parseInt("sinceSeconds"); err != nil {
+		return nil, err
+	}
+	if v := query.Get("sinceTime"); len(v) > 0 {
+		sinceTime, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sinceTime %s", v)
+		}
+		opts.SinceTime = &metav1.Time{Time: sinceTime}
+	}
+	if opts.SinceSeconds != nil && opts.SinceTime != nil {
+		return nil, fmt.Errorf("at most one of sinceTime or sinceSeconds may be specified")
+	}
+	return opts, nil
+}
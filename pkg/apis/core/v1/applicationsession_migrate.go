@@ -0,0 +1,91 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"sigs.k8s.io/apiserver-runtime/pkg/builder/resource"
+	contextutil "sigs.k8s.io/apiserver-runtime/pkg/util/context"
+)
+
+var _ resource.GetterUpdaterSubResource = &ApplicationSessionMigrate{}
+
+// ApplicationSessionMigrate is migrate subresource of ApplicationSession, a update of it request fornaxcore to move a open session
+// to status.migration.targetPod of request body, or any idle pod of application if target pod is empty,
+// request is saved as a annotation of session, fornaxcore track migration progress in status.migration
+// +k8s:deepcopy-gen=false
+type ApplicationSessionMigrate struct{}
+
+func (m *ApplicationSessionMigrate) SubResourceName() string {
+	return "migrate"
+}
+
+func (m *ApplicationSessionMigrate) New() runtime.Object {
+	return &ApplicationSession{}
+}
+
+func (m *ApplicationSessionMigrate) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	parentStorage, ok := contextutil.GetParentStorageGetter(ctx)
+	if !ok {
+		return nil, fmt.Errorf("no parent storage found in context")
+	}
+	return parentStorage.Get(ctx, name, options)
+}
+
+func (m *ApplicationSessionMigrate) Update(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+	parentStorage, ok := contextutil.GetParentStorage(ctx)
+	if !ok {
+		return nil, false, fmt.Errorf("no parent storage found in context")
+	}
+	obj, err := parentStorage.Get(ctx, name, &metav1.GetOptions{})
+	if err != nil {
+		return nil, false, err
+	}
+	session := obj.(*ApplicationSession)
+	requested, err := objInfo.UpdatedObject(ctx, session)
+	if err != nil {
+		return nil, false, err
+	}
+	targetPod := ""
+	if migration := requested.(*ApplicationSession).Status.Migration; migration != nil {
+		targetPod = migration.TargetPod
+	}
+
+	if session.DeletionTimestamp != nil || (session.Status.SessionStatus != SessionStatusAvailable && session.Status.SessionStatus != SessionStatusInUse) {
+		return nil, false, apierrors.NewBadRequest(fmt.Sprintf("session %s is not open, status %s", name, session.Status.SessionStatus))
+	}
+	if session.Status.PodReference != nil && session.Status.PodReference.Name == targetPod {
+		return nil, false, apierrors.NewBadRequest(fmt.Sprintf("session %s is already open on pod %s", name, targetPod))
+	}
+	if _, found := session.Annotations[AnnotationFornaxCoreMigrateSession]; found ||
+		(session.Status.Migration != nil && session.Status.Migration.Phase == SessionMigrationPhaseMigrating) {
+		return nil, false, apierrors.NewConflict(ApplicationSessionGrv.GroupResource(), name, fmt.Errorf("session is already being migrated"))
+	}
+
+	updated := session.DeepCopy()
+	if updated.Annotations == nil {
+		updated.Annotations = map[string]string{}
+	}
+	updated.Annotations[AnnotationFornaxCoreMigrateSession] = targetPod
+	return parentStorage.Update(ctx, name, rest.DefaultUpdatedObjectInfo(updated), createValidation, updateValidation, false, options)
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"sigs.k8s.io/apiserver-runtime/pkg/builder/resource"
+	contextutil "sigs.k8s.io/apiserver-runtime/pkg/util/context"
+)
+
+// ContainerStreamFunc return a handler which proxy a upgraded connection to a exec, attach or port forward stream of pod,
+// opts is a *corev1.PodExecOptions, *corev1.PodAttachOptions or *corev1.PodPortForwardOptions, errors should be api errors
+type ContainerStreamFunc func(ctx context.Context, podName string, opts runtime.Object, responder rest.Responder) (http.Handler, error)
+
+var (
+	_ContainerStreamMutex = &sync.RWMutex{}
+	_ContainerStreamFunc  ContainerStreamFunc
+)
+
+// SetContainerStreamFunc register a func to proxy container streams to node agent, exec, attach and portforward subresources are unavailable until it's set
+func SetContainerStreamFunc(f ContainerStreamFunc) {
+	_ContainerStreamMutex.Lock()
+	defer _ContainerStreamMutex.Unlock()
+	_ContainerStreamFunc = f
+}
+
+func getContainerStreamFunc() ContainerStreamFunc {
+	_ContainerStreamMutex.RLock()
+	defer _ContainerStreamMutex.RUnlock()
+	return _ContainerStreamFunc
+}
+
+var (
+	_ resource.ConnectorSubResource = &ApplicationSessionExec{}
+	_ resource.ConnectorSubResource = &ApplicationSessionAttach{}
+	_ resource.ConnectorSubResource = &ApplicationSessionPortForward{}
+
+	// streams are upgraded connections, kubectl use post, websocket clients use get
+	sessionStreamMethods = []string{http.MethodGet, http.MethodPost}
+)
+
+// ApplicationSessionExec is exec subresource of ApplicationSession, it run a command in a container of pod session is bound to,
+// query parameters are same as pod exec, e.g. command, container, stdin, stdout, stderr and tty
+// +k8s:deepcopy-gen=false
+type ApplicationSessionExec struct{}
+
+func (e *ApplicationSessionExec) SubResourceName() string {
+	return "exec"
+}
+
+func (e *ApplicationSessionExec) New() runtime.Object {
+	return &ApplicationSession{}
+}
+
+func (e *ApplicationSessionExec) NewConnectOptions() (runtime.Object, bool, string) {
+	return nil, false, ""
+}
+
+func (e *ApplicationSessionExec) ConnectMethods() []string {
+	return sessionStreamMethods
+}
+
+func (e *ApplicationSessionExec) Connect(ctx context.Context, name string, options runtime.Object, responder rest.Responder) (http.Handler, error) {
+	return connectSessionStream(ctx, name, responder, func(query url.Values) (runtime.Object, error) {
+		opts := &corev1.PodExecOptions{Container: query.Get("container"), Command: query["command"]}
+		if len(opts.Command) == 0 {
+			return nil, fmt.Errorf("command is required")
+		}
+		var err error
+		opts.Stdin, opts.Stdout, opts.Stderr, opts.TTY, err = parseStreamFlags(query)
+		return opts, err
+	})
+}
+
+// ApplicationSessionAttach is attach subresource of ApplicationSession, it attach to a running container of pod session is bound to,
+// query parameters are same as pod attach, e.g. container, stdin, stdout, stderr and tty
+// +k8s:deepcopy-gen=false
+type ApplicationSessionAttach struct{}
+
+func (a *ApplicationSessionAttach) SubResourceName() string {
+	return "attach"
+}
+
+func (a *ApplicationSessionAttach) New() runtime.Object {
+	return &ApplicationSession{}
+}
+
+func (a *ApplicationSessionAttach) NewConnectOptions() (runtime.Object, bool, string) {
+	return nil, false, ""
+}
+
+func (a *ApplicationSessionAttach) ConnectMethods() []string {
+	return sessionStreamMethods
+}
+
+func (a *ApplicationSessionAttach) Connect(ctx context.Context, name string, options runtime.Object, responder rest.Responder) (http.Handler, error) {
+	return connectSessionStream(ctx, name, responder, func(query url.Values) (runtime.Object, error) {
+		opts := &corev1.PodAttachOptions{Container: query.Get("container")}
+		var err error
+		opts.Stdin, opts.Stdout, opts.Stderr, opts.TTY, err = parseStreamFlags(query)
+		return opts, err
+	})
+}
+
+// ApplicationSessionPortForward is portforward subresource of ApplicationSession, it forward ports of pod session is bound to,
+// ports query parameter is a list of pod ports, ports can also be requested in streams of connection
+// +k8s:deepcopy-gen=false
+type ApplicationSessionPortForward struct{}
+
+func (p *ApplicationSessionPortForward) SubResourceName() string {
+	return "portforward"
+}
+
+func (p *ApplicationSessionPortForward) New() runtime.Object {
+	return &ApplicationSession{}
+}
+
+func (p *ApplicationSessionPortForward) NewConnectOptions() (runtime.Object, bool, string) {
+	return nil, false, ""
+}
+
+func (p *ApplicationSessionPortForward) ConnectMethods() []string {
+	return sessionStreamMethods
+}
+
+func (p *ApplicationSessionPortForward) Connect(ctx context.Context, name string, options runtime.Object, responder rest.Responder) (http.Handler, error) {
+	return connectSessionStream(ctx, name, responder, func(query url.Values) (runtime.Object, error) {
+		opts := &corev1.PodPortForwardOptions{}
+		for _, v := range query["ports"] {
+			for _, port := range strings.Split(v, ",") {
+				i, err := strconv.ParseUint(strings.TrimSpace(port), 10, 16)
+				if err != nil || i == 0 {
+					return nil, fmt.Errorf("invalid port %s", port)
+				}
+				opts.Ports = append(opts.Ports, int32(i))
+			}
+		}
+		return opts, nil
+	})
+}
+
+func parseStreamFlags(query url.Values) (stdin, stdout, stderr, tty bool, err error) {
+	flags := []*bool{&stdin, &stdout, &stderr, &tty}
+	for i, key := range []string{"stdin", "stdout", "stderr", "tty"} {
+		if v := query.Get(key); len(v) > 0 {
+			if *flags[i], err = strconv.ParseBool(v); err != nil {
+				return false, false, false, false, fmt.Errorf("invalid %s %s", key, v)
+			}
+		}
+	}
+	if !stdin && !stdout && !stderr {
+		return false, false, false, false, fmt.Errorf("at least one of stdin, stdout or stderr is required")
+	}
+	return stdin, stdout, stderr, tty, nil
+}
+
+// connectSessionStream find pod session is bound to, and return a handler parsing stream options from request query,
+// request is proxied to node agent of pod
+func connectSessionStream(ctx context.Context, name string, responder rest.Responder, parseOptions func(url.Values) (runtime.Object, error)) (http.Handler, error) {
+	parentStorage, ok := contextutil.GetParentStorageGetter(ctx)
+	if !ok {
+		return nil, fmt.Errorf("no parent storage found in context")
+	}
+	obj, err := parentStorage.Get(ctx, name, &metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	session := obj.(*ApplicationSession)
+	if session.Status.PodReference == nil || len(session.Status.PodReference.Name) == 0 {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("session %s is not bound to a pod, status %s", name, session.Status.SessionStatus))
+	}
+	podName := session.Status.PodReference.Name
+	containerStream := getContainerStreamFunc()
+	if containerStream == nil {
+		return nil, apierrors.NewServiceUnavailable("container streams are not served")
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		opts, err := parseOptions(r.URL.Query())
+		if err != nil {
+			responder.Error(apierrors.NewBadRequest(err.Error()))
+			return
+		}
+		handler, err := containerStream(r.Context(), podName, opts, responder)
+		if err != nil {
+			responder.Error(err)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}), nil
+}
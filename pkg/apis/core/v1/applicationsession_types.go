@@ -18,13 +18,18 @@ package v1
 
 import (
 	"context"
+	"fmt"
+	"net/url"
+	"regexp"
 
-	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/endpoints/request"
 	"sigs.k8s.io/apiserver-runtime/pkg/builder/resource"
 	"sigs.k8s.io/apiserver-runtime/pkg/builder/resource/resourcestrategy"
 )
@@ -57,10 +62,16 @@ type ApplicationSessionSpec struct {
 	// ApplicationName, client provided application
 	ApplicationName string `json:"applicationName,omitempty"`
 
-	// Session data is a base64 string pass through into application instances when session started
+	// Session data is a base64 string pass through into application instances when session started,
+	// it's kept in session object and watch events, so, it must not be larger than MaxSessionDataBytes,
+	// use Attachment for larger payload
 	// +optional
 	SessionData string `json:"sessionData,omitempty"`
 
+	// Attachment is a out of band payload of session, application instance download it from its url when session open
+	// +optional
+	Attachment *SessionAttachment `json:"attachment,omitempty"`
+
 	// if a application instance evacuated all session, kill it, default true
 	KillInstanceWhenSessionClosed bool `json:"killInstanceWhenSessionClosed,omitempty"`
 
@@ -69,6 +80,98 @@ type ApplicationSessionSpec struct {
 
 	// how long to wait for session status from Starting to Available
 	OpenTimeoutSeconds uint16 `json:"openTimeoutSeconds,omitempty"`
+
+	// Affinity co-locate or spread session with related sessions of same application, e.g. sessions of same client or game room
+	// +optional
+	Affinity *SessionAffinity `json:"affinity,omitempty"`
+
+	// sessions of same stickiness key, e.g. a user id, prefer pod which served last session of key if pod is still idle,
+	// so application can reuse its warm cache of key
+	// +optional
+	StickinessKey string `json:"stickinessKey,omitempty"`
+
+	// pending sessions of higher priority are bound to pods first, sessions of same priority are bound in creation order
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// node agent close session after it's open for this long, 0 use max session duration of application, session is not time limited if neither is set
+	// +optional
+	MaxSessionDurationSeconds uint32 `json:"maxSessionDurationSeconds,omitempty"`
+
+	// application is notified this many seconds before session reach its time limit, so it can tell clients to wrap up
+	// +optional, default 60
+	ExpiryWarningSeconds uint32 `json:"expiryWarningSeconds,omitempty"`
+
+	// session is only bound to application instance of this identity, e.g. game-0 of a application using Ordinal instance naming,
+	// session wait pending until instance is idle
+	// +optional
+	InstanceIdentity string `json:"instanceIdentity,omitempty"`
+}
+
+const (
+	MaxSessionDataBytes           = 64 * 1024
+	MaxSessionAttachmentBytes     = 1024 * 1024 * 1024
+	MaxSessionStickinessKeyLength = 256
+
+	// most recent clients left session kept in status
+	MaxLeftClientSessions = 16
+)
+
+var sha256HexRegexp = regexp.MustCompile("^[0-9a-f]{64}$")
+
+// SessionAttachment reference a session payload stored out of fornaxcore, e.g. a object storage pre-signed url,
+// only reference is passed to application instance, payload never go through fornaxcore store or watch streams
+type SessionAttachment struct {
+	// http or https url to download payload, e.g. a pre-signed url which expire after session open timeout
+	URL string `json:"url"`
+
+	// payload size in bytes, instance should reject a payload of different size
+	SizeBytes int64 `json:"sizeBytes"`
+
+	// lowercase hex sha256 digest of payload, instance verify downloaded payload if it's set
+	// +optional
+	SHA256 string `json:"sha256,omitempty"`
+
+	// media type of payload, e.g. application/octet-stream
+	// +optional
+	ContentType string `json:"contentType,omitempty"`
+}
+
+const (
+	// TopologyKeyNode is default topology of session affinity term, sessions on pods of same node are in same topology
+	TopologyKeyNode = v1.LabelHostname
+
+	MaxSessionAffinityWeight = 100
+)
+
+// SessionAffinity is evaluated when a pending session is assigned to a idle pod, related sessions are open or starting sessions
+// of same application whose labels match term's selector, required terms filter out idle pods, preferred terms score them
+type SessionAffinity struct {
+	// put session in same topology as related sessions, e.g. prefer same node as sessions of same client
+	// +optional
+	SessionAffinity []SessionAffinityTerm `json:"sessionAffinity,omitempty"`
+
+	// put session away from topology of related sessions, e.g. spread sessions of a game across zones
+	// +optional
+	SessionAntiAffinity []SessionAffinityTerm `json:"sessionAntiAffinity,omitempty"`
+}
+
+type SessionAffinityTerm struct {
+	// select related sessions by labels
+	LabelSelector *metav1.LabelSelector `json:"labelSelector"`
+
+	// node label whose value define a topology, e.g. topology.kubernetes.io/zone, default kubernetes.io/hostname which is node of pod
+	// +optional
+	TopologyKey string `json:"topologyKey,omitempty"`
+
+	// a required term must be satisfied, session stay pending until a idle pod satisfy it,
+	// a required affinity term is satisfied by any pod when there is no related session yet
+	// +optional
+	Required bool `json:"required,omitempty"`
+
+	// score of preferred term for each related session in same topology, 1-100, default 1
+	// +optional
+	Weight int32 `json:"weight,omitempty"`
 }
 
 // +enum
@@ -98,10 +201,21 @@ const (
 
 	// session is dead, no heartbeat, should close and start a new one
 	SessionStatusTimeout SessionStatus = "Timeout"
+
+	// session is ended by a instance failure, e.g. session process is oom killed, reason tell why
+	SessionStatusFailed SessionStatus = "Failed"
+)
+
+const (
+	// a process of session instance was killed by kernel oom killer
+	SessionReasonOOMKilled = "OOMKilled"
+
+	// session was open longer than its max session duration and was closed by node agent
+	SessionReasonTimeLimitExceeded = "TimeLimitExceeded"
 )
 
 type AccessEndPoint struct {
-	// TCP/UDP
+	// TCP/UDP/SCTP
 	Protocol v1.Protocol `json:"protocol,omitempty"`
 
 	// IPaddress
@@ -109,9 +223,47 @@ type AccessEndPoint struct {
 
 	// Port
 	Port int32 `json:"port,omitempty"`
+
+	// IPv4 or IPv6, a dual stack pod has a endpoint of each family for a port
+	// +optional
+	IPFamily v1.IPFamily `json:"ipFamily,omitempty"`
+
+	// tls server name client must send to reach session, set on session gateway sni endpoints
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
 }
 
+// +enum
+type SessionHealthStatus string
+
+const (
+	// session is not checked by fornaxcore, or application do not have session health check
+	SessionHealthStatusUnknown SessionHealthStatus = ""
+
+	// session pass health check of fornaxcore prober
+	SessionHealthStatusHealthy SessionHealthStatus = "Healthy"
+
+	// session failed health check of fornaxcore prober more than failure threshold
+	SessionHealthStatusUnhealthy SessionHealthStatus = "Unhealthy"
+)
+
 // ApplicationSessionStatus defines the observed state of ApplicationSession
+// ClientSessionReference is a end user connection of a session
+type ClientSessionReference struct {
+	// client identifier reported by container
+	Name string `json:"name"`
+
+	// +optional
+	SourceAddress string `json:"sourceAddress,omitempty"`
+
+	// +optional
+	JoinTime *metav1.Time `json:"joinTime,omitempty"`
+
+	// client left session if set
+	// +optional
+	LeaveTime *metav1.Time `json:"leaveTime,omitempty"`
+}
+
 type ApplicationSessionStatus struct {
 	// Endpoint this session is using
 	// +optional
@@ -125,10 +277,16 @@ type ApplicationSessionStatus struct {
 	// +optional
 	SessionStatus SessionStatus `json:"sessionStatus,omitempty"`
 
+	// end user connections attached to session reported by container, clients left session are kept for a while with leave time
 	// +optional
 	// +patchStrategy=merge
-	// +listType=set
-	ClientSessions []corev1.LocalObjectReference `json:"clientSessions,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+	// +listType=map
+	// +listMapKey=name
+	ClientSessions []ClientSessionReference `json:"clientSessions,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+
+	// number of clients attached to session now
+	// +optional
+	ClientSessionCount int32 `json:"clientSessionCount,omitempty"`
 
 	// +optional
 	AvailableTime *metav1.Time `json:"availableTime,omitempty"`
@@ -138,10 +296,141 @@ type ApplicationSessionStatus struct {
 
 	// +optional, for metrics test
 	AvailableTimeMicro int64 `json:"availableTimeMicro,omitempty"`
+
+	// health of session access endpoints checked by fornaxcore prober
+	// +optional
+	HealthStatus SessionHealthStatus `json:"healthStatus,omitempty"`
+
+	// machine readable reason why session failed, e.g. OOMKilled
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// human readable detail of session failure
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// quality of session calculated from pod pressure stall information when session is closed
+	// +optional
+	QualityScore *SessionQualityScore `json:"qualityScore,omitempty"`
+
+	// load of application observed by fornaxcore when session was created, client use it to back off when platform is saturated
+	// +optional
+	Backpressure *SessionBackpressure `json:"backpressure,omitempty"`
+
+	// public endpoints of udp ports discovered by node using stun servers of application, clients behind nat connect them directly
+	// +optional
+	PublicEndPoints []AccessEndPoint `json:"publicEndPoints,omitempty"`
+
+	// relay clients fallback to when they can not connect public endpoints
+	// +optional
+	Relay *SessionRelay `json:"relay,omitempty"`
+
+	// last migration of session to another pod, requested using migrate subresource
+	// +optional
+	Migration *SessionMigration `json:"migration,omitempty"`
+}
+
+// +enum
+type SessionMigrationPhase string
+
+const (
+	// session is being closed on source pod and reopened on target pod
+	SessionMigrationPhaseMigrating SessionMigrationPhase = "Migrating"
+
+	// session is open on target pod
+	SessionMigrationPhaseMigrated SessionMigrationPhase = "Migrated"
+
+	// session could not be moved, it stay on source pod if it was not closed yet, message tell why
+	SessionMigrationPhaseFailed SessionMigrationPhase = "MigrateFailed"
+)
+
+const (
+	// session metadata exported by application larger than this is not passed to target pod
+	MaxSessionMigrationMetadataBytes = 64 * 1024
+)
+
+// SessionMigration close a open session on its pod and reopen it on another pod of same application,
+// application on source pod export session metadata when it close session, metadata is passed to target pod when session reopen
+type SessionMigration struct {
+	Phase SessionMigrationPhase `json:"phase,omitempty"`
+
+	// pod session was open on when migration started
+	SourcePod string `json:"sourcePod,omitempty"`
+
+	// idle pod session is moved to, any idle pod of application is picked if it's empty
+	// +optional
+	TargetPod string `json:"targetPod,omitempty"`
+
+	// session metadata exported by application on source pod
+	// +optional
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// time source pod reported session closed
+	// +optional
+	SourceClosedTime *metav1.Time `json:"sourceClosedTime,omitempty"`
+
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// SessionBackpressure is a hint of how long a new session of application need to wait for a pod
+type SessionBackpressure struct {
+	// true if there are more pending sessions than idle and starting pods of application
+	Saturated bool `json:"saturated"`
+
+	// number of sessions of application waiting for a pod or starting, including this session
+	QueueDepth int32 `json:"queueDepth"`
+
+	// estimated seconds before session is available, calculated from recent session allocation latency
+	// +optional
+	EstimatedWaitSeconds int32 `json:"estimatedWaitSeconds,omitempty"`
+
+	// suggested seconds before client create another session of application, only set when saturated
+	// +optional
+	RetryAfterSeconds int32 `json:"retryAfterSeconds,omitempty"`
+}
+
+// SessionQualityScore is calculated from kernel pressure stall information(PSI) of pod cgroup during session lifetime,
+// stall percent is percentage of session time some tasks of pod were stalled on a resource
+type SessionQualityScore struct {
+	// 0-100, 100 means session was never stalled, it's 100 minus sum of cpu, memory and io stall percent
+	Score int32 `json:"score"`
+
+	// +optional
+	CPUStallPercent int32 `json:"cpuStallPercent,omitempty"`
+
+	// +optional
+	MemoryStallPercent int32 `json:"memoryStallPercent,omitempty"`
+
+	// +optional
+	IOStallPercent int32 `json:"ioStallPercent,omitempty"`
 }
 
 var _ resource.Object = &ApplicationSession{}
 var _ resourcestrategy.Validater = &ApplicationSession{}
+var _ resourcestrategy.PrepareForCreater = &ApplicationSession{}
+
+// PrepareForCreate set request id annotation if client does not provide one, audit id of api request is used,
+// so, session can also be found in api server audit log, it's carried in messages and logs of session in all components
+func (in *ApplicationSession) PrepareForCreate(ctx context.Context) {
+	if len(in.Annotations[AnnotationFornaxCoreRequestId]) > 0 {
+		return
+	}
+	requestId := string(uuid.NewUUID())
+	if auditId, found := request.AuditIDFrom(ctx); found && len(auditId) > 0 {
+		requestId = string(auditId)
+	}
+	if in.Annotations == nil {
+		in.Annotations = map[string]string{}
+	}
+	in.Annotations[AnnotationFornaxCoreRequestId] = requestId
+}
 
 func (in *ApplicationSession) GetObjectMeta() *metav1.ObjectMeta {
 	return &in.ObjectMeta
@@ -183,7 +472,7 @@ func (in *ApplicationSession) Validate(ctx context.Context) field.ErrorList {
 		errorList = append(errorList, &err)
 	}
 
-	if len(in.Spec.SessionData) == 0 {
+	if len(in.Spec.SessionData) == 0 && in.Spec.Attachment == nil {
 		err := field.Error{
 			Type:  field.ErrorTypeRequired,
 			Field: "Spec.SessionData",
@@ -191,6 +480,33 @@ func (in *ApplicationSession) Validate(ctx context.Context) field.ErrorList {
 		errorList = append(errorList, &err)
 	}
 
+	if len(in.Spec.SessionData) > MaxSessionDataBytes {
+		errorList = append(errorList, field.TooLong(field.NewPath("Spec.SessionData"), fmt.Sprintf("%d bytes", len(in.Spec.SessionData)), MaxSessionDataBytes))
+	}
+
+	if in.Spec.Attachment != nil {
+		errorList = append(errorList, validateSessionAttachment(in.Spec.Attachment, field.NewPath("Spec.Attachment"))...)
+	}
+
+	if len(in.Spec.StickinessKey) > MaxSessionStickinessKeyLength {
+		errorList = append(errorList, field.TooLong(field.NewPath("Spec.StickinessKey"), in.Spec.StickinessKey, MaxSessionStickinessKeyLength))
+	}
+
+	if len(in.Spec.InstanceIdentity) > 0 {
+		for _, msg := range validation.IsDNS1123Label(in.Spec.InstanceIdentity) {
+			errorList = append(errorList, field.Invalid(field.NewPath("Spec.InstanceIdentity"), in.Spec.InstanceIdentity, msg))
+		}
+	}
+
+	if in.Spec.Affinity != nil {
+		errorList = append(errorList, validateSessionAffinityTerms(in.Spec.Affinity.SessionAffinity, field.NewPath("Spec.Affinity.SessionAffinity"))...)
+		errorList = append(errorList, validateSessionAffinityTerms(in.Spec.Affinity.SessionAntiAffinity, field.NewPath("Spec.Affinity.SessionAntiAffinity"))...)
+	}
+
+	if in.Spec.MaxSessionDurationSeconds > 0 && in.Spec.ExpiryWarningSeconds >= in.Spec.MaxSessionDurationSeconds {
+		errorList = append(errorList, field.Invalid(field.NewPath("Spec.ExpiryWarningSeconds"), in.Spec.ExpiryWarningSeconds, "must be less than max session duration seconds"))
+	}
+
 	if len(errorList) > 0 {
 		return errorList
 	} else {
@@ -198,6 +514,35 @@ func (in *ApplicationSession) Validate(ctx context.Context) field.ErrorList {
 	}
 }
 
+func validateSessionAffinityTerms(terms []SessionAffinityTerm, path *field.Path) field.ErrorList {
+	errorList := field.ErrorList{}
+	for i, v := range terms {
+		if v.LabelSelector == nil {
+			errorList = append(errorList, field.Required(path.Index(i).Child("LabelSelector"), "related sessions must be selected by labels"))
+		} else if _, err := metav1.LabelSelectorAsSelector(v.LabelSelector); err != nil {
+			errorList = append(errorList, field.Invalid(path.Index(i).Child("LabelSelector"), v.LabelSelector, err.Error()))
+		}
+		if v.Weight < 0 || v.Weight > MaxSessionAffinityWeight {
+			errorList = append(errorList, field.Invalid(path.Index(i).Child("Weight"), v.Weight, fmt.Sprintf("must be between 0 and %d", MaxSessionAffinityWeight)))
+		}
+	}
+	return errorList
+}
+
+func validateSessionAttachment(attachment *SessionAttachment, path *field.Path) field.ErrorList {
+	errorList := field.ErrorList{}
+	if u, err := url.Parse(attachment.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		errorList = append(errorList, field.Invalid(path.Child("URL"), attachment.URL, "must be a absolute http or https url"))
+	}
+	if attachment.SizeBytes <= 0 || attachment.SizeBytes > MaxSessionAttachmentBytes {
+		errorList = append(errorList, field.Invalid(path.Child("SizeBytes"), attachment.SizeBytes, fmt.Sprintf("must be between 1 and %d", MaxSessionAttachmentBytes)))
+	}
+	if len(attachment.SHA256) > 0 && !sha256HexRegexp.MatchString(attachment.SHA256) {
+		errorList = append(errorList, field.Invalid(path.Child("SHA256"), attachment.SHA256, "must be a lowercase hex sha256 digest"))
+	}
+	return errorList
+}
+
 var _ resource.ObjectList = &ApplicationSessionList{}
 
 func (in *ApplicationSessionList) GetListMeta() *metav1.ListMeta {
@@ -221,3 +566,15 @@ var _ resource.StatusSubResource = &ApplicationSessionStatus{}
 func (in ApplicationSessionStatus) CopyTo(parent resource.ObjectWithStatusSubResource) {
 	parent.(*ApplicationSession).Status = in
 }
+
+var _ resource.ObjectWithArbitrarySubResource = &ApplicationSession{}
+
+func (in *ApplicationSession) GetArbitrarySubResources() []resource.ArbitrarySubResource {
+	return []resource.ArbitrarySubResource{
+		&ApplicationSessionMigrate{},
+		&ApplicationSessionLogs{},
+		&ApplicationSessionExec{},
+		&ApplicationSessionAttach{},
+		&ApplicationSessionPortForward{},
+	}
+}
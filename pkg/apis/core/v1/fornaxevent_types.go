@@ -0,0 +1,132 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/apiserver-runtime/pkg/builder/resource"
+)
+
+const (
+	// information only, e.g. session opened
+	FornaxEventTypeNormal = "Normal"
+	// something might go wrong, e.g. session timeout, node lost
+	FornaxEventTypeWarning = "Warning"
+
+	// events of cluster scoped objects like nodes are put in default namespace
+	FornaxEventClusterNamespace = "default"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FornaxEvent is a report of something happened to a application, session, pod or node, it's recorded by fornaxcore,
+// same events of a object are aggregated into one event with a count, events expire after one hour as kubernetes events,
+// events of a object are labeled with uid of object
+// +k8s:openapi-gen=true
+type FornaxEvent struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	InvolvedObject FornaxObjectReference `json:"involvedObject"`
+
+	// Normal or Warning
+	Type string `json:"type"`
+
+	// short CamelCase reason, e.g. SessionTimeout
+	Reason string `json:"reason"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// component reported event, e.g. application-manager or node agent of a node
+	// +optional
+	Source string `json:"source,omitempty"`
+
+	// +optional
+	FirstTimestamp metav1.Time `json:"firstTimestamp,omitempty"`
+
+	// +optional
+	LastTimestamp metav1.Time `json:"lastTimestamp,omitempty"`
+
+	// number of times this event happened
+	// +optional
+	Count int32 `json:"count,omitempty"`
+}
+
+// FornaxObjectReference refer to object of a event
+type FornaxObjectReference struct {
+	// Application, ApplicationSession, Pod or Node
+	Kind string `json:"kind"`
+
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	Name string `json:"name"`
+
+	// +optional
+	UID string `json:"uid,omitempty"`
+}
+
+// FornaxEventList
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type FornaxEventList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []FornaxEvent `json:"items"`
+}
+
+var _ resource.Object = &FornaxEvent{}
+
+func (in *FornaxEvent) GetObjectMeta() *metav1.ObjectMeta {
+	return &in.ObjectMeta
+}
+
+func (in *FornaxEvent) NamespaceScoped() bool {
+	return true
+}
+
+func (in *FornaxEvent) New() runtime.Object {
+	return &FornaxEvent{}
+}
+
+func (in *FornaxEvent) NewList() runtime.Object {
+	return &FornaxEventList{}
+}
+
+var FornaxEventGrv = schema.GroupVersionResource{
+	Group:    "core.fornax-serverless.centaurusinfra.io",
+	Version:  "v1",
+	Resource: "fornaxevents",
+}
+
+func (in *FornaxEvent) GetGroupVersionResource() schema.GroupVersionResource {
+	return FornaxEventGrv
+}
+
+func (in *FornaxEvent) IsStorageVersion() bool {
+	return true
+}
+
+var _ resource.ObjectList = &FornaxEventList{}
+
+func (in *FornaxEventList) GetListMeta() *metav1.ListMeta {
+	return &in.ListMeta
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/apiserver-runtime/pkg/builder/resource"
+	"sigs.k8s.io/apiserver-runtime/pkg/builder/resource/resourcestrategy"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FornaxQuota limit number of objects can be created in a namespace, creation exceeding any quota in namespace is forbidden
+// +k8s:openapi-gen=true
+type FornaxQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec FornaxQuotaSpec `json:"spec,omitempty"`
+}
+
+// FornaxQuotaList
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type FornaxQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []FornaxQuota `json:"items"`
+}
+
+// FornaxQuotaSpec defines max object counts of a namespace, nil means no limit
+type FornaxQuotaSpec struct {
+	// max number of application instances of all applications in namespace
+	// +optional
+	MaxApplicationInstances *int64 `json:"maxApplicationInstances,omitempty"`
+
+	// max number of application sessions in namespace, closed sessions are counted until they are deleted
+	// +optional
+	MaxApplicationSessions *int64 `json:"maxApplicationSessions,omitempty"`
+}
+
+var _ resource.Object = &FornaxQuota{}
+var _ resourcestrategy.Validater = &FornaxQuota{}
+
+func (in *FornaxQuota) GetObjectMeta() *metav1.ObjectMeta {
+	return &in.ObjectMeta
+}
+
+func (in *FornaxQuota) NamespaceScoped() bool {
+	return true
+}
+
+func (in *FornaxQuota) New() runtime.Object {
+	return &FornaxQuota{}
+}
+
+func (in *FornaxQuota) NewList() runtime.Object {
+	return &FornaxQuotaList{}
+}
+
+var FornaxQuotaGrv = schema.GroupVersionResource{
+	Group:    "core.fornax-serverless.centaurusinfra.io",
+	Version:  "v1",
+	Resource: "fornaxquotas",
+}
+
+func (in *FornaxQuota) GetGroupVersionResource() schema.GroupVersionResource {
+	return FornaxQuotaGrv
+}
+
+func (in *FornaxQuota) IsStorageVersion() bool {
+	return true
+}
+
+func (in *FornaxQuota) Validate(ctx context.Context) field.ErrorList {
+	errorList := make(field.ErrorList, 0)
+	if in.Spec.MaxApplicationInstances != nil && *in.Spec.MaxApplicationInstances < 0 {
+		errorList = append(errorList, field.Invalid(field.NewPath("spec", "maxApplicationInstances"), *in.Spec.MaxApplicationInstances, "must not be negative"))
+	}
+	if in.Spec.MaxApplicationSessions != nil && *in.Spec.MaxApplicationSessions < 0 {
+		errorList = append(errorList, field.Invalid(field.NewPath("spec", "maxApplicationSessions"), *in.Spec.MaxApplicationSessions, "must not be negative"))
+	}
+	return errorList
+}
+
+var _ resource.ObjectList = &FornaxQuotaList{}
+
+func (in *FornaxQuotaList) GetListMeta() *metav1.ListMeta {
+	return &in.ListMeta
+}
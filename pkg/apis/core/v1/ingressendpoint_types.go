@@ -19,6 +19,7 @@ package v1
 import (
 	"context"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -51,7 +52,7 @@ type IngressEndpointList struct {
 
 // IngressEndpointSpec defines the desired state of IngressEndpoint
 type IngressEndpointSpec struct {
-	// TCP/UDP
+	// TCP/UDP/SCTP
 	Protocol string `json:"protocol,omitempty"`
 
 	// is port application is accessible from ingress gateway
@@ -164,6 +165,16 @@ func (in *IngressEndpoint) Validate(ctx context.Context) field.ErrorList {
 		errorList = append(errorList, &err)
 	}
 
+	if len(in.Spec.Protocol) > 0 && !ValidPortProtocol(corev1.Protocol(in.Spec.Protocol)) {
+		err := field.Error{
+			Type:     field.ErrorTypeNotSupported,
+			Field:    "Spec.Protocol",
+			BadValue: in.Spec.Protocol,
+			Detail:   "Protocol must be TCP, UDP or SCTP",
+		}
+		errorList = append(errorList, &err)
+	}
+
 	if len(in.Spec.IngressGWIPAddress) == 0 {
 		err := field.Error{
 			Type:  field.ErrorTypeRequired,
@@ -27,8 +27,57 @@ const (
 	LabelFornaxCoreCreationUnixMicro      = "create.unixmicro.core.fornax-serverless.centaurusinfra.io"
 	LabelFornaxCoreApplicationSession     = "applicationsession.core.fornax-serverless.centaurusinfra.io"
 	LabelFornaxCoreSessionService         = "sessionservice.core.fornax-serverless.centaurusinfra.io"
+	LabelFornaxCoreTeam                   = "team.core.fornax-serverless.centaurusinfra.io"
+	LabelFornaxCoreTemplateHash           = "templatehash.core.fornax-serverless.centaurusinfra.io"
+	LabelFornaxCoreEventInvolvedUID       = "involveduid.event.core.fornax-serverless.centaurusinfra.io"
 	AnnotationFornaxCoreHibernatePod      = "hibernatepod.core.fornax-serverless.centaurusinfra.io"
 	AnnotationFornaxCoreSessionServicePod = "sessionservicepod.core.fornax-serverless.centaurusinfra.io"
+	AnnotationFornaxCoreSecretVersion     = "secretversion.core.fornax-serverless.centaurusinfra.io"
+	AnnotationFornaxCorePrevSecretVersion = "prevsecretversion.core.fornax-serverless.centaurusinfra.io"
+	AnnotationFornaxCoreRequestId         = "requestid.core.fornax-serverless.centaurusinfra.io"
+	AnnotationFornaxCoreSTUNServers       = "stunservers.core.fornax-serverless.centaurusinfra.io"
+	AnnotationFornaxCorePublicEndpoints   = "publicendpoints.core.fornax-serverless.centaurusinfra.io"
+
+	// hourly price of node, scheduler and session usage use spot price if it's set, otherwise instance cost, value is a decimal like 0.0416
+	LabelFornaxCoreNodeSpotPrice    = "spotprice.node.fornax-serverless.centaurusinfra.io"
+	LabelFornaxCoreNodeInstanceCost = "instancecost.node.fornax-serverless.centaurusinfra.io"
+
+	// target pod of a session migration requested by migrate subresource, a empty value let fornaxcore pick a idle pod,
+	// it's removed when fornaxcore start migration
+	AnnotationFornaxCoreMigrateSession = "migratesession.core.fornax-serverless.centaurusinfra.io"
+
+	// session runtime of application, grpc by default, websocket let node agent drive session lifecycle through a sidecar in pod,
+	// sidecar listen on port of AnnotationFornaxCoreSessionSidecarPort, both are copied from application to its pods
+	AnnotationFornaxCoreSessionRuntime     = "sessionruntime.core.fornax-serverless.centaurusinfra.io"
+	AnnotationFornaxCoreSessionSidecarPort = "sessionsidecarport.core.fornax-serverless.centaurusinfra.io"
+
+	// restart backoff of pod containers, <initial seconds>,<max seconds>, copied from application restart policy, restart policy itself is in pod spec
+	AnnotationFornaxCoreRestartBackoff = "restartbackoff.core.fornax-serverless.centaurusinfra.io"
+
+	// process limits of pod copied from application, node agent set pids limit of pod cgroup and oom_score_adj of containers
+	AnnotationFornaxCorePodPidsLimit = "podpidslimit.core.fornax-serverless.centaurusinfra.io"
+	AnnotationFornaxCoreOOMScoreAdj  = "oomscoreadj.core.fornax-serverless.centaurusinfra.io"
+
+	// json of application warm up, node agent run it before report pod running
+	AnnotationFornaxCoreWarmUp = "warmup.core.fornax-serverless.centaurusinfra.io"
+
+	// image pull priority of pod on node, Session for pods created for pending sessions, Prewarm for standby and warm pool pods
+	AnnotationFornaxCoreImagePullPriority = "imagepullpriority.core.fornax-serverless.centaurusinfra.io"
+
+	ImagePullPrioritySession = "Session"
+	ImagePullPriorityPrewarm = "Prewarm"
+
+	// json of gpu device ids allocated to each container of pod, set by node agent when pod is created
+	AnnotationFornaxCoreGPUDevices = "gpudevices.core.fornax-serverless.centaurusinfra.io"
+
+	// json of application network policy, node agent program it in pod network namespace before containers start
+	AnnotationFornaxCoreNetworkPolicy = "networkpolicy.core.fornax-serverless.centaurusinfra.io"
+
+	// stable identity of a pod of application using ordinal instance naming, it's pod name without namespace
+	LabelFornaxCoreInstanceIdentity = "instanceidentity.core.fornax-serverless.centaurusinfra.io"
+
+	// node which hold persistent volumes of a instance identity, scheduler only place pod on this node
+	AnnotationFornaxCoreRequiredNode = "requirednode.core.fornax-serverless.centaurusinfra.io"
 )
 
 var (
@@ -36,4 +85,10 @@ var (
 	ApplicationSessionKind   = SchemeGroupVersion.WithKind("ApplicationSession")
 	ApplicationGrvKey        = fmt.Sprintf("/%s/%s", ApplicationGrv.Group, ApplicationGrv.Resource)
 	ApplicationSessionGrvKey = fmt.Sprintf("/%s/%s", ApplicationSessionGrv.Group, ApplicationSessionGrv.Resource)
+	FornaxQuotaGrvKey        = fmt.Sprintf("/%s/%s", FornaxQuotaGrv.Group, FornaxQuotaGrv.Resource)
+	NodeOperationGrvKey      = fmt.Sprintf("/%s/%s", NodeOperationGrv.Group, NodeOperationGrv.Resource)
+	NodeLeaseGrvKey          = fmt.Sprintf("/%s/%s", NodeLeaseGrv.Group, NodeLeaseGrv.Resource)
+	NodeConfigProfileGrvKey  = fmt.Sprintf("/%s/%s", NodeConfigProfileGrv.Group, NodeConfigProfileGrv.Resource)
+	SessionUsageGrvKey       = fmt.Sprintf("/%s/%s", SessionUsageGrv.Group, SessionUsageGrv.Resource)
+	FornaxEventGrvKey        = fmt.Sprintf("/%s/%s", FornaxEventGrv.Group, FornaxEventGrv.Resource)
 )
@@ -0,0 +1,279 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strings"
+
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/apiserver-runtime/pkg/builder/resource"
+	"sigs.k8s.io/apiserver-runtime/pkg/builder/resource/resourcestrategy"
+)
+
+var (
+	sysctlNameRegexp    = regexp.MustCompile(`^[a-z0-9_]+([./][a-z0-9_-]+)*$`)
+	zramAlgorithmRegexp = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+	// hugepage sizes supported on x86_64 and arm64 with 4k base page
+	SupportedHugePageSizes = []string{"2Mi", "1Gi"}
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeConfigProfile describe host configuration of nodes selected by node selector, node agents reconcile their host to match it
+// and report whether host comply with it, when multiple profiles select a node, profile with highest priority win
+// +k8s:openapi-gen=true
+type NodeConfigProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NodeConfigProfileSpec   `json:"spec,omitempty"`
+	Status NodeConfigProfileStatus `json:"status,omitempty"`
+}
+
+// NodeConfigProfileList
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type NodeConfigProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []NodeConfigProfile `json:"items"`
+}
+
+type HugePageReservation struct {
+	// page size, 2Mi or 1Gi
+	PageSize string `json:"pageSize"`
+
+	// number of pages reserved on node
+	Count int32 `json:"count"`
+}
+
+type ZramConfig struct {
+	// enable a zram swap device, zram device is removed when it's false
+	Enabled bool `json:"enabled"`
+
+	// uncompressed size of zram device
+	// +optional
+	Size *k8sresource.Quantity `json:"size,omitempty"`
+
+	// compression algorithm, e.g. lz4, zstd
+	// +optional, default kernel default
+	Algorithm string `json:"algorithm,omitempty"`
+
+	// swap priority of zram device
+	// +optional, default 100
+	SwapPriority int32 `json:"swapPriority,omitempty"`
+}
+
+type EvictionThresholds struct {
+	// memory kept available on node, it's excluded from node allocatable memory
+	// +optional
+	MemoryAvailable *k8sresource.Quantity `json:"memoryAvailable,omitempty"`
+
+	// ephemeral storage kept available on node, it's excluded from node allocatable storage
+	// +optional
+	EphemeralStorageAvailable *k8sresource.Quantity `json:"ephemeralStorageAvailable,omitempty"`
+}
+
+type NodeConfigProfileSpec struct {
+	// nodes matching selector use this profile, empty selector select all nodes
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+
+	// profile with higher priority win when multiple profiles select a node, profile name break tie
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// kernel parameters set on node, e.g. net.core.somaxconn: "1024"
+	// +optional
+	Sysctls map[string]string `json:"sysctls,omitempty"`
+
+	// +optional
+	HugePages []HugePageReservation `json:"hugePages,omitempty"`
+
+	// +optional
+	Zram *ZramConfig `json:"zram,omitempty"`
+
+	// +optional
+	EvictionThresholds *EvictionThresholds `json:"evictionThresholds,omitempty"`
+}
+
+type NodeConfigProfileNodeStatus struct {
+	NodeName string `json:"nodeName"`
+
+	// node host match profile of observed generation
+	Compliant bool `json:"compliant"`
+
+	// profile generation node reconciled
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// settings which node could not apply
+	// +optional
+	Drifts []string `json:"drifts,omitempty"`
+
+	// +optional
+	LastReportTime *metav1.Time `json:"lastReportTime,omitempty"`
+}
+
+type NodeConfigProfileStatus struct {
+	// nodes selected by this profile
+	// +optional
+	MatchedNodes int32 `json:"matchedNodes,omitempty"`
+
+	// +optional
+	CompliantNodes int32 `json:"compliantNodes,omitempty"`
+
+	// compliance of every selected node
+	// +optional
+	Nodes []NodeConfigProfileNodeStatus `json:"nodes,omitempty"`
+}
+
+var _ resource.Object = &NodeConfigProfile{}
+var _ resourcestrategy.Validater = &NodeConfigProfile{}
+var _ resourcestrategy.PrepareForCreater = &NodeConfigProfile{}
+var _ resourcestrategy.PrepareForUpdater = &NodeConfigProfile{}
+
+// PrepareForCreate set generation, node agents report generation they reconciled
+func (in *NodeConfigProfile) PrepareForCreate(ctx context.Context) {
+	in.Generation = 1
+}
+
+// PrepareForUpdate bump generation when spec change
+func (in *NodeConfigProfile) PrepareForUpdate(ctx context.Context, old runtime.Object) {
+	oldProfile := old.(*NodeConfigProfile)
+	in.Generation = oldProfile.Generation
+	if !reflect.DeepEqual(in.Spec, oldProfile.Spec) {
+		in.Generation += 1
+	}
+}
+
+func (in *NodeConfigProfile) GetObjectMeta() *metav1.ObjectMeta {
+	return &in.ObjectMeta
+}
+
+func (in *NodeConfigProfile) NamespaceScoped() bool {
+	return false
+}
+
+func (in *NodeConfigProfile) New() runtime.Object {
+	return &NodeConfigProfile{}
+}
+
+func (in *NodeConfigProfile) NewList() runtime.Object {
+	return &NodeConfigProfileList{}
+}
+
+var NodeConfigProfileGrv = schema.GroupVersionResource{
+	Group:    "core.fornax-serverless.centaurusinfra.io",
+	Version:  "v1",
+	Resource: "nodeconfigprofiles",
+}
+
+func (in *NodeConfigProfile) GetGroupVersionResource() schema.GroupVersionResource {
+	return NodeConfigProfileGrv
+}
+
+func (in *NodeConfigProfile) IsStorageVersion() bool {
+	return true
+}
+
+func (in *NodeConfigProfile) Validate(ctx context.Context) field.ErrorList {
+	errorList := make(field.ErrorList, 0)
+	specPath := field.NewPath("spec")
+	if in.Spec.NodeSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(in.Spec.NodeSelector); err != nil {
+			errorList = append(errorList, field.Invalid(specPath.Child("nodeSelector"), in.Spec.NodeSelector, err.Error()))
+		}
+	}
+	for k, v := range in.Spec.Sysctls {
+		if !sysctlNameRegexp.MatchString(k) || strings.Contains(k, "..") {
+			errorList = append(errorList, field.Invalid(specPath.Child("sysctls").Key(k), k, "invalid sysctl name"))
+		}
+		if len(strings.TrimSpace(v)) == 0 || strings.ContainsAny(v, "\n") {
+			errorList = append(errorList, field.Invalid(specPath.Child("sysctls").Key(k), v, "sysctl value must be a non empty single line"))
+		}
+	}
+	sizes := map[string]bool{}
+	for i, v := range in.Spec.HugePages {
+		path := specPath.Child("hugePages").Index(i)
+		supported := false
+		for _, s := range SupportedHugePageSizes {
+			supported = supported || s == v.PageSize
+		}
+		if !supported {
+			errorList = append(errorList, field.NotSupported(path.Child("pageSize"), v.PageSize, SupportedHugePageSizes))
+		}
+		if sizes[v.PageSize] {
+			errorList = append(errorList, field.Duplicate(path.Child("pageSize"), v.PageSize))
+		}
+		sizes[v.PageSize] = true
+		if v.Count < 0 {
+			errorList = append(errorList, field.Invalid(path.Child("count"), v.Count, "must not be negative"))
+		}
+	}
+	if zram := in.Spec.Zram; zram != nil {
+		path := specPath.Child("zram")
+		if zram.Enabled && (zram.Size == nil || zram.Size.Sign() <= 0) {
+			errorList = append(errorList, field.Required(path.Child("size"), "size is required when zram is enabled"))
+		}
+		if len(zram.Algorithm) > 0 && !zramAlgorithmRegexp.MatchString(zram.Algorithm) {
+			errorList = append(errorList, field.Invalid(path.Child("algorithm"), zram.Algorithm, "invalid compression algorithm"))
+		}
+	}
+	if thresholds := in.Spec.EvictionThresholds; thresholds != nil {
+		path := specPath.Child("evictionThresholds")
+		if thresholds.MemoryAvailable != nil && thresholds.MemoryAvailable.Sign() < 0 {
+			errorList = append(errorList, field.Invalid(path.Child("memoryAvailable"), thresholds.MemoryAvailable.String(), "must not be negative"))
+		}
+		if thresholds.EphemeralStorageAvailable != nil && thresholds.EphemeralStorageAvailable.Sign() < 0 {
+			errorList = append(errorList, field.Invalid(path.Child("ephemeralStorageAvailable"), thresholds.EphemeralStorageAvailable.String(), "must not be negative"))
+		}
+	}
+	return errorList
+}
+
+var _ resource.ObjectList = &NodeConfigProfileList{}
+
+func (in *NodeConfigProfileList) GetListMeta() *metav1.ListMeta {
+	return &in.ListMeta
+}
+
+func (in NodeConfigProfileStatus) SubResourceName() string {
+	return "status"
+}
+
+var _ resource.ObjectWithStatusSubResource = &NodeConfigProfile{}
+
+func (in *NodeConfigProfile) GetStatus() resource.StatusSubResource {
+	return in.Status
+}
+
+var _ resource.StatusSubResource = &NodeConfigProfileStatus{}
+
+func (in NodeConfigProfileStatus) CopyTo(parent resource.ObjectWithStatusSubResource) {
+	parent.(*NodeConfigProfile).Status = in
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/apiserver-runtime/pkg/builder/resource"
+	"sigs.k8s.io/apiserver-runtime/pkg/builder/resource/resourcestrategy"
+)
+
+type NodeLeasePhase string
+
+const (
+	// node renewed lease in lease duration
+	NodeLeasePhaseActive NodeLeasePhase = "Active"
+
+	// node did not renew lease in lease duration, node is not schedulable, pods are kept until eviction grace period passed
+	NodeLeasePhaseExpired NodeLeasePhase = "Expired"
+
+	// pods and sessions on node were evicted and rescheduled on other nodes
+	NodeLeasePhaseEvicted NodeLeasePhase = "Evicted"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeLease is created by fornaxcore when a node renew its lease first time, it's named after node,
+// lease spec can be changed to tune how fast pods on a unresponsive node are evicted
+// +k8s:openapi-gen=true
+type NodeLease struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NodeLeaseSpec   `json:"spec,omitempty"`
+	Status NodeLeaseStatus `json:"status,omitempty"`
+}
+
+// NodeLeaseList
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type NodeLeaseList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []NodeLease `json:"items"`
+}
+
+type NodeLeaseSpec struct {
+	NodeName string `json:"nodeName,omitempty"`
+
+	// lease expire when node does not renew it in this period, node is then not schedulable
+	// +optional, default 40 seconds
+	LeaseDurationSeconds int32 `json:"leaseDurationSeconds,omitempty"`
+
+	// how often node renew lease, node agent is told when it change
+	// +optional, default 10 seconds
+	RenewIntervalSeconds int32 `json:"renewIntervalSeconds,omitempty"`
+
+	// wait time after lease expired before pods on node are evicted and their sessions are closed
+	// +optional, default 20 seconds
+	EvictionGraceSeconds int32 `json:"evictionGraceSeconds,omitempty"`
+}
+
+type NodeLeaseStatus struct {
+	Phase NodeLeasePhase `json:"phase,omitempty"`
+
+	// +optional
+	AcquireTime *metav1.Time `json:"acquireTime,omitempty"`
+
+	// +optional
+	RenewTime *metav1.Time `json:"renewTime,omitempty"`
+
+	// +optional
+	Renewals int64 `json:"renewals,omitempty"`
+
+	// renewals node missed since last renew time
+	// +optional
+	MissedRenewals int32 `json:"missedRenewals,omitempty"`
+
+	// +optional
+	ExpireTime *metav1.Time `json:"expireTime,omitempty"`
+
+	// +optional
+	EvictionTime *metav1.Time `json:"evictionTime,omitempty"`
+
+	// number of pods evicted in last eviction
+	// +optional
+	EvictedPods int32 `json:"evictedPods,omitempty"`
+}
+
+var _ resource.Object = &NodeLease{}
+var _ resourcestrategy.Validater = &NodeLease{}
+
+func (in *NodeLease) GetObjectMeta() *metav1.ObjectMeta {
+	return &in.ObjectMeta
+}
+
+func (in *NodeLease) NamespaceScoped() bool {
+	return false
+}
+
+func (in *NodeLease) New() runtime.Object {
+	return &NodeLease{}
+}
+
+func (in *NodeLease) NewList() runtime.Object {
+	return &NodeLeaseList{}
+}
+
+var NodeLeaseGrv = schema.GroupVersionResource{
+	Group:    "core.fornax-serverless.centaurusinfra.io",
+	Version:  "v1",
+	Resource: "nodeleases",
+}
+
+func (in *NodeLease) GetGroupVersionResource() schema.GroupVersionResource {
+	return NodeLeaseGrv
+}
+
+func (in *NodeLease) IsStorageVersion() bool {
+	return true
+}
+
+func (in *NodeLease) Validate(ctx context.Context) field.ErrorList {
+	errorList := make(field.ErrorList, 0)
+	spec := in.Spec
+	if spec.LeaseDurationSeconds < 0 || spec.RenewIntervalSeconds < 0 || spec.EvictionGraceSeconds < 0 {
+		errorList = append(errorList, field.Invalid(field.NewPath("spec"), spec, "leaseDurationSeconds, renewIntervalSeconds and evictionGraceSeconds must not be negative"))
+	}
+	if spec.LeaseDurationSeconds > 0 && spec.RenewIntervalSeconds > 0 && spec.RenewIntervalSeconds >= spec.LeaseDurationSeconds {
+		errorList = append(errorList, field.Invalid(field.NewPath("spec", "renewIntervalSeconds"), spec.RenewIntervalSeconds, "must be less than leaseDurationSeconds"))
+	}
+	return errorList
+}
+
+var _ resource.ObjectList = &NodeLeaseList{}
+
+func (in *NodeLeaseList) GetListMeta() *metav1.ListMeta {
+	return &in.ListMeta
+}
+
+func (in NodeLeaseStatus) SubResourceName() string {
+	return "status"
+}
+
+var _ resource.ObjectWithStatusSubResource = &NodeLease{}
+
+func (in *NodeLease) GetStatus() resource.StatusSubResource {
+	return in.Status
+}
+
+var _ resource.StatusSubResource = &NodeLeaseStatus{}
+
+func (in NodeLeaseStatus) CopyTo(parent resource.ObjectWithStatusSubResource) {
+	parent.(*NodeLease).Status = in
+}
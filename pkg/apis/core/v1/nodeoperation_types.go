@@ -0,0 +1,220 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/apiserver-runtime/pkg/builder/resource"
+	"sigs.k8s.io/apiserver-runtime/pkg/builder/resource/resourcestrategy"
+)
+
+type NodeOperationType string
+
+const (
+	// mark node unschedulable and terminate application pods on node, pods are recreated on other nodes by application manager
+	NodeOperationTypeDrain NodeOperationType = "Drain"
+
+	// mark node unschedulable, existing pods keep running
+	NodeOperationTypeCordon NodeOperationType = "Cordon"
+
+	// mark node schedulable again
+	NodeOperationTypeUncordon NodeOperationType = "Uncordon"
+
+	// set labels of node
+	NodeOperationTypeReconfigure NodeOperationType = "Reconfigure"
+)
+
+type NodeOperationPhase string
+
+const (
+	NodeOperationPhasePending   NodeOperationPhase = "Pending"
+	NodeOperationPhaseRunning   NodeOperationPhase = "Running"
+	NodeOperationPhaseSucceeded NodeOperationPhase = "Succeeded"
+	NodeOperationPhaseFailed    NodeOperationPhase = "Failed"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeOperation apply a operation on nodes selected by label selector batch by batch, fornaxcore track progress of every node in status
+// +k8s:openapi-gen=true
+type NodeOperation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NodeOperationSpec   `json:"spec,omitempty"`
+	Status NodeOperationStatus `json:"status,omitempty"`
+}
+
+// NodeOperationList
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type NodeOperationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []NodeOperation `json:"items"`
+}
+
+type NodeOperationSpec struct {
+	Type NodeOperationType `json:"type"`
+
+	// nodes matching selector when operation start are operated, empty selector select all nodes
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+
+	// labels set on nodes, only used by Reconfigure, a label with empty value is removed from node
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// number of nodes operated at same time
+	// +optional, default 1
+	BatchSize int32 `json:"batchSize,omitempty"`
+
+	// wait time after a batch is done before starting next batch
+	// +optional, default 0
+	BatchIntervalSeconds int32 `json:"batchIntervalSeconds,omitempty"`
+
+	// operation stop and fail when more nodes failed than this number
+	// +optional, default 0
+	MaxFailedNodes int32 `json:"maxFailedNodes,omitempty"`
+
+	// a node is failed if operation does not finish on it in this period, e.g. pods are not terminated when draining
+	// +optional, default 300 seconds
+	NodeTimeoutSeconds int32 `json:"nodeTimeoutSeconds,omitempty"`
+}
+
+type NodeOperationNodeStatus struct {
+	NodeName string `json:"nodeName"`
+
+	Phase NodeOperationPhase `json:"phase,omitempty"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+type NodeOperationStatus struct {
+	Phase NodeOperationPhase `json:"phase,omitempty"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// progress of every selected node
+	// +optional
+	Nodes []NodeOperationNodeStatus `json:"nodes,omitempty"`
+
+	// +optional
+	SucceededNodes int32 `json:"succeededNodes,omitempty"`
+
+	// +optional
+	FailedNodes int32 `json:"failedNodes,omitempty"`
+
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+var _ resource.Object = &NodeOperation{}
+var _ resourcestrategy.Validater = &NodeOperation{}
+
+func (in *NodeOperation) GetObjectMeta() *metav1.ObjectMeta {
+	return &in.ObjectMeta
+}
+
+func (in *NodeOperation) NamespaceScoped() bool {
+	return false
+}
+
+func (in *NodeOperation) New() runtime.Object {
+	return &NodeOperation{}
+}
+
+func (in *NodeOperation) NewList() runtime.Object {
+	return &NodeOperationList{}
+}
+
+var NodeOperationGrv = schema.GroupVersionResource{
+	Group:    "core.fornax-serverless.centaurusinfra.io",
+	Version:  "v1",
+	Resource: "nodeoperations",
+}
+
+func (in *NodeOperation) GetGroupVersionResource() schema.GroupVersionResource {
+	return NodeOperationGrv
+}
+
+func (in *NodeOperation) IsStorageVersion() bool {
+	return true
+}
+
+func (in *NodeOperation) Validate(ctx context.Context) field.ErrorList {
+	errorList := make(field.ErrorList, 0)
+	switch in.Spec.Type {
+	case NodeOperationTypeDrain, NodeOperationTypeCordon, NodeOperationTypeUncordon:
+	case NodeOperationTypeReconfigure:
+		if len(in.Spec.Labels) == 0 {
+			errorList = append(errorList, field.Required(field.NewPath("spec", "labels"), "labels are required by Reconfigure"))
+		}
+	default:
+		errorList = append(errorList, field.NotSupported(field.NewPath("spec", "type"), in.Spec.Type,
+			[]string{string(NodeOperationTypeDrain), string(NodeOperationTypeCordon), string(NodeOperationTypeUncordon), string(NodeOperationTypeReconfigure)}))
+	}
+	if in.Spec.NodeSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(in.Spec.NodeSelector); err != nil {
+			errorList = append(errorList, field.Invalid(field.NewPath("spec", "nodeSelector"), in.Spec.NodeSelector, err.Error()))
+		}
+	}
+	if in.Spec.BatchSize < 0 || in.Spec.BatchIntervalSeconds < 0 || in.Spec.MaxFailedNodes < 0 || in.Spec.NodeTimeoutSeconds < 0 {
+		errorList = append(errorList, field.Invalid(field.NewPath("spec"), in.Spec, "batchSize, batchIntervalSeconds, maxFailedNodes and nodeTimeoutSeconds must not be negative"))
+	}
+	return errorList
+}
+
+var _ resource.ObjectList = &NodeOperationList{}
+
+func (in *NodeOperationList) GetListMeta() *metav1.ListMeta {
+	return &in.ListMeta
+}
+
+func (in NodeOperationStatus) SubResourceName() string {
+	return "status"
+}
+
+var _ resource.ObjectWithStatusSubResource = &NodeOperation{}
+
+func (in *NodeOperation) GetStatus() resource.StatusSubResource {
+	return in.Status
+}
+
+var _ resource.StatusSubResource = &NodeOperationStatus{}
+
+func (in NodeOperationStatus) CopyTo(parent resource.ObjectWithStatusSubResource) {
+	parent.(*NodeOperation).Status = in
+}
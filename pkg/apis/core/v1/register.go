@@ -61,6 +61,36 @@ var AddToScheme = func(scheme *runtime.Scheme) error {
 		Version: "v1",
 	}, &IngressEndpoint{}, &IngressEndpointList{})
 
+	scheme.AddKnownTypes(schema.GroupVersion{
+		Group:   "core.fornax-serverless.centaurusinfra.io",
+		Version: "v1",
+	}, &FornaxQuota{}, &FornaxQuotaList{})
+
+	scheme.AddKnownTypes(schema.GroupVersion{
+		Group:   "core.fornax-serverless.centaurusinfra.io",
+		Version: "v1",
+	}, &NodeOperation{}, &NodeOperationList{})
+
+	scheme.AddKnownTypes(schema.GroupVersion{
+		Group:   "core.fornax-serverless.centaurusinfra.io",
+		Version: "v1",
+	}, &NodeLease{}, &NodeLeaseList{})
+
+	scheme.AddKnownTypes(schema.GroupVersion{
+		Group:   "core.fornax-serverless.centaurusinfra.io",
+		Version: "v1",
+	}, &NodeConfigProfile{}, &NodeConfigProfileList{})
+
+	scheme.AddKnownTypes(schema.GroupVersion{
+		Group:   "core.fornax-serverless.centaurusinfra.io",
+		Version: "v1",
+	}, &SessionUsage{}, &SessionUsageList{})
+
+	scheme.AddKnownTypes(schema.GroupVersion{
+		Group:   "core.fornax-serverless.centaurusinfra.io",
+		Version: "v1",
+	}, &FornaxEvent{}, &FornaxEventList{})
+
 	scheme.AddKnownTypes(schema.GroupVersion{
 		Group:   "core.fornax-serverless.centaurusinfra.io",
 		Version: "v1",
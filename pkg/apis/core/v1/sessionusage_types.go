@@ -0,0 +1,169 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/apiserver-runtime/pkg/builder/resource"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SessionUsage is resource consumption of a application session reported by node agents, it has same namespace and name as session,
+// usage of a pod is shared evenly by its open sessions, it's kept after session is deleted, so it can be used to bill actual consumption
+// +k8s:openapi-gen=true
+type SessionUsage struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SessionUsageSpec   `json:"spec,omitempty"`
+	Status SessionUsageStatus `json:"status,omitempty"`
+}
+
+// SessionUsageList
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type SessionUsageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []SessionUsage `json:"items"`
+}
+
+type SessionUsageSpec struct {
+	// application of session
+	ApplicationName string `json:"applicationName,omitempty"`
+
+	// uid of session, a session recreated with same name get a new usage
+	// +optional
+	SessionUID string `json:"sessionUID,omitempty"`
+}
+
+// SessionPodUsage is usage of session on one pod, a migrated session has usage on multiple pods
+type SessionPodUsage struct {
+	PodName string `json:"podName"`
+
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+
+	CPUMilliCoreSeconds int64 `json:"cpuMilliCoreSeconds"`
+
+	MemoryMebibyteSeconds int64 `json:"memoryMebibyteSeconds"`
+
+	// peak working set memory of pod while session was open on it
+	// +optional
+	MemoryPeakBytes int64 `json:"memoryPeakBytes,omitempty"`
+
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// end of last reported interval
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+
+	// node reported last usage of session on pod
+	// +optional
+	Final bool `json:"final,omitempty"`
+
+	// estimated cost in millionths of price unit of node price labels, it's share of node price by larger fraction of node cpu or memory used
+	// +optional
+	EstimatedCostMicros int64 `json:"estimatedCostMicros,omitempty"`
+}
+
+type SessionUsageStatus struct {
+	// cpu time used by session, 1000 is one cpu core for one second
+	// +optional
+	CPUMilliCoreSeconds int64 `json:"cpuMilliCoreSeconds,omitempty"`
+
+	// working set memory integrated over time
+	// +optional
+	MemoryMebibyteSeconds int64 `json:"memoryMebibyteSeconds,omitempty"`
+
+	// +optional
+	MemoryPeakBytes int64 `json:"memoryPeakBytes,omitempty"`
+
+	// start of first reported interval
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// end of last reported interval
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+
+	// estimated cost of session on all pods, only usage on nodes with price label is counted
+	// +optional
+	EstimatedCostMicros int64 `json:"estimatedCostMicros,omitempty"`
+
+	// +optional
+	Pods []SessionPodUsage `json:"pods,omitempty"`
+}
+
+var _ resource.Object = &SessionUsage{}
+
+func (in *SessionUsage) GetObjectMeta() *metav1.ObjectMeta {
+	return &in.ObjectMeta
+}
+
+func (in *SessionUsage) NamespaceScoped() bool {
+	return true
+}
+
+func (in *SessionUsage) New() runtime.Object {
+	return &SessionUsage{}
+}
+
+func (in *SessionUsage) NewList() runtime.Object {
+	return &SessionUsageList{}
+}
+
+var SessionUsageGrv = schema.GroupVersionResource{
+	Group:    "core.fornax-serverless.centaurusinfra.io",
+	Version:  "v1",
+	Resource: "sessionusages",
+}
+
+func (in *SessionUsage) GetGroupVersionResource() schema.GroupVersionResource {
+	return SessionUsageGrv
+}
+
+func (in *SessionUsage) IsStorageVersion() bool {
+	return true
+}
+
+var _ resource.ObjectList = &SessionUsageList{}
+
+func (in *SessionUsageList) GetListMeta() *metav1.ListMeta {
+	return &in.ListMeta
+}
+
+func (in SessionUsageStatus) SubResourceName() string {
+	return "status"
+}
+
+var _ resource.ObjectWithStatusSubResource = &SessionUsage{}
+
+func (in *SessionUsage) GetStatus() resource.StatusSubResource {
+	return in.Status
+}
+
+var _ resource.StatusSubResource = &SessionUsageStatus{}
+
+func (in SessionUsageStatus) CopyTo(parent resource.ObjectWithStatusSubResource) {
+	parent.(*SessionUsage).Status = in
+}
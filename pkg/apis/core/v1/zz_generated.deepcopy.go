@@ -23,6 +23,7 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -95,6 +96,26 @@ func (in *ApplicationInstance) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationInstanceNaming) DeepCopyInto(out *ApplicationInstanceNaming) {
+	*out = *in
+	if in.VolumeClaimTemplates != nil {
+		in, out := &in.VolumeClaimTemplates, &out.VolumeClaimTemplates
+		*out = make([]ApplicationVolumeClaimTemplate, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationInstanceNaming.
+func (in *ApplicationInstanceNaming) DeepCopy() *ApplicationInstanceNaming {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationInstanceNaming)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplicationInstanceList) DeepCopyInto(out *ApplicationInstanceList) {
 	*out = *in
@@ -196,6 +217,244 @@ func (in *ApplicationList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationNATTraversal) DeepCopyInto(out *ApplicationNATTraversal) {
+	*out = *in
+	if in.STUNServers != nil {
+		in, out := &in.STUNServers, &out.STUNServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Relay != nil {
+		in, out := &in.Relay, &out.Relay
+		*out = new(SessionRelay)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationNATTraversal.
+func (in *ApplicationNATTraversal) DeepCopy() *ApplicationNATTraversal {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationNATTraversal)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationNetworkPolicy) DeepCopyInto(out *ApplicationNetworkPolicy) {
+	*out = *in
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = make([]NetworkPolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Egress != nil {
+		in, out := &in.Egress, &out.Egress
+		*out = make([]NetworkPolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationNetworkPolicy.
+func (in *ApplicationNetworkPolicy) DeepCopy() *ApplicationNetworkPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationNetworkPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationOwnership) DeepCopyInto(out *ApplicationOwnership) {
+	*out = *in
+	if in.Owners != nil {
+		in, out := &in.Owners, &out.Owners
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationOwnership.
+func (in *ApplicationOwnership) DeepCopy() *ApplicationOwnership {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationOwnership)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationReadinessGate) DeepCopyInto(out *ApplicationReadinessGate) {
+	*out = *in
+	if in.HTTPGet != nil {
+		in, out := &in.HTTPGet, &out.HTTPGet
+		*out = new(HTTPReadinessCheck)
+		**out = **in
+	}
+	if in.GRPC != nil {
+		in, out := &in.GRPC, &out.GRPC
+		*out = new(GRPCReadinessCheck)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationReadinessGate.
+func (in *ApplicationReadinessGate) DeepCopy() *ApplicationReadinessGate {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationReadinessGate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationProcessLimits) DeepCopyInto(out *ApplicationProcessLimits) {
+	*out = *in
+	if in.OOMScoreAdj != nil {
+		in, out := &in.OOMScoreAdj, &out.OOMScoreAdj
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationProcessLimits.
+func (in *ApplicationProcessLimits) DeepCopy() *ApplicationProcessLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationProcessLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationRestartPolicy) DeepCopyInto(out *ApplicationRestartPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationRestartPolicy.
+func (in *ApplicationRestartPolicy) DeepCopy() *ApplicationRestartPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationRestartPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationRolloutPolicy) DeepCopyInto(out *ApplicationRolloutPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationRolloutPolicy.
+func (in *ApplicationRolloutPolicy) DeepCopy() *ApplicationRolloutPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationRolloutPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationRolloutStatus) DeepCopyInto(out *ApplicationRolloutStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PreviousContainers != nil {
+		in, out := &in.PreviousContainers, &out.PreviousContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationRolloutStatus.
+func (in *ApplicationRolloutStatus) DeepCopy() *ApplicationRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSecret) DeepCopyInto(out *ApplicationSecret) {
+	*out = *in
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make(map[string][]byte, len(*in))
+		for key, val := range *in {
+			var outVal []byte
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]byte, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.EncryptedData != nil {
+		in, out := &in.EncryptedData, &out.EncryptedData
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSecret.
+func (in *ApplicationSecret) DeepCopy() *ApplicationSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSecretStatus) DeepCopyInto(out *ApplicationSecretStatus) {
+	*out = *in
+	if in.RotationTime != nil {
+		in, out := &in.RotationTime, &out.RotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.OutdatedInstances != nil {
+		in, out := &in.OutdatedInstances, &out.OutdatedInstances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSecretStatus.
+func (in *ApplicationSecretStatus) DeepCopy() *ApplicationSecretStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSecretStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplicationSession) DeepCopyInto(out *ApplicationSession) {
 	*out = *in
@@ -258,11 +517,21 @@ func (in *ApplicationSessionList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplicationSessionSpec) DeepCopyInto(out *ApplicationSessionSpec) {
 	*out = *in
+	if in.Attachment != nil {
+		in, out := &in.Attachment, &out.Attachment
+		*out = new(SessionAttachment)
+		**out = **in
+	}
 	if in.CloseGracePeriodSeconds != nil {
 		in, out := &in.CloseGracePeriodSeconds, &out.CloseGracePeriodSeconds
 		*out = new(uint16)
 		**out = **in
 	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(SessionAffinity)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSessionSpec.
@@ -290,8 +559,10 @@ func (in *ApplicationSessionStatus) DeepCopyInto(out *ApplicationSessionStatus)
 	}
 	if in.ClientSessions != nil {
 		in, out := &in.ClientSessions, &out.ClientSessions
-		*out = make([]corev1.LocalObjectReference, len(*in))
-		copy(*out, *in)
+		*out = make([]ClientSessionReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.AvailableTime != nil {
 		in, out := &in.AvailableTime, &out.AvailableTime
@@ -301,6 +572,31 @@ func (in *ApplicationSessionStatus) DeepCopyInto(out *ApplicationSessionStatus)
 		in, out := &in.CloseTime, &out.CloseTime
 		*out = (*in).DeepCopy()
 	}
+	if in.QualityScore != nil {
+		in, out := &in.QualityScore, &out.QualityScore
+		*out = new(SessionQualityScore)
+		**out = **in
+	}
+	if in.Backpressure != nil {
+		in, out := &in.Backpressure, &out.Backpressure
+		*out = new(SessionBackpressure)
+		**out = **in
+	}
+	if in.PublicEndPoints != nil {
+		in, out := &in.PublicEndPoints, &out.PublicEndPoints
+		*out = make([]AccessEndPoint, len(*in))
+		copy(*out, *in)
+	}
+	if in.Relay != nil {
+		in, out := &in.Relay, &out.Relay
+		*out = new(SessionRelay)
+		**out = **in
+	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(SessionMigration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSessionStatus.
@@ -331,6 +627,83 @@ func (in *ApplicationSpec) DeepCopyInto(out *ApplicationSpec) {
 		}
 	}
 	in.ScalingPolicy.DeepCopyInto(&out.ScalingPolicy)
+	if in.ReadinessGates != nil {
+		in, out := &in.ReadinessGates, &out.ReadinessGates
+		*out = make([]ApplicationReadinessGate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SessionHealthCheck != nil {
+		in, out := &in.SessionHealthCheck, &out.SessionHealthCheck
+		*out = new(SessionHealthCheck)
+		**out = **in
+	}
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(ApplicationSecret)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(ApplicationTLS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SuspensionPolicy != nil {
+		in, out := &in.SuspensionPolicy, &out.SuspensionPolicy
+		*out = new(ApplicationSuspensionPolicy)
+		**out = **in
+	}
+	if in.Ownership != nil {
+		in, out := &in.Ownership, &out.Ownership
+		*out = new(ApplicationOwnership)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NATTraversal != nil {
+		in, out := &in.NATTraversal, &out.NATTraversal
+		*out = new(ApplicationNATTraversal)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WarmPool != nil {
+		in, out := &in.WarmPool, &out.WarmPool
+		*out = new(ApplicationWarmPool)
+		**out = **in
+	}
+	if in.WarmUp != nil {
+		in, out := &in.WarmUp, &out.WarmUp
+		*out = new(ApplicationWarmUp)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RolloutPolicy != nil {
+		in, out := &in.RolloutPolicy, &out.RolloutPolicy
+		*out = new(ApplicationRolloutPolicy)
+		**out = **in
+	}
+	if in.Sysctls != nil {
+		in, out := &in.Sysctls, &out.Sysctls
+		*out = make([]corev1.Sysctl, len(*in))
+		copy(*out, *in)
+	}
+	if in.RestartPolicy != nil {
+		in, out := &in.RestartPolicy, &out.RestartPolicy
+		*out = new(ApplicationRestartPolicy)
+		**out = **in
+	}
+	if in.ProcessLimits != nil {
+		in, out := &in.ProcessLimits, &out.ProcessLimits
+		*out = new(ApplicationProcessLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(ApplicationNetworkPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstanceNaming != nil {
+		in, out := &in.InstanceNaming, &out.InstanceNaming
+		*out = new(ApplicationInstanceNaming)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSpec.
@@ -354,6 +727,32 @@ func (in *ApplicationStatus) DeepCopyInto(out *ApplicationStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SecretStatus != nil {
+		in, out := &in.SecretStatus, &out.SecretStatus
+		*out = new(ApplicationSecretStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLSStatus != nil {
+		in, out := &in.TLSStatus, &out.TLSStatus
+		*out = new(ApplicationTLSStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SuspendedTime != nil {
+		in, out := &in.SuspendedTime, &out.SuspendedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(ApplicationRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstanceNodes != nil {
+		in, out := &in.InstanceNodes, &out.InstanceNodes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationStatus.
@@ -367,71 +766,200 @@ func (in *ApplicationStatus) DeepCopy() *ApplicationStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClientSession) DeepCopyInto(out *ClientSession) {
+func (in *ApplicationSuspensionPolicy) DeepCopyInto(out *ApplicationSuspensionPolicy) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientSession.
-func (in *ClientSession) DeepCopy() *ClientSession {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSuspensionPolicy.
+func (in *ApplicationSuspensionPolicy) DeepCopy() *ApplicationSuspensionPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(ClientSession)
+	out := new(ApplicationSuspensionPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClientSession) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClientSessionList) DeepCopyInto(out *ClientSessionList) {
+func (in *ApplicationTLS) DeepCopyInto(out *ApplicationTLS) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ClientSession, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.DNSNames != nil {
+		in, out := &in.DNSNames, &out.DNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientSessionList.
-func (in *ClientSessionList) DeepCopy() *ClientSessionList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationTLS.
+func (in *ApplicationTLS) DeepCopy() *ApplicationTLS {
 	if in == nil {
 		return nil
 	}
-	out := new(ClientSessionList)
+	out := new(ApplicationTLS)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClientSessionList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClientSessionSpec) DeepCopyInto(out *ClientSessionSpec) {
+func (in *ApplicationTLSStatus) DeepCopyInto(out *ApplicationTLSStatus) {
 	*out = *in
+	if in.DNSNames != nil {
+		in, out := &in.DNSNames, &out.DNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientSessionSpec.
-func (in *ClientSessionSpec) DeepCopy() *ClientSessionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationTLSStatus.
+func (in *ApplicationTLSStatus) DeepCopy() *ApplicationTLSStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationTLSStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationVolumeClaimTemplate) DeepCopyInto(out *ApplicationVolumeClaimTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationVolumeClaimTemplate.
+func (in *ApplicationVolumeClaimTemplate) DeepCopy() *ApplicationVolumeClaimTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationVolumeClaimTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationWarmPool) DeepCopyInto(out *ApplicationWarmPool) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationWarmPool.
+func (in *ApplicationWarmPool) DeepCopy() *ApplicationWarmPool {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationWarmPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationWarmUp) DeepCopyInto(out *ApplicationWarmUp) {
+	*out = *in
+	in.Handler.DeepCopyInto(&out.Handler)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationWarmUp.
+func (in *ApplicationWarmUp) DeepCopy() *ApplicationWarmUp {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationWarmUp)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientSession) DeepCopyInto(out *ClientSession) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientSession.
+func (in *ClientSession) DeepCopy() *ClientSession {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientSession)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClientSession) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientSessionList) DeepCopyInto(out *ClientSessionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClientSession, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientSessionList.
+func (in *ClientSessionList) DeepCopy() *ClientSessionList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientSessionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClientSessionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientSessionReference) DeepCopyInto(out *ClientSessionReference) {
+	*out = *in
+	if in.JoinTime != nil {
+		in, out := &in.JoinTime, &out.JoinTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LeaveTime != nil {
+		in, out := &in.LeaveTime, &out.LeaveTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientSessionReference.
+func (in *ClientSessionReference) DeepCopy() *ClientSessionReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientSessionReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientSessionSpec) DeepCopyInto(out *ClientSessionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientSessionSpec.
+func (in *ClientSessionSpec) DeepCopy() *ClientSessionSpec {
 	if in == nil {
 		return nil
 	}
@@ -487,56 +1015,84 @@ func (in *Destination) DeepCopy() *Destination {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IdelSessionNumThreshold) DeepCopyInto(out *IdelSessionNumThreshold) {
+func (in *EvictionThresholds) DeepCopyInto(out *EvictionThresholds) {
 	*out = *in
+	if in.MemoryAvailable != nil {
+		in, out := &in.MemoryAvailable, &out.MemoryAvailable
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.EphemeralStorageAvailable != nil {
+		in, out := &in.EphemeralStorageAvailable, &out.EphemeralStorageAvailable
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdelSessionNumThreshold.
-func (in *IdelSessionNumThreshold) DeepCopy() *IdelSessionNumThreshold {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EvictionThresholds.
+func (in *EvictionThresholds) DeepCopy() *EvictionThresholds {
 	if in == nil {
 		return nil
 	}
-	out := new(IdelSessionNumThreshold)
+	out := new(EvictionThresholds)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IdelSessionPercentThreshold) DeepCopyInto(out *IdelSessionPercentThreshold) {
+func (in *FornaxEvent) DeepCopyInto(out *FornaxEvent) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.InvolvedObject = in.InvolvedObject
+	in.FirstTimestamp.DeepCopyInto(&out.FirstTimestamp)
+	in.LastTimestamp.DeepCopyInto(&out.LastTimestamp)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdelSessionPercentThreshold.
-func (in *IdelSessionPercentThreshold) DeepCopy() *IdelSessionPercentThreshold {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FornaxEvent.
+func (in *FornaxEvent) DeepCopy() *FornaxEvent {
 	if in == nil {
 		return nil
 	}
-	out := new(IdelSessionPercentThreshold)
+	out := new(FornaxEvent)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FornaxEvent) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IngressEndpoint) DeepCopyInto(out *IngressEndpoint) {
+func (in *FornaxEventList) DeepCopyInto(out *FornaxEventList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FornaxEvent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressEndpoint.
-func (in *IngressEndpoint) DeepCopy() *IngressEndpoint {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FornaxEventList.
+func (in *FornaxEventList) DeepCopy() *FornaxEventList {
 	if in == nil {
 		return nil
 	}
-	out := new(IngressEndpoint)
+	out := new(FornaxEventList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *IngressEndpoint) DeepCopyObject() runtime.Object {
+func (in *FornaxEventList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -544,47 +1100,72 @@ func (in *IngressEndpoint) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IngressEndpointHistory) DeepCopyInto(out *IngressEndpointHistory) {
+func (in *FornaxObjectReference) DeepCopyInto(out *FornaxObjectReference) {
 	*out = *in
-	in.UpdateTime.DeepCopyInto(&out.UpdateTime)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressEndpointHistory.
-func (in *IngressEndpointHistory) DeepCopy() *IngressEndpointHistory {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FornaxObjectReference.
+func (in *FornaxObjectReference) DeepCopy() *FornaxObjectReference {
 	if in == nil {
 		return nil
 	}
-	out := new(IngressEndpointHistory)
+	out := new(FornaxObjectReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IngressEndpointList) DeepCopyInto(out *IngressEndpointList) {
+func (in *FornaxQuota) DeepCopyInto(out *FornaxQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FornaxQuota.
+func (in *FornaxQuota) DeepCopy() *FornaxQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(FornaxQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FornaxQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FornaxQuotaList) DeepCopyInto(out *FornaxQuotaList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]IngressEndpoint, len(*in))
+		*out = make([]FornaxQuota, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressEndpointList.
-func (in *IngressEndpointList) DeepCopy() *IngressEndpointList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FornaxQuotaList.
+func (in *FornaxQuotaList) DeepCopy() *FornaxQuotaList {
 	if in == nil {
 		return nil
 	}
-	out := new(IngressEndpointList)
+	out := new(FornaxQuotaList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *IngressEndpointList) DeepCopyObject() runtime.Object {
+func (in *FornaxQuotaList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -592,100 +1173,1032 @@ func (in *IngressEndpointList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IngressEndpointSpec) DeepCopyInto(out *IngressEndpointSpec) {
+func (in *FornaxQuotaSpec) DeepCopyInto(out *FornaxQuotaSpec) {
 	*out = *in
-	if in.Destinations != nil {
-		in, out := &in.Destinations, &out.Destinations
-		*out = make([]Destination, len(*in))
-		copy(*out, *in)
+	if in.MaxApplicationInstances != nil {
+		in, out := &in.MaxApplicationInstances, &out.MaxApplicationInstances
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxApplicationSessions != nil {
+		in, out := &in.MaxApplicationSessions, &out.MaxApplicationSessions
+		*out = new(int64)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressEndpointSpec.
-func (in *IngressEndpointSpec) DeepCopy() *IngressEndpointSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FornaxQuotaSpec.
+func (in *FornaxQuotaSpec) DeepCopy() *FornaxQuotaSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(IngressEndpointSpec)
+	out := new(FornaxQuotaSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IngressEndpointStatus) DeepCopyInto(out *IngressEndpointStatus) {
+func (in *GRPCReadinessCheck) DeepCopyInto(out *GRPCReadinessCheck) {
 	*out = *in
-	if in.History != nil {
-		in, out := &in.History, &out.History
-		*out = make([]IngressEndpointHistory, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressEndpointStatus.
-func (in *IngressEndpointStatus) DeepCopy() *IngressEndpointStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPCReadinessCheck.
+func (in *GRPCReadinessCheck) DeepCopy() *GRPCReadinessCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(IngressEndpointStatus)
+	out := new(GRPCReadinessCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InstanceHistory) DeepCopyInto(out *InstanceHistory) {
+func (in *HTTPReadinessCheck) DeepCopyInto(out *HTTPReadinessCheck) {
 	*out = *in
-	out.PodReference = in.PodReference
-	in.UpdateTime.DeepCopyInto(&out.UpdateTime)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceHistory.
-func (in *InstanceHistory) DeepCopy() *InstanceHistory {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPReadinessCheck.
+func (in *HTTPReadinessCheck) DeepCopy() *HTTPReadinessCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(InstanceHistory)
+	out := new(HTTPReadinessCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkInterface) DeepCopyInto(out *NetworkInterface) {
+func (in *HugePageReservation) DeepCopyInto(out *HugePageReservation) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkInterface.
-func (in *NetworkInterface) DeepCopy() *NetworkInterface {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HugePageReservation.
+func (in *HugePageReservation) DeepCopy() *HugePageReservation {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkInterface)
+	out := new(HugePageReservation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScalingPolicy) DeepCopyInto(out *ScalingPolicy) {
+func (in *IdelSessionNumThreshold) DeepCopyInto(out *IdelSessionNumThreshold) {
 	*out = *in
-	if in.IdleSessionNumThreshold != nil {
-		in, out := &in.IdleSessionNumThreshold, &out.IdleSessionNumThreshold
-		*out = new(IdelSessionNumThreshold)
-		**out = **in
-	}
-	if in.IdleSessionPercentThreshold != nil {
-		in, out := &in.IdleSessionPercentThreshold, &out.IdleSessionPercentThreshold
-		*out = new(IdelSessionPercentThreshold)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdelSessionNumThreshold.
+func (in *IdelSessionNumThreshold) DeepCopy() *IdelSessionNumThreshold {
+	if in == nil {
+		return nil
 	}
+	out := new(IdelSessionNumThreshold)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingPolicy.
-func (in *ScalingPolicy) DeepCopy() *ScalingPolicy {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdelSessionPercentThreshold) DeepCopyInto(out *IdelSessionPercentThreshold) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdelSessionPercentThreshold.
+func (in *IdelSessionPercentThreshold) DeepCopy() *IdelSessionPercentThreshold {
 	if in == nil {
 		return nil
 	}
-	out := new(ScalingPolicy)
+	out := new(IdelSessionPercentThreshold)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressEndpoint) DeepCopyInto(out *IngressEndpoint) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressEndpoint.
+func (in *IngressEndpoint) DeepCopy() *IngressEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IngressEndpoint) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressEndpointHistory) DeepCopyInto(out *IngressEndpointHistory) {
+	*out = *in
+	in.UpdateTime.DeepCopyInto(&out.UpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressEndpointHistory.
+func (in *IngressEndpointHistory) DeepCopy() *IngressEndpointHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressEndpointHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressEndpointList) DeepCopyInto(out *IngressEndpointList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IngressEndpoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressEndpointList.
+func (in *IngressEndpointList) DeepCopy() *IngressEndpointList {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressEndpointList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IngressEndpointList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressEndpointSpec) DeepCopyInto(out *IngressEndpointSpec) {
+	*out = *in
+	if in.Destinations != nil {
+		in, out := &in.Destinations, &out.Destinations
+		*out = make([]Destination, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressEndpointSpec.
+func (in *IngressEndpointSpec) DeepCopy() *IngressEndpointSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressEndpointSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressEndpointStatus) DeepCopyInto(out *IngressEndpointStatus) {
+	*out = *in
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]IngressEndpointHistory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressEndpointStatus.
+func (in *IngressEndpointStatus) DeepCopy() *IngressEndpointStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressEndpointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceHistory) DeepCopyInto(out *InstanceHistory) {
+	*out = *in
+	out.PodReference = in.PodReference
+	in.UpdateTime.DeepCopyInto(&out.UpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceHistory.
+func (in *InstanceHistory) DeepCopy() *InstanceHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkInterface) DeepCopyInto(out *NetworkInterface) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkInterface.
+func (in *NetworkInterface) DeepCopy() *NetworkInterface {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkInterface)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyPort) DeepCopyInto(out *NetworkPolicyPort) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyPort.
+func (in *NetworkPolicyPort) DeepCopy() *NetworkPolicyPort {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyPort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyRule) DeepCopyInto(out *NetworkPolicyRule) {
+	*out = *in
+	if in.CIDRs != nil {
+		in, out := &in.CIDRs, &out.CIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]NetworkPolicyPort, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyRule.
+func (in *NetworkPolicyRule) DeepCopy() *NetworkPolicyRule {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeConfigProfile) DeepCopyInto(out *NodeConfigProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeConfigProfile.
+func (in *NodeConfigProfile) DeepCopy() *NodeConfigProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeConfigProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeConfigProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeConfigProfileList) DeepCopyInto(out *NodeConfigProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NodeConfigProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeConfigProfileList.
+func (in *NodeConfigProfileList) DeepCopy() *NodeConfigProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeConfigProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeConfigProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeConfigProfileNodeStatus) DeepCopyInto(out *NodeConfigProfileNodeStatus) {
+	*out = *in
+	if in.Drifts != nil {
+		in, out := &in.Drifts, &out.Drifts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastReportTime != nil {
+		in, out := &in.LastReportTime, &out.LastReportTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeConfigProfileNodeStatus.
+func (in *NodeConfigProfileNodeStatus) DeepCopy() *NodeConfigProfileNodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeConfigProfileNodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeConfigProfileSpec) DeepCopyInto(out *NodeConfigProfileSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sysctls != nil {
+		in, out := &in.Sysctls, &out.Sysctls
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.HugePages != nil {
+		in, out := &in.HugePages, &out.HugePages
+		*out = make([]HugePageReservation, len(*in))
+		copy(*out, *in)
+	}
+	if in.Zram != nil {
+		in, out := &in.Zram, &out.Zram
+		*out = new(ZramConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EvictionThresholds != nil {
+		in, out := &in.EvictionThresholds, &out.EvictionThresholds
+		*out = new(EvictionThresholds)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeConfigProfileSpec.
+func (in *NodeConfigProfileSpec) DeepCopy() *NodeConfigProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeConfigProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeConfigProfileStatus) DeepCopyInto(out *NodeConfigProfileStatus) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]NodeConfigProfileNodeStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeConfigProfileStatus.
+func (in *NodeConfigProfileStatus) DeepCopy() *NodeConfigProfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeConfigProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeLease) DeepCopyInto(out *NodeLease) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeLease.
+func (in *NodeLease) DeepCopy() *NodeLease {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeLease)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeLease) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeLeaseList) DeepCopyInto(out *NodeLeaseList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NodeLease, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeLeaseList.
+func (in *NodeLeaseList) DeepCopy() *NodeLeaseList {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeLeaseList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeLeaseList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeLeaseSpec) DeepCopyInto(out *NodeLeaseSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeLeaseSpec.
+func (in *NodeLeaseSpec) DeepCopy() *NodeLeaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeLeaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeLeaseStatus) DeepCopyInto(out *NodeLeaseStatus) {
+	*out = *in
+	if in.AcquireTime != nil {
+		in, out := &in.AcquireTime, &out.AcquireTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RenewTime != nil {
+		in, out := &in.RenewTime, &out.RenewTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpireTime != nil {
+		in, out := &in.ExpireTime, &out.ExpireTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EvictionTime != nil {
+		in, out := &in.EvictionTime, &out.EvictionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeLeaseStatus.
+func (in *NodeLeaseStatus) DeepCopy() *NodeLeaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeLeaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeOperation) DeepCopyInto(out *NodeOperation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeOperation.
+func (in *NodeOperation) DeepCopy() *NodeOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeOperation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeOperationList) DeepCopyInto(out *NodeOperationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NodeOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeOperationList.
+func (in *NodeOperationList) DeepCopy() *NodeOperationList {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeOperationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeOperationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeOperationNodeStatus) DeepCopyInto(out *NodeOperationNodeStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeOperationNodeStatus.
+func (in *NodeOperationNodeStatus) DeepCopy() *NodeOperationNodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeOperationNodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeOperationSpec) DeepCopyInto(out *NodeOperationSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeOperationSpec.
+func (in *NodeOperationSpec) DeepCopy() *NodeOperationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeOperationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeOperationStatus) DeepCopyInto(out *NodeOperationStatus) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]NodeOperationNodeStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeOperationStatus.
+func (in *NodeOperationStatus) DeepCopy() *NodeOperationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeOperationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingPolicy) DeepCopyInto(out *ScalingPolicy) {
+	*out = *in
+	if in.IdleSessionNumThreshold != nil {
+		in, out := &in.IdleSessionNumThreshold, &out.IdleSessionNumThreshold
+		*out = new(IdelSessionNumThreshold)
+		**out = **in
+	}
+	if in.IdleSessionPercentThreshold != nil {
+		in, out := &in.IdleSessionPercentThreshold, &out.IdleSessionPercentThreshold
+		*out = new(IdelSessionPercentThreshold)
+		**out = **in
+	}
+	if in.SessionDemand != nil {
+		in, out := &in.SessionDemand, &out.SessionDemand
+		*out = new(SessionDemandScalingPolicy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingPolicy.
+func (in *ScalingPolicy) DeepCopy() *ScalingPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionAffinity) DeepCopyInto(out *SessionAffinity) {
+	*out = *in
+	if in.SessionAffinity != nil {
+		in, out := &in.SessionAffinity, &out.SessionAffinity
+		*out = make([]SessionAffinityTerm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SessionAntiAffinity != nil {
+		in, out := &in.SessionAntiAffinity, &out.SessionAntiAffinity
+		*out = make([]SessionAffinityTerm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionAffinity.
+func (in *SessionAffinity) DeepCopy() *SessionAffinity {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionAffinity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionAffinityTerm) DeepCopyInto(out *SessionAffinityTerm) {
+	*out = *in
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionAffinityTerm.
+func (in *SessionAffinityTerm) DeepCopy() *SessionAffinityTerm {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionAffinityTerm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionAttachment) DeepCopyInto(out *SessionAttachment) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionAttachment.
+func (in *SessionAttachment) DeepCopy() *SessionAttachment {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionAttachment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionBackpressure) DeepCopyInto(out *SessionBackpressure) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionBackpressure.
+func (in *SessionBackpressure) DeepCopy() *SessionBackpressure {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionBackpressure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionDemandScalingPolicy) DeepCopyInto(out *SessionDemandScalingPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionDemandScalingPolicy.
+func (in *SessionDemandScalingPolicy) DeepCopy() *SessionDemandScalingPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionDemandScalingPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionHealthCheck) DeepCopyInto(out *SessionHealthCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionHealthCheck.
+func (in *SessionHealthCheck) DeepCopy() *SessionHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionMigration) DeepCopyInto(out *SessionMigration) {
+	*out = *in
+	*out = *in
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.SourceClosedTime != nil {
+		in, out := &in.SourceClosedTime, &out.SourceClosedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionMigration.
+func (in *SessionMigration) DeepCopy() *SessionMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionPodUsage) DeepCopyInto(out *SessionPodUsage) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionPodUsage.
+func (in *SessionPodUsage) DeepCopy() *SessionPodUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionPodUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionQualityScore) DeepCopyInto(out *SessionQualityScore) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionQualityScore.
+func (in *SessionQualityScore) DeepCopy() *SessionQualityScore {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionQualityScore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionRelay) DeepCopyInto(out *SessionRelay) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionRelay.
+func (in *SessionRelay) DeepCopy() *SessionRelay {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionRelay)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionUsage) DeepCopyInto(out *SessionUsage) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionUsage.
+func (in *SessionUsage) DeepCopy() *SessionUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SessionUsage) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionUsageList) DeepCopyInto(out *SessionUsageList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SessionUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionUsageList.
+func (in *SessionUsageList) DeepCopy() *SessionUsageList {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionUsageList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SessionUsageList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionUsageSpec) DeepCopyInto(out *SessionUsageSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionUsageSpec.
+func (in *SessionUsageSpec) DeepCopy() *SessionUsageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionUsageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionUsageStatus) DeepCopyInto(out *SessionUsageStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]SessionPodUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionUsageStatus.
+func (in *SessionUsageStatus) DeepCopy() *SessionUsageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionUsageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZramConfig) DeepCopyInto(out *ZramConfig) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZramConfig.
+func (in *ZramConfig) DeepCopy() *ZramConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ZramConfig)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -0,0 +1,133 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+)
+
+// conversions between v1beta2 and v1 must be lossless in both directions, every v1beta2 field has a v1 field,
+// a new v1beta2 field without v1 counterpart need to be kept in a v1 annotation, round trip is checked by fuzz tests,
+// type meta is not converted, scheme set it to target version
+
+func Convert_v1beta2_Application_To_v1_Application(in *Application, out *fornaxv1.Application) error {
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+
+	spec := in.Spec.DeepCopy()
+	out.Spec = fornaxv1.ApplicationSpec{
+		Containers:              spec.Containers,
+		UsingNodeSessionService: spec.UsingNodeSessionService,
+		ConfigData:              spec.ConfigData,
+		ScalingPolicy: fornaxv1.ScalingPolicy{
+			MinimumInstance:             spec.Autoscaling.MinInstances,
+			MaximumInstance:             spec.Autoscaling.MaxInstances,
+			Burst:                       spec.Autoscaling.Burst,
+			ScalingPolicyType:           spec.Autoscaling.Policy,
+			IdleSessionNumThreshold:     spec.Autoscaling.IdleSessionNumThreshold,
+			IdleSessionPercentThreshold: spec.Autoscaling.IdleSessionPercentThreshold,
+			SessionDemand:               spec.Autoscaling.SessionDemand,
+		},
+		ReadinessGates:              spec.ReadinessGates,
+		SessionHealthCheck:          spec.SessionHealthCheck,
+		Secret:                      spec.Secret,
+		TLS:                         spec.TLS,
+		SuspensionPolicy:            spec.Autoscaling.Suspension,
+		Ownership:                   spec.Ownership,
+		NATTraversal:                spec.NATTraversal,
+		WarmPool:                    spec.Autoscaling.WarmPool,
+		WarmUp:                      spec.WarmUp,
+		RolloutPolicy:               spec.Rollout,
+		Sysctls:                     spec.Sysctls,
+		RestartPolicy:               spec.RestartPolicy,
+		MaxSessionDurationSeconds:   spec.Sessions.MaxDurationSeconds,
+		SessionExpiryWarningSeconds: spec.Sessions.ExpiryWarningSeconds,
+		ProcessLimits:               spec.ProcessLimits,
+		NetworkPolicy:               spec.NetworkPolicy,
+		InstanceNaming:              spec.InstanceNaming,
+	}
+	return nil
+}
+
+func Convert_v1_Application_To_v1beta2_Application(in *fornaxv1.Application, out *Application) error {
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+
+	spec := in.Spec.DeepCopy()
+	out.Spec = ApplicationSpec{
+		Containers:              spec.Containers,
+		UsingNodeSessionService: spec.UsingNodeSessionService,
+		ConfigData:              spec.ConfigData,
+		Autoscaling: ApplicationAutoscaling{
+			MinInstances:                spec.ScalingPolicy.MinimumInstance,
+			MaxInstances:                spec.ScalingPolicy.MaximumInstance,
+			Burst:                       spec.ScalingPolicy.Burst,
+			Policy:                      spec.ScalingPolicy.ScalingPolicyType,
+			IdleSessionNumThreshold:     spec.ScalingPolicy.IdleSessionNumThreshold,
+			IdleSessionPercentThreshold: spec.ScalingPolicy.IdleSessionPercentThreshold,
+			SessionDemand:               spec.ScalingPolicy.SessionDemand,
+			WarmPool:                    spec.WarmPool,
+			Suspension:                  spec.SuspensionPolicy,
+		},
+		Rollout: spec.RolloutPolicy,
+		Sessions: ApplicationSessionLimits{
+			MaxDurationSeconds:   spec.MaxSessionDurationSeconds,
+			ExpiryWarningSeconds: spec.SessionExpiryWarningSeconds,
+		},
+		ReadinessGates:     spec.ReadinessGates,
+		SessionHealthCheck: spec.SessionHealthCheck,
+		Secret:             spec.Secret,
+		TLS:                spec.TLS,
+		Ownership:          spec.Ownership,
+		NATTraversal:       spec.NATTraversal,
+		WarmUp:             spec.WarmUp,
+		Sysctls:            spec.Sysctls,
+		RestartPolicy:      spec.RestartPolicy,
+		ProcessLimits:      spec.ProcessLimits,
+		NetworkPolicy:      spec.NetworkPolicy,
+		InstanceNaming:     spec.InstanceNaming,
+	}
+	return nil
+}
+
+func Convert_v1beta2_ApplicationList_To_v1_ApplicationList(in *ApplicationList, out *fornaxv1.ApplicationList) error {
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	out.Items = nil
+	if in.Items != nil {
+		out.Items = make([]fornaxv1.Application, len(in.Items))
+		for i := range in.Items {
+			if err := Convert_v1beta2_Application_To_v1_Application(&in.Items[i], &out.Items[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func Convert_v1_ApplicationList_To_v1beta2_ApplicationList(in *fornaxv1.ApplicationList, out *ApplicationList) error {
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	out.Items = nil
+	if in.Items != nil {
+		out.Items = make([]Application, len(in.Items))
+		for i := range in.Items {
+			if err := Convert_v1_Application_To_v1beta2_Application(&in.Items[i], &out.Items[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
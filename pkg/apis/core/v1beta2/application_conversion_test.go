@@ -0,0 +1,150 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"math/rand"
+	"testing"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	fuzz "github.com/google/gofuzz"
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+const fuzzIterations = 200
+
+func newFuzzer(t *testing.T) *fuzz.Fuzzer {
+	seed := rand.Int63()
+	t.Logf("fuzz seed %d", seed)
+	return fuzzer.FuzzerFor(fuzzer.MergeFuzzerFuncs(metafuzzer.Funcs, func(codecs serializer.CodecFactory) []interface{} {
+		return []interface{}{
+			func(q *resource.Quantity, c fuzz.Continue) {
+				*q = *resource.NewQuantity(c.Int63n(1000), resource.DecimalSI)
+			},
+		}
+	}), rand.NewSource(seed), serializer.NewCodecFactory(runtime.NewScheme())).NilChance(0.3).NumElements(0, 2)
+}
+
+func TestApplicationRoundTripFromV1beta2(t *testing.T) {
+	f := newFuzzer(t)
+	for i := 0; i < fuzzIterations; i++ {
+		in := &Application{}
+		f.Fuzz(in)
+		in.TypeMeta = metav1.TypeMeta{}
+
+		storage := &fornaxv1.Application{}
+		if err := in.ConvertToStorageVersion(storage); err != nil {
+			t.Fatal(err)
+		}
+		out := &Application{}
+		if err := out.ConvertFromStorageVersion(storage); err != nil {
+			t.Fatal(err)
+		}
+		if !apiequality.Semantic.DeepEqual(in, out) {
+			t.Fatalf("v1beta2 application changed after round trip through v1: %s", diff.ObjectReflectDiff(in, out))
+		}
+	}
+}
+
+func TestApplicationRoundTripFromV1(t *testing.T) {
+	f := newFuzzer(t)
+	for i := 0; i < fuzzIterations; i++ {
+		in := &fornaxv1.Application{}
+		f.Fuzz(in)
+		in.TypeMeta = metav1.TypeMeta{}
+
+		beta := &Application{}
+		if err := beta.ConvertFromStorageVersion(in); err != nil {
+			t.Fatal(err)
+		}
+		out := &fornaxv1.Application{}
+		if err := beta.ConvertToStorageVersion(out); err != nil {
+			t.Fatal(err)
+		}
+		if !apiequality.Semantic.DeepEqual(in, out) {
+			t.Fatalf("v1 application changed after round trip through v1beta2: %s", diff.ObjectReflectDiff(in, out))
+		}
+	}
+}
+
+func TestApplicationListConversionInScheme(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := fornaxv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	f := newFuzzer(t)
+	for i := 0; i < fuzzIterations/10; i++ {
+		in := &fornaxv1.ApplicationList{}
+		f.Fuzz(in)
+		in.TypeMeta = metav1.TypeMeta{}
+		for j := range in.Items {
+			in.Items[j].TypeMeta = metav1.TypeMeta{}
+		}
+
+		beta := &ApplicationList{}
+		if err := scheme.Convert(in, beta, nil); err != nil {
+			t.Fatal(err)
+		}
+		out := &fornaxv1.ApplicationList{}
+		if err := scheme.Convert(beta, out, nil); err != nil {
+			t.Fatal(err)
+		}
+		if !apiequality.Semantic.DeepEqual(in, out) {
+			t.Fatalf("v1 application list changed after round trip through v1beta2: %s", diff.ObjectReflectDiff(in, out))
+		}
+	}
+}
+
+func TestApplicationConversionMovesFields(t *testing.T) {
+	in := &Application{
+		Spec: ApplicationSpec{
+			Autoscaling: ApplicationAutoscaling{
+				MinInstances: 1,
+				MaxInstances: 10,
+				Policy:       fornaxv1.ScalingPolicyTypeSessionDemand,
+				WarmPool:     &fornaxv1.ApplicationWarmPool{Size: 2},
+				Suspension:   &fornaxv1.ApplicationSuspensionPolicy{IdleSeconds: 600},
+			},
+			Rollout:  &fornaxv1.ApplicationRolloutPolicy{BakeSeconds: 60},
+			Sessions: ApplicationSessionLimits{MaxDurationSeconds: 3600},
+		},
+	}
+	out := &fornaxv1.Application{}
+	if err := in.ConvertToStorageVersion(out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Spec.ScalingPolicy.MinimumInstance != 1 || out.Spec.ScalingPolicy.MaximumInstance != 10 || out.Spec.ScalingPolicy.ScalingPolicyType != fornaxv1.ScalingPolicyTypeSessionDemand {
+		t.Errorf("autoscaling is not converted to scaling policy, got %+v", out.Spec.ScalingPolicy)
+	}
+	if out.Spec.WarmPool == nil || out.Spec.WarmPool.Size != 2 || out.Spec.SuspensionPolicy == nil || out.Spec.SuspensionPolicy.IdleSeconds != 600 {
+		t.Errorf("warm pool and suspension are not converted, got %+v %+v", out.Spec.WarmPool, out.Spec.SuspensionPolicy)
+	}
+	if out.Spec.RolloutPolicy == nil || out.Spec.RolloutPolicy.BakeSeconds != 60 || out.Spec.MaxSessionDurationSeconds != 3600 {
+		t.Errorf("rollout and session limits are not converted, got %+v %d", out.Spec.RolloutPolicy, out.Spec.MaxSessionDurationSeconds)
+	}
+}
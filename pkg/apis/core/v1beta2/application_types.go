@@ -0,0 +1,210 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/apiserver-runtime/pkg/builder/resource"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Application
+// +k8s:openapi-gen=true
+type Application struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ApplicationSpec            `json:"spec,omitempty"`
+	Status fornaxv1.ApplicationStatus `json:"status,omitempty"`
+}
+
+// ApplicationList
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ApplicationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Application `json:"items"`
+}
+
+// ApplicationSpec defines the desired state of Application, scaling, warm pool and suspension are grouped in autoscaling,
+// session limits are grouped in sessions, other fields are same as v1
+type ApplicationSpec struct {
+	// runtime image and resource requirement of a application container, a container request gpus using nvidia.com/gpu resource
+	Containers []corev1.Container `json:"containers,omitempty"`
+
+	// container will use grpc session service on node agent to start application session,
+	// or a websocket sidecar if application has websocket session runtime annotation
+	UsingNodeSessionService bool `json:"usingNodeSessionService,omitempty"`
+
+	// +optional
+	ConfigData map[string]string `json:"configData,omitempty"`
+
+	// how many pods application run and when it scale to zero or suspend
+	// +optional
+	Autoscaling ApplicationAutoscaling `json:"autoscaling,omitempty"`
+
+	// watch health of new pods after container spec change, pause or roll back a failing rollout
+	// +optional
+	Rollout *fornaxv1.ApplicationRolloutPolicy `json:"rollout,omitempty"`
+
+	// default limits of sessions which do not set their own
+	// +optional
+	Sessions ApplicationSessionLimits `json:"sessions,omitempty"`
+
+	// +optional
+	ReadinessGates []fornaxv1.ApplicationReadinessGate `json:"readinessGates,omitempty"`
+
+	// +optional
+	SessionHealthCheck *fornaxv1.SessionHealthCheck `json:"sessionHealthCheck,omitempty"`
+
+	// +optional
+	Secret *fornaxv1.ApplicationSecret `json:"secret,omitempty"`
+
+	// +optional
+	TLS *fornaxv1.ApplicationTLS `json:"tls,omitempty"`
+
+	// +optional
+	Ownership *fornaxv1.ApplicationOwnership `json:"ownership,omitempty"`
+
+	// +optional
+	NATTraversal *fornaxv1.ApplicationNATTraversal `json:"natTraversal,omitempty"`
+
+	// +optional
+	WarmUp *fornaxv1.ApplicationWarmUp `json:"warmUp,omitempty"`
+
+	// +optional
+	Sysctls []corev1.Sysctl `json:"sysctls,omitempty"`
+
+	// +optional
+	RestartPolicy *fornaxv1.ApplicationRestartPolicy `json:"restartPolicy,omitempty"`
+
+	// +optional
+	ProcessLimits *fornaxv1.ApplicationProcessLimits `json:"processLimits,omitempty"`
+
+	// +optional
+	NetworkPolicy *fornaxv1.ApplicationNetworkPolicy `json:"networkPolicy,omitempty"`
+
+	// +optional
+	InstanceNaming *fornaxv1.ApplicationInstanceNaming `json:"instanceNaming,omitempty"`
+}
+
+// ApplicationAutoscaling is v1 scaling policy, warm pool and suspension policy
+type ApplicationAutoscaling struct {
+	// +optional
+	MinInstances uint32 `json:"minInstances,omitempty"`
+
+	// +optional
+	MaxInstances uint32 `json:"maxInstances,omitempty"`
+
+	// maximum pods created in one sync
+	// +optional
+	Burst uint32 `json:"burst,omitempty"`
+
+	// scale by idle session number, idle session percent or session demand
+	// +optional
+	Policy fornaxv1.ScalingPolicyType `json:"policy,omitempty"`
+
+	// +optional, must set if Policy == "idle_session_number"
+	IdleSessionNumThreshold *fornaxv1.IdelSessionNumThreshold `json:"idleSessionNumThreshold,omitempty"`
+
+	// +optional, must set if Policy == "idle_session_percent"
+	IdleSessionPercentThreshold *fornaxv1.IdelSessionPercentThreshold `json:"idleSessionPercentThreshold,omitempty"`
+
+	// +optional, used if Policy == "session_demand"
+	SessionDemand *fornaxv1.SessionDemandScalingPolicy `json:"sessionDemand,omitempty"`
+
+	// +optional
+	WarmPool *fornaxv1.ApplicationWarmPool `json:"warmPool,omitempty"`
+
+	// suspend application when it does not have session for a long time, nil means never suspend
+	// +optional
+	Suspension *fornaxv1.ApplicationSuspensionPolicy `json:"suspension,omitempty"`
+}
+
+// ApplicationSessionLimits is v1 session time limit defaults
+type ApplicationSessionLimits struct {
+	// 0 mean sessions are not time limited
+	// +optional
+	MaxDurationSeconds uint32 `json:"maxDurationSeconds,omitempty"`
+
+	// +optional, default 60
+	ExpiryWarningSeconds uint32 `json:"expiryWarningSeconds,omitempty"`
+}
+
+var _ resource.Object = &Application{}
+var _ resource.MultiVersionObject = &Application{}
+
+func (in *Application) GetObjectMeta() *metav1.ObjectMeta {
+	return &in.ObjectMeta
+}
+
+func (in *Application) NamespaceScoped() bool {
+	return true
+}
+
+func (in *Application) New() runtime.Object {
+	return &Application{}
+}
+
+func (in *Application) NewList() runtime.Object {
+	return &ApplicationList{}
+}
+
+var ApplicationGrv = schema.GroupVersionResource{
+	Group:    "core.fornax-serverless.centaurusinfra.io",
+	Version:  "v1beta2",
+	Resource: "applications",
+}
+
+func (in *Application) GetGroupVersionResource() schema.GroupVersionResource {
+	return ApplicationGrv
+}
+
+// IsStorageVersion return false, v1 is storage version of applications
+func (in *Application) IsStorageVersion() bool {
+	return false
+}
+
+func (in *Application) NewStorageVersionObject() runtime.Object {
+	return &fornaxv1.Application{}
+}
+
+func (in *Application) ConvertToStorageVersion(storageObj runtime.Object) error {
+	return Convert_v1beta2_Application_To_v1_Application(in, storageObj.(*fornaxv1.Application))
+}
+
+func (in *Application) ConvertFromStorageVersion(storageObj runtime.Object) error {
+	return Convert_v1_Application_To_v1beta2_Application(storageObj.(*fornaxv1.Application), in)
+}
+
+func (in *ApplicationList) GetListMeta() *metav1.ListMeta {
+	return &in.ListMeta
+}
+
+// Application implements ObjectWithStatusSubResource interface, status is same as v1
+var _ resource.ObjectWithStatusSubResource = &Application{}
+
+func (in *Application) GetStatus() resource.StatusSubResource {
+	return in.Status
+}
@@ -0,0 +1,25 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// v1beta2 is served beside v1, v1 stay storage version, v1beta2 objects are converted to v1 before they reach storage,
+// so clients of v1 keep working while application spec evolve in v1beta2
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=package,register
+// +k8s:conversion-gen=centaurusinfra.io/fornax-serverless/pkg/apis/core/v1
+// +k8s:defaulter-gen=TypeMeta
+// +groupName=core.fornax-serverless.centaurusinfra.io
+package v1beta2 // import "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1beta2"
@@ -0,0 +1,61 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var SchemeGroupVersion = schema.GroupVersion{Group: "core.fornax-serverless.centaurusinfra.io", Version: "v1beta2"}
+
+// Resource takes an unqualified resource and returns a Group qualified GroupResource
+func Resource(resource string) schema.GroupResource {
+	return SchemeGroupVersion.WithResource(resource).GroupResource()
+}
+
+var AddToScheme = func(scheme *runtime.Scheme) error {
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	scheme.AddKnownTypes(SchemeGroupVersion, &Application{}, &ApplicationList{})
+	return AddConversionFuncs(scheme)
+}
+
+// AddConversionFuncs register conversions between v1beta2 and storage version v1,
+// apiserver builder only register object conversion of a multi version resource, list conversion is added here
+func AddConversionFuncs(scheme *runtime.Scheme) error {
+	if err := scheme.AddConversionFunc((*Application)(nil), (*fornaxv1.Application)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_Application_To_v1_Application(a.(*Application), b.(*fornaxv1.Application))
+	}); err != nil {
+		return err
+	}
+	if err := scheme.AddConversionFunc((*fornaxv1.Application)(nil), (*Application)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_Application_To_v1beta2_Application(a.(*fornaxv1.Application), b.(*Application))
+	}); err != nil {
+		return err
+	}
+	if err := scheme.AddConversionFunc((*ApplicationList)(nil), (*fornaxv1.ApplicationList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_ApplicationList_To_v1_ApplicationList(a.(*ApplicationList), b.(*fornaxv1.ApplicationList))
+	}); err != nil {
+		return err
+	}
+	return scheme.AddConversionFunc((*fornaxv1.ApplicationList)(nil), (*ApplicationList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ApplicationList_To_v1beta2_ApplicationList(a.(*fornaxv1.ApplicationList), b.(*ApplicationList))
+	})
+}
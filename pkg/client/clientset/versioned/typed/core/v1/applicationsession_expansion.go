@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The fornax-serverless Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	v1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	scheme "centaurusinfra.io/fornax-serverless/pkg/client/clientset/versioned/scheme"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sscheme "k8s.io/client-go/kubernetes/scheme"
+	rest "k8s.io/client-go/rest"
+)
+
+// The ApplicationSessionExpansion interface allows manually adding extra methods to the ApplicationSessionInterface.
+type ApplicationSessionExpansion interface {
+	Migrate(ctx context.Context, name, targetPod string, opts metav1.UpdateOptions) (*v1.ApplicationSession, error)
+	GetLogs(name string, opts *corev1.PodLogOptions) *rest.Request
+}
+
+// Migrate request fornaxcore to move a open session to target pod using migrate subresource,
+// a empty target pod let fornaxcore pick a idle pod of application
+func (c *applicationSessions) Migrate(ctx context.Context, name, targetPod string, opts metav1.UpdateOptions) (result *v1.ApplicationSession, err error) {
+	request := &v1.ApplicationSession{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: c.ns},
+		Status: v1.ApplicationSessionStatus{
+			Migration: &v1.SessionMigration{TargetPod: targetPod},
+		},
+	}
+	result = &v1.ApplicationSession{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("applicationsessions").
+		Name(name).
+		SubResource("migrate").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(request).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// GetLogs construct a request to read container logs of pod a session is on using logs subresource,
+// options are same as pod logs, so they are encoded using kubernetes parameter codec
+func (c *applicationSessions) GetLogs(name string, opts *corev1.PodLogOptions) *rest.Request {
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("applicationsessions").
+		Name(name).
+		SubResource("logs").
+		VersionedParams(opts, k8sscheme.ParameterCodec)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The fornax-serverless Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	corev1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	k8scorev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	restclient "k8s.io/client-go/rest"
+	fakerest "k8s.io/client-go/rest/fake"
+	testing "k8s.io/client-go/testing"
+)
+
+func (c *FakeApplicationSessions) Migrate(ctx context.Context, name, targetPod string, opts v1.UpdateOptions) (*corev1.ApplicationSession, error) {
+	request := &corev1.ApplicationSession{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: c.ns},
+		Status: corev1.ApplicationSessionStatus{
+			Migration: &corev1.SessionMigration{TargetPod: targetPod},
+		},
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(applicationsessionsResource, "migrate", c.ns, request), &corev1.ApplicationSession{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*corev1.ApplicationSession), err
+}
+
+func (c *FakeApplicationSessions) GetLogs(name string, opts *k8scorev1.PodLogOptions) *restclient.Request {
+	action := testing.GenericActionImpl{}
+	action.Verb = "get"
+	action.Namespace = c.ns
+	action.Resource = applicationsessionsResource
+	action.Subresource = "logs"
+	action.Value = opts
+
+	_, _ = c.Fake.Invokes(action, &corev1.ApplicationSession{})
+	fakeClient := &fakerest.RESTClient{
+		Client: fakerest.CreateHTTPClient(func(request *http.Request) (*http.Response, error) {
+			resp := &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader("fake logs")),
+			}
+			return resp, nil
+		}),
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		GroupVersion:         applicationsessionsKind.GroupVersion(),
+		VersionedAPIPath:     fmt.Sprintf("/apis/%s/namespaces/%s/applicationsessions/%s/logs", applicationsessionsKind.GroupVersion(), c.ns, name),
+	}
+	return fakeClient.Request()
+}
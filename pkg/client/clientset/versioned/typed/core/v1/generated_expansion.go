@@ -21,8 +21,6 @@ type ApplicationExpansion interface{}
 
 type ApplicationInstanceExpansion interface{}
 
-type ApplicationSessionExpansion interface{}
-
 type ClientSessionExpansion interface{}
 
 type IngressEndpointExpansion interface{}
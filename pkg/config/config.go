@@ -19,4 +19,74 @@ package config
 const (
 	DefaultFornaxCoreNodeNameSpace = "node.centaurusinfra.io"
 	DefaultDomainName              = "cluster.centaurusinfra.io"
+
+	// file used to persist memory store revision high water mark if storage configuration does not set revisionFile
+	DefaultFornaxCoreRevisionFile = "/var/lib/fornaxcore/memory_revision"
+
+	// file used to configure store of each resource, optional
+	DefaultFornaxCoreStorageConfigFile = "/etc/fornaxcore/storage.json"
+
+	// CA used to issue application certificates, a self signed CA is generated if files do not exist
+	DefaultFornaxCoreCACertFile = "/etc/fornaxcore/ca.crt"
+	DefaultFornaxCoreCAKeyFile  = "/etc/fornaxcore/ca.key"
+
+	// file used to select issuer of application certificates, local CA or spiffe, optional
+	DefaultFornaxCoreCertificateIssuerConfigFile = "/etc/fornaxcore/certificate_issuer.json"
+
+	// key used to seal application secret data at rest, a random key is generated if file does not exist,
+	// all fornaxcore replicas must share same key file
+	DefaultFornaxCoreSecretKeyFile = "/etc/fornaxcore/secret.key"
+
+	// file used to configure out of process scheduler extenders, optional
+	DefaultFornaxCoreSchedulerExtenderConfigFile = "/etc/fornaxcore/scheduler_extender.json"
+
+	// file used to configure which applications users can see by label selectors, optional
+	DefaultFornaxCoreAccessPartitionConfigFile = "/etc/fornaxcore/access_partition.json"
+
+	// append only log of pod and session placement decisions
+	DefaultFornaxCorePlacementAuditLogFile = "/var/lib/fornaxcore/placement_audit.log"
+
+	// file used to configure goroutine and lock contention watchdog, optional
+	DefaultFornaxCoreWatchdogConfigFile = "/etc/fornaxcore/watchdog.json"
+
+	// file used to configure default node lease duration, renew interval and eviction grace, optional
+	DefaultFornaxCoreNodeLeaseConfigFile = "/etc/fornaxcore/node_lease.json"
+
+	// file used to enable cost aware scheduling and its cost weight, optional
+	DefaultFornaxCoreSchedulerCostConfigFile = "/etc/fornaxcore/scheduler_cost.json"
+
+	// file used to enable recording of watch events and node messages for replay, optional
+	DefaultFornaxCoreEventRecorderConfigFile = "/etc/fornaxcore/event_recorder.json"
+
+	// file used to enable session gateway and its advertised addresses and port range, optional
+	DefaultFornaxCoreSessionGatewayConfigFile = "/etc/fornaxcore/session_gateway.json"
+
+	// file used to configure conversion webhooks of extension resources, optional
+	DefaultFornaxCoreExtensionConversionConfigFile = "/etc/fornaxcore/extension_conversion.json"
+
+	// file used to enable mtls of node agent grpc channel, optional
+	DefaultFornaxCoreNodePKIConfigFile = "/etc/fornaxcore/node_pki.json"
+
+	// CA used to issue node agent and grpc server certificates, a self signed CA is generated and saved if files do not exist
+	DefaultFornaxCoreNodeCACertFile = "/etc/fornaxcore/node-ca.crt"
+	DefaultFornaxCoreNodeCAKeyFile  = "/etc/fornaxcore/node-ca.key"
+
+	// bootstrap tokens node agents use to request first certificate
+	DefaultFornaxCoreBootstrapTokenFile = "/etc/fornaxcore/bootstrap_tokens.json"
+
+	// file used to configure authentication and namespace rbac of api server clients, optional
+	DefaultFornaxCoreAuthConfigFile = "/etc/fornaxcore/auth.json"
+
+	// file used to configure mutating and validating admission webhooks of applications and sessions, optional
+	DefaultFornaxCoreAdmissionWebhookConfigFile = "/etc/fornaxcore/admission_webhooks.json"
+
+	// file used to configure allowed version skew between fornaxcore and node agents, optional
+	DefaultFornaxCoreNodeVersionSkewConfigFile = "/etc/fornaxcore/node_version_skew.json"
+
+	// file used to enable leader election of multiple fornaxcore replicas and memory store replication to followers, optional
+	DefaultFornaxCoreHAConfigFile = "/etc/fornaxcore/ha.json"
+
+	// CA shared by fornaxcore replicas to issue replica certificates of store replication, files must be same on all replicas
+	DefaultFornaxCoreReplicaCACertFile = "/etc/fornaxcore/replica-ca.crt"
+	DefaultFornaxCoreReplicaCAKeyFile  = "/etc/fornaxcore/replica-ca.key"
 )
@@ -0,0 +1,460 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	jsonpatch "github.com/evanphx/json-patch"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/warning"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+type WebhookType string
+type FailurePolicy string
+
+const (
+	WebhookTypeMutating   WebhookType = "Mutating"
+	WebhookTypeValidating WebhookType = "Validating"
+
+	FailurePolicyFail   FailurePolicy = "Fail"
+	FailurePolicyIgnore FailurePolicy = "Ignore"
+
+	DefaultAdmissionWebhookTimeoutSeconds = 10
+
+	AdmissionReviewAPIVersion = "admission.k8s.io/v1"
+	AdmissionReviewKind       = "AdmissionReview"
+)
+
+var (
+	admissionWebhookRequests = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_admission",
+			Name:           "webhook_requests_total",
+			Help:           "Number of admission reviews sent to admission webhooks",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"webhook", "type", "result"},
+	)
+	admissionWebhookLatency = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem:      "fornax_admission",
+			Name:           "webhook_duration_seconds",
+			Help:           "Latency of admission webhooks",
+			Buckets:        metrics.ExponentialBuckets(0.001, 2, 14),
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"webhook", "type"},
+	)
+
+	// resources admission webhooks can intercept
+	webhookResources = map[string]bool{
+		fornaxv1.ApplicationGrv.Resource:        true,
+		fornaxv1.ApplicationSessionGrv.Resource: true,
+	}
+	webhookOperations = map[admission.Operation]bool{
+		admission.Create: true,
+		admission.Update: true,
+	}
+)
+
+func init() {
+	legacyregistry.MustRegister(admissionWebhookRequests, admissionWebhookLatency)
+}
+
+// AdmissionWebhook is called when applications or sessions are created or updated by api server clients,
+// mutating webhooks are called in order and return json patches, validating webhooks are called after mutation and object validation,
+// objects are always sent in v1, a webhook written for kubernetes admission works since review is admission.k8s.io/v1
+type AdmissionWebhook struct {
+	Name string      `json:"name"`
+	Type WebhookType `json:"type"`
+
+	// +optional, applications and/or applicationsessions, default both
+	Resources []string `json:"resources,omitempty"`
+
+	// +optional, CREATE and/or UPDATE, default both
+	Operations []admission.Operation `json:"operations,omitempty"`
+
+	// https url admission review is posted to
+	URL string `json:"url"`
+
+	// pem encoded ca certificates to verify webhook server, system roots are used if empty
+	// +optional
+	CABundle string `json:"caBundle,omitempty"`
+
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+
+	// +optional, Fail or Ignore when webhook can not be called, default Fail
+	FailurePolicy FailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+type AdmissionWebhookConfiguration struct {
+	Webhooks []AdmissionWebhook `json:"webhooks,omitempty"`
+}
+
+// LoadAdmissionWebhookConfiguration read admission webhooks from a json file, no webhook is configured if file does not exist
+func LoadAdmissionWebhookConfiguration(file string) (*AdmissionWebhookConfiguration, error) {
+	config := &AdmissionWebhookConfiguration{}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid admission webhook configuration %s: %v", file, err)
+	}
+	return config, nil
+}
+
+type webhookClient struct {
+	*AdmissionWebhook
+	client  *http.Client
+	timeout time.Duration
+}
+
+// WebhookAdmission implement mutation and validation admission interface using configured webhooks
+type WebhookAdmission struct {
+	mutating   []*webhookClient
+	validating []*webhookClient
+}
+
+var _ admission.MutationInterface = &WebhookAdmission{}
+var _ admission.ValidationInterface = &WebhookAdmission{}
+
+func NewWebhookAdmission(config *AdmissionWebhookConfiguration) (*WebhookAdmission, error) {
+	wa := &WebhookAdmission{}
+	for i := range config.Webhooks {
+		v := &config.Webhooks[i]
+		u, err := url.Parse(v.URL)
+		if err != nil || u.Scheme != "https" || len(u.Host) == 0 {
+			return nil, fmt.Errorf("admission webhook %s must be a https url, got %s", v.Name, v.URL)
+		}
+		for _, r := range v.Resources {
+			if !webhookResources[r] {
+				return nil, fmt.Errorf("admission webhook %s does not support resource %s", v.Name, r)
+			}
+		}
+		for _, op := range v.Operations {
+			if !webhookOperations[op] {
+				return nil, fmt.Errorf("admission webhook %s does not support operation %s", v.Name, op)
+			}
+		}
+		if v.FailurePolicy == "" {
+			v.FailurePolicy = FailurePolicyFail
+		}
+		if v.FailurePolicy != FailurePolicyFail && v.FailurePolicy != FailurePolicyIgnore {
+			return nil, fmt.Errorf("admission webhook %s has unknown failure policy %s", v.Name, v.FailurePolicy)
+		}
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if len(v.CABundle) > 0 {
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM([]byte(v.CABundle)) {
+				return nil, fmt.Errorf("invalid ca bundle of admission webhook %s", v.Name)
+			}
+			tlsConfig.RootCAs = pool
+		}
+		timeout := time.Duration(v.TimeoutSeconds) * time.Second
+		if timeout <= 0 {
+			timeout = DefaultAdmissionWebhookTimeoutSeconds * time.Second
+		}
+		client := &webhookClient{
+			AdmissionWebhook: v,
+			client:           &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}},
+			timeout:          timeout,
+		}
+		switch v.Type {
+		case WebhookTypeMutating:
+			wa.mutating = append(wa.mutating, client)
+		case WebhookTypeValidating:
+			wa.validating = append(wa.validating, client)
+		default:
+			return nil, fmt.Errorf("admission webhook %s has unknown type %s", v.Name, v.Type)
+		}
+		klog.InfoS("Admission webhook configured", "name", v.Name, "type", v.Type, "url", v.URL, "resources", v.Resources, "failure policy", v.FailurePolicy)
+	}
+	return wa, nil
+}
+
+// Handles implement admission.Interface
+func (wa *WebhookAdmission) Handles(operation admission.Operation) bool {
+	return webhookOperations[operation]
+}
+
+func (w *webhookClient) matches(a admission.Attributes) bool {
+	resource := a.GetResource()
+	if resource.Group != fornaxv1.ApplicationGrv.Group || !webhookResources[resource.Resource] || len(a.GetSubresource()) > 0 {
+		return false
+	}
+	if len(w.Resources) > 0 && !containsString(w.Resources, resource.Resource) {
+		return false
+	}
+	if len(w.Operations) > 0 {
+		for _, op := range w.Operations {
+			if op == a.GetOperation() {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Admit call mutating webhooks in order, every webhook see object patched by previous webhooks
+func (wa *WebhookAdmission) Admit(ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces) error {
+	for _, w := range wa.mutating {
+		if !w.matches(a) {
+			continue
+		}
+		response, err := w.call(ctx, a)
+		if err != nil {
+			if err = w.failed(err); err != nil {
+				return err
+			}
+			continue
+		}
+		if !response.Allowed {
+			return w.denied(response)
+		}
+		if len(response.Patch) > 0 {
+			if err := applyPatch(a, response.Patch); err != nil {
+				return apierrors.NewInternalError(fmt.Errorf("admission webhook %q returned invalid patch: %v", w.Name, err))
+			}
+		}
+	}
+	return nil
+}
+
+// Validate call validating webhooks, request is rejected if any webhook deny it
+func (wa *WebhookAdmission) Validate(ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces) error {
+	for _, w := range wa.validating {
+		if !w.matches(a) {
+			continue
+		}
+		response, err := w.call(ctx, a)
+		if err != nil {
+			if err = w.failed(err); err != nil {
+				return err
+			}
+			continue
+		}
+		if !response.Allowed {
+			return w.denied(response)
+		}
+	}
+	return nil
+}
+
+// failed return error of a webhook call failure according to failure policy
+func (w *webhookClient) failed(err error) error {
+	if w.FailurePolicy == FailurePolicyIgnore {
+		klog.ErrorS(err, "Admission webhook failed, ignored by failure policy", "webhook", w.Name)
+		return nil
+	}
+	return apierrors.NewInternalError(fmt.Errorf("failed calling admission webhook %q: %v", w.Name, err))
+}
+
+func (w *webhookClient) denied(response *admissionv1.AdmissionResponse) error {
+	status := metav1.Status{Status: metav1.StatusFailure, Code: http.StatusForbidden, Reason: metav1.StatusReasonForbidden}
+	message := ""
+	if response.Result != nil {
+		message = response.Result.Message
+		if response.Result.Code >= 400 {
+			status.Code = response.Result.Code
+		}
+		if len(response.Result.Reason) > 0 {
+			status.Reason = response.Result.Reason
+		}
+	}
+	status.Message = fmt.Sprintf("admission webhook %q denied the request: %s", w.Name, message)
+	return &apierrors.StatusError{ErrStatus: status}
+}
+
+// encodeObject encode a object as v1 json, objects in admission attributes have no type meta
+func encodeObject(obj runtime.Object, kind string) ([]byte, error) {
+	if obj == nil || reflect.ValueOf(obj).IsNil() {
+		return nil, nil
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	m["apiVersion"] = fornaxv1.SchemeGroupVersion.String()
+	m["kind"] = kind
+	return json.Marshal(m)
+}
+
+func (w *webhookClient) call(ctx context.Context, a admission.Attributes) (*admissionv1.AdmissionResponse, error) {
+	kind := a.GetKind()
+	object, err := encodeObject(a.GetObject(), kind.Kind)
+	if err != nil {
+		return nil, err
+	}
+	oldObject, err := encodeObject(a.GetOldObject(), kind.Kind)
+	if err != nil {
+		return nil, err
+	}
+	resource := a.GetResource()
+	dryRun := a.IsDryRun()
+	request := &admissionv1.AdmissionRequest{
+		UID:             uuid.NewUUID(),
+		Kind:            metav1.GroupVersionKind{Group: fornaxv1.SchemeGroupVersion.Group, Version: fornaxv1.SchemeGroupVersion.Version, Kind: kind.Kind},
+		Resource:        metav1.GroupVersionResource{Group: resource.Group, Version: fornaxv1.SchemeGroupVersion.Version, Resource: resource.Resource},
+		RequestKind:     &metav1.GroupVersionKind{Group: kind.Group, Version: kind.Version, Kind: kind.Kind},
+		RequestResource: &metav1.GroupVersionResource{Group: resource.Group, Version: resource.Version, Resource: resource.Resource},
+		Name:            a.GetName(),
+		Namespace:       a.GetNamespace(),
+		Operation:       admissionv1.Operation(a.GetOperation()),
+		Object:          runtime.RawExtension{Raw: object},
+		OldObject:       runtime.RawExtension{Raw: oldObject},
+		DryRun:          &dryRun,
+	}
+	if u := a.GetUserInfo(); u != nil {
+		request.UserInfo = authenticationv1.UserInfo{Username: u.GetName(), UID: u.GetUID(), Groups: u.GetGroups()}
+		for k, v := range u.GetExtra() {
+			if request.UserInfo.Extra == nil {
+				request.UserInfo.Extra = map[string]authenticationv1.ExtraValue{}
+			}
+			request.UserInfo.Extra[k] = v
+		}
+	}
+	body, err := json.Marshal(&admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: AdmissionReviewAPIVersion, Kind: AdmissionReviewKind},
+		Request:  request,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	result := "error"
+	defer func() {
+		admissionWebhookRequests.WithLabelValues(w.Name, string(w.Type), result).Inc()
+		admissionWebhookLatency.WithLabelValues(w.Name, string(w.Type)).Observe(time.Since(start).Seconds())
+	}()
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("admission webhook returned status %d: %s", resp.StatusCode, string(data))
+	}
+	review := &admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(data, review); err != nil {
+		return nil, err
+	}
+	if review.Response == nil || review.Response.UID != request.UID {
+		return nil, fmt.Errorf("admission webhook response does not match request %s", request.UID)
+	}
+	if len(review.Response.Patch) > 0 && (review.Response.PatchType == nil || *review.Response.PatchType != admissionv1.PatchTypeJSONPatch) {
+		return nil, fmt.Errorf("admission webhook returned unsupported patch type")
+	}
+	for _, v := range review.Response.Warnings {
+		warning.AddWarning(ctx, w.Name, v)
+	}
+	if review.Response.Allowed {
+		result = "allowed"
+	} else {
+		result = "denied"
+	}
+	return review.Response, nil
+}
+
+// applyPatch apply json patch to object of admission attributes in place, name and namespace can not be changed by a webhook
+func applyPatch(a admission.Attributes, patch []byte) error {
+	obj := a.GetObject()
+	original, err := encodeObject(obj, a.GetKind().Kind)
+	if err != nil {
+		return err
+	}
+	p, err := jsonpatch.DecodePatch(patch)
+	if err != nil {
+		return err
+	}
+	patched, err := p.Apply(original)
+	if err != nil {
+		return err
+	}
+	out := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(runtime.Object)
+	if err := json.Unmarshal(patched, out); err != nil {
+		return err
+	}
+	before, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	after, err := meta.Accessor(out)
+	if err != nil {
+		return err
+	}
+	if before.GetName() != after.GetName() || before.GetNamespace() != after.GetNamespace() {
+		return fmt.Errorf("name and namespace can not be changed")
+	}
+	typeMeta := obj.GetObjectKind().GroupVersionKind()
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(out).Elem())
+	obj.GetObjectKind().SetGroupVersionKind(typeMeta)
+	return nil
+}
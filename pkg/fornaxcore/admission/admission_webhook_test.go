@@ -0,0 +1,153 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+// testWebhook add a label and a sidecar container to applications, and deny applications without containers
+func testWebhook(t *testing.T, status int) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		review := &admissionv1.AdmissionReview{}
+		if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+			t.Fatal(err)
+		}
+		app := &fornaxv1.Application{}
+		if err := json.Unmarshal(review.Request.Object.Raw, app); err != nil {
+			t.Fatal(err)
+		}
+		response := &admissionv1.AdmissionResponse{UID: review.Request.UID, Allowed: true}
+		switch r.URL.Path {
+		case "/mutate":
+			patchType := admissionv1.PatchTypeJSONPatch
+			response.PatchType = &patchType
+			response.Patch = []byte(`[{"op": "add", "path": "/metadata/labels", "value": {"team": "` + review.Request.UserInfo.Username + `"}},` +
+				`{"op": "add", "path": "/spec/containers/-", "value": {"name": "sidecar", "image": "sidecar:latest"}}]`)
+		case "/validate":
+			if len(app.Spec.Containers) == 0 {
+				response.Allowed = false
+				response.Result = &metav1.Status{Message: "application must have containers"}
+			}
+		}
+		json.NewEncoder(w).Encode(&admissionv1.AdmissionReview{TypeMeta: review.TypeMeta, Response: response})
+	}))
+}
+
+func newTestAdmission(t *testing.T, server *httptest.Server, webhooks ...AdmissionWebhook) *WebhookAdmission {
+	caBundle := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	for i := range webhooks {
+		webhooks[i].URL = server.URL + webhooks[i].URL
+		webhooks[i].CABundle = caBundle
+	}
+	wa, err := NewWebhookAdmission(&AdmissionWebhookConfiguration{Webhooks: webhooks})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return wa
+}
+
+func applicationAttributes(app *fornaxv1.Application, operation admission.Operation) admission.Attributes {
+	return admission.NewAttributesRecord(app, nil, fornaxv1.SchemeGroupVersion.WithKind("Application"), app.Namespace, app.Name,
+		fornaxv1.ApplicationGrv, "", operation, nil, false, &user.DefaultInfo{Name: "alice"})
+}
+
+func TestMutatingAndValidatingWebhooks(t *testing.T) {
+	server := testWebhook(t, http.StatusOK)
+	defer server.Close()
+	wa := newTestAdmission(t, server,
+		AdmissionWebhook{Name: "defaults", Type: WebhookTypeMutating, URL: "/mutate", Operations: []admission.Operation{admission.Create}},
+		AdmissionWebhook{Name: "policy", Type: WebhookTypeValidating, URL: "/validate"},
+	)
+
+	app := &fornaxv1.Application{ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "app"}}
+	app.Spec.Containers = []corev1.Container{{Name: "main", Image: "main:latest"}}
+	a := applicationAttributes(app, admission.Create)
+	if err := wa.Admit(context.Background(), a, nil); err != nil {
+		t.Fatal(err)
+	}
+	if app.Labels["team"] != "alice" || len(app.Spec.Containers) != 2 || app.Spec.Containers[1].Name != "sidecar" || app.Name != "app" {
+		t.Errorf("expected application mutated by webhook, got %v", app)
+	}
+	if err := wa.Validate(context.Background(), a, nil); err != nil {
+		t.Errorf("expected application allowed, got %v", err)
+	}
+
+	// mutating webhook only handle create
+	update := &fornaxv1.Application{ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "app"}}
+	a = applicationAttributes(update, admission.Update)
+	if err := wa.Admit(context.Background(), a, nil); err != nil || len(update.Labels) != 0 {
+		t.Errorf("expected update not mutated, got %v, %v", update, err)
+	}
+	err := wa.Validate(context.Background(), a, nil)
+	if !apierrors.IsForbidden(err) {
+		t.Errorf("expected application without containers forbidden, got %v", err)
+	}
+
+	// status and other resources are not sent to webhooks
+	status := admission.NewAttributesRecord(update, nil, fornaxv1.SchemeGroupVersion.WithKind("Application"), "a", "app",
+		fornaxv1.ApplicationGrv, "status", admission.Update, nil, false, &user.DefaultInfo{Name: "alice"})
+	if err := wa.Validate(context.Background(), status, nil); err != nil {
+		t.Errorf("expected status update not validated by webhook, got %v", err)
+	}
+}
+
+func TestWebhookFailurePolicy(t *testing.T) {
+	server := testWebhook(t, http.StatusInternalServerError)
+	defer server.Close()
+	app := &fornaxv1.Application{ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "app"}}
+
+	wa := newTestAdmission(t, server, AdmissionWebhook{Name: "policy", Type: WebhookTypeValidating, URL: "/validate"})
+	if err := wa.Validate(context.Background(), applicationAttributes(app, admission.Create), nil); !apierrors.IsInternalError(err) {
+		t.Errorf("expected internal error of failed webhook, got %v", err)
+	}
+	wa = newTestAdmission(t, server, AdmissionWebhook{Name: "policy", Type: WebhookTypeValidating, URL: "/validate", FailurePolicy: FailurePolicyIgnore})
+	if err := wa.Validate(context.Background(), applicationAttributes(app, admission.Create), nil); err != nil {
+		t.Errorf("expected failed webhook ignored, got %v", err)
+	}
+}
+
+func TestNewWebhookAdmissionValidation(t *testing.T) {
+	invalid := []AdmissionWebhook{
+		{Name: "http", Type: WebhookTypeValidating, URL: "http://example.com/validate"},
+		{Name: "type", Type: "Audit", URL: "https://example.com/validate"},
+		{Name: "resource", Type: WebhookTypeValidating, URL: "https://example.com/validate", Resources: []string{"nodeoperations"}},
+		{Name: "operation", Type: WebhookTypeValidating, URL: "https://example.com/validate", Operations: []admission.Operation{admission.Delete}},
+	}
+	for _, v := range invalid {
+		if _, err := NewWebhookAdmission(&AdmissionWebhookConfiguration{Webhooks: []AdmissionWebhook{v}}); err == nil {
+			t.Errorf("%s: expected invalid webhook error", v.Name)
+		}
+	}
+}
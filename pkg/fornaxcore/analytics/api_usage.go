@@ -0,0 +1,352 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package analytics count api calls of each tenant identity in time buckets, so operators can find integrations
+// which call fornaxcore too often, create too many sessions or keep failing
+package analytics
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/endpoints/responsewriter"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const (
+	APIUsagePath = "/fornaxcore/analytics/apiusage"
+
+	DefaultBucketDuration = 1 * time.Minute
+	DefaultRetention      = 2 * time.Hour
+	DefaultQueryWindow    = 1 * time.Hour
+	// identities beyond it in a bucket are counted as OtherIdentity, it bound memory when many users call api server
+	DefaultMaxIdentitiesPerBucket = 1000
+
+	OtherIdentity = "<other>"
+	Anonymous     = "system:anonymous"
+)
+
+var (
+	apiUsageOverflowRequests = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_api_usage",
+			Name:           "overflow_requests_total",
+			Help:           "Number of api requests counted as other identity because a bucket has too many identities",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(apiUsageOverflowRequests)
+}
+
+// Identity is a tenant identity, user of request in namespace of request, namespace is empty for cluster requests
+type Identity struct {
+	User      string `json:"user"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Usage is api calls of a identity in a time range
+type Usage struct {
+	Requests int64 `json:"requests"`
+	// 4xx responses except 429
+	ClientErrors int64 `json:"clientErrors"`
+	// 5xx responses
+	ServerErrors int64 `json:"serverErrors"`
+	// 429 responses, request was rejected by max inflight or priority and fairness limit
+	Throttled      int64            `json:"throttled"`
+	SessionCreates int64            `json:"sessionCreates"`
+	Verbs          map[string]int64 `json:"verbs,omitempty"`
+}
+
+func (u *Usage) add(o *Usage) {
+	u.Requests += o.Requests
+	u.ClientErrors += o.ClientErrors
+	u.ServerErrors += o.ServerErrors
+	u.Throttled += o.Throttled
+	u.SessionCreates += o.SessionCreates
+	for k, v := range o.Verbs {
+		if u.Verbs == nil {
+			u.Verbs = map[string]int64{}
+		}
+		u.Verbs[k] += v
+	}
+}
+
+type bucket struct {
+	start time.Time
+	usage map[Identity]*Usage
+}
+
+// APIUsage keep api usage of identities in a ring of fixed duration buckets
+type APIUsage struct {
+	mu             sync.Mutex
+	bucketDuration time.Duration
+	maxIdentities  int
+	buckets        []*bucket
+	now            func() time.Time
+}
+
+func NewAPIUsage() *APIUsage {
+	return &APIUsage{
+		bucketDuration: DefaultBucketDuration,
+		maxIdentities:  DefaultMaxIdentitiesPerBucket,
+		buckets:        make([]*bucket, int(DefaultRetention/DefaultBucketDuration)),
+		now:            time.Now,
+	}
+}
+
+// Record count a finished request of identity
+func (a *APIUsage) Record(identity Identity, verb, resource string, code int) {
+	now := a.now()
+	start := now.Truncate(a.bucketDuration)
+	index := int(start.UnixNano()/int64(a.bucketDuration)) % len(a.buckets)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	b := a.buckets[index]
+	if b == nil || !b.start.Equal(start) {
+		b = &bucket{start: start, usage: map[Identity]*Usage{}}
+		a.buckets[index] = b
+	}
+	usage, found := b.usage[identity]
+	if !found {
+		if len(b.usage) >= a.maxIdentities {
+			apiUsageOverflowRequests.Inc()
+			identity = Identity{User: OtherIdentity}
+			usage = b.usage[identity]
+		}
+		if usage == nil {
+			usage = &Usage{Verbs: map[string]int64{}}
+			b.usage[identity] = usage
+		}
+	}
+	usage.Requests += 1
+	usage.Verbs[verb] += 1
+	switch {
+	case code == http.StatusTooManyRequests:
+		usage.Throttled += 1
+	case code >= 500:
+		usage.ServerErrors += 1
+	case code >= 400:
+		usage.ClientErrors += 1
+	}
+	if verb == "create" && resource == fornaxv1.ApplicationSessionGrv.Resource {
+		usage.SessionCreates += 1
+	}
+}
+
+// BucketUsage is usage of a identity in a bucket
+type BucketUsage struct {
+	Start time.Time `json:"start"`
+	Usage
+}
+
+// IdentityUsage is usage of a identity in query window, buckets without request are omitted
+type IdentityUsage struct {
+	Identity
+	Total Usage `json:"total"`
+	// session creates per minute in window
+	SessionCreateRate float64 `json:"sessionCreateRate"`
+	// client and server errors per request
+	ErrorRate float64       `json:"errorRate"`
+	Buckets   []BucketUsage `json:"buckets,omitempty"`
+}
+
+type Query struct {
+	Window time.Duration
+	// bucket of result, it's rounded up to multiple of bucket duration of APIUsage
+	Bucket    time.Duration
+	User      string
+	Namespace string
+	// only return top identities by requests if it's positive
+	Top int
+}
+
+type Report struct {
+	Start         time.Time       `json:"start"`
+	End           time.Time       `json:"end"`
+	BucketSeconds int64           `json:"bucketSeconds"`
+	Identities    []IdentityUsage `json:"identities"`
+}
+
+// Query return usage of identities in recent window, identities are sorted by requests
+func (a *APIUsage) Query(q Query) *Report {
+	now := a.now()
+	window := q.Window
+	if window <= 0 {
+		window = DefaultQueryWindow
+	}
+	if retention := a.bucketDuration * time.Duration(len(a.buckets)); window > retention {
+		window = retention
+	}
+	step := a.bucketDuration
+	if q.Bucket > step {
+		step = (q.Bucket + a.bucketDuration - 1) / a.bucketDuration * a.bucketDuration
+	}
+	end := now.Truncate(a.bucketDuration).Add(a.bucketDuration)
+	start := end.Add(-window).Truncate(a.bucketDuration)
+
+	identities := map[Identity]*IdentityUsage{}
+	a.mu.Lock()
+	for _, b := range a.buckets {
+		if b == nil || b.start.Before(start) || !b.start.Before(end) {
+			continue
+		}
+		outStart := start.Add(b.start.Sub(start) / step * step)
+		for identity, usage := range b.usage {
+			if (len(q.User) > 0 && identity.User != q.User) || (len(q.Namespace) > 0 && identity.Namespace != q.Namespace) {
+				continue
+			}
+			iu, found := identities[identity]
+			if !found {
+				iu = &IdentityUsage{Identity: identity}
+				identities[identity] = iu
+			}
+			iu.Total.add(usage)
+			var bu *BucketUsage
+			for i := range iu.Buckets {
+				if iu.Buckets[i].Start.Equal(outStart) {
+					bu = &iu.Buckets[i]
+				}
+			}
+			if bu == nil {
+				iu.Buckets = append(iu.Buckets, BucketUsage{Start: outStart})
+				bu = &iu.Buckets[len(iu.Buckets)-1]
+			}
+			bu.add(usage)
+		}
+	}
+	a.mu.Unlock()
+
+	report := &Report{Start: start, End: end, BucketSeconds: int64(step.Seconds()), Identities: []IdentityUsage{}}
+	for _, iu := range identities {
+		sort.Slice(iu.Buckets, func(i, j int) bool { return iu.Buckets[i].Start.Before(iu.Buckets[j].Start) })
+		iu.SessionCreateRate = float64(iu.Total.SessionCreates) / end.Sub(start).Minutes()
+		if iu.Total.Requests > 0 {
+			iu.ErrorRate = float64(iu.Total.ClientErrors+iu.Total.ServerErrors) / float64(iu.Total.Requests)
+		}
+		report.Identities = append(report.Identities, *iu)
+	}
+	sort.Slice(report.Identities, func(i, j int) bool {
+		if report.Identities[i].Total.Requests != report.Identities[j].Total.Requests {
+			return report.Identities[i].Total.Requests > report.Identities[j].Total.Requests
+		}
+		if report.Identities[i].User != report.Identities[j].User {
+			return report.Identities[i].User < report.Identities[j].User
+		}
+		return report.Identities[i].Namespace < report.Identities[j].Namespace
+	})
+	if q.Top > 0 && len(report.Identities) > q.Top {
+		report.Identities = report.Identities[:q.Top]
+	}
+	return report
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.code == 0 {
+		r.code = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(data []byte) (int, error) {
+	if r.code == 0 {
+		r.code = http.StatusOK
+	}
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// WithAPIUsage count requests after they are authenticated, it's put inside of default handler chain,
+// so user and request info are set in request context
+func WithAPIUsage(handler http.Handler, usage *APIUsage) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: w}
+		handler.ServeHTTP(responsewriter.WrapForHTTP1Or2(recorder), req)
+
+		identity := Identity{User: Anonymous}
+		if user, ok := request.UserFrom(req.Context()); ok && len(user.GetName()) > 0 {
+			identity.User = user.GetName()
+		}
+		verb, resource := req.Method, ""
+		if info, ok := request.RequestInfoFrom(req.Context()); ok {
+			identity.Namespace, verb, resource = info.Namespace, info.Verb, info.Resource
+		}
+		code := recorder.code
+		if code == 0 {
+			code = http.StatusOK
+		}
+		usage.Record(identity, verb, resource, code)
+	})
+}
+
+// APIUsageHandler return api usage report in json,
+// e.g. kubectl get --raw "/fornaxcore/analytics/apiusage?window=30m&bucket=5m&namespace=tenant1&top=10"
+type APIUsageHandler struct {
+	usage *APIUsage
+}
+
+func NewAPIUsageHandler(usage *APIUsage) *APIUsageHandler {
+	return &APIUsageHandler{usage: usage}
+}
+
+func (h *APIUsageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	q := Query{User: values.Get("user"), Namespace: values.Get("namespace")}
+	var err error
+	if v := values.Get("window"); len(v) > 0 {
+		if q.Window, err = time.ParseDuration(v); err != nil {
+			http.Error(w, "invalid window, "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if v := values.Get("bucket"); len(v) > 0 {
+		if q.Bucket, err = time.ParseDuration(v); err != nil {
+			http.Error(w, "invalid bucket, "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if v := values.Get("top"); len(v) > 0 {
+		if q.Top, err = strconv.Atoi(v); err != nil {
+			http.Error(w, "invalid top, "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	data, err := json.MarshalIndent(h.usage.Query(q), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
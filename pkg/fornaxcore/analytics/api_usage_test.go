@@ -0,0 +1,127 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analytics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestAPIUsageQuery(t *testing.T) {
+	usage := NewAPIUsage()
+	now := time.Date(2022, 10, 1, 10, 0, 30, 0, time.UTC)
+	usage.now = func() time.Time { return now }
+
+	alice := Identity{User: "alice", Namespace: "tenant1"}
+	bob := Identity{User: "bob", Namespace: "tenant2"}
+	usage.Record(alice, "create", "applicationsessions", http.StatusCreated)
+	usage.Record(alice, "create", "applicationsessions", http.StatusTooManyRequests)
+	usage.Record(bob, "get", "applications", http.StatusNotFound)
+	now = now.Add(3 * time.Minute)
+	usage.Record(alice, "create", "applicationsessions", http.StatusInternalServerError)
+	usage.Record(alice, "list", "applicationsessions", http.StatusOK)
+	usage.Record(bob, "get", "applications", http.StatusOK)
+
+	report := usage.Query(Query{Window: 10 * time.Minute, Bucket: 2 * time.Minute})
+	if len(report.Identities) != 2 || report.BucketSeconds != 120 {
+		t.Fatalf("expected usage of 2 identities in 2 minutes bucket, got %+v", report)
+	}
+	a := report.Identities[0]
+	if a.Identity != alice || a.Total.Requests != 4 || a.Total.SessionCreates != 3 || a.Total.Throttled != 1 || a.Total.ServerErrors != 1 || a.Total.Verbs["create"] != 3 {
+		t.Errorf("unexpected usage of alice %+v", a)
+	}
+	if a.ErrorRate != 0.25 || a.SessionCreateRate != 0.3 {
+		t.Errorf("unexpected rates of alice, error %v, session create %v", a.ErrorRate, a.SessionCreateRate)
+	}
+	if len(a.Buckets) != 2 || a.Buckets[0].Requests != 2 || a.Buckets[1].Requests != 2 || a.Buckets[1].Start.Sub(a.Buckets[0].Start) != 2*time.Minute {
+		t.Errorf("unexpected buckets of alice %+v", a.Buckets)
+	}
+	if b := report.Identities[1]; b.Identity != bob || b.Total.Requests != 2 || b.Total.ClientErrors != 1 {
+		t.Errorf("unexpected usage of bob %+v", b)
+	}
+
+	report = usage.Query(Query{Window: 10 * time.Minute, Namespace: "tenant2"})
+	if len(report.Identities) != 1 || report.Identities[0].Identity != bob {
+		t.Errorf("expected usage of tenant2 only, got %+v", report.Identities)
+	}
+	report = usage.Query(Query{Window: 10 * time.Minute, Top: 1})
+	if len(report.Identities) != 1 || report.Identities[0].Identity != alice {
+		t.Errorf("expected top identity alice, got %+v", report.Identities)
+	}
+
+	// usage older than retention is not reported
+	now = now.Add(DefaultRetention)
+	if report = usage.Query(Query{Window: 2 * DefaultRetention}); len(report.Identities) != 0 {
+		t.Errorf("expected no usage in retention, got %+v", report.Identities)
+	}
+}
+
+func TestAPIUsageOverflowIdentities(t *testing.T) {
+	usage := NewAPIUsage()
+	usage.maxIdentities = 2
+	for _, u := range []string{"a", "b", "c", "d", "a"} {
+		usage.Record(Identity{User: u}, "get", "applications", http.StatusOK)
+	}
+	report := usage.Query(Query{})
+	total := map[string]int64{}
+	for _, v := range report.Identities {
+		total[v.User] = v.Total.Requests
+	}
+	if total["a"] != 2 || total["b"] != 1 || total[OtherIdentity] != 2 || len(total) != 3 {
+		t.Errorf("expected identities over limit counted as other, got %v", total)
+	}
+}
+
+func TestWithAPIUsage(t *testing.T) {
+	usage := NewAPIUsage()
+	handler := WithAPIUsage(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte("ok"))
+	}), usage)
+	req := httptest.NewRequest(http.MethodPost, "/apis/core.fornax-serverless.centaurusinfra.io/v1/namespaces/tenant1/applicationsessions", nil)
+	ctx := request.WithUser(req.Context(), &user.DefaultInfo{Name: "alice"})
+	ctx = request.WithRequestInfo(ctx, &request.RequestInfo{IsResourceRequest: true, Verb: "create", Namespace: "tenant1", Resource: "applicationsessions"})
+	handler.ServeHTTP(httptest.NewRecorder(), req.WithContext(ctx))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	report := usage.Query(Query{})
+	if len(report.Identities) != 2 {
+		t.Fatalf("expected 2 identities, got %+v", report.Identities)
+	}
+	for _, v := range report.Identities {
+		switch v.User {
+		case "alice":
+			if v.Namespace != "tenant1" || v.Total.ClientErrors != 1 || v.Total.SessionCreates != 1 {
+				t.Errorf("unexpected usage of alice %+v", v)
+			}
+		case Anonymous:
+			if v.Total.Verbs[http.MethodGet] != 1 || v.Total.ClientErrors != 0 {
+				t.Errorf("unexpected usage of anonymous %+v", v)
+			}
+		default:
+			t.Errorf("unexpected identity %v", v.Identity)
+		}
+	}
+}
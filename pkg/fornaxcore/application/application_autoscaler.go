@@ -0,0 +1,187 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	storefactory "centaurusinfra.io/fornax-serverless/pkg/store/factory"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+var (
+	autoscalerPendingSessions = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      "fornax_application_autoscaler",
+			Name:           "pending_sessions",
+			Help:           "Number of pending sessions waiting for a pod of application",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"application"},
+	)
+	autoscalerDesiredInstances = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      "fornax_application_autoscaler",
+			Name:           "desired_instances",
+			Help:           "Number of pods application autoscaler want, including occupied pods",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"application"},
+	)
+	autoscalerScaleToZero = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_application_autoscaler",
+			Name:           "scale_to_zero_total",
+			Help:           "Number of times application is scaled to zero after idle timeout",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"application"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(autoscalerPendingSessions, autoscalerDesiredInstances, autoscalerScaleToZero)
+}
+
+// sessionDemandPolicy return session demand policy of application, nil if application use other scaling policy type
+func sessionDemandPolicy(application *fornaxv1.Application) *fornaxv1.SessionDemandScalingPolicy {
+	if application.Spec.ScalingPolicy.ScalingPolicyType != fornaxv1.ScalingPolicyTypeSessionDemand {
+		return nil
+	}
+	if application.Spec.ScalingPolicy.SessionDemand == nil {
+		return &fornaxv1.SessionDemandScalingPolicy{}
+	}
+	return application.Spec.ScalingPolicy.SessionDemand
+}
+
+// applicationMaxInstances return maximum pods of application, MaxInstances of session demand policy override MaximumInstance
+func applicationMaxInstances(application *fornaxv1.Application) int {
+	if policy := sessionDemandPolicy(application); policy != nil && policy.MaxInstances > 0 {
+		return int(policy.MaxInstances)
+	}
+	return int(application.Spec.ScalingPolicy.MaximumInstance)
+}
+
+// scaledToZero tell if application has been without any session longer than idle timeout of session demand policy
+func (am *ApplicationManager) scaledToZero(pool *ApplicationPool, policy *fornaxv1.SessionDemandScalingPolicy) bool {
+	if policy == nil || policy.IdleTimeoutSeconds <= 0 || pool.sessionLength() > 0 {
+		return false
+	}
+	lastActiveTime := pool.getLastActiveTime()
+	if lastActiveTime.Before(pool.createTime) {
+		lastActiveTime = pool.createTime
+	}
+	return time.Since(lastActiveTime) >= time.Duration(policy.IdleTimeoutSeconds)*time.Second
+}
+
+// markScaledToZero record application is scaled to zero, return false if it was already scaled to zero in current idle period
+func (pool *ApplicationPool) markScaledToZero() bool {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	if pool.scaledToZeroTime.After(pool.lastActiveTime) {
+		return false
+	}
+	pool.scaledToZeroTime = time.Now()
+	return true
+}
+
+// calculateSessionDemandIdlePods return desired pending and idle pods of session demand policy,
+// a pod for each pending session plus min idle pods, total pods must between minimum and maximum instances,
+// no idle pod is kept when application is scaled to zero, a new session scale it up again
+func (am *ApplicationManager) calculateSessionDemandIdlePods(pool *ApplicationPool, application *fornaxv1.Application, policy *fornaxv1.SessionDemandScalingPolicy, occupiedPodNum, idlePodNum, pendingSessionNum int) int {
+	desiredCount := pendingSessionNum
+	if am.scaledToZero(pool, policy) {
+		desiredCount = 0
+		if idlePodNum > 0 {
+			klog.InfoS("Scaling idle application to zero", "application", pool.appName, "idle-pods", idlePodNum, "last-active-time", pool.getLastActiveTime())
+			if pool.markScaledToZero() {
+				autoscalerScaleToZero.WithLabelValues(pool.appName).Inc()
+			}
+		}
+	} else {
+		desiredCount += int(policy.MinIdleInstances)
+		if minimum := int(application.Spec.ScalingPolicy.MinimumInstance); occupiedPodNum+desiredCount < minimum {
+			desiredCount = minimum - occupiedPodNum
+		}
+	}
+
+	if maximum := applicationMaxInstances(application); occupiedPodNum+desiredCount > maximum {
+		desiredCount = maximum - occupiedPodNum
+		if desiredCount < 0 {
+			desiredCount = 0
+		}
+	}
+	autoscalerPendingSessions.WithLabelValues(pool.appName).Set(float64(pendingSessionNum))
+	autoscalerDesiredInstances.WithLabelValues(pool.appName).Set(float64(occupiedPodNum + desiredCount))
+	return desiredCount
+}
+
+// applicationScalingBurst return maximum pods created or deleted in one sync, session demand policy can set scale up and down burst separately,
+// and scale up by percent of active pods
+func (am *ApplicationManager) applicationScalingBurst(pool *ApplicationPool, application *fornaxv1.Application, scaleUp bool) int {
+	burst := util.ApplicationScalingBurst(application)
+	policy := sessionDemandPolicy(application)
+	if policy == nil {
+		return burst
+	}
+	if !scaleUp {
+		if policy.ScaleDownBurst > 0 {
+			burst = int(policy.ScaleDownBurst)
+		}
+		return burst
+	}
+	if policy.ScaleUpBurst > 0 {
+		burst = int(policy.ScaleUpBurst)
+	}
+	if policy.ScaleUpPercent > 0 {
+		occupiedPods, pendingPods, idlePods := pool.activePodNums()
+		if byPercent := (occupiedPods + pendingPods + idlePods) * int(policy.ScaleUpPercent) / 100; byPercent > burst {
+			burst = byPercent
+		}
+	}
+	return burst
+}
+
+// scaleToZeroDue tell if a application has been idle longer than idle timeout and still has idle pods to delete
+func (am *ApplicationManager) scaleToZeroDue(applicationKey string, pool *ApplicationPool) bool {
+	if pool.sessionLength() > 0 {
+		return false
+	}
+	if _, pendingPods, idlePods := pool.activePodNums(); pendingPods+idlePods == 0 {
+		return false
+	}
+	application, err := storefactory.GetApplicationCache(am.applicationStore, applicationKey)
+	if err != nil || application == nil || application.DeletionTimestamp != nil {
+		return false
+	}
+	return am.scaledToZero(pool, sessionDemandPolicy(application))
+}
+
+// forgetAutoscalerMetrics delete autoscaler, warm pool and rollout metrics of a deleted application
+func forgetAutoscalerMetrics(applicationKey string) {
+	autoscalerPendingSessions.DeleteLabelValues(applicationKey)
+	autoscalerDesiredInstances.DeleteLabelValues(applicationKey)
+	autoscalerScaleToZero.DeleteLabelValues(applicationKey)
+	warmPoolSize.DeleteLabelValues(applicationKey)
+	for _, action := range []fornaxv1.RolloutFailureAction{fornaxv1.RolloutFailureActionPause, fornaxv1.RolloutFailureActionRollback} {
+		rolloutFailures.DeleteLabelValues(applicationKey, string(action))
+	}
+}
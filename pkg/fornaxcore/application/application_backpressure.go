@@ -0,0 +1,215 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	storefactory "centaurusinfra.io/fornax-serverless/pkg/store/factory"
+)
+
+const (
+	ClusterStatusPath = "/debug/fornaxcore/cluster/status"
+
+	// used before any session became available
+	DefaultSessionAllocationLatency = 1 * time.Second
+
+	// weight of latest observed latency in moving average
+	sessionLatencySmoothingFactor = 0.2
+)
+
+// sessionLatencyTracker keep exponential moving average of duration from session creation to available
+type sessionLatencyTracker struct {
+	mu      sync.RWMutex
+	average time.Duration
+}
+
+func (t *sessionLatencyTracker) observe(latency time.Duration) {
+	if latency < 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.average == 0 {
+		t.average = latency
+		return
+	}
+	t.average = time.Duration(float64(t.average)*(1-sessionLatencySmoothingFactor) + float64(latency)*sessionLatencySmoothingFactor)
+}
+
+func (t *sessionLatencyTracker) get() time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.average == 0 {
+		return DefaultSessionAllocationLatency
+	}
+	return t.average
+}
+
+// observeSessionAllocation record allocation latency when a session become available first time
+func (am *ApplicationManager) observeSessionAllocation(oldSession, newSession *fornaxv1.ApplicationSession) {
+	if oldSession.Status.SessionStatus == fornaxv1.SessionStatusAvailable || newSession.Status.SessionStatus != fornaxv1.SessionStatusAvailable {
+		return
+	}
+	if newSession.Status.AvailableTime == nil {
+		return
+	}
+	am.sessionLatency.observe(newSession.Status.AvailableTime.Sub(newSession.CreationTimestamp.Time))
+}
+
+// SessionBackpressure calculate backpressure hint of a new session, a application is saturated when it has more waiting sessions
+// than idle and pending pods, and it can not get more pods because of maximum instance or insufficient cluster resource
+func (am *ApplicationManager) SessionBackpressure(session *fornaxv1.ApplicationSession) *fornaxv1.SessionBackpressure {
+	return am.applicationBackpressure(getSessionApplicationKey(session), 1)
+}
+
+func (am *ApplicationManager) applicationBackpressure(applicationKey string, newSessions int) *fornaxv1.SessionBackpressure {
+	latency := am.sessionLatency.get()
+	backpressure := &fornaxv1.SessionBackpressure{
+		QueueDepth:           int32(newSessions),
+		EstimatedWaitSeconds: int32(math.Ceil(latency.Seconds())),
+	}
+	pool := am.getApplicationPool(applicationKey)
+	if pool == nil {
+		return backpressure
+	}
+	summary := pool.summarySession()
+	occupiedPods, pendingPods, idlePods := pool.activePodNums()
+	waitingSessions := summary.pendingCount + summary.startingCount + newSessions
+	backpressure.QueueDepth = int32(waitingSessions)
+
+	capacity := idlePods + pendingPods
+	if waitingSessions <= capacity {
+		return backpressure
+	}
+
+	canScale := true
+	if application, err := storefactory.GetApplicationCache(am.applicationStore, applicationKey); err == nil && application != nil {
+		canScale = occupiedPods+pendingPods+idlePods < applicationMaxInstances(application)
+	}
+	if am.podScheduler != nil {
+		// pods in backoff queue can not find a node with sufficient resources
+		if _, backoff := am.podScheduler.QueueLength(); backoff > 0 {
+			canScale = false
+		}
+	}
+	if canScale {
+		return backpressure
+	}
+
+	// sessions beyond capacity wait for pods released by other sessions, assume pods are released at allocation latency pace
+	rounds := 1 + (waitingSessions-capacity-1)/int(math.Max(float64(occupiedPods+capacity), 1))
+	wait := int32(math.Ceil(latency.Seconds() * float64(1+rounds)))
+	backpressure.Saturated = true
+	backpressure.EstimatedWaitSeconds = wait
+	backpressure.RetryAfterSeconds = wait
+	return backpressure
+}
+
+type ApplicationLoadStatus struct {
+	Application      string                        `json:"application"`
+	PendingSessions  int                           `json:"pendingSessions"`
+	StartingSessions int                           `json:"startingSessions"`
+	RunningSessions  int                           `json:"runningSessions"`
+	IdlePods         int                           `json:"idlePods"`
+	PendingPods      int                           `json:"pendingPods"`
+	OccupiedPods     int                           `json:"occupiedPods"`
+	Backpressure     *fornaxv1.SessionBackpressure `json:"backpressure"`
+	// owning team and alert channel, so alerts on application load can be routed to team
+	Team         string `json:"team,omitempty"`
+	AlertChannel string `json:"alertChannel,omitempty"`
+}
+
+type ClusterStatus struct {
+	Saturated                  bool                     `json:"saturated"`
+	ScheduleActiveQueueLength  int                      `json:"scheduleActiveQueueLength"`
+	ScheduleBackoffQueueLength int                      `json:"scheduleBackoffQueueLength"`
+	SessionAllocationLatency   string                   `json:"sessionAllocationLatency"`
+	Applications               []*ApplicationLoadStatus `json:"applications,omitempty"`
+}
+
+// ClusterStatus return load of scheduler and every application, cluster is saturated if any application is saturated
+func (am *ApplicationManager) ClusterStatus() *ClusterStatus {
+	status := &ClusterStatus{
+		SessionAllocationLatency: am.sessionLatency.get().String(),
+		Applications:             []*ApplicationLoadStatus{},
+	}
+	if am.podScheduler != nil {
+		status.ScheduleActiveQueueLength, status.ScheduleBackoffQueueLength = am.podScheduler.QueueLength()
+	}
+	for applicationKey, pool := range am.applicationList() {
+		summary := pool.summarySession()
+		occupiedPods, pendingPods, idlePods := pool.activePodNums()
+		load := &ApplicationLoadStatus{
+			Application:      applicationKey,
+			PendingSessions:  summary.pendingCount,
+			StartingSessions: summary.startingCount,
+			RunningSessions:  summary.runningCount,
+			IdlePods:         idlePods,
+			PendingPods:      pendingPods,
+			OccupiedPods:     occupiedPods,
+			Backpressure:     am.applicationBackpressure(applicationKey, 0),
+		}
+		if application, err := storefactory.GetApplicationCache(am.applicationStore, applicationKey); err == nil && application != nil && application.Spec.Ownership != nil {
+			load.Team = application.Spec.Ownership.Team
+			load.AlertChannel = application.Spec.Ownership.AlertChannel
+		}
+		status.Saturated = status.Saturated || load.Backpressure.Saturated
+		status.Applications = append(status.Applications, load)
+	}
+	sort.Slice(status.Applications, func(i, j int) bool {
+		return status.Applications[i].Application < status.Applications[j].Application
+	})
+	return status
+}
+
+// ClusterStatusHandler serve cluster load, e.g. kubectl get --raw "/debug/fornaxcore/cluster/status"
+type ClusterStatusHandler struct {
+	am *ApplicationManager
+}
+
+func NewClusterStatusHandler(am *ApplicationManager) *ClusterStatusHandler {
+	return &ClusterStatusHandler{am: am}
+}
+
+func (h *ClusterStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	status := h.am.ClusterStatus()
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if status.Saturated {
+		// largest suggested retry of saturated applications
+		retryAfter := int32(0)
+		for _, v := range status.Applications {
+			if v.Backpressure.RetryAfterSeconds > retryAfter {
+				retryAfter = v.Backpressure.RetryAfterSeconds
+			}
+		}
+		w.Header().Set("Retry-After", fmt.Sprint(retryAfter))
+	}
+	w.Write(data)
+}
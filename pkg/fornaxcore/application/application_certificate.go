@@ -0,0 +1,404 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/config"
+	"centaurusinfra.io/fornax-serverless/pkg/pki"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultCertificateValidity    = 720 * time.Hour
+	DefaultCertificateRenewBefore = 240 * time.Hour
+
+	// keys of certificate in application secret data
+	SecretKeyTLSCert = "tls.crt"
+	SecretKeyTLSKey  = "tls.key"
+	SecretKeyCACert  = "ca.crt"
+)
+
+const (
+	CertificateIssuerTypeCA     = "ca"
+	CertificateIssuerTypeSPIFFE = "spiffe"
+)
+
+var (
+	CertificateIssuerNotConfiguredError = errors.New("certificate issuer is not configured")
+	SPIFFETrustDomainNotConfiguredError = errors.New("spiffe certificate issuer require a trust domain")
+)
+
+// CertificateIssuerConfiguration select issuer of application certificates, a local CA issuer is used if file does not exist
+type CertificateIssuerConfiguration struct {
+	// ca or spiffe
+	Type string `json:"type,omitempty"`
+
+	// CA signing application certificates, a spiffe issuer use it as signing authority of trust domain, its certificate is trust bundle
+	CACertFile string `json:"caCertFile,omitempty"`
+	CAKeyFile  string `json:"caKeyFile,omitempty"`
+
+	// spiffe trust domain, application certificate is a x509 svid of spiffe://<trust domain>/ns/<namespace>/app/<name>
+	TrustDomain string `json:"trustDomain,omitempty"`
+}
+
+func DefaultCertificateIssuerConfiguration() *CertificateIssuerConfiguration {
+	return &CertificateIssuerConfiguration{
+		Type:       CertificateIssuerTypeCA,
+		CACertFile: config.DefaultFornaxCoreCACertFile,
+		CAKeyFile:  config.DefaultFornaxCoreCAKeyFile,
+	}
+}
+
+// LoadCertificateIssuerConfiguration read certificate issuer configuration from a json file, default configuration is returned if file does not exist,
+// fields missing in file keep their default value
+func LoadCertificateIssuerConfiguration(file string) (*CertificateIssuerConfiguration, error) {
+	cfg := DefaultCertificateIssuerConfiguration()
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, err
+	}
+	switch cfg.Type {
+	case CertificateIssuerTypeCA:
+	case CertificateIssuerTypeSPIFFE:
+		if len(cfg.TrustDomain) == 0 {
+			return nil, SPIFFETrustDomainNotConfiguredError
+		}
+	default:
+		return nil, fmt.Errorf("unknown certificate issuer type %s", cfg.Type)
+	}
+	return cfg, nil
+}
+
+// NewCertificateIssuer create issuer of configured type
+func NewCertificateIssuer(cfg *CertificateIssuerConfiguration) (CertificateIssuer, error) {
+	switch cfg.Type {
+	case CertificateIssuerTypeSPIFFE:
+		// a spiffe issuer sign svids of a trust domain, a generated CA is not trusted by any workload, so, CA files must exist
+		ca, err := pki.LoadCertificateAuthority(cfg.CACertFile, cfg.CAKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		return NewSPIFFEIssuer(ca, cfg.TrustDomain)
+	default:
+		return NewCAIssuer(cfg.CACertFile, cfg.CAKeyFile)
+	}
+}
+
+func loadCertificateIssuer(file string) (CertificateIssuer, error) {
+	cfg, err := LoadCertificateIssuerConfiguration(file)
+	if err != nil {
+		return nil, err
+	}
+	return NewCertificateIssuer(cfg)
+}
+
+// CertificateRequest is certificate wanted by a application
+type CertificateRequest struct {
+	Namespace   string
+	Application string
+	// first dns name is used as common name
+	DNSNames []string
+	Validity time.Duration
+}
+
+type IssuedCertificate struct {
+	CertPEM      []byte
+	KeyPEM       []byte
+	CACertPEM    []byte
+	SerialNumber string
+	DNSNames     []string
+	// spiffe id of application if certificate is a x509 svid
+	SPIFFEID string
+	NotAfter time.Time
+}
+
+// CertificateIssuer sign a certificate of a application, fornaxcore has a local CA issuer and a SPIFFE issuer,
+// other issuers like ACME can be plugged in by implementing this interface
+type CertificateIssuer interface {
+	Issue(request *CertificateRequest) (*IssuedCertificate, error)
+}
+
+var _ CertificateIssuer = &caIssuer{}
+var _ CertificateIssuer = &spiffeIssuer{}
+
+// caIssuer sign certificates using a CA key pair
+type caIssuer struct {
+	ca *pki.CertificateAuthority
+}
+
+// NewCAIssuer load CA certificate and key from pem files, if files do not exist, a self signed CA is generated in memory,
+// certificates signed by a generated CA are not trusted after fornaxcore restart, they are renewed on next application sync
+func NewCAIssuer(certFile, keyFile string) (*caIssuer, error) {
+	_, certErr := os.Stat(certFile)
+	_, keyErr := os.Stat(keyFile)
+	if os.IsNotExist(certErr) && os.IsNotExist(keyErr) {
+		klog.InfoS("CA certificate not found, generate a self signed CA", "cert", certFile, "key", keyFile)
+		ca, err := pki.NewSelfSignedCertificateAuthority(fmt.Sprintf("fornaxcore-ca.%s", config.DefaultDomainName))
+		if err != nil {
+			return nil, err
+		}
+		return &caIssuer{ca: ca}, nil
+	}
+	ca, err := pki.LoadCertificateAuthority(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &caIssuer{ca: ca}, nil
+}
+
+func (ci *caIssuer) Issue(request *CertificateRequest) (*IssuedCertificate, error) {
+	pair, err := ci.ca.Issue(pkix.Name{CommonName: request.DNSNames[0]}, request.DNSNames, nil, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}, request.Validity)
+	if err != nil {
+		return nil, err
+	}
+	return issuedCertificate(ci.ca, pair, request, "")
+}
+
+// spiffeIssuer sign x509 svids of applications in a trust domain, svid is also a client certificate,
+// so, application pods can use it for mtls with other workloads of trust domain
+type spiffeIssuer struct {
+	ca          *pki.CertificateAuthority
+	trustDomain string
+}
+
+func NewSPIFFEIssuer(ca *pki.CertificateAuthority, trustDomain string) (*spiffeIssuer, error) {
+	if len(trustDomain) == 0 {
+		return nil, SPIFFETrustDomainNotConfiguredError
+	}
+	return &spiffeIssuer{ca: ca, trustDomain: trustDomain}, nil
+}
+
+// SPIFFEID return spiffe id of a application in trust domain
+func (si *spiffeIssuer) SPIFFEID(namespace, application string) *url.URL {
+	return &url.URL{Scheme: "spiffe", Host: si.trustDomain, Path: fmt.Sprintf("/ns/%s/app/%s", namespace, application)}
+}
+
+func (si *spiffeIssuer) Issue(request *CertificateRequest) (*IssuedCertificate, error) {
+	id := si.SPIFFEID(request.Namespace, request.Application)
+	usages := []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	pair, err := si.ca.IssueWithURIs(pkix.Name{CommonName: request.DNSNames[0]}, request.DNSNames, []*url.URL{id}, usages, request.Validity)
+	if err != nil {
+		return nil, err
+	}
+	return issuedCertificate(si.ca, pair, request, id.String())
+}
+
+func issuedCertificate(ca *pki.CertificateAuthority, pair *pki.KeyPair, request *CertificateRequest, spiffeID string) (*IssuedCertificate, error) {
+	certs, err := pki.ParseCertificatesPEM(pair.CertPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &IssuedCertificate{
+		CertPEM:      pair.CertPEM,
+		KeyPEM:       pair.KeyPEM,
+		CACertPEM:    ca.CertPEM(),
+		SerialNumber: certs[0].SerialNumber.Text(16),
+		DNSNames:     request.DNSNames,
+		SPIFFEID:     spiffeID,
+		NotAfter:     certs[0].NotAfter,
+	}, nil
+}
+
+// ApplicationCertificateManager keep issued certificate of applications in memory, certificate is reissued after fornaxcore restart
+type ApplicationCertificateManager struct {
+	mu           sync.Mutex
+	issuer       CertificateIssuer
+	certificates map[string]*IssuedCertificate
+}
+
+func NewApplicationCertificateManager(issuer CertificateIssuer) *ApplicationCertificateManager {
+	return &ApplicationCertificateManager{
+		mu:           sync.Mutex{},
+		issuer:       issuer,
+		certificates: map[string]*IssuedCertificate{},
+	}
+}
+
+// applicationDNSNames return sorted dns names of application certificate, a wildcard of first dns name is appended,
+// so, certificate is also valid for session hosts routed by session gateway
+func applicationDNSNames(application *fornaxv1.Application) []string {
+	dnsNames := append([]string{}, application.Spec.TLS.DNSNames...)
+	if len(dnsNames) == 0 {
+		dnsNames = []string{fmt.Sprintf("%s.%s.%s", application.Name, application.Namespace, config.DefaultDomainName)}
+	}
+	sort.Strings(dnsNames)
+	if strings.HasPrefix(dnsNames[0], "*.") {
+		return dnsNames
+	}
+	wildcard := "*." + dnsNames[0]
+	for _, v := range dnsNames {
+		if v == wildcard {
+			return dnsNames
+		}
+	}
+	return append(dnsNames, wildcard)
+}
+
+// sessionDNSName return host of a session routed by session gateway sni port, it's a subdomain of application dns name,
+// empty if application does not have tls or only has wildcard dns names
+func sessionDNSName(application *fornaxv1.Application, session *fornaxv1.ApplicationSession) string {
+	if application == nil || application.Spec.TLS == nil {
+		return ""
+	}
+	if dnsName := applicationDNSNames(application)[0]; !strings.HasPrefix(dnsName, "*.") {
+		return fmt.Sprintf("%s.%s", session.Name, dnsName)
+	}
+	return ""
+}
+
+func certificateDurations(tls *fornaxv1.ApplicationTLS) (validity, renewBefore time.Duration) {
+	validity, renewBefore = DefaultCertificateValidity, DefaultCertificateRenewBefore
+	if tls.ValidityHours > 0 {
+		validity = time.Duration(tls.ValidityHours) * time.Hour
+	}
+	if tls.RenewBeforeHours > 0 {
+		renewBefore = time.Duration(tls.RenewBeforeHours) * time.Hour
+	}
+	if renewBefore >= validity {
+		renewBefore = validity / 3
+	}
+	return validity, renewBefore
+}
+
+func (cm *ApplicationCertificateManager) get(applicationKey string) *IssuedCertificate {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	return cm.certificates[applicationKey]
+}
+
+// certificate return current certificate of application, a new one is issued if application does not have one,
+// dns names changed or it's going to expire, previous is the replaced certificate if a new one is issued
+func (cm *ApplicationCertificateManager) certificate(application *fornaxv1.Application) (current, previous *IssuedCertificate, err error) {
+	if cm.issuer == nil {
+		return nil, nil, CertificateIssuerNotConfiguredError
+	}
+	applicationKey := util.Name(application)
+	dnsNames := applicationDNSNames(application)
+	validity, renewBefore := certificateDurations(application.Spec.TLS)
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	current = cm.certificates[applicationKey]
+	if current != nil && reflect.DeepEqual(current.DNSNames, dnsNames) && time.Now().Add(renewBefore).Before(current.NotAfter) {
+		return current, nil, nil
+	}
+	cert, err := cm.issuer.Issue(&CertificateRequest{
+		Namespace:   application.Namespace,
+		Application: application.Name,
+		DNSNames:    dnsNames,
+		Validity:    validity,
+	})
+	if err != nil {
+		return current, nil, err
+	}
+	klog.InfoS("Issued application certificate", "app", applicationKey, "serial", cert.SerialNumber, "dns names", dnsNames, "not after", cert.NotAfter)
+	cm.certificates[applicationKey] = cert
+	return cert, current, nil
+}
+
+// Forget remove certificate of a application, called when application is deleted
+func (cm *ApplicationCertificateManager) Forget(applicationKey string) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	delete(cm.certificates, applicationKey)
+}
+
+// secretWithCertificate merge certificate into application secret data, version is combination of secret version and certificate serial
+func secretWithCertificate(secret *fornaxv1.ApplicationSecret, cert *IssuedCertificate) *fornaxv1.ApplicationSecret {
+	if cert == nil {
+		return secret
+	}
+	merged := &fornaxv1.ApplicationSecret{
+		Version: fmt.Sprintf("tls-%s", cert.SerialNumber),
+		Data:    map[string][]byte{},
+	}
+	if secret != nil {
+		merged.Version = fmt.Sprintf("%s.tls-%s", secret.Version, cert.SerialNumber)
+		merged.RevokeGracePeriodSeconds = secret.RevokeGracePeriodSeconds
+		merged.EncryptedData = secret.EncryptedData
+		for k, v := range secret.Data {
+			merged.Data[k] = v
+		}
+	}
+	merged.Data[SecretKeyTLSCert] = cert.CertPEM
+	merged.Data[SecretKeyTLSKey] = cert.KeyPEM
+	merged.Data[SecretKeyCACert] = cert.CACertPEM
+	return merged
+}
+
+// applicationSecret return secret delivered to application pods, it include application certificate if application has tls,
+// when certificate is renewed, a secret rotation is started, so, sessions using old certificate keep working during grace period
+func (am *ApplicationManager) applicationSecret(application *fornaxv1.Application) (*fornaxv1.ApplicationSecret, *fornaxv1.ApplicationTLSStatus) {
+	applicationKey := util.Name(application)
+	if application.Spec.TLS == nil {
+		am.certificates.Forget(applicationKey)
+		return application.Spec.Secret, nil
+	}
+
+	cert, previous, err := am.certificates.certificate(application)
+	status := &fornaxv1.ApplicationTLSStatus{}
+	if err != nil {
+		klog.ErrorS(err, "Failed to issue application certificate", "app", applicationKey)
+		status.Message = err.Error()
+		// retry later
+		am.applicationQueue.EnqueueAfter(applicationKey, DefaultApplicationSyncErrorRecycleDuration)
+	}
+	if cert == nil {
+		return application.Spec.Secret, status
+	}
+	status.SerialNumber = cert.SerialNumber
+	status.DNSNames = cert.DNSNames
+	status.NotAfter = &metav1.Time{Time: cert.NotAfter}
+
+	secret := secretWithCertificate(application.Spec.Secret, cert)
+	if previous != nil {
+		previousSecret := secretWithCertificate(application.Spec.Secret, previous)
+		gracePeriod := secretRevokeGracePeriod(secret)
+		klog.InfoS("Application certificate renewed, rotate secret", "app", applicationKey, "version", secret.Version, "grace period", gracePeriod)
+		am.secretRotator.startRotation(applicationKey, previousSecret, gracePeriod)
+		am.applicationQueue.EnqueueAfter(applicationKey, gracePeriod)
+	}
+	// sync again when certificate need renew
+	_, renewBefore := certificateDurations(application.Spec.TLS)
+	if renewAt := time.Until(cert.NotAfter.Add(-renewBefore)); renewAt > 0 {
+		am.applicationQueue.EnqueueAfter(applicationKey, renewAt)
+	}
+	return secret, status
+}
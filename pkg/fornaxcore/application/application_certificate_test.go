@@ -0,0 +1,110 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"crypto/x509"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/config"
+	"centaurusinfra.io/fornax-serverless/pkg/pki"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func tlsApplication(dnsNames ...string) *fornaxv1.Application {
+	return &fornaxv1.Application{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "app1"},
+		Spec:       fornaxv1.ApplicationSpec{TLS: &fornaxv1.ApplicationTLS{DNSNames: dnsNames}},
+	}
+}
+
+func TestSPIFFEIssuer(t *testing.T) {
+	ca, err := pki.NewSelfSignedCertificateAuthority("test-ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer, err := NewSPIFFEIssuer(ca, "example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cm := NewApplicationCertificateManager(issuer)
+	cert, _, err := cm.certificate(tlsApplication("app1.example.org"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert.SPIFFEID != "spiffe://example.org/ns/ns1/app/app1" {
+		t.Errorf("unexpected spiffe id %s", cert.SPIFFEID)
+	}
+	certs, err := pki.ParseCertificatesPEM(cert.CertPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certs[0].URIs) != 1 || certs[0].URIs[0].String() != cert.SPIFFEID {
+		t.Errorf("certificate uris %v do not have spiffe id %s", certs[0].URIs, cert.SPIFFEID)
+	}
+	if !reflect.DeepEqual(certs[0].DNSNames, []string{"app1.example.org", "*.app1.example.org"}) {
+		t.Errorf("unexpected certificate dns names %v", certs[0].DNSNames)
+	}
+	bundle, err := pki.ParseCertificatesPEM(cert.CACertPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(bundle[0])
+	for _, usage := range []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth} {
+		if _, err := certs[0].Verify(x509.VerifyOptions{Roots: roots, DNSName: "session1.app1.example.org", KeyUsages: []x509.ExtKeyUsage{usage}}); err != nil {
+			t.Errorf("svid is not verified by trust bundle for usage %v, %v", usage, err)
+		}
+	}
+}
+
+func TestLoadCertificateIssuerConfiguration(t *testing.T) {
+	cfg, err := LoadCertificateIssuerConfiguration(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Type != CertificateIssuerTypeCA {
+		t.Errorf("expected ca issuer by default, got %s", cfg.Type)
+	}
+	if _, err := NewCertificateIssuer(&CertificateIssuerConfiguration{Type: CertificateIssuerTypeSPIFFE, TrustDomain: "example.org", CACertFile: "missing.crt", CAKeyFile: "missing.key"}); err == nil {
+		t.Errorf("expected error of spiffe issuer without ca files")
+	}
+}
+
+func TestSessionDNSName(t *testing.T) {
+	session := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "session1"}}
+	tests := []struct {
+		name        string
+		application *fornaxv1.Application
+		want        string
+	}{
+		{name: "default dns name", application: tlsApplication(), want: "session1.app1.ns1." + config.DefaultDomainName},
+		{name: "first custom dns name", application: tlsApplication("b.example.org", "a.example.org"), want: "session1.a.example.org"},
+		{name: "wildcard dns name", application: tlsApplication("*.example.org"), want: ""},
+		{name: "no tls", application: &fornaxv1.Application{}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sessionDNSName(tt.application, session); got != tt.want {
+				t.Errorf("sessionDNSName() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
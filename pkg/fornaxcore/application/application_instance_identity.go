@@ -0,0 +1,164 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+func usingOrdinalNaming(application *fornaxv1.Application) bool {
+	return application.Spec.InstanceNaming != nil && application.Spec.InstanceNaming.Strategy == fornaxv1.InstanceNamingStrategyOrdinal
+}
+
+// instanceOrdinal return ordinal of a instance identity of application, false if identity is not <application>-<ordinal>
+func instanceOrdinal(appName, identity string) (int, bool) {
+	suffix := strings.TrimPrefix(identity, appName+"-")
+	if suffix == identity {
+		return 0, false
+	}
+	ordinal, err := strconv.Atoi(suffix)
+	if err != nil || ordinal < 0 || strconv.Itoa(ordinal) != suffix {
+		return 0, false
+	}
+	return ordinal, true
+}
+
+// nextInstanceIdentity return name of next ordinal pod, identities demanded by pending sessions are created first, then lowest free ordinal,
+// a identity is free when no pod of pool or pod manager has its name, so a replacement pod is only created after old pod is gone
+func (am *ApplicationManager) nextInstanceIdentity(pool *ApplicationPool, application *fornaxv1.Application) string {
+	free := func(identity string) bool {
+		podName := fmt.Sprintf("%s/%s", application.Namespace, identity)
+		return pool.getPod(podName) == nil && am.podManager.FindPod(podName) == nil
+	}
+	pendingSessions, _, _ := pool.getNonRunningSessions()
+	demanded := -1
+	for _, s := range pendingSessions {
+		if ordinal, ok := instanceOrdinal(application.Name, s.session.Spec.InstanceIdentity); ok && (demanded == -1 || ordinal < demanded) && free(s.session.Spec.InstanceIdentity) {
+			demanded = ordinal
+		}
+	}
+	if demanded >= 0 {
+		return fmt.Sprintf("%s-%d", application.Name, demanded)
+	}
+	for ordinal := 0; ; ordinal++ {
+		if identity := fmt.Sprintf("%s-%d", application.Name, ordinal); free(identity) {
+			return identity
+		}
+	}
+}
+
+// setPodInstanceIdentity label ordinal pod with its identity and mount volumes claimed by identity,
+// pod is required on node of its volumes if a earlier pod of identity has run on a node
+func setPodInstanceIdentity(pool *ApplicationPool, application *fornaxv1.Application, pod *v1.Pod) {
+	if !usingOrdinalNaming(application) {
+		return
+	}
+	pod.Labels[fornaxv1.LabelFornaxCoreInstanceIdentity] = pod.Name
+	templates := application.Spec.InstanceNaming.VolumeClaimTemplates
+	if len(templates) == 0 {
+		return
+	}
+	for _, t := range templates {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+			Name: t.Name,
+			VolumeSource: v1.VolumeSource{
+				PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: fmt.Sprintf("%s-%s", t.Name, pod.Name)},
+			},
+		})
+		for i := range pod.Spec.Containers {
+			pod.Spec.Containers[i].VolumeMounts = append(pod.Spec.Containers[i].VolumeMounts, v1.VolumeMount{Name: t.Name, MountPath: t.MountPath})
+		}
+	}
+	if nodeId := pool.instanceNode(pod.Name); len(nodeId) > 0 {
+		pod.Annotations[fornaxv1.AnnotationFornaxCoreRequiredNode] = nodeId
+	}
+}
+
+// recordInstanceNode remember node of a identity pod with persistent volumes, binding is saved in application status by syncInstanceNodes
+func recordInstanceNode(pool *ApplicationPool, pod *v1.Pod) {
+	identity, found := pod.GetLabels()[fornaxv1.LabelFornaxCoreInstanceIdentity]
+	if !found {
+		return
+	}
+	nodeId, found := pod.GetLabels()[fornaxv1.LabelFornaxCoreNode]
+	if !found {
+		return
+	}
+	for _, v := range pod.Spec.Volumes {
+		if v.PersistentVolumeClaim != nil {
+			if previous := pool.bindInstanceNode(identity, nodeId); previous != nodeId {
+				klog.InfoS("Bound application instance identity to node", "application", pool.appName, "identity", identity, "node", nodeId, "previous", previous)
+			}
+			return
+		}
+	}
+}
+
+// syncInstanceNodes restore bindings saved in application status which pool does not know, e.g. after fornaxcore restart when
+// pod of a identity is gone, node reported by a pod replace saved binding, it return bindings of pool to save in status
+func syncInstanceNodes(pool *ApplicationPool, application *fornaxv1.Application) map[string]string {
+	if !usingOrdinalNaming(application) || len(application.Spec.InstanceNaming.VolumeClaimTemplates) == 0 {
+		return nil
+	}
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	for identity, nodeId := range application.Status.InstanceNodes {
+		if _, found := pool.instanceNodes[identity]; !found {
+			pool.instanceNodes[identity] = nodeId
+		}
+	}
+	if len(pool.instanceNodes) == 0 {
+		return nil
+	}
+	instanceNodes := make(map[string]string, len(pool.instanceNodes))
+	for identity, nodeId := range pool.instanceNodes {
+		instanceNodes[identity] = nodeId
+	}
+	return instanceNodes
+}
+
+func (pool *ApplicationPool) instanceNode(identity string) string {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+	return pool.instanceNodes[identity]
+}
+
+// bindInstanceNode set node of identity and return previous node
+func (pool *ApplicationPool) bindInstanceNode(identity, nodeId string) string {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	previous := pool.instanceNodes[identity]
+	pool.instanceNodes[identity] = nodeId
+	return previous
+}
+
+// instanceIdentityFilter only allow pod of session instance identity if session require one
+func instanceIdentityFilter(session *fornaxv1.ApplicationSession, allowed func(*v1.Pod) bool) func(*v1.Pod) bool {
+	identity := session.Spec.InstanceIdentity
+	if len(identity) == 0 {
+		return allowed
+	}
+	return func(pod *v1.Pod) bool {
+		return pod.Name == identity && allowed(pod)
+	}
+}
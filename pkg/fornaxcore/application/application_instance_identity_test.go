@@ -0,0 +1,180 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"testing"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	ie "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/internal"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// fakePodManager only find pods by name, other methods are not used by instance identity
+type fakePodManager struct {
+	ie.PodManagerInterface
+	pods map[string]*v1.Pod
+}
+
+func (f *fakePodManager) FindPod(podName string) *v1.Pod {
+	return f.pods[podName]
+}
+
+func newTestOrdinalApplication(templates ...fornaxv1.ApplicationVolumeClaimTemplate) *fornaxv1.Application {
+	return &fornaxv1.Application{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "app"},
+		Spec: fornaxv1.ApplicationSpec{
+			InstanceNaming: &fornaxv1.ApplicationInstanceNaming{
+				Strategy:             fornaxv1.InstanceNamingStrategyOrdinal,
+				VolumeClaimTemplates: templates,
+			},
+		},
+	}
+}
+
+func addTestPendingSession(pool *ApplicationPool, name, identity string) {
+	session := &fornaxv1.ApplicationSession{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, UID: types.UID("uid-" + name), CreationTimestamp: metav1.Now()},
+		Spec:       fornaxv1.ApplicationSessionSpec{ApplicationName: "app", InstanceIdentity: identity},
+		Status:     fornaxv1.ApplicationSessionStatus{SessionStatus: fornaxv1.SessionStatusPending},
+	}
+	pool.addSession("ns/"+name, session)
+}
+
+func TestInstanceOrdinal(t *testing.T) {
+	tests := []struct {
+		identity string
+		ordinal  int
+		ok       bool
+	}{
+		{identity: "app-0", ordinal: 0, ok: true},
+		{identity: "app-12", ordinal: 12, ok: true},
+		{identity: "app-01", ok: false},
+		{identity: "app--1", ok: false},
+		{identity: "app-", ok: false},
+		{identity: "app-x", ok: false},
+		{identity: "app", ok: false},
+		{identity: "other-1", ok: false},
+		{identity: "app-1-2", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.identity, func(t *testing.T) {
+			ordinal, ok := instanceOrdinal("app", tt.identity)
+			if ok != tt.ok || (ok && ordinal != tt.ordinal) {
+				t.Errorf("instanceOrdinal(%q) = %d, %v, want %d, %v", tt.identity, ordinal, ok, tt.ordinal, tt.ok)
+			}
+		})
+	}
+}
+
+func TestNextInstanceIdentity(t *testing.T) {
+	application := newTestOrdinalApplication()
+	podManager := &fakePodManager{pods: map[string]*v1.Pod{}}
+	am := &ApplicationManager{podManager: podManager}
+	pool := NewApplicationPool("ns/app")
+
+	if got := am.nextInstanceIdentity(pool, application); got != "app-0" {
+		t.Errorf("expected first identity app-0, got %s", got)
+	}
+
+	// app-1 is free while app-0 and app-2 are taken by pool and pod manager
+	pool.addOrUpdatePod("ns/app-0", PodStateIdle, []string{})
+	podManager.pods["ns/app-2"] = &v1.Pod{}
+	if got := am.nextInstanceIdentity(pool, application); got != "app-1" {
+		t.Errorf("expected lowest free identity app-1, got %s", got)
+	}
+
+	// replacement of a pod is only created after old pod is gone from pod manager
+	pool.addOrUpdatePod("ns/app-1", PodStateIdle, []string{})
+	if got := am.nextInstanceIdentity(pool, application); got != "app-3" {
+		t.Errorf("expected app-3 when app-2 is still known by pod manager, got %s", got)
+	}
+	delete(podManager.pods, "ns/app-2")
+	if got := am.nextInstanceIdentity(pool, application); got != "app-2" {
+		t.Errorf("expected app-2 reused after old pod is gone, got %s", got)
+	}
+}
+
+func TestNextInstanceIdentityDemandedBySession(t *testing.T) {
+	application := newTestOrdinalApplication()
+	am := &ApplicationManager{podManager: &fakePodManager{pods: map[string]*v1.Pod{}}}
+	pool := NewApplicationPool("ns/app")
+
+	// lowest identity demanded by a pending session is created before lower free ordinals
+	addTestPendingSession(pool, "s1", "app-7")
+	addTestPendingSession(pool, "s2", "app-4")
+	// identity of other application or malformed identity is not demanded
+	addTestPendingSession(pool, "s3", "other-1")
+	if got := am.nextInstanceIdentity(pool, application); got != "app-4" {
+		t.Errorf("expected lowest demanded identity app-4, got %s", got)
+	}
+
+	// demanded identity already has a pod
+	pool.addOrUpdatePod("ns/app-4", PodStatePending, []string{})
+	if got := am.nextInstanceIdentity(pool, application); got != "app-7" {
+		t.Errorf("expected next demanded identity app-7, got %s", got)
+	}
+	pool.addOrUpdatePod("ns/app-7", PodStatePending, []string{})
+	if got := am.nextInstanceIdentity(pool, application); got != "app-0" {
+		t.Errorf("expected lowest free identity app-0 after demands are met, got %s", got)
+	}
+}
+
+func TestSyncInstanceNodes(t *testing.T) {
+	pool := NewApplicationPool("ns/app")
+	application := newTestOrdinalApplication(fornaxv1.ApplicationVolumeClaimTemplate{Name: "data", MountPath: "/data"})
+	application.Status.InstanceNodes = map[string]string{"app-0": "node-1", "app-1": "node-2"}
+
+	// node reported by a running pod replace saved binding
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "app-1",
+			Labels:    map[string]string{fornaxv1.LabelFornaxCoreInstanceIdentity: "app-1", fornaxv1.LabelFornaxCoreNode: "node-3"},
+		},
+		Spec: v1.PodSpec{Volumes: []v1.Volume{{Name: "data", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "data-app-1"}}}}},
+	}
+	recordInstanceNode(pool, pod)
+
+	instanceNodes := syncInstanceNodes(pool, application)
+	if instanceNodes["app-0"] != "node-1" || instanceNodes["app-1"] != "node-3" || len(instanceNodes) != 2 {
+		t.Errorf("expected saved binding restored and reported node kept, got %v", instanceNodes)
+	}
+	if got := pool.instanceNode("app-0"); got != "node-1" {
+		t.Errorf("expected restored binding used by pool, got %s", got)
+	}
+
+	// replacement pod of a restored identity is required on node of its volumes
+	replacement := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "app-0", Labels: map[string]string{}, Annotations: map[string]string{}},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "c"}}},
+	}
+	setPodInstanceIdentity(pool, application, replacement)
+	if got := replacement.Annotations[fornaxv1.AnnotationFornaxCoreRequiredNode]; got != "node-1" {
+		t.Errorf("expected replacement pod required on node-1, got %s", got)
+	}
+	if got := replacement.Spec.Volumes[0].PersistentVolumeClaim.ClaimName; got != "data-app-0" {
+		t.Errorf("expected claim of identity, got %s", got)
+	}
+
+	// application without volumes does not save bindings
+	if got := syncInstanceNodes(pool, newTestOrdinalApplication()); got != nil {
+		t.Errorf("expected no bindings saved without volume claims, got %v", got)
+	}
+}
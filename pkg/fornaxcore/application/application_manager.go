@@ -18,14 +18,21 @@ package application
 
 import (
 	"context"
-	"fmt"
 	"math"
 	"reflect"
 	"sync"
 	"time"
 
 	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/config"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/controller"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/gateway"
 	ie "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/internal"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/placement"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/podscheduler"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/prober"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/replay"
+	"centaurusinfra.io/fornax-serverless/pkg/pki"
 	fornaxstore "centaurusinfra.io/fornax-serverless/pkg/store"
 	storefactory "centaurusinfra.io/fornax-serverless/pkg/store/factory"
 	"centaurusinfra.io/fornax-serverless/pkg/util"
@@ -33,10 +40,8 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	apistorage "k8s.io/apiserver/pkg/storage"
-	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 )
 
@@ -53,6 +58,25 @@ type ApplicationPool struct {
 	mu          sync.RWMutex
 	podsByState map[ApplicationPodState]map[string]*ApplicationPod
 	sessions    map[ApplicationSessionState]map[string]*ApplicationSession
+
+	// used by suspension policy to find idle application
+	createTime     time.Time
+	lastActiveTime time.Time
+
+	// used by autoscaler to count scale to zero once in a idle period
+	scaledToZeroTime time.Time
+
+	// outdated pods drained by rollout and when, their sessions are closed after close grace period
+	rolloutDrainTime map[string]time.Time
+
+	// used by rollout policy to check health of new pods
+	rolloutHealth rolloutHealth
+
+	// node of each ordinal instance identity which has persistent volumes
+	instanceNodes map[string]string
+
+	// secret versions last sent to each pod, a pod is not sent same versions again until resend period passed
+	secretDeliveries map[string]*secretDelivery
 }
 
 func NewApplicationPool(appName string) *ApplicationPool {
@@ -71,6 +95,10 @@ func NewApplicationPool(appName string) *ApplicationPool {
 			SessionStateRunning:  {},
 			SessionStateDeleting: {},
 		},
+		createTime:       time.Now(),
+		rolloutDrainTime: map[string]time.Time{},
+		instanceNodes:    map[string]string{},
+		secretDeliveries: map[string]*secretDelivery{},
 	}
 }
 
@@ -82,7 +110,7 @@ type ApplicationManager struct {
 
 	appKind          schema.GroupVersionKind
 	appSessionKind   schema.GroupVersionKind
-	applicationQueue workqueue.RateLimitingInterface
+	applicationQueue *controller.Controller
 
 	applicationPools map[string]*ApplicationPool
 
@@ -95,20 +123,42 @@ type ApplicationManager struct {
 	sessionUpdateChannel <-chan fornaxstore.WatchEventWithOldObj
 
 	applicationStatusManager *ApplicationStatusManager
+	readinessGateChecker     *ApplicationReadinessGateChecker
+	secretRotator            *ApplicationSecretRotator
+	secretBox                *pki.SecretBox
+	certificates             *ApplicationCertificateManager
+	sessionProberPool        *prober.ProberPool
+	sessionGateway           *gateway.SessionGateway
+	podScheduler             podscheduler.PodScheduler
+	nodeManager              ie.NodeManagerInterface
+	placementAuditLog        *placement.AuditLog
+	sessionLatency           *sessionLatencyTracker
 }
 
 // NewApplicationManager init ApplicationInformer and ApplicationSessionInformer,
 // and start to listen to pod event from node
-func NewApplicationManager(ctx context.Context, podManager ie.PodManagerInterface, sessionManager ie.SessionManagerInterface, appStore fornaxstore.ApiStorageInterface) *ApplicationManager {
+func NewApplicationManager(ctx context.Context, podManager ie.PodManagerInterface, sessionManager ie.SessionManagerInterface, podScheduler podscheduler.PodScheduler, appStore fornaxstore.ApiStorageInterface) *ApplicationManager {
 	am := &ApplicationManager{
 		ctx:              ctx,
-		applicationQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "fornaxv1.Application"),
 		applicationPools: map[string]*ApplicationPool{},
 		podUpdateChannel: make(chan *ie.PodEvent, 1000),
 		podManager:       podManager,
 		sessionManager:   sessionManager,
 		applicationStore: appStore,
+		podScheduler:     podScheduler,
+		sessionLatency:   &sessionLatencyTracker{},
+		secretRotator:    NewApplicationSecretRotator(),
+	}
+	am.readinessGateChecker = NewApplicationReadinessGateChecker(am.enqueueApplication)
+	am.applicationQueue = controller.NewController("fornaxv1.Application", DefaultNumOfApplicationWorkers, am.syncApplication)
+	issuer, err := loadCertificateIssuer(config.DefaultFornaxCoreCertificateIssuerConfigFile)
+	if err != nil {
+		klog.ErrorS(err, "Failed to create certificate issuer, application certificates are not issued")
+		am.certificates = NewApplicationCertificateManager(nil)
+	} else {
+		am.certificates = NewApplicationCertificateManager(issuer)
 	}
+	am.sessionProberPool = prober.NewProberPool(prober.DefaultNumOfProberWorkers, am.onSessionHealthChange)
 	am.podManager.Watch(am.podUpdateChannel)
 
 	return am
@@ -192,16 +242,18 @@ func (am *ApplicationManager) Run(ctx context.Context) {
 			case <-ctx.Done():
 				break
 			case we := <-am.appUpdateChannel:
+				replay.RecordWatchEvent(fornaxv1.ApplicationGrv.GroupResource().String(), we)
 				am.onApplicationEventFromStorage(we)
+				fornaxstore.ObserveWatchEventHandled(fornaxv1.ApplicationGrv.GroupResource().String(), "application-manager", we)
 			}
 		}
 	}()
 
 	am.initApplicationSessionInformer(ctx)
+	am.sessionProberPool.Run(ctx)
 
+	am.applicationQueue.Run(ctx)
 	for i := 0; i < DefaultNumOfApplicationWorkers; i++ {
-		go wait.UntilWithContext(ctx, am.worker, time.Second)
-
 		go func() {
 			defer klog.Info("Shutting down fornaxv1 application pod manager")
 			for {
@@ -221,7 +273,9 @@ func (am *ApplicationManager) Run(ctx context.Context) {
 				case <-ctx.Done():
 					break
 				case we := <-am.sessionUpdateChannel:
+					replay.RecordWatchEvent(fornaxv1.ApplicationSessionGrv.GroupResource().String(), we)
 					am.onSessionEventFromStorage(we)
+					fornaxstore.ObserveWatchEventHandled(fornaxv1.ApplicationSessionGrv.GroupResource().String(), "application-session-manager", we)
 				}
 			}
 		}()
@@ -230,7 +284,6 @@ func (am *ApplicationManager) Run(ctx context.Context) {
 
 	go func() {
 		defer utilruntime.HandleCrash()
-		defer am.applicationQueue.ShutDown()
 		ticker := time.NewTicker(HouseKeepingDuration)
 		for {
 			select {
@@ -241,11 +294,25 @@ func (am *ApplicationManager) Run(ctx context.Context) {
 			}
 		}
 	}()
+
+	go func() {
+		defer utilruntime.HandleCrash()
+		ticker := time.NewTicker(DefaultApplicationResyncPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				am.resyncApplications()
+			}
+		}
+	}()
 	klog.Info("Fornaxv1 application manager started")
 }
 
 func (am *ApplicationManager) enqueueApplication(applicationKey string) {
-	am.applicationQueue.Add(applicationKey)
+	am.applicationQueue.Enqueue(applicationKey)
 }
 
 // callback from Application informer when Application is created
@@ -269,6 +336,23 @@ func (am *ApplicationManager) onApplicationUpdateEvent(old, cur interface{}) {
 		klog.InfoS("Updating application", "app", applicationKey, "deleting", newCopy.DeletionTimestamp != nil)
 		am.enqueueApplication(applicationKey)
 	}
+
+	// push changed config data to live pods, so, long running sessions do not need to restart to get new config
+	if newCopy.DeletionTimestamp == nil && !reflect.DeepEqual(oldCopy.Spec.ConfigData, newCopy.Spec.ConfigData) {
+		if pool := am.getApplicationPool(applicationKey); pool != nil {
+			klog.InfoS("Application config data changed, update pods", "app", applicationKey)
+			am.updateApplicationPodsConfig(pool, newCopy)
+		}
+	}
+
+	// keep container spec of last revision, rollout policy use it to roll back a failed rollout
+	if !reflect.DeepEqual(oldCopy.Spec.Containers, newCopy.Spec.Containers) {
+		am.getOrCreateApplicationPool(applicationKey).setPreviousContainers(podTemplateHash(oldCopy), oldCopy.Spec.Containers)
+	}
+
+	if newCopy.DeletionTimestamp == nil && !reflect.DeepEqual(oldCopy.Spec.Secret, newCopy.Spec.Secret) {
+		am.onApplicationSecretChange(oldCopy, newCopy)
+	}
 }
 
 // callback from application informer when Application is deleted
@@ -276,35 +360,11 @@ func (am *ApplicationManager) onApplicationDeleteEvent(obj interface{}) {
 	appliation := obj.(*fornaxv1.Application)
 	applicationKey := util.Name(appliation)
 	klog.Infof("Deleting application %s", applicationKey)
-	am.applicationQueue.Add(applicationKey)
+	am.applicationQueue.Enqueue(applicationKey)
 }
 
 // worker runs a worker thread that just dequeues items, processes them, and marks them done.
 // It enforces that the syncHandler is never invoked concurrently with the same key.
-func (am *ApplicationManager) worker(ctx context.Context) {
-	for am.processNextWorkItem(ctx) {
-	}
-}
-
-func (am *ApplicationManager) processNextWorkItem(ctx context.Context) bool {
-	key, quit := am.applicationQueue.Get()
-	if quit {
-		return false
-	}
-	defer am.applicationQueue.Done(key)
-
-	err := am.syncApplication(ctx, key.(string))
-	if err == nil {
-		am.applicationQueue.Forget(key)
-		return true
-	}
-
-	utilruntime.HandleError(fmt.Errorf("sync %q failed with %v", key, err))
-	am.applicationQueue.AddRateLimited(key)
-
-	return true
-}
-
 func (am *ApplicationManager) cleanupDeletedApplication(pool *ApplicationPool) error {
 	klog.InfoS("Cleanup a deleting Application, close all remaining session then deleting pod", "application", pool.appName)
 	numOfSession := pool.sessionLength()
@@ -324,6 +384,10 @@ func (am *ApplicationManager) cleanupDeletedApplication(pool *ApplicationPool) e
 	if pool.podLength() == 0 && pool.sessionLength() == 0 {
 		klog.InfoS("No remaining pod and session for deleting application, cleanup is done", "application", pool.appName)
 		am.deleteApplicationPool(pool.appName)
+		am.readinessGateChecker.Forget(pool.appName)
+		am.secretRotator.Forget(pool.appName)
+		am.certificates.Forget(pool.appName)
+		forgetAutoscalerMetrics(pool.appName)
 	}
 	return nil
 }
@@ -352,15 +416,29 @@ func (am *ApplicationManager) syncApplication(ctx context.Context, applicationKe
 			numOfDesiredPod = 0
 			action = fornaxv1.DeploymentActionDeleteInstance
 			syncErr = am.cleanupDeletedApplication(pool)
-			// as application is not found in storage, just return and skip update status
+			// as application is not found in storage, just return and skip update status, failed cleanup is retried with backoff
 			if syncErr != nil {
-				am.applicationQueue.AddAfter(applicationKey, DefaultApplicationSyncErrorRecycleDuration)
-				return nil
+				return syncErr
 			}
 		}
 	} else if application != nil {
-		if application.DeletionTimestamp == nil {
-			// 1, assign pending session to idle pods firstly and cleanup timedout and deleting sessions
+		suspended := false
+		var rollout *fornaxv1.ApplicationRolloutStatus
+		instanceNodes := application.Status.InstanceNodes
+		if application.DeletionTimestamp == nil && am.applicationSuspended(pool, application) {
+			suspended = true
+			numOfDesiredPod = 0
+			action = fornaxv1.DeploymentActionDeleteInstance
+		} else if application.DeletionTimestamp == nil {
+			// restore identity bindings before any identity pod is created
+			instanceNodes = syncInstanceNodes(pool, application)
+
+			// 0, replace pods created from a old container spec
+			rollout = am.rolloutApplication(pool, application)
+
+			// 1, assign pending session to idle pods firstly and cleanup timedout and deleting sessions,
+			// sessions requested to migrate are closed on their pods and become pending session of target pods
+			am.migrateApplicationSessions(pool)
 			syncErr = am.deployApplicationSessions(pool, application)
 
 			// 2, find how many more pods required for remaining pending sessions
@@ -369,7 +447,13 @@ func (am *ApplicationManager) syncApplication(ctx context.Context, applicationKe
 				numOfOccupiedPod, numOfPendingPod, numOfIdlePod := pool.activePodNums()
 				numOfUnoccupiedPod := numOfPendingPod + numOfIdlePod
 				numOfPendingSession := sessionSummary.pendingCount
-				numOfDesiredUnoccupiedPod := am.calculateDesiredIdlePods(application, numOfOccupiedPod, numOfUnoccupiedPod, numOfPendingSession)
+				var numOfDesiredUnoccupiedPod int
+				if policy := sessionDemandPolicy(application); policy != nil {
+					numOfDesiredUnoccupiedPod = am.calculateSessionDemandIdlePods(pool, application, policy, numOfOccupiedPod, numOfUnoccupiedPod, numOfPendingSession)
+				} else {
+					numOfDesiredUnoccupiedPod = am.calculateDesiredIdlePods(application, numOfOccupiedPod, numOfUnoccupiedPod, numOfPendingSession)
+				}
+				numOfDesiredUnoccupiedPod = am.applyWarmPool(pool, application, numOfOccupiedPod, numOfUnoccupiedPod, numOfDesiredUnoccupiedPod)
 				numOfDesiredPod = numOfOccupiedPod + numOfDesiredUnoccupiedPod
 				klog.InfoS("Syncing application pod", "application", applicationKey, "pending-sessions", numOfPendingSession, "active-pods", numOfOccupiedPod+numOfUnoccupiedPod, "pending-pods", numOfPendingPod, "idle-pods", numOfIdlePod, "desired-pending+idle-pods", numOfDesiredUnoccupiedPod)
 				if numOfDesiredUnoccupiedPod > numOfUnoccupiedPod {
@@ -391,16 +475,30 @@ func (am *ApplicationManager) syncApplication(ctx context.Context, applicationKe
 		}
 
 		newStatus := am.calculateStatus(pool, application, numOfDesiredPod, action, syncErr)
-		am.applicationStatusManager.UpdateApplicationStatus(application, newStatus)
+		if suspended {
+			syncErr = am.suspendApplication(pool, application, newStatus)
+		} else {
+			newStatus.Suspended = false
+			newStatus.SuspendedTime = nil
+		}
+		if application.DeletionTimestamp == nil {
+			secret, tlsStatus := am.applicationSecret(application)
+			newStatus.SecretStatus = am.syncApplicationSecret(pool, application, secret)
+			newStatus.TLSStatus = tlsStatus
+			if rollout != nil {
+				newStatus.History = appendRolloutHistory(application.Status.Rollout, rollout, newStatus.History)
+				newStatus.Rollout = rollout
+			}
+			newStatus.InstanceNodes = instanceNodes
+		}
+		if err := am.applicationStatusManager.UpdateApplicationStatus(application, newStatus); err != nil && syncErr == nil {
+			// status is recalculated in retry
+			syncErr = err
+		}
 	}
 
-	// Requeue the Application if there is error, if no error but total pods number does not meet desired number,
+	// Application is requeued with backoff by controller if there is error, if no error but total pods number does not meet desired number,
 	// when event of pods created/deleted in this sync come back from nodes will trigger next sync, finally meet desired state
-	if syncErr != nil {
-		klog.ErrorS(syncErr, "Failed to sync application, requeue", "application", applicationKey)
-		am.applicationQueue.AddAfter(applicationKey, DefaultApplicationSyncErrorRecycleDuration)
-	}
-
 	return syncErr
 }
 
@@ -551,6 +649,22 @@ func (am *ApplicationManager) HouseKeeping() error {
 		if podSummary.pendingCount > 0 || podSummary.deletingCount > 0 {
 			am.enqueueApplication(appKey)
 		}
+
+		if am.suspensionDue(appKey, pool) {
+			am.enqueueApplication(appKey)
+		}
+
+		if am.scaleToZeroDue(appKey, pool) {
+			am.enqueueApplication(appKey)
+		}
+
+		if am.warmPoolShort(appKey, pool) {
+			am.enqueueApplication(appKey)
+		}
+
+		if pool.rolloutDraining() {
+			am.enqueueApplication(appKey)
+		}
 	}
 
 	return nil
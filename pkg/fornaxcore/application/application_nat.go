@@ -0,0 +1,51 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"encoding/json"
+	"strings"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// setPodSTUNServers ask node to discover public endpoints of pod udp ports
+func setPodSTUNServers(application *fornaxv1.Application, pod *v1.Pod) {
+	if application.Spec.NATTraversal == nil || len(application.Spec.NATTraversal.STUNServers) == 0 {
+		return
+	}
+	pod.Annotations[fornaxv1.AnnotationFornaxCoreSTUNServers] = strings.Join(application.Spec.NATTraversal.STUNServers, ",")
+}
+
+// setSessionNATEndpoints copy public endpoints discovered by node from pod annotation and relay of application into session status
+func setSessionNATEndpoints(application *fornaxv1.Application, pod *v1.Pod, status *fornaxv1.ApplicationSessionStatus) {
+	if application == nil || application.Spec.NATTraversal == nil {
+		return
+	}
+	if data, found := pod.GetAnnotations()[fornaxv1.AnnotationFornaxCorePublicEndpoints]; found {
+		endpoints := []fornaxv1.AccessEndPoint{}
+		if err := json.Unmarshal([]byte(data), &endpoints); err != nil {
+			klog.ErrorS(err, "Invalid public endpoints of pod", "pod", util.Name(pod))
+		} else {
+			status.PublicEndPoints = endpoints
+		}
+	}
+	status.Relay = application.Spec.NATTraversal.Relay.DeepCopy()
+}
@@ -17,7 +17,10 @@ limitations under the License.
 package application
 
 import (
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
@@ -50,6 +53,15 @@ const (
 	PodStateIdle                      ApplicationPodState = 3 // pod is available to assign a session
 )
 
+const (
+	// node agent close session using this grace period if session does not specify it
+	DefaultSessionCloseGracePeriod = 120 * time.Second
+
+	SessionDrainReasonPodDeleting = "PodDeleting"
+
+	SessionDrainReasonApplicationRollout = "ApplicationRollout"
+)
+
 type ApplicationPod struct {
 	podName  string
 	state    ApplicationPodState
@@ -97,6 +109,7 @@ func (am *ApplicationManager) handlePodAddUpdateFromNode(pod *v1.Pod) {
 		return
 	} else {
 		pool := am.getOrCreateApplicationPool(applicationKey)
+		recordInstanceNode(pool, pod)
 		ap := pool.getPod(podName)
 		if ap != nil && ap.state == PodStateDeleting {
 			// this pod was requested to terminate, and node did not receive termination or failed to do it, try it again
@@ -152,6 +165,7 @@ func (am *ApplicationManager) handlePodDeleteFromNode(pod *v1.Pod) {
 		if pool == nil {
 			return
 		}
+		am.observeRolloutPodTermination(pool, pod)
 		am.cleanupSessionOnDeletedPod(pool, podName)
 		pool.deletePod(podName)
 	}
@@ -175,6 +189,7 @@ func (am *ApplicationManager) deleteApplicationPod(pool *ApplicationPool, podNam
 		}
 	}
 
+	am.drainPodSessions(pool, podName, SessionDrainReasonPodDeleting)
 	pool.addOrUpdatePod(podName, PodStateDeleting, []string{})
 	err := am.podManager.TerminatePod(podName)
 	if err != nil {
@@ -191,10 +206,73 @@ func (am *ApplicationManager) deleteApplicationPod(pool *ApplicationPool, podNam
 	return nil
 }
 
-func (am *ApplicationManager) createApplicationPod(application *fornaxv1.Application, standby bool) (*v1.Pod, error) {
+// namespacePodNum count pods which are not being deleted of all applications in namespace
+func (am *ApplicationManager) namespacePodNum(namespace string) int {
+	num := 0
+	for key, pool := range am.applicationList() {
+		if strings.HasPrefix(key, namespace+"/") {
+			occupiedPods, pendingPods, idlePods := pool.activePodNums()
+			num += occupiedPods + pendingPods + idlePods
+		}
+	}
+	return num
+}
+
+// sessionCloseGracePeriod return close grace period of session, default if session does not specify it
+func sessionCloseGracePeriod(session *fornaxv1.ApplicationSession) time.Duration {
+	if session.Spec.CloseGracePeriodSeconds != nil {
+		return time.Duration(*session.Spec.CloseGracePeriodSeconds) * time.Second
+	}
+	return DefaultSessionCloseGracePeriod
+}
+
+// drainPodSessions tell open sessions on pod they are ending soon, so application can ask connected clients to leave,
+// deadline is when node agent force close session after its close grace period
+func (am *ApplicationManager) drainPodSessions(pool *ApplicationPool, podName string, reason string) {
+	pod := am.podManager.FindPod(podName)
+	if pod == nil {
+		return
+	}
+	for _, s := range pool.getPodSessions(podName) {
+		if !util.SessionIsOpen(s.session) || util.SessionIsClosing(s.session) {
+			continue
+		}
+		if err := am.sessionManager.DrainSession(pod, s.session, reason, time.Now().Add(sessionCloseGracePeriod(s.session))); err != nil {
+			klog.ErrorS(err, "Failed to drain session", "application", pool.appName, "pod", podName, "session", util.Name(s.session), "requestId", util.RequestId(s.session))
+		}
+	}
+}
+
+// updateApplicationPodsConfig push application config data to all pods of application which are not being deleted,
+// node agent notify open sessions on pod, pending pods are skipped since they are not bound with node yet
+func (am *ApplicationManager) updateApplicationPodsConfig(pool *ApplicationPool, application *fornaxv1.Application) {
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      application.Name,
+			Namespace: application.Namespace,
+		},
+		Data: application.Spec.ConfigData,
+	}
+	for _, state := range []ApplicationPodState{PodStateIdle, PodStateAllocated} {
+		for _, ap := range pool.podListOfState(state) {
+			if err := am.podManager.UpdatePodConfig(ap.podName, configMap); err != nil {
+				klog.ErrorS(err, "Failed to update pod config", "application", util.Name(application), "pod", ap.podName)
+			}
+		}
+	}
+}
+
+// createApplicationPod create a pod with image pull priority, node pull images of pods for pending sessions before prewarm pods
+// pod of a application using ordinal naming get next free instance identity as its name
+func (am *ApplicationManager) createApplicationPod(pool *ApplicationPool, application *fornaxv1.Application, standby bool, pullPriority string) (*v1.Pod, error) {
 	uid := uuid.New()
 	name := fmt.Sprintf("%s-%s-%d", application.Name, rand.String(16), uid.ClockSequence())
+	if usingOrdinalNaming(application) {
+		name = am.nextInstanceIdentity(pool, application)
+	}
 	podTemplate := am.getPodApplicationPodTemplate(uid, name, application, standby)
+	setPodInstanceIdentity(pool, application, podTemplate)
+	podTemplate.Annotations[fornaxv1.AnnotationFornaxCoreImagePullPriority] = pullPriority
 	pod, err := am.podManager.AddOrUpdatePod("", podTemplate)
 	if err != nil {
 		return nil, err
@@ -230,7 +308,8 @@ func (am *ApplicationManager) getPodApplicationPodTemplate(uid uuid.UUID, name s
 			DeletionTimestamp:          nil,
 			DeletionGracePeriodSeconds: application.DeletionGracePeriodSeconds,
 			Labels: map[string]string{
-				fornaxv1.LabelFornaxCoreApplication: util.Name(application),
+				fornaxv1.LabelFornaxCoreApplication:  util.Name(application),
+				fornaxv1.LabelFornaxCoreTemplateHash: podTemplateHash(application),
 			},
 			Annotations: map[string]string{},
 			OwnerReferences: []metav1.OwnerReference{
@@ -262,7 +341,7 @@ func (am *ApplicationManager) getPodApplicationPodTemplate(uid uuid.UUID, name s
 			HostPID:                       false,
 			HostIPC:                       false,
 			ShareProcessNamespace:         &shareProcessNamespace,
-			SecurityContext:               &v1.PodSecurityContext{},
+			SecurityContext:               &v1.PodSecurityContext{Sysctls: append([]v1.Sysctl{}, application.Spec.Sysctls...)},
 			ImagePullSecrets:              []v1.LocalObjectReference{},
 			Hostname:                      "",
 			Subdomain:                     default_config.DefaultDomainName,
@@ -292,6 +371,11 @@ func (am *ApplicationManager) getPodApplicationPodTemplate(uid uuid.UUID, name s
 	containers := []v1.Container{}
 	for _, v := range application.Spec.Containers {
 		cont := v.DeepCopy()
+		for i := range cont.Ports {
+			if len(cont.Ports[i].Protocol) == 0 {
+				cont.Ports[i].Protocol = v1.ProtocolTCP
+			}
+		}
 		cont.Env = append(cont.Env, v1.EnvVar{
 			Name:  fornaxv1.LabelFornaxCorePod,
 			Value: util.Name(pod),
@@ -319,10 +403,71 @@ func (am *ApplicationManager) getPodApplicationPodTemplate(uid uuid.UUID, name s
 	if application.Spec.UsingNodeSessionService {
 		pod.Annotations[fornaxv1.AnnotationFornaxCoreSessionServicePod] = "sessionservicepod"
 	}
+	if runtime, found := application.Annotations[fornaxv1.AnnotationFornaxCoreSessionRuntime]; found {
+		pod.Annotations[fornaxv1.AnnotationFornaxCoreSessionRuntime] = runtime
+		if fornaxv1.SessionRuntime(runtime) == fornaxv1.SessionRuntimeWebSocket {
+			// sidecar drive session lifecycle, container does not need to link session sdk
+			pod.Annotations[fornaxv1.AnnotationFornaxCoreSessionServicePod] = "sessionservicepod"
+		}
+	}
+	if port, found := application.Annotations[fornaxv1.AnnotationFornaxCoreSessionSidecarPort]; found {
+		pod.Annotations[fornaxv1.AnnotationFornaxCoreSessionSidecarPort] = port
+	}
+	setPodSTUNServers(application, pod)
+	setPodRestartPolicy(application, pod)
+	setPodProcessLimits(application, pod)
+	setPodWarmUp(application, pod)
+	setPodNetworkPolicy(application, pod)
 
 	return pod
 }
 
+// setPodRestartPolicy copy application restart policy into pod spec, backoff is passed to node in annotation
+func setPodRestartPolicy(application *fornaxv1.Application, pod *v1.Pod) {
+	restart := application.Spec.RestartPolicy
+	if restart == nil || len(restart.Policy) == 0 {
+		return
+	}
+	pod.Spec.RestartPolicy = restart.Policy
+	if restart.InitialBackoffSeconds > 0 || restart.MaxBackoffSeconds > 0 {
+		pod.Annotations[fornaxv1.AnnotationFornaxCoreRestartBackoff] = fmt.Sprintf("%d,%d", restart.InitialBackoffSeconds, restart.MaxBackoffSeconds)
+	}
+}
+
+// setPodProcessLimits pass application process limits to node in annotations
+func setPodProcessLimits(application *fornaxv1.Application, pod *v1.Pod) {
+	limits := application.Spec.ProcessLimits
+	if limits == nil {
+		return
+	}
+	if limits.PodPidsLimit > 0 {
+		pod.Annotations[fornaxv1.AnnotationFornaxCorePodPidsLimit] = strconv.FormatInt(limits.PodPidsLimit, 10)
+	}
+	if limits.OOMScoreAdj != nil {
+		pod.Annotations[fornaxv1.AnnotationFornaxCoreOOMScoreAdj] = strconv.Itoa(int(*limits.OOMScoreAdj))
+	}
+}
+
+// setPodWarmUp pass application warm up to node in annotation
+func setPodWarmUp(application *fornaxv1.Application, pod *v1.Pod) {
+	if application.Spec.WarmUp == nil {
+		return
+	}
+	if data, err := json.Marshal(application.Spec.WarmUp); err == nil {
+		pod.Annotations[fornaxv1.AnnotationFornaxCoreWarmUp] = string(data)
+	}
+}
+
+// setPodNetworkPolicy pass application network policy to node in annotation
+func setPodNetworkPolicy(application *fornaxv1.Application, pod *v1.Pod) {
+	if application.Spec.NetworkPolicy == nil {
+		return
+	}
+	if data, err := json.Marshal(application.Spec.NetworkPolicy); err == nil {
+		pod.Annotations[fornaxv1.AnnotationFornaxCoreNetworkPolicy] = string(data)
+	}
+}
+
 // given a list pods, pick up which can be deleted with less cost, priority is
 // 1, pods not find in podManager
 // 2, pods still in pending state
@@ -402,18 +547,37 @@ func (am *ApplicationManager) getPodsToBeDelete(pool *ApplicationPool, numOfDesi
 func (am *ApplicationManager) deployApplicationPods(pool *ApplicationPool, application *fornaxv1.Application, desiredAddition int) error {
 	var err error
 
-	applicationBurst := util.ApplicationScalingBurst(application)
+	applicationBurst := am.applicationScalingBurst(pool, application, desiredAddition > 0)
 	if desiredAddition > 0 {
 		if desiredAddition > applicationBurst {
 			desiredAddition = applicationBurst
 		}
+		var remaining int64
+		remaining, err = factory.RemainingApplicationInstanceQuota(am.ctx, application.Namespace, int64(am.namespacePodNum(application.Namespace)))
+		if err != nil {
+			return err
+		}
+		if int64(desiredAddition) > remaining {
+			klog.InfoS("Application instance quota exceeded", "application", pool.appName, "addition", desiredAddition, "remaining", remaining)
+			desiredAddition = int(remaining)
+			if desiredAddition == 0 {
+				return nil
+			}
+		}
 
-		klog.InfoS("Creating pods", "application", pool.appName, "addition", desiredAddition)
+		// pods more than pending sessions need are created for warm pool and session demand forecast
+		_, pendingPods, idlePods := pool.activePodNums()
+		sessionPods := pool.summarySession().pendingCount - pendingPods - idlePods
+		klog.InfoS("Creating pods", "application", pool.appName, "addition", desiredAddition, "for pending sessions", sessionPods)
 		createdPods := []*v1.Pod{}
 		createErrors := []error{}
 		standby := !application.Spec.UsingNodeSessionService
 		for i := 0; i < desiredAddition; i++ {
-			pod, err := am.createApplicationPod(application, standby)
+			pullPriority := fornaxv1.ImagePullPriorityPrewarm
+			if i < sessionPods {
+				pullPriority = fornaxv1.ImagePullPrioritySession
+			}
+			pod, err := am.createApplicationPod(pool, application, standby, pullPriority)
 			if err != nil {
 				klog.ErrorS(err, "Create pod failed", "application", pool.appName)
 				if apierrors.HasStatusCause(err, v1.NamespaceTerminatingCause) {
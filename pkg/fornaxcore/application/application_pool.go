@@ -227,7 +227,7 @@ func (pool *ApplicationPool) summarySession() ApplicationSessionSummary {
 			timeoutDuration = time.Duration(s.session.Spec.OpenTimeoutSeconds) * time.Second
 		}
 		pendingTimeoutTimeStamp := time.Now().Add(-1 * timeoutDuration)
-		if s.session.CreationTimestamp.Time.Before(pendingTimeoutTimeStamp) {
+		if sessionPendingSince(s.session).Before(pendingTimeoutTimeStamp) {
 			summary.timeoutCount += 1
 		} else {
 			summary.pendingCount += 1
@@ -240,7 +240,7 @@ func (pool *ApplicationPool) summarySession() ApplicationSessionSummary {
 			timeoutDuration = time.Duration(s.session.Spec.OpenTimeoutSeconds) * time.Second
 		}
 		pendingTimeoutTimeStamp := time.Now().Add(-1 * timeoutDuration)
-		if s.session.CreationTimestamp.Time.Before(pendingTimeoutTimeStamp) {
+		if sessionPendingSince(s.session).Before(pendingTimeoutTimeStamp) {
 			summary.timeoutCount += 1
 		} else {
 			summary.startingCount += 1
@@ -290,6 +290,10 @@ func (pool *ApplicationPool) addSession(sessionId string, session *fornaxv1.Appl
 		if newState != s.state {
 			delete(pool.sessions[s.state], sessionId)
 		}
+		// session moved away from its pod, e.g. it's closed on source pod of a migration
+		if old := s.session.Status.PodReference; old != nil && (session.Status.PodReference == nil || session.Status.PodReference.Name != old.Name) {
+			pool._releasePodSessionNoLock(old.Name, sessionId)
+		}
 	}
 
 	// update pool with new state
@@ -312,25 +316,28 @@ func (pool *ApplicationPool) deleteSession(session *fornaxv1.ApplicationSession)
 func (pool *ApplicationPool) _deleteSessionNoLock(session *fornaxv1.ApplicationSession) {
 	sessionId := string(session.GetUID())
 	if session.Status.PodReference != nil {
-		podName := session.Status.PodReference.Name
-		for _, podsOfState := range pool.podsByState {
-			if pod, found := podsOfState[podName]; found {
-				delete(pod.sessions, sessionId)
-				if len(pod.sessions) == 0 && pod.state == PodStateAllocated {
-					// only allow from allocated => idle when delete a session from this pod, pod is in pending/deleting state should keep its state
-					delete(podsOfState, podName)
-					pod.state = PodStateIdle
-					pool.podsByState[PodStateIdle][podName] = pod
-				}
-				break
-			}
-		}
+		pool._releasePodSessionNoLock(session.Status.PodReference.Name, sessionId)
 	}
 	for _, v := range pool.sessions {
 		delete(v, sessionId)
 	}
 }
 
+func (pool *ApplicationPool) _releasePodSessionNoLock(podName, sessionId string) {
+	for _, podsOfState := range pool.podsByState {
+		if pod, found := podsOfState[podName]; found {
+			delete(pod.sessions, sessionId)
+			if len(pod.sessions) == 0 && pod.state == PodStateAllocated {
+				// only allow from allocated => idle when delete a session from this pod, pod is in pending/deleting state should keep its state
+				delete(podsOfState, podName)
+				pod.state = PodStateIdle
+				pool.podsByState[PodStateIdle][podName] = pod
+			}
+			break
+		}
+	}
+}
+
 // getNonRunningSessions return a list of session of different states,
 // pending, not assigned to pod yet
 // deleting, delete requested
@@ -345,7 +352,7 @@ func (pool *ApplicationPool) getNonRunningSessions() (pendingSessions, deletingS
 			timeoutDuration = time.Duration(s.session.Spec.OpenTimeoutSeconds) * time.Second
 		}
 		pendingTimeoutTimeStamp := time.Now().Add(-1 * timeoutDuration)
-		if s.session.CreationTimestamp.Time.Before(pendingTimeoutTimeStamp) {
+		if sessionPendingSince(s.session).Before(pendingTimeoutTimeStamp) {
 			timeoutSessions = append(timeoutSessions, s)
 		} else {
 			pendingSessions = append(pendingSessions, s)
@@ -358,7 +365,7 @@ func (pool *ApplicationPool) getNonRunningSessions() (pendingSessions, deletingS
 			timeoutDuration = time.Duration(s.session.Spec.OpenTimeoutSeconds) * time.Second
 		}
 		pendingTimeoutTimeStamp := time.Now().Add(-1 * timeoutDuration)
-		if s.session.CreationTimestamp.Time.Before(pendingTimeoutTimeStamp) {
+		if sessionPendingSince(s.session).Before(pendingTimeoutTimeStamp) {
 			timeoutSessions = append(timeoutSessions, s)
 		} else {
 			pendingSessions = append(pendingSessions, s)
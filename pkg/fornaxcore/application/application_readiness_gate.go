@@ -0,0 +1,222 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultReadinessGateTimeout = 1 * time.Second
+	DefaultReadinessGatePeriod  = 10 * time.Second
+)
+
+type readinessGateResult struct {
+	ready     bool
+	err       error
+	expiredAt time.Time
+}
+
+// ApplicationReadinessGateChecker check external dependencies of applications declared in Spec.ReadinessGates,
+// check results are cached for gate period, so, application sync do not call dependencies on every sync,
+// gates are probed in background, application worker only read cached results and is never blocked by a slow dependency
+type ApplicationReadinessGateChecker struct {
+	mu         sync.Mutex
+	results    map[string]*readinessGateResult
+	checking   map[string]bool
+	httpClient *http.Client
+	onChecked  func(applicationKey string)
+}
+
+// NewApplicationReadinessGateChecker return a checker, onChecked is called with application key
+// when a background probe changed readiness of a gate, so application is synced again
+func NewApplicationReadinessGateChecker(onChecked func(applicationKey string)) *ApplicationReadinessGateChecker {
+	return &ApplicationReadinessGateChecker{
+		mu:         sync.Mutex{},
+		results:    map[string]*readinessGateResult{},
+		checking:   map[string]bool{},
+		httpClient: &http.Client{},
+		onChecked:  onChecked,
+	}
+}
+
+// ApplicationReady return nil if all readiness gates of application pass,
+// otherwise return a error of first failed gate,
+// a gate without cached result is not ready until its first probe finish, expired result is still used while it's refreshed
+func (gc *ApplicationReadinessGateChecker) ApplicationReady(application *fornaxv1.Application) error {
+	applicationKey := util.Name(application)
+	var notReady error
+	for i := range application.Spec.ReadinessGates {
+		gate := application.Spec.ReadinessGates[i]
+		key := fmt.Sprintf("%s/%s", applicationKey, gate.Name)
+		result, expired := gc.getCachedResult(key)
+		if result == nil || expired {
+			gc.probeGate(applicationKey, key, &gate)
+		}
+		if notReady != nil {
+			continue
+		}
+		if result == nil {
+			notReady = fmt.Errorf("readiness gate %s of application %s is being checked", gate.Name, applicationKey)
+		} else if !result.ready {
+			notReady = fmt.Errorf("readiness gate %s of application %s is not ready, error: %v", gate.Name, applicationKey, result.err)
+		}
+	}
+	return notReady
+}
+
+// RecheckPeriod return shortest period of application readiness gates, application need to be synced again
+// after this period to pick up refreshed gate results
+func RecheckPeriod(application *fornaxv1.Application) time.Duration {
+	period := time.Duration(0)
+	for _, gate := range application.Spec.ReadinessGates {
+		p := gatePeriod(&gate)
+		if period == 0 || p < period {
+			period = p
+		}
+	}
+	if period == 0 {
+		period = DefaultReadinessGatePeriod
+	}
+	return period
+}
+
+// Forget remove cached results of a application, called when application is deleted
+func (gc *ApplicationReadinessGateChecker) Forget(applicationKey string) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	prefix := applicationKey + "/"
+	for k := range gc.results {
+		if strings.HasPrefix(k, prefix) {
+			delete(gc.results, k)
+		}
+	}
+}
+
+func (gc *ApplicationReadinessGateChecker) getCachedResult(key string) (*readinessGateResult, bool) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	if r, found := gc.results[key]; found {
+		return r, !time.Now().Before(r.expiredAt)
+	}
+	return nil, false
+}
+
+// probeGate check gate in a goroutine, only one probe of a gate run at a time
+func (gc *ApplicationReadinessGateChecker) probeGate(applicationKey, key string, gate *fornaxv1.ApplicationReadinessGate) {
+	gc.mu.Lock()
+	if gc.checking[key] {
+		gc.mu.Unlock()
+		return
+	}
+	gc.checking[key] = true
+	gc.mu.Unlock()
+
+	go func() {
+		result := gc.checkGate(gate)
+		gc.mu.Lock()
+		delete(gc.checking, key)
+		prev, found := gc.results[key]
+		changed := !found || prev.ready != result.ready
+		gc.results[key] = result
+		gc.mu.Unlock()
+		if changed && gc.onChecked != nil {
+			gc.onChecked(applicationKey)
+		}
+	}()
+}
+
+func gatePeriod(gate *fornaxv1.ApplicationReadinessGate) time.Duration {
+	if gate.PeriodSeconds > 0 {
+		return time.Duration(gate.PeriodSeconds) * time.Second
+	}
+	return DefaultReadinessGatePeriod
+}
+
+func (gc *ApplicationReadinessGateChecker) checkGate(gate *fornaxv1.ApplicationReadinessGate) *readinessGateResult {
+	timeout := DefaultReadinessGateTimeout
+	if gate.TimeoutSeconds > 0 {
+		timeout = time.Duration(gate.TimeoutSeconds) * time.Second
+	}
+	period := gatePeriod(gate)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var err error
+	if gate.HTTPGet != nil {
+		err = gc.checkHTTP(ctx, gate.HTTPGet)
+	} else if gate.GRPC != nil {
+		err = gc.checkGRPC(ctx, gate.GRPC)
+	} else {
+		err = fmt.Errorf("readiness gate does not have any check")
+	}
+	if err != nil {
+		klog.ErrorS(err, "Application readiness gate check failed", "gate", gate.Name)
+	}
+
+	return &readinessGateResult{
+		ready:     err == nil,
+		err:       err,
+		expiredAt: time.Now().Add(period),
+	}
+}
+
+func (gc *ApplicationReadinessGateChecker) checkHTTP(ctx context.Context, check *fornaxv1.HTTPReadinessCheck) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, check.URL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := gc.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("http check returned status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (gc *ApplicationReadinessGateChecker) checkGRPC(ctx context.Context, check *fornaxv1.GRPCReadinessCheck) error {
+	conn, err := grpc.DialContext(ctx, check.Address, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: check.Service})
+	if err != nil {
+		return err
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("grpc check returned status %s", resp.GetStatus())
+	}
+	return nil
+}
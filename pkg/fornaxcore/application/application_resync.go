@@ -0,0 +1,186 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	storefactory "centaurusinfra.io/fornax-serverless/pkg/store/factory"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// applications and their sessions are compared with store in this period, it fix drift caused by missed watch events
+	DefaultApplicationResyncPeriod = 10 * time.Minute
+
+	ApplicationReconcilePath = "/fornaxcore/application/reconcile"
+)
+
+var (
+	applicationResyncSessions = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Name:           "fornax_application_resync_sessions_total",
+			Help:           "Number of sessions fixed by application resync, by action of added, updated or removed",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"action"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(applicationResyncSessions)
+}
+
+// ReconcileResult is sessions in application pool fixed by a reconcile
+type ReconcileResult struct {
+	Application string `json:"application"`
+	Added       int    `json:"added"`
+	Updated     int    `json:"updated"`
+	Removed     int    `json:"removed"`
+}
+
+// newerResourceVersion tell if store copy is newer than pooled copy, a unparsable version is considered newer
+func newerResourceVersion(storeVersion, poolVersion string) bool {
+	s, err1 := strconv.ParseUint(storeVersion, 10, 64)
+	p, err2 := strconv.ParseUint(poolVersion, 10, 64)
+	if err1 != nil || err2 != nil {
+		return storeVersion != poolVersion
+	}
+	return s > p
+}
+
+// reconcileApplicationSessions replay missed session events by comparing sessions in application pool with session store,
+// session not in pool is added, session newer in store is updated, session not in store anymore is deleted
+func (am *ApplicationManager) reconcileApplicationSessions(applicationKey string) (*ReconcileResult, error) {
+	result := &ReconcileResult{Application: applicationKey}
+	sessions, err := am.sessionManager.ListApplicationSessions(applicationKey)
+	if err != nil {
+		return nil, err
+	}
+	pool := am.getOrCreateApplicationPool(applicationKey)
+	stored := map[string]bool{}
+	for _, v := range sessions {
+		session := v.DeepCopy()
+		stored[string(session.GetUID())] = true
+		if pooled := pool.getSession(string(session.GetUID())); pooled == nil {
+			if util.SessionInTerminalState(session) {
+				continue
+			}
+			klog.InfoS("Resync found session missing in application pool", "application", applicationKey, "session", util.Name(session))
+			am.onApplicationSessionAddEvent(session)
+			result.Added += 1
+		} else if newerResourceVersion(session.ResourceVersion, pooled.session.ResourceVersion) {
+			klog.InfoS("Resync found stale session in application pool", "application", applicationKey, "session", util.Name(session), "pool-version", pooled.session.ResourceVersion, "store-version", session.ResourceVersion)
+			am.onApplicationSessionUpdateEvent(pooled.session, session)
+			result.Updated += 1
+		}
+	}
+	for _, v := range pool.sessionList() {
+		if !stored[string(v.session.GetUID())] {
+			klog.InfoS("Resync found session deleted from store", "application", applicationKey, "session", util.Name(v.session))
+			am.onApplicationSessionDeleteEvent(v.session)
+			result.Removed += 1
+		}
+	}
+	applicationResyncSessions.WithLabelValues("added").Add(float64(result.Added))
+	applicationResyncSessions.WithLabelValues("updated").Add(float64(result.Updated))
+	applicationResyncSessions.WithLabelValues("removed").Add(float64(result.Removed))
+	return result, nil
+}
+
+// ReconcileApplication force a full reconcile of a application, its sessions are resynced with store and it's synced immediately
+func (am *ApplicationManager) ReconcileApplication(applicationKey string) (*ReconcileResult, error) {
+	application, err := storefactory.GetApplicationCache(am.applicationStore, applicationKey)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	if application == nil && am.getApplicationPool(applicationKey) == nil {
+		return nil, apierrors.NewNotFound(fornaxv1.ApplicationGrv.GroupResource(), applicationKey)
+	}
+	result, err := am.reconcileApplicationSessions(applicationKey)
+	if err != nil {
+		return nil, err
+	}
+	klog.InfoS("Reconcile application", "application", applicationKey, "result", result)
+	am.enqueueApplication(applicationKey)
+	return result, nil
+}
+
+// resyncApplications reconcile every application in store, and sync pools of applications not in store anymore to clean them up
+func (am *ApplicationManager) resyncApplications() {
+	klog.Info("Resync applications")
+	list := &fornaxv1.ApplicationList{}
+	if err := am.applicationStore.GetList(am.ctx, fornaxv1.ApplicationGrvKey, apistorage.ListOptions{Predicate: apistorage.Everything, Recursive: true}, list); err != nil {
+		klog.ErrorS(err, "Failed to list applications to resync")
+		return
+	}
+	applications := map[string]bool{}
+	for i := range list.Items {
+		applicationKey := util.Name(&list.Items[i])
+		applications[applicationKey] = true
+		if _, err := am.reconcileApplicationSessions(applicationKey); err != nil {
+			klog.ErrorS(err, "Failed to resync application sessions", "application", applicationKey)
+		}
+		am.enqueueApplication(applicationKey)
+	}
+	for applicationKey := range am.applicationList() {
+		if !applications[applicationKey] {
+			am.enqueueApplication(applicationKey)
+		}
+	}
+}
+
+// ApplicationReconcileHandler force reconcile a application, e.g. kubectl create --raw "/fornaxcore/application/reconcile?application=<namespace>/<name>" -f /dev/null
+type ApplicationReconcileHandler struct {
+	am *ApplicationManager
+}
+
+func NewApplicationReconcileHandler(am *ApplicationManager) *ApplicationReconcileHandler {
+	return &ApplicationReconcileHandler{am: am}
+}
+
+func (h *ApplicationReconcileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	applicationKey := r.URL.Query().Get("application")
+	if len(applicationKey) == 0 {
+		http.Error(w, "application is required", http.StatusBadRequest)
+		return
+	}
+	result, err := h.am.ReconcileApplication(applicationKey)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"fmt"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultRolloutBakeSeconds = 300
+	DefaultRolloutMinSessions = 5
+
+	// rollout failures are kept in status history, only last ones are kept to keep status small
+	MaxRolloutHistory = 10
+)
+
+var (
+	rolloutFailures = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_application_rollout",
+			Name:           "failures_total",
+			Help:           "Number of rollouts failed health check of rollout policy, by action taken",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"application", "action"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(rolloutFailures)
+}
+
+// rolloutHealth count sessions and failures of pods of a container spec, it's reset when spec change,
+// and seeded from rollout status after fornaxcore restart
+type rolloutHealth struct {
+	templateHash   string
+	sessions       int32
+	failedSessions int32
+	failedPods     int32
+
+	// container spec before last spec change, captured from application update event
+	previousHash       string
+	previousContainers []v1.Container
+}
+
+func (pool *ApplicationPool) setPreviousContainers(templateHash string, containers []v1.Container) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	pool.rolloutHealth.previousHash = templateHash
+	pool.rolloutHealth.previousContainers = containers
+}
+
+func (pool *ApplicationPool) getPreviousContainers(templateHash string) []v1.Container {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+	if pool.rolloutHealth.previousHash != templateHash {
+		return nil
+	}
+	return pool.rolloutHealth.previousContainers
+}
+
+// syncRolloutHealth start counting for rollout, return counts of it
+func (pool *ApplicationPool) syncRolloutHealth(rollout *fornaxv1.ApplicationRolloutStatus) (int32, int32, int32) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	health := &pool.rolloutHealth
+	if health.templateHash != rollout.TemplateHash {
+		health.templateHash = rollout.TemplateHash
+		health.sessions, health.failedSessions, health.failedPods = rollout.Sessions, rollout.FailedSessions, rollout.FailedPods
+	}
+	return health.sessions, health.failedSessions, health.failedPods
+}
+
+func (pool *ApplicationPool) recordRolloutHealth(templateHash string, sessions, failedSessions, failedPods int32) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	health := &pool.rolloutHealth
+	if len(templateHash) == 0 || health.templateHash != templateHash {
+		return
+	}
+	health.sessions += sessions
+	health.failedSessions += failedSessions
+	health.failedPods += failedPods
+}
+
+// observeRolloutSession count a session bound to a pod when it leave starting state, and count it as failed
+// if it failed or timed out, sessions of pods without template hash label are not counted
+func (am *ApplicationManager) observeRolloutSession(pool *ApplicationPool, oldSession, newSession *fornaxv1.ApplicationSession) {
+	if newSession.Status.PodReference == nil {
+		return
+	}
+	oldStatus, newStatus := oldSession.Status.SessionStatus, newSession.Status.SessionStatus
+	started, failed := int32(0), int32(0)
+	if oldStatus == fornaxv1.SessionStatusStarting && newStatus != fornaxv1.SessionStatusStarting {
+		started = 1
+	}
+	if (newStatus == fornaxv1.SessionStatusFailed || newStatus == fornaxv1.SessionStatusTimeout) && oldStatus != newStatus {
+		failed = 1
+	}
+	if started == 0 && failed == 0 {
+		return
+	}
+	pod := am.podManager.FindPod(newSession.Status.PodReference.Name)
+	if pod == nil {
+		return
+	}
+	pool.recordRolloutHealth(pod.GetLabels()[fornaxv1.LabelFornaxCoreTemplateHash], started, failed, 0)
+}
+
+// observeRolloutPodTermination count a pod terminated without being deleted by fornaxcore, e.g. crashed or failed to start
+func (am *ApplicationManager) observeRolloutPodTermination(pool *ApplicationPool, pod *v1.Pod) {
+	ap := pool.getPod(util.Name(pod))
+	if ap == nil || ap.state == PodStateDeleting {
+		return
+	}
+	pool.recordRolloutHealth(pod.GetLabels()[fornaxv1.LabelFornaxCoreTemplateHash], 0, 0, 1)
+}
+
+// rolloutFailure check health of new pods in bake window against rollout policy, return why rollout failed, empty if it did not fail,
+// a rollback rollout is not checked to avoid rolling back and forth
+func rolloutFailure(policy *fornaxv1.ApplicationRolloutPolicy, rollout *fornaxv1.ApplicationRolloutStatus) string {
+	if policy == nil || len(rollout.RollbackOf) > 0 || rollout.StartTime == nil {
+		return ""
+	}
+	bake := time.Duration(policy.BakeSeconds) * time.Second
+	if policy.BakeSeconds == 0 {
+		bake = DefaultRolloutBakeSeconds * time.Second
+	}
+	if time.Since(rollout.StartTime.Time) > bake {
+		return ""
+	}
+	if policy.MaxPodFailures > 0 && rollout.FailedPods > policy.MaxPodFailures {
+		return fmt.Sprintf("%d new pods failed, more than %d", rollout.FailedPods, policy.MaxPodFailures)
+	}
+	minSessions := policy.MinSessions
+	if minSessions == 0 {
+		minSessions = DefaultRolloutMinSessions
+	}
+	if policy.MaxSessionFailurePercent > 0 && rollout.Sessions >= minSessions && rollout.FailedSessions*100 > policy.MaxSessionFailurePercent*rollout.Sessions {
+		return fmt.Sprintf("%d of %d sessions on new pods failed, more than %d percent", rollout.FailedSessions, rollout.Sessions, policy.MaxSessionFailurePercent)
+	}
+	return ""
+}
+
+// rollbackApplication restore container spec of last revision, a new rollout replace pods of failed revision
+func (am *ApplicationManager) rollbackApplication(application *fornaxv1.Application, containers []v1.Container) error {
+	updatedApplication := application.DeepCopy()
+	updatedApplication.Spec.Containers = containers
+	key := fmt.Sprintf("%s/%s", fornaxv1.ApplicationGrvKey, util.Name(application))
+	return am.applicationStore.EnsureUpdateAndDelete(am.ctx, key, true, nil, updatedApplication, &fornaxv1.Application{})
+}
+
+// checkRolloutHealth update health counts of rollout, pause or roll back rollout if it fail health check of rollout policy
+func (am *ApplicationManager) checkRolloutHealth(pool *ApplicationPool, application *fornaxv1.Application, rollout *fornaxv1.ApplicationRolloutStatus) {
+	rollout.Sessions, rollout.FailedSessions, rollout.FailedPods = pool.syncRolloutHealth(rollout)
+	if rollout.Phase == fornaxv1.RolloutPhasePaused || rollout.Phase == fornaxv1.RolloutPhaseRolledBack {
+		return
+	}
+	reason := rolloutFailure(application.Spec.RolloutPolicy, rollout)
+	if len(reason) == 0 {
+		return
+	}
+
+	action := application.Spec.RolloutPolicy.FailureAction
+	if action == fornaxv1.RolloutFailureActionRollback {
+		if len(rollout.PreviousContainers) == 0 {
+			reason = fmt.Sprintf("%s, container spec of last revision is unknown, pause rollout", reason)
+			action = fornaxv1.RolloutFailureActionPause
+		} else if err := am.rollbackApplication(application, rollout.PreviousContainers); err != nil {
+			klog.ErrorS(err, "Failed to roll back application", "application", pool.appName)
+			reason = fmt.Sprintf("%s, roll back failed: %v, pause rollout", reason, err)
+			action = fornaxv1.RolloutFailureActionPause
+		}
+	}
+	if action == fornaxv1.RolloutFailureActionRollback {
+		rollout.Phase = fornaxv1.RolloutPhaseRolledBack
+	} else {
+		action = fornaxv1.RolloutFailureActionPause
+		rollout.Phase = fornaxv1.RolloutPhasePaused
+	}
+	rollout.Message = reason
+	rollout.CompletionTime = util.NewCurrentMetaTime()
+	klog.InfoS("Application rollout failed health check", "application", pool.appName, "hash", rollout.TemplateHash, "action", action, "reason", reason)
+	rolloutFailures.WithLabelValues(pool.appName, string(action)).Inc()
+}
+
+// rolloutPaused tell if application is serving sessions with old pods after its rollout was paused
+func rolloutPaused(application *fornaxv1.Application, templateHash string) bool {
+	rollout := application.Status.Rollout
+	return rollout != nil && rollout.Phase == fornaxv1.RolloutPhasePaused && rollout.TemplateHash == templateHash
+}
+
+// appendRolloutHistory add a history entry when rollout is paused or rolled back, only last MaxRolloutHistory entries are kept
+func appendRolloutHistory(oldRollout, rollout *fornaxv1.ApplicationRolloutStatus, history []fornaxv1.DeploymentHistory) []fornaxv1.DeploymentHistory {
+	if oldRollout != nil && oldRollout.TemplateHash == rollout.TemplateHash && oldRollout.Phase == rollout.Phase {
+		return history
+	}
+	var action fornaxv1.DeploymentAction
+	switch rollout.Phase {
+	case fornaxv1.RolloutPhasePaused:
+		action = fornaxv1.DeploymentActionPauseRollout
+	case fornaxv1.RolloutPhaseRolledBack:
+		action = fornaxv1.DeploymentActionRollbackRollout
+	default:
+		return history
+	}
+	history = append(history, fornaxv1.DeploymentHistory{
+		Action:     action,
+		UpdateTime: metav1.Time{Time: time.Now()},
+		Reason:     "rollout failed health check",
+		Message:    fmt.Sprintf("revision %s: %s", rollout.TemplateHash, rollout.Message),
+	})
+	if len(history) > MaxRolloutHistory {
+		history = history[len(history)-MaxRolloutHistory:]
+	}
+	return history
+}
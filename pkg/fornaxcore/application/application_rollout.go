@@ -0,0 +1,209 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// recheck draining pods at least this often, so sessions are closed soon after their close grace period
+	DefaultRolloutRecheckDuration = 10 * time.Second
+)
+
+// podTemplateHash return hash of application container spec, pods are labeled with it, a rollout start when it change
+func podTemplateHash(application *fornaxv1.Application) string {
+	hasher := fnv.New32a()
+	data, _ := json.Marshal(application.Spec.Containers)
+	hasher.Write(data)
+	// pods are replaced when sysctls change too, hash of applications without sysctls does not change
+	if len(application.Spec.Sysctls) > 0 {
+		data, _ = json.Marshal(application.Spec.Sysctls)
+		hasher.Write(data)
+	}
+	// so are process limits, they are set when pod cgroup and containers are created
+	if application.Spec.ProcessLimits != nil {
+		data, _ = json.Marshal(application.Spec.ProcessLimits)
+		hasher.Write(data)
+	}
+	return fmt.Sprintf("%x", hasher.Sum32())
+}
+
+// podOutdated tell if pod was created from a old container spec, pods created before template hash label are considered current
+func podOutdated(pod *v1.Pod, templateHash string) bool {
+	hash, found := pod.GetLabels()[fornaxv1.LabelFornaxCoreTemplateHash]
+	return found && hash != templateHash
+}
+
+// rolloutDrainStarted return when pod was drained by rollout, it's recorded as now if pod was not drained yet
+func (pool *ApplicationPool) rolloutDrainStarted(podName string) (time.Time, bool) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	if t, found := pool.rolloutDrainTime[podName]; found {
+		return t, true
+	}
+	t := time.Now()
+	pool.rolloutDrainTime[podName] = t
+	return t, false
+}
+
+// forgetRolloutDrains delete drain time of pods which are not draining anymore
+func (pool *ApplicationPool) forgetRolloutDrains(draining map[string]bool) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	for k := range pool.rolloutDrainTime {
+		if !draining[k] {
+			delete(pool.rolloutDrainTime, k)
+		}
+	}
+}
+
+func (pool *ApplicationPool) rolloutDraining() bool {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+	return len(pool.rolloutDrainTime) > 0
+}
+
+// drainOutdatedPod drain sessions of a outdated pod once, sessions still open after their close grace period are closed,
+// return number of open sessions and how long until next session should be closed
+func (am *ApplicationManager) drainOutdatedPod(pool *ApplicationPool, pod *v1.Pod) (int, time.Duration) {
+	podName := util.Name(pod)
+	drainTime, found := pool.rolloutDrainStarted(podName)
+	if !found {
+		klog.InfoS("Drain sessions on pod of old container spec", "application", pool.appName, "pod", podName)
+		am.drainPodSessions(pool, podName, SessionDrainReasonApplicationRollout)
+	}
+	openSessions, nextClose := 0, DefaultRolloutRecheckDuration
+	for _, s := range pool.getPodSessions(podName) {
+		if !util.SessionIsOpen(s.session) {
+			continue
+		}
+		openSessions += 1
+		if util.SessionIsClosing(s.session) {
+			continue
+		}
+		if remaining := sessionCloseGracePeriod(s.session) - time.Since(drainTime); remaining > 0 {
+			if remaining < nextClose {
+				nextClose = remaining
+			}
+			continue
+		}
+		klog.InfoS("Close drained session after close grace period", "application", pool.appName, "pod", podName, "session", util.Name(s.session), "requestId", util.RequestId(s.session))
+		am.changeSessionStatus(s.session, fornaxv1.SessionStatusClosing)
+		if err := am.sessionManager.CloseSession(pod, s.session); err != nil {
+			klog.ErrorS(err, "Failed to close drained session", "application", pool.appName, "pod", podName, "session", util.Name(s.session), "requestId", util.RequestId(s.session))
+		}
+	}
+	return openSessions, nextClose
+}
+
+// rolloutApplication replace pods created from a old container spec, pending and idle outdated pods are deleted,
+// so new sessions only go to pods of current spec, sessions on occupied outdated pods are drained and closed after close grace period,
+// outdated pods become idle when their sessions closed, and are deleted in next sync,
+// rollout stop replacing pods when new pods fail health check of rollout policy
+func (am *ApplicationManager) rolloutApplication(pool *ApplicationPool, application *fornaxv1.Application) *fornaxv1.ApplicationRolloutStatus {
+	templateHash := podTemplateHash(application)
+	rollout := application.Status.Rollout.DeepCopy()
+	if rollout == nil || rollout.TemplateHash != templateHash {
+		previous := rollout
+		rollout = &fornaxv1.ApplicationRolloutStatus{
+			TemplateHash: templateHash,
+			Phase:        fornaxv1.RolloutPhaseProgressing,
+			StartTime:    util.NewCurrentMetaTime(),
+		}
+		if previous != nil {
+			klog.InfoS("Application container spec changed, start rollout", "application", pool.appName, "old-hash", previous.TemplateHash, "new-hash", templateHash)
+			rollout.PreviousTemplateHash = previous.TemplateHash
+			rollout.PreviousContainers = pool.getPreviousContainers(previous.TemplateHash)
+			if previous.Phase == fornaxv1.RolloutPhaseRolledBack && previous.PreviousTemplateHash == templateHash {
+				rollout.RollbackOf = previous.TemplateHash
+			}
+		}
+	}
+	am.checkRolloutHealth(pool, application, rollout)
+
+	// a paused or rolled back rollout keep old pods
+	replacing := rollout.Phase != fornaxv1.RolloutPhasePaused && rollout.Phase != fornaxv1.RolloutPhaseRolledBack
+	updatedPods, outdatedPods, drainingSessions := 0, 0, 0
+	for _, state := range []ApplicationPodState{PodStatePending, PodStateIdle} {
+		for _, ap := range pool.podListOfState(state) {
+			pod := am.podManager.FindPod(ap.podName)
+			if pod == nil {
+				continue
+			}
+			if !podOutdated(pod, templateHash) {
+				updatedPods += 1
+				continue
+			}
+			if replacing {
+				klog.InfoS("Delete unoccupied pod of old container spec", "application", pool.appName, "pod", ap.podName)
+				am.deleteApplicationPod(pool, ap.podName)
+			}
+		}
+	}
+
+	draining := map[string]bool{}
+	recheck := DefaultRolloutRecheckDuration
+	for _, ap := range pool.podListOfState(PodStateAllocated) {
+		pod := am.podManager.FindPod(ap.podName)
+		if pod == nil {
+			continue
+		}
+		if !podOutdated(pod, templateHash) {
+			updatedPods += 1
+			continue
+		}
+		outdatedPods += 1
+		if !replacing {
+			continue
+		}
+		draining[ap.podName] = true
+		openSessions, nextClose := am.drainOutdatedPod(pool, pod)
+		drainingSessions += openSessions
+		if nextClose < recheck {
+			recheck = nextClose
+		}
+	}
+	pool.forgetRolloutDrains(draining)
+	if len(draining) > 0 {
+		am.applicationQueue.EnqueueAfter(pool.appName, recheck)
+	}
+
+	rollout.UpdatedInstances = int32(updatedPods)
+	rollout.OutdatedInstances = int32(outdatedPods)
+	rollout.DrainingSessions = int32(drainingSessions)
+	if !replacing {
+		return rollout
+	}
+	if outdatedPods > 0 {
+		rollout.Phase = fornaxv1.RolloutPhaseProgressing
+		rollout.CompletionTime = nil
+	} else if rollout.Phase != fornaxv1.RolloutPhaseComplete {
+		klog.InfoS("Application rollout complete", "application", pool.appName, "hash", templateHash, "updated-pods", updatedPods)
+		rollout.Phase = fornaxv1.RolloutPhaseComplete
+		rollout.CompletionTime = util.NewCurrentMetaTime()
+	}
+	return rollout
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/pki"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultSecretRevokeGracePeriod = 300 * time.Second
+
+	// same secret versions are sent to a pod again if pod does not report them back in this period, e.g. message lost or node agent restarted
+	DefaultSecretResendPeriod = 60 * time.Second
+)
+
+var (
+	SecretKeyNotAvailableError = errors.New("secret key is not available to open sealed secret data")
+)
+
+// secretDelivery is secret versions sent to a pod and when
+type secretDelivery struct {
+	version         string
+	previousVersion string
+	sentAt          time.Time
+}
+
+type secretRotation struct {
+	previous  *fornaxv1.ApplicationSecret
+	rotatedAt time.Time
+	revokeAt  time.Time
+}
+
+// ApplicationSecretRotator track secret rotations of applications, previous secret version of a application is kept until grace period passed,
+// rotation state is in memory, if fornaxcore restart during a rotation, previous version is revoked on next sync
+type ApplicationSecretRotator struct {
+	mu        sync.Mutex
+	rotations map[string]*secretRotation
+}
+
+func NewApplicationSecretRotator() *ApplicationSecretRotator {
+	return &ApplicationSecretRotator{
+		mu:        sync.Mutex{},
+		rotations: map[string]*secretRotation{},
+	}
+}
+
+func (r *ApplicationSecretRotator) startRotation(applicationKey string, previous *fornaxv1.ApplicationSecret, gracePeriod time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	r.rotations[applicationKey] = &secretRotation{
+		previous:  previous,
+		rotatedAt: now,
+		revokeAt:  now.Add(gracePeriod),
+	}
+}
+
+// getRotation return rotation of application and remove it if grace period passed
+func (r *ApplicationSecretRotator) getRotation(applicationKey string) (rotation *secretRotation, expired bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rotation, found := r.rotations[applicationKey]
+	if !found {
+		return nil, false
+	}
+	if time.Now().After(rotation.revokeAt) {
+		delete(r.rotations, applicationKey)
+		return rotation, true
+	}
+	return rotation, false
+}
+
+// Forget remove rotation of a application, called when application is deleted
+func (r *ApplicationSecretRotator) Forget(applicationKey string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.rotations, applicationKey)
+}
+
+func (pool *ApplicationPool) getSecretDeliveries() map[string]*secretDelivery {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+	return pool.secretDeliveries
+}
+
+// setSecretDeliveries replace deliveries, pods which have picked up secret or gone are not in new deliveries
+func (pool *ApplicationPool) setSecretDeliveries(deliveries map[string]*secretDelivery) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	pool.secretDeliveries = deliveries
+}
+
+// SetSecretBox let application manager open sealed secret data before secret is sent to pods
+func (am *ApplicationManager) SetSecretBox(secretBox *pki.SecretBox) {
+	am.secretBox = secretBox
+}
+
+// openSecret return a copy of secret with plain data, sealed data is only opened when secret is sent to pods,
+// data already in secret, e.g. application certificate, is kept
+func (am *ApplicationManager) openSecret(secret *fornaxv1.ApplicationSecret) (*fornaxv1.ApplicationSecret, error) {
+	if secret == nil || len(secret.EncryptedData) == 0 {
+		return secret, nil
+	}
+	if am.secretBox == nil {
+		return nil, SecretKeyNotAvailableError
+	}
+	data, err := fornaxv1.OpenSecretData(secret, am.secretBox.Open)
+	if err != nil {
+		return nil, err
+	}
+	opened := secret.DeepCopy()
+	opened.EncryptedData = nil
+	if opened.Data == nil {
+		opened.Data = map[string][]byte{}
+	}
+	for k, v := range data {
+		if _, found := opened.Data[k]; !found {
+			opened.Data[k] = v
+		}
+	}
+	return opened, nil
+}
+
+func secretRevokeGracePeriod(secret *fornaxv1.ApplicationSecret) time.Duration {
+	if secret.RevokeGracePeriodSeconds > 0 {
+		return time.Duration(secret.RevokeGracePeriodSeconds) * time.Second
+	}
+	return DefaultSecretRevokeGracePeriod
+}
+
+// onApplicationSecretChange start a rotation when secret version changed, previous version is kept valid on pods for grace period,
+// new version is pushed to pods in application sync
+func (am *ApplicationManager) onApplicationSecretChange(oldApplication, newApplication *fornaxv1.Application) {
+	applicationKey := util.Name(newApplication)
+	if newApplication.Spec.Secret == nil {
+		am.secretRotator.Forget(applicationKey)
+		return
+	}
+	if oldApplication.Spec.Secret != nil && oldApplication.Spec.Secret.Version == newApplication.Spec.Secret.Version {
+		return
+	}
+
+	gracePeriod := secretRevokeGracePeriod(newApplication.Spec.Secret)
+	klog.InfoS("Application secret rotated", "app", applicationKey, "version", newApplication.Spec.Secret.Version, "grace period", gracePeriod)
+	previous := secretWithCertificate(oldApplication.Spec.Secret.DeepCopy(), am.certificates.get(applicationKey))
+	am.secretRotator.startRotation(applicationKey, previous, gracePeriod)
+	am.enqueueApplication(applicationKey)
+	// sync again when grace period passed to revoke previous version
+	am.applicationQueue.EnqueueAfter(applicationKey, gracePeriod)
+}
+
+// syncApplicationSecret push current and previous secret to running pods which do not have them yet, previous secret is revoked after grace period,
+// pod report back secret versions in annotations, return secret status of application
+func (am *ApplicationManager) syncApplicationSecret(pool *ApplicationPool, application *fornaxv1.Application, secret *fornaxv1.ApplicationSecret) *fornaxv1.ApplicationSecretStatus {
+	if secret == nil {
+		return nil
+	}
+
+	applicationKey := util.Name(application)
+	rotation, expired := am.secretRotator.getRotation(applicationKey)
+	var previous *fornaxv1.ApplicationSecret
+	if rotation != nil && !expired {
+		previous = rotation.previous
+	}
+	if expired && rotation.previous != nil {
+		klog.InfoS("Revoke previous application secret", "app", applicationKey, "version", rotation.previous.Version)
+	}
+
+	status := &fornaxv1.ApplicationSecretStatus{
+		CurrentVersion:    secret.Version,
+		OutdatedInstances: []string{},
+	}
+	if previous != nil {
+		status.PreviousVersion = previous.Version
+	}
+	if rotation != nil {
+		status.RotationTime = &metav1.Time{Time: rotation.rotatedAt}
+	} else if application.Status.SecretStatus != nil && application.Status.SecretStatus.CurrentVersion == secret.Version {
+		status.RotationTime = application.Status.SecretStatus.RotationTime.DeepCopy()
+	}
+
+	lastDeliveries := pool.getSecretDeliveries()
+	deliveries := map[string]*secretDelivery{}
+	var opened, openedPrevious *fornaxv1.ApplicationSecret
+	var openErr error
+	sent := false
+	for _, state := range []ApplicationPodState{PodStateIdle, PodStateAllocated} {
+		for _, ap := range pool.podListOfState(state) {
+			pod := am.podManager.FindPod(ap.podName)
+			if pod == nil || !util.PodIsRunning(pod) {
+				continue
+			}
+			annotations := pod.GetAnnotations()
+			if annotations[fornaxv1.AnnotationFornaxCoreSecretVersion] == secret.Version {
+				status.UpdatedInstances += 1
+			} else {
+				status.OutdatedInstances = append(status.OutdatedInstances, ap.podName)
+			}
+			if annotations[fornaxv1.AnnotationFornaxCoreSecretVersion] == secret.Version && annotations[fornaxv1.AnnotationFornaxCorePrevSecretVersion] == status.PreviousVersion {
+				continue
+			}
+
+			// only send when versions changed since last delivery, or pod did not pick them up in resend period
+			delivery := lastDeliveries[ap.podName]
+			if delivery != nil && delivery.version == secret.Version && delivery.previousVersion == status.PreviousVersion && time.Since(delivery.sentAt) < DefaultSecretResendPeriod {
+				deliveries[ap.podName] = delivery
+				continue
+			}
+			if opened == nil && openErr == nil {
+				if opened, openErr = am.openSecret(secret); openErr == nil {
+					openedPrevious, openErr = am.openSecret(previous)
+				}
+				if openErr != nil {
+					klog.ErrorS(openErr, "Failed to open application secret", "app", applicationKey)
+				}
+			}
+			if openErr != nil {
+				continue
+			}
+			if err := am.podManager.UpdatePodSecret(ap.podName, opened, openedPrevious); err != nil {
+				klog.ErrorS(err, "Failed to update pod secret", "app", applicationKey, "pod", ap.podName)
+				continue
+			}
+			deliveries[ap.podName] = &secretDelivery{version: secret.Version, previousVersion: status.PreviousVersion, sentAt: time.Now()}
+			sent = true
+		}
+	}
+	pool.setSecretDeliveries(deliveries)
+	if sent {
+		// check again if pods do not report new versions back
+		am.applicationQueue.EnqueueAfter(applicationKey, DefaultSecretResendPeriod)
+	}
+	sort.Strings(status.OutdatedInstances)
+
+	return status
+}
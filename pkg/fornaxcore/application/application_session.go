@@ -23,7 +23,10 @@ import (
 	"time"
 
 	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/event"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/placement"
 	fornaxstore "centaurusinfra.io/fornax-serverless/pkg/store"
+	storefactory "centaurusinfra.io/fornax-serverless/pkg/store/factory"
 	"centaurusinfra.io/fornax-serverless/pkg/util"
 
 	v1 "k8s.io/api/core/v1"
@@ -74,8 +77,15 @@ func (am *ApplicationManager) onSessionEventFromStorage(we fornaxstore.WatchEven
 func (am *ApplicationManager) changeSessionStatus(session *fornaxv1.ApplicationSession, status fornaxv1.SessionStatus) error {
 	newStatus := session.Status.DeepCopy()
 	newStatus.SessionStatus = status
-	if status == fornaxv1.SessionStatusClosed || status == fornaxv1.SessionStatusTimeout {
-		newStatus.ClientSessions = []v1.LocalObjectReference{}
+	if status == fornaxv1.SessionStatusClosed || status == fornaxv1.SessionStatusTimeout || status == fornaxv1.SessionStatusFailed {
+		// clients still attached are left when session is terminated
+		now := util.NewCurrentMetaTimeNormallized()
+		for i := range newStatus.ClientSessions {
+			if newStatus.ClientSessions[i].LeaveTime == nil {
+				newStatus.ClientSessions[i].LeaveTime = now
+			}
+		}
+		newStatus.ClientSessionCount = 0
 	}
 	// set local copy status then update store
 	session.Status = *newStatus
@@ -94,7 +104,9 @@ func (am *ApplicationManager) onApplicationSessionAddEvent(obj interface{}) {
 	applicationKey := getSessionApplicationKey(session)
 	pool := am.getOrCreateApplicationPool(applicationKey)
 
-	klog.InfoS("Application session created", "session", util.Name(session))
+	klog.InfoS("Application session created", "session", util.Name(session), "requestId", util.RequestId(session))
+	am.syncSessionProbe(session)
+	am.syncSessionGateway(session)
 	if v := pool.getSession(string(session.GetUID())); v != nil {
 		am.onApplicationSessionUpdateEvent(v.session, session)
 		return
@@ -112,10 +124,14 @@ func (am *ApplicationManager) onApplicationSessionAddEvent(obj interface{}) {
 func (am *ApplicationManager) onApplicationSessionUpdateEvent(old, cur interface{}) {
 	oldCopy := old.(*fornaxv1.ApplicationSession)
 	newCopy := cur.(*fornaxv1.ApplicationSession)
-	klog.InfoS("Application session updated", "session", util.Name(newCopy), "old status", oldCopy.Status.SessionStatus, "new status", newCopy.Status.SessionStatus, "deleting", newCopy.DeletionTimestamp != nil)
+	klog.InfoS("Application session updated", "session", util.Name(newCopy), "requestId", util.RequestId(newCopy), "old status", oldCopy.Status.SessionStatus, "new status", newCopy.Status.SessionStatus, "deleting", newCopy.DeletionTimestamp != nil)
 
 	applicationKey := getSessionApplicationKey(newCopy)
 	pool := am.getOrCreateApplicationPool(applicationKey)
+	am.syncSessionProbe(newCopy)
+	am.syncSessionGateway(newCopy)
+	am.observeSessionAllocation(oldCopy, newCopy)
+	am.observeRolloutSession(pool, oldCopy, newCopy)
 
 	if v := pool.getSession(string(newCopy.GetUID())); v != nil {
 		updateSessionPool(pool, newCopy)
@@ -137,7 +153,11 @@ func (am *ApplicationManager) onApplicationSessionDeleteEvent(obj interface{}) {
 		klog.Errorf("Received a unknown runtime object", obj)
 	}
 
-	klog.InfoS("Application session deleted", "session", util.Name(session), "status", session.Status)
+	klog.InfoS("Application session deleted", "session", util.Name(session), "requestId", util.RequestId(session), "status", session.Status)
+	am.sessionProberPool.RemoveSession(util.Name(session))
+	if am.sessionGateway != nil {
+		am.sessionGateway.Unroute(util.Name(session))
+	}
 	applicationKey := getSessionApplicationKey(session)
 	pool := am.getApplicationPool(applicationKey)
 	if pool == nil {
@@ -153,14 +173,49 @@ func (am *ApplicationManager) onApplicationSessionDeleteEvent(obj interface{}) {
 	am.enqueueApplication(applicationKey)
 }
 
+// syncSessionProbe add a open session into prober pool if its application has session health check,
+// and remove session from prober pool when session is closing or closed
+func (am *ApplicationManager) syncSessionProbe(session *fornaxv1.ApplicationSession) {
+	if session.DeletionTimestamp == nil &&
+		(session.Status.SessionStatus == fornaxv1.SessionStatusAvailable || session.Status.SessionStatus == fornaxv1.SessionStatusInUse) {
+		application, err := storefactory.GetApplicationCache(am.applicationStore, getSessionApplicationKey(session))
+		if err == nil && application != nil && application.Spec.SessionHealthCheck != nil {
+			am.sessionProberPool.AddSession(session, application.Spec.SessionHealthCheck)
+			return
+		}
+	}
+	am.sessionProberPool.RemoveSession(util.Name(session))
+}
+
+// onSessionHealthChange is called by prober pool to save session health status
+func (am *ApplicationManager) onSessionHealthChange(session *fornaxv1.ApplicationSession, status fornaxv1.SessionHealthStatus) {
+	pool := am.getApplicationPool(getSessionApplicationKey(session))
+	if pool == nil {
+		return
+	}
+	// use latest copy in application pool, prober copy could be stale
+	v := pool.getSession(string(session.GetUID()))
+	if v == nil || !util.SessionIsOpen(v.session) || v.session.Status.HealthStatus == status {
+		return
+	}
+	newStatus := v.session.Status.DeepCopy()
+	newStatus.HealthStatus = status
+	if err := am.sessionManager.UpdateSessionStatus(v.session, newStatus); err != nil {
+		klog.ErrorS(err, "Failed to update session health status", "session", util.Name(v.session), "status", status)
+	}
+}
+
 type PendingSessions []*ApplicationSession
 
 func (ps PendingSessions) Len() int {
 	return len(ps)
 }
 
-//so, sort latency from smaller to lager value
+//so, sort by priority from higher to lower value, and sort latency from smaller to lager value in same priority
 func (ps PendingSessions) Less(i, j int) bool {
+	if ps[i].session.Spec.Priority != ps[j].session.Spec.Priority {
+		return ps[i].session.Spec.Priority > ps[j].session.Spec.Priority
+	}
 	return ps[i].session.CreationTimestamp.Before(&ps[j].session.CreationTimestamp)
 }
 
@@ -179,35 +234,78 @@ func (ps PendingSessions) Swap(i, j int) {
 func (am *ApplicationManager) deployApplicationSessions(pool *ApplicationPool, application *fornaxv1.Application) error {
 	pendingSessions, deletingSessions, timeoutSessions := pool.getNonRunningSessions()
 	// get 5 more in case some pods assigment failed
-	idlePods := pool.getSomeIdlePods(len(pendingSessions))
+	numOfIdlePods := len(pendingSessions)
+	for _, v := range pendingSessions {
+		if v.session.Spec.Affinity != nil || len(v.session.Spec.InstanceIdentity) > 0 {
+			// affinity and instance identity need to choose from all idle pods
+			numOfIdlePods = pool.podLength()
+			break
+		}
+	}
+	idlePods := pool.getSomeIdlePods(numOfIdlePods)
 	klog.InfoS("Syncing application pending session", "application", pool.appName, "#pending", len(pendingSessions), "#deleting", len(deletingSessions), "#timeout", len(timeoutSessions))
 
 	sort.Sort(PendingSessions(pendingSessions))
 	sessionErrors := []error{}
-	// 1/ assign pending sessions to idle pod
-	si := 0
+
+	// 0, keep sessions pending if external dependencies of application are not ready,
+	// recheck after gate cache expired, pending sessions still time out as usual
+	if len(pendingSessions) > 0 {
+		if err := am.readinessGateChecker.ApplicationReady(application); err != nil {
+			klog.InfoS("Application readiness gates not passed, do not assign session", "application", pool.appName, "reason", err)
+			for _, as := range pendingSessions {
+				event.Eventf(event.SessionRef(as.session), fornaxv1.FornaxEventTypeWarning, event.SourceApplicationManager, "ApplicationNotReady", "Readiness gates of application not passed, %v", err)
+			}
+			pendingSessions = []*ApplicationSession{}
+			am.applicationQueue.EnqueueAfter(pool.appName, RecheckPeriod(application))
+		}
+	}
+	// 1/ assign pending sessions to idle pod, pod is picked by session affinity if session has it
+	candidates := []*v1.Pod{}
+	templateHash := podTemplateHash(application)
 	for _, ap := range idlePods {
-		if si == len(pendingSessions) {
-			// has assigned all pending sesion to pod
-			break
+		if pod := am.podManager.FindPod(ap.podName); pod != nil {
+			if am.podNodeUnderMemoryPressure(pod) {
+				klog.V(5).InfoS("Node of idle pod is under memory pressure, do not assign session", "application", pool.appName, "pod", util.Name(pod))
+				continue
+			}
+			// pod of old container spec is deleted by rollout, unless rollout was paused
+			if !podOutdated(pod, templateHash) || rolloutPaused(application, templateHash) {
+				candidates = append(candidates, pod)
+			}
+		} else {
+			klog.InfoS("A idle Pod does not exist in Pod manager at all, should be deleted", "application", pool.appName, "pod", util.Name(ap.podName))
+		}
+	}
+	for _, as := range pendingSessions {
+		if len(candidates) == 0 {
+			// all idle pods are assigned, rest of sessions wait for new pods
+			event.Eventf(event.SessionRef(as.session), fornaxv1.FornaxEventTypeNormal, event.SourceApplicationManager, "WaitingForPod", "No idle pod of application, waiting for a new pod")
+			continue
 		}
-		pod := am.podManager.FindPod(ap.podName)
-		if pod != nil {
+		for attempt := 1; len(candidates) > 0; attempt++ {
+			i, score := am.pickPodForSession(pool, as.session, candidates)
+			if i < 0 {
+				klog.InfoS("No idle pod satisfy session affinity, keep session pending", "application", pool.appName, "session", util.Name(as.session), "requestId", util.RequestId(as.session))
+				event.Eventf(event.SessionRef(as.session), fornaxv1.FornaxEventTypeNormal, event.SourceApplicationManager, "WaitingForPod", "No idle pod satisfies session affinity, waiting for a new pod")
+				break
+			}
+			pod := candidates[i]
+			candidates = append(candidates[:i], candidates[i+1:]...)
 			// update as status and set access point of as
-			as := pendingSessions[si]
-			klog.InfoS("Assign session to pod", "application", pool.appName, "pod", util.Name(pod), "session", util.Name(as.session))
-			err := am.bindSessionToPod(pool, pod, as.session)
+			klog.InfoS("Assign session to pod", "application", pool.appName, "pod", util.Name(pod), "session", util.Name(as.session), "requestId", util.RequestId(as.session))
+			err := am.bindSessionToPod(pool, application, pod, as.session)
+			am.auditSessionPlacement(pool, pod, as.session, attempt, score, len(candidates)+1, err)
 			if err != nil {
 				// move to next pod, it could fail to accept other session also
-				klog.ErrorS(err, "Failed to open session on pod", "app", pool.appName, "session", as.session.Name, "pod", util.Name(pod))
+				klog.ErrorS(err, "Failed to open session on pod", "app", pool.appName, "session", as.session.Name, "requestId", util.RequestId(as.session), "pod", util.Name(pod))
+				event.Eventf(event.SessionRef(as.session), fornaxv1.FornaxEventTypeWarning, event.SourceApplicationManager, "FailedBinding", "Failed to open session on pod %s, %v", util.Name(pod), err)
 				sessionErrors = append(sessionErrors, err)
 				continue
-			} else {
-				pool.addOrUpdatePod(ap.podName, PodStateAllocated, []string{string(as.session.GetUID())})
-				si += 1
 			}
-		} else {
-			klog.InfoS("A idle Pod does not exist in Pod manager at all, should be deleted", "application", pool.appName, "pod", util.Name(ap.podName))
+			pool.addOrUpdatePod(util.Name(pod), PodStateAllocated, []string{string(as.session.GetUID())})
+			event.Eventf(event.SessionRef(as.session), fornaxv1.FornaxEventTypeNormal, event.SourceApplicationManager, "Assigned", "Assigned session to pod %s", util.Name(pod))
+			break
 		}
 	}
 
@@ -235,6 +333,16 @@ func (am *ApplicationManager) deployApplicationSessions(pool *ApplicationPool, a
 	return nil
 }
 
+// podNodeUnderMemoryPressure tell if node of pod is shedding session load, node evict its idle pods until pressure is gone
+func (am *ApplicationManager) podNodeUnderMemoryPressure(pod *v1.Pod) bool {
+	nodeId, found := pod.GetLabels()[fornaxv1.LabelFornaxCoreNode]
+	if !found || am.nodeManager == nil {
+		return false
+	}
+	node := am.nodeManager.FindNode(nodeId)
+	return node != nil && node.Node != nil && util.IsNodeUnderMemoryPressure(node.Node)
+}
+
 // if session is open, close it and wait for node report back
 // if session is still in pending, change status to timeout
 // if session is not open or pending, just delete since it's already in a terminal state
@@ -264,41 +372,65 @@ func (am *ApplicationManager) deleteApplicationSession(pool *ApplicationPool, s
 		}
 	} else {
 		if util.SessionIsPending(s.session) {
+			failSessionMigration(s.session, SessionMigrationTargetTimeoutError)
+			event.Eventf(event.SessionRef(s.session), fornaxv1.FornaxEventTypeWarning, event.SourceApplicationManager, "SessionTimeout", "Session was not opened before timeout, create a new session")
 			if err := am.changeSessionStatus(s.session, fornaxv1.SessionStatusTimeout); err != nil {
 				return err
 			}
 		}
 
-		klog.InfoS("Cleanup a session is in pending or terminated state", "session", util.Name(s.session))
+		klog.InfoS("Cleanup a session is in pending or terminated state", "session", util.Name(s.session), "requestId", util.RequestId(s.session))
 		pool.deleteSession(s.session)
 	}
 	return nil
 }
 
+// sessionWithTimeLimit return a copy of session with time limit of application if session does not set its own, it's sent to node,
+// session in store is not changed, so, a application time limit change only apply to sessions bound after it
+func sessionWithTimeLimit(application *fornaxv1.Application, session *fornaxv1.ApplicationSession) *fornaxv1.ApplicationSession {
+	if application == nil || application.Spec.MaxSessionDurationSeconds == 0 || session.Spec.MaxSessionDurationSeconds > 0 {
+		return session
+	}
+	limited := session.DeepCopy()
+	limited.Spec.MaxSessionDurationSeconds = application.Spec.MaxSessionDurationSeconds
+	if limited.Spec.ExpiryWarningSeconds == 0 {
+		limited.Spec.ExpiryWarningSeconds = application.Spec.SessionExpiryWarningSeconds
+	}
+	return limited
+}
+
 // change sessions status to starting and set access point
-func (am *ApplicationManager) bindSessionToPod(pool *ApplicationPool, pod *v1.Pod, session *fornaxv1.ApplicationSession) error {
+func (am *ApplicationManager) bindSessionToPod(pool *ApplicationPool, application *fornaxv1.Application, pod *v1.Pod, session *fornaxv1.ApplicationSession) error {
 	newStatus := session.Status.DeepCopy()
 	newStatus.SessionStatus = fornaxv1.SessionStatusStarting
 	for _, cont := range pod.Spec.Containers {
 		for _, port := range cont.Ports {
-			newStatus.AccessEndPoints = append(session.Status.AccessEndPoints, fornaxv1.AccessEndPoint{
+			// a dual stack node map a container port on its ipv4 and ipv6 address, each is a endpoint
+			newStatus.AccessEndPoints = append(newStatus.AccessEndPoints, fornaxv1.AccessEndPoint{
 				Protocol:  port.Protocol,
 				IPAddress: port.HostIP,
 				Port:      port.HostPort,
+				IPFamily:  util.IPFamilyOf(port.HostIP),
 			})
 		}
 	}
+	setSessionNATEndpoints(application, pod, newStatus)
+	am.setSessionGatewayEndpoints(application, session, newStatus)
 	newStatus.PodReference = &v1.LocalObjectReference{
 		Name: util.Name(pod),
 	}
 	oldStatus := session.Status.DeepCopy()
 	session.Status = *newStatus
-	if err := am.sessionManager.OpenSession(pod, session); err != nil {
+	if err := am.sessionManager.BindSession(pool.appName, pod, sessionWithTimeLimit(application, session)); err != nil {
 		session.Status = *oldStatus
+		if am.sessionGateway != nil {
+			am.sessionGateway.Unroute(util.Name(session))
+		}
 		return err
 	} else {
 		// just change pool directly, no need to update storage for a transient state, and triger unnecessary sync
 		updateSessionPool(pool, session)
+		am.sessionManager.RememberStickyPod(session, util.Name(pod))
 		return nil
 	}
 }
@@ -309,6 +441,20 @@ func (am *ApplicationManager) bindSessionToPod(pool *ApplicationPool, pod *v1.Po
 // and does not try to call node to close session, as session does not exist at all on node when pod deleted
 func (am *ApplicationManager) cleanupSessionOnDeletedPod(pool *ApplicationPool, podName string) {
 	podSessions := pool.getPodSessions(podName)
+	// pool could miss sessions bound to pod if session events are not received yet, also find them using session store pod index
+	storeSessions, err := am.sessionManager.ListPodSessions(podName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list sessions of pod from session store", "pod", podName)
+	}
+	for _, session := range storeSessions {
+		sessionId := string(session.GetUID())
+		if pool.getSession(sessionId) == nil {
+			pool.addSession(sessionId, session)
+			if sess := pool.getSession(sessionId); sess != nil {
+				podSessions = append(podSessions, sess)
+			}
+		}
+	}
 	for _, sess := range podSessions {
 		klog.Infof("Delete session %s on deleted pod %s", util.Name(sess.session), podName)
 		am.deleteApplicationSession(pool, sess)
@@ -335,3 +481,27 @@ func (am *ApplicationManager) cleanupSessionOfApplication(pool *ApplicationPool)
 
 	return nil
 }
+
+// SetPlacementAuditLog record session to pod bindings in placement audit log
+func (am *ApplicationManager) SetPlacementAuditLog(auditLog *placement.AuditLog) {
+	am.placementAuditLog = auditLog
+}
+
+func (am *ApplicationManager) auditSessionPlacement(pool *ApplicationPool, pod *v1.Pod, session *fornaxv1.ApplicationSession, attempt, score, candidates int, err error) {
+	record := &placement.Record{
+		Kind:        placement.KindSession,
+		Application: pool.appName,
+		Session:     util.Name(session),
+		Pod:         util.Name(pod),
+		Node:        pod.GetLabels()[fornaxv1.LabelFornaxCoreNode],
+		Result:      placement.ResultPlaced,
+		Attempt:     attempt,
+		Score:       int64(score),
+		Candidates:  candidates,
+	}
+	if err != nil {
+		record.Result = placement.ResultFailed
+		record.Message = err.Error()
+	}
+	am.placementAuditLog.Record(record)
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	ie "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/internal"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+// SetNodeManager let session affinity terms use node labels as topology, without it only node topology is supported
+func (am *ApplicationManager) SetNodeManager(nodeManager ie.NodeManagerInterface) {
+	am.nodeManager = nodeManager
+}
+
+// podTopology return topology value of a pod, node of pod for default topology key, or label value of pod's node
+func (am *ApplicationManager) podTopology(pod *v1.Pod, topologyKey string) (string, bool) {
+	nodeId, found := pod.GetLabels()[fornaxv1.LabelFornaxCoreNode]
+	if !found {
+		return "", false
+	}
+	if len(topologyKey) == 0 || topologyKey == fornaxv1.TopologyKeyNode {
+		return nodeId, true
+	}
+	if am.nodeManager == nil {
+		return "", false
+	}
+	node := am.nodeManager.FindNode(nodeId)
+	if node == nil || node.Node == nil {
+		return "", false
+	}
+	value, found := node.Node.GetLabels()[topologyKey]
+	return value, found
+}
+
+// affinityTopologyCounts count related sessions of a term by topology value, sessions without pod are not counted
+type affinityTopologyCounts struct {
+	term    fornaxv1.SessionAffinityTerm
+	anti    bool
+	related int
+	counts  map[string]int
+}
+
+// sessionAffinityCounts collect related sessions of each term of session from sessions of pool
+func (am *ApplicationManager) sessionAffinityCounts(pool *ApplicationPool, session *fornaxv1.ApplicationSession) []*affinityTopologyCounts {
+	affinity := session.Spec.Affinity
+	if affinity == nil || (len(affinity.SessionAffinity) == 0 && len(affinity.SessionAntiAffinity) == 0) {
+		return nil
+	}
+	terms := []*affinityTopologyCounts{}
+	selectors := []labels.Selector{}
+	addTerms := func(affinityTerms []fornaxv1.SessionAffinityTerm, anti bool) {
+		for _, v := range affinityTerms {
+			selector, err := metav1.LabelSelectorAsSelector(v.LabelSelector)
+			if err != nil {
+				klog.ErrorS(err, "Invalid session affinity selector, ignore it", "session", util.Name(session))
+				continue
+			}
+			terms = append(terms, &affinityTopologyCounts{term: v, anti: anti, counts: map[string]int{}})
+			selectors = append(selectors, selector)
+		}
+	}
+	addTerms(affinity.SessionAffinity, false)
+	addTerms(affinity.SessionAntiAffinity, true)
+
+	pods := map[string]*v1.Pod{}
+	for _, v := range pool.sessionList() {
+		s := v.session
+		if s.GetUID() == session.GetUID() || s.Status.PodReference == nil || !(util.SessionIsOpen(s) || s.Status.SessionStatus == fornaxv1.SessionStatusStarting) {
+			continue
+		}
+		podName := s.Status.PodReference.Name
+		pod, found := pods[podName]
+		if !found {
+			pod = am.podManager.FindPod(podName)
+			pods[podName] = pod
+		}
+		if pod == nil {
+			continue
+		}
+		for i, t := range terms {
+			if !selectors[i].Matches(labels.Set(s.GetLabels())) {
+				continue
+			}
+			t.related += 1
+			if value, found := am.podTopology(pod, t.term.TopologyKey); found {
+				t.counts[value] += 1
+			}
+		}
+	}
+	return terms
+}
+
+// scorePodForSession return false if pod violate a required term, otherwise, sum of weights of preferred terms,
+// each related session in same topology add weight of affinity term or subtract weight of anti affinity term
+func (am *ApplicationManager) scorePodForSession(pod *v1.Pod, terms []*affinityTopologyCounts) (int, bool) {
+	score := 0
+	for _, t := range terms {
+		value, found := am.podTopology(pod, t.term.TopologyKey)
+		count := 0
+		if found {
+			count = t.counts[value]
+		}
+		if t.term.Required {
+			if t.anti && count > 0 {
+				return 0, false
+			}
+			if !t.anti && t.related > 0 && count == 0 {
+				return 0, false
+			}
+			continue
+		}
+		weight := int(t.term.Weight)
+		if weight == 0 {
+			weight = 1
+		}
+		if t.anti {
+			score -= weight * count
+		} else {
+			score += weight * count
+		}
+	}
+	return score, true
+}
+
+// pickPodForSession return index and affinity score of best idle pod for session, first pod if session has no affinity,
+// -1 if no pod satisfy required terms
+func (am *ApplicationManager) pickPodForSession(pool *ApplicationPool, session *fornaxv1.ApplicationSession, pods []*v1.Pod) (int, int) {
+	allowed := instanceIdentityFilter(session, migrationTargetFilter(session))
+	terms := am.sessionAffinityCounts(pool, session)
+	if i, score, found := am.pickStickyPod(session, pods, allowed, terms); found {
+		return i, score
+	}
+	if len(terms) == 0 {
+		for i, pod := range pods {
+			if allowed(pod) {
+				return i, 0
+			}
+		}
+		return -1, 0
+	}
+	best, bestScore := -1, 0
+	for i, pod := range pods {
+		if !allowed(pod) {
+			continue
+		}
+		score, feasible := am.scorePodForSession(pod, terms)
+		if feasible && (best == -1 || score > bestScore) {
+			best, bestScore = i, score
+		}
+	}
+	return best, bestScore
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/gateway"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+// SetSessionGateway route sessions through session gateway, sessions are accessed using pod endpoints if it's not set
+func (am *ApplicationManager) SetSessionGateway(sessionGateway *gateway.SessionGateway) {
+	am.sessionGateway = sessionGateway
+}
+
+// setSessionGatewayEndpoints route a session being bound to pod and put gateway endpoints before pod endpoints,
+// session of a tls application is also routed by a subdomain of application dns name through gateway sni port,
+// session still use pod endpoints if gateway failed to route it
+func (am *ApplicationManager) setSessionGatewayEndpoints(application *fornaxv1.Application, session *fornaxv1.ApplicationSession, status *fornaxv1.ApplicationSessionStatus) {
+	if am.sessionGateway == nil {
+		return
+	}
+	endpoints, err := am.sessionGateway.Route(session, sessionDNSName(application, session), status.AccessEndPoints)
+	if err != nil {
+		klog.ErrorS(err, "Failed to route session through session gateway", "session", util.Name(session))
+		return
+	}
+	status.AccessEndPoints = append(endpoints, status.AccessEndPoints...)
+}
+
+// syncSessionGateway remove gateway route of a terminated session, and restore route of a open session if gateway does not have it
+func (am *ApplicationManager) syncSessionGateway(session *fornaxv1.ApplicationSession) {
+	if am.sessionGateway == nil {
+		return
+	}
+	if util.SessionIsOpen(session) {
+		am.sessionGateway.Restore(session)
+	} else if util.SessionInTerminalState(session) {
+		am.sessionGateway.Unroute(util.Name(session))
+	}
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"errors"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+var (
+	SessionMigrationNotOpenError          = errors.New("session is not open")
+	SessionMigrationSourcePodNotFound     = errors.New("source pod of session does not exist")
+	SessionMigrationTargetPodNotIdleError = errors.New("target pod is not a idle pod of application")
+	SessionMigrationNoIdlePodError        = errors.New("no idle pod to migrate session to")
+	SessionMigrationSourceTimeoutError    = errors.New("session is not closed on source pod in time")
+	SessionMigrationTargetTimeoutError    = errors.New("session is not opened on target pod in time")
+)
+
+var (
+	sessionMigrations = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_session_migration",
+			Name:           "total",
+			Help:           "Number of session migrations by result",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"application", "result"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(sessionMigrations)
+}
+
+// sessionPendingSince return when session became pending, a migrated session is pending again after it's closed on source pod
+func sessionPendingSince(session *fornaxv1.ApplicationSession) time.Time {
+	if migration := session.Status.Migration; migration != nil && migration.Phase == fornaxv1.SessionMigrationPhaseMigrating && migration.SourceClosedTime != nil {
+		return migration.SourceClosedTime.Time
+	}
+	return session.CreationTimestamp.Time
+}
+
+// migrationTargetFilter return which pods a pending session can be assigned to, a migrating session go to its target pod,
+// or any pod other than its source pod
+func migrationTargetFilter(session *fornaxv1.ApplicationSession) func(*v1.Pod) bool {
+	migration := session.Status.Migration
+	if migration == nil || migration.Phase != fornaxv1.SessionMigrationPhaseMigrating {
+		return func(*v1.Pod) bool { return true }
+	}
+	return func(pod *v1.Pod) bool {
+		if len(migration.TargetPod) > 0 {
+			return util.Name(pod) == migration.TargetPod
+		}
+		return util.Name(pod) != migration.SourcePod
+	}
+}
+
+// failSessionMigration mark a ongoing migration of session failed in local copy, caller save session status
+func failSessionMigration(session *fornaxv1.ApplicationSession, reason error) {
+	migration := session.Status.Migration
+	if migration == nil || migration.Phase != fornaxv1.SessionMigrationPhaseMigrating {
+		return
+	}
+	migration = migration.DeepCopy()
+	migration.Phase = fornaxv1.SessionMigrationPhaseFailed
+	migration.Message = reason.Error()
+	migration.CompletionTime = util.NewCurrentMetaTimeNormallized()
+	session.Status.Migration = migration
+	sessionMigrations.WithLabelValues(getSessionApplicationKey(session), "failed").Inc()
+}
+
+// migrateApplicationSessions start migrations requested by migrate subresource of open sessions,
+// and fail migrations whose source pod does not close session in time, session is still open on source pod then
+func (am *ApplicationManager) migrateApplicationSessions(pool *ApplicationPool) {
+	for _, s := range pool.sessionList() {
+		session := s.session
+		if targetPod, requested := session.Annotations[fornaxv1.AnnotationFornaxCoreMigrateSession]; requested {
+			if err := am.startSessionMigration(pool, session, targetPod); err != nil {
+				klog.ErrorS(err, "Failed to migrate session", "application", pool.appName, "session", util.Name(session), "target", targetPod)
+				now := util.NewCurrentMetaTimeNormallized()
+				source := ""
+				if session.Status.PodReference != nil {
+					source = session.Status.PodReference.Name
+				}
+				migration := &fornaxv1.SessionMigration{
+					Phase:          fornaxv1.SessionMigrationPhaseFailed,
+					SourcePod:      source,
+					TargetPod:      targetPod,
+					Message:        err.Error(),
+					StartTime:      now,
+					CompletionTime: now,
+				}
+				if err := am.sessionManager.UpdateSessionMigration(session, migration); err != nil {
+					klog.ErrorS(err, "Failed to update session migration status", "session", util.Name(session))
+				}
+				sessionMigrations.WithLabelValues(pool.appName, "failed").Inc()
+			}
+			continue
+		}
+
+		migration := session.Status.Migration
+		if migration == nil || migration.Phase != fornaxv1.SessionMigrationPhaseMigrating || migration.SourceClosedTime != nil || migration.StartTime == nil {
+			continue
+		}
+		if time.Since(migration.StartTime.Time) > sessionCloseGracePeriod(session)+DefaultSessionOpenTimeoutDuration {
+			klog.InfoS("Session is not closed on source pod in time, fail migration", "application", pool.appName, "session", util.Name(session), "source", migration.SourcePod)
+			failSessionMigration(session, SessionMigrationSourceTimeoutError)
+			if err := am.sessionManager.UpdateSessionMigration(session, session.Status.Migration); err != nil {
+				klog.ErrorS(err, "Failed to update session migration status", "session", util.Name(session))
+			}
+		}
+	}
+}
+
+// startSessionMigration check session can be moved to target pod and ask source node to close session with its metadata exported,
+// session become pending after source pod closed it and is assigned to target pod like a new session
+func (am *ApplicationManager) startSessionMigration(pool *ApplicationPool, session *fornaxv1.ApplicationSession, targetPod string) error {
+	if session.DeletionTimestamp != nil || session.Status.PodReference == nil ||
+		(session.Status.SessionStatus != fornaxv1.SessionStatusAvailable && session.Status.SessionStatus != fornaxv1.SessionStatusInUse) {
+		return SessionMigrationNotOpenError
+	}
+	sourcePod := session.Status.PodReference.Name
+	pod := am.podManager.FindPod(sourcePod)
+	if pod == nil {
+		return SessionMigrationSourcePodNotFound
+	}
+
+	if len(targetPod) > 0 {
+		if ap := pool.getPod(targetPod); ap == nil || ap.state != PodStateIdle {
+			return SessionMigrationTargetPodNotIdleError
+		}
+	} else {
+		found := false
+		for _, ap := range pool.podListOfState(PodStateIdle) {
+			if ap.podName != sourcePod {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return SessionMigrationNoIdlePodError
+		}
+	}
+
+	klog.InfoS("Migrate session", "application", pool.appName, "session", util.Name(session), "requestId", util.RequestId(session), "source", sourcePod, "target", targetPod)
+	migration := &fornaxv1.SessionMigration{
+		Phase:     fornaxv1.SessionMigrationPhaseMigrating,
+		SourcePod: sourcePod,
+		TargetPod: targetPod,
+		StartTime: util.NewCurrentMetaTimeNormallized(),
+	}
+	if err := am.sessionManager.UpdateSessionMigration(session, migration); err != nil {
+		return err
+	}
+	sessionMigrations.WithLabelValues(pool.appName, "started").Inc()
+	return am.sessionManager.MigrateSession(pod, session, targetPod)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var (
+	sessionStickiness = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_session_stickiness",
+			Name:           "total",
+			Help:           "Number of sessions with stickiness key assigned to pods, hit if session got pod which served last session of key",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"application", "result"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(sessionStickiness)
+}
+
+// pickStickyPod return index of pod which served last session of stickiness key if it's a idle candidate,
+// sticky pod does not override anti affinity or migration target of session
+func (am *ApplicationManager) pickStickyPod(session *fornaxv1.ApplicationSession, pods []*v1.Pod, allowed func(*v1.Pod) bool, terms []*affinityTopologyCounts) (int, int, bool) {
+	if len(session.Spec.StickinessKey) == 0 {
+		return -1, 0, false
+	}
+	application := getSessionApplicationKey(session)
+	if podName := am.sessionManager.StickyPod(session); len(podName) > 0 {
+		for i, pod := range pods {
+			if util.Name(pod) != podName || !allowed(pod) {
+				continue
+			}
+			score, feasible := 0, true
+			if len(terms) > 0 {
+				score, feasible = am.scorePodForSession(pod, terms)
+			}
+			if feasible {
+				sessionStickiness.WithLabelValues(application, "hit").Inc()
+				return i, score, true
+			}
+		}
+	}
+	sessionStickiness.WithLabelValues(application, "miss").Inc()
+	return -1, 0, false
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"net/http"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	storefactory "centaurusinfra.io/fornax-serverless/pkg/store/factory"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	ApplicationResumePath = "/fornaxcore/application/resume"
+)
+
+// touch record application is in use, idle period of suspension policy start from last touch
+func (pool *ApplicationPool) touch(t time.Time) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	if t.After(pool.lastActiveTime) {
+		pool.lastActiveTime = t
+	}
+}
+
+func (pool *ApplicationPool) getLastActiveTime() time.Time {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+	return pool.lastActiveTime
+}
+
+// applicationSuspended decide if application should be suspended, application is resumed if it's touched after suspended time,
+// an application never suspended is idle since pool created if it does not have any session since fornaxcore started
+func (am *ApplicationManager) applicationSuspended(pool *ApplicationPool, application *fornaxv1.Application) bool {
+	now := time.Now()
+	if pool.sessionLength() > 0 {
+		pool.touch(now)
+	}
+	lastActiveTime := pool.getLastActiveTime()
+	if application.Status.Suspended && application.Status.SuspendedTime != nil {
+		if application.Status.SuspendedTime.Time.After(lastActiveTime) {
+			return true
+		}
+		klog.InfoS("Resuming application", "application", pool.appName, "suspended-time", application.Status.SuspendedTime.Time, "last-active-time", lastActiveTime)
+	}
+	if application.Spec.SuspensionPolicy == nil {
+		return false
+	}
+	if lastActiveTime.Before(pool.createTime) {
+		lastActiveTime = pool.createTime
+	}
+	idle := now.Sub(lastActiveTime)
+	return idle >= time.Duration(application.Spec.SuspensionPolicy.IdleSeconds)*time.Second
+}
+
+// suspendApplication delete all unoccupied pods of a suspended application, a suspended app does not have sessions,
+// so, all pods are deleted eventually
+func (am *ApplicationManager) suspendApplication(pool *ApplicationPool, application *fornaxv1.Application, status *fornaxv1.ApplicationStatus) error {
+	if !application.Status.Suspended {
+		klog.InfoS("Suspending idle application", "application", pool.appName, "last-active-time", pool.getLastActiveTime())
+		status.Suspended = true
+		status.SuspendedTime = &metav1.Time{Time: time.Now()}
+	}
+	_, pendingPods, idlePods := pool.activePodNums()
+	if pendingPods+idlePods > 0 {
+		if err := am.deployApplicationPods(pool, application, -1*(pendingPods+idlePods)); err != nil {
+			return err
+		}
+	}
+	am.pruneDeadPods(pool)
+	return nil
+}
+
+// suspensionDue tell if a application has been idle longer than suspension policy and still has pods to delete
+func (am *ApplicationManager) suspensionDue(applicationKey string, pool *ApplicationPool) bool {
+	if pool.sessionLength() > 0 || pool.podLength() == 0 {
+		return false
+	}
+	application, err := storefactory.GetApplicationCache(am.applicationStore, applicationKey)
+	if err != nil || application == nil || application.DeletionTimestamp != nil {
+		return false
+	}
+	return am.applicationSuspended(pool, application)
+}
+
+// ResumeApplication resume a suspended application, pods are created again according scaling policy
+func (am *ApplicationManager) ResumeApplication(applicationKey string) error {
+	application, err := storefactory.GetApplicationCache(am.applicationStore, applicationKey)
+	if err != nil {
+		return err
+	}
+	if application == nil {
+		return apierrors.NewNotFound(fornaxv1.ApplicationGrv.GroupResource(), applicationKey)
+	}
+	pool := am.getOrCreateApplicationPool(applicationKey)
+	pool.touch(time.Now())
+	am.enqueueApplication(applicationKey)
+	return nil
+}
+
+// ApplicationResumeHandler resume a application, e.g. kubectl create --raw "/fornaxcore/application/resume?application=<namespace>/<name>" -f /dev/null
+type ApplicationResumeHandler struct {
+	am *ApplicationManager
+}
+
+func NewApplicationResumeHandler(am *ApplicationManager) *ApplicationResumeHandler {
+	return &ApplicationResumeHandler{am: am}
+}
+
+func (h *ApplicationResumeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	applicationKey := r.URL.Query().Get("application")
+	if len(applicationKey) == 0 {
+		http.Error(w, "application is required", http.StatusBadRequest)
+		return
+	}
+	if err := h.am.ResumeApplication(applicationKey); err != nil {
+		if apierrors.IsNotFound(err) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	storefactory "centaurusinfra.io/fornax-serverless/pkg/store/factory"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultWarmPoolMaxCapacityPercent = 50
+)
+
+var (
+	warmPoolSize = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      "fornax_application_warm_pool",
+			Name:           "size",
+			Help:           "Number of warm pods application keep after limited by node capacity and maximum instances",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"application"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(warmPoolSize)
+}
+
+// applicationPodResources sum resource requests of application containers
+func applicationPodResources(application *fornaxv1.Application) v1.ResourceList {
+	cpu, memory := resource.Quantity{}, resource.Quantity{}
+	for _, v := range application.Spec.Containers {
+		cpu.Add(*v.Resources.Requests.Cpu())
+		memory.Add(*v.Resources.Requests.Memory())
+	}
+	return v1.ResourceList{v1.ResourceCPU: cpu, v1.ResourceMemory: memory}
+}
+
+// warmPoolTarget return how many unoccupied pods warm pool want, it's spec size limited by maximum instances
+// and a percent of free node capacity, existing unoccupied pods already hold their resources, so they are always counted in
+func (am *ApplicationManager) warmPoolTarget(pool *ApplicationPool, application *fornaxv1.Application, occupiedPodNum, unoccupiedPodNum int) int {
+	warmPool := application.Spec.WarmPool
+	if warmPool == nil || warmPool.Size == 0 {
+		return 0
+	}
+	target := int(warmPool.Size)
+	if maximum := applicationMaxInstances(application) - occupiedPodNum; target > maximum {
+		target = maximum
+	}
+	if am.podScheduler != nil && target > unoccupiedPodNum {
+		if capacity := am.podScheduler.PodCapacity(applicationPodResources(application)); capacity >= 0 {
+			percent := int(warmPool.MaxCapacityPercent)
+			if percent == 0 {
+				percent = DefaultWarmPoolMaxCapacityPercent
+			}
+			if limit := unoccupiedPodNum + capacity*percent/100; target > limit {
+				klog.InfoS("Warm pool is limited by node capacity", "application", pool.appName, "size", warmPool.Size, "limit", limit)
+				target = limit
+			}
+		}
+	}
+	if target < 0 {
+		target = 0
+	}
+	warmPoolSize.WithLabelValues(pool.appName).Set(float64(target))
+	return target
+}
+
+// applyWarmPool raise desired unoccupied pods to warm pool target, warm pool is not kept for a application scaled to zero
+func (am *ApplicationManager) applyWarmPool(pool *ApplicationPool, application *fornaxv1.Application, occupiedPodNum, unoccupiedPodNum, desiredUnoccupiedPodNum int) int {
+	if application.Spec.WarmPool == nil || am.scaledToZero(pool, sessionDemandPolicy(application)) {
+		return desiredUnoccupiedPodNum
+	}
+	if target := am.warmPoolTarget(pool, application, occupiedPodNum, unoccupiedPodNum); desiredUnoccupiedPodNum < target {
+		return target
+	}
+	return desiredUnoccupiedPodNum
+}
+
+// warmPoolShort tell if application has less unoccupied pods than warm pool size, it's rechecked when node capacity change
+func (am *ApplicationManager) warmPoolShort(applicationKey string, pool *ApplicationPool) bool {
+	application, err := storefactory.GetApplicationCache(am.applicationStore, applicationKey)
+	if err != nil || application == nil || application.DeletionTimestamp != nil || application.Spec.WarmPool == nil || application.Status.Suspended {
+		return false
+	}
+	_, pendingPods, idlePods := pool.activePodNums()
+	return pendingPods+idlePods < int(application.Spec.WarmPool.Size) && !am.scaledToZero(pool, sessionDemandPolicy(application))
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/request/bearertoken"
+	authenticatorunion "k8s.io/apiserver/pkg/authentication/request/union"
+	"k8s.io/apiserver/pkg/authentication/token/cache"
+	"k8s.io/apiserver/pkg/authentication/token/tokenfile"
+	tokenunion "k8s.io/apiserver/pkg/authentication/token/union"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	authorizerunion "k8s.io/apiserver/pkg/authorization/union"
+	"k8s.io/apiserver/pkg/server"
+	webhookutil "k8s.io/apiserver/pkg/util/webhook"
+	"k8s.io/apiserver/plugin/pkg/authenticator/token/webhook"
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultWebhookCacheTTLSeconds = 120
+)
+
+// WebhookConfiguration authenticate bearer tokens using a TokenReview webhook, kubeconfig file point to webhook server
+type WebhookConfiguration struct {
+	KubeConfigFile string `json:"kubeConfigFile"`
+
+	// +optional, how long a authenticated token is cached, default 120s
+	CacheTTLSeconds int `json:"cacheTTLSeconds,omitempty"`
+}
+
+// AuthConfiguration enable authentication of api server clients and namespace rbac,
+// bearer tokens are checked by static token file, webhook and oidc in order, first authenticated user is used
+type AuthConfiguration struct {
+	Enabled bool `json:"enabled"`
+
+	// +optional, static token csv file, same format as kube-apiserver --token-auth-file, token,user,uid,"group1,group2"
+	TokenFile string `json:"tokenFile,omitempty"`
+
+	// +optional
+	Webhook *WebhookConfiguration `json:"webhook,omitempty"`
+
+	// +optional
+	OIDC *OIDCConfiguration `json:"oidc,omitempty"`
+
+	RBAC RBACConfiguration `json:"rbac"`
+}
+
+// LoadAuthConfiguration read auth configuration from a json file, authentication and rbac are disabled if file does not exist
+func LoadAuthConfiguration(file string) (*AuthConfiguration, error) {
+	config := &AuthConfiguration{}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid auth configuration %s: %v", file, err)
+	}
+	if config.Enabled && config.TokenFile == "" && config.Webhook == nil && config.OIDC == nil {
+		return nil, fmt.Errorf("invalid auth configuration %s: no token file, webhook or oidc authenticator", file)
+	}
+	if err := config.RBAC.validate(); err != nil {
+		return nil, fmt.Errorf("invalid auth configuration %s: %v", file, err)
+	}
+	return config, nil
+}
+
+// NewTokenAuthenticator create token authenticators in configuration
+func NewTokenAuthenticator(config *AuthConfiguration) (authenticator.Token, error) {
+	tokenAuthenticators := []authenticator.Token{}
+	if config.TokenFile != "" {
+		tokenAuth, err := tokenfile.NewCSV(config.TokenFile)
+		if err != nil {
+			return nil, err
+		}
+		tokenAuthenticators = append(tokenAuthenticators, tokenAuth)
+	}
+	if config.Webhook != nil {
+		clientConfig, err := webhookutil.LoadKubeconfig(config.Webhook.KubeConfigFile, nil)
+		if err != nil {
+			return nil, err
+		}
+		webhookAuth, err := webhook.New(clientConfig, "v1", nil, wait.Backoff{Duration: 500 * time.Millisecond, Factor: 1.5, Jitter: 0.2, Steps: 5})
+		if err != nil {
+			return nil, err
+		}
+		ttl := config.Webhook.CacheTTLSeconds
+		if ttl <= 0 {
+			ttl = DefaultWebhookCacheTTLSeconds
+		}
+		tokenAuthenticators = append(tokenAuthenticators, cache.New(webhookAuth, false, time.Duration(ttl)*time.Second, 10*time.Second))
+	}
+	if config.OIDC != nil {
+		oidcAuth, err := NewOIDCAuthenticator(config.OIDC)
+		if err != nil {
+			return nil, err
+		}
+		tokenAuthenticators = append(tokenAuthenticators, oidcAuth)
+	}
+	return tokenunion.New(tokenAuthenticators...), nil
+}
+
+// ApplyTo add configured authenticators and rbac authorizer to api server config,
+// existing authenticator and authorizer are kept, so, fornaxcore loopback client and delegated auth still work
+func ApplyTo(config *AuthConfiguration, serverConfig *server.RecommendedConfig) error {
+	if !config.Enabled {
+		return nil
+	}
+	tokenAuth, err := NewTokenAuthenticator(config)
+	if err != nil {
+		return err
+	}
+	requestAuth := authenticator.Request(bearertoken.New(tokenAuth))
+	if serverConfig.Authentication.Authenticator != nil {
+		requestAuth = authenticatorunion.New(requestAuth, serverConfig.Authentication.Authenticator)
+	}
+	serverConfig.Authentication.Authenticator = requestAuth
+
+	rbacAuthorizer := authorizer.Authorizer(NewRBACAuthorizer(&config.RBAC))
+	existingAuthorizer := serverConfig.Authorization.Authorizer
+	if existingAuthorizer != nil {
+		serverConfig.Authorization.Authorizer = authorizerunion.New(rbacAuthorizer, existingAuthorizer)
+	} else {
+		// authorization is bypassed in standalone mode, loopback client token was not authorized, do it now
+		serverConfig.Authorization.Authorizer = rbacAuthorizer
+		server.AuthorizeClientBearerToken(serverConfig.LoopbackClientConfig, &serverConfig.Authentication, &serverConfig.Authorization)
+	}
+	klog.InfoS("Api server authentication and rbac enabled", "token file", config.TokenFile, "webhook", config.Webhook != nil, "oidc", config.OIDC != nil, "bindings", len(config.RBAC.Bindings))
+	return nil
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+func attributes(u user.Info, verb, namespace, resource string) authorizer.AttributesRecord {
+	return authorizer.AttributesRecord{
+		User: u, Verb: verb, Namespace: namespace, Resource: resource,
+		APIGroup: "core.fornax-serverless.centaurusinfra.io", APIVersion: "v1", ResourceRequest: true,
+	}
+}
+
+func TestRBACAuthorizer(t *testing.T) {
+	rbac := NewRBACAuthorizer(&RBACConfiguration{
+		AdminGroups: []string{"fornax:admins"},
+		Bindings: []RoleBinding{
+			{Name: "team-a", Groups: []string{"team-a"}, Namespaces: []string{"a"}, Role: RoleEdit},
+			{Name: "auditor", Users: []string{"auditor"}, Namespaces: []string{AllNamespaces}, Role: RoleView},
+		},
+	})
+	alice := &user.DefaultInfo{Name: "alice", Groups: []string{"team-a", user.AllAuthenticated}}
+	auditor := &user.DefaultInfo{Name: "auditor", Groups: []string{user.AllAuthenticated}}
+	admin := &user.DefaultInfo{Name: "bob", Groups: []string{"fornax:admins"}}
+	stranger := &user.DefaultInfo{Name: "eve", Groups: []string{user.AllAuthenticated}}
+	anonymous := &user.DefaultInfo{Name: user.Anonymous, Groups: []string{user.AllUnauthenticated}}
+	nodeOperation := attributes(admin, "create", "", "nodeoperations")
+	debugPath := authorizer.AttributesRecord{User: alice, Verb: "get", Path: "/debug/fornaxcore/cluster/status"}
+	discovery := authorizer.AttributesRecord{User: alice, Verb: "get", Path: "/apis/core.fornax-serverless.centaurusinfra.io/v1"}
+	anonymousDiscovery := discovery
+	anonymousDiscovery.User = anonymous
+	statusUpdate := attributes(alice, "update", "a", "applications")
+	statusUpdate.Subresource = "status"
+	exec := attributes(alice, "create", "a", "applicationsessions")
+	exec.Subresource = "exec"
+	viewExec := attributes(auditor, "get", "a", "applicationsessions")
+	viewExec.Subresource = "exec"
+	viewLogs := attributes(auditor, "get", "a", "applicationsessions")
+	viewLogs.Subresource = "logs"
+	otherExec := exec
+	otherExec.Namespace = "b"
+
+	cases := []struct {
+		name     string
+		attrs    authorizer.Attributes
+		decision authorizer.Decision
+	}{
+		{"edit own namespace", attributes(alice, "create", "a", "applications"), authorizer.DecisionAllow},
+		{"delete session of own namespace", attributes(alice, "delete", "a", "applicationsessions"), authorizer.DecisionAllow},
+		{"list own namespace", attributes(alice, "list", "a", "applicationsessions"), authorizer.DecisionAllow},
+		{"create in other namespace", attributes(alice, "create", "b", "applications"), authorizer.DecisionDeny},
+		{"list all namespaces", attributes(alice, "list", "", "applications"), authorizer.DecisionDeny},
+		{"edit quota", attributes(alice, "update", "a", "fornaxquotas"), authorizer.DecisionDeny},
+		{"read quota", attributes(alice, "get", "a", "fornaxquotas"), authorizer.DecisionAllow},
+		{"update status", statusUpdate, authorizer.DecisionDeny},
+		{"exec in own namespace", exec, authorizer.DecisionAllow},
+		{"exec in other namespace", otherExec, authorizer.DecisionDeny},
+		{"view can not exec", viewExec, authorizer.DecisionDeny},
+		{"view read logs", viewLogs, authorizer.DecisionAllow},
+		{"view any namespace", attributes(auditor, "watch", "b", "applications"), authorizer.DecisionAllow},
+		{"view can not edit", attributes(auditor, "create", "b", "applications"), authorizer.DecisionDeny},
+		{"admin", nodeOperation, authorizer.DecisionAllow},
+		{"system masters", attributes(&user.DefaultInfo{Name: "root", Groups: []string{user.SystemPrivilegedGroup}}, "delete", "", "nodeleases"), authorizer.DecisionAllow},
+		{"unbound user", attributes(stranger, "get", "a", "applications"), authorizer.DecisionNoOpinion},
+		{"debug endpoint", debugPath, authorizer.DecisionNoOpinion},
+		{"discovery", discovery, authorizer.DecisionAllow},
+		{"anonymous discovery", anonymousDiscovery, authorizer.DecisionNoOpinion},
+	}
+	for _, c := range cases {
+		decision, _, err := rbac.Authorize(context.Background(), c.attrs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decision != c.decision {
+			t.Errorf("%s: expected decision %v, got %v", c.name, c.decision, decision)
+		}
+	}
+}
+
+func TestLoadAuthConfiguration(t *testing.T) {
+	dir := t.TempDir()
+	config, err := LoadAuthConfiguration(filepath.Join(dir, "auth.json"))
+	if err != nil || config.Enabled {
+		t.Fatalf("expected disabled auth without configuration file, got %v, %v", config, err)
+	}
+	file := filepath.Join(dir, "bad.json")
+	os.WriteFile(file, []byte(`{"enabled": true, "tokenFile": "tokens.csv", "rbac": {"bindings": [{"name": "a", "namespaces": ["a"], "role": "owner"}]}}`), 0600)
+	if _, err := LoadAuthConfiguration(file); err == nil {
+		t.Errorf("expected error of unknown role")
+	}
+}
+
+type testJSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type testIssuer struct {
+	server *httptest.Server
+	rsaKey *rsa.PrivateKey
+	ecKey  *ecdsa.PrivateKey
+}
+
+func b64(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	issuer := &testIssuer{}
+	var err error
+	if issuer.rsaKey, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+		t.Fatal(err)
+	}
+	if issuer.ecKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	issuer.server = httptest.NewTLSServer(mux)
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"issuer": issuer.server.URL, "jwks_uri": issuer.server.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []testJSONWebKey{
+			{Kty: "RSA", Kid: "rsa", Use: "sig", N: b64(issuer.rsaKey.N.Bytes()), E: b64(big.NewInt(int64(issuer.rsaKey.E)).Bytes())},
+			{Kty: "EC", Kid: "ec", Crv: "P-256", X: b64(issuer.ecKey.X.FillBytes(make([]byte, 32))), Y: b64(issuer.ecKey.Y.FillBytes(make([]byte, 32)))},
+		}})
+	})
+	return issuer
+}
+
+func (i *testIssuer) sign(t *testing.T, alg string, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": map[string]string{"RS256": "rsa", "ES256": "ec"}[alg], "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := b64(header) + "." + b64(payload)
+	digest := sha256.Sum256([]byte(signed))
+	var signature []byte
+	if alg == "RS256" {
+		var err error
+		if signature, err = rsa.SignPKCS1v15(rand.Reader, i.rsaKey, crypto.SHA256, digest[:]); err != nil {
+			t.Fatal(err)
+		}
+	} else {
+		r, s, err := ecdsa.Sign(rand.Reader, i.ecKey, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return signed + "." + b64(signature)
+}
+
+func TestOIDCAuthenticator(t *testing.T) {
+	issuer := newTestIssuer(t)
+	defer issuer.server.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: issuer.server.Certificate().Raw}), 0600)
+	oidc, err := NewOIDCAuthenticator(&OIDCConfiguration{
+		IssuerURL: issuer.server.URL, ClientID: "fornax", UsernameClaim: "email", UsernamePrefix: "oidc:", GroupsClaim: "groups", CAFile: caFile,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := func(modify func(map[string]interface{})) map[string]interface{} {
+		c := map[string]interface{}{
+			"iss": issuer.server.URL, "aud": []string{"fornax", "other"}, "sub": "1234", "email": "alice@example.com", "email_verified": true,
+			"groups": []string{"team-a"}, "exp": time.Now().Add(time.Hour).Unix(),
+		}
+		if modify != nil {
+			modify(c)
+		}
+		return c
+	}
+
+	// issuer is discovered in background
+	if err := wait.PollImmediate(100*time.Millisecond, 10*time.Second, func() (bool, error) {
+		_, ok, _ := oidc.AuthenticateToken(context.Background(), issuer.sign(t, "RS256", claims(nil)))
+		return ok, nil
+	}); err != nil {
+		t.Fatalf("expected oidc authenticator initialized, got %v", err)
+	}
+	for _, alg := range []string{"RS256", "ES256"} {
+		resp, ok, err := oidc.AuthenticateToken(context.Background(), issuer.sign(t, alg, claims(nil)))
+		if err != nil || !ok {
+			t.Fatalf("%s: expected token authenticated, got %v", alg, err)
+		}
+		if resp.User.GetName() != "oidc:alice@example.com" || len(resp.User.GetGroups()) != 1 || resp.User.GetGroups()[0] != "team-a" {
+			t.Errorf("%s: unexpected user %v", alg, resp.User)
+		}
+	}
+
+	invalid := map[string]string{
+		"expired":            issuer.sign(t, "RS256", claims(func(c map[string]interface{}) { c["exp"] = time.Now().Add(-time.Minute).Unix() })),
+		"wrong audience":     issuer.sign(t, "RS256", claims(func(c map[string]interface{}) { c["aud"] = "other" })),
+		"email not verified": issuer.sign(t, "ES256", claims(func(c map[string]interface{}) { c["email_verified"] = false })),
+	}
+	tampered := issuer.sign(t, "RS256", claims(nil))
+	invalid["tampered"] = tampered[:len(tampered)-4] + "AAAA"
+	for name, token := range invalid {
+		if _, ok, err := oidc.AuthenticateToken(context.Background(), token); ok || err == nil {
+			t.Errorf("%s: expected token rejected", name)
+		}
+	}
+
+	// tokens of other issuers and non jwt tokens are left to other authenticators
+	other := issuer.sign(t, "RS256", claims(func(c map[string]interface{}) { c["iss"] = "https://other.example.com" }))
+	for _, token := range []string{other, "abcdef.0123456789abcdef"} {
+		if _, ok, err := oidc.AuthenticateToken(context.Background(), token); ok || err != nil {
+			t.Errorf("expected token ignored, got %v, %v", ok, err)
+		}
+	}
+}
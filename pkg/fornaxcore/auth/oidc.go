@@ -0,0 +1,82 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/server/dynamiccertificates"
+	"k8s.io/apiserver/plugin/pkg/authenticator/token/oidc"
+)
+
+const (
+	DefaultOIDCUsernameClaim = "sub"
+)
+
+// signing algorithms accepted in oidc id tokens
+var oidcSigningAlgs = []string{"RS256", "ES256"}
+
+// OIDCConfiguration authenticate users using id tokens of a openid connect issuer, only RS256 and ES256 signed tokens are accepted
+type OIDCConfiguration struct {
+	IssuerURL string `json:"issuerURL"`
+	ClientID  string `json:"clientID"`
+
+	// +optional, claim used as user name, default sub
+	UsernameClaim string `json:"usernameClaim,omitempty"`
+
+	// +optional, prefix of user name to avoid conflict with users of other authenticators, e.g. oidc:
+	UsernamePrefix string `json:"usernamePrefix,omitempty"`
+
+	// +optional, claim used as user groups, string or string array
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+
+	// +optional
+	GroupsPrefix string `json:"groupsPrefix,omitempty"`
+
+	// +optional, CA used to verify issuer, system roots are used if empty
+	CAFile string `json:"caFile,omitempty"`
+}
+
+// NewOIDCAuthenticator create k8s oidc token authenticator, issuer discovery is retried in background,
+// so, api server start does not depend on issuer, tokens of other issuers are left to other authenticators
+func NewOIDCAuthenticator(config *OIDCConfiguration) (authenticator.Token, error) {
+	if config.IssuerURL == "" || config.ClientID == "" {
+		return nil, fmt.Errorf("oidc issuer url and client id are required")
+	}
+	usernameClaim := config.UsernameClaim
+	if usernameClaim == "" {
+		usernameClaim = DefaultOIDCUsernameClaim
+	}
+	opts := oidc.Options{
+		IssuerURL:            config.IssuerURL,
+		ClientID:             config.ClientID,
+		UsernameClaim:        usernameClaim,
+		UsernamePrefix:       config.UsernamePrefix,
+		GroupsClaim:          config.GroupsClaim,
+		GroupsPrefix:         config.GroupsPrefix,
+		SupportedSigningAlgs: oidcSigningAlgs,
+	}
+	if config.CAFile != "" {
+		caContent, err := dynamiccertificates.NewDynamicCAContentFromFile("oidc-authenticator", config.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		opts.CAContentProvider = caContent
+	}
+	return oidc.New(opts)
+}
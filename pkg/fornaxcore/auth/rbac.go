@@ -0,0 +1,190 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+type Role string
+
+const (
+	// RoleView can get, list and watch applications, sessions, quotas, session usages and events in namespace
+	RoleView Role = "view"
+	// RoleEdit can also create, update and delete applications and sessions in namespace, and exec, attach and port forward to session pods
+	RoleEdit Role = "edit"
+
+	// AllNamespaces in a binding allow all namespaces, cluster scoped requests like listing all namespaces still require a admin
+	AllNamespaces = "*"
+)
+
+var (
+	readVerbs  = []string{"get", "list", "watch"}
+	writeVerbs = []string{"create", "update", "patch", "delete", "deletecollection"}
+
+	// resources tenants can access in their namespaces, status subresources are written by fornaxcore only
+	tenantReadResources  = []string{fornaxv1.ApplicationGrv.Resource, fornaxv1.ApplicationSessionGrv.Resource, fornaxv1.FornaxQuotaGrv.Resource, fornaxv1.SessionUsageGrv.Resource, fornaxv1.FornaxEventGrv.Resource}
+	tenantWriteResources = []string{fornaxv1.ApplicationGrv.Resource, fornaxv1.ApplicationSessionGrv.Resource}
+
+	// session subresources streaming into session containers, they are connected by get or create, and require edit role
+	sessionStreamSubresources = []string{"exec", "attach", "portforward"}
+
+	// non resource paths every authenticated user can get, e.g. discovery and health check
+	DefaultNonResourcePaths = []string{"/api", "/api/*", "/apis", "/apis/*", "/healthz", "/livez", "/readyz", "/version", "/openapi/*"}
+)
+
+// RoleBinding grant a role in namespaces to users and groups
+type RoleBinding struct {
+	Name       string   `json:"name"`
+	Users      []string `json:"users,omitempty"`
+	Groups     []string `json:"groups,omitempty"`
+	Namespaces []string `json:"namespaces"`
+	Role       Role     `json:"role"`
+}
+
+// RBACConfiguration, admins can do anything include cluster scoped requests and fornaxcore debug endpoints,
+// users in system:masters are always admin
+type RBACConfiguration struct {
+	AdminUsers  []string      `json:"adminUsers,omitempty"`
+	AdminGroups []string      `json:"adminGroups,omitempty"`
+	Bindings    []RoleBinding `json:"bindings,omitempty"`
+
+	// +optional, non resource paths authenticated users can get, default DefaultNonResourcePaths, a path ending with * match prefix
+	NonResourcePaths []string `json:"nonResourcePaths,omitempty"`
+}
+
+func (c *RBACConfiguration) validate() error {
+	for _, b := range c.Bindings {
+		if b.Role != RoleView && b.Role != RoleEdit {
+			return fmt.Errorf("unknown role %s of binding %s", b.Role, b.Name)
+		}
+		if len(b.Namespaces) == 0 {
+			return fmt.Errorf("binding %s has no namespace", b.Name)
+		}
+	}
+	return nil
+}
+
+// rbacAuthorizer authorize requests of fornax resources by namespace role bindings,
+// it has no opinion of users who are not admin and match no binding, they are decided by delegated authorizer if there is one, or denied,
+// a user matching any binding is denied out of its bindings
+type rbacAuthorizer struct {
+	config           *RBACConfiguration
+	nonResourcePaths []string
+}
+
+func NewRBACAuthorizer(config *RBACConfiguration) authorizer.Authorizer {
+	paths := config.NonResourcePaths
+	if len(paths) == 0 {
+		paths = DefaultNonResourcePaths
+	}
+	return &rbacAuthorizer{config: config, nonResourcePaths: paths}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSubject(u user.Info, users, groups []string) bool {
+	if contains(users, u.GetName()) {
+		return true
+	}
+	for _, g := range u.GetGroups() {
+		if contains(groups, g) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *rbacAuthorizer) isAdmin(u user.Info) bool {
+	return contains(u.GetGroups(), user.SystemPrivilegedGroup) || matchSubject(u, r.config.AdminUsers, r.config.AdminGroups)
+}
+
+func (r *rbacAuthorizer) allowNonResourcePath(p string) bool {
+	for _, v := range r.nonResourcePaths {
+		if v == p {
+			return true
+		}
+		if strings.HasSuffix(v, "*") && strings.HasPrefix(p, strings.TrimSuffix(v, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *RoleBinding) allow(a authorizer.Attributes) bool {
+	if a.GetNamespace() == "" || (!contains(b.Namespaces, AllNamespaces) && !contains(b.Namespaces, a.GetNamespace())) {
+		return false
+	}
+	if a.GetAPIGroup() != fornaxv1.ApplicationGrv.Group {
+		return false
+	}
+	if a.GetResource() == fornaxv1.ApplicationSessionGrv.Resource && contains(sessionStreamSubresources, a.GetSubresource()) {
+		return b.Role == RoleEdit && (a.GetVerb() == "get" || a.GetVerb() == "create")
+	}
+	if contains(readVerbs, a.GetVerb()) {
+		return contains(tenantReadResources, a.GetResource())
+	}
+	if b.Role == RoleEdit && contains(writeVerbs, a.GetVerb()) {
+		return a.GetSubresource() == "" && contains(tenantWriteResources, a.GetResource())
+	}
+	return false
+}
+
+// Authorize implement authorizer.Authorizer
+func (r *rbacAuthorizer) Authorize(ctx context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+	u := a.GetUser()
+	if u == nil {
+		return authorizer.DecisionNoOpinion, "", nil
+	}
+	if r.isAdmin(u) {
+		return authorizer.DecisionAllow, "", nil
+	}
+	if !a.IsResourceRequest() {
+		if a.GetVerb() == "get" && u.GetName() != user.Anonymous && r.allowNonResourcePath(a.GetPath()) {
+			return authorizer.DecisionAllow, "", nil
+		}
+		return authorizer.DecisionNoOpinion, "", nil
+	}
+	bound := false
+	for i := range r.config.Bindings {
+		binding := &r.config.Bindings[i]
+		if !matchSubject(u, binding.Users, binding.Groups) {
+			continue
+		}
+		bound = true
+		if binding.allow(a) {
+			return authorizer.DecisionAllow, fmt.Sprintf("allowed by binding %s", binding.Name), nil
+		}
+	}
+	if bound {
+		return authorizer.DecisionDeny, fmt.Sprintf("user %s is not allowed to %s %s in namespace %q", u.GetName(), a.GetVerb(), a.GetResource(), a.GetNamespace()), nil
+	}
+	return authorizer.DecisionNoOpinion, "", nil
+}
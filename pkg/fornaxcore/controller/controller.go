@@ -0,0 +1,154 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+
+	// register workqueue depth, latency and retry metrics
+	_ "k8s.io/component-base/metrics/prometheus/workqueue"
+)
+
+const (
+	DefaultRetryBaseDelay = 5 * time.Millisecond
+	DefaultRetryMaxDelay  = 1 * time.Minute
+
+	// overall retry rate of a controller, per key backoff still apply
+	DefaultRetryQPS   = 50
+	DefaultRetryBurst = 200
+)
+
+var (
+	controllerSyncs = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_controller",
+			Name:           "sync_total",
+			Help:           "Number of key syncs of fornaxcore controller by result",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"controller", "result"},
+	)
+	controllerSyncDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem:      "fornax_controller",
+			Name:           "sync_duration_seconds",
+			Help:           "Duration of a key sync of fornaxcore controller",
+			Buckets:        metrics.ExponentialBuckets(0.0001, 2, 16),
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"controller"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(controllerSyncs, controllerSyncDuration)
+}
+
+// SyncFunc reconcile object of key, a returned error requeue key with per key exponential backoff
+type SyncFunc func(ctx context.Context, key string) error
+
+// Controller is a rate limited work queue with parallel workers, a key is never synced by two workers at same time,
+// a failed key is retried with exponential backoff capped at DefaultRetryMaxDelay until it succeed, so a transient failure does not leave object stuck
+type Controller struct {
+	name    string
+	workers int
+	sync    SyncFunc
+	queue   workqueue.RateLimitingInterface
+}
+
+func NewRateLimiter() workqueue.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(DefaultRetryBaseDelay, DefaultRetryMaxDelay),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(DefaultRetryQPS), DefaultRetryBurst)},
+	)
+}
+
+func NewController(name string, workers int, sync SyncFunc) *Controller {
+	return &Controller{
+		name:    name,
+		workers: workers,
+		sync:    sync,
+		queue:   workqueue.NewNamedRateLimitingQueue(NewRateLimiter(), name),
+	}
+}
+
+// Enqueue add key to queue, a key already in queue is synced once
+func (c *Controller) Enqueue(key string) {
+	c.queue.Add(key)
+}
+
+// EnqueueAfter add key to queue after duration, used to recheck object later without counting it as a failure
+func (c *Controller) EnqueueAfter(key string, duration time.Duration) {
+	c.queue.AddAfter(key, duration)
+}
+
+// Retries return number of failed syncs of key since it last succeed
+func (c *Controller) Retries(key string) int {
+	return c.queue.NumRequeues(key)
+}
+
+func (c *Controller) Len() int {
+	return c.queue.Len()
+}
+
+// Run start workers, queue is shutdown when ctx is done
+func (c *Controller) Run(ctx context.Context) {
+	klog.InfoS("Starting controller", "controller", c.name, "workers", c.workers)
+	for i := 0; i < c.workers; i++ {
+		go wait.UntilWithContext(ctx, c.worker, time.Second)
+	}
+	go func() {
+		<-ctx.Done()
+		klog.InfoS("Shutting down controller", "controller", c.name)
+		c.queue.ShutDown()
+	}()
+}
+
+func (c *Controller) worker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	st := time.Now()
+	err := c.sync(ctx, key.(string))
+	controllerSyncDuration.WithLabelValues(c.name).Observe(time.Since(st).Seconds())
+	if err == nil {
+		controllerSyncs.WithLabelValues(c.name, "success").Inc()
+		c.queue.Forget(key)
+		return true
+	}
+
+	controllerSyncs.WithLabelValues(c.name, "error").Inc()
+	klog.ErrorS(err, "Failed to sync key, retry with backoff", "controller", c.name, "key", key, "retries", c.queue.NumRequeues(key))
+	c.queue.AddRateLimited(key)
+	return true
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	debuggrpc "centaurusinfra.io/fornax-serverless/pkg/nodeagent/debugservice/grpc"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/proxy"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/klog/v2"
+)
+
+// tunnelAddr is address of both ends of a tunnel connection
+type tunnelAddr string
+
+func (a tunnelAddr) Network() string { return "tunnel" }
+func (a tunnelAddr) String() string  { return string(a) }
+
+// tunnelConn is a connection to runtime streaming server of a node tunneled in a node agent grpc stream,
+// deadlines are not supported, connection is closed when stream is canceled
+type tunnelConn struct {
+	stream  debuggrpc.DebugService_TunnelClient
+	cancel  context.CancelFunc
+	address tunnelAddr
+	pending []byte
+}
+
+var _ net.Conn = &tunnelConn{}
+
+func (c *tunnelConn) Read(p []byte) (int, error) {
+	for len(c.pending) == 0 {
+		msg, err := c.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		c.pending = msg.Data
+	}
+	n := copy(p, c.pending)
+	c.pending = c.pending[n:]
+	return n, nil
+}
+
+func (c *tunnelConn) Write(p []byte) (int, error) {
+	// grpc keep reference of message until it's sent, so, send a copy
+	data := make([]byte, len(p))
+	copy(data, p)
+	if err := c.stream.Send(&debuggrpc.TunnelData{Data: data}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (c *tunnelConn) Close() error {
+	c.stream.CloseSend()
+	c.cancel()
+	return nil
+}
+
+func (c *tunnelConn) LocalAddr() net.Addr                { return c.address }
+func (c *tunnelConn) RemoteAddr() net.Addr               { return c.address }
+func (c *tunnelConn) SetDeadline(t time.Time) error      { return nil }
+func (c *tunnelConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *tunnelConn) SetWriteDeadline(t time.Time) error { return nil }
+
+func newContainerStreamRequest(podName string, opts runtime.Object) (*debuggrpc.ContainerStreamRequest, error) {
+	request := &debuggrpc.ContainerStreamRequest{PodIdentifier: podName}
+	switch o := opts.(type) {
+	case *v1.PodExecOptions:
+		request.StreamType = debuggrpc.StreamType_EXEC
+		request.Container, request.Command = o.Container, o.Command
+		request.Stdin, request.Stdout, request.Stderr, request.Tty = o.Stdin, o.Stdout, o.Stderr, o.TTY
+	case *v1.PodAttachOptions:
+		request.StreamType = debuggrpc.StreamType_ATTACH
+		request.Container = o.Container
+		request.Stdin, request.Stdout, request.Stderr, request.Tty = o.Stdin, o.Stdout, o.Stderr, o.TTY
+	case *v1.PodPortForwardOptions:
+		request.StreamType = debuggrpc.StreamType_PORT_FORWARD
+		request.Ports = o.Ports
+	default:
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("unknown stream options %T", opts))
+	}
+	return request, nil
+}
+
+// ContainerStream prepare a exec, attach or port forward stream of pod in container runtime of node, returned handler proxy upgraded
+// client connection to runtime streaming server through a node agent tunnel, opts is a pod exec, attach or port forward options
+func (p *DebugProxy) ContainerStream(ctx context.Context, podName string, opts runtime.Object, responder rest.Responder) (http.Handler, error) {
+	request, err := newContainerStreamRequest(podName, opts)
+	if err != nil {
+		return nil, err
+	}
+	nodeId, err := p.podNode(podName)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, address, err := p.dialNode(ctx, nodeId)
+	if err != nil {
+		return nil, err
+	}
+	client := debuggrpc.NewDebugServiceClient(conn)
+	resp, err := client.ContainerStream(ctx, request)
+	if err != nil {
+		conn.Close()
+		klog.ErrorS(err, "Failed to prepare container stream", "pod", podName, "type", request.StreamType, "address", address)
+		return nil, toAPIError(podName, err)
+	}
+	location, err := url.Parse(resp.Url)
+	if err != nil {
+		conn.Close()
+		return nil, apierrors.NewInternalError(err)
+	}
+
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			tunnelCtx, cancel := context.WithCancel(ctx)
+			stream, err := client.Tunnel(tunnelCtx)
+			if err == nil {
+				err = stream.Send(&debuggrpc.TunnelData{Url: resp.Url})
+			}
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			return &tunnelConn{stream: stream, cancel: cancel, address: tunnelAddr(address)}, nil
+		},
+	}
+	handler := proxy.NewUpgradeAwareHandler(location, transport, false, true, proxy.NewErrorResponder(responder))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer conn.Close()
+		userName := ""
+		if u, ok := genericapirequest.UserFrom(r.Context()); ok {
+			userName = u.GetName()
+		}
+		klog.InfoS("Proxy container stream", "pod", podName, "type", request.StreamType, "user", userName)
+		handler.ServeHTTP(w, r)
+	}), nil
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	ie "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/internal"
+	debuggrpc "centaurusinfra.io/fornax-serverless/pkg/nodeagent/debugservice/grpc"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	grpcstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+)
+
+var podGroupResource = schema.GroupResource{Resource: "pods"}
+
+// NodeCredentialsFunc return grpc credentials to call debug service of a node
+type NodeCredentialsFunc func(nodeId string) credentials.TransportCredentials
+
+// DebugProxy proxy debug requests of a pod to debug service of node agent where pod run,
+// node agent report its debug service port as kubelet endpoint of node status, debug service is only called over node mtls
+type DebugProxy struct {
+	podManager      ie.PodManagerInterface
+	nodeManager     ie.NodeManagerInterface
+	nodeCredentials NodeCredentialsFunc
+}
+
+func NewDebugProxy(podManager ie.PodManagerInterface, nodeManager ie.NodeManagerInterface) *DebugProxy {
+	return &DebugProxy{
+		podManager:  podManager,
+		nodeManager: nodeManager,
+	}
+}
+
+// SetNodeCredentials set credentials of node debug service calls, it's set if node pki is enabled, debug requests are rejected if not set
+func (p *DebugProxy) SetNodeCredentials(nodeCredentials NodeCredentialsFunc) {
+	p.nodeCredentials = nodeCredentials
+}
+
+// podNode return node where pod run, errors are api errors returned to api server clients
+func (p *DebugProxy) podNode(podName string) (string, error) {
+	pod := p.podManager.FindPod(podName)
+	if pod == nil {
+		return "", apierrors.NewNotFound(podGroupResource, podName)
+	}
+	nodeId, found := pod.GetLabels()[fornaxv1.LabelFornaxCoreNode]
+	if !found {
+		return "", apierrors.NewServiceUnavailable(fmt.Sprintf("pod %s is not scheduled to a node", podName))
+	}
+	return nodeId, nil
+}
+
+// dialNode connect debug service of a node using node mtls, it returns connection and address of debug service
+func (p *DebugProxy) dialNode(ctx context.Context, nodeId string) (*grpc.ClientConn, string, error) {
+	if p.nodeCredentials == nil {
+		return nil, "", apierrors.NewServiceUnavailable("node debug service require node pki to be enabled")
+	}
+	address, err := p.nodeDebugServiceAddress(nodeId)
+	if err != nil {
+		return nil, "", err
+	}
+	conn, err := grpc.DialContext(ctx, address, grpc.WithTransportCredentials(p.nodeCredentials(nodeId)))
+	if err != nil {
+		return nil, address, apierrors.NewServiceUnavailable(err.Error())
+	}
+	return conn, address, nil
+}
+
+// nodeDebugServiceAddress return debug service address of a connected node
+func (p *DebugProxy) nodeDebugServiceAddress(nodeId string) (string, error) {
+	node := p.nodeManager.FindNode(nodeId)
+	if node == nil || node.Node == nil || node.State == ie.NodeWorkingStateDisconnected {
+		return "", apierrors.NewServiceUnavailable(fmt.Sprintf("node %s is not connected", nodeId))
+	}
+	port := node.Node.Status.DaemonEndpoints.KubeletEndpoint.Port
+	if port <= 0 {
+		return "", apierrors.NewServiceUnavailable(fmt.Sprintf("node %s does not serve debug service", nodeId))
+	}
+	for _, v := range node.Node.Status.Addresses {
+		if v.Type == v1.NodeInternalIP {
+			return net.JoinHostPort(v.Address, strconv.Itoa(int(port))), nil
+		}
+	}
+	return "", apierrors.NewServiceUnavailable(fmt.Sprintf("node %s does not have a internal ip", nodeId))
+}
+
+func toAPIError(podName string, err error) error {
+	s, _ := grpcstatus.FromError(err)
+	switch s.Code() {
+	case codes.NotFound:
+		return apierrors.NewNotFound(podGroupResource, podName)
+	case codes.InvalidArgument:
+		return apierrors.NewBadRequest(s.Message())
+	case codes.PermissionDenied, codes.Unavailable, codes.DeadlineExceeded:
+		return apierrors.NewServiceUnavailable(s.Message())
+	default:
+		return apierrors.NewInternalError(err)
+	}
+}
+
+// logStreamReader read log content from node agent log stream, grpc connection is closed when reader is closed
+type logStreamReader struct {
+	stream  debuggrpc.DebugService_ContainerLogsClient
+	conn    *grpc.ClientConn
+	cancel  context.CancelFunc
+	pending []byte
+}
+
+func (r *logStreamReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		msg, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.pending = msg.Content
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func (r *logStreamReader) Close() error {
+	r.cancel()
+	return r.conn.Close()
+}
+
+// ContainerLogs open a log stream of a pod container, nil tail lines mean all lines,
+// it returns after node agent found container log, so, errors of pod or container not found are returned before streaming
+func (p *DebugProxy) ContainerLogs(ctx context.Context, podName string, opts *v1.PodLogOptions) (io.ReadCloser, error) {
+	nodeId, err := p.podNode(podName)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &debuggrpc.ContainerLogsRequest{
+		PodIdentifier: podName,
+		Container:     opts.Container,
+		Follow:        opts.Follow,
+		TailLines:     -1,
+		Timestamps:    opts.Timestamps,
+	}
+	if opts.TailLines != nil {
+		request.TailLines = *opts.TailLines
+	}
+	if opts.LimitBytes != nil {
+		request.LimitBytes = *opts.LimitBytes
+	}
+	if opts.SinceTime != nil {
+		request.SinceTime = timestamppb.New(opts.SinceTime.Time)
+	} else if opts.SinceSeconds != nil {
+		request.SinceTime = timestamppb.New(time.Now().Add(-time.Duration(*opts.SinceSeconds) * time.Second))
+	}
+
+	conn, address, err := p.dialNode(ctx, nodeId)
+	if err != nil {
+		return nil, err
+	}
+	streamCtx, cancel := context.WithCancel(ctx)
+	stream, err := debuggrpc.NewDebugServiceClient(conn).ContainerLogs(streamCtx, request)
+	if err == nil {
+		// first message is a empty ack
+		_, err = stream.Recv()
+	}
+	if err != nil {
+		cancel()
+		conn.Close()
+		klog.ErrorS(err, "Failed to open container log stream", "pod", podName, "container", opts.Container, "address", address)
+		return nil, toAPIError(podName, err)
+	}
+	return &logStreamReader{stream: stream, conn: conn, cancel: cancel}, nil
+}
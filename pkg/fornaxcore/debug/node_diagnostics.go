@@ -0,0 +1,117 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	debuggrpc "centaurusinfra.io/fornax-serverless/pkg/nodeagent/debugservice/grpc"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	NodeDiagnosticsPath = "/debug/fornaxcore/node/diagnostics"
+
+	// time to wait diagnostics of a node
+	DefaultNodeDiagnosticsTimeout = 10 * time.Second
+	// number of nodes asked for diagnostics at same time
+	nodeDiagnosticsConcurrency = 16
+)
+
+// NodeDiagnostics is diagnostics of a node agent, or error why it could not be got
+type NodeDiagnostics struct {
+	Node        string          `json:"node"`
+	State       string          `json:"state,omitempty"`
+	Error       string          `json:"error,omitempty"`
+	Diagnostics json.RawMessage `json:"diagnostics,omitempty"`
+}
+
+// NodeDiagnostics ask debug service of a node for its diagnostics
+func (p *DebugProxy) NodeDiagnostics(ctx context.Context, nodeId string, goroutines bool) (*debuggrpc.Diagnostics, error) {
+	ctx, cancel := context.WithTimeout(ctx, DefaultNodeDiagnosticsTimeout)
+	defer cancel()
+	conn, _, err := p.dialNode(ctx, nodeId)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return debuggrpc.NewDebugServiceClient(conn).Diagnostics(ctx, &debuggrpc.DiagnosticsRequest{Goroutines: goroutines})
+}
+
+// ListNodeDiagnostics get diagnostics of nodes, all known nodes if nodeIds is empty, a node which failed is reported with its error
+func (p *DebugProxy) ListNodeDiagnostics(ctx context.Context, nodeIds []string, goroutines bool) []*NodeDiagnostics {
+	if len(nodeIds) == 0 {
+		for _, v := range p.nodeManager.List() {
+			nodeIds = append(nodeIds, v.NodeId)
+		}
+	}
+	sort.Strings(nodeIds)
+	results := make([]*NodeDiagnostics, len(nodeIds))
+	sem := make(chan struct{}, nodeDiagnosticsConcurrency)
+	wg := sync.WaitGroup{}
+	for i, nodeId := range nodeIds {
+		result := &NodeDiagnostics{Node: nodeId}
+		if node := p.nodeManager.FindNode(nodeId); node != nil {
+			result.State = string(node.State)
+		}
+		results[i] = result
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			diagnostics, err := p.NodeDiagnostics(ctx, result.Node, goroutines)
+			if err == nil {
+				result.Diagnostics, err = protojson.Marshal(diagnostics)
+			}
+			if err != nil {
+				result.Error = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// NodeDiagnosticsHandler return diagnostics of all nodes or nodes in node param, goroutine dumps are included with goroutines=true,
+// e.g. kubectl get --raw "/debug/fornaxcore/node/diagnostics?node=node1&goroutines=true"
+type NodeDiagnosticsHandler struct {
+	proxy *DebugProxy
+}
+
+func NewNodeDiagnosticsHandler(proxy *DebugProxy) *NodeDiagnosticsHandler {
+	return &NodeDiagnosticsHandler{proxy: proxy}
+}
+
+func (h *NodeDiagnosticsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	results := h.proxy.ListNodeDiagnostics(r.Context(), r.URL.Query()["node"], r.URL.Query().Get("goroutines") == "true")
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
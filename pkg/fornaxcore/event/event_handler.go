@@ -0,0 +1,145 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	fornaxstore "centaurusinfra.io/fornax-serverless/pkg/store"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	EventsPath = "/fornaxcore/events"
+
+	// number of recent events returned when object is not specified
+	DefaultRecentEventsLimit = 500
+)
+
+// EventsHandler return events of a object, in kubectl describe format by default or as a event list with output=json,
+// e.g. kubectl get --raw "/fornaxcore/events?kind=ApplicationSession&object=<namespace>/<name>",
+// recent events of all objects are returned if kind and object are not specified, limit param change number of events
+type EventsHandler struct {
+	eventStore fornaxstore.ApiStorageInterface
+}
+
+func NewEventsHandler(eventStore fornaxstore.ApiStorageInterface) *EventsHandler {
+	return &EventsHandler{eventStore: eventStore}
+}
+
+func (h *EventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	kind, object := r.URL.Query().Get("kind"), r.URL.Query().Get("object")
+	if len(kind) == 0 && len(object) == 0 {
+		h.serveRecentEvents(w, r)
+		return
+	}
+	if len(kind) == 0 || len(object) == 0 {
+		http.Error(w, "kind and object are required", http.StatusBadRequest)
+		return
+	}
+	namespace, name, err := cache.SplitMetaNamespaceKey(object)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	events, err := ListEvents(r.Context(), h.eventStore, kind, namespace, name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if r.URL.Query().Get("output") == "json" {
+		data, err := json.MarshalIndent(&fornaxv1.FornaxEventList{Items: events}, "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Fprintf(w, "Kind:       %s\nName:       %s\nNamespace:  %s\n", kind, name, namespace)
+	w.Write([]byte(DescribeEvents(events, time.Now())))
+}
+
+// DescribeEvents format events as events section of kubectl describe
+func DescribeEvents(events []fornaxv1.FornaxEvent, now time.Time) string {
+	if len(events) == 0 {
+		return "Events:     <none>\n"
+	}
+	buf := &strings.Builder{}
+	buf.WriteString("Events:\n")
+	tw := tabwriter.NewWriter(buf, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "  Type\tReason\tAge\tFrom\tMessage\n")
+	fmt.Fprintf(tw, "  ----\t------\t----\t----\t-------\n")
+	for _, e := range events {
+		age := duration.HumanDuration(now.Sub(e.LastTimestamp.Time))
+		if e.Count > 1 {
+			age = fmt.Sprintf("%s (x%d over %s)", age, e.Count, duration.HumanDuration(now.Sub(e.FirstTimestamp.Time)))
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\n", e.Type, e.Reason, age, e.Source, strings.TrimSpace(e.Message))
+	}
+	tw.Flush()
+	return buf.String()
+}
+
+func (h *EventsHandler) serveRecentEvents(w http.ResponseWriter, r *http.Request) {
+	limit := DefaultRecentEventsLimit
+	if v := r.URL.Query().Get("limit"); len(v) > 0 {
+		l, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid limit %s", v), http.StatusBadRequest)
+			return
+		}
+		limit = l
+	}
+	events, err := ListRecentEvents(r.Context(), h.eventStore, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if r.URL.Query().Get("output") == "json" {
+		data, err := json.MarshalIndent(&fornaxv1.FornaxEventList{Items: events}, "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+		return
+	}
+	now := time.Now()
+	w.Header().Set("Content-Type", "text/plain")
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "Object\tType\tReason\tAge\tFrom\tMessage\n")
+	for _, e := range events {
+		object := e.InvolvedObject.Kind + "/" + e.InvolvedObject.Name
+		if len(e.InvolvedObject.Namespace) > 0 {
+			object = e.InvolvedObject.Kind + "/" + e.InvolvedObject.Namespace + "/" + e.InvolvedObject.Name
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", object, e.Type, e.Reason, duration.HumanDuration(now.Sub(e.LastTimestamp.Time)), e.Source, strings.TrimSpace(e.Message))
+	}
+	tw.Flush()
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package event
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	fornaxstore "centaurusinfra.io/fornax-serverless/pkg/store"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/lru"
+)
+
+const (
+	SourceApplicationManager = "application-manager"
+	SourcePodScheduler       = "pod-scheduler"
+	SourceNodeLease          = "node-lease-controller"
+	SourceNodeMonitor        = "node-monitor"
+
+	// EventInvolvedObjectIndex index events by kind/namespace/name of involved object
+	EventInvolvedObjectIndex = "f:involvedObject"
+
+	// events are deleted one hour after they were first recorded
+	DefaultEventTTLSeconds = 3600
+
+	// a event same as one recorded in window is aggregated into it
+	DefaultEventAggregationWindow    = 10 * time.Minute
+	DefaultEventAggregationCacheSize = 4096
+
+	// recording never block controllers, events are dropped when queue is full
+	DefaultEventQueueSize = 1024
+)
+
+var (
+	recordedEvents = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_event",
+			Name:           "recorded_total",
+			Help:           "Number of events recorded by result, aggregated events are counted as updated",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"result"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(recordedEvents)
+}
+
+var eventIndexers = cache.Indexers{
+	EventInvolvedObjectIndex: func(obj interface{}) ([]string, error) {
+		event, ok := obj.(*fornaxv1.FornaxEvent)
+		if !ok {
+			return nil, fmt.Errorf("not a valid fornax FornaxEvent")
+		}
+		return []string{involvedObjectKey(event.InvolvedObject.Kind, event.InvolvedObject.Namespace, event.InvolvedObject.Name)}, nil
+	},
+}
+
+func involvedObjectKey(kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
+
+func ApplicationRef(application *fornaxv1.Application) fornaxv1.FornaxObjectReference {
+	return fornaxv1.FornaxObjectReference{Kind: "Application", Namespace: application.Namespace, Name: application.Name, UID: string(application.UID)}
+}
+
+func SessionRef(session *fornaxv1.ApplicationSession) fornaxv1.FornaxObjectReference {
+	return fornaxv1.FornaxObjectReference{Kind: "ApplicationSession", Namespace: session.Namespace, Name: session.Name, UID: string(session.UID)}
+}
+
+func PodRef(pod *v1.Pod) fornaxv1.FornaxObjectReference {
+	return fornaxv1.FornaxObjectReference{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name, UID: string(pod.UID)}
+}
+
+// NodeRef refer to a node by its id, fornaxcore track nodes by id only
+func NodeRef(nodeId string) fornaxv1.FornaxObjectReference {
+	return fornaxv1.FornaxObjectReference{Kind: "Node", Name: nodeId}
+}
+
+// NodeAgentSource is source of events reported by node agent of a node
+func NodeAgentSource(nodeId string) string {
+	return "nodeagent/" + nodeId
+}
+
+// eventNamespace return namespace of events of object, events of cluster scoped objects are put in default namespace
+func eventNamespace(ref fornaxv1.FornaxObjectReference) string {
+	if len(ref.Namespace) == 0 {
+		return fornaxv1.FornaxEventClusterNamespace
+	}
+	return ref.Namespace
+}
+
+type aggregatedEvent struct {
+	namespace string
+	name      string
+	last      time.Time
+}
+
+// Recorder save events in store asynchronously, it aggregate a event same as one recorded in aggregation window
+// by increasing count, only one recorder goroutine write events, so aggregation does not race
+type Recorder struct {
+	ctx        context.Context
+	store      fornaxstore.ApiStorageInterface
+	queue      chan *fornaxv1.FornaxEvent
+	aggregated *lru.Cache
+	now        func() time.Time
+}
+
+func NewRecorder(ctx context.Context, store fornaxstore.ApiStorageInterface) *Recorder {
+	if err := store.AddIndexers(eventIndexers); err != nil {
+		klog.ErrorS(err, "Failed to add event indexers")
+	}
+	return &Recorder{
+		ctx:        ctx,
+		store:      store,
+		queue:      make(chan *fornaxv1.FornaxEvent, DefaultEventQueueSize),
+		aggregated: lru.New(DefaultEventAggregationCacheSize),
+		now:        time.Now,
+	}
+}
+
+// Eventf queue a event of object, it never block
+func (r *Recorder) Eventf(ref fornaxv1.FornaxObjectReference, eventType, source, reason, messageFmt string, args ...interface{}) {
+	now := metav1.NewTime(r.now())
+	event := &fornaxv1.FornaxEvent{
+		InvolvedObject: ref,
+		Type:           eventType,
+		Reason:         reason,
+		Message:        fmt.Sprintf(messageFmt, args...),
+		Source:         source,
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	select {
+	case r.queue <- event:
+	default:
+		recordedEvents.WithLabelValues("dropped").Inc()
+	}
+}
+
+func (r *Recorder) Run() {
+	go func() {
+		for {
+			select {
+			case <-r.ctx.Done():
+				return
+			case event := <-r.queue:
+				if err := r.save(event); err != nil {
+					recordedEvents.WithLabelValues("error").Inc()
+					klog.ErrorS(err, "Failed to save event", "object", event.InvolvedObject, "reason", event.Reason)
+				}
+			}
+		}
+	}()
+}
+
+func aggregationKey(event *fornaxv1.FornaxEvent) string {
+	ref := event.InvolvedObject
+	return fmt.Sprintf("%s/%s/%s/%s/%s/%s/%s/%s", ref.Kind, ref.Namespace, ref.Name, ref.UID, event.Type, event.Source, event.Reason, event.Message)
+}
+
+func (r *Recorder) save(event *fornaxv1.FornaxEvent) error {
+	aggKey := aggregationKey(event)
+	if v, found := r.aggregated.Get(aggKey); found {
+		agg := v.(*aggregatedEvent)
+		if event.LastTimestamp.Sub(agg.last) < DefaultEventAggregationWindow {
+			err := r.aggregate(agg, event)
+			if err == nil {
+				agg.last = event.LastTimestamp.Time
+				r.aggregated.Add(aggKey, agg)
+				recordedEvents.WithLabelValues("updated").Inc()
+				return nil
+			}
+			if !fornaxstore.IsObjectNotFoundErr(err) && !apistorage.IsNotFound(err) {
+				return err
+			}
+			// aggregated event expired, record a new one
+		}
+	}
+
+	event.TypeMeta = metav1.TypeMeta{Kind: "FornaxEvent", APIVersion: fornaxv1.FornaxEventGrv.GroupVersion().String()}
+	event.Namespace = eventNamespace(event.InvolvedObject)
+	event.Name = fmt.Sprintf("%s.%x", event.InvolvedObject.Name, event.FirstTimestamp.UnixNano())
+	event.CreationTimestamp = event.FirstTimestamp
+	if len(event.InvolvedObject.UID) > 0 {
+		event.Labels = map[string]string{fornaxv1.LabelFornaxCoreEventInvolvedUID: event.InvolvedObject.UID}
+	}
+	key := fmt.Sprintf("%s/%s/%s", fornaxv1.FornaxEventGrvKey, event.Namespace, event.Name)
+	if err := r.store.Create(r.ctx, key, event, &fornaxv1.FornaxEvent{}, DefaultEventTTLSeconds); err != nil {
+		return err
+	}
+	r.aggregated.Add(aggKey, &aggregatedEvent{namespace: event.Namespace, name: event.Name, last: event.LastTimestamp.Time})
+	recordedEvents.WithLabelValues("created").Inc()
+	return nil
+}
+
+func (r *Recorder) aggregate(agg *aggregatedEvent, event *fornaxv1.FornaxEvent) error {
+	key := fmt.Sprintf("%s/%s/%s", fornaxv1.FornaxEventGrvKey, agg.namespace, agg.name)
+	return r.store.GuaranteedUpdate(r.ctx, key, &fornaxv1.FornaxEvent{}, false, nil, func(input runtime.Object, res apistorage.ResponseMeta) (runtime.Object, *uint64, error) {
+		updated := input.(*fornaxv1.FornaxEvent).DeepCopy()
+		updated.Count++
+		updated.LastTimestamp = event.LastTimestamp
+		return updated, nil, nil
+	}, nil)
+}
+
+// ListEvents return events of a object sorted by last timestamp
+func ListEvents(ctx context.Context, store fornaxstore.ApiStorageInterface, kind, namespace, name string) ([]fornaxv1.FornaxEvent, error) {
+	list := &fornaxv1.FornaxEventList{}
+	if err := store.ListByIndex(ctx, fornaxv1.FornaxEventGrvKey, EventInvolvedObjectIndex, involvedObjectKey(kind, namespace, name), list); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(list.Items, func(i, j int) bool {
+		return list.Items[i].LastTimestamp.Before(&list.Items[j].LastTimestamp)
+	})
+	return list.Items, nil
+}
+
+// ListRecentEvents return last limit events of all objects sorted by last timestamp, all events are returned if limit is not positive
+func ListRecentEvents(ctx context.Context, store fornaxstore.ApiStorageInterface, limit int) ([]fornaxv1.FornaxEvent, error) {
+	list := &fornaxv1.FornaxEventList{}
+	if err := store.GetList(ctx, fornaxv1.FornaxEventGrvKey, apistorage.ListOptions{Predicate: apistorage.Everything, Recursive: true}, list); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(list.Items, func(i, j int) bool {
+		return list.Items[i].LastTimestamp.Before(&list.Items[j].LastTimestamp)
+	})
+	if limit > 0 && len(list.Items) > limit {
+		return list.Items[len(list.Items)-limit:], nil
+	}
+	return list.Items, nil
+}
+
+var (
+	defaultRecorderMu sync.RWMutex
+	defaultRecorder   *Recorder
+)
+
+// SetRecorder set recorder used by Eventf, events are not recorded until it's set
+func SetRecorder(r *Recorder) {
+	defaultRecorderMu.Lock()
+	defer defaultRecorderMu.Unlock()
+	defaultRecorder = r
+}
+
+// Eventf record a event of object using default recorder
+func Eventf(ref fornaxv1.FornaxObjectReference, eventType, source, reason, messageFmt string, args ...interface{}) {
+	defaultRecorderMu.RLock()
+	r := defaultRecorder
+	defaultRecorderMu.RUnlock()
+	if r != nil {
+		r.Eventf(ref, eventType, source, reason, messageFmt, args...)
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package event
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	fornaxstore "centaurusinfra.io/fornax-serverless/pkg/store"
+	"centaurusinfra.io/fornax-serverless/pkg/store/inmemory"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newTestRecorder(t *testing.T) *Recorder {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	gr := fornaxv1.FornaxEventGrv.GroupResource()
+	store := inmemory.NewMemoryStore(ctx, gr, fornaxv1.FornaxEventGrvKey,
+		func() runtime.Object { return &fornaxv1.FornaxEvent{} },
+		func() runtime.Object { return &fornaxv1.FornaxEventList{} },
+		fornaxstore.DefaultResourceStorageConfiguration(gr))
+	return NewRecorder(ctx, store)
+}
+
+// flush save queued events synchronously
+func flush(t *testing.T, r *Recorder) {
+	for {
+		select {
+		case e := <-r.queue:
+			if err := r.save(e); err != nil {
+				t.Fatal(err)
+			}
+		default:
+			return
+		}
+	}
+}
+
+func TestRecordAndAggregateEvents(t *testing.T) {
+	r := newTestRecorder(t)
+	now := time.Date(2022, 10, 1, 10, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+	session := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "s1", UID: "uid-1"}}
+
+	r.Eventf(SessionRef(session), fornaxv1.FornaxEventTypeNormal, SourceApplicationManager, "WaitingForPod", "No idle pod of application")
+	now = now.Add(time.Minute)
+	r.Eventf(SessionRef(session), fornaxv1.FornaxEventTypeNormal, SourceApplicationManager, "WaitingForPod", "No idle pod of application")
+	now = now.Add(time.Minute)
+	r.Eventf(SessionRef(session), fornaxv1.FornaxEventTypeNormal, SourceApplicationManager, "Assigned", "Assigned session to pod %s", "ns/pod1")
+	// same event out of aggregation window is a new event
+	now = now.Add(DefaultEventAggregationWindow + time.Minute)
+	r.Eventf(SessionRef(session), fornaxv1.FornaxEventTypeNormal, SourceApplicationManager, "WaitingForPod", "No idle pod of application")
+	// events of other objects are not listed
+	r.Eventf(NodeRef("node1"), fornaxv1.FornaxEventTypeWarning, SourceNodeLease, "NodeLost", "Node did not renew lease")
+	flush(t, r)
+
+	events, err := ListEvents(context.Background(), r.store, "ApplicationSession", "ns", "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events of session, got %v", events)
+	}
+	if events[0].Reason != "WaitingForPod" || events[0].Count != 2 || events[0].LastTimestamp.Sub(events[0].FirstTimestamp.Time) != time.Minute {
+		t.Errorf("expected first event aggregated, got %v", events[0])
+	}
+	if events[1].Reason != "Assigned" || events[1].Message != "Assigned session to pod ns/pod1" || events[1].Labels[fornaxv1.LabelFornaxCoreEventInvolvedUID] != "uid-1" {
+		t.Errorf("unexpected second event %v", events[1])
+	}
+	if events[2].Reason != "WaitingForPod" || events[2].Count != 1 {
+		t.Errorf("expected a new event out of aggregation window, got %v", events[2])
+	}
+
+	nodeEvents, err := ListEvents(context.Background(), r.store, "Node", "", "node1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodeEvents) != 1 || nodeEvents[0].Namespace != fornaxv1.FornaxEventClusterNamespace {
+		t.Errorf("expected node event in default namespace, got %v", nodeEvents)
+	}
+
+	recent, err := ListRecentEvents(context.Background(), r.store, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 2 || !recent[0].LastTimestamp.Time.Equal(now) || !recent[1].LastTimestamp.Time.Equal(now) {
+		t.Errorf("expected last 2 events of all objects, got %v", recent)
+	}
+
+	out := DescribeEvents(events, now)
+	if !strings.Contains(out, "WaitingForPod") || !strings.Contains(out, "(x2 over 13m)") || !strings.Contains(out, SourceApplicationManager) {
+		t.Errorf("unexpected describe output\n%s", out)
+	}
+}
+
+func TestEventfDropWhenQueueFull(t *testing.T) {
+	r := newTestRecorder(t)
+	for i := 0; i < DefaultEventQueueSize+10; i++ {
+		r.Eventf(NodeRef("node1"), fornaxv1.FornaxEventTypeWarning, SourceNodeLease, "NodeLost", "lost %d", i)
+	}
+	if len(r.queue) != DefaultEventQueueSize {
+		t.Errorf("expected events dropped when queue is full, got %d queued", len(r.queue))
+	}
+}
@@ -0,0 +1,384 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/apiserver-runtime/pkg/builder/resource"
+)
+
+const (
+	DefaultConversionWebhookTimeoutSeconds = 10
+
+	// conversion review is wire compatible with apiextensions.k8s.io/v1, so, a crd conversion webhook work as a extension webhook
+	ConversionReviewAPIVersion = "apiextensions.k8s.io/v1"
+	ConversionReviewKind       = "ConversionReview"
+)
+
+var (
+	conversionWebhookRequests = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_extension_conversion",
+			Name:           "webhook_requests_total",
+			Help:           "Number of conversion requests sent to conversion webhook of a extension resource",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource", "result"},
+	)
+	conversionWebhookLatency = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem:      "fornax_extension_conversion",
+			Name:           "webhook_duration_seconds",
+			Help:           "Latency of conversion webhook of a extension resource",
+			Buckets:        metrics.ExponentialBuckets(0.001, 2, 14),
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(conversionWebhookRequests, conversionWebhookLatency)
+}
+
+// ConversionWebhook convert objects of a extension resource between versions,
+// a extension without webhook is converted by changing apiVersion only, as crd None conversion strategy
+type ConversionWebhook struct {
+	Group    string `json:"group"`
+	Resource string `json:"resource"`
+
+	// https url conversion review is posted to
+	URL string `json:"url"`
+
+	// pem encoded ca certificates to verify webhook server, system roots are used if empty
+	// +optional
+	CABundle string `json:"caBundle,omitempty"`
+
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// ConversionWebhookConfiguration is conversion webhooks of extension resources
+type ConversionWebhookConfiguration struct {
+	Webhooks []ConversionWebhook `json:"webhooks,omitempty"`
+}
+
+// LoadConversionWebhookConfiguration read conversion webhooks from a json file, no webhook is configured if file does not exist
+func LoadConversionWebhookConfiguration(file string) (*ConversionWebhookConfiguration, error) {
+	config := &ConversionWebhookConfiguration{}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// ConversionReview, ConversionRequest and ConversionResponse are same as apiextensions.k8s.io/v1 types
+type ConversionReview struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	Request *ConversionRequest `json:"request,omitempty"`
+	// +optional
+	Response *ConversionResponse `json:"response,omitempty"`
+}
+
+type ConversionRequest struct {
+	UID               types.UID              `json:"uid"`
+	DesiredAPIVersion string                 `json:"desiredAPIVersion"`
+	Objects           []runtime.RawExtension `json:"objects"`
+}
+
+type ConversionResponse struct {
+	UID              types.UID              `json:"uid"`
+	ConvertedObjects []runtime.RawExtension `json:"convertedObjects"`
+	Result           metav1.Status          `json:"result"`
+}
+
+type webhookClient struct {
+	url     string
+	client  *http.Client
+	timeout time.Duration
+}
+
+var (
+	webhooksMu sync.RWMutex
+	webhooks   = map[schema.GroupResource]*webhookClient{}
+)
+
+// SetConversionWebhooks replace conversion webhooks of extension resources
+func SetConversionWebhooks(config *ConversionWebhookConfiguration) error {
+	clients := map[schema.GroupResource]*webhookClient{}
+	for _, v := range config.Webhooks {
+		gr := schema.GroupResource{Group: v.Group, Resource: v.Resource}
+		u, err := url.Parse(v.URL)
+		if err != nil || u.Scheme != "https" || len(u.Host) == 0 {
+			return fmt.Errorf("conversion webhook of %s must be a https url, got %s", gr, v.URL)
+		}
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if len(v.CABundle) > 0 {
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM([]byte(v.CABundle)) {
+				return fmt.Errorf("invalid ca bundle of conversion webhook of %s", gr)
+			}
+			tlsConfig.RootCAs = pool
+		}
+		timeout := time.Duration(v.TimeoutSeconds) * time.Second
+		if timeout <= 0 {
+			timeout = DefaultConversionWebhookTimeoutSeconds * time.Second
+		}
+		clients[gr] = &webhookClient{
+			url:     v.URL,
+			client:  &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}},
+			timeout: timeout,
+		}
+	}
+	webhooksMu.Lock()
+	defer webhooksMu.Unlock()
+	webhooks = clients
+	return nil
+}
+
+func getWebhook(gr schema.GroupResource) *webhookClient {
+	webhooksMu.RLock()
+	defer webhooksMu.RUnlock()
+	return webhooks[gr]
+}
+
+// ConvertToStorageVersion convert a extension object to its storage version, it implement ConvertToStorageVersion of MultiVersionObject
+func ConvertToStorageVersion(obj resource.Object, storageObj runtime.Object) error {
+	out, ok := storageObj.(resource.Object)
+	if !ok {
+		return fmt.Errorf("storage version %T is not a resource object", storageObj)
+	}
+	return convertObjects([]resource.Object{obj}, []resource.Object{out})
+}
+
+// ConvertFromStorageVersion convert a storage version object to a extension version, it implement ConvertFromStorageVersion of MultiVersionObject
+func ConvertFromStorageVersion(storageObj runtime.Object, obj resource.Object) error {
+	in, ok := storageObj.(resource.Object)
+	if !ok {
+		return fmt.Errorf("storage version %T is not a resource object", storageObj)
+	}
+	return convertObjects([]resource.Object{in}, []resource.Object{obj})
+}
+
+// convertList convert items of a list to version of target and set them in out list
+func convertList(in, out runtime.Object, target resource.Object) error {
+	items, err := meta.ExtractList(in)
+	if err != nil {
+		return err
+	}
+	from := make([]resource.Object, 0, len(items))
+	to := make([]resource.Object, 0, len(items))
+	for _, v := range items {
+		obj, ok := v.(resource.Object)
+		if !ok {
+			return fmt.Errorf("list item %T is not a resource object", v)
+		}
+		from = append(from, obj)
+		to = append(to, target.New().(resource.Object))
+	}
+	if err := convertObjects(from, to); err != nil {
+		return err
+	}
+	converted := make([]runtime.Object, 0, len(to))
+	for _, v := range to {
+		converted = append(converted, v)
+	}
+	if err := meta.SetList(out, converted); err != nil {
+		return err
+	}
+	inList, err := meta.ListAccessor(in)
+	if err != nil {
+		return err
+	}
+	outList, err := meta.ListAccessor(out)
+	if err != nil {
+		return err
+	}
+	outList.SetResourceVersion(inList.GetResourceVersion())
+	outList.SetContinue(inList.GetContinue())
+	outList.SetRemainingItemCount(inList.GetRemainingItemCount())
+	outList.SetSelfLink(inList.GetSelfLink())
+	return nil
+}
+
+// convertObjects convert objects of one version into out objects of another version of same extension,
+// only labels and annotations may be changed by webhook, other metadata are kept as crd conversion does
+func convertObjects(in []resource.Object, out []resource.Object) error {
+	if len(in) == 0 {
+		return nil
+	}
+	from, to := gvkOf(in[0]), gvkOf(out[0])
+	raws := make([][]byte, 0, len(in))
+	for _, v := range in {
+		raw, err := encodeObject(v, from)
+		if err != nil {
+			return err
+		}
+		raws = append(raws, raw)
+	}
+
+	gr := in[0].GetGroupVersionResource().GroupResource()
+	converted := raws
+	if from.GroupVersion() != to.GroupVersion() {
+		if webhook := getWebhook(gr); webhook != nil {
+			var err error
+			if converted, err = webhook.convert(gr, raws, to); err != nil {
+				return err
+			}
+		} else {
+			// no webhook, only apiVersion is changed
+			converted = make([][]byte, 0, len(raws))
+			for i := range in {
+				raw, err := encodeObject(in[i], to)
+				if err != nil {
+					return err
+				}
+				converted = append(converted, raw)
+			}
+		}
+	}
+
+	for i := range out {
+		v := reflect.ValueOf(out[i]).Elem()
+		v.Set(reflect.Zero(v.Type()))
+		if err := json.Unmarshal(converted[i], out[i]); err != nil {
+			return fmt.Errorf("failed to decode %s converted from %s, %v", to, from, err)
+		}
+		outMeta := out[i].GetObjectMeta()
+		labels, annotations := outMeta.Labels, outMeta.Annotations
+		in[i].GetObjectMeta().DeepCopyInto(outMeta)
+		outMeta.Labels, outMeta.Annotations = labels, annotations
+	}
+	return nil
+}
+
+// encodeObject return json of a object with apiVersion and kind of gvk
+func encodeObject(obj resource.Object, gvk schema.GroupVersionKind) ([]byte, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	fields["apiVersion"], fields["kind"] = gvk.GroupVersion().String(), gvk.Kind
+	return json.Marshal(fields)
+}
+
+func (w *webhookClient) convert(gr schema.GroupResource, objects [][]byte, desired schema.GroupVersionKind) (converted [][]byte, err error) {
+	start := time.Now()
+	defer func() {
+		result := "success"
+		if err != nil {
+			result = "failure"
+			klog.ErrorS(err, "Extension conversion webhook failed", "resource", gr, "url", w.url)
+		}
+		conversionWebhookRequests.WithLabelValues(gr.String(), result).Inc()
+		conversionWebhookLatency.WithLabelValues(gr.String()).Observe(time.Since(start).Seconds())
+	}()
+
+	review := &ConversionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: ConversionReviewAPIVersion, Kind: ConversionReviewKind},
+		Request: &ConversionRequest{
+			UID:               uuid.NewUUID(),
+			DesiredAPIVersion: desired.GroupVersion().String(),
+		},
+	}
+	for _, v := range objects {
+		review.Request.Objects = append(review.Request.Objects, runtime.RawExtension{Raw: v})
+	}
+	body, err := json.Marshal(review)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("conversion webhook of %s failed, %v", gr, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("conversion webhook of %s returned status %d, %s", gr, resp.StatusCode, string(data))
+	}
+
+	result := &ConversionReview{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, fmt.Errorf("invalid conversion review from webhook of %s, %v", gr, err)
+	}
+	if result.Response == nil || result.Response.UID != review.Request.UID {
+		return nil, fmt.Errorf("conversion webhook of %s returned response of another request", gr)
+	}
+	if result.Response.Result.Status != metav1.StatusSuccess {
+		return nil, fmt.Errorf("conversion webhook of %s failed to convert to %s, %s", gr, review.Request.DesiredAPIVersion, result.Response.Result.Message)
+	}
+	if len(result.Response.ConvertedObjects) != len(objects) {
+		return nil, fmt.Errorf("conversion webhook of %s returned %d objects, expected %d", gr, len(result.Response.ConvertedObjects), len(objects))
+	}
+	for _, v := range result.Response.ConvertedObjects {
+		typeMeta := metav1.TypeMeta{}
+		if err := json.Unmarshal(v.Raw, &typeMeta); err != nil {
+			return nil, err
+		}
+		if typeMeta.APIVersion != review.Request.DesiredAPIVersion || typeMeta.Kind != desired.Kind {
+			return nil, fmt.Errorf("conversion webhook of %s returned %s %s, expected %s %s", gr, typeMeta.APIVersion, typeMeta.Kind, review.Request.DesiredAPIVersion, desired.Kind)
+		}
+		converted = append(converted, v.Raw)
+	}
+	return converted, nil
+}
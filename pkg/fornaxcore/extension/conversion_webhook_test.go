@@ -0,0 +1,236 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/apiserver-runtime/pkg/builder/resource"
+)
+
+// Widget is storage version of a test extension, WidgetV2 rename size to replicas
+type Widget struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		Size int `json:"size,omitempty"`
+	} `json:"spec,omitempty"`
+}
+
+type WidgetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Widget `json:"items"`
+}
+
+type WidgetV2 struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		Replicas int `json:"replicas,omitempty"`
+	} `json:"spec,omitempty"`
+}
+
+type WidgetV2List struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WidgetV2 `json:"items"`
+}
+
+var widgetGroupResource = schema.GroupResource{Group: "widgets.example.com", Resource: "widgets"}
+
+func (in *Widget) GetObjectMeta() *metav1.ObjectMeta { return &in.ObjectMeta }
+func (in *Widget) NamespaceScoped() bool             { return true }
+func (in *Widget) New() runtime.Object               { return &Widget{} }
+func (in *Widget) NewList() runtime.Object           { return &WidgetList{} }
+func (in *Widget) IsStorageVersion() bool            { return true }
+func (in *Widget) GetGroupVersionResource() schema.GroupVersionResource {
+	return widgetGroupResource.WithVersion("v1")
+}
+func (in *Widget) DeepCopyObject() runtime.Object {
+	out := *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	return &out
+}
+func (in *WidgetList) DeepCopyObject() runtime.Object {
+	out := *in
+	out.Items = append([]Widget{}, in.Items...)
+	return &out
+}
+
+func (in *WidgetV2) GetObjectMeta() *metav1.ObjectMeta { return &in.ObjectMeta }
+func (in *WidgetV2) NamespaceScoped() bool             { return true }
+func (in *WidgetV2) New() runtime.Object               { return &WidgetV2{} }
+func (in *WidgetV2) NewList() runtime.Object           { return &WidgetV2List{} }
+func (in *WidgetV2) IsStorageVersion() bool            { return false }
+func (in *WidgetV2) GetGroupVersionResource() schema.GroupVersionResource {
+	return widgetGroupResource.WithVersion("v2")
+}
+func (in *WidgetV2) DeepCopyObject() runtime.Object {
+	out := *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	return &out
+}
+func (in *WidgetV2) NewStorageVersionObject() runtime.Object { return &Widget{} }
+func (in *WidgetV2) ConvertToStorageVersion(storageObj runtime.Object) error {
+	return ConvertToStorageVersion(in, storageObj)
+}
+func (in *WidgetV2) ConvertFromStorageVersion(storageObj runtime.Object) error {
+	return ConvertFromStorageVersion(storageObj, in)
+}
+func (in *WidgetV2List) DeepCopyObject() runtime.Object {
+	out := *in
+	out.Items = append([]WidgetV2{}, in.Items...)
+	return &out
+}
+
+var _ resource.MultiVersionObject = &WidgetV2{}
+
+// widgetWebhook convert widgets between v1 and v2, it also change metadata which should be ignored except labels
+func widgetWebhook(t *testing.T, fail bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review := &ConversionReview{}
+		if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+			t.Fatal(err)
+		}
+		response := &ConversionResponse{UID: review.Request.UID, Result: metav1.Status{Status: metav1.StatusSuccess}}
+		if fail {
+			response.Result = metav1.Status{Status: metav1.StatusFailure, Message: "unsupported version"}
+		}
+		for _, v := range review.Request.Objects {
+			obj := map[string]interface{}{}
+			json.Unmarshal(v.Raw, &obj)
+			spec := obj["spec"].(map[string]interface{})
+			if review.Request.DesiredAPIVersion == "widgets.example.com/v1" {
+				obj["kind"] = "Widget"
+				spec["size"], spec["replicas"] = spec["replicas"], nil
+			} else {
+				obj["kind"] = "WidgetV2"
+				spec["replicas"], spec["size"] = spec["size"], nil
+			}
+			obj["apiVersion"] = review.Request.DesiredAPIVersion
+			metadata := obj["metadata"].(map[string]interface{})
+			metadata["name"] = "renamed"
+			metadata["labels"] = map[string]interface{}{"converted": "true"}
+			raw, _ := json.Marshal(obj)
+			response.ConvertedObjects = append(response.ConvertedObjects, runtime.RawExtension{Raw: raw})
+		}
+		json.NewEncoder(w).Encode(&ConversionReview{TypeMeta: review.TypeMeta, Response: response})
+	}
+}
+
+func setWidgetWebhook(t *testing.T, fail bool) func() {
+	server := httptest.NewTLSServer(widgetWebhook(t, fail))
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := SetConversionWebhooks(&ConversionWebhookConfiguration{Webhooks: []ConversionWebhook{{
+		Group: widgetGroupResource.Group, Resource: widgetGroupResource.Resource, URL: server.URL, CABundle: string(caBundle),
+	}}}); err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		server.Close()
+		SetConversionWebhooks(&ConversionWebhookConfiguration{})
+	}
+}
+
+func TestConvertUsingWebhook(t *testing.T) {
+	defer setWidgetWebhook(t, false)()
+
+	in := &WidgetV2{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "w1", UID: "uid-1", ResourceVersion: "7"}}
+	in.Spec.Replicas = 3
+	storage := &Widget{}
+	if err := in.ConvertToStorageVersion(storage); err != nil {
+		t.Fatal(err)
+	}
+	if storage.Spec.Size != 3 {
+		t.Errorf("expected size 3, got %d", storage.Spec.Size)
+	}
+	if storage.Name != "w1" || storage.UID != "uid-1" || storage.ResourceVersion != "7" {
+		t.Errorf("metadata other than labels and annotations must not be changed by webhook, got %v", storage.ObjectMeta)
+	}
+	if storage.Labels["converted"] != "true" {
+		t.Errorf("labels changed by webhook must be kept, got %v", storage.Labels)
+	}
+
+	out := &WidgetV2{}
+	if err := out.ConvertFromStorageVersion(storage); err != nil {
+		t.Fatal(err)
+	}
+	if out.Spec.Replicas != 3 || out.Name != "w1" {
+		t.Errorf("expected replicas 3 of w1, got %v", out)
+	}
+}
+
+func TestConvertListUsingWebhook(t *testing.T) {
+	defer setWidgetWebhook(t, false)()
+
+	in := &WidgetList{ListMeta: metav1.ListMeta{ResourceVersion: "10", Continue: "token"}}
+	for i := 1; i <= 3; i++ {
+		w := Widget{ObjectMeta: metav1.ObjectMeta{Name: string(rune('a' + i))}}
+		w.Spec.Size = i
+		in.Items = append(in.Items, w)
+	}
+	out := &WidgetV2List{}
+	if err := convertList(in, out, &WidgetV2{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Items) != 3 || out.ResourceVersion != "10" || out.Continue != "token" {
+		t.Fatalf("unexpected converted list %v", out)
+	}
+	for i, v := range out.Items {
+		if v.Spec.Replicas != i+1 || v.Name != in.Items[i].Name {
+			t.Errorf("item %d, expected replicas %d of %s, got %v", i, i+1, in.Items[i].Name, v)
+		}
+	}
+}
+
+func TestConvertWebhookFailure(t *testing.T) {
+	defer setWidgetWebhook(t, true)()
+
+	in := &WidgetV2{ObjectMeta: metav1.ObjectMeta{Name: "w1"}}
+	if err := in.ConvertToStorageVersion(&Widget{}); err == nil {
+		t.Errorf("expected error of failed webhook conversion")
+	}
+}
+
+func TestConvertWithoutWebhook(t *testing.T) {
+	in := &Widget{ObjectMeta: metav1.ObjectMeta{Name: "w1", Labels: map[string]string{"app": "a"}}}
+	in.Spec.Size = 2
+	out := &WidgetV2{}
+	if err := out.ConvertFromStorageVersion(in); err != nil {
+		t.Fatal(err)
+	}
+	if out.Name != "w1" || out.Labels["app"] != "a" || out.Spec.Replicas != 0 {
+		t.Errorf("expected only apiVersion changed without webhook, got %v", out)
+	}
+}
+
+func TestSetConversionWebhooksRequireHttps(t *testing.T) {
+	err := SetConversionWebhooks(&ConversionWebhookConfiguration{Webhooks: []ConversionWebhook{{
+		Group: widgetGroupResource.Group, Resource: widgetGroupResource.Resource, URL: "http://example.com/convert",
+	}}})
+	if err == nil {
+		t.Errorf("expected error of http conversion webhook")
+	}
+}
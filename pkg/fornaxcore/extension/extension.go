@@ -0,0 +1,127 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	storefactory "centaurusinfra.io/fornax-serverless/pkg/store/factory"
+	"k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/apiserver-runtime/pkg/builder/resource"
+)
+
+// extensionResource is a resource defined outside of fornax and served by fornax api server,
+// versions which are not storage version are converted to storage version by its conversion webhook
+type extensionResource struct {
+	storageVersion resource.Object
+	versions       []resource.Object
+}
+
+var (
+	extensionsMu sync.RWMutex
+	extensions   = map[schema.GroupResource]*extensionResource{}
+	// group resources in registration order
+	extensionOrder = []schema.GroupResource{}
+)
+
+// Register add a extension resource served by fornax api server, it's called in init of a extension package imported by fornaxcore,
+// other versions must implement resource.MultiVersionObject using ConvertToStorageVersion and ConvertFromStorageVersion of this package,
+// it panic if registration is invalid, as sql.Register does
+func Register(storageVersion resource.Object, versions ...resource.Object) {
+	extensionsMu.Lock()
+	defer extensionsMu.Unlock()
+	gr := storageVersion.GetGroupVersionResource().GroupResource()
+	if gr.Group == fornaxv1.ApplicationGrv.Group {
+		panic(fmt.Sprintf("extension %s can not use fornax core group", gr))
+	}
+	if _, found := extensions[gr]; found {
+		panic(fmt.Sprintf("extension %s is registered twice", gr))
+	}
+	if !storageVersion.IsStorageVersion() {
+		panic(fmt.Sprintf("extension %s version %s is not storage version", gr, storageVersion.GetGroupVersionResource().Version))
+	}
+	for _, v := range versions {
+		grv := v.GetGroupVersionResource()
+		if grv.GroupResource() != gr {
+			panic(fmt.Sprintf("extension %s has a version of another resource %s", gr, grv.GroupResource()))
+		}
+		if _, ok := v.(resource.MultiVersionObject); !ok || v.IsStorageVersion() {
+			panic(fmt.Sprintf("extension %s version %s must implement MultiVersionObject and not be storage version", gr, grv.Version))
+		}
+	}
+	extensions[gr] = &extensionResource{storageVersion: storageVersion, versions: versions}
+	extensionOrder = append(extensionOrder, gr)
+}
+
+// Resources return all versions of registered extensions for api server, storage version of a extension come first
+func Resources() []resource.Object {
+	extensionsMu.RLock()
+	defer extensionsMu.RUnlock()
+	objs := []resource.Object{}
+	for _, gr := range extensionOrder {
+		objs = append(objs, extensions[gr].storageVersion)
+		objs = append(objs, extensions[gr].versions...)
+	}
+	return objs
+}
+
+// NewExtensionStorages create stores of registered extensions, extension store backend is configured in storage configuration as other resources
+func NewExtensionStorages(ctx context.Context) {
+	extensionsMu.RLock()
+	defer extensionsMu.RUnlock()
+	for _, gr := range extensionOrder {
+		obj := extensions[gr].storageVersion
+		storefactory.NewExtensionStorage(ctx, obj.GetGroupVersionResource(), obj.New, obj.NewList)
+		klog.InfoS("Registered extension resource", "resource", gr, "versions", len(extensions[gr].versions)+1)
+	}
+}
+
+// AddConversionFuncs register list conversions of extension versions, scheme only have object conversions registered by api server builder,
+// list items are sent to conversion webhook in one request
+func AddConversionFuncs(scheme *runtime.Scheme) error {
+	extensionsMu.RLock()
+	defer extensionsMu.RUnlock()
+	for _, gr := range extensionOrder {
+		storageVersion := extensions[gr].storageVersion
+		for _, v := range extensions[gr].versions {
+			version := v
+			if err := scheme.AddConversionFunc(version.NewList(), storageVersion.NewList(), func(a, b interface{}, scope conversion.Scope) error {
+				return convertList(a.(runtime.Object), b.(runtime.Object), storageVersion)
+			}); err != nil {
+				return err
+			}
+			if err := scheme.AddConversionFunc(storageVersion.NewList(), version.NewList(), func(a, b interface{}, scope conversion.Scope) error {
+				return convertList(a.(runtime.Object), b.(runtime.Object), version)
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// gvkOf return group version kind of a resource object, kind is name of its go type as api server builder register it
+func gvkOf(obj resource.Object) schema.GroupVersionKind {
+	return obj.GetGroupVersionResource().GroupVersion().WithKind(reflect.Indirect(reflect.ValueOf(obj)).Type().Name())
+}
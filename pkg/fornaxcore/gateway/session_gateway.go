@@ -0,0 +1,607 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxerrors"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultSessionGatewayPortRangeStart     = 30000
+	DefaultSessionGatewayPortRangeEnd       = 32767
+	DefaultSessionGatewayDialTimeoutSeconds = 5
+)
+
+var (
+	InvalidSessionGatewayConfigurationError = errors.New("session gateway require at least one advertise address and a valid port range")
+	InvalidSessionGatewaySNIPortError       = errors.New("session gateway sni port must be a valid port out of gateway port range")
+	clientHelloReadError                    = errors.New("client hello is read")
+)
+
+var (
+	sessionGatewayRoutes = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Subsystem:      "fornax_session_gateway",
+			Name:           "routes",
+			Help:           "Number of sessions routed by session gateway",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+	sessionGatewayConnections = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_session_gateway",
+			Name:           "connections_total",
+			Help:           "Number of client connections proxied by session gateway to session pods",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+	sessionGatewayDialFailures = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_session_gateway",
+			Name:           "dial_failures_total",
+			Help:           "Number of client connections closed because session gateway could not connect session pod",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(sessionGatewayRoutes, sessionGatewayConnections, sessionGatewayDialFailures)
+}
+
+// SessionGatewayConfiguration enable session gateway, gateway is disabled if file does not exist
+type SessionGatewayConfiguration struct {
+	Enabled bool `json:"enabled,omitempty"`
+
+	// addresses published in session access endpoints, e.g. fornaxcore host or a load balancer in front of it, one per ip family
+	AdvertiseAddresses []string `json:"advertiseAddresses,omitempty"`
+
+	// address gateway ports listen on, all addresses if empty
+	BindAddress string `json:"bindAddress,omitempty"`
+
+	// each routed session port get a gateway port in this range
+	PortRangeStart int32 `json:"portRangeStart,omitempty"`
+	PortRangeEnd   int32 `json:"portRangeEnd,omitempty"`
+
+	DialTimeoutSeconds int32 `json:"dialTimeoutSeconds,omitempty"`
+
+	// a single port routing tls connections to sessions by server name, tls is not terminated by gateway,
+	// session certificate is served by pod, sni routing is disabled if it's 0
+	SNIPort int32 `json:"sniPort,omitempty"`
+}
+
+func DefaultSessionGatewayConfiguration() *SessionGatewayConfiguration {
+	return &SessionGatewayConfiguration{
+		Enabled:            false,
+		PortRangeStart:     DefaultSessionGatewayPortRangeStart,
+		PortRangeEnd:       DefaultSessionGatewayPortRangeEnd,
+		DialTimeoutSeconds: DefaultSessionGatewayDialTimeoutSeconds,
+	}
+}
+
+// LoadSessionGatewayConfiguration read session gateway configuration from a json file, default configuration is returned if file does not exist,
+// fields missing in file keep their default value
+func LoadSessionGatewayConfiguration(file string) (*SessionGatewayConfiguration, error) {
+	config := DefaultSessionGatewayConfiguration()
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	if !config.Enabled {
+		return config, nil
+	}
+	if len(config.AdvertiseAddresses) == 0 || config.PortRangeStart <= 0 || config.PortRangeEnd < config.PortRangeStart || config.PortRangeEnd > 65535 {
+		return nil, InvalidSessionGatewayConfigurationError
+	}
+	if config.SNIPort < 0 || config.SNIPort > 65535 || (config.SNIPort >= config.PortRangeStart && config.SNIPort <= config.PortRangeEnd) {
+		return nil, InvalidSessionGatewaySNIPortError
+	}
+	for _, v := range config.AdvertiseAddresses {
+		if net.ParseIP(v) == nil {
+			return nil, fmt.Errorf("invalid session gateway advertise address %s", v)
+		}
+	}
+	return config, nil
+}
+
+// gatewayPort is a gateway port listener, it proxy tcp connections to a session port of pod,
+// targets are endpoints of same pod port on each ip family of node, they are tried in order,
+// a sni route of session is a gatewayPort having a host and no listener, its connections are accepted by sni listener
+type gatewayPort struct {
+	port     int32
+	host     string
+	targets  []fornaxv1.AccessEndPoint
+	listener net.Listener
+	mu       sync.Mutex
+	conns    map[net.Conn]bool
+}
+
+// SessionGateway assign gateway ports to open sessions and proxy client connections to pod owning session,
+// gateway endpoints are published in session access endpoints before pod endpoints,
+// so, client connect gateway advertise addresses and do not need to reach pod node directly,
+// only tcp ports are routed, other endpoints are published as it is
+type SessionGateway struct {
+	config *SessionGatewayConfiguration
+	mu     sync.Mutex
+	// session name to its gateway ports
+	routes    map[string][]*gatewayPort
+	usedPorts map[int32]bool
+	nextPort  int32
+	// sni host to sni route of a session
+	hosts       map[string]*gatewayPort
+	sniListener net.Listener
+}
+
+func NewSessionGateway(config *SessionGatewayConfiguration) *SessionGateway {
+	return &SessionGateway{
+		config:    config,
+		routes:    map[string][]*gatewayPort{},
+		usedPorts: map[int32]bool{},
+		nextPort:  config.PortRangeStart,
+		hosts:     map[string]*gatewayPort{},
+	}
+}
+
+// SNIEnabled return true if gateway route tls connections by server name
+func (g *SessionGateway) SNIEnabled() bool {
+	return g.config.SNIPort > 0
+}
+
+// Start listen on sni port if sni routing is enabled, per session ports are listened when sessions are routed
+func (g *SessionGateway) Start() error {
+	if !g.SNIEnabled() {
+		return nil
+	}
+	listener, err := net.Listen("tcp", net.JoinHostPort(g.config.BindAddress, strconv.Itoa(int(g.config.SNIPort))))
+	if err != nil {
+		return err
+	}
+	g.mu.Lock()
+	g.sniListener = listener
+	g.mu.Unlock()
+	go g.serveSNI(listener)
+	klog.InfoS("Session gateway is routing tls connections by server name", "port", g.config.SNIPort)
+	return nil
+}
+
+// Stop close sni listener and all session routes
+func (g *SessionGateway) Stop() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.sniListener != nil {
+		g.sniListener.Close()
+		g.sniListener = nil
+	}
+	for sessionName := range g.routes {
+		g.unrouteNoLock(sessionName)
+	}
+}
+
+// Route assign gateway ports to tcp endpoints of a session and return gateway endpoints,
+// if host is not empty and sni routing is enabled, host is also routed to first tcp port of session through sni port,
+// it's the tls port of application, existing route of session is replaced, e.g. session is migrated to another pod
+func (g *SessionGateway) Route(session *fornaxv1.ApplicationSession, host string, endpoints []fornaxv1.AccessEndPoint) ([]fornaxv1.AccessEndPoint, error) {
+	sessionName := util.Name(session)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.unrouteNoLock(sessionName)
+
+	ports := []*gatewayPort{}
+	groups := groupTargets(endpoints)
+	if len(host) > 0 && g.SNIEnabled() && len(groups) > 0 {
+		if route, found := g.hosts[host]; found {
+			return nil, fmt.Errorf("session gateway host %s is already routed to session port %d", host, route.targets[0].Port)
+		}
+		ports = append(ports, g.routeHostNoLock(host, groups[0]))
+	}
+	for _, targets := range groups {
+		port, err := g.listenNoLock(0, targets)
+		if err != nil {
+			for _, v := range ports {
+				g.closePortNoLock(v)
+			}
+			return nil, err
+		}
+		ports = append(ports, port)
+	}
+	if len(ports) == 0 {
+		return nil, nil
+	}
+	g.routes[sessionName] = ports
+	sessionGatewayRoutes.Set(float64(len(g.routes)))
+	klog.InfoS("Routed session through session gateway", "session", sessionName, "ports", len(ports))
+	return g.endpoints(ports), nil
+}
+
+// Restore listen on gateway ports published in a open session again, e.g. fornaxcore restarted,
+// gateway endpoints are published in same order as pod ports, so, gateway ports are mapped to pod ports by order
+func (g *SessionGateway) Restore(session *fornaxv1.ApplicationSession) {
+	sessionName := util.Name(session)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, found := g.routes[sessionName]; found {
+		return
+	}
+	host := ""
+	gatewayPorts := []int32{}
+	podEndpoints := []fornaxv1.AccessEndPoint{}
+	for _, v := range session.Status.AccessEndPoints {
+		if g.isSNIEndpoint(v) {
+			host = v.Hostname
+		} else if g.isGatewayEndpoint(v) {
+			if len(gatewayPorts) == 0 || gatewayPorts[len(gatewayPorts)-1] != v.Port {
+				gatewayPorts = append(gatewayPorts, v.Port)
+			}
+		} else {
+			podEndpoints = append(podEndpoints, v)
+		}
+	}
+	if len(gatewayPorts) == 0 {
+		return
+	}
+	groups := groupTargets(podEndpoints)
+	if len(groups) != len(gatewayPorts) {
+		klog.InfoS("Session gateway endpoints do not match pod endpoints, skip restore", "session", sessionName)
+		return
+	}
+	ports := []*gatewayPort{}
+	if len(host) > 0 && g.SNIEnabled() {
+		if _, found := g.hosts[host]; !found {
+			ports = append(ports, g.routeHostNoLock(host, groups[0]))
+		}
+	}
+	for i, targets := range groups {
+		port, err := g.listenNoLock(gatewayPorts[i], targets)
+		if err != nil {
+			klog.ErrorS(err, "Failed to restore session gateway port", "session", sessionName, "port", gatewayPorts[i])
+			continue
+		}
+		ports = append(ports, port)
+	}
+	if len(ports) > 0 {
+		g.routes[sessionName] = ports
+		sessionGatewayRoutes.Set(float64(len(g.routes)))
+		klog.InfoS("Restored session gateway route", "session", sessionName, "ports", len(ports))
+	}
+}
+
+// Unroute close gateway ports of a session and connections through them
+func (g *SessionGateway) Unroute(sessionName string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, found := g.routes[sessionName]; found {
+		g.unrouteNoLock(sessionName)
+		klog.InfoS("Removed session gateway route", "session", sessionName)
+	}
+}
+
+func (g *SessionGateway) unrouteNoLock(sessionName string) {
+	for _, v := range g.routes[sessionName] {
+		g.closePortNoLock(v)
+	}
+	delete(g.routes, sessionName)
+	sessionGatewayRoutes.Set(float64(len(g.routes)))
+}
+
+func (g *SessionGateway) isSNIEndpoint(endpoint fornaxv1.AccessEndPoint) bool {
+	return len(endpoint.Hostname) > 0 && endpoint.Port == g.config.SNIPort && g.isAdvertiseAddress(endpoint.IPAddress)
+}
+
+func (g *SessionGateway) isGatewayEndpoint(endpoint fornaxv1.AccessEndPoint) bool {
+	if endpoint.Port < g.config.PortRangeStart || endpoint.Port > g.config.PortRangeEnd {
+		return false
+	}
+	return g.isAdvertiseAddress(endpoint.IPAddress)
+}
+
+func (g *SessionGateway) isAdvertiseAddress(address string) bool {
+	for _, v := range g.config.AdvertiseAddresses {
+		if v == address {
+			return true
+		}
+	}
+	return false
+}
+
+// endpoints return a endpoint of each advertise address for each gateway port, sni endpoints have host of session
+func (g *SessionGateway) endpoints(ports []*gatewayPort) []fornaxv1.AccessEndPoint {
+	endpoints := []fornaxv1.AccessEndPoint{}
+	for _, port := range ports {
+		for _, address := range g.config.AdvertiseAddresses {
+			endpoints = append(endpoints, fornaxv1.AccessEndPoint{
+				Protocol:  v1.ProtocolTCP,
+				IPAddress: address,
+				Port:      port.port,
+				IPFamily:  util.IPFamilyOf(address),
+				Hostname:  port.host,
+			})
+		}
+	}
+	return endpoints
+}
+
+// groupTargets group tcp endpoints by pod host port, a dual stack node publish a endpoint of each family for one port
+func groupTargets(endpoints []fornaxv1.AccessEndPoint) [][]fornaxv1.AccessEndPoint {
+	groups := [][]fornaxv1.AccessEndPoint{}
+	index := map[int32]int{}
+	for _, v := range endpoints {
+		if (len(v.Protocol) > 0 && v.Protocol != v1.ProtocolTCP) || len(v.IPAddress) == 0 || v.Port == 0 {
+			continue
+		}
+		if i, found := index[v.Port]; found {
+			groups[i] = append(groups[i], v)
+		} else {
+			index[v.Port] = len(groups)
+			groups = append(groups, []fornaxv1.AccessEndPoint{v})
+		}
+	}
+	return groups
+}
+
+// listenNoLock listen on a given port, or a free port in port range if port is 0
+func (g *SessionGateway) listenNoLock(port int32, targets []fornaxv1.AccessEndPoint) (*gatewayPort, error) {
+	if port > 0 {
+		return g.startPortNoLock(port, targets)
+	}
+	size := g.config.PortRangeEnd - g.config.PortRangeStart + 1
+	for i := int32(0); i < size; i++ {
+		candidate := g.nextPort
+		g.nextPort += 1
+		if g.nextPort > g.config.PortRangeEnd {
+			g.nextPort = g.config.PortRangeStart
+		}
+		if g.usedPorts[candidate] {
+			continue
+		}
+		if p, err := g.startPortNoLock(candidate, targets); err == nil {
+			return p, nil
+		}
+	}
+	return nil, fornaxerrors.Errorf(fornaxerrors.NoCapacity, "no free session gateway port in range %d-%d", g.config.PortRangeStart, g.config.PortRangeEnd)
+}
+
+func (g *SessionGateway) startPortNoLock(port int32, targets []fornaxv1.AccessEndPoint) (*gatewayPort, error) {
+	listener, err := net.Listen("tcp", net.JoinHostPort(g.config.BindAddress, strconv.Itoa(int(port))))
+	if err != nil {
+		return nil, err
+	}
+	p := &gatewayPort{
+		port:     port,
+		targets:  targets,
+		listener: listener,
+		conns:    map[net.Conn]bool{},
+	}
+	g.usedPorts[port] = true
+	go g.serve(p)
+	return p, nil
+}
+
+// routeHostNoLock route a sni host to targets, connections of host are accepted by sni listener
+func (g *SessionGateway) routeHostNoLock(host string, targets []fornaxv1.AccessEndPoint) *gatewayPort {
+	p := &gatewayPort{
+		port:    g.config.SNIPort,
+		host:    host,
+		targets: targets,
+		conns:   map[net.Conn]bool{},
+	}
+	g.hosts[host] = p
+	return p
+}
+
+func (g *SessionGateway) closePortNoLock(port *gatewayPort) {
+	if port.listener != nil {
+		port.listener.Close()
+		delete(g.usedPorts, port.port)
+	} else if g.hosts[port.host] == port {
+		delete(g.hosts, port.host)
+	}
+	port.mu.Lock()
+	for conn := range port.conns {
+		conn.Close()
+	}
+	port.conns = nil
+	port.mu.Unlock()
+}
+
+func (g *SessionGateway) serve(port *gatewayPort) {
+	for {
+		conn, err := port.listener.Accept()
+		if err != nil {
+			// listener is closed when session is unrouted
+			return
+		}
+		go g.proxy(port, conn)
+	}
+}
+
+func (g *SessionGateway) serveSNI(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			// listener is closed when gateway is stopped
+			return
+		}
+		go g.proxySNI(conn)
+	}
+}
+
+// proxySNI read server name from tls client hello and proxy connection to session routed by server name,
+// client hello is replayed to pod, so, pod terminate tls using application certificate
+func (g *SessionGateway) proxySNI(conn net.Conn) {
+	timeout := time.Duration(g.config.DialTimeoutSeconds) * time.Second
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	host, hello, err := readServerName(conn)
+	if err != nil {
+		klog.InfoS("Failed to read tls server name, close connection", "client", conn.RemoteAddr(), "err", err)
+		conn.Close()
+		return
+	}
+	conn.SetReadDeadline(time.Time{})
+	g.mu.Lock()
+	port, found := g.hosts[host]
+	g.mu.Unlock()
+	if !found {
+		klog.InfoS("Tls server name is not routed by session gateway, close connection", "host", host, "client", conn.RemoteAddr())
+		conn.Close()
+		return
+	}
+	g.proxy(port, &prefixConn{Conn: conn, prefix: hello})
+}
+
+// readServerName read tls client hello from a connection, return server name and bytes read
+func readServerName(conn net.Conn) (string, []byte, error) {
+	hello := &bytes.Buffer{}
+	serverName := ""
+	err := tls.Server(&readOnlyConn{reader: io.TeeReader(conn, hello)}, &tls.Config{
+		GetConfigForClient: func(info *tls.ClientHelloInfo) (*tls.Config, error) {
+			serverName = info.ServerName
+			return nil, clientHelloReadError
+		},
+	}).Handshake()
+	if serverName == "" {
+		if err == nil || errors.Is(err, clientHelloReadError) {
+			err = errors.New("tls client hello does not have server name")
+		}
+		return "", nil, err
+	}
+	return serverName, hello.Bytes(), nil
+}
+
+// readOnlyConn is a connection used to parse client hello, handshake can not write anything to client
+type readOnlyConn struct {
+	net.Conn
+	reader io.Reader
+}
+
+func (c *readOnlyConn) Read(p []byte) (int, error)         { return c.reader.Read(p) }
+func (c *readOnlyConn) Write(p []byte) (int, error)        { return 0, io.ErrClosedPipe }
+func (c *readOnlyConn) Close() error                       { return nil }
+func (c *readOnlyConn) LocalAddr() net.Addr                { return nil }
+func (c *readOnlyConn) RemoteAddr() net.Addr               { return nil }
+func (c *readOnlyConn) SetDeadline(t time.Time) error      { return nil }
+func (c *readOnlyConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *readOnlyConn) SetWriteDeadline(t time.Time) error { return nil }
+
+// prefixConn return bytes already read from connection before reading connection
+type prefixConn struct {
+	net.Conn
+	prefix []byte
+}
+
+func (c *prefixConn) Read(p []byte) (int, error) {
+	if len(c.prefix) > 0 {
+		n := copy(p, c.prefix)
+		c.prefix = c.prefix[n:]
+		return n, nil
+	}
+	return c.Conn.Read(p)
+}
+
+func (g *SessionGateway) proxy(port *gatewayPort, conn net.Conn) {
+	defer conn.Close()
+	var upstream net.Conn
+	var err error
+	timeout := time.Duration(g.config.DialTimeoutSeconds) * time.Second
+	for _, v := range port.targets {
+		upstream, err = net.DialTimeout("tcp", net.JoinHostPort(v.IPAddress, strconv.Itoa(int(v.Port))), timeout)
+		if err == nil {
+			break
+		}
+	}
+	if upstream == nil {
+		sessionGatewayDialFailures.Inc()
+		klog.ErrorS(err, "Failed to connect session pod", "port", port.port, "client", conn.RemoteAddr())
+		return
+	}
+	defer upstream.Close()
+	if !port.track(conn, upstream) {
+		return
+	}
+	defer port.untrack(conn, upstream)
+	sessionGatewayConnections.Inc()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(upstream, conn)
+		closeWrite(upstream)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(conn, upstream)
+		closeWrite(conn)
+		done <- struct{}{}
+	}()
+	<-done
+	<-done
+}
+
+// track remember connections of a port, return false if port is already closed
+func (p *gatewayPort) track(conns ...net.Conn) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conns == nil {
+		return false
+	}
+	for _, v := range conns {
+		p.conns[v] = true
+	}
+	return true
+}
+
+func (p *gatewayPort) untrack(conns ...net.Conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, v := range conns {
+		delete(p.conns, v)
+	}
+}
+
+// closeWrite half close a tcp connection, so, other side get eof and still can send response
+func closeWrite(conn net.Conn) {
+	if c, ok := conn.(*prefixConn); ok {
+		conn = c.Conn
+	}
+	if c, ok := conn.(*net.TCPConn); ok {
+		c.CloseWrite()
+	} else {
+		conn.Close()
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/pki"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func freePort(t *testing.T) int32 {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return int32(listener.Addr().(*net.TCPAddr).Port)
+}
+
+// startTLSEchoServer start a tls server of a session pod, it reply each line with its server name
+func startTLSEchoServer(t *testing.T, ca *pki.CertificateAuthority, dnsName string) fornaxv1.AccessEndPoint {
+	pair, err := ca.Issue(pkix.Name{CommonName: dnsName}, []string{"*." + dnsName}, nil, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := pki.NewTLSCertificate(pair)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{*cert}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				tlsConn := conn.(*tls.Conn)
+				line, err := bufio.NewReader(tlsConn).ReadString('\n')
+				if err != nil {
+					return
+				}
+				tlsConn.Write([]byte(tlsConn.ConnectionState().ServerName + ":" + line))
+			}()
+		}
+	}()
+	return fornaxv1.AccessEndPoint{
+		Protocol:  v1.ProtocolTCP,
+		IPAddress: "127.0.0.1",
+		Port:      int32(listener.Addr().(*net.TCPAddr).Port),
+		IPFamily:  v1.IPv4Protocol,
+	}
+}
+
+func dialSNI(port int32, host string, roots *x509.CertPool) (string, error) {
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: time.Second}, "tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(int(port))), &tls.Config{ServerName: host, RootCAs: roots})
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(time.Second))
+	if _, err := conn.Write([]byte("hello\n")); err != nil {
+		return "", err
+	}
+	return bufio.NewReader(conn).ReadString('\n')
+}
+
+func TestSessionGatewaySNIRouting(t *testing.T) {
+	ca, err := pki.NewSelfSignedCertificateAuthority("test-ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rangePort := freePort(t)
+	config := DefaultSessionGatewayConfiguration()
+	config.Enabled = true
+	config.AdvertiseAddresses = []string{"127.0.0.1"}
+	config.BindAddress = "127.0.0.1"
+	config.PortRangeStart, config.PortRangeEnd = rangePort, rangePort
+	config.SNIPort = freePort(t)
+	g := NewSessionGateway(config)
+	if err := g.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer g.Stop()
+
+	target := startTLSEchoServer(t, ca, "app1.ns1.fornax")
+	host := "session1.app1.ns1.fornax"
+	session := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "session1"}}
+	endpoints, err := g.Route(session, host, []fornaxv1.AccessEndPoint{target})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) != 2 || endpoints[0].Port != config.SNIPort || endpoints[0].Hostname != host || endpoints[1].Port != rangePort {
+		t.Fatalf("expected a sni endpoint of host and a gateway port endpoint, got %v", endpoints)
+	}
+
+	reply, err := dialSNI(config.SNIPort, host, ca.CertPool())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply != host+":hello\n" {
+		t.Errorf("unexpected reply %q", reply)
+	}
+	if _, err := dialSNI(config.SNIPort, "session2.app1.ns1.fornax", ca.CertPool()); err == nil {
+		t.Errorf("expected connection of a host not routed is closed")
+	}
+
+	// sni route is restored from published endpoints, e.g. fornaxcore restarted
+	g.Unroute("ns1/session1")
+	if _, err := dialSNI(config.SNIPort, host, ca.CertPool()); err == nil {
+		t.Errorf("expected connection of a unrouted session is closed")
+	}
+	session.Status.AccessEndPoints = append(endpoints, target)
+	g.Restore(session)
+	if _, err := dialSNI(config.SNIPort, host, ca.CertPool()); err != nil {
+		t.Errorf("expected restored session is routed, %v", err)
+	}
+}
+
+func TestLoadSessionGatewayConfigurationSNIPort(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "session_gateway.json")
+	data := `{"enabled": true, "advertiseAddresses": ["10.0.0.1"], "sniPort": 30000}`
+	if err := os.WriteFile(file, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSessionGatewayConfiguration(file); err != InvalidSessionGatewaySNIPortError {
+		t.Errorf("expected sni port in gateway port range is rejected, got %v", err)
+	}
+}
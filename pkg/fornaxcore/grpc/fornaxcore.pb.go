@@ -23,6 +23,7 @@ package grpc
 
 import (
 	empty "github.com/golang/protobuf/ptypes/empty"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	v1 "k8s.io/api/core/v1"
@@ -40,20 +41,29 @@ const (
 type MessageType int32
 
 const (
-	MessageType_UNSPECIFIED               MessageType = 0
-	MessageType_FORNAX_CORE_CONFIGURATION MessageType = 100
-	MessageType_NODE_CONFIGURATION        MessageType = 200
-	MessageType_NODE_REGISTER             MessageType = 201
-	MessageType_NODE_READY                MessageType = 202
-	MessageType_NODE_STATE                MessageType = 203
-	MessageType_NODE_FULL_SYNC            MessageType = 204
-	MessageType_POD_CREATE                MessageType = 300
-	MessageType_POD_TERMINATE             MessageType = 301
-	MessageType_POD_HIBERNATE             MessageType = 302
-	MessageType_POD_STATE                 MessageType = 303
-	MessageType_SESSION_OPEN              MessageType = 400
-	MessageType_SESSION_CLOSE             MessageType = 401
-	MessageType_SESSION_STATE             MessageType = 402
+	MessageType_UNSPECIFIED                MessageType = 0
+	MessageType_FORNAX_CORE_CONFIGURATION  MessageType = 100
+	MessageType_NODE_CONFIGURATION         MessageType = 200
+	MessageType_NODE_REGISTER              MessageType = 201
+	MessageType_NODE_READY                 MessageType = 202
+	MessageType_NODE_STATE                 MessageType = 203
+	MessageType_NODE_FULL_SYNC             MessageType = 204
+	MessageType_NODE_LEASE_RENEW           MessageType = 205
+	MessageType_NODE_LEASE_CONFIGURATION   MessageType = 206
+	MessageType_NODE_CONFIG_PROFILE        MessageType = 207
+	MessageType_NODE_CONFIG_PROFILE_STATUS MessageType = 208
+	MessageType_POD_CREATE                 MessageType = 300
+	MessageType_POD_TERMINATE              MessageType = 301
+	MessageType_POD_HIBERNATE              MessageType = 302
+	MessageType_POD_STATE                  MessageType = 303
+	MessageType_POD_CONFIG_UPDATE          MessageType = 304
+	MessageType_POD_SECRET_UPDATE          MessageType = 305
+	MessageType_SESSION_OPEN               MessageType = 400
+	MessageType_SESSION_CLOSE              MessageType = 401
+	MessageType_SESSION_STATE              MessageType = 402
+	MessageType_SESSION_DRAIN              MessageType = 403
+	MessageType_SESSION_MIGRATE            MessageType = 404
+	MessageType_SESSION_USAGE              MessageType = 405
 )
 
 // Enum value maps for MessageType.
@@ -66,29 +76,47 @@ var (
 		202: "NODE_READY",
 		203: "NODE_STATE",
 		204: "NODE_FULL_SYNC",
+		205: "NODE_LEASE_RENEW",
+		206: "NODE_LEASE_CONFIGURATION",
+		207: "NODE_CONFIG_PROFILE",
+		208: "NODE_CONFIG_PROFILE_STATUS",
 		300: "POD_CREATE",
 		301: "POD_TERMINATE",
 		302: "POD_HIBERNATE",
 		303: "POD_STATE",
+		304: "POD_CONFIG_UPDATE",
+		305: "POD_SECRET_UPDATE",
 		400: "SESSION_OPEN",
 		401: "SESSION_CLOSE",
 		402: "SESSION_STATE",
+		403: "SESSION_DRAIN",
+		404: "SESSION_MIGRATE",
+		405: "SESSION_USAGE",
 	}
 	MessageType_value = map[string]int32{
-		"UNSPECIFIED":               0,
-		"FORNAX_CORE_CONFIGURATION": 100,
-		"NODE_CONFIGURATION":        200,
-		"NODE_REGISTER":             201,
-		"NODE_READY":                202,
-		"NODE_STATE":                203,
-		"NODE_FULL_SYNC":            204,
-		"POD_CREATE":                300,
-		"POD_TERMINATE":             301,
-		"POD_HIBERNATE":             302,
-		"POD_STATE":                 303,
-		"SESSION_OPEN":              400,
-		"SESSION_CLOSE":             401,
-		"SESSION_STATE":             402,
+		"UNSPECIFIED":                0,
+		"FORNAX_CORE_CONFIGURATION":  100,
+		"NODE_CONFIGURATION":         200,
+		"NODE_REGISTER":              201,
+		"NODE_READY":                 202,
+		"NODE_STATE":                 203,
+		"NODE_FULL_SYNC":             204,
+		"NODE_LEASE_RENEW":           205,
+		"NODE_LEASE_CONFIGURATION":   206,
+		"NODE_CONFIG_PROFILE":        207,
+		"NODE_CONFIG_PROFILE_STATUS": 208,
+		"POD_CREATE":                 300,
+		"POD_TERMINATE":              301,
+		"POD_HIBERNATE":              302,
+		"POD_STATE":                  303,
+		"POD_CONFIG_UPDATE":          304,
+		"POD_SECRET_UPDATE":          305,
+		"SESSION_OPEN":               400,
+		"SESSION_CLOSE":              401,
+		"SESSION_STATE":              402,
+		"SESSION_DRAIN":              403,
+		"SESSION_MIGRATE":            404,
+		"SESSION_USAGE":              405,
 	}
 )
 
@@ -177,7 +205,59 @@ func (x PodState_State) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use PodState_State.Descriptor instead.
 func (PodState_State) EnumDescriptor() ([]byte, []int) {
-	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{9, 0}
+	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{13, 0}
+}
+
+type SessionWatchEvent_Type int32
+
+const (
+	SessionWatchEvent_Added    SessionWatchEvent_Type = 0
+	SessionWatchEvent_Modified SessionWatchEvent_Type = 1
+	SessionWatchEvent_Deleted  SessionWatchEvent_Type = 2
+	SessionWatchEvent_Bookmark SessionWatchEvent_Type = 3
+)
+
+// Enum value maps for SessionWatchEvent_Type.
+var (
+	SessionWatchEvent_Type_name = map[int32]string{
+		0: "Added",
+		1: "Modified",
+		2: "Deleted",
+		3: "Bookmark",
+	}
+	SessionWatchEvent_Type_value = map[string]int32{
+		"Added":    0,
+		"Modified": 1,
+		"Deleted":  2,
+		"Bookmark": 3,
+	}
+)
+
+func (x SessionWatchEvent_Type) Enum() *SessionWatchEvent_Type {
+	p := new(SessionWatchEvent_Type)
+	*p = x
+	return p
+}
+
+func (x SessionWatchEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SessionWatchEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_fornaxcore_grpc_fornaxcore_proto_enumTypes[2].Descriptor()
+}
+
+func (SessionWatchEvent_Type) Type() protoreflect.EnumType {
+	return &file_pkg_fornaxcore_grpc_fornaxcore_proto_enumTypes[2]
+}
+
+func (x SessionWatchEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SessionWatchEvent_Type.Descriptor instead.
+func (SessionWatchEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{30, 0}
 }
 
 type FornaxCoreMessage struct {
@@ -188,6 +268,7 @@ type FornaxCoreMessage struct {
 	MessageIdentifier string          `protobuf:"bytes,1,opt,name=messageIdentifier,proto3" json:"messageIdentifier,omitempty"`
 	NodeIdentifier    *NodeIdentifier `protobuf:"bytes,2,opt,name=nodeIdentifier,proto3" json:"nodeIdentifier,omitempty"`
 	MessageType       MessageType     `protobuf:"varint,3,opt,name=messageType,proto3,enum=centaurusinfra.io.fornaxcore.service.MessageType" json:"messageType,omitempty"`
+	RequestIdentifier string          `protobuf:"bytes,4,opt,name=requestIdentifier,proto3" json:"requestIdentifier,omitempty"` // request id of session messages, used to correlate logs of a session across components
 	// Types that are assignable to MessageBody:
 	//	*FornaxCoreMessage_FornaxCoreConfiguration
 	//	*FornaxCoreMessage_NodeConfiguration
@@ -195,13 +276,22 @@ type FornaxCoreMessage struct {
 	//	*FornaxCoreMessage_NodeReady
 	//	*FornaxCoreMessage_NodeState
 	//	*FornaxCoreMessage_NodeFullSync
+	//	*FornaxCoreMessage_NodeLeaseRenew
+	//	*FornaxCoreMessage_NodeLeaseConfiguration
+	//	*FornaxCoreMessage_NodeConfigProfile
+	//	*FornaxCoreMessage_NodeConfigProfileStatus
 	//	*FornaxCoreMessage_PodCreate
 	//	*FornaxCoreMessage_PodTerminate
 	//	*FornaxCoreMessage_PodHibernate
 	//	*FornaxCoreMessage_PodState
+	//	*FornaxCoreMessage_PodConfigUpdate
+	//	*FornaxCoreMessage_PodSecretUpdate
 	//	*FornaxCoreMessage_SessionOpen
 	//	*FornaxCoreMessage_SessionClose
 	//	*FornaxCoreMessage_SessionState
+	//	*FornaxCoreMessage_SessionDrain
+	//	*FornaxCoreMessage_SessionMigrate
+	//	*FornaxCoreMessage_SessionUsage
 	MessageBody isFornaxCoreMessage_MessageBody `protobuf_oneof:"MessageBody"`
 }
 
@@ -258,6 +348,13 @@ func (x *FornaxCoreMessage) GetMessageType() MessageType {
 	return MessageType_UNSPECIFIED
 }
 
+func (x *FornaxCoreMessage) GetRequestIdentifier() string {
+	if x != nil {
+		return x.RequestIdentifier
+	}
+	return ""
+}
+
 func (m *FornaxCoreMessage) GetMessageBody() isFornaxCoreMessage_MessageBody {
 	if m != nil {
 		return m.MessageBody
@@ -307,6 +404,34 @@ func (x *FornaxCoreMessage) GetNodeFullSync() *NodeFullSync {
 	return nil
 }
 
+func (x *FornaxCoreMessage) GetNodeLeaseRenew() *NodeLeaseRenew {
+	if x, ok := x.GetMessageBody().(*FornaxCoreMessage_NodeLeaseRenew); ok {
+		return x.NodeLeaseRenew
+	}
+	return nil
+}
+
+func (x *FornaxCoreMessage) GetNodeLeaseConfiguration() *NodeLeaseConfiguration {
+	if x, ok := x.GetMessageBody().(*FornaxCoreMessage_NodeLeaseConfiguration); ok {
+		return x.NodeLeaseConfiguration
+	}
+	return nil
+}
+
+func (x *FornaxCoreMessage) GetNodeConfigProfile() *NodeConfigProfile {
+	if x, ok := x.GetMessageBody().(*FornaxCoreMessage_NodeConfigProfile); ok {
+		return x.NodeConfigProfile
+	}
+	return nil
+}
+
+func (x *FornaxCoreMessage) GetNodeConfigProfileStatus() *NodeConfigProfileStatus {
+	if x, ok := x.GetMessageBody().(*FornaxCoreMessage_NodeConfigProfileStatus); ok {
+		return x.NodeConfigProfileStatus
+	}
+	return nil
+}
+
 func (x *FornaxCoreMessage) GetPodCreate() *PodCreate {
 	if x, ok := x.GetMessageBody().(*FornaxCoreMessage_PodCreate); ok {
 		return x.PodCreate
@@ -335,6 +460,20 @@ func (x *FornaxCoreMessage) GetPodState() *PodState {
 	return nil
 }
 
+func (x *FornaxCoreMessage) GetPodConfigUpdate() *PodConfigUpdate {
+	if x, ok := x.GetMessageBody().(*FornaxCoreMessage_PodConfigUpdate); ok {
+		return x.PodConfigUpdate
+	}
+	return nil
+}
+
+func (x *FornaxCoreMessage) GetPodSecretUpdate() *PodSecretUpdate {
+	if x, ok := x.GetMessageBody().(*FornaxCoreMessage_PodSecretUpdate); ok {
+		return x.PodSecretUpdate
+	}
+	return nil
+}
+
 func (x *FornaxCoreMessage) GetSessionOpen() *SessionOpen {
 	if x, ok := x.GetMessageBody().(*FornaxCoreMessage_SessionOpen); ok {
 		return x.SessionOpen
@@ -356,6 +495,27 @@ func (x *FornaxCoreMessage) GetSessionState() *SessionState {
 	return nil
 }
 
+func (x *FornaxCoreMessage) GetSessionDrain() *SessionDrain {
+	if x, ok := x.GetMessageBody().(*FornaxCoreMessage_SessionDrain); ok {
+		return x.SessionDrain
+	}
+	return nil
+}
+
+func (x *FornaxCoreMessage) GetSessionMigrate() *SessionMigrate {
+	if x, ok := x.GetMessageBody().(*FornaxCoreMessage_SessionMigrate); ok {
+		return x.SessionMigrate
+	}
+	return nil
+}
+
+func (x *FornaxCoreMessage) GetSessionUsage() *SessionUsage {
+	if x, ok := x.GetMessageBody().(*FornaxCoreMessage_SessionUsage); ok {
+		return x.SessionUsage
+	}
+	return nil
+}
+
 type isFornaxCoreMessage_MessageBody interface {
 	isFornaxCoreMessage_MessageBody()
 }
@@ -384,6 +544,22 @@ type FornaxCoreMessage_NodeFullSync struct {
 	NodeFullSync *NodeFullSync `protobuf:"bytes,204,opt,name=nodeFullSync,proto3,oneof"`
 }
 
+type FornaxCoreMessage_NodeLeaseRenew struct {
+	NodeLeaseRenew *NodeLeaseRenew `protobuf:"bytes,205,opt,name=nodeLeaseRenew,proto3,oneof"`
+}
+
+type FornaxCoreMessage_NodeLeaseConfiguration struct {
+	NodeLeaseConfiguration *NodeLeaseConfiguration `protobuf:"bytes,206,opt,name=nodeLeaseConfiguration,proto3,oneof"`
+}
+
+type FornaxCoreMessage_NodeConfigProfile struct {
+	NodeConfigProfile *NodeConfigProfile `protobuf:"bytes,207,opt,name=nodeConfigProfile,proto3,oneof"`
+}
+
+type FornaxCoreMessage_NodeConfigProfileStatus struct {
+	NodeConfigProfileStatus *NodeConfigProfileStatus `protobuf:"bytes,208,opt,name=nodeConfigProfileStatus,proto3,oneof"`
+}
+
 type FornaxCoreMessage_PodCreate struct {
 	PodCreate *PodCreate `protobuf:"bytes,300,opt,name=podCreate,proto3,oneof"`
 }
@@ -400,6 +576,14 @@ type FornaxCoreMessage_PodState struct {
 	PodState *PodState `protobuf:"bytes,303,opt,name=podState,proto3,oneof"`
 }
 
+type FornaxCoreMessage_PodConfigUpdate struct {
+	PodConfigUpdate *PodConfigUpdate `protobuf:"bytes,304,opt,name=podConfigUpdate,proto3,oneof"`
+}
+
+type FornaxCoreMessage_PodSecretUpdate struct {
+	PodSecretUpdate *PodSecretUpdate `protobuf:"bytes,305,opt,name=podSecretUpdate,proto3,oneof"`
+}
+
 type FornaxCoreMessage_SessionOpen struct {
 	SessionOpen *SessionOpen `protobuf:"bytes,400,opt,name=sessionOpen,proto3,oneof"`
 }
@@ -412,6 +596,18 @@ type FornaxCoreMessage_SessionState struct {
 	SessionState *SessionState `protobuf:"bytes,402,opt,name=sessionState,proto3,oneof"`
 }
 
+type FornaxCoreMessage_SessionDrain struct {
+	SessionDrain *SessionDrain `protobuf:"bytes,403,opt,name=sessionDrain,proto3,oneof"`
+}
+
+type FornaxCoreMessage_SessionMigrate struct {
+	SessionMigrate *SessionMigrate `protobuf:"bytes,404,opt,name=sessionMigrate,proto3,oneof"`
+}
+
+type FornaxCoreMessage_SessionUsage struct {
+	SessionUsage *SessionUsage `protobuf:"bytes,405,opt,name=sessionUsage,proto3,oneof"`
+}
+
 func (*FornaxCoreMessage_FornaxCoreConfiguration) isFornaxCoreMessage_MessageBody() {}
 
 func (*FornaxCoreMessage_NodeConfiguration) isFornaxCoreMessage_MessageBody() {}
@@ -424,6 +620,14 @@ func (*FornaxCoreMessage_NodeState) isFornaxCoreMessage_MessageBody() {}
 
 func (*FornaxCoreMessage_NodeFullSync) isFornaxCoreMessage_MessageBody() {}
 
+func (*FornaxCoreMessage_NodeLeaseRenew) isFornaxCoreMessage_MessageBody() {}
+
+func (*FornaxCoreMessage_NodeLeaseConfiguration) isFornaxCoreMessage_MessageBody() {}
+
+func (*FornaxCoreMessage_NodeConfigProfile) isFornaxCoreMessage_MessageBody() {}
+
+func (*FornaxCoreMessage_NodeConfigProfileStatus) isFornaxCoreMessage_MessageBody() {}
+
 func (*FornaxCoreMessage_PodCreate) isFornaxCoreMessage_MessageBody() {}
 
 func (*FornaxCoreMessage_PodTerminate) isFornaxCoreMessage_MessageBody() {}
@@ -432,12 +636,22 @@ func (*FornaxCoreMessage_PodHibernate) isFornaxCoreMessage_MessageBody() {}
 
 func (*FornaxCoreMessage_PodState) isFornaxCoreMessage_MessageBody() {}
 
+func (*FornaxCoreMessage_PodConfigUpdate) isFornaxCoreMessage_MessageBody() {}
+
+func (*FornaxCoreMessage_PodSecretUpdate) isFornaxCoreMessage_MessageBody() {}
+
 func (*FornaxCoreMessage_SessionOpen) isFornaxCoreMessage_MessageBody() {}
 
 func (*FornaxCoreMessage_SessionClose) isFornaxCoreMessage_MessageBody() {}
 
 func (*FornaxCoreMessage_SessionState) isFornaxCoreMessage_MessageBody() {}
 
+func (*FornaxCoreMessage_SessionDrain) isFornaxCoreMessage_MessageBody() {}
+
+func (*FornaxCoreMessage_SessionMigrate) isFornaxCoreMessage_MessageBody() {}
+
+func (*FornaxCoreMessage_SessionUsage) isFornaxCoreMessage_MessageBody() {}
+
 type FornaxCore struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -609,8 +823,9 @@ type NodeRegistry struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	NodeRevision int64    `protobuf:"varint,1,opt,name=nodeRevision,proto3" json:"nodeRevision,omitempty"`
-	Node         *v1.Node `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
+	NodeRevision int64                `protobuf:"varint,1,opt,name=nodeRevision,proto3" json:"nodeRevision,omitempty"`
+	Node         *v1.Node             `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
+	NodeTime     *timestamp.Timestamp `protobuf:"bytes,3,opt,name=nodeTime,proto3" json:"nodeTime,omitempty"` // node clock when message is sent, fornax core measure node clock skew with it
 }
 
 func (x *NodeRegistry) Reset() {
@@ -659,15 +874,23 @@ func (x *NodeRegistry) GetNode() *v1.Node {
 	return nil
 }
 
+func (x *NodeRegistry) GetNodeTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.NodeTime
+	}
+	return nil
+}
+
 // fornax core send node configuration to node to initialize using this configuration before tell fornax it's ready
 type NodeConfiguration struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ClusterDomain string    `protobuf:"bytes,1,opt,name=clusterDomain,proto3" json:"clusterDomain,omitempty"`
-	Node          *v1.Node  `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
-	DaemonPods    []*v1.Pod `protobuf:"bytes,3,rep,name=daemonPods,proto3" json:"daemonPods,omitempty"`
+	ClusterDomain string                  `protobuf:"bytes,1,opt,name=clusterDomain,proto3" json:"clusterDomain,omitempty"`
+	Node          *v1.Node                `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
+	DaemonPods    []*v1.Pod               `protobuf:"bytes,3,rep,name=daemonPods,proto3" json:"daemonPods,omitempty"`
+	Lease         *NodeLeaseConfiguration `protobuf:"bytes,4,opt,name=lease,proto3" json:"lease,omitempty"`
 }
 
 func (x *NodeConfiguration) Reset() {
@@ -723,6 +946,13 @@ func (x *NodeConfiguration) GetDaemonPods() []*v1.Pod {
 	return nil
 }
 
+func (x *NodeConfiguration) GetLease() *NodeLeaseConfiguration {
+	if x != nil {
+		return x.Lease
+	}
+	return nil
+}
+
 // node report back to fornax core, it's ready for take pod
 type NodeReady struct {
 	state         protoimpl.MessageState
@@ -898,20 +1128,19 @@ func (*NodeFullSync) Descriptor() ([]byte, []int) {
 	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{8}
 }
 
-type PodState struct {
+// node renew its lease periodically after it's ready, fornax core evict pods of node when node miss renewals longer than lease duration
+type NodeLeaseRenew struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	NodeRevision  int64           `protobuf:"varint,1,opt,name=nodeRevision,proto3" json:"nodeRevision,omitempty"`
-	State         PodState_State  `protobuf:"varint,2,opt,name=state,proto3,enum=centaurusinfra.io.fornaxcore.service.PodState_State" json:"state,omitempty"`
-	Pod           *v1.Pod         `protobuf:"bytes,3,opt,name=pod,proto3" json:"pod,omitempty"`
-	Resource      *PodResource    `protobuf:"bytes,4,opt,name=resource,proto3" json:"resource,omitempty"`
-	SessionStates []*SessionState `protobuf:"bytes,5,rep,name=sessionStates,proto3" json:"sessionStates,omitempty"`
+	NodeRevision         int64                `protobuf:"varint,1,opt,name=nodeRevision,proto3" json:"nodeRevision,omitempty"`
+	RenewIntervalSeconds int32                `protobuf:"varint,2,opt,name=renewIntervalSeconds,proto3" json:"renewIntervalSeconds,omitempty"`
+	NodeTime             *timestamp.Timestamp `protobuf:"bytes,3,opt,name=nodeTime,proto3" json:"nodeTime,omitempty"` // node clock when message is sent
 }
 
-func (x *PodState) Reset() {
-	*x = PodState{}
+func (x *NodeLeaseRenew) Reset() {
+	*x = NodeLeaseRenew{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -919,13 +1148,13 @@ func (x *PodState) Reset() {
 	}
 }
 
-func (x *PodState) String() string {
+func (x *NodeLeaseRenew) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PodState) ProtoMessage() {}
+func (*NodeLeaseRenew) ProtoMessage() {}
 
-func (x *PodState) ProtoReflect() protoreflect.Message {
+func (x *NodeLeaseRenew) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -937,57 +1166,44 @@ func (x *PodState) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PodState.ProtoReflect.Descriptor instead.
-func (*PodState) Descriptor() ([]byte, []int) {
+// Deprecated: Use NodeLeaseRenew.ProtoReflect.Descriptor instead.
+func (*NodeLeaseRenew) Descriptor() ([]byte, []int) {
 	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *PodState) GetNodeRevision() int64 {
+func (x *NodeLeaseRenew) GetNodeRevision() int64 {
 	if x != nil {
 		return x.NodeRevision
 	}
 	return 0
 }
 
-func (x *PodState) GetState() PodState_State {
-	if x != nil {
-		return x.State
-	}
-	return PodState_Creating
-}
-
-func (x *PodState) GetPod() *v1.Pod {
-	if x != nil {
-		return x.Pod
-	}
-	return nil
-}
-
-func (x *PodState) GetResource() *PodResource {
+func (x *NodeLeaseRenew) GetRenewIntervalSeconds() int32 {
 	if x != nil {
-		return x.Resource
+		return x.RenewIntervalSeconds
 	}
-	return nil
+	return 0
 }
 
-func (x *PodState) GetSessionStates() []*SessionState {
+func (x *NodeLeaseRenew) GetNodeTime() *timestamp.Timestamp {
 	if x != nil {
-		return x.SessionStates
+		return x.NodeTime
 	}
 	return nil
 }
 
-type PodResource struct {
+// fornax core tell node how often to renew its lease, sent in node configuration and when lease spec change
+type NodeLeaseConfiguration struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ResourceQuotaStatus *v1.ResourceQuotaStatus `protobuf:"bytes,1,opt,name=resourceQuotaStatus,proto3" json:"resourceQuotaStatus,omitempty"`
-	Volumes             []*v1.AttachedVolume    `protobuf:"bytes,2,rep,name=volumes,proto3" json:"volumes,omitempty"`
+	LeaseDurationSeconds int32 `protobuf:"varint,1,opt,name=leaseDurationSeconds,proto3" json:"leaseDurationSeconds,omitempty"`
+	RenewIntervalSeconds int32 `protobuf:"varint,2,opt,name=renewIntervalSeconds,proto3" json:"renewIntervalSeconds,omitempty"`
 }
 
-func (x *PodResource) Reset() {
-	*x = PodResource{}
+func (x *NodeLeaseConfiguration) Reset() {
+	*x = NodeLeaseConfiguration{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -995,13 +1211,13 @@ func (x *PodResource) Reset() {
 	}
 }
 
-func (x *PodResource) String() string {
+func (x *NodeLeaseConfiguration) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PodResource) ProtoMessage() {}
+func (*NodeLeaseConfiguration) ProtoMessage() {}
 
-func (x *PodResource) ProtoReflect() protoreflect.Message {
+func (x *NodeLeaseConfiguration) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1013,37 +1229,38 @@ func (x *PodResource) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PodResource.ProtoReflect.Descriptor instead.
-func (*PodResource) Descriptor() ([]byte, []int) {
+// Deprecated: Use NodeLeaseConfiguration.ProtoReflect.Descriptor instead.
+func (*NodeLeaseConfiguration) Descriptor() ([]byte, []int) {
 	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *PodResource) GetResourceQuotaStatus() *v1.ResourceQuotaStatus {
+func (x *NodeLeaseConfiguration) GetLeaseDurationSeconds() int32 {
 	if x != nil {
-		return x.ResourceQuotaStatus
+		return x.LeaseDurationSeconds
 	}
-	return nil
+	return 0
 }
 
-func (x *PodResource) GetVolumes() []*v1.AttachedVolume {
+func (x *NodeLeaseConfiguration) GetRenewIntervalSeconds() int32 {
 	if x != nil {
-		return x.Volumes
+		return x.RenewIntervalSeconds
 	}
-	return nil
+	return 0
 }
 
-type PodCreate struct {
+// fornax core assign a node config profile to node, node reconcile its host configuration to match profile spec, empty name clear profile
+type NodeConfigProfile struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	PodIdentifier string        `protobuf:"bytes,1,opt,name=podIdentifier,proto3" json:"podIdentifier,omitempty"`
-	Pod           *v1.Pod       `protobuf:"bytes,2,opt,name=pod,proto3" json:"pod,omitempty"`
-	ConfigMap     *v1.ConfigMap `protobuf:"bytes,3,opt,name=configMap,proto3" json:"configMap,omitempty"`
+	Name       string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Generation int64  `protobuf:"varint,2,opt,name=generation,proto3" json:"generation,omitempty"`
+	Spec       []byte `protobuf:"bytes,3,opt,name=spec,proto3" json:"spec,omitempty"` // json of fornaxv1.NodeConfigProfileSpec
 }
 
-func (x *PodCreate) Reset() {
-	*x = PodCreate{}
+func (x *NodeConfigProfile) Reset() {
+	*x = NodeConfigProfile{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1051,13 +1268,13 @@ func (x *PodCreate) Reset() {
 	}
 }
 
-func (x *PodCreate) String() string {
+func (x *NodeConfigProfile) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PodCreate) ProtoMessage() {}
+func (*NodeConfigProfile) ProtoMessage() {}
 
-func (x *PodCreate) ProtoReflect() protoreflect.Message {
+func (x *NodeConfigProfile) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1069,42 +1286,46 @@ func (x *PodCreate) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PodCreate.ProtoReflect.Descriptor instead.
-func (*PodCreate) Descriptor() ([]byte, []int) {
+// Deprecated: Use NodeConfigProfile.ProtoReflect.Descriptor instead.
+func (*NodeConfigProfile) Descriptor() ([]byte, []int) {
 	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *PodCreate) GetPodIdentifier() string {
+func (x *NodeConfigProfile) GetName() string {
 	if x != nil {
-		return x.PodIdentifier
+		return x.Name
 	}
 	return ""
 }
 
-func (x *PodCreate) GetPod() *v1.Pod {
+func (x *NodeConfigProfile) GetGeneration() int64 {
 	if x != nil {
-		return x.Pod
+		return x.Generation
 	}
-	return nil
+	return 0
 }
 
-func (x *PodCreate) GetConfigMap() *v1.ConfigMap {
+func (x *NodeConfigProfile) GetSpec() []byte {
 	if x != nil {
-		return x.ConfigMap
+		return x.Spec
 	}
 	return nil
 }
 
-type PodTerminate struct {
+// node report whether its host comply with assigned node config profile, it's sent after reconcile when compliance change
+type NodeConfigProfileStatus struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	PodIdentifier string `protobuf:"bytes,1,opt,name=podIdentifier,proto3" json:"podIdentifier,omitempty"`
+	Name       string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Generation int64    `protobuf:"varint,2,opt,name=generation,proto3" json:"generation,omitempty"`
+	Compliant  bool     `protobuf:"varint,3,opt,name=compliant,proto3" json:"compliant,omitempty"`
+	Drifts     []string `protobuf:"bytes,4,rep,name=drifts,proto3" json:"drifts,omitempty"`
 }
 
-func (x *PodTerminate) Reset() {
-	*x = PodTerminate{}
+func (x *NodeConfigProfileStatus) Reset() {
+	*x = NodeConfigProfileStatus{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1112,13 +1333,13 @@ func (x *PodTerminate) Reset() {
 	}
 }
 
-func (x *PodTerminate) String() string {
+func (x *NodeConfigProfileStatus) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PodTerminate) ProtoMessage() {}
+func (*NodeConfigProfileStatus) ProtoMessage() {}
 
-func (x *PodTerminate) ProtoReflect() protoreflect.Message {
+func (x *NodeConfigProfileStatus) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1130,28 +1351,53 @@ func (x *PodTerminate) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PodTerminate.ProtoReflect.Descriptor instead.
-func (*PodTerminate) Descriptor() ([]byte, []int) {
+// Deprecated: Use NodeConfigProfileStatus.ProtoReflect.Descriptor instead.
+func (*NodeConfigProfileStatus) Descriptor() ([]byte, []int) {
 	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *PodTerminate) GetPodIdentifier() string {
+func (x *NodeConfigProfileStatus) GetName() string {
 	if x != nil {
-		return x.PodIdentifier
+		return x.Name
 	}
 	return ""
 }
 
-type PodHibernate struct {
+func (x *NodeConfigProfileStatus) GetGeneration() int64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+func (x *NodeConfigProfileStatus) GetCompliant() bool {
+	if x != nil {
+		return x.Compliant
+	}
+	return false
+}
+
+func (x *NodeConfigProfileStatus) GetDrifts() []string {
+	if x != nil {
+		return x.Drifts
+	}
+	return nil
+}
+
+type PodState struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	PodIdentifier string `protobuf:"bytes,1,opt,name=podIdentifier,proto3" json:"podIdentifier,omitempty"`
+	NodeRevision  int64           `protobuf:"varint,1,opt,name=nodeRevision,proto3" json:"nodeRevision,omitempty"`
+	State         PodState_State  `protobuf:"varint,2,opt,name=state,proto3,enum=centaurusinfra.io.fornaxcore.service.PodState_State" json:"state,omitempty"`
+	Pod           *v1.Pod         `protobuf:"bytes,3,opt,name=pod,proto3" json:"pod,omitempty"`
+	Resource      *PodResource    `protobuf:"bytes,4,opt,name=resource,proto3" json:"resource,omitempty"`
+	SessionStates []*SessionState `protobuf:"bytes,5,rep,name=sessionStates,proto3" json:"sessionStates,omitempty"`
 }
 
-func (x *PodHibernate) Reset() {
-	*x = PodHibernate{}
+func (x *PodState) Reset() {
+	*x = PodState{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1159,13 +1405,13 @@ func (x *PodHibernate) Reset() {
 	}
 }
 
-func (x *PodHibernate) String() string {
+func (x *PodState) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PodHibernate) ProtoMessage() {}
+func (*PodState) ProtoMessage() {}
 
-func (x *PodHibernate) ProtoReflect() protoreflect.Message {
+func (x *PodState) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1177,44 +1423,71 @@ func (x *PodHibernate) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PodHibernate.ProtoReflect.Descriptor instead.
-func (*PodHibernate) Descriptor() ([]byte, []int) {
+// Deprecated: Use PodState.ProtoReflect.Descriptor instead.
+func (*PodState) Descriptor() ([]byte, []int) {
 	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *PodHibernate) GetPodIdentifier() string {
+func (x *PodState) GetNodeRevision() int64 {
 	if x != nil {
-		return x.PodIdentifier
+		return x.NodeRevision
 	}
-	return ""
+	return 0
 }
 
-type SessionState struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	NodeRevision      int64    `protobuf:"varint,1,opt,name=nodeRevision,proto3" json:"nodeRevision,omitempty"`
-	SessionData       []byte   `protobuf:"bytes,2,opt,name=sessionData,proto3" json:"sessionData,omitempty"`
-	ClientSessionData [][]byte `protobuf:"bytes,3,rep,name=clientSessionData,proto3" json:"clientSessionData,omitempty"`
+func (x *PodState) GetState() PodState_State {
+	if x != nil {
+		return x.State
+	}
+	return PodState_Creating
 }
 
-func (x *SessionState) Reset() {
-	*x = SessionState{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[14]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *PodState) GetPod() *v1.Pod {
+	if x != nil {
+		return x.Pod
 	}
+	return nil
 }
 
-func (x *SessionState) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *PodState) GetResource() *PodResource {
+	if x != nil {
+		return x.Resource
+	}
+	return nil
 }
 
-func (*SessionState) ProtoMessage() {}
+func (x *PodState) GetSessionStates() []*SessionState {
+	if x != nil {
+		return x.SessionStates
+	}
+	return nil
+}
 
-func (x *SessionState) ProtoReflect() protoreflect.Message {
+type PodResource struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ResourceQuotaStatus *v1.ResourceQuotaStatus `protobuf:"bytes,1,opt,name=resourceQuotaStatus,proto3" json:"resourceQuotaStatus,omitempty"`
+	Volumes             []*v1.AttachedVolume    `protobuf:"bytes,2,rep,name=volumes,proto3" json:"volumes,omitempty"`
+}
+
+func (x *PodResource) Reset() {
+	*x = PodResource{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PodResource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PodResource) ProtoMessage() {}
+
+func (x *PodResource) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1226,44 +1499,37 @@ func (x *SessionState) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SessionState.ProtoReflect.Descriptor instead.
-func (*SessionState) Descriptor() ([]byte, []int) {
+// Deprecated: Use PodResource.ProtoReflect.Descriptor instead.
+func (*PodResource) Descriptor() ([]byte, []int) {
 	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *SessionState) GetNodeRevision() int64 {
-	if x != nil {
-		return x.NodeRevision
-	}
-	return 0
-}
-
-func (x *SessionState) GetSessionData() []byte {
+func (x *PodResource) GetResourceQuotaStatus() *v1.ResourceQuotaStatus {
 	if x != nil {
-		return x.SessionData
+		return x.ResourceQuotaStatus
 	}
 	return nil
 }
 
-func (x *SessionState) GetClientSessionData() [][]byte {
+func (x *PodResource) GetVolumes() []*v1.AttachedVolume {
 	if x != nil {
-		return x.ClientSessionData
+		return x.Volumes
 	}
 	return nil
 }
 
-type SessionOpen struct {
+type PodCreate struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SessionIdentifier string `protobuf:"bytes,1,opt,name=sessionIdentifier,proto3" json:"sessionIdentifier,omitempty"`
-	PodIdentifier     string `protobuf:"bytes,2,opt,name=podIdentifier,proto3" json:"podIdentifier,omitempty"`
-	SessionData       []byte `protobuf:"bytes,3,opt,name=sessionData,proto3" json:"sessionData,omitempty"`
+	PodIdentifier string        `protobuf:"bytes,1,opt,name=podIdentifier,proto3" json:"podIdentifier,omitempty"`
+	Pod           *v1.Pod       `protobuf:"bytes,2,opt,name=pod,proto3" json:"pod,omitempty"`
+	ConfigMap     *v1.ConfigMap `protobuf:"bytes,3,opt,name=configMap,proto3" json:"configMap,omitempty"`
 }
 
-func (x *SessionOpen) Reset() {
-	*x = SessionOpen{}
+func (x *PodCreate) Reset() {
+	*x = PodCreate{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1271,13 +1537,13 @@ func (x *SessionOpen) Reset() {
 	}
 }
 
-func (x *SessionOpen) String() string {
+func (x *PodCreate) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SessionOpen) ProtoMessage() {}
+func (*PodCreate) ProtoMessage() {}
 
-func (x *SessionOpen) ProtoReflect() protoreflect.Message {
+func (x *PodCreate) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1289,43 +1555,42 @@ func (x *SessionOpen) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SessionOpen.ProtoReflect.Descriptor instead.
-func (*SessionOpen) Descriptor() ([]byte, []int) {
+// Deprecated: Use PodCreate.ProtoReflect.Descriptor instead.
+func (*PodCreate) Descriptor() ([]byte, []int) {
 	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *SessionOpen) GetSessionIdentifier() string {
+func (x *PodCreate) GetPodIdentifier() string {
 	if x != nil {
-		return x.SessionIdentifier
+		return x.PodIdentifier
 	}
 	return ""
 }
 
-func (x *SessionOpen) GetPodIdentifier() string {
+func (x *PodCreate) GetPod() *v1.Pod {
 	if x != nil {
-		return x.PodIdentifier
+		return x.Pod
 	}
-	return ""
+	return nil
 }
 
-func (x *SessionOpen) GetSessionData() []byte {
+func (x *PodCreate) GetConfigMap() *v1.ConfigMap {
 	if x != nil {
-		return x.SessionData
+		return x.ConfigMap
 	}
 	return nil
 }
 
-type SessionClose struct {
+type PodTerminate struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SessionIdentifier string `protobuf:"bytes,1,opt,name=sessionIdentifier,proto3" json:"sessionIdentifier,omitempty"`
-	PodIdentifier     string `protobuf:"bytes,2,opt,name=podIdentifier,proto3" json:"podIdentifier,omitempty"`
+	PodIdentifier string `protobuf:"bytes,1,opt,name=podIdentifier,proto3" json:"podIdentifier,omitempty"`
 }
 
-func (x *SessionClose) Reset() {
-	*x = SessionClose{}
+func (x *PodTerminate) Reset() {
+	*x = PodTerminate{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1333,13 +1598,13 @@ func (x *SessionClose) Reset() {
 	}
 }
 
-func (x *SessionClose) String() string {
+func (x *PodTerminate) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SessionClose) ProtoMessage() {}
+func (*PodTerminate) ProtoMessage() {}
 
-func (x *SessionClose) ProtoReflect() protoreflect.Message {
+func (x *PodTerminate) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1351,21 +1616,946 @@ func (x *SessionClose) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SessionClose.ProtoReflect.Descriptor instead.
-func (*SessionClose) Descriptor() ([]byte, []int) {
+// Deprecated: Use PodTerminate.ProtoReflect.Descriptor instead.
+func (*PodTerminate) Descriptor() ([]byte, []int) {
 	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *SessionClose) GetSessionIdentifier() string {
+func (x *PodTerminate) GetPodIdentifier() string {
+	if x != nil {
+		return x.PodIdentifier
+	}
+	return ""
+}
+
+type PodHibernate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PodIdentifier string `protobuf:"bytes,1,opt,name=podIdentifier,proto3" json:"podIdentifier,omitempty"`
+}
+
+func (x *PodHibernate) Reset() {
+	*x = PodHibernate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PodHibernate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PodHibernate) ProtoMessage() {}
+
+func (x *PodHibernate) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PodHibernate.ProtoReflect.Descriptor instead.
+func (*PodHibernate) Descriptor() ([]byte, []int) {
+	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PodHibernate) GetPodIdentifier() string {
+	if x != nil {
+		return x.PodIdentifier
+	}
+	return ""
+}
+
+// fornax core send new config map data to node when application config data is changed,
+// node agent update pod config map and notify open sessions of pod
+type PodConfigUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PodIdentifier string        `protobuf:"bytes,1,opt,name=podIdentifier,proto3" json:"podIdentifier,omitempty"`
+	ConfigMap     *v1.ConfigMap `protobuf:"bytes,2,opt,name=configMap,proto3" json:"configMap,omitempty"`
+}
+
+func (x *PodConfigUpdate) Reset() {
+	*x = PodConfigUpdate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PodConfigUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PodConfigUpdate) ProtoMessage() {}
+
+func (x *PodConfigUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PodConfigUpdate.ProtoReflect.Descriptor instead.
+func (*PodConfigUpdate) Descriptor() ([]byte, []int) {
+	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PodConfigUpdate) GetPodIdentifier() string {
+	if x != nil {
+		return x.PodIdentifier
+	}
+	return ""
+}
+
+func (x *PodConfigUpdate) GetConfigMap() *v1.ConfigMap {
+	if x != nil {
+		return x.ConfigMap
+	}
+	return nil
+}
+
+type SecretVersion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version string            `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Data    map[string][]byte `protobuf:"bytes,2,rep,name=data,proto3" json:"data,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *SecretVersion) Reset() {
+	*x = SecretVersion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecretVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretVersion) ProtoMessage() {}
+
+func (x *SecretVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretVersion.ProtoReflect.Descriptor instead.
+func (*SecretVersion) Descriptor() ([]byte, []int) {
+	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SecretVersion) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *SecretVersion) GetData() map[string][]byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// fornax core send new secret version to node when application secret is rotated,
+// previous secret is still valid on pod during rotation grace period, it's not set after previous version is revoked
+type PodSecretUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PodIdentifier  string         `protobuf:"bytes,1,opt,name=podIdentifier,proto3" json:"podIdentifier,omitempty"`
+	Secret         *SecretVersion `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	PreviousSecret *SecretVersion `protobuf:"bytes,3,opt,name=previousSecret,proto3" json:"previousSecret,omitempty"`
+}
+
+func (x *PodSecretUpdate) Reset() {
+	*x = PodSecretUpdate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PodSecretUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PodSecretUpdate) ProtoMessage() {}
+
+func (x *PodSecretUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PodSecretUpdate.ProtoReflect.Descriptor instead.
+func (*PodSecretUpdate) Descriptor() ([]byte, []int) {
+	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *PodSecretUpdate) GetPodIdentifier() string {
+	if x != nil {
+		return x.PodIdentifier
+	}
+	return ""
+}
+
+func (x *PodSecretUpdate) GetSecret() *SecretVersion {
+	if x != nil {
+		return x.Secret
+	}
+	return nil
+}
+
+func (x *PodSecretUpdate) GetPreviousSecret() *SecretVersion {
+	if x != nil {
+		return x.PreviousSecret
+	}
+	return nil
+}
+
+type SessionState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NodeRevision      int64    `protobuf:"varint,1,opt,name=nodeRevision,proto3" json:"nodeRevision,omitempty"`
+	SessionData       []byte   `protobuf:"bytes,2,opt,name=sessionData,proto3" json:"sessionData,omitempty"`
+	ClientSessionData [][]byte `protobuf:"bytes,3,rep,name=clientSessionData,proto3" json:"clientSessionData,omitempty"`
+}
+
+func (x *SessionState) Reset() {
+	*x = SessionState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessionState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionState) ProtoMessage() {}
+
+func (x *SessionState) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionState.ProtoReflect.Descriptor instead.
+func (*SessionState) Descriptor() ([]byte, []int) {
+	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SessionState) GetNodeRevision() int64 {
+	if x != nil {
+		return x.NodeRevision
+	}
+	return 0
+}
+
+func (x *SessionState) GetSessionData() []byte {
+	if x != nil {
+		return x.SessionData
+	}
+	return nil
+}
+
+func (x *SessionState) GetClientSessionData() [][]byte {
+	if x != nil {
+		return x.ClientSessionData
+	}
+	return nil
+}
+
+type SessionOpen struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessionIdentifier string `protobuf:"bytes,1,opt,name=sessionIdentifier,proto3" json:"sessionIdentifier,omitempty"`
+	PodIdentifier     string `protobuf:"bytes,2,opt,name=podIdentifier,proto3" json:"podIdentifier,omitempty"`
+	SessionData       []byte `protobuf:"bytes,3,opt,name=sessionData,proto3" json:"sessionData,omitempty"`
+}
+
+func (x *SessionOpen) Reset() {
+	*x = SessionOpen{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessionOpen) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionOpen) ProtoMessage() {}
+
+func (x *SessionOpen) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionOpen.ProtoReflect.Descriptor instead.
+func (*SessionOpen) Descriptor() ([]byte, []int) {
+	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SessionOpen) GetSessionIdentifier() string {
+	if x != nil {
+		return x.SessionIdentifier
+	}
+	return ""
+}
+
+func (x *SessionOpen) GetPodIdentifier() string {
+	if x != nil {
+		return x.PodIdentifier
+	}
+	return ""
+}
+
+func (x *SessionOpen) GetSessionData() []byte {
+	if x != nil {
+		return x.SessionData
+	}
+	return nil
+}
+
+type SessionClose struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessionIdentifier string `protobuf:"bytes,1,opt,name=sessionIdentifier,proto3" json:"sessionIdentifier,omitempty"`
+	PodIdentifier     string `protobuf:"bytes,2,opt,name=podIdentifier,proto3" json:"podIdentifier,omitempty"`
+	// close grace period of session when it's closed, it could be changed after session is open, 0 keep grace period node has
+	GracePeriodSeconds uint32 `protobuf:"varint,3,opt,name=gracePeriodSeconds,proto3" json:"gracePeriodSeconds,omitempty"`
+}
+
+func (x *SessionClose) Reset() {
+	*x = SessionClose{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessionClose) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionClose) ProtoMessage() {}
+
+func (x *SessionClose) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionClose.ProtoReflect.Descriptor instead.
+func (*SessionClose) Descriptor() ([]byte, []int) {
+	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SessionClose) GetSessionIdentifier() string {
+	if x != nil {
+		return x.SessionIdentifier
+	}
+	return ""
+}
+
+func (x *SessionClose) GetPodIdentifier() string {
+	if x != nil {
+		return x.PodIdentifier
+	}
+	return ""
+}
+
+func (x *SessionClose) GetGracePeriodSeconds() uint32 {
+	if x != nil {
+		return x.GracePeriodSeconds
+	}
+	return 0
+}
+
+// fornax core notify a open session it's going to end before deadline, e.g. pod is evicted or rolled out,
+// node agent relay it to application, so application can tell connected clients to leave
+type SessionDrain struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessionIdentifier string               `protobuf:"bytes,1,opt,name=sessionIdentifier,proto3" json:"sessionIdentifier,omitempty"`
+	PodIdentifier     string               `protobuf:"bytes,2,opt,name=podIdentifier,proto3" json:"podIdentifier,omitempty"`
+	Reason            string               `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Deadline          *timestamp.Timestamp `protobuf:"bytes,4,opt,name=deadline,proto3" json:"deadline,omitempty"`
+}
+
+func (x *SessionDrain) Reset() {
+	*x = SessionDrain{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessionDrain) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionDrain) ProtoMessage() {}
+
+func (x *SessionDrain) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionDrain.ProtoReflect.Descriptor instead.
+func (*SessionDrain) Descriptor() ([]byte, []int) {
+	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SessionDrain) GetSessionIdentifier() string {
+	if x != nil {
+		return x.SessionIdentifier
+	}
+	return ""
+}
+
+func (x *SessionDrain) GetPodIdentifier() string {
+	if x != nil {
+		return x.PodIdentifier
+	}
+	return ""
+}
+
+func (x *SessionDrain) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *SessionDrain) GetDeadline() *timestamp.Timestamp {
+	if x != nil {
+		return x.Deadline
+	}
+	return nil
+}
+
+// fornax core move a open session to another pod, node agent ask application to export session metadata and close session,
+// metadata is reported back in status.migration of session state, fornax core then open session on target pod
+type SessionMigrate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessionIdentifier   string `protobuf:"bytes,1,opt,name=sessionIdentifier,proto3" json:"sessionIdentifier,omitempty"`
+	PodIdentifier       string `protobuf:"bytes,2,opt,name=podIdentifier,proto3" json:"podIdentifier,omitempty"`
+	TargetPodIdentifier string `protobuf:"bytes,3,opt,name=targetPodIdentifier,proto3" json:"targetPodIdentifier,omitempty"`
+}
+
+func (x *SessionMigrate) Reset() {
+	*x = SessionMigrate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessionMigrate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionMigrate) ProtoMessage() {}
+
+func (x *SessionMigrate) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionMigrate.ProtoReflect.Descriptor instead.
+func (*SessionMigrate) Descriptor() ([]byte, []int) {
+	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SessionMigrate) GetSessionIdentifier() string {
+	if x != nil {
+		return x.SessionIdentifier
+	}
+	return ""
+}
+
+func (x *SessionMigrate) GetPodIdentifier() string {
+	if x != nil {
+		return x.PodIdentifier
+	}
+	return ""
+}
+
+func (x *SessionMigrate) GetTargetPodIdentifier() string {
+	if x != nil {
+		return x.TargetPodIdentifier
+	}
+	return ""
+}
+
+// resource usage of pod cgroup attributed to a session in a interval, usage of a pod is shared evenly by its open sessions,
+// node send a record every report period and when session is closed
+type SessionUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessionIdentifier   string               `protobuf:"bytes,1,opt,name=sessionIdentifier,proto3" json:"sessionIdentifier,omitempty"`
+	PodIdentifier       string               `protobuf:"bytes,2,opt,name=podIdentifier,proto3" json:"podIdentifier,omitempty"`
+	StartTime           *timestamp.Timestamp `protobuf:"bytes,3,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	EndTime             *timestamp.Timestamp `protobuf:"bytes,4,opt,name=endTime,proto3" json:"endTime,omitempty"`
+	CpuUsageNanoSeconds uint64               `protobuf:"varint,5,opt,name=cpuUsageNanoSeconds,proto3" json:"cpuUsageNanoSeconds,omitempty"`
+	MemoryByteSeconds   uint64               `protobuf:"varint,6,opt,name=memoryByteSeconds,proto3" json:"memoryByteSeconds,omitempty"`
+	MemoryPeakBytes     uint64               `protobuf:"varint,7,opt,name=memoryPeakBytes,proto3" json:"memoryPeakBytes,omitempty"`
+	Final               bool                 `protobuf:"varint,8,opt,name=final,proto3" json:"final,omitempty"` // last record of session on pod
+}
+
+func (x *SessionUsage) Reset() {
+	*x = SessionUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessionUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionUsage) ProtoMessage() {}
+
+func (x *SessionUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionUsage.ProtoReflect.Descriptor instead.
+func (*SessionUsage) Descriptor() ([]byte, []int) {
+	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SessionUsage) GetSessionIdentifier() string {
+	if x != nil {
+		return x.SessionIdentifier
+	}
+	return ""
+}
+
+func (x *SessionUsage) GetPodIdentifier() string {
+	if x != nil {
+		return x.PodIdentifier
+	}
+	return ""
+}
+
+func (x *SessionUsage) GetStartTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *SessionUsage) GetEndTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *SessionUsage) GetCpuUsageNanoSeconds() uint64 {
+	if x != nil {
+		return x.CpuUsageNanoSeconds
+	}
+	return 0
+}
+
+func (x *SessionUsage) GetMemoryByteSeconds() uint64 {
+	if x != nil {
+		return x.MemoryByteSeconds
+	}
+	return 0
+}
+
+func (x *SessionUsage) GetMemoryPeakBytes() uint64 {
+	if x != nil {
+		return x.MemoryPeakBytes
+	}
+	return 0
+}
+
+func (x *SessionUsage) GetFinal() bool {
+	if x != nil {
+		return x.Final
+	}
+	return false
+}
+
+// node agent watch sessions assigned to pods on this node, sessions of other nodes are filtered out by fornax core,
+// if resourceVersion is empty, current sessions of node are sent as Added events first,
+// knownSessions are sessions node received before, they are sent as Deleted if they are not on node anymore when watch is resumed
+type WatchSessionsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NodeIdentifier  *NodeIdentifier `protobuf:"bytes,1,opt,name=nodeIdentifier,proto3" json:"nodeIdentifier,omitempty"`
+	ResourceVersion string          `protobuf:"bytes,2,opt,name=resourceVersion,proto3" json:"resourceVersion,omitempty"`
+	KnownSessions   []string        `protobuf:"bytes,3,rep,name=knownSessions,proto3" json:"knownSessions,omitempty"`
+}
+
+func (x *WatchSessionsRequest) Reset() {
+	*x = WatchSessionsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchSessionsRequest) ProtoMessage() {}
+
+func (x *WatchSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchSessionsRequest.ProtoReflect.Descriptor instead.
+func (*WatchSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *WatchSessionsRequest) GetNodeIdentifier() *NodeIdentifier {
+	if x != nil {
+		return x.NodeIdentifier
+	}
+	return nil
+}
+
+func (x *WatchSessionsRequest) GetResourceVersion() string {
+	if x != nil {
+		return x.ResourceVersion
+	}
+	return ""
+}
+
+func (x *WatchSessionsRequest) GetKnownSessions() []string {
+	if x != nil {
+		return x.KnownSessions
+	}
+	return nil
+}
+
+// node agent request a client certificate of node, first certificate is requested with a bootstrap token,
+// renewal is authenticated by current certificate of node, csr is a pem encoded certificate signing request
+type CertificateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NodeIdentifier *NodeIdentifier `protobuf:"bytes,1,opt,name=nodeIdentifier,proto3" json:"nodeIdentifier,omitempty"`
+	Csr            []byte          `protobuf:"bytes,2,opt,name=csr,proto3" json:"csr,omitempty"`
+	BootstrapToken string          `protobuf:"bytes,3,opt,name=bootstrapToken,proto3" json:"bootstrapToken,omitempty"`
+}
+
+func (x *CertificateRequest) Reset() {
+	*x = CertificateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CertificateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CertificateRequest) ProtoMessage() {}
+
+func (x *CertificateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CertificateRequest.ProtoReflect.Descriptor instead.
+func (*CertificateRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *CertificateRequest) GetNodeIdentifier() *NodeIdentifier {
+	if x != nil {
+		return x.NodeIdentifier
+	}
+	return nil
+}
+
+func (x *CertificateRequest) GetCsr() []byte {
+	if x != nil {
+		return x.Csr
+	}
+	return nil
+}
+
+func (x *CertificateRequest) GetBootstrapToken() string {
+	if x != nil {
+		return x.BootstrapToken
+	}
+	return ""
+}
+
+type CertificateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Certificate   []byte               `protobuf:"bytes,1,opt,name=certificate,proto3" json:"certificate,omitempty"`
+	CaCertificate []byte               `protobuf:"bytes,2,opt,name=caCertificate,proto3" json:"caCertificate,omitempty"`
+	NotAfter      *timestamp.Timestamp `protobuf:"bytes,3,opt,name=notAfter,proto3" json:"notAfter,omitempty"`
+}
+
+func (x *CertificateResponse) Reset() {
+	*x = CertificateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CertificateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CertificateResponse) ProtoMessage() {}
+
+func (x *CertificateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CertificateResponse.ProtoReflect.Descriptor instead.
+func (*CertificateResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *CertificateResponse) GetCertificate() []byte {
+	if x != nil {
+		return x.Certificate
+	}
+	return nil
+}
+
+func (x *CertificateResponse) GetCaCertificate() []byte {
+	if x != nil {
+		return x.CaCertificate
+	}
+	return nil
+}
+
+func (x *CertificateResponse) GetNotAfter() *timestamp.Timestamp {
+	if x != nil {
+		return x.NotAfter
+	}
+	return nil
+}
+
+// a session change of node, a session moved away from node or whose pod is gone is sent as Deleted,
+// Bookmark only carry resourceVersion, node agent resume watch from resourceVersion of last received event
+type SessionWatchEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type              SessionWatchEvent_Type `protobuf:"varint,1,opt,name=type,proto3,enum=centaurusinfra.io.fornaxcore.service.SessionWatchEvent_Type" json:"type,omitempty"`
+	ResourceVersion   string                 `protobuf:"bytes,2,opt,name=resourceVersion,proto3" json:"resourceVersion,omitempty"`
+	PodIdentifier     string                 `protobuf:"bytes,3,opt,name=podIdentifier,proto3" json:"podIdentifier,omitempty"`
+	SessionData       []byte                 `protobuf:"bytes,4,opt,name=sessionData,proto3" json:"sessionData,omitempty"`
+	SessionIdentifier string                 `protobuf:"bytes,5,opt,name=sessionIdentifier,proto3" json:"sessionIdentifier,omitempty"`
+}
+
+func (x *SessionWatchEvent) Reset() {
+	*x = SessionWatchEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessionWatchEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionWatchEvent) ProtoMessage() {}
+
+func (x *SessionWatchEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionWatchEvent.ProtoReflect.Descriptor instead.
+func (*SessionWatchEvent) Descriptor() ([]byte, []int) {
+	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SessionWatchEvent) GetType() SessionWatchEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return SessionWatchEvent_Added
+}
+
+func (x *SessionWatchEvent) GetResourceVersion() string {
+	if x != nil {
+		return x.ResourceVersion
+	}
+	return ""
+}
+
+func (x *SessionWatchEvent) GetPodIdentifier() string {
+	if x != nil {
+		return x.PodIdentifier
+	}
+	return ""
+}
+
+func (x *SessionWatchEvent) GetSessionData() []byte {
 	if x != nil {
-		return x.SessionIdentifier
+		return x.SessionData
 	}
-	return ""
+	return nil
 }
 
-func (x *SessionClose) GetPodIdentifier() string {
+func (x *SessionWatchEvent) GetSessionIdentifier() string {
 	if x != nil {
-		return x.PodIdentifier
+		return x.SessionIdentifier
 	}
 	return ""
 }
@@ -1379,276 +2569,545 @@ var file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDesc = []byte{
 	0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78,
 	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x1a, 0x1b, 0x67, 0x6f,
 	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d,
-	0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x22, 0x6b, 0x38, 0x73, 0x2e, 0x69,
-	0x6f, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x67, 0x65,
-	0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xa7, 0x0b,
-	0x0a, 0x11, 0x46, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x43, 0x6f, 0x72, 0x65, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x12, 0x2c, 0x0a, 0x11, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x64,
-	0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11,
-	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
-	0x72, 0x12, 0x5c, 0x0a, 0x0e, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66,
-	0x69, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x63, 0x65, 0x6e, 0x74,
-	0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f,
-	0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52,
-	0x0e, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12,
-	0x53, 0x0a, 0x0b, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x31, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73,
-	0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63,
-	0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0b, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x54, 0x79, 0x70, 0x65, 0x12, 0x79, 0x0a, 0x17, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x43, 0x6f,
-	0x72, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
-	0x64, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3d, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75,
+	0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x22, 0x6b, 0x38, 0x73, 0x2e,
+	0x69, 0x6f, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x67,
+	0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf4,
+	0x12, 0x0a, 0x11, 0x46, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x43, 0x6f, 0x72, 0x65, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x2c, 0x0a, 0x11, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49,
+	0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x11, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69,
+	0x65, 0x72, 0x12, 0x5c, 0x0a, 0x0e, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69,
+	0x66, 0x69, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x63, 0x65, 0x6e,
+	0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66,
+	0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x52, 0x0e, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x12, 0x53, 0x0a, 0x0b, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x31, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75,
 	0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78,
-	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x46, 0x6f, 0x72,
-	0x6e, 0x61, 0x78, 0x43, 0x6f, 0x72, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x17, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x43, 0x6f,
-	0x72, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
-	0x68, 0x0a, 0x11, 0x6e, 0x6f, 0x64, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x18, 0xc8, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x37, 0x2e, 0x63, 0x65,
-	0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e,
-	0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x11, 0x6e, 0x6f, 0x64, 0x65, 0x43, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x59, 0x0a, 0x0c, 0x6e, 0x6f, 0x64,
-	0x65, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x18, 0xc9, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x32, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72,
-	0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e,
-	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x67, 0x69,
-	0x73, 0x74, 0x72, 0x79, 0x48, 0x00, 0x52, 0x0c, 0x6e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x67, 0x69,
-	0x73, 0x74, 0x72, 0x79, 0x12, 0x50, 0x0a, 0x09, 0x6e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x61, 0x64,
-	0x79, 0x18, 0xca, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61,
-	0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72,
-	0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
-	0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x61, 0x64, 0x79, 0x48, 0x00, 0x52, 0x09, 0x6e, 0x6f, 0x64,
-	0x65, 0x52, 0x65, 0x61, 0x64, 0x79, 0x12, 0x50, 0x0a, 0x09, 0x6e, 0x6f, 0x64, 0x65, 0x53, 0x74,
-	0x61, 0x74, 0x65, 0x18, 0xcb, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x63, 0x65, 0x6e,
+	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0b, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x2c, 0x0a, 0x11, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x11, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66,
+	0x69, 0x65, 0x72, 0x12, 0x79, 0x0a, 0x17, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x43, 0x6f, 0x72,
+	0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x64,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x3d, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73,
+	0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63,
+	0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x46, 0x6f, 0x72, 0x6e,
+	0x61, 0x78, 0x43, 0x6f, 0x72, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x17, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x43, 0x6f, 0x72,
+	0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x68,
+	0x0a, 0x11, 0x6e, 0x6f, 0x64, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0xc8, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x37, 0x2e, 0x63, 0x65, 0x6e,
 	0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66,
 	0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x48, 0x00, 0x52, 0x09, 0x6e,
-	0x6f, 0x64, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x59, 0x0a, 0x0c, 0x6e, 0x6f, 0x64, 0x65,
-	0x46, 0x75, 0x6c, 0x6c, 0x53, 0x79, 0x6e, 0x63, 0x18, 0xcc, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x65, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x11, 0x6e, 0x6f, 0x64, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x59, 0x0a, 0x0c, 0x6e, 0x6f, 0x64, 0x65,
+	0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x18, 0xc9, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
 	0x32, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61,
 	0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x46, 0x75, 0x6c, 0x6c, 0x53,
-	0x79, 0x6e, 0x63, 0x48, 0x00, 0x52, 0x0c, 0x6e, 0x6f, 0x64, 0x65, 0x46, 0x75, 0x6c, 0x6c, 0x53,
-	0x79, 0x6e, 0x63, 0x12, 0x50, 0x0a, 0x09, 0x70, 0x6f, 0x64, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
-	0x18, 0xac, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75,
-	0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e,
-	0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50,
-	0x6f, 0x64, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x48, 0x00, 0x52, 0x09, 0x70, 0x6f, 0x64, 0x43,
-	0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x59, 0x0a, 0x0c, 0x70, 0x6f, 0x64, 0x54, 0x65, 0x72, 0x6d,
-	0x69, 0x6e, 0x61, 0x74, 0x65, 0x18, 0xad, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x63,
-	0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f,
-	0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x2e, 0x50, 0x6f, 0x64, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x65,
-	0x48, 0x00, 0x52, 0x0c, 0x70, 0x6f, 0x64, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x65,
-	0x12, 0x59, 0x0a, 0x0c, 0x70, 0x6f, 0x64, 0x48, 0x69, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x65,
-	0x18, 0xae, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x72, 0x79, 0x48, 0x00, 0x52, 0x0c, 0x6e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x72, 0x79, 0x12, 0x50, 0x0a, 0x09, 0x6e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x61, 0x64, 0x79,
+	0x18, 0xca, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75,
 	0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e,
-	0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50,
-	0x6f, 0x64, 0x48, 0x69, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x65, 0x48, 0x00, 0x52, 0x0c, 0x70,
-	0x6f, 0x64, 0x48, 0x69, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x65, 0x12, 0x4d, 0x0a, 0x08, 0x70,
-	0x6f, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x18, 0xaf, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2e,
-	0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e,
-	0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x6f, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x48, 0x00,
-	0x52, 0x08, 0x70, 0x6f, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x56, 0x0a, 0x0b, 0x73, 0x65,
-	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4f, 0x70, 0x65, 0x6e, 0x18, 0x90, 0x03, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x31, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72,
-	0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e,
-	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4f,
-	0x70, 0x65, 0x6e, 0x48, 0x00, 0x52, 0x0b, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4f, 0x70,
-	0x65, 0x6e, 0x12, 0x59, 0x0a, 0x0c, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6c, 0x6f,
-	0x73, 0x65, 0x18, 0x91, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x63, 0x65, 0x6e, 0x74,
+	0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4e,
+	0x6f, 0x64, 0x65, 0x52, 0x65, 0x61, 0x64, 0x79, 0x48, 0x00, 0x52, 0x09, 0x6e, 0x6f, 0x64, 0x65,
+	0x52, 0x65, 0x61, 0x64, 0x79, 0x12, 0x50, 0x0a, 0x09, 0x6e, 0x6f, 0x64, 0x65, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x18, 0xcb, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x63, 0x65, 0x6e, 0x74,
 	0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f,
 	0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x48, 0x00, 0x52,
-	0x0c, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x12, 0x59, 0x0a,
-	0x0c, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x18, 0x92, 0x03,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73,
+	0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x48, 0x00, 0x52, 0x09, 0x6e, 0x6f,
+	0x64, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x59, 0x0a, 0x0c, 0x6e, 0x6f, 0x64, 0x65, 0x46,
+	0x75, 0x6c, 0x6c, 0x53, 0x79, 0x6e, 0x63, 0x18, 0xcc, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x32,
+	0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e,
+	0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x46, 0x75, 0x6c, 0x6c, 0x53, 0x79,
+	0x6e, 0x63, 0x48, 0x00, 0x52, 0x0c, 0x6e, 0x6f, 0x64, 0x65, 0x46, 0x75, 0x6c, 0x6c, 0x53, 0x79,
+	0x6e, 0x63, 0x12, 0x5f, 0x0a, 0x0e, 0x6e, 0x6f, 0x64, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52,
+	0x65, 0x6e, 0x65, 0x77, 0x18, 0xcd, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x63, 0x65,
+	0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e,
+	0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x6e, 0x65,
+	0x77, 0x48, 0x00, 0x52, 0x0e, 0x6e, 0x6f, 0x64, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65,
+	0x6e, 0x65, 0x77, 0x12, 0x77, 0x0a, 0x16, 0x6e, 0x6f, 0x64, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0xce, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x3c, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73,
 	0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63,
-	0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73,
-	0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x48, 0x00, 0x52, 0x0c, 0x73, 0x65, 0x73, 0x73,
-	0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x42, 0x0d, 0x0a, 0x0b, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x42, 0x6f, 0x64, 0x79, 0x22, 0x3c, 0x0a, 0x0a, 0x46, 0x6f, 0x72, 0x6e, 0x61,
-	0x78, 0x43, 0x6f, 0x72, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x02, 0x69, 0x70, 0x12, 0x1e, 0x0a, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66,
-	0x69, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74,
-	0x69, 0x66, 0x69, 0x65, 0x72, 0x22, 0xb3, 0x01, 0x0a, 0x17, 0x46, 0x6f, 0x72, 0x6e, 0x61, 0x78,
-	0x43, 0x6f, 0x72, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x12, 0x4a, 0x0a, 0x07, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x30, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e,
-	0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72,
-	0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x46, 0x6f, 0x72, 0x6e, 0x61, 0x78,
-	0x43, 0x6f, 0x72, 0x65, 0x52, 0x07, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x4c, 0x0a,
-	0x08, 0x73, 0x74, 0x61, 0x6e, 0x64, 0x62, 0x79, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x30, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61,
-	0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x46, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x43, 0x6f, 0x72,
-	0x65, 0x52, 0x08, 0x73, 0x74, 0x61, 0x6e, 0x64, 0x62, 0x79, 0x73, 0x22, 0x40, 0x0a, 0x0e, 0x4e,
-	0x6f, 0x64, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x0e, 0x0a,
-	0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x12, 0x1e, 0x0a,
-	0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x22, 0x60, 0x0a,
-	0x0c, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x12, 0x22, 0x0a,
-	0x0c, 0x6e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x03, 0x52, 0x0c, 0x6e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f,
-	0x6e, 0x12, 0x2c, 0x0a, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x18, 0x2e, 0x6b, 0x38, 0x73, 0x2e, 0x69, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x63, 0x6f, 0x72,
-	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x22,
-	0xa0, 0x01, 0x0a, 0x11, 0x4e, 0x6f, 0x64, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x0d, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72,
-	0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6c,
-	0x75, 0x73, 0x74, 0x65, 0x72, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x12, 0x2c, 0x0a, 0x04, 0x6e,
-	0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6b, 0x38, 0x73, 0x2e,
-	0x69, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4e,
-	0x6f, 0x64, 0x65, 0x52, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x12, 0x37, 0x0a, 0x0a, 0x64, 0x61, 0x65,
-	0x6d, 0x6f, 0x6e, 0x50, 0x6f, 0x64, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e,
-	0x6b, 0x38, 0x73, 0x2e, 0x69, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e,
-	0x76, 0x31, 0x2e, 0x50, 0x6f, 0x64, 0x52, 0x0a, 0x64, 0x61, 0x65, 0x6d, 0x6f, 0x6e, 0x50, 0x6f,
-	0x64, 0x73, 0x22, 0x85, 0x02, 0x0a, 0x09, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x61, 0x64, 0x79,
-	0x12, 0x22, 0x0a, 0x0c, 0x6e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x76, 0x69,
-	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2c, 0x0a, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6b, 0x38, 0x73, 0x2e, 0x69, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e,
-	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6e, 0x6f,
-	0x64, 0x65, 0x12, 0x4c, 0x0a, 0x09, 0x70, 0x6f, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x73, 0x18,
-	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75,
-	0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78,
-	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x6f, 0x64,
-	0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x09, 0x70, 0x6f, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x73,
-	0x12, 0x58, 0x0a, 0x0d, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65,
-	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75,
+	0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4e, 0x6f, 0x64, 0x65,
+	0x4c, 0x65, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x48, 0x00, 0x52, 0x16, 0x6e, 0x6f, 0x64, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x68, 0x0a, 0x11,
+	0x6e, 0x6f, 0x64, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c,
+	0x65, 0x18, 0xcf, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x37, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61,
+	0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72,
+	0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x4e, 0x6f, 0x64, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c,
+	0x65, 0x48, 0x00, 0x52, 0x11, 0x6e, 0x6f, 0x64, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x50,
+	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x7a, 0x0a, 0x17, 0x6e, 0x6f, 0x64, 0x65, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0xd0, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3d, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61,
+	0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72,
+	0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x4e, 0x6f, 0x64, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c,
+	0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x48, 0x00, 0x52, 0x17, 0x6e, 0x6f, 0x64, 0x65, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x50, 0x0a, 0x09, 0x70, 0x6f, 0x64, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x18,
+	0xac, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72,
+	0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61,
+	0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x6f,
+	0x64, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x48, 0x00, 0x52, 0x09, 0x70, 0x6f, 0x64, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x12, 0x59, 0x0a, 0x0c, 0x70, 0x6f, 0x64, 0x54, 0x65, 0x72, 0x6d, 0x69,
+	0x6e, 0x61, 0x74, 0x65, 0x18, 0xad, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x63, 0x65,
+	0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e,
+	0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x50, 0x6f, 0x64, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x48,
+	0x00, 0x52, 0x0c, 0x70, 0x6f, 0x64, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x12,
+	0x59, 0x0a, 0x0c, 0x70, 0x6f, 0x64, 0x48, 0x69, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x65, 0x18,
+	0xae, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72,
+	0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61,
+	0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x6f,
+	0x64, 0x48, 0x69, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x65, 0x48, 0x00, 0x52, 0x0c, 0x70, 0x6f,
+	0x64, 0x48, 0x69, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x65, 0x12, 0x4d, 0x0a, 0x08, 0x70, 0x6f,
+	0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x18, 0xaf, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2e, 0x2e,
+	0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69,
+	0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x6f, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x48, 0x00, 0x52,
+	0x08, 0x70, 0x6f, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x62, 0x0a, 0x0f, 0x70, 0x6f, 0x64,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x18, 0xb0, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x35, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69,
+	0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f,
+	0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x6f, 0x64, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x48, 0x00, 0x52, 0x0f, 0x70, 0x6f,
+	0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x62, 0x0a,
+	0x0f, 0x70, 0x6f, 0x64, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x18, 0xb1, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x35, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75,
+	0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e,
+	0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50,
+	0x6f, 0x64, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x48, 0x00,
+	0x52, 0x0f, 0x70, 0x6f, 0x64, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x12, 0x56, 0x0a, 0x0b, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4f, 0x70, 0x65, 0x6e,
+	0x18, 0x90, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75,
 	0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e,
 	0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53,
-	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0d, 0x73, 0x65, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x73, 0x22, 0xab, 0x01, 0x0a, 0x09, 0x4e,
-	0x6f, 0x64, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x6e, 0x6f, 0x64, 0x65,
-	0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c,
-	0x6e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2c, 0x0a, 0x04,
-	0x6e, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6b, 0x38, 0x73,
-	0x2e, 0x69, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e,
-	0x4e, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x12, 0x4c, 0x0a, 0x09, 0x70, 0x6f,
-	0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2e, 0x2e,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4f, 0x70, 0x65, 0x6e, 0x48, 0x00, 0x52, 0x0b, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4f, 0x70, 0x65, 0x6e, 0x12, 0x59, 0x0a, 0x0c, 0x73, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x18, 0x91, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x32, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72,
+	0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43,
+	0x6c, 0x6f, 0x73, 0x65, 0x48, 0x00, 0x52, 0x0c, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43,
+	0x6c, 0x6f, 0x73, 0x65, 0x12, 0x59, 0x0a, 0x0c, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x18, 0x92, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x63, 0x65,
+	0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e,
+	0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x48,
+	0x00, 0x52, 0x0c, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x59, 0x0a, 0x0c, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x18,
+	0x93, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72,
+	0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61,
+	0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x48, 0x00, 0x52, 0x0c, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x12, 0x5f, 0x0a, 0x0e, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x65, 0x18, 0x94, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69,
+	0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f,
+	0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x65, 0x48, 0x00, 0x52, 0x0e, 0x73, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x65, 0x12, 0x59, 0x0a, 0x0c, 0x73,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x55, 0x73, 0x61, 0x67, 0x65, 0x18, 0x95, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x32, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e,
+	0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x55, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x0c, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x55, 0x73, 0x61, 0x67, 0x65, 0x42, 0x0d, 0x0a, 0x0b, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x42, 0x6f, 0x64, 0x79, 0x22, 0x3c, 0x0a, 0x0a, 0x46, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x43,
+	0x6f, 0x72, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x02, 0x69, 0x70, 0x12, 0x1e, 0x0a, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
+	0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66,
+	0x69, 0x65, 0x72, 0x22, 0xb3, 0x01, 0x0a, 0x17, 0x46, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x43, 0x6f,
+	0x72, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x4a, 0x0a, 0x07, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x30, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72,
+	0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x46, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x43, 0x6f,
+	0x72, 0x65, 0x52, 0x07, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x4c, 0x0a, 0x08, 0x73,
+	0x74, 0x61, 0x6e, 0x64, 0x62, 0x79, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x30, 0x2e,
 	0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69,
 	0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x6f, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x09, 0x70,
-	0x6f, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x73, 0x22, 0x0e, 0x0a, 0x0c, 0x4e, 0x6f, 0x64, 0x65,
-	0x46, 0x75, 0x6c, 0x6c, 0x53, 0x79, 0x6e, 0x63, 0x22, 0xc0, 0x03, 0x0a, 0x08, 0x50, 0x6f, 0x64,
-	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x6e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x76,
-	0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6e, 0x6f, 0x64,
-	0x65, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x4a, 0x0a, 0x05, 0x73, 0x74, 0x61,
-	0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x34, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x46, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x43, 0x6f, 0x72, 0x65, 0x52,
+	0x08, 0x73, 0x74, 0x61, 0x6e, 0x64, 0x62, 0x79, 0x73, 0x22, 0x40, 0x0a, 0x0e, 0x4e, 0x6f, 0x64,
+	0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x12, 0x1e, 0x0a, 0x0a, 0x69,
+	0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x22, 0x98, 0x01, 0x0a, 0x0c,
+	0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x12, 0x22, 0x0a, 0x0c,
+	0x6e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0c, 0x6e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x2c, 0x0a, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x6b, 0x38, 0x73, 0x2e, 0x69, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x63, 0x6f, 0x72, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x12, 0x36,
+	0x0a, 0x08, 0x6e, 0x6f, 0x64, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x6e, 0x6f,
+	0x64, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x22, 0xf4, 0x01, 0x0a, 0x11, 0x4e, 0x6f, 0x64, 0x65, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x0d,
+	0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x44, 0x6f, 0x6d, 0x61,
+	0x69, 0x6e, 0x12, 0x2c, 0x0a, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x18, 0x2e, 0x6b, 0x38, 0x73, 0x2e, 0x69, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x63, 0x6f,
+	0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6e, 0x6f, 0x64, 0x65,
+	0x12, 0x37, 0x0a, 0x0a, 0x64, 0x61, 0x65, 0x6d, 0x6f, 0x6e, 0x50, 0x6f, 0x64, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6b, 0x38, 0x73, 0x2e, 0x69, 0x6f, 0x2e, 0x61, 0x70,
+	0x69, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x64, 0x52, 0x0a, 0x64,
+	0x61, 0x65, 0x6d, 0x6f, 0x6e, 0x50, 0x6f, 0x64, 0x73, 0x12, 0x52, 0x0a, 0x05, 0x6c, 0x65, 0x61,
+	0x73, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3c, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61,
 	0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72,
 	0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
-	0x50, 0x6f, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05,
-	0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x29, 0x0a, 0x03, 0x70, 0x6f, 0x64, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6b, 0x38, 0x73, 0x2e, 0x69, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e,
-	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x64, 0x52, 0x03, 0x70, 0x6f, 0x64,
-	0x12, 0x4d, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x31, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e,
+	0x4e, 0x6f, 0x64, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x05, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x22, 0x85, 0x02,
+	0x0a, 0x09, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x61, 0x64, 0x79, 0x12, 0x22, 0x0a, 0x0c, 0x6e,
+	0x6f, 0x64, 0x65, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0c, 0x6e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x2c, 0x0a, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e,
+	0x6b, 0x38, 0x73, 0x2e, 0x69, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x12, 0x4c, 0x0a,
+	0x09, 0x70, 0x6f, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x2e, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72,
+	0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x6f, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x09, 0x70, 0x6f, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x73, 0x12, 0x58, 0x0a, 0x0d, 0x73,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x32, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e,
 	0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72,
-	0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x6f, 0x64, 0x52, 0x65, 0x73,
-	0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x08, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12,
-	0x58, 0x0a, 0x0d, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x73,
-	0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72,
+	0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0d, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x73, 0x22, 0xab, 0x01, 0x0a, 0x09, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x6e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x76, 0x69, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6e, 0x6f, 0x64, 0x65, 0x52,
+	0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2c, 0x0a, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6b, 0x38, 0x73, 0x2e, 0x69, 0x6f, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52,
+	0x04, 0x6e, 0x6f, 0x64, 0x65, 0x12, 0x4c, 0x0a, 0x09, 0x70, 0x6f, 0x64, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61,
+	0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72,
+	0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x50, 0x6f, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x09, 0x70, 0x6f, 0x64, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x73, 0x22, 0x0e, 0x0a, 0x0c, 0x4e, 0x6f, 0x64, 0x65, 0x46, 0x75, 0x6c, 0x6c, 0x53,
+	0x79, 0x6e, 0x63, 0x22, 0xa0, 0x01, 0x0a, 0x0e, 0x4e, 0x6f, 0x64, 0x65, 0x4c, 0x65, 0x61, 0x73,
+	0x65, 0x52, 0x65, 0x6e, 0x65, 0x77, 0x12, 0x22, 0x0a, 0x0c, 0x6e, 0x6f, 0x64, 0x65, 0x52, 0x65,
+	0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6e, 0x6f,
+	0x64, 0x65, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x32, 0x0a, 0x14, 0x72, 0x65,
+	0x6e, 0x65, 0x77, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x53, 0x65, 0x63, 0x6f, 0x6e,
+	0x64, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x14, 0x72, 0x65, 0x6e, 0x65, 0x77, 0x49,
+	0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x36,
+	0x0a, 0x08, 0x6e, 0x6f, 0x64, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x6e, 0x6f,
+	0x64, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x80, 0x01, 0x0a, 0x16, 0x4e, 0x6f, 0x64, 0x65, 0x4c,
+	0x65, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x32, 0x0a, 0x14, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x14, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65,
+	0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x32, 0x0a, 0x14, 0x72, 0x65, 0x6e, 0x65, 0x77, 0x49, 0x6e,
+	0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x14, 0x72, 0x65, 0x6e, 0x65, 0x77, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76,
+	0x61, 0x6c, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x22, 0x5b, 0x0a, 0x11, 0x4e, 0x6f, 0x64,
+	0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x70, 0x65, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x04, 0x73, 0x70, 0x65, 0x63, 0x22, 0x83, 0x01, 0x0a, 0x17, 0x4e, 0x6f, 0x64, 0x65, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x67, 0x65, 0x6e, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x69,
+	0x61, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x6c,
+	0x69, 0x61, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x72, 0x69, 0x66, 0x74, 0x73, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x64, 0x72, 0x69, 0x66, 0x74, 0x73, 0x22, 0xc0, 0x03, 0x0a,
+	0x08, 0x50, 0x6f, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x6e, 0x6f, 0x64,
+	0x65, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0c, 0x6e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x4a, 0x0a,
+	0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x34, 0x2e, 0x63,
+	0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f,
+	0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x50, 0x6f, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x29, 0x0a, 0x03, 0x70, 0x6f, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6b, 0x38, 0x73, 0x2e, 0x69, 0x6f, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x64, 0x52,
+	0x03, 0x70, 0x6f, 0x64, 0x12, 0x4d, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72,
 	0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61,
-	0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65,
-	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0d, 0x73, 0x65, 0x73, 0x73,
-	0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x73, 0x22, 0x70, 0x0a, 0x05, 0x53, 0x74, 0x61,
-	0x74, 0x65, 0x12, 0x0c, 0x0a, 0x08, 0x43, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x10, 0x00,
-	0x12, 0x0b, 0x0a, 0x07, 0x53, 0x74, 0x61, 0x6e, 0x64, 0x62, 0x79, 0x10, 0x0a, 0x12, 0x0e, 0x0a,
-	0x0a, 0x41, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x10, 0x14, 0x12, 0x0b, 0x0a,
-	0x07, 0x52, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x10, 0x1e, 0x12, 0x0e, 0x0a, 0x0a, 0x45, 0x76,
-	0x61, 0x63, 0x75, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x10, 0x28, 0x12, 0x0f, 0x0a, 0x0b, 0x54, 0x65,
-	0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x10, 0x32, 0x12, 0x0e, 0x0a, 0x0a, 0x54,
-	0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x64, 0x10, 0x3c, 0x22, 0xa6, 0x01, 0x0a, 0x0b,
-	0x50, 0x6f, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x59, 0x0a, 0x13, 0x72,
-	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x53, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x6b, 0x38, 0x73, 0x2e, 0x69,
-	0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65,
-	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x53, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x52, 0x13, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x51, 0x75, 0x6f, 0x74, 0x61,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x3c, 0x0a, 0x07, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65,
-	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x6b, 0x38, 0x73, 0x2e, 0x69, 0x6f,
-	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x74, 0x74,
-	0x61, 0x63, 0x68, 0x65, 0x64, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x52, 0x07, 0x76, 0x6f, 0x6c,
-	0x75, 0x6d, 0x65, 0x73, 0x22, 0x99, 0x01, 0x0a, 0x09, 0x50, 0x6f, 0x64, 0x43, 0x72, 0x65, 0x61,
+	0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x6f,
+	0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x08, 0x72, 0x65, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x12, 0x58, 0x0a, 0x0d, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x63, 0x65, 0x6e,
+	0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66,
+	0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0d,
+	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x73, 0x22, 0x70, 0x0a,
+	0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0c, 0x0a, 0x08, 0x43, 0x72, 0x65, 0x61, 0x74, 0x69,
+	0x6e, 0x67, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x74, 0x61, 0x6e, 0x64, 0x62, 0x79, 0x10,
+	0x0a, 0x12, 0x0e, 0x0a, 0x0a, 0x41, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x10,
+	0x14, 0x12, 0x0b, 0x0a, 0x07, 0x52, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x10, 0x1e, 0x12, 0x0e,
+	0x0a, 0x0a, 0x45, 0x76, 0x61, 0x63, 0x75, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x10, 0x28, 0x12, 0x0f,
+	0x0a, 0x0b, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x10, 0x32, 0x12,
+	0x0e, 0x0a, 0x0a, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x64, 0x10, 0x3c, 0x22,
+	0xa6, 0x01, 0x0a, 0x0b, 0x50, 0x6f, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12,
+	0x59, 0x0a, 0x13, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x51, 0x75, 0x6f, 0x74, 0x61,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x6b,
+	0x38, 0x73, 0x2e, 0x69, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x13, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x51,
+	0x75, 0x6f, 0x74, 0x61, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x3c, 0x0a, 0x07, 0x76, 0x6f,
+	0x6c, 0x75, 0x6d, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x6b, 0x38,
+	0x73, 0x2e, 0x69, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x65, 0x64, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x52,
+	0x07, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x73, 0x22, 0x99, 0x01, 0x0a, 0x09, 0x50, 0x6f, 0x64,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70,
+	0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x29, 0x0a, 0x03,
+	0x70, 0x6f, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6b, 0x38, 0x73, 0x2e,
+	0x69, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x6f, 0x64, 0x52, 0x03, 0x70, 0x6f, 0x64, 0x12, 0x3b, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x4d, 0x61, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6b, 0x38, 0x73,
+	0x2e, 0x69, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4d, 0x61, 0x70, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x4d, 0x61, 0x70, 0x22, 0x34, 0x0a, 0x0c, 0x50, 0x6f, 0x64, 0x54, 0x65, 0x72, 0x6d, 0x69,
+	0x6e, 0x61, 0x74, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x6f, 0x64,
+	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x22, 0x34, 0x0a, 0x0c, 0x50, 0x6f,
+	0x64, 0x48, 0x69, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x70, 0x6f,
+	0x64, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x22, 0x74, 0x0a, 0x0f, 0x50, 0x6f, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69,
+	0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x6f, 0x64, 0x49,
+	0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x3b, 0x0a, 0x09, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x4d, 0x61, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6b,
+	0x38, 0x73, 0x2e, 0x69, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4d, 0x61, 0x70, 0x52, 0x09, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x4d, 0x61, 0x70, 0x22, 0xb5, 0x01, 0x0a, 0x0d, 0x53, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x51, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x3d, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72,
+	0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x04, 0x64, 0x61, 0x74, 0x61, 0x1a, 0x37, 0x0a, 0x09, 0x44, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xe1,
+	0x01, 0x0a, 0x0f, 0x50, 0x6f, 0x64, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x55, 0x70, 0x64, 0x61,
 	0x74, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66,
 	0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x6f, 0x64, 0x49, 0x64,
-	0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x29, 0x0a, 0x03, 0x70, 0x6f, 0x64, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6b, 0x38, 0x73, 0x2e, 0x69, 0x6f, 0x2e, 0x61,
-	0x70, 0x69, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x64, 0x52, 0x03,
-	0x70, 0x6f, 0x64, 0x12, 0x3b, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4d, 0x61, 0x70,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6b, 0x38, 0x73, 0x2e, 0x69, 0x6f, 0x2e,
-	0x61, 0x70, 0x69, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x4d, 0x61, 0x70, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4d, 0x61, 0x70,
-	0x22, 0x34, 0x0a, 0x0c, 0x50, 0x6f, 0x64, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x65,
-	0x12, 0x24, 0x0a, 0x0d, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
-	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e,
-	0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x22, 0x34, 0x0a, 0x0c, 0x50, 0x6f, 0x64, 0x48, 0x69, 0x62,
-	0x65, 0x72, 0x6e, 0x61, 0x74, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x65,
-	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70,
-	0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x22, 0x82, 0x01, 0x0a,
-	0x0c, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x22, 0x0a,
-	0x0c, 0x6e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x03, 0x52, 0x0c, 0x6e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f,
-	0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x44,
-	0x61, 0x74, 0x61, 0x12, 0x2c, 0x0a, 0x11, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x11,
-	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74,
-	0x61, 0x22, 0x83, 0x01, 0x0a, 0x0b, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4f, 0x70, 0x65,
-	0x6e, 0x12, 0x2c, 0x0a, 0x11, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x65, 0x6e,
-	0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x73, 0x65,
-	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12,
-	0x24, 0x0a, 0x0d, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74,
-	0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
-	0x44, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x73, 0x65, 0x73, 0x73,
-	0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x22, 0x62, 0x0a, 0x0c, 0x53, 0x65, 0x73, 0x73, 0x69,
-	0x6f, 0x6e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x11, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x4b, 0x0a, 0x06, 0x73, 0x65, 0x63, 0x72,
+	0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x33, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61,
+	0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72,
+	0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x73,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x5b, 0x0a, 0x0e, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75,
+	0x73, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x33, 0x2e,
+	0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69,
+	0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x52, 0x0e, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x53, 0x65, 0x63, 0x72,
+	0x65, 0x74, 0x22, 0x82, 0x01, 0x0a, 0x0c, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x6e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x76, 0x69, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6e, 0x6f, 0x64, 0x65, 0x52,
+	0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x12, 0x2c, 0x0a, 0x11, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0c, 0x52, 0x11, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x22, 0x83, 0x01, 0x0a, 0x0b, 0x53, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x4f, 0x70, 0x65, 0x6e, 0x12, 0x2c, 0x0a, 0x11, 0x73, 0x65, 0x73, 0x73, 0x69,
 	0x6f, 0x6e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01,
 	0x28, 0x09, 0x52, 0x11, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x65, 0x6e, 0x74,
 	0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x24, 0x0a, 0x0d, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e,
 	0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x6f,
-	0x64, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x2a, 0xa5, 0x02, 0x0a, 0x0b,
-	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x55,
-	0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1d, 0x0a, 0x19,
-	0x46, 0x4f, 0x52, 0x4e, 0x41, 0x58, 0x5f, 0x43, 0x4f, 0x52, 0x45, 0x5f, 0x43, 0x4f, 0x4e, 0x46,
-	0x49, 0x47, 0x55, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x64, 0x12, 0x17, 0x0a, 0x12, 0x4e,
-	0x4f, 0x44, 0x45, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x55, 0x52, 0x41, 0x54, 0x49, 0x4f,
-	0x4e, 0x10, 0xc8, 0x01, 0x12, 0x12, 0x0a, 0x0d, 0x4e, 0x4f, 0x44, 0x45, 0x5f, 0x52, 0x45, 0x47,
-	0x49, 0x53, 0x54, 0x45, 0x52, 0x10, 0xc9, 0x01, 0x12, 0x0f, 0x0a, 0x0a, 0x4e, 0x4f, 0x44, 0x45,
-	0x5f, 0x52, 0x45, 0x41, 0x44, 0x59, 0x10, 0xca, 0x01, 0x12, 0x0f, 0x0a, 0x0a, 0x4e, 0x4f, 0x44,
-	0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x10, 0xcb, 0x01, 0x12, 0x13, 0x0a, 0x0e, 0x4e, 0x4f,
-	0x44, 0x45, 0x5f, 0x46, 0x55, 0x4c, 0x4c, 0x5f, 0x53, 0x59, 0x4e, 0x43, 0x10, 0xcc, 0x01, 0x12,
-	0x0f, 0x0a, 0x0a, 0x50, 0x4f, 0x44, 0x5f, 0x43, 0x52, 0x45, 0x41, 0x54, 0x45, 0x10, 0xac, 0x02,
-	0x12, 0x12, 0x0a, 0x0d, 0x50, 0x4f, 0x44, 0x5f, 0x54, 0x45, 0x52, 0x4d, 0x49, 0x4e, 0x41, 0x54,
-	0x45, 0x10, 0xad, 0x02, 0x12, 0x12, 0x0a, 0x0d, 0x50, 0x4f, 0x44, 0x5f, 0x48, 0x49, 0x42, 0x45,
-	0x52, 0x4e, 0x41, 0x54, 0x45, 0x10, 0xae, 0x02, 0x12, 0x0e, 0x0a, 0x09, 0x50, 0x4f, 0x44, 0x5f,
-	0x53, 0x54, 0x41, 0x54, 0x45, 0x10, 0xaf, 0x02, 0x12, 0x11, 0x0a, 0x0c, 0x53, 0x45, 0x53, 0x53,
-	0x49, 0x4f, 0x4e, 0x5f, 0x4f, 0x50, 0x45, 0x4e, 0x10, 0x90, 0x03, 0x12, 0x12, 0x0a, 0x0d, 0x53,
-	0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x43, 0x4c, 0x4f, 0x53, 0x45, 0x10, 0x91, 0x03, 0x12,
-	0x12, 0x0a, 0x0d, 0x53, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45,
-	0x10, 0x92, 0x03, 0x32, 0xf1, 0x01, 0x0a, 0x11, 0x46, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x43, 0x6f,
-	0x72, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x7d, 0x0a, 0x0a, 0x67, 0x65, 0x74,
-	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x34, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75,
-	0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e,
-	0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4e,
-	0x6f, 0x64, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x1a, 0x37, 0x2e,
-	0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69,
-	0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x2e, 0x46, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x43, 0x6f, 0x72, 0x65, 0x4d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x30, 0x01, 0x12, 0x5d, 0x0a, 0x0a, 0x70, 0x75, 0x74, 0x4d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x37, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72,
+	0x64, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x73,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0b, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x22, 0x92, 0x01,
+	0x0a, 0x0c, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x12, 0x2c,
+	0x0a, 0x11, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66,
+	0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x24, 0x0a, 0x0d,
+	0x70, 0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69,
+	0x65, 0x72, 0x12, 0x2e, 0x0a, 0x12, 0x67, 0x72, 0x61, 0x63, 0x65, 0x50, 0x65, 0x72, 0x69, 0x6f,
+	0x64, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x12,
+	0x67, 0x72, 0x61, 0x63, 0x65, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x53, 0x65, 0x63, 0x6f, 0x6e,
+	0x64, 0x73, 0x22, 0xb2, 0x01, 0x0a, 0x0c, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x44, 0x72,
+	0x61, 0x69, 0x6e, 0x12, 0x2c, 0x0a, 0x11, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11,
+	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
+	0x72, 0x12, 0x24, 0x0a, 0x0d, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69,
+	0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12,
+	0x36, 0x0a, 0x08, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x64,
+	0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x22, 0x96, 0x01, 0x0a, 0x0e, 0x53, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x65, 0x12, 0x2c, 0x0a, 0x11, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x24, 0x0a, 0x0d, 0x70, 0x6f, 0x64, 0x49,
+	0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0d, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x30,
+	0x0a, 0x13, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x50, 0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x74, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x50, 0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x22, 0xf2, 0x02, 0x0a, 0x0c, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x55, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x2c, 0x0a, 0x11, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12,
+	0x24, 0x0a, 0x0d, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x38, 0x0a, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69,
+	0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12,
+	0x34, 0x0a, 0x07, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x65, 0x6e,
+	0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x30, 0x0a, 0x13, 0x63, 0x70, 0x75, 0x55, 0x73, 0x61, 0x67,
+	0x65, 0x4e, 0x61, 0x6e, 0x6f, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x13, 0x63, 0x70, 0x75, 0x55, 0x73, 0x61, 0x67, 0x65, 0x4e, 0x61, 0x6e, 0x6f,
+	0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x2c, 0x0a, 0x11, 0x6d, 0x65, 0x6d, 0x6f, 0x72,
+	0x79, 0x42, 0x79, 0x74, 0x65, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x11, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x42, 0x79, 0x74, 0x65, 0x53, 0x65,
+	0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x28, 0x0a, 0x0f, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x50,
+	0x65, 0x61, 0x6b, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f,
+	0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x50, 0x65, 0x61, 0x6b, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12,
+	0x14, 0x0a, 0x05, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05,
+	0x66, 0x69, 0x6e, 0x61, 0x6c, 0x22, 0xc4, 0x01, 0x0a, 0x14, 0x57, 0x61, 0x74, 0x63, 0x68, 0x53,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x5c,
+	0x0a, 0x0e, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72,
 	0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61,
-	0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x46, 0x6f,
-	0x72, 0x6e, 0x61, 0x78, 0x43, 0x6f, 0x72, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a,
-	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x42, 0x39, 0x5a, 0x37, 0x63, 0x65, 0x6e, 0x74, 0x61,
+	0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4e, 0x6f,
+	0x64, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x0e, 0x6e, 0x6f,
+	0x64, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x28, 0x0a, 0x0f,
+	0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x0d, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x53,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x6b,
+	0x6e, 0x6f, 0x77, 0x6e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xac, 0x01, 0x0a,
+	0x12, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x5c, 0x0a, 0x0e, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x63, 0x65,
+	0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e,
+	0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
+	0x72, 0x52, 0x0e, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
+	0x72, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x73, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03,
+	0x63, 0x73, 0x72, 0x12, 0x26, 0x0a, 0x0e, 0x62, 0x6f, 0x6f, 0x74, 0x73, 0x74, 0x72, 0x61, 0x70,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x62, 0x6f, 0x6f,
+	0x74, 0x73, 0x74, 0x72, 0x61, 0x70, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x95, 0x01, 0x0a, 0x13,
+	0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61,
+	0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x63, 0x61, 0x43, 0x65, 0x72, 0x74, 0x69,
+	0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x63, 0x61,
+	0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x36, 0x0a, 0x08, 0x6e,
+	0x6f, 0x74, 0x41, 0x66, 0x74, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x6e, 0x6f, 0x74, 0x41, 0x66,
+	0x74, 0x65, 0x72, 0x22, 0xc1, 0x02, 0x0a, 0x11, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x57,
+	0x61, 0x74, 0x63, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x50, 0x0a, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x3c, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75,
+	0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e,
+	0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x72,
+	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x0d, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x6f,
+	0x64, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x73,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0b, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x12, 0x2c, 0x0a,
+	0x11, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69,
+	0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x22, 0x3a, 0x0a, 0x04, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x09, 0x0a, 0x05, 0x41, 0x64, 0x64, 0x65, 0x64, 0x10, 0x00, 0x12, 0x0c,
+	0x0a, 0x08, 0x4d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x65, 0x64, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x42, 0x6f, 0x6f,
+	0x6b, 0x6d, 0x61, 0x72, 0x6b, 0x10, 0x03, 0x2a, 0x84, 0x04, 0x0a, 0x0b, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1d, 0x0a, 0x19, 0x46, 0x4f, 0x52, 0x4e,
+	0x41, 0x58, 0x5f, 0x43, 0x4f, 0x52, 0x45, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x55, 0x52,
+	0x41, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x64, 0x12, 0x17, 0x0a, 0x12, 0x4e, 0x4f, 0x44, 0x45, 0x5f,
+	0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x55, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0xc8, 0x01,
+	0x12, 0x12, 0x0a, 0x0d, 0x4e, 0x4f, 0x44, 0x45, 0x5f, 0x52, 0x45, 0x47, 0x49, 0x53, 0x54, 0x45,
+	0x52, 0x10, 0xc9, 0x01, 0x12, 0x0f, 0x0a, 0x0a, 0x4e, 0x4f, 0x44, 0x45, 0x5f, 0x52, 0x45, 0x41,
+	0x44, 0x59, 0x10, 0xca, 0x01, 0x12, 0x0f, 0x0a, 0x0a, 0x4e, 0x4f, 0x44, 0x45, 0x5f, 0x53, 0x54,
+	0x41, 0x54, 0x45, 0x10, 0xcb, 0x01, 0x12, 0x13, 0x0a, 0x0e, 0x4e, 0x4f, 0x44, 0x45, 0x5f, 0x46,
+	0x55, 0x4c, 0x4c, 0x5f, 0x53, 0x59, 0x4e, 0x43, 0x10, 0xcc, 0x01, 0x12, 0x15, 0x0a, 0x10, 0x4e,
+	0x4f, 0x44, 0x45, 0x5f, 0x4c, 0x45, 0x41, 0x53, 0x45, 0x5f, 0x52, 0x45, 0x4e, 0x45, 0x57, 0x10,
+	0xcd, 0x01, 0x12, 0x1d, 0x0a, 0x18, 0x4e, 0x4f, 0x44, 0x45, 0x5f, 0x4c, 0x45, 0x41, 0x53, 0x45,
+	0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x55, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0xce,
+	0x01, 0x12, 0x18, 0x0a, 0x13, 0x4e, 0x4f, 0x44, 0x45, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47,
+	0x5f, 0x50, 0x52, 0x4f, 0x46, 0x49, 0x4c, 0x45, 0x10, 0xcf, 0x01, 0x12, 0x1f, 0x0a, 0x1a, 0x4e,
+	0x4f, 0x44, 0x45, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x5f, 0x50, 0x52, 0x4f, 0x46, 0x49,
+	0x4c, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x10, 0xd0, 0x01, 0x12, 0x0f, 0x0a, 0x0a,
+	0x50, 0x4f, 0x44, 0x5f, 0x43, 0x52, 0x45, 0x41, 0x54, 0x45, 0x10, 0xac, 0x02, 0x12, 0x12, 0x0a,
+	0x0d, 0x50, 0x4f, 0x44, 0x5f, 0x54, 0x45, 0x52, 0x4d, 0x49, 0x4e, 0x41, 0x54, 0x45, 0x10, 0xad,
+	0x02, 0x12, 0x12, 0x0a, 0x0d, 0x50, 0x4f, 0x44, 0x5f, 0x48, 0x49, 0x42, 0x45, 0x52, 0x4e, 0x41,
+	0x54, 0x45, 0x10, 0xae, 0x02, 0x12, 0x0e, 0x0a, 0x09, 0x50, 0x4f, 0x44, 0x5f, 0x53, 0x54, 0x41,
+	0x54, 0x45, 0x10, 0xaf, 0x02, 0x12, 0x16, 0x0a, 0x11, 0x50, 0x4f, 0x44, 0x5f, 0x43, 0x4f, 0x4e,
+	0x46, 0x49, 0x47, 0x5f, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x10, 0xb0, 0x02, 0x12, 0x16, 0x0a,
+	0x11, 0x50, 0x4f, 0x44, 0x5f, 0x53, 0x45, 0x43, 0x52, 0x45, 0x54, 0x5f, 0x55, 0x50, 0x44, 0x41,
+	0x54, 0x45, 0x10, 0xb1, 0x02, 0x12, 0x11, 0x0a, 0x0c, 0x53, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e,
+	0x5f, 0x4f, 0x50, 0x45, 0x4e, 0x10, 0x90, 0x03, 0x12, 0x12, 0x0a, 0x0d, 0x53, 0x45, 0x53, 0x53,
+	0x49, 0x4f, 0x4e, 0x5f, 0x43, 0x4c, 0x4f, 0x53, 0x45, 0x10, 0x91, 0x03, 0x12, 0x12, 0x0a, 0x0d,
+	0x53, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x10, 0x92, 0x03,
+	0x12, 0x12, 0x0a, 0x0d, 0x53, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x44, 0x52, 0x41, 0x49,
+	0x4e, 0x10, 0x93, 0x03, 0x12, 0x14, 0x0a, 0x0f, 0x53, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f,
+	0x4d, 0x49, 0x47, 0x52, 0x41, 0x54, 0x45, 0x10, 0x94, 0x03, 0x12, 0x12, 0x0a, 0x0d, 0x53, 0x45,
+	0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x55, 0x53, 0x41, 0x47, 0x45, 0x10, 0x95, 0x03, 0x32, 0x86,
+	0x04, 0x0a, 0x11, 0x46, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x43, 0x6f, 0x72, 0x65, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x12, 0x7d, 0x0a, 0x0a, 0x67, 0x65, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x12, 0x34, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e,
+	0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x1a, 0x37, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61,
+	0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72,
+	0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x46, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x43, 0x6f, 0x72, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x30, 0x01, 0x12, 0x5d, 0x0a, 0x0a, 0x70, 0x75, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x37, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66,
+	0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65,
+	0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x46, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x43,
+	0x6f, 0x72, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x12, 0x86, 0x01, 0x0a, 0x0d, 0x77, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x73, 0x12, 0x3a, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73,
+	0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63,
+	0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x37, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72,
+	0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x57,
+	0x61, 0x74, 0x63, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x89, 0x01, 0x0a, 0x12,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61,
+	0x74, 0x65, 0x12, 0x38, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e,
+	0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x39, 0x2e, 0x63,
+	0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f,
+	0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x39, 0x5a, 0x37, 0x63, 0x65, 0x6e, 0x74, 0x61,
 	0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2f, 0x66, 0x6f, 0x72,
 	0x6e, 0x61, 0x78, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x6c, 0x65, 0x73, 0x73, 0x2f, 0x70,
 	0x6b, 0x67, 0x2f, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x67, 0x72,
@@ -1667,78 +3126,122 @@ func file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescGZIP() []byte {
 	return file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDescData
 }
 
-var file_pkg_fornaxcore_grpc_fornaxcore_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_pkg_fornaxcore_grpc_fornaxcore_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
 var file_pkg_fornaxcore_grpc_fornaxcore_proto_goTypes = []interface{}{
 	(MessageType)(0),                // 0: centaurusinfra.io.fornaxcore.service.MessageType
 	(PodState_State)(0),             // 1: centaurusinfra.io.fornaxcore.service.PodState.State
-	(*FornaxCoreMessage)(nil),       // 2: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage
-	(*FornaxCore)(nil),              // 3: centaurusinfra.io.fornaxcore.service.FornaxCore
-	(*FornaxCoreConfiguration)(nil), // 4: centaurusinfra.io.fornaxcore.service.FornaxCoreConfiguration
-	(*NodeIdentifier)(nil),          // 5: centaurusinfra.io.fornaxcore.service.NodeIdentifier
-	(*NodeRegistry)(nil),            // 6: centaurusinfra.io.fornaxcore.service.NodeRegistry
-	(*NodeConfiguration)(nil),       // 7: centaurusinfra.io.fornaxcore.service.NodeConfiguration
-	(*NodeReady)(nil),               // 8: centaurusinfra.io.fornaxcore.service.NodeReady
-	(*NodeState)(nil),               // 9: centaurusinfra.io.fornaxcore.service.NodeState
-	(*NodeFullSync)(nil),            // 10: centaurusinfra.io.fornaxcore.service.NodeFullSync
-	(*PodState)(nil),                // 11: centaurusinfra.io.fornaxcore.service.PodState
-	(*PodResource)(nil),             // 12: centaurusinfra.io.fornaxcore.service.PodResource
-	(*PodCreate)(nil),               // 13: centaurusinfra.io.fornaxcore.service.PodCreate
-	(*PodTerminate)(nil),            // 14: centaurusinfra.io.fornaxcore.service.PodTerminate
-	(*PodHibernate)(nil),            // 15: centaurusinfra.io.fornaxcore.service.PodHibernate
-	(*SessionState)(nil),            // 16: centaurusinfra.io.fornaxcore.service.SessionState
-	(*SessionOpen)(nil),             // 17: centaurusinfra.io.fornaxcore.service.SessionOpen
-	(*SessionClose)(nil),            // 18: centaurusinfra.io.fornaxcore.service.SessionClose
-	(*v1.Node)(nil),                 // 19: k8s.io.api.core.v1.Node
-	(*v1.Pod)(nil),                  // 20: k8s.io.api.core.v1.Pod
-	(*v1.ResourceQuotaStatus)(nil),  // 21: k8s.io.api.core.v1.ResourceQuotaStatus
-	(*v1.AttachedVolume)(nil),       // 22: k8s.io.api.core.v1.AttachedVolume
-	(*v1.ConfigMap)(nil),            // 23: k8s.io.api.core.v1.ConfigMap
-	(*empty.Empty)(nil),             // 24: google.protobuf.Empty
+	(SessionWatchEvent_Type)(0),     // 2: centaurusinfra.io.fornaxcore.service.SessionWatchEvent.Type
+	(*FornaxCoreMessage)(nil),       // 3: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage
+	(*FornaxCore)(nil),              // 4: centaurusinfra.io.fornaxcore.service.FornaxCore
+	(*FornaxCoreConfiguration)(nil), // 5: centaurusinfra.io.fornaxcore.service.FornaxCoreConfiguration
+	(*NodeIdentifier)(nil),          // 6: centaurusinfra.io.fornaxcore.service.NodeIdentifier
+	(*NodeRegistry)(nil),            // 7: centaurusinfra.io.fornaxcore.service.NodeRegistry
+	(*NodeConfiguration)(nil),       // 8: centaurusinfra.io.fornaxcore.service.NodeConfiguration
+	(*NodeReady)(nil),               // 9: centaurusinfra.io.fornaxcore.service.NodeReady
+	(*NodeState)(nil),               // 10: centaurusinfra.io.fornaxcore.service.NodeState
+	(*NodeFullSync)(nil),            // 11: centaurusinfra.io.fornaxcore.service.NodeFullSync
+	(*NodeLeaseRenew)(nil),          // 12: centaurusinfra.io.fornaxcore.service.NodeLeaseRenew
+	(*NodeLeaseConfiguration)(nil),  // 13: centaurusinfra.io.fornaxcore.service.NodeLeaseConfiguration
+	(*NodeConfigProfile)(nil),       // 14: centaurusinfra.io.fornaxcore.service.NodeConfigProfile
+	(*NodeConfigProfileStatus)(nil), // 15: centaurusinfra.io.fornaxcore.service.NodeConfigProfileStatus
+	(*PodState)(nil),                // 16: centaurusinfra.io.fornaxcore.service.PodState
+	(*PodResource)(nil),             // 17: centaurusinfra.io.fornaxcore.service.PodResource
+	(*PodCreate)(nil),               // 18: centaurusinfra.io.fornaxcore.service.PodCreate
+	(*PodTerminate)(nil),            // 19: centaurusinfra.io.fornaxcore.service.PodTerminate
+	(*PodHibernate)(nil),            // 20: centaurusinfra.io.fornaxcore.service.PodHibernate
+	(*PodConfigUpdate)(nil),         // 21: centaurusinfra.io.fornaxcore.service.PodConfigUpdate
+	(*SecretVersion)(nil),           // 22: centaurusinfra.io.fornaxcore.service.SecretVersion
+	(*PodSecretUpdate)(nil),         // 23: centaurusinfra.io.fornaxcore.service.PodSecretUpdate
+	(*SessionState)(nil),            // 24: centaurusinfra.io.fornaxcore.service.SessionState
+	(*SessionOpen)(nil),             // 25: centaurusinfra.io.fornaxcore.service.SessionOpen
+	(*SessionClose)(nil),            // 26: centaurusinfra.io.fornaxcore.service.SessionClose
+	(*SessionDrain)(nil),            // 27: centaurusinfra.io.fornaxcore.service.SessionDrain
+	(*SessionMigrate)(nil),          // 28: centaurusinfra.io.fornaxcore.service.SessionMigrate
+	(*SessionUsage)(nil),            // 29: centaurusinfra.io.fornaxcore.service.SessionUsage
+	(*WatchSessionsRequest)(nil),    // 30: centaurusinfra.io.fornaxcore.service.WatchSessionsRequest
+	(*CertificateRequest)(nil),      // 31: centaurusinfra.io.fornaxcore.service.CertificateRequest
+	(*CertificateResponse)(nil),     // 32: centaurusinfra.io.fornaxcore.service.CertificateResponse
+	(*SessionWatchEvent)(nil),       // 33: centaurusinfra.io.fornaxcore.service.SessionWatchEvent
+	nil,                             // 34: centaurusinfra.io.fornaxcore.service.SecretVersion.DataEntry
+	(*v1.Node)(nil),                 // 35: k8s.io.api.core.v1.Node
+	(*timestamp.Timestamp)(nil),     // 36: google.protobuf.Timestamp
+	(*v1.Pod)(nil),                  // 37: k8s.io.api.core.v1.Pod
+	(*v1.ResourceQuotaStatus)(nil),  // 38: k8s.io.api.core.v1.ResourceQuotaStatus
+	(*v1.AttachedVolume)(nil),       // 39: k8s.io.api.core.v1.AttachedVolume
+	(*v1.ConfigMap)(nil),            // 40: k8s.io.api.core.v1.ConfigMap
+	(*empty.Empty)(nil),             // 41: google.protobuf.Empty
 }
 var file_pkg_fornaxcore_grpc_fornaxcore_proto_depIdxs = []int32{
-	5,  // 0: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.nodeIdentifier:type_name -> centaurusinfra.io.fornaxcore.service.NodeIdentifier
+	6,  // 0: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.nodeIdentifier:type_name -> centaurusinfra.io.fornaxcore.service.NodeIdentifier
 	0,  // 1: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.messageType:type_name -> centaurusinfra.io.fornaxcore.service.MessageType
-	4,  // 2: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.fornaxCoreConfiguration:type_name -> centaurusinfra.io.fornaxcore.service.FornaxCoreConfiguration
-	7,  // 3: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.nodeConfiguration:type_name -> centaurusinfra.io.fornaxcore.service.NodeConfiguration
-	6,  // 4: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.nodeRegistry:type_name -> centaurusinfra.io.fornaxcore.service.NodeRegistry
-	8,  // 5: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.nodeReady:type_name -> centaurusinfra.io.fornaxcore.service.NodeReady
-	9,  // 6: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.nodeState:type_name -> centaurusinfra.io.fornaxcore.service.NodeState
-	10, // 7: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.nodeFullSync:type_name -> centaurusinfra.io.fornaxcore.service.NodeFullSync
-	13, // 8: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.podCreate:type_name -> centaurusinfra.io.fornaxcore.service.PodCreate
-	14, // 9: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.podTerminate:type_name -> centaurusinfra.io.fornaxcore.service.PodTerminate
-	15, // 10: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.podHibernate:type_name -> centaurusinfra.io.fornaxcore.service.PodHibernate
-	11, // 11: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.podState:type_name -> centaurusinfra.io.fornaxcore.service.PodState
-	17, // 12: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.sessionOpen:type_name -> centaurusinfra.io.fornaxcore.service.SessionOpen
-	18, // 13: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.sessionClose:type_name -> centaurusinfra.io.fornaxcore.service.SessionClose
-	16, // 14: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.sessionState:type_name -> centaurusinfra.io.fornaxcore.service.SessionState
-	3,  // 15: centaurusinfra.io.fornaxcore.service.FornaxCoreConfiguration.primary:type_name -> centaurusinfra.io.fornaxcore.service.FornaxCore
-	3,  // 16: centaurusinfra.io.fornaxcore.service.FornaxCoreConfiguration.standbys:type_name -> centaurusinfra.io.fornaxcore.service.FornaxCore
-	19, // 17: centaurusinfra.io.fornaxcore.service.NodeRegistry.node:type_name -> k8s.io.api.core.v1.Node
-	19, // 18: centaurusinfra.io.fornaxcore.service.NodeConfiguration.node:type_name -> k8s.io.api.core.v1.Node
-	20, // 19: centaurusinfra.io.fornaxcore.service.NodeConfiguration.daemonPods:type_name -> k8s.io.api.core.v1.Pod
-	19, // 20: centaurusinfra.io.fornaxcore.service.NodeReady.node:type_name -> k8s.io.api.core.v1.Node
-	11, // 21: centaurusinfra.io.fornaxcore.service.NodeReady.podStates:type_name -> centaurusinfra.io.fornaxcore.service.PodState
-	16, // 22: centaurusinfra.io.fornaxcore.service.NodeReady.sessionStates:type_name -> centaurusinfra.io.fornaxcore.service.SessionState
-	19, // 23: centaurusinfra.io.fornaxcore.service.NodeState.node:type_name -> k8s.io.api.core.v1.Node
-	11, // 24: centaurusinfra.io.fornaxcore.service.NodeState.podStates:type_name -> centaurusinfra.io.fornaxcore.service.PodState
-	1,  // 25: centaurusinfra.io.fornaxcore.service.PodState.state:type_name -> centaurusinfra.io.fornaxcore.service.PodState.State
-	20, // 26: centaurusinfra.io.fornaxcore.service.PodState.pod:type_name -> k8s.io.api.core.v1.Pod
-	12, // 27: centaurusinfra.io.fornaxcore.service.PodState.resource:type_name -> centaurusinfra.io.fornaxcore.service.PodResource
-	16, // 28: centaurusinfra.io.fornaxcore.service.PodState.sessionStates:type_name -> centaurusinfra.io.fornaxcore.service.SessionState
-	21, // 29: centaurusinfra.io.fornaxcore.service.PodResource.resourceQuotaStatus:type_name -> k8s.io.api.core.v1.ResourceQuotaStatus
-	22, // 30: centaurusinfra.io.fornaxcore.service.PodResource.volumes:type_name -> k8s.io.api.core.v1.AttachedVolume
-	20, // 31: centaurusinfra.io.fornaxcore.service.PodCreate.pod:type_name -> k8s.io.api.core.v1.Pod
-	23, // 32: centaurusinfra.io.fornaxcore.service.PodCreate.configMap:type_name -> k8s.io.api.core.v1.ConfigMap
-	5,  // 33: centaurusinfra.io.fornaxcore.service.FornaxCoreService.getMessage:input_type -> centaurusinfra.io.fornaxcore.service.NodeIdentifier
-	2,  // 34: centaurusinfra.io.fornaxcore.service.FornaxCoreService.putMessage:input_type -> centaurusinfra.io.fornaxcore.service.FornaxCoreMessage
-	2,  // 35: centaurusinfra.io.fornaxcore.service.FornaxCoreService.getMessage:output_type -> centaurusinfra.io.fornaxcore.service.FornaxCoreMessage
-	24, // 36: centaurusinfra.io.fornaxcore.service.FornaxCoreService.putMessage:output_type -> google.protobuf.Empty
-	35, // [35:37] is the sub-list for method output_type
-	33, // [33:35] is the sub-list for method input_type
-	33, // [33:33] is the sub-list for extension type_name
-	33, // [33:33] is the sub-list for extension extendee
-	0,  // [0:33] is the sub-list for field type_name
+	5,  // 2: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.fornaxCoreConfiguration:type_name -> centaurusinfra.io.fornaxcore.service.FornaxCoreConfiguration
+	8,  // 3: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.nodeConfiguration:type_name -> centaurusinfra.io.fornaxcore.service.NodeConfiguration
+	7,  // 4: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.nodeRegistry:type_name -> centaurusinfra.io.fornaxcore.service.NodeRegistry
+	9,  // 5: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.nodeReady:type_name -> centaurusinfra.io.fornaxcore.service.NodeReady
+	10, // 6: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.nodeState:type_name -> centaurusinfra.io.fornaxcore.service.NodeState
+	11, // 7: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.nodeFullSync:type_name -> centaurusinfra.io.fornaxcore.service.NodeFullSync
+	12, // 8: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.nodeLeaseRenew:type_name -> centaurusinfra.io.fornaxcore.service.NodeLeaseRenew
+	13, // 9: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.nodeLeaseConfiguration:type_name -> centaurusinfra.io.fornaxcore.service.NodeLeaseConfiguration
+	14, // 10: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.nodeConfigProfile:type_name -> centaurusinfra.io.fornaxcore.service.NodeConfigProfile
+	15, // 11: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.nodeConfigProfileStatus:type_name -> centaurusinfra.io.fornaxcore.service.NodeConfigProfileStatus
+	18, // 12: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.podCreate:type_name -> centaurusinfra.io.fornaxcore.service.PodCreate
+	19, // 13: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.podTerminate:type_name -> centaurusinfra.io.fornaxcore.service.PodTerminate
+	20, // 14: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.podHibernate:type_name -> centaurusinfra.io.fornaxcore.service.PodHibernate
+	16, // 15: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.podState:type_name -> centaurusinfra.io.fornaxcore.service.PodState
+	21, // 16: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.podConfigUpdate:type_name -> centaurusinfra.io.fornaxcore.service.PodConfigUpdate
+	23, // 17: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.podSecretUpdate:type_name -> centaurusinfra.io.fornaxcore.service.PodSecretUpdate
+	25, // 18: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.sessionOpen:type_name -> centaurusinfra.io.fornaxcore.service.SessionOpen
+	26, // 19: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.sessionClose:type_name -> centaurusinfra.io.fornaxcore.service.SessionClose
+	24, // 20: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.sessionState:type_name -> centaurusinfra.io.fornaxcore.service.SessionState
+	27, // 21: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.sessionDrain:type_name -> centaurusinfra.io.fornaxcore.service.SessionDrain
+	28, // 22: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.sessionMigrate:type_name -> centaurusinfra.io.fornaxcore.service.SessionMigrate
+	29, // 23: centaurusinfra.io.fornaxcore.service.FornaxCoreMessage.sessionUsage:type_name -> centaurusinfra.io.fornaxcore.service.SessionUsage
+	4,  // 24: centaurusinfra.io.fornaxcore.service.FornaxCoreConfiguration.primary:type_name -> centaurusinfra.io.fornaxcore.service.FornaxCore
+	4,  // 25: centaurusinfra.io.fornaxcore.service.FornaxCoreConfiguration.standbys:type_name -> centaurusinfra.io.fornaxcore.service.FornaxCore
+	35, // 26: centaurusinfra.io.fornaxcore.service.NodeRegistry.node:type_name -> k8s.io.api.core.v1.Node
+	36, // 27: centaurusinfra.io.fornaxcore.service.NodeRegistry.nodeTime:type_name -> google.protobuf.Timestamp
+	35, // 28: centaurusinfra.io.fornaxcore.service.NodeConfiguration.node:type_name -> k8s.io.api.core.v1.Node
+	37, // 29: centaurusinfra.io.fornaxcore.service.NodeConfiguration.daemonPods:type_name -> k8s.io.api.core.v1.Pod
+	13, // 30: centaurusinfra.io.fornaxcore.service.NodeConfiguration.lease:type_name -> centaurusinfra.io.fornaxcore.service.NodeLeaseConfiguration
+	35, // 31: centaurusinfra.io.fornaxcore.service.NodeReady.node:type_name -> k8s.io.api.core.v1.Node
+	16, // 32: centaurusinfra.io.fornaxcore.service.NodeReady.podStates:type_name -> centaurusinfra.io.fornaxcore.service.PodState
+	24, // 33: centaurusinfra.io.fornaxcore.service.NodeReady.sessionStates:type_name -> centaurusinfra.io.fornaxcore.service.SessionState
+	35, // 34: centaurusinfra.io.fornaxcore.service.NodeState.node:type_name -> k8s.io.api.core.v1.Node
+	16, // 35: centaurusinfra.io.fornaxcore.service.NodeState.podStates:type_name -> centaurusinfra.io.fornaxcore.service.PodState
+	36, // 36: centaurusinfra.io.fornaxcore.service.NodeLeaseRenew.nodeTime:type_name -> google.protobuf.Timestamp
+	1,  // 37: centaurusinfra.io.fornaxcore.service.PodState.state:type_name -> centaurusinfra.io.fornaxcore.service.PodState.State
+	37, // 38: centaurusinfra.io.fornaxcore.service.PodState.pod:type_name -> k8s.io.api.core.v1.Pod
+	17, // 39: centaurusinfra.io.fornaxcore.service.PodState.resource:type_name -> centaurusinfra.io.fornaxcore.service.PodResource
+	24, // 40: centaurusinfra.io.fornaxcore.service.PodState.sessionStates:type_name -> centaurusinfra.io.fornaxcore.service.SessionState
+	38, // 41: centaurusinfra.io.fornaxcore.service.PodResource.resourceQuotaStatus:type_name -> k8s.io.api.core.v1.ResourceQuotaStatus
+	39, // 42: centaurusinfra.io.fornaxcore.service.PodResource.volumes:type_name -> k8s.io.api.core.v1.AttachedVolume
+	37, // 43: centaurusinfra.io.fornaxcore.service.PodCreate.pod:type_name -> k8s.io.api.core.v1.Pod
+	40, // 44: centaurusinfra.io.fornaxcore.service.PodCreate.configMap:type_name -> k8s.io.api.core.v1.ConfigMap
+	40, // 45: centaurusinfra.io.fornaxcore.service.PodConfigUpdate.configMap:type_name -> k8s.io.api.core.v1.ConfigMap
+	34, // 46: centaurusinfra.io.fornaxcore.service.SecretVersion.data:type_name -> centaurusinfra.io.fornaxcore.service.SecretVersion.DataEntry
+	22, // 47: centaurusinfra.io.fornaxcore.service.PodSecretUpdate.secret:type_name -> centaurusinfra.io.fornaxcore.service.SecretVersion
+	22, // 48: centaurusinfra.io.fornaxcore.service.PodSecretUpdate.previousSecret:type_name -> centaurusinfra.io.fornaxcore.service.SecretVersion
+	36, // 49: centaurusinfra.io.fornaxcore.service.SessionDrain.deadline:type_name -> google.protobuf.Timestamp
+	36, // 50: centaurusinfra.io.fornaxcore.service.SessionUsage.startTime:type_name -> google.protobuf.Timestamp
+	36, // 51: centaurusinfra.io.fornaxcore.service.SessionUsage.endTime:type_name -> google.protobuf.Timestamp
+	6,  // 52: centaurusinfra.io.fornaxcore.service.WatchSessionsRequest.nodeIdentifier:type_name -> centaurusinfra.io.fornaxcore.service.NodeIdentifier
+	6,  // 53: centaurusinfra.io.fornaxcore.service.CertificateRequest.nodeIdentifier:type_name -> centaurusinfra.io.fornaxcore.service.NodeIdentifier
+	36, // 54: centaurusinfra.io.fornaxcore.service.CertificateResponse.notAfter:type_name -> google.protobuf.Timestamp
+	2,  // 55: centaurusinfra.io.fornaxcore.service.SessionWatchEvent.type:type_name -> centaurusinfra.io.fornaxcore.service.SessionWatchEvent.Type
+	6,  // 56: centaurusinfra.io.fornaxcore.service.FornaxCoreService.getMessage:input_type -> centaurusinfra.io.fornaxcore.service.NodeIdentifier
+	3,  // 57: centaurusinfra.io.fornaxcore.service.FornaxCoreService.putMessage:input_type -> centaurusinfra.io.fornaxcore.service.FornaxCoreMessage
+	30, // 58: centaurusinfra.io.fornaxcore.service.FornaxCoreService.watchSessions:input_type -> centaurusinfra.io.fornaxcore.service.WatchSessionsRequest
+	31, // 59: centaurusinfra.io.fornaxcore.service.FornaxCoreService.requestCertificate:input_type -> centaurusinfra.io.fornaxcore.service.CertificateRequest
+	3,  // 60: centaurusinfra.io.fornaxcore.service.FornaxCoreService.getMessage:output_type -> centaurusinfra.io.fornaxcore.service.FornaxCoreMessage
+	41, // 61: centaurusinfra.io.fornaxcore.service.FornaxCoreService.putMessage:output_type -> google.protobuf.Empty
+	33, // 62: centaurusinfra.io.fornaxcore.service.FornaxCoreService.watchSessions:output_type -> centaurusinfra.io.fornaxcore.service.SessionWatchEvent
+	32, // 63: centaurusinfra.io.fornaxcore.service.FornaxCoreService.requestCertificate:output_type -> centaurusinfra.io.fornaxcore.service.CertificateResponse
+	60, // [60:64] is the sub-list for method output_type
+	56, // [56:60] is the sub-list for method input_type
+	56, // [56:56] is the sub-list for extension type_name
+	56, // [56:56] is the sub-list for extension extendee
+	0,  // [0:56] is the sub-list for field type_name
 }
 
 func init() { file_pkg_fornaxcore_grpc_fornaxcore_proto_init() }
@@ -1856,7 +3359,7 @@ func file_pkg_fornaxcore_grpc_fornaxcore_proto_init() {
 			}
 		}
 		file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PodState); i {
+			switch v := v.(*NodeLeaseRenew); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1868,7 +3371,7 @@ func file_pkg_fornaxcore_grpc_fornaxcore_proto_init() {
 			}
 		}
 		file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PodResource); i {
+			switch v := v.(*NodeLeaseConfiguration); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1880,7 +3383,7 @@ func file_pkg_fornaxcore_grpc_fornaxcore_proto_init() {
 			}
 		}
 		file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PodCreate); i {
+			switch v := v.(*NodeConfigProfile); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1892,7 +3395,7 @@ func file_pkg_fornaxcore_grpc_fornaxcore_proto_init() {
 			}
 		}
 		file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PodTerminate); i {
+			switch v := v.(*NodeConfigProfileStatus); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1904,7 +3407,7 @@ func file_pkg_fornaxcore_grpc_fornaxcore_proto_init() {
 			}
 		}
 		file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PodHibernate); i {
+			switch v := v.(*PodState); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1916,7 +3419,7 @@ func file_pkg_fornaxcore_grpc_fornaxcore_proto_init() {
 			}
 		}
 		file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SessionState); i {
+			switch v := v.(*PodResource); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1928,7 +3431,7 @@ func file_pkg_fornaxcore_grpc_fornaxcore_proto_init() {
 			}
 		}
 		file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SessionOpen); i {
+			switch v := v.(*PodCreate); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1940,6 +3443,90 @@ func file_pkg_fornaxcore_grpc_fornaxcore_proto_init() {
 			}
 		}
 		file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PodTerminate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PodHibernate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PodConfigUpdate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SecretVersion); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PodSecretUpdate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SessionState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SessionOpen); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SessionClose); i {
 			case 0:
 				return &v.state
@@ -1951,6 +3538,90 @@ func file_pkg_fornaxcore_grpc_fornaxcore_proto_init() {
 				return nil
 			}
 		}
+		file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SessionDrain); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SessionMigrate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SessionUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchSessionsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CertificateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CertificateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SessionWatchEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_pkg_fornaxcore_grpc_fornaxcore_proto_msgTypes[0].OneofWrappers = []interface{}{
 		(*FornaxCoreMessage_FornaxCoreConfiguration)(nil),
@@ -1959,21 +3630,30 @@ func file_pkg_fornaxcore_grpc_fornaxcore_proto_init() {
 		(*FornaxCoreMessage_NodeReady)(nil),
 		(*FornaxCoreMessage_NodeState)(nil),
 		(*FornaxCoreMessage_NodeFullSync)(nil),
+		(*FornaxCoreMessage_NodeLeaseRenew)(nil),
+		(*FornaxCoreMessage_NodeLeaseConfiguration)(nil),
+		(*FornaxCoreMessage_NodeConfigProfile)(nil),
+		(*FornaxCoreMessage_NodeConfigProfileStatus)(nil),
 		(*FornaxCoreMessage_PodCreate)(nil),
 		(*FornaxCoreMessage_PodTerminate)(nil),
 		(*FornaxCoreMessage_PodHibernate)(nil),
 		(*FornaxCoreMessage_PodState)(nil),
+		(*FornaxCoreMessage_PodConfigUpdate)(nil),
+		(*FornaxCoreMessage_PodSecretUpdate)(nil),
 		(*FornaxCoreMessage_SessionOpen)(nil),
 		(*FornaxCoreMessage_SessionClose)(nil),
 		(*FornaxCoreMessage_SessionState)(nil),
+		(*FornaxCoreMessage_SessionDrain)(nil),
+		(*FornaxCoreMessage_SessionMigrate)(nil),
+		(*FornaxCoreMessage_SessionUsage)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pkg_fornaxcore_grpc_fornaxcore_proto_rawDesc,
-			NumEnums:      2,
-			NumMessages:   17,
+			NumEnums:      3,
+			NumMessages:   32,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -25,6 +25,8 @@ const _ = grpc.SupportPackageIsVersion7
 type FornaxCoreServiceClient interface {
 	GetMessage(ctx context.Context, in *NodeIdentifier, opts ...grpc.CallOption) (FornaxCoreService_GetMessageClient, error)
 	PutMessage(ctx context.Context, in *FornaxCoreMessage, opts ...grpc.CallOption) (*empty.Empty, error)
+	WatchSessions(ctx context.Context, in *WatchSessionsRequest, opts ...grpc.CallOption) (FornaxCoreService_WatchSessionsClient, error)
+	RequestCertificate(ctx context.Context, in *CertificateRequest, opts ...grpc.CallOption) (*CertificateResponse, error)
 }
 
 type fornaxCoreServiceClient struct {
@@ -76,12 +78,55 @@ func (c *fornaxCoreServiceClient) PutMessage(ctx context.Context, in *FornaxCore
 	return out, nil
 }
 
+func (c *fornaxCoreServiceClient) WatchSessions(ctx context.Context, in *WatchSessionsRequest, opts ...grpc.CallOption) (FornaxCoreService_WatchSessionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FornaxCoreService_ServiceDesc.Streams[1], "/centaurusinfra.io.fornaxcore.service.FornaxCoreService/watchSessions", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fornaxCoreServiceWatchSessionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FornaxCoreService_WatchSessionsClient interface {
+	Recv() (*SessionWatchEvent, error)
+	grpc.ClientStream
+}
+
+type fornaxCoreServiceWatchSessionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *fornaxCoreServiceWatchSessionsClient) Recv() (*SessionWatchEvent, error) {
+	m := new(SessionWatchEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fornaxCoreServiceClient) RequestCertificate(ctx context.Context, in *CertificateRequest, opts ...grpc.CallOption) (*CertificateResponse, error) {
+	out := new(CertificateResponse)
+	err := c.cc.Invoke(ctx, "/centaurusinfra.io.fornaxcore.service.FornaxCoreService/requestCertificate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // FornaxCoreServiceServer is the server API for FornaxCoreService service.
 // All implementations must embed UnimplementedFornaxCoreServiceServer
 // for forward compatibility
 type FornaxCoreServiceServer interface {
 	GetMessage(*NodeIdentifier, FornaxCoreService_GetMessageServer) error
 	PutMessage(context.Context, *FornaxCoreMessage) (*empty.Empty, error)
+	WatchSessions(*WatchSessionsRequest, FornaxCoreService_WatchSessionsServer) error
+	RequestCertificate(context.Context, *CertificateRequest) (*CertificateResponse, error)
 	mustEmbedUnimplementedFornaxCoreServiceServer()
 }
 
@@ -95,6 +140,12 @@ func (UnimplementedFornaxCoreServiceServer) GetMessage(*NodeIdentifier, FornaxCo
 func (UnimplementedFornaxCoreServiceServer) PutMessage(context.Context, *FornaxCoreMessage) (*empty.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PutMessage not implemented")
 }
+func (UnimplementedFornaxCoreServiceServer) WatchSessions(*WatchSessionsRequest, FornaxCoreService_WatchSessionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchSessions not implemented")
+}
+func (UnimplementedFornaxCoreServiceServer) RequestCertificate(context.Context, *CertificateRequest) (*CertificateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestCertificate not implemented")
+}
 func (UnimplementedFornaxCoreServiceServer) mustEmbedUnimplementedFornaxCoreServiceServer() {}
 
 // UnsafeFornaxCoreServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -147,6 +198,45 @@ func _FornaxCoreService_PutMessage_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FornaxCoreService_WatchSessions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchSessionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FornaxCoreServiceServer).WatchSessions(m, &fornaxCoreServiceWatchSessionsServer{stream})
+}
+
+type FornaxCoreService_WatchSessionsServer interface {
+	Send(*SessionWatchEvent) error
+	grpc.ServerStream
+}
+
+type fornaxCoreServiceWatchSessionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *fornaxCoreServiceWatchSessionsServer) Send(m *SessionWatchEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FornaxCoreService_RequestCertificate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CertificateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FornaxCoreServiceServer).RequestCertificate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/centaurusinfra.io.fornaxcore.service.FornaxCoreService/requestCertificate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FornaxCoreServiceServer).RequestCertificate(ctx, req.(*CertificateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // FornaxCoreService_ServiceDesc is the grpc.ServiceDesc for FornaxCoreService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -158,6 +248,10 @@ var FornaxCoreService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "putMessage",
 			Handler:    _FornaxCoreService_PutMessage_Handler,
 		},
+		{
+			MethodName: "requestCertificate",
+			Handler:    _FornaxCoreService_RequestCertificate_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -165,6 +259,11 @@ var FornaxCoreService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _FornaxCoreService_GetMessage_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "watchSessions",
+			Handler:       _FornaxCoreService_WatchSessions_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "pkg/fornaxcore/grpc/fornaxcore.proto",
 }
@@ -18,6 +18,7 @@ package nodeagent
 
 import (
 	"errors"
+	"time"
 
 	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
 	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc"
@@ -43,6 +44,10 @@ type NodeAgentClient interface {
 	CreatePod(nodeId string, pod *v1.Pod) error
 	TerminatePod(nodeId string, pod *v1.Pod) error
 	HibernatePod(nodeId string, pod *v1.Pod) error
+	UpdatePodConfig(nodeId string, pod *v1.Pod, configMap *v1.ConfigMap) error
+	UpdatePodSecret(nodeId string, pod *v1.Pod, secret, previousSecret *fornaxv1.ApplicationSecret) error
 	OpenSession(nodeId string, pod *v1.Pod, session *fornaxv1.ApplicationSession) error
 	CloseSession(nodeId string, pod *v1.Pod, session *fornaxv1.ApplicationSession) error
+	DrainSession(nodeId string, pod *v1.Pod, session *fornaxv1.ApplicationSession, reason string, deadline time.Time) error
+	MigrateSession(nodeId string, pod *v1.Pod, session *fornaxv1.ApplicationSession, targetPod string) error
 }
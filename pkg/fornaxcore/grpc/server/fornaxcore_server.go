@@ -23,6 +23,7 @@ import (
 	"math/rand"
 	"net"
 	"sync"
+	"time"
 
 	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
 	fornaxcore_grpc "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc"
@@ -31,10 +32,13 @@ import (
 	"google.golang.org/grpc/credentials"
 
 	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc/nodeagent"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/replay"
+	fornaxstore "centaurusinfra.io/fornax-serverless/pkg/store"
 	"centaurusinfra.io/fornax-serverless/pkg/util"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 )
@@ -57,6 +61,10 @@ type grpcServer struct {
 	nodeIncommingChans      map[string]chan *fornaxcore_grpc.FornaxCoreMessage
 	nodeIncommingChansMutex sync.Mutex
 	nodeMessageHandlerChans []chan *fornaxcore_grpc.FornaxCoreMessage
+	sessionStore            fornaxstore.ApiStorageInterface
+	podManager              ie.PodManagerInterface
+	sessionWatchers         map[string]int
+	nodePKI                 *NodePKI
 }
 
 func (g *grpcServer) RunGrpcServer(ctx context.Context, nodeMonitor ie.NodeMonitorInterface, port int, certFile, keyFile string) error {
@@ -66,7 +74,9 @@ func (g *grpcServer) RunGrpcServer(ctx context.Context, nodeMonitor ie.NodeMonit
 		return err
 	}
 	var opts []grpc.ServerOption
-	if certFile != "" && keyFile != "" {
+	if g.nodePKI != nil {
+		opts = []grpc.ServerOption{grpc.Creds(credentials.NewTLS(g.nodePKI.TLSConfig()))}
+	} else if certFile != "" && keyFile != "" {
 		creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
 		if err != nil {
 			klog.ErrorS(err, "Fornaxcore grpc server failed to generate credentials", "certFile", certFile, "keyFile", keyFile)
@@ -74,11 +84,13 @@ func (g *grpcServer) RunGrpcServer(ctx context.Context, nodeMonitor ie.NodeMonit
 		}
 		opts = []grpc.ServerOption{grpc.Creds(creds)}
 	}
+	opts = append(opts, ServerKeepaliveOptions()...)
 
 	// start node agent grpc server
 	g.nodeMonitor = nodeMonitor
 	grpcServer := grpc.NewServer(opts...)
 	fornaxcore_grpc.RegisterFornaxCoreServiceServer(grpcServer, g)
+	registerHealthAndReflection(ctx, grpcServer)
 	go func() {
 		err = grpcServer.Serve(lis)
 		if err != nil {
@@ -121,6 +133,9 @@ func (g *grpcServer) delistNode(node string) {
 }
 
 func (g *grpcServer) GetMessage(identifier *fornaxcore_grpc.NodeIdentifier, server fornaxcore_grpc.FornaxCoreService_GetMessageServer) error {
+	if err := g.authorizeNode(server.Context(), identifier); err != nil {
+		return err
+	}
 	var messageSeq int64 = 0
 	ch := make(chan *fornaxcore_grpc.FornaxCoreMessage, NodeOutgoingChanBufferSize)
 	if err := g.enlistNode(identifier.GetIdentifier(), ch); err != nil {
@@ -165,12 +180,16 @@ func (g *grpcServer) getNodeMessageHandlerChannel(nodeId string) chan *fornaxcor
 
 // PutMessage send node's message to handler to process message and return
 func (g *grpcServer) PutMessage(ctx context.Context, message *fornaxcore_grpc.FornaxCoreMessage) (*empty.Empty, error) {
+	if err := g.authorizeNode(ctx, message.GetNodeIdentifier()); err != nil {
+		return nil, err
+	}
 	messageCh := g.getNodeMessageHandlerChannel(message.GetNodeIdentifier().GetIdentifier())
 	messageCh <- message
 	return &emptypb.Empty{}, nil
 }
 
 func (g *grpcServer) handleMessages(message *fornaxcore_grpc.FornaxCoreMessage) {
+	replay.RecordNodeMessage(message)
 	var err error
 	var msg *fornaxcore_grpc.FornaxCoreMessage
 	switch message.GetMessageType() {
@@ -184,6 +203,12 @@ func (g *grpcServer) handleMessages(message *fornaxcore_grpc.FornaxCoreMessage)
 		msg, err = g.nodeMonitor.OnPodStateUpdate(message)
 	case fornaxcore_grpc.MessageType_SESSION_STATE:
 		msg, err = g.nodeMonitor.OnSessionUpdate(message)
+	case fornaxcore_grpc.MessageType_NODE_LEASE_RENEW:
+		msg, err = g.nodeMonitor.OnNodeLeaseRenew(message)
+	case fornaxcore_grpc.MessageType_NODE_CONFIG_PROFILE_STATUS:
+		msg, err = g.nodeMonitor.OnNodeConfigProfileStatus(message)
+	case fornaxcore_grpc.MessageType_SESSION_USAGE:
+		msg, err = g.nodeMonitor.OnSessionUsage(message)
 	default:
 		klog.Errorf(fmt.Sprintf("not supported message type %s, message %v", message.GetMessageType(), message))
 	}
@@ -211,6 +236,7 @@ func NewGrpcServer() *grpcServer {
 		RWMutex:                              sync.RWMutex{},
 		nodeOutgoingChans:                    make(map[string]chan<- *fornaxcore_grpc.FornaxCoreMessage),
 		nodeIncommingChans:                   make(map[string]chan *fornaxcore_grpc.FornaxCoreMessage),
+		sessionWatchers:                      make(map[string]int),
 		nodeMonitor:                          nil,
 		UnimplementedFornaxCoreServiceServer: fornaxcore_grpc.UnimplementedFornaxCoreServiceServer{},
 		nodeMessageHandlerChans:              handlerChans,
@@ -285,9 +311,63 @@ func (g *grpcServer) HibernatePod(nodeIdentifier string, pod *v1.Pod) error {
 	return nil
 }
 
-// CloseSession dispatch a SessionClose event to node agent
+// UpdatePodConfig dispatch a PodConfigUpdate grpc message to node agent
+func (g *grpcServer) UpdatePodConfig(nodeIdentifier string, pod *v1.Pod, configMap *v1.ConfigMap) error {
+	podIdentifier := util.Name(pod)
+	messageType := fornaxcore_grpc.MessageType_POD_CONFIG_UPDATE
+	podConfigUpdate := fornaxcore_grpc.FornaxCoreMessage_PodConfigUpdate{
+		PodConfigUpdate: &fornaxcore_grpc.PodConfigUpdate{
+			PodIdentifier: podIdentifier,
+			ConfigMap:     configMap.DeepCopy(),
+		},
+	}
+	m := &fornaxcore_grpc.FornaxCoreMessage{
+		MessageType: messageType,
+		MessageBody: &podConfigUpdate,
+	}
+
+	err := g.DispatchNodeMessage(nodeIdentifier, m)
+	if err != nil {
+		klog.ErrorS(err, "Failed to dispatch pod config update message to node", "node", nodeIdentifier, "pod", util.Name(pod))
+		return err
+	}
+	return nil
+}
+
+// UpdatePodSecret dispatch a PodSecretUpdate grpc message to node agent, previousSecret is nil when previous version is revoked
+func (g *grpcServer) UpdatePodSecret(nodeIdentifier string, pod *v1.Pod, secret, previousSecret *fornaxv1.ApplicationSecret) error {
+	podIdentifier := util.Name(pod)
+	messageType := fornaxcore_grpc.MessageType_POD_SECRET_UPDATE
+	podSecretUpdate := fornaxcore_grpc.FornaxCoreMessage_PodSecretUpdate{
+		PodSecretUpdate: &fornaxcore_grpc.PodSecretUpdate{
+			PodIdentifier: podIdentifier,
+			Secret:        &fornaxcore_grpc.SecretVersion{Version: secret.Version, Data: secret.Data},
+		},
+	}
+	if previousSecret != nil {
+		podSecretUpdate.PodSecretUpdate.PreviousSecret = &fornaxcore_grpc.SecretVersion{Version: previousSecret.Version, Data: previousSecret.Data}
+	}
+	m := &fornaxcore_grpc.FornaxCoreMessage{
+		MessageType: messageType,
+		MessageBody: &podSecretUpdate,
+	}
+
+	err := g.DispatchNodeMessage(nodeIdentifier, m)
+	if err != nil {
+		klog.ErrorS(err, "Failed to dispatch pod secret update message to node", "node", nodeIdentifier, "pod", util.Name(pod))
+		return err
+	}
+	return nil
+}
+
+// CloseSession dispatch a SessionClose event to node agent,
+// a node watching sessions close session when session is closing or deleted in store, no message is dispatched to it
 func (g *grpcServer) CloseSession(nodeIdentifier string, pod *v1.Pod, session *fornaxv1.ApplicationSession) error {
 	sessionIdentifier := util.Name(session)
+	if g.watchingSessions(nodeIdentifier) {
+		klog.V(5).InfoS("Node watch sessions, session is closed by session watch", "node", nodeIdentifier, "session", sessionIdentifier)
+		return nil
+	}
 	podIdentifier := util.Name(pod)
 	messageType := fornaxcore_grpc.MessageType_SESSION_CLOSE
 	body := fornaxcore_grpc.FornaxCoreMessage_SessionClose{
@@ -296,18 +376,75 @@ func (g *grpcServer) CloseSession(nodeIdentifier string, pod *v1.Pod, session *f
 			PodIdentifier:     podIdentifier,
 		},
 	}
+	if session.Spec.CloseGracePeriodSeconds != nil {
+		body.SessionClose.GracePeriodSeconds = uint32(*session.Spec.CloseGracePeriodSeconds)
+	}
 	m := &fornaxcore_grpc.FornaxCoreMessage{
-		MessageType: messageType,
-		MessageBody: &body,
+		MessageType:       messageType,
+		MessageBody:       &body,
+		RequestIdentifier: util.RequestId(session),
+	}
+
+	err := g.DispatchNodeMessage(nodeIdentifier, m)
+	if err != nil {
+		klog.ErrorS(err, "Failed to dispatch message to node", "node", nodeIdentifier, "session", sessionIdentifier, "requestId", util.RequestId(session))
+		return err
+	}
+	return nil
+
+}
+
+// DrainSession dispatch a SessionDrain event to node agent, session is still open until it's closed
+func (g *grpcServer) DrainSession(nodeIdentifier string, pod *v1.Pod, session *fornaxv1.ApplicationSession, reason string, deadline time.Time) error {
+	sessionIdentifier := util.Name(session)
+	podIdentifier := util.Name(pod)
+	messageType := fornaxcore_grpc.MessageType_SESSION_DRAIN
+	body := fornaxcore_grpc.FornaxCoreMessage_SessionDrain{
+		SessionDrain: &fornaxcore_grpc.SessionDrain{
+			SessionIdentifier: sessionIdentifier,
+			PodIdentifier:     podIdentifier,
+			Reason:            reason,
+			Deadline:          timestamppb.New(deadline),
+		},
+	}
+	m := &fornaxcore_grpc.FornaxCoreMessage{
+		MessageType:       messageType,
+		MessageBody:       &body,
+		RequestIdentifier: util.RequestId(session),
 	}
 
 	err := g.DispatchNodeMessage(nodeIdentifier, m)
 	if err != nil {
-		klog.ErrorS(err, "Failed to dispatch message to node", "node", nodeIdentifier, "session", sessionIdentifier)
+		klog.ErrorS(err, "Failed to dispatch session drain message to node", "node", nodeIdentifier, "session", sessionIdentifier, "requestId", util.RequestId(session))
 		return err
 	}
 	return nil
+}
 
+// MigrateSession dispatch a SessionMigrate event to node agent, session is closed on pod and reported back with exported metadata
+func (g *grpcServer) MigrateSession(nodeIdentifier string, pod *v1.Pod, session *fornaxv1.ApplicationSession, targetPod string) error {
+	sessionIdentifier := util.Name(session)
+	podIdentifier := util.Name(pod)
+	messageType := fornaxcore_grpc.MessageType_SESSION_MIGRATE
+	body := fornaxcore_grpc.FornaxCoreMessage_SessionMigrate{
+		SessionMigrate: &fornaxcore_grpc.SessionMigrate{
+			SessionIdentifier:   sessionIdentifier,
+			PodIdentifier:       podIdentifier,
+			TargetPodIdentifier: targetPod,
+		},
+	}
+	m := &fornaxcore_grpc.FornaxCoreMessage{
+		MessageType:       messageType,
+		MessageBody:       &body,
+		RequestIdentifier: util.RequestId(session),
+	}
+
+	err := g.DispatchNodeMessage(nodeIdentifier, m)
+	if err != nil {
+		klog.ErrorS(err, "Failed to dispatch session migrate message to node", "node", nodeIdentifier, "session", sessionIdentifier, "requestId", util.RequestId(session))
+		return err
+	}
+	return nil
 }
 
 // OpenSession implements FornaxCoreServer
@@ -328,13 +465,14 @@ func (g *grpcServer) OpenSession(nodeIdentifier string, pod *v1.Pod, session *fo
 		},
 	}
 	m := &fornaxcore_grpc.FornaxCoreMessage{
-		MessageType: messageType,
-		MessageBody: &body,
+		MessageType:       messageType,
+		MessageBody:       &body,
+		RequestIdentifier: util.RequestId(session),
 	}
 
 	err = g.DispatchNodeMessage(nodeIdentifier, m)
 	if err != nil {
-		klog.ErrorS(err, "Failed to dispatch message to node", "node", nodeIdentifier, "session", sessionIdentifier)
+		klog.ErrorS(err, "Failed to dispatch message to node", "node", nodeIdentifier, "session", sessionIdentifier, "requestId", util.RequestId(session))
 		return err
 	}
 	return nil
@@ -0,0 +1,69 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"time"
+
+	fornaxcore_grpc "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
+)
+
+// node agents keep a GetMessage stream open for their whole life, server ping idle connection to find dead nodes behind
+// NAT or load balancer which silently drop connection, connections are never aged out, otherwise all nodes reconnect periodically
+const (
+	DefaultServerKeepaliveTime    = 60 * time.Second
+	DefaultServerKeepaliveTimeout = 20 * time.Second
+	// node agents ping every 30s, server close connection of client pinging more often than this with GOAWAY
+	DefaultServerKeepaliveMinTime = 15 * time.Second
+)
+
+// ServerKeepaliveOptions return keepalive server options of node agent facing grpc server
+func ServerKeepaliveOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    DefaultServerKeepaliveTime,
+			Timeout: DefaultServerKeepaliveTimeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime: DefaultServerKeepaliveMinTime,
+			// node agent ping before its first stream is opened
+			PermitWithoutStream: true,
+		}),
+	}
+}
+
+// registerHealthAndReflection register grpc health service and server reflection, so load balancer can health check fornaxcore
+// and tools like grpcurl can list and describe fornaxcore service, health is serving until context is done
+func registerHealthAndReflection(ctx context.Context, s *grpc.Server) *health.Server {
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(fornaxcore_grpc.FornaxCoreService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(s, healthServer)
+	reflection.Register(s)
+	go func() {
+		<-ctx.Done()
+		// set all services to NOT_SERVING, load balancer stop sending new nodes to this fornaxcore
+		healthServer.Shutdown()
+	}()
+	return healthServer
+}
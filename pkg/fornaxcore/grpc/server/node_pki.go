@@ -0,0 +1,249 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"time"
+
+	"centaurusinfra.io/fornax-serverless/pkg/config"
+	fornaxcore_grpc "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc"
+	"centaurusinfra.io/fornax-serverless/pkg/pki"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	grpcstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultNodeCertificateValiditySeconds = 24 * 60 * 60
+)
+
+var (
+	InvalidNodePKIConfigurationError = errors.New("node pki requires server names or server certificate files")
+	NodeUnauthenticatedError         = grpcstatus.Error(codes.Unauthenticated, "node does not have a valid client certificate")
+	NodePermissionDeniedError        = grpcstatus.Error(codes.PermissionDenied, "node client certificate does not match node identifier")
+	NodePKINotEnabledError           = grpcstatus.Error(codes.Unimplemented, "node pki is not enabled")
+)
+
+// NodePKIConfiguration enable mtls of node agent grpc channel, node agents must have a client certificate issued by node CA,
+// node pki is disabled if file does not exist
+type NodePKIConfiguration struct {
+	Enabled    bool   `json:"enabled"`
+	CACertFile string `json:"caCertFile,omitempty"`
+	CAKeyFile  string `json:"caKeyFile,omitempty"`
+	// +optional, server certificate of grpc server, files are reloaded on rotation,
+	// if not set, server certificate is issued by node CA using server names
+	ServerCertFile string `json:"serverCertFile,omitempty"`
+	ServerKeyFile  string `json:"serverKeyFile,omitempty"`
+	// dns names and ips node agents use to connect fornaxcore
+	// +optional
+	ServerNames        []string `json:"serverNames,omitempty"`
+	BootstrapTokenFile string   `json:"bootstrapTokenFile,omitempty"`
+	// +optional
+	NodeCertificateValiditySeconds int64 `json:"nodeCertificateValiditySeconds,omitempty"`
+}
+
+func DefaultNodePKIConfiguration() *NodePKIConfiguration {
+	return &NodePKIConfiguration{
+		Enabled:                        false,
+		CACertFile:                     config.DefaultFornaxCoreNodeCACertFile,
+		CAKeyFile:                      config.DefaultFornaxCoreNodeCAKeyFile,
+		BootstrapTokenFile:             config.DefaultFornaxCoreBootstrapTokenFile,
+		NodeCertificateValiditySeconds: DefaultNodeCertificateValiditySeconds,
+	}
+}
+
+func LoadNodePKIConfiguration(file string) (*NodePKIConfiguration, error) {
+	config := DefaultNodePKIConfiguration()
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	if !config.Enabled {
+		return config, nil
+	}
+	if len(config.ServerNames) == 0 && (len(config.ServerCertFile) == 0 || len(config.ServerKeyFile) == 0) {
+		return nil, InvalidNodePKIConfigurationError
+	}
+	if config.NodeCertificateValiditySeconds <= 0 {
+		config.NodeCertificateValiditySeconds = DefaultNodeCertificateValiditySeconds
+	}
+	return config, nil
+}
+
+// NodePKI issue certificates of node agents and serve grpc server certificate, a node certificate is also server certificate of node agent debug service,
+// fornaxcore call debug service using a client certificate issued by node CA
+type NodePKI struct {
+	config            *NodePKIConfiguration
+	ca                *pki.CertificateAuthority
+	serverCertificate *pki.RotatingCertificate
+	clientCertificate *pki.RotatingCertificate
+	bootstrapTokens   *pki.BootstrapTokenAuthenticator
+}
+
+func NewNodePKI(config *NodePKIConfiguration) (*NodePKI, error) {
+	ca, err := pki.LoadOrCreateCertificateAuthority(config.CACertFile, config.CAKeyFile, "fornaxcore-node-ca")
+	if err != nil {
+		return nil, err
+	}
+	p := &NodePKI{
+		config:          config,
+		ca:              ca,
+		bootstrapTokens: pki.NewBootstrapTokenAuthenticator(config.BootstrapTokenFile),
+	}
+	p.serverCertificate, err = pki.NewRotatingCertificate("fornaxcore-grpc-server", nil, p.renewServerCertificate)
+	if err != nil {
+		return nil, err
+	}
+	p.clientCertificate, err = pki.NewRotatingCertificate("fornaxcore-node-client", nil, p.renewClientCertificate)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// renewServerCertificate reload server certificate files if configured, so, a certificate rotated by other tools is picked up,
+// else issue a new one using node CA
+func (p *NodePKI) renewServerCertificate(current *tls.Certificate) (*pki.KeyPair, error) {
+	if len(p.config.ServerCertFile) > 0 && len(p.config.ServerKeyFile) > 0 {
+		certPEM, err := os.ReadFile(p.config.ServerCertFile)
+		if err != nil {
+			return nil, err
+		}
+		keyPEM, err := os.ReadFile(p.config.ServerKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		return &pki.KeyPair{CertPEM: certPEM, KeyPEM: keyPEM}, nil
+	}
+	dnsNames, ips := []string{}, []net.IP{}
+	for _, v := range p.config.ServerNames {
+		if ip := net.ParseIP(v); ip != nil {
+			ips = append(ips, ip)
+		} else {
+			dnsNames = append(dnsNames, v)
+		}
+	}
+	return p.ca.Issue(pkix.Name{CommonName: "fornaxcore"}, dnsNames, ips, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}, pki.DefaultServerCertValidity)
+}
+
+func (p *NodePKI) renewClientCertificate(current *tls.Certificate) (*pki.KeyPair, error) {
+	return p.ca.Issue(pkix.Name{CommonName: pki.FornaxCoreCommonName}, nil, nil, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, pki.DefaultClientCertValidity)
+}
+
+// Run rotate server and client certificates until context is done
+func (p *NodePKI) Run(ctx context.Context) {
+	go p.serverCertificate.Run(ctx)
+	go p.clientCertificate.Run(ctx)
+}
+
+// NodeTransportCredentials is grpc credentials fornaxcore use to call debug service of a node, node agent must serve certificate of node issued by node CA
+func (p *NodePKI) NodeTransportCredentials(nodeId string) credentials.TransportCredentials {
+	return credentials.NewTLS(&tls.Config{
+		MinVersion:           tls.VersionTLS12,
+		GetClientCertificate: p.clientCertificate.GetClientCertificate,
+		// node certificate prove identity in common name, it does not have node address, chain and common name are checked in VerifyConnection
+		InsecureSkipVerify: true,
+		VerifyConnection:   pki.VerifyServerCommonName(p.ca.CertPool(), pki.NodeCommonName(nodeId)),
+	})
+}
+
+// TLSConfig is grpc server tls config, client certificate is optional on handshake, so, a node without certificate can bootstrap,
+// all methods except RequestCertificate reject a call without a verified node certificate
+func (p *NodePKI) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: p.serverCertificate.GetCertificate,
+		ClientAuth:     tls.VerifyClientCertIfGiven,
+		ClientCAs:      p.ca.CertPool(),
+	}
+}
+
+// authorizeNode check client certificate of call is certificate of node, it's always allowed if node pki is not enabled
+func (g *grpcServer) authorizeNode(ctx context.Context, identifier *fornaxcore_grpc.NodeIdentifier) error {
+	if g.nodePKI == nil {
+		return nil
+	}
+	cn, err := pki.PeerCommonName(ctx)
+	if err != nil {
+		return NodeUnauthenticatedError
+	}
+	if cn != pki.NodeCommonName(identifier.GetIdentifier()) {
+		klog.InfoS("Reject node call using certificate of other node", "node", identifier.GetIdentifier(), "commonName", cn)
+		return NodePermissionDeniedError
+	}
+	return nil
+}
+
+// SetNodePKI enable mtls of node channel, it must be called before RunGrpcServer, caller run node pki
+func (g *grpcServer) SetNodePKI(nodePKI *NodePKI) {
+	g.nodePKI = nodePKI
+}
+
+// RequestCertificate sign node client certificate, a node renew its certificate using current certificate,
+// a node without a valid certificate must provide a bootstrap token
+func (g *grpcServer) RequestCertificate(ctx context.Context, request *fornaxcore_grpc.CertificateRequest) (*fornaxcore_grpc.CertificateResponse, error) {
+	if g.nodePKI == nil {
+		return nil, NodePKINotEnabledError
+	}
+	nodeId := request.GetNodeIdentifier().GetIdentifier()
+	if len(nodeId) == 0 {
+		return nil, grpcstatus.Error(codes.InvalidArgument, "node identifier is required to request certificate")
+	}
+	if err := g.authorizeNode(ctx, request.GetNodeIdentifier()); err != nil {
+		if err == NodePermissionDeniedError || len(request.GetBootstrapToken()) == 0 {
+			return nil, err
+		}
+		tokenId, err := g.nodePKI.bootstrapTokens.Authenticate(request.GetBootstrapToken())
+		if err != nil {
+			klog.ErrorS(err, "Reject node certificate request", "node", nodeId)
+			return nil, grpcstatus.Error(codes.Unauthenticated, pki.InvalidBootstrapTokenError.Error())
+		}
+		klog.InfoS("Node bootstrapped using token", "node", nodeId, "token", tokenId)
+	}
+	validity := time.Duration(g.nodePKI.config.NodeCertificateValiditySeconds) * time.Second
+	// node agent also serve its debug service using node certificate
+	certPEM, err := g.nodePKI.ca.SignCSR(request.GetCsr(), pki.NodeCommonName(nodeId), validity, x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth)
+	if err != nil {
+		return nil, grpcstatus.Error(codes.InvalidArgument, err.Error())
+	}
+	certs, err := pki.ParseCertificatesPEM(certPEM)
+	if err != nil {
+		return nil, grpcstatus.Error(codes.Internal, err.Error())
+	}
+	klog.InfoS("Issued node certificate", "node", nodeId, "serial", certs[0].SerialNumber.Text(16), "notAfter", certs[0].NotAfter)
+	return &fornaxcore_grpc.CertificateResponse{
+		Certificate:   certPEM,
+		CaCertificate: g.nodePKI.ca.CertPEM(),
+		NotAfter:      timestamppb.New(certs[0].NotAfter),
+	}, nil
+}
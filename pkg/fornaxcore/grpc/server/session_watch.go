@@ -0,0 +1,227 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	fornaxcore_grpc "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc"
+	ie "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/internal"
+	fornaxstore "centaurusinfra.io/fornax-serverless/pkg/store"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+var (
+	SessionWatchNotReadyError  = grpcstatus.Error(codes.Unavailable, "session watch is not ready yet")
+	MissingNodeIdentifierError = grpcstatus.Error(codes.InvalidArgument, "node identifier is required to watch sessions")
+)
+
+// SetSessionWatchSource set store and pod manager used by WatchSessions, node name of a session is found from its pod
+func (g *grpcServer) SetSessionWatchSource(sessionStore fornaxstore.ApiStorageInterface, podManager ie.PodManagerInterface) {
+	g.Lock()
+	defer g.Unlock()
+	g.sessionStore = sessionStore
+	g.podManager = podManager
+}
+
+// addSessionWatcher record a session watch of node, session close of a watching node is sent by session watch
+func (g *grpcServer) addSessionWatcher(nodeId string) {
+	g.Lock()
+	defer g.Unlock()
+	g.sessionWatchers[nodeId] += 1
+}
+
+func (g *grpcServer) removeSessionWatcher(nodeId string) {
+	g.Lock()
+	defer g.Unlock()
+	if g.sessionWatchers[nodeId] <= 1 {
+		delete(g.sessionWatchers, nodeId)
+	} else {
+		g.sessionWatchers[nodeId] -= 1
+	}
+}
+
+// watchingSessions return true if node has a open session watch on this server
+func (g *grpcServer) watchingSessions(nodeId string) bool {
+	g.RLock()
+	defer g.RUnlock()
+	return g.sessionWatchers[nodeId] > 0
+}
+
+// sessionNode return node of session's pod, empty if session is not assigned or pod is gone
+func (g *grpcServer) sessionNode(session *fornaxv1.ApplicationSession) (string, string) {
+	if session == nil || session.Status.PodReference == nil {
+		return "", ""
+	}
+	podName := session.Status.PodReference.Name
+	pod := g.podManager.FindPod(podName)
+	if pod == nil {
+		return "", podName
+	}
+	return pod.GetLabels()[fornaxv1.LabelFornaxCoreNode], podName
+}
+
+// WatchSessions stream changes of sessions on node of request, it list sessions of node first if resource version is empty,
+// then watch session store from list or request resource version and drop events of other nodes,
+// a session which was sent to node is sent as Deleted when it's deleted or moved to other node,
+// sessions known by node before resume are taken from request, so, they are also sent as Deleted after they left node
+func (g *grpcServer) WatchSessions(request *fornaxcore_grpc.WatchSessionsRequest, server fornaxcore_grpc.FornaxCoreService_WatchSessionsServer) error {
+	nodeId := request.GetNodeIdentifier().GetIdentifier()
+	if len(nodeId) == 0 {
+		return MissingNodeIdentifierError
+	}
+	if err := g.authorizeNode(server.Context(), request.GetNodeIdentifier()); err != nil {
+		return err
+	}
+	g.RLock()
+	sessionStore := g.sessionStore
+	g.RUnlock()
+	if sessionStore == nil {
+		return SessionWatchNotReadyError
+	}
+
+	ctx := server.Context()
+	// sessions known by node, so, a session leaving node is sent as deleted even its new pod is on other node
+	nodeSessions := map[string]bool{}
+	for _, name := range request.GetKnownSessions() {
+		nodeSessions[name] = true
+	}
+	send := func(eventType fornaxcore_grpc.SessionWatchEvent_Type, session *fornaxv1.ApplicationSession, podName string) error {
+		sessionData, err := json.Marshal(session)
+		if err != nil {
+			return err
+		}
+		return server.Send(&fornaxcore_grpc.SessionWatchEvent{
+			Type:              eventType,
+			ResourceVersion:   session.ResourceVersion,
+			PodIdentifier:     podName,
+			SessionData:       sessionData,
+			SessionIdentifier: util.Name(session),
+		})
+	}
+
+	rv := request.GetResourceVersion()
+	if len(rv) == 0 {
+		list := &fornaxv1.ApplicationSessionList{}
+		if err := sessionStore.GetList(ctx, fornaxv1.ApplicationSessionGrvKey, apistorage.ListOptions{
+			ResourceVersion: "0",
+			Predicate:       apistorage.Everything,
+			Recursive:       true,
+		}, list); err != nil {
+			return err
+		}
+		listed := map[string]bool{}
+		for i := range list.Items {
+			session := &list.Items[i]
+			if node, podName := g.sessionNode(session); node == nodeId {
+				listed[util.Name(session)] = true
+				if err := send(fornaxcore_grpc.SessionWatchEvent_Added, session, podName); err != nil {
+					return err
+				}
+			}
+		}
+		// known sessions which are not on node anymore, their changes could be compacted, send them as deleted
+		for name := range nodeSessions {
+			if listed[name] {
+				continue
+			}
+			namespace, sessionName, err := cache.SplitMetaNamespaceKey(name)
+			if err != nil {
+				continue
+			}
+			session := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: sessionName, ResourceVersion: list.ResourceVersion}}
+			if err := send(fornaxcore_grpc.SessionWatchEvent_Deleted, session, ""); err != nil {
+				return err
+			}
+		}
+		nodeSessions = listed
+		rv = list.ResourceVersion
+	}
+
+	predicate := apistorage.Everything
+	predicate.AllowWatchBookmarks = true
+	wi, err := sessionStore.WatchWithOldObj(ctx, fornaxv1.ApplicationSessionGrvKey, apistorage.ListOptions{
+		ResourceVersion: rv,
+		Predicate:       predicate,
+		Recursive:       true,
+	})
+	if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+		return grpcstatus.Error(codes.OutOfRange, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	defer wi.Stop()
+	g.addSessionWatcher(nodeId)
+	defer g.removeSessionWatcher(nodeId)
+	klog.InfoS("Node start to watch sessions", "node", nodeId, "resourceVersion", rv, "sessions", len(nodeSessions))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-wi.ResultChanWithPrevobj():
+			if !ok {
+				return grpcstatus.Error(codes.Aborted, "session watch is closed by store, watch again from last resource version")
+			}
+			if event.Type == watch.Error {
+				return grpcstatus.Errorf(codes.Aborted, "session watch failed, %v", apierrors.FromObject(event.Object))
+			}
+			if event.Type == watch.Bookmark {
+				if accessor, err := meta.Accessor(event.Object); err == nil {
+					if err := server.Send(&fornaxcore_grpc.SessionWatchEvent{Type: fornaxcore_grpc.SessionWatchEvent_Bookmark, ResourceVersion: accessor.GetResourceVersion()}); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			session, ok := event.Object.(*fornaxv1.ApplicationSession)
+			if !ok {
+				continue
+			}
+			name := util.Name(session)
+			node, podName := g.sessionNode(session)
+			known := nodeSessions[name]
+			switch {
+			case event.Type != watch.Deleted && node == nodeId:
+				eventType := fornaxcore_grpc.SessionWatchEvent_Modified
+				if !known {
+					eventType = fornaxcore_grpc.SessionWatchEvent_Added
+				}
+				nodeSessions[name] = true
+				err = send(eventType, session, podName)
+			case known || (event.Type == watch.Deleted && node == nodeId):
+				delete(nodeSessions, name)
+				err = send(fornaxcore_grpc.SessionWatchEvent_Deleted, session, podName)
+			}
+			if err != nil {
+				klog.ErrorS(err, "Failed to send session event to node", "node", nodeId, "session", name)
+				return err
+			}
+		}
+	}
+}
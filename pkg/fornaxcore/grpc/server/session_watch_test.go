@@ -0,0 +1,208 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	fornaxcore_grpc "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc"
+	ie "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/internal"
+	"centaurusinfra.io/fornax-serverless/pkg/store"
+	"centaurusinfra.io/fornax-serverless/pkg/store/inmemory"
+	"google.golang.org/grpc"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// fakePodManager only find pods by name, other methods are not used by session watch
+type fakePodManager struct {
+	ie.PodManagerInterface
+	pods map[string]*v1.Pod
+}
+
+func (f *fakePodManager) FindPod(podName string) *v1.Pod {
+	return f.pods[podName]
+}
+
+type fakeSessionWatchStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *fornaxcore_grpc.SessionWatchEvent
+}
+
+func (f *fakeSessionWatchStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeSessionWatchStream) Send(event *fornaxcore_grpc.SessionWatchEvent) error {
+	f.events <- event
+	return nil
+}
+
+func newTestPodOnNode(name, node string) *v1.Pod {
+	return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, Labels: map[string]string{fornaxv1.LabelFornaxCoreNode: node}}}
+}
+
+func newTestSessionWatchServer(t *testing.T) (*grpcServer, *inmemory.MemoryStore) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	gr := fornaxv1.ApplicationSessionGrv.GroupResource()
+	sessionStore := inmemory.NewMemoryStore(ctx, gr, fornaxv1.ApplicationSessionGrvKey,
+		func() runtime.Object { return &fornaxv1.ApplicationSession{} },
+		func() runtime.Object { return &fornaxv1.ApplicationSessionList{} },
+		store.DefaultResourceStorageConfiguration(gr))
+	podManager := &fakePodManager{pods: map[string]*v1.Pod{
+		"ns/pod1": newTestPodOnNode("pod1", "node1"),
+		"ns/pod2": newTestPodOnNode("pod2", "node2"),
+	}}
+	g := NewGrpcServer()
+	g.SetSessionWatchSource(sessionStore, podManager)
+	return g, sessionStore
+}
+
+func putTestSession(t *testing.T, sessionStore *inmemory.MemoryStore, name, pod string) *fornaxv1.ApplicationSession {
+	session := &fornaxv1.ApplicationSession{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+		Status: fornaxv1.ApplicationSessionStatus{
+			SessionStatus: fornaxv1.SessionStatusAvailable,
+			PodReference:  &v1.LocalObjectReference{Name: pod},
+		},
+	}
+	out := &fornaxv1.ApplicationSession{}
+	key := fmt.Sprintf("%s/ns/%s", fornaxv1.ApplicationSessionGrvKey, name)
+	if err := sessionStore.CreateOrReplace(context.Background(), key, session, out); err != nil {
+		t.Fatalf("failed to put session %s: %v", name, err)
+	}
+	return out
+}
+
+func startTestSessionWatch(t *testing.T, g *grpcServer, rv string, knownSessions ...string) *fakeSessionWatchStream {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	stream := &fakeSessionWatchStream{ctx: ctx, events: make(chan *fornaxcore_grpc.SessionWatchEvent, 100)}
+	request := &fornaxcore_grpc.WatchSessionsRequest{
+		NodeIdentifier:  &fornaxcore_grpc.NodeIdentifier{Identifier: "node1"},
+		ResourceVersion: rv,
+		KnownSessions:   knownSessions,
+	}
+	go g.WatchSessions(request, stream)
+	return stream
+}
+
+func nextTestSessionEvent(t *testing.T, stream *fakeSessionWatchStream) *fornaxcore_grpc.SessionWatchEvent {
+	for {
+		select {
+		case event := <-stream.events:
+			if event.GetType() == fornaxcore_grpc.SessionWatchEvent_Bookmark {
+				continue
+			}
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for session event")
+			return nil
+		}
+	}
+}
+
+func TestWatchSessionsListSessionsOfNode(t *testing.T) {
+	g, sessionStore := newTestSessionWatchServer(t)
+	putTestSession(t, sessionStore, "s1", "ns/pod1")
+	putTestSession(t, sessionStore, "s2", "ns/pod2")
+
+	stream := startTestSessionWatch(t, g, "")
+	event := nextTestSessionEvent(t, stream)
+	if event.GetType() != fornaxcore_grpc.SessionWatchEvent_Added || event.GetSessionIdentifier() != "ns/s1" || event.GetPodIdentifier() != "ns/pod1" {
+		t.Fatalf("expect s1 added on pod1, got %v", event)
+	}
+	session := &fornaxv1.ApplicationSession{}
+	if err := json.Unmarshal(event.GetSessionData(), session); err != nil || session.Name != "s1" {
+		t.Fatalf("expect session data of s1, got %s, %v", session.Name, err)
+	}
+
+	// session of other node is skipped, a session moved to this node is added
+	putTestSession(t, sessionStore, "s2", "ns/pod1")
+	if event := nextTestSessionEvent(t, stream); event.GetType() != fornaxcore_grpc.SessionWatchEvent_Added || event.GetSessionIdentifier() != "ns/s2" {
+		t.Fatalf("expect s2 added, got %v", event)
+	}
+	putTestSession(t, sessionStore, "s2", "ns/pod2")
+	if event := nextTestSessionEvent(t, stream); event.GetType() != fornaxcore_grpc.SessionWatchEvent_Deleted || event.GetSessionIdentifier() != "ns/s2" {
+		t.Fatalf("expect s2 deleted, got %v", event)
+	}
+	if !g.watchingSessions("node1") {
+		t.Errorf("expect node1 watching sessions")
+	}
+}
+
+func TestWatchSessionsResumeSendDeletedOfKnownSessions(t *testing.T) {
+	g, sessionStore := newTestSessionWatchServer(t)
+	putTestSession(t, sessionStore, "s1", "ns/pod1")
+	s2 := putTestSession(t, sessionStore, "s2", "ns/pod1")
+
+	// resume from resource version, s2 move to other node and its pod is gone after node received it
+	stream := startTestSessionWatch(t, g, s2.ResourceVersion, "ns/s1", "ns/s2")
+	time.Sleep(100 * time.Millisecond)
+	putTestSession(t, sessionStore, "s2", "ns/pod2")
+	if event := nextTestSessionEvent(t, stream); event.GetType() != fornaxcore_grpc.SessionWatchEvent_Deleted || event.GetSessionIdentifier() != "ns/s2" {
+		t.Fatalf("expect s2 deleted, got %v", event)
+	}
+	putTestSession(t, sessionStore, "s1", "ns/pod3")
+	if event := nextTestSessionEvent(t, stream); event.GetType() != fornaxcore_grpc.SessionWatchEvent_Deleted || event.GetSessionIdentifier() != "ns/s1" {
+		t.Fatalf("expect s1 deleted, got %v", event)
+	}
+
+	// resync from scratch, known sessions which are not on node are sent as deleted
+	stream = startTestSessionWatch(t, g, "", "ns/s1", "ns/s3")
+	putTestSession(t, sessionStore, "s4", "ns/pod1")
+	events := map[string]fornaxcore_grpc.SessionWatchEvent_Type{}
+	for len(events) < 3 {
+		event := nextTestSessionEvent(t, stream)
+		events[event.GetSessionIdentifier()] = event.GetType()
+	}
+	expected := map[string]fornaxcore_grpc.SessionWatchEvent_Type{
+		"ns/s1": fornaxcore_grpc.SessionWatchEvent_Deleted,
+		"ns/s3": fornaxcore_grpc.SessionWatchEvent_Deleted,
+		"ns/s4": fornaxcore_grpc.SessionWatchEvent_Added,
+	}
+	for name, eventType := range expected {
+		if events[name] != eventType {
+			t.Errorf("expect %s %v, got %v", name, eventType, events[name])
+		}
+	}
+}
+
+func TestCloseSessionSkipNodeWatchingSessions(t *testing.T) {
+	g, _ := newTestSessionWatchServer(t)
+	messages := make(chan *fornaxcore_grpc.FornaxCoreMessage, 10)
+	g.nodeOutgoingChans["node1"] = messages
+	session := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "s1"}}
+	pod := newTestPodOnNode("pod1", "node1")
+
+	g.addSessionWatcher("node1")
+	if err := g.CloseSession("node1", pod, session); err != nil || len(messages) != 0 {
+		t.Fatalf("expect no session close message to node watching sessions, got %d, %v", len(messages), err)
+	}
+	g.removeSessionWatcher("node1")
+	if err := g.CloseSession("node1", pod, session); err != nil || len(messages) != 1 {
+		t.Fatalf("expect session close message to node, got %d, %v", len(messages), err)
+	}
+}
@@ -0,0 +1,344 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"centaurusinfra.io/fornax-serverless/pkg/config"
+	"centaurusinfra.io/fornax-serverless/pkg/store/factory"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultElectionKey        = "/fornaxcore/leader"
+	DefaultLeaseSeconds       = 15
+	DefaultReplicationAddress = ":18002"
+	DefaultEtcdDialTimeout    = 10 * time.Second
+
+	// header of rejected request on follower, client retry request on leader api server
+	LeaderHeader = "X-Fornax-Leader"
+)
+
+var (
+	InvalidHAConfigurationError = errors.New("etcd servers, a https advertise url and a positive lease seconds are required by fornaxcore ha")
+	NotLeaderError              = errors.New("fornaxcore replica is not leader, send request to leader")
+)
+
+var (
+	haLeader = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Subsystem:      "fornax_ha",
+			Name:           "leader",
+			Help:           "1 if this fornaxcore replica is leader, 0 if it is follower",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+	haLeaderChanges = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_ha",
+			Name:           "leader_changes_total",
+			Help:           "Number of leader changes observed by this fornaxcore replica",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(haLeader, haLeaderChanges)
+}
+
+// HAConfiguration enable multiple fornaxcore replicas, replicas elect a leader using a etcd lease,
+// leader run controllers and node agent grpc server and serve api requests, followers replicate leader memory stores and serve only health checks
+type HAConfiguration struct {
+	Enabled bool `json:"enabled"`
+	// unique name of replica, hostname by default
+	Identity    string   `json:"identity,omitempty"`
+	EtcdServers []string `json:"etcdServers,omitempty"`
+	ElectionKey string   `json:"electionKey,omitempty"`
+	// leader lose leadership if it can not renew its lease in lease seconds
+	LeaseSeconds int `json:"leaseSeconds,omitempty"`
+	// address replication server listen on
+	ReplicationAddress string `json:"replicationAddress,omitempty"`
+	// url followers use to reach replication server of this replica, e.g. https://10.0.0.1:18002
+	AdvertiseURL string `json:"advertiseURL,omitempty"`
+	// CA issue replica certificates of replication server and followers, files must be same on all replicas
+	ReplicaCACertFile string `json:"replicaCACertFile,omitempty"`
+	ReplicaCAKeyFile  string `json:"replicaCAKeyFile,omitempty"`
+	// url of api server of this replica returned to clients writing to a follower, optional
+	APIServerURL string `json:"apiServerURL,omitempty"`
+}
+
+func DefaultHAConfiguration() *HAConfiguration {
+	identity, _ := os.Hostname()
+	return &HAConfiguration{
+		Identity:           identity,
+		ElectionKey:        DefaultElectionKey,
+		LeaseSeconds:       DefaultLeaseSeconds,
+		ReplicationAddress: DefaultReplicationAddress,
+		ReplicaCACertFile:  config.DefaultFornaxCoreReplicaCACertFile,
+		ReplicaCAKeyFile:   config.DefaultFornaxCoreReplicaCAKeyFile,
+	}
+}
+
+// LoadHAConfiguration read ha configuration from a json file, default configuration is returned if file does not exist,
+// fields missing in file keep their default value
+func LoadHAConfiguration(file string) (*HAConfiguration, error) {
+	haConfig := DefaultHAConfiguration()
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return haConfig, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, haConfig); err != nil {
+		return nil, err
+	}
+	if haConfig.Enabled && (len(haConfig.EtcdServers) == 0 || !strings.HasPrefix(haConfig.AdvertiseURL, "https://") || len(haConfig.Identity) == 0 || haConfig.LeaseSeconds <= 0) {
+		return nil, InvalidHAConfigurationError
+	}
+	return haConfig, nil
+}
+
+// LeaderInfo is value of election key written by leader
+type LeaderInfo struct {
+	Identity       string `json:"identity"`
+	ReplicationURL string `json:"replicationURL"`
+	APIServerURL   string `json:"apiServerURL,omitempty"`
+}
+
+// Elector campaign for leadership of fornaxcore replicas and replicate leader memory stores until this replica become leader
+type Elector struct {
+	config *HAConfiguration
+	self   LeaderInfo
+
+	mu      sync.RWMutex
+	leading bool
+	leader  *LeaderInfo
+	// cancel and done of current replication from leader
+	stopReplication func()
+	replicationDone chan struct{}
+	// replicate memory stores from leader url until ctx is done, it's set when replication tls is ready
+	replicate func(ctx context.Context, leaderURL string)
+}
+
+func NewElector(config *HAConfiguration) *Elector {
+	return &Elector{
+		config: config,
+		self: LeaderInfo{
+			Identity:       config.Identity,
+			ReplicationURL: strings.TrimSuffix(config.AdvertiseURL, "/"),
+			APIServerURL:   config.APIServerURL,
+		},
+	}
+}
+
+// IsLeader return true if this replica has won election
+func (e *Elector) IsLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.leading
+}
+
+// Leader return last observed leader, nil if there is no leader yet
+func (e *Elector) Leader() *LeaderInfo {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.leader
+}
+
+// Run start replication server and campaign, this replica follow observed leader until it win election,
+// then it stop replication and call startLeading, fornaxcore exit if its etcd session is lost,
+// controllers of a old leader must not run with a new leader, replica restart as a follower
+func (e *Elector) Run(ctx context.Context, startLeading func()) error {
+	value, err := json.Marshal(&e.self)
+	if err != nil {
+		return err
+	}
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   e.config.EtcdServers,
+		DialTimeout: DefaultEtcdDialTimeout,
+	})
+	if err != nil {
+		return err
+	}
+	session, err := concurrency.NewSession(client, concurrency.WithTTL(e.config.LeaseSeconds), concurrency.WithContext(ctx))
+	if err != nil {
+		client.Close()
+		return err
+	}
+	election := concurrency.NewElection(session, e.config.ElectionKey)
+
+	replicationTLS, err := NewReplicationTLS(e.config)
+	if err != nil {
+		client.Close()
+		return err
+	}
+	replicationTLS.Run(ctx)
+	replicationClient := replicationTLS.Client()
+	e.mu.Lock()
+	e.replicate = func(ctx context.Context, leaderURL string) {
+		factory.ReplicateMemoryStores(ctx, leaderURL, replicationClient)
+	}
+	e.mu.Unlock()
+
+	mux := http.NewServeMux()
+	mux.Handle(factory.StoreReplicationPath, factory.NewStoreReplicationHandler(e.IsLeader))
+	server := &http.Server{
+		Addr:      e.config.ReplicationAddress,
+		Handler:   WithReplicaIdentity(mux),
+		TLSConfig: replicationTLS.ServerTLSConfig(),
+	}
+	go func() {
+		klog.InfoS("Starting store replication server", "address", e.config.ReplicationAddress)
+		if err := server.ListenAndServeTLS("", ""); err != nil {
+			klog.Fatal(err)
+		}
+	}()
+
+	go func() {
+		<-session.Done()
+		klog.Fatal("Fornaxcore ha etcd session is lost, exit to rejoin as follower")
+	}()
+
+	go func() {
+		for resp := range election.Observe(ctx) {
+			if len(resp.Kvs) == 0 {
+				continue
+			}
+			leader := &LeaderInfo{}
+			if err := json.Unmarshal(resp.Kvs[0].Value, leader); err != nil {
+				klog.ErrorS(err, "Failed to decode fornaxcore leader", "value", string(resp.Kvs[0].Value))
+				continue
+			}
+			e.follow(ctx, leader)
+		}
+	}()
+
+	go func() {
+		klog.InfoS("Campaigning for fornaxcore leader", "identity", e.self.Identity, "key", e.config.ElectionKey)
+		if err := election.Campaign(ctx, string(value)); err != nil {
+			if ctx.Err() == nil {
+				klog.Fatal(err)
+			}
+			return
+		}
+		e.lead()
+		klog.InfoS("Became fornaxcore leader, starting controllers", "identity", e.self.Identity)
+		startLeading()
+	}()
+	return nil
+}
+
+// follow replicate memory stores from a new leader, replication from previous leader is stopped
+func (e *Elector) follow(ctx context.Context, leader *LeaderInfo) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.leader != nil && *e.leader == *leader {
+		return
+	}
+	klog.InfoS("Observed fornaxcore leader", "leader", leader.Identity, "replicationURL", leader.ReplicationURL)
+	// first observed leader is not a change
+	if e.leader != nil {
+		haLeaderChanges.Inc()
+	}
+	e.leader = leader
+	if e.leading || leader.Identity == e.self.Identity {
+		return
+	}
+	e.stopReplicationLocked()
+	if e.replicate == nil {
+		return
+	}
+	replicate := e.replicate
+	replicaCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	e.stopReplication, e.replicationDone = cancel, done
+	go func() {
+		defer close(done)
+		replicate(replicaCtx, leader.ReplicationURL)
+	}()
+}
+
+// lead stop replication, memory stores must not be changed by old leader after controllers of this replica start
+func (e *Elector) lead() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stopReplicationLocked()
+	e.leading = true
+	self := e.self
+	if e.leader != nil && *e.leader != self {
+		haLeaderChanges.Inc()
+	}
+	e.leader = &self
+	haLeader.Set(1)
+}
+
+func (e *Elector) stopReplicationLocked() {
+	if e.stopReplication != nil {
+		e.stopReplication()
+		<-e.replicationDone
+		e.stopReplication, e.replicationDone = nil, nil
+	}
+}
+
+// WithLeaderRequests reject api requests on a follower with a hint of leader api server, health checks are served by any replica,
+// replicated objects get resource versions of follower store, reads of follower are not consistent with leader, so, they are rejected too
+func WithLeaderRequests(handler http.Handler, elector *Elector) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if followerPath(r.URL.Path) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		if elector.IsLeader() {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		if leader := elector.Leader(); leader != nil {
+			// api server url is more useful to client, identity is returned if leader does not advertise it
+			hint := leader.APIServerURL
+			if len(hint) == 0 {
+				hint = leader.Identity
+			}
+			w.Header().Set(LeaderHeader, hint)
+		}
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, NotLeaderError.Error(), http.StatusServiceUnavailable)
+	})
+}
+
+// followerPath return true if path is served by a follower, they do not read resource stores
+func followerPath(path string) bool {
+	for _, prefix := range []string{"/healthz", "/livez", "/readyz", "/metrics", "/version"} {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
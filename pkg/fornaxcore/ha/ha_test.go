@@ -0,0 +1,203 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ha
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/component-base/metrics/testutil"
+)
+
+func TestLoadHAConfiguration(t *testing.T) {
+	config, err := LoadHAConfiguration(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil || config.Enabled || config.LeaseSeconds != DefaultLeaseSeconds {
+		t.Fatalf("expected disabled default configuration, got %v, %v", config, err)
+	}
+
+	file := filepath.Join(t.TempDir(), "ha.json")
+	os.WriteFile(file, []byte(`{"enabled": true, "etcdServers": ["http://127.0.0.1:2379"]}`), 0644)
+	if _, err := LoadHAConfiguration(file); err != InvalidHAConfigurationError {
+		t.Errorf("expected error of missing advertise url, got %v", err)
+	}
+	os.WriteFile(file, []byte(`{"enabled": true, "identity": "core-1", "etcdServers": ["http://127.0.0.1:2379"], "advertiseURL": "http://10.0.0.1:18002"}`), 0644)
+	if _, err := LoadHAConfiguration(file); err != InvalidHAConfigurationError {
+		t.Errorf("expected error of plain http advertise url, got %v", err)
+	}
+	os.WriteFile(file, []byte(`{"enabled": true, "identity": "core-1", "etcdServers": ["http://127.0.0.1:2379"], "advertiseURL": "https://10.0.0.1:18002/"}`), 0644)
+	config, err = LoadHAConfiguration(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.ElectionKey != DefaultElectionKey || config.ReplicationAddress != DefaultReplicationAddress {
+		t.Errorf("expected missing fields keep default, got %v", config)
+	}
+	if elector := NewElector(config); elector.self.ReplicationURL != "https://10.0.0.1:18002" {
+		t.Errorf("expected trailing slash trimmed from advertise url, got %s", elector.self.ReplicationURL)
+	}
+}
+
+func TestWithLeaderRequests(t *testing.T) {
+	elector := NewElector(&HAConfiguration{Identity: "core-2"})
+	handler := WithLeaderRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), elector)
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+	applications := "/apis/core.fornax-serverless.centaurusinfra.io/v1/applications"
+
+	elector.leader = &LeaderInfo{Identity: "core-1", APIServerURL: "https://10.0.0.1:6443"}
+	for _, path := range []string{"/healthz", "/readyz/ping", "/metrics"} {
+		if w := serve(http.MethodGet, path); w.Code != http.StatusOK {
+			t.Errorf("expected follower serve %s, got %d", path, w.Code)
+		}
+	}
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		w := serve(method, applications)
+		if w.Code != http.StatusServiceUnavailable || w.Header().Get(LeaderHeader) != "https://10.0.0.1:6443" {
+			t.Errorf("expected follower reject %s with leader hint, got %d, %v", method, w.Code, w.Header())
+		}
+	}
+	if w := serve(http.MethodGet, "/healthzfoo"); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected follower reject path having health check prefix, got %d", w.Code)
+	}
+
+	elector.lead()
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		if w := serve(method, applications); w.Code != http.StatusOK {
+			t.Errorf("expected leader serve %s, got %d", method, w.Code)
+		}
+	}
+}
+
+// fakeReplication record replications started by elector, a replication run until it's stopped
+type fakeReplication struct {
+	started chan string
+	stopped chan string
+}
+
+func newTestElector(identity string) (*Elector, *fakeReplication) {
+	replication := &fakeReplication{started: make(chan string, 10), stopped: make(chan string, 10)}
+	elector := NewElector(&HAConfiguration{Identity: identity, AdvertiseURL: "https://" + identity + ":18002"})
+	elector.replicate = func(ctx context.Context, leaderURL string) {
+		replication.started <- leaderURL
+		<-ctx.Done()
+		replication.stopped <- leaderURL
+	}
+	return elector, replication
+}
+
+func expectReplication(t *testing.T, ch chan string, want string) {
+	select {
+	case got := <-ch:
+		if got != want {
+			t.Errorf("expected replication of %s, got %s", want, got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for replication of %s", want)
+	}
+}
+
+func expectNoReplication(t *testing.T, ch chan string) {
+	select {
+	case got := <-ch:
+		t.Errorf("expected no replication change, got %s", got)
+	default:
+	}
+}
+
+func leaderChanges(t *testing.T) float64 {
+	v, err := testutil.GetCounterMetricValue(haLeaderChanges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestElectorFollowHandoff(t *testing.T) {
+	elector, replication := newTestElector("core-3")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	core1 := &LeaderInfo{Identity: "core-1", ReplicationURL: "https://core-1:18002"}
+	core2 := &LeaderInfo{Identity: "core-2", ReplicationURL: "https://core-2:18002"}
+
+	changes := leaderChanges(t)
+	elector.follow(ctx, core1)
+	expectReplication(t, replication.started, core1.ReplicationURL)
+	if got := leaderChanges(t); got != changes {
+		t.Errorf("expected first observed leader is not counted as change, got %v changes", got-changes)
+	}
+
+	// same leader observed again does not restart replication
+	elector.follow(ctx, &LeaderInfo{Identity: "core-1", ReplicationURL: "https://core-1:18002"})
+	expectNoReplication(t, replication.started)
+	expectNoReplication(t, replication.stopped)
+
+	// replication of old leader is stopped before replication of new leader start
+	elector.follow(ctx, core2)
+	expectReplication(t, replication.stopped, core1.ReplicationURL)
+	expectReplication(t, replication.started, core2.ReplicationURL)
+	if got := leaderChanges(t); got != changes+1 {
+		t.Errorf("expected one leader change, got %v", got-changes)
+	}
+	if leader := elector.Leader(); leader == nil || *leader != *core2 {
+		t.Errorf("expected leader %v, got %v", core2, leader)
+	}
+	if elector.IsLeader() {
+		t.Errorf("expected follower is not leader")
+	}
+}
+
+func TestElectorLeadStopReplication(t *testing.T) {
+	elector, replication := newTestElector("core-2")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	core1 := &LeaderInfo{Identity: "core-1", ReplicationURL: "https://core-1:18002"}
+
+	elector.follow(ctx, core1)
+	expectReplication(t, replication.started, core1.ReplicationURL)
+
+	changes := leaderChanges(t)
+	elector.lead()
+	// lead return after replication stopped, memory stores are not changed by old leader any more
+	expectReplication(t, replication.stopped, core1.ReplicationURL)
+	if !elector.IsLeader() || elector.Leader().Identity != "core-2" {
+		t.Errorf("expected this replica became leader, got %v", elector.Leader())
+	}
+	if got := leaderChanges(t); got != changes+1 {
+		t.Errorf("expected one leader change, got %v", got-changes)
+	}
+
+	// leader observe its own election, it's neither a change nor a replication
+	elector.follow(ctx, &LeaderInfo{Identity: "core-2", ReplicationURL: "https://core-2:18002"})
+	expectNoReplication(t, replication.started)
+	if got := leaderChanges(t); got != changes+1 {
+		t.Errorf("expected observing self as leader is not counted again, got %v", got-changes)
+	}
+}
+
+func TestElectorFollowSelf(t *testing.T) {
+	elector, replication := newTestElector("core-1")
+	elector.follow(context.Background(), &LeaderInfo{Identity: "core-1", ReplicationURL: "https://core-1:18002"})
+	expectNoReplication(t, replication.started)
+}
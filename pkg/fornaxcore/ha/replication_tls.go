@@ -0,0 +1,109 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ha
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"net/http"
+	"net/url"
+
+	"centaurusinfra.io/fornax-serverless/pkg/pki"
+	"k8s.io/klog/v2"
+)
+
+// ReplicationTLS is mtls of store replication, replica CA shared by all replicas issue a certificate of this replica,
+// it's server certificate of replication server and client certificate used to replicate from leader
+type ReplicationTLS struct {
+	ca          *pki.CertificateAuthority
+	certificate *pki.RotatingCertificate
+}
+
+// NewReplicationTLS load or create replica CA, certificate is valid for host of advertise url
+func NewReplicationTLS(config *HAConfiguration) (*ReplicationTLS, error) {
+	ca, err := pki.LoadOrCreateCertificateAuthority(config.ReplicaCACertFile, config.ReplicaCAKeyFile, "fornaxcore-replica-ca")
+	if err != nil {
+		return nil, err
+	}
+	dnsNames, ips := []string{}, []net.IP{}
+	if u, err := url.Parse(config.AdvertiseURL); err == nil && len(u.Hostname()) > 0 {
+		if ip := net.ParseIP(u.Hostname()); ip != nil {
+			ips = append(ips, ip)
+		} else {
+			dnsNames = append(dnsNames, u.Hostname())
+		}
+	}
+	t := &ReplicationTLS{ca: ca}
+	t.certificate, err = pki.NewRotatingCertificate("fornaxcore-replica", nil, func(*tls.Certificate) (*pki.KeyPair, error) {
+		return ca.Issue(pkix.Name{CommonName: pki.ReplicaCommonName(config.Identity)}, dnsNames, ips,
+			[]x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}, pki.DefaultServerCertValidity)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Run rotate replica certificate until context is done
+func (t *ReplicationTLS) Run(ctx context.Context) {
+	go t.certificate.Run(ctx)
+}
+
+// ServerTLSConfig require followers to use a client certificate issued by replica CA
+func (t *ReplicationTLS) ServerTLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: t.certificate.GetCertificate,
+		ClientAuth:     tls.RequireAndVerifyClientCert,
+		ClientCAs:      t.ca.CertPool(),
+	}
+}
+
+// ClientTLSConfig trust replication server certificate issued by replica CA and present replica certificate
+func (t *ReplicationTLS) ClientTLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:           tls.VersionTLS12,
+		RootCAs:              t.ca.CertPool(),
+		GetClientCertificate: t.certificate.GetClientCertificate,
+	}
+}
+
+// Client is http client followers use to replicate leader stores
+func (t *ReplicationTLS) Client() *http.Client {
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: t.ClientTLSConfig()}}
+}
+
+// WithReplicaIdentity reject a replication request without a verified replica certificate,
+// a certificate issued by replica CA for other usage must not read memory stores
+func WithReplicaIdentity(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cn, err := pki.VerifiedCommonName(r.TLS)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if !pki.IsReplicaCommonName(cn) {
+			klog.InfoS("Reject store replication request of non replica certificate", "commonName", cn, "remote", r.RemoteAddr)
+			http.Error(w, "client certificate is not a fornaxcore replica certificate", http.StatusForbidden)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ha
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"centaurusinfra.io/fornax-serverless/pkg/pki"
+)
+
+func newTestReplicationTLS(t *testing.T, dir, identity string) *ReplicationTLS {
+	replicationTLS, err := NewReplicationTLS(&HAConfiguration{
+		Identity:          identity,
+		AdvertiseURL:      "https://127.0.0.1:18002",
+		ReplicaCACertFile: filepath.Join(dir, "replica-ca.crt"),
+		ReplicaCAKeyFile:  filepath.Join(dir, "replica-ca.key"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return replicationTLS
+}
+
+func TestReplicationTLS(t *testing.T) {
+	dir := t.TempDir()
+	leader := newTestReplicationTLS(t, dir, "core-1")
+	server := httptest.NewUnstartedServer(WithReplicaIdentity(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	server.TLS = leader.ServerTLSConfig()
+	// httptest use its own certificate if tls config does not have one
+	server.TLS.Certificates = []tls.Certificate{*leader.certificate.Current()}
+	server.StartTLS()
+	defer server.Close()
+	get := func(client *http.Client) (int, error) {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		return resp.StatusCode, nil
+	}
+
+	// follower loaded same replica CA
+	follower := newTestReplicationTLS(t, dir, "core-2")
+	if code, err := get(follower.Client()); err != nil || code != http.StatusOK {
+		t.Errorf("expected replica allowed to replicate, got %d, %v", code, err)
+	}
+
+	// a certificate issued by replica CA which is not a replica certificate
+	pair, err := leader.ca.Issue(pkix.Name{CommonName: pki.NodeCommonName("node-1")}, nil, nil, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, pki.DefaultClientCertValidity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := pki.NewTLSCertificate(pair)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := leader.ClientTLSConfig()
+	config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) { return cert, nil }
+	if code, err := get(&http.Client{Transport: &http.Transport{TLSClientConfig: config}}); err != nil || code != http.StatusForbidden {
+		t.Errorf("expected non replica certificate rejected, got %d, %v", code, err)
+	}
+
+	// client without certificate fail handshake
+	config = leader.ClientTLSConfig()
+	config.GetClientCertificate = nil
+	if _, err := get(&http.Client{Transport: &http.Transport{TLSClientConfig: config}}); err == nil {
+		t.Errorf("expected client without certificate rejected")
+	}
+
+	// replica of other CA neither trust leader nor is trusted by leader
+	stranger := newTestReplicationTLS(t, t.TempDir(), "core-3")
+	if _, err := get(stranger.Client()); err == nil {
+		t.Errorf("expected replica of other CA rejected")
+	}
+}
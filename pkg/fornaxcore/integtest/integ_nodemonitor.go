@@ -59,6 +59,26 @@ func (*integtestNodeMonitor) OnSessionUpdate(message *grpc.FornaxCoreMessage) (*
 	panic("unimplemented")
 }
 
+// OnNodeLeaseRenew implements server.NodeMonitor
+func (*integtestNodeMonitor) OnNodeLeaseRenew(message *grpc.FornaxCoreMessage) (*grpc.FornaxCoreMessage, error) {
+	klog.InfoS("Received a node lease renewal", "node", message.GetNodeIdentifier().GetIdentifier())
+	return nil, nil
+}
+
+// OnNodeConfigProfileStatus implements server.NodeMonitor
+func (*integtestNodeMonitor) OnNodeConfigProfileStatus(message *grpc.FornaxCoreMessage) (*grpc.FornaxCoreMessage, error) {
+	status := message.GetNodeConfigProfileStatus()
+	klog.InfoS("Received a node config profile status", "node", message.GetNodeIdentifier().GetIdentifier(), "profile", status.GetName(), "compliant", status.GetCompliant())
+	return nil, nil
+}
+
+// OnSessionUsage implements server.NodeMonitor
+func (*integtestNodeMonitor) OnSessionUsage(message *grpc.FornaxCoreMessage) (*grpc.FornaxCoreMessage, error) {
+	usage := message.GetSessionUsage()
+	klog.InfoS("Received a session usage", "node", message.GetNodeIdentifier().GetIdentifier(), "session", usage.GetSessionIdentifier(), "cpu", usage.GetCpuUsageNanoSeconds(), "final", usage.GetFinal())
+	return nil, nil
+}
+
 // OnPodUpdate implements server.NodeMonitor
 func (*integtestNodeMonitor) OnPodStateUpdate(message *grpc.FornaxCoreMessage) (*grpc.FornaxCoreMessage, error) {
 	podState := message.GetPodState()
@@ -33,6 +33,8 @@ type PodManagerInterface interface {
 	DeletePod(nodeId string, pod *v1.Pod) (*v1.Pod, error)
 	TerminatePod(podName string) error
 	HibernatePod(podName string) error
+	UpdatePodConfig(podName string, configMap *v1.ConfigMap) error
+	UpdatePodSecret(podName string, secret, previousSecret *fornaxv1.ApplicationSecret) error
 	FindPod(podName string) *v1.Pod
 	Watch(watcher chan<- *PodEvent)
 }
@@ -64,6 +66,12 @@ type NodeManagerInterface interface {
 	CreateNode(nodeId string, node *v1.Node) (*FornaxNodeWithState, error)
 	UpdateNode(nodeId string, node *v1.Node) (*FornaxNodeWithState, error)
 	SetupNode(nodeId string, node *v1.Node) (*FornaxNodeWithState, error)
+	CordonNode(nodeId string, unschedulable bool) error
+	UpdateNodeLabels(nodeId string, labels map[string]string) error
+	RenewNodeLease(nodeId string, renew *grpc.NodeLeaseRenew) (*grpc.FornaxCoreMessage, error)
+	NodeLeaseConfiguration(nodeId string) *grpc.NodeLeaseConfiguration
+	UpdateNodeConfigProfileStatus(nodeId string, status *grpc.NodeConfigProfileStatus)
+	UpdateSessionUsage(nodeId string, usage *grpc.SessionUsage)
 }
 
 // SessionManagerInterface work as a bridge between node agent and fornax core, it call nodeagent to open/close a session
@@ -71,8 +79,17 @@ type NodeManagerInterface interface {
 type SessionManagerInterface interface {
 	UpdateSessionStatus(session *fornaxv1.ApplicationSession, newStatus *fornaxv1.ApplicationSessionStatus) error
 	OnSessionStatusFromNode(nodeId string, pod *v1.Pod, session *fornaxv1.ApplicationSession) error
+	OnSessionUsageFromNode(nodeId string, usage *grpc.SessionUsage)
+	StickyPod(session *fornaxv1.ApplicationSession) string
+	RememberStickyPod(session *fornaxv1.ApplicationSession, podName string)
 	OpenSession(pod *v1.Pod, session *fornaxv1.ApplicationSession) error
+	BindSession(applicationKey string, pod *v1.Pod, session *fornaxv1.ApplicationSession) error
 	CloseSession(pod *v1.Pod, session *fornaxv1.ApplicationSession) error
+	DrainSession(pod *v1.Pod, session *fornaxv1.ApplicationSession, reason string, deadline time.Time) error
+	MigrateSession(pod *v1.Pod, session *fornaxv1.ApplicationSession, targetPod string) error
+	UpdateSessionMigration(session *fornaxv1.ApplicationSession, migration *fornaxv1.SessionMigration) error
+	ListPodSessions(podName string) ([]*fornaxv1.ApplicationSession, error)
+	ListApplicationSessions(applicationKey string) ([]*fornaxv1.ApplicationSession, error)
 	Watch(ctx context.Context) (<-chan fornaxstore.WatchEventWithOldObj, error)
 }
 
@@ -96,4 +113,7 @@ type NodeMonitorInterface interface {
 	OnNodeStateUpdate(message *grpc.FornaxCoreMessage) (*grpc.FornaxCoreMessage, error)
 	OnPodStateUpdate(message *grpc.FornaxCoreMessage) (*grpc.FornaxCoreMessage, error)
 	OnSessionUpdate(message *grpc.FornaxCoreMessage) (*grpc.FornaxCoreMessage, error)
+	OnNodeLeaseRenew(message *grpc.FornaxCoreMessage) (*grpc.FornaxCoreMessage, error)
+	OnNodeConfigProfileStatus(message *grpc.FornaxCoreMessage) (*grpc.FornaxCoreMessage, error)
+	OnSessionUsage(message *grpc.FornaxCoreMessage) (*grpc.FornaxCoreMessage, error)
 }
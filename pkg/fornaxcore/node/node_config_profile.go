@@ -0,0 +1,232 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc"
+	ie "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/internal"
+	fornaxstore "centaurusinfra.io/fornax-serverless/pkg/store"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultNodeConfigProfileSyncPeriod = 5 * time.Second
+
+	// profile is sent again if node does not report compliance of it in this period, e.g. node agent restarted
+	DefaultNodeConfigProfileResendPeriod = 30 * time.Second
+)
+
+// nodeProfileAssignment is profile sent to a node and last compliance node reported
+type nodeProfileAssignment struct {
+	name       string
+	generation int64
+	sentTime   time.Time
+	report     *grpc.NodeConfigProfileStatus
+	reportTime time.Time
+}
+
+func (a *nodeProfileAssignment) reported() bool {
+	return a.report != nil && a.report.GetName() == a.name && a.report.GetGeneration() == a.generation
+}
+
+// NodeConfigProfileController assign a node config profile to every node selected by profiles, node agents reconcile host configuration of profile,
+// compliance reported by nodes is aggregated in profile status, a node selected by no profile keep its host configuration
+type NodeConfigProfileController struct {
+	ctx         context.Context
+	mu          sync.Mutex
+	nodeManager *nodeManager
+	store       fornaxstore.ApiStorageInterface
+	assignments map[string]*nodeProfileAssignment
+}
+
+func NewNodeConfigProfileController(ctx context.Context, nodeManager *nodeManager, store fornaxstore.ApiStorageInterface) *NodeConfigProfileController {
+	c := &NodeConfigProfileController{
+		ctx:         ctx,
+		nodeManager: nodeManager,
+		store:       store,
+		assignments: map[string]*nodeProfileAssignment{},
+	}
+	nodeManager.profileController = c
+	return c
+}
+
+func (c *NodeConfigProfileController) Run() {
+	klog.Info("Starting node config profile controller")
+	go func() {
+		ticker := time.NewTicker(DefaultNodeConfigProfileSyncPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+				c.syncAll()
+			}
+		}
+	}()
+}
+
+// Report record compliance reported by node, it's applied to profile status in next sync
+func (c *NodeConfigProfileController) Report(nodeId string, status *grpc.NodeConfigProfileStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	a, found := c.assignments[nodeId]
+	if !found {
+		return
+	}
+	klog.InfoS("Node reported config profile compliance", "node", nodeId, "profile", status.GetName(), "generation", status.GetGeneration(), "compliant", status.GetCompliant())
+	a.report = status
+	a.reportTime = time.Now()
+}
+
+// selectProfile return profile with highest priority selecting node, profiles are sorted by priority and name
+func selectProfile(profiles []*fornaxv1.NodeConfigProfile, selectors []labels.Selector, node *ie.FornaxNodeWithState) *fornaxv1.NodeConfigProfile {
+	for i, v := range profiles {
+		if selectors[i].Matches(labels.Set(node.Node.Labels)) {
+			return v
+		}
+	}
+	return nil
+}
+
+func (c *NodeConfigProfileController) sendProfile(nodeId string, profile *fornaxv1.NodeConfigProfile) error {
+	message := &grpc.NodeConfigProfile{}
+	if profile != nil {
+		spec, err := json.Marshal(profile.Spec)
+		if err != nil {
+			return err
+		}
+		message = &grpc.NodeConfigProfile{
+			Name:       profile.Name,
+			Generation: profile.Generation,
+			Spec:       spec,
+		}
+	}
+	return c.nodeManager.nodeAgent.DispatchNodeMessage(nodeId, &grpc.FornaxCoreMessage{
+		MessageType: grpc.MessageType_NODE_CONFIG_PROFILE,
+		MessageBody: &grpc.FornaxCoreMessage_NodeConfigProfile{
+			NodeConfigProfile: message,
+		},
+	})
+}
+
+func (c *NodeConfigProfileController) syncAll() {
+	profileList := &fornaxv1.NodeConfigProfileList{}
+	if err := c.store.GetList(c.ctx, fornaxv1.NodeConfigProfileGrvKey, apistorage.ListOptions{Predicate: apistorage.Everything, Recursive: true}, profileList); err != nil {
+		klog.ErrorS(err, "Failed to list node config profiles")
+		return
+	}
+	profiles, selectors := []*fornaxv1.NodeConfigProfile{}, []labels.Selector{}
+	for i := range profileList.Items {
+		profile := &profileList.Items[i]
+		if profile.DeletionTimestamp != nil {
+			continue
+		}
+		profiles = append(profiles, profile)
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		if profiles[i].Spec.Priority != profiles[j].Spec.Priority {
+			return profiles[i].Spec.Priority > profiles[j].Spec.Priority
+		}
+		return profiles[i].Name < profiles[j].Name
+	})
+	for _, v := range profiles {
+		selector := labels.Everything()
+		if v.Spec.NodeSelector != nil {
+			var err error
+			if selector, err = metav1.LabelSelectorAsSelector(v.Spec.NodeSelector); err != nil {
+				klog.ErrorS(err, "Invalid node selector of node config profile", "profile", v.Name)
+				selector = labels.Nothing()
+			}
+		}
+		selectors = append(selectors, selector)
+	}
+
+	nodes := c.nodeManager.nodes.list()
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].NodeId < nodes[j].NodeId })
+	statuses := map[string]*fornaxv1.NodeConfigProfileStatus{}
+	for _, v := range profiles {
+		statuses[v.Name] = &fornaxv1.NodeConfigProfileStatus{Nodes: []fornaxv1.NodeConfigProfileNodeStatus{}}
+	}
+	c.mu.Lock()
+	for _, node := range nodes {
+		profile := selectProfile(profiles, selectors, node)
+		a, assigned := c.assignments[node.NodeId]
+		if profile == nil {
+			if assigned && node.State == ie.NodeWorkingStateRunning {
+				klog.InfoS("Node is not selected by any node config profile, clear its profile", "node", node.NodeId, "profile", a.name)
+				if err := c.sendProfile(node.NodeId, nil); err == nil {
+					delete(c.assignments, node.NodeId)
+				}
+			}
+			continue
+		}
+
+		if !assigned || a.name != profile.Name || a.generation != profile.Generation {
+			a = &nodeProfileAssignment{name: profile.Name, generation: profile.Generation}
+			c.assignments[node.NodeId] = a
+		}
+		if node.State == ie.NodeWorkingStateRunning && !a.reported() && time.Since(a.sentTime) > DefaultNodeConfigProfileResendPeriod {
+			klog.InfoS("Assign node config profile to node", "node", node.NodeId, "profile", profile.Name, "generation", profile.Generation)
+			if err := c.sendProfile(node.NodeId, profile); err != nil {
+				klog.ErrorS(err, "Failed to send node config profile to node", "node", node.NodeId, "profile", profile.Name)
+			} else {
+				a.sentTime = time.Now()
+			}
+		}
+
+		status := statuses[profile.Name]
+		nodeStatus := fornaxv1.NodeConfigProfileNodeStatus{NodeName: node.NodeId}
+		if a.report != nil && a.report.GetName() == profile.Name {
+			nodeStatus.Compliant = a.reported() && a.report.GetCompliant()
+			nodeStatus.ObservedGeneration = a.report.GetGeneration()
+			nodeStatus.Drifts = a.report.GetDrifts()
+			nodeStatus.LastReportTime = &metav1.Time{Time: a.reportTime.Truncate(time.Second)}
+		}
+		status.MatchedNodes += 1
+		if nodeStatus.Compliant {
+			status.CompliantNodes += 1
+		}
+		status.Nodes = append(status.Nodes, nodeStatus)
+	}
+	c.mu.Unlock()
+
+	for _, profile := range profiles {
+		newStatus := statuses[profile.Name]
+		if reflect.DeepEqual(&profile.Status, newStatus) || (len(profile.Status.Nodes) == 0 && len(newStatus.Nodes) == 0) {
+			continue
+		}
+		updated := profile.DeepCopy()
+		updated.Status = *newStatus
+		key := fmt.Sprintf("%s/%s", fornaxv1.NodeConfigProfileGrvKey, profile.Name)
+		if err := c.store.GuaranteedUpdate(c.ctx, key, &fornaxv1.NodeConfigProfile{}, false, nil, fornaxstore.GetTryUpdateFunc(updated), nil); err != nil {
+			klog.ErrorS(err, "Failed to update node config profile status", "profile", profile.Name)
+		}
+	}
+}
@@ -0,0 +1,375 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/event"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc/nodeagent"
+	fornaxpod "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/pod"
+	fornaxstore "centaurusinfra.io/fornax-serverless/pkg/store"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultNodeLeaseSyncPeriod           = 1 * time.Second
+	DefaultNodeLeaseDurationSeconds      = 40
+	DefaultNodeLeaseRenewIntervalSeconds = 10
+	DefaultNodeLeaseEvictionGraceSeconds = 20
+)
+
+var (
+	InvalidNodeLeaseConfigurationError = errors.New("node lease renew interval must be positive and less than lease duration, eviction grace must not be negative")
+)
+
+var (
+	nodeLeaseEvictions = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_node_lease",
+			Name:           "evictions_total",
+			Help:           "Number of times pods on a node were evicted after node lease expired",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+	nodeLeaseExpirations = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_node_lease",
+			Name:           "expirations_total",
+			Help:           "Number of times a node did not renew its lease in lease duration",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(nodeLeaseEvictions, nodeLeaseExpirations)
+}
+
+// NodeLeaseConfiguration is default lease spec of nodes, a NodeLease spec override it for its node
+type NodeLeaseConfiguration struct {
+	LeaseDurationSeconds int32 `json:"leaseDurationSeconds,omitempty"`
+	RenewIntervalSeconds int32 `json:"renewIntervalSeconds,omitempty"`
+	EvictionGraceSeconds int32 `json:"evictionGraceSeconds,omitempty"`
+}
+
+func DefaultNodeLeaseConfiguration() *NodeLeaseConfiguration {
+	return &NodeLeaseConfiguration{
+		LeaseDurationSeconds: DefaultNodeLeaseDurationSeconds,
+		RenewIntervalSeconds: DefaultNodeLeaseRenewIntervalSeconds,
+		EvictionGraceSeconds: DefaultNodeLeaseEvictionGraceSeconds,
+	}
+}
+
+// LoadNodeLeaseConfiguration read node lease configuration from a json file, default configuration is returned if file does not exist,
+// fields missing in file keep their default value
+func LoadNodeLeaseConfiguration(file string) (*NodeLeaseConfiguration, error) {
+	config := DefaultNodeLeaseConfiguration()
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	if config.RenewIntervalSeconds <= 0 || config.LeaseDurationSeconds <= config.RenewIntervalSeconds || config.EvictionGraceSeconds < 0 {
+		return nil, InvalidNodeLeaseConfigurationError
+	}
+	return config, nil
+}
+
+// nodeLease is lease of a node tracked in memory, it's persisted as NodeLease in sync loop
+type nodeLease struct {
+	spec      fornaxv1.NodeLeaseSpec
+	status    fornaxv1.NodeLeaseStatus
+	renewTime time.Time
+	// pods evicted when lease expired, they are terminated if node come back
+	evictedPods []*v1.Pod
+}
+
+// NodeLeaseController track lease renewals of nodes, a node is not schedulable when it miss renewals longer than lease duration,
+// pods on it are evicted after eviction grace period, application manager close their sessions and create pods on other nodes,
+// nodes never renewed a lease since fornaxcore started are not tracked
+type NodeLeaseController struct {
+	ctx         context.Context
+	mu          sync.Mutex
+	nodeManager *nodeManager
+	store       fornaxstore.ApiStorageInterface
+	config      *NodeLeaseConfiguration
+	leases      map[string]*nodeLease
+}
+
+func NewNodeLeaseController(ctx context.Context, nodeManager *nodeManager, store fornaxstore.ApiStorageInterface, config *NodeLeaseConfiguration) *NodeLeaseController {
+	c := &NodeLeaseController{
+		ctx:         ctx,
+		nodeManager: nodeManager,
+		store:       store,
+		config:      config,
+		leases:      map[string]*nodeLease{},
+	}
+	nodeManager.leaseController = c
+	return c
+}
+
+func (c *NodeLeaseController) Run() {
+	klog.Info("Starting node lease controller")
+	go func() {
+		ticker := time.NewTicker(DefaultNodeLeaseSyncPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+				c.syncAll()
+			}
+		}
+	}()
+}
+
+// leaseSpec fill fields not set in NodeLease spec with default configuration
+func (c *NodeLeaseController) leaseSpec(nodeId string, spec fornaxv1.NodeLeaseSpec) fornaxv1.NodeLeaseSpec {
+	spec.NodeName = nodeId
+	if spec.LeaseDurationSeconds == 0 {
+		spec.LeaseDurationSeconds = c.config.LeaseDurationSeconds
+	}
+	if spec.RenewIntervalSeconds == 0 {
+		spec.RenewIntervalSeconds = c.config.RenewIntervalSeconds
+	}
+	if spec.EvictionGraceSeconds == 0 {
+		spec.EvictionGraceSeconds = c.config.EvictionGraceSeconds
+	}
+	if spec.RenewIntervalSeconds >= spec.LeaseDurationSeconds {
+		spec.RenewIntervalSeconds = spec.LeaseDurationSeconds / 2
+	}
+	if spec.RenewIntervalSeconds == 0 {
+		spec.RenewIntervalSeconds = 1
+	}
+	return spec
+}
+
+func leaseConfiguration(spec fornaxv1.NodeLeaseSpec) *grpc.NodeLeaseConfiguration {
+	return &grpc.NodeLeaseConfiguration{
+		LeaseDurationSeconds: spec.LeaseDurationSeconds,
+		RenewIntervalSeconds: spec.RenewIntervalSeconds,
+	}
+}
+
+// LeaseConfiguration return lease configuration sent to node in node configuration
+func (c *NodeLeaseController) LeaseConfiguration(nodeId string) *grpc.NodeLeaseConfiguration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if lease, found := c.leases[nodeId]; found {
+		return leaseConfiguration(lease.spec)
+	}
+	return leaseConfiguration(c.leaseSpec(nodeId, fornaxv1.NodeLeaseSpec{}))
+}
+
+// Renew record a lease renewal of node, a lease configuration is sent back if node renew at a different interval,
+// a expired or evicted node is asked a full sync to report its state again, pods evicted from it are terminated
+func (c *NodeLeaseController) Renew(nodeId string, renew *grpc.NodeLeaseRenew) (*grpc.FornaxCoreMessage, error) {
+	if c.nodeManager.nodes.get(nodeId) == nil {
+		return nil, nodeagent.NodeRevisionOutOfOrderError
+	}
+
+	c.mu.Lock()
+	now := time.Now()
+	lease, found := c.leases[nodeId]
+	if !found {
+		klog.InfoS("Node acquired lease", "node", nodeId)
+		lease = &nodeLease{
+			spec: c.leaseSpec(nodeId, fornaxv1.NodeLeaseSpec{}),
+			status: fornaxv1.NodeLeaseStatus{
+				Phase:       fornaxv1.NodeLeasePhaseActive,
+				AcquireTime: &metav1.Time{Time: now},
+			},
+		}
+		c.leases[nodeId] = lease
+	}
+	lease.renewTime = now
+	lease.status.RenewTime = &metav1.Time{Time: now}
+	lease.status.Renewals += 1
+	lease.status.MissedRenewals = 0
+
+	var err error
+	evictedPods := []*v1.Pod{}
+	if lease.status.Phase != fornaxv1.NodeLeasePhaseActive {
+		klog.InfoS("Node renewed lease after it expired, ask node full sync", "node", nodeId, "phase", lease.status.Phase, "evicted-pods", len(lease.evictedPods))
+		evictedPods = lease.evictedPods
+		lease.evictedPods = nil
+		lease.status.Phase = fornaxv1.NodeLeasePhaseActive
+		lease.status.ExpireTime = nil
+		err = nodeagent.NodeRevisionOutOfOrderError
+	}
+
+	var reply *grpc.FornaxCoreMessage
+	if renew.GetRenewIntervalSeconds() != lease.spec.RenewIntervalSeconds {
+		reply = &grpc.FornaxCoreMessage{
+			MessageType: grpc.MessageType_NODE_LEASE_CONFIGURATION,
+			MessageBody: &grpc.FornaxCoreMessage_NodeLeaseConfiguration{
+				NodeLeaseConfiguration: leaseConfiguration(lease.spec),
+			},
+		}
+	}
+	c.mu.Unlock()
+
+	// evicted pods were recreated on other nodes, node should not keep running them
+	for _, pod := range evictedPods {
+		if e := c.nodeManager.nodeAgent.TerminatePod(nodeId, pod); e != nil {
+			klog.ErrorS(e, "Failed to terminate evicted pod", "node", nodeId, "pod", util.Name(pod))
+		}
+	}
+	return reply, err
+}
+
+// checkLease expire lease of a node missed renewals longer than lease duration, and evict pods on node after eviction grace period
+func (c *NodeLeaseController) checkLease(nodeId string, lease *nodeLease, now time.Time) {
+	sinceRenew := now.Sub(lease.renewTime)
+	lease.status.MissedRenewals = int32(sinceRenew / (time.Duration(lease.spec.RenewIntervalSeconds) * time.Second))
+	duration := time.Duration(lease.spec.LeaseDurationSeconds) * time.Second
+	grace := time.Duration(lease.spec.EvictionGraceSeconds) * time.Second
+	switch lease.status.Phase {
+	case fornaxv1.NodeLeasePhaseActive:
+		if sinceRenew <= duration {
+			return
+		}
+		klog.InfoS("Node lease expired, mark node disconnected", "node", nodeId, "renew-time", lease.renewTime, "missed-renewals", lease.status.MissedRenewals)
+		nodeLeaseExpirations.Inc()
+		lease.status.Phase = fornaxv1.NodeLeasePhaseExpired
+		lease.status.ExpireTime = &metav1.Time{Time: now}
+		c.nodeManager.DisconnectNode(nodeId)
+		event.Eventf(event.NodeRef(nodeId), fornaxv1.FornaxEventTypeWarning, event.SourceNodeLease, "NodeLost", "Node did not renew lease in %s, node is not schedulable", duration)
+	case fornaxv1.NodeLeasePhaseExpired:
+		if sinceRenew <= duration+grace {
+			return
+		}
+		lease.evictedPods = c.nodeManager.evictNodePods(nodeId)
+		klog.InfoS("Node lease expired longer than eviction grace period, evicted pods on node", "node", nodeId, "pods", len(lease.evictedPods))
+		event.Eventf(event.NodeRef(nodeId), fornaxv1.FornaxEventTypeWarning, event.SourceNodeLease, "NodeEvicted", "Evicted %d pods on node lost longer than eviction grace period", len(lease.evictedPods))
+		nodeLeaseEvictions.Inc()
+		lease.status.Phase = fornaxv1.NodeLeasePhaseEvicted
+		lease.status.EvictionTime = &metav1.Time{Time: now}
+		lease.status.EvictedPods = int32(len(lease.evictedPods))
+	}
+}
+
+func (c *NodeLeaseController) syncAll() {
+	leaseList := &fornaxv1.NodeLeaseList{}
+	if err := c.store.GetList(c.ctx, fornaxv1.NodeLeaseGrvKey, apistorage.ListOptions{Predicate: apistorage.Everything, Recursive: true}, leaseList); err != nil {
+		klog.ErrorS(err, "Failed to list node leases")
+		return
+	}
+	stored := map[string]*fornaxv1.NodeLease{}
+	for i := range leaseList.Items {
+		stored[leaseList.Items[i].Name] = &leaseList.Items[i]
+	}
+
+	creates, updates := []*fornaxv1.NodeLease{}, []*fornaxv1.NodeLease{}
+	c.mu.Lock()
+	now := time.Now()
+	for nodeId, lease := range c.leases {
+		obj, found := stored[nodeId]
+		if found {
+			// spec of lease object override default configuration
+			lease.spec = c.leaseSpec(nodeId, obj.Spec)
+		}
+		c.checkLease(nodeId, lease, now)
+		if !found {
+			creates = append(creates, &fornaxv1.NodeLease{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "NodeLease",
+					APIVersion: fornaxv1.SchemeGroupVersion.String(),
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              nodeId,
+					CreationTimestamp: metav1.Time{Time: now},
+				},
+				Spec:   fornaxv1.NodeLeaseSpec{NodeName: nodeId},
+				Status: *lease.status.DeepCopy(),
+			})
+		} else if obj.DeletionTimestamp == nil && !reflect.DeepEqual(&obj.Status, &lease.status) {
+			updated := obj.DeepCopy()
+			updated.Status = *lease.status.DeepCopy()
+			updates = append(updates, updated)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, v := range creates {
+		key := fmt.Sprintf("%s/%s", fornaxv1.NodeLeaseGrvKey, v.Name)
+		if err := c.store.Create(c.ctx, key, v, &fornaxv1.NodeLease{}, uint64(0)); err != nil && !apistorage.IsExist(err) {
+			klog.ErrorS(err, "Failed to create node lease", "node", v.Name)
+		}
+	}
+	for _, v := range updates {
+		key := fmt.Sprintf("%s/%s", fornaxv1.NodeLeaseGrvKey, v.Name)
+		if err := c.store.GuaranteedUpdate(c.ctx, key, &fornaxv1.NodeLease{}, false, nil, fornaxstore.GetTryUpdateFunc(v), nil); err != nil {
+			klog.ErrorS(err, "Failed to update node lease status", "node", v.Name)
+		}
+	}
+}
+
+// evictNodePods delete application pods of a node from pod manager as failed pods, application manager clean up their sessions
+// and create pods on other nodes, daemon pods are kept, return evicted pods
+func (nm *nodeManager) evictNodePods(nodeId string) []*v1.Pod {
+	nodeWS := nm.nodes.get(nodeId)
+	if nodeWS == nil {
+		return nil
+	}
+	evicted := []*v1.Pod{}
+	for _, podName := range nodeWS.Pods.GetKeys() {
+		if _, found := nodeWS.DaemonPods[podName]; found {
+			continue
+		}
+		pod := nm.podManager.FindPod(podName)
+		if pod == nil {
+			nodeWS.Pods.Delete(podName)
+			continue
+		}
+		if _, found := pod.Labels[fornaxv1.LabelFornaxCoreNodeDaemon]; found {
+			continue
+		}
+		evicted = append(evicted, pod.DeepCopy())
+		if util.PodNotTerminated(pod) {
+			pod.Status.Phase = v1.PodFailed
+		}
+		if _, err := nm.podManager.DeletePod(nodeId, pod); err != nil && err != fornaxpod.PodNotFoundError {
+			klog.ErrorS(err, "Failed to evict a pod", "node", nodeId, "pod", podName)
+			continue
+		}
+		nodeWS.Pods.Delete(podName)
+	}
+	return evicted
+}
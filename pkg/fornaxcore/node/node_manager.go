@@ -52,6 +52,8 @@ type nodeManager struct {
 	nodePodCidrManager NodeCidrManager
 	nodeDaemonManager  NodeDaemonManager
 	houseKeepingTicker *time.Ticker
+	leaseController    *NodeLeaseController
+	profileController  *NodeConfigProfileController
 }
 
 // UpdateSessionState implements NodeManagerInterface
@@ -66,6 +68,11 @@ func (nm *nodeManager) UpdateSessionState(nodeIdentifier string, session *fornax
 	return nil
 }
 
+// UpdateSessionUsage implements NodeManagerInterface
+func (nm *nodeManager) UpdateSessionUsage(nodeId string, usage *grpc.SessionUsage) {
+	nm.sessionManager.OnSessionUsageFromNode(nodeId, usage)
+}
+
 // Watch add a watcher, and beging to send NodeEvent to watcher
 func (nm *nodeManager) Watch(watcher chan<- *ie.NodeEvent) {
 	nm.watchers = append(nm.watchers, watcher)
@@ -280,6 +287,77 @@ func (nm *nodeManager) DisconnectNode(nodeId string) error {
 	return nil
 }
 
+// RenewNodeLease implements NodeManagerInterface
+func (nm *nodeManager) RenewNodeLease(nodeId string, renew *grpc.NodeLeaseRenew) (*grpc.FornaxCoreMessage, error) {
+	if nm.leaseController == nil {
+		return nil, nil
+	}
+	return nm.leaseController.Renew(nodeId, renew)
+}
+
+// NodeLeaseConfiguration implements NodeManagerInterface, nil if node lease is not enabled
+func (nm *nodeManager) NodeLeaseConfiguration(nodeId string) *grpc.NodeLeaseConfiguration {
+	if nm.leaseController == nil {
+		return nil
+	}
+	return nm.leaseController.LeaseConfiguration(nodeId)
+}
+
+// UpdateNodeConfigProfileStatus implements NodeManagerInterface
+func (nm *nodeManager) UpdateNodeConfigProfileStatus(nodeId string, status *grpc.NodeConfigProfileStatus) {
+	if nm.profileController == nil {
+		return
+	}
+	nm.profileController.Report(nodeId, status)
+}
+
+// CordonNode mark node unschedulable or schedulable, node spec is kept when node agent report status,
+// scheduler does not put new pods on unschedulable node
+func (nm *nodeManager) CordonNode(nodeId string, unschedulable bool) error {
+	nodeWS := nm.nodes.get(nodeId)
+	if nodeWS == nil {
+		return nodeagent.NodeNotFoundError
+	}
+	if nodeWS.Node.Spec.Unschedulable == unschedulable {
+		return nil
+	}
+	klog.InfoS("Cordon node", "node", nodeId, "unschedulable", unschedulable)
+	nodeWS.Node.Spec.Unschedulable = unschedulable
+	nm.nodeUpdates <- &ie.NodeEvent{
+		NodeId: nodeId,
+		Node:   nodeWS.Node.DeepCopy(),
+		Type:   ie.NodeEventTypeUpdate,
+	}
+	return nil
+}
+
+// UpdateNodeLabels set labels of node, a label with empty value is removed
+func (nm *nodeManager) UpdateNodeLabels(nodeId string, labels map[string]string) error {
+	nodeWS := nm.nodes.get(nodeId)
+	if nodeWS == nil {
+		return nodeagent.NodeNotFoundError
+	}
+	node := nodeWS.Node.DeepCopy()
+	if node.Labels == nil {
+		node.Labels = map[string]string{}
+	}
+	for k, v := range labels {
+		if len(v) == 0 {
+			delete(node.Labels, k)
+		} else {
+			node.Labels[k] = v
+		}
+	}
+	klog.InfoS("Update node labels", "node", nodeId, "labels", labels)
+	nodeWS.Node = node
+	nm.nodeUpdates <- &ie.NodeEvent{
+		NodeId: nodeId,
+		Node:   node.DeepCopy(),
+		Type:   ie.NodeEventTypeUpdate,
+	}
+	return nil
+}
+
 func (nm *nodeManager) Run() error {
 	klog.Info("starting node manager")
 	go func() {
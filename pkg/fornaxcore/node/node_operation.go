@@ -0,0 +1,296 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	fornaxstore "centaurusinfra.io/fornax-serverless/pkg/store"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultNodeOperationSyncPeriod  = 2 * time.Second
+	DefaultNodeOperationBatchSize   = 1
+	DefaultNodeOperationNodeTimeout = 300 * time.Second
+)
+
+var (
+	NodeOperationNodeNotFoundError    = errors.New("node not found")
+	NodeOperationDrainTimeoutError    = errors.New("pods are not terminated before timeout")
+	NodeOperationTooManyFailuresError = errors.New("too many failed nodes")
+)
+
+// NodeOperationController run NodeOperations batch by batch, a batch start when previous batch is done and batch interval passed,
+// operation stop starting new batch and fail when failed nodes exceed max failed nodes
+type NodeOperationController struct {
+	ctx         context.Context
+	nodeManager *nodeManager
+	store       fornaxstore.ApiStorageInterface
+}
+
+func NewNodeOperationController(ctx context.Context, nodeManager *nodeManager, store fornaxstore.ApiStorageInterface) *NodeOperationController {
+	return &NodeOperationController{
+		ctx:         ctx,
+		nodeManager: nodeManager,
+		store:       store,
+	}
+}
+
+func (c *NodeOperationController) Run() {
+	klog.Info("Starting node operation controller")
+	go func() {
+		ticker := time.NewTicker(DefaultNodeOperationSyncPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+				c.syncAll()
+			}
+		}
+	}()
+}
+
+func (c *NodeOperationController) syncAll() {
+	operations := &fornaxv1.NodeOperationList{}
+	if err := c.store.GetList(c.ctx, fornaxv1.NodeOperationGrvKey, apistorage.ListOptions{Predicate: apistorage.Everything, Recursive: true}, operations); err != nil {
+		klog.ErrorS(err, "Failed to list node operations")
+		return
+	}
+	for i := range operations.Items {
+		operation := &operations.Items[i]
+		if operation.DeletionTimestamp != nil || operation.Status.Phase == fornaxv1.NodeOperationPhaseSucceeded || operation.Status.Phase == fornaxv1.NodeOperationPhaseFailed {
+			continue
+		}
+		newStatus := c.syncOperation(operation)
+		if reflect.DeepEqual(&operation.Status, newStatus) {
+			continue
+		}
+		updated := operation.DeepCopy()
+		updated.Status = *newStatus
+		out := &fornaxv1.NodeOperation{}
+		key := fmt.Sprintf("%s/%s", fornaxv1.NodeOperationGrvKey, operation.Name)
+		if err := c.store.GuaranteedUpdate(c.ctx, key, out, false, nil, fornaxstore.GetTryUpdateFunc(updated), nil); err != nil {
+			klog.ErrorS(err, "Failed to update node operation status", "operation", operation.Name)
+		}
+	}
+}
+
+// selectNodes return names of nodes matching operation node selector sorted by name
+func (c *NodeOperationController) selectNodes(operation *fornaxv1.NodeOperation) ([]string, error) {
+	selector := labels.Everything()
+	if operation.Spec.NodeSelector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(operation.Spec.NodeSelector); err != nil {
+			return nil, err
+		}
+	}
+	names := []string{}
+	for _, v := range c.nodeManager.nodes.list() {
+		if selector.Matches(labels.Set(v.Node.Labels)) {
+			names = append(names, v.NodeId)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (c *NodeOperationController) syncOperation(operation *fornaxv1.NodeOperation) *fornaxv1.NodeOperationStatus {
+	status := operation.Status.DeepCopy()
+	now := metav1.Now()
+	if len(status.Phase) == 0 || status.Phase == fornaxv1.NodeOperationPhasePending {
+		nodeNames, err := c.selectNodes(operation)
+		if err != nil {
+			status.Phase = fornaxv1.NodeOperationPhaseFailed
+			status.Message = err.Error()
+			status.CompletionTime = &now
+			return status
+		}
+		klog.InfoS("Starting node operation", "operation", operation.Name, "type", operation.Spec.Type, "nodes", len(nodeNames))
+		status.Phase = fornaxv1.NodeOperationPhaseRunning
+		status.StartTime = &now
+		status.Nodes = []fornaxv1.NodeOperationNodeStatus{}
+		for _, v := range nodeNames {
+			status.Nodes = append(status.Nodes, fornaxv1.NodeOperationNodeStatus{NodeName: v, Phase: fornaxv1.NodeOperationPhasePending})
+		}
+	}
+
+	timeout := DefaultNodeOperationNodeTimeout
+	if operation.Spec.NodeTimeoutSeconds > 0 {
+		timeout = time.Duration(operation.Spec.NodeTimeoutSeconds) * time.Second
+	}
+	running, pending := 0, []int{}
+	var lastCompletion time.Time
+	for i := range status.Nodes {
+		nodeStatus := &status.Nodes[i]
+		if nodeStatus.Phase == fornaxv1.NodeOperationPhaseRunning {
+			c.checkNode(operation, nodeStatus, timeout)
+		}
+		switch nodeStatus.Phase {
+		case fornaxv1.NodeOperationPhaseRunning:
+			running += 1
+		case fornaxv1.NodeOperationPhasePending:
+			pending = append(pending, i)
+		}
+		if nodeStatus.CompletionTime != nil && nodeStatus.CompletionTime.Time.After(lastCompletion) {
+			lastCompletion = nodeStatus.CompletionTime.Time
+		}
+	}
+	c.countNodes(status)
+
+	if status.FailedNodes > operation.Spec.MaxFailedNodes {
+		status.Phase = fornaxv1.NodeOperationPhaseFailed
+		status.Message = NodeOperationTooManyFailuresError.Error()
+		status.CompletionTime = &now
+		klog.InfoS("Node operation failed", "operation", operation.Name, "failed", status.FailedNodes)
+		return status
+	}
+	if running > 0 {
+		return status
+	}
+	if len(pending) == 0 {
+		status.Phase = fornaxv1.NodeOperationPhaseSucceeded
+		status.CompletionTime = &now
+		klog.InfoS("Node operation succeeded", "operation", operation.Name, "nodes", len(status.Nodes))
+		return status
+	}
+	if !lastCompletion.IsZero() && time.Since(lastCompletion) < time.Duration(operation.Spec.BatchIntervalSeconds)*time.Second {
+		return status
+	}
+
+	// start next batch
+	batchSize := DefaultNodeOperationBatchSize
+	if operation.Spec.BatchSize > 0 {
+		batchSize = int(operation.Spec.BatchSize)
+	}
+	if batchSize > len(pending) {
+		batchSize = len(pending)
+	}
+	for _, i := range pending[:batchSize] {
+		nodeStatus := &status.Nodes[i]
+		nodeStatus.Phase = fornaxv1.NodeOperationPhaseRunning
+		nodeStatus.StartTime = &now
+		c.startNode(operation, nodeStatus)
+		c.checkNode(operation, nodeStatus, timeout)
+	}
+	c.countNodes(status)
+	return status
+}
+
+func (c *NodeOperationController) countNodes(status *fornaxv1.NodeOperationStatus) {
+	status.SucceededNodes, status.FailedNodes = 0, 0
+	for _, v := range status.Nodes {
+		switch v.Phase {
+		case fornaxv1.NodeOperationPhaseSucceeded:
+			status.SucceededNodes += 1
+		case fornaxv1.NodeOperationPhaseFailed:
+			status.FailedNodes += 1
+		}
+	}
+}
+
+func (c *NodeOperationController) finishNode(nodeStatus *fornaxv1.NodeOperationNodeStatus, err error) {
+	now := metav1.Now()
+	nodeStatus.CompletionTime = &now
+	if err != nil {
+		nodeStatus.Phase = fornaxv1.NodeOperationPhaseFailed
+		nodeStatus.Message = err.Error()
+	} else {
+		nodeStatus.Phase = fornaxv1.NodeOperationPhaseSucceeded
+	}
+}
+
+// startNode apply operation on a node, operation finish immediately except drain which wait for pods terminated
+func (c *NodeOperationController) startNode(operation *fornaxv1.NodeOperation, nodeStatus *fornaxv1.NodeOperationNodeStatus) {
+	nodeId := nodeStatus.NodeName
+	klog.InfoS("Starting node operation on node", "operation", operation.Name, "type", operation.Spec.Type, "node", nodeId)
+	var err error
+	switch operation.Spec.Type {
+	case fornaxv1.NodeOperationTypeCordon:
+		err = c.nodeManager.CordonNode(nodeId, true)
+	case fornaxv1.NodeOperationTypeUncordon:
+		err = c.nodeManager.CordonNode(nodeId, false)
+	case fornaxv1.NodeOperationTypeReconfigure:
+		err = c.nodeManager.UpdateNodeLabels(nodeId, operation.Spec.Labels)
+	case fornaxv1.NodeOperationTypeDrain:
+		if err = c.nodeManager.CordonNode(nodeId, true); err == nil {
+			for _, podName := range c.podsToDrain(nodeId) {
+				if err := c.nodeManager.podManager.TerminatePod(podName); err != nil {
+					klog.ErrorS(err, "Failed to terminate pod when draining node", "node", nodeId, "pod", podName)
+				}
+			}
+			// drain is finished when all pods are gone
+			return
+		}
+	default:
+		err = fmt.Errorf("unsupported node operation %s", operation.Spec.Type)
+	}
+	c.finishNode(nodeStatus, err)
+}
+
+// checkNode check if a running drain is done or timed out
+func (c *NodeOperationController) checkNode(operation *fornaxv1.NodeOperation, nodeStatus *fornaxv1.NodeOperationNodeStatus, timeout time.Duration) {
+	if nodeStatus.Phase != fornaxv1.NodeOperationPhaseRunning {
+		return
+	}
+	if c.nodeManager.nodes.get(nodeStatus.NodeName) == nil {
+		c.finishNode(nodeStatus, NodeOperationNodeNotFoundError)
+		return
+	}
+	if len(c.podsToDrain(nodeStatus.NodeName)) == 0 {
+		c.finishNode(nodeStatus, nil)
+		return
+	}
+	if nodeStatus.StartTime != nil && time.Since(nodeStatus.StartTime.Time) > timeout {
+		c.finishNode(nodeStatus, NodeOperationDrainTimeoutError)
+	}
+}
+
+// podsToDrain return application pods on node which are not terminated, daemon pods are kept
+func (c *NodeOperationController) podsToDrain(nodeId string) []string {
+	nodeWS := c.nodeManager.nodes.get(nodeId)
+	if nodeWS == nil {
+		return []string{}
+	}
+	pods := []string{}
+	for _, podName := range nodeWS.Pods.GetKeys() {
+		if _, found := nodeWS.DaemonPods[podName]; found {
+			continue
+		}
+		pod := c.nodeManager.podManager.FindPod(podName)
+		if pod == nil || !util.PodNotTerminated(pod) {
+			continue
+		}
+		if _, found := pod.Labels[fornaxv1.LabelFornaxCoreNodeDaemon]; found {
+			continue
+		}
+		pods = append(pods, podName)
+	}
+	return pods
+}
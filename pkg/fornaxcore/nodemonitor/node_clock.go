@@ -0,0 +1,204 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemonitor
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/event"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// number of recent clock samples of a node used to estimate its skew
+	DefaultNodeClockSamples = 8
+	// skew smaller than this is within network delay and not corrected
+	DefaultNodeClockSkewCorrectionThreshold = 250 * time.Millisecond
+	// a warning event is recorded on node when its skew is larger than this
+	DefaultNodeClockSkewWarningThreshold = 5 * time.Second
+)
+
+var (
+	nodeClockSkewSeconds = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      "fornax_node",
+			Name:           "clock_skew_seconds",
+			Help:           "Estimated clock skew of a node to fornaxcore, positive if node clock is ahead",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"node"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(nodeClockSkewSeconds)
+}
+
+type nodeClock struct {
+	samples []time.Duration
+	next    int
+	skew    time.Duration
+	warned  bool
+}
+
+// nodeClocks estimate clock skew of nodes from node time carried in node registry and lease renew messages,
+// a sample is node time minus fornaxcore time when message is received, it's smaller than real skew by message delay,
+// so largest recent sample is the best estimate, timestamps reported by node are moved back to fornaxcore time using it
+type nodeClocks struct {
+	mu     sync.RWMutex
+	clocks map[string]*nodeClock
+	now    func() time.Time
+}
+
+func newNodeClocks() *nodeClocks {
+	return &nodeClocks{
+		clocks: map[string]*nodeClock{},
+		now:    time.Now,
+	}
+}
+
+// reset forget samples of node, node register again after restart and its clock may be fixed
+func (c *nodeClocks) reset(nodeId string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.clocks, nodeId)
+}
+
+// observe add a sample of node clock, nil node time is sent by node agent of older version, it's ignored
+func (c *nodeClocks) observe(nodeId string, nodeTime *timestamppb.Timestamp) {
+	if nodeTime == nil {
+		return
+	}
+	sample := nodeTime.AsTime().Sub(c.now())
+
+	c.mu.Lock()
+	clock, found := c.clocks[nodeId]
+	if !found {
+		clock = &nodeClock{}
+		c.clocks[nodeId] = clock
+	}
+	if len(clock.samples) < DefaultNodeClockSamples {
+		clock.samples = append(clock.samples, sample)
+	} else {
+		clock.samples[clock.next] = sample
+		clock.next = (clock.next + 1) % DefaultNodeClockSamples
+	}
+	clock.skew = clock.samples[0]
+	for _, v := range clock.samples[1:] {
+		if v > clock.skew {
+			clock.skew = v
+		}
+	}
+	skew, warn := clock.skew, false
+	if absDuration(skew) >= DefaultNodeClockSkewWarningThreshold {
+		warn, clock.warned = !clock.warned, true
+	} else {
+		clock.warned = false
+	}
+	c.mu.Unlock()
+
+	nodeClockSkewSeconds.WithLabelValues(nodeId).Set(skew.Seconds())
+	if warn {
+		klog.InfoS("Node clock is skewed from fornaxcore, timestamps reported by node are corrected", "node", nodeId, "skew", skew)
+		event.Eventf(event.NodeRef(nodeId), fornaxv1.FornaxEventTypeWarning, event.SourceNodeLease, "ClockSkew", "Node clock is %s off fornaxcore clock", skew.Round(time.Millisecond))
+	}
+}
+
+// skew return estimated skew of node, it's zero if it's too small to correct or node has not sent its time
+func (c *nodeClocks) skew(nodeId string) time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if clock, found := c.clocks[nodeId]; found && absDuration(clock.skew) >= DefaultNodeClockSkewCorrectionThreshold {
+		return clock.skew
+	}
+	return 0
+}
+
+func correctMetaTime(t *metav1.Time, skew time.Duration) *metav1.Time {
+	if t == nil {
+		return nil
+	}
+	corrected := metav1.NewTime(t.Add(-skew))
+	return &corrected
+}
+
+// normalizeSession move timestamps in session status reported by node to fornaxcore time, it return false if nothing is changed
+func (c *nodeClocks) normalizeSession(nodeId string, session *fornaxv1.ApplicationSession) bool {
+	skew := c.skew(nodeId)
+	if skew == 0 {
+		return false
+	}
+	status := &session.Status
+	status.AvailableTime = correctMetaTime(status.AvailableTime, skew)
+	status.CloseTime = correctMetaTime(status.CloseTime, skew)
+	for i := range status.ClientSessions {
+		status.ClientSessions[i].JoinTime = correctMetaTime(status.ClientSessions[i].JoinTime, skew)
+		status.ClientSessions[i].LeaveTime = correctMetaTime(status.ClientSessions[i].LeaveTime, skew)
+	}
+	return true
+}
+
+// normalizePodStates correct session data embedded in pod states, session data is decoded and encoded again only when node clock is skewed
+func (c *nodeClocks) normalizePodStates(nodeId string, podStates []*grpc.PodState) {
+	if c.skew(nodeId) == 0 {
+		return
+	}
+	for _, podState := range podStates {
+		for _, v := range podState.GetSessionStates() {
+			session := &fornaxv1.ApplicationSession{}
+			if err := json.Unmarshal(v.SessionData, session); err != nil {
+				continue
+			}
+			c.normalizeSession(nodeId, session)
+			if data, err := json.Marshal(session); err == nil {
+				v.SessionData = data
+			}
+		}
+	}
+}
+
+// normalizeSessionUsage return a copy of usage whose interval is in fornaxcore time, usage itself is returned if node clock is not skewed
+func (c *nodeClocks) normalizeSessionUsage(nodeId string, usage *grpc.SessionUsage) *grpc.SessionUsage {
+	skew := c.skew(nodeId)
+	if skew == 0 || usage == nil {
+		return usage
+	}
+	corrected := proto.Clone(usage).(*grpc.SessionUsage)
+	if usage.GetStartTime() != nil {
+		corrected.StartTime = timestamppb.New(usage.GetStartTime().AsTime().Add(-skew))
+	}
+	if usage.GetEndTime() != nil {
+		corrected.EndTime = timestamppb.New(usage.GetEndTime().AsTime().Add(-skew))
+	}
+	return corrected
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
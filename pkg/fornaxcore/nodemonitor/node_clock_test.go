@@ -0,0 +1,91 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemonitor
+
+import (
+	"testing"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNodeClockSkewEstimate(t *testing.T) {
+	c := newNodeClocks()
+	now := time.Date(2022, 10, 1, 10, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	// node is 10s ahead, messages are delayed by 5ms to 2s
+	for _, delay := range []time.Duration{2 * time.Second, 5 * time.Millisecond, 300 * time.Millisecond} {
+		c.observe("node1", timestamppb.New(now.Add(10*time.Second).Add(-delay)))
+	}
+	if skew := c.skew("node1"); skew != 10*time.Second-5*time.Millisecond {
+		t.Errorf("expected skew estimated from least delayed sample, got %s", skew)
+	}
+
+	// old samples are dropped
+	for i := 0; i < DefaultNodeClockSamples; i++ {
+		c.observe("node1", timestamppb.New(now.Add(-3*time.Second)))
+	}
+	if skew := c.skew("node1"); skew != -3*time.Second {
+		t.Errorf("expected skew of recent samples, got %s", skew)
+	}
+
+	// small skew is not corrected, node without samples has no skew
+	c.reset("node1")
+	c.observe("node1", timestamppb.New(now.Add(100*time.Millisecond)))
+	c.observe("node1", nil)
+	if skew := c.skew("node1"); skew != 0 {
+		t.Errorf("expected skew under threshold is not corrected, got %s", skew)
+	}
+	if skew := c.skew("node2"); skew != 0 {
+		t.Errorf("expected no skew of unknown node, got %s", skew)
+	}
+}
+
+func TestNormalizeNodeTimestamps(t *testing.T) {
+	c := newNodeClocks()
+	now := time.Date(2022, 10, 1, 10, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	c.observe("node1", timestamppb.New(now.Add(time.Minute)))
+
+	join := metav1.NewTime(now.Add(time.Minute))
+	session := &fornaxv1.ApplicationSession{Status: fornaxv1.ApplicationSessionStatus{
+		CloseTime:      &join,
+		ClientSessions: []fornaxv1.ClientSessionReference{{Name: "c1", JoinTime: &join}},
+	}}
+	if !c.normalizeSession("node1", session) {
+		t.Fatal("expected session of skewed node is normalized")
+	}
+	if !session.Status.CloseTime.Time.Equal(now) || !session.Status.ClientSessions[0].JoinTime.Time.Equal(now) || session.Status.ClientSessions[0].LeaveTime != nil {
+		t.Errorf("unexpected normalized session status %v", session.Status)
+	}
+
+	usage := &grpc.SessionUsage{SessionIdentifier: "s1", StartTime: timestamppb.New(now), EndTime: timestamppb.New(now.Add(time.Minute))}
+	corrected := c.normalizeSessionUsage("node1", usage)
+	if !corrected.GetStartTime().AsTime().Equal(now.Add(-time.Minute)) || !corrected.GetEndTime().AsTime().Equal(now) || corrected.GetSessionIdentifier() != "s1" {
+		t.Errorf("unexpected normalized usage %v", corrected)
+	}
+	if !usage.GetStartTime().AsTime().Equal(now) {
+		t.Errorf("usage reported by node must not be changed")
+	}
+	if c.normalizeSessionUsage("node2", usage) != usage {
+		t.Errorf("expected usage of node without skew is not copied")
+	}
+}
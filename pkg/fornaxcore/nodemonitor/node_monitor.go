@@ -72,6 +72,8 @@ type nodeMonitor struct {
 	nodeManager ie.NodeManagerInterface
 	nodes       NodeRevisionMap
 	staleNodes  NodeRevisionMap
+	clocks      *nodeClocks
+	versions    *nodeVersions
 }
 
 // OnSessionUpdate implements server.NodeMonitor
@@ -87,7 +89,7 @@ func (nm *nodeMonitor) OnSessionUpdate(message *grpc.FornaxCoreMessage) (*grpc.F
 	}
 	defer func() {
 		et := time.Now().UnixMicro()
-		klog.InfoS("Done Received a session state", "node", nodeId, "session", util.Name(session), "node revision", revision, "status", session.Status, "took-micro", et-st)
+		klog.InfoS("Done Received a session state", "node", nodeId, "session", util.Name(session), "requestId", util.RequestId(session), "node revision", revision, "status", session.Status, "took-micro", et-st)
 	}()
 
 	nodeWRev := nm.nodes.get(nodeId)
@@ -102,6 +104,7 @@ func (nm *nodeMonitor) OnSessionUpdate(message *grpc.FornaxCoreMessage) (*grpc.F
 	}
 
 	nodeWRev.Revision = revision
+	nm.clocks.normalizeSession(nodeId, session)
 	err := nm.nodeManager.UpdateSessionState(nodeId, session)
 	if err != nil {
 		klog.ErrorS(err, "Failed to update pod state", "pod", sessionState)
@@ -111,6 +114,27 @@ func (nm *nodeMonitor) OnSessionUpdate(message *grpc.FornaxCoreMessage) (*grpc.F
 	return nil, nil
 }
 
+// OnNodeLeaseRenew renew lease of node, a lease configuration is sent back if node should renew at a different interval
+func (nm *nodeMonitor) OnNodeLeaseRenew(message *grpc.FornaxCoreMessage) (*grpc.FornaxCoreMessage, error) {
+	nodeId := message.GetNodeIdentifier().GetIdentifier()
+	nm.clocks.observe(nodeId, message.GetNodeLeaseRenew().GetNodeTime())
+	return nm.nodeManager.RenewNodeLease(nodeId, message.GetNodeLeaseRenew())
+}
+
+// OnNodeConfigProfileStatus record compliance of node config profile reported by node
+func (nm *nodeMonitor) OnNodeConfigProfileStatus(message *grpc.FornaxCoreMessage) (*grpc.FornaxCoreMessage, error) {
+	nodeId := message.GetNodeIdentifier().GetIdentifier()
+	nm.nodeManager.UpdateNodeConfigProfileStatus(nodeId, message.GetNodeConfigProfileStatus())
+	return nil, nil
+}
+
+// OnSessionUsage pass session usage reported by node to node manager
+func (nm *nodeMonitor) OnSessionUsage(message *grpc.FornaxCoreMessage) (*grpc.FornaxCoreMessage, error) {
+	nodeId := message.GetNodeIdentifier().GetIdentifier()
+	nm.nodeManager.UpdateSessionUsage(nodeId, nm.clocks.normalizeSessionUsage(nodeId, message.GetSessionUsage()))
+	return nil, nil
+}
+
 // OnRegistry setup a new node, send a a node configruation back to node for initialization,
 // node will send back node ready message after node configruation finished
 func (nm *nodeMonitor) OnRegistry(message *grpc.FornaxCoreMessage) (*grpc.FornaxCoreMessage, error) {
@@ -118,6 +142,11 @@ func (nm *nodeMonitor) OnRegistry(message *grpc.FornaxCoreMessage) (*grpc.Fornax
 	nodeId := message.GetNodeIdentifier().GetIdentifier()
 	revision := message.GetNodeRegistry().GetNodeRevision()
 	klog.InfoS("A node is registering", "node", nodeId, "revision", revision)
+	nm.clocks.reset(nodeId)
+	nm.clocks.observe(nodeId, message.GetNodeRegistry().GetNodeTime())
+	if err := nm.versions.check(nodeId, v1node); err != nil {
+		return nil, err
+	}
 
 	// on node register, we reset revision
 	if nodeWRev := nm.nodes.get(nodeId); nodeWRev == nil {
@@ -150,6 +179,7 @@ func (nm *nodeMonitor) OnRegistry(message *grpc.FornaxCoreMessage) (*grpc.Fornax
 			ClusterDomain: domain,
 			Node:          fornaxnode.Node.DeepCopy(),
 			DaemonPods:    daemons,
+			Lease:         nm.nodeManager.NodeLeaseConfiguration(nodeId),
 		},
 	}
 	messageType := grpc.MessageType_NODE_CONFIGURATION
@@ -246,6 +276,7 @@ func (nm *nodeMonitor) OnPodStateUpdate(message *grpc.FornaxCoreMessage) (*grpc.
 	for _, v := range podState.GetSessionStates() {
 		session := &fornaxv1.ApplicationSession{}
 		if err := json.Unmarshal(v.SessionData, session); err == nil {
+			nm.clocks.normalizeSession(nodeId.GetIdentifier(), session)
 			sessions = append(sessions, session)
 		}
 	}
@@ -273,6 +304,7 @@ func (nm *nodeMonitor) validateNodeRevision(nodeWRev *NodeWithRevision, revision
 }
 
 func (nm *nodeMonitor) updateOrCreateNode(nodeId string, v1node *v1.Node, revision int64, podStates []*grpc.PodState) error {
+	nm.clocks.normalizePodStates(nodeId, podStates)
 	if nodeWRev := nm.nodes.get(nodeId); nodeWRev == nil {
 		nm.nodes.add(nodeId, &NodeWithRevision{
 			NodeId:   nodeId,
@@ -301,6 +333,11 @@ func (nm *nodeMonitor) updateOrCreateNode(nodeId string, v1node *v1.Node, revisi
 	return nil
 }
 
+// SetNodeVersionSkewConfiguration change version skew policy checked when nodes register
+func (nm *nodeMonitor) SetNodeVersionSkewConfiguration(config *NodeVersionSkewConfiguration) {
+	nm.versions.setConfiguration(config)
+}
+
 func (nm *nodeMonitor) CheckStaleNode() {
 	//TODO
 }
@@ -317,6 +354,8 @@ func NewNodeMonitor(nodeManager ie.NodeManagerInterface) *nodeMonitor {
 			mu:    sync.RWMutex{},
 			nodes: map[string]*NodeWithRevision{},
 		},
+		clocks:   newNodeClocks(),
+		versions: newNodeVersions(DefaultNodeVersionSkewConfiguration()),
 	}
 
 	return nm
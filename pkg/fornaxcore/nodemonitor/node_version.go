@@ -0,0 +1,277 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemonitor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/event"
+	ie "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/internal"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	componentversion "k8s.io/component-base/version"
+	"k8s.io/klog/v2"
+)
+
+type NodeVersionSkewAction string
+
+const (
+	// node out of skew policy can register, a warning event is recorded on it
+	NodeVersionSkewActionWarn NodeVersionSkewAction = "Warn"
+	// node out of skew policy is refused to register until it's upgraded or fornaxcore is rolled back
+	NodeVersionSkewActionRefuse NodeVersionSkewAction = "Refuse"
+
+	DefaultNodeVersionMaxMinorSkew = 2
+
+	NodeVersionReportPath = "/fornaxcore/node/versions"
+
+	// version of node agents which do not report version or report a version can not be parsed
+	UnknownNodeVersion = "unknown"
+)
+
+var (
+	InvalidNodeVersionSkewConfigurationError = errors.New("node version max minor skew must not be negative, action must be Warn or Refuse")
+	NodeVersionSkewError                     = errors.New("node agent version is out of fornaxcore version skew policy")
+)
+
+var (
+	nodeVersionMinorSkew = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      "fornax_node",
+			Name:           "version_minor_skew",
+			Help:           "Number of minor versions node agent is behind fornaxcore, negative if node agent is ahead",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"node", "version"},
+	)
+	nodeVersionRefusals = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_node",
+			Name:           "version_skew_refusals_total",
+			Help:           "Number of node registrations refused because node agent version is out of skew policy",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(nodeVersionMinorSkew, nodeVersionRefusals)
+}
+
+// NodeVersionSkewConfiguration is allowed minor version difference between fornaxcore and node agents of same major version,
+// node agent of another major version is always out of policy
+type NodeVersionSkewConfiguration struct {
+	MaxMinorSkew int                   `json:"maxMinorSkew"`
+	Action       NodeVersionSkewAction `json:"action,omitempty"`
+}
+
+func DefaultNodeVersionSkewConfiguration() *NodeVersionSkewConfiguration {
+	return &NodeVersionSkewConfiguration{
+		MaxMinorSkew: DefaultNodeVersionMaxMinorSkew,
+		Action:       NodeVersionSkewActionWarn,
+	}
+}
+
+// LoadNodeVersionSkewConfiguration read node version skew configuration from a json file, default configuration is returned if file does not exist,
+// fields missing in file keep their default value
+func LoadNodeVersionSkewConfiguration(file string) (*NodeVersionSkewConfiguration, error) {
+	config := DefaultNodeVersionSkewConfiguration()
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	if config.MaxMinorSkew < 0 || (config.Action != NodeVersionSkewActionWarn && config.Action != NodeVersionSkewActionRefuse) {
+		return nil, InvalidNodeVersionSkewConfigurationError
+	}
+	return config, nil
+}
+
+// NodeVersion is version of a node agent and its skew to fornaxcore
+type NodeVersion struct {
+	Node       string `json:"node"`
+	Version    string `json:"version"`
+	MinorSkew  int    `json:"minorSkew"`
+	OutOfSkew  bool   `json:"outOfSkew,omitempty"`
+	SkewReason string `json:"skewReason,omitempty"`
+}
+
+// NodeVersionReport is versions of node agents in fleet, operator use it to find nodes need to be upgraded before or after a fornaxcore upgrade
+type NodeVersionReport struct {
+	FornaxCoreVersion string                       `json:"fornaxCoreVersion"`
+	Policy            NodeVersionSkewConfiguration `json:"policy"`
+	// number of nodes of each node agent version
+	Versions  map[string]int `json:"versions"`
+	OutOfSkew int            `json:"outOfSkew"`
+	Nodes     []NodeVersion  `json:"nodes"`
+}
+
+// nodeVersions check node agent version against fornaxcore version when node register,
+// version is reported by node agent as kubelet version in node info
+type nodeVersions struct {
+	mu          sync.Mutex
+	config      *NodeVersionSkewConfiguration
+	coreVersion string
+	// last evaluated version of nodes, a node out of skew is warned again only after it's back in skew
+	nodes map[string]*NodeVersion
+}
+
+func newNodeVersions(config *NodeVersionSkewConfiguration) *nodeVersions {
+	return &nodeVersions{
+		config:      config,
+		coreVersion: componentversion.Get().GitVersion,
+		nodes:       map[string]*NodeVersion{},
+	}
+}
+
+func (c *nodeVersions) setConfiguration(config *NodeVersionSkewConfiguration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.config = config
+}
+
+// evaluate compare a node agent version with fornaxcore version, unknown versions are not out of skew
+// since node agents built before version report and dev builds can not be told apart from real skew
+func (c *nodeVersions) evaluate(nodeId, nodeVersion string, maxMinorSkew int) NodeVersion {
+	result := NodeVersion{Node: nodeId, Version: nodeVersion}
+	nv, err := version.ParseGeneric(nodeVersion)
+	if len(nodeVersion) == 0 || err != nil {
+		result.Version = UnknownNodeVersion
+		return result
+	}
+	cv, err := version.ParseGeneric(c.coreVersion)
+	if err != nil {
+		return result
+	}
+	if nv.Major() != cv.Major() {
+		result.OutOfSkew = true
+		result.SkewReason = fmt.Sprintf("major version %d is different from fornaxcore major version %d", nv.Major(), cv.Major())
+		return result
+	}
+	result.MinorSkew = int(cv.Minor()) - int(nv.Minor())
+	if result.MinorSkew > maxMinorSkew || -result.MinorSkew > maxMinorSkew {
+		result.OutOfSkew = true
+		result.SkewReason = fmt.Sprintf("%d minor versions apart from fornaxcore %s, at most %d are allowed", abs(result.MinorSkew), c.coreVersion, maxMinorSkew)
+	}
+	return result
+}
+
+// check evaluate version of a registering node, it return NodeVersionSkewError if node is out of skew and policy refuse it
+func (c *nodeVersions) check(nodeId string, node *v1.Node) error {
+	c.mu.Lock()
+	config := *c.config
+	result := c.evaluate(nodeId, node.Status.NodeInfo.KubeletVersion, config.MaxMinorSkew)
+	last, found := c.nodes[nodeId]
+	warn := result.OutOfSkew && (!found || !last.OutOfSkew)
+	c.nodes[nodeId] = &result
+	c.mu.Unlock()
+
+	if found && last.Version != result.Version {
+		nodeVersionMinorSkew.DeleteLabelValues(nodeId, last.Version)
+	}
+	nodeVersionMinorSkew.WithLabelValues(nodeId, result.Version).Set(float64(result.MinorSkew))
+	if !result.OutOfSkew {
+		return nil
+	}
+	refuse := config.Action == NodeVersionSkewActionRefuse
+	if warn {
+		klog.InfoS("Node agent version is out of skew policy", "node", nodeId, "version", result.Version, "fornaxcore", c.coreVersion, "reason", result.SkewReason, "refused", refuse)
+		event.Eventf(event.NodeRef(nodeId), fornaxv1.FornaxEventTypeWarning, event.SourceNodeMonitor, "VersionSkew", "Node agent %s is %s", result.Version, result.SkewReason)
+	}
+	if refuse {
+		nodeVersionRefusals.Inc()
+		return fmt.Errorf("%w, node %s version %s is %s", NodeVersionSkewError, nodeId, result.Version, result.SkewReason)
+	}
+	return nil
+}
+
+// report evaluate versions of all known nodes
+func (c *nodeVersions) report(nodes []*ie.NodeEvent) *NodeVersionReport {
+	c.mu.Lock()
+	config := *c.config
+	c.mu.Unlock()
+	report := &NodeVersionReport{
+		FornaxCoreVersion: c.coreVersion,
+		Policy:            config,
+		Versions:          map[string]int{},
+		Nodes:             []NodeVersion{},
+	}
+	for _, v := range nodes {
+		if v.Node == nil {
+			continue
+		}
+		result := c.evaluate(v.NodeId, v.Node.Status.NodeInfo.KubeletVersion, config.MaxMinorSkew)
+		report.Versions[result.Version] += 1
+		if result.OutOfSkew {
+			report.OutOfSkew += 1
+		}
+		report.Nodes = append(report.Nodes, result)
+	}
+	sort.Slice(report.Nodes, func(i, j int) bool { return report.Nodes[i].Node < report.Nodes[j].Node })
+	return report
+}
+
+// NodeVersionReportHandler serve version report of nodes, outofskew=true only list nodes out of skew policy
+type NodeVersionReportHandler struct {
+	versions *nodeVersions
+	nodeInfo ie.NodeInfoProviderInterface
+}
+
+func (nm *nodeMonitor) NewNodeVersionReportHandler() *NodeVersionReportHandler {
+	return &NodeVersionReportHandler{versions: nm.versions, nodeInfo: nm.nodeManager}
+}
+
+func (h *NodeVersionReportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report := h.versions.report(h.nodeInfo.List())
+	if r.URL.Query().Get("outofskew") == "true" {
+		nodes := []NodeVersion{}
+		for _, v := range report.Nodes {
+			if v.OutOfSkew {
+				nodes = append(nodes, v)
+			}
+		}
+		report.Nodes = nodes
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemonitor
+
+import (
+	"errors"
+	"testing"
+
+	ie "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/internal"
+	v1 "k8s.io/api/core/v1"
+)
+
+func nodeOfVersion(version string) *v1.Node {
+	return &v1.Node{Status: v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{KubeletVersion: version}}}
+}
+
+func TestNodeVersionSkewPolicy(t *testing.T) {
+	c := newNodeVersions(&NodeVersionSkewConfiguration{MaxMinorSkew: 2, Action: NodeVersionSkewActionWarn})
+	c.coreVersion = "v1.5.0"
+
+	tests := []struct {
+		version   string
+		minorSkew int
+		outOfSkew bool
+	}{
+		{"v1.5.2", 0, false},
+		{"v1.3.0", 2, false},
+		{"v1.2.9", 3, true},
+		{"v1.8.0-beta.1", -3, true},
+		{"v2.5.0", 0, true},
+		{"", 0, false},
+		{"dev", 0, false},
+	}
+	for _, test := range tests {
+		result := c.evaluate("node1", test.version, 2)
+		if result.MinorSkew != test.minorSkew || result.OutOfSkew != test.outOfSkew {
+			t.Errorf("version %q, expected skew %d out of skew %v, got %v", test.version, test.minorSkew, test.outOfSkew, result)
+		}
+	}
+
+	if err := c.check("node1", nodeOfVersion("v1.1.0")); err != nil {
+		t.Errorf("expected node out of skew is allowed with warn action, got %v", err)
+	}
+	c.setConfiguration(&NodeVersionSkewConfiguration{MaxMinorSkew: 2, Action: NodeVersionSkewActionRefuse})
+	if err := c.check("node1", nodeOfVersion("v1.1.0")); !errors.Is(err, NodeVersionSkewError) {
+		t.Errorf("expected node out of skew is refused, got %v", err)
+	}
+	if err := c.check("node1", nodeOfVersion("v1.4.0")); err != nil {
+		t.Errorf("expected upgraded node is allowed, got %v", err)
+	}
+}
+
+func TestNodeVersionReport(t *testing.T) {
+	c := newNodeVersions(DefaultNodeVersionSkewConfiguration())
+	c.coreVersion = "v1.5.0"
+	report := c.report([]*ie.NodeEvent{
+		{NodeId: "node2", Node: nodeOfVersion("v1.5.0")},
+		{NodeId: "node1", Node: nodeOfVersion("v1.1.0")},
+		{NodeId: "node3", Node: nodeOfVersion("")},
+		{NodeId: "node4", Node: nodeOfVersion("v1.5.0")},
+	})
+	if report.OutOfSkew != 1 || report.Versions["v1.5.0"] != 2 || report.Versions[UnknownNodeVersion] != 1 {
+		t.Errorf("unexpected version report %v", report)
+	}
+	if len(report.Nodes) != 4 || report.Nodes[0].Node != "node1" || !report.Nodes[0].OutOfSkew {
+		t.Errorf("expected nodes sorted by name with node1 out of skew, got %v", report.Nodes)
+	}
+}
@@ -0,0 +1,287 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package placement keep a append only audit log of placement decisions, pod to node and session to pod,
+// it's queried by application and time range after a incident to find capacity hot spots
+package placement
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	PlacementAuditPath = "/debug/fornaxcore/placement/audit"
+
+	// log file is rotated to file.1 when it's larger than it, only one rotated file is kept
+	DefaultMaxLogBytes = 256 * 1024 * 1024
+	DefaultQueueSize   = 10000
+	DefaultQueryLimit  = 1000
+	MaxQueryLimit      = 100000
+)
+
+type Kind string
+
+const (
+	// pod is scheduled to a node
+	KindPod Kind = "pod"
+	// session is bound to a pod
+	KindSession Kind = "session"
+)
+
+type Result string
+
+const (
+	ResultPlaced Result = "placed"
+	ResultFailed Result = "failed"
+)
+
+// Record is a compact placement decision, one json line in log
+type Record struct {
+	Time        time.Time `json:"t"`
+	Kind        Kind      `json:"k"`
+	Application string    `json:"app"`
+	Session     string    `json:"session,omitempty"`
+	Pod         string    `json:"pod"`
+	Node        string    `json:"node,omitempty"`
+	Result      Result    `json:"result"`
+	// attempt number of pod schedule or session bind, 1 is first attempt
+	Attempt int `json:"attempt,omitempty"`
+	// score of selected node or pod, extender score is added for pods
+	Score int64 `json:"score,omitempty"`
+	// number of nodes or idle pods evaluated
+	Candidates int    `json:"candidates,omitempty"`
+	Message    string `json:"msg,omitempty"`
+}
+
+var (
+	droppedRecords = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_placement_audit",
+			Name:           "dropped_records_total",
+			Help:           "Number of placement audit records dropped because queue is full or log file is not writable",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(droppedRecords)
+}
+
+var (
+	InvalidTimeRangeError = errors.New("since must be before until")
+)
+
+// AuditLog append records to a json lines file in background, Record never block scheduling,
+// a nil AuditLog ignore records, so, callers do not need to check if audit log is enabled
+type AuditLog struct {
+	file     string
+	maxBytes int64
+	queue    chan *Record
+}
+
+func NewAuditLog(file string) (*AuditLog, error) {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return nil, err
+	}
+	return &AuditLog{
+		file:     file,
+		maxBytes: DefaultMaxLogBytes,
+		queue:    make(chan *Record, DefaultQueueSize),
+	}, nil
+}
+
+func (l *AuditLog) Record(record *Record) {
+	if l == nil {
+		return
+	}
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+	select {
+	case l.queue <- record:
+	default:
+		droppedRecords.Inc()
+	}
+}
+
+func (l *AuditLog) open() (*os.File, int64, error) {
+	f, err := os.OpenFile(l.file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, 0, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+	return f, info.Size(), nil
+}
+
+// Run write queued records until context is done
+func (l *AuditLog) Run(ctx context.Context) {
+	f, size, err := l.open()
+	if err != nil {
+		klog.ErrorS(err, "Failed to open placement audit log, records are dropped", "file", l.file)
+	}
+	defer func() {
+		if f != nil {
+			f.Close()
+		}
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case record := <-l.queue:
+			if f == nil {
+				if f, size, err = l.open(); err != nil {
+					droppedRecords.Inc()
+					continue
+				}
+			}
+			data, err := json.Marshal(record)
+			if err != nil {
+				droppedRecords.Inc()
+				continue
+			}
+			data = append(data, '\n')
+			if _, err := f.Write(data); err != nil {
+				klog.ErrorS(err, "Failed to write placement audit log", "file", l.file)
+				droppedRecords.Inc()
+				f.Close()
+				f = nil
+				continue
+			}
+			size += int64(len(data))
+			if size > l.maxBytes {
+				f.Close()
+				f = nil
+				if err := os.Rename(l.file, l.file+".1"); err != nil {
+					klog.ErrorS(err, "Failed to rotate placement audit log", "file", l.file)
+				}
+			}
+		}
+	}
+}
+
+// Query scan rotated and current log file, return records of application in [since, until), oldest first, at most limit records,
+// empty application match all applications
+func (l *AuditLog) Query(application string, since, until time.Time, limit int) ([]*Record, error) {
+	if !until.IsZero() && !since.Before(until) {
+		return nil, InvalidTimeRangeError
+	}
+	records := []*Record{}
+	for _, file := range []string{l.file + ".1", l.file} {
+		f, err := os.Open(file)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() && len(records) < limit {
+			record := &Record{}
+			if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+				// a partial line written before a crash
+				continue
+			}
+			if len(application) > 0 && record.Application != application {
+				continue
+			}
+			if record.Time.Before(since) || (!until.IsZero() && !record.Time.Before(until)) {
+				continue
+			}
+			records = append(records, record)
+		}
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+// AuditLogHandler serve placement records, e.g. ?application=namespace/name&since=2022-10-01T00:00:00Z&until=2022-10-02T00:00:00Z&limit=100,
+// since default one hour ago, until default now
+type AuditLogHandler struct {
+	log *AuditLog
+}
+
+func NewAuditLogHandler(log *AuditLog) *AuditLogHandler {
+	return &AuditLogHandler{log: log}
+}
+
+func (h *AuditLogHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.log == nil {
+		http.Error(w, "placement audit log is not enabled", http.StatusNotFound)
+		return
+	}
+	query := r.URL.Query()
+	since, until := time.Now().Add(-time.Hour), time.Time{}
+	var err error
+	if v := query.Get("since"); len(v) > 0 {
+		if since, err = time.Parse(time.RFC3339, v); err != nil {
+			http.Error(w, fmt.Sprintf("invalid since %s, must be RFC3339 time", v), http.StatusBadRequest)
+			return
+		}
+	}
+	if v := query.Get("until"); len(v) > 0 {
+		if until, err = time.Parse(time.RFC3339, v); err != nil {
+			http.Error(w, fmt.Sprintf("invalid until %s, must be RFC3339 time", v), http.StatusBadRequest)
+			return
+		}
+	}
+	limit := DefaultQueryLimit
+	if v := query.Get("limit"); len(v) > 0 {
+		if limit, err = strconv.Atoi(v); err != nil || limit <= 0 || limit > MaxQueryLimit {
+			http.Error(w, fmt.Sprintf("invalid limit %s, must be between 1 and %d", v, MaxQueryLimit), http.StatusBadRequest)
+			return
+		}
+	}
+
+	records, err := h.log.Query(query.Get("application"), since, until, limit)
+	if err == InvalidTimeRangeError {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
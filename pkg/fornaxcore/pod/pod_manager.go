@@ -22,6 +22,7 @@ import (
 	"sync"
 	"time"
 
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
 	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc/nodeagent"
 	ie "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/internal"
 	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/podscheduler"
@@ -240,6 +241,42 @@ func (pm *podManager) HibernatePod(podName string) error {
 	return nil
 }
 
+// UpdatePodConfig send new config map to node where pod is running, it's no op if pod is not bound with node
+func (pm *podManager) UpdatePodConfig(podName string, configMap *v1.ConfigMap) error {
+	fornaxPodState := pm.podStateMap.findPod(podName)
+	if fornaxPodState == nil {
+		return PodNotFoundError
+	}
+	podInCache := fornaxPodState.v1pod
+
+	if len(fornaxPodState.nodeId) > 0 && util.PodNotTerminated(podInCache) {
+		err := pm.nodeAgentClient.UpdatePodConfig(fornaxPodState.nodeId, fornaxPodState.v1pod, configMap)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdatePodSecret send secret versions to node where pod is running, it's no op if pod is not bound with node
+func (pm *podManager) UpdatePodSecret(podName string, secret, previousSecret *fornaxv1.ApplicationSecret) error {
+	fornaxPodState := pm.podStateMap.findPod(podName)
+	if fornaxPodState == nil {
+		return PodNotFoundError
+	}
+	podInCache := fornaxPodState.v1pod
+
+	if len(fornaxPodState.nodeId) > 0 && util.PodNotTerminated(podInCache) {
+		err := pm.nodeAgentClient.UpdatePodSecret(fornaxPodState.nodeId, fornaxPodState.v1pod, secret, previousSecret)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (pm *podManager) createPodAndSendEvent(nodeId string, pod *v1.Pod) {
 	var eType ie.PodEventType
 	switch {
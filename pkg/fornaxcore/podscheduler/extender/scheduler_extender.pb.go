@@ -0,0 +1,573 @@
+//
+//Copyright 2022.
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        v3.12.1
+// source: pkg/fornaxcore/podscheduler/extender/scheduler_extender.proto
+
+package extender
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	v1 "k8s.io/api/core/v1"
+	resource "k8s.io/apimachinery/pkg/api/resource"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CandidateNode struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NodeName string            `protobuf:"bytes,1,opt,name=nodeName,proto3" json:"nodeName,omitempty"`
+	Labels   map[string]string `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// resources not occupied by pods yet
+	Allocatable map[string]*resource.Quantity `protobuf:"bytes,3,rep,name=allocatable,proto3" json:"allocatable,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *CandidateNode) Reset() {
+	*x = CandidateNode{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CandidateNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CandidateNode) ProtoMessage() {}
+
+func (x *CandidateNode) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CandidateNode.ProtoReflect.Descriptor instead.
+func (*CandidateNode) Descriptor() ([]byte, []int) {
+	return file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CandidateNode) GetNodeName() string {
+	if x != nil {
+		return x.NodeName
+	}
+	return ""
+}
+
+func (x *CandidateNode) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *CandidateNode) GetAllocatable() map[string]*resource.Quantity {
+	if x != nil {
+		return x.Allocatable
+	}
+	return nil
+}
+
+type ExtenderArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pod   *v1.Pod          `protobuf:"bytes,1,opt,name=pod,proto3" json:"pod,omitempty"`
+	Nodes []*CandidateNode `protobuf:"bytes,2,rep,name=nodes,proto3" json:"nodes,omitempty"`
+}
+
+func (x *ExtenderArgs) Reset() {
+	*x = ExtenderArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExtenderArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtenderArgs) ProtoMessage() {}
+
+func (x *ExtenderArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtenderArgs.ProtoReflect.Descriptor instead.
+func (*ExtenderArgs) Descriptor() ([]byte, []int) {
+	return file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ExtenderArgs) GetPod() *v1.Pod {
+	if x != nil {
+		return x.Pod
+	}
+	return nil
+}
+
+func (x *ExtenderArgs) GetNodes() []*CandidateNode {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+type ExtenderFilterResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// nodes still schedulable after filter
+	NodeNames []string `protobuf:"bytes,1,rep,name=nodeNames,proto3" json:"nodeNames,omitempty"`
+	// filtered out node name => reason
+	FailedNodes map[string]string `protobuf:"bytes,2,rep,name=failedNodes,proto3" json:"failedNodes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// non empty error means extender failed to filter nodes
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *ExtenderFilterResult) Reset() {
+	*x = ExtenderFilterResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExtenderFilterResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtenderFilterResult) ProtoMessage() {}
+
+func (x *ExtenderFilterResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtenderFilterResult.ProtoReflect.Descriptor instead.
+func (*ExtenderFilterResult) Descriptor() ([]byte, []int) {
+	return file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ExtenderFilterResult) GetNodeNames() []string {
+	if x != nil {
+		return x.NodeNames
+	}
+	return nil
+}
+
+func (x *ExtenderFilterResult) GetFailedNodes() map[string]string {
+	if x != nil {
+		return x.FailedNodes
+	}
+	return nil
+}
+
+func (x *ExtenderFilterResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type NodeScore struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NodeName string `protobuf:"bytes,1,opt,name=nodeName,proto3" json:"nodeName,omitempty"`
+	Score    int64  `protobuf:"varint,2,opt,name=score,proto3" json:"score,omitempty"`
+}
+
+func (x *NodeScore) Reset() {
+	*x = NodeScore{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NodeScore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeScore) ProtoMessage() {}
+
+func (x *NodeScore) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeScore.ProtoReflect.Descriptor instead.
+func (*NodeScore) Descriptor() ([]byte, []int) {
+	return file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *NodeScore) GetNodeName() string {
+	if x != nil {
+		return x.NodeName
+	}
+	return ""
+}
+
+func (x *NodeScore) GetScore() int64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type ExtenderScoreResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Scores []*NodeScore `protobuf:"bytes,1,rep,name=scores,proto3" json:"scores,omitempty"`
+	Error  string       `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *ExtenderScoreResult) Reset() {
+	*x = ExtenderScoreResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExtenderScoreResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtenderScoreResult) ProtoMessage() {}
+
+func (x *ExtenderScoreResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtenderScoreResult.ProtoReflect.Descriptor instead.
+func (*ExtenderScoreResult) Descriptor() ([]byte, []int) {
+	return file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ExtenderScoreResult) GetScores() []*NodeScore {
+	if x != nil {
+		return x.Scores
+	}
+	return nil
+}
+
+func (x *ExtenderScoreResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto protoreflect.FileDescriptor
+
+var file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_rawDesc = []byte{
+	0x0a, 0x3d, 0x70, 0x6b, 0x67, 0x2f, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65,
+	0x2f, 0x70, 0x6f, 0x64, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x2f, 0x65, 0x78,
+	0x74, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x2f, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72,
+	0x5f, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x32, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e,
+	0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x70, 0x6f,
+	0x64, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x2e, 0x65, 0x78, 0x74, 0x65, 0x6e,
+	0x64, 0x65, 0x72, 0x1a, 0x22, 0x6b, 0x38, 0x73, 0x2e, 0x69, 0x6f, 0x2f, 0x61, 0x70, 0x69, 0x2f,
+	0x63, 0x6f, 0x72, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65,
+	0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x34, 0x6b, 0x38, 0x73, 0x2e, 0x69, 0x6f, 0x2f,
+	0x61, 0x70, 0x69, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x72, 0x79, 0x2f, 0x70, 0x6b, 0x67,
+	0x2f, 0x61, 0x70, 0x69, 0x2f, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x2f, 0x67, 0x65,
+	0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb3, 0x03,
+	0x0a, 0x0d, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x12,
+	0x1a, 0x0a, 0x08, 0x6e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x6e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x65, 0x0a, 0x06, 0x6c,
+	0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x4d, 0x2e, 0x63, 0x65,
+	0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e,
+	0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x70, 0x6f, 0x64, 0x73, 0x63,
+	0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x2e, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x72,
+	0x2e, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x2e, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65,
+	0x6c, 0x73, 0x12, 0x74, 0x0a, 0x0b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x61, 0x62, 0x6c,
+	0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x52, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75,
+	0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e,
+	0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x70, 0x6f, 0x64, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75,
+	0x6c, 0x65, 0x72, 0x2e, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x2e, 0x43, 0x61, 0x6e,
+	0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x2e, 0x41, 0x6c, 0x6c, 0x6f, 0x63,
+	0x61, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x61, 0x6c, 0x6c,
+	0x6f, 0x63, 0x61, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x1a, 0x39, 0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65,
+	0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x1a, 0x6e, 0x0a, 0x10, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x61, 0x62,
+	0x6c, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x44, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x6b, 0x38, 0x73, 0x2e, 0x69,
+	0x6f, 0x2e, 0x61, 0x70, 0x69, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x72, 0x79, 0x2e, 0x70,
+	0x6b, 0x67, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x2e,
+	0x51, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x22, 0x92, 0x01, 0x0a, 0x0c, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x72,
+	0x41, 0x72, 0x67, 0x73, 0x12, 0x29, 0x0a, 0x03, 0x70, 0x6f, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x6b, 0x38, 0x73, 0x2e, 0x69, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x63,
+	0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x64, 0x52, 0x03, 0x70, 0x6f, 0x64, 0x12,
+	0x57, 0x0a, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x41,
+	0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e,
+	0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x70, 0x6f,
+	0x64, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x2e, 0x65, 0x78, 0x74, 0x65, 0x6e,
+	0x64, 0x65, 0x72, 0x2e, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x4e, 0x6f, 0x64,
+	0x65, 0x52, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x22, 0x87, 0x02, 0x0a, 0x14, 0x45, 0x78, 0x74,
+	0x65, 0x6e, 0x64, 0x65, 0x72, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x12,
+	0x7b, 0x0a, 0x0b, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x59, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73,
+	0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63,
+	0x6f, 0x72, 0x65, 0x2e, 0x70, 0x6f, 0x64, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72,
+	0x2e, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x2e, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64,
+	0x65, 0x72, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x2e, 0x46,
+	0x61, 0x69, 0x6c, 0x65, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x0b, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x1a, 0x3e, 0x0a, 0x10, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x4e, 0x6f, 0x64, 0x65,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x22, 0x3d, 0x0a, 0x09, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12,
+	0x1a, 0x0a, 0x08, 0x6e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x6e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x63, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72,
+	0x65, 0x22, 0x82, 0x01, 0x0a, 0x13, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x53, 0x63,
+	0x6f, 0x72, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x55, 0x0a, 0x06, 0x73, 0x63, 0x6f,
+	0x72, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x3d, 0x2e, 0x63, 0x65, 0x6e, 0x74,
+	0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f,
+	0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x70, 0x6f, 0x64, 0x73, 0x63, 0x68, 0x65,
+	0x64, 0x75, 0x6c, 0x65, 0x72, 0x2e, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x2e, 0x4e,
+	0x6f, 0x64, 0x65, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x52, 0x06, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73,
+	0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x32, 0xbf, 0x02, 0x0a, 0x11, 0x53, 0x63, 0x68, 0x65, 0x64,
+	0x75, 0x6c, 0x65, 0x72, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x94, 0x01, 0x0a,
+	0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x40, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75,
+	0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e,
+	0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x70, 0x6f, 0x64, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75,
+	0x6c, 0x65, 0x72, 0x2e, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x2e, 0x45, 0x78, 0x74,
+	0x65, 0x6e, 0x64, 0x65, 0x72, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x48, 0x2e, 0x63, 0x65, 0x6e, 0x74,
+	0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f,
+	0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x70, 0x6f, 0x64, 0x73, 0x63, 0x68, 0x65,
+	0x64, 0x75, 0x6c, 0x65, 0x72, 0x2e, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x2e, 0x45,
+	0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x12, 0x92, 0x01, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x40, 0x2e,
+	0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69,
+	0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x70, 0x6f, 0x64,
+	0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x2e, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x64,
+	0x65, 0x72, 0x2e, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x41, 0x72, 0x67, 0x73, 0x1a,
+	0x47, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61,
+	0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x70,
+	0x6f, 0x64, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x2e, 0x65, 0x78, 0x74, 0x65,
+	0x6e, 0x64, 0x65, 0x72, 0x2e, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x53, 0x63, 0x6f,
+	0x72, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x42, 0x4a, 0x5a, 0x48, 0x63, 0x65, 0x6e, 0x74,
+	0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2f, 0x66, 0x6f,
+	0x72, 0x6e, 0x61, 0x78, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x6c, 0x65, 0x73, 0x73, 0x2f,
+	0x70, 0x6b, 0x67, 0x2f, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x70,
+	0x6f, 0x64, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x2f, 0x65, 0x78, 0x74, 0x65,
+	0x6e, 0x64, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_rawDescOnce sync.Once
+	file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_rawDescData = file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_rawDesc
+)
+
+func file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_rawDescGZIP() []byte {
+	file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_rawDescOnce.Do(func() {
+		file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_rawDescData)
+	})
+	return file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_rawDescData
+}
+
+var file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_goTypes = []interface{}{
+	(*CandidateNode)(nil),        // 0: centaurusinfra.io.fornaxcore.podscheduler.extender.CandidateNode
+	(*ExtenderArgs)(nil),         // 1: centaurusinfra.io.fornaxcore.podscheduler.extender.ExtenderArgs
+	(*ExtenderFilterResult)(nil), // 2: centaurusinfra.io.fornaxcore.podscheduler.extender.ExtenderFilterResult
+	(*NodeScore)(nil),            // 3: centaurusinfra.io.fornaxcore.podscheduler.extender.NodeScore
+	(*ExtenderScoreResult)(nil),  // 4: centaurusinfra.io.fornaxcore.podscheduler.extender.ExtenderScoreResult
+	nil,                          // 5: centaurusinfra.io.fornaxcore.podscheduler.extender.CandidateNode.LabelsEntry
+	nil,                          // 6: centaurusinfra.io.fornaxcore.podscheduler.extender.CandidateNode.AllocatableEntry
+	nil,                          // 7: centaurusinfra.io.fornaxcore.podscheduler.extender.ExtenderFilterResult.FailedNodesEntry
+	(*v1.Pod)(nil),               // 8: k8s.io.api.core.v1.Pod
+	(*resource.Quantity)(nil),    // 9: k8s.io.apimachinery.pkg.api.resource.Quantity
+}
+var file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_depIdxs = []int32{
+	5, // 0: centaurusinfra.io.fornaxcore.podscheduler.extender.CandidateNode.labels:type_name -> centaurusinfra.io.fornaxcore.podscheduler.extender.CandidateNode.LabelsEntry
+	6, // 1: centaurusinfra.io.fornaxcore.podscheduler.extender.CandidateNode.allocatable:type_name -> centaurusinfra.io.fornaxcore.podscheduler.extender.CandidateNode.AllocatableEntry
+	8, // 2: centaurusinfra.io.fornaxcore.podscheduler.extender.ExtenderArgs.pod:type_name -> k8s.io.api.core.v1.Pod
+	0, // 3: centaurusinfra.io.fornaxcore.podscheduler.extender.ExtenderArgs.nodes:type_name -> centaurusinfra.io.fornaxcore.podscheduler.extender.CandidateNode
+	7, // 4: centaurusinfra.io.fornaxcore.podscheduler.extender.ExtenderFilterResult.failedNodes:type_name -> centaurusinfra.io.fornaxcore.podscheduler.extender.ExtenderFilterResult.FailedNodesEntry
+	3, // 5: centaurusinfra.io.fornaxcore.podscheduler.extender.ExtenderScoreResult.scores:type_name -> centaurusinfra.io.fornaxcore.podscheduler.extender.NodeScore
+	9, // 6: centaurusinfra.io.fornaxcore.podscheduler.extender.CandidateNode.AllocatableEntry.value:type_name -> k8s.io.apimachinery.pkg.api.resource.Quantity
+	1, // 7: centaurusinfra.io.fornaxcore.podscheduler.extender.SchedulerExtender.filter:input_type -> centaurusinfra.io.fornaxcore.podscheduler.extender.ExtenderArgs
+	1, // 8: centaurusinfra.io.fornaxcore.podscheduler.extender.SchedulerExtender.score:input_type -> centaurusinfra.io.fornaxcore.podscheduler.extender.ExtenderArgs
+	2, // 9: centaurusinfra.io.fornaxcore.podscheduler.extender.SchedulerExtender.filter:output_type -> centaurusinfra.io.fornaxcore.podscheduler.extender.ExtenderFilterResult
+	4, // 10: centaurusinfra.io.fornaxcore.podscheduler.extender.SchedulerExtender.score:output_type -> centaurusinfra.io.fornaxcore.podscheduler.extender.ExtenderScoreResult
+	9, // [9:11] is the sub-list for method output_type
+	7, // [7:9] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_init() }
+func file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_init() {
+	if File_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CandidateNode); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExtenderArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExtenderFilterResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NodeScore); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExtenderScoreResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_goTypes,
+		DependencyIndexes: file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_depIdxs,
+		MessageInfos:      file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_msgTypes,
+	}.Build()
+	File_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto = out.File
+	file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_rawDesc = nil
+	file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_goTypes = nil
+	file_pkg_fornaxcore_podscheduler_extender_scheduler_extender_proto_depIdxs = nil
+}
@@ -0,0 +1,141 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.12.1
+// source: pkg/fornaxcore/podscheduler/extender/scheduler_extender.proto
+
+package extender
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// SchedulerExtenderClient is the client API for SchedulerExtender service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SchedulerExtenderClient interface {
+	Filter(ctx context.Context, in *ExtenderArgs, opts ...grpc.CallOption) (*ExtenderFilterResult, error)
+	Score(ctx context.Context, in *ExtenderArgs, opts ...grpc.CallOption) (*ExtenderScoreResult, error)
+}
+
+type schedulerExtenderClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSchedulerExtenderClient(cc grpc.ClientConnInterface) SchedulerExtenderClient {
+	return &schedulerExtenderClient{cc}
+}
+
+func (c *schedulerExtenderClient) Filter(ctx context.Context, in *ExtenderArgs, opts ...grpc.CallOption) (*ExtenderFilterResult, error) {
+	out := new(ExtenderFilterResult)
+	err := c.cc.Invoke(ctx, "/centaurusinfra.io.fornaxcore.podscheduler.extender.SchedulerExtender/filter", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerExtenderClient) Score(ctx context.Context, in *ExtenderArgs, opts ...grpc.CallOption) (*ExtenderScoreResult, error) {
+	out := new(ExtenderScoreResult)
+	err := c.cc.Invoke(ctx, "/centaurusinfra.io.fornaxcore.podscheduler.extender.SchedulerExtender/score", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SchedulerExtenderServer is the server API for SchedulerExtender service.
+// All implementations must embed UnimplementedSchedulerExtenderServer
+// for forward compatibility
+type SchedulerExtenderServer interface {
+	Filter(context.Context, *ExtenderArgs) (*ExtenderFilterResult, error)
+	Score(context.Context, *ExtenderArgs) (*ExtenderScoreResult, error)
+	mustEmbedUnimplementedSchedulerExtenderServer()
+}
+
+// UnimplementedSchedulerExtenderServer must be embedded to have forward compatible implementations.
+type UnimplementedSchedulerExtenderServer struct {
+}
+
+func (UnimplementedSchedulerExtenderServer) Filter(context.Context, *ExtenderArgs) (*ExtenderFilterResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Filter not implemented")
+}
+func (UnimplementedSchedulerExtenderServer) Score(context.Context, *ExtenderArgs) (*ExtenderScoreResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Score not implemented")
+}
+func (UnimplementedSchedulerExtenderServer) mustEmbedUnimplementedSchedulerExtenderServer() {}
+
+// UnsafeSchedulerExtenderServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SchedulerExtenderServer will
+// result in compilation errors.
+type UnsafeSchedulerExtenderServer interface {
+	mustEmbedUnimplementedSchedulerExtenderServer()
+}
+
+func RegisterSchedulerExtenderServer(s grpc.ServiceRegistrar, srv SchedulerExtenderServer) {
+	s.RegisterService(&SchedulerExtender_ServiceDesc, srv)
+}
+
+func _SchedulerExtender_Filter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtenderArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerExtenderServer).Filter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/centaurusinfra.io.fornaxcore.podscheduler.extender.SchedulerExtender/filter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerExtenderServer).Filter(ctx, req.(*ExtenderArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerExtender_Score_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtenderArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerExtenderServer).Score(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/centaurusinfra.io.fornaxcore.podscheduler.extender.SchedulerExtender/score",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerExtenderServer).Score(ctx, req.(*ExtenderArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SchedulerExtender_ServiceDesc is the grpc.ServiceDesc for SchedulerExtender service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SchedulerExtender_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "centaurusinfra.io.fornaxcore.podscheduler.extender.SchedulerExtender",
+	HandlerType: (*SchedulerExtenderServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "filter",
+			Handler:    _SchedulerExtender_Filter_Handler,
+		},
+		{
+			MethodName: "score",
+			Handler:    _SchedulerExtender_Score_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/fornaxcore/podscheduler/extender/scheduler_extender.proto",
+}
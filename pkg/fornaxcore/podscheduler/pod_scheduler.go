@@ -25,9 +25,13 @@ import (
 	"sync"
 	"time"
 
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
 	"centaurusinfra.io/fornax-serverless/pkg/collection"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/event"
 	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc/nodeagent"
 	ie "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/internal"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/placement"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxerrors"
 	"centaurusinfra.io/fornax-serverless/pkg/util"
 
 	v1 "k8s.io/api/core/v1"
@@ -37,6 +41,10 @@ import (
 type PodScheduler interface {
 	AddPod(pod *v1.Pod, duration time.Duration)
 	RemovePod(pod *v1.Pod)
+	// QueueLength return number of pods in active and backoff queue
+	QueueLength() (int, int)
+	// PodCapacity return how many more pods requesting resource list fit in allocatable resources of nodes, -1 if pod request nothing
+	PodCapacity(resourceList v1.ResourceList) int
 }
 
 var _ PodScheduler = &podScheduler{}
@@ -50,11 +58,12 @@ const (
 	NodeSortingMethodMoreMemory  NodeSortingMethod = "more_memory"   // chose node with more memory
 	NodeSortingMethodLessLastUse NodeSortingMethod = "less_last_use" // choose oldest node
 	NodeSortingMethodLessUse     NodeSortingMethod = "less_use"      // choose node with less pods
+	NodeSortingMethodCostAware   NodeSortingMethod = "cost_aware"    // balance bin packing density and node price
 )
 
 var (
 	PodIsDeletedError         = fmt.Errorf("pod has a deletion timestamp")
-	InsufficientResourceError = fmt.Errorf("can not find node with sufficient resources")
+	InsufficientResourceError = fornaxerrors.New(fornaxerrors.NoCapacity, "can not find node with sufficient resources")
 	PodBindToNodeError        = fmt.Errorf("Pod bind to node error")
 )
 
@@ -84,6 +93,8 @@ func BuildNodeSortingFunc(sortingMethod NodeSortingMethod) collection.LessFunc {
 		return NodeHasMoreMemorySortFunc
 	case NodeSortingMethodLessLastUse:
 		return NodeLeastLastUseSortFunc
+	case NodeSortingMethodCostAware:
+		return NodeLessCostSortFunc
 	default:
 		return NodeLeastLastUseSortFunc
 	}
@@ -93,6 +104,12 @@ type SchedulePolicy struct {
 	NumOfEvaluatedNodes int
 	BackoffDuration     time.Duration
 	NodeSortingMethod   NodeSortingMethod
+	// used by cost aware sorting method, 0 only pack pods densely, 1 only prefer cheaper nodes
+	CostWeight float64
+	// out of process schedulers called after built-in conditions, in order
+	Extenders []SchedulerExtender
+	// record every schedule attempt, optional
+	AuditLog *placement.AuditLog
 }
 
 type podScheduler struct {
@@ -107,6 +124,27 @@ type podScheduler struct {
 	ScheduleConditionBuilders []ConditionBuildFunc
 	policy                    *SchedulePolicy
 	schedulers                []*nodeChunkScheduler
+	explanations              *scheduleExplanations
+}
+
+// auditSchedule record schedule attempt of a pod in placement audit log
+func (ps *podScheduler) auditSchedule(pod *v1.Pod, explanation *ScheduleExplanation) {
+	record := &placement.Record{
+		Time:        explanation.AttemptTime,
+		Kind:        placement.KindPod,
+		Application: pod.GetLabels()[fornaxv1.LabelFornaxCoreApplication],
+		Pod:         explanation.Pod,
+		Node:        explanation.Node,
+		Result:      placement.ResultPlaced,
+		Attempt:     explanation.Attempt,
+		Score:       explanation.selectedScore(),
+		Candidates:  explanation.EvaluatedNodes,
+	}
+	if explanation.Result != ScheduleResultScheduled {
+		record.Result = placement.ResultFailed
+		record.Message = explanation.Message
+	}
+	ps.policy.AuditLog.Record(record)
 }
 
 // RemovePod remove a pod from scheduling queue
@@ -119,6 +157,35 @@ func (ps *podScheduler) AddPod(pod *v1.Pod, duration time.Duration) {
 	ps.scheduleQueue.AddPod(pod, duration)
 }
 
+func (ps *podScheduler) QueueLength() (int, int) {
+	return ps.scheduleQueue.Length()
+}
+
+func (ps *podScheduler) PodCapacity(resourceList v1.ResourceList) int {
+	cpu, memory := resourceList.Cpu().MilliValue(), resourceList.Memory().Value()
+	if cpu <= 0 && memory <= 0 {
+		return -1
+	}
+	capacity := 0
+	for _, node := range ps.nodePool.GetNodes() {
+		if node.Node != nil && (node.Node.Spec.Unschedulable || util.IsNodeUnderMemoryPressure(node.Node)) {
+			continue
+		}
+		allocatable := node.GetAllocatableResources()
+		fit := math.MaxInt32
+		if cpu > 0 {
+			fit = int(allocatable.Cpu().MilliValue() / cpu)
+		}
+		if memory > 0 {
+			if n := int(allocatable.Memory().Value() / memory); n < fit {
+				fit = n
+			}
+		}
+		capacity += fit
+	}
+	return capacity
+}
+
 func (ps *podScheduler) calcScore(node *SchedulableNode, conditions []ScheduleCondition) int {
 	allocatedResources := node.GetAllocatableResources()
 	score := 0
@@ -171,6 +238,7 @@ func (ps *podScheduler) bindNode(snode *SchedulableNode, pod *v1.Pod) error {
 		klog.ErrorS(err, "Failed to bind pod, reschedule", "node", nodeId, "pod", podName)
 		return err
 	}
+	event.Eventf(event.PodRef(pod), fornaxv1.FornaxEventTypeNormal, event.SourcePodScheduler, "Scheduled", "Assigned pod to node %s", nodeId)
 
 	return nil
 }
@@ -185,7 +253,7 @@ func (ps *podScheduler) unbindNode(node *SchedulableNode, pod *v1.Pod) {
 	pod.Status.Reason = "Schedule failed"
 }
 
-func (ps *podScheduler) schedulePod(pod *v1.Pod, candidateNodes []*SchedulableNode) error {
+func (ps *podScheduler) schedulePod(pod *v1.Pod, candidateNodes []*SchedulableNode, explanation *ScheduleExplanation) error {
 	st := time.Now().UnixMicro()
 	defer func() {
 		et := time.Now().UnixMicro()
@@ -205,11 +273,13 @@ func (ps *podScheduler) schedulePod(pod *v1.Pod, candidateNodes []*SchedulableNo
 		for _, cond := range conditions {
 			goodNode = goodNode && cond.Apply(node, &allocatedResources)
 			if !goodNode {
+				explanation.reject(node, allocatedResources, cond)
 				break
 			}
 		}
 		if goodNode {
 			availableNodes = append(availableNodes, node)
+			explanation.accept(node, allocatedResources, ps.calcScore(node, conditions))
 		}
 
 		if len(availableNodes) >= ps.policy.NumOfEvaluatedNodes {
@@ -219,22 +289,35 @@ func (ps *podScheduler) schedulePod(pod *v1.Pod, candidateNodes []*SchedulableNo
 
 	if len(availableNodes) == 0 {
 		klog.InfoS("Can not find node met condition for pod, come back later", "pod", util.Name(pod), "required resource", util.GetPodResourceList(pod))
+		event.Eventf(event.PodRef(pod), fornaxv1.FornaxEventTypeWarning, event.SourcePodScheduler, "FailedScheduling", "No node satisfies pod, %d nodes evaluated", len(candidateNodes))
 		return InsufficientResourceError
 	} else {
 		// sort candidates to use first one,
-		sortedNodes := &SortedNodes{
-			nodes:    availableNodes,
-			lessFunc: BuildNodeSortingFunc(ps.policy.NodeSortingMethod),
+		var candidates []*SchedulableNode
+		if ps.policy.NodeSortingMethod == NodeSortingMethodCostAware {
+			candidates = sortNodesByCostScore(pod, availableNodes, ps.policy.CostWeight)
+		} else {
+			sortedNodes := &SortedNodes{
+				nodes:    availableNodes,
+				lessFunc: BuildNodeSortingFunc(ps.policy.NodeSortingMethod),
+			}
+			sort.Sort(sortedNodes)
+			candidates = sortedNodes.nodes
+		}
+		nodes, err := ps.extendNodes(pod, candidates, explanation)
+		if err != nil {
+			klog.ErrorS(err, "Scheduler extenders did not return a node for pod, come back later", "pod", util.Name(pod))
+			return err
 		}
-		sort.Sort(sortedNodes)
 
 		var bindError error
-		for _, node := range sortedNodes.nodes {
+		for _, node := range nodes {
 			bindError = ps.bindNode(node, pod)
 			if bindError != nil {
 				ps.unbindNode(node, pod)
 				continue
 			}
+			explanation.selected(node)
 			break
 		}
 		if bindError != nil {
@@ -253,13 +336,16 @@ func (ps *podScheduler) updateNodePool(nodeId string, v1node *v1.Node, updateTyp
 	} else {
 		if snode := ps.nodePool.GetNode(nodeName); snode != nil {
 			snode.LastSeen = time.Now()
-			if !util.IsNodeRunning(v1node) {
+			if !util.IsNodeRunning(v1node) || v1node.Spec.Unschedulable || util.IsNodeUnderMemoryPressure(v1node) {
 				ps.nodePool.DeleteNode(nodeName)
 			}
+			snode.mu.Lock()
+			snode.Node = v1node.DeepCopy()
+			snode.mu.Unlock()
 			return snode
 		} else {
-			// only add ready node into scheduleable node list
-			if util.IsNodeRunning(v1node) {
+			// only add ready and schedulable node into scheduleable node list, node under memory pressure is added back when pressure is gone
+			if util.IsNodeRunning(v1node) && !v1node.Spec.Unschedulable && !util.IsNodeUnderMemoryPressure(v1node) {
 				snode := &SchedulableNode{
 					mu:                         sync.Mutex{},
 					NodeId:                     nodeId,
@@ -322,11 +408,11 @@ func (cps *nodeChunkScheduler) sortNodes() {
 	cps.nodes = sortedNodes.nodes
 }
 
-func (cps *nodeChunkScheduler) schedulePod(pod *v1.Pod) error {
+func (cps *nodeChunkScheduler) schedulePod(pod *v1.Pod, explanation *ScheduleExplanation) error {
 	// lock to avoid overcommit, only one pod can can be scheduled at one time
 	cps.mu.Lock()
 	defer cps.mu.Unlock()
-	return cps.scheduler.schedulePod(pod, cps.nodes)
+	return cps.scheduler.schedulePod(pod, cps.nodes, explanation)
 }
 
 func (ps *podScheduler) initializeChunkSchedulers() {
@@ -382,16 +468,20 @@ func (ps *podScheduler) Run() {
 					go func(index int) {
 						pod := pods[index]
 						var schedErr error
+						explanation := newScheduleExplanation(pod)
 						for i := 0; i < numOfScheduler; i++ {
 							scheduler := schedulers[(index+i)%numOfScheduler]
-							schedErr = scheduler.schedulePod(pod)
+							schedErr = scheduler.schedulePod(pod, explanation)
 							if schedErr == nil {
 								break
 							}
 						}
 						if schedErr != nil {
+							explanation.Message = schedErr.Error()
 							ps.scheduleQueue.BackoffPod(pod, ps.policy.BackoffDuration)
 						}
+						ps.explanations.record(explanation)
+						ps.auditSchedule(pod, explanation)
 						wg.Done()
 					}(i)
 				}
@@ -475,9 +565,12 @@ func NewPodScheduler(ctx context.Context, nodeAgent nodeagent.NodeAgentClient, n
 		ScheduleConditionBuilders: []ConditionBuildFunc{
 			NewPodCPUCondition,
 			NewPodMemoryCondition,
+			NewPodGPUCondition,
+			NewNodeNameCondition,
 		},
-		policy:     policy,
-		schedulers: []*nodeChunkScheduler{},
+		policy:       policy,
+		schedulers:   []*nodeChunkScheduler{},
+		explanations: newScheduleExplanations(DefaultMaxScheduleExplanations),
 	}
 	nodeInfoP.Watch(ps.nodeUpdateCh)
 	podInfoP.Watch(ps.podUpdateCh)
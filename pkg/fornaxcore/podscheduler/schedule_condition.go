@@ -16,6 +16,9 @@ limitations under the License.
 package podscheduler
 
 import (
+	"fmt"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
 	podutil "centaurusinfra.io/fornax-serverless/pkg/util"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -27,6 +30,10 @@ var (
 )
 
 type ScheduleCondition interface {
+	// String describe condition requirement
+	String() string
+	// RejectReason explain why a node not meeting condition is rejected
+	RejectReason() string
 	Mandatory() bool
 	Apply(node *SchedulableNode, allocatableResourceList *v1.ResourceList) bool
 	Score(node *SchedulableNode, allocatableResourceList *v1.ResourceList) int64
@@ -37,8 +44,10 @@ type ConditionBuildFunc func(*v1.Pod) ScheduleCondition
 func CalculateScheduleConditions(condBuildFuncs []ConditionBuildFunc, pod *v1.Pod) []ScheduleCondition {
 	conditions := []ScheduleCondition{}
 	for _, v := range condBuildFuncs {
-		condition := v(pod)
-		conditions = append(conditions, condition)
+		// condition not required by pod is nil
+		if condition := v(pod); condition != nil {
+			conditions = append(conditions, condition)
+		}
 	}
 	return conditions
 }
@@ -50,6 +59,14 @@ type CPUCondition struct {
 	ResourceQuantity resource.Quantity
 }
 
+func (cond *CPUCondition) String() string {
+	return fmt.Sprintf("%s %s", cond.Name, cond.ResourceQuantity.String())
+}
+
+func (cond *CPUCondition) RejectReason() string {
+	return fmt.Sprintf("insufficient %s", cond.String())
+}
+
 // Mandatory implements ScheduleCondition
 func (*CPUCondition) Mandatory() bool {
 	return true
@@ -88,6 +105,14 @@ type MemoryCondition struct {
 	ResourceQuantity resource.Quantity
 }
 
+func (cond *MemoryCondition) String() string {
+	return fmt.Sprintf("%s %s", cond.Name, cond.ResourceQuantity.String())
+}
+
+func (cond *MemoryCondition) RejectReason() string {
+	return fmt.Sprintf("insufficient %s", cond.String())
+}
+
 // Mandatory of memory condition, true always
 func (*MemoryCondition) Mandatory() bool {
 	return true
@@ -121,11 +146,64 @@ func NewPodMemoryCondition(pod *v1.Pod) ScheduleCondition {
 	}
 }
 
+var _ ScheduleCondition = &GPUCondition{}
+
+// GPUCondition require node has enough free gpus for pod, a pod without gpu is accepted by any node
+type GPUCondition struct {
+	Name             string
+	ResourceQuantity resource.Quantity
+}
+
+func (cond *GPUCondition) String() string {
+	return fmt.Sprintf("%s %s", cond.Name, cond.ResourceQuantity.String())
+}
+
+func (cond *GPUCondition) RejectReason() string {
+	return fmt.Sprintf("insufficient %s", cond.String())
+}
+
+// Mandatory implements ScheduleCondition
+func (*GPUCondition) Mandatory() bool {
+	return true
+}
+
+// check if node stastify gpu requirement
+func (cond *GPUCondition) Apply(node *SchedulableNode, allocatableResourceList *v1.ResourceList) bool {
+	if cond.ResourceQuantity.Sign() <= 0 {
+		return true
+	}
+	gpu := (*allocatableResourceList)[fornaxv1.ResourceNvidiaGPU]
+	return gpu.Cmp(cond.ResourceQuantity) >= 0
+}
+
+// calc score of gpu condition, node with less free gpus left after placing pod score higher
+func (cond *GPUCondition) Score(node *SchedulableNode, allocatableResourceList *v1.ResourceList) int64 {
+	gpu := (*allocatableResourceList)[fornaxv1.ResourceNvidiaGPU]
+	gpu.Sub(cond.ResourceQuantity)
+	return -gpu.Value() * 100
+}
+
+func NewPodGPUCondition(pod *v1.Pod) ScheduleCondition {
+	resource := (*podutil.GetPodResourceList(pod))[fornaxv1.ResourceNvidiaGPU]
+	return &GPUCondition{
+		Name:             "GPU",
+		ResourceQuantity: resource,
+	}
+}
+
 type StorageCondition struct {
 	Name             string
 	ResourceQuantity resource.Quantity
 }
 
+func (cond *StorageCondition) String() string {
+	return fmt.Sprintf("%s %s", cond.Name, cond.ResourceQuantity.String())
+}
+
+func (cond *StorageCondition) RejectReason() string {
+	return fmt.Sprintf("insufficient %s", cond.String())
+}
+
 // Mandatory implements ScheduleCondition
 func (*StorageCondition) Mandatory() bool {
 	return false
@@ -157,32 +235,42 @@ func NewStorageCondition(pod *v1.Pod) ScheduleCondition {
 
 }
 
+var _ ScheduleCondition = &NodeNameCondition{}
+
+// NodeNameCondition only accept node required by pod, e.g. node holding persistent volumes of pod instance identity
 type NodeNameCondition struct {
-	Name             string
-	ResourceQuantity resource.Quantity
+	Name   string
+	NodeId string
 }
 
-func (*NodeNameCondition) Apply(node *SchedulableNode, allocatableResourceList *v1.ResourceList) bool {
-	panic("unimplemented")
+func (cond *NodeNameCondition) String() string {
+	return fmt.Sprintf("%s %s", cond.Name, cond.NodeId)
 }
 
+// RejectReason of a node name mismatch is not a resource shortage, pod can only run on its required node
+func (cond *NodeNameCondition) RejectReason() string {
+	return fmt.Sprintf("node is not required node %s", cond.NodeId)
+}
+
+// Mandatory implements ScheduleCondition
 func (*NodeNameCondition) Mandatory() bool {
-	panic("unimplemented")
+	return true
+}
+
+func (cond *NodeNameCondition) Apply(node *SchedulableNode, allocatableResourceList *v1.ResourceList) bool {
+	return node.NodeId == cond.NodeId
 }
 
 func (*NodeNameCondition) Score(node *SchedulableNode, allocatableResourceList *v1.ResourceList) int64 {
-	panic("unimplemented")
+	return 0
 }
 
 func NewNodeNameCondition(pod *v1.Pod) ScheduleCondition {
-	resource := podutil.GetPodResourceList(pod).Cpu()
-	if resource.Sign() > 0 {
+	if nodeId, found := pod.GetAnnotations()[fornaxv1.AnnotationFornaxCoreRequiredNode]; found && len(nodeId) > 0 {
 		return &NodeNameCondition{
-			Name:             "NodeName",
-			ResourceQuantity: *resource,
+			Name:   "NodeName",
+			NodeId: nodeId,
 		}
-	} else {
-		return nil
 	}
-
+	return nil
 }
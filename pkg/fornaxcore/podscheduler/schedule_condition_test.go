@@ -0,0 +1,81 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podscheduler
+
+import (
+	"testing"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNodeNameCondition(t *testing.T) {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "app-0", Annotations: map[string]string{}}}
+	if cond := NewNodeNameCondition(pod); cond != nil {
+		t.Errorf("expected no node name condition for pod without required node, got %v", cond)
+	}
+	pod.Annotations[fornaxv1.AnnotationFornaxCoreRequiredNode] = ""
+	if cond := NewNodeNameCondition(pod); cond != nil {
+		t.Errorf("expected no node name condition for empty required node, got %v", cond)
+	}
+
+	pod.Annotations[fornaxv1.AnnotationFornaxCoreRequiredNode] = "node-1"
+	cond := NewNodeNameCondition(pod)
+	if cond == nil {
+		t.Fatalf("expected node name condition for pod with required node")
+	}
+	if !cond.Mandatory() {
+		t.Errorf("expected node name condition is mandatory")
+	}
+	if !cond.Apply(&SchedulableNode{NodeId: "node-1"}, &v1.ResourceList{}) {
+		t.Errorf("expected required node accepted")
+	}
+	if cond.Apply(&SchedulableNode{NodeId: "node-2"}, &v1.ResourceList{}) {
+		t.Errorf("expected other node rejected")
+	}
+	if score := cond.Score(&SchedulableNode{NodeId: "node-1"}, &v1.ResourceList{}); score != 0 {
+		t.Errorf("expected node name condition does not score nodes, got %d", score)
+	}
+}
+
+func TestCalculateScheduleConditionsSkipNotRequired(t *testing.T) {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "app-0"}}
+	conditions := CalculateScheduleConditions([]ConditionBuildFunc{NewNodeNameCondition}, pod)
+	if len(conditions) != 0 {
+		t.Errorf("expected condition not required by pod skipped, got %v", conditions)
+	}
+	pod.Annotations = map[string]string{fornaxv1.AnnotationFornaxCoreRequiredNode: "node-1"}
+	conditions = CalculateScheduleConditions([]ConditionBuildFunc{NewNodeNameCondition}, pod)
+	if len(conditions) != 1 {
+		t.Errorf("expected node name condition of pod, got %v", conditions)
+	}
+}
+
+func TestScheduleExplanationRejectReason(t *testing.T) {
+	cpu := &CPUCondition{Name: "CPU", ResourceQuantity: MinimumCpuRequestQuantity}
+	nodeName := &NodeNameCondition{Name: "NodeName", NodeId: "node-1"}
+	e := newScheduleExplanation(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "app-0"}})
+	e.reject(&SchedulableNode{NodeId: "node-2"}, v1.ResourceList{}, cpu)
+	e.reject(&SchedulableNode{NodeId: "node-3"}, v1.ResourceList{}, nodeName)
+	if reason := e.Nodes[0].Reason; reason != "insufficient "+cpu.String() {
+		t.Errorf("unexpected reject reason of cpu condition %s", reason)
+	}
+	if reason := e.Nodes[1].Reason; reason != "node is not required node node-1" {
+		t.Errorf("unexpected reject reason of node name condition %s", reason)
+	}
+}
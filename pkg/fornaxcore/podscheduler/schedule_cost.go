@@ -0,0 +1,141 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podscheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	DefaultNodeCostWeight = 0.5
+)
+
+var (
+	InvalidSchedulerCostConfigurationError = errors.New("scheduler cost weight must be between 0 and 1")
+)
+
+// SchedulerCostConfiguration enable cost aware node sorting, cost weight balance bin packing density against node price,
+// 0 only pack pods densely, 1 only prefer cheaper nodes
+type SchedulerCostConfiguration struct {
+	Enabled    bool    `json:"enabled,omitempty"`
+	CostWeight float64 `json:"costWeight,omitempty"`
+}
+
+// LoadSchedulerCostConfiguration read cost configuration from a json file, cost aware sorting is disabled if file does not exist
+func LoadSchedulerCostConfiguration(file string) (*SchedulerCostConfiguration, error) {
+	config := &SchedulerCostConfiguration{CostWeight: DefaultNodeCostWeight}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	if config.CostWeight < 0 || config.CostWeight > 1 {
+		return nil, InvalidSchedulerCostConfigurationError
+	}
+	return config, nil
+}
+
+// cheaper nodes are put ahead, nodes without price label are put last
+func NodeLessCostSortFunc(pi, pj interface{}) bool {
+	piCost, piFound := util.NodeHourlyCost(pi.(*SchedulableNode).Node)
+	pjCost, pjFound := util.NodeHourlyCost(pj.(*SchedulableNode).Node)
+	if piFound != pjFound {
+		return piFound
+	}
+	return piCost < pjCost
+}
+
+// nodeDensity return average fraction of node cpu and memory occupied after pod is placed, a denser node pack pods better
+func nodeDensity(node *SchedulableNode, podResource *v1.ResourceList) float64 {
+	node.mu.Lock()
+	capacity := node.ResourceList.DeepCopy()
+	node.mu.Unlock()
+	allocatable := node.GetAllocatableResources()
+
+	fraction := func(total, free, request float64) (float64, bool) {
+		if total <= 0 {
+			return 0, false
+		}
+		f := (total - free + request) / total
+		if f > 1 {
+			f = 1
+		}
+		return f, true
+	}
+	sum, n := 0.0, 0
+	if f, ok := fraction(float64(capacity.Cpu().MilliValue()), float64(allocatable.Cpu().MilliValue()), float64(podResource.Cpu().MilliValue())); ok {
+		sum, n = sum+f, n+1
+	}
+	if f, ok := fraction(float64(capacity.Memory().Value()), float64(allocatable.Memory().Value()), float64(podResource.Memory().Value())); ok {
+		sum, n = sum+f, n+1
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
+
+// sortNodesByCostScore sort nodes by weighted sum of density and node price normalized among nodes,
+// a node without price label is considered as most expensive one
+func sortNodesByCostScore(pod *v1.Pod, nodes []*SchedulableNode, costWeight float64) []*SchedulableNode {
+	podResource := util.GetPodResourceList(pod)
+	prices := make([]float64, len(nodes))
+	priced := make([]bool, len(nodes))
+	minPrice, maxPrice, anyPriced := 0.0, 0.0, false
+	for i, node := range nodes {
+		if node.Node == nil {
+			continue
+		}
+		if prices[i], priced[i] = util.NodeHourlyCost(node.Node); priced[i] {
+			if !anyPriced || prices[i] < minPrice {
+				minPrice = prices[i]
+			}
+			if !anyPriced || prices[i] > maxPrice {
+				maxPrice = prices[i]
+			}
+			anyPriced = true
+		}
+	}
+
+	scores := map[string]float64{}
+	for i, node := range nodes {
+		normalizedCost := 0.0
+		if !priced[i] {
+			normalizedCost = 1
+		} else if maxPrice > minPrice {
+			normalizedCost = (prices[i] - minPrice) / (maxPrice - minPrice)
+		}
+		scores[node.NodeId] = (1-costWeight)*nodeDensity(node, podResource) + costWeight*(1-normalizedCost)
+	}
+
+	sorted := append([]*SchedulableNode{}, nodes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return scores[sorted[i].NodeId] > scores[sorted[j].NodeId]
+	})
+	return sorted
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podscheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	fornaxstore "centaurusinfra.io/fornax-serverless/pkg/store"
+	storefactory "centaurusinfra.io/fornax-serverless/pkg/store/factory"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	ScheduleExplainPath = "/debug/fornaxcore/schedule/explain"
+
+	// max number of pods whose last schedule decision is kept, oldest one is forgotten
+	DefaultMaxScheduleExplanations = 10000
+	// max number of node details in one explanation, other nodes are only counted in rejected reasons
+	DefaultMaxExplainedNodes = 200
+)
+
+type ScheduleResult string
+
+const (
+	ScheduleResultScheduled ScheduleResult = "Scheduled"
+	ScheduleResultPending   ScheduleResult = "Pending"
+)
+
+// NodeScheduleDecision explain why a node is rejected or its score if it passed all conditions
+type NodeScheduleDecision struct {
+	Node        string          `json:"node"`
+	Allocatable v1.ResourceList `json:"allocatable,omitempty"`
+	Rejected    bool            `json:"rejected"`
+	Reason      string          `json:"reason,omitempty"`
+	Score       int             `json:"score,omitempty"`
+	// sum of weighted scores from scheduler extenders
+	ExtenderScore int64 `json:"extenderScore,omitempty"`
+	Selected      bool  `json:"selected,omitempty"`
+}
+
+// ScheduleExplanation is filter and score breakdown of last schedule attempt of a pod
+type ScheduleExplanation struct {
+	Pod         string         `json:"pod"`
+	Session     string         `json:"session,omitempty"`
+	Result      ScheduleResult `json:"result"`
+	Message     string         `json:"message,omitempty"`
+	Node        string         `json:"node,omitempty"`
+	AttemptTime time.Time      `json:"attemptTime"`
+	// number of schedule attempts of pod, since pod was first scheduled or its decision was forgotten
+	Attempt         int                     `json:"attempt"`
+	EvaluatedNodes  int                     `json:"evaluatedNodes"`
+	RejectedReasons map[string]int          `json:"rejectedReasons,omitempty"`
+	Nodes           []*NodeScheduleDecision `json:"nodes,omitempty"`
+}
+
+func newScheduleExplanation(pod *v1.Pod) *ScheduleExplanation {
+	return &ScheduleExplanation{
+		Pod:             util.Name(pod),
+		Result:          ScheduleResultPending,
+		AttemptTime:     time.Now(),
+		RejectedReasons: map[string]int{},
+		Nodes:           []*NodeScheduleDecision{},
+	}
+}
+
+func (e *ScheduleExplanation) reject(node *SchedulableNode, allocatable v1.ResourceList, cond ScheduleCondition) {
+	reason := cond.RejectReason()
+	e.EvaluatedNodes += 1
+	e.RejectedReasons[reason] += 1
+	if len(e.Nodes) < DefaultMaxExplainedNodes {
+		e.Nodes = append(e.Nodes, &NodeScheduleDecision{
+			Node:        node.NodeId,
+			Allocatable: allocatable,
+			Rejected:    true,
+			Reason:      reason,
+		})
+	}
+}
+
+// accept record a node passed all conditions, accepted node is always recorded to show final scores
+func (e *ScheduleExplanation) accept(node *SchedulableNode, allocatable v1.ResourceList, score int) {
+	e.EvaluatedNodes += 1
+	e.Nodes = append(e.Nodes, &NodeScheduleDecision{
+		Node:        node.NodeId,
+		Allocatable: allocatable,
+		Rejected:    false,
+		Score:       score,
+	})
+}
+
+// rejectByExtender mark a accepted node rejected by a scheduler extender
+func (e *ScheduleExplanation) rejectByExtender(extenderName, nodeName, reason string) {
+	reason = fmt.Sprintf("extender %s: %s", extenderName, reason)
+	e.RejectedReasons[reason] += 1
+	for _, v := range e.Nodes {
+		if v.Node == nodeName && !v.Rejected {
+			v.Rejected = true
+			v.Reason = reason
+		}
+	}
+}
+
+func (e *ScheduleExplanation) extenderScored(scores map[string]int64) {
+	for _, v := range e.Nodes {
+		if !v.Rejected {
+			v.ExtenderScore = scores[v.Node]
+		}
+	}
+}
+
+// selectedScore return score and extender score of selected node
+func (e *ScheduleExplanation) selectedScore() int64 {
+	for _, v := range e.Nodes {
+		if v.Selected {
+			return int64(v.Score) + v.ExtenderScore
+		}
+	}
+	return 0
+}
+
+func (e *ScheduleExplanation) selected(node *SchedulableNode) {
+	e.Result = ScheduleResultScheduled
+	e.Node = node.NodeId
+	e.Message = ""
+	for _, v := range e.Nodes {
+		if v.Node == node.NodeId && !v.Rejected {
+			v.Selected = true
+		}
+	}
+}
+
+// scheduleExplanations keep last schedule explanation of recent pods
+type scheduleExplanations struct {
+	mu    sync.RWMutex
+	size  int
+	items map[string]*ScheduleExplanation
+	order []string
+}
+
+func newScheduleExplanations(size int) *scheduleExplanations {
+	return &scheduleExplanations{
+		mu:    sync.RWMutex{},
+		size:  size,
+		items: map[string]*ScheduleExplanation{},
+		order: []string{},
+	}
+}
+
+func (se *scheduleExplanations) record(explanation *ScheduleExplanation) {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+	if last, found := se.items[explanation.Pod]; !found {
+		se.order = append(se.order, explanation.Pod)
+		explanation.Attempt = 1
+	} else {
+		explanation.Attempt = last.Attempt + 1
+	}
+	se.items[explanation.Pod] = explanation
+	for len(se.order) > se.size {
+		delete(se.items, se.order[0])
+		se.order = se.order[1:]
+	}
+}
+
+func (se *scheduleExplanations) get(podName string) *ScheduleExplanation {
+	se.mu.RLock()
+	defer se.mu.RUnlock()
+	return se.items[podName]
+}
+
+// Explain return last schedule decision of a pod, nil if pod was never scheduled or its decision was forgotten
+func (ps *podScheduler) Explain(podName string) *ScheduleExplanation {
+	return ps.explanations.get(podName)
+}
+
+// ScheduleExplainHandler serve schedule explanation of a pod or the pod a session is bound to,
+// e.g. kubectl get --raw "/debug/fornaxcore/schedule/explain?session=namespace/name", or fornaxctl explain session/name
+type ScheduleExplainHandler struct {
+	scheduler    *podScheduler
+	sessionStore fornaxstore.ApiStorageInterface
+}
+
+func NewScheduleExplainHandler(scheduler *podScheduler, sessionStore fornaxstore.ApiStorageInterface) *ScheduleExplainHandler {
+	return &ScheduleExplainHandler{
+		scheduler:    scheduler,
+		sessionStore: sessionStore,
+	}
+}
+
+func (h *ScheduleExplainHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	podName := r.URL.Query().Get("pod")
+	sessionName := r.URL.Query().Get("session")
+	if len(podName) == 0 && len(sessionName) == 0 {
+		http.Error(w, "pod or session query parameter is required, e.g. ?pod=namespace/name", http.StatusBadRequest)
+		return
+	}
+
+	var explanation *ScheduleExplanation
+	if len(sessionName) > 0 {
+		session, err := storefactory.GetApplicationSessionCache(h.sessionStore, sessionName)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if session == nil {
+			http.Error(w, fmt.Sprintf("session %s not found", sessionName), http.StatusNotFound)
+			return
+		}
+		if session.Status.PodReference == nil {
+			// session is not bound to a pod, it wait for a idle pod of application, application could be scheduling more pods
+			explanation = &ScheduleExplanation{
+				Session: sessionName,
+				Result:  ScheduleResultPending,
+				Message: fmt.Sprintf("session is %s, waiting for a idle pod of application %s", session.Status.SessionStatus, session.Spec.ApplicationName),
+			}
+			if session.Status.SessionStatus == fornaxv1.SessionStatusUnspecified {
+				explanation.Message = fmt.Sprintf("session is not allocated yet, waiting for a idle pod of application %s", session.Spec.ApplicationName)
+			}
+			writeExplanation(w, explanation)
+			return
+		}
+		podName = session.Status.PodReference.Name
+	}
+
+	explanation = h.scheduler.Explain(podName)
+	if explanation == nil {
+		http.Error(w, fmt.Sprintf("no schedule decision of pod %s", podName), http.StatusNotFound)
+		return
+	}
+	if len(sessionName) > 0 {
+		sessionExplanation := *explanation
+		sessionExplanation.Session = sessionName
+		explanation = &sessionExplanation
+	}
+	writeExplanation(w, explanation)
+}
+
+func writeExplanation(w http.ResponseWriter, explanation *ScheduleExplanation) {
+	data, err := json.MarshalIndent(explanation, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
@@ -0,0 +1,289 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podscheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/podscheduler/extender"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultExtenderTimeout = 100 * time.Millisecond
+)
+
+var (
+	ExtenderFilteredAllNodesError = errors.New("scheduler extender filtered out all nodes")
+)
+
+// SchedulerExtenderConfiguration configure a out of process scheduler which implement extender.SchedulerExtender grpc service
+type SchedulerExtenderConfiguration struct {
+	Name string `json:"name"`
+
+	// grpc address of extender, e.g. localhost:18010
+	Address string `json:"address"`
+
+	// call extender filter on nodes passed built-in conditions
+	// +optional
+	FilterEnabled bool `json:"filterEnabled,omitempty"`
+
+	// call extender score and prefer nodes with higher extender score, built-in sorting order break tie
+	// +optional
+	ScoreEnabled bool `json:"scoreEnabled,omitempty"`
+
+	// per call timeout, default 100ms
+	// +optional
+	TimeoutMilliseconds int `json:"timeoutMilliseconds,omitempty"`
+
+	// multiplier of extender score, default 1
+	// +optional
+	Weight int64 `json:"weight,omitempty"`
+
+	// if true, scheduler fall back to built-in result when extender fail or time out,
+	// otherwise pod is put into backoff queue and retried later
+	// +optional
+	Ignorable bool `json:"ignorable,omitempty"`
+}
+
+type SchedulerExtenderConfigurations struct {
+	Extenders []SchedulerExtenderConfiguration `json:"extenders,omitempty"`
+}
+
+// LoadSchedulerExtenderConfiguration read extenders from a json file, no extender is configured if file does not exist
+func LoadSchedulerExtenderConfiguration(file string) (*SchedulerExtenderConfigurations, error) {
+	config := &SchedulerExtenderConfigurations{}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	for _, v := range config.Extenders {
+		if len(v.Address) == 0 {
+			return nil, fmt.Errorf("scheduler extender %s does not have a address", v.Name)
+		}
+		if v.TimeoutMilliseconds < 0 || v.Weight < 0 {
+			return nil, fmt.Errorf("scheduler extender %s must not have negative timeout or weight", v.Name)
+		}
+	}
+	return config, nil
+}
+
+// SchedulerExtender filter and score candidate nodes of a pod out of fornaxcore
+type SchedulerExtender interface {
+	Name() string
+	IsIgnorable() bool
+	// Filter return nodes still schedulable and reasons of filtered nodes
+	Filter(pod *v1.Pod, nodes []*SchedulableNode) ([]*SchedulableNode, map[string]string, error)
+	// Score return weighted score of nodes, node not in result get 0
+	Score(pod *v1.Pod, nodes []*SchedulableNode) (map[string]int64, error)
+}
+
+var _ SchedulerExtender = &grpcSchedulerExtender{}
+
+type grpcSchedulerExtender struct {
+	config  SchedulerExtenderConfiguration
+	timeout time.Duration
+	conn    *grpc.ClientConn
+	client  extender.SchedulerExtenderClient
+}
+
+// NewGrpcSchedulerExtender create a extender client, connection is not blocked, it's established when first call happen
+func NewGrpcSchedulerExtender(config SchedulerExtenderConfiguration) (*grpcSchedulerExtender, error) {
+	conn, err := grpc.Dial(config.Address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	timeout := DefaultExtenderTimeout
+	if config.TimeoutMilliseconds > 0 {
+		timeout = time.Duration(config.TimeoutMilliseconds) * time.Millisecond
+	}
+	if config.Weight == 0 {
+		config.Weight = 1
+	}
+	if len(config.Name) == 0 {
+		config.Name = config.Address
+	}
+	return &grpcSchedulerExtender{
+		config:  config,
+		timeout: timeout,
+		conn:    conn,
+		client:  extender.NewSchedulerExtenderClient(conn),
+	}, nil
+}
+
+// NewGrpcSchedulerExtenders create extender clients of configurations
+func NewGrpcSchedulerExtenders(configs *SchedulerExtenderConfigurations) ([]SchedulerExtender, error) {
+	extenders := []SchedulerExtender{}
+	for _, v := range configs.Extenders {
+		e, err := NewGrpcSchedulerExtender(v)
+		if err != nil {
+			return nil, err
+		}
+		klog.InfoS("Scheduler extender configured", "name", e.Name(), "address", v.Address, "filter", v.FilterEnabled, "score", v.ScoreEnabled)
+		extenders = append(extenders, e)
+	}
+	return extenders, nil
+}
+
+func (e *grpcSchedulerExtender) Name() string {
+	return e.config.Name
+}
+
+func (e *grpcSchedulerExtender) IsIgnorable() bool {
+	return e.config.Ignorable
+}
+
+func (e *grpcSchedulerExtender) buildArgs(pod *v1.Pod, nodes []*SchedulableNode) *extender.ExtenderArgs {
+	args := &extender.ExtenderArgs{
+		Pod:   pod,
+		Nodes: []*extender.CandidateNode{},
+	}
+	for _, node := range nodes {
+		allocatable := map[string]*resource.Quantity{}
+		for k, v := range node.GetAllocatableResources() {
+			q := v.DeepCopy()
+			allocatable[string(k)] = &q
+		}
+		candidate := &extender.CandidateNode{
+			NodeName:    node.NodeId,
+			Allocatable: allocatable,
+		}
+		if node.Node != nil {
+			candidate.Labels = node.Node.Labels
+		}
+		args.Nodes = append(args.Nodes, candidate)
+	}
+	return args
+}
+
+func (e *grpcSchedulerExtender) Filter(pod *v1.Pod, nodes []*SchedulableNode) ([]*SchedulableNode, map[string]string, error) {
+	if !e.config.FilterEnabled {
+		return nodes, map[string]string{}, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+	result, err := e.client.Filter(ctx, e.buildArgs(pod, nodes))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(result.GetError()) > 0 {
+		return nil, nil, errors.New(result.GetError())
+	}
+	passed := map[string]bool{}
+	for _, v := range result.GetNodeNames() {
+		passed[v] = true
+	}
+	failedNodes := map[string]string{}
+	for k, v := range result.GetFailedNodes() {
+		failedNodes[k] = v
+	}
+	filteredNodes := []*SchedulableNode{}
+	for _, node := range nodes {
+		if passed[node.NodeId] {
+			filteredNodes = append(filteredNodes, node)
+		} else if _, found := failedNodes[node.NodeId]; !found {
+			failedNodes[node.NodeId] = "filtered"
+		}
+	}
+	return filteredNodes, failedNodes, nil
+}
+
+func (e *grpcSchedulerExtender) Score(pod *v1.Pod, nodes []*SchedulableNode) (map[string]int64, error) {
+	scores := map[string]int64{}
+	if !e.config.ScoreEnabled {
+		return scores, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+	result, err := e.client.Score(ctx, e.buildArgs(pod, nodes))
+	if err != nil {
+		return nil, err
+	}
+	if len(result.GetError()) > 0 {
+		return nil, errors.New(result.GetError())
+	}
+	for _, v := range result.GetScores() {
+		scores[v.GetNodeName()] += v.GetScore() * e.config.Weight
+	}
+	return scores, nil
+}
+
+// extendNodes call extenders to filter nodes passed built-in conditions, then sort remaining nodes by extender scores,
+// nodes are expected already sorted by built-in sorting method, it's kept for nodes with same extender score,
+// if a ignorable extender fail, its result is skipped, otherwise error is returned and pod is rescheduled later
+func (ps *podScheduler) extendNodes(pod *v1.Pod, nodes []*SchedulableNode, explanation *ScheduleExplanation) ([]*SchedulableNode, error) {
+	if len(ps.policy.Extenders) == 0 {
+		return nodes, nil
+	}
+	for _, e := range ps.policy.Extenders {
+		filteredNodes, failedNodes, err := e.Filter(pod, nodes)
+		if err != nil {
+			if e.IsIgnorable() {
+				klog.ErrorS(err, "Scheduler extender failed to filter nodes, ignore it", "extender", e.Name(), "pod", util.Name(pod))
+				continue
+			}
+			return nil, fmt.Errorf("scheduler extender %s failed to filter nodes: %v", e.Name(), err)
+		}
+		for nodeName, reason := range failedNodes {
+			explanation.rejectByExtender(e.Name(), nodeName, reason)
+		}
+		nodes = filteredNodes
+		if len(nodes) == 0 {
+			return nil, ExtenderFilteredAllNodesError
+		}
+	}
+
+	scores := map[string]int64{}
+	for _, e := range ps.policy.Extenders {
+		extenderScores, err := e.Score(pod, nodes)
+		if err != nil {
+			if e.IsIgnorable() {
+				klog.ErrorS(err, "Scheduler extender failed to score nodes, ignore it", "extender", e.Name(), "pod", util.Name(pod))
+				continue
+			}
+			return nil, fmt.Errorf("scheduler extender %s failed to score nodes: %v", e.Name(), err)
+		}
+		for k, v := range extenderScores {
+			scores[k] += v
+		}
+	}
+	if len(scores) > 0 {
+		sort.SliceStable(nodes, func(i, j int) bool {
+			return scores[nodes[i].NodeId] > scores[nodes[j].NodeId]
+		})
+		explanation.extenderScored(scores)
+	}
+	return nodes, nil
+}
@@ -20,6 +20,7 @@ import (
 	"sync"
 	"time"
 
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
 	"centaurusinfra.io/fornax-serverless/pkg/collection"
 	"centaurusinfra.io/fornax-serverless/pkg/util"
 	v1 "k8s.io/api/core/v1"
@@ -63,6 +64,12 @@ func (snode *SchedulableNode) AdmitPodOccupiedResourceList(resourceList *v1.Reso
 		nodeStorage.Set(0)
 	}
 	snode.PodPreOccupiedResourceList[v1.ResourceStorage] = nodeStorage
+
+	if gpu, found := (*resourceList)[fornaxv1.ResourceNvidiaGPU]; found {
+		nodeGPU := snode.PodPreOccupiedResourceList[fornaxv1.ResourceNvidiaGPU]
+		nodeGPU.Add(gpu)
+		snode.PodPreOccupiedResourceList[fornaxv1.ResourceNvidiaGPU] = nodeGPU
+	}
 }
 
 func (snode *SchedulableNode) GetAllocatableResources() v1.ResourceList {
@@ -94,6 +101,16 @@ func (snode *SchedulableNode) GetAllocatableResources() v1.ResourceList {
 	}
 	allocatedResources[v1.ResourceStorage] = nodeStorage
 
+	if nodeGPU, found := snode.ResourceList[fornaxv1.ResourceNvidiaGPU]; found {
+		nodeGPU = nodeGPU.DeepCopy()
+		gpu := snode.PodPreOccupiedResourceList[fornaxv1.ResourceNvidiaGPU]
+		nodeGPU.Sub(gpu)
+		if nodeGPU.Sign() <= 0 {
+			nodeGPU.Set(0)
+		}
+		allocatedResources[fornaxv1.ResourceNvidiaGPU] = nodeGPU
+	}
+
 	return allocatedResources
 }
 
@@ -123,6 +140,15 @@ func (snode *SchedulableNode) ReleasePodOccupiedResourceList(resourceList *v1.Re
 		nodeStorage.Set(0)
 	}
 	snode.PodPreOccupiedResourceList[v1.ResourceStorage] = nodeStorage
+
+	if gpu, found := (*resourceList)[fornaxv1.ResourceNvidiaGPU]; found {
+		nodeGPU := snode.PodPreOccupiedResourceList[fornaxv1.ResourceNvidiaGPU]
+		nodeGPU.Sub(gpu)
+		if nodeGPU.Sign() <= 0 {
+			nodeGPU.Set(0)
+		}
+		snode.PodPreOccupiedResourceList[fornaxv1.ResourceNvidiaGPU] = nodeGPU
+	}
 }
 
 type SortedNodes struct {
@@ -217,5 +243,10 @@ func GetNodeAllocatableResourceList(node *v1.Node) v1.ResourceList {
 		resourceList[v1.ResourceStorage] = util.ResourceQuantity(0, v1.ResourceStorage)
 	}
 
+	// gpu inventory reported by node agent, node without gpu does not have it
+	if gpu, found := res[fornaxv1.ResourceNvidiaGPU]; found && gpu.Sign() > 0 {
+		resourceList[fornaxv1.ResourceNvidiaGPU] = gpu
+	}
+
 	return resourceList
 }
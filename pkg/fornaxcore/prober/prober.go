@@ -0,0 +1,104 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prober
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+)
+
+var (
+	ProberNotFoundError = errors.New("no prober registered for health check type")
+)
+
+// Prober check a session access endpoint, return nil if endpoint is healthy,
+// endpoint address could be a ip or a dns name, it's resolved when probing
+type Prober interface {
+	Probe(ctx context.Context, endpoint fornaxv1.AccessEndPoint, check *fornaxv1.SessionHealthCheck) error
+}
+
+var (
+	probersMu sync.RWMutex
+	probers   = map[fornaxv1.SessionHealthCheckType]Prober{
+		fornaxv1.SessionHealthCheckTypeHTTP: &httpProber{client: &http.Client{}},
+		fornaxv1.SessionHealthCheckTypeTCP:  &tcpProber{},
+	}
+)
+
+// RegisterProber add or replace prober of a health check type
+func RegisterProber(checkType fornaxv1.SessionHealthCheckType, prober Prober) {
+	probersMu.Lock()
+	defer probersMu.Unlock()
+	probers[checkType] = prober
+}
+
+func getProber(checkType fornaxv1.SessionHealthCheckType) (Prober, error) {
+	probersMu.RLock()
+	defer probersMu.RUnlock()
+	if p, found := probers[checkType]; found {
+		return p, nil
+	}
+	return nil, ProberNotFoundError
+}
+
+func endpointAddress(endpoint fornaxv1.AccessEndPoint) string {
+	return net.JoinHostPort(endpoint.IPAddress, strconv.Itoa(int(endpoint.Port)))
+}
+
+type httpProber struct {
+	client *http.Client
+}
+
+func (p *httpProber) Probe(ctx context.Context, endpoint fornaxv1.AccessEndPoint, check *fornaxv1.SessionHealthCheck) error {
+	path := check.Path
+	if len(path) == 0 {
+		path = "/"
+	}
+	url := fmt.Sprintf("http://%s%s", endpointAddress(endpoint), path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("http probe %s returned status code %d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+type tcpProber struct {
+	dialer net.Dialer
+}
+
+func (p *tcpProber) Probe(ctx context.Context, endpoint fornaxv1.AccessEndPoint, check *fornaxv1.SessionHealthCheck) error {
+	conn, err := p.dialer.DialContext(ctx, "tcp", endpointAddress(endpoint))
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
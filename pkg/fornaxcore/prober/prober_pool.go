@@ -0,0 +1,206 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prober
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultNumOfProberWorkers    = 8
+	DefaultProbePeriod           = 10 * time.Second
+	DefaultProbeTimeout          = 1 * time.Second
+	DefaultProbeFailureThreshold = 3
+	proberScheduleInterval       = 1 * time.Second
+)
+
+// SessionHealthChangeFunc is called when health status of a probed session changed
+type SessionHealthChangeFunc func(session *fornaxv1.ApplicationSession, status fornaxv1.SessionHealthStatus)
+
+type probeTarget struct {
+	sessionName string
+	session     *fornaxv1.ApplicationSession
+	endpoints   []fornaxv1.AccessEndPoint
+	check       fornaxv1.SessionHealthCheck
+	status      fornaxv1.SessionHealthStatus
+	failures    int32
+	nextProbe   time.Time
+	probing     bool
+}
+
+// ProberPool run health check of open sessions with a fixed number of workers,
+// a session is marked unhealthy after failure threshold consecutive failures, and healthy again after a success
+type ProberPool struct {
+	mu         sync.Mutex
+	targets    map[string]*probeTarget
+	probeQueue chan *probeTarget
+	numWorkers int
+	onChange   SessionHealthChangeFunc
+}
+
+func NewProberPool(numWorkers int, onChange SessionHealthChangeFunc) *ProberPool {
+	return &ProberPool{
+		mu:         sync.Mutex{},
+		targets:    map[string]*probeTarget{},
+		probeQueue: make(chan *probeTarget, 1000),
+		numWorkers: numWorkers,
+		onChange:   onChange,
+	}
+}
+
+// AddSession start to probe a session, if session is already probed, its endpoints and check are updated
+func (pp *ProberPool) AddSession(session *fornaxv1.ApplicationSession, check *fornaxv1.SessionHealthCheck) {
+	if check == nil || len(session.Status.AccessEndPoints) == 0 {
+		return
+	}
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+	sessionName := util.Name(session)
+	if t, found := pp.targets[sessionName]; found {
+		t.session = session
+		t.endpoints = append([]fornaxv1.AccessEndPoint{}, session.Status.AccessEndPoints...)
+		t.check = *check
+		return
+	}
+	pp.targets[sessionName] = &probeTarget{
+		sessionName: sessionName,
+		session:     session,
+		endpoints:   append([]fornaxv1.AccessEndPoint{}, session.Status.AccessEndPoints...),
+		check:       *check,
+		status:      session.Status.HealthStatus,
+		nextProbe:   time.Now().Add(probePeriod(check)),
+	}
+}
+
+// RemoveSession stop probing a session
+func (pp *ProberPool) RemoveSession(sessionName string) {
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+	delete(pp.targets, sessionName)
+}
+
+func (pp *ProberPool) Run(ctx context.Context) {
+	for i := 0; i < pp.numWorkers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case t := <-pp.probeQueue:
+					pp.probe(ctx, t)
+				}
+			}
+		}()
+	}
+
+	go func() {
+		ticker := time.NewTicker(proberScheduleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				pp.scheduleProbes()
+			}
+		}
+	}()
+}
+
+func (pp *ProberPool) scheduleProbes() {
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+	now := time.Now()
+	for _, t := range pp.targets {
+		if t.probing || now.Before(t.nextProbe) {
+			continue
+		}
+		select {
+		case pp.probeQueue <- t:
+			t.probing = true
+		default:
+			klog.Warning("Session prober queue is full, delay remaining probes")
+			return
+		}
+	}
+}
+
+func (pp *ProberPool) probe(ctx context.Context, t *probeTarget) {
+	pp.mu.Lock()
+	endpoints := t.endpoints
+	check := t.check
+	pp.mu.Unlock()
+
+	prober, probeErr := getProber(check.Type)
+	if probeErr == nil {
+		for _, ep := range endpoints {
+			timeout := DefaultProbeTimeout
+			if check.TimeoutSeconds > 0 {
+				timeout = time.Duration(check.TimeoutSeconds) * time.Second
+			}
+			probeCtx, cancel := context.WithTimeout(ctx, timeout)
+			probeErr = prober.Probe(probeCtx, ep, &check)
+			cancel()
+			if probeErr != nil {
+				break
+			}
+		}
+	}
+
+	pp.mu.Lock()
+	t.probing = false
+	t.nextProbe = time.Now().Add(probePeriod(&check))
+	newStatus := t.status
+	if probeErr != nil {
+		klog.V(5).InfoS("Session probe failed", "session", t.sessionName, "err", probeErr)
+		t.failures += 1
+		threshold := check.FailureThreshold
+		if threshold <= 0 {
+			threshold = DefaultProbeFailureThreshold
+		}
+		if t.failures >= threshold {
+			newStatus = fornaxv1.SessionHealthStatusUnhealthy
+		}
+	} else {
+		t.failures = 0
+		newStatus = fornaxv1.SessionHealthStatusHealthy
+	}
+	changed := newStatus != t.status
+	t.status = newStatus
+	session := t.session
+	_, stillProbed := pp.targets[t.sessionName]
+	pp.mu.Unlock()
+
+	if changed && stillProbed {
+		klog.InfoS("Session health status changed", "session", t.sessionName, "status", newStatus, "err", probeErr)
+		pp.onChange(session, newStatus)
+	}
+}
+
+func probePeriod(check *fornaxv1.SessionHealthCheck) time.Duration {
+	if check.PeriodSeconds > 0 {
+		return time.Duration(check.PeriodSeconds) * time.Second
+	}
+	return DefaultProbePeriod
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc"
+	fornaxstore "centaurusinfra.io/fornax-serverless/pkg/store"
+	"google.golang.org/protobuf/encoding/protojson"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var (
+	resourcesMu sync.RWMutex
+	resources   = map[string]func() runtime.Object{
+		fornaxv1.ApplicationGrv.GroupResource().String():        func() runtime.Object { return &fornaxv1.Application{} },
+		fornaxv1.ApplicationSessionGrv.GroupResource().String(): func() runtime.Object { return &fornaxv1.ApplicationSession{} },
+	}
+)
+
+// RegisterResource tell player how to decode objects of recorded watch events of a resource
+func RegisterResource(resource string, newFunc func() runtime.Object) {
+	resourcesMu.Lock()
+	defer resourcesMu.Unlock()
+	resources[resource] = newFunc
+}
+
+func decodeObject(resource string, data json.RawMessage) (runtime.Object, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	resourcesMu.RLock()
+	newFunc, found := resources[resource]
+	resourcesMu.RUnlock()
+	if !found {
+		return nil, fmt.Errorf("resource %s is not registered to replay", resource)
+	}
+	obj := newFunc()
+	if err := json.Unmarshal(data, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// Handlers receive replayed events, a nil handler skip events of its kind,
+// Tick is called with recorded time before each event, a test can use it to advance a fake clock,
+// AfterEach is called after each event, a test can use it to check controller state at a point of event stream
+type Handlers struct {
+	Tick        func(t time.Time)
+	WatchEvent  func(resource string, we fornaxstore.WatchEventWithOldObj)
+	NodeMessage func(message *grpc.FornaxCoreMessage)
+	AfterEach   func(event *RecordedEvent)
+}
+
+// LoadRecording read recorded events and sort them by sequence number
+func LoadRecording(r io.Reader) ([]*RecordedEvent, error) {
+	events := []*RecordedEvent{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		event := &RecordedEvent{}
+		if err := json.Unmarshal(scanner.Bytes(), event); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Seq < events[j].Seq })
+	return events, nil
+}
+
+func LoadRecordingFile(file string) ([]*RecordedEvent, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadRecording(f)
+}
+
+// Player replay recorded events one by one in recorded order in caller goroutine,
+// so, a controller receive same event sequence in every run no matter how events interleaved when they were recorded
+type Player struct {
+	events   []*RecordedEvent
+	handlers Handlers
+	next     int
+}
+
+func NewPlayer(events []*RecordedEvent, handlers Handlers) *Player {
+	return &Player{events: events, handlers: handlers}
+}
+
+// Step replay next event, return false when all events are replayed
+func (p *Player) Step() (bool, error) {
+	if p.next >= len(p.events) {
+		return false, nil
+	}
+	event := p.events[p.next]
+	p.next += 1
+	if p.handlers.Tick != nil {
+		p.handlers.Tick(event.Time)
+	}
+	switch event.Kind {
+	case EventKindWatch:
+		if p.handlers.WatchEvent != nil {
+			obj, err := decodeObject(event.Resource, event.Object)
+			if err != nil {
+				return false, fmt.Errorf("failed to decode object of event %d: %v", event.Seq, err)
+			}
+			oldObj, err := decodeObject(event.Resource, event.OldObject)
+			if err != nil {
+				return false, fmt.Errorf("failed to decode old object of event %d: %v", event.Seq, err)
+			}
+			p.handlers.WatchEvent(event.Resource, fornaxstore.WatchEventWithOldObj{Type: event.Type, Object: obj, OldObject: oldObj})
+		}
+	case EventKindNode:
+		if p.handlers.NodeMessage != nil {
+			message := &grpc.FornaxCoreMessage{}
+			if err := protojson.Unmarshal(event.NodeMessage, message); err != nil {
+				return false, fmt.Errorf("failed to decode node message of event %d: %v", event.Seq, err)
+			}
+			p.handlers.NodeMessage(message)
+		}
+	default:
+		return false, fmt.Errorf("unknown kind %s of event %d", event.Kind, event.Seq)
+	}
+	if p.handlers.AfterEach != nil {
+		p.handlers.AfterEach(event)
+	}
+	return true, nil
+}
+
+// Replay replay all remaining events
+func (p *Player) Replay() error {
+	for {
+		more, err := p.Step()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+	}
+}
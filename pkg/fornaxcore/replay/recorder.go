@@ -0,0 +1,199 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc"
+	fornaxstore "centaurusinfra.io/fornax-serverless/pkg/store"
+	"google.golang.org/protobuf/encoding/protojson"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/klog/v2"
+)
+
+const (
+	EventKindWatch = "watch"
+	EventKindNode  = "node"
+)
+
+var (
+	InvalidRecorderConfigurationError = errors.New("event record file is required when recording is enabled")
+)
+
+// RecordedEvent is a store watch event handled by a controller or a message received from node, one event is saved per line
+type RecordedEvent struct {
+	Seq  int64     `json:"seq"`
+	Time time.Time `json:"time"`
+	Kind string    `json:"kind"`
+
+	// group resource of watch event
+	// +optional
+	Resource string `json:"resource,omitempty"`
+
+	// +optional
+	Type watch.EventType `json:"type,omitempty"`
+
+	// +optional
+	Object json.RawMessage `json:"object,omitempty"`
+
+	// +optional
+	OldObject json.RawMessage `json:"oldObject,omitempty"`
+
+	// node message in protobuf json format
+	// +optional
+	NodeMessage json.RawMessage `json:"nodeMessage,omitempty"`
+}
+
+// RecorderConfiguration enable recording of watch events and node messages into a file, recording is disabled by default
+type RecorderConfiguration struct {
+	Enabled bool   `json:"enabled,omitempty"`
+	File    string `json:"file,omitempty"`
+}
+
+// LoadRecorderConfiguration read recorder configuration from a json file, recording is disabled if file does not exist
+func LoadRecorderConfiguration(file string) (*RecorderConfiguration, error) {
+	config := &RecorderConfiguration{}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	if config.Enabled && len(config.File) == 0 {
+		return nil, InvalidRecorderConfigurationError
+	}
+	return config, nil
+}
+
+// Recorder save events in order they are handled, a sequence number is assigned to every event,
+// events handled concurrently by different controllers are serialized in order recorder received them
+type Recorder struct {
+	mu      sync.Mutex
+	seq     int64
+	encoder *json.Encoder
+	closer  io.Closer
+}
+
+func NewRecorder(w io.Writer) *Recorder {
+	r := &Recorder{encoder: json.NewEncoder(w)}
+	if c, ok := w.(io.Closer); ok {
+		r.closer = c
+	}
+	return r
+}
+
+// NewFileRecorder create a recorder appending to file
+func NewFileRecorder(file string) (*Recorder, error) {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return NewRecorder(f), nil
+}
+
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closer != nil {
+		return r.closer.Close()
+	}
+	return nil
+}
+
+func (r *Recorder) record(event *RecordedEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq += 1
+	event.Seq = r.seq
+	event.Time = time.Now()
+	if err := r.encoder.Encode(event); err != nil {
+		klog.ErrorS(err, "Failed to record event", "kind", event.Kind, "seq", event.Seq)
+	}
+}
+
+func (r *Recorder) RecordWatchEvent(resource string, we fornaxstore.WatchEventWithOldObj) {
+	event := &RecordedEvent{Kind: EventKindWatch, Resource: resource, Type: we.Type}
+	var err error
+	if we.Object != nil {
+		if event.Object, err = json.Marshal(we.Object); err != nil {
+			klog.ErrorS(err, "Failed to marshal watch event object", "resource", resource)
+			return
+		}
+	}
+	if we.OldObject != nil {
+		if event.OldObject, err = json.Marshal(we.OldObject); err != nil {
+			klog.ErrorS(err, "Failed to marshal watch event old object", "resource", resource)
+			return
+		}
+	}
+	r.record(event)
+}
+
+func (r *Recorder) RecordNodeMessage(message *grpc.FornaxCoreMessage) {
+	data, err := protojson.Marshal(message)
+	if err != nil {
+		klog.ErrorS(err, "Failed to marshal node message", "node", message.GetNodeIdentifier().GetIdentifier(), "msgType", message.GetMessageType())
+		return
+	}
+	r.record(&RecordedEvent{Kind: EventKindNode, NodeMessage: data})
+}
+
+var (
+	defaultRecorderMu sync.RWMutex
+	defaultRecorder   *Recorder
+)
+
+// SetRecorder set recorder used by controllers and node message handlers, nil disable recording
+func SetRecorder(r *Recorder) {
+	defaultRecorderMu.Lock()
+	defer defaultRecorderMu.Unlock()
+	defaultRecorder = r
+}
+
+func getRecorder() *Recorder {
+	defaultRecorderMu.RLock()
+	defer defaultRecorderMu.RUnlock()
+	return defaultRecorder
+}
+
+// RecordWatchEvent record a watch event before controller handle it if recording is enabled
+func RecordWatchEvent(resource string, we fornaxstore.WatchEventWithOldObj) {
+	if r := getRecorder(); r != nil {
+		r.RecordWatchEvent(resource, we)
+	}
+}
+
+// RecordNodeMessage record a node message before it's handled if recording is enabled
+func RecordNodeMessage(message *grpc.FornaxCoreMessage) {
+	if r := getRecorder(); r != nil {
+		r.RecordNodeMessage(message)
+	}
+}
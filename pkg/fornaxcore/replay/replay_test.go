@@ -0,0 +1,143 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc"
+	fornaxstore "centaurusinfra.io/fornax-serverless/pkg/store"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+var sessionResource = fornaxv1.ApplicationSessionGrv.GroupResource().String()
+
+func testSession(name string, status fornaxv1.SessionStatus) *fornaxv1.ApplicationSession {
+	return &fornaxv1.ApplicationSession{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+		Status:     fornaxv1.ApplicationSessionStatus{SessionStatus: status},
+	}
+}
+
+// recordConcurrently record session events and node messages from several goroutines, like controllers do
+func recordConcurrently(t *testing.T) []*RecordedEvent {
+	buf := &bytes.Buffer{}
+	recorder := NewRecorder(buf)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("session-%d", i)
+			recorder.RecordWatchEvent(sessionResource, fornaxstore.WatchEventWithOldObj{Type: watch.Added, Object: testSession(name, fornaxv1.SessionStatusPending)})
+			recorder.RecordNodeMessage(&grpc.FornaxCoreMessage{
+				MessageType:    grpc.MessageType_SESSION_STATE,
+				NodeIdentifier: &grpc.NodeIdentifier{Identifier: fmt.Sprintf("node-%d", i)},
+			})
+			recorder.RecordWatchEvent(sessionResource, fornaxstore.WatchEventWithOldObj{
+				Type:      watch.Modified,
+				Object:    testSession(name, fornaxv1.SessionStatusAvailable),
+				OldObject: testSession(name, fornaxv1.SessionStatusPending),
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	events, err := LoadRecording(buf)
+	if err != nil {
+		t.Fatalf("failed to load recording: %v", err)
+	}
+	if len(events) != 12 {
+		t.Fatalf("expected 12 recorded events, got %d", len(events))
+	}
+	return events
+}
+
+func replayToStrings(t *testing.T, events []*RecordedEvent) []string {
+	handled := []string{}
+	player := NewPlayer(events, Handlers{
+		WatchEvent: func(resource string, we fornaxstore.WatchEventWithOldObj) {
+			session := we.Object.(*fornaxv1.ApplicationSession)
+			old := ""
+			if we.OldObject != nil {
+				old = string(we.OldObject.(*fornaxv1.ApplicationSession).Status.SessionStatus)
+			}
+			handled = append(handled, fmt.Sprintf("%s %s %s %s->%s", resource, we.Type, session.Name, old, session.Status.SessionStatus))
+		},
+		NodeMessage: func(message *grpc.FornaxCoreMessage) {
+			handled = append(handled, fmt.Sprintf("%s %s", message.GetNodeIdentifier().GetIdentifier(), message.GetMessageType()))
+		},
+	})
+	if err := player.Replay(); err != nil {
+		t.Fatalf("failed to replay: %v", err)
+	}
+	return handled
+}
+
+func TestReplayIsDeterministic(t *testing.T) {
+	events := recordConcurrently(t)
+	for i, v := range events {
+		if v.Seq != int64(i+1) {
+			t.Fatalf("expected event %d has sequence %d, got %d", i, i+1, v.Seq)
+		}
+	}
+
+	first := replayToStrings(t, events)
+	for run := 0; run < 3; run++ {
+		again := replayToStrings(t, events)
+		if fmt.Sprint(first) != fmt.Sprint(again) {
+			t.Fatalf("replay %d is different from first replay\nfirst: %v\nagain: %v", run, first, again)
+		}
+	}
+
+	// every session is added before it's modified, same as recorded
+	added := map[string]bool{}
+	for _, v := range events {
+		if v.Kind != EventKindWatch {
+			continue
+		}
+		obj, err := decodeObject(v.Resource, v.Object)
+		if err != nil {
+			t.Fatalf("failed to decode event %d: %v", v.Seq, err)
+		}
+		name := obj.(*fornaxv1.ApplicationSession).Name
+		if v.Type == watch.Added {
+			added[name] = true
+		} else if !added[name] {
+			t.Errorf("session %s is modified before it's added in event %d", name, v.Seq)
+		}
+	}
+}
+
+func TestReplayUnknownResource(t *testing.T) {
+	buf := &bytes.Buffer{}
+	recorder := NewRecorder(buf)
+	recorder.RecordWatchEvent("unknown", fornaxstore.WatchEventWithOldObj{Type: watch.Added, Object: testSession("session", fornaxv1.SessionStatusPending)})
+	events, err := LoadRecording(buf)
+	if err != nil {
+		t.Fatalf("failed to load recording: %v", err)
+	}
+	player := NewPlayer(events, Handlers{WatchEvent: func(string, fornaxstore.WatchEventWithOldObj) {}})
+	if err := player.Replay(); err == nil {
+		t.Fatal("expected error when replaying event of unregistered resource")
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+var (
+	PodAlreadyBoundError = errors.New("pod is already bound to another session")
+
+	sessionBindings = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_session",
+			Name:           "bindings_total",
+			Help:           "Number of session binding decisions by result, conflict means a pod was about to be bound to a second session",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"result"},
+	)
+	sessionBindingLaneWait = metrics.NewHistogram(
+		&metrics.HistogramOpts{
+			Subsystem:      "fornax_session",
+			Name:           "binding_lane_wait_seconds",
+			Help:           "Time a binding decision wait for previous decisions of same application",
+			Buckets:        metrics.ExponentialBuckets(0.0001, 2, 16),
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(sessionBindings, sessionBindingLaneWait)
+}
+
+// applicationLanes run functions of one application one by one in call order, functions of different applications run in parallel,
+// a lane is a chain of tickets, every caller wait for ticket of previous caller and close its own ticket when done,
+// lane is removed when its last ticket is done, so idle applications do not hold memory
+type applicationLanes struct {
+	mu    sync.Mutex
+	tails map[string]chan struct{}
+}
+
+func newApplicationLanes() *applicationLanes {
+	return &applicationLanes{tails: map[string]chan struct{}{}}
+}
+
+func (l *applicationLanes) run(applicationKey string, fn func() error) error {
+	ticket := make(chan struct{})
+	l.mu.Lock()
+	previous := l.tails[applicationKey]
+	l.tails[applicationKey] = ticket
+	l.mu.Unlock()
+
+	defer func() {
+		l.mu.Lock()
+		if l.tails[applicationKey] == ticket {
+			delete(l.tails, applicationKey)
+		}
+		l.mu.Unlock()
+		close(ticket)
+	}()
+	if previous != nil {
+		st := time.Now()
+		<-previous
+		sessionBindingLaneWait.Observe(time.Since(st).Seconds())
+	}
+	return fn()
+}
+
+// sessionBinder decide session bindings in application lanes and remember which session every pod is bound to,
+// a pod is bound to one session at most, a binding is released when session is terminated,
+// or found stale when bound session is deleted, terminated or moved to another pod in store
+type sessionBinder struct {
+	lanes *applicationLanes
+	open  func(pod *v1.Pod, session *fornaxv1.ApplicationSession) error
+	// find latest session by name, nil if session does not exist
+	lookup func(sessionName string) *fornaxv1.ApplicationSession
+
+	mu            sync.Mutex
+	podSessions   map[string]string
+	sessionPods   map[string]string
+	sessionOwners map[string]string
+}
+
+func newSessionBinder(open func(*v1.Pod, *fornaxv1.ApplicationSession) error, lookup func(string) *fornaxv1.ApplicationSession) *sessionBinder {
+	return &sessionBinder{
+		lanes:         newApplicationLanes(),
+		open:          open,
+		lookup:        lookup,
+		podSessions:   map[string]string{},
+		sessionPods:   map[string]string{},
+		sessionOwners: map[string]string{},
+	}
+}
+
+// bind open session on pod in lane of application, it fail with PodAlreadyBoundError if pod is serving another live session,
+// rebinding a session to a new pod, e.g. target pod of migration, release its old pod
+func (b *sessionBinder) bind(applicationKey string, pod *v1.Pod, session *fornaxv1.ApplicationSession) error {
+	podName, sessionName := util.Name(pod), util.Name(session)
+	return b.lanes.run(applicationKey, func() error {
+		b.mu.Lock()
+		bound, found := b.podSessions[podName]
+		b.mu.Unlock()
+		if found && bound != sessionName {
+			// lookup outside of lock, store could be slow
+			if b.liveBinding(bound, podName) {
+				sessionBindings.WithLabelValues("conflict").Inc()
+				klog.InfoS("Reject binding session to a pod serving another session", "application", applicationKey, "pod", podName, "session", sessionName, "bound session", bound)
+				return fmt.Errorf("%w, pod %s, session %s", PodAlreadyBoundError, podName, bound)
+			}
+			b.release(bound, podName)
+		}
+		if err := b.open(pod, session); err != nil {
+			sessionBindings.WithLabelValues("error").Inc()
+			return err
+		}
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b._releaseNoLock(sessionName)
+		b.podSessions[podName] = sessionName
+		b.sessionPods[sessionName] = podName
+		b.sessionOwners[sessionName] = applicationKey
+		sessionBindings.WithLabelValues("bound").Inc()
+		return nil
+	})
+}
+
+// liveBinding tell if bound session still occupy pod, a session bound but not reported by node yet is pending without pod reference in store
+func (b *sessionBinder) liveBinding(sessionName, podName string) bool {
+	session := b.lookup(sessionName)
+	if session == nil || util.SessionInTerminalState(session) {
+		return false
+	}
+	return session.Status.PodReference == nil || session.Status.PodReference.Name == podName
+}
+
+// release binding of session, if podName is not empty, binding is released only if session is still bound to this pod,
+// a late closed report from source pod of a migrated session does not release its new pod
+func (b *sessionBinder) release(sessionName, podName string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(podName) > 0 && b.sessionPods[sessionName] != podName {
+		return
+	}
+	b._releaseNoLock(sessionName)
+}
+
+func (b *sessionBinder) _releaseNoLock(sessionName string) {
+	if podName, found := b.sessionPods[sessionName]; found {
+		if b.podSessions[podName] == sessionName {
+			delete(b.podSessions, podName)
+		}
+		delete(b.sessionPods, sessionName)
+		delete(b.sessionOwners, sessionName)
+	}
+}
+
+// checkInvariants verify every pod is bound to one session and every session is bound to one pod
+func (b *sessionBinder) checkInvariants() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.podSessions) != len(b.sessionPods) || len(b.sessionPods) != len(b.sessionOwners) {
+		return fmt.Errorf("binding index size mismatch, %d pods, %d sessions, %d owners", len(b.podSessions), len(b.sessionPods), len(b.sessionOwners))
+	}
+	for podName, sessionName := range b.podSessions {
+		if b.sessionPods[sessionName] != podName {
+			return fmt.Errorf("pod %s is bound to session %s which is bound to pod %s", podName, sessionName, b.sessionPods[sessionName])
+		}
+	}
+	return nil
+}
+
+// BindSession open session on pod, binding decisions of one application are serialized, so a pod is never bound to two sessions
+func (sm *sessionManager) BindSession(applicationKey string, pod *v1.Pod, session *fornaxv1.ApplicationSession) error {
+	return sm.binder.bind(applicationKey, pod, session)
+}
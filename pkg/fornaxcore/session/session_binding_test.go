@@ -0,0 +1,210 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testPod(app string, i int) *v1.Pod {
+	return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: fmt.Sprintf("%s-pod-%d", app, i)}}
+}
+
+func testSession(app string, i int, status fornaxv1.SessionStatus) *fornaxv1.ApplicationSession {
+	return &fornaxv1.ApplicationSession{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: fmt.Sprintf("%s-session-%d", app, i)},
+		Spec:       fornaxv1.ApplicationSessionSpec{ApplicationName: app},
+		Status:     fornaxv1.ApplicationSessionStatus{SessionStatus: status},
+	}
+}
+
+func laneTail(l *applicationLanes, key string) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.tails[key]
+}
+
+func TestApplicationLanesRunInCallOrder(t *testing.T) {
+	lanes := newApplicationLanes()
+	hold := make(chan struct{})
+	go lanes.run("ns/a", func() error { <-hold; return nil })
+	for laneTail(lanes, "ns/a") == nil {
+		time.Sleep(time.Millisecond)
+	}
+
+	order := []int{}
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		tail := laneTail(lanes, "ns/a")
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			lanes.run("ns/a", func() error { order = append(order, i); return nil })
+		}(i)
+		// wait until goroutine queued before starting next one
+		for laneTail(lanes, "ns/a") == tail {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	close(hold)
+	wg.Wait()
+	for i, v := range order {
+		if i != v {
+			t.Fatalf("expected functions run in call order, got %v", order)
+		}
+	}
+	if laneTail(lanes, "ns/a") != nil {
+		t.Errorf("expected lane removed when it's idle")
+	}
+}
+
+func TestApplicationLanesRunInParallelAcrossApplications(t *testing.T) {
+	lanes := newApplicationLanes()
+	hold := make(chan struct{})
+	defer close(hold)
+	go lanes.run("ns/a", func() error { <-hold; return nil })
+	done := make(chan error)
+	go func() { done <- lanes.run("ns/b", func() error { return errors.New("b") }) }()
+	select {
+	case err := <-done:
+		if err == nil || err.Error() != "b" {
+			t.Errorf("expected error of function returned, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("application b is blocked by application a")
+	}
+}
+
+// TestConcurrentBindingsNeverDoubleBindPod race many sessions of several applications for a few warm pods,
+// every pod must end up with exactly one session and bindings of one application never overlap
+func TestConcurrentBindingsNeverDoubleBindPod(t *testing.T) {
+	const apps, podsPerApp, sessionsPerApp = 4, 10, 50
+	nodeMu := sync.Mutex{}
+	node := map[string]string{}
+	inflight := map[string]*int32{}
+	violations := int32(0)
+	for a := 0; a < apps; a++ {
+		inflight[fmt.Sprintf("ns/app%d", a)] = new(int32)
+	}
+
+	binder := newSessionBinder(func(pod *v1.Pod, session *fornaxv1.ApplicationSession) error {
+		key := "ns/" + session.Spec.ApplicationName
+		if atomic.AddInt32(inflight[key], 1) > 1 {
+			atomic.AddInt32(&violations, 1)
+		}
+		defer atomic.AddInt32(inflight[key], -1)
+		time.Sleep(time.Duration(rand.Intn(200)) * time.Microsecond)
+		nodeMu.Lock()
+		defer nodeMu.Unlock()
+		if _, found := node[pod.Name]; found {
+			atomic.AddInt32(&violations, 1)
+		}
+		node[pod.Name] = session.Name
+		return nil
+	}, func(name string) *fornaxv1.ApplicationSession {
+		// bound sessions are still pending in store until node report back
+		return testSession("", 0, fornaxv1.SessionStatusPending)
+	})
+
+	bound := make([]int32, apps)
+	wg := sync.WaitGroup{}
+	for a := 0; a < apps; a++ {
+		app := fmt.Sprintf("app%d", a)
+		for s := 0; s < sessionsPerApp; s++ {
+			wg.Add(1)
+			go func(a, s int) {
+				defer wg.Done()
+				session := testSession(app, s, fornaxv1.SessionStatusPending)
+				for _, p := range rand.Perm(podsPerApp) {
+					err := binder.bind("ns/"+app, testPod(app, p), session)
+					if err == nil {
+						atomic.AddInt32(&bound[a], 1)
+						return
+					}
+					if !errors.Is(err, PodAlreadyBoundError) {
+						t.Errorf("unexpected bind error %v", err)
+					}
+				}
+			}(a, s)
+		}
+	}
+	wg.Wait()
+
+	if violations > 0 {
+		t.Errorf("%d bindings raced or double bound a pod", violations)
+	}
+	for a := 0; a < apps; a++ {
+		if bound[a] != podsPerApp {
+			t.Errorf("app%d, expected %d sessions bound, got %d", a, podsPerApp, bound[a])
+		}
+	}
+	if len(node) != apps*podsPerApp {
+		t.Errorf("expected %d pods bound, got %d", apps*podsPerApp, len(node))
+	}
+	if err := binder.checkInvariants(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStaleBindingReleased(t *testing.T) {
+	sessions := map[string]*fornaxv1.ApplicationSession{}
+	binder := newSessionBinder(func(*v1.Pod, *fornaxv1.ApplicationSession) error { return nil }, func(name string) *fornaxv1.ApplicationSession {
+		return sessions[name]
+	})
+	pod := testPod("a", 0)
+	s1, s2 := testSession("a", 1, fornaxv1.SessionStatusPending), testSession("a", 2, fornaxv1.SessionStatusPending)
+	sessions["ns/a-session-1"] = s1
+	if err := binder.bind("ns/a", pod, s1); err != nil {
+		t.Fatal(err)
+	}
+	if err := binder.bind("ns/a", pod, s2); !errors.Is(err, PodAlreadyBoundError) {
+		t.Fatalf("expected pod already bound error, got %v", err)
+	}
+
+	// a late release from another pod does not release binding
+	binder.release("ns/a-session-1", "ns/a-pod-9")
+	if err := binder.bind("ns/a", pod, s2); !errors.Is(err, PodAlreadyBoundError) {
+		t.Fatalf("expected pod already bound error, got %v", err)
+	}
+
+	// bound session is terminated in store, binding is stale
+	sessions["ns/a-session-1"] = testSession("a", 1, fornaxv1.SessionStatusClosed)
+	if err := binder.bind("ns/a", pod, s2); err != nil {
+		t.Fatalf("expected stale binding released, got %v", err)
+	}
+
+	// rebinding a session to another pod release its old pod
+	if err := binder.bind("ns/a", testPod("a", 1), s2); err != nil {
+		t.Fatal(err)
+	}
+	if err := binder.bind("ns/a", pod, s1); err != nil {
+		t.Fatalf("expected pod released by rebinding, got %v", err)
+	}
+	if err := binder.checkInvariants(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	fornaxstore "centaurusinfra.io/fornax-serverless/pkg/store"
+	storefactory "centaurusinfra.io/fornax-serverless/pkg/store/factory"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	"k8s.io/apimachinery/pkg/labels"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	"k8s.io/klog/v2"
+)
+
+const (
+	SessionBulkOperationPath = "/fornaxcore/session/bulk"
+)
+
+type SessionBulkOperation string
+
+const (
+	// close matched sessions, same as deleting them one by one
+	SessionBulkOperationClose SessionBulkOperation = "Close"
+
+	// set close grace period of matched sessions, it's sent to node when session is closed
+	SessionBulkOperationExtendGrace SessionBulkOperation = "ExtendGrace"
+
+	// set priority of matched sessions, it decide which pending session is bound to a pod first
+	SessionBulkOperationPrioritize SessionBulkOperation = "Prioritize"
+)
+
+var (
+	BulkOperationNamespaceRequiredError   = errors.New("namespace is required")
+	BulkOperationSelectorRequiredError    = errors.New("labelSelector is required, bulk operation on all sessions of namespace is not allowed")
+	BulkOperationGracePeriodRequiredError = errors.New("gracePeriodSeconds is required by ExtendGrace")
+	BulkOperationPriorityRequiredError    = errors.New("priority is required by Prioritize")
+)
+
+// SessionBulkRequest apply a operation to all sessions in namespace which match label selector, sessions already closed are skipped
+type SessionBulkRequest struct {
+	Namespace     string               `json:"namespace"`
+	LabelSelector string               `json:"labelSelector"`
+	Operation     SessionBulkOperation `json:"operation"`
+
+	// +optional
+	GracePeriodSeconds *uint16 `json:"gracePeriodSeconds,omitempty"`
+
+	// +optional
+	Priority *int32 `json:"priority,omitempty"`
+}
+
+type SessionBulkResult struct {
+	Matched int `json:"matched"`
+	Updated int `json:"updated"`
+
+	// error of sessions failed to update, by session name
+	// +optional
+	Failed map[string]string `json:"failed,omitempty"`
+}
+
+func (req *SessionBulkRequest) validate() (labels.Selector, error) {
+	if len(req.Namespace) == 0 {
+		return nil, BulkOperationNamespaceRequiredError
+	}
+	if len(req.LabelSelector) == 0 {
+		return nil, BulkOperationSelectorRequiredError
+	}
+	selector, err := labels.Parse(req.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	switch req.Operation {
+	case SessionBulkOperationClose:
+	case SessionBulkOperationExtendGrace:
+		if req.GracePeriodSeconds == nil {
+			return nil, BulkOperationGracePeriodRequiredError
+		}
+	case SessionBulkOperationPrioritize:
+		if req.Priority == nil {
+			return nil, BulkOperationPriorityRequiredError
+		}
+	default:
+		return nil, fmt.Errorf("unknown operation %s, must be one of Close, ExtendGrace, Prioritize", req.Operation)
+	}
+	return selector, nil
+}
+
+// apply change a copy of session, it return false if session does not need to be updated
+func (req *SessionBulkRequest) apply(session *fornaxv1.ApplicationSession) bool {
+	if util.SessionInTerminalState(session) {
+		return false
+	}
+	switch req.Operation {
+	case SessionBulkOperationClose:
+		if session.DeletionTimestamp != nil {
+			return false
+		}
+		session.DeletionTimestamp = util.NewCurrentMetaTime()
+	case SessionBulkOperationExtendGrace:
+		graceSeconds := *req.GracePeriodSeconds
+		session.Spec.CloseGracePeriodSeconds = &graceSeconds
+	case SessionBulkOperationPrioritize:
+		if session.Spec.Priority == *req.Priority {
+			return false
+		}
+		session.Spec.Priority = *req.Priority
+	}
+	return true
+}
+
+// BulkUpdateSessions list sessions of namespace matching selector and update them one by one,
+// application manager receive session update events and close or rebind sessions like they are updated by client
+func BulkUpdateSessions(ctx context.Context, sessionStore fornaxstore.ApiStorageInterface, req *SessionBulkRequest) (*SessionBulkResult, error) {
+	selector, err := req.validate()
+	if err != nil {
+		return nil, err
+	}
+	list := &fornaxv1.ApplicationSessionList{}
+	key := fmt.Sprintf("%s/%s", fornaxv1.ApplicationSessionGrvKey, req.Namespace)
+	if err := sessionStore.GetList(ctx, key, apistorage.ListOptions{Predicate: apistorage.Everything, Recursive: true}, list); err != nil {
+		return nil, err
+	}
+
+	result := &SessionBulkResult{Failed: map[string]string{}}
+	for i := range list.Items {
+		session := list.Items[i].DeepCopy()
+		if session.Namespace != req.Namespace || !selector.Matches(labels.Set(session.Labels)) {
+			continue
+		}
+		result.Matched += 1
+		if !req.apply(session) {
+			continue
+		}
+		if _, err := storefactory.UpdateApplicationSession(ctx, sessionStore, session); err != nil {
+			klog.ErrorS(err, "Failed to update session in bulk operation", "session", util.Name(session), "operation", req.Operation)
+			result.Failed[util.Name(session)] = err.Error()
+			continue
+		}
+		result.Updated += 1
+	}
+	klog.InfoS("Bulk update sessions", "namespace", req.Namespace, "selector", req.LabelSelector, "operation", req.Operation, "matched", result.Matched, "updated", result.Updated, "failed", len(result.Failed))
+	return result, nil
+}
+
+// SessionBulkOperationHandler apply a operation to a cohort of sessions selected by labels,
+// e.g. kubectl create --raw "/fornaxcore/session/bulk" -f request.json, request is a SessionBulkRequest
+type SessionBulkOperationHandler struct {
+	sessionStore fornaxstore.ApiStorageInterface
+}
+
+func NewSessionBulkOperationHandler(sessionStore fornaxstore.ApiStorageInterface) *SessionBulkOperationHandler {
+	return &SessionBulkOperationHandler{sessionStore: sessionStore}
+}
+
+func (h *SessionBulkOperationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	req := &SessionBulkRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request, %v", err), http.StatusBadRequest)
+		return
+	}
+	if _, err := req.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	result, err := BulkUpdateSessions(r.Context(), h.sessionStore, req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
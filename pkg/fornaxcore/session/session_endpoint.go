@@ -0,0 +1,94 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	fornaxstore "centaurusinfra.io/fornax-serverless/pkg/store"
+	storefactory "centaurusinfra.io/fornax-serverless/pkg/store/factory"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	SessionEndpointsPath = "/fornaxcore/session/endpoints"
+)
+
+// clientIPFamily return ip family requested by client, ipFamily query param or family of client address,
+// first address of X-Forwarded-For is client address when request come through a proxy
+func clientIPFamily(r *http.Request) v1.IPFamily {
+	if v := r.URL.Query().Get("ipFamily"); len(v) > 0 {
+		return util.ParseIPFamily(v)
+	}
+	if v := r.Header.Get("X-Forwarded-For"); len(v) > 0 {
+		return util.IPFamilyOf(strings.TrimSpace(strings.Split(v, ",")[0]))
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return util.IPFamilyOf(host)
+}
+
+// SessionEndpointsHandler return access endpoints of a session, endpoints of family requested by client come first,
+// e.g. kubectl get --raw "/fornaxcore/session/endpoints?session=<namespace>/<name>&ipFamily=IPv6"
+type SessionEndpointsHandler struct {
+	sessionStore fornaxstore.ApiStorageInterface
+}
+
+func NewSessionEndpointsHandler(sessionStore fornaxstore.ApiStorageInterface) *SessionEndpointsHandler {
+	return &SessionEndpointsHandler{sessionStore: sessionStore}
+}
+
+func (h *SessionEndpointsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sessionName := r.URL.Query().Get("session")
+	if len(sessionName) == 0 {
+		http.Error(w, "session is required", http.StatusBadRequest)
+		return
+	}
+	family := clientIPFamily(r)
+	if v := r.URL.Query().Get("ipFamily"); len(v) > 0 && len(family) == 0 {
+		http.Error(w, fmt.Sprintf("invalid ipFamily %s, must be IPv4 or IPv6", v), http.StatusBadRequest)
+		return
+	}
+	session, err := storefactory.GetApplicationSessionCache(h.sessionStore, sessionName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if session == nil || len(session.Name) == 0 {
+		http.Error(w, fmt.Sprintf("session %s not found", sessionName), http.StatusNotFound)
+		return
+	}
+	endpoints := util.PreferredAccessEndPoints(session.Status.AccessEndPoints, family)
+	data, err := json.MarshalIndent(struct {
+		IPFamily  v1.IPFamily               `json:"ipFamily,omitempty"`
+		Endpoints []fornaxv1.AccessEndPoint `json:"endpoints"`
+	}{IPFamily: family, Endpoints: endpoints}, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/event"
 	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc/nodeagent"
 	ie "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/internal"
 	fornaxstore "centaurusinfra.io/fornax-serverless/pkg/store"
@@ -30,14 +31,48 @@ import (
 	apistorage "k8s.io/apiserver/pkg/storage"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/lru"
+)
+
+const (
+	// SessionPodIndex index sessions by name of pod they are bound to
+	SessionPodIndex = "f:status.podReference.name"
+	// SessionApplicationIndex index sessions by application key, namespace/applicationName
+	SessionApplicationIndex = "f:spec.applicationName"
 )
 
 var _ ie.SessionManagerInterface = &sessionManager{}
 
+var sessionIndexers = cache.Indexers{
+	SessionPodIndex: func(obj interface{}) ([]string, error) {
+		session, ok := obj.(*fornaxv1.ApplicationSession)
+		if !ok {
+			return nil, fmt.Errorf("not a valid fornax ApplicationSession")
+		}
+		if session.Status.PodReference == nil {
+			return []string{}, nil
+		}
+		return []string{session.Status.PodReference.Name}, nil
+	},
+	SessionApplicationIndex: func(obj interface{}) ([]string, error) {
+		session, ok := obj.(*fornaxv1.ApplicationSession)
+		if !ok {
+			return nil, fmt.Errorf("not a valid fornax ApplicationSession")
+		}
+		return []string{fmt.Sprintf("%s/%s", session.Namespace, session.Spec.ApplicationName)}, nil
+	},
+}
+
 type sessionManager struct {
 	ctx             context.Context
 	nodeAgentClient nodeagent.NodeAgentClient
 	sessionStore    fornaxstore.ApiStorageInterface
+	usageAggregator *SessionUsageAggregator
+	stickyPods      *lru.Cache
+	statusUpdater   *sessionStatusUpdater
+	binder          *sessionBinder
 }
 
 func NewSessionManager(ctx context.Context, nodeAgentProxy nodeagent.NodeAgentClient, sessionStore fornaxstore.ApiStorageInterface) *sessionManager {
@@ -45,10 +80,42 @@ func NewSessionManager(ctx context.Context, nodeAgentProxy nodeagent.NodeAgentCl
 		ctx:             ctx,
 		nodeAgentClient: nodeAgentProxy,
 		sessionStore:    sessionStore,
+		stickyPods:      lru.New(DefaultSessionStickinessCacheSize),
+	}
+	if err := sessionStore.AddIndexers(sessionIndexers); err != nil {
+		klog.ErrorS(err, "Failed to add session indexers")
 	}
+	mgr.statusUpdater = newSessionStatusUpdater(mgr)
+	mgr.binder = newSessionBinder(mgr.OpenSession, func(name string) *fornaxv1.ApplicationSession {
+		session, _ := storefactory.GetApplicationSessionCache(sessionStore, name)
+		return session
+	})
+	mgr.statusUpdater.queue.Run(ctx)
 	return mgr
 }
 
+// ListPodSessions return sessions bound to a pod using session pod index
+func (sm *sessionManager) ListPodSessions(podName string) ([]*fornaxv1.ApplicationSession, error) {
+	return sm.listSessionsByIndex(SessionPodIndex, podName)
+}
+
+// ListApplicationSessions return sessions of a application using session application index
+func (sm *sessionManager) ListApplicationSessions(applicationKey string) ([]*fornaxv1.ApplicationSession, error) {
+	return sm.listSessionsByIndex(SessionApplicationIndex, applicationKey)
+}
+
+func (sm *sessionManager) listSessionsByIndex(indexName, indexedValue string) ([]*fornaxv1.ApplicationSession, error) {
+	list := &fornaxv1.ApplicationSessionList{}
+	if err := sm.sessionStore.ListByIndex(sm.ctx, fornaxv1.ApplicationSessionGrvKey, indexName, indexedValue, list); err != nil {
+		return nil, err
+	}
+	sessions := []*fornaxv1.ApplicationSession{}
+	for i := range list.Items {
+		sessions = append(sessions, &list.Items[i])
+	}
+	return sessions, nil
+}
+
 // treat node as authority for session status, session status from node could be Starting, Available, Closed,
 // use status from node to update storge status, session will be deleted if session is already closed
 // if a session from node does not exist in pool, add it
@@ -66,6 +133,9 @@ func (sm *sessionManager) OnSessionStatusFromNode(nodeId string, pod *v1.Pod, se
 			storefactory.CreateApplicationSession(sm.ctx, sm.sessionStore, session)
 		}
 	} else {
+		if ignore := syncMigrationFromNode(storeCopy, session); ignore {
+			return nil
+		}
 		if util.SessionIsOpen(session) && storeCopy.DeletionTimestamp != nil {
 			// session was requested to delete, ask node to close session
 			session.DeletionTimestamp = storeCopy.DeletionTimestamp
@@ -76,16 +146,42 @@ func (sm *sessionManager) OnSessionStatusFromNode(nodeId string, pod *v1.Pod, se
 			session.Status.AvailableTime = util.NewCurrentMetaTimeNormallized()
 			session.Status.AvailableTimeMicro = time.Now().UnixMicro()
 		}
-		if session.Status.SessionStatus == fornaxv1.SessionStatusClosed {
+		if session.Status.SessionStatus == fornaxv1.SessionStatusClosed || session.Status.SessionStatus == fornaxv1.SessionStatusFailed {
 			session.Status.CloseTime = util.NewCurrentMetaTimeNormallized()
 		}
 
+		recordSessionStatusEvent(nodeId, storeCopy, session)
 		sm.UpdateSessionStatus(storeCopy.DeepCopy(), session.Status.DeepCopy())
 	}
 
 	return nil
 }
 
+// recordSessionStatusEvent record a event when node report a session status change
+func recordSessionStatusEvent(nodeId string, storeCopy, session *fornaxv1.ApplicationSession) {
+	if storeCopy.Status.SessionStatus == session.Status.SessionStatus {
+		return
+	}
+	pod := ""
+	if session.Status.PodReference != nil {
+		pod = session.Status.PodReference.Name
+	}
+	source := event.NodeAgentSource(nodeId)
+	switch session.Status.SessionStatus {
+	case fornaxv1.SessionStatusAvailable:
+		event.Eventf(event.SessionRef(storeCopy), fornaxv1.FornaxEventTypeNormal, source, "SessionOpened", "Session is open on pod %s", pod)
+	case fornaxv1.SessionStatusClosed:
+		event.Eventf(event.SessionRef(storeCopy), fornaxv1.FornaxEventTypeNormal, source, "SessionClosed", "Session is closed on pod %s", pod)
+	case fornaxv1.SessionStatusFailed:
+		// failure reason reported by node, e.g. OOMKilled, is used as event reason
+		if len(session.Status.Reason) > 0 {
+			event.Eventf(event.SessionRef(storeCopy), fornaxv1.FornaxEventTypeWarning, source, session.Status.Reason, "Session failed on pod %s, %s", pod, session.Status.Message)
+		} else {
+			event.Eventf(event.SessionRef(storeCopy), fornaxv1.FornaxEventTypeWarning, source, "SessionFailed", "Session failed on pod %s", pod)
+		}
+	}
+}
+
 func (sm *sessionManager) CloseSession(pod *v1.Pod, session *fornaxv1.ApplicationSession) error {
 	if nodeName, found := pod.GetLabels()[fornaxv1.LabelFornaxCoreNode]; found {
 		return sm.nodeAgentClient.CloseSession(nodeName, pod, session)
@@ -94,6 +190,15 @@ func (sm *sessionManager) CloseSession(pod *v1.Pod, session *fornaxv1.Applicatio
 	}
 }
 
+// DrainSession tell application on pod that session will be closed before deadline
+func (sm *sessionManager) DrainSession(pod *v1.Pod, session *fornaxv1.ApplicationSession, reason string, deadline time.Time) error {
+	if nodeName, found := pod.GetLabels()[fornaxv1.LabelFornaxCoreNode]; found {
+		return sm.nodeAgentClient.DrainSession(nodeName, pod, session, reason, deadline)
+	} else {
+		return fmt.Errorf("Can not find which node this pod is on, %s", util.Name(pod))
+	}
+}
+
 func (sm *sessionManager) OpenSession(pod *v1.Pod, session *fornaxv1.ApplicationSession) error {
 	if nodeName, found := pod.GetLabels()[fornaxv1.LabelFornaxCoreNode]; found {
 		return sm.nodeAgentClient.OpenSession(nodeName, pod, session)
@@ -117,9 +222,23 @@ func (sm *sessionManager) Watch(ctx context.Context) (<-chan fornaxstore.WatchEv
 	return wi.ResultChanWithPrevobj(), nil
 }
 
-// UpdateApplicationSessionStatus put updated status into a map send singal into a channel to asynchronously update session status
+// UpdateSessionStatus save session status in store, a failed update is retried with backoff until it succeed or a newer status is saved
 func (sm *sessionManager) UpdateSessionStatus(session *fornaxv1.ApplicationSession, newStatus *fornaxv1.ApplicationSessionStatus) error {
-	e := sm._updateSessionStatus(util.Name(session), newStatus)
+	name := util.Name(session)
+	if newStatus.SessionStatus == fornaxv1.SessionStatusClosed || newStatus.SessionStatus == fornaxv1.SessionStatusTimeout || newStatus.SessionStatus == fornaxv1.SessionStatusFailed {
+		podName := ""
+		if newStatus.PodReference != nil {
+			podName = newStatus.PodReference.Name
+		}
+		sm.binder.release(name, podName)
+	}
+	e := sm._updateSessionStatus(name, newStatus)
+	if e != nil {
+		klog.ErrorS(e, "Failed to update session status, retry later", "session", name)
+		sm.statusUpdater.retry(name, newStatus)
+	} else {
+		sm.statusUpdater.forget(name)
+	}
 	return e
 }
 
@@ -0,0 +1,140 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"fmt"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	storefactory "centaurusinfra.io/fornax-serverless/pkg/store/factory"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// MigrateSession ask node to export session metadata and close session on pod, session is reopened on target pod after node report it closed
+func (sm *sessionManager) MigrateSession(pod *v1.Pod, session *fornaxv1.ApplicationSession, targetPod string) error {
+	if nodeName, found := pod.GetLabels()[fornaxv1.LabelFornaxCoreNode]; found {
+		return sm.nodeAgentClient.MigrateSession(nodeName, pod, session, targetPod)
+	} else {
+		return fmt.Errorf("Can not find which node this pod is on, %s", util.Name(pod))
+	}
+}
+
+// UpdateSessionMigration save migration status and remove migrate request annotation of session
+func (sm *sessionManager) UpdateSessionMigration(session *fornaxv1.ApplicationSession, migration *fornaxv1.SessionMigration) error {
+	var updateErr error
+	for i := 0; i <= 3; i++ {
+		storeCopy, err := storefactory.GetApplicationSessionCache(sm.sessionStore, util.Name(session))
+		if err != nil {
+			return err
+		}
+		if storeCopy == nil {
+			return nil
+		}
+
+		updatedSession := storeCopy.DeepCopy()
+		delete(updatedSession.Annotations, fornaxv1.AnnotationFornaxCoreMigrateSession)
+		updatedSession.Status.Migration = migration.DeepCopy()
+		_, updateErr = storefactory.UpdateApplicationSession(sm.ctx, sm.sessionStore, updatedSession)
+		if updateErr == nil {
+			session.Annotations = updatedSession.Annotations
+			session.Status.Migration = migration.DeepCopy()
+			break
+		}
+	}
+	return updateErr
+}
+
+func metadataBytes(metadata map[string]string) int {
+	size := 0
+	for k, v := range metadata {
+		size += len(k) + len(v)
+	}
+	return size
+}
+
+// syncMigrationFromNode merge session status reported by node with migration status in store, fornaxcore is authority of migration,
+// node only add metadata exported by application on source pod. session closed on source pod is put back to pending with metadata,
+// so, it's assigned to target pod like a new session, return true if report is from source pod after it closed session and should be ignored
+func syncMigrationFromNode(storeCopy, session *fornaxv1.ApplicationSession) bool {
+	if storeCopy.Status.Migration == nil {
+		session.Status.Migration = nil
+		return false
+	}
+	migration := storeCopy.Status.Migration.DeepCopy()
+	var metadata map[string]string
+	if session.Status.Migration != nil {
+		metadata = session.Status.Migration.Metadata
+	}
+	session.Status.Migration = migration
+
+	podName := ""
+	if session.Status.PodReference != nil {
+		podName = session.Status.PodReference.Name
+	}
+	if podName == migration.SourcePod && migration.SourceClosedTime != nil {
+		// session has moved away from source pod, e.g. a full sync from source node
+		return true
+	}
+	if migration.Phase != fornaxv1.SessionMigrationPhaseMigrating {
+		return false
+	}
+
+	now := util.NewCurrentMetaTimeNormallized()
+	if podName == migration.SourcePod {
+		switch {
+		case session.Status.SessionStatus == fornaxv1.SessionStatusClosed && storeCopy.DeletionTimestamp == nil:
+			klog.InfoS("Session closed on source pod of migration, reopen it on target pod", "session", util.Name(session), "requestId", util.RequestId(session), "source", podName, "target", migration.TargetPod, "metadata-bytes", metadataBytes(metadata))
+			migration.SourceClosedTime = now
+			migration.Metadata = metadata
+			if size := metadataBytes(metadata); size > fornaxv1.MaxSessionMigrationMetadataBytes {
+				migration.Metadata = nil
+				migration.Message = fmt.Sprintf("session metadata of %d bytes is larger than %d bytes, it's not passed to target pod", size, fornaxv1.MaxSessionMigrationMetadataBytes)
+			}
+			session.Status.SessionStatus = fornaxv1.SessionStatusPending
+			session.Status.PodReference = nil
+			session.Status.AccessEndPoints = nil
+			session.Status.PublicEndPoints = nil
+			session.Status.Relay = nil
+			session.Status.ClientSessions = []fornaxv1.ClientSessionReference{}
+			session.Status.ClientSessionCount = 0
+			session.Status.HealthStatus = fornaxv1.SessionHealthStatusUnknown
+			session.Status.AvailableTime = nil
+			session.Status.CloseTime = nil
+			session.Status.QualityScore = nil
+		case util.SessionInTerminalState(session):
+			migration.Phase = fornaxv1.SessionMigrationPhaseFailed
+			migration.Message = fmt.Sprintf("session %s on source pod before it's migrated", session.Status.SessionStatus)
+			migration.CompletionTime = now
+		}
+		return false
+	}
+
+	switch {
+	case session.Status.SessionStatus == fornaxv1.SessionStatusAvailable || session.Status.SessionStatus == fornaxv1.SessionStatusInUse:
+		klog.InfoS("Session migrated", "session", util.Name(session), "requestId", util.RequestId(session), "source", migration.SourcePod, "target", podName)
+		migration.Phase = fornaxv1.SessionMigrationPhaseMigrated
+		migration.TargetPod = podName
+		migration.CompletionTime = now
+	case util.SessionInTerminalState(session):
+		migration.Phase = fornaxv1.SessionMigrationPhaseFailed
+		migration.Message = fmt.Sprintf("session %s on target pod %s", session.Status.SessionStatus, podName)
+		migration.CompletionTime = now
+	}
+	return false
+}
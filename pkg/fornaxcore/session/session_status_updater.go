@@ -0,0 +1,82 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"context"
+	"sync"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/controller"
+)
+
+const (
+	DefaultNumOfSessionStatusWorkers = 4
+)
+
+// sessionStatusUpdater retry session status updates failed in store, only latest failed status of a session is kept,
+// a later successful update of session drop it, so a retry never overwrite a newer status
+type sessionStatusUpdater struct {
+	mu             sync.Mutex
+	changes        map[string]*fornaxv1.ApplicationSessionStatus
+	sessionManager *sessionManager
+	queue          *controller.Controller
+}
+
+func newSessionStatusUpdater(sessionManager *sessionManager) *sessionStatusUpdater {
+	su := &sessionStatusUpdater{
+		changes:        map[string]*fornaxv1.ApplicationSessionStatus{},
+		sessionManager: sessionManager,
+	}
+	su.queue = controller.NewController("fornaxv1.ApplicationSessionStatus", DefaultNumOfSessionStatusWorkers, su.sync)
+	return su
+}
+
+// retry save failed status of session and enqueue session
+func (su *sessionStatusUpdater) retry(sessionName string, status *fornaxv1.ApplicationSessionStatus) {
+	su.mu.Lock()
+	su.changes[sessionName] = status.DeepCopy()
+	su.mu.Unlock()
+	su.queue.Enqueue(sessionName)
+}
+
+// forget drop failed status of session after a newer status is saved
+func (su *sessionStatusUpdater) forget(sessionName string) {
+	su.mu.Lock()
+	defer su.mu.Unlock()
+	delete(su.changes, sessionName)
+}
+
+func (su *sessionStatusUpdater) sync(ctx context.Context, sessionName string) error {
+	su.mu.Lock()
+	status, found := su.changes[sessionName]
+	su.mu.Unlock()
+	if !found {
+		return nil
+	}
+	if err := su.sessionManager._updateSessionStatus(sessionName, status); err != nil {
+		return err
+	}
+
+	su.mu.Lock()
+	defer su.mu.Unlock()
+	// keep status if session failed again with a newer status while this one was saved
+	if su.changes[sessionName] == status {
+		delete(su.changes, sessionName)
+	}
+	return nil
+}
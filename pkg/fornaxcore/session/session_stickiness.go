@@ -0,0 +1,51 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"fmt"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+)
+
+const (
+	// least recently used stickiness keys are forgot when there are more keys, a forgot key's next session go to any pod
+	DefaultSessionStickinessCacheSize = 100000
+)
+
+func stickinessKey(session *fornaxv1.ApplicationSession) string {
+	return fmt.Sprintf("%s/%s/%s", session.Namespace, session.Spec.ApplicationName, session.Spec.StickinessKey)
+}
+
+// StickyPod return pod which served last session of session's stickiness key, pod may be gone or busy, caller check it
+func (sm *sessionManager) StickyPod(session *fornaxv1.ApplicationSession) string {
+	if len(session.Spec.StickinessKey) == 0 {
+		return ""
+	}
+	if pod, found := sm.stickyPods.Get(stickinessKey(session)); found {
+		return pod.(string)
+	}
+	return ""
+}
+
+// RememberStickyPod record pod serving session as sticky pod of its stickiness key
+func (sm *sessionManager) RememberStickyPod(session *fornaxv1.ApplicationSession, podName string) {
+	if len(session.Spec.StickinessKey) == 0 {
+		return
+	}
+	sm.stickyPods.Add(stickinessKey(session), podName)
+}
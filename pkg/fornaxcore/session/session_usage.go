@@ -0,0 +1,291 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc"
+	ie "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/internal"
+	fornaxstore "centaurusinfra.io/fornax-serverless/pkg/store"
+	storefactory "centaurusinfra.io/fornax-serverless/pkg/store/factory"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// usage records are buffered and saved in batch, a session usage is updated once in a flush period
+	DefaultSessionUsageFlushPeriod = 10 * time.Second
+)
+
+var (
+	sessionUsageCPUSeconds = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_session_usage",
+			Name:           "cpu_core_seconds_total",
+			Help:           "Cpu time used by sessions of application reported by node agents",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"application"},
+	)
+	sessionUsageCost = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_session_usage",
+			Name:           "estimated_cost_total",
+			Help:           "Estimated cost of sessions of application in price unit of node price labels",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"application"},
+	)
+	sessionUsageMemoryMebibyteSeconds = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_session_usage",
+			Name:           "memory_mebibyte_seconds_total",
+			Help:           "Working set memory integrated over time of sessions of application reported by node agents",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"application"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(sessionUsageCPUSeconds, sessionUsageMemoryMebibyteSeconds, sessionUsageCost)
+}
+
+type sessionUsageRecord struct {
+	nodeId     string
+	usage      *grpc.SessionUsage
+	costMicros int64
+}
+
+// estimateCostMicros return share of node price used by a usage record, node is fully used by record if it used all cpu or memory of node
+func estimateCostMicros(node *v1.Node, usage *grpc.SessionUsage) int64 {
+	price, found := util.NodeHourlyCost(node)
+	if !found {
+		return 0
+	}
+	nodeSeconds := 0.0
+	if cpu := node.Status.Capacity.Cpu().MilliValue(); cpu > 0 {
+		nodeSeconds = float64(usage.GetCpuUsageNanoSeconds()) / float64(time.Second) / (float64(cpu) / 1000)
+	}
+	if memory := node.Status.Capacity.Memory().Value(); memory > 0 {
+		if s := float64(usage.GetMemoryByteSeconds()) / float64(memory); s > nodeSeconds {
+			nodeSeconds = s
+		}
+	}
+	return int64(nodeSeconds * price / 3600 * 1e6)
+}
+
+// SessionUsageAggregator fold session usage records reported by node agents into SessionUsage of session,
+// a record ending before last saved interval of its pod is a duplicate and ignored
+type SessionUsageAggregator struct {
+	ctx          context.Context
+	mu           sync.Mutex
+	store        fornaxstore.ApiStorageInterface
+	sessionStore fornaxstore.ApiStorageInterface
+	nodeInfo     ie.NodeInfoProviderInterface
+	pending      map[string][]sessionUsageRecord
+}
+
+func NewSessionUsageAggregator(ctx context.Context, sessionManager *sessionManager, store fornaxstore.ApiStorageInterface, nodeInfo ie.NodeInfoProviderInterface) *SessionUsageAggregator {
+	a := &SessionUsageAggregator{
+		ctx:          ctx,
+		store:        store,
+		sessionStore: sessionManager.sessionStore,
+		nodeInfo:     nodeInfo,
+		pending:      map[string][]sessionUsageRecord{},
+	}
+	sessionManager.usageAggregator = a
+	return a
+}
+
+func (a *SessionUsageAggregator) Run() {
+	klog.Info("Starting session usage aggregator")
+	go func() {
+		ticker := time.NewTicker(DefaultSessionUsageFlushPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-a.ctx.Done():
+				a.flush()
+				return
+			case <-ticker.C:
+				a.flush()
+			}
+		}
+	}()
+}
+
+// Report buffer a usage record, it's saved in next flush
+func (a *SessionUsageAggregator) Report(nodeId string, usage *grpc.SessionUsage) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	sessionId := usage.GetSessionIdentifier()
+	a.pending[sessionId] = append(a.pending[sessionId], sessionUsageRecord{nodeId: nodeId, usage: usage})
+}
+
+// OnSessionUsageFromNode pass usage record reported by node to usage aggregator, usage is dropped if aggregator is not enabled
+func (sm *sessionManager) OnSessionUsageFromNode(nodeId string, usage *grpc.SessionUsage) {
+	if sm.usageAggregator != nil {
+		sm.usageAggregator.Report(nodeId, usage)
+	}
+}
+
+func (a *SessionUsageAggregator) flush() {
+	a.mu.Lock()
+	pending := a.pending
+	a.pending = map[string][]sessionUsageRecord{}
+	a.mu.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	nodes := map[string]*v1.Node{}
+	for _, v := range a.nodeInfo.List() {
+		nodes[v.NodeId] = v.Node
+	}
+	for sessionId, records := range pending {
+		for i, r := range records {
+			if node, found := nodes[r.nodeId]; found && node != nil {
+				records[i].costMicros = estimateCostMicros(node, r.usage)
+			}
+		}
+		if err := a.saveSessionUsage(sessionId, records); err != nil {
+			klog.ErrorS(err, "Failed to save session usage, usage records are dropped", "session", sessionId, "records", len(records))
+		}
+	}
+}
+
+func mergeSessionUsage(usage *fornaxv1.SessionUsage, records []sessionUsageRecord) []sessionUsageRecord {
+	merged := []sessionUsageRecord{}
+	for _, r := range records {
+		start, end := metav1.NewTime(r.usage.GetStartTime().AsTime()), metav1.NewTime(r.usage.GetEndTime().AsTime())
+		var podUsage *fornaxv1.SessionPodUsage
+		for i := range usage.Status.Pods {
+			if usage.Status.Pods[i].PodName == r.usage.GetPodIdentifier() {
+				podUsage = &usage.Status.Pods[i]
+				break
+			}
+		}
+		if podUsage == nil {
+			usage.Status.Pods = append(usage.Status.Pods, fornaxv1.SessionPodUsage{PodName: r.usage.GetPodIdentifier(), NodeName: r.nodeId, StartTime: &start})
+			podUsage = &usage.Status.Pods[len(usage.Status.Pods)-1]
+		}
+		if podUsage.Final || (podUsage.EndTime != nil && !end.After(podUsage.EndTime.Time)) {
+			continue
+		}
+
+		cpu := int64(r.usage.GetCpuUsageNanoSeconds() / uint64(time.Millisecond))
+		memory := int64(r.usage.GetMemoryByteSeconds() / (1024 * 1024))
+		peak := int64(r.usage.GetMemoryPeakBytes())
+		podUsage.CPUMilliCoreSeconds += cpu
+		podUsage.MemoryMebibyteSeconds += memory
+		podUsage.EstimatedCostMicros += r.costMicros
+		podUsage.EndTime = &end
+		podUsage.Final = r.usage.GetFinal()
+		if peak > podUsage.MemoryPeakBytes {
+			podUsage.MemoryPeakBytes = peak
+		}
+
+		usage.Status.CPUMilliCoreSeconds += cpu
+		usage.Status.MemoryMebibyteSeconds += memory
+		usage.Status.EstimatedCostMicros += r.costMicros
+		if peak > usage.Status.MemoryPeakBytes {
+			usage.Status.MemoryPeakBytes = peak
+		}
+		if usage.Status.StartTime == nil || start.Before(usage.Status.StartTime) {
+			usage.Status.StartTime = start.DeepCopy()
+		}
+		if usage.Status.EndTime == nil || usage.Status.EndTime.Before(&end) {
+			usage.Status.EndTime = end.DeepCopy()
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// saveSessionUsage merge records into session usage in store, usage is created when first records of session are saved
+func (a *SessionUsageAggregator) saveSessionUsage(sessionId string, records []sessionUsageRecord) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(sessionId)
+	if err != nil {
+		return err
+	}
+	key := fmt.Sprintf("%s/%s", fornaxv1.SessionUsageGrvKey, sessionId)
+
+	var merged []sessionUsageRecord
+	existing := &fornaxv1.SessionUsage{}
+	err = a.store.Get(a.ctx, key, apistorage.GetOptions{IgnoreNotFound: false}, existing)
+	if err != nil && fornaxstore.IsObjectNotFoundErr(err) {
+		usage := &fornaxv1.SessionUsage{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "SessionUsage",
+				APIVersion: fornaxv1.SessionUsageGrv.GroupVersion().String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         namespace,
+				Name:              name,
+				CreationTimestamp: metav1.Now(),
+			},
+		}
+		if session, _ := storefactory.GetApplicationSessionCache(a.sessionStore, sessionId); session != nil {
+			usage.Spec.ApplicationName = session.Spec.ApplicationName
+			usage.Spec.SessionUID = string(session.UID)
+			usage.Labels = map[string]string{fornaxv1.LabelFornaxCoreApplication: session.Spec.ApplicationName}
+		}
+		merged = mergeSessionUsage(usage, records)
+		err = a.store.Create(a.ctx, key, usage, &fornaxv1.SessionUsage{}, 0)
+		if err == nil {
+			a.observe(usage, merged)
+			return nil
+		}
+	}
+	if err != nil && !apistorage.IsExist(err) {
+		return err
+	}
+
+	// merge records into latest usage in store
+	usage := &fornaxv1.SessionUsage{}
+	if err := a.store.GuaranteedUpdate(a.ctx, key, usage, false, nil, func(input runtime.Object, res apistorage.ResponseMeta) (runtime.Object, *uint64, error) {
+		updated := input.(*fornaxv1.SessionUsage).DeepCopy()
+		merged = mergeSessionUsage(updated, records)
+		return updated, nil, nil
+	}, nil); err != nil {
+		return err
+	}
+	a.observe(usage, merged)
+	return nil
+}
+
+func (a *SessionUsageAggregator) observe(usage *fornaxv1.SessionUsage, records []sessionUsageRecord) {
+	application := fmt.Sprintf("%s/%s", usage.Namespace, usage.Spec.ApplicationName)
+	for _, r := range records {
+		sessionUsageCPUSeconds.WithLabelValues(application).Add(float64(r.usage.GetCpuUsageNanoSeconds()) / float64(time.Second))
+		sessionUsageMemoryMebibyteSeconds.WithLabelValues(application).Add(float64(r.usage.GetMemoryByteSeconds()) / (1024 * 1024))
+		sessionUsageCost.WithLabelValues(application).Add(float64(r.costMicros) / 1e6)
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"context"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	ie "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/internal"
+	"centaurusinfra.io/fornax-serverless/pkg/store/inmemory"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// RegisterSessionValidators register session invariants checked by memory store before a session is stored
+func RegisterSessionValidators(podManager ie.PodManagerInterface) {
+	inmemory.RegisterValidator(fornaxv1.ApplicationSessionGrv.GroupResource(), newSessionPodValidator(podManager))
+}
+
+// newSessionPodValidator reject a session bound to a pod which fornaxcore does not know,
+// only a new or changed pod reference is checked, a session keep its reference after its pod is gone
+func newSessionPodValidator(podManager ie.PodManagerInterface) inmemory.ValidatorFunc {
+	return func(ctx context.Context, obj runtime.Object, oldObj runtime.Object) field.ErrorList {
+		session, ok := obj.(*fornaxv1.ApplicationSession)
+		if !ok || session.Status.PodReference == nil {
+			return nil
+		}
+		podName := session.Status.PodReference.Name
+		if oldSession, ok := oldObj.(*fornaxv1.ApplicationSession); ok && oldSession.Status.PodReference != nil && oldSession.Status.PodReference.Name == podName {
+			return nil
+		}
+		if podManager.FindPod(podName) == nil {
+			return field.ErrorList{field.NotFound(field.NewPath("Status", "PodReference", "Name"), podName)}
+		}
+		return nil
+	}
+}
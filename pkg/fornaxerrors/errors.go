@@ -0,0 +1,164 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fornaxerrors define error codes shared by fornaxcore, node agent and clients,
+// a code is carried in grpc status details, api status causes and pod/session status reasons,
+// so, callers branch on code instead of matching error message
+package fornaxerrors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Code is a machine readable cause of a error, it's also used as status reason
+type Code string
+
+const (
+	Unknown                   Code = ""
+	ImagePullFailed           Code = "ImagePullFailed"
+	NoCapacity                Code = "NoCapacity"
+	SessionServiceUnavailable Code = "SessionServiceUnavailable"
+	QuotaExceeded             Code = "QuotaExceeded"
+	AdmissionRejected         Code = "AdmissionRejected"
+	OOMKilled                 Code = "OOMKilled"
+	NetworkPolicyFailed       Code = "NetworkPolicyFailed"
+)
+
+// Domain is ErrorInfo domain of fornax error codes in grpc status details
+const Domain = "fornax-serverless.centaurusinfra.io"
+
+// codeProperties is how a code is surfaced in grpc and api errors
+var codeProperties = map[Code]struct {
+	grpcCode   codes.Code
+	httpCode   int32
+	statusCode metav1.StatusReason
+}{
+	ImagePullFailed:           {codes.FailedPrecondition, http.StatusInternalServerError, metav1.StatusReasonInternalError},
+	NetworkPolicyFailed:       {codes.FailedPrecondition, http.StatusInternalServerError, metav1.StatusReasonInternalError},
+	NoCapacity:                {codes.Unavailable, http.StatusServiceUnavailable, metav1.StatusReasonServiceUnavailable},
+	SessionServiceUnavailable: {codes.Unavailable, http.StatusServiceUnavailable, metav1.StatusReasonServiceUnavailable},
+	QuotaExceeded:             {codes.ResourceExhausted, http.StatusForbidden, metav1.StatusReasonForbidden},
+	AdmissionRejected:         {codes.PermissionDenied, http.StatusForbidden, metav1.StatusReasonForbidden},
+	OOMKilled:                 {codes.ResourceExhausted, http.StatusInternalServerError, metav1.StatusReasonInternalError},
+}
+
+// FornaxError is a error with a code, it's converted to a grpc status carrying code in a ErrorInfo detail
+type FornaxError struct {
+	Code    Code
+	Message string
+	Err     error
+}
+
+func (e *FornaxError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *FornaxError) Unwrap() error {
+	return e.Err
+}
+
+// GRPCStatus is used by grpc to convert a error returned by grpc handler into status
+func (e *FornaxError) GRPCStatus() *grpcstatus.Status {
+	grpcCode := codes.Unknown
+	if p, found := codeProperties[e.Code]; found {
+		grpcCode = p.grpcCode
+	}
+	s := grpcstatus.New(grpcCode, e.Error())
+	if ds, err := s.WithDetails(&errdetails.ErrorInfo{Reason: string(e.Code), Domain: Domain}); err == nil {
+		return ds
+	}
+	return s
+}
+
+func New(code Code, message string) *FornaxError {
+	return &FornaxError{Code: code, Message: message}
+}
+
+func Errorf(code Code, format string, args ...interface{}) *FornaxError {
+	return &FornaxError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap add a code to a error, message of wrapped error is kept
+func Wrap(code Code, message string, err error) *FornaxError {
+	return &FornaxError{Code: code, Message: message, Err: err}
+}
+
+// NewAPIError return a api status error of a object, code is added as a status cause, so, clients get it using CodeOf
+func NewAPIError(code Code, qualifiedResource schema.GroupResource, name string, err error) *apierrors.StatusError {
+	p, found := codeProperties[code]
+	if !found {
+		p.httpCode, p.statusCode = http.StatusInternalServerError, metav1.StatusReasonInternalError
+	}
+	return &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    p.httpCode,
+		Reason:  p.statusCode,
+		Message: fmt.Sprintf("%s %q is rejected: %v", qualifiedResource.String(), name, err),
+		Details: &metav1.StatusDetails{
+			Group: qualifiedResource.Group,
+			Kind:  qualifiedResource.Resource,
+			Name:  name,
+			Causes: []metav1.StatusCause{{
+				Type:    metav1.CauseType(code),
+				Message: err.Error(),
+			}},
+		},
+	}}
+}
+
+// CodeOf return code of a error, it understand FornaxError, grpc status with fornax ErrorInfo and api status error created by NewAPIError
+func CodeOf(err error) Code {
+	if err == nil {
+		return Unknown
+	}
+	fe := &FornaxError{}
+	if errors.As(err, &fe) {
+		return fe.Code
+	}
+	if s, ok := grpcstatus.FromError(err); ok {
+		for _, d := range s.Details() {
+			if info, ok := d.(*errdetails.ErrorInfo); ok && info.Domain == Domain {
+				return Code(info.Reason)
+			}
+		}
+	}
+	se := &apierrors.StatusError{}
+	if errors.As(err, &se) && se.ErrStatus.Details != nil {
+		for _, v := range se.ErrStatus.Details.Causes {
+			if _, found := codeProperties[Code(v.Type)]; found {
+				return Code(v.Type)
+			}
+		}
+	}
+	return Unknown
+}
+
+// Is return true if error has code
+func Is(err error, code Code) bool {
+	return code != Unknown && CodeOf(err) == code
+}
@@ -29,6 +29,7 @@ import (
 
 	"github.com/google/cadvisor/cache/memory"
 	cadvisormetrics "github.com/google/cadvisor/container"
+	"github.com/google/cadvisor/events"
 	cadvisorinfov1 "github.com/google/cadvisor/info/v1"
 	cadvisorinfov2 "github.com/google/cadvisor/info/v2"
 	"github.com/google/cadvisor/manager"
@@ -65,6 +66,38 @@ func (c *cadvisorInfoProvider) GetNodeCAdvisorInfo() (*NodeCAdvisorInfo, error)
 	return c.nodeCAdvisorInfo, nil
 }
 
+// GetContainerStats implements CAdvisorInfoProvider
+// return latest stats of a cgroup, cgroupName is cgroupfs name of cgroup, e.g. pod cgroup parent
+func (c *cadvisorInfoProvider) GetContainerStats(cgroupName string) (*cadvisorinfov2.ContainerStats, error) {
+	options := cadvisorinfov2.RequestOptions{
+		IdType:    cadvisorinfov2.TypeName,
+		Count:     1,
+		Recursive: false,
+	}
+	infos, err := c.realCAdvisor.GetContainerInfoV2(cgroupName, options)
+	if err != nil {
+		return nil, err
+	}
+	info, found := infos[cgroupName]
+	if !found || len(info.Stats) == 0 {
+		return nil, fmt.Errorf("no cadvisor stats of cgroup %s", cgroupName)
+	}
+	return info.Stats[len(info.Stats)-1], nil
+}
+
+// GetOOMKillEvents implements CAdvisorInfoProvider
+// return oom kill events of cgroup and its sub cgroups since a time, event carry victim process pid and name
+func (c *cadvisorInfoProvider) GetOOMKillEvents(cgroupName string, since time.Time) ([]*cadvisorinfov1.Event, error) {
+	return c.realCAdvisor.GetPastEvents(&events.Request{
+		StartTime:            since,
+		EndTime:              time.Now(),
+		EventType:            map[cadvisorinfov1.EventType]bool{cadvisorinfov1.EventOomKill: true},
+		MaxEventsReturned:    10,
+		ContainerName:        cgroupName,
+		IncludeSubcontainers: true,
+	})
+}
+
 // Stop implements CAdvisorInfoProvider
 func (cc *cadvisorInfoProvider) Stop() error {
 	cc.done = true
@@ -17,6 +17,8 @@ limitations under the License.
 package cadvisor
 
 import (
+	"time"
+
 	cadvisorv1 "github.com/google/cadvisor/info/v1"
 	cadvisorv2 "github.com/google/cadvisor/info/v2"
 )
@@ -34,4 +36,6 @@ type CAdvisorInfoProvider interface {
 	Stop() error
 	ReceiveCAdvisorInfo(id string, receiver *chan NodeCAdvisorInfo)
 	GetNodeCAdvisorInfo() (*NodeCAdvisorInfo, error)
+	GetContainerStats(cgroupName string) (*cadvisorv2.ContainerStats, error)
+	GetOOMKillEvents(cgroupName string, since time.Time) ([]*cadvisorv1.Event, error)
 }
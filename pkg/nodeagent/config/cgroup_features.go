@@ -0,0 +1,73 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"os"
+	"strings"
+
+	libcontainercgroups "github.com/opencontainers/runc/libcontainer/cgroups"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+const (
+	cgroupV2ControllersFile = "/sys/fs/cgroup/cgroup.controllers"
+)
+
+// CgroupFeatures is what node cgroup hierarchy support, detected once when node agent start
+type CgroupFeatures struct {
+	UnifiedMode bool
+	Controllers sets.String
+}
+
+// MemoryQoS tell if memory.min and memory.high can be set, it requires cgroup v2 memory controller
+func (f CgroupFeatures) MemoryQoS() bool {
+	return f.UnifiedMode && f.Controllers.Has("memory")
+}
+
+// CPUWeight tell if cpu.weight can be set, it requires cgroup v2 cpu controller
+func (f CgroupFeatures) CPUWeight() bool {
+	return f.UnifiedMode && f.Controllers.Has("cpu")
+}
+
+func detectCgroupFeatures() CgroupFeatures {
+	features := CgroupFeatures{UnifiedMode: libcontainercgroups.IsCgroup2UnifiedMode(), Controllers: sets.NewString()}
+	if !features.UnifiedMode {
+		return features
+	}
+	data, err := os.ReadFile(cgroupV2ControllersFile)
+	if err != nil {
+		klog.ErrorS(err, "Failed to read cgroup v2 controllers", "file", cgroupV2ControllersFile)
+		return features
+	}
+	features.Controllers.Insert(strings.Fields(string(data))...)
+	return features
+}
+
+// DetectCgroupFeatures check node cgroup hierarchy and save it in node config, memory qos is turned off if node can not enforce it,
+// so a node running cgroup v1 still start with default configuration
+func DetectCgroupFeatures(nodeConfig *NodeConfiguration) CgroupFeatures {
+	features := detectCgroupFeatures()
+	nodeConfig.CgroupFeatures = features
+	klog.InfoS("Detected cgroup features", "unified", features.UnifiedMode, "controllers", features.Controllers.List())
+	if nodeConfig.MemoryQoS && !features.MemoryQoS() {
+		klog.InfoS("Memory qos is disabled, it requires cgroup v2 unified mode with memory controller")
+		nodeConfig.MemoryQoS = false
+	}
+	return features
+}
@@ -21,8 +21,10 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/network"
 	libcontainercgroups "github.com/opencontainers/runc/libcontainer/cgroups"
 	"github.com/spf13/pflag"
@@ -60,6 +62,7 @@ const (
 	DefaultPodsDirName                = "pods"
 	DefaultPodLogsRootPath            = "/var/log/pods"
 	DefaultVolumesDirName             = "volumes"
+	DefaultPersistentVolumesDirName   = "persistent-volumes"
 	DefaultVolumeSubpathsDirName      = "volume-subpaths"
 	DefaultVolumeDevicesDirName       = "volumeDevices"
 	DefaultPluginsDirName             = "plugins"
@@ -68,11 +71,19 @@ const (
 	DefaultPluginContainersDirName    = "plugin-containers"
 	DefaultPodResourcesDirName        = "pod-resources"
 	DefaultMemoryThrottlingFactor     = 0.8
+	DefaultCPUThrottlingThreshold     = 0.25
 	DefaultSessionServicePort         = 1022
+	DefaultDebugServicePort           = 1021
+	DefaultStandalonePodCIDR          = "192.168.0.1/24"
 	DefaultNodePortStartingNum        = 1024
 	KubeletPluginsDirSELinuxLabel     = "system_u:object_r:container_file_t:s0"
 	DefaultPodCgroupName              = "containers"
 	DefaultRuntimeHandler             = "runc"
+	DefaultImagePullPrewarmPercent    = 50
+	DefaultMaxParallelImagePulls      = 2
+	DefaultMemoryPressurePercent      = 20
+	DefaultSwapPressurePercent        = 50
+	DefaultMemoryAvailablePercent     = 5
 )
 
 type NodeConfiguration struct {
@@ -84,11 +95,14 @@ type NodeConfiguration struct {
 	FornaxCoreUrls           []string
 	Hostname                 string
 	MemoryQoS                bool
+	CgroupFeatures           CgroupFeatures // detected at startup
 	DisableSwap              bool
+	DisableGPU               bool
 	MaxPods                  int
 	MaxContainerPerPod       int
 	MounterPath              string // a mounter bin path, leave it empty if use default
 	NodeIP                   string
+	NodeIPv6                 string // ipv6 address of a dual stack node, pod ports are also mapped on it, first local ipv6 address if empty
 	NodeAgentCgroupName      string
 	OOMScoreAdj              int32
 	QOSReserved              map[v1.ResourceName]int64
@@ -111,6 +125,22 @@ type NodeConfiguration struct {
 	SeccompDefault           bool
 	NodePortStartingNo       int32
 	SessionServicePort       int32
+	CPUThrottlingThreshold   float64           // ratio of throttled cfs periods to raise pod ThrottlingHigh condition
+	PodAdmissionPolicyFile   string            // json file of node local pod admission policy, no policy if empty
+	WatchdogConfigFile       string            // json file of goroutine and lock contention watchdog, default thresholds if empty
+	NodeLabels               map[string]string // extra labels of node, e.g. node price labels used by cost aware scheduling
+	ImagePullBandwidthLimit  int64             // bytes per second of image pulls, 0 is unlimited
+	ImagePullPrewarmPercent  int               // percent of image pull bandwidth prewarm pulls use, default 50
+	MaxParallelImagePulls    int               // default 2
+	MemoryPressurePercent    int               // node memory psi some avg10 to shed session load, 0 is disabled
+	SwapPressurePercent      int               // percent of used swap to shed session load, 0 is disabled
+	MemoryAvailablePercent   int               // percent of available memory below which session load is shed, 0 is disabled
+	FornaxCoreCAFile         string            // CA of fornaxcore grpc server, node agent use mtls to connect fornaxcore if set
+	BootstrapTokenFile       string            // bootstrap token used to request first node certificate
+	SessionServiceTLS        bool              // require pods to use mtls to connect node session service
+	DebugServicePort         int32             // port of debug service serving container logs and streams to fornaxcore over mtls, 0 is disabled
+	StandaloneAPIAddress     string            // address of local pod and session api of a node running without fornaxcore, standalone is disabled if empty
+	StandalonePodCIDR        string            // pod cidr of standalone node, fornaxcore assign it otherwise
 }
 
 func DefaultNodeConfiguration() (*NodeConfiguration, error) {
@@ -127,6 +157,11 @@ func DefaultNodeConfiguration() (*NodeConfiguration, error) {
 		return nil, err
 	}
 
+	nodeIPv6 := ""
+	if v6ips, err := network.GetLocalV6IP(); err == nil && len(v6ips) > 0 {
+		nodeIPv6 = v6ips[0].String()
+	}
+
 	return &NodeConfiguration{
 		ContainerRuntime:         "remote",
 		ContainerRuntimeEndpoint: DefaultContainerRuntimeEndpoint,
@@ -139,6 +174,7 @@ func DefaultNodeConfiguration() (*NodeConfiguration, error) {
 		MaxContainerPerPod:       DefaultMaxContainerPerPod,
 		MounterPath:              DefaultMounter,
 		NodeIP:                   ips[0].String(),
+		NodeIPv6:                 nodeIPv6,
 		NodeAgentCgroupName:      DefaultNodeAgentCgroupName,
 		OOMScoreAdj:              -999,
 		QOSReserved:              map[v1.ResourceName]int64{},
@@ -151,6 +187,8 @@ func DefaultNodeConfiguration() (*NodeConfiguration, error) {
 		SeccompProfileRoot:       filepath.Join(DefaultRootPath, "seccomp"),
 		NodePortStartingNo:       DefaultNodePortStartingNum,
 		SessionServicePort:       DefaultSessionServicePort,
+		DebugServicePort:         DefaultDebugServicePort,
+		CPUThrottlingThreshold:   DefaultCPUThrottlingThreshold,
 		SeccompDefault:           false,
 		ProtectKernelDefaults:    false,
 		SystemCgroupName:         DefaultSystemCgroupName,
@@ -163,15 +201,30 @@ func DefaultNodeConfiguration() (*NodeConfiguration, error) {
 		EnforceNodeAllocatable:   map[string]sets.Empty{},
 		NodeAgentReserved:        map[v1.ResourceName]resource.Quantity{},
 		SystemReserved:           map[v1.ResourceName]resource.Quantity{},
+		NodeLabels:               map[string]string{},
+		ImagePullPrewarmPercent:  DefaultImagePullPrewarmPercent,
+		MaxParallelImagePulls:    DefaultMaxParallelImagePulls,
+		MemoryPressurePercent:    DefaultMemoryPressurePercent,
+		SwapPressurePercent:      DefaultSwapPressurePercent,
+		MemoryAvailablePercent:   DefaultMemoryAvailablePercent,
+		StandalonePodCIDR:        DefaultStandalonePodCIDR,
 	}, nil
 }
 
 func ValidateNodeConfiguration(nodeConfig NodeConfiguration) []error {
 	errs := []error{}
-	if nodeConfig.MemoryQoS && libcontainercgroups.IsCgroup2UnifiedMode() {
+	if nodeConfig.MemoryQoS && !libcontainercgroups.IsCgroup2UnifiedMode() {
 		errs = append(errs, errors.New("memory qos is true but cgroup is not running in v2 unified mode "))
 	}
 
+	for _, k := range []string{fornaxv1.LabelFornaxCoreNodeSpotPrice, fornaxv1.LabelFornaxCoreNodeInstanceCost} {
+		if v, found := nodeConfig.NodeLabels[k]; found {
+			if price, err := strconv.ParseFloat(v, 64); err != nil || price < 0 {
+				errs = append(errs, fmt.Errorf("node label %s=%s is not a valid price", k, v))
+			}
+		}
+	}
+
 	var err error
 	if _, err = os.Stat(nodeConfig.RootPath); os.IsNotExist(err) {
 		err = os.Mkdir(nodeConfig.RootPath, os.FileMode(int(0755)))
@@ -187,6 +240,25 @@ func ValidateNodeConfiguration(nodeConfig NodeConfiguration) []error {
 		}
 	}
 
+	if nodeConfig.ImagePullBandwidthLimit < 0 || nodeConfig.ImagePullPrewarmPercent < 0 || nodeConfig.ImagePullPrewarmPercent > 100 || nodeConfig.MaxParallelImagePulls < 0 {
+		errs = append(errs, fmt.Errorf("invalid image pull limits, bandwidth %d, prewarm percent %d, parallel pulls %d", nodeConfig.ImagePullBandwidthLimit, nodeConfig.ImagePullPrewarmPercent, nodeConfig.MaxParallelImagePulls))
+	}
+
+	for name, percent := range map[string]int{"memory pressure": nodeConfig.MemoryPressurePercent, "swap pressure": nodeConfig.SwapPressurePercent, "memory available": nodeConfig.MemoryAvailablePercent} {
+		if percent < 0 || percent > 100 {
+			errs = append(errs, fmt.Errorf("invalid %s percent %d, it should be in [0, 100]", name, percent))
+		}
+	}
+
+	if len(nodeConfig.StandaloneAPIAddress) > 0 {
+		if len(nodeConfig.FornaxCoreUrls) > 0 {
+			errs = append(errs, errors.New("standalone node can not connect fornaxcore, do not set fornaxcore url with standalone api address"))
+		}
+		if _, _, err := net.ParseCIDR(nodeConfig.StandalonePodCIDR); err != nil {
+			errs = append(errs, fmt.Errorf("invalid standalone pod cidr %s: %v", nodeConfig.StandalonePodCIDR, err))
+		}
+	}
+
 	dbDir := fmt.Sprintf("%s/db", nodeConfig.RootPath)
 	if _, err = os.Stat(dbDir); os.IsNotExist(err) {
 		err = os.Mkdir(dbDir, os.FileMode(int(0755)))
@@ -200,12 +272,44 @@ func ValidateNodeConfiguration(nodeConfig NodeConfiguration) []error {
 
 func AddConfigFlags(flagSet *pflag.FlagSet, nodeConfig *NodeConfiguration) {
 	flagSet.BoolVar(&nodeConfig.DisableSwap, "disable-swap", nodeConfig.DisableSwap, "should disable swap, fail when host swap is on")
+	flagSet.BoolVar(&nodeConfig.DisableGPU, "disable-gpu", nodeConfig.DisableGPU, "do not detect and expose nvidia gpus of node")
 
 	flagSet.StringVar(&nodeConfig.NodeIP, "node-ip", nodeConfig.NodeIP, "IPv4 addresses of the node. If unset, use the node's default IPv4 address")
 
+	flagSet.StringVar(&nodeConfig.NodeIPv6, "node-ipv6", nodeConfig.NodeIPv6, "IPv6 addresses of a dual stack node. If unset, use the node's first global or unique local IPv6 address, node is single stack if it does not have one")
+
 	flagSet.StringVar(&nodeConfig.ContainerRuntimeEndpoint, "remote-runtime-endpoint", nodeConfig.ContainerRuntimeEndpoint, "container runtime remote endpoint")
 
 	flagSet.StringArrayVar(&nodeConfig.FornaxCoreUrls, "fornaxcore-url", nodeConfig.FornaxCoreUrls, "addresses of the fornaxcores, format is ip:port. must provided")
 
+	flagSet.StringVar(&nodeConfig.PodAdmissionPolicyFile, "pod-admission-policy", nodeConfig.PodAdmissionPolicyFile, "json file of node local pod admission policy, pods violating it are rejected")
+
+	flagSet.StringVar(&nodeConfig.WatchdogConfigFile, "watchdog-config", nodeConfig.WatchdogConfigFile, "json file of goroutine and lock contention watchdog, default thresholds are used if unset")
+
+	flagSet.StringToStringVar(&nodeConfig.NodeLabels, "node-labels", nodeConfig.NodeLabels, "extra labels of node, e.g. spotprice.node.fornax-serverless.centaurusinfra.io=0.0125 to let scheduler know node price")
+
 	flagSet.StringVar(&nodeConfig.RuntimeHandler, "runtime-handler", nodeConfig.RuntimeHandler, "container runtime handler name, check /etc/docker/daemon.json for valid name")
+
+	flagSet.Int64Var(&nodeConfig.ImagePullBandwidthLimit, "image-pull-bandwidth", nodeConfig.ImagePullBandwidthLimit, "bytes per second image pulls use on average, 0 is unlimited")
+
+	flagSet.IntVar(&nodeConfig.ImagePullPrewarmPercent, "image-pull-prewarm-percent", nodeConfig.ImagePullPrewarmPercent, "percent of image pull bandwidth used by pulls of standby and warm pool pods, pulls of pods for pending sessions go first")
+
+	flagSet.IntVar(&nodeConfig.MaxParallelImagePulls, "max-parallel-image-pulls", nodeConfig.MaxParallelImagePulls, "max number of images pulled at same time")
+
+	flagSet.IntVar(&nodeConfig.MemoryPressurePercent, "memory-pressure-percent", nodeConfig.MemoryPressurePercent, "node memory psi some avg10 percent to stop taking new sessions and evict idle pods, 0 is disabled")
+
+	flagSet.IntVar(&nodeConfig.SwapPressurePercent, "swap-pressure-percent", nodeConfig.SwapPressurePercent, "percent of used swap to stop taking new sessions and evict idle pods, 0 is disabled")
+
+	flagSet.StringVar(&nodeConfig.FornaxCoreCAFile, "fornaxcore-ca-file", nodeConfig.FornaxCoreCAFile, "CA certificate of fornaxcore, if set, node agent connect fornaxcore using mtls with a node certificate saved in <root path>/pki")
+
+	flagSet.StringVar(&nodeConfig.BootstrapTokenFile, "bootstrap-token-file", nodeConfig.BootstrapTokenFile, "file of bootstrap token used to request first node certificate from fornaxcore")
+
+	flagSet.BoolVar(&nodeConfig.SessionServiceTLS, "session-service-tls", nodeConfig.SessionServiceTLS, "require pods to connect node session service using mtls, pod certificates are issued by a node local CA")
+	flagSet.Int32Var(&nodeConfig.DebugServicePort, "debug-service-port", nodeConfig.DebugServicePort, "port of debug service which serve container logs, exec, attach and port forward to fornaxcore, it require fornaxcore-ca-file, 0 disable it")
+
+	flagSet.StringVar(&nodeConfig.StandaloneAPIAddress, "standalone-api-address", nodeConfig.StandaloneAPIAddress, "run node without fornaxcore and serve local pod and session api on this address, e.g. 127.0.0.1:1020, used by edge deployments and to test application images")
+
+	flagSet.StringVar(&nodeConfig.StandalonePodCIDR, "standalone-pod-cidr", nodeConfig.StandalonePodCIDR, "pod cidr of standalone node")
+
+	flagSet.IntVar(&nodeConfig.MemoryAvailablePercent, "memory-available-percent", nodeConfig.MemoryAvailablePercent, "percent of available node memory below which node stop taking new sessions and evict idle pods, 0 is disabled")
 }
@@ -25,6 +25,11 @@ func GetPodsDir(rootPath string) string {
 	return filepath.Join(rootPath, DefaultPodsDirName)
 }
 
+// GetPersistentVolumeDir returns the full path to directory of a persistent volume claim, it's not under pod dir, so it's kept after pod is deleted
+func GetPersistentVolumeDir(rootPath string, namespace, claimName string) string {
+	return filepath.Join(rootPath, DefaultPersistentVolumesDirName, namespace, claimName)
+}
+
 // getPodLogDir returns the full path to the pod log dir
 func GetPodLogDir(rootPath string, podNamespace, podName string, podUID types.UID) string {
 	return filepath.Join(rootPath, podNamespace, podName, string(podUID))
@@ -0,0 +1,213 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debugservice
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	// interval to check new log content and container state when following a log
+	logFollowPollInterval = 200 * time.Millisecond
+	logTailBlockSize      = 4096
+)
+
+var errLogLimitReached = errors.New("log limit bytes reached")
+
+// LogOptions of reading cri container log, a negative TailLines read all lines
+type LogOptions struct {
+	Follow     bool
+	TailLines  int64
+	SinceTime  time.Time
+	Timestamps bool
+	LimitBytes int64
+}
+
+// criLogLine is a line of cri log file, format is "<rfc3339nano time> <stream> <P|F> <content>",
+// content of a partial line(P) does not end with new line
+type criLogLine struct {
+	timestamp time.Time
+	stream    []byte
+	content   []byte
+}
+
+func parseCRILogLine(line []byte) (*criLogLine, error) {
+	fields := bytes.SplitN(line, []byte{' '}, 4)
+	if len(fields) < 4 {
+		return nil, errors.New("invalid cri log line")
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, string(fields[0]))
+	if err != nil {
+		return nil, err
+	}
+	content := fields[3]
+	if bytes.Equal(fields[2], []byte("P")) {
+		content = bytes.TrimSuffix(content, []byte{'\n'})
+	}
+	return &criLogLine{timestamp: timestamp, stream: fields[1], content: content}, nil
+}
+
+// tailOffset find offset of file where last n lines start, it read file backward by blocks,
+// last line without new line is also counted
+func tailOffset(f *os.File, n int64) (int64, error) {
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return size, nil
+	}
+	offset := size
+	lines := int64(0)
+	buf := make([]byte, logTailBlockSize)
+	for offset > 0 {
+		blockSize := int64(len(buf))
+		if offset < blockSize {
+			blockSize = offset
+		}
+		offset -= blockSize
+		if _, err := f.ReadAt(buf[:blockSize], offset); err != nil {
+			return 0, err
+		}
+		for i := blockSize - 1; i >= 0; i-- {
+			if buf[i] != '\n' || offset+i == size-1 {
+				continue
+			}
+			lines++
+			if lines == n {
+				return offset + i + 1, nil
+			}
+		}
+	}
+	return 0, nil
+}
+
+type limitedWriter struct {
+	w         io.Writer
+	remaining int64
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining {
+		n, err := l.w.Write(p[:l.remaining])
+		l.remaining -= int64(n)
+		if err == nil {
+			err = errLogLimitReached
+		}
+		return n, err
+	}
+	n, err := l.w.Write(p)
+	l.remaining -= int64(n)
+	return n, err
+}
+
+// ReadLogs write content of a cri container log file to w, if follow is set, it wait for new log until container is not running,
+// a line being written is held until it's completed
+func ReadLogs(ctx context.Context, path string, opts *LogOptions, containerRunning func() bool, w io.Writer) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	start := int64(0)
+	if opts.TailLines >= 0 {
+		if start, err = tailOffset(f, opts.TailLines); err != nil {
+			return err
+		}
+	}
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		return err
+	}
+
+	if opts.LimitBytes > 0 {
+		w = &limitedWriter{w: w, remaining: opts.LimitBytes}
+	}
+	out := bufio.NewWriter(w)
+	reader := bufio.NewReader(f)
+	pending := []byte{}
+	exited := false
+	for {
+		line, err := reader.ReadBytes('\n')
+		pending = append(pending, line...)
+		if err == nil {
+			if err := writeLogLine(out, pending, opts); err != nil {
+				return ignoreLimitReached(err)
+			}
+			pending = pending[:0]
+			continue
+		}
+		if err != io.EOF {
+			return err
+		}
+
+		// read to end of file, flush what we have and wait for more if container is still running
+		if !opts.Follow || exited {
+			if len(pending) > 0 {
+				if err := writeLogLine(out, pending, opts); err != nil {
+					return ignoreLimitReached(err)
+				}
+			}
+			return ignoreLimitReached(out.Flush())
+		}
+		if err := out.Flush(); err != nil {
+			return ignoreLimitReached(err)
+		}
+		if !containerRunning() {
+			// read once more, container may write last lines before exit
+			exited = true
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(logFollowPollInterval):
+		}
+	}
+}
+
+func writeLogLine(w io.Writer, line []byte, opts *LogOptions) error {
+	msg, err := parseCRILogLine(line)
+	if err != nil {
+		// not a cri log line, write it as it is
+		_, err = w.Write(line)
+		return err
+	}
+	if !opts.SinceTime.IsZero() && msg.timestamp.Before(opts.SinceTime) {
+		return nil
+	}
+	if opts.Timestamps {
+		if _, err := w.Write([]byte(msg.timestamp.Format(time.RFC3339Nano) + " ")); err != nil {
+			return err
+		}
+	}
+	_, err = w.Write(msg.content)
+	return err
+}
+
+func ignoreLimitReached(err error) error {
+	if errors.Is(err, errLogLimitReached) {
+		return nil
+	}
+	return err
+}
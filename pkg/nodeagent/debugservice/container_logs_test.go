@@ -0,0 +1,109 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debugservice
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testCRILog = `2022-10-01T10:00:00.000000001Z stdout F line 1
+2022-10-01T10:00:01.000000001Z stderr P line 
+2022-10-01T10:00:01.000000002Z stderr F 2
+not a cri log line
+2022-10-01T10:00:03.000000001Z stdout F line 4
+`
+
+func writeTestLog(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "0.log")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadLogs(t *testing.T) {
+	path := writeTestLog(t, testCRILog)
+	sinceTime, _ := time.Parse(time.RFC3339Nano, "2022-10-01T10:00:01.000000002Z")
+	tests := []struct {
+		name string
+		opts LogOptions
+		want string
+	}{
+		{name: "all", opts: LogOptions{TailLines: -1}, want: "line 1\nline 2\nnot a cri log line\nline 4\n"},
+		{name: "tail", opts: LogOptions{TailLines: 2}, want: "not a cri log line\nline 4\n"},
+		{name: "tail more than file", opts: LogOptions{TailLines: 100}, want: "line 1\nline 2\nnot a cri log line\nline 4\n"},
+		{name: "tail zero", opts: LogOptions{TailLines: 0}, want: ""},
+		{name: "since", opts: LogOptions{TailLines: -1, SinceTime: sinceTime}, want: "2\nnot a cri log line\nline 4\n"},
+		{name: "timestamps", opts: LogOptions{TailLines: 1, Timestamps: true}, want: "2022-10-01T10:00:03.000000001Z line 4\n"},
+		{name: "limit bytes", opts: LogOptions{TailLines: -1, LimitBytes: 10}, want: "line 1\nlin"},
+	}
+	for _, test := range tests {
+		out := &bytes.Buffer{}
+		if err := ReadLogs(context.Background(), path, &test.opts, func() bool { return false }, out); err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+		if out.String() != test.want {
+			t.Errorf("%s: expected %q, got %q", test.name, test.want, out.String())
+		}
+	}
+}
+
+func TestReadLogsFollow(t *testing.T) {
+	path := writeTestLog(t, "2022-10-01T10:00:00.000000001Z stdout F line 1\n")
+	running := make(chan bool, 1)
+	running <- true
+	isRunning := func() bool {
+		select {
+		case v := <-running:
+			running <- v
+			return v
+		default:
+			return false
+		}
+	}
+	done := make(chan string)
+	go func() {
+		out := &bytes.Buffer{}
+		ReadLogs(context.Background(), path, &LogOptions{Follow: true, TailLines: -1}, isRunning, out)
+		done <- out.String()
+	}()
+
+	time.Sleep(2 * logFollowPollInterval)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("2022-10-01T10:00:01.000000001Z stdout F line 2\n")
+	f.Close()
+	<-running
+	running <- false
+
+	select {
+	case got := <-done:
+		if got != "line 1\nline 2\n" {
+			t.Errorf("expected followed logs until container exit, got %q", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("follow did not stop after container exited")
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debugservice
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	debuggrpc "centaurusinfra.io/fornax-serverless/pkg/nodeagent/debugservice/grpc"
+
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// stream url returned by container runtime is valid for a short time, same as runtime streaming server token
+	streamURLTTL = 1 * time.Minute
+	// size of data sent in one tunnel message
+	tunnelChunkSize   = 32 * 1024
+	tunnelDialTimeout = 10 * time.Second
+)
+
+// streamURLs are urls of prepared runtime streams, a tunnel can only connect to a url just issued, and only once
+type streamURLs struct {
+	mu   sync.Mutex
+	urls map[string]time.Time
+}
+
+func newStreamURLs() *streamURLs {
+	return &streamURLs{urls: map[string]time.Time{}}
+}
+
+func (s *streamURLs) add(u string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for k, v := range s.urls {
+		if now.Sub(v) > streamURLTTL {
+			delete(s.urls, k)
+		}
+	}
+	s.urls[u] = now
+}
+
+func (s *streamURLs) take(u string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issued, found := s.urls[u]
+	delete(s.urls, u)
+	return found && time.Since(issued) <= streamURLTTL
+}
+
+// ContainerStream implements DebugServiceServer
+func (s *DebugServer) ContainerStream(ctx context.Context, request *debuggrpc.ContainerStreamRequest) (*debuggrpc.ContainerStreamResponse, error) {
+	var streamURL string
+	var err error
+	if request.StreamType == debuggrpc.StreamType_PORT_FORWARD {
+		pod := s.pods.Get(request.PodIdentifier)
+		if pod == nil || pod.RuntimePod == nil {
+			return nil, grpcstatus.Errorf(codes.NotFound, "pod %s not found on node", request.PodIdentifier)
+		}
+		streamURL, err = s.runtimeService.GetPortForwardURL(&criv1.PortForwardRequest{PodSandboxId: pod.RuntimePod.Id, Port: request.Ports})
+	} else {
+		_, container, ferr := s.findContainer(request.PodIdentifier, request.Container)
+		if ferr != nil {
+			return nil, ferr
+		}
+		containerId := container.RuntimeContainer.Id
+		if request.StreamType == debuggrpc.StreamType_EXEC {
+			if len(request.Command) == 0 {
+				return nil, grpcstatus.Error(codes.InvalidArgument, "command is required to exec in container")
+			}
+			streamURL, err = s.runtimeService.GetExecURL(&criv1.ExecRequest{
+				ContainerId: containerId,
+				Cmd:         request.Command,
+				Tty:         request.Tty,
+				Stdin:       request.Stdin,
+				Stdout:      request.Stdout,
+				Stderr:      request.Stderr,
+			})
+		} else {
+			streamURL, err = s.runtimeService.GetAttachURL(&criv1.AttachRequest{
+				ContainerId: containerId,
+				Tty:         request.Tty,
+				Stdin:       request.Stdin,
+				Stdout:      request.Stdout,
+				Stderr:      request.Stderr,
+			})
+		}
+	}
+	if err != nil {
+		klog.ErrorS(err, "Failed to prepare container stream", "pod", request.PodIdentifier, "container", request.Container, "type", request.StreamType)
+		return nil, grpcstatus.Error(codes.Internal, err.Error())
+	}
+	klog.InfoS("Prepared container stream", "pod", request.PodIdentifier, "container", request.Container, "type", request.StreamType)
+	s.streamURLs.add(streamURL)
+	return &debuggrpc.ContainerStreamResponse{Url: streamURL}, nil
+}
+
+// Tunnel implements DebugServiceServer, it connect to runtime streaming server and copy data in both directions until any side close
+func (s *DebugServer) Tunnel(stream debuggrpc.DebugService_TunnelServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if !s.streamURLs.take(first.Url) {
+		return grpcstatus.Error(codes.PermissionDenied, "stream url is not issued or expired")
+	}
+	u, err := url.Parse(first.Url)
+	if err != nil {
+		return grpcstatus.Error(codes.InvalidArgument, err.Error())
+	}
+	address := u.Host
+	if len(u.Port()) == 0 {
+		port := "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+		address = net.JoinHostPort(u.Hostname(), port)
+	}
+	conn, err := net.DialTimeout("tcp", address, tunnelDialTimeout)
+	if err != nil {
+		return grpcstatus.Error(codes.Unavailable, err.Error())
+	}
+	defer conn.Close()
+
+	errCh := make(chan error, 2)
+	go func() {
+		if len(first.Data) > 0 {
+			if _, err := conn.Write(first.Data); err != nil {
+				errCh <- err
+				return
+			}
+		}
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				errCh <- err
+				return
+			}
+			if _, err := conn.Write(msg.Data); err != nil {
+				errCh <- err
+				return
+			}
+		}
+	}()
+	go func() {
+		buf := make([]byte, tunnelChunkSize)
+		for {
+			n, err := conn.Read(buf)
+			if n > 0 {
+				if serr := stream.Send(&debuggrpc.TunnelData{Data: buf[:n]}); serr != nil {
+					errCh <- serr
+					return
+				}
+			}
+			if err != nil {
+				errCh <- err
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-errCh:
+	case <-stream.Context().Done():
+	}
+	return nil
+}
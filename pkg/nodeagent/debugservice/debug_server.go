@@ -0,0 +1,195 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debugservice
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/config"
+	debuggrpc "centaurusinfra.io/fornax-serverless/pkg/nodeagent/debugservice/grpc"
+	podutil "centaurusinfra.io/fornax-serverless/pkg/nodeagent/pod"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/runtime"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
+	"centaurusinfra.io/fornax-serverless/pkg/pki"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	grpcstatus "google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// size of log content sent in one message
+	logChunkSize = 16 * 1024
+)
+
+var (
+	PeerNotAllowedError = grpcstatus.Error(codes.PermissionDenied, "debug service only accept fornaxcore")
+)
+
+// PodProvider find a node agent pod by identifier, node pod pool implement it
+type PodProvider interface {
+	Get(id string) *types.FornaxPod
+	List() []*types.FornaxPod
+}
+
+var _ debuggrpc.DebugServiceServer = &DebugServer{}
+
+// DebugServer serve container logs and exec, attach and port forward streams of node pods to fornaxcore, fornaxcore proxy requests of api server users to it,
+// it's served using node certificate and a peer is accepted only if it has fornaxcore client certificate issued by node CA
+type DebugServer struct {
+	pods           PodProvider
+	runtimeService runtime.RuntimeService
+	tlsConfig      *tls.Config
+	streamURLs     *streamURLs
+
+	debuggrpc.UnimplementedDebugServiceServer
+}
+
+func NewDebugServer(pods PodProvider, runtimeService runtime.RuntimeService, tlsConfig *tls.Config) *DebugServer {
+	return &DebugServer{
+		pods:           pods,
+		runtimeService: runtimeService,
+		tlsConfig:      tlsConfig,
+		streamURLs:     newStreamURLs(),
+	}
+}
+
+func (s *DebugServer) Run(ctx context.Context, port int32) error {
+	lis, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%d", port))
+	if err != nil {
+		klog.ErrorS(err, "Node agent debug grpc server failed to listen", "port", port)
+		return err
+	}
+	grpcServer := grpc.NewServer(grpc.Creds(credentials.NewTLS(s.tlsConfig)), grpc.UnaryInterceptor(s.authorizeUnary), grpc.StreamInterceptor(s.authorizeStream))
+	debuggrpc.RegisterDebugServiceServer(grpcServer, s)
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			klog.ErrorS(err, "Node agent debug grpc server stopped to serve")
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		grpcServer.Stop()
+	}()
+	return nil
+}
+
+func (s *DebugServer) authorizeUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !pki.PeerIs(ctx, pki.FornaxCoreCommonName) {
+		return nil, PeerNotAllowedError
+	}
+	return handler(ctx, req)
+}
+
+func (s *DebugServer) authorizeStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !pki.PeerIs(ss.Context(), pki.FornaxCoreCommonName) {
+		return PeerNotAllowedError
+	}
+	return handler(srv, ss)
+}
+
+// findContainer return container of pod, first container of pod spec if name is empty
+func (s *DebugServer) findContainer(podId, name string) (*types.FornaxPod, *types.FornaxContainer, error) {
+	pod := s.pods.Get(podId)
+	if pod == nil || pod.Pod == nil {
+		return nil, nil, grpcstatus.Errorf(codes.NotFound, "pod %s not found on node", podId)
+	}
+	if len(name) == 0 {
+		if len(pod.Pod.Spec.Containers) == 0 {
+			return nil, nil, grpcstatus.Errorf(codes.NotFound, "pod %s has no container", podId)
+		}
+		name = pod.Pod.Spec.Containers[0].Name
+	}
+	container, found := pod.Containers[name]
+	if !found || container.RuntimeContainer == nil {
+		return nil, nil, grpcstatus.Errorf(codes.NotFound, "container %s of pod %s not found on node", name, podId)
+	}
+	return pod, container, nil
+}
+
+// containerLogPath use log path reported by runtime, or log path node agent set in container config
+func containerLogPath(pod *types.FornaxPod, container *types.FornaxContainer) string {
+	if container.ContainerStatus != nil && container.ContainerStatus.RuntimeStatus != nil && len(container.ContainerStatus.RuntimeStatus.LogPath) > 0 {
+		return container.ContainerStatus.RuntimeStatus.LogPath
+	}
+	podLogDir := config.GetPodLogDir(config.DefaultPodLogsRootPath, pod.Pod.Namespace, pod.Pod.Name, pod.Pod.UID)
+	return filepath.Join(podLogDir, podutil.ContainerLogFileName(container.ContainerSpec.Name, 0))
+}
+
+// logStreamWriter send written bytes as log messages, chunks larger than logChunkSize are split
+type logStreamWriter struct {
+	stream debuggrpc.DebugService_ContainerLogsServer
+}
+
+func (w *logStreamWriter) Write(p []byte) (int, error) {
+	written := 0
+	for written < len(p) {
+		end := written + logChunkSize
+		if end > len(p) {
+			end = len(p)
+		}
+		if err := w.stream.Send(&debuggrpc.ContainerLogs{Content: p[written:end]}); err != nil {
+			return written, err
+		}
+		written = end
+	}
+	return written, nil
+}
+
+// ContainerLogs implements DebugServiceServer
+func (s *DebugServer) ContainerLogs(request *debuggrpc.ContainerLogsRequest, stream debuggrpc.DebugService_ContainerLogsServer) error {
+	pod, container, err := s.findContainer(request.PodIdentifier, request.Container)
+	if err != nil {
+		return err
+	}
+	opts := &LogOptions{
+		Follow:     request.Follow,
+		TailLines:  request.TailLines,
+		Timestamps: request.Timestamps,
+		LimitBytes: request.LimitBytes,
+	}
+	if request.SinceTime != nil {
+		opts.SinceTime = request.SinceTime.AsTime()
+	}
+	containerId := container.RuntimeContainer.Id
+	containerRunning := func() bool {
+		status, err := s.runtimeService.GetContainerStatus(containerId)
+		return err == nil && runtime.ContainerRunning(status)
+	}
+
+	path := containerLogPath(pod, container)
+	if _, err := os.Stat(path); err != nil {
+		return grpcstatus.Errorf(codes.NotFound, "log of container %s of pod %s not found, %v", container.ContainerSpec.Name, request.PodIdentifier, err)
+	}
+	// tell fornaxcore log is found before waiting for log content
+	if err := stream.Send(&debuggrpc.ContainerLogs{}); err != nil {
+		return err
+	}
+	klog.InfoS("Read container logs", "pod", request.PodIdentifier, "container", container.ContainerSpec.Name, "path", path, "follow", request.Follow)
+	if err := ReadLogs(stream.Context(), path, opts, containerRunning, &logStreamWriter{stream: stream}); err != nil {
+		klog.ErrorS(err, "Failed to read container logs", "pod", request.PodIdentifier, "container", container.ContainerSpec.Name)
+		return grpcstatus.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
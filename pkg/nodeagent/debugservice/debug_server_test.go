@@ -0,0 +1,125 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debugservice
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	debuggrpc "centaurusinfra.io/fornax-serverless/pkg/nodeagent/debugservice/grpc"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
+	"centaurusinfra.io/fornax-serverless/pkg/pki"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+type emptyPods struct{}
+
+func (emptyPods) Get(id string) *types.FornaxPod { return nil }
+func (emptyPods) List() []*types.FornaxPod       { return nil }
+
+func issueTestCertificate(t *testing.T, ca *pki.CertificateAuthority, cn string, usages ...x509.ExtKeyUsage) *tls.Certificate {
+	pair, err := ca.Issue(pkix.Name{CommonName: cn}, nil, nil, usages, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := pki.NewTLSCertificate(pair)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func freePort(t *testing.T) int32 {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	return int32(lis.Addr().(*net.TCPAddr).Port)
+}
+
+func TestDebugServerRequireFornaxCoreCertificate(t *testing.T) {
+	ca, err := pki.NewSelfSignedCertificateAuthority("test-node-ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCA, err := pki.NewSelfSignedCertificateAuthority("test-other-ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeCert := issueTestCertificate(t, ca, pki.NodeCommonName("node1"), x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth)
+	serverConfig := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{*nodeCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    ca.CertPool(),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	port := freePort(t)
+	if err := NewDebugServer(emptyPods{}, nil, serverConfig).Run(ctx, port); err != nil {
+		t.Fatal(err)
+	}
+	address := fmt.Sprintf("127.0.0.1:%d", port)
+
+	tests := []struct {
+		name       string
+		clientCert *tls.Certificate
+		node       string
+		code       codes.Code
+	}{
+		{name: "fornaxcore", clientCert: issueTestCertificate(t, ca, pki.FornaxCoreCommonName, x509.ExtKeyUsageClientAuth), node: "node1", code: codes.NotFound},
+		{name: "other node", clientCert: issueTestCertificate(t, ca, pki.NodeCommonName("node2"), x509.ExtKeyUsageClientAuth), node: "node1", code: codes.PermissionDenied},
+		{name: "other ca", clientCert: issueTestCertificate(t, otherCA, pki.FornaxCoreCommonName, x509.ExtKeyUsageClientAuth), node: "node1", code: codes.Unavailable},
+		{name: "no certificate", node: "node1", code: codes.Unavailable},
+		{name: "unexpected node", clientCert: issueTestCertificate(t, ca, pki.FornaxCoreCommonName, x509.ExtKeyUsageClientAuth), node: "node2", code: codes.Unavailable},
+	}
+	for _, test := range tests {
+		clientConfig := &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: true,
+			VerifyConnection:   pki.VerifyServerCommonName(ca.CertPool(), pki.NodeCommonName(test.node)),
+		}
+		if test.clientCert != nil {
+			clientConfig.Certificates = []tls.Certificate{*test.clientCert}
+		}
+		callCtx, callCancel := context.WithTimeout(ctx, 5*time.Second)
+		conn, err := grpc.DialContext(callCtx, address, grpc.WithTransportCredentials(credentials.NewTLS(clientConfig)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		stream, err := debuggrpc.NewDebugServiceClient(conn).ContainerLogs(callCtx, &debuggrpc.ContainerLogsRequest{PodIdentifier: "pod1"})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		if code := grpcstatus.Code(err); code != test.code {
+			t.Errorf("%s: expected code %s, got %v", test.name, test.code, err)
+		}
+		callCancel()
+		conn.Close()
+	}
+}
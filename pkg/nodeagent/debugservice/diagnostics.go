@@ -0,0 +1,102 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debugservice
+
+import (
+	"bytes"
+	"context"
+	goruntime "runtime"
+	"runtime/debug"
+	"runtime/pprof"
+	"sort"
+	"time"
+
+	debuggrpc "centaurusinfra.io/fornax-serverless/pkg/nodeagent/debugservice/grpc"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// startTime is when node agent process started, reported in diagnostics
+var startTime = time.Now()
+
+// Diagnostics report node agent process, container runtime and pods state, only states are reported,
+// pod specs are not included as they carry env, config data and secrets of applications
+func (s *DebugServer) Diagnostics(ctx context.Context, request *debuggrpc.DiagnosticsRequest) (*debuggrpc.Diagnostics, error) {
+	memStats := &goruntime.MemStats{}
+	goruntime.ReadMemStats(memStats)
+	diagnostics := &debuggrpc.Diagnostics{
+		GoVersion:      goruntime.Version(),
+		StartTime:      timestamppb.New(startTime),
+		GoroutineCount: int32(goruntime.NumGoroutine()),
+		HeapAllocBytes: memStats.HeapAlloc,
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		diagnostics.Version = info.Main.Version
+	}
+	if request.Goroutines {
+		buf := &bytes.Buffer{}
+		pprof.Lookup("goroutine").WriteTo(buf, 2)
+		diagnostics.GoroutineDump = buf.String()
+	}
+
+	if status, err := s.runtimeService.GetRuntimeStatus(); err != nil {
+		diagnostics.RuntimeError = err.Error()
+	} else if status != nil {
+		for _, c := range status.Conditions {
+			diagnostics.RuntimeConditions = append(diagnostics.RuntimeConditions, &debuggrpc.RuntimeCondition{
+				Type:    c.Type,
+				Status:  c.Status,
+				Reason:  c.Reason,
+				Message: c.Message,
+			})
+		}
+	}
+
+	for _, pod := range s.pods.List() {
+		diagnostics.Pods = append(diagnostics.Pods, podDiagnostics(pod))
+	}
+	sort.Slice(diagnostics.Pods, func(i, j int) bool {
+		return diagnostics.Pods[i].Identifier < diagnostics.Pods[j].Identifier
+	})
+	return diagnostics, nil
+}
+
+func podDiagnostics(pod *types.FornaxPod) *debuggrpc.PodDiagnostics {
+	d := &debuggrpc.PodDiagnostics{
+		Identifier:              pod.Identifier,
+		State:                   string(pod.FornaxPodState),
+		Daemon:                  pod.Daemon,
+		Sessions:                int32(len(pod.Sessions)),
+		LastStateTransitionTime: timestamppb.New(pod.LastStateTransitionTime),
+	}
+	for name, c := range pod.Containers {
+		cd := &debuggrpc.ContainerDiagnostics{
+			Name:         name,
+			State:        string(c.State),
+			RestartCount: c.RestartCount,
+		}
+		if c.LastTerminationState != nil {
+			cd.LastTerminationReason = c.LastTerminationState.Reason
+		}
+		d.Containers = append(d.Containers, cd)
+	}
+	sort.Slice(d.Containers, func(i, j int) bool {
+		return d.Containers[i].Name < d.Containers[j].Name
+	})
+	return d
+}
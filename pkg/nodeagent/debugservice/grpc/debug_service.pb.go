@@ -0,0 +1,1207 @@
+//
+//Copyright 2022.
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        v3.12.1
+// source: pkg/nodeagent/debugservice/grpc/debug_service.proto
+
+package grpc
+
+import (
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type StreamType int32
+
+const (
+	StreamType_EXEC         StreamType = 0
+	StreamType_ATTACH       StreamType = 1
+	StreamType_PORT_FORWARD StreamType = 2
+)
+
+// Enum value maps for StreamType.
+var (
+	StreamType_name = map[int32]string{
+		0: "EXEC",
+		1: "ATTACH",
+		2: "PORT_FORWARD",
+	}
+	StreamType_value = map[string]int32{
+		"EXEC":         0,
+		"ATTACH":       1,
+		"PORT_FORWARD": 2,
+	}
+)
+
+func (x StreamType) Enum() *StreamType {
+	p := new(StreamType)
+	*p = x
+	return p
+}
+
+func (x StreamType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StreamType) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_nodeagent_debugservice_grpc_debug_service_proto_enumTypes[0].Descriptor()
+}
+
+func (StreamType) Type() protoreflect.EnumType {
+	return &file_pkg_nodeagent_debugservice_grpc_debug_service_proto_enumTypes[0]
+}
+
+func (x StreamType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StreamType.Descriptor instead.
+func (StreamType) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_nodeagent_debugservice_grpc_debug_service_proto_rawDescGZIP(), []int{0}
+}
+
+type ContainerLogsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PodIdentifier string               `protobuf:"bytes,1,opt,name=podIdentifier,proto3" json:"podIdentifier,omitempty"`
+	Container     string               `protobuf:"bytes,2,opt,name=container,proto3" json:"container,omitempty"` // first container of pod if empty
+	Follow        bool                 `protobuf:"varint,3,opt,name=follow,proto3" json:"follow,omitempty"`
+	TailLines     int64                `protobuf:"varint,4,opt,name=tailLines,proto3" json:"tailLines,omitempty"` // negative value mean all lines
+	SinceTime     *timestamp.Timestamp `protobuf:"bytes,5,opt,name=sinceTime,proto3" json:"sinceTime,omitempty"`
+	Timestamps    bool                 `protobuf:"varint,6,opt,name=timestamps,proto3" json:"timestamps,omitempty"`
+	LimitBytes    int64                `protobuf:"varint,7,opt,name=limitBytes,proto3" json:"limitBytes,omitempty"` // zero mean no limit
+}
+
+func (x *ContainerLogsRequest) Reset() {
+	*x = ContainerLogsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ContainerLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContainerLogsRequest) ProtoMessage() {}
+
+func (x *ContainerLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContainerLogsRequest.ProtoReflect.Descriptor instead.
+func (*ContainerLogsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_nodeagent_debugservice_grpc_debug_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ContainerLogsRequest) GetPodIdentifier() string {
+	if x != nil {
+		return x.PodIdentifier
+	}
+	return ""
+}
+
+func (x *ContainerLogsRequest) GetContainer() string {
+	if x != nil {
+		return x.Container
+	}
+	return ""
+}
+
+func (x *ContainerLogsRequest) GetFollow() bool {
+	if x != nil {
+		return x.Follow
+	}
+	return false
+}
+
+func (x *ContainerLogsRequest) GetTailLines() int64 {
+	if x != nil {
+		return x.TailLines
+	}
+	return 0
+}
+
+func (x *ContainerLogsRequest) GetSinceTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.SinceTime
+	}
+	return nil
+}
+
+func (x *ContainerLogsRequest) GetTimestamps() bool {
+	if x != nil {
+		return x.Timestamps
+	}
+	return false
+}
+
+func (x *ContainerLogsRequest) GetLimitBytes() int64 {
+	if x != nil {
+		return x.LimitBytes
+	}
+	return 0
+}
+
+type ContainerLogs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Content []byte `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+}
+
+func (x *ContainerLogs) Reset() {
+	*x = ContainerLogs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ContainerLogs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContainerLogs) ProtoMessage() {}
+
+func (x *ContainerLogs) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContainerLogs.ProtoReflect.Descriptor instead.
+func (*ContainerLogs) Descriptor() ([]byte, []int) {
+	return file_pkg_nodeagent_debugservice_grpc_debug_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ContainerLogs) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+type ContainerStreamRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PodIdentifier string     `protobuf:"bytes,1,opt,name=podIdentifier,proto3" json:"podIdentifier,omitempty"`
+	Container     string     `protobuf:"bytes,2,opt,name=container,proto3" json:"container,omitempty"` // first container of pod if empty, not used by port forward
+	StreamType    StreamType `protobuf:"varint,3,opt,name=streamType,proto3,enum=centaurusinfra.io.fornaxcore.nodeagent.debugservice.StreamType" json:"streamType,omitempty"`
+	Command       []string   `protobuf:"bytes,4,rep,name=command,proto3" json:"command,omitempty"` // exec only
+	Stdin         bool       `protobuf:"varint,5,opt,name=stdin,proto3" json:"stdin,omitempty"`
+	Stdout        bool       `protobuf:"varint,6,opt,name=stdout,proto3" json:"stdout,omitempty"`
+	Stderr        bool       `protobuf:"varint,7,opt,name=stderr,proto3" json:"stderr,omitempty"`
+	Tty           bool       `protobuf:"varint,8,opt,name=tty,proto3" json:"tty,omitempty"`
+	Ports         []int32    `protobuf:"varint,9,rep,packed,name=ports,proto3" json:"ports,omitempty"` // port forward only
+}
+
+func (x *ContainerStreamRequest) Reset() {
+	*x = ContainerStreamRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ContainerStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContainerStreamRequest) ProtoMessage() {}
+
+func (x *ContainerStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContainerStreamRequest.ProtoReflect.Descriptor instead.
+func (*ContainerStreamRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_nodeagent_debugservice_grpc_debug_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ContainerStreamRequest) GetPodIdentifier() string {
+	if x != nil {
+		return x.PodIdentifier
+	}
+	return ""
+}
+
+func (x *ContainerStreamRequest) GetContainer() string {
+	if x != nil {
+		return x.Container
+	}
+	return ""
+}
+
+func (x *ContainerStreamRequest) GetStreamType() StreamType {
+	if x != nil {
+		return x.StreamType
+	}
+	return StreamType_EXEC
+}
+
+func (x *ContainerStreamRequest) GetCommand() []string {
+	if x != nil {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *ContainerStreamRequest) GetStdin() bool {
+	if x != nil {
+		return x.Stdin
+	}
+	return false
+}
+
+func (x *ContainerStreamRequest) GetStdout() bool {
+	if x != nil {
+		return x.Stdout
+	}
+	return false
+}
+
+func (x *ContainerStreamRequest) GetStderr() bool {
+	if x != nil {
+		return x.Stderr
+	}
+	return false
+}
+
+func (x *ContainerStreamRequest) GetTty() bool {
+	if x != nil {
+		return x.Tty
+	}
+	return false
+}
+
+func (x *ContainerStreamRequest) GetPorts() []int32 {
+	if x != nil {
+		return x.Ports
+	}
+	return nil
+}
+
+type ContainerStreamResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+}
+
+func (x *ContainerStreamResponse) Reset() {
+	*x = ContainerStreamResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ContainerStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContainerStreamResponse) ProtoMessage() {}
+
+func (x *ContainerStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContainerStreamResponse.ProtoReflect.Descriptor instead.
+func (*ContainerStreamResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_nodeagent_debugservice_grpc_debug_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ContainerStreamResponse) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type TunnelData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Url  string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"` // stream url, set in first message only
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *TunnelData) Reset() {
+	*x = TunnelData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TunnelData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TunnelData) ProtoMessage() {}
+
+func (x *TunnelData) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TunnelData.ProtoReflect.Descriptor instead.
+func (*TunnelData) Descriptor() ([]byte, []int) {
+	return file_pkg_nodeagent_debugservice_grpc_debug_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TunnelData) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *TunnelData) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type DiagnosticsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Goroutines bool `protobuf:"varint,1,opt,name=goroutines,proto3" json:"goroutines,omitempty"` // include full goroutine dump
+}
+
+func (x *DiagnosticsRequest) Reset() {
+	*x = DiagnosticsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DiagnosticsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiagnosticsRequest) ProtoMessage() {}
+
+func (x *DiagnosticsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiagnosticsRequest.ProtoReflect.Descriptor instead.
+func (*DiagnosticsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_nodeagent_debugservice_grpc_debug_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DiagnosticsRequest) GetGoroutines() bool {
+	if x != nil {
+		return x.Goroutines
+	}
+	return false
+}
+
+type RuntimeCondition struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type    string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Status  bool   `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
+	Reason  string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Message string `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *RuntimeCondition) Reset() {
+	*x = RuntimeCondition{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RuntimeCondition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuntimeCondition) ProtoMessage() {}
+
+func (x *RuntimeCondition) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuntimeCondition.ProtoReflect.Descriptor instead.
+func (*RuntimeCondition) Descriptor() ([]byte, []int) {
+	return file_pkg_nodeagent_debugservice_grpc_debug_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RuntimeCondition) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *RuntimeCondition) GetStatus() bool {
+	if x != nil {
+		return x.Status
+	}
+	return false
+}
+
+func (x *RuntimeCondition) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RuntimeCondition) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ContainerDiagnostics struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name                  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	State                 string `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	RestartCount          int32  `protobuf:"varint,3,opt,name=restartCount,proto3" json:"restartCount,omitempty"`
+	LastTerminationReason string `protobuf:"bytes,4,opt,name=lastTerminationReason,proto3" json:"lastTerminationReason,omitempty"`
+}
+
+func (x *ContainerDiagnostics) Reset() {
+	*x = ContainerDiagnostics{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ContainerDiagnostics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContainerDiagnostics) ProtoMessage() {}
+
+func (x *ContainerDiagnostics) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContainerDiagnostics.ProtoReflect.Descriptor instead.
+func (*ContainerDiagnostics) Descriptor() ([]byte, []int) {
+	return file_pkg_nodeagent_debugservice_grpc_debug_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ContainerDiagnostics) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ContainerDiagnostics) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ContainerDiagnostics) GetRestartCount() int32 {
+	if x != nil {
+		return x.RestartCount
+	}
+	return 0
+}
+
+func (x *ContainerDiagnostics) GetLastTerminationReason() string {
+	if x != nil {
+		return x.LastTerminationReason
+	}
+	return ""
+}
+
+type PodDiagnostics struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Identifier              string                  `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	State                   string                  `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	Daemon                  bool                    `protobuf:"varint,3,opt,name=daemon,proto3" json:"daemon,omitempty"`
+	Sessions                int32                   `protobuf:"varint,4,opt,name=sessions,proto3" json:"sessions,omitempty"`
+	LastStateTransitionTime *timestamp.Timestamp    `protobuf:"bytes,5,opt,name=lastStateTransitionTime,proto3" json:"lastStateTransitionTime,omitempty"`
+	Containers              []*ContainerDiagnostics `protobuf:"bytes,6,rep,name=containers,proto3" json:"containers,omitempty"`
+}
+
+func (x *PodDiagnostics) Reset() {
+	*x = PodDiagnostics{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PodDiagnostics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PodDiagnostics) ProtoMessage() {}
+
+func (x *PodDiagnostics) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PodDiagnostics.ProtoReflect.Descriptor instead.
+func (*PodDiagnostics) Descriptor() ([]byte, []int) {
+	return file_pkg_nodeagent_debugservice_grpc_debug_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PodDiagnostics) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *PodDiagnostics) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *PodDiagnostics) GetDaemon() bool {
+	if x != nil {
+		return x.Daemon
+	}
+	return false
+}
+
+func (x *PodDiagnostics) GetSessions() int32 {
+	if x != nil {
+		return x.Sessions
+	}
+	return 0
+}
+
+func (x *PodDiagnostics) GetLastStateTransitionTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.LastStateTransitionTime
+	}
+	return nil
+}
+
+func (x *PodDiagnostics) GetContainers() []*ContainerDiagnostics {
+	if x != nil {
+		return x.Containers
+	}
+	return nil
+}
+
+type Diagnostics struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version           string               `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"` // main module version of node agent binary
+	GoVersion         string               `protobuf:"bytes,2,opt,name=goVersion,proto3" json:"goVersion,omitempty"`
+	StartTime         *timestamp.Timestamp `protobuf:"bytes,3,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	GoroutineCount    int32                `protobuf:"varint,4,opt,name=goroutineCount,proto3" json:"goroutineCount,omitempty"`
+	GoroutineDump     string               `protobuf:"bytes,5,opt,name=goroutineDump,proto3" json:"goroutineDump,omitempty"`
+	HeapAllocBytes    uint64               `protobuf:"varint,6,opt,name=heapAllocBytes,proto3" json:"heapAllocBytes,omitempty"`
+	RuntimeConditions []*RuntimeCondition  `protobuf:"bytes,7,rep,name=runtimeConditions,proto3" json:"runtimeConditions,omitempty"`
+	RuntimeError      string               `protobuf:"bytes,8,opt,name=runtimeError,proto3" json:"runtimeError,omitempty"` // error of getting container runtime status
+	Pods              []*PodDiagnostics    `protobuf:"bytes,9,rep,name=pods,proto3" json:"pods,omitempty"`
+}
+
+func (x *Diagnostics) Reset() {
+	*x = Diagnostics{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Diagnostics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Diagnostics) ProtoMessage() {}
+
+func (x *Diagnostics) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Diagnostics.ProtoReflect.Descriptor instead.
+func (*Diagnostics) Descriptor() ([]byte, []int) {
+	return file_pkg_nodeagent_debugservice_grpc_debug_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Diagnostics) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Diagnostics) GetGoVersion() string {
+	if x != nil {
+		return x.GoVersion
+	}
+	return ""
+}
+
+func (x *Diagnostics) GetStartTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *Diagnostics) GetGoroutineCount() int32 {
+	if x != nil {
+		return x.GoroutineCount
+	}
+	return 0
+}
+
+func (x *Diagnostics) GetGoroutineDump() string {
+	if x != nil {
+		return x.GoroutineDump
+	}
+	return ""
+}
+
+func (x *Diagnostics) GetHeapAllocBytes() uint64 {
+	if x != nil {
+		return x.HeapAllocBytes
+	}
+	return 0
+}
+
+func (x *Diagnostics) GetRuntimeConditions() []*RuntimeCondition {
+	if x != nil {
+		return x.RuntimeConditions
+	}
+	return nil
+}
+
+func (x *Diagnostics) GetRuntimeError() string {
+	if x != nil {
+		return x.RuntimeError
+	}
+	return ""
+}
+
+func (x *Diagnostics) GetPods() []*PodDiagnostics {
+	if x != nil {
+		return x.Pods
+	}
+	return nil
+}
+
+var File_pkg_nodeagent_debugservice_grpc_debug_service_proto protoreflect.FileDescriptor
+
+var file_pkg_nodeagent_debugservice_grpc_debug_service_proto_rawDesc = []byte{
+	0x0a, 0x33, 0x70, 0x6b, 0x67, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2f,
+	0x64, 0x65, 0x62, 0x75, 0x67, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x67, 0x72, 0x70,
+	0x63, 0x2f, 0x64, 0x65, 0x62, 0x75, 0x67, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x33, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73,
+	0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63,
+	0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x64, 0x65,
+	0x62, 0x75, 0x67, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x8a, 0x02, 0x0a, 0x14,
+	0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x4c, 0x6f, 0x67, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x6f, 0x64,
+	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f,
+	0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63,
+	0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x6c, 0x6c,
+	0x6f, 0x77, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77,
+	0x12, 0x1c, 0x0a, 0x09, 0x74, 0x61, 0x69, 0x6c, 0x4c, 0x69, 0x6e, 0x65, 0x73, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x61, 0x69, 0x6c, 0x4c, 0x69, 0x6e, 0x65, 0x73, 0x12, 0x38,
+	0x0a, 0x09, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x73,
+	0x69, 0x6e, 0x63, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x29, 0x0a, 0x0d, 0x43, 0x6f, 0x6e, 0x74,
+	0x61, 0x69, 0x6e, 0x65, 0x72, 0x4c, 0x6f, 0x67, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x22, 0xc5, 0x02, 0x0a, 0x16, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65,
+	0x72, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24,
+	0x0a, 0x0d, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69,
+	0x66, 0x69, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65,
+	0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e,
+	0x65, 0x72, 0x12, 0x5f, 0x0a, 0x0a, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x54, 0x79, 0x70, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x3f, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72,
+	0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61,
+	0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e,
+	0x64, 0x65, 0x62, 0x75, 0x67, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0a, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x14, 0x0a,
+	0x05, 0x73, 0x74, 0x64, 0x69, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x73, 0x74,
+	0x64, 0x69, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x64, 0x6f, 0x75, 0x74, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x06, 0x73, 0x74, 0x64, 0x6f, 0x75, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73,
+	0x74, 0x64, 0x65, 0x72, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x73, 0x74, 0x64,
+	0x65, 0x72, 0x72, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x74, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x03, 0x74, 0x74, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x18, 0x09,
+	0x20, 0x03, 0x28, 0x05, 0x52, 0x05, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x22, 0x2b, 0x0a, 0x17, 0x43,
+	0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x22, 0x32, 0x0a, 0x0a, 0x54, 0x75, 0x6e, 0x6e,
+	0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x34, 0x0a, 0x12,
+	0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x67, 0x6f, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x67, 0x6f, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e,
+	0x65, 0x73, 0x22, 0x70, 0x0a, 0x10, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x43, 0x6f, 0x6e,
+	0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x22, 0x9a, 0x01, 0x0a, 0x14, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e,
+	0x65, 0x72, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x73, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x72, 0x65, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x72,
+	0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x34, 0x0a, 0x15, 0x6c,
+	0x61, 0x73, 0x74, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x15, 0x6c, 0x61, 0x73, 0x74,
+	0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x22, 0xbb, 0x02, 0x0a, 0x0e, 0x50, 0x6f, 0x64, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73,
+	0x74, 0x69, 0x63, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69,
+	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69,
+	0x66, 0x69, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x61,
+	0x65, 0x6d, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x64, 0x61, 0x65, 0x6d,
+	0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x54,
+	0x0a, 0x17, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x17, 0x6c, 0x61, 0x73,
+	0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x54, 0x69, 0x6d, 0x65, 0x12, 0x69, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65,
+	0x72, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x49, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61,
+	0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72,
+	0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43,
+	0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74,
+	0x69, 0x63, 0x73, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x22,
+	0xe7, 0x03, 0x0a, 0x0b, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x73, 0x12,
+	0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x67, 0x6f, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x67, 0x6f,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x38, 0x0a, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x54, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d,
+	0x65, 0x12, 0x26, 0x0a, 0x0e, 0x67, 0x6f, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x65, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x67, 0x6f, 0x72, 0x6f, 0x75,
+	0x74, 0x69, 0x6e, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x67, 0x6f, 0x72,
+	0x6f, 0x75, 0x74, 0x69, 0x6e, 0x65, 0x44, 0x75, 0x6d, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x67, 0x6f, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x65, 0x44, 0x75, 0x6d, 0x70, 0x12,
+	0x26, 0x0a, 0x0e, 0x68, 0x65, 0x61, 0x70, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x42, 0x79, 0x74, 0x65,
+	0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x68, 0x65, 0x61, 0x70, 0x41, 0x6c, 0x6c,
+	0x6f, 0x63, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x73, 0x0a, 0x11, 0x72, 0x75, 0x6e, 0x74, 0x69,
+	0x6d, 0x65, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x07, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x45, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e,
+	0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x64, 0x65, 0x62, 0x75,
+	0x67, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65,
+	0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x11, 0x72, 0x75, 0x6e, 0x74, 0x69,
+	0x6d, 0x65, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x22, 0x0a, 0x0c,
+	0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72,
+	0x12, 0x57, 0x0a, 0x04, 0x70, 0x6f, 0x64, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x43,
+	0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e,
+	0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f,
+	0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x6f, 0x64, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74,
+	0x69, 0x63, 0x73, 0x52, 0x04, 0x70, 0x6f, 0x64, 0x73, 0x2a, 0x34, 0x0a, 0x0a, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x45, 0x58, 0x45, 0x43, 0x10,
+	0x00, 0x12, 0x0a, 0x0a, 0x06, 0x41, 0x54, 0x54, 0x41, 0x43, 0x48, 0x10, 0x01, 0x12, 0x10, 0x0a,
+	0x0c, 0x50, 0x4f, 0x52, 0x54, 0x5f, 0x46, 0x4f, 0x52, 0x57, 0x41, 0x52, 0x44, 0x10, 0x02, 0x32,
+	0x8c, 0x05, 0x0a, 0x0c, 0x44, 0x65, 0x62, 0x75, 0x67, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x12, 0xa0, 0x01, 0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x4c, 0x6f,
+	0x67, 0x73, 0x12, 0x49, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e,
+	0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x64, 0x65, 0x62, 0x75,
+	0x67, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e,
+	0x65, 0x72, 0x4c, 0x6f, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x42, 0x2e,
+	0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69,
+	0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64,
+	0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x4c, 0x6f, 0x67,
+	0x73, 0x30, 0x01, 0x12, 0xac, 0x01, 0x0a, 0x0f, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65,
+	0x72, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x4b, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75,
+	0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e,
+	0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x6f,
+	0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x4c, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73,
+	0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63,
+	0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x64, 0x65,
+	0x62, 0x75, 0x67, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x61,
+	0x69, 0x6e, 0x65, 0x72, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x8e, 0x01, 0x0a, 0x06, 0x74, 0x75, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x3f, 0x2e,
+	0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69,
+	0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64,
+	0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x54, 0x75, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x1a, 0x3f,
+	0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e,
+	0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f,
+	0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x54, 0x75, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x28,
+	0x01, 0x30, 0x01, 0x12, 0x98, 0x01, 0x0a, 0x0b, 0x64, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74,
+	0x69, 0x63, 0x73, 0x12, 0x47, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69,
+	0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f,
+	0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x64, 0x65, 0x62,
+	0x75, 0x67, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f,
+	0x73, 0x74, 0x69, 0x63, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x40, 0x2e, 0x63,
+	0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f,
+	0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x73, 0x42, 0x45,
+	0x5a, 0x43, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61,
+	0x2e, 0x69, 0x6f, 0x2f, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x6c, 0x65, 0x73, 0x73, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x2f, 0x64, 0x65, 0x62, 0x75, 0x67, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2f, 0x67, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_nodeagent_debugservice_grpc_debug_service_proto_rawDescOnce sync.Once
+	file_pkg_nodeagent_debugservice_grpc_debug_service_proto_rawDescData = file_pkg_nodeagent_debugservice_grpc_debug_service_proto_rawDesc
+)
+
+func file_pkg_nodeagent_debugservice_grpc_debug_service_proto_rawDescGZIP() []byte {
+	file_pkg_nodeagent_debugservice_grpc_debug_service_proto_rawDescOnce.Do(func() {
+		file_pkg_nodeagent_debugservice_grpc_debug_service_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_nodeagent_debugservice_grpc_debug_service_proto_rawDescData)
+	})
+	return file_pkg_nodeagent_debugservice_grpc_debug_service_proto_rawDescData
+}
+
+var file_pkg_nodeagent_debugservice_grpc_debug_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_pkg_nodeagent_debugservice_grpc_debug_service_proto_goTypes = []interface{}{
+	(StreamType)(0),                 // 0: centaurusinfra.io.fornaxcore.nodeagent.debugservice.StreamType
+	(*ContainerLogsRequest)(nil),    // 1: centaurusinfra.io.fornaxcore.nodeagent.debugservice.ContainerLogsRequest
+	(*ContainerLogs)(nil),           // 2: centaurusinfra.io.fornaxcore.nodeagent.debugservice.ContainerLogs
+	(*ContainerStreamRequest)(nil),  // 3: centaurusinfra.io.fornaxcore.nodeagent.debugservice.ContainerStreamRequest
+	(*ContainerStreamResponse)(nil), // 4: centaurusinfra.io.fornaxcore.nodeagent.debugservice.ContainerStreamResponse
+	(*TunnelData)(nil),              // 5: centaurusinfra.io.fornaxcore.nodeagent.debugservice.TunnelData
+	(*DiagnosticsRequest)(nil),      // 6: centaurusinfra.io.fornaxcore.nodeagent.debugservice.DiagnosticsRequest
+	(*RuntimeCondition)(nil),        // 7: centaurusinfra.io.fornaxcore.nodeagent.debugservice.RuntimeCondition
+	(*ContainerDiagnostics)(nil),    // 8: centaurusinfra.io.fornaxcore.nodeagent.debugservice.ContainerDiagnostics
+	(*PodDiagnostics)(nil),          // 9: centaurusinfra.io.fornaxcore.nodeagent.debugservice.PodDiagnostics
+	(*Diagnostics)(nil),             // 10: centaurusinfra.io.fornaxcore.nodeagent.debugservice.Diagnostics
+	(*timestamp.Timestamp)(nil),     // 11: google.protobuf.Timestamp
+}
+var file_pkg_nodeagent_debugservice_grpc_debug_service_proto_depIdxs = []int32{
+	11, // 0: centaurusinfra.io.fornaxcore.nodeagent.debugservice.ContainerLogsRequest.sinceTime:type_name -> google.protobuf.Timestamp
+	0,  // 1: centaurusinfra.io.fornaxcore.nodeagent.debugservice.ContainerStreamRequest.streamType:type_name -> centaurusinfra.io.fornaxcore.nodeagent.debugservice.StreamType
+	11, // 2: centaurusinfra.io.fornaxcore.nodeagent.debugservice.PodDiagnostics.lastStateTransitionTime:type_name -> google.protobuf.Timestamp
+	8,  // 3: centaurusinfra.io.fornaxcore.nodeagent.debugservice.PodDiagnostics.containers:type_name -> centaurusinfra.io.fornaxcore.nodeagent.debugservice.ContainerDiagnostics
+	11, // 4: centaurusinfra.io.fornaxcore.nodeagent.debugservice.Diagnostics.startTime:type_name -> google.protobuf.Timestamp
+	7,  // 5: centaurusinfra.io.fornaxcore.nodeagent.debugservice.Diagnostics.runtimeConditions:type_name -> centaurusinfra.io.fornaxcore.nodeagent.debugservice.RuntimeCondition
+	9,  // 6: centaurusinfra.io.fornaxcore.nodeagent.debugservice.Diagnostics.pods:type_name -> centaurusinfra.io.fornaxcore.nodeagent.debugservice.PodDiagnostics
+	1,  // 7: centaurusinfra.io.fornaxcore.nodeagent.debugservice.DebugService.containerLogs:input_type -> centaurusinfra.io.fornaxcore.nodeagent.debugservice.ContainerLogsRequest
+	3,  // 8: centaurusinfra.io.fornaxcore.nodeagent.debugservice.DebugService.containerStream:input_type -> centaurusinfra.io.fornaxcore.nodeagent.debugservice.ContainerStreamRequest
+	5,  // 9: centaurusinfra.io.fornaxcore.nodeagent.debugservice.DebugService.tunnel:input_type -> centaurusinfra.io.fornaxcore.nodeagent.debugservice.TunnelData
+	6,  // 10: centaurusinfra.io.fornaxcore.nodeagent.debugservice.DebugService.diagnostics:input_type -> centaurusinfra.io.fornaxcore.nodeagent.debugservice.DiagnosticsRequest
+	2,  // 11: centaurusinfra.io.fornaxcore.nodeagent.debugservice.DebugService.containerLogs:output_type -> centaurusinfra.io.fornaxcore.nodeagent.debugservice.ContainerLogs
+	4,  // 12: centaurusinfra.io.fornaxcore.nodeagent.debugservice.DebugService.containerStream:output_type -> centaurusinfra.io.fornaxcore.nodeagent.debugservice.ContainerStreamResponse
+	5,  // 13: centaurusinfra.io.fornaxcore.nodeagent.debugservice.DebugService.tunnel:output_type -> centaurusinfra.io.fornaxcore.nodeagent.debugservice.TunnelData
+	10, // 14: centaurusinfra.io.fornaxcore.nodeagent.debugservice.DebugService.diagnostics:output_type -> centaurusinfra.io.fornaxcore.nodeagent.debugservice.Diagnostics
+	11, // [11:15] is the sub-list for method output_type
+	7,  // [7:11] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_pkg_nodeagent_debugservice_grpc_debug_service_proto_init() }
+func file_pkg_nodeagent_debugservice_grpc_debug_service_proto_init() {
+	if File_pkg_nodeagent_debugservice_grpc_debug_service_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ContainerLogsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ContainerLogs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ContainerStreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ContainerStreamResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TunnelData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DiagnosticsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RuntimeCondition); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ContainerDiagnostics); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PodDiagnostics); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Diagnostics); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_nodeagent_debugservice_grpc_debug_service_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pkg_nodeagent_debugservice_grpc_debug_service_proto_goTypes,
+		DependencyIndexes: file_pkg_nodeagent_debugservice_grpc_debug_service_proto_depIdxs,
+		EnumInfos:         file_pkg_nodeagent_debugservice_grpc_debug_service_proto_enumTypes,
+		MessageInfos:      file_pkg_nodeagent_debugservice_grpc_debug_service_proto_msgTypes,
+	}.Build()
+	File_pkg_nodeagent_debugservice_grpc_debug_service_proto = out.File
+	file_pkg_nodeagent_debugservice_grpc_debug_service_proto_rawDesc = nil
+	file_pkg_nodeagent_debugservice_grpc_debug_service_proto_goTypes = nil
+	file_pkg_nodeagent_debugservice_grpc_debug_service_proto_depIdxs = nil
+}
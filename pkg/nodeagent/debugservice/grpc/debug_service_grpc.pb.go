@@ -0,0 +1,287 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.12.1
+// source: pkg/nodeagent/debugservice/grpc/debug_service.proto
+
+package grpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// DebugServiceClient is the client API for DebugService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DebugServiceClient interface {
+	// stream cri log of a pod container, first message is empty and sent once container log is found,
+	// stream end when container log is read to end, or container exited if follow is set
+	ContainerLogs(ctx context.Context, in *ContainerLogsRequest, opts ...grpc.CallOption) (DebugService_ContainerLogsClient, error)
+	// prepare a exec, attach or port forward stream of container runtime, it return stream url of runtime streaming server,
+	// url can be used once in a tunnel
+	ContainerStream(ctx context.Context, in *ContainerStreamRequest, opts ...grpc.CallOption) (*ContainerStreamResponse, error)
+	// tunnel a tcp connection to runtime streaming server, first message must carry a url returned by containerStream,
+	// fornaxcore proxy upgraded connection of api server client in a tunnel
+	Tunnel(ctx context.Context, opts ...grpc.CallOption) (DebugService_TunnelClient, error)
+	// return node agent process, container runtime and pod diagnostics for support bundle, pod specs, configs and secrets are not included
+	Diagnostics(ctx context.Context, in *DiagnosticsRequest, opts ...grpc.CallOption) (*Diagnostics, error)
+}
+
+type debugServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDebugServiceClient(cc grpc.ClientConnInterface) DebugServiceClient {
+	return &debugServiceClient{cc}
+}
+
+func (c *debugServiceClient) ContainerLogs(ctx context.Context, in *ContainerLogsRequest, opts ...grpc.CallOption) (DebugService_ContainerLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DebugService_ServiceDesc.Streams[0], "/centaurusinfra.io.fornaxcore.nodeagent.debugservice.DebugService/containerLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &debugServiceContainerLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DebugService_ContainerLogsClient interface {
+	Recv() (*ContainerLogs, error)
+	grpc.ClientStream
+}
+
+type debugServiceContainerLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *debugServiceContainerLogsClient) Recv() (*ContainerLogs, error) {
+	m := new(ContainerLogs)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *debugServiceClient) ContainerStream(ctx context.Context, in *ContainerStreamRequest, opts ...grpc.CallOption) (*ContainerStreamResponse, error) {
+	out := new(ContainerStreamResponse)
+	err := c.cc.Invoke(ctx, "/centaurusinfra.io.fornaxcore.nodeagent.debugservice.DebugService/containerStream", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *debugServiceClient) Tunnel(ctx context.Context, opts ...grpc.CallOption) (DebugService_TunnelClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DebugService_ServiceDesc.Streams[1], "/centaurusinfra.io.fornaxcore.nodeagent.debugservice.DebugService/tunnel", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &debugServiceTunnelClient{stream}
+	return x, nil
+}
+
+type DebugService_TunnelClient interface {
+	Send(*TunnelData) error
+	Recv() (*TunnelData, error)
+	grpc.ClientStream
+}
+
+type debugServiceTunnelClient struct {
+	grpc.ClientStream
+}
+
+func (x *debugServiceTunnelClient) Send(m *TunnelData) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *debugServiceTunnelClient) Recv() (*TunnelData, error) {
+	m := new(TunnelData)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *debugServiceClient) Diagnostics(ctx context.Context, in *DiagnosticsRequest, opts ...grpc.CallOption) (*Diagnostics, error) {
+	out := new(Diagnostics)
+	err := c.cc.Invoke(ctx, "/centaurusinfra.io.fornaxcore.nodeagent.debugservice.DebugService/diagnostics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DebugServiceServer is the server API for DebugService service.
+// All implementations must embed UnimplementedDebugServiceServer
+// for forward compatibility
+type DebugServiceServer interface {
+	// stream cri log of a pod container, first message is empty and sent once container log is found,
+	// stream end when container log is read to end, or container exited if follow is set
+	ContainerLogs(*ContainerLogsRequest, DebugService_ContainerLogsServer) error
+	// prepare a exec, attach or port forward stream of container runtime, it return stream url of runtime streaming server,
+	// url can be used once in a tunnel
+	ContainerStream(context.Context, *ContainerStreamRequest) (*ContainerStreamResponse, error)
+	// tunnel a tcp connection to runtime streaming server, first message must carry a url returned by containerStream,
+	// fornaxcore proxy upgraded connection of api server client in a tunnel
+	Tunnel(DebugService_TunnelServer) error
+	// return node agent process, container runtime and pod diagnostics for support bundle, pod specs, configs and secrets are not included
+	Diagnostics(context.Context, *DiagnosticsRequest) (*Diagnostics, error)
+	mustEmbedUnimplementedDebugServiceServer()
+}
+
+// UnimplementedDebugServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedDebugServiceServer struct {
+}
+
+func (UnimplementedDebugServiceServer) ContainerLogs(*ContainerLogsRequest, DebugService_ContainerLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ContainerLogs not implemented")
+}
+func (UnimplementedDebugServiceServer) ContainerStream(context.Context, *ContainerStreamRequest) (*ContainerStreamResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ContainerStream not implemented")
+}
+func (UnimplementedDebugServiceServer) Tunnel(DebugService_TunnelServer) error {
+	return status.Errorf(codes.Unimplemented, "method Tunnel not implemented")
+}
+func (UnimplementedDebugServiceServer) Diagnostics(context.Context, *DiagnosticsRequest) (*Diagnostics, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Diagnostics not implemented")
+}
+func (UnimplementedDebugServiceServer) mustEmbedUnimplementedDebugServiceServer() {}
+
+// UnsafeDebugServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DebugServiceServer will
+// result in compilation errors.
+type UnsafeDebugServiceServer interface {
+	mustEmbedUnimplementedDebugServiceServer()
+}
+
+func RegisterDebugServiceServer(s grpc.ServiceRegistrar, srv DebugServiceServer) {
+	s.RegisterService(&DebugService_ServiceDesc, srv)
+}
+
+func _DebugService_ContainerLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ContainerLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DebugServiceServer).ContainerLogs(m, &debugServiceContainerLogsServer{stream})
+}
+
+type DebugService_ContainerLogsServer interface {
+	Send(*ContainerLogs) error
+	grpc.ServerStream
+}
+
+type debugServiceContainerLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *debugServiceContainerLogsServer) Send(m *ContainerLogs) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DebugService_ContainerStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContainerStreamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DebugServiceServer).ContainerStream(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/centaurusinfra.io.fornaxcore.nodeagent.debugservice.DebugService/containerStream",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DebugServiceServer).ContainerStream(ctx, req.(*ContainerStreamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DebugService_Tunnel_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DebugServiceServer).Tunnel(&debugServiceTunnelServer{stream})
+}
+
+type DebugService_TunnelServer interface {
+	Send(*TunnelData) error
+	Recv() (*TunnelData, error)
+	grpc.ServerStream
+}
+
+type debugServiceTunnelServer struct {
+	grpc.ServerStream
+}
+
+func (x *debugServiceTunnelServer) Send(m *TunnelData) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *debugServiceTunnelServer) Recv() (*TunnelData, error) {
+	m := new(TunnelData)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _DebugService_Diagnostics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiagnosticsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DebugServiceServer).Diagnostics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/centaurusinfra.io.fornaxcore.nodeagent.debugservice.DebugService/diagnostics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DebugServiceServer).Diagnostics(ctx, req.(*DiagnosticsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DebugService_ServiceDesc is the grpc.ServiceDesc for DebugService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DebugService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "centaurusinfra.io.fornaxcore.nodeagent.debugservice.DebugService",
+	HandlerType: (*DebugServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "containerStream",
+			Handler:    _DebugService_ContainerStream_Handler,
+		},
+		{
+			MethodName: "diagnostics",
+			Handler:    _DebugService_Diagnostics_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "containerLogs",
+			Handler:       _DebugService_ContainerLogs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "tunnel",
+			Handler:       _DebugService_Tunnel_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "pkg/nodeagent/debugservice/grpc/debug_service.proto",
+}
@@ -17,16 +17,21 @@ package dependency
 
 import (
 	"context"
+	"net"
+	"path/filepath"
 
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/cadvisor"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/config"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/fornaxcore"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/images"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/network"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/qos"
 	resourcemanager "centaurusinfra.io/fornax-serverless/pkg/nodeagent/resource"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/runtime"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/sessionservice"
-	sessionserver "centaurusinfra.io/fornax-serverless/pkg/nodeagent/sessionservice/grpc"
+	sessiongrpc "centaurusinfra.io/fornax-serverless/pkg/nodeagent/sessionservice/grpc"
+	sessionserver "centaurusinfra.io/fornax-serverless/pkg/nodeagent/sessionservice/server"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/sessionservice/sidecar"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/store"
 	"centaurusinfra.io/fornax-serverless/pkg/store/storage/sqlite"
 	v1 "k8s.io/api/core/v1"
@@ -38,30 +43,39 @@ import (
 )
 
 type Dependencies struct {
-	NetworkProvider network.NetworkAddressProvider
-	CAdvisor        cadvisor.CAdvisorInfoProvider
-	RuntimeService  runtime.RuntimeService
-	QosManager      qos.QoSManager
-	ImageManager    images.ImageManager
-	MemoryManager   resourcemanager.MemoryManager
-	CPUManager      resourcemanager.CPUManager
-	VolumeManager   resourcemanager.VolumeManager
-	NodeStore       *store.NodeStore
-	PodStore        *store.PodStore
-	SessionService  sessionservice.SessionService
+	NetworkProvider  network.NetworkAddressProvider
+	CAdvisor         cadvisor.CAdvisorInfoProvider
+	RuntimeService   runtime.RuntimeService
+	QosManager       qos.QoSManager
+	ImageManager     images.ImageManager
+	MemoryManager    resourcemanager.MemoryManager
+	CPUManager       resourcemanager.CPUManager
+	VolumeManager    resourcemanager.VolumeManager
+	GPUManager       *resourcemanager.GPUManager
+	NodeStore        *store.NodeStore
+	PodStore         *store.PodStore
+	TerminationStore *store.PodTerminationStore
+	SessionStore     *store.SessionStore
+	SessionService   sessionservice.SessionService
+	// mtls of fornaxcore and session service connections, nil if not enabled
+	NodeCredentials   *fornaxcore.NodeCredentials
+	SessionServiceTLS *sessiongrpc.SessionServiceTLS
 }
 
 func InitBasicDependencies(ctx context.Context, nodeConfig config.NodeConfiguration) (*Dependencies, error) {
 	dependencies := Dependencies{
-		NetworkProvider: nil,
-		CAdvisor:        nil,
-		RuntimeService:  nil,
-		QosManager:      nil,
-		MemoryManager:   resourcemanager.MemoryManager{},
-		CPUManager:      resourcemanager.CPUManager{},
-		VolumeManager:   resourcemanager.VolumeManager{},
-		PodStore:        &store.PodStore{},
-		NodeStore:       &store.NodeStore{},
+		NetworkProvider:  nil,
+		CAdvisor:         nil,
+		RuntimeService:   nil,
+		QosManager:       nil,
+		MemoryManager:    resourcemanager.MemoryManager{},
+		CPUManager:       resourcemanager.CPUManager{},
+		VolumeManager:    resourcemanager.VolumeManager{},
+		GPUManager:       resourcemanager.NewGPUManager(nodeConfig.DisableGPU),
+		PodStore:         &store.PodStore{},
+		NodeStore:        &store.NodeStore{},
+		TerminationStore: &store.PodTerminationStore{},
+		SessionStore:     &store.SessionStore{},
 	}
 
 	// SqliteStore
@@ -76,8 +90,18 @@ func InitBasicDependencies(ctx context.Context, nodeConfig config.NodeConfigurat
 		return nil, err
 	}
 
+	dependencies.TerminationStore, err = InitPodTerminationStore(nodeConfig.DatabaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies.SessionStore, err = InitSessionStore(nodeConfig.DatabaseURL)
+	if err != nil {
+		return nil, err
+	}
+
 	// NetworkProvider
-	dependencies.NetworkProvider = InitNetworkProvider(nodeConfig.Hostname)
+	dependencies.NetworkProvider = InitNetworkProvider(nodeConfig.Hostname, nodeConfig.NodeIPv6)
 
 	// Runtime
 	dependencies.RuntimeService, err = InitRuntimeService(nodeConfig.ContainerRuntimeEndpoint)
@@ -93,13 +117,38 @@ func InitBasicDependencies(ctx context.Context, nodeConfig config.NodeConfigurat
 		return nil, err
 	}
 
+	// Node certificate
+	pkiDir := filepath.Join(nodeConfig.RootPath, "pki")
+	if len(nodeConfig.FornaxCoreCAFile) > 0 {
+		dependencies.NodeCredentials, err = fornaxcore.NewNodeCredentials(nodeConfig.NodeIP, nodeConfig.Hostname, nodeConfig.FornaxCoreUrls, nodeConfig.FornaxCoreCAFile, nodeConfig.BootstrapTokenFile, pkiDir)
+		if err != nil {
+			klog.ErrorS(err, "failed to get node certificate")
+			return nil, err
+		}
+		dependencies.NodeCredentials.Run(ctx)
+	}
+
 	// SessionService
-	sessionService := sessionserver.NewSessionService()
-	err = sessionService.Run(ctx, nodeConfig.SessionServicePort)
+	grpcSessionService := sessiongrpc.NewSessionService()
+	if nodeConfig.SessionServiceTLS {
+		nodeIPs := []string{nodeConfig.NodeIP}
+		if len(nodeConfig.NodeIPv6) > 0 {
+			nodeIPs = append(nodeIPs, nodeConfig.NodeIPv6)
+		}
+		dependencies.SessionServiceTLS, err = sessiongrpc.NewSessionServiceTLS(pkiDir, nodeIPs)
+		if err != nil {
+			klog.ErrorS(err, "failed to init session service tls")
+			return nil, err
+		}
+		grpcSessionService.SetTLS(dependencies.SessionServiceTLS)
+	}
+	err = grpcSessionService.Run(ctx, nodeConfig.SessionServicePort)
 	if err != nil {
 		return nil, err
 	}
-	dependencies.SessionService = sessionService
+	sidecarSessionService := sidecar.NewSidecarSessionService()
+	sidecarSessionService.Run(ctx)
+	dependencies.SessionService = sessionserver.NewSessionService(grpcSessionService, sidecarSessionService)
 
 	return &dependencies, nil
 }
@@ -108,7 +157,7 @@ func InitRuntimeService(endpoint string) (runtime.RuntimeService, error) {
 	return runtime.NewRemoteRuntimeService(endpoint, runtime.DefaultTimeout)
 }
 
-func InitImageService(endpoint string) (images.ImageManager, error) {
+func InitImageService(endpoint string, limiterConfig images.PullLimiterConfig) (images.ImageManager, error) {
 	klog.InfoS("Connecting to runtime service", "endpoint", endpoint)
 	remoteService, err := remote.NewRemoteImageService(endpoint, runtime.DefaultTimeout)
 	if err != nil {
@@ -116,12 +165,13 @@ func InitImageService(endpoint string) (images.ImageManager, error) {
 		return nil, err
 	}
 
-	return images.NewImageManager(remoteService, &criv1.AuthConfig{}), nil
+	return images.NewImageManager(remoteService, &criv1.AuthConfig{}, limiterConfig), nil
 }
 
-func InitNetworkProvider(hostname string) network.NetworkAddressProvider {
+func InitNetworkProvider(hostname, nodeIPv6 string) network.NetworkAddressProvider {
 	return &network.LocalNetworkAddressProvider{
 		Hostname: hostname,
+		NodeIPv6: net.ParseIP(nodeIPv6),
 	}
 }
 
@@ -137,6 +187,18 @@ func InitNodeStore(databaseURL string) (*store.NodeStore, error) {
 	})
 }
 
+func InitPodTerminationStore(databaseURL string) (*store.PodTerminationStore, error) {
+	return store.NewPodTerminationSqliteStore(&sqlite.SQLiteStoreOptions{
+		ConnUrl: databaseURL,
+	})
+}
+
+func InitSessionStore(databaseURL string) (*store.SessionStore, error) {
+	return store.NewSessionSqliteStore(&sqlite.SQLiteStoreOptions{
+		ConnUrl: databaseURL,
+	})
+}
+
 func InitCAdvisor(cAdvisorConfig cadvisor.CAdvisorConfig, CRIRuntime runtime.RuntimeService) (cadvisor.CAdvisorInfoProvider, error) {
 	return cadvisor.NewCAdvisorInfoProvider(cAdvisorConfig, CRIRuntime)
 }
@@ -162,9 +224,27 @@ func (n *Dependencies) Complete(node *v1.Node, nodeConfig config.NodeConfigurati
 		}
 	}
 
+	// SqliteStore
+	if n.TerminationStore == nil {
+		n.TerminationStore, err = InitPodTerminationStore(nodeConfig.DatabaseURL)
+		if err != nil {
+			klog.ErrorS(err, "Failed to init node agent store")
+			return err
+		}
+	}
+
+	// SqliteStore
+	if n.SessionStore == nil {
+		n.SessionStore, err = InitSessionStore(nodeConfig.DatabaseURL)
+		if err != nil {
+			klog.ErrorS(err, "Failed to init node agent store")
+			return err
+		}
+	}
+
 	// networkProvider
 	if n.NetworkProvider == nil {
-		n.NetworkProvider = InitNetworkProvider(nodeConfig.Hostname)
+		n.NetworkProvider = InitNetworkProvider(nodeConfig.Hostname, nodeConfig.NodeIPv6)
 	}
 
 	// CRIRuntime
@@ -178,7 +258,11 @@ func (n *Dependencies) Complete(node *v1.Node, nodeConfig config.NodeConfigurati
 
 	// CRIRuntime
 	if n.ImageManager == nil {
-		n.ImageManager, err = InitImageService(nodeConfig.ContainerRuntimeEndpoint)
+		n.ImageManager, err = InitImageService(nodeConfig.ContainerRuntimeEndpoint, images.PullLimiterConfig{
+			BandwidthBytesPerSecond: nodeConfig.ImagePullBandwidthLimit,
+			PrewarmBandwidthPercent: nodeConfig.ImagePullPrewarmPercent,
+			MaxParallelPulls:        nodeConfig.MaxParallelImagePulls,
+		})
 		if err != nil {
 			klog.ErrorS(err, "Failed to init runtime image manager")
 			return err
@@ -204,6 +288,11 @@ func (n *Dependencies) Complete(node *v1.Node, nodeConfig config.NodeConfigurati
 		}
 	}
 
+	// GPUManager
+	if n.GPUManager == nil {
+		n.GPUManager = resourcemanager.NewGPUManager(nodeConfig.DisableGPU)
+	}
+
 	// TODO
 	// MemoryManager   resourcemanager.MemoryManager
 	// CPUManager      resourcemanager.CPUManager
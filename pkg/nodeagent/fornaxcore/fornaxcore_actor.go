@@ -17,18 +17,21 @@ limitations under the License.
 package fornaxcore
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
 
 	fornax "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc"
 	"centaurusinfra.io/fornax-serverless/pkg/message"
+	"google.golang.org/grpc/credentials"
 	"k8s.io/klog/v2"
 )
 
 // fornaxcore actor work as a communication proxy with fornax core,
 // it receive messages using fornax.GetMessage stream and send to node actor
 // it send messages to fornax core using fornax.PutMessage
+// it watch sessions of node from each fornax core and send session watch events to node actor
 // fornaxcore actor talk with node actor using proto.actor protocol
 
 type FornaxCoreActor struct {
@@ -38,8 +41,12 @@ type FornaxCoreActor struct {
 	innerActor    message.Actor
 	fornaxcores   map[string]FornaxCoreClient
 	fornaxChannel chan *fornax.FornaxCoreMessage
-	nodeActor     message.ActorRef
-	messageSeq    int64
+	// cancel session watch of each fornax core
+	sessionWatches map[string]context.CancelFunc
+	nodeActor      message.ActorRef
+	messageSeq     int64
+	// mtls credentials of fornaxcore connections, connections are insecure if nil
+	credentials credentials.TransportCredentials
 }
 
 func (n *FornaxCoreActor) Start(nodeActor message.ActorRef) error {
@@ -55,6 +62,9 @@ func (n *FornaxCoreActor) Start(nodeActor message.ActorRef) error {
 			return err
 		}
 	}
+	for k, v := range n.fornaxcores {
+		n.watchSessions(k, v)
+	}
 
 	// process fornax grpc message in a go routine
 	go func() {
@@ -90,6 +100,26 @@ func (n *FornaxCoreActor) Start(nodeActor message.ActorRef) error {
 	return nil
 }
 
+// watchSessions watch sessions of node from a fornax core in a go routine until fornax core leave or actor stop
+func (n *FornaxCoreActor) watchSessions(endpoint string, client FornaxCoreClient) {
+	ctx, cancel := context.WithCancel(context.Background())
+	n.sessionWatches[endpoint] = cancel
+	go func() {
+		if err := client.WatchSessions(ctx, func(event *fornax.SessionWatchEvent) {
+			n.notify(n.nodeActor, event)
+		}); err != nil {
+			klog.ErrorS(err, "Failed to watch sessions from fornax core", "endpoint", endpoint)
+		}
+	}()
+}
+
+func (n *FornaxCoreActor) stopWatchSessions(endpoint string) {
+	if cancel, found := n.sessionWatches[endpoint]; found {
+		cancel()
+		delete(n.sessionWatches, endpoint)
+	}
+}
+
 func (n *FornaxCoreActor) notify(receiver message.ActorRef, msg interface{}) error {
 	return message.Send(n.innerActor.Reference(), receiver, msg)
 }
@@ -97,7 +127,8 @@ func (n *FornaxCoreActor) notify(receiver message.ActorRef, msg interface{}) err
 func (n *FornaxCoreActor) Stop() error {
 	n.stop = true
 	n.innerActor.Stop()
-	for _, v := range n.fornaxcores {
+	for k, v := range n.fornaxcores {
+		n.stopWatchSessions(k)
 		v.Stop()
 	}
 	return nil
@@ -155,14 +186,16 @@ func (n *FornaxCoreActor) onFornaxCoreConfigurationCommand(msg *fornax.FornaxCor
 		}
 	}
 
-	newfornaxcores := InitFornaxCoreClients(n.nodeIP, n.identifier, newips)
+	newfornaxcores := InitFornaxCoreClients(n.nodeIP, n.identifier, newips, n.credentials)
 	for k, v := range newfornaxcores {
 		v.Start()
 		n.fornaxcores[k] = v
+		n.watchSessions(k, v)
 	}
 
 	for k, v := range oldfornaxcores {
 		delete(n.fornaxcores, k)
+		n.stopWatchSessions(k)
 		v.Stop()
 	}
 	return nil
@@ -172,10 +205,12 @@ func (n *FornaxCoreActor) Reference() message.ActorRef {
 	return n.innerActor.Reference()
 }
 
-func InitFornaxCoreClients(nodeIp, nodeName string, fornaxCoreIps []string) map[string]FornaxCoreClient {
+func InitFornaxCoreClients(nodeIp, nodeName string, fornaxCoreIps []string, creds credentials.TransportCredentials) map[string]FornaxCoreClient {
 	configs := []*FornaxCoreConfiguration{}
 	for _, v := range fornaxCoreIps {
-		configs = append(configs, NewFornaxCoreConfiguration(v))
+		config := NewFornaxCoreConfiguration(v)
+		config.credentials = creds
+		configs = append(configs, config)
 	}
 	fornaxcores := map[string]FornaxCoreClient{}
 	for _, v := range configs {
@@ -190,15 +225,22 @@ func InitFornaxCoreClients(nodeIp, nodeName string, fornaxCoreIps []string) map[
 	return fornaxcores
 }
 
-func NewFornaxCoreActor(nodeIP, nodeName string, fornaxCoreIps []string) *FornaxCoreActor {
-	fornaxcores := InitFornaxCoreClients(nodeIP, nodeName, fornaxCoreIps)
+func NewFornaxCoreActor(nodeIP, nodeName string, fornaxCoreIps []string, creds credentials.TransportCredentials) *FornaxCoreActor {
+	actor := NewFornaxCoreActorWithClients(nodeIP, nodeName, InitFornaxCoreClients(nodeIP, nodeName, fornaxCoreIps, creds))
+	actor.credentials = creds
+	return actor
+}
+
+// NewFornaxCoreActorWithClients create a fornaxcore actor using given clients, e.g. a local fornaxcore of a standalone node
+func NewFornaxCoreActorWithClients(nodeIP, nodeName string, fornaxcores map[string]FornaxCoreClient) *FornaxCoreActor {
 	actor := &FornaxCoreActor{
-		nodeIP:        nodeIP,
-		identifier:    nodeName,
-		stop:          false,
-		fornaxcores:   fornaxcores,
-		fornaxChannel: make(chan *fornax.FornaxCoreMessage, 30),
-		messageSeq:    time.Now().Unix() + 1, // use current epeco for starting message seq, so, it will be different everytime when nodeagent start
+		nodeIP:         nodeIP,
+		identifier:     nodeName,
+		stop:           false,
+		fornaxcores:    fornaxcores,
+		fornaxChannel:  make(chan *fornax.FornaxCoreMessage, 30),
+		sessionWatches: map[string]context.CancelFunc{},
+		messageSeq:     time.Now().Unix() + 1, // use current epeco for starting message seq, so, it will be different everytime when nodeagent start
 	}
 
 	actor.innerActor = message.NewLocalChannelActor(nodeName, actor.actorMessageProcess)
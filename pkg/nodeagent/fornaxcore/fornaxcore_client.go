@@ -28,6 +28,9 @@ import (
 	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+	grpcstatus "google.golang.org/grpc/status"
 	"k8s.io/klog/v2"
 )
 
@@ -36,12 +39,16 @@ type FornaxCoreConfiguration struct {
 	connTimeout    time.Duration
 	callTimeout    time.Duration
 	maxRecvMsgSize int
+	credentials    credentials.TransportCredentials
 }
 
 const (
 	DefaultConnTimeout    = 5 * time.Second
 	DefaultCallTimeout    = 5 * time.Second
 	DefaultMaxRecvMsgSize = 16 * 1024
+	// fornaxcore reject client pinging more often than 15s, see fornaxcore grpc server keepalive enforcement policy
+	DefaultKeepaliveTime    = 30 * time.Second
+	DefaultKeepaliveTimeout = 10 * time.Second
 )
 
 func NewFornaxCoreConfiguration(endpoint string) *FornaxCoreConfiguration {
@@ -58,6 +65,7 @@ type FornaxCoreClient interface {
 	Stop()
 	PutMessage(message *fornax.FornaxCoreMessage) error
 	GetMessage(receiver string, channel chan *fornax.FornaxCoreMessage) error
+	WatchSessions(ctx context.Context, handler func(event *fornax.SessionWatchEvent)) error
 }
 
 type fornaxCoreClient struct {
@@ -97,6 +105,65 @@ func (f *fornaxCoreClient) PutMessage(message *fornax.FornaxCoreMessage) error {
 	return nil
 }
 
+// WatchSessions receive session changes of this node from fornax core until context is done, sessions of node are received as Added first,
+// stream is reopened from resource version of last received event after a error, and from scratch if resource version is too old,
+// sessions received before are sent in request when stream is reopened, so, fornax core send Deleted of sessions which left node meanwhile
+func (f *fornaxCoreClient) WatchSessions(ctx context.Context, handler func(event *fornax.SessionWatchEvent)) error {
+	rv := ""
+	// pod of sessions received from fornax core, a Deleted event of resync does not have pod
+	knownSessions := map[string]string{}
+	for {
+		if ctx.Err() != nil || f.done {
+			return nil
+		}
+		f.mu.Lock()
+		service := f.service
+		f.mu.Unlock()
+		if service == nil {
+			time.Sleep(2 * time.Second)
+			continue
+		}
+		known := make([]string, 0, len(knownSessions))
+		for name := range knownSessions {
+			known = append(known, name)
+		}
+		stream, err := service.WatchSessions(ctx, &fornax.WatchSessionsRequest{NodeIdentifier: f.identifier, ResourceVersion: rv, KnownSessions: known})
+		if err == nil {
+			klog.InfoS("Watching sessions from FornaxCore", "endpoint", f.config.endpoint, "resourceVersion", rv, "knownSessions", len(known))
+			for {
+				var event *fornax.SessionWatchEvent
+				if event, err = stream.Recv(); err != nil {
+					break
+				}
+				if len(event.GetResourceVersion()) > 0 {
+					rv = event.GetResourceVersion()
+				}
+				switch event.GetType() {
+				case fornax.SessionWatchEvent_Bookmark:
+					continue
+				case fornax.SessionWatchEvent_Deleted:
+					if len(event.GetPodIdentifier()) == 0 {
+						event.PodIdentifier = knownSessions[event.GetSessionIdentifier()]
+					}
+					delete(knownSessions, event.GetSessionIdentifier())
+				default:
+					knownSessions[event.GetSessionIdentifier()] = event.GetPodIdentifier()
+				}
+				handler(event)
+			}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if s, ok := grpcstatus.FromError(err); ok && s.Code() == codes.OutOfRange {
+			// resource version is compacted, get all sessions of node again
+			rv = ""
+		}
+		klog.ErrorS(err, "Session watch stream failed, watch again", "endpoint", f.config.endpoint, "resourceVersion", rv)
+		time.Sleep(2 * time.Second)
+	}
+}
+
 func (f *fornaxCoreClient) disconnect() error {
 	return f.conn.Close()
 }
@@ -110,12 +177,21 @@ func (f *fornaxCoreClient) connect() error {
 			grpc_retry.WithBackoff(grpc_retry.BackoffLinear(100 * time.Millisecond)),
 			grpc_retry.WithCodes(codes.NotFound, codes.Aborted, codes.Unavailable, codes.DataLoss, codes.Unknown),
 		}
+		transport := grpc.WithInsecure()
+		if f.config.credentials != nil {
+			transport = grpc.WithTransportCredentials(f.config.credentials)
+		}
 		conn, err := grpc.DialContext(
 			ctx,
 			f.config.endpoint,
 			grpc.WithBlock(),
-			grpc.WithInsecure(),
+			transport,
 			grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(f.config.maxRecvMsgSize)),
+			grpc.WithKeepaliveParams(keepalive.ClientParameters{
+				Time:                DefaultKeepaliveTime,
+				Timeout:             DefaultKeepaliveTimeout,
+				PermitWithoutStream: true,
+			}),
 			grpc.WithStreamInterceptor(grpc_retry.StreamClientInterceptor(opts...)),
 			grpc.WithUnaryInterceptor(grpc_retry.UnaryClientInterceptor(opts...)),
 		)
@@ -0,0 +1,222 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fornaxcore
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	fornax "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc"
+	"centaurusinfra.io/fornax-serverless/pkg/pki"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"k8s.io/klog/v2"
+)
+
+var (
+	FornaxCoreCANotValidError = errors.New("no valid fornaxcore CA certificate")
+	NodeCANotFoundError       = errors.New("node CA certificate is not received from fornaxcore")
+)
+
+// NodeCredentials is mtls credentials node agent use to connect fornaxcore, client certificate is saved in pki dir
+// and renewed using current certificate before it expire, first certificate is requested with a bootstrap token,
+// node certificate and node CA returned with it are also used to serve debug service to fornaxcore
+type NodeCredentials struct {
+	identifier         *fornax.NodeIdentifier
+	fornaxCoreUrls     []string
+	certFile           string
+	keyFile            string
+	nodeCAFile         string
+	bootstrapTokenFile string
+	rootCAs            *x509.CertPool
+	certificate        *pki.RotatingCertificate
+
+	mu      sync.RWMutex
+	nodeCAs *x509.CertPool
+}
+
+// NewNodeCredentials load node certificate in pki dir, or request one from fornaxcore if node does not have a valid certificate,
+// it retry until fornaxcore issue a certificate or timeout
+func NewNodeCredentials(nodeIP, nodeName string, fornaxCoreUrls []string, caFile, bootstrapTokenFile, pkiDir string) (*NodeCredentials, error) {
+	caPEM, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(caPEM) {
+		return nil, FornaxCoreCANotValidError
+	}
+	c := &NodeCredentials{
+		identifier:         &fornax.NodeIdentifier{Ip: nodeIP, Identifier: nodeName},
+		fornaxCoreUrls:     fornaxCoreUrls,
+		certFile:           filepath.Join(pkiDir, "node.crt"),
+		keyFile:            filepath.Join(pkiDir, "node.key"),
+		nodeCAFile:         filepath.Join(pkiDir, "node-ca.crt"),
+		bootstrapTokenFile: bootstrapTokenFile,
+		rootCAs:            rootCAs,
+	}
+	if nodeCAPEM, err := os.ReadFile(c.nodeCAFile); err == nil {
+		c.setNodeCA(nodeCAPEM)
+	}
+
+	var initial *pki.KeyPair
+	certPEM, certErr := os.ReadFile(c.certFile)
+	keyPEM, keyErr := os.ReadFile(c.keyFile)
+	if certErr == nil && keyErr == nil {
+		initial = &pki.KeyPair{CertPEM: certPEM, KeyPEM: keyPEM}
+	}
+	err = util.BackoffExec(2*time.Second, 1*time.Minute, 3*time.Minute, 1.7, func() error {
+		c.certificate, err = pki.NewRotatingCertificate("node-agent-client", initial, c.renew)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	// a certificate issued before debug service use mtls is not a server certificate and node CA is not saved, renew it now
+	if !c.serving() {
+		if err := c.certificate.Rotate(); err != nil {
+			klog.ErrorS(err, "Failed to renew node certificate for debug service, retry on next rotation")
+		}
+	}
+	return c, nil
+}
+
+// serving check if node has node CA and current certificate can be used as server certificate
+func (c *NodeCredentials) serving() bool {
+	cert := c.certificate.Current()
+	if c.nodeCAPool() == nil || cert == nil {
+		return false
+	}
+	for _, v := range cert.Leaf.ExtKeyUsage {
+		if v == x509.ExtKeyUsageServerAuth {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *NodeCredentials) setNodeCA(nodeCAPEM []byte) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(nodeCAPEM) {
+		klog.InfoS("Ignore invalid node CA certificate", "file", c.nodeCAFile)
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nodeCAs = pool
+}
+
+func (c *NodeCredentials) nodeCAPool() *x509.CertPool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.nodeCAs
+}
+
+// renew request a new certificate from fornaxcores in order, bootstrap token is used if node does not have a valid certificate
+func (c *NodeCredentials) renew(current *tls.Certificate) (*pki.KeyPair, error) {
+	csrPEM, keyPEM, err := pki.NewCertificateRequest(pkix.Name{CommonName: pki.NodeCommonName(c.identifier.GetIdentifier()), Organization: []string{pki.NodeOrganization}})
+	if err != nil {
+		return nil, err
+	}
+	request := &fornax.CertificateRequest{NodeIdentifier: c.identifier, Csr: csrPEM}
+	if current == nil {
+		token, err := os.ReadFile(c.bootstrapTokenFile)
+		if err != nil {
+			return nil, err
+		}
+		request.BootstrapToken = strings.TrimSpace(string(token))
+	}
+
+	err = errors.New("no fornaxcore to request certificate")
+	for _, endpoint := range c.fornaxCoreUrls {
+		var response *fornax.CertificateResponse
+		if response, err = c.requestCertificate(endpoint, current, request); err != nil {
+			klog.ErrorS(err, "Failed to request node certificate", "endpoint", endpoint, "bootstrap", current == nil)
+			continue
+		}
+		pair := &pki.KeyPair{CertPEM: response.GetCertificate(), KeyPEM: keyPEM}
+		if err = pki.WriteKeyPair(c.certFile, c.keyFile, pair); err != nil {
+			return nil, err
+		}
+		if nodeCAPEM := response.GetCaCertificate(); len(nodeCAPEM) > 0 {
+			if err = os.WriteFile(c.nodeCAFile, nodeCAPEM, 0644); err != nil {
+				return nil, err
+			}
+			c.setNodeCA(nodeCAPEM)
+		}
+		return pair, nil
+	}
+	return nil, err
+}
+
+func (c *NodeCredentials) requestCertificate(endpoint string, current *tls.Certificate, request *fornax.CertificateRequest) (*fornax.CertificateResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultConnTimeout)
+	defer cancel()
+	config := &tls.Config{MinVersion: tls.VersionTLS12, RootCAs: c.rootCAs}
+	if current != nil {
+		config.Certificates = []tls.Certificate{*current}
+	}
+	conn, err := grpc.DialContext(ctx, endpoint, grpc.WithBlock(), grpc.WithTransportCredentials(credentials.NewTLS(config)))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return fornax.NewFornaxCoreServiceClient(conn).RequestCertificate(ctx, request)
+}
+
+// Run renew node certificate until context is done
+func (c *NodeCredentials) Run(ctx context.Context) {
+	go c.certificate.Run(ctx)
+}
+
+// TransportCredentials is grpc credentials of fornaxcore connections, new connections use current node certificate
+func (c *NodeCredentials) TransportCredentials() credentials.TransportCredentials {
+	return credentials.NewTLS(&tls.Config{
+		MinVersion:           tls.VersionTLS12,
+		RootCAs:              c.rootCAs,
+		GetClientCertificate: c.certificate.GetClientCertificate,
+	})
+}
+
+// ServerTLSConfig is tls config of node debug service, it's served with node certificate,
+// and require a client certificate issued by node CA, caller check client is fornaxcore
+func (c *NodeCredentials) ServerTLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			nodeCAs := c.nodeCAPool()
+			if nodeCAs == nil {
+				return nil, NodeCANotFoundError
+			}
+			return &tls.Config{
+				MinVersion:     tls.VersionTLS12,
+				GetCertificate: c.certificate.GetCertificate,
+				ClientAuth:     tls.RequireAndVerifyClientCert,
+				ClientCAs:      nodeCAs,
+			}, nil
+		},
+	}
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostconfig
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultSysctlRoot       = "/proc/sys"
+	DefaultSysRoot          = "/sys"
+	DefaultProcSwapsFile    = "/proc/swaps"
+	DefaultZramDevice       = "zram0"
+	DefaultZramSwapPriority = 100
+)
+
+var (
+	hugePageSizeKB = map[string]int64{
+		"2Mi": 2 * 1024,
+		"1Gi": 1024 * 1024,
+	}
+)
+
+// Reconciler set host configuration of a node config profile, and report settings host does not match after reconcile
+type Reconciler struct {
+	sysctlRoot string
+	sysRoot    string
+	procSwaps  string
+	zramDevice string
+}
+
+func NewReconciler() *Reconciler {
+	return &Reconciler{
+		sysctlRoot: DefaultSysctlRoot,
+		sysRoot:    DefaultSysRoot,
+		procSwaps:  DefaultProcSwapsFile,
+		zramDevice: DefaultZramDevice,
+	}
+}
+
+// Reconcile apply profile spec on host, only settings differing from spec are written, return drifts which could not be fixed
+func (r *Reconciler) Reconcile(spec *fornaxv1.NodeConfigProfileSpec) []string {
+	drifts := []string{}
+	names := []string{}
+	for k := range spec.Sysctls {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if drift := r.reconcileSysctl(name, spec.Sysctls[name]); len(drift) > 0 {
+			drifts = append(drifts, drift)
+		}
+	}
+	for _, v := range spec.HugePages {
+		if drift := r.reconcileHugePages(v); len(drift) > 0 {
+			drifts = append(drifts, drift)
+		}
+	}
+	if spec.Zram != nil {
+		if drift := r.reconcileZram(spec.Zram); len(drift) > 0 {
+			drifts = append(drifts, drift)
+		}
+	}
+	return drifts
+}
+
+func readValue(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(strings.Fields(string(data)), " "), nil
+}
+
+// writeValue write value to a proc or sys file if current value is different, return value after write
+func writeValue(path, value string) (string, error) {
+	want := strings.Join(strings.Fields(value), " ")
+	current, err := readValue(path)
+	if err != nil {
+		return "", err
+	}
+	if current == want {
+		return current, nil
+	}
+	if err := os.WriteFile(path, []byte(value), 0644); err != nil {
+		return current, err
+	}
+	return readValue(path)
+}
+
+// sysctlPath map sysctl name to its file, names use dot as separator unless they use slash, e.g. net/ipv4/conf/eth0.100/forwarding
+func (r *Reconciler) sysctlPath(name string) string {
+	if strings.Contains(name, "/") {
+		return filepath.Join(r.sysctlRoot, name)
+	}
+	return filepath.Join(r.sysctlRoot, strings.ReplaceAll(name, ".", "/"))
+}
+
+func (r *Reconciler) reconcileSysctl(name, value string) string {
+	want := strings.Join(strings.Fields(value), " ")
+	got, err := writeValue(r.sysctlPath(name), value)
+	if err != nil {
+		return fmt.Sprintf("sysctl %s: %v", name, err)
+	}
+	if got != want {
+		return fmt.Sprintf("sysctl %s is %q, want %q", name, got, want)
+	}
+	return ""
+}
+
+func (r *Reconciler) reconcileHugePages(hugePages fornaxv1.HugePageReservation) string {
+	sizeKB, found := hugePageSizeKB[hugePages.PageSize]
+	if !found {
+		return fmt.Sprintf("hugepages %s: unsupported page size", hugePages.PageSize)
+	}
+	path := filepath.Join(r.sysRoot, "kernel/mm/hugepages", fmt.Sprintf("hugepages-%dkB", sizeKB), "nr_hugepages")
+	got, err := writeValue(path, strconv.Itoa(int(hugePages.Count)))
+	if err != nil {
+		return fmt.Sprintf("hugepages %s: %v", hugePages.PageSize, err)
+	}
+	// kernel reserve less pages when memory is fragmented
+	if got != strconv.Itoa(int(hugePages.Count)) {
+		return fmt.Sprintf("hugepages %s reserved %s pages, want %d", hugePages.PageSize, got, hugePages.Count)
+	}
+	return ""
+}
+
+func (r *Reconciler) zramSwapActive() (bool, error) {
+	data, err := os.ReadFile(r.procSwaps)
+	if err != nil {
+		return false, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && fields[0] == filepath.Join("/dev", r.zramDevice) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// zramAlgorithm return selected compression algorithm, kernel list all algorithms with selected one in brackets
+func zramAlgorithm(algorithms string) string {
+	for _, v := range strings.Fields(algorithms) {
+		if strings.HasPrefix(v, "[") && strings.HasSuffix(v, "]") {
+			return strings.Trim(v, "[]")
+		}
+	}
+	return algorithms
+}
+
+func (r *Reconciler) zramMatch(zram *fornaxv1.ZramConfig, active bool) bool {
+	if !zram.Enabled {
+		return !active
+	}
+	deviceDir := filepath.Join(r.sysRoot, "block", r.zramDevice)
+	disksize, err := readValue(filepath.Join(deviceDir, "disksize"))
+	if err != nil || disksize != strconv.FormatInt(zram.Size.Value(), 10) {
+		return false
+	}
+	if len(zram.Algorithm) > 0 {
+		algorithms, err := readValue(filepath.Join(deviceDir, "comp_algorithm"))
+		if err != nil || zramAlgorithm(algorithms) != zram.Algorithm {
+			return false
+		}
+	}
+	return active
+}
+
+// reconcileZram set up zram device as swap, device must be reset to change its size or algorithm, so swap is turned off first
+func (r *Reconciler) reconcileZram(zram *fornaxv1.ZramConfig) string {
+	deviceDir := filepath.Join(r.sysRoot, "block", r.zramDevice)
+	device := filepath.Join("/dev", r.zramDevice)
+	if zram.Enabled && zram.Size == nil {
+		return "zram: size is not set"
+	}
+	if _, err := os.Stat(deviceDir); err != nil {
+		if !zram.Enabled {
+			return ""
+		}
+		return fmt.Sprintf("zram: device %s not found, zram kernel module is not loaded", r.zramDevice)
+	}
+	active, err := r.zramSwapActive()
+	if err != nil {
+		return fmt.Sprintf("zram: %v", err)
+	}
+	if r.zramMatch(zram, active) {
+		return ""
+	}
+
+	klog.InfoS("Reconcile zram swap device", "device", device, "enabled", zram.Enabled, "size", zram.Size, "algorithm", zram.Algorithm)
+	if active {
+		if out, err := exec.Command("swapoff", device).CombinedOutput(); err != nil {
+			return fmt.Sprintf("zram: swapoff failed: %v, %s", err, strings.TrimSpace(string(out)))
+		}
+	}
+	if err := os.WriteFile(filepath.Join(deviceDir, "reset"), []byte("1"), 0644); err != nil {
+		return fmt.Sprintf("zram: reset failed: %v", err)
+	}
+	if !zram.Enabled {
+		return ""
+	}
+	if len(zram.Algorithm) > 0 {
+		if err := os.WriteFile(filepath.Join(deviceDir, "comp_algorithm"), []byte(zram.Algorithm), 0644); err != nil {
+			return fmt.Sprintf("zram: set algorithm %s failed: %v", zram.Algorithm, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(deviceDir, "disksize"), []byte(strconv.FormatInt(zram.Size.Value(), 10)), 0644); err != nil {
+		return fmt.Sprintf("zram: set size failed: %v", err)
+	}
+	priority := zram.SwapPriority
+	if priority == 0 {
+		priority = DefaultZramSwapPriority
+	}
+	if out, err := exec.Command("mkswap", device).CombinedOutput(); err != nil {
+		return fmt.Sprintf("zram: mkswap failed: %v, %s", err, strings.TrimSpace(string(out)))
+	}
+	if out, err := exec.Command("swapon", "-p", strconv.Itoa(int(priority)), device).CombinedOutput(); err != nil {
+		return fmt.Sprintf("zram: swapon failed: %v, %s", err, strings.TrimSpace(string(out)))
+	}
+	return ""
+}
+
+// EvictionReservation return resources kept available by eviction thresholds of profile, they are excluded from node allocatable
+func EvictionReservation(spec *fornaxv1.NodeConfigProfileSpec) v1.ResourceList {
+	reserved := v1.ResourceList{}
+	if spec == nil || spec.EvictionThresholds == nil {
+		return reserved
+	}
+	if v := spec.EvictionThresholds.MemoryAvailable; v != nil {
+		reserved[v1.ResourceMemory] = v.DeepCopy()
+	}
+	// node agent report node storage as storage resource
+	if v := spec.EvictionThresholds.EphemeralStorageAvailable; v != nil {
+		reserved[v1.ResourceStorage] = v.DeepCopy()
+	}
+	return reserved
+}
@@ -19,7 +19,9 @@ package images
 import (
 	"fmt"
 	"strings"
+	"sync"
 
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
 	dockerref "github.com/docker/distribution/reference"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
@@ -34,18 +36,67 @@ type ImageManager interface {
 
 // imageManager provides the functionalities for image pulling.
 type imageManager struct {
+	mu           sync.Mutex
 	imageRefs    map[string]*criv1.Image
+	pulling      map[string]chan struct{}
 	imageService cri.ImageManagerService
 	authConfig   *criv1.AuthConfig
+	limiter      *pullLimiter
 }
 
 var _ ImageManager = &imageManager{}
 
-func NewImageManager(imageService cri.ImageManagerService, authConfig *criv1.AuthConfig) ImageManager {
+func NewImageManager(imageService cri.ImageManagerService, authConfig *criv1.AuthConfig, limiterConfig PullLimiterConfig) ImageManager {
 	return &imageManager{
 		imageRefs:    map[string]*criv1.Image{},
+		pulling:      map[string]chan struct{}{},
 		imageService: imageService,
 		authConfig:   authConfig,
+		limiter:      newPullLimiter(limiterConfig),
+	}
+}
+
+// pullPriority return image pull priority annotated on pod, pods created for pending sessions are pulled first
+func pullPriority(podSandboxConfig *criv1.PodSandboxConfig) string {
+	if podSandboxConfig != nil && podSandboxConfig.Annotations[fornaxv1.AnnotationFornaxCoreImagePullPriority] == fornaxv1.ImagePullPriorityPrewarm {
+		return fornaxv1.ImagePullPriorityPrewarm
+	}
+	return fornaxv1.ImagePullPrioritySession
+}
+
+func (m *imageManager) cachedImage(imageWithTag string) (*criv1.Image, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	image, found := m.imageRefs[imageWithTag]
+	return image, found
+}
+
+func (m *imageManager) cacheImage(imageWithTag string, image *criv1.Image) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.imageRefs[imageWithTag] = image
+}
+
+// startPull return true if caller should pull image, otherwise it wait for a in flight pull of same image to finish
+func (m *imageManager) startPull(imageWithTag string) bool {
+	m.mu.Lock()
+	done, found := m.pulling[imageWithTag]
+	if !found {
+		m.pulling[imageWithTag] = make(chan struct{})
+		m.mu.Unlock()
+		return true
+	}
+	m.mu.Unlock()
+	<-done
+	return false
+}
+
+func (m *imageManager) finishPull(imageWithTag string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if done, found := m.pulling[imageWithTag]; found {
+		close(done)
+		delete(m.pulling, imageWithTag)
 	}
 }
 
@@ -69,7 +120,7 @@ func (m *imageManager) PullImageForContainer(container *v1.Container, podSandbox
 		return nil, ErrInvalidImageName
 	}
 
-	image, found := m.imageRefs[imageWithTag]
+	image, found := m.cachedImage(imageWithTag)
 	if found {
 		klog.Infof("Container image with tag %s already present on machine", imageWithTag)
 		return image, nil
@@ -98,13 +149,23 @@ func (m *imageManager) PullImageForContainer(container *v1.Container, podSandbox
 	}
 
 	if image != nil {
-		m.imageRefs[imageWithTag] = image
+		m.cacheImage(imageWithTag, image)
 		klog.InfoS("Container image already present on machine", "image", image, "tag", imageWithTag)
 		return image, nil
 	}
 
+	// only one pull of a image at a time, others wait for it and list image again
+	if !m.startPull(imageWithTag) {
+		return m.PullImageForContainer(container, podSandboxConfig)
+	}
+	defer m.finishPull(imageWithTag)
+
+	priority := pullPriority(podSandboxConfig)
+	m.limiter.acquire(priority)
+	klog.InfoS("Pulling image", "image", imageWithTag, "priority", priority)
 	_, err = m.imageService.PullImage(imageSpec, m.authConfig, podSandboxConfig)
 	if err != nil {
+		m.limiter.release(priority, 0)
 		klog.ErrorS(err, "Failed to pull image", "image", imageWithTag)
 		return nil, ErrImagePull
 	}
@@ -114,6 +175,7 @@ func (m *imageManager) PullImageForContainer(container *v1.Container, podSandbox
 	})
 
 	if err != nil {
+		m.limiter.release(priority, 0)
 		klog.ErrorS(err, "Failed to list image", "image", imageWithTag)
 		return nil, ErrImageInspect
 	}
@@ -124,10 +186,11 @@ func (m *imageManager) PullImageForContainer(container *v1.Container, podSandbox
 		}
 		if present {
 			image = v
-			m.imageRefs[imageWithTag] = image
+			m.cacheImage(imageWithTag, image)
 			break
 		}
 	}
+	m.limiter.release(priority, image.GetSize_())
 
 	return image, nil
 }
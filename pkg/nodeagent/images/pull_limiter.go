@@ -0,0 +1,192 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	nodeconfig "centaurusinfra.io/fornax-serverless/pkg/nodeagent/config"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+var (
+	imagePullWaitSeconds = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem:      "fornax_node_image_pull",
+			Name:           "wait_seconds",
+			Help:           "Time a image pull waited for bandwidth and pull slot, by priority",
+			Buckets:        metrics.ExponentialBuckets(0.01, 2, 14),
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"priority"},
+	)
+	imagePullBytes = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_node_image_pull",
+			Name:           "bytes_total",
+			Help:           "Size of pulled images, by priority",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"priority"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(imagePullWaitSeconds)
+	legacyregistry.MustRegister(imagePullBytes)
+}
+
+// PullLimiterConfig limit image pulls on node, 0 bandwidth means unlimited
+type PullLimiterConfig struct {
+	BandwidthBytesPerSecond int64
+	PrewarmBandwidthPercent int
+	MaxParallelPulls        int
+}
+
+func pullPriorityRank(priority string) int {
+	if priority == fornaxv1.ImagePullPriorityPrewarm {
+		return 1
+	}
+	return 0
+}
+
+type pullWaiter struct {
+	priority string
+	seq      uint64
+	ready    chan struct{}
+}
+
+type pullWaiterQueue []*pullWaiter
+
+func (q pullWaiterQueue) Len() int { return len(q) }
+func (q pullWaiterQueue) Less(i, j int) bool {
+	ri, rj := pullPriorityRank(q[i].priority), pullPriorityRank(q[j].priority)
+	if ri != rj {
+		return ri < rj
+	}
+	return q[i].seq < q[j].seq
+}
+func (q pullWaiterQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *pullWaiterQueue) Push(x interface{}) { *q = append(*q, x.(*pullWaiter)) }
+func (q *pullWaiterQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[:n-1]
+	return item
+}
+
+// pullLimiter admit image pulls by priority, session pulls always go before prewarm pulls,
+// cri pull can not be throttled while it's downloading, so bandwidth is paced between pulls, each pulled image size is
+// charged to a budget refilled at bandwidth limit, next pull start when budget is paid off,
+// prewarm pulls wait for a second budget refilled at a percent of limit, so they only use that percent of bandwidth on average
+type pullLimiter struct {
+	mu              sync.Mutex
+	config          PullLimiterConfig
+	active          int
+	seq             uint64
+	waiters         pullWaiterQueue
+	nextFree        time.Time
+	nextPrewarmFree time.Time
+	timer           *time.Timer
+}
+
+func newPullLimiter(config PullLimiterConfig) *pullLimiter {
+	if config.MaxParallelPulls <= 0 {
+		config.MaxParallelPulls = nodeconfig.DefaultMaxParallelImagePulls
+	}
+	if config.PrewarmBandwidthPercent <= 0 || config.PrewarmBandwidthPercent > 100 {
+		config.PrewarmBandwidthPercent = nodeconfig.DefaultImagePullPrewarmPercent
+	}
+	return &pullLimiter{config: config, waiters: pullWaiterQueue{}}
+}
+
+// acquire block until a pull of priority can start
+func (l *pullLimiter) acquire(priority string) {
+	start := time.Now()
+	l.mu.Lock()
+	l.seq += 1
+	w := &pullWaiter{priority: priority, seq: l.seq, ready: make(chan struct{})}
+	heap.Push(&l.waiters, w)
+	l.dispatchNoLock()
+	l.mu.Unlock()
+
+	<-w.ready
+	imagePullWaitSeconds.WithLabelValues(priority).Observe(time.Since(start).Seconds())
+}
+
+// release free pull slot and charge bytes pulled to bandwidth budget
+func (l *pullLimiter) release(priority string, bytes uint64) {
+	imagePullBytes.WithLabelValues(priority).Add(float64(bytes))
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.active -= 1
+	if l.config.BandwidthBytesPerSecond > 0 && bytes > 0 {
+		cost := time.Duration(float64(bytes) / float64(l.config.BandwidthBytesPerSecond) * float64(time.Second))
+		now := time.Now()
+		l.nextFree = chargeBudget(l.nextFree, now, cost)
+		l.nextPrewarmFree = chargeBudget(l.nextPrewarmFree, now, cost*100/time.Duration(l.config.PrewarmBandwidthPercent))
+	}
+	l.dispatchNoLock()
+}
+
+func chargeBudget(free, now time.Time, cost time.Duration) time.Time {
+	if free.Before(now) {
+		free = now
+	}
+	return free.Add(cost)
+}
+
+// waitOf return how long a pull of priority wait for bandwidth budget
+func (l *pullLimiter) waitOf(priority string) time.Duration {
+	wait := time.Until(l.nextFree)
+	if priority == fornaxv1.ImagePullPriorityPrewarm {
+		if prewarmWait := time.Until(l.nextPrewarmFree); prewarmWait > wait {
+			wait = prewarmWait
+		}
+	}
+	return wait
+}
+
+// dispatchNoLock start waiters in priority order while there are free slots and bandwidth budget,
+// a timer redispatch when budget is paid off
+func (l *pullLimiter) dispatchNoLock() {
+	for l.waiters.Len() > 0 && l.active < l.config.MaxParallelPulls {
+		if wait := l.waitOf(l.waiters[0].priority); wait > 0 {
+			// head of queue could have changed to a session pull which wait less, reset timer
+			if l.timer != nil {
+				l.timer.Stop()
+			}
+			klog.V(5).InfoS("Image pulls wait for bandwidth", "wait", wait, "waiting pulls", l.waiters.Len())
+			l.timer = time.AfterFunc(wait, func() {
+				l.mu.Lock()
+				defer l.mu.Unlock()
+				l.timer = nil
+				l.dispatchNoLock()
+			})
+			return
+		}
+		w := heap.Pop(&l.waiters).(*pullWaiter)
+		l.active += 1
+		close(w.ready)
+	}
+}
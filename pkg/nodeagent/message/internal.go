@@ -21,10 +21,15 @@ import (
 
 	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
+
+	v1 "k8s.io/api/core/v1"
 )
 
 type NodeUpdate struct{}
 
+// sent periodically to node actor to check node memory pressure and shed session load
+type NodeMemoryPressureCheck struct{}
+
 type PodSandboxCreated struct {
 	Pod *types.FornaxPod
 }
@@ -95,10 +100,27 @@ type PodTerminate struct{}
 
 type PodHibernate struct{}
 
+// when node evict a idle pod to relieve resource pressure, pod is not evicted if it has open sessions
+type PodEvict struct {
+	Reason  string
+	Message string
+}
+
 type PodCreate struct {
 	Pod *types.FornaxPod
 }
 
+// when application config data is changed by fornaxcore
+type PodConfigUpdate struct {
+	ConfigMap *v1.ConfigMap
+}
+
+// when application secret is rotated by fornaxcore, PreviousSecret is nil after previous version is revoked
+type PodSecretUpdate struct {
+	Secret         *types.FornaxSecret
+	PreviousSecret *types.FornaxSecret
+}
+
 type PodCleanup struct {
 	Pod *types.FornaxPod
 }
@@ -118,13 +140,51 @@ type SessionOpen struct {
 
 type SessionClose struct {
 	SessionId   string
+	RequestId   string
 	GracePeriod time.Duration
 }
 
+// when a time limited session is going to reach its time limit at deadline
+type SessionTimeLimitWarning struct {
+	SessionId string
+	Deadline  time.Time
+}
+
+// when a time limited session reached its time limit
+type SessionTimeLimitExceeded struct {
+	SessionId string
+}
+
+// when fornaxcore notify session it will be closed before deadline
+type SessionDrain struct {
+	SessionId string
+	RequestId string
+	Reason    string
+	Deadline  time.Time
+}
+
+// when fornaxcore move a session to another pod
+// usage of a session in a interval, final is last usage of session on pod
+type SessionUsageReport struct {
+	Session *types.FornaxSession
+	Usage   types.FornaxSessionUsage
+	EndTime time.Time
+	Final   bool
+}
+
+type SessionMigrate struct {
+	SessionId string
+	RequestId string
+	TargetPod string
+}
+
 type SessionState struct {
 	SessionId      string
 	SessionState   types.SessionState
 	ClientSessions []types.ClientSession
+
+	// session metadata exported by application when session is closed for migration
+	MigrationMetadata map[string]string
 }
 
 type SessionStatusChange struct {
@@ -31,6 +31,8 @@ var _ NetworkAddressProvider = &LocalNetworkAddressProvider{}
 type LocalNetworkAddressProvider struct {
 	NodeIPs  []net.IP
 	Hostname string
+	// ipv6 address reported by a dual stack node, first local ipv6 address is used if not set
+	NodeIPv6 net.IP
 }
 
 func GetLocalV4IP() ([]net.IP, error) {
@@ -54,6 +56,30 @@ func GetLocalV4IP() ([]net.IP, error) {
 	return ips, nil
 }
 
+// GetLocalV6IP return global and unique local ipv6 addresses, unique local addresses come first
+func GetLocalV6IP() ([]net.IP, error) {
+	private, global := []net.IP{}, []net.IP{}
+
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return []net.IP{}, err
+	}
+	for _, address := range addrs {
+		if ipnet, ok := address.(*net.IPNet); ok && ipnet.IP.To4() == nil && ipnet.IP.To16() != nil {
+			v := ipnet.IP
+			if v.IsLoopback() || v.IsLinkLocalMulticast() || v.IsLinkLocalUnicast() || v.IsUnspecified() || v.IsMulticast() || v.IsInterfaceLocalMulticast() {
+				continue
+			}
+			if v.IsPrivate() {
+				private = append(private, v)
+			} else if v.IsGlobalUnicast() {
+				global = append(global, v)
+			}
+		}
+	}
+	return append(private, global...), nil
+}
+
 func (p *LocalNetworkAddressProvider) GetNetAddress() ([]v1.NodeAddress, error) {
 	var err error
 	var nodeIP, secondaryNodeIP, externalNodeIP net.IP
@@ -94,6 +120,15 @@ func (p *LocalNetworkAddressProvider) GetNetAddress() ([]v1.NodeAddress, error)
 	if externalNodeIP != nil {
 		addresses = append(addresses, v1.NodeAddress{Type: v1.NodeExternalIP, Address: externalNodeIP.String()})
 	}
+	// dual stack node report its ipv6 address after ipv4 addresses, ipv4 is still primary node ip
+	if p.NodeIPv6 == nil {
+		if ips, err := GetLocalV6IP(); err == nil && len(ips) > 0 {
+			p.NodeIPv6 = ips[0]
+		}
+	}
+	if p.NodeIPv6 != nil {
+		addresses = append(addresses, v1.NodeAddress{Type: v1.NodeInternalIP, Address: p.NodeIPv6.String()})
+	}
 	addresses = append(addresses, v1.NodeAddress{Type: v1.NodeHostName, Address: p.Hostname})
 
 	return addresses, nil
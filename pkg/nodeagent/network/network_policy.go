@@ -0,0 +1,155 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package network
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os/exec"
+	"strings"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	netutils "k8s.io/utils/net"
+)
+
+const (
+	policyIngressChain = "FORNAX-INGRESS"
+	policyEgressChain  = "FORNAX-EGRESS"
+)
+
+// BuildNetworkPolicyRules return iptables-restore input of filter table in pod network namespace for one ip family,
+// whole filter table is replaced, pod network namespace is owned by node agent, traffic with host addresses is always allowed
+func BuildNetworkPolicyRules(policy *fornaxv1.ApplicationNetworkPolicy, ipv6 bool, hostIPs []net.IP) []byte {
+	lines := []string{
+		"*filter",
+		":INPUT ACCEPT [0:0]",
+		":FORWARD ACCEPT [0:0]",
+		":OUTPUT ACCEPT [0:0]",
+		fmt.Sprintf(":%s - [0:0]", policyIngressChain),
+		fmt.Sprintf(":%s - [0:0]", policyEgressChain),
+		fmt.Sprintf("-A INPUT -j %s", policyIngressChain),
+		fmt.Sprintf("-A OUTPUT -j %s", policyEgressChain),
+	}
+	lines = append(lines, buildPolicyChain(policyIngressChain, policy.Ingress, true, ipv6, hostIPs)...)
+	lines = append(lines, buildPolicyChain(policyEgressChain, policy.Egress, false, ipv6, hostIPs)...)
+	lines = append(lines, "COMMIT", "")
+	return []byte(strings.Join(lines, "\n"))
+}
+
+func buildPolicyChain(chain string, rules []fornaxv1.NetworkPolicyRule, ingress, ipv6 bool, hostIPs []net.IP) []string {
+	peer, iface := "-d", "-o"
+	if ingress {
+		peer, iface = "-s", "-i"
+	}
+	lines := []string{
+		fmt.Sprintf("-A %s %s lo -j ACCEPT", chain, iface),
+		fmt.Sprintf("-A %s -m conntrack --ctstate RELATED,ESTABLISHED -j ACCEPT", chain),
+	}
+	if ipv6 {
+		// neighbor discovery
+		lines = append(lines, fmt.Sprintf("-A %s -p ipv6-icmp -j ACCEPT", chain))
+	}
+	for _, ip := range hostIPs {
+		if netutils.IsIPv6(ip) != ipv6 {
+			continue
+		}
+		bits := 32
+		if ipv6 {
+			bits = 128
+		}
+		lines = append(lines, fmt.Sprintf("-A %s %s %s/%d -j ACCEPT", chain, peer, ip.String(), bits))
+	}
+
+	for _, rule := range rules {
+		cidrs := []string{}
+		for _, cidr := range rule.CIDRs {
+			if netutils.IsIPv6CIDRString(cidr) == ipv6 {
+				cidrs = append(cidrs, fmt.Sprintf(" %s %s", peer, cidr))
+			}
+		}
+		if len(rule.CIDRs) > 0 && len(cidrs) == 0 {
+			// rule only has cidrs of other ip family
+			continue
+		}
+		if len(cidrs) == 0 {
+			cidrs = []string{""}
+		}
+		ports := []string{}
+		for _, port := range rule.Ports {
+			ports = append(ports, policyPortMatch(port))
+		}
+		if len(ports) == 0 {
+			ports = []string{""}
+		}
+		for _, cidr := range cidrs {
+			for _, port := range ports {
+				lines = append(lines, fmt.Sprintf("-A %s%s%s -j ACCEPT", chain, cidr, port))
+			}
+		}
+	}
+	lines = append(lines, fmt.Sprintf("-A %s -j DROP", chain))
+	return lines
+}
+
+func policyPortMatch(port fornaxv1.NetworkPolicyPort) string {
+	protocol := port.Protocol
+	if len(protocol) == 0 {
+		protocol = v1.ProtocolTCP
+	}
+	p := strings.ToLower(string(protocol))
+	if port.Port == 0 {
+		return fmt.Sprintf(" -p %s", p)
+	}
+	if port.EndPort > port.Port {
+		return fmt.Sprintf(" -p %s -m %s --dport %d:%d", p, p, port.Port, port.EndPort)
+	}
+	return fmt.Sprintf(" -p %s -m %s --dport %d", p, p, port.Port)
+}
+
+// ApplyNetworkPolicy program network policy rules in pod network namespace using iptables-restore,
+// ipv6 rules are only programmed if pod has ipv6 address
+func ApplyNetworkPolicy(netns string, policy *fornaxv1.ApplicationNetworkPolicy, podIPs []string) error {
+	hostIPs, err := GetLocalV4IP()
+	if err != nil {
+		return err
+	}
+	if v6, err := GetLocalV6IP(); err == nil {
+		hostIPs = append(hostIPs, v6...)
+	}
+
+	if err := restoreRulesInNetNS(netns, "iptables-restore", BuildNetworkPolicyRules(policy, false, hostIPs)); err != nil {
+		return err
+	}
+	for _, v := range podIPs {
+		if netutils.IsIPv6String(v) {
+			return restoreRulesInNetNS(netns, "ip6tables-restore", BuildNetworkPolicyRules(policy, true, hostIPs))
+		}
+	}
+	return nil
+}
+
+func restoreRulesInNetNS(netns, restore string, rules []byte) error {
+	cmd := exec.Command("nsenter", fmt.Sprintf("--net=%s", netns), restore)
+	cmd.Stdin = bytes.NewReader(rules)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed in network namespace %s: %v, %s", restore, netns, err, strings.TrimSpace(string(out)))
+	}
+	klog.V(5).InfoS("Programmed network policy", "netns", netns, "rules", string(rules))
+	return nil
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"net"
+	"time"
+)
+
+const (
+	stunBindingRequest  = 0x0001
+	stunBindingResponse = 0x0101
+	stunMagicCookie     = 0x2112A442
+	stunHeaderLength    = 20
+
+	stunAttrMappedAddress    = 0x0001
+	stunAttrXorMappedAddress = 0x0020
+
+	stunFamilyIPv4 = 0x01
+	stunFamilyIPv6 = 0x02
+
+	DefaultSTUNTimeout = 500 * time.Millisecond
+)
+
+var (
+	InvalidSTUNResponseError = errors.New("invalid stun binding response")
+	NoMappedAddressError     = errors.New("stun binding response does not have mapped address")
+)
+
+// DiscoverPublicAddress send a stun binding request from local address to stun server and return mapped address,
+// it's public address of local address if node is behind a nat with endpoint independent mapping,
+// local address must not be used by others, e.g. a host port which is not yet mapped to pod
+func DiscoverPublicAddress(localAddress, stunServer string, timeout time.Duration) (*net.UDPAddr, error) {
+	laddr, err := net.ResolveUDPAddr("udp", localAddress)
+	if err != nil {
+		return nil, err
+	}
+	raddr, err := net.ResolveUDPAddr("udp", stunServer)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenUDP("udp", laddr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	request := make([]byte, stunHeaderLength)
+	binary.BigEndian.PutUint16(request[0:2], stunBindingRequest)
+	binary.BigEndian.PutUint16(request[2:4], 0)
+	binary.BigEndian.PutUint32(request[4:8], stunMagicCookie)
+	if _, err := rand.Read(request[8:20]); err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(timeout))
+	if _, err := conn.WriteToUDP(request, raddr); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 1024)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return nil, err
+		}
+		// ignore stray packets
+		if !from.IP.Equal(raddr.IP) || from.Port != raddr.Port {
+			continue
+		}
+		return parseBindingResponse(buf[:n], request[8:20])
+	}
+}
+
+// parseBindingResponse return xor mapped address, or mapped address of a classic stun server
+func parseBindingResponse(data []byte, transactionId []byte) (*net.UDPAddr, error) {
+	if len(data) < stunHeaderLength ||
+		binary.BigEndian.Uint16(data[0:2]) != stunBindingResponse ||
+		binary.BigEndian.Uint32(data[4:8]) != stunMagicCookie ||
+		string(data[8:20]) != string(transactionId) {
+		return nil, InvalidSTUNResponseError
+	}
+	length := int(binary.BigEndian.Uint16(data[2:4]))
+	if stunHeaderLength+length > len(data) {
+		return nil, InvalidSTUNResponseError
+	}
+	var mapped *net.UDPAddr
+	attrs := data[stunHeaderLength : stunHeaderLength+length]
+	for len(attrs) >= 4 {
+		attrType := binary.BigEndian.Uint16(attrs[0:2])
+		attrLength := int(binary.BigEndian.Uint16(attrs[2:4]))
+		if 4+attrLength > len(attrs) {
+			return nil, InvalidSTUNResponseError
+		}
+		value := attrs[4 : 4+attrLength]
+		switch attrType {
+		case stunAttrXorMappedAddress:
+			if addr := parseAddress(value, data[4:20]); addr != nil {
+				return addr, nil
+			}
+		case stunAttrMappedAddress:
+			mapped = parseAddress(value, nil)
+		}
+		// attributes are padded to 4 bytes
+		padded := (attrLength + 3) &^ 3
+		if 4+padded > len(attrs) {
+			break
+		}
+		attrs = attrs[4+padded:]
+	}
+	if mapped == nil {
+		return nil, NoMappedAddressError
+	}
+	return mapped, nil
+}
+
+// parseAddress parse a address attribute, it's xor'ed with magic cookie and transaction id if xor is not nil
+func parseAddress(value []byte, xor []byte) *net.UDPAddr {
+	if len(value) < 4 {
+		return nil
+	}
+	family := value[1]
+	port := binary.BigEndian.Uint16(value[2:4])
+	var ip net.IP
+	switch {
+	case family == stunFamilyIPv4 && len(value) >= 8:
+		ip = net.IP(append([]byte{}, value[4:8]...))
+	case family == stunFamilyIPv6 && len(value) >= 20:
+		ip = net.IP(append([]byte{}, value[4:20]...))
+	default:
+		return nil
+	}
+	if xor != nil {
+		port ^= uint16(stunMagicCookie >> 16)
+		for i := range ip {
+			ip[i] ^= xor[i]
+		}
+	}
+	return &net.UDPAddr{IP: ip, Port: int(port)}
+}
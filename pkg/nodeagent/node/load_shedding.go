@@ -0,0 +1,279 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/config"
+	internal "centaurusinfra.io/fornax-serverless/pkg/nodeagent/message"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	nodeMemoryPressureFile = "/proc/pressure/memory"
+	nodeMemInfoFile        = "/proc/meminfo"
+
+	memoryPressureCheckPeriod = 10 * time.Second
+	// node leave pressure only after all signals stay below thresholds for this long, avoid flapping
+	memoryPressureRecoveryPeriod = 1 * time.Minute
+	// evict a few idle pods each check, give kernel time to reclaim memory before evicting more
+	maxEvictionsPerCheck = 2
+
+	NodeReasonMemoryPressure   = "NodeMemoryPressure"
+	NodeReasonNoMemoryPressure = "NodeHasSufficientMemory"
+	PodReasonEvicted           = "Evicted"
+)
+
+var (
+	nodeMemoryPressure = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Subsystem:      "fornax_nodeagent",
+			Name:           "memory_pressure",
+			Help:           "1 if node is under memory pressure and does not take new sessions",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+	loadSheddingEvictions = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_nodeagent",
+			Name:           "load_shedding_evictions_total",
+			Help:           "Number of idle pods evicted by node memory pressure",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(nodeMemoryPressure)
+	legacyregistry.MustRegister(loadSheddingEvictions)
+}
+
+// nodeMemorySample is node memory state used to decide load shedding, memory values are in KB as in /proc/meminfo
+type nodeMemorySample struct {
+	PressureAvg10   float64
+	MemoryTotal     uint64
+	MemoryAvailable uint64
+	SwapTotal       uint64
+	SwapFree        uint64
+}
+
+// readNodeMemoryPressureAvg10 read avg10 of "some" line in node memory pressure file,
+// e.g. some avg10=0.00 avg60=0.00 avg300=0.00 total=12345
+func readNodeMemoryPressureAvg10() (float64, error) {
+	f, err := os.Open(nodeMemoryPressureFile)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] != "some" {
+			continue
+		}
+		for _, field := range fields[1:] {
+			if strings.HasPrefix(field, "avg10=") {
+				return strconv.ParseFloat(strings.TrimPrefix(field, "avg10="), 64)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("no some avg10 in %s", nodeMemoryPressureFile)
+}
+
+func readNodeMemInfo(sample *nodeMemorySample) error {
+	f, err := os.Open(nodeMemInfoFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fields := map[string]*uint64{
+		"MemTotal:":     &sample.MemoryTotal,
+		"MemAvailable:": &sample.MemoryAvailable,
+		"SwapTotal:":    &sample.SwapTotal,
+		"SwapFree:":     &sample.SwapFree,
+	}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.Fields(scanner.Text())
+		if len(line) < 2 {
+			continue
+		}
+		if value, found := fields[line[0]]; found {
+			if *value, err = strconv.ParseUint(line[1], 10, 64); err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// sampleNodeMemory read node memory psi and meminfo, psi is missing if kernel does not enable it, then only meminfo is used
+func sampleNodeMemory() (*nodeMemorySample, error) {
+	sample := &nodeMemorySample{}
+	if err := readNodeMemInfo(sample); err != nil {
+		return nil, err
+	}
+	if avg10, err := readNodeMemoryPressureAvg10(); err == nil {
+		sample.PressureAvg10 = avg10
+	} else {
+		klog.V(5).InfoS("Failed to read node memory pressure", "err", err)
+	}
+	return sample, nil
+}
+
+// memoryPressureMessage return why node is under memory pressure, empty if all signals are below thresholds of node config
+func memoryPressureMessage(nodeConfig config.NodeConfiguration, sample *nodeMemorySample) string {
+	if nodeConfig.MemoryPressurePercent > 0 && sample.PressureAvg10 >= float64(nodeConfig.MemoryPressurePercent) {
+		return fmt.Sprintf("memory psi some avg10 %.2f%% is over %d%%", sample.PressureAvg10, nodeConfig.MemoryPressurePercent)
+	}
+	if nodeConfig.SwapPressurePercent > 0 && sample.SwapTotal > 0 {
+		if used := (sample.SwapTotal - sample.SwapFree) * 100 / sample.SwapTotal; used >= uint64(nodeConfig.SwapPressurePercent) {
+			return fmt.Sprintf("used swap %d%% is over %d%%", used, nodeConfig.SwapPressurePercent)
+		}
+	}
+	if nodeConfig.MemoryAvailablePercent > 0 && sample.MemoryTotal > 0 {
+		if available := sample.MemoryAvailable * 100 / sample.MemoryTotal; available < uint64(nodeConfig.MemoryAvailablePercent) {
+			return fmt.Sprintf("available memory %d%% is below %d%%", available, nodeConfig.MemoryAvailablePercent)
+		}
+	}
+	return ""
+}
+
+func loadSheddingEnabled(nodeConfig config.NodeConfiguration) bool {
+	return nodeConfig.MemoryPressurePercent > 0 || nodeConfig.SwapPressurePercent > 0 || nodeConfig.MemoryAvailablePercent > 0
+}
+
+func buildMemoryPressureCondition(pressure bool, message string) *v1.NodeCondition {
+	currentTime := metav1.NewTime(time.Now())
+	if pressure {
+		return &v1.NodeCondition{
+			Type:               v1.NodeMemoryPressure,
+			Status:             v1.ConditionTrue,
+			Reason:             NodeReasonMemoryPressure,
+			Message:            message,
+			LastHeartbeatTime:  currentTime,
+			LastTransitionTime: currentTime,
+		}
+	}
+	return &v1.NodeCondition{
+		Type:               v1.NodeMemoryPressure,
+		Status:             v1.ConditionFalse,
+		Reason:             NodeReasonNoMemoryPressure,
+		Message:            "Node has sufficient memory for new sessions",
+		LastHeartbeatTime:  currentTime,
+		LastTransitionTime: currentTime,
+	}
+}
+
+// startLoadShedding start go routine to check node memory pressure forever, check run in node actor
+func (n *FornaxNodeActor) startLoadShedding() {
+	if !loadSheddingEnabled(n.node.NodeConfig) {
+		return
+	}
+	go wait.Until(func() {
+		n.notify(n.innerActor.Reference(), internal.NodeMemoryPressureCheck{})
+	}, memoryPressureCheckPeriod, n.stopCh)
+}
+
+// checkMemoryPressure set node memory pressure condition and report node state to fornaxcore when pressure start or end,
+// fornaxcore stop scheduling pods and binding sessions to a node under pressure, node evict idle pods until pressure end,
+// pods with open sessions are never evicted, so memory is given back to active sessions instead of oom killing them
+func (n *FornaxNodeActor) checkMemoryPressure() {
+	sample, err := sampleNodeMemory()
+	if err != nil {
+		klog.ErrorS(err, "Failed to sample node memory")
+		return
+	}
+	message := memoryPressureMessage(n.node.NodeConfig, sample)
+	now := time.Now()
+	if len(message) > 0 {
+		n.lastMemoryPressure = now
+	}
+	pressure := len(message) > 0 || (n.underMemoryPressure && now.Sub(n.lastMemoryPressure) < memoryPressureRecoveryPeriod)
+
+	if pressure != n.underMemoryPressure || n.node.MemoryPressure == nil {
+		if pressure {
+			klog.InfoS("Node is under memory pressure, stop taking new sessions", "reason", message)
+			nodeMemoryPressure.Set(1)
+		} else {
+			klog.InfoS("Node memory pressure is gone, take new sessions")
+			nodeMemoryPressure.Set(0)
+		}
+		n.underMemoryPressure = pressure
+		n.node.MemoryPressure = buildMemoryPressureCondition(pressure, message)
+		mergeNodeConditions(n.node.V1Node, map[v1.NodeConditionType]*v1.NodeCondition{v1.NodeMemoryPressure: n.node.MemoryPressure})
+		n.notify(n.fornoxCoreRef, BuildFornaxGrpcNodeState(n.node, n.incrementNodeRevision()))
+	}
+
+	if pressure && len(message) > 0 {
+		n.evictIdlePods(message)
+	}
+}
+
+// evictIdlePods evict a few pods which have no open session, hibernated pods go first, then standby pods idle longest,
+// daemon pods and pods still being created or terminated are left alone
+func (n *FornaxNodeActor) evictIdlePods(message string) {
+	idlePods := []*types.FornaxPod{}
+	for _, v := range n.node.Pods.List() {
+		if v.Daemon || types.PodHasOpenSessions(v) {
+			continue
+		}
+		if v.FornaxPodState == types.PodStateHibernated || v.FornaxPodState == types.PodStateRunning {
+			idlePods = append(idlePods, v)
+		}
+	}
+	sort.Slice(idlePods, func(i, j int) bool {
+		hi, hj := idlePods[i].FornaxPodState == types.PodStateHibernated, idlePods[j].FornaxPodState == types.PodStateHibernated
+		if hi != hj {
+			return hi
+		}
+		return idlePods[i].LastStateTransitionTime.Before(idlePods[j].LastStateTransitionTime)
+	})
+	if len(idlePods) == 0 {
+		klog.InfoS("Node is under memory pressure, but no idle pod to evict")
+		return
+	}
+
+	for i := 0; i < len(idlePods) && i < maxEvictionsPerCheck; i++ {
+		podActor := n.podActors.Get(idlePods[i].Identifier)
+		if podActor == nil {
+			continue
+		}
+		klog.InfoS("Evict idle pod to relieve node memory pressure", "pod", types.UniquePodName(idlePods[i]), "state", idlePods[i].FornaxPodState)
+		loadSheddingEvictions.Inc()
+		n.notify(podActor.Reference(), internal.PodEvict{Reason: PodReasonEvicted, Message: fmt.Sprintf("node is under memory pressure, %s", message)})
+	}
+}
@@ -19,6 +19,7 @@ package node
 import (
 	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/pod"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func BuildFornaxGrpcNodeState(node *FornaxNode, revision int64) *grpc.FornaxCoreMessage {
@@ -64,3 +65,27 @@ func BuildFornaxGrpcNodeReady(node *FornaxNode, revision int64) *grpc.FornaxCore
 		},
 	}
 }
+
+func BuildFornaxGrpcNodeLeaseRenew(revision int64, renewIntervalSeconds int32) *grpc.FornaxCoreMessage {
+	messageType := grpc.MessageType_NODE_LEASE_RENEW
+	return &grpc.FornaxCoreMessage{
+		MessageType: messageType,
+		MessageBody: &grpc.FornaxCoreMessage_NodeLeaseRenew{
+			NodeLeaseRenew: &grpc.NodeLeaseRenew{
+				NodeRevision:         revision,
+				RenewIntervalSeconds: renewIntervalSeconds,
+				NodeTime:             timestamppb.Now(),
+			},
+		},
+	}
+}
+
+func BuildFornaxGrpcNodeConfigProfileStatus(status *grpc.NodeConfigProfileStatus) *grpc.FornaxCoreMessage {
+	messageType := grpc.MessageType_NODE_CONFIG_PROFILE_STATUS
+	return &grpc.FornaxCoreMessage{
+		MessageType: messageType,
+		MessageBody: &grpc.FornaxCoreMessage_NodeConfigProfileStatus{
+			NodeConfigProfileStatus: status,
+		},
+	}
+}
@@ -21,9 +21,11 @@ import (
 	"os"
 	goruntime "runtime"
 	"sort"
+	"strconv"
 	"sync"
 	"time"
 
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
 	default_config "centaurusinfra.io/fornax-serverless/pkg/config"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/config"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/dependency"
@@ -39,6 +41,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/component-base/version"
 
 	// criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
 	"k8s.io/klog/v2"
@@ -90,6 +93,10 @@ type FornaxNode struct {
 	Revision     int64
 	Pods         *PodPool
 	Dependencies *dependency.Dependencies
+	// resources excluded from allocatable by eviction thresholds of node config profile
+	EvictionReserved v1.ResourceList
+	// memory pressure condition set by load shedding, nil before first check or if load shedding is disabled
+	MemoryPressure *v1.NodeCondition
 }
 
 func (n *FornaxNode) initV1Node() (*v1.Node, error) {
@@ -128,8 +135,8 @@ func (n *FornaxNode) initV1Node() (*v1.Node, error) {
 			Phase:           v1.NodePending,
 			Conditions:      []v1.NodeCondition{},
 			Addresses:       []v1.NodeAddress{},
-			DaemonEndpoints: v1.NodeDaemonEndpoints{},
-			NodeInfo:        v1.NodeSystemInfo{},
+			DaemonEndpoints: v1.NodeDaemonEndpoints{KubeletEndpoint: v1.DaemonEndpoint{Port: n.NodeConfig.DebugServicePort}},
+			NodeInfo:        v1.NodeSystemInfo{KubeletVersion: version.Get().GitVersion}, // node agent version, fornaxcore check it against its version skew policy
 			Images:          []v1.ContainerImage{},
 			VolumesInUse:    []v1.UniqueVolumeName{},
 			VolumesAttached: []v1.AttachedVolume{},
@@ -137,6 +144,10 @@ func (n *FornaxNode) initV1Node() (*v1.Node, error) {
 		},
 	}
 
+	for k, v := range n.NodeConfig.NodeLabels {
+		node.Labels[k] = v
+	}
+
 	node.Status.Conditions = append(node.Status.Conditions, v1.NodeCondition{
 		Type:               v1.NodeReady,
 		Status:             v1.ConditionFalse,
@@ -161,8 +172,11 @@ func (n *FornaxNode) initV1Node() (*v1.Node, error) {
 	} else {
 		node.Status.Addresses = []v1.NodeAddress{
 			{Type: v1.NodeInternalIP, Address: n.NodeConfig.NodeIP},
-			{Type: v1.NodeHostName, Address: hostname},
 		}
+		if len(n.NodeConfig.NodeIPv6) > 0 {
+			node.Status.Addresses = append(node.Status.Addresses, v1.NodeAddress{Type: v1.NodeInternalIP, Address: n.NodeConfig.NodeIPv6})
+		}
+		node.Status.Addresses = append(node.Status.Addresses, v1.NodeAddress{Type: v1.NodeHostName, Address: hostname})
 	}
 
 	return node, nil
@@ -193,6 +207,11 @@ func LoadPodsFromContainerRuntime(runtimeService runtime.RuntimeService, db *sto
 		if fornaxpod.Containers == nil {
 			fornaxpod.Containers = map[string]*fornaxtypes.FornaxContainer{}
 		}
+		if fornaxpod.Pod != nil {
+			// secret data is not saved, pod does not have secret versions it reported before restart
+			delete(fornaxpod.Pod.Annotations, fornaxv1.AnnotationFornaxCoreSecretVersion)
+			delete(fornaxpod.Pod.Annotations, fornaxv1.AnnotationFornaxCorePrevSecretVersion)
+		}
 
 		if fornaxpod.RuntimePod == nil {
 			world.terminatedPods = append(world.terminatedPods, fornaxpod)
@@ -255,6 +274,101 @@ func LoadPodsFromContainerRuntime(runtimeService runtime.RuntimeService, db *sto
 	return world, nil
 }
 
+// ApplyPodTerminationJournal put pods which have a termination entry back into terminating state,
+// node agent could crash after some pod containers are killed but before terminating state is saved,
+// pod actor recovered in terminating state complete termination in house keeping instead of treating pod as running
+func ApplyPodTerminationJournal(world ContainerWorldSummary, journal *store.PodTerminationStore) error {
+	if journal == nil {
+		return nil
+	}
+	objs, err := journal.ListObject()
+	if err != nil {
+		return err
+	}
+
+	pods := map[string]*fornaxtypes.FornaxPod{}
+	for _, v := range world.runningPods {
+		pods[v.Identifier] = v
+	}
+	for _, v := range world.terminatedPods {
+		pods[v.Identifier] = v
+	}
+	for _, obj := range objs {
+		termination := obj.(*fornaxtypes.FornaxPodTermination)
+		fornaxpod, found := pods[termination.PodIdentifier]
+		if !found {
+			// pod was removed from store but journal entry was not, nothing to terminate
+			klog.InfoS("Remove termination journal of a pod not in store", "pod", termination.PodIdentifier)
+			if err := journal.DelObject(termination.PodIdentifier); err != nil {
+				return err
+			}
+			continue
+		}
+		if fornaxpod.FornaxPodState == fornaxtypes.PodStateTerminated || fornaxpod.FornaxPodState == fornaxtypes.PodStateCleanup {
+			continue
+		}
+		klog.InfoS("Recover pod termination from journal", "pod", fornaxtypes.UniquePodName(fornaxpod), "state", fornaxpod.FornaxPodState, "requested at", termination.RequestedAt)
+		fornaxpod.FornaxPodState = fornaxtypes.PodStateTerminating
+		if fornaxpod.Pod.DeletionTimestamp == nil {
+			fornaxpod.Pod.DeletionTimestamp = &metav1.Time{Time: termination.RequestedAt}
+		}
+		if fornaxpod.Pod.DeletionGracePeriodSeconds == nil {
+			fornaxpod.Pod.DeletionGracePeriodSeconds = &termination.GracePeriodSeconds
+		}
+	}
+	return nil
+}
+
+// ApplySessionCheckpoints put checkpointed sessions back into their pods if checkpoint is newer than session copy saved with pod,
+// pod and session are saved in different go routines, so pod store could have a older session state when node agent crash,
+// recovered session actors ping session service to revalidate session state instead of fornaxcore closing them
+func ApplySessionCheckpoints(world ContainerWorldSummary, checkpoints *store.SessionStore) error {
+	if checkpoints == nil {
+		return nil
+	}
+	objs, err := checkpoints.ListObject()
+	if err != nil {
+		return err
+	}
+
+	pods := map[string]*fornaxtypes.FornaxPod{}
+	for _, v := range world.runningPods {
+		pods[v.Identifier] = v
+	}
+	for _, v := range world.terminatedPods {
+		pods[v.Identifier] = v
+	}
+	for _, obj := range objs {
+		checkpoint := obj.(*fornaxtypes.FornaxSession)
+		fornaxpod, found := pods[checkpoint.PodIdentifier]
+		if !found || checkpoint.Session == nil {
+			// pod was removed from store but session checkpoint was not
+			klog.InfoS("Remove session checkpoint of a pod not in store", "session", checkpoint.Identifier, "pod", checkpoint.PodIdentifier)
+			if err := checkpoints.DelObject(checkpoint.Identifier); err != nil {
+				return err
+			}
+			continue
+		}
+		saved, found := fornaxpod.Sessions[checkpoint.Identifier]
+		if found && saved.Session != nil && !newerRevision(checkpoint.Session.ResourceVersion, saved.Session.ResourceVersion) {
+			continue
+		}
+		klog.InfoS("Recover session from checkpoint", "pod", fornaxtypes.UniquePodName(fornaxpod), "session", checkpoint.Identifier, "status", checkpoint.Session.Status.SessionStatus)
+		fornaxpod.Sessions[checkpoint.Identifier] = checkpoint
+	}
+	return nil
+}
+
+// newerRevision tell if revision a is newer than b, session resource version is node revision when session state changed
+func newerRevision(a, b string) bool {
+	ra, err1 := strconv.ParseInt(a, 10, 64)
+	rb, err2 := strconv.ParseInt(b, 10, 64)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	return ra > rb
+}
+
 func NodeSpecPodCidrChanged(myNode *v1.Node, apiNode *v1.Node) bool {
 	errs := ValidateNodeSpec(apiNode)
 	if len(errs) > 0 {
@@ -28,6 +28,7 @@ import (
 	fornaxgrpc "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc"
 	"centaurusinfra.io/fornax-serverless/pkg/message"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/fornaxcore"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/hostconfig"
 	internal "centaurusinfra.io/fornax-serverless/pkg/nodeagent/message"
 	podutil "centaurusinfra.io/fornax-serverless/pkg/nodeagent/pod"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/session"
@@ -35,6 +36,9 @@ import (
 	"centaurusinfra.io/fornax-serverless/pkg/util"
 
 	"github.com/pkg/errors"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -52,6 +56,11 @@ const (
 	NodeStateReady        NodeState = "Ready"
 )
 
+const (
+	// used when fornaxcore enable node lease but does not tell renew interval
+	DefaultNodeLeaseRenewIntervalSeconds = 10
+)
+
 type FornaxNodeActor struct {
 	nodeMutex       sync.RWMutex
 	stopCh          chan struct{}
@@ -61,6 +70,17 @@ type FornaxNodeActor struct {
 	fornoxCoreRef   message.ActorRef
 	podActors       *PodActorPool
 	nodePortManager *nodePortManager
+	admission       *PodAdmissionPolicy
+	// lease renew interval told by fornaxcore, zero if fornaxcore does not use node lease
+	leaseRenewIntervalSeconds int32
+	hostConfig                *hostconfig.Reconciler
+	// node config profile assigned by fornaxcore and last compliance reported
+	configProfile       *fornaxgrpc.NodeConfigProfile
+	configProfileSpec   *fornaxv1.NodeConfigProfileSpec
+	configProfileStatus *fornaxgrpc.NodeConfigProfileStatus
+	// node memory pressure state of load shedding, and last time a pressure signal was over threshold
+	underMemoryPressure bool
+	lastMemoryPressure  time.Time
 }
 
 func (n *FornaxNodeActor) Stop() error {
@@ -82,6 +102,12 @@ func (n *FornaxNodeActor) Start() error {
 		if err == nil {
 			klog.InfoS("Load pod state from runtime service and nodeagent store")
 			runtimeSummary, err := LoadPodsFromContainerRuntime(n.node.Dependencies.RuntimeService, n.node.Dependencies.PodStore)
+			if err == nil {
+				err = ApplyPodTerminationJournal(runtimeSummary, n.node.Dependencies.TerminationStore)
+			}
+			if err == nil {
+				err = ApplySessionCheckpoints(runtimeSummary, n.node.Dependencies.SessionStore)
+			}
 			if err != nil {
 				klog.ErrorS(err, "Failed to load container from runtime, wait for next 5 second")
 				time.Sleep(5 * time.Second)
@@ -112,6 +138,7 @@ func (n *FornaxNodeActor) Start() error {
 					NodeRegistry: &fornaxgrpc.NodeRegistry{
 						NodeRevision: n.node.Revision,
 						Node:         n.node.V1Node,
+						NodeTime:     timestamppb.Now(),
 					},
 				},
 			},
@@ -133,6 +160,7 @@ func (n *FornaxNodeActor) recreatePodStateFromRuntimeSummary(runtimeSummary Cont
 	for _, fpod := range runtimeSummary.runningPods {
 		klog.InfoS("Recover pod actor for a running pod", "pod", types.UniquePodName(fpod), "state", fpod.FornaxPodState)
 		n.nodePortManager.initNodePortRangeSlot(fpod.Pod)
+		n.node.Dependencies.GPUManager.Restore(fpod.Pod)
 		n.startPodActor(fpod)
 	}
 }
@@ -144,6 +172,19 @@ func (n *FornaxNodeActor) startStateReport() {
 	}, 1*time.Minute, n.stopCh)
 }
 
+// startLeaseRenew start go routine to renew node lease forever, renew interval can be changed by fornaxcore
+func (n *FornaxNodeActor) startLeaseRenew() {
+	var lastRenew time.Time
+	go wait.Until(func() {
+		interval := atomic.LoadInt32(&n.leaseRenewIntervalSeconds)
+		if time.Since(lastRenew) < time.Duration(interval)*time.Second {
+			return
+		}
+		lastRenew = time.Now()
+		n.notify(n.fornoxCoreRef, BuildFornaxGrpcNodeLeaseRenew(atomic.LoadInt64(&n.node.Revision), interval))
+	}, 1*time.Second, n.stopCh)
+}
+
 // https://www.sqlite.org/faq.html#q19, sqlite transaction is slow, so, call PutNode in go routine.
 // PutNode use provided revision to avoid newer revision is overwriten by older revision when there is race condition
 func (n *FornaxNodeActor) incrementNodeRevision() int64 {
@@ -169,6 +210,10 @@ func (n *FornaxNodeActor) nodeHandler(msg message.ActorMessage) (interface{}, er
 	switch msg.Body.(type) {
 	case *fornaxgrpc.FornaxCoreMessage:
 		return n.processFornaxCoreMessage(msg.Body.(*fornaxgrpc.FornaxCoreMessage))
+	case *fornaxgrpc.SessionWatchEvent:
+		if err := n.onSessionWatchEvent(msg.Body.(*fornaxgrpc.SessionWatchEvent)); err != nil {
+			klog.ErrorS(err, "Failed to handle session watch event", "session", msg.Body.(*fornaxgrpc.SessionWatchEvent).GetSessionIdentifier())
+		}
 	case internal.PodStatusChange:
 		fppod := msg.Body.(internal.PodStatusChange).Pod
 		if fppod.FornaxPodState == types.PodStateCleanup {
@@ -202,13 +247,23 @@ func (n *FornaxNodeActor) nodeHandler(msg message.ActorMessage) (interface{}, er
 		revision := n.incrementNodeRevision()
 		fpsession.Session.ResourceVersion = fmt.Sprint(revision)
 		n.notify(n.fornoxCoreRef, session.BuildFornaxcoreGrpcSessionState(revision, fpsession))
+		// session is checkpointed alone, pod is saved in go routine and could be saved with a older session copy, restart recovery use newer one
+		if n.node.Dependencies.SessionStore != nil {
+			go n.node.Dependencies.SessionStore.PutSession(fpsession, revision)
+		}
 		// fppod is nil if session can not find pod
 		if fppod != nil {
 			go n.node.Dependencies.PodStore.PutPod(fppod, revision)
 		}
+	case internal.SessionUsageReport:
+		// usage is not saved on node, usage reported when fornaxcore is disconnected is lost
+		n.notify(n.fornoxCoreRef, session.BuildFornaxcoreGrpcSessionUsage(msg.Body.(internal.SessionUsageReport)))
 	case internal.NodeUpdate:
+		n.reconcileHostConfig(false)
 		SetNodeStatus(n.node)
 		n.notify(n.fornoxCoreRef, BuildFornaxGrpcNodeState(n.node, n.node.Revision))
+	case internal.NodeMemoryPressureCheck:
+		n.checkMemoryPressure()
 	default:
 		klog.InfoS("Received unknown message", "from", msg.Sender, "msg", msg.Body)
 	}
@@ -224,16 +279,28 @@ func (n *FornaxNodeActor) processFornaxCoreMessage(msg *fornaxgrpc.FornaxCoreMes
 		err = n.onNodeConfigurationCommand(msg.GetNodeConfiguration())
 	case fornaxgrpc.MessageType_NODE_FULL_SYNC:
 		err = n.onNodeFullSyncCommand(msg.GetNodeFullSync())
+	case fornaxgrpc.MessageType_NODE_LEASE_CONFIGURATION:
+		err = n.onNodeLeaseConfigurationCommand(msg.GetNodeLeaseConfiguration())
+	case fornaxgrpc.MessageType_NODE_CONFIG_PROFILE:
+		err = n.onNodeConfigProfileCommand(msg.GetNodeConfigProfile())
 	case fornaxgrpc.MessageType_POD_CREATE:
 		err = n.onPodCreateCommand(msg.GetPodCreate())
 	case fornaxgrpc.MessageType_POD_TERMINATE:
 		err = n.onPodTerminateCommand(msg.GetPodTerminate())
 	case fornaxgrpc.MessageType_POD_HIBERNATE:
 		err = n.onPodHibernateCommand(msg.GetPodHibernate())
+	case fornaxgrpc.MessageType_POD_CONFIG_UPDATE:
+		err = n.onPodConfigUpdateCommand(msg.GetPodConfigUpdate())
+	case fornaxgrpc.MessageType_POD_SECRET_UPDATE:
+		err = n.onPodSecretUpdateCommand(msg.GetPodSecretUpdate())
 	case fornaxgrpc.MessageType_SESSION_OPEN:
 		err = n.onSessionOpenCommand(msg.GetSessionOpen())
 	case fornaxgrpc.MessageType_SESSION_CLOSE:
-		err = n.onSessionCloseCommand(msg.GetSessionClose())
+		err = n.onSessionCloseCommand(msg.GetSessionClose(), msg.GetRequestIdentifier())
+	case fornaxgrpc.MessageType_SESSION_DRAIN:
+		err = n.onSessionDrainCommand(msg.GetSessionDrain(), msg.GetRequestIdentifier())
+	case fornaxgrpc.MessageType_SESSION_MIGRATE:
+		err = n.onSessionMigrateCommand(msg.GetSessionMigrate(), msg.GetRequestIdentifier())
 	case fornaxgrpc.MessageType_SESSION_STATE, fornaxgrpc.MessageType_POD_STATE, fornaxgrpc.MessageType_NODE_STATE:
 		// messages are sent to fornaxcore, should just forward
 		n.notify(n.fornoxCoreRef, msg)
@@ -251,6 +318,58 @@ func (n *FornaxNodeActor) onNodeFullSyncCommand(msg *fornaxgrpc.NodeFullSync) er
 	return nil
 }
 
+// change lease renew interval, lease renewal is not started if fornaxcore did not enable node lease in node configuration
+func (n *FornaxNodeActor) onNodeLeaseConfigurationCommand(msg *fornaxgrpc.NodeLeaseConfiguration) error {
+	if msg.GetRenewIntervalSeconds() <= 0 {
+		return fmt.Errorf("invalid node lease renew interval %d", msg.GetRenewIntervalSeconds())
+	}
+	klog.InfoS("Change node lease renew interval", "interval-seconds", msg.GetRenewIntervalSeconds(), "lease-duration-seconds", msg.GetLeaseDurationSeconds())
+	atomic.StoreInt32(&n.leaseRenewIntervalSeconds, msg.GetRenewIntervalSeconds())
+	return nil
+}
+
+// reconcile host with node config profile assigned by fornaxcore, a empty profile name clear profile,
+// host configuration set by a cleared profile is kept as original configuration is unknown
+func (n *FornaxNodeActor) onNodeConfigProfileCommand(msg *fornaxgrpc.NodeConfigProfile) error {
+	if len(msg.GetName()) == 0 {
+		klog.InfoS("Node config profile is cleared")
+		n.configProfile, n.configProfileSpec, n.configProfileStatus = nil, nil, nil
+		n.node.EvictionReserved = nil
+		return nil
+	}
+	spec := &fornaxv1.NodeConfigProfileSpec{}
+	if err := json.Unmarshal(msg.GetSpec(), spec); err != nil {
+		return fmt.Errorf("invalid node config profile %s: %v", msg.GetName(), err)
+	}
+	klog.InfoS("Received node config profile", "profile", msg.GetName(), "generation", msg.GetGeneration())
+	n.configProfile, n.configProfileSpec = msg, spec
+	n.reconcileHostConfig(true)
+	return nil
+}
+
+// reconcileHostConfig apply node config profile on host, compliance is reported when it change, or always if forced
+func (n *FornaxNodeActor) reconcileHostConfig(force bool) {
+	if n.configProfile == nil {
+		return
+	}
+	drifts := n.hostConfig.Reconcile(n.configProfileSpec)
+	n.node.EvictionReserved = hostconfig.EvictionReservation(n.configProfileSpec)
+	status := &fornaxgrpc.NodeConfigProfileStatus{
+		Name:       n.configProfile.GetName(),
+		Generation: n.configProfile.GetGeneration(),
+		Compliant:  len(drifts) == 0,
+		Drifts:     drifts,
+	}
+	if len(drifts) > 0 {
+		klog.InfoS("Host does not comply with node config profile", "profile", status.Name, "generation", status.Generation, "drifts", drifts)
+	}
+	if !force && proto.Equal(status, n.configProfileStatus) {
+		return
+	}
+	n.configProfileStatus = status
+	n.notify(n.fornoxCoreRef, BuildFornaxGrpcNodeConfigProfileStatus(status))
+}
+
 // initialize node with node spec provided by fornaxcore, especially pod cidr
 func (n *FornaxNodeActor) onNodeConfigurationCommand(msg *fornaxgrpc.NodeConfiguration) error {
 	if n.state != NodeStateRegistering {
@@ -280,6 +399,14 @@ func (n *FornaxNodeActor) onNodeConfigurationCommand(msg *fornaxgrpc.NodeConfigu
 		return err
 	}
 
+	if lease := msg.GetLease(); lease != nil {
+		interval := lease.GetRenewIntervalSeconds()
+		if interval <= 0 {
+			interval = DefaultNodeLeaseRenewIntervalSeconds
+		}
+		atomic.StoreInt32(&n.leaseRenewIntervalSeconds, interval)
+	}
+
 	n.state = NodeStateRegistered
 	// start go routine to check node status until it is ready
 	go func() {
@@ -300,6 +427,10 @@ func (n *FornaxNodeActor) onNodeConfigurationCommand(msg *fornaxgrpc.NodeConfigu
 				n.notify(n.fornoxCoreRef, BuildFornaxGrpcNodeReady(n.node, revision))
 				n.state = NodeStateReady
 				n.startStateReport()
+				n.startLoadShedding()
+				if atomic.LoadInt32(&n.leaseRenewIntervalSeconds) > 0 {
+					n.startLeaseRenew()
+				}
 			} else {
 				time.Sleep(5 * time.Second)
 			}
@@ -371,11 +502,22 @@ func (n *FornaxNodeActor) buildAFornaxPod(state types.PodState, v1pod *v1.Pod, c
 	// to avoid port conflict on host of multiple pods, node allocate a unique host port number for each container port
 	// and overwrite pod spec's container port mapping, modified pod spec is returned back to FornaxCore,
 	// FornaxCore use modified port mapping to let its client to access pod using node allocated host port
-	err := n.nodePortManager.AllocatePodPortMapping(n.node.NodeConfig.NodeIP, fornaxPod.Pod)
+	// a dual stack node also map container port on its ipv6 address
+	nodeIps := []string{n.node.NodeConfig.NodeIP}
+	if len(n.node.NodeConfig.NodeIPv6) > 0 {
+		nodeIps = append(nodeIps, n.node.NodeConfig.NodeIPv6)
+	}
+	if err := checkPortProtocols(fornaxPod.Pod); err != nil {
+		return nil, err
+	}
+	err := n.nodePortManager.AllocatePodPortMapping(nodeIps, fornaxPod.Pod)
 	if err != nil {
 		return nil, err
 	}
 
+	// public endpoints of udp ports are discovered before ports are mapped to pod containers
+	discoverPodPublicEndpoints(fornaxPod.Pod)
+
 	// for _, v := range fornaxPod.Pod.Spec.Containers {
 	//  klog.Info("Pod Containers port mapping", v.Ports)
 	// }
@@ -422,7 +564,21 @@ func (n *FornaxNodeActor) cleanupPodStoreAndActor(fppod *types.FornaxPod) error
 	}
 	n.node.Pods.Del(fppod.Identifier)
 	n.nodePortManager.DeallocatePodPortMapping(fppod.Pod)
-	return n.node.Dependencies.PodStore.DelObject(fppod.Identifier)
+	if err := n.node.Dependencies.PodStore.DelObject(fppod.Identifier); err != nil {
+		return err
+	}
+	if n.node.Dependencies.SessionStore != nil {
+		for _, v := range fppod.Sessions {
+			if err := n.node.Dependencies.SessionStore.DelObject(v.Identifier); err != nil {
+				return err
+			}
+		}
+	}
+	// pod is gone from store, remove its termination journal entry, a entry left by crash is removed in next restart recovery
+	if n.node.Dependencies.TerminationStore != nil {
+		return n.node.Dependencies.TerminationStore.DelObject(fppod.Identifier)
+	}
+	return nil
 }
 
 // find pod actor and send a message to it, if pod actor does not exist, create one
@@ -442,6 +598,29 @@ func (n *FornaxNodeActor) onPodCreateCommand(msg *fornaxgrpc.PodCreate) error {
 		})
 		return fmt.Errorf("Node is not in ready state to create a new pod")
 	}
+	err := admitPodSysctls(msg.Pod)
+	if err == nil && n.admission != nil {
+		err = n.admission.Admit(msg.Pod)
+	}
+	if err != nil {
+		klog.ErrorS(err, "Pod rejected by node admission policy", "pod", util.Name(msg.Pod))
+		pod := msg.Pod.DeepCopy()
+		pod.Status.Reason = PodReasonAdmissionRejected
+		pod.Status.Message = err.Error()
+		n.notify(n.fornoxCoreRef, internal.PodStatusChange{
+			Pod: &types.FornaxPod{
+				Identifier:              util.Name(msg.Pod),
+				FornaxPodState:          types.PodStateFailed,
+				Daemon:                  false,
+				Pod:                     pod,
+				RuntimePod:              nil,
+				Containers:              map[string]*types.FornaxContainer{},
+				Sessions:                map[string]*types.FornaxSession{},
+				LastStateTransitionTime: time.Now(),
+			},
+		})
+		return err
+	}
 	v := n.node.Pods.Get(msg.GetPodIdentifier())
 	if v == nil {
 		fpod, actor, err := n.createPodAndActor(types.PodStateCreating, msg.GetPod().DeepCopy(), msg.GetConfigMap().DeepCopy(), false)
@@ -508,6 +687,50 @@ func (n *FornaxNodeActor) onPodHibernateCommand(msg *fornaxgrpc.PodHibernate) er
 	return nil
 }
 
+// forward new config map to pod actor, pod actor notify open sessions of pod
+func (n *FornaxNodeActor) onPodConfigUpdateCommand(msg *fornaxgrpc.PodConfigUpdate) error {
+	if n.state != NodeStateReady {
+		return fmt.Errorf("Node is not in ready state to update pod config")
+	}
+	configMap := msg.GetConfigMap()
+	if configMap == nil {
+		return fmt.Errorf("Pod: %s config update does not have config map", msg.GetPodIdentifier())
+	}
+	if errs := podutil.ValidateConfigMapSpec(configMap); len(errs) > 0 {
+		return errors.New("ConfigMap spec is invalid")
+	}
+	podActor := n.podActors.Get(msg.GetPodIdentifier())
+	if podActor == nil {
+		return fmt.Errorf("Pod: %s does not exist, Fornax core is not in sync", msg.GetPodIdentifier())
+	} else {
+		n.notify(podActor.Reference(), internal.PodConfigUpdate{ConfigMap: configMap.DeepCopy()})
+	}
+	return nil
+}
+
+// forward new secret versions to pod actor, pod actor notify open sessions of pod
+func (n *FornaxNodeActor) onPodSecretUpdateCommand(msg *fornaxgrpc.PodSecretUpdate) error {
+	if n.state != NodeStateReady {
+		return fmt.Errorf("Node is not in ready state to update pod secret")
+	}
+	if msg.GetSecret() == nil {
+		return fmt.Errorf("Pod: %s secret update does not have secret", msg.GetPodIdentifier())
+	}
+	podActor := n.podActors.Get(msg.GetPodIdentifier())
+	if podActor == nil {
+		return fmt.Errorf("Pod: %s does not exist, Fornax core is not in sync", msg.GetPodIdentifier())
+	} else {
+		update := internal.PodSecretUpdate{
+			Secret: &types.FornaxSecret{Version: msg.GetSecret().GetVersion(), Data: msg.GetSecret().GetData()},
+		}
+		if msg.GetPreviousSecret() != nil {
+			update.PreviousSecret = &types.FornaxSecret{Version: msg.GetPreviousSecret().GetVersion(), Data: msg.GetPreviousSecret().GetData()}
+		}
+		n.notify(podActor.Reference(), update)
+	}
+	return nil
+}
+
 // build a session actor to start session and monitor session state
 func (n *FornaxNodeActor) onSessionOpenCommand(msg *fornaxgrpc.SessionOpen) error {
 	s := &fornaxv1.ApplicationSession{}
@@ -533,12 +756,72 @@ func (n *FornaxNodeActor) onSessionOpenCommand(msg *fornaxgrpc.SessionOpen) erro
 }
 
 // find session actor to let it terminate a session, if pod actor does not exist, return failure
-func (n *FornaxNodeActor) onSessionCloseCommand(msg *fornaxgrpc.SessionClose) error {
+func (n *FornaxNodeActor) onSessionCloseCommand(msg *fornaxgrpc.SessionClose, requestId string) error {
 	podActor := n.podActors.Get(msg.GetPodIdentifier())
 	if podActor == nil {
 		return fmt.Errorf("Pod: %s does not exist, Fornax core is not in sync, can not close session", msg.GetPodIdentifier())
 	} else {
-		n.notify(podActor.Reference(), internal.SessionClose{SessionId: msg.GetSessionIdentifier()})
+		n.notify(podActor.Reference(), internal.SessionClose{SessionId: msg.GetSessionIdentifier(), RequestId: requestId, GracePeriod: time.Duration(msg.GetGracePeriodSeconds()) * time.Second})
+	}
+	return nil
+}
+
+// close session on pod when session watch tell session is closing or deleted in fornaxcore, or it left this node,
+// session is still opened by a SessionOpen message
+func (n *FornaxNodeActor) onSessionWatchEvent(event *fornaxgrpc.SessionWatchEvent) error {
+	session := &fornaxv1.ApplicationSession{}
+	if len(event.GetSessionData()) > 0 {
+		if err := json.Unmarshal(event.GetSessionData(), session); err != nil {
+			return err
+		}
+	}
+	switch {
+	case event.GetType() == fornaxgrpc.SessionWatchEvent_Deleted && !util.SessionInTerminalState(session):
+	case event.GetType() != fornaxgrpc.SessionWatchEvent_Deleted && util.SessionIsOpen(session) && (session.DeletionTimestamp != nil || util.SessionIsClosing(session)):
+	default:
+		return nil
+	}
+	if n.podActors.Get(event.GetPodIdentifier()) == nil {
+		// sessions are closed with pod
+		return nil
+	}
+	msg := &fornaxgrpc.SessionClose{
+		SessionIdentifier: event.GetSessionIdentifier(),
+		PodIdentifier:     event.GetPodIdentifier(),
+	}
+	if session.Spec.CloseGracePeriodSeconds != nil {
+		msg.GracePeriodSeconds = uint32(*session.Spec.CloseGracePeriodSeconds)
+	}
+	return n.onSessionCloseCommand(msg, util.RequestId(session))
+}
+
+// find pod actor to let it relay session drain notification to session
+func (n *FornaxNodeActor) onSessionDrainCommand(msg *fornaxgrpc.SessionDrain, requestId string) error {
+	podActor := n.podActors.Get(msg.GetPodIdentifier())
+	if podActor == nil {
+		return fmt.Errorf("Pod: %s does not exist, Fornax core is not in sync, can not drain session", msg.GetPodIdentifier())
+	} else {
+		n.notify(podActor.Reference(), internal.SessionDrain{
+			SessionId: msg.GetSessionIdentifier(),
+			RequestId: requestId,
+			Reason:    msg.GetReason(),
+			Deadline:  msg.GetDeadline().AsTime(),
+		})
+	}
+	return nil
+}
+
+// find pod actor to let it close session for migration, session is reopened on target pod by fornaxcore
+func (n *FornaxNodeActor) onSessionMigrateCommand(msg *fornaxgrpc.SessionMigrate, requestId string) error {
+	podActor := n.podActors.Get(msg.GetPodIdentifier())
+	if podActor == nil {
+		return fmt.Errorf("Pod: %s does not exist, Fornax core is not in sync, can not migrate session", msg.GetPodIdentifier())
+	} else {
+		n.notify(podActor.Reference(), internal.SessionMigrate{
+			SessionId: msg.GetSessionIdentifier(),
+			RequestId: requestId,
+			TargetPod: msg.GetTargetPodIdentifier(),
+		})
 	}
 	return nil
 }
@@ -548,6 +831,23 @@ func (n *FornaxNodeActor) notify(receiver message.ActorRef, msg interface{}) {
 }
 
 func NewNodeActor(node *FornaxNode) (*FornaxNodeActor, error) {
+	var creds credentials.TransportCredentials
+	if node.Dependencies.NodeCredentials != nil {
+		creds = node.Dependencies.NodeCredentials.TransportCredentials()
+	}
+	return newNodeActor(node, fornaxcore.NewFornaxCoreActor(node.NodeConfig.NodeIP, util.Name(node.V1Node), node.NodeConfig.FornaxCoreUrls, creds))
+}
+
+// NewStandaloneNodeActor create a node actor talking with a local fornaxcore instead of fornaxcore servers
+func NewStandaloneNodeActor(node *FornaxNode, core fornaxcore.FornaxCoreClient) (*FornaxNodeActor, error) {
+	return newNodeActor(node, fornaxcore.NewFornaxCoreActorWithClients(node.NodeConfig.NodeIP, util.Name(node.V1Node), map[string]fornaxcore.FornaxCoreClient{"standalone": core}))
+}
+
+func newNodeActor(node *FornaxNode, fornaxCoreActor *fornaxcore.FornaxCoreActor) (*FornaxNodeActor, error) {
+	admission, err := LoadPodAdmissionPolicy(node.NodeConfig.PodAdmissionPolicyFile)
+	if err != nil {
+		return nil, err
+	}
 	actor := &FornaxNodeActor{
 		nodeMutex:       sync.RWMutex{},
 		stopCh:          make(chan struct{}),
@@ -557,13 +857,14 @@ func NewNodeActor(node *FornaxNode) (*FornaxNodeActor, error) {
 		fornoxCoreRef:   nil,
 		podActors:       NewPodActorPool(),
 		nodePortManager: NewNodePortManager(&node.NodeConfig),
+		admission:       admission,
+		hostConfig:      hostconfig.NewReconciler(),
 	}
 	actor.innerActor = message.NewLocalChannelActor(node.V1Node.GetName(), actor.nodeHandler)
 
 	klog.Info("Starting Fornax core actor")
-	fornaxCoreActor := fornaxcore.NewFornaxCoreActor(node.NodeConfig.NodeIP, util.Name(node.V1Node), node.NodeConfig.FornaxCoreUrls)
 	actor.fornoxCoreRef = fornaxCoreActor.Reference()
-	err := fornaxCoreActor.Start(actor.innerActor.Reference())
+	err = fornaxCoreActor.Start(actor.innerActor.Reference())
 	if err != nil {
 		klog.ErrorS(err, "Can not start Fornax core actor")
 		return nil, err
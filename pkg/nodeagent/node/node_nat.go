@@ -0,0 +1,88 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"encoding/json"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/network"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// total time spent on stun discovery of a pod, pod creation is delayed by discovery
+	maxPodSTUNDiscoveryDuration = 2 * time.Second
+)
+
+// discoverPodPublicEndpoints send stun binding request from each udp host port of pod before port is mapped to pod,
+// discovered public endpoints are saved in pod annotation and reported to fornaxcore with pod,
+// ports which can not be discovered are skipped, clients of them use relay, ipv6 ports are not discovered as they are not behind nat
+func discoverPodPublicEndpoints(pod *v1.Pod) {
+	servers := []string{}
+	for _, v := range strings.Split(pod.GetAnnotations()[fornaxv1.AnnotationFornaxCoreSTUNServers], ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			servers = append(servers, v)
+		}
+	}
+	if len(servers) == 0 {
+		return
+	}
+
+	deadline := time.Now().Add(maxPodSTUNDiscoveryDuration)
+	endpoints := []fornaxv1.AccessEndPoint{}
+	for _, cont := range pod.Spec.Containers {
+		for _, port := range cont.Ports {
+			if port.Protocol != v1.ProtocolUDP || port.HostPort == 0 || util.IPFamilyOf(port.HostIP) != v1.IPv4Protocol {
+				continue
+			}
+			localAddress := net.JoinHostPort(port.HostIP, strconv.Itoa(int(port.HostPort)))
+			for _, server := range servers {
+				if time.Now().After(deadline) {
+					break
+				}
+				addr, err := network.DiscoverPublicAddress(localAddress, server, network.DefaultSTUNTimeout)
+				if err != nil {
+					klog.ErrorS(err, "Failed to discover public endpoint of pod port", "pod", util.Name(pod), "local", localAddress, "stun", server)
+					continue
+				}
+				endpoints = append(endpoints, fornaxv1.AccessEndPoint{
+					Protocol:  v1.ProtocolUDP,
+					IPAddress: addr.IP.String(),
+					Port:      int32(addr.Port),
+					IPFamily:  util.IPFamilyOf(addr.IP.String()),
+				})
+				break
+			}
+		}
+	}
+	if len(endpoints) == 0 {
+		return
+	}
+	data, err := json.Marshal(endpoints)
+	if err != nil {
+		return
+	}
+	klog.InfoS("Discovered public endpoints of pod", "pod", util.Name(pod), "endpoints", endpoints)
+	pod.Annotations[fornaxv1.AnnotationFornaxCorePublicEndpoints] = string(data)
+}
@@ -18,7 +18,9 @@ package node
 
 import (
 	"errors"
+	"fmt"
 	"math"
+	"os"
 	"sync"
 
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/config"
@@ -31,6 +33,7 @@ const (
 
 var (
 	InSufficientHostPortError = errors.New("There are no free node port range slot")
+	SCTPNotSupportedError     = errors.New("Node kernel does not have sctp module loaded")
 )
 
 type RangeSlot struct {
@@ -150,12 +153,19 @@ type nodePortManager struct {
 }
 
 // AllocatePodPortMapping find a not used host port range slot to a pod and assign host port number from this range
-// to each container port to make sure host port number is unique on a node to avoid conflict between pods
-func (npm *nodePortManager) AllocatePodPortMapping(nodeIp string, pod *v1.Pod) error {
+// to each container port to make sure host port number is unique on a node to avoid conflict between pods,
+// port is mapped on first node ip, a dual stack node pass ipv6 address as second ip, a ipv6 mapping of same host port is added for each port
+// a container port number exposed with multiple protocols, e.g. tcp and udp, share a host port, each protocol is a mapping
+func (npm *nodePortManager) AllocatePodPortMapping(nodeIps []string, pod *v1.Pod) error {
 	containerPorts := []*v1.ContainerPort{}
+	portNums := map[int32]bool{}
 	for _, cont := range pod.Spec.Containers {
 		for _, port := range cont.Ports {
-			containerPorts = append(containerPorts, &port)
+			if portNums[port.ContainerPort] {
+				continue
+			}
+			portNums[port.ContainerPort] = true
+			containerPorts = append(containerPorts, port.DeepCopy())
 		}
 	}
 
@@ -172,10 +182,15 @@ func (npm *nodePortManager) AllocatePodPortMapping(nodeIp string, pod *v1.Pod) e
 			for _, v := range containerPorts {
 				if v.ContainerPort == port.ContainerPort {
 					port.HostPort = v.HostPort
-					port.HostIP = nodeIp
+					port.HostIP = nodeIps[0]
 				}
 			}
 			ports = append(ports, *port)
+			for _, ip := range nodeIps[1:] {
+				dualPort := port.DeepCopy()
+				dualPort.HostIP = ip
+				ports = append(ports, *dualPort)
+			}
 		}
 		updatedContSpec.Ports = ports
 		conts = append(conts, *updatedContSpec)
@@ -185,14 +200,38 @@ func (npm *nodePortManager) AllocatePodPortMapping(nodeIp string, pod *v1.Pod) e
 	return nil
 }
 
+// sctpSupported check if sctp kernel module is loaded, runtime can not map a sctp port without it
+func sctpSupported() bool {
+	for _, v := range []string{"/proc/net/sctp", "/sys/module/sctp"} {
+		if _, err := os.Stat(v); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPortProtocols reject a pod exposing a port of protocol node can not map, empty protocol is tcp
+func checkPortProtocols(pod *v1.Pod) error {
+	for _, v := range GetContainerPorts(pod) {
+		switch v.Protocol {
+		case "", v1.ProtocolTCP, v1.ProtocolUDP:
+		case v1.ProtocolSCTP:
+			if !sctpSupported() {
+				return SCTPNotSupportedError
+			}
+		default:
+			return fmt.Errorf("container port %d has unsupported protocol %s", v.ContainerPort, v.Protocol)
+		}
+	}
+	return nil
+}
+
 // DeallocatePodPortMapping check host port number of container ports of pod, and find allocated slot,
 // and reset slot for allocation for other pods
 func (npm *nodePortManager) DeallocatePodPortMapping(pod *v1.Pod) {
 	containerPorts := []*v1.ContainerPort{}
-	for _, cont := range pod.Spec.Containers {
-		for _, port := range cont.Ports {
-			containerPorts = append(containerPorts, &port)
-		}
+	for _, v := range uniqueHostPorts(pod) {
+		containerPorts = append(containerPorts, v.DeepCopy())
 	}
 
 	npm.portRange.deallocateHostPort(containerPorts)
@@ -204,11 +243,25 @@ func (npm *nodePortManager) DeallocatePodPortMapping(pod *v1.Pod) {
 // this method is called before allocate new pod on this node,
 // so node port range is initialized correctly to avoid double allocation
 func (npm *nodePortManager) initNodePortRangeSlot(pod *v1.Pod) {
-	containerPorts := GetContainerPorts(pod)
+	containerPorts := uniqueHostPorts(pod)
 	npm.portRange.initRangeWithContainerPorts(containerPorts)
 	return
 }
 
+// uniqueHostPorts return a container port of each allocated host port, a host port is shared by protocols and ip families of same container port
+func uniqueHostPorts(pod *v1.Pod) []v1.ContainerPort {
+	containerPorts := []v1.ContainerPort{}
+	hostPorts := map[int32]bool{}
+	for _, v := range GetContainerPorts(pod) {
+		if v.HostPort > 0 && hostPorts[v.HostPort] {
+			continue
+		}
+		hostPorts[v.HostPort] = true
+		containerPorts = append(containerPorts, v)
+	}
+	return containerPorts
+}
+
 func GetContainerPorts(pod *v1.Pod) []v1.ContainerPort {
 	containerPorts := []v1.ContainerPort{}
 	for _, cont := range pod.Spec.Containers {
@@ -21,6 +21,7 @@ import (
 	goruntime "runtime"
 	"time"
 
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/cadvisor"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/config"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/network"
@@ -80,6 +81,14 @@ func SetNodeStatus(node *FornaxNode) error {
 	}
 	conditions[condition.Type] = condition
 
+	UpdateNodeGPUStatus(node.Dependencies.GPUManager, node.V1Node)
+	ReserveEvictionThresholds(node.V1Node, node.EvictionReserved)
+	if node.MemoryPressure != nil {
+		condition = node.MemoryPressure.DeepCopy()
+		condition.LastHeartbeatTime = metav1.NewTime(time.Now())
+		conditions[condition.Type] = condition
+	}
+
 	currentTime := metav1.NewTime(time.Now())
 	if len(errs) == 0 {
 		conditions[v1.NodeReady] = &v1.NodeCondition{
@@ -212,6 +221,23 @@ func UpdateNodeVolumeStatus(volumeManager resource.VolumeManager, node *v1.Node)
 	return condition, nil
 }
 
+// UpdateNodeGPUStatus report whole gpus of node as capacity and allocatable, fornaxcore scheduler account them by pods' gpu requests
+func UpdateNodeGPUStatus(gpuManager *resource.GPUManager, node *v1.Node) {
+	if gpuManager == nil || !gpuManager.HasGPU() {
+		delete(node.Status.Capacity, fornaxv1.ResourceNvidiaGPU)
+		delete(node.Status.Allocatable, fornaxv1.ResourceNvidiaGPU)
+		return
+	}
+	if node.Status.Capacity == nil {
+		node.Status.Capacity = v1.ResourceList{}
+	}
+	if node.Status.Allocatable == nil {
+		node.Status.Allocatable = make(v1.ResourceList)
+	}
+	node.Status.Capacity[fornaxv1.ResourceNvidiaGPU] = gpuManager.Capacity()
+	node.Status.Allocatable[fornaxv1.ResourceNvidiaGPU] = gpuManager.Capacity()
+}
+
 func UpdateNodeCapacity(cc cadvisor.CAdvisorInfoProvider, nodeConfig config.NodeConfiguration, node *v1.Node) error {
 	info, err := cc.GetNodeCAdvisorInfo()
 	if err != nil {
@@ -274,6 +300,22 @@ func UpdateAllocatableResourceQuantity(resourceName v1.ResourceName, node *v1.No
 	}
 }
 
+// ReserveEvictionThresholds exclude resources kept available by eviction thresholds of node config profile from allocatable
+func ReserveEvictionThresholds(node *v1.Node, reserved v1.ResourceList) {
+	for name, quantity := range reserved {
+		allocatable, found := node.Status.Allocatable[name]
+		if !found {
+			continue
+		}
+		value := allocatable.DeepCopy()
+		value.Sub(quantity)
+		if value.Sign() < 0 {
+			value.Set(0)
+		}
+		node.Status.Allocatable[name] = value
+	}
+}
+
 func IsNodeStatusReady(myNode *FornaxNode) bool {
 	// check node capacity and allocatable are set
 	cpuReady := false
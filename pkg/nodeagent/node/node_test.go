@@ -0,0 +1,143 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/store"
+	fornaxtypes "centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
+	"centaurusinfra.io/fornax-serverless/pkg/store/storage/sqlite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestCheckpointStore(t *testing.T) *store.SessionStore {
+	checkpoints, err := store.NewSessionSqliteStore(&sqlite.SQLiteStoreOptions{
+		ConnUrl: filepath.Join(t.TempDir(), "session.db"),
+	})
+	if err != nil {
+		t.Fatalf("failed to create session store: %v", err)
+	}
+	return checkpoints
+}
+
+func newTestPod(id string) *fornaxtypes.FornaxPod {
+	return &fornaxtypes.FornaxPod{
+		Identifier: id,
+		Pod: &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: id, Namespace: "test"},
+		},
+		Sessions: map[string]*fornaxtypes.FornaxSession{},
+	}
+}
+
+func newTestSession(id, podId string, revision int64, status fornaxv1.SessionStatus) *fornaxtypes.FornaxSession {
+	return &fornaxtypes.FornaxSession{
+		Identifier:    id,
+		PodIdentifier: podId,
+		Session: &fornaxv1.ApplicationSession{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            id,
+				Namespace:       "test",
+				ResourceVersion: fmt.Sprint(revision),
+			},
+			Status: fornaxv1.ApplicationSessionStatus{SessionStatus: status},
+		},
+	}
+}
+
+func putTestCheckpoint(t *testing.T, checkpoints *store.SessionStore, session *fornaxtypes.FornaxSession, revision int64) {
+	if err := checkpoints.PutSession(session, revision); err != nil {
+		t.Fatalf("failed to checkpoint session %s: %v", session.Identifier, err)
+	}
+}
+
+func TestApplySessionCheckpoints(t *testing.T) {
+	checkpoints := newTestCheckpointStore(t)
+	running := newTestPod("pod1")
+	terminated := newTestPod("pod2")
+	world := ContainerWorldSummary{
+		runningPods:    []*fornaxtypes.FornaxPod{running},
+		terminatedPods: []*fornaxtypes.FornaxPod{terminated},
+	}
+
+	// pod store saved session when it was starting, checkpoint saved it open later
+	running.Sessions["stale"] = newTestSession("stale", "pod1", 1, fornaxv1.SessionStatusStarting)
+	putTestCheckpoint(t, checkpoints, newTestSession("stale", "pod1", 2, fornaxv1.SessionStatusAvailable), 2)
+	// pod store saved a session state after checkpoint
+	running.Sessions["fresh"] = newTestSession("fresh", "pod1", 5, fornaxv1.SessionStatusClosed)
+	putTestCheckpoint(t, checkpoints, newTestSession("fresh", "pod1", 4, fornaxv1.SessionStatusAvailable), 4)
+	// session only checkpointed, pod store did not save it before crash
+	putTestCheckpoint(t, checkpoints, newTestSession("unsaved", "pod2", 3, fornaxv1.SessionStatusAvailable), 3)
+	// pod was removed from pod store
+	putTestCheckpoint(t, checkpoints, newTestSession("orphan", "pod3", 3, fornaxv1.SessionStatusAvailable), 3)
+
+	if err := ApplySessionCheckpoints(world, checkpoints); err != nil {
+		t.Fatalf("ApplySessionCheckpoints() error = %v", err)
+	}
+
+	if got := running.Sessions["stale"].Session.Status.SessionStatus; got != fornaxv1.SessionStatusAvailable {
+		t.Errorf("stale session status = %s, want rehydrated %s", got, fornaxv1.SessionStatusAvailable)
+	}
+	if got := running.Sessions["fresh"].Session.Status.SessionStatus; got != fornaxv1.SessionStatusClosed {
+		t.Errorf("fresh session status = %s, want pod copy %s", got, fornaxv1.SessionStatusClosed)
+	}
+	if _, found := terminated.Sessions["unsaved"]; !found {
+		t.Errorf("unsaved session not rehydrated into terminated pod")
+	}
+	if _, err := checkpoints.GetSession("orphan"); err == nil {
+		t.Errorf("checkpoint of pod not in store was not removed")
+	}
+	for _, id := range []string{"stale", "fresh", "unsaved"} {
+		if _, err := checkpoints.GetSession(id); err != nil {
+			t.Errorf("checkpoint of session %s should be kept, error = %v", id, err)
+		}
+	}
+}
+
+func TestApplySessionCheckpointsNilStore(t *testing.T) {
+	if err := ApplySessionCheckpoints(ContainerWorldSummary{}, nil); err != nil {
+		t.Errorf("ApplySessionCheckpoints() with nil store error = %v", err)
+	}
+}
+
+func TestNewerRevision(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want bool
+	}{
+		{name: "newer", a: "10", b: "9", want: true},
+		{name: "older", a: "9", b: "10", want: false},
+		{name: "same", a: "7", b: "7", want: false},
+		{name: "compare as number", a: "100", b: "99", want: true},
+		{name: "malformed newer", a: "x", b: "1", want: false},
+		{name: "malformed older", a: "2", b: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newerRevision(tt.a, tt.b); got != tt.want {
+				t.Errorf("newerRevision(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
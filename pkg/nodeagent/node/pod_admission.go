@@ -0,0 +1,165 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxerrors"
+)
+
+const (
+	PodReasonAdmissionRejected = string(fornaxerrors.AdmissionRejected)
+
+	defaultImageRegistry = "docker.io"
+)
+
+// PodAdmissionPolicy is node local policy checked before a pod is created, it reject non compliant pods
+// even if fornaxcore is misconfigured, empty field means no restriction
+type PodAdmissionPolicy struct {
+	// image must come from one of registries or repository prefixes, e.g. docker.io/library, registry.example.com
+	// image without registry is treated as docker.io image
+	// +optional
+	AllowedImageRegistries []string `json:"allowedImageRegistries,omitempty"`
+
+	// max resources of a pod, sum of max of request and limit of every container
+	// +optional
+	MaxPodResources v1.ResourceList `json:"maxPodResources,omitempty"`
+
+	// host paths and their sub directories which pod can not mount, e.g. /etc, /var/run/docker.sock,
+	// a parent directory of a forbidden path can not be mounted either
+	// +optional
+	ForbiddenHostPaths []string `json:"forbiddenHostPaths,omitempty"`
+}
+
+// LoadPodAdmissionPolicy read policy from a json file, return nil if file is not configured
+func LoadPodAdmissionPolicy(file string) (*PodAdmissionPolicy, error) {
+	if len(file) == 0 {
+		return nil, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	policy := &PodAdmissionPolicy{}
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("invalid pod admission policy %s: %v", file, err)
+	}
+	return policy, nil
+}
+
+// Admit return error describing first violation of pod
+func (p *PodAdmissionPolicy) Admit(pod *v1.Pod) error {
+	if err := p.admitImages(pod); err != nil {
+		return err
+	}
+	if err := p.admitResources(pod); err != nil {
+		return err
+	}
+	return p.admitHostPaths(pod)
+}
+
+// normalizeImage add default registry and library repository to image name,
+// e.g. nginx => docker.io/library/nginx, a name component is a registry if it contain a . or : or it's localhost
+func normalizeImage(image string) string {
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 1 {
+		return fmt.Sprintf("%s/library/%s", defaultImageRegistry, image)
+	}
+	if parts[0] == "localhost" || strings.ContainsAny(parts[0], ".:") {
+		return image
+	}
+	return fmt.Sprintf("%s/%s", defaultImageRegistry, image)
+}
+
+func (p *PodAdmissionPolicy) imageAllowed(image string) bool {
+	normalized := normalizeImage(image)
+	for _, v := range p.AllowedImageRegistries {
+		prefix := strings.TrimSuffix(v, "/")
+		if strings.HasPrefix(normalized, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *PodAdmissionPolicy) admitImages(pod *v1.Pod) error {
+	if len(p.AllowedImageRegistries) == 0 {
+		return nil
+	}
+	containers := append([]v1.Container{}, pod.Spec.InitContainers...)
+	containers = append(containers, pod.Spec.Containers...)
+	for _, v := range containers {
+		if !p.imageAllowed(v.Image) {
+			return fmt.Errorf("image %s of container %s is not from allowed registries", v.Image, v.Name)
+		}
+	}
+	return nil
+}
+
+func (p *PodAdmissionPolicy) admitResources(pod *v1.Pod) error {
+	if len(p.MaxPodResources) == 0 {
+		return nil
+	}
+	total := v1.ResourceList{}
+	for _, v := range pod.Spec.Containers {
+		for name := range p.MaxPodResources {
+			quantity := resource.Quantity{}
+			if request, found := v.Resources.Requests[name]; found {
+				quantity = request.DeepCopy()
+			}
+			if limit, found := v.Resources.Limits[name]; found && limit.Cmp(quantity) > 0 {
+				quantity = limit.DeepCopy()
+			}
+			sum := total[name]
+			sum.Add(quantity)
+			total[name] = sum
+		}
+	}
+	for name, max := range p.MaxPodResources {
+		if sum := total[name]; sum.Cmp(max) > 0 {
+			return fmt.Errorf("pod %s %s exceed max %s of node policy", name, sum.String(), max.String())
+		}
+	}
+	return nil
+}
+
+func (p *PodAdmissionPolicy) admitHostPaths(pod *v1.Pod) error {
+	if len(p.ForbiddenHostPaths) == 0 {
+		return nil
+	}
+	for _, v := range pod.Spec.Volumes {
+		if v.HostPath == nil {
+			continue
+		}
+		path := filepath.Clean(v.HostPath.Path)
+		for _, forbidden := range p.ForbiddenHostPaths {
+			forbidden = filepath.Clean(forbidden)
+			if path == forbidden || strings.HasPrefix(path, strings.TrimSuffix(forbidden, "/")+"/") || strings.HasPrefix(forbidden, strings.TrimSuffix(path, "/")+"/") {
+				return fmt.Errorf("volume %s mount forbidden host path %s", v.Name, v.HostPath.Path)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/hostconfig"
+	v1 "k8s.io/api/core/v1"
+)
+
+// admitPodSysctls reject pod using sysctls not in safe list, or not supported by node kernel, runtime would fail pod sandbox creation later
+// with a less clear error, sysctls exist in /proc/sys of node since pod network and ipc namespaces have same kernel
+func admitPodSysctls(pod *v1.Pod) error {
+	if pod.Spec.SecurityContext == nil {
+		return nil
+	}
+	for _, v := range pod.Spec.SecurityContext.Sysctls {
+		if !fornaxv1.IsSafeSysctl(v.Name) {
+			return fmt.Errorf("sysctl %s is not in safe list", v.Name)
+		}
+		path := v.Name
+		if !strings.Contains(path, "/") {
+			path = strings.ReplaceAll(path, ".", "/")
+		}
+		if _, err := os.Stat(filepath.Join(hostconfig.DefaultSysctlRoot, path)); err != nil {
+			return fmt.Errorf("sysctl %s is not supported by node kernel", v.Name)
+		}
+	}
+	return nil
+}
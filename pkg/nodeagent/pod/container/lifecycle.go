@@ -21,7 +21,9 @@ import (
 	"net"
 	"net/http"
 	"strconv"
+	"time"
 
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/runtime"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
 	v1 "k8s.io/api/core/v1"
 
@@ -35,10 +37,15 @@ const (
 )
 
 func (pl *PodContainerActor) runLifecycleHandler(pod *types.FornaxPod, container *types.FornaxContainer, handler *v1.LifecycleHandler) (string, error) {
+	return RunLifecycleHandler(pl.dependencies.RuntimeService, pod, container, handler, 0)
+}
+
+// RunLifecycleHandler exec handler command in container or http get against pod, 0 timeout means no timeout
+func RunLifecycleHandler(runtimeService runtime.RuntimeService, pod *types.FornaxPod, container *types.FornaxContainer, handler *v1.LifecycleHandler, timeout time.Duration) (string, error) {
 	switch {
 	case handler.Exec != nil:
 		var msg string
-		stdout, stderr, err := pl.dependencies.RuntimeService.ExecCommand(container.RuntimeContainer.Id, handler.Exec.Command, 0)
+		stdout, stderr, err := runtimeService.ExecCommand(container.RuntimeContainer.Id, handler.Exec.Command, timeout)
 		if err != nil {
 			klog.ErrorS(err, "Exec lifecycle hook for Container in Pod failed",
 				"execCommand", handler.Exec.Command,
@@ -52,7 +59,7 @@ func (pl *PodContainerActor) runLifecycleHandler(pod *types.FornaxPod, container
 
 		return string(msg), err
 	case handler.HTTPGet != nil:
-		msg, err := pl.runHTTPHandler(pod, container, handler)
+		msg, err := runHTTPHandler(pod, container, handler, timeout)
 		if err != nil {
 			klog.ErrorS(err, "HTTP lifecycle hook for Container in Pod failed",
 				"path", handler.HTTPGet.Path,
@@ -69,7 +76,38 @@ func (pl *PodContainerActor) runLifecycleHandler(pod *types.FornaxPod, container
 	}
 }
 
-func (pl *PodContainerActor) runHTTPHandler(pod *types.FornaxPod, container *types.FornaxContainer, handler *v1.LifecycleHandler) (string, error) {
+func runHTTPHandler(pod *types.FornaxPod, container *types.FornaxContainer, handler *v1.LifecycleHandler, timeout time.Duration) (string, error) {
+	url, err := httpHandlerURL(pod, container, handler)
+	if err != nil {
+		return "", err
+	}
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(url)
+	return getHTTPRespBody(resp), err
+}
+
+// RunWarmUpHandler run pod warm up like a lifecycle hook, but a http get response with error status is a failure too
+func RunWarmUpHandler(runtimeService runtime.RuntimeService, pod *types.FornaxPod, container *types.FornaxContainer, handler *v1.LifecycleHandler, timeout time.Duration) (string, error) {
+	if handler.HTTPGet == nil {
+		return RunLifecycleHandler(runtimeService, pod, container, handler, timeout)
+	}
+	url, err := httpHandlerURL(pod, container, handler)
+	if err != nil {
+		return "", err
+	}
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	msg := getHTTPRespBody(resp)
+	if resp.StatusCode >= http.StatusBadRequest {
+		return msg, fmt.Errorf("http get %s returned status %d", url, resp.StatusCode)
+	}
+	return msg, nil
+}
+
+func httpHandlerURL(pod *types.FornaxPod, container *types.FornaxContainer, handler *v1.LifecycleHandler) (string, error) {
 	host := handler.HTTPGet.Host
 	if len(host) == 0 {
 		if len(pod.RuntimePod.IPs) == 0 {
@@ -87,9 +125,7 @@ func (pl *PodContainerActor) runHTTPHandler(pod *types.FornaxPod, container *typ
 			return "", err
 		}
 	}
-	url := fmt.Sprintf("http://%s/%s", net.JoinHostPort(host, strconv.Itoa(port)), handler.HTTPGet.Path)
-	resp, err := http.Get(url)
-	return getHTTPRespBody(resp), err
+	return fmt.Sprintf("http://%s/%s", net.JoinHostPort(host, strconv.Itoa(port)), handler.HTTPGet.Path), nil
 }
 
 func resolvePort(portReference intstr.IntOrString, container *v1.Container) (int, error) {
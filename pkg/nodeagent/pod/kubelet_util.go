@@ -30,6 +30,7 @@ import (
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/config"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/qos"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/runtime"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
 	libcontainercgroups "github.com/opencontainers/runc/libcontainer/cgroups"
 	"github.com/opencontainers/selinux/go-selinux"
 	v1 "k8s.io/api/core/v1"
@@ -294,12 +295,15 @@ func generateLinuxContainerConfig(nodeConfig *config.NodeConfiguration, containe
 	lc.Resources = calculateLinuxResources(nodeConfig, container.Resources.Requests.Cpu(), container.Resources.Limits.Cpu(), container.Resources.Limits.Memory())
 
 	lc.Resources.OomScoreAdj = int64(nodeConfig.OOMScoreAdj)
+	if score, found := util.PodOOMScoreAdj(pod); found {
+		lc.Resources.OomScoreAdj = score
+	}
 	lc.Resources.HugepageLimits = GetHugepageLimitsFromResources(container.Resources)
 	lc.Resources.MemorySwapLimitInBytes = lc.Resources.MemoryLimitInBytes
 
 	// Set memory.min and memory.high to enforce MemoryQoS
+	unified := map[string]string{}
 	if enforceMemoryQoS {
-		unified := map[string]string{}
 		memoryRequest := container.Resources.Requests.Memory().Value()
 		memoryLimit := container.Resources.Limits.Memory().Value()
 		if memoryRequest != 0 {
@@ -308,31 +312,25 @@ func generateLinuxContainerConfig(nodeConfig *config.NodeConfiguration, containe
 
 		// If container sets limits.memory, we set memory.high=pod.spec.containers[i].resources.limits[memory] * memory_throttling_factor
 		// for container level cgroup if memory.high>memory.min.
-		// If container doesn't set limits.memory, we set memory.high=node_allocatable_memory * memory_throttling_factor
-		// for container level cgroup.
-		memoryHigh := int64(0)
-		if memoryLimit != 0 {
-			memoryHigh = int64(float64(memoryLimit) * config.DefaultMemoryThrottlingFactor)
-		} else {
-			// allocatable := m.getNodeAllocatable()
-			// allocatableMemory, ok := allocatable[v1.ResourceMemory]
-			// if ok && allocatableMemory.Value() > 0 {
-			//  memoryHigh = int64(float64(allocatableMemory.Value()) * m.memoryThrottlingFactor)
-			// }
-		}
-		if memoryHigh > memoryRequest {
+		// memory.high is only set for burstable pods, guaranteed pods are limited by memory.max
+		if memoryHigh := qos.BurstableMemoryHigh(pod, memoryRequest, memoryLimit); memoryHigh > 0 {
 			unified[qos.MemoryHigh] = strconv.FormatInt(memoryHigh, 10)
 		}
-		if len(unified) > 0 {
-			if lc.Resources.Unified == nil {
-				lc.Resources.Unified = unified
-			} else {
-				for k, v := range unified {
-					lc.Resources.Unified[k] = v
-				}
+	}
+
+	// set cpu.weight from cpu request on cgroup v2, instead of relying on runtime converting cpu shares
+	if nodeConfig.CgroupFeatures.CPUWeight() {
+		unified[qos.CPUWeight] = strconv.FormatUint(qos.CPUSharesToWeight(uint64(lc.Resources.CpuShares)), 10)
+	}
+	if len(unified) > 0 {
+		if lc.Resources.Unified == nil {
+			lc.Resources.Unified = unified
+		} else {
+			for k, v := range unified {
+				lc.Resources.Unified[k] = v
 			}
-			klog.V(4).InfoS("MemoryQoS config for container", "pod", klog.KObj(pod), "containerName", container.Name, "unified", unified)
 		}
+		klog.V(4).InfoS("Cgroup v2 config for container", "pod", klog.KObj(pod), "containerName", container.Name, "unified", unified)
 	}
 
 	return lc
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"time"
 
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxerrors"
 	internal "centaurusinfra.io/fornax-serverless/pkg/nodeagent/message"
 	podcontainer "centaurusinfra.io/fornax-serverless/pkg/nodeagent/pod/container"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/runtime"
@@ -74,6 +75,12 @@ func (a *PodActor) CreatePod() (err error) {
 	var firstSeenTime time.Time = time.Now()
 	metrics.PodWorkerStartDuration.Observe(metrics.SinceInSeconds(firstSeenTime))
 
+	// Allocate gpus before anything is created for pod, so, a pod not fit on node fail fast
+	if _, err := a.dependencies.GPUManager.Allocate(pod); err != nil {
+		klog.ErrorS(err, "Failed to allocate gpus for pod", "pod", types.UniquePodName(a.pod))
+		return err
+	}
+
 	// Create Cgroups for the pod and apply resource parameters
 	klog.InfoS("Create Pod Cgroup", "pod", types.UniquePodName(a.pod))
 	pcm := a.dependencies.QosManager
@@ -99,6 +106,10 @@ func (a *PodActor) CreatePod() (err error) {
 		klog.ErrorS(err, "Unable to make pod data directories for pod", "pod", types.UniquePodName(a.pod))
 		return err
 	}
+	if err := a.issueSessionServiceCertificate(); err != nil {
+		klog.ErrorS(err, "Unable to issue session service certificate for pod", "pod", types.UniquePodName(a.pod))
+		return err
+	}
 
 	// Make log directories for the pod
 	klog.InfoS("Make Pod log dirs", "pod", types.UniquePodName(a.pod))
@@ -135,6 +146,12 @@ func (a *PodActor) CreatePod() (err error) {
 		return err
 	}
 
+	// program network policy before any container start, so application never run with unrestricted network
+	if err := a.applyNetworkPolicy(runtimePod); err != nil {
+		klog.ErrorS(err, "Failed to apply network policy", "pod", types.UniquePodName(a.pod))
+		return fornaxerrors.Wrap(fornaxerrors.NetworkPolicyFailed, "failed to apply network policy of pod", err)
+	}
+
 	klog.InfoS("Start pod init containers", "pod", types.UniquePodName(a.pod))
 	var runtimeContainer *runtime.Container
 	for _, v1InitContainer := range pod.Spec.InitContainers {
@@ -243,6 +260,7 @@ func (a *PodActor) CleanupPod() (err error) {
 
 	// Remove data directories for the pod
 	klog.InfoS("Remove Pod Data dirs", "pod", types.UniquePodName(a.pod))
+	a.forgetSessionServiceCertificate()
 	if err := CleanupPodDataDirs(a.nodeConfig.RootPath, pod); err != nil {
 		klog.ErrorS(err, "Unable to remove pod data directories for pod", "pod", types.UniquePodName(a.pod))
 		return err
@@ -269,6 +287,8 @@ func (a *PodActor) CleanupPod() (err error) {
 		pcm.UpdateQOSCgroups()
 	}
 
+	a.dependencies.GPUManager.Deallocate(pod)
+
 	// TODO
 	// update resource manager about resource usage
 	return nil
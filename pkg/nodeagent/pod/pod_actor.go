@@ -22,6 +22,7 @@ import (
 	"time"
 
 	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxerrors"
 	"centaurusinfra.io/fornax-serverless/pkg/message"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/config"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/dependency"
@@ -32,17 +33,22 @@ import (
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/sessionservice"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
 	"centaurusinfra.io/fornax-serverless/pkg/util"
-	v1 "k8s.io/api/core/v1"
 	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
 	"k8s.io/klog/v2"
 )
 
 const (
 	houseKeepingPeriod = 5 * time.Second
+	podStatsPeriod     = 30 * time.Second
+	oomCheckPeriod     = 5 * time.Second
 )
 
 type HouseKeeping struct{}
 
+type PodStatsCollect struct{}
+
+type PodOOMCheck struct{}
+
 type PodActor struct {
 	supervisor        message.ActorRef
 	stop              bool
@@ -53,6 +59,8 @@ type PodActor struct {
 	dependencies      *dependency.Dependencies
 	nodeConfig        *config.NodeConfiguration
 	houseKeepingError error
+	restartBackoff    map[string]time.Duration
+	warmingUp         bool
 }
 
 func (n *PodActor) Reference() message.ActorRef {
@@ -78,9 +86,13 @@ func (a *PodActor) Start() {
 	// start house keeping loop to make sure pod reach its final state, either failed or revive from temporary runtime error
 	go func() {
 		ticker := time.NewTicker(houseKeepingPeriod)
+		statsTicker := time.NewTicker(podStatsPeriod)
+		oomTicker := time.NewTicker(oomCheckPeriod)
 		for {
 			if a.stop {
 				ticker.Stop()
+				statsTicker.Stop()
+				oomTicker.Stop()
 				break
 			}
 
@@ -89,6 +101,15 @@ func (a *PodActor) Start() {
 				if a.houseKeepingError != nil {
 					a.notify(a.Reference(), HouseKeeping{})
 				}
+			case _ = <-statsTicker.C:
+				if a.pod.FornaxPodState == types.PodStateRunning {
+					a.notify(a.Reference(), PodStatsCollect{})
+				}
+			case _ = <-oomTicker.C:
+				// a oom kill could happen when pod is starting or hibernated, check it unless pod is gone
+				if a.pod.FornaxPodState != types.PodStateTerminated && a.pod.FornaxPodState != types.PodStateCleanup {
+					a.notify(a.Reference(), PodOOMCheck{})
+				}
 			}
 		}
 	}()
@@ -109,8 +130,8 @@ func (a *PodActor) recoverContainerAndSessionActors() {
 	}
 
 	for _, sess := range a.pod.Sessions {
-		if !util.SessionIsClosed(sess.Session) {
-			klog.InfoS("Recover session actor on pod", "pod", types.UniquePodName(a.pod), "session", sess.Identifier, "status", sess.Session.Status)
+		if !util.SessionInTerminalState(sess.Session) {
+			klog.InfoS("Recover session actor on pod", "pod", types.UniquePodName(a.pod), "session", sess.Identifier, "requestId", util.RequestId(sess.Session), "status", sess.Session.Status)
 			var sessService sessionservice.SessionService
 			if util.PodHasSessionServiceAnnotation(a.pod.Pod) {
 				sessService = a.dependencies.SessionService
@@ -119,7 +140,9 @@ func (a *PodActor) recoverContainerAndSessionActors() {
 			}
 			actor := session.NewSessionActor(a.pod, sess, sessService, a.innerActor.Reference())
 			a.sessionActors[sess.Identifier] = actor
-			actor.PingSession()
+			if err := actor.PingSession(); err != nil {
+				klog.ErrorS(err, "Failed to revalidate recovered session with session service", "pod", types.UniquePodName(a.pod), "session", sess.Identifier)
+			}
 		}
 	}
 }
@@ -139,6 +162,8 @@ func (n *PodActor) notifyContainer(containerName string, msg interface{}) error
 
 func (a *PodActor) podHandler(msg message.ActorMessage) (interface{}, error) {
 	oldPodState := a.pod.FornaxPodState
+	oldRestartCount := a.containerRestartCount()
+	statsChanged := false
 	var err error
 	switch msg.Body.(type) {
 	case internal.PodCreate:
@@ -147,6 +172,8 @@ func (a *PodActor) podHandler(msg message.ActorMessage) (interface{}, error) {
 		err = a.hibernate()
 	case internal.PodTerminate:
 		err = a.terminate(false)
+	case internal.PodEvict:
+		err = a.evict(msg.Body.(internal.PodEvict))
 	case internal.PodContainerCreated:
 		err = a.onPodContainerCreated(msg.Body.(internal.PodContainerCreated))
 	case internal.PodContainerStarted:
@@ -157,16 +184,37 @@ func (a *PodActor) podHandler(msg message.ActorMessage) (interface{}, error) {
 		err = a.onPodContainerStopped(msg.Body.(internal.PodContainerStopped))
 	case internal.PodContainerFailed:
 		err = a.onPodContainerFailed(msg.Body.(internal.PodContainerFailed))
+	case PodContainerRestart:
+		err = a.restartContainer(msg.Body.(PodContainerRestart).Name)
+	case PodWarmUpResult:
+		err = a.onPodWarmUpResult(msg.Body.(PodWarmUpResult))
+	case internal.PodConfigUpdate:
+		err = a.onPodConfigUpdateCommand(msg.Body.(internal.PodConfigUpdate))
+	case internal.PodSecretUpdate:
+		err = a.onPodSecretUpdateCommand(msg.Body.(internal.PodSecretUpdate))
 	case internal.SessionOpen:
 		err = a.onSessionOpenCommand(msg.Body.(internal.SessionOpen))
 	case internal.SessionClose:
 		err = a.onSessionCloseCommand(msg.Body.(internal.SessionClose))
+	case internal.SessionDrain:
+		err = a.onSessionDrainCommand(msg.Body.(internal.SessionDrain))
+	case internal.SessionMigrate:
+		err = a.onSessionMigrateCommand(msg.Body.(internal.SessionMigrate))
+	case internal.SessionTimeLimitWarning:
+		err = a.onSessionTimeLimitWarning(msg.Body.(internal.SessionTimeLimitWarning))
+	case internal.SessionTimeLimitExceeded:
+		err = a.onSessionTimeLimitExceeded(msg.Body.(internal.SessionTimeLimitExceeded))
 	case internal.SessionState:
 		err = a.handleSessionState(msg.Body.(internal.SessionState))
 		if err != nil || a.pod.FornaxPodState == types.PodStateTerminating {
 			// when pod termination was requested, recheck if pod can be finally terminated after session closed
 			err = a.terminate(false)
 		}
+	case PodStatsCollect:
+		statsChanged = a.collectCPUStats()
+		a.collectSessionUsage(nil)
+	case PodOOMCheck:
+		a.detectOOMKill(nil)
 	case HouseKeeping:
 		// calibarate pod error and cleanup, return if cleanup failed, do not change previous error state
 		if a.houseKeepingError != nil {
@@ -183,12 +231,14 @@ func (a *PodActor) podHandler(msg message.ActorMessage) (interface{}, error) {
 		a.houseKeepingError = nil
 		if a.pod.FornaxPodState == types.PodStateTerminated {
 			a.houseKeepingError = a.cleanup()
+			a.forgetCPUStats()
 			SetPodStatus(a.pod, nil)
 		}
 	}
 
 	// notify fornax core when state changed or pod cleaned
-	if oldPodState != a.pod.FornaxPodState || a.pod.FornaxPodState == types.PodStateCleanup {
+	// a container restart is reported too, so fornaxcore see restart count and last termination reason
+	if oldPodState != a.pod.FornaxPodState || a.pod.FornaxPodState == types.PodStateCleanup || statsChanged || oldRestartCount != a.containerRestartCount() {
 		klog.InfoS("PodState changed", "pod", types.UniquePodName(a.pod), "old state", oldPodState, "new state", a.pod.FornaxPodState)
 		a.notify(a.supervisor, internal.PodStatusChange{Pod: a.pod})
 	}
@@ -209,6 +259,11 @@ func (a *PodActor) create() error {
 	a.pod.FornaxPodState = types.PodStateCreating
 	err := a.CreatePod()
 	if err != nil {
+		// a coded failure is reported as pod status reason, so, fornaxcore know why pod failed
+		if code := fornaxerrors.CodeOf(err); code != fornaxerrors.Unknown {
+			a.pod.Pod.Status.Reason = string(code)
+			a.pod.Pod.Status.Message = err.Error()
+		}
 		return err
 	}
 
@@ -218,6 +273,19 @@ func (a *PodActor) create() error {
 	return nil
 }
 
+// evict terminate a idle pod to give back node resource, node checked pod was idle, but a session could be opened on pod since then,
+// such a pod is kept, pod status reason tell fornaxcore why pod was terminated
+func (a *PodActor) evict(msg internal.PodEvict) error {
+	if a.pod.Daemon || types.PodHasOpenSessions(a.pod) || types.PodInTerminating(a.pod) {
+		klog.InfoS("Pod is not idle, skip eviction", "pod", types.UniquePodName(a.pod), "state", a.pod.FornaxPodState)
+		return nil
+	}
+	klog.InfoS("Evicting pod", "pod", types.UniquePodName(a.pod), "reason", msg.Reason, "message", msg.Message)
+	a.pod.Pod.Status.Reason = msg.Reason
+	a.pod.Pod.Status.Message = msg.Message
+	return a.terminate(false)
+}
+
 // terminate evacute session if there are live sessions, and terminate pod containers,
 // containers are notified to exit itself, and use onPodContainerStopped call back to get container status,
 // and recall this method to check if all container are finished, and finally set pod to terminted state
@@ -251,6 +319,11 @@ func (a *PodActor) terminate(forceTerminatePod bool) error {
 		if pod.Pod.DeletionGracePeriodSeconds != nil {
 			gracefulPeriodSeconds = *pod.Pod.DeletionGracePeriodSeconds
 		}
+		// record termination intent before killing containers, if node agent crash in middle, termination is completed after restart
+		if err := a.journalTermination(gracefulPeriodSeconds, forceTerminatePod); err != nil {
+			klog.ErrorS(err, "Failed to record pod termination, retry later", "pod", types.UniquePodName(pod))
+			return err
+		}
 		terminated, err := a.TerminatePod(time.Duration(gracefulPeriodSeconds), forceTerminatePod)
 		if err != nil {
 			klog.ErrorS(err, "Pod termination failed, state is left in terminating to retry later,", "pod", types.UniquePodName(pod))
@@ -265,6 +338,23 @@ func (a *PodActor) terminate(forceTerminatePod bool) error {
 	return nil
 }
 
+// journalTermination save a termination entry into node agent store synchronously, entry is removed when pod is cleaned from store
+func (a *PodActor) journalTermination(gracefulPeriodSeconds int64, force bool) error {
+	if a.dependencies.TerminationStore == nil {
+		return nil
+	}
+	termination := &types.FornaxPodTermination{
+		PodIdentifier:      a.pod.Identifier,
+		Force:              force,
+		GracePeriodSeconds: gracefulPeriodSeconds,
+		RequestedAt:        time.Now(),
+	}
+	if a.pod.RuntimePod != nil {
+		termination.RuntimePodId = a.pod.RuntimePod.Id
+	}
+	return a.dependencies.TerminationStore.PutPodTermination(termination)
+}
+
 func (a *PodActor) cleanup() error {
 	klog.InfoS("Cleanup pod", "pod", types.UniquePodName(a.pod))
 	err := a.CleanupPod()
@@ -376,6 +466,8 @@ func (a *PodActor) onPodContainerFailed(msg internal.PodContainerFailed) error {
 }
 
 func (a *PodActor) handlePodContainerExit(pod *types.FornaxPod, container *types.FornaxContainer) error {
+	// close sessions with oom reason before container is restarted or pod is terminated
+	a.detectOOMKill(container)
 	actor, found := a.containerActors[container.ContainerSpec.Name]
 	if found {
 		actor.Stop()
@@ -389,6 +481,9 @@ func (a *PodActor) handlePodContainerExit(pod *types.FornaxPod, container *types
 			return a.terminate(true)
 		}
 	} else {
+		if a.shouldRestartContainer(container) {
+			return a.scheduleContainerRestart(container)
+		}
 		return a.terminate(true)
 	}
 	return nil
@@ -410,18 +505,26 @@ func (a *PodActor) onPodContainerReady(msg internal.PodContainerReady) error {
 	}
 
 	if allContainerReady {
-		pod.FornaxPodState = types.PodStateRunning
-		// hibernate pod if pod spec has hibernate annotation
-		if util.PodHasHibernateAnnotation(pod.Pod) && (a.nodeConfig.RuntimeHandler == runtime.QuarkRuntime || a.nodeConfig.RuntimeHandler == runtime.QuarkRuntime_D) {
-			a.hibernateContainer(container)
+		// pod with warm up is set to running after warm up succeed
+		if warmUp, need := a.podNeedWarmUp(); need {
+			return a.startWarmUp(warmUp)
 		}
+		a.setPodRunning(container)
 	}
 	return nil
 }
 
+func (a *PodActor) setPodRunning(container *types.FornaxContainer) {
+	a.pod.FornaxPodState = types.PodStateRunning
+	// hibernate pod if pod spec has hibernate annotation
+	if util.PodHasHibernateAnnotation(a.pod.Pod) && (a.nodeConfig.RuntimeHandler == runtime.QuarkRuntime || a.nodeConfig.RuntimeHandler == runtime.QuarkRuntime_D) {
+		a.hibernateContainer(container)
+	}
+}
+
 // build a session actor to start session and monitor session state
 func (a *PodActor) onSessionOpenCommand(msg internal.SessionOpen) (err error) {
-	klog.InfoS("Open session", "Pod", a.pod.Identifier, "session", msg.SessionId)
+	klog.InfoS("Open session", "Pod", a.pod.Identifier, "session", msg.SessionId, "requestId", util.RequestId(msg.Session))
 	if a.pod.FornaxPodState == types.PodStateHibernated {
 		for _, v := range a.pod.Containers {
 			if v.State == types.ContainerStateHibernated {
@@ -433,7 +536,7 @@ func (a *PodActor) onSessionOpenCommand(msg internal.SessionOpen) (err error) {
 				v.State = types.ContainerStateRunning
 			}
 		}
-		klog.InfoS("Open session, wakeup container", "Pod", a.pod.Identifier, "session", msg.SessionId)
+		klog.InfoS("Open session, wakeup container", "Pod", a.pod.Identifier, "session", msg.SessionId, "requestId", util.RequestId(msg.Session))
 		a.pod.FornaxPodState = types.PodStateRunning
 	} else if a.pod.FornaxPodState != types.PodStateRunning {
 		return fmt.Errorf("Pod: %s is not in running state, can not open session", msg.SessionId)
@@ -449,6 +552,8 @@ func (a *PodActor) onSessionOpenCommand(msg internal.SessionOpen) (err error) {
 		PodIdentifier:  a.pod.Identifier,
 		Session:        msg.Session.DeepCopy(),
 		ClientSessions: map[string]*types.ClientSession{},
+		PressureStall:  a.samplePressureStall(),
+		Usage:          &types.FornaxSessionUsage{StartTime: time.Now()},
 	}
 	var sessService sessionservice.SessionService
 	if util.PodHasSessionServiceAnnotation(a.pod.Pod) {
@@ -464,22 +569,120 @@ func (a *PodActor) onSessionOpenCommand(msg internal.SessionOpen) (err error) {
 	}
 
 	if err != nil {
-		klog.ErrorS(err, "Failed to open session", "session", msg.SessionId)
+		klog.ErrorS(err, "Failed to open session", "session", msg.SessionId, "requestId", util.RequestId(msg.Session))
+		if fornaxerrors.Is(err, fornaxerrors.SessionServiceUnavailable) {
+			// session can not be opened until pod connect session service, report it failed instead of waiting for timeout
+			a.failSession(sess, string(fornaxerrors.SessionServiceUnavailable), err.Error())
+			return nil
+		}
 	}
 
 	return err
 }
 
+// save new config map in pod and push config data to open sessions, pod is saved by supervisor when pod config changed
+func (a *PodActor) onPodConfigUpdateCommand(msg internal.PodConfigUpdate) error {
+	klog.InfoS("Update pod config", "pod", types.UniquePodName(a.pod))
+	if reflect.DeepEqual(a.pod.ConfigMap, msg.ConfigMap) {
+		return nil
+	}
+	a.pod.ConfigMap = msg.ConfigMap
+	a.notify(a.supervisor, internal.PodStatusChange{Pod: a.pod})
+	a.pushConfigToSessions()
+	return nil
+}
+
+// save new secret versions in pod and push them to open sessions, secret versions are recorded in pod annotations,
+// so, fornaxcore know which pods have picked up new version when pod state is reported back
+func (a *PodActor) onPodSecretUpdateCommand(msg internal.PodSecretUpdate) error {
+	klog.InfoS("Update pod secret", "pod", types.UniquePodName(a.pod), "version", msg.Secret.Version)
+	if reflect.DeepEqual(a.pod.Secret, msg.Secret) && reflect.DeepEqual(a.pod.PreviousSecret, msg.PreviousSecret) {
+		return nil
+	}
+	a.pod.Secret = msg.Secret
+	a.pod.PreviousSecret = msg.PreviousSecret
+	a.pushConfigToSessions()
+
+	if a.pod.Pod.Annotations == nil {
+		a.pod.Pod.Annotations = map[string]string{}
+	}
+	a.pod.Pod.Annotations[fornaxv1.AnnotationFornaxCoreSecretVersion] = msg.Secret.Version
+	if msg.PreviousSecret != nil {
+		a.pod.Pod.Annotations[fornaxv1.AnnotationFornaxCorePrevSecretVersion] = msg.PreviousSecret.Version
+	} else {
+		delete(a.pod.Pod.Annotations, fornaxv1.AnnotationFornaxCorePrevSecretVersion)
+	}
+	a.notify(a.supervisor, internal.PodStatusChange{Pod: a.pod})
+	return nil
+}
+
+// session which failed to receive config keep running with old config
+func (a *PodActor) pushConfigToSessions() {
+	for sessionId, sactor := range a.sessionActors {
+		if err := sactor.UpdateConfig(); err != nil {
+			klog.ErrorS(err, "Failed to push config update to session", "pod", types.UniquePodName(a.pod), "session", sessionId)
+		}
+	}
+}
+
 // find session actor to let it terminate a session, if pod actor does not exist, return failure
 func (a *PodActor) onSessionCloseCommand(msg internal.SessionClose) error {
-	klog.InfoS("Close session", "Pod", a.pod.Identifier, "session", msg.SessionId)
+	klog.InfoS("Close session", "Pod", a.pod.Identifier, "session", msg.SessionId, "requestId", msg.RequestId)
 	if sActor, found := a.sessionActors[msg.SessionId]; !found {
 		return fmt.Errorf("Session does not exist, %s", msg.SessionId)
 	} else {
+		// grace period could be extended after session was opened
+		if sess, found := a.pod.Sessions[msg.SessionId]; found && msg.GracePeriod > 0 {
+			graceSeconds := uint16(msg.GracePeriod / time.Second)
+			sess.Session.Spec.CloseGracePeriodSeconds = &graceSeconds
+		}
 		return sActor.CloseSession()
 	}
 }
 
+// tell application a time limited session is ending at deadline, it's relayed like a drain from fornaxcore
+func (a *PodActor) onSessionTimeLimitWarning(msg internal.SessionTimeLimitWarning) error {
+	sActor, found := a.sessionActors[msg.SessionId]
+	if !found {
+		return nil
+	}
+	klog.InfoS("Session is reaching time limit", "Pod", a.pod.Identifier, "session", msg.SessionId, "deadline", msg.Deadline)
+	return sActor.DrainSession(session.SessionDrainReasonTimeLimit, msg.Deadline)
+}
+
+// force close a session reached time limit, reason is kept in status when session is closed
+func (a *PodActor) onSessionTimeLimitExceeded(msg internal.SessionTimeLimitExceeded) error {
+	sActor, found := a.sessionActors[msg.SessionId]
+	sess, sessFound := a.pod.Sessions[msg.SessionId]
+	if !found || !sessFound || !util.SessionIsOpen(sess.Session) {
+		return nil
+	}
+	klog.InfoS("Close session reached time limit", "Pod", a.pod.Identifier, "session", msg.SessionId, "requestId", util.RequestId(sess.Session), "max duration seconds", sess.Session.Spec.MaxSessionDurationSeconds)
+	sess.Session.Status.Reason = fornaxv1.SessionReasonTimeLimitExceeded
+	sess.Session.Status.Message = fmt.Sprintf("session was open longer than %d seconds", sess.Session.Spec.MaxSessionDurationSeconds)
+	return sActor.CloseSession()
+}
+
+// find session actor to let it notify session it's ending soon
+func (a *PodActor) onSessionDrainCommand(msg internal.SessionDrain) error {
+	klog.InfoS("Drain session", "Pod", a.pod.Identifier, "session", msg.SessionId, "requestId", msg.RequestId, "reason", msg.Reason, "deadline", msg.Deadline)
+	if sActor, found := a.sessionActors[msg.SessionId]; !found {
+		return fmt.Errorf("Session does not exist, %s", msg.SessionId)
+	} else {
+		return sActor.DrainSession(msg.Reason, msg.Deadline)
+	}
+}
+
+// find session actor to let it export session metadata and close session for migration
+func (a *PodActor) onSessionMigrateCommand(msg internal.SessionMigrate) error {
+	klog.InfoS("Migrate session", "Pod", a.pod.Identifier, "session", msg.SessionId, "requestId", msg.RequestId, "target", msg.TargetPod)
+	if sActor, found := a.sessionActors[msg.SessionId]; !found {
+		return fmt.Errorf("Session does not exist, %s", msg.SessionId)
+	} else {
+		return sActor.MigrateSession(msg.TargetPod)
+	}
+}
+
 // simply update application session status and copy client session
 // if a session timeout, terminate pod,it could close other sessions on it
 func (a *PodActor) handleSessionState(s internal.SessionState) error {
@@ -488,6 +691,10 @@ func (a *PodActor) handleSessionState(s internal.SessionState) error {
 		klog.Warningf("Received session state from unknown session %s", s.SessionId)
 		return nil
 	}
+	if session.Session.Status.SessionStatus == fornaxv1.SessionStatusFailed {
+		// session was failed by node agent, e.g. oom killed, keep failed state and reason
+		return nil
+	}
 	newStatus := session.Session.Status.DeepCopy()
 
 	switch s.SessionState {
@@ -498,28 +705,47 @@ func (a *PodActor) handleSessionState(s internal.SessionState) error {
 	case types.SessionStateClosed:
 		newStatus.SessionStatus = fornaxv1.SessionStatusClosed
 		newStatus.CloseTime = util.NewCurrentMetaTime()
+		newStatus.QualityScore = a.sessionQualityScore(session)
+		a.finishSessionUsage(session)
 	case types.SessionStateNoHeartbeat:
 		newStatus.SessionStatus = fornaxv1.SessionStatusClosed
 		newStatus.CloseTime = util.NewCurrentMetaTime()
+		newStatus.QualityScore = a.sessionQualityScore(session)
+		a.finishSessionUsage(session)
+	case types.SessionStateTimeout:
+		// a session closing too long is considered closed, a session opening too long is timeout
+		if newStatus.SessionStatus == fornaxv1.SessionStatusClosing {
+			newStatus.SessionStatus = fornaxv1.SessionStatusClosed
+		} else {
+			newStatus.SessionStatus = fornaxv1.SessionStatusTimeout
+		}
+		newStatus.CloseTime = util.NewCurrentMetaTime()
+		newStatus.QualityScore = a.sessionQualityScore(session)
+		a.finishSessionUsage(session)
 	}
 
-	// just copy client sessions
-	clientSessions := []v1.LocalObjectReference{}
-	for _, v := range s.ClientSessions {
-		clientSessions = append(clientSessions, v1.LocalObjectReference{Name: v.Identifier})
-	}
-	newStatus.ClientSessions = clientSessions
-	if len(newStatus.ClientSessions) > 0 {
+	newStatus.ClientSessions, newStatus.ClientSessionCount = mergeClientSessions(session, s.ClientSessions)
+	if newStatus.ClientSessionCount > 0 {
 		newStatus.SessionStatus = fornaxv1.SessionStatusInUse
 	}
+	if newStatus.CloseTime != nil && sessionClosedWithFailure(newStatus) {
+		// session closed by node agent because of failure, e.g. oom killed, report it failed
+		newStatus.SessionStatus = fornaxv1.SessionStatusFailed
+	}
+	if newStatus.Migration != nil && len(s.MigrationMetadata) > 0 {
+		newStatus.Migration.Metadata = s.MigrationMetadata
+	}
 
 	if !reflect.DeepEqual(session.Session.Status, *newStatus) {
-		klog.InfoS("Session status changed", "session", s.SessionId, "old status", session.Session.Status, "new status", *newStatus)
+		klog.InfoS("Session status changed", "session", s.SessionId, "requestId", util.RequestId(session.Session), "old status", session.Session.Status, "new status", *newStatus)
 		session.Session.Status = *newStatus
 		a.notify(a.supervisor, internal.SessionStatusChange{Session: session, Pod: a.pod})
 	}
 
-	if util.SessionIsClosed(session.Session) {
+	if util.SessionInTerminalState(session.Session) {
+		if sActor, found := a.sessionActors[session.Identifier]; found {
+			sActor.Stop()
+		}
 		delete(a.sessionActors, session.Identifier)
 		if session.Session.Spec.KillInstanceWhenSessionClosed {
 			return a.terminate(false)
@@ -542,6 +768,7 @@ func NewPodActor(supervisor message.ActorRef, pod *types.FornaxPod, nodeConfig *
 		nodeConfig:        nodeConfig,
 		sessionActors:     map[string]*session.SessionActor{},
 		containerActors:   map[string]*podcontainer.PodContainerActor{},
+		restartBackoff:    map[string]time.Duration{},
 	}
 	actor.innerActor = message.NewLocalChannelActor(types.UniquePodName(pod), actor.podHandler)
 	return actor
@@ -23,6 +23,8 @@ import (
 	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
 	"k8s.io/klog/v2"
 
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxerrors"
+	resourcemanager "centaurusinfra.io/fornax-serverless/pkg/nodeagent/resource"
 	cruntime "centaurusinfra.io/fornax-serverless/pkg/nodeagent/runtime"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
 )
@@ -41,7 +43,7 @@ func (a *PodActor) createContainer(podSandboxConfig *criv1.PodSandboxConfig, con
 	imageRef, err := a.dependencies.ImageManager.PullImageForContainer(containerSpec, podSandboxConfig)
 	if err != nil {
 		klog.ErrorS(err, "Failed to pull image", "pod", types.UniquePodName(a.pod), "container", containerSpec.Name)
-		return nil, err
+		return nil, fornaxerrors.Wrap(fornaxerrors.ImagePullFailed, fmt.Sprintf("failed to pull image %s of container %s", containerSpec.Image, containerSpec.Name), err)
 	}
 
 	// create the container log dir
@@ -145,7 +147,7 @@ func (m *PodActor) generateContainerConfig(container *v1.Container, imageRef *cr
 		uid = &value
 	}
 	username := imageRef.GetUsername()
-	generateLinuxContainerConfig(m.nodeConfig, container, pod, uid, username, true)
+	config.Linux = generateLinuxContainerConfig(m.nodeConfig, container, pod, uid, username, m.nodeConfig.MemoryQoS)
 
 	// set environment variables
 	criEnvs := make([]*criv1.KeyValue, len(envs))
@@ -158,6 +160,31 @@ func (m *PodActor) generateContainerConfig(container *v1.Container, imageRef *cr
 	}
 	config.Envs = criEnvs
 
+	mounts, err := makePersistentVolumeMounts(m.nodeConfig.RootPath, pod, container)
+	if err != nil {
+		return nil, err
+	}
+	config.Mounts = mounts
+
+	// mount allocated gpus, nvidia container runtime expose all gpus if NVIDIA_VISIBLE_DEVICES is not set, hide them from containers without gpu
+	devices, err := m.dependencies.GPUManager.ContainerDevices(pod, container.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(devices) > 0 {
+		ids := []string{}
+		for _, v := range devices {
+			config.Devices = append(config.Devices, &criv1.Device{ContainerPath: v.HostPath, HostPath: v.HostPath, Permissions: "rwm"})
+			if len(v.ID) > 0 {
+				ids = append(ids, v.ID)
+			}
+		}
+		config.Envs = append(config.Envs, &criv1.KeyValue{Key: resourcemanager.NvidiaVisibleDevices, Value: strings.Join(ids, ",")})
+	} else if m.dependencies.GPUManager.HasGPU() {
+		config.Envs = append(config.Envs, &criv1.KeyValue{Key: resourcemanager.NvidiaVisibleDevices, Value: "none"})
+	}
+	m.setSessionServiceTLS(config)
+
 	return config, nil
 }
 
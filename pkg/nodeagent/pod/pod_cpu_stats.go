@@ -0,0 +1,148 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"time"
+
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// PodThrottlingHigh is set on pod when its sessions are cpu throttled more than node threshold
+	PodThrottlingHigh v1.PodConditionType = "ThrottlingHigh"
+)
+
+var (
+	podCPUThrottledSeconds = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      "fornax_nodeagent",
+			Name:           "pod_cpu_throttled_seconds",
+			Help:           "Total time pod cgroup has been cpu throttled",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"namespace", "pod"},
+	)
+	podCPUThrottledRatio = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      "fornax_nodeagent",
+			Name:           "pod_cpu_throttled_ratio",
+			Help:           "Ratio of throttled cfs periods of pod cgroup in last sample interval",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"namespace", "pod"},
+	)
+	nodeCPUThrottlingHighPods = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Subsystem:      "fornax_nodeagent",
+			Name:           "cpu_throttling_high_pods",
+			Help:           "Number of pods on node which have ThrottlingHigh condition",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(podCPUThrottledSeconds, podCPUThrottledRatio, nodeCPUThrottlingHighPods)
+}
+
+// collectCPUStats read cfs stats of pod cgroup from cadvisor and calculate throttled ratio since last sample,
+// pod is marked as throttling high only when it has open sessions, return true if throttling high state changed
+func (a *PodActor) collectCPUStats() bool {
+	pod := a.pod
+	if a.dependencies.CAdvisor == nil || a.dependencies.QosManager == nil || pod.RuntimePod == nil {
+		return false
+	}
+	stats, err := a.dependencies.CAdvisor.GetContainerStats(a.dependencies.QosManager.GetPodCgroupParent(pod.Pod))
+	if err != nil {
+		klog.V(5).InfoS("Failed to get pod cgroup stats", "pod", types.UniquePodName(pod), "err", err)
+		return false
+	}
+	if stats.Cpu == nil {
+		return false
+	}
+
+	cfs := stats.Cpu.CFS
+	cpuStats := &types.FornaxPodCPUStats{
+		Periods:          cfs.Periods,
+		ThrottledPeriods: cfs.ThrottledPeriods,
+		ThrottledTime:    cfs.ThrottledTime,
+		SampleTime:       time.Now(),
+	}
+	prev := pod.CPUStats
+	if prev != nil && cfs.Periods > prev.Periods && cfs.ThrottledPeriods >= prev.ThrottledPeriods {
+		cpuStats.ThrottledRatio = float64(cfs.ThrottledPeriods-prev.ThrottledPeriods) / float64(cfs.Periods-prev.Periods)
+	}
+	cpuStats.ThrottlingHigh = types.PodHasOpenSessions(pod) && cpuStats.ThrottledRatio >= a.throttlingThreshold()
+	pod.CPUStats = cpuStats
+
+	podCPUThrottledSeconds.WithLabelValues(pod.Pod.Namespace, pod.Pod.Name).Set(float64(cfs.ThrottledTime) / float64(time.Second))
+	podCPUThrottledRatio.WithLabelValues(pod.Pod.Namespace, pod.Pod.Name).Set(cpuStats.ThrottledRatio)
+
+	wasHigh := prev != nil && prev.ThrottlingHigh
+	if wasHigh != cpuStats.ThrottlingHigh {
+		if cpuStats.ThrottlingHigh {
+			nodeCPUThrottlingHighPods.Inc()
+			klog.InfoS("Pod cpu throttling high", "pod", types.UniquePodName(pod), "throttled ratio", cpuStats.ThrottledRatio)
+		} else {
+			nodeCPUThrottlingHighPods.Dec()
+		}
+		return true
+	}
+	return false
+}
+
+func (a *PodActor) throttlingThreshold() float64 {
+	if a.nodeConfig != nil && a.nodeConfig.CPUThrottlingThreshold > 0 {
+		return a.nodeConfig.CPUThrottlingThreshold
+	}
+	return 1
+}
+
+// forgetCPUStats remove pod cpu metrics when pod is cleaned
+func (a *PodActor) forgetCPUStats() {
+	pod := a.pod
+	podCPUThrottledSeconds.DeleteLabelValues(pod.Pod.Namespace, pod.Pod.Name)
+	podCPUThrottledRatio.DeleteLabelValues(pod.Pod.Namespace, pod.Pod.Name)
+	if pod.CPUStats != nil && pod.CPUStats.ThrottlingHigh {
+		nodeCPUThrottlingHighPods.Dec()
+		pod.CPUStats.ThrottlingHigh = false
+	}
+}
+
+func getPodThrottlingCondition(fppod *types.FornaxPod) *v1.PodCondition {
+	if fppod.CPUStats == nil {
+		return nil
+	}
+	condition := &v1.PodCondition{
+		Type:          PodThrottlingHigh,
+		Status:        v1.ConditionFalse,
+		LastProbeTime: metav1.Time{Time: fppod.CPUStats.SampleTime},
+	}
+	if fppod.CPUStats.ThrottlingHigh {
+		condition.Status = v1.ConditionTrue
+		condition.Reason = "CPUThrottled"
+		condition.Message = "pod sessions are cpu throttled more than threshold, consider to increase cpu limit"
+	}
+	return condition
+}
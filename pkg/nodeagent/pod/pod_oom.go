@@ -0,0 +1,153 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	internal "centaurusinfra.io/fornax-serverless/pkg/nodeagent/message"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/runtime"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+
+	libcontainercgroups "github.com/opencontainers/runc/libcontainer/cgroups"
+	"k8s.io/klog/v2"
+)
+
+const (
+	cgroupMountPoint = "/sys/fs/cgroup"
+)
+
+// readCgroupOOMKillCount read oom_kill counter of a cgroup, it's in memory.events on cgroup v2 and memory.oom_control on cgroup v1
+func readCgroupOOMKillCount(cgroupName string) (uint64, error) {
+	file := filepath.Join(cgroupMountPoint, "memory", cgroupName, "memory.oom_control")
+	if libcontainercgroups.IsCgroup2UnifiedMode() {
+		file = filepath.Join(cgroupMountPoint, cgroupName, "memory.events")
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "oom_kill" {
+			return strconv.ParseUint(fields[1], 10, 64)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("no oom_kill counter in %s", file)
+}
+
+// detectOOMKill check oom_kill counter of pod cgroup and exit reason of exited container, when a oom kill is found,
+// find victim process from cadvisor oom events, and close open sessions on pod with OOMKilled reason,
+// it's checked on every oom check tick whatever pod state is and when a container exit
+func (a *PodActor) detectOOMKill(exitedContainer *types.FornaxContainer) {
+	pod := a.pod
+	kills := uint64(0)
+	cgroupName := ""
+	if a.dependencies.QosManager != nil && pod.RuntimePod != nil {
+		cgroupName = a.dependencies.QosManager.GetPodCgroupParent(pod.Pod)
+		count, err := readCgroupOOMKillCount(cgroupName)
+		if err != nil {
+			klog.V(5).InfoS("Failed to read pod cgroup oom kill count", "pod", types.UniquePodName(pod), "err", err)
+		} else if count > pod.OOMKillCount {
+			kills = count - pod.OOMKillCount
+			pod.OOMKillCount = count
+		}
+	}
+	containerOOMKilled := exitedContainer != nil && runtime.ContainerOOMKilled(exitedContainer.ContainerStatus)
+	if kills == 0 && !containerOOMKilled {
+		return
+	}
+
+	victim := "unknown process"
+	if containerOOMKilled {
+		victim = fmt.Sprintf("container %s", exitedContainer.ContainerSpec.Name)
+		if kills == 0 {
+			kills = 1
+		}
+	}
+	if a.dependencies.CAdvisor != nil && cgroupName != "" {
+		events, err := a.dependencies.CAdvisor.GetOOMKillEvents(cgroupName, time.Now().Add(-2*oomCheckPeriod))
+		if err == nil && len(events) > 0 {
+			// use latest event, could miss some kills details if there are multiple kills
+			event := events[len(events)-1]
+			if event.EventData.OomKill != nil {
+				victim = fmt.Sprintf("process %s(pid %d) in %s", event.EventData.OomKill.ProcessName, event.EventData.OomKill.Pid, event.ContainerName)
+			}
+		}
+	}
+	klog.InfoS("Pod OOM killed", "pod", types.UniquePodName(pod), "kills", kills, "victim", victim)
+
+	for _, session := range pod.Sessions {
+		if util.SessionIsOpen(session.Session) {
+			a.closeSessionWithReason(session, fornaxv1.SessionReasonOOMKilled, fmt.Sprintf("%s was oom killed", victim))
+		}
+	}
+}
+
+// closeSessionWithReason close a session through session actor, like a close from fornaxcore, reason is kept in status,
+// and session is reported failed when it's closed, session without actor is failed directly
+func (a *PodActor) closeSessionWithReason(session *types.FornaxSession, reason, message string) {
+	sActor, found := a.sessionActors[session.Identifier]
+	if !found {
+		a.failSession(session, reason, message)
+		return
+	}
+	klog.InfoS("Close session", "pod", types.UniquePodName(a.pod), "session", session.Identifier, "requestId", util.RequestId(session.Session), "reason", reason, "message", message)
+	session.Session.Status.Reason = reason
+	session.Session.Status.Message = message
+	if err := sActor.CloseSession(); err != nil {
+		// session actor time out closing session if application can not be reached, then session is reported failed
+		klog.ErrorS(err, "Failed to close session", "pod", types.UniquePodName(a.pod), "session", session.Identifier)
+	}
+	a.notify(a.supervisor, internal.SessionStatusChange{Session: session, Pod: a.pod})
+}
+
+// failSession mark a session failed with reason, session actor is removed, so, late session state from session service is ignored,
+// it's only used when session can not be closed by session actor, e.g. session service is not available when session is opened
+func (a *PodActor) failSession(session *types.FornaxSession, reason, message string) {
+	klog.InfoS("Session failed", "pod", types.UniquePodName(a.pod), "session", session.Identifier, "requestId", util.RequestId(session.Session), "reason", reason, "message", message)
+	newStatus := session.Session.Status.DeepCopy()
+	newStatus.SessionStatus = fornaxv1.SessionStatusFailed
+	newStatus.Reason = reason
+	newStatus.Message = message
+	newStatus.CloseTime = util.NewCurrentMetaTime()
+	newStatus.QualityScore = a.sessionQualityScore(session)
+	a.finishSessionUsage(session)
+	session.Session.Status = *newStatus
+	session.ClientSessions = map[string]*types.ClientSession{}
+	delete(a.sessionActors, session.Identifier)
+	a.notify(a.supervisor, internal.SessionStatusChange{Session: session, Pod: a.pod})
+}
+
+// sessionClosedWithFailure return true if node agent closed session because of a failure, it's reported failed instead of closed
+func sessionClosedWithFailure(status *fornaxv1.ApplicationSessionStatus) bool {
+	return status.Reason == fornaxv1.SessionReasonOOMKilled
+}
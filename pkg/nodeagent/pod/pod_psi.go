@@ -0,0 +1,122 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
+
+	libcontainercgroups "github.com/opencontainers/runc/libcontainer/cgroups"
+	"k8s.io/klog/v2"
+)
+
+// readCgroupPressureTotal read total stall time in microseconds of "some" line in a cgroup v2 pressure file,
+// e.g. some avg10=0.00 avg60=0.00 avg300=0.00 total=12345
+func readCgroupPressureTotal(cgroupName, resource string) (uint64, error) {
+	file := filepath.Join(cgroupMountPoint, cgroupName, fmt.Sprintf("%s.pressure", resource))
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] != "some" {
+			continue
+		}
+		for _, field := range fields[1:] {
+			if strings.HasPrefix(field, "total=") {
+				return strconv.ParseUint(strings.TrimPrefix(field, "total="), 10, 64)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("no some total in %s", file)
+}
+
+// samplePressureStall read cpu, memory and io pressure of pod cgroup, PSI is only available on cgroup v2,
+// return nil if pressure can not be read
+func (a *PodActor) samplePressureStall() *types.FornaxSessionPressureStall {
+	if a.dependencies.QosManager == nil || a.pod.RuntimePod == nil || !libcontainercgroups.IsCgroup2UnifiedMode() {
+		return nil
+	}
+	cgroupName := a.dependencies.QosManager.GetPodCgroupParent(a.pod.Pod)
+	stall := &types.FornaxSessionPressureStall{SampleTime: time.Now()}
+	var err error
+	if stall.CPUStallTotal, err = readCgroupPressureTotal(cgroupName, "cpu"); err != nil {
+		klog.V(5).InfoS("Failed to read pod cgroup cpu pressure", "pod", types.UniquePodName(a.pod), "err", err)
+		return nil
+	}
+	if stall.MemoryStallTotal, err = readCgroupPressureTotal(cgroupName, "memory"); err != nil {
+		klog.V(5).InfoS("Failed to read pod cgroup memory pressure", "pod", types.UniquePodName(a.pod), "err", err)
+		return nil
+	}
+	if stall.IOStallTotal, err = readCgroupPressureTotal(cgroupName, "io"); err != nil {
+		klog.V(5).InfoS("Failed to read pod cgroup io pressure", "pod", types.UniquePodName(a.pod), "err", err)
+		return nil
+	}
+	return stall
+}
+
+func stallPercent(start, end uint64, elapsed time.Duration) int32 {
+	if end <= start || elapsed <= 0 {
+		return 0
+	}
+	percent := int32((end - start) * 100 / uint64(elapsed.Microseconds()))
+	if percent > 100 {
+		return 100
+	}
+	return percent
+}
+
+// sessionQualityScore compare pod pressure stall at session close with the one sampled when session was opened,
+// score is 100 minus sum of cpu, memory and io stall percent during session, return nil if no pressure sampled
+func (a *PodActor) sessionQualityScore(session *types.FornaxSession) *fornaxv1.SessionQualityScore {
+	if session.PressureStall == nil {
+		return nil
+	}
+	stall := a.samplePressureStall()
+	if stall == nil {
+		return nil
+	}
+	elapsed := stall.SampleTime.Sub(session.PressureStall.SampleTime)
+	if elapsed.Microseconds() <= 0 {
+		return nil
+	}
+	score := &fornaxv1.SessionQualityScore{
+		CPUStallPercent:    stallPercent(session.PressureStall.CPUStallTotal, stall.CPUStallTotal, elapsed),
+		MemoryStallPercent: stallPercent(session.PressureStall.MemoryStallTotal, stall.MemoryStallTotal, elapsed),
+		IOStallPercent:     stallPercent(session.PressureStall.IOStallTotal, stall.IOStallTotal, elapsed),
+	}
+	score.Score = 100 - score.CPUStallPercent - score.MemoryStallPercent - score.IOStallPercent
+	if score.Score < 0 {
+		score.Score = 0
+	}
+	return score
+}
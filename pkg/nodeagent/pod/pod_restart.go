@@ -0,0 +1,170 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"time"
+
+	podcontainer "centaurusinfra.io/fornax-serverless/pkg/nodeagent/pod/container"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/runtime"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// restart backoff is reset if container ran longer than this before it exited
+	restartBackoffResetPeriod = 10 * time.Minute
+
+	ContainerRestartReasonFailed    = "ContainerFailed"
+	ContainerRestartReasonError     = "Error"
+	ContainerRestartReasonCompleted = "Completed"
+)
+
+// PodContainerRestart is sent by restart backoff timer to recreate a exited container
+type PodContainerRestart struct {
+	Name string
+}
+
+func (a *PodActor) containerRestartCount() int32 {
+	count := int32(0)
+	for _, v := range a.pod.Containers {
+		count += v.RestartCount
+	}
+	return count
+}
+
+// shouldRestartContainer check pod restart policy, a exited container of a pod being terminated is never restarted,
+// OnFailure restart a container unless it exited with zero code, a container failed probe or lost runtime status is also a failure
+func (a *PodActor) shouldRestartContainer(container *types.FornaxContainer) bool {
+	if a.stop || types.PodInTerminating(a.pod) || a.pod.FornaxPodState == types.PodStateFailed || a.pod.RuntimePod == nil {
+		return false
+	}
+	if container.State == types.ContainerStateStopping || container.State == types.ContainerStateTerminating {
+		return false
+	}
+	switch a.pod.Pod.Spec.RestartPolicy {
+	case v1.RestartPolicyAlways:
+		return true
+	case v1.RestartPolicyOnFailure:
+		return !runtime.ContainerExitNormal(container.ContainerStatus)
+	default:
+		return false
+	}
+}
+
+func lastTerminationStateOf(container *types.FornaxContainer) *v1.ContainerStateTerminated {
+	terminated := &v1.ContainerStateTerminated{
+		Reason:     ContainerRestartReasonFailed,
+		FinishedAt: metav1.Now(),
+	}
+	if container.RuntimeContainer != nil {
+		terminated.ContainerID = container.RuntimeContainer.Id
+	}
+	if container.ContainerStatus == nil || container.ContainerStatus.RuntimeStatus == nil {
+		return terminated
+	}
+	status := container.ContainerStatus.RuntimeStatus
+	if status.StartedAt != 0 {
+		terminated.StartedAt = metav1.NewTime(time.Unix(0, status.StartedAt))
+	}
+	if runtime.ContainerExit(container.ContainerStatus) {
+		terminated.ExitCode = status.ExitCode
+		terminated.FinishedAt = metav1.NewTime(time.Unix(0, status.FinishedAt))
+		terminated.Message = status.Message
+		if len(status.Reason) > 0 {
+			terminated.Reason = status.Reason
+		} else if status.ExitCode == 0 {
+			terminated.Reason = ContainerRestartReasonCompleted
+		} else {
+			terminated.Reason = ContainerRestartReasonError
+		}
+	}
+	return terminated
+}
+
+// nextRestartBackoff double backoff of container after each restart up to max, and reset it if container ran long enough
+func (a *PodActor) nextRestartBackoff(name string, terminated *v1.ContainerStateTerminated) time.Duration {
+	initial, max := util.PodRestartBackoff(a.pod.Pod)
+	backoff, found := a.restartBackoff[name]
+	if !found || (!terminated.StartedAt.IsZero() && terminated.FinishedAt.Sub(terminated.StartedAt.Time) > restartBackoffResetPeriod) {
+		backoff = initial
+	} else {
+		backoff = backoff * 2
+	}
+	if backoff > max {
+		backoff = max
+	}
+	a.restartBackoff[name] = backoff
+	return backoff
+}
+
+// scheduleContainerRestart record how container exited and restart it after backoff, pod stay in its state,
+// container status is reset so a exited container does not make pod failed while it's waiting for restart
+func (a *PodActor) scheduleContainerRestart(container *types.FornaxContainer) error {
+	name := container.ContainerSpec.Name
+	terminated := lastTerminationStateOf(container)
+	backoff := a.nextRestartBackoff(name, terminated)
+	klog.InfoS("Restart pod container after backoff", "pod", types.UniquePodName(a.pod), "container", name, "policy", a.pod.Pod.Spec.RestartPolicy, "reason", terminated.Reason, "exit code", terminated.ExitCode, "restart count", container.RestartCount+1, "backoff", backoff)
+
+	container.LastTerminationState = terminated
+	container.RestartCount += 1
+	container.State = types.ContainerStateTerminated
+	container.ContainerStatus = &runtime.ContainerStatus{}
+	time.AfterFunc(backoff, func() {
+		if !a.stop {
+			a.notify(a.Reference(), PodContainerRestart{Name: name})
+		}
+	})
+	return nil
+}
+
+// restartContainer remove exited runtime container and create a new one from same spec in pod sandbox,
+// a container which failed to be recreated is scheduled to restart again with a longer backoff
+func (a *PodActor) restartContainer(name string) error {
+	container, found := a.pod.Containers[name]
+	if !found || !a.shouldRestartContainer(container) {
+		return nil
+	}
+	if _, found := a.containerActors[name]; found {
+		return nil
+	}
+
+	klog.InfoS("Restart pod container", "pod", types.UniquePodName(a.pod), "container", name, "restart count", container.RestartCount)
+	if container.RuntimeContainer != nil {
+		if err := a.terminateContainer(container); err != nil {
+			klog.ErrorS(err, "Failed to remove exited container, retry restart", "pod", types.UniquePodName(a.pod), "container", name)
+			return a.scheduleContainerRestart(container)
+		}
+	}
+	runtimeContainer, err := a.createContainer(a.pod.RuntimePod.SandboxConfig, container.ContainerSpec, []*v1.Secret{})
+	if err != nil {
+		klog.ErrorS(err, "Failed to recreate container, retry restart", "pod", types.UniquePodName(a.pod), "container", name)
+		return a.scheduleContainerRestart(container)
+	}
+	container.RuntimeContainer = runtimeContainer
+	container.State = types.ContainerStateCreating
+	container.ContainerStatus = &runtime.ContainerStatus{}
+	a.pod.RuntimePod.Containers[name] = runtimeContainer.Container
+
+	containerActor := podcontainer.NewPodContainerActor(a.Reference(), a.pod, container, a.dependencies)
+	a.containerActors[name] = containerActor
+	containerActor.Start()
+	return nil
+}
@@ -25,8 +25,10 @@ import (
 	"k8s.io/klog/v2"
 
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/config"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/network"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/runtime"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
 	"k8s.io/kubernetes/pkg/kubelet/util/format"
 	netutils "k8s.io/utils/net"
 )
@@ -65,6 +67,25 @@ func (a *PodActor) createPodSandbox() (*runtime.Pod, error) {
 	return runtimepod, nil
 }
 
+// applyNetworkPolicy program application network policy in sandbox network namespace, pod using host network is not restricted
+func (a *PodActor) applyNetworkPolicy(runtimePod *runtime.Pod) error {
+	pod := a.pod.Pod
+	policy := util.PodNetworkPolicy(pod)
+	if policy == nil {
+		return nil
+	}
+	if pod.Spec.HostNetwork {
+		klog.InfoS("Skip network policy of pod using host network", "pod", types.UniquePodName(a.pod))
+		return nil
+	}
+	netns, err := a.dependencies.RuntimeService.GetPodSandboxNetNS(runtimePod.Id)
+	if err != nil {
+		return err
+	}
+	klog.InfoS("Apply network policy", "pod", types.UniquePodName(a.pod), "netns", netns, "ingress rules", len(policy.Ingress), "egress rules", len(policy.Egress))
+	return network.ApplyNetworkPolicy(netns, policy, runtimePod.IPs)
+}
+
 func (a *PodActor) removePodSandbox(podSandboxId string, podSandboxConfig *criv1.PodSandboxConfig) error {
 	var err error
 
@@ -0,0 +1,95 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"sort"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func metaTimeOf(t *time.Time) *metav1.Time {
+	if t == nil {
+		return nil
+	}
+	return &metav1.Time{Time: t.Truncate(time.Second)}
+}
+
+// mergeClientSessions merge clients reported by container with clients in session status,
+// container report either all clients with leave time or only attached clients, a attached client not reported anymore is considered left,
+// attached clients are sorted by join time, most recent left clients are kept up to MaxLeftClientSessions
+func mergeClientSessions(session *types.FornaxSession, reported []types.ClientSession) ([]fornaxv1.ClientSessionReference, int32) {
+	now := time.Now()
+	previous := map[string]fornaxv1.ClientSessionReference{}
+	for _, v := range session.Session.Status.ClientSessions {
+		previous[v.Name] = v
+	}
+
+	attached, left := []fornaxv1.ClientSessionReference{}, []fornaxv1.ClientSessionReference{}
+	clients := map[string]*types.ClientSession{}
+	for i := range reported {
+		client := reported[i]
+		prev, found := previous[client.Identifier]
+		delete(previous, client.Identifier)
+		ref := fornaxv1.ClientSessionReference{
+			Name:          client.Identifier,
+			SourceAddress: client.SourceAddress,
+			JoinTime:      metaTimeOf(client.JoinTime),
+			LeaveTime:     metaTimeOf(client.LeaveTime),
+		}
+		if ref.JoinTime == nil {
+			if found && prev.JoinTime != nil {
+				ref.JoinTime = prev.JoinTime
+			} else {
+				ref.JoinTime = metaTimeOf(&now)
+			}
+		}
+		if len(ref.SourceAddress) == 0 && found {
+			ref.SourceAddress = prev.SourceAddress
+		}
+		if ref.LeaveTime == nil {
+			attached = append(attached, ref)
+			clients[client.Identifier] = &client
+		} else {
+			left = append(left, ref)
+		}
+	}
+	for _, v := range previous {
+		if v.LeaveTime == nil {
+			v.LeaveTime = metaTimeOf(&now)
+		}
+		left = append(left, v)
+	}
+	session.ClientSessions = clients
+
+	sort.SliceStable(attached, func(i, j int) bool {
+		return attached[i].JoinTime.Before(attached[j].JoinTime)
+	})
+	sort.SliceStable(left, func(i, j int) bool {
+		if !left[i].LeaveTime.Equal(left[j].LeaveTime) {
+			return left[j].LeaveTime.Before(left[i].LeaveTime)
+		}
+		return left[i].Name < left[j].Name
+	})
+	if len(left) > fornaxv1.MaxLeftClientSessions {
+		left = left[:fornaxv1.MaxLeftClientSessions]
+	}
+	return append(attached, left...), int32(len(attached))
+}
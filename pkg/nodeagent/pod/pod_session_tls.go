@@ -0,0 +1,63 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package pod
+
+import (
+	"path/filepath"
+
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/config"
+	sessiongrpc "centaurusinfra.io/fornax-serverless/pkg/nodeagent/sessionservice/grpc"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+
+	"k8s.io/apimachinery/pkg/types"
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// pod certificate of session service is kept in pod data dir, so, it's removed with pod data dir
+func sessionServiceTLSDir(rootPath string, podUID types.UID) string {
+	return filepath.Join(config.GetPodDir(rootPath, podUID), "session-tls")
+}
+
+func (a *PodActor) usingSessionServiceTLS() bool {
+	return a.dependencies.SessionServiceTLS != nil && util.PodHasSessionServiceAnnotation(a.pod.Pod)
+}
+
+// issueSessionServiceCertificate write client certificate of a session service pod into its tls dir, it's mounted into containers
+func (a *PodActor) issueSessionServiceCertificate() error {
+	if !a.usingSessionServiceTLS() {
+		return nil
+	}
+	return a.dependencies.SessionServiceTLS.IssuePodCertificate(a.pod.Identifier, sessionServiceTLSDir(a.nodeConfig.RootPath, a.pod.Pod.UID))
+}
+
+func (a *PodActor) forgetSessionServiceCertificate() {
+	if a.dependencies.SessionServiceTLS != nil {
+		a.dependencies.SessionServiceTLS.ForgetPod(a.pod.Identifier)
+	}
+}
+
+// setSessionServiceTLS mount pod tls dir read only and tell session service client where it is
+func (a *PodActor) setSessionServiceTLS(containerConfig *criv1.ContainerConfig) {
+	if !a.usingSessionServiceTLS() {
+		return
+	}
+	containerConfig.Mounts = append(containerConfig.Mounts, &criv1.Mount{
+		ContainerPath: sessiongrpc.SessionServiceTLSContainerDir,
+		HostPath:      sessionServiceTLSDir(a.nodeConfig.RootPath, a.pod.Pod.UID),
+		Readonly:      true,
+	})
+	containerConfig.Envs = append(containerConfig.Envs, &criv1.KeyValue{Key: sessiongrpc.SessionServiceTLSDirEnv, Value: sessiongrpc.SessionServiceTLSContainerDir})
+}
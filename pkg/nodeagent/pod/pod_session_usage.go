@@ -0,0 +1,98 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"time"
+
+	internal "centaurusinfra.io/fornax-serverless/pkg/nodeagent/message"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// usage of a long session is reported every period, so it can be billed before session is closed
+	DefaultSessionUsageReportPeriod = 60 * time.Second
+)
+
+// collectSessionUsage read cpu and memory usage of pod cgroup and share usage since last sample evenly by open sessions of pod,
+// closing session is counted as open, it's used to sample a session right before it's closed
+func (a *PodActor) collectSessionUsage(closing *types.FornaxSession) {
+	pod := a.pod
+	if a.dependencies.CAdvisor == nil || a.dependencies.QosManager == nil || pod.RuntimePod == nil {
+		return
+	}
+	stats, err := a.dependencies.CAdvisor.GetContainerStats(a.dependencies.QosManager.GetPodCgroupParent(pod.Pod))
+	if err != nil {
+		klog.V(5).InfoS("Failed to get pod cgroup stats", "pod", types.UniquePodName(pod), "err", err)
+		return
+	}
+	if stats.Cpu == nil || stats.Memory == nil {
+		return
+	}
+
+	now := time.Now()
+	sample := &types.FornaxPodUsageSample{CPUUsageTotal: stats.Cpu.Usage.Total, SampleTime: now}
+	prev := pod.UsageSample
+	pod.UsageSample = sample
+
+	sessions := []*types.FornaxSession{}
+	for _, v := range pod.Sessions {
+		if v == closing || util.SessionIsOpen(v.Session) {
+			if v.Usage == nil {
+				// session restored from store before usage was recorded
+				v.Usage = &types.FornaxSessionUsage{StartTime: now}
+			}
+			sessions = append(sessions, v)
+		}
+	}
+	if prev == nil || len(sessions) == 0 || sample.CPUUsageTotal < prev.CPUUsageTotal {
+		// cgroup counters restarted, e.g. pod cgroup was recreated, skip this interval
+		return
+	}
+
+	n := uint64(len(sessions))
+	elapsed := now.Sub(prev.SampleTime)
+	cpu := (sample.CPUUsageTotal - prev.CPUUsageTotal) / n
+	memory := uint64(float64(stats.Memory.WorkingSet) * elapsed.Seconds() / float64(n))
+	for _, v := range sessions {
+		v.Usage.CPUUsageNanoSeconds += cpu
+		v.Usage.MemoryByteSeconds += memory
+		if stats.Memory.WorkingSet > v.Usage.MemoryPeakBytes {
+			v.Usage.MemoryPeakBytes = stats.Memory.WorkingSet
+		}
+		if v != closing && now.Sub(v.Usage.StartTime) >= DefaultSessionUsageReportPeriod {
+			a.reportSessionUsage(v, now, false)
+		}
+	}
+}
+
+func (a *PodActor) reportSessionUsage(session *types.FornaxSession, endTime time.Time, final bool) {
+	a.notify(a.supervisor, internal.SessionUsageReport{Session: session, Usage: *session.Usage, EndTime: endTime, Final: final})
+	session.Usage = &types.FornaxSessionUsage{StartTime: endTime}
+}
+
+// finishSessionUsage sample usage once more and report last usage of a session being closed on pod
+func (a *PodActor) finishSessionUsage(session *types.FornaxSession) {
+	if session.Usage == nil {
+		return
+	}
+	a.collectSessionUsage(session)
+	a.reportSessionUsage(session, time.Now(), true)
+	session.Usage = nil
+}
@@ -22,6 +22,7 @@ import (
 
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/runtime"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -58,11 +59,22 @@ func SetPodStatus(fppod *types.FornaxPod, node *v1.Node) {
 	}
 	podStatus.Conditions = GetPodConditions(fppod)
 
+	// container status
+	podStatus.InitContainerStatuses = GetContainerStatuses(fppod, fppod.Pod.Spec.InitContainers)
+	podStatus.ContainerStatuses = GetContainerStatuses(fppod, fppod.Pod.Spec.Containers)
+
 	// pod ip
 	if fppod.RuntimePod != nil && len(fppod.RuntimePod.IPs) > 0 {
 		podStatus.PodIP = fppod.RuntimePod.IPs[0]
+		// a dual stack pod get a ipv4 and a ipv6 address from cni, pod ips have at most one address of each family
 		podIps := []v1.PodIP{}
+		families := map[v1.IPFamily]bool{}
 		for _, v := range fppod.RuntimePod.IPs {
+			family := util.IPFamilyOf(v)
+			if families[family] {
+				continue
+			}
+			families[family] = true
 			podIps = append(podIps, v1.PodIP{
 				IP: v,
 			})
@@ -82,6 +94,31 @@ func SetPodStatus(fppod *types.FornaxPod, node *v1.Node) {
 	fppod.Pod.Status = *podStatus
 }
 
+// GetContainerStatuses return status of containers in spec order, restart count and last termination state come from restart policy
+func GetContainerStatuses(fppod *types.FornaxPod, containers []v1.Container) []v1.ContainerStatus {
+	statuses := []v1.ContainerStatus{}
+	for _, spec := range containers {
+		container, found := fppod.Containers[spec.Name]
+		if !found {
+			continue
+		}
+		status := v1.ContainerStatus{
+			Name:         spec.Name,
+			Image:        spec.Image,
+			Ready:        container.State == types.ContainerStateRunning || container.State == types.ContainerStateHibernated,
+			RestartCount: container.RestartCount,
+		}
+		if container.RuntimeContainer != nil {
+			status.ContainerID = container.RuntimeContainer.Id
+		}
+		if container.LastTerminationState != nil {
+			status.LastTerminationState = v1.ContainerState{Terminated: container.LastTerminationState.DeepCopy()}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
 func ToV1PodPhase(fppod *types.FornaxPod) v1.PodPhase {
 	var podPhase v1.PodPhase
 
@@ -227,6 +264,10 @@ func GetPodConditions(fppod *types.FornaxPod) []v1.PodCondition {
 		podReadyCondition.Reason = "some pod containers are not running"
 	}
 
+	if throttlingCondition := getPodThrottlingCondition(fppod); throttlingCondition != nil {
+		conditions[PodThrottlingHigh] = throttlingCondition
+	}
+
 	// merg old condition with new condtion and delete merged new condition
 	for _, oldCondition := range fppod.Pod.Status.Conditions {
 		newCondtion, found := conditions[oldCondition.Type]
@@ -0,0 +1,55 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"fmt"
+	"os"
+
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/config"
+	v1 "k8s.io/api/core/v1"
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// makePersistentVolumeMounts bind mount node local directories of pod persistent volume claims into container,
+// directory is created on first use and kept after pod is deleted, so next pod of same instance identity on this node get its data back,
+// other volume types are not supported yet and ignored
+func makePersistentVolumeMounts(rootPath string, pod *v1.Pod, container *v1.Container) ([]*criv1.Mount, error) {
+	claims := map[string]string{}
+	for _, v := range pod.Spec.Volumes {
+		if v.PersistentVolumeClaim != nil {
+			claims[v.Name] = v.PersistentVolumeClaim.ClaimName
+		}
+	}
+	mounts := []*criv1.Mount{}
+	for _, m := range container.VolumeMounts {
+		claimName, found := claims[m.Name]
+		if !found {
+			continue
+		}
+		dir := config.GetPersistentVolumeDir(rootPath, pod.Namespace, claimName)
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return nil, fmt.Errorf("create persistent volume %s of pod failed: %v", claimName, err)
+		}
+		mounts = append(mounts, &criv1.Mount{
+			HostPath:      dir,
+			ContainerPath: m.MountPath,
+			Readonly:      m.ReadOnly,
+		})
+	}
+	return mounts, nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"fmt"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	podcontainer "centaurusinfra.io/fornax-serverless/pkg/nodeagent/pod/container"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultWarmUpTimeoutSeconds = 60
+	DefaultWarmUpMaxAttempts    = 3
+
+	// wait between two failed warm up attempts
+	warmUpRetryPeriod = 5 * time.Second
+
+	PodReasonWarmUpFailed = "WarmUpFailed"
+)
+
+// PodWarmUpResult is sent by warm up goroutine when warm up succeeded or all attempts failed
+type PodWarmUpResult struct {
+	Container *types.FornaxContainer
+	Err       error
+}
+
+func warmUpContainer(pod *types.FornaxPod, warmUp *fornaxv1.ApplicationWarmUp) (*types.FornaxContainer, error) {
+	name := warmUp.Container
+	if len(name) == 0 {
+		if len(pod.Pod.Spec.Containers) == 0 {
+			return nil, fmt.Errorf("pod does not have container to run warm up")
+		}
+		name = pod.Pod.Spec.Containers[0].Name
+	}
+	container, found := pod.Containers[name]
+	if !found || container.RuntimeContainer == nil {
+		return nil, fmt.Errorf("warm up container %s not found", name)
+	}
+	return container, nil
+}
+
+// startWarmUp run pod warm up in a goroutine after all containers are ready, pod stay in created state until result come back,
+// a pod recovered after node agent restart redo warm up when its containers are ready again
+func (a *PodActor) startWarmUp(warmUp *fornaxv1.ApplicationWarmUp) error {
+	if a.warmingUp {
+		return nil
+	}
+	container, err := warmUpContainer(a.pod, warmUp)
+	if err != nil {
+		return a.onPodWarmUpResult(PodWarmUpResult{Container: container, Err: err})
+	}
+	timeout := time.Duration(warmUp.TimeoutSeconds) * time.Second
+	if timeout == 0 {
+		timeout = DefaultWarmUpTimeoutSeconds * time.Second
+	}
+	maxAttempts := int(warmUp.MaxAttempts)
+	if maxAttempts == 0 {
+		maxAttempts = DefaultWarmUpMaxAttempts
+	}
+
+	a.warmingUp = true
+	pod := a.pod
+	handler := warmUp.Handler.DeepCopy()
+	runtimeService := a.dependencies.RuntimeService
+	klog.InfoS("Warm up pod", "pod", types.UniquePodName(pod), "container", container.ContainerSpec.Name, "timeout", timeout, "max attempts", maxAttempts)
+	go func() {
+		var err error
+		for attempt := 1; attempt <= maxAttempts && !a.stop; attempt++ {
+			var msg string
+			if msg, err = podcontainer.RunWarmUpHandler(runtimeService, pod, container, handler, timeout); err == nil {
+				break
+			}
+			klog.ErrorS(err, "Pod warm up attempt failed", "pod", types.UniquePodName(pod), "attempt", attempt, "errMsg", msg)
+			if attempt < maxAttempts {
+				time.Sleep(warmUpRetryPeriod)
+			}
+		}
+		if !a.stop {
+			a.notify(a.Reference(), PodWarmUpResult{Container: container, Err: err})
+		}
+	}()
+	return nil
+}
+
+// onPodWarmUpResult set pod running when warm up succeeded, pod is terminated as failed if warm up failed,
+// result is ignored if pod was requested to terminate while warm up was running
+func (a *PodActor) onPodWarmUpResult(msg PodWarmUpResult) error {
+	a.warmingUp = false
+	if a.pod.FornaxPodState != types.PodStateCreated {
+		return nil
+	}
+	if msg.Err != nil {
+		klog.ErrorS(msg.Err, "Pod warm up failed", "pod", types.UniquePodName(a.pod))
+		a.pod.Pod.Status.Reason = PodReasonWarmUpFailed
+		a.pod.Pod.Status.Message = msg.Err.Error()
+		return a.terminate(true)
+	}
+	klog.InfoS("Pod warmed up", "pod", types.UniquePodName(a.pod))
+	a.setPodRunning(msg.Container)
+	return nil
+}
+
+// podNeedWarmUp tell if pod has a warm up which has not run yet, a running pod is already warmed up
+func (a *PodActor) podNeedWarmUp() (*fornaxv1.ApplicationWarmUp, bool) {
+	if a.pod.FornaxPodState != types.PodStateCreated {
+		return nil, false
+	}
+	warmUp := util.PodWarmUp(a.pod.Pod)
+	return warmUp, warmUp != nil
+}
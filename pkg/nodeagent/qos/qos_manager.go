@@ -130,9 +130,15 @@ func NewQoSManager(
 	if err != nil {
 		return nil, fmt.Errorf("failed to start kubelet container manager for qos management, %v", err)
 	}
+	podCgroupManager := cm.NewPodContainerManager()
+	// pids limit annotated on pod override node pod pids limit, a noop manager is returned when cgroups per qos is disabled
+	if impl, ok := podCgroupManager.(*kubeletcm.PodContainerManagerImpl); ok {
+		impl.PodPidsLimitFunc = util.PodPidsLimit
+		impl.PodUnifiedResourcesFunc = podUnifiedResources(nodeConfig)
+	}
 	return &QoSManagerImpl{
 		KubeletCM:        cm,
-		PodCgroupManager: cm.NewPodContainerManager(),
+		PodCgroupManager: podCgroupManager,
 	}, nil
 }
 
@@ -0,0 +1,74 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qos
+
+import (
+	"strconv"
+
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/config"
+	v1 "k8s.io/api/core/v1"
+	v1qos "k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
+)
+
+const (
+	// CPUWeight is cpu.weight for cgroup v2
+	CPUWeight string = "cpu.weight"
+)
+
+// CPUSharesToWeight convert cgroup v1 cpu shares [2, 262144] to cgroup v2 cpu weight [1, 10000], same as runc
+func CPUSharesToWeight(shares uint64) uint64 {
+	if shares < 2 {
+		shares = 2
+	}
+	return 1 + ((shares-2)*9999)/262142
+}
+
+// BurstableMemoryHigh return memory.high of a burstable pod or container from its memory limit, it throttle and reclaim memory
+// before reaching limit, so a burstable session is slowed instead of oom killed, guaranteed pods are only limited by memory.max,
+// 0 means memory.high is not set
+func BurstableMemoryHigh(pod *v1.Pod, memoryRequest, memoryLimit int64) int64 {
+	if memoryLimit == 0 || v1qos.GetPodQOS(pod) != v1.PodQOSBurstable {
+		return 0
+	}
+	memoryHigh := int64(float64(memoryLimit) * config.DefaultMemoryThrottlingFactor)
+	if memoryHigh <= memoryRequest {
+		return 0
+	}
+	return memoryHigh
+}
+
+// podUnifiedResources return cgroup v2 parameters of pod cgroup in addition to what kubelet cgroup manager set,
+// memory.min and cpu.weight are already set from pod requests, memory.high is set if every container has memory limit
+func podUnifiedResources(nodeConfig config.NodeConfiguration) func(pod *v1.Pod) map[string]string {
+	return func(pod *v1.Pod) map[string]string {
+		if !nodeConfig.MemoryQoS {
+			return nil
+		}
+		memoryRequest, memoryLimit := int64(0), int64(0)
+		for _, v := range pod.Spec.Containers {
+			if v.Resources.Limits.Memory().IsZero() {
+				return nil
+			}
+			memoryRequest += v.Resources.Requests.Memory().Value()
+			memoryLimit += v.Resources.Limits.Memory().Value()
+		}
+		if memoryHigh := BurstableMemoryHigh(pod, memoryRequest, memoryLimit); memoryHigh > 0 {
+			return map[string]string{MemoryHigh: strconv.FormatInt(memoryHigh, 10)}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxerrors"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+const (
+	nvidiaDeviceDir = "/dev"
+
+	// NvidiaVisibleDevices tell nvidia container runtime which gpus are visible in container
+	NvidiaVisibleDevices = "NVIDIA_VISIBLE_DEVICES"
+)
+
+var (
+	nvidiaGPUDevicePattern = regexp.MustCompile(`^nvidia([0-9]+)$`)
+
+	// control devices every gpu container need besides its gpu devices
+	nvidiaControlDevices = []string{"nvidiactl", "nvidia-uvm", "nvidia-uvm-tools", "nvidia-modeset"}
+)
+
+// GPUDevice is a device node mounted into a container
+type GPUDevice struct {
+	ID       string
+	HostPath string
+}
+
+// GPUManager is a built in device plugin of nvidia gpus, it find gpu device nodes on host, and allocate whole gpus to containers,
+// allocation is annotated on pod, so, it's recovered from pods after node agent restart
+type GPUManager struct {
+	mu             sync.Mutex
+	devices        []GPUDevice
+	controlDevices []string
+	// device id to uid of pod using it
+	allocated map[string]types.UID
+}
+
+func NewGPUManager(disabled bool) *GPUManager {
+	manager := &GPUManager{
+		mu:             sync.Mutex{},
+		devices:        []GPUDevice{},
+		controlDevices: []string{},
+		allocated:      map[string]types.UID{},
+	}
+	if disabled {
+		return manager
+	}
+	manager.devices, manager.controlDevices = detectNvidiaDevices(nvidiaDeviceDir)
+	if len(manager.devices) > 0 {
+		klog.InfoS("Detected nvidia gpus", "gpus", len(manager.devices), "control devices", manager.controlDevices)
+	}
+	return manager
+}
+
+func detectNvidiaDevices(dir string) ([]GPUDevice, []string) {
+	devices := []GPUDevice{}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list devices", "dir", dir)
+		return devices, []string{}
+	}
+	for _, v := range entries {
+		if matches := nvidiaGPUDevicePattern.FindStringSubmatch(v.Name()); matches != nil {
+			devices = append(devices, GPUDevice{ID: matches[1], HostPath: filepath.Join(dir, v.Name())})
+		}
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		ii, _ := strconv.Atoi(devices[i].ID)
+		jj, _ := strconv.Atoi(devices[j].ID)
+		return ii < jj
+	})
+	controlDevices := []string{}
+	if len(devices) > 0 {
+		for _, v := range nvidiaControlDevices {
+			path := filepath.Join(dir, v)
+			if _, err := os.Stat(path); err == nil {
+				controlDevices = append(controlDevices, path)
+			}
+		}
+	}
+	return devices, controlDevices
+}
+
+// Capacity return number of gpus on node
+func (m *GPUManager) Capacity() resource.Quantity {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return *resource.NewQuantity(int64(len(m.devices)), resource.DecimalSI)
+}
+
+// Allocate pick free gpus for each container requesting gpu and annotate them on pod,
+// a pod already annotated keep its devices, e.g. allocation recovered after node agent restart
+func (m *GPUManager) Allocate(pod *v1.Pod) (map[string][]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if allocation := util.PodGPUDevices(pod); allocation != nil {
+		m.restoreNoLock(pod.UID, allocation)
+		return allocation, nil
+	}
+
+	requested := int64(0)
+	for _, v := range pod.Spec.Containers {
+		requested += util.ContainerGPUs(&v)
+	}
+	if requested == 0 {
+		return nil, nil
+	}
+	free := []string{}
+	for _, v := range m.devices {
+		if _, found := m.allocated[v.ID]; !found {
+			free = append(free, v.ID)
+		}
+	}
+	if int64(len(free)) < requested {
+		return nil, fornaxerrors.Errorf(fornaxerrors.NoCapacity, "pod request %d gpus, node has %d free gpus", requested, len(free))
+	}
+
+	allocation := map[string][]string{}
+	for _, v := range pod.Spec.Containers {
+		gpus := util.ContainerGPUs(&v)
+		if gpus == 0 {
+			continue
+		}
+		allocation[v.Name], free = free[:gpus], free[gpus:]
+		for _, id := range allocation[v.Name] {
+			m.allocated[id] = pod.UID
+		}
+	}
+	data, err := json.Marshal(allocation)
+	if err != nil {
+		m.deallocateNoLock(pod.UID)
+		return nil, err
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[fornaxv1.AnnotationFornaxCoreGPUDevices] = string(data)
+	klog.InfoS("Allocated gpus to pod", "pod", util.Name(pod), "gpus", allocation)
+	return allocation, nil
+}
+
+func (m *GPUManager) restoreNoLock(uid types.UID, allocation map[string][]string) {
+	for _, ids := range allocation {
+		for _, id := range ids {
+			m.allocated[id] = uid
+		}
+	}
+}
+
+// Restore mark gpus annotated on a recovered pod as allocated
+func (m *GPUManager) Restore(pod *v1.Pod) {
+	if allocation := util.PodGPUDevices(pod); allocation != nil {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.restoreNoLock(pod.UID, allocation)
+	}
+}
+
+// Deallocate free gpus of a pod
+func (m *GPUManager) Deallocate(pod *v1.Pod) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deallocateNoLock(pod.UID)
+}
+
+func (m *GPUManager) deallocateNoLock(podUID types.UID) {
+	for id, uid := range m.allocated {
+		if uid == podUID {
+			delete(m.allocated, id)
+		}
+	}
+}
+
+// ContainerDevices return device nodes mounted into a container, gpus allocated to container and control devices,
+// nil if container does not have gpu
+func (m *GPUManager) ContainerDevices(pod *v1.Pod, containerName string) ([]GPUDevice, error) {
+	ids := util.PodGPUDevices(pod)[containerName]
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	devices := []GPUDevice{}
+	for _, id := range ids {
+		found := false
+		for _, v := range m.devices {
+			if v.ID == id {
+				devices = append(devices, v)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("gpu %s allocated to container %s does not exist on node", id, containerName)
+		}
+	}
+	for _, v := range m.controlDevices {
+		devices = append(devices, GPUDevice{HostPath: v})
+	}
+	return devices, nil
+}
+
+// HasGPU tell if node has gpu
+func (m *GPUManager) HasGPU() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.devices) > 0
+}
@@ -20,6 +20,9 @@ import (
 	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
+// ContainerReasonOOMKilled is reason of container status set by cri runtime when container is oom killed
+const ContainerReasonOOMKilled = "OOMKilled"
+
 func ContainerExit(status *ContainerStatus) bool {
 	return status != nil && status.RuntimeStatus != nil && status.RuntimeStatus.FinishedAt != 0 && status.RuntimeStatus.State == criv1.ContainerState_CONTAINER_EXITED
 }
@@ -39,3 +42,8 @@ func ContainerStatusUnknown(status *ContainerStatus) bool {
 func ContainerRunning(status *ContainerStatus) bool {
 	return status != nil && status.RuntimeStatus != nil && status.RuntimeStatus.State == criv1.ContainerState_CONTAINER_RUNNING
 }
+
+// ContainerOOMKilled return true if container exited because it was killed by kernel oom killer
+func ContainerOOMKilled(status *ContainerStatus) bool {
+	return ContainerExit(status) && status.RuntimeStatus.Reason == ContainerReasonOOMKilled
+}
@@ -46,6 +46,11 @@ func (*FakeRuntimeService) GetPodSandbox(podSandboxID string) (*criv1.PodSandbox
 	panic("unimplemented")
 }
 
+// GetPodSandboxNetNS implements RuntimeService
+func (*FakeRuntimeService) GetPodSandboxNetNS(podSandboxID string) (string, error) {
+	panic("unimplemented")
+}
+
 // GetPodStatus implements RuntimeService
 func (*FakeRuntimeService) GetPodStatus(podSandboxID string, containerIDs []string) (*PodStatus, error) {
 	panic("unimplemented")
@@ -55,6 +60,21 @@ func (*FakeRuntimeService) ExecCommand(containerID string, cmd []string, timeout
 	panic("unimplemented")
 }
 
+// GetExecURL implements RuntimeService
+func (*FakeRuntimeService) GetExecURL(request *criv1.ExecRequest) (string, error) {
+	panic("unimplemented")
+}
+
+// GetAttachURL implements RuntimeService
+func (*FakeRuntimeService) GetAttachURL(request *criv1.AttachRequest) (string, error) {
+	panic("unimplemented")
+}
+
+// GetPortForwardURL implements RuntimeService
+func (*FakeRuntimeService) GetPortForwardURL(request *criv1.PortForwardRequest) (string, error) {
+	panic("unimplemented")
+}
+
 // CreateContainer implements RuntimeService
 func (*FakeRuntimeService) CreateContainer(podSandboxID string, containerConfig *criv1.ContainerConfig, podSandboxConfig *criv1.PodSandboxConfig) (*Container, error) {
 	panic("unimplemented")
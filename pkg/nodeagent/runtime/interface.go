@@ -35,6 +35,8 @@ type RuntimeService interface {
 
 	GetPodSandbox(podSandboxID string) (*criv1.PodSandbox, error)
 
+	GetPodSandboxNetNS(podSandboxID string) (string, error)
+
 	GetPodStatus(podSandboxID string, containerIDs []string) (*PodStatus, error)
 
 	GetContainerStatus(containerID string) (*ContainerStatus, error)
@@ -53,6 +55,13 @@ type RuntimeService interface {
 
 	ExecCommand(containerID string, cmd []string, timeout time.Duration) ([]byte, []byte, error)
 
+	// GetExecURL, GetAttachURL and GetPortForwardURL return url of runtime streaming server which serve a prepared stream
+	GetExecURL(request *criv1.ExecRequest) (string, error)
+
+	GetAttachURL(request *criv1.AttachRequest) (string, error)
+
+	GetPortForwardURL(request *criv1.PortForwardRequest) (string, error)
+
 	GetImageLabel() (string, error)
 
 	HibernateContainer(containerID string) error
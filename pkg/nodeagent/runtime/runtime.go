@@ -18,6 +18,7 @@ package runtime
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -395,6 +396,44 @@ func (r *remoteRuntimeManager) WakeupContainer(containerID string) error {
 	return err
 }
 
+// sandboxVerboseInfo is part of verbose sandbox info of containerd cri plugin used to find sandbox network namespace
+type sandboxVerboseInfo struct {
+	Pid         uint32 `json:"pid"`
+	RuntimeSpec struct {
+		Linux struct {
+			Namespaces []struct {
+				Type string `json:"type"`
+				Path string `json:"path"`
+			} `json:"namespaces"`
+		} `json:"linux"`
+	} `json:"runtimeSpec"`
+}
+
+// GetPodSandboxNetNS implements RuntimeService, return network namespace path of sandbox from its verbose status
+func (r *remoteRuntimeManager) GetPodSandboxNetNS(podSandboxID string) (string, error) {
+	response, err := r.runtimeService.PodSandboxStatus(podSandboxID, true)
+	if err != nil {
+		return "", err
+	}
+	data, found := response.GetInfo()["info"]
+	if !found {
+		return "", fmt.Errorf("runtime does not return verbose info of sandbox %s", podSandboxID)
+	}
+	info := sandboxVerboseInfo{}
+	if err := json.Unmarshal([]byte(data), &info); err != nil {
+		return "", err
+	}
+	for _, v := range info.RuntimeSpec.Linux.Namespaces {
+		if v.Type == "network" && len(v.Path) > 0 {
+			return v.Path, nil
+		}
+	}
+	if info.Pid > 0 {
+		return fmt.Sprintf("/proc/%d/ns/net", info.Pid), nil
+	}
+	return "", fmt.Errorf("can not find network namespace of sandbox %s", podSandboxID)
+}
+
 func (r *remoteRuntimeManager) getPodSandboxStatus(podSandboxID string) (*criv1.PodSandboxStatus, error) {
 	response, err := r.runtimeService.PodSandboxStatus(podSandboxID, false)
 	if err != nil {
@@ -454,3 +493,30 @@ func NewRemoteRuntimeService(endpoint string, connectionTimeout time.Duration) (
 func (r *remoteRuntimeManager) ExecCommand(containerID string, cmd []string, timeout time.Duration) ([]byte, []byte, error) {
 	return r.runtimeService.ExecSync(containerID, cmd, timeout)
 }
+
+// GetExecURL implements RuntimeService
+func (r *remoteRuntimeManager) GetExecURL(request *criv1.ExecRequest) (string, error) {
+	resp, err := r.runtimeService.Exec(request)
+	if err != nil {
+		return "", err
+	}
+	return resp.Url, nil
+}
+
+// GetAttachURL implements RuntimeService
+func (r *remoteRuntimeManager) GetAttachURL(request *criv1.AttachRequest) (string, error) {
+	resp, err := r.runtimeService.Attach(request)
+	if err != nil {
+		return "", err
+	}
+	return resp.Url, nil
+}
+
+// GetPortForwardURL implements RuntimeService
+func (r *remoteRuntimeManager) GetPortForwardURL(request *criv1.PortForwardRequest) (string, error) {
+	resp, err := r.runtimeService.PortForward(request)
+	if err != nil {
+		return "", err
+	}
+	return resp.Url, nil
+}
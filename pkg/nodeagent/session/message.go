@@ -20,7 +20,9 @@ import (
 	"encoding/json"
 
 	"centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc"
+	internal "centaurusinfra.io/fornax-serverless/pkg/nodeagent/message"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"k8s.io/klog/v2"
 )
 
@@ -66,3 +68,22 @@ func BuildFornaxcoreGrpcSessionState(revision int64, session *types.FornaxSessio
 		},
 	}
 }
+
+func BuildFornaxcoreGrpcSessionUsage(usage internal.SessionUsageReport) *grpc.FornaxCoreMessage {
+	messageType := grpc.MessageType_SESSION_USAGE
+	return &grpc.FornaxCoreMessage{
+		MessageType: messageType,
+		MessageBody: &grpc.FornaxCoreMessage_SessionUsage{
+			SessionUsage: &grpc.SessionUsage{
+				SessionIdentifier:   usage.Session.Identifier,
+				PodIdentifier:       usage.Session.PodIdentifier,
+				StartTime:           timestamppb.New(usage.Usage.StartTime),
+				EndTime:             timestamppb.New(usage.EndTime),
+				CpuUsageNanoSeconds: usage.Usage.CPUUsageNanoSeconds,
+				MemoryByteSeconds:   usage.Usage.MemoryByteSeconds,
+				MemoryPeakBytes:     usage.Usage.MemoryPeakBytes,
+				Final:               usage.Final,
+			},
+		},
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package session
 
 import (
+	"sync"
 	"time"
 
 	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
@@ -25,10 +26,29 @@ import (
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/sessionservice"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
 	"centaurusinfra.io/fornax-serverless/pkg/util"
+	"k8s.io/klog/v2"
 )
 
+type SessionActorPhase string
+
+const (
+	SessionActorPhaseOpening SessionActorPhase = "Opening"
+	SessionActorPhaseOpen    SessionActorPhase = "Open"
+	SessionActorPhaseClosing SessionActorPhase = "Closing"
+	SessionActorPhaseClosed  SessionActorPhase = "Closed"
+	SessionActorPhaseTimeout SessionActorPhase = "Timeout"
+)
+
+// SessionActor track a session on pod through Opening, Open, Closing to Closed, Opening and Closing have a timer,
+// if session service does not report session open or closed before timer fire, actor move to Timeout and send a timeout state to supervisor,
+// states reported after Closed or Timeout are dropped
 type SessionActor struct {
+	mu             sync.Mutex
 	stop           bool
+	phase          SessionActorPhase
+	timer          *time.Timer
+	warningTimer   *time.Timer
+	expiryTimer    *time.Timer
 	pod            *types.FornaxPod
 	session        *types.FornaxSession
 	sessionService sessionservice.SessionService
@@ -37,10 +57,31 @@ type SessionActor struct {
 
 const (
 	DefaultCloseSessionGraceSeconds = uint16(120)
+	DefaultOpenSessionTimeout       = 10 * time.Second
+
+	// added to open timeout and close grace period, give session service time to report state before actor give up
+	DefaultSessionTimeoutMargin = 10 * time.Second
 )
 
+// sessionActorPhase derive phase from session status, used when actor is recovered after node agent restart
+func sessionActorPhase(session *fornaxv1.ApplicationSession) SessionActorPhase {
+	switch session.Status.SessionStatus {
+	case fornaxv1.SessionStatusAvailable, fornaxv1.SessionStatusInUse:
+		return SessionActorPhaseOpen
+	case fornaxv1.SessionStatusClosing:
+		return SessionActorPhaseClosing
+	case fornaxv1.SessionStatusClosed, fornaxv1.SessionStatusFailed:
+		return SessionActorPhaseClosed
+	case fornaxv1.SessionStatusTimeout:
+		return SessionActorPhaseTimeout
+	default:
+		return SessionActorPhaseOpening
+	}
+}
+
 func NewSessionActor(pod *types.FornaxPod, session *types.FornaxSession, sessionService sessionservice.SessionService, supervisor message.ActorRef) *SessionActor {
 	actor := &SessionActor{
+		phase:          sessionActorPhase(session.Session),
 		pod:            pod,
 		session:        session,
 		sessionService: sessionService,
@@ -49,8 +90,82 @@ func NewSessionActor(pod *types.FornaxPod, session *types.FornaxSession, session
 	return actor
 }
 
+func (a *SessionActor) Phase() SessionActorPhase {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.phase
+}
+
+func (a *SessionActor) openTimeout() time.Duration {
+	if a.session.Session.Spec.OpenTimeoutSeconds > 0 {
+		return time.Duration(a.session.Session.Spec.OpenTimeoutSeconds)*time.Second + DefaultSessionTimeoutMargin
+	}
+	return DefaultOpenSessionTimeout + DefaultSessionTimeoutMargin
+}
+
+func (a *SessionActor) closeGraceSeconds() uint16 {
+	if a.session.Session.Spec.CloseGracePeriodSeconds != nil {
+		return *a.session.Session.Spec.CloseGracePeriodSeconds
+	}
+	return DefaultCloseSessionGraceSeconds
+}
+
+// transitNoLock move actor to new phase and replace timer of old phase with timer of new phase if new phase has a timeout
+func (a *SessionActor) transitNoLock(phase SessionActorPhase, timeout time.Duration) {
+	if a.timer != nil {
+		a.timer.Stop()
+		a.timer = nil
+	}
+	a.phase = phase
+	if timeout > 0 {
+		a.timer = time.AfterFunc(timeout, func() { a.onTimeout(phase) })
+	}
+}
+
+func (a *SessionActor) transit(phase SessionActorPhase, timeout time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.transitNoLock(phase, timeout)
+}
+
+// onTimeout is called by timer of a phase, it's ignored if actor already left this phase
+func (a *SessionActor) onTimeout(phase SessionActorPhase) {
+	a.mu.Lock()
+	if a.phase != phase {
+		a.mu.Unlock()
+		return
+	}
+	a.transitNoLock(SessionActorPhaseTimeout, 0)
+	a.stopTimeLimitNoLock()
+	a.mu.Unlock()
+
+	klog.InfoS("Session did not report state in time", "session", a.session.Identifier, "requestId", util.RequestId(a.session.Session), "phase", phase)
+	if phase == SessionActorPhaseOpening {
+		// best effort to close session if application is still opening it
+		a.sessionService.CloseSession(a.pod, a.session, 0)
+	}
+	message.Send(nil, a.supervisor, internal.SessionState{
+		SessionId:      a.session.Identifier,
+		SessionState:   types.SessionStateTimeout,
+		ClientSessions: []types.ClientSession{},
+	})
+}
+
+// Stop release timer of actor, no state is sent to supervisor after it's stopped
+func (a *SessionActor) Stop() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.stop = true
+	if a.timer != nil {
+		a.timer.Stop()
+		a.timer = nil
+	}
+	a.stopTimeLimitNoLock()
+}
+
 // try to open a session with session service, if it failed, send a session closed message
 func (a *SessionActor) OpenSession() error {
+	a.transit(SessionActorPhaseOpening, a.openTimeout())
 	err := util.BackoffExec(1*time.Millisecond, 10*time.Millisecond, 2*time.Second, 2, func() error {
 		return a.sessionService.OpenSession(a.pod, a.session, a.receiveSessionState)
 	})
@@ -70,15 +185,14 @@ func (a *SessionActor) OpenSession() error {
 	return nil
 }
 
-// try to open a session with session service, if it failed, send a session closed message
+// ask session service to close a open session, actor wait for closed state until close grace period plus margin,
+// if session service does not know session, send a session closed message
 func (a *SessionActor) CloseSession() (err error) {
-	graceSeconds := DefaultCloseSessionGraceSeconds
-	if a.session.Session.Spec.CloseGracePeriodSeconds != nil {
-		graceSeconds = *a.session.Session.Spec.CloseGracePeriodSeconds
-	}
+	graceSeconds := a.closeGraceSeconds()
 	if util.SessionIsOpen(a.session.Session) {
 		// save this state to report back to fornaxcore
 		a.session.Session.Status.SessionStatus = fornaxv1.SessionStatusClosing
+		a.transit(SessionActorPhaseClosing, time.Duration(graceSeconds)*time.Second+DefaultSessionTimeoutMargin)
 		err = a.sessionService.CloseSession(a.pod, a.session, graceSeconds)
 		if err != nil && err == sessionservice.SessionNotFound {
 			// send session closed state event
@@ -92,11 +206,92 @@ func (a *SessionActor) CloseSession() (err error) {
 	return err
 }
 
+// ask application to export session metadata and close session, session is reopened on target pod by fornaxcore with metadata,
+// migration is saved in session status, so, closed session state report it back to fornaxcore
+func (a *SessionActor) MigrateSession(targetPod string) (err error) {
+	if !util.SessionIsOpen(a.session.Session) {
+		return nil
+	}
+	graceSeconds := a.closeGraceSeconds()
+	a.session.Session.Status.Migration = &fornaxv1.SessionMigration{
+		Phase:     fornaxv1.SessionMigrationPhaseMigrating,
+		SourcePod: util.Name(a.pod.Pod),
+		TargetPod: targetPod,
+		StartTime: util.NewCurrentMetaTime(),
+	}
+	a.session.Session.Status.SessionStatus = fornaxv1.SessionStatusClosing
+	a.transit(SessionActorPhaseClosing, time.Duration(graceSeconds)*time.Second+DefaultSessionTimeoutMargin)
+	err = a.sessionService.MigrateSession(a.pod, a.session, targetPod, graceSeconds)
+	if err != nil && err == sessionservice.SessionNotFound {
+		a.receiveSessionState(internal.SessionState{
+			SessionId:      a.session.Identifier,
+			SessionState:   types.SessionStateClosed,
+			ClientSessions: []types.ClientSession{},
+		})
+	}
+	return err
+}
+
+// notify a open session it will be closed before deadline, application relay it to connected clients
+func (a *SessionActor) DrainSession(reason string, deadline time.Time) error {
+	if !util.SessionIsOpen(a.session.Session) {
+		return nil
+	}
+	return a.sessionService.DrainSession(a.pod, a.session, reason, deadline)
+}
+
+// ping session to get its state, a recovered actor in Opening or Closing phase restart timer of phase,
+// recovered session state come from local checkpoint, if session service does not know session, send a session closed message
 func (a *SessionActor) PingSession() error {
-	return a.sessionService.PingSession(a.pod, a.session, a.receiveSessionState)
+	a.mu.Lock()
+	switch {
+	case a.timer != nil:
+	case a.phase == SessionActorPhaseOpening:
+		a.transitNoLock(SessionActorPhaseOpening, a.openTimeout())
+	case a.phase == SessionActorPhaseClosing:
+		a.transitNoLock(SessionActorPhaseClosing, time.Duration(a.closeGraceSeconds())*time.Second+DefaultSessionTimeoutMargin)
+	}
+	if a.phase == SessionActorPhaseOpen {
+		a.startTimeLimitNoLock()
+	}
+	a.mu.Unlock()
+	err := a.sessionService.PingSession(a.pod, a.session, a.receiveSessionState)
+	if err != nil && err == sessionservice.SessionNotFound {
+		a.receiveSessionState(internal.SessionState{
+			SessionId:      a.session.Identifier,
+			SessionState:   types.SessionStateClosed,
+			ClientSessions: []types.ClientSession{},
+		})
+	}
+	return err
 }
 
-// session actor forward session state to pod to handle
+// notify a open session that application config data or secret changed, so session can reload config without restart
+func (a *SessionActor) UpdateConfig() error {
+	if !util.SessionIsOpen(a.session.Session) {
+		return nil
+	}
+	return a.sessionService.UpdateSessionConfig(a.pod, a.session)
+}
+
+// session actor move to phase of reported state and forward state to pod to handle, states after Closed or Timeout are dropped
 func (a *SessionActor) receiveSessionState(state internal.SessionState) {
+	a.mu.Lock()
+	if a.stop || a.phase == SessionActorPhaseClosed || a.phase == SessionActorPhaseTimeout {
+		a.mu.Unlock()
+		return
+	}
+	switch state.SessionState {
+	case types.SessionStateReady:
+		// a session still serving clients during Closing stay in Closing
+		if a.phase == SessionActorPhaseOpening {
+			a.transitNoLock(SessionActorPhaseOpen, 0)
+			a.startTimeLimitNoLock()
+		}
+	case types.SessionStateClosed, types.SessionStateNoHeartbeat:
+		a.transitNoLock(SessionActorPhaseClosed, 0)
+		a.stopTimeLimitNoLock()
+	}
+	a.mu.Unlock()
 	message.Send(nil, a.supervisor, state)
 }
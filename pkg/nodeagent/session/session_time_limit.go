@@ -0,0 +1,85 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"time"
+
+	"centaurusinfra.io/fornax-serverless/pkg/message"
+	internal "centaurusinfra.io/fornax-serverless/pkg/nodeagent/message"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultSessionExpiryWarningSeconds = uint32(60)
+
+	SessionDrainReasonTimeLimit = "SessionTimeLimit"
+)
+
+func (a *SessionActor) maxSessionDuration() time.Duration {
+	return time.Duration(a.session.Session.Spec.MaxSessionDurationSeconds) * time.Second
+}
+
+func (a *SessionActor) expiryWarning() time.Duration {
+	if a.session.Session.Spec.ExpiryWarningSeconds > 0 {
+		return time.Duration(a.session.Session.Spec.ExpiryWarningSeconds) * time.Second
+	}
+	return time.Duration(DefaultSessionExpiryWarningSeconds) * time.Second
+}
+
+// startTimeLimitNoLock start timers of a time limited session when it's open, expiry time is saved in session,
+// so a actor recovered after node agent restart keep original expiry, a warning missed during restart is sent immediately,
+// timers send messages to pod actor, pod actor drain and close session in its own goroutine
+func (a *SessionActor) startTimeLimitNoLock() {
+	if a.maxSessionDuration() == 0 || a.expiryTimer != nil {
+		return
+	}
+	if a.session.ExpiryTime == nil {
+		expiry := time.Now().Add(a.maxSessionDuration())
+		a.session.ExpiryTime = &expiry
+	}
+	expiry := *a.session.ExpiryTime
+	sessionId := a.session.Identifier
+	klog.InfoS("Session is time limited", "session", sessionId, "requestId", util.RequestId(a.session.Session), "expiry", expiry)
+
+	warnAfter := time.Until(expiry.Add(-a.expiryWarning()))
+	if warnAfter < 0 {
+		warnAfter = 0
+	}
+	a.warningTimer = time.AfterFunc(warnAfter, func() {
+		message.Send(nil, a.supervisor, internal.SessionTimeLimitWarning{SessionId: sessionId, Deadline: expiry})
+	})
+	expireAfter := time.Until(expiry)
+	if expireAfter < 0 {
+		expireAfter = 0
+	}
+	a.expiryTimer = time.AfterFunc(expireAfter, func() {
+		message.Send(nil, a.supervisor, internal.SessionTimeLimitExceeded{SessionId: sessionId})
+	})
+}
+
+func (a *SessionActor) stopTimeLimitNoLock() {
+	if a.warningTimer != nil {
+		a.warningTimer.Stop()
+		a.warningTimer = nil
+	}
+	if a.expiryTimer != nil {
+		a.expiryTimer.Stop()
+		a.expiryTimer = nil
+	}
+}
@@ -23,13 +23,17 @@ import (
 	"sync"
 	"time"
 
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
 	internal "centaurusinfra.io/fornax-serverless/pkg/nodeagent/message"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/sessionservice"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"k8s.io/klog/v2"
 )
 
@@ -66,6 +70,9 @@ type GrpcSessionService struct {
 	// pod's get message connection by pod id
 	sessionClients map[string]*GetSessionMessageClient
 
+	// mtls of pod connections, nil if not enabled
+	tls *SessionServiceTLS
+
 	UnimplementedSessionServiceServer
 }
 
@@ -81,6 +88,10 @@ func (g *GrpcSessionService) Run(ctx context.Context, port int32) error {
 		return err
 	}
 	var opts []grpc.ServerOption
+	if g.tls != nil {
+		g.tls.Run(ctx)
+		opts = append(opts, grpc.Creds(credentials.NewTLS(g.tls.TLSConfig())))
+	}
 	grpcServer := grpc.NewServer(opts...)
 	RegisterSessionServiceServer(grpcServer, g)
 	go func() {
@@ -169,6 +180,9 @@ func (g *GrpcSessionService) checkAndCleanSessionHeartbeat() {
 // pod use get message to maitain a stream connection with session service to receive session command messages
 // only one connection is allowed from one pod, method return until pod disconnect or send message failed via this stream connection
 func (g *GrpcSessionService) GetMessage(identifier *PodIdentifier, server SessionService_GetMessageServer) error {
+	if err := g.authorizePod(server.Context(), identifier.GetPodId()); err != nil {
+		return err
+	}
 	var messageSeq int64 = 0
 	klog.InfoS("Received GetMessage stream connection from pod", "pod", identifier)
 	ch := make(chan *SessionMessage, 10)
@@ -200,14 +214,44 @@ func (g *GrpcSessionService) GetMessage(identifier *PodIdentifier, server Sessio
 }
 
 func (g *GrpcSessionService) PutMessage(ctx context.Context, message *SessionMessage) (*empty.Empty, error) {
-	var err error
+	if err := g.authorizePod(ctx, message.GetSessionIdentifier().GetPodId()); err != nil {
+		return nil, err
+	}
+	g.handleSessionMessage(message.GetSessionIdentifier().GetPodId(), message)
+	return &emptypb.Empty{}, nil
+}
+
+// clientSessions return clients still on session, a client left session has exit time
+// clientSessions convert clients reported by container, clients left session are reported with leave time
+func clientSessions(status *SessionStatus) []types.ClientSession {
+	clients := []types.ClientSession{}
+	for _, v := range status.GetClientSession() {
+		client := types.ClientSession{Identifier: v.GetClientIdentifier(), SourceAddress: v.GetSourceAddress()}
+		if v.GetTimeJoin() != nil {
+			t := v.GetTimeJoin().AsTime()
+			client.JoinTime = &t
+		}
+		if v.GetTimeExit() != nil {
+			t := v.GetTimeExit().AsTime()
+			client.LeaveTime = &t
+		}
+		clients = append(clients, client)
+	}
+	return clients
+}
+
+// handleSessionMessage handle message sent by pod via PutMessage or v2 stream
+func (g *GrpcSessionService) handleSessionMessage(podId string, message *SessionMessage) {
 	switch message.GetMessageType() {
+	case MessageType_HEARTBEAT:
+		g.refreshSessionHeartbeats(podId, message.GetHeartbeat().GetOpenSessions())
 	case MessageType_SESSION_STATE:
+		status := message.GetSessionStatus()
 		msg := internal.SessionState{
-			SessionId:      message.GetSessionIdentifier().GetIdentifier(),
-			ClientSessions: []types.ClientSession{},
+			SessionId:         message.GetSessionIdentifier().GetIdentifier(),
+			ClientSessions:    clientSessions(status),
+			MigrationMetadata: status.GetMigrationMetadata(),
 		}
-		status := message.GetSessionStatus()
 		sessionId := message.GetSessionIdentifier().GetIdentifier()
 		switch status.GetSessionState() {
 		case SessionState_STATE_CLOSED:
@@ -226,8 +270,6 @@ func (g *GrpcSessionService) PutMessage(ctx context.Context, message *SessionMes
 	default:
 		klog.Errorf(fmt.Sprintf("not supported message type %s, message %v", message.GetMessageType(), message))
 	}
-
-	return &emptypb.Empty{}, err
 }
 
 // CloseSession dispatch a SessionClose event to pod
@@ -248,6 +290,7 @@ func (g *GrpcSessionService) CloseSession(pod *types.FornaxPod, session *types.F
 		},
 	}
 	m := &SessionMessage{
+		RequestIdentifier: util.RequestId(session.Session),
 		MessageIdentifier: "",
 		SessionIdentifier: &SessionIdentifier{
 			PodId:      podId,
@@ -259,12 +302,71 @@ func (g *GrpcSessionService) CloseSession(pod *types.FornaxPod, session *types.F
 
 	err := g.sendGrpcMessageToPod(podId, m)
 	if err != nil {
-		klog.ErrorS(err, "Failed to dispatch close session message to pod", "pod", podId, "session", sessionId)
+		klog.ErrorS(err, "Failed to dispatch close session message to pod", "pod", podId, "session", sessionId, "requestId", util.RequestId(session.Session))
 		return err
 	}
 	return nil
 }
 
+// MigrateSession dispatch a MigrateSession event to pod, container export session metadata when it close session
+func (g *GrpcSessionService) MigrateSession(pod *types.FornaxPod, session *types.FornaxSession, targetPod string, gracePeriodSeconds uint16) error {
+	podId := pod.Identifier
+	sessionId := session.Identifier
+	if g.getSessionHeartbeat(sessionId) == nil {
+		return sessionservice.SessionNotFound
+	}
+
+	messageType := MessageType_MIGRATE_SESSION
+	body := SessionMessage_MigrateSession{
+		MigrateSession: &MigrateSession{
+			TargetPodId:        targetPod,
+			GracePeriodSeconds: int64(gracePeriodSeconds),
+		},
+	}
+	m := &SessionMessage{
+		RequestIdentifier: util.RequestId(session.Session),
+		SessionIdentifier: &SessionIdentifier{
+			PodId:      podId,
+			Identifier: sessionId,
+		},
+		MessageType: messageType,
+		MessageBody: &body,
+	}
+
+	err := g.sendGrpcMessageToPod(podId, m)
+	if err != nil {
+		klog.ErrorS(err, "Failed to dispatch migrate session message to pod", "pod", podId, "session", sessionId, "requestId", util.RequestId(session.Session))
+		return err
+	}
+	return nil
+}
+
+// sessionMigration return metadata exported by source pod if session is being moved to this pod
+func sessionMigration(session *types.FornaxSession) *SessionMigration {
+	migration := session.Session.Status.Migration
+	if migration == nil || migration.Phase != fornaxv1.SessionMigrationPhaseMigrating {
+		return nil
+	}
+	return &SessionMigration{
+		SourcePodId: migration.SourcePod,
+		Metadata:    migration.Metadata,
+	}
+}
+
+// sessionAttachment return reference of session out of band payload, payload itself is downloaded by container
+func sessionAttachment(session *types.FornaxSession) *SessionAttachment {
+	attachment := session.Session.Spec.Attachment
+	if attachment == nil {
+		return nil
+	}
+	return &SessionAttachment{
+		Url:         attachment.URL,
+		SizeBytes:   attachment.SizeBytes,
+		Sha256:      attachment.SHA256,
+		ContentType: attachment.ContentType,
+	}
+}
+
 // OpenSession dispatch a SessionOpen event to pod
 func (g *GrpcSessionService) OpenSession(pod *types.FornaxPod, session *types.FornaxSession, stateCallbackFunc func(internal.SessionState)) error {
 	podId := pod.Identifier
@@ -275,12 +377,15 @@ func (g *GrpcSessionService) OpenSession(pod *types.FornaxPod, session *types.Fo
 		OpenSession: &OpenSession{
 			SessionConfiguration: &SessionConfiguration{
 				SessionData: []byte(sessionData),
+				Attachment:  sessionAttachment(session),
 			},
+			Migration: sessionMigration(session),
 		},
 	}
 	m := &SessionMessage{
-		MessageType: messageType,
-		MessageBody: &body,
+		RequestIdentifier: util.RequestId(session.Session),
+		MessageType:       messageType,
+		MessageBody:       &body,
 		SessionIdentifier: &SessionIdentifier{
 			PodId:      podId,
 			Identifier: sessionId,
@@ -289,7 +394,7 @@ func (g *GrpcSessionService) OpenSession(pod *types.FornaxPod, session *types.Fo
 
 	err := g.sendGrpcMessageToPod(podId, m)
 	if err != nil {
-		klog.ErrorS(err, "Failed to dispatch open session message to pod", "pod", podId, "session", sessionId)
+		klog.ErrorS(err, "Failed to dispatch open session message to pod", "pod", podId, "session", sessionId, "requestId", util.RequestId(session.Session))
 		return err
 	}
 	heartbeat := g.getSessionHeartbeat(sessionId)
@@ -315,6 +420,7 @@ func (g *GrpcSessionService) PingSession(pod *types.FornaxPod, session *types.Fo
 		PingSession: &PingSession{},
 	}
 	m := &SessionMessage{
+		RequestIdentifier: util.RequestId(session.Session),
 		SessionIdentifier: &SessionIdentifier{
 			PodId:      podId,
 			Identifier: sessionId,
@@ -326,6 +432,82 @@ func (g *GrpcSessionService) PingSession(pod *types.FornaxPod, session *types.Fo
 	return g.sendGrpcMessageToPod(podId, m)
 }
 
+// UpdateSessionConfig send a SessionConfiguration message with current config data and secrets of pod to pod/session
+func (g *GrpcSessionService) UpdateSessionConfig(pod *types.FornaxPod, session *types.FornaxSession) error {
+	podId := pod.Identifier
+	sessionId := session.Identifier
+	if g.getSessionHeartbeat(sessionId) == nil {
+		return sessionservice.SessionNotFound
+	}
+
+	configuration := &SessionConfiguration{
+		SessionData: []byte(session.Session.Spec.SessionData),
+		Attachment:  sessionAttachment(session),
+	}
+	if pod.ConfigMap != nil {
+		configuration.ConfigData = pod.ConfigMap.Data
+	}
+	if pod.Secret != nil {
+		configuration.Secret = &SessionSecret{Version: pod.Secret.Version, Data: pod.Secret.Data}
+	}
+	if pod.PreviousSecret != nil {
+		configuration.PreviousSecret = &SessionSecret{Version: pod.PreviousSecret.Version, Data: pod.PreviousSecret.Data}
+	}
+	messageType := MessageType_SESSION_CONFIGURATION
+	body := SessionMessage_SessionConfiguration{
+		SessionConfiguration: configuration,
+	}
+	m := &SessionMessage{
+		RequestIdentifier: util.RequestId(session.Session),
+		SessionIdentifier: &SessionIdentifier{
+			PodId:      podId,
+			Identifier: sessionId,
+		},
+		MessageType: messageType,
+		MessageBody: &body,
+	}
+
+	err := g.sendGrpcMessageToPod(podId, m)
+	if err != nil {
+		klog.ErrorS(err, "Failed to dispatch session configuration message to pod", "pod", podId, "session", sessionId, "requestId", util.RequestId(session.Session))
+		return err
+	}
+	return nil
+}
+
+// DrainSession send a DrainSession message to pod/session, session is still open until it's closed
+func (g *GrpcSessionService) DrainSession(pod *types.FornaxPod, session *types.FornaxSession, reason string, deadline time.Time) error {
+	podId := pod.Identifier
+	sessionId := session.Identifier
+	if g.getSessionHeartbeat(sessionId) == nil {
+		return sessionservice.SessionNotFound
+	}
+
+	messageType := MessageType_DRAIN_SESSION
+	body := SessionMessage_DrainSession{
+		DrainSession: &DrainSession{
+			Reason:   reason,
+			Deadline: timestamppb.New(deadline),
+		},
+	}
+	m := &SessionMessage{
+		RequestIdentifier: util.RequestId(session.Session),
+		SessionIdentifier: &SessionIdentifier{
+			PodId:      podId,
+			Identifier: sessionId,
+		},
+		MessageType: messageType,
+		MessageBody: &body,
+	}
+
+	err := g.sendGrpcMessageToPod(podId, m)
+	if err != nil {
+		klog.ErrorS(err, "Failed to dispatch drain session message to pod", "pod", podId, "session", sessionId, "requestId", util.RequestId(session.Session))
+		return err
+	}
+	return nil
+}
+
 func (g *GrpcSessionService) sendGrpcMessageToPod(podId string, msg *SessionMessage) error {
 	if client := g.getSessionClient(podId); client != nil {
 		client.channel <- msg
@@ -46,6 +46,9 @@ const (
 	MessageType_CLOSE_SESSION         MessageType = 102
 	MessageType_PING_SESSION          MessageType = 103
 	MessageType_SESSION_STATE         MessageType = 104
+	MessageType_DRAIN_SESSION         MessageType = 105
+	MessageType_MIGRATE_SESSION       MessageType = 106
+	MessageType_HEARTBEAT             MessageType = 107
 )
 
 // Enum value maps for MessageType.
@@ -57,6 +60,9 @@ var (
 		102: "CLOSE_SESSION",
 		103: "PING_SESSION",
 		104: "SESSION_STATE",
+		105: "DRAIN_SESSION",
+		106: "MIGRATE_SESSION",
+		107: "HEARTBEAT",
 	}
 	MessageType_value = map[string]int32{
 		"UNSPECIFIED":           0,
@@ -65,6 +71,9 @@ var (
 		"CLOSE_SESSION":         102,
 		"PING_SESSION":          103,
 		"SESSION_STATE":         104,
+		"DRAIN_SESSION":         105,
+		"MIGRATE_SESSION":       106,
+		"HEARTBEAT":             107,
 	}
 )
 
@@ -155,12 +164,16 @@ type SessionMessage struct {
 	MessageIdentifier string             `protobuf:"bytes,1,opt,name=messageIdentifier,proto3" json:"messageIdentifier,omitempty"`
 	SessionIdentifier *SessionIdentifier `protobuf:"bytes,2,opt,name=sessionIdentifier,proto3" json:"sessionIdentifier,omitempty"`
 	MessageType       MessageType        `protobuf:"varint,3,opt,name=messageType,proto3,enum=centaurusinfra.io.fornaxcore.nodeagent.sessionservice.MessageType" json:"messageType,omitempty"`
+	RequestIdentifier string             `protobuf:"bytes,4,opt,name=requestIdentifier,proto3" json:"requestIdentifier,omitempty"` // request id of session, used to correlate logs of a session across components
 	// Types that are assignable to MessageBody:
 	//	*SessionMessage_SessionConfiguration
 	//	*SessionMessage_OpenSession
 	//	*SessionMessage_CloseSession
 	//	*SessionMessage_PingSession
 	//	*SessionMessage_SessionStatus
+	//	*SessionMessage_DrainSession
+	//	*SessionMessage_MigrateSession
+	//	*SessionMessage_Heartbeat
 	MessageBody isSessionMessage_MessageBody `protobuf_oneof:"MessageBody"`
 }
 
@@ -217,6 +230,13 @@ func (x *SessionMessage) GetMessageType() MessageType {
 	return MessageType_UNSPECIFIED
 }
 
+func (x *SessionMessage) GetRequestIdentifier() string {
+	if x != nil {
+		return x.RequestIdentifier
+	}
+	return ""
+}
+
 func (m *SessionMessage) GetMessageBody() isSessionMessage_MessageBody {
 	if m != nil {
 		return m.MessageBody
@@ -259,6 +279,27 @@ func (x *SessionMessage) GetSessionStatus() *SessionStatus {
 	return nil
 }
 
+func (x *SessionMessage) GetDrainSession() *DrainSession {
+	if x, ok := x.GetMessageBody().(*SessionMessage_DrainSession); ok {
+		return x.DrainSession
+	}
+	return nil
+}
+
+func (x *SessionMessage) GetMigrateSession() *MigrateSession {
+	if x, ok := x.GetMessageBody().(*SessionMessage_MigrateSession); ok {
+		return x.MigrateSession
+	}
+	return nil
+}
+
+func (x *SessionMessage) GetHeartbeat() *Heartbeat {
+	if x, ok := x.GetMessageBody().(*SessionMessage_Heartbeat); ok {
+		return x.Heartbeat
+	}
+	return nil
+}
+
 type isSessionMessage_MessageBody interface {
 	isSessionMessage_MessageBody()
 }
@@ -283,6 +324,18 @@ type SessionMessage_SessionStatus struct {
 	SessionStatus *SessionStatus `protobuf:"bytes,104,opt,name=sessionStatus,proto3,oneof"`
 }
 
+type SessionMessage_DrainSession struct {
+	DrainSession *DrainSession `protobuf:"bytes,105,opt,name=drainSession,proto3,oneof"`
+}
+
+type SessionMessage_MigrateSession struct {
+	MigrateSession *MigrateSession `protobuf:"bytes,106,opt,name=migrateSession,proto3,oneof"`
+}
+
+type SessionMessage_Heartbeat struct {
+	Heartbeat *Heartbeat `protobuf:"bytes,107,opt,name=heartbeat,proto3,oneof"`
+}
+
 func (*SessionMessage_SessionConfiguration) isSessionMessage_MessageBody() {}
 
 func (*SessionMessage_OpenSession) isSessionMessage_MessageBody() {}
@@ -293,6 +346,12 @@ func (*SessionMessage_PingSession) isSessionMessage_MessageBody() {}
 
 func (*SessionMessage_SessionStatus) isSessionMessage_MessageBody() {}
 
+func (*SessionMessage_DrainSession) isSessionMessage_MessageBody() {}
+
+func (*SessionMessage_MigrateSession) isSessionMessage_MessageBody() {}
+
+func (*SessionMessage_Heartbeat) isSessionMessage_MessageBody() {}
+
 type PodIdentifier struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -401,7 +460,11 @@ type SessionConfiguration struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SessionData []byte `protobuf:"bytes,1,opt,name=sessionData,proto3" json:"sessionData,omitempty"` // a container specific blob
+	SessionData    []byte             `protobuf:"bytes,1,opt,name=sessionData,proto3" json:"sessionData,omitempty"`                                                                                       // a container specific blob
+	ConfigData     map[string]string  `protobuf:"bytes,2,rep,name=configData,proto3" json:"configData,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // application config data, sent when it's changed
+	Secret         *SessionSecret     `protobuf:"bytes,3,opt,name=secret,proto3" json:"secret,omitempty"`                                                                                                 // current application secret
+	PreviousSecret *SessionSecret     `protobuf:"bytes,4,opt,name=previousSecret,proto3" json:"previousSecret,omitempty"`                                                                                 // previous application secret, only set during secret rotation grace period
+	Attachment     *SessionAttachment `protobuf:"bytes,5,opt,name=attachment,proto3" json:"attachment,omitempty"`                                                                                         // out of band session payload, container download it from url
 }
 
 func (x *SessionConfiguration) Reset() {
@@ -443,20 +506,175 @@ func (x *SessionConfiguration) GetSessionData() []byte {
 	return nil
 }
 
+func (x *SessionConfiguration) GetConfigData() map[string]string {
+	if x != nil {
+		return x.ConfigData
+	}
+	return nil
+}
+
+func (x *SessionConfiguration) GetSecret() *SessionSecret {
+	if x != nil {
+		return x.Secret
+	}
+	return nil
+}
+
+func (x *SessionConfiguration) GetPreviousSecret() *SessionSecret {
+	if x != nil {
+		return x.PreviousSecret
+	}
+	return nil
+}
+
+func (x *SessionConfiguration) GetAttachment() *SessionAttachment {
+	if x != nil {
+		return x.Attachment
+	}
+	return nil
+}
+
+type SessionAttachment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Url         string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	SizeBytes   int64  `protobuf:"varint,2,opt,name=sizeBytes,proto3" json:"sizeBytes,omitempty"`
+	Sha256      string `protobuf:"bytes,3,opt,name=sha256,proto3" json:"sha256,omitempty"` // lowercase hex digest, verify payload if it's set
+	ContentType string `protobuf:"bytes,4,opt,name=contentType,proto3" json:"contentType,omitempty"`
+}
+
+func (x *SessionAttachment) Reset() {
+	*x = SessionAttachment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessionAttachment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionAttachment) ProtoMessage() {}
+
+func (x *SessionAttachment) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionAttachment.ProtoReflect.Descriptor instead.
+func (*SessionAttachment) Descriptor() ([]byte, []int) {
+	return file_pkg_nodeagent_sessionservice_grpc_session_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SessionAttachment) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *SessionAttachment) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *SessionAttachment) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *SessionAttachment) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+type SessionSecret struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version string            `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Data    map[string][]byte `protobuf:"bytes,2,rep,name=data,proto3" json:"data,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *SessionSecret) Reset() {
+	*x = SessionSecret{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessionSecret) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionSecret) ProtoMessage() {}
+
+func (x *SessionSecret) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionSecret.ProtoReflect.Descriptor instead.
+func (*SessionSecret) Descriptor() ([]byte, []int) {
+	return file_pkg_nodeagent_sessionservice_grpc_session_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SessionSecret) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *SessionSecret) GetData() map[string][]byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
 // request container to initialize a session,
-//container send a session state message back to notify session is ready for client use
+// container send a session state message back to notify session is ready for client use
 type OpenSession struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	SessionConfiguration *SessionConfiguration `protobuf:"bytes,1,opt,name=sessionConfiguration,proto3" json:"sessionConfiguration,omitempty"`
+	Migration            *SessionMigration     `protobuf:"bytes,2,opt,name=migration,proto3" json:"migration,omitempty"` // set when session is moved from another pod
 }
 
 func (x *OpenSession) Reset() {
 	*x = OpenSession{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[4]
+		mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -469,7 +687,7 @@ func (x *OpenSession) String() string {
 func (*OpenSession) ProtoMessage() {}
 
 func (x *OpenSession) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[4]
+	mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -482,7 +700,7 @@ func (x *OpenSession) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OpenSession.ProtoReflect.Descriptor instead.
 func (*OpenSession) Descriptor() ([]byte, []int) {
-	return file_pkg_nodeagent_sessionservice_grpc_session_service_proto_rawDescGZIP(), []int{4}
+	return file_pkg_nodeagent_sessionservice_grpc_session_service_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *OpenSession) GetSessionConfiguration() *SessionConfiguration {
@@ -492,8 +710,71 @@ func (x *OpenSession) GetSessionConfiguration() *SessionConfiguration {
 	return nil
 }
 
+func (x *OpenSession) GetMigration() *SessionMigration {
+	if x != nil {
+		return x.Migration
+	}
+	return nil
+}
+
+// metadata of a migrated session exported by container on source pod
+type SessionMigration struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourcePodId string            `protobuf:"bytes,1,opt,name=sourcePodId,proto3" json:"sourcePodId,omitempty"`
+	Metadata    map[string]string `protobuf:"bytes,2,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *SessionMigration) Reset() {
+	*x = SessionMigration{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessionMigration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionMigration) ProtoMessage() {}
+
+func (x *SessionMigration) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionMigration.ProtoReflect.Descriptor instead.
+func (*SessionMigration) Descriptor() ([]byte, []int) {
+	return file_pkg_nodeagent_sessionservice_grpc_session_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SessionMigration) GetSourcePodId() string {
+	if x != nil {
+		return x.SourcePodId
+	}
+	return ""
+}
+
+func (x *SessionMigration) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
 // close session and notify client to left, and container will close session after gracePeriodSeconds
-//container send a session state message back to notify session is closed
+// container send a session state message back to notify session is closed
 type CloseSession struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -505,7 +786,7 @@ type CloseSession struct {
 func (x *CloseSession) Reset() {
 	*x = CloseSession{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[5]
+		mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -518,7 +799,7 @@ func (x *CloseSession) String() string {
 func (*CloseSession) ProtoMessage() {}
 
 func (x *CloseSession) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[5]
+	mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -531,7 +812,7 @@ func (x *CloseSession) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CloseSession.ProtoReflect.Descriptor instead.
 func (*CloseSession) Descriptor() ([]byte, []int) {
-	return file_pkg_nodeagent_sessionservice_grpc_session_service_proto_rawDescGZIP(), []int{5}
+	return file_pkg_nodeagent_sessionservice_grpc_session_service_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *CloseSession) GetGracePeriodSeconds() int64 {
@@ -541,8 +822,122 @@ func (x *CloseSession) GetGracePeriodSeconds() int64 {
 	return 0
 }
 
+// notify session it will be closed before deadline, container should relay it to connected clients,
+// session is still open until a close session message is received
+type DrainSession struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Reason   string               `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	Deadline *timestamp.Timestamp `protobuf:"bytes,2,opt,name=deadline,proto3" json:"deadline,omitempty"`
+}
+
+func (x *DrainSession) Reset() {
+	*x = DrainSession{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DrainSession) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainSession) ProtoMessage() {}
+
+func (x *DrainSession) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainSession.ProtoReflect.Descriptor instead.
+func (*DrainSession) Descriptor() ([]byte, []int) {
+	return file_pkg_nodeagent_sessionservice_grpc_session_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DrainSession) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *DrainSession) GetDeadline() *timestamp.Timestamp {
+	if x != nil {
+		return x.Deadline
+	}
+	return nil
+}
+
+// session is moved to another pod, container export session metadata in session state message and close session after gracePeriodSeconds,
+// container send a closed session state with metadata, metadata is passed to container on target pod when session is reopened
+type MigrateSession struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TargetPodId        string `protobuf:"bytes,1,opt,name=targetPodId,proto3" json:"targetPodId,omitempty"`
+	GracePeriodSeconds int64  `protobuf:"varint,2,opt,name=gracePeriodSeconds,proto3" json:"gracePeriodSeconds,omitempty"`
+}
+
+func (x *MigrateSession) Reset() {
+	*x = MigrateSession{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MigrateSession) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MigrateSession) ProtoMessage() {}
+
+func (x *MigrateSession) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MigrateSession.ProtoReflect.Descriptor instead.
+func (*MigrateSession) Descriptor() ([]byte, []int) {
+	return file_pkg_nodeagent_sessionservice_grpc_session_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *MigrateSession) GetTargetPodId() string {
+	if x != nil {
+		return x.TargetPodId
+	}
+	return ""
+}
+
+func (x *MigrateSession) GetGracePeriodSeconds() int64 {
+	if x != nil {
+		return x.GracePeriodSeconds
+	}
+	return 0
+}
+
 // ping session and request container to report its status container send a session state message back,
-//if session do not reply ping request consecutively, session is considered as dead, and pod will be terminated
+// if session do not reply ping request consecutively, session is considered as dead, and pod will be terminated
 type PingSession struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -552,7 +947,7 @@ type PingSession struct {
 func (x *PingSession) Reset() {
 	*x = PingSession{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[6]
+		mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -565,7 +960,7 @@ func (x *PingSession) String() string {
 func (*PingSession) ProtoMessage() {}
 
 func (x *PingSession) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[6]
+	mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -578,7 +973,64 @@ func (x *PingSession) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PingSession.ProtoReflect.Descriptor instead.
 func (*PingSession) Descriptor() ([]byte, []int) {
-	return file_pkg_nodeagent_sessionservice_grpc_session_service_proto_rawDescGZIP(), []int{6}
+	return file_pkg_nodeagent_sessionservice_grpc_session_service_proto_rawDescGZIP(), []int{11}
+}
+
+// keepalive of v2 stream, node agent send heartbeat periodically and pod reply a heartbeat with sessions open on pod,
+// node agent consider sessions in openSessions alive without pinging them
+type Heartbeat struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Timestamp    *timestamp.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	OpenSessions []string             `protobuf:"bytes,2,rep,name=openSessions,proto3" json:"openSessions,omitempty"`
+}
+
+func (x *Heartbeat) Reset() {
+	*x = Heartbeat{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Heartbeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Heartbeat) ProtoMessage() {}
+
+func (x *Heartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Heartbeat.ProtoReflect.Descriptor instead.
+func (*Heartbeat) Descriptor() ([]byte, []int) {
+	return file_pkg_nodeagent_sessionservice_grpc_session_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Heartbeat) GetTimestamp() *timestamp.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Heartbeat) GetOpenSessions() []string {
+	if x != nil {
+		return x.OpenSessions
+	}
+	return nil
 }
 
 // container keep its internal state of clients are on this session, in long term it could be managed via ingress gateway
@@ -590,12 +1042,13 @@ type ClientSession struct {
 	ClientIdentifier string               `protobuf:"bytes,1,opt,name=clientIdentifier,proto3" json:"clientIdentifier,omitempty"`
 	TimeJoin         *timestamp.Timestamp `protobuf:"bytes,2,opt,name=timeJoin,proto3" json:"timeJoin,omitempty"`
 	TimeExit         *timestamp.Timestamp `protobuf:"bytes,3,opt,name=timeExit,proto3" json:"timeExit,omitempty"`
+	SourceAddress    string               `protobuf:"bytes,4,opt,name=sourceAddress,proto3" json:"sourceAddress,omitempty"` // remote address of end user connection
 }
 
 func (x *ClientSession) Reset() {
 	*x = ClientSession{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[7]
+		mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -608,7 +1061,7 @@ func (x *ClientSession) String() string {
 func (*ClientSession) ProtoMessage() {}
 
 func (x *ClientSession) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[7]
+	mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -621,7 +1074,7 @@ func (x *ClientSession) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ClientSession.ProtoReflect.Descriptor instead.
 func (*ClientSession) Descriptor() ([]byte, []int) {
-	return file_pkg_nodeagent_sessionservice_grpc_session_service_proto_rawDescGZIP(), []int{7}
+	return file_pkg_nodeagent_sessionservice_grpc_session_service_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *ClientSession) GetClientIdentifier() string {
@@ -645,20 +1098,29 @@ func (x *ClientSession) GetTimeExit() *timestamp.Timestamp {
 	return nil
 }
 
-// container report its state and clients is using this session
+func (x *ClientSession) GetSourceAddress() string {
+	if x != nil {
+		return x.SourceAddress
+	}
+	return ""
+}
+
+// container report its state and clients is using this session,
+// in v2 stream, container send it whenever a client join or leave session
 type SessionStatus struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SessionState  SessionState     `protobuf:"varint,1,opt,name=sessionState,proto3,enum=centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionState" json:"sessionState,omitempty"`
-	ClientSession []*ClientSession `protobuf:"bytes,2,rep,name=clientSession,proto3" json:"clientSession,omitempty"`
+	SessionState      SessionState      `protobuf:"varint,1,opt,name=sessionState,proto3,enum=centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionState" json:"sessionState,omitempty"`
+	ClientSession     []*ClientSession  `protobuf:"bytes,2,rep,name=clientSession,proto3" json:"clientSession,omitempty"`
+	MigrationMetadata map[string]string `protobuf:"bytes,3,rep,name=migrationMetadata,proto3" json:"migrationMetadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // session metadata exported when session is closed for migration
 }
 
 func (x *SessionStatus) Reset() {
 	*x = SessionStatus{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[8]
+		mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -671,7 +1133,7 @@ func (x *SessionStatus) String() string {
 func (*SessionStatus) ProtoMessage() {}
 
 func (x *SessionStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[8]
+	mi := &file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -684,7 +1146,7 @@ func (x *SessionStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SessionStatus.ProtoReflect.Descriptor instead.
 func (*SessionStatus) Descriptor() ([]byte, []int) {
-	return file_pkg_nodeagent_sessionservice_grpc_session_service_proto_rawDescGZIP(), []int{8}
+	return file_pkg_nodeagent_sessionservice_grpc_session_service_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *SessionStatus) GetSessionState() SessionState {
@@ -701,6 +1163,13 @@ func (x *SessionStatus) GetClientSession() []*ClientSession {
 	return nil
 }
 
+func (x *SessionStatus) GetMigrationMetadata() map[string]string {
+	if x != nil {
+		return x.MigrationMetadata
+	}
+	return nil
+}
+
 var File_pkg_nodeagent_sessionservice_grpc_session_service_proto protoreflect.FileDescriptor
 
 var file_pkg_nodeagent_sessionservice_grpc_session_service_proto_rawDesc = []byte{
@@ -714,8 +1183,8 @@ var file_pkg_nodeagent_sessionservice_grpc_session_service_proto_rawDesc = []byt
 	0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
 	0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67,
 	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74,
-	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd8,
-	0x06, 0x0a, 0x0e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xc4,
+	0x09, 0x0a, 0x0e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
 	0x65, 0x12, 0x2c, 0x0a, 0x11, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x64, 0x65, 0x6e,
 	0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x6d, 0x65,
 	0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12,
@@ -732,130 +1201,273 @@ var file_pkg_nodeagent_sessionservice_grpc_session_service_proto_rawDesc = []byt
 	0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65,
 	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65, 0x72,
 	0x76, 0x69, 0x63, 0x65, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65,
-	0x52, 0x0b, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x81, 0x01,
-	0x0a, 0x14, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75,
-	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x64, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x4b, 0x2e, 0x63,
-	0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f,
-	0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65,
-	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x14, 0x73, 0x65, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x12, 0x66, 0x0a, 0x0b, 0x6f, 0x70, 0x65, 0x6e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
-	0x18, 0x65, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x42, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72,
+	0x52, 0x0b, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x2c, 0x0a,
+	0x11, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69,
+	0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x81, 0x01, 0x0a, 0x14,
+	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x64, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x4b, 0x2e, 0x63, 0x65, 0x6e,
+	0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66,
+	0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x14, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x66, 0x0a, 0x0b, 0x6f, 0x70, 0x65, 0x6e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x65,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x42, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73,
+	0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63,
+	0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4f, 0x70, 0x65,
+	0x6e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0b, 0x6f, 0x70, 0x65, 0x6e,
+	0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x69, 0x0a, 0x0c, 0x63, 0x6c, 0x6f, 0x73, 0x65,
+	0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x66, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x43, 0x2e,
+	0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69,
+	0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64,
+	0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0c, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x66, 0x0a, 0x0b, 0x70, 0x69, 0x6e, 0x67, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x67, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x42, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75,
+	0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e,
+	0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x50, 0x69, 0x6e, 0x67, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0b, 0x70,
+	0x69, 0x6e, 0x67, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x6c, 0x0a, 0x0d, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x68, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x44, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66,
+	0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65,
+	0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x48, 0x00, 0x52, 0x0d, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x69, 0x0a, 0x0c, 0x64, 0x72, 0x61, 0x69,
+	0x6e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x69, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x43,
+	0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e,
+	0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f,
+	0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x53, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0c, 0x64, 0x72, 0x61, 0x69, 0x6e, 0x53, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x6f, 0x0a, 0x0e, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x65, 0x53, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x6a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x45, 0x2e, 0x63, 0x65,
+	0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e,
+	0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0e, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x65, 0x53, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x60, 0x0a, 0x09, 0x68, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61,
+	0x74, 0x18, 0x6b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x40, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75,
+	0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e,
+	0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x48, 0x00, 0x52, 0x09, 0x68, 0x65, 0x61,
+	0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x42, 0x0d, 0x0a, 0x0b, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x42, 0x6f, 0x64, 0x79, 0x22, 0x25, 0x0a, 0x0d, 0x50, 0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x22, 0x49, 0x0a, 0x11,
+	0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
+	0x72, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x22, 0xaa, 0x04, 0x0a, 0x14, 0x53, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x20, 0x0a, 0x0b, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x44, 0x61,
+	0x74, 0x61, 0x12, 0x7b, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x44, 0x61, 0x74, 0x61,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x5b, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72,
 	0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61,
 	0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e,
-	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4f,
-	0x70, 0x65, 0x6e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0b, 0x6f, 0x70,
-	0x65, 0x6e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x69, 0x0a, 0x0c, 0x63, 0x6c, 0x6f,
-	0x73, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x66, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x43, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61,
+	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x44, 0x61, 0x74, 0x61, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x44, 0x61, 0x74, 0x61, 0x12,
+	0x5c, 0x0a, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x44, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61,
 	0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e,
 	0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
-	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x53, 0x65, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0c, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x53, 0x65, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x66, 0x0a, 0x0b, 0x70, 0x69, 0x6e, 0x67, 0x53, 0x65, 0x73, 0x73,
-	0x69, 0x6f, 0x6e, 0x18, 0x67, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x42, 0x2e, 0x63, 0x65, 0x6e, 0x74,
-	0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f,
-	0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65,
-	0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52,
-	0x0b, 0x70, 0x69, 0x6e, 0x67, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x6c, 0x0a, 0x0d,
-	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x68, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x44, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69,
-	0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f,
-	0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73,
-	0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x48, 0x00, 0x52, 0x0d, 0x73, 0x65, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x42, 0x0d, 0x0a, 0x0b, 0x4d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x42, 0x6f, 0x64, 0x79, 0x22, 0x25, 0x0a, 0x0d, 0x50, 0x6f, 0x64,
-	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x6f,
-	0x64, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x70, 0x6f, 0x64, 0x49, 0x64,
-	0x22, 0x49, 0x0a, 0x11, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x65, 0x6e, 0x74,
-	0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x70, 0x6f, 0x64, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x69,
-	0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x22, 0x38, 0x0a, 0x14, 0x53,
-	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x44, 0x61,
-	0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
-	0x6e, 0x44, 0x61, 0x74, 0x61, 0x22, 0x8e, 0x01, 0x0a, 0x0b, 0x4f, 0x70, 0x65, 0x6e, 0x53, 0x65,
-	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x7f, 0x0a, 0x14, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
-	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x4b, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69,
-	0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f,
-	0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73,
-	0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x52, 0x14, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75,
-	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x3e, 0x0a, 0x0c, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x53,
-	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2e, 0x0a, 0x12, 0x67, 0x72, 0x61, 0x63, 0x65, 0x50,
-	0x65, 0x72, 0x69, 0x6f, 0x64, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x03, 0x52, 0x12, 0x67, 0x72, 0x61, 0x63, 0x65, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x53,
-	0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x22, 0x0d, 0x0a, 0x0b, 0x50, 0x69, 0x6e, 0x67, 0x53, 0x65,
-	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0xab, 0x01, 0x0a, 0x0d, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74,
-	0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2a, 0x0a, 0x10, 0x63, 0x6c, 0x69, 0x65, 0x6e,
-	0x74, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x10, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66,
-	0x69, 0x65, 0x72, 0x12, 0x36, 0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x4a, 0x6f, 0x69, 0x6e, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
-	0x70, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x4a, 0x6f, 0x69, 0x6e, 0x12, 0x36, 0x0a, 0x08, 0x74,
-	0x69, 0x6d, 0x65, 0x45, 0x78, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
-	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
-	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x45,
-	0x78, 0x69, 0x74, 0x22, 0xe4, 0x01, 0x0a, 0x0d, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x67, 0x0a, 0x0c, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
-	0x53, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x43, 0x2e, 0x63, 0x65,
-	0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e,
-	0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61,
-	0x67, 0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65,
-	0x52, 0x0c, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x6a,
-	0x0a, 0x0d, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18,
-	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x44, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x6c, 0x0a,
+	0x0e, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x44, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75,
 	0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78,
 	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x73,
-	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x6c,
-	0x69, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0d, 0x63, 0x6c, 0x69,
-	0x65, 0x6e, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2a, 0x83, 0x01, 0x0a, 0x0b, 0x4d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x55, 0x4e,
-	0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x19, 0x0a, 0x15, 0x53,
-	0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x55, 0x52, 0x41,
-	0x54, 0x49, 0x4f, 0x4e, 0x10, 0x64, 0x12, 0x10, 0x0a, 0x0c, 0x4f, 0x50, 0x45, 0x4e, 0x5f, 0x53,
-	0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x10, 0x65, 0x12, 0x11, 0x0a, 0x0d, 0x43, 0x4c, 0x4f, 0x53,
-	0x45, 0x5f, 0x53, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x10, 0x66, 0x12, 0x10, 0x0a, 0x0c, 0x50,
-	0x49, 0x4e, 0x47, 0x5f, 0x53, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x10, 0x67, 0x12, 0x11, 0x0a,
-	0x0d, 0x53, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x10, 0x68,
-	0x2a, 0x5b, 0x0a, 0x0c, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65,
-	0x12, 0x16, 0x0a, 0x12, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x49, 0x4e, 0x49, 0x54, 0x49, 0x41,
-	0x4c, 0x49, 0x5a, 0x49, 0x4e, 0x47, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x53, 0x54, 0x41, 0x54,
-	0x45, 0x5f, 0x4f, 0x50, 0x45, 0x4e, 0x10, 0x65, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x54, 0x41, 0x54,
-	0x45, 0x5f, 0x43, 0x4c, 0x4f, 0x53, 0x45, 0x44, 0x10, 0x66, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x54,
-	0x41, 0x54, 0x45, 0x5f, 0x43, 0x4c, 0x4f, 0x53, 0x49, 0x4e, 0x47, 0x10, 0x67, 0x32, 0x9b, 0x02,
-	0x0a, 0x0e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x12, 0x9b, 0x01, 0x0a, 0x0a, 0x67, 0x65, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
-	0x44, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x0e, 0x70, 0x72, 0x65,
+	0x76, 0x69, 0x6f, 0x75, 0x73, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x68, 0x0a, 0x0a, 0x61,
+	0x74, 0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x48, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61,
 	0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e,
 	0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
-	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x6f, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74,
-	0x69, 0x66, 0x69, 0x65, 0x72, 0x1a, 0x45, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x41,
+	0x74, 0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x0a, 0x61, 0x74, 0x74, 0x61, 0x63,
+	0x68, 0x6d, 0x65, 0x6e, 0x74, 0x1a, 0x3d, 0x0a, 0x0f, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x44,
+	0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x22, 0x7d, 0x0a, 0x11, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x41,
+	0x74, 0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x73,
+	0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
+	0x73, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x68, 0x61,
+	0x32, 0x35, 0x36, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35,
+	0x36, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54,
+	0x79, 0x70, 0x65, 0x22, 0xc6, 0x01, 0x0a, 0x0d, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x62, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x4e, 0x2e,
+	0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69,
+	0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64,
+	0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x1a, 0x37, 0x0a, 0x09, 0x44, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xf5, 0x01, 0x0a,
+	0x0b, 0x4f, 0x70, 0x65, 0x6e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x7f, 0x0a, 0x14,
+	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x4b, 0x2e, 0x63, 0x65, 0x6e,
+	0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66,
+	0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x14, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x65, 0x0a,
+	0x09, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x47, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72,
+	0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x6d, 0x69, 0x67, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x22, 0xe4, 0x01, 0x0a, 0x10, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x50, 0x6f, 0x64, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x50, 0x6f, 0x64, 0x49, 0x64, 0x12, 0x71, 0x0a, 0x08, 0x6d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x55, 0x2e,
+	0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69,
+	0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64,
+	0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4d, 0x69, 0x67,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x1a, 0x3b,
+	0x0a, 0x0d, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x3e, 0x0a, 0x0c, 0x43,
+	0x6c, 0x6f, 0x73, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2e, 0x0a, 0x12, 0x67,
+	0x72, 0x61, 0x63, 0x65, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12, 0x67, 0x72, 0x61, 0x63, 0x65, 0x50, 0x65,
+	0x72, 0x69, 0x6f, 0x64, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x22, 0x5e, 0x0a, 0x0c, 0x44,
+	0x72, 0x61, 0x69, 0x6e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x72,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x12, 0x36, 0x0a, 0x08, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x08, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x22, 0x62, 0x0a, 0x0e, 0x4d,
+	0x69, 0x67, 0x72, 0x61, 0x74, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a,
+	0x0b, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x50, 0x6f, 0x64, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x50, 0x6f, 0x64, 0x49, 0x64, 0x12,
+	0x2e, 0x0a, 0x12, 0x67, 0x72, 0x61, 0x63, 0x65, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x53, 0x65,
+	0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12, 0x67, 0x72, 0x61,
+	0x63, 0x65, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x22,
+	0x0d, 0x0a, 0x0b, 0x50, 0x69, 0x6e, 0x67, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x69,
+	0x0a, 0x09, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x12, 0x38, 0x0a, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x22, 0x0a, 0x0c, 0x6f, 0x70, 0x65, 0x6e, 0x53, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x6f, 0x70, 0x65,
+	0x6e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xd1, 0x01, 0x0a, 0x0d, 0x43, 0x6c,
+	0x69, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2a, 0x0a, 0x10, 0x63,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x36, 0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x4a,
+	0x6f, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x4a, 0x6f, 0x69, 0x6e, 0x12,
+	0x36, 0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x45, 0x78, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x74,
+	0x69, 0x6d, 0x65, 0x45, 0x78, 0x69, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0xb6, 0x03,
+	0x0a, 0x0d, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x67, 0x0a, 0x0c, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x43, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75,
 	0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78,
 	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x73,
 	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65,
-	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x30, 0x01, 0x12, 0x6b,
-	0x0a, 0x0a, 0x70, 0x75, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x45, 0x2e, 0x63,
-	0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f,
-	0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65,
-	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x42, 0x47, 0x5a, 0x45, 0x63,
-	0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f,
-	0x2f, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x6c, 0x65,
-	0x73, 0x73, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74,
-	0x2f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f,
-	0x67, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0c, 0x73, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x6a, 0x0a, 0x0d, 0x63, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x44, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61,
+	0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e,
+	0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0d, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x89, 0x01, 0x0a, 0x11, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x5b, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72,
+	0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x11, 0x6d,
+	0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x1a, 0x44, 0x0a, 0x16, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x2a, 0xba, 0x01, 0x0a, 0x0b, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x19, 0x0a, 0x15, 0x53, 0x45, 0x53, 0x53, 0x49,
+	0x4f, 0x4e, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x55, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e,
+	0x10, 0x64, 0x12, 0x10, 0x0a, 0x0c, 0x4f, 0x50, 0x45, 0x4e, 0x5f, 0x53, 0x45, 0x53, 0x53, 0x49,
+	0x4f, 0x4e, 0x10, 0x65, 0x12, 0x11, 0x0a, 0x0d, 0x43, 0x4c, 0x4f, 0x53, 0x45, 0x5f, 0x53, 0x45,
+	0x53, 0x53, 0x49, 0x4f, 0x4e, 0x10, 0x66, 0x12, 0x10, 0x0a, 0x0c, 0x50, 0x49, 0x4e, 0x47, 0x5f,
+	0x53, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x10, 0x67, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x45, 0x53,
+	0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x10, 0x68, 0x12, 0x11, 0x0a, 0x0d,
+	0x44, 0x52, 0x41, 0x49, 0x4e, 0x5f, 0x53, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x10, 0x69, 0x12,
+	0x13, 0x0a, 0x0f, 0x4d, 0x49, 0x47, 0x52, 0x41, 0x54, 0x45, 0x5f, 0x53, 0x45, 0x53, 0x53, 0x49,
+	0x4f, 0x4e, 0x10, 0x6a, 0x12, 0x0d, 0x0a, 0x09, 0x48, 0x45, 0x41, 0x52, 0x54, 0x42, 0x45, 0x41,
+	0x54, 0x10, 0x6b, 0x2a, 0x5b, 0x0a, 0x0c, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x12, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x49, 0x4e, 0x49,
+	0x54, 0x49, 0x41, 0x4c, 0x49, 0x5a, 0x49, 0x4e, 0x47, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x53,
+	0x54, 0x41, 0x54, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x4e, 0x10, 0x65, 0x12, 0x10, 0x0a, 0x0c, 0x53,
+	0x54, 0x41, 0x54, 0x45, 0x5f, 0x43, 0x4c, 0x4f, 0x53, 0x45, 0x44, 0x10, 0x66, 0x12, 0x11, 0x0a,
+	0x0d, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x43, 0x4c, 0x4f, 0x53, 0x49, 0x4e, 0x47, 0x10, 0x67,
+	0x32, 0xb9, 0x03, 0x0a, 0x0e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x12, 0x9b, 0x01, 0x0a, 0x0a, 0x67, 0x65, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x12, 0x44, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e,
+	0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x6f, 0x64, 0x49, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x1a, 0x45, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61,
+	0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72,
+	0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x30,
+	0x01, 0x12, 0x6b, 0x0a, 0x0a, 0x70, 0x75, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
+	0x45, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61,
+	0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e,
+	0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x9b,
+	0x01, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x12, 0x45, 0x2e, 0x63, 0x65, 0x6e,
+	0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66,
+	0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x1a, 0x45, 0x2e, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66,
+	0x72, 0x61, 0x2e, 0x69, 0x6f, 0x2e, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x63, 0x6f, 0x72, 0x65,
+	0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x28, 0x01, 0x30, 0x01, 0x42, 0x47, 0x5a, 0x45,
+	0x63, 0x65, 0x6e, 0x74, 0x61, 0x75, 0x72, 0x75, 0x73, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2e, 0x69,
+	0x6f, 0x2f, 0x66, 0x6f, 0x72, 0x6e, 0x61, 0x78, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x6c,
+	0x65, 0x73, 0x73, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x2f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2f, 0x67, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -871,7 +1483,7 @@ func file_pkg_nodeagent_sessionservice_grpc_session_service_proto_rawDescGZIP()
 }
 
 var file_pkg_nodeagent_sessionservice_grpc_session_service_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
 var file_pkg_nodeagent_sessionservice_grpc_session_service_proto_goTypes = []interface{}{
 	(MessageType)(0),             // 0: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.MessageType
 	(SessionState)(0),            // 1: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionState
@@ -879,36 +1491,61 @@ var file_pkg_nodeagent_sessionservice_grpc_session_service_proto_goTypes = []int
 	(*PodIdentifier)(nil),        // 3: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.PodIdentifier
 	(*SessionIdentifier)(nil),    // 4: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionIdentifier
 	(*SessionConfiguration)(nil), // 5: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionConfiguration
-	(*OpenSession)(nil),          // 6: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.OpenSession
-	(*CloseSession)(nil),         // 7: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.CloseSession
-	(*PingSession)(nil),          // 8: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.PingSession
-	(*ClientSession)(nil),        // 9: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.ClientSession
-	(*SessionStatus)(nil),        // 10: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionStatus
-	(*timestamp.Timestamp)(nil),  // 11: google.protobuf.Timestamp
-	(*empty.Empty)(nil),          // 12: google.protobuf.Empty
+	(*SessionAttachment)(nil),    // 6: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionAttachment
+	(*SessionSecret)(nil),        // 7: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionSecret
+	(*OpenSession)(nil),          // 8: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.OpenSession
+	(*SessionMigration)(nil),     // 9: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMigration
+	(*CloseSession)(nil),         // 10: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.CloseSession
+	(*DrainSession)(nil),         // 11: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.DrainSession
+	(*MigrateSession)(nil),       // 12: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.MigrateSession
+	(*PingSession)(nil),          // 13: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.PingSession
+	(*Heartbeat)(nil),            // 14: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.Heartbeat
+	(*ClientSession)(nil),        // 15: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.ClientSession
+	(*SessionStatus)(nil),        // 16: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionStatus
+	nil,                          // 17: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionConfiguration.ConfigDataEntry
+	nil,                          // 18: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionSecret.DataEntry
+	nil,                          // 19: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMigration.MetadataEntry
+	nil,                          // 20: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionStatus.MigrationMetadataEntry
+	(*timestamp.Timestamp)(nil),  // 21: google.protobuf.Timestamp
+	(*empty.Empty)(nil),          // 22: google.protobuf.Empty
 }
 var file_pkg_nodeagent_sessionservice_grpc_session_service_proto_depIdxs = []int32{
 	4,  // 0: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMessage.sessionIdentifier:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionIdentifier
 	0,  // 1: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMessage.messageType:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.MessageType
 	5,  // 2: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMessage.sessionConfiguration:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionConfiguration
-	6,  // 3: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMessage.openSession:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.OpenSession
-	7,  // 4: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMessage.closeSession:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.CloseSession
-	8,  // 5: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMessage.pingSession:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.PingSession
-	10, // 6: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMessage.sessionStatus:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionStatus
-	5,  // 7: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.OpenSession.sessionConfiguration:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionConfiguration
-	11, // 8: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.ClientSession.timeJoin:type_name -> google.protobuf.Timestamp
-	11, // 9: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.ClientSession.timeExit:type_name -> google.protobuf.Timestamp
-	1,  // 10: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionStatus.sessionState:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionState
-	9,  // 11: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionStatus.clientSession:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.ClientSession
-	3,  // 12: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionService.getMessage:input_type -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.PodIdentifier
-	2,  // 13: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionService.putMessage:input_type -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMessage
-	2,  // 14: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionService.getMessage:output_type -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMessage
-	12, // 15: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionService.putMessage:output_type -> google.protobuf.Empty
-	14, // [14:16] is the sub-list for method output_type
-	12, // [12:14] is the sub-list for method input_type
-	12, // [12:12] is the sub-list for extension type_name
-	12, // [12:12] is the sub-list for extension extendee
-	0,  // [0:12] is the sub-list for field type_name
+	8,  // 3: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMessage.openSession:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.OpenSession
+	10, // 4: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMessage.closeSession:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.CloseSession
+	13, // 5: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMessage.pingSession:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.PingSession
+	16, // 6: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMessage.sessionStatus:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionStatus
+	11, // 7: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMessage.drainSession:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.DrainSession
+	12, // 8: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMessage.migrateSession:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.MigrateSession
+	14, // 9: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMessage.heartbeat:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.Heartbeat
+	17, // 10: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionConfiguration.configData:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionConfiguration.ConfigDataEntry
+	7,  // 11: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionConfiguration.secret:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionSecret
+	7,  // 12: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionConfiguration.previousSecret:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionSecret
+	6,  // 13: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionConfiguration.attachment:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionAttachment
+	18, // 14: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionSecret.data:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionSecret.DataEntry
+	5,  // 15: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.OpenSession.sessionConfiguration:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionConfiguration
+	9,  // 16: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.OpenSession.migration:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMigration
+	19, // 17: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMigration.metadata:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMigration.MetadataEntry
+	21, // 18: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.DrainSession.deadline:type_name -> google.protobuf.Timestamp
+	21, // 19: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.Heartbeat.timestamp:type_name -> google.protobuf.Timestamp
+	21, // 20: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.ClientSession.timeJoin:type_name -> google.protobuf.Timestamp
+	21, // 21: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.ClientSession.timeExit:type_name -> google.protobuf.Timestamp
+	1,  // 22: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionStatus.sessionState:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionState
+	15, // 23: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionStatus.clientSession:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.ClientSession
+	20, // 24: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionStatus.migrationMetadata:type_name -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionStatus.MigrationMetadataEntry
+	3,  // 25: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionService.getMessage:input_type -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.PodIdentifier
+	2,  // 26: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionService.putMessage:input_type -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMessage
+	2,  // 27: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionService.connect:input_type -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMessage
+	2,  // 28: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionService.getMessage:output_type -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMessage
+	22, // 29: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionService.putMessage:output_type -> google.protobuf.Empty
+	2,  // 30: centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionService.connect:output_type -> centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionMessage
+	28, // [28:31] is the sub-list for method output_type
+	25, // [25:28] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
 }
 
 func init() { file_pkg_nodeagent_sessionservice_grpc_session_service_proto_init() }
@@ -966,7 +1603,7 @@ func file_pkg_nodeagent_sessionservice_grpc_session_service_proto_init() {
 			}
 		}
 		file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*OpenSession); i {
+			switch v := v.(*SessionAttachment); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -978,7 +1615,7 @@ func file_pkg_nodeagent_sessionservice_grpc_session_service_proto_init() {
 			}
 		}
 		file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CloseSession); i {
+			switch v := v.(*SessionSecret); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -990,7 +1627,7 @@ func file_pkg_nodeagent_sessionservice_grpc_session_service_proto_init() {
 			}
 		}
 		file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PingSession); i {
+			switch v := v.(*OpenSession); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1002,7 +1639,7 @@ func file_pkg_nodeagent_sessionservice_grpc_session_service_proto_init() {
 			}
 		}
 		file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ClientSession); i {
+			switch v := v.(*SessionMigration); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1014,6 +1651,78 @@ func file_pkg_nodeagent_sessionservice_grpc_session_service_proto_init() {
 			}
 		}
 		file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CloseSession); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DrainSession); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MigrateSession); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PingSession); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Heartbeat); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ClientSession); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_nodeagent_sessionservice_grpc_session_service_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SessionStatus); i {
 			case 0:
 				return &v.state
@@ -1032,6 +1741,9 @@ func file_pkg_nodeagent_sessionservice_grpc_session_service_proto_init() {
 		(*SessionMessage_CloseSession)(nil),
 		(*SessionMessage_PingSession)(nil),
 		(*SessionMessage_SessionStatus)(nil),
+		(*SessionMessage_DrainSession)(nil),
+		(*SessionMessage_MigrateSession)(nil),
+		(*SessionMessage_Heartbeat)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -1039,7 +1751,7 @@ func file_pkg_nodeagent_sessionservice_grpc_session_service_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pkg_nodeagent_sessionservice_grpc_session_service_proto_rawDesc,
 			NumEnums:      2,
-			NumMessages:   9,
+			NumMessages:   19,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
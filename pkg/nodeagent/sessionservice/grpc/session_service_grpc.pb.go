@@ -25,6 +25,10 @@ const _ = grpc.SupportPackageIsVersion7
 type SessionServiceClient interface {
 	GetMessage(ctx context.Context, in *PodIdentifier, opts ...grpc.CallOption) (SessionService_GetMessageClient, error)
 	PutMessage(ctx context.Context, in *SessionMessage, opts ...grpc.CallOption) (*empty.Empty, error)
+	// v2 protocol, a bidirectional stream carry session commands and session states of a pod,
+	// first message sent by pod must be a heartbeat with pod id in sessionIdentifier,
+	// node agent and pod send heartbeat to each other, stream is closed if peer do not send any message in heartbeat timeout
+	Connect(ctx context.Context, opts ...grpc.CallOption) (SessionService_ConnectClient, error)
 }
 
 type sessionServiceClient struct {
@@ -76,12 +80,47 @@ func (c *sessionServiceClient) PutMessage(ctx context.Context, in *SessionMessag
 	return out, nil
 }
 
+func (c *sessionServiceClient) Connect(ctx context.Context, opts ...grpc.CallOption) (SessionService_ConnectClient, error) {
+	stream, err := c.cc.NewStream(ctx, &SessionService_ServiceDesc.Streams[1], "/centaurusinfra.io.fornaxcore.nodeagent.sessionservice.SessionService/connect", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sessionServiceConnectClient{stream}
+	return x, nil
+}
+
+type SessionService_ConnectClient interface {
+	Send(*SessionMessage) error
+	Recv() (*SessionMessage, error)
+	grpc.ClientStream
+}
+
+type sessionServiceConnectClient struct {
+	grpc.ClientStream
+}
+
+func (x *sessionServiceConnectClient) Send(m *SessionMessage) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *sessionServiceConnectClient) Recv() (*SessionMessage, error) {
+	m := new(SessionMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // SessionServiceServer is the server API for SessionService service.
 // All implementations must embed UnimplementedSessionServiceServer
 // for forward compatibility
 type SessionServiceServer interface {
 	GetMessage(*PodIdentifier, SessionService_GetMessageServer) error
 	PutMessage(context.Context, *SessionMessage) (*empty.Empty, error)
+	// v2 protocol, a bidirectional stream carry session commands and session states of a pod,
+	// first message sent by pod must be a heartbeat with pod id in sessionIdentifier,
+	// node agent and pod send heartbeat to each other, stream is closed if peer do not send any message in heartbeat timeout
+	Connect(SessionService_ConnectServer) error
 	mustEmbedUnimplementedSessionServiceServer()
 }
 
@@ -95,6 +134,9 @@ func (UnimplementedSessionServiceServer) GetMessage(*PodIdentifier, SessionServi
 func (UnimplementedSessionServiceServer) PutMessage(context.Context, *SessionMessage) (*empty.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PutMessage not implemented")
 }
+func (UnimplementedSessionServiceServer) Connect(SessionService_ConnectServer) error {
+	return status.Errorf(codes.Unimplemented, "method Connect not implemented")
+}
 func (UnimplementedSessionServiceServer) mustEmbedUnimplementedSessionServiceServer() {}
 
 // UnsafeSessionServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -147,6 +189,32 @@ func _SessionService_PutMessage_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SessionService_Connect_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SessionServiceServer).Connect(&sessionServiceConnectServer{stream})
+}
+
+type SessionService_ConnectServer interface {
+	Send(*SessionMessage) error
+	Recv() (*SessionMessage, error)
+	grpc.ServerStream
+}
+
+type sessionServiceConnectServer struct {
+	grpc.ServerStream
+}
+
+func (x *sessionServiceConnectServer) Send(m *SessionMessage) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *sessionServiceConnectServer) Recv() (*SessionMessage, error) {
+	m := new(SessionMessage)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // SessionService_ServiceDesc is the grpc.ServiceDesc for SessionService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -165,6 +233,12 @@ var SessionService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _SessionService_GetMessage_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "connect",
+			Handler:       _SessionService_Connect_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "pkg/nodeagent/sessionservice/grpc/session_service.proto",
 }
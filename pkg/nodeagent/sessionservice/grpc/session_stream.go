@@ -0,0 +1,144 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultSessionStreamHeartbeatPeriod  = 5 * time.Second
+	DefaultSessionStreamHeartbeatTimeout = 3 * DefaultSessionStreamHeartbeatPeriod
+)
+
+var (
+	SessionStreamNotIdentified    = errors.New("first message of session stream must be a heartbeat with pod id")
+	SessionStreamHeartbeatTimeout = errors.New("session stream did not receive any message from pod in heartbeat timeout")
+)
+
+// Connect serve v2 bidirectional stream of a pod, session commands are sent and session states are received on same stream,
+// every message from pod keep stream alive, heartbeat from pod keep its open sessions alive, so they are not pinged,
+// if stream is broken or timeout, sessions fall back to be pinged until pod reconnect or they are considered dead
+func (g *GrpcSessionService) Connect(server SessionService_ConnectServer) error {
+	first, err := server.Recv()
+	if err != nil {
+		return err
+	}
+	podId := first.GetSessionIdentifier().GetPodId()
+	if first.GetMessageType() != MessageType_HEARTBEAT || len(podId) == 0 {
+		return SessionStreamNotIdentified
+	}
+	if err := g.authorizePod(server.Context(), podId); err != nil {
+		return err
+	}
+
+	klog.InfoS("Received session stream connection from pod", "pod", podId)
+	ch := make(chan *SessionMessage, 10)
+	if err := g.enlistPod(podId, ch); err != nil {
+		return fmt.Errorf("Only one session stream connection is allowed from one pod")
+	}
+	defer g.delistPod(podId)
+	g.handleSessionMessage(podId, first)
+
+	var lastReceived int64 = time.Now().UnixNano()
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			msg, err := server.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			atomic.StoreInt64(&lastReceived, time.Now().UnixNano())
+			g.handleSessionMessage(podId, msg)
+		}
+	}()
+
+	var messageSeq int64 = 0
+	send := func(msg *SessionMessage) error {
+		messageSeq += 1
+		msg.MessageIdentifier = fmt.Sprintf("%d", messageSeq)
+		return server.Send(msg)
+	}
+	ticker := time.NewTicker(DefaultSessionStreamHeartbeatPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-server.Context().Done():
+			return nil
+		case err := <-recvErr:
+			if err == io.EOF {
+				klog.InfoS("Pod closed session stream", "pod", podId)
+				return nil
+			}
+			klog.ErrorS(err, "Failed to receive message from session stream", "pod", podId)
+			return err
+		case <-ticker.C:
+			if time.Since(time.Unix(0, atomic.LoadInt64(&lastReceived))) > DefaultSessionStreamHeartbeatTimeout {
+				klog.ErrorS(SessionStreamHeartbeatTimeout, "Close session stream", "pod", podId)
+				return SessionStreamHeartbeatTimeout
+			}
+			if err := send(g.buildHeartbeat(podId)); err != nil {
+				klog.ErrorS(err, "Failed to send heartbeat via session stream", "pod", podId)
+				return err
+			}
+		case msg := <-ch:
+			if err := send(msg); err != nil {
+				klog.ErrorS(err, "Failed to send message via session stream", "pod", podId)
+				return err
+			}
+		}
+	}
+}
+
+// buildHeartbeat return heartbeat with sessions node agent think are open on pod, pod can tell which sessions it lost or node agent forgot
+func (g *GrpcSessionService) buildHeartbeat(podId string) *SessionMessage {
+	openSessions := []string{}
+	for _, v := range g.getSessions() {
+		if v.pod.Identifier == podId {
+			openSessions = append(openSessions, v.session.Identifier)
+		}
+	}
+	return &SessionMessage{
+		SessionIdentifier: &SessionIdentifier{PodId: podId},
+		MessageType:       MessageType_HEARTBEAT,
+		MessageBody: &SessionMessage_Heartbeat{
+			Heartbeat: &Heartbeat{
+				Timestamp:    timestamppb.Now(),
+				OpenSessions: openSessions,
+			},
+		},
+	}
+}
+
+// refreshSessionHeartbeats mark sessions reported open by pod as seen, other sessions of pod are pinged by heartbeat check
+func (g *GrpcSessionService) refreshSessionHeartbeats(podId string, sessionIds []string) {
+	now := time.Now()
+	for _, v := range sessionIds {
+		if heartbeat := g.getSessionHeartbeat(v); heartbeat != nil && heartbeat.pod.Identifier == podId {
+			heartbeat.lastSeen = now
+			heartbeat.consectuivePingFailures = 0
+		}
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"centaurusinfra.io/fornax-serverless/pkg/pki"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// session service client find pod certificate, key and session service CA in dir of this env
+	SessionServiceTLSDirEnv = "FORNAX_SESSION_SERVICE_TLS_DIR"
+	// dir in container where pod tls dir is mounted read only
+	SessionServiceTLSContainerDir = "/var/run/fornax/session-tls"
+	SessionServiceTLSCertFile     = "tls.crt"
+	SessionServiceTLSKeyFile      = "tls.key"
+	SessionServiceTLSCAFile       = "ca.crt"
+
+	DefaultPodCertificateCheckDuration = 10 * time.Minute
+)
+
+var (
+	PodPermissionDeniedError = grpcstatus.Error(codes.PermissionDenied, "pod client certificate does not match pod identifier")
+)
+
+// SessionServiceTLS is mtls of node session service, a node local CA issue certificate of session service and client certificates of pods,
+// pod certificate is written into pod tls dir which is mounted into pod containers, it's reissued in same dir before it expire
+type SessionServiceTLS struct {
+	mu                sync.Mutex
+	ca                *pki.CertificateAuthority
+	serverCertificate *pki.RotatingCertificate
+	// tls dir by pod identifier
+	pods map[string]string
+}
+
+// NewSessionServiceTLS load or create node local session CA in pki dir, session service certificate is valid for node ips
+func NewSessionServiceTLS(pkiDir string, nodeIPs []string) (*SessionServiceTLS, error) {
+	ca, err := pki.LoadOrCreateCertificateAuthority(filepath.Join(pkiDir, "session-ca.crt"), filepath.Join(pkiDir, "session-ca.key"), "fornax-session-service-ca")
+	if err != nil {
+		return nil, err
+	}
+	ips := []net.IP{}
+	for _, v := range nodeIPs {
+		if ip := net.ParseIP(v); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+	s := &SessionServiceTLS{ca: ca, pods: map[string]string{}}
+	s.serverCertificate, err = pki.NewRotatingCertificate("session-service-server", nil, func(*tls.Certificate) (*pki.KeyPair, error) {
+		return ca.Issue(pkix.Name{CommonName: "fornax-session-service"}, nil, ips, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}, pki.DefaultClientCertValidity)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// TLSConfig require pods to use a client certificate issued by session CA
+func (s *SessionServiceTLS) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: s.serverCertificate.GetCertificate,
+		ClientAuth:     tls.RequireAndVerifyClientCert,
+		ClientCAs:      s.ca.CertPool(),
+	}
+}
+
+// IssuePodCertificate write a client certificate of pod into tls dir
+func (s *SessionServiceTLS) IssuePodCertificate(podId, dir string) error {
+	pair, err := s.ca.Issue(pkix.Name{CommonName: pki.PodCommonName(podId)}, nil, nil, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, pki.DefaultClientCertValidity)
+	if err != nil {
+		return err
+	}
+	if err := pki.WriteKeyPair(filepath.Join(dir, SessionServiceTLSCertFile), filepath.Join(dir, SessionServiceTLSKeyFile), pair); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, SessionServiceTLSCAFile), s.ca.CertPEM(), 0644); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pods[podId] = dir
+	return nil
+}
+
+// ForgetPod stop renewing certificate of a terminated pod
+func (s *SessionServiceTLS) ForgetPod(podId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pods, podId)
+}
+
+func (s *SessionServiceTLS) renewPodCertificates() {
+	s.mu.Lock()
+	pods := map[string]string{}
+	for k, v := range s.pods {
+		pods[k] = v
+	}
+	s.mu.Unlock()
+	for podId, dir := range pods {
+		pair := &pki.KeyPair{}
+		var err error
+		if pair.CertPEM, err = os.ReadFile(filepath.Join(dir, SessionServiceTLSCertFile)); err == nil {
+			if pair.KeyPEM, err = os.ReadFile(filepath.Join(dir, SessionServiceTLSKeyFile)); err == nil {
+				var cert *tls.Certificate
+				if cert, err = pki.NewTLSCertificate(pair); err == nil && time.Now().Before(pki.RotationDeadline(cert)) {
+					continue
+				}
+			}
+		}
+		if os.IsNotExist(err) {
+			if _, statErr := os.Stat(dir); os.IsNotExist(statErr) {
+				// pod dir is cleaned up
+				s.ForgetPod(podId)
+				continue
+			}
+		}
+		if err := s.IssuePodCertificate(podId, dir); err != nil {
+			klog.ErrorS(err, "Failed to renew pod session service certificate", "pod", podId)
+			continue
+		}
+		klog.InfoS("Renewed pod session service certificate", "pod", podId)
+	}
+}
+
+// Run rotate session service certificate and renew pod certificates until context is done
+func (s *SessionServiceTLS) Run(ctx context.Context) {
+	go s.serverCertificate.Run(ctx)
+	go func() {
+		ticker := time.NewTicker(DefaultPodCertificateCheckDuration)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.renewPodCertificates()
+			}
+		}
+	}()
+}
+
+// authorizePod check client certificate of call is certificate of pod, it's always allowed if mtls is not enabled
+func (g *GrpcSessionService) authorizePod(ctx context.Context, podId string) error {
+	if g.tls == nil {
+		return nil
+	}
+	if !pki.PeerIs(ctx, pki.PodCommonName(podId)) {
+		klog.InfoS("Reject session service call without certificate of pod", "pod", podId)
+		return PodPermissionDeniedError
+	}
+	return nil
+}
+
+// SetTLS require mtls of pod connections, it must be called before Run
+func (g *GrpcSessionService) SetTLS(tls *SessionServiceTLS) {
+	g.tls = tls
+}
@@ -18,7 +18,9 @@ package sessionservice
 
 import (
 	"errors"
+	"time"
 
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxerrors"
 	internal "centaurusinfra.io/fornax-serverless/pkg/nodeagent/message"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
 )
@@ -26,7 +28,7 @@ import (
 var (
 	SessionNotFound                 = errors.New("Session not found")
 	SessionAlreadyExist             = errors.New("Session is already open")
-	SessionStreamDisconnected       = errors.New("Session stream not connected")
+	SessionStreamDisconnected       = fornaxerrors.New(fornaxerrors.SessionServiceUnavailable, "Session stream not connected")
 	SessionStreamAlreadyEstablished = errors.New("only one stream connection is allowed from one instance")
 )
 
@@ -34,4 +36,7 @@ type SessionService interface {
 	OpenSession(pod *types.FornaxPod, session *types.FornaxSession, stateCallbackFunc func(internal.SessionState)) error
 	CloseSession(pod *types.FornaxPod, session *types.FornaxSession, graceSeconds uint16) error
 	PingSession(pod *types.FornaxPod, session *types.FornaxSession, stateCallbackFunc func(internal.SessionState)) error
+	UpdateSessionConfig(pod *types.FornaxPod, session *types.FornaxSession) error
+	DrainSession(pod *types.FornaxPod, session *types.FornaxSession, reason string, deadline time.Time) error
+	MigrateSession(pod *types.FornaxPod, session *types.FornaxSession, targetPod string, graceSeconds uint16) error
 }
@@ -17,6 +17,8 @@ limitations under the License.
 package sessionservice
 
 import (
+	"time"
+
 	internal "centaurusinfra.io/fornax-serverless/pkg/nodeagent/message"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
 )
@@ -53,8 +55,26 @@ func (f *NullSessionService) OpenSession(pod *types.FornaxPod, session *types.Fo
 	return nil
 }
 
-// Ping implements SessionService
+// Ping implements SessionService, session of pod without session service live as long as pod,
+// a session recovered after node agent restart is valid, just save its state callback again
 func (f *NullSessionService) PingSession(pod *types.FornaxPod, session *types.FornaxSession, stateCallbackFunc func(internal.SessionState)) error {
+	if _, found := f.stateCallbackFuncs[session.Identifier]; !found {
+		f.stateCallbackFuncs[session.Identifier] = stateCallbackFunc
+	}
+	return nil
+}
+
+// UpdateSessionConfig implements SessionService
+func (f *NullSessionService) UpdateSessionConfig(pod *types.FornaxPod, session *types.FornaxSession) error {
+	if _, found := f.stateCallbackFuncs[session.Identifier]; found {
+		return nil
+	} else {
+		return SessionNotFound
+	}
+}
+
+// DrainSession implements SessionService
+func (f *NullSessionService) DrainSession(pod *types.FornaxPod, session *types.FornaxSession, reason string, deadline time.Time) error {
 	if _, found := f.stateCallbackFuncs[session.Identifier]; found {
 		return nil
 	} else {
@@ -62,6 +82,11 @@ func (f *NullSessionService) PingSession(pod *types.FornaxPod, session *types.Fo
 	}
 }
 
+// MigrateSession implements SessionService, there is no application state to export, session is just closed
+func (f *NullSessionService) MigrateSession(pod *types.FornaxPod, session *types.FornaxSession, targetPod string, graceSeconds uint16) error {
+	return f.CloseSession(pod, session, graceSeconds)
+}
+
 // NullSessionService used when pod do not use session service to open/close session, have a NullSessionService just make the pod actor handle sessions in same way for all pods no matter they use session service or not.
 // it does not check session status, it just return a dumb message to fool pod actor
 func NewNullSessionService() *NullSessionService {
@@ -17,37 +17,63 @@ limitations under the License.
 package server
 
 import (
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
 	internal "centaurusinfra.io/fornax-serverless/pkg/nodeagent/message"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/sessionservice"
-	session_grpc "centaurusinfra.io/fornax-serverless/pkg/nodeagent/sessionservice/grpc"
 	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
 )
 
 var _ sessionservice.SessionService = &sessionServer{}
 
+// sessionServer dispatch session lifecycle to session service of session runtime annotated on pod
 type sessionServer struct {
-	nullService *sessionservice.NullSessionService
-	grpcService *session_grpc.GrpcSessionService
+	grpcService    sessionservice.SessionService
+	sidecarService sessionservice.SessionService
+}
+
+func (s *sessionServer) runtimeService(pod *types.FornaxPod) sessionservice.SessionService {
+	if util.PodSessionRuntime(pod.Pod) == fornaxv1.SessionRuntimeWebSocket {
+		return s.sidecarService
+	}
+	return s.grpcService
 }
 
 // CloseSession implements sessionservice.SessionService
-func (*sessionServer) CloseSession(pod *types.FornaxPod, session *types.FornaxSession, graceSeconds uint16) error {
-	panic("unimplemented")
+func (s *sessionServer) CloseSession(pod *types.FornaxPod, session *types.FornaxSession, graceSeconds uint16) error {
+	return s.runtimeService(pod).CloseSession(pod, session, graceSeconds)
 }
 
 // OpenSession implements sessionservice.SessionService
-func (*sessionServer) OpenSession(pod *types.FornaxPod, session *types.FornaxSession, stateCallbackFunc func(internal.SessionState)) error {
-	panic("unimplemented")
+func (s *sessionServer) OpenSession(pod *types.FornaxPod, session *types.FornaxSession, stateCallbackFunc func(internal.SessionState)) error {
+	return s.runtimeService(pod).OpenSession(pod, session, stateCallbackFunc)
 }
 
 // PingSession implements sessionservice.SessionService
-func (*sessionServer) PingSession(pod *types.FornaxPod, session *types.FornaxSession, stateCallbackFunc func(internal.SessionState)) error {
-	panic("unimplemented")
+func (s *sessionServer) PingSession(pod *types.FornaxPod, session *types.FornaxSession, stateCallbackFunc func(internal.SessionState)) error {
+	return s.runtimeService(pod).PingSession(pod, session, stateCallbackFunc)
+}
+
+// UpdateSessionConfig implements sessionservice.SessionService
+func (s *sessionServer) UpdateSessionConfig(pod *types.FornaxPod, session *types.FornaxSession) error {
+	return s.runtimeService(pod).UpdateSessionConfig(pod, session)
+}
+
+// DrainSession implements sessionservice.SessionService
+func (s *sessionServer) DrainSession(pod *types.FornaxPod, session *types.FornaxSession, reason string, deadline time.Time) error {
+	return s.runtimeService(pod).DrainSession(pod, session, reason, deadline)
+}
+
+// MigrateSession implements sessionservice.SessionService
+func (s *sessionServer) MigrateSession(pod *types.FornaxPod, session *types.FornaxSession, targetPod string, graceSeconds uint16) error {
+	return s.runtimeService(pod).MigrateSession(pod, session, targetPod, graceSeconds)
 }
 
-func NewSessionService() *sessionServer {
+func NewSessionService(grpcService, sidecarService sessionservice.SessionService) *sessionServer {
 	return &sessionServer{
-		nullService: &sessionservice.NullSessionService{},
-		grpcService: session_grpc.NewSessionService(),
+		grpcService:    grpcService,
+		sidecarService: sidecarService,
 	}
 }
@@ -0,0 +1,119 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecar
+
+import (
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+)
+
+// websocket session runtime protocol, node agent dial ws://<pod ip>:<sidecar port>/fornax/session and exchange one json message per frame,
+// node agent send open, close, ping, config, drain and migrate message of a session, sidecar report session state via state message,
+// both send heartbeat message, sidecar report all its open sessions in heartbeat
+const (
+	SidecarSessionPath = "/fornax/session"
+
+	MessageTypeOpen      = "open"
+	MessageTypeClose     = "close"
+	MessageTypePing      = "ping"
+	MessageTypeConfig    = "config"
+	MessageTypeDrain     = "drain"
+	MessageTypeMigrate   = "migrate"
+	MessageTypeState     = "state"
+	MessageTypeHeartbeat = "heartbeat"
+
+	SessionStateInitializing = "initializing"
+	SessionStateOpen         = "open"
+	SessionStateClosing      = "closing"
+	SessionStateClosed       = "closed"
+)
+
+type SidecarClientSession struct {
+	Identifier string `json:"identifier"`
+
+	// +optional
+	SourceAddress string `json:"sourceAddress,omitempty"`
+
+	// +optional
+	JoinTime *time.Time `json:"joinTime,omitempty"`
+
+	// client left session if set
+	// +optional
+	LeaveTime *time.Time `json:"leaveTime,omitempty"`
+}
+
+type SidecarSecret struct {
+	Version string            `json:"version,omitempty"`
+	Data    map[string][]byte `json:"data,omitempty"`
+}
+
+type SidecarMessage struct {
+	Type string `json:"type"`
+
+	// +optional
+	PodId string `json:"podId,omitempty"`
+
+	// +optional
+	SessionId string `json:"sessionId,omitempty"`
+
+	// +optional
+	RequestId string `json:"requestId,omitempty"`
+
+	// session data of open and config message
+	// +optional
+	SessionData string `json:"sessionData,omitempty"`
+
+	// +optional
+	Attachment *fornaxv1.SessionAttachment `json:"attachment,omitempty"`
+
+	// +optional
+	ConfigData map[string]string `json:"configData,omitempty"`
+
+	// +optional
+	Secret *SidecarSecret `json:"secret,omitempty"`
+
+	// +optional
+	PreviousSecret *SidecarSecret `json:"previousSecret,omitempty"`
+
+	// grace period of close and migrate message
+	// +optional
+	GraceSeconds uint16 `json:"graceSeconds,omitempty"`
+
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// +optional
+	Deadline *time.Time `json:"deadline,omitempty"`
+
+	// +optional
+	TargetPod string `json:"targetPod,omitempty"`
+
+	// session metadata imported in open message of a migrated session, or exported in closed state of a migrating session
+	// +optional
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// +optional
+	ClientSessions []SidecarClientSession `json:"clientSessions,omitempty"`
+
+	// open sessions of heartbeat sent by sidecar
+	// +optional
+	OpenSessions []string `json:"openSessions,omitempty"`
+}
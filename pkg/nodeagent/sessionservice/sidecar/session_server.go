@@ -0,0 +1,447 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	internal "centaurusinfra.io/fornax-serverless/pkg/nodeagent/message"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/sessionservice"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/types"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+	"golang.org/x/net/websocket"
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultSessionSidecarPort                   = 8099
+	DefaultSidecarHeartbeatPeriod               = 10 * time.Second
+	DefaultDeadSessionHeartbeatMissingThreshold = 3
+	DefaultSidecarIOTimeout                     = 3 * time.Second
+)
+
+var (
+	SidecarAddressNotFound = errors.New("Can not find ip address of pod to connect session sidecar")
+)
+
+type sidecarConnection struct {
+	podId  string
+	mu     sync.Mutex
+	conn   *websocket.Conn
+	closed bool
+}
+
+func (c *sidecarConnection) send(msg *SidecarMessage) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(DefaultSidecarIOTimeout))
+	return websocket.JSON.Send(c.conn, msg)
+}
+
+type sidecarSession struct {
+	pod                     *types.FornaxPod
+	session                 *types.FornaxSession
+	stateCallback           func(internal.SessionState)
+	lastSeen                time.Time
+	consectuivePingFailures uint16
+}
+
+var _ sessionservice.SessionService = &SidecarSessionService{}
+
+// SidecarSessionService drive session lifecycle of pods using websocket session runtime, it dial sidecar in pod on first message of pod
+// and keep connection until sidecar disconnect, sessions not seen in heartbeats are pinged and reported as no heartbeat at last
+type SidecarSessionService struct {
+	mu sync.RWMutex
+
+	// connection to pod sidecar by pod id
+	connections map[string]*sidecarConnection
+
+	// state callback and heartbeat by session id
+	sessions map[string]*sidecarSession
+}
+
+func NewSidecarSessionService() *SidecarSessionService {
+	return &SidecarSessionService{
+		connections: map[string]*sidecarConnection{},
+		sessions:    map[string]*sidecarSession{},
+	}
+}
+
+func (s *SidecarSessionService) Run(ctx context.Context) {
+	klog.Info("Starting websocket sidecar session service")
+	go func() {
+		ticker := time.NewTicker(DefaultSidecarHeartbeatPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				s.closeConnections()
+				return
+			case <-ticker.C:
+				s.sendHeartbeats()
+				s.checkAndCleanSessionHeartbeat()
+			}
+		}
+	}()
+}
+
+// sidecarURL return websocket url of sidecar listening on pod ip and port annotated on pod
+func sidecarURL(pod *types.FornaxPod) (string, error) {
+	if pod.RuntimePod == nil || len(pod.RuntimePod.IPs) == 0 {
+		return "", SidecarAddressNotFound
+	}
+	port := DefaultSessionSidecarPort
+	if v, found := pod.Pod.GetAnnotations()[fornaxv1.AnnotationFornaxCoreSessionSidecarPort]; found {
+		if p, err := strconv.Atoi(v); err == nil {
+			port = p
+		}
+	}
+	return fmt.Sprintf("ws://%s%s", net.JoinHostPort(pod.RuntimePod.IPs[0], strconv.Itoa(port)), SidecarSessionPath), nil
+}
+
+func (s *SidecarSessionService) getConnection(pod *types.FornaxPod) (*sidecarConnection, error) {
+	s.mu.RLock()
+	c, found := s.connections[pod.Identifier]
+	s.mu.RUnlock()
+	if found {
+		return c, nil
+	}
+
+	url, err := sidecarURL(pod)
+	if err != nil {
+		return nil, err
+	}
+	config, err := websocket.NewConfig(url, "http://localhost/")
+	if err != nil {
+		return nil, err
+	}
+	config.Dialer = &net.Dialer{Timeout: DefaultSidecarIOTimeout}
+	conn, err := websocket.DialConfig(config)
+	if err != nil {
+		klog.ErrorS(err, "Failed to connect session sidecar", "pod", pod.Identifier, "url", url)
+		return nil, sessionservice.SessionStreamDisconnected
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, found := s.connections[pod.Identifier]; found {
+		// raced with another session of same pod
+		conn.Close()
+		return c, nil
+	}
+	klog.InfoS("Connected session sidecar", "pod", pod.Identifier, "url", url)
+	c = &sidecarConnection{podId: pod.Identifier, conn: conn}
+	s.connections[pod.Identifier] = c
+	go s.receive(c)
+	return c, nil
+}
+
+func (s *SidecarSessionService) closeConnection(c *sidecarConnection) {
+	s.mu.Lock()
+	if v, found := s.connections[c.podId]; found && v == c {
+		delete(s.connections, c.podId)
+	}
+	s.mu.Unlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.closed {
+		c.closed = true
+		c.conn.Close()
+	}
+}
+
+func (s *SidecarSessionService) closeConnections() {
+	s.mu.RLock()
+	connections := []*sidecarConnection{}
+	for _, c := range s.connections {
+		connections = append(connections, c)
+	}
+	s.mu.RUnlock()
+	for _, c := range connections {
+		s.closeConnection(c)
+	}
+}
+
+// receive read messages from sidecar until it disconnect, sessions of pod are pinged again via a new connection in heartbeat check
+func (s *SidecarSessionService) receive(c *sidecarConnection) {
+	defer s.closeConnection(c)
+	for {
+		msg := &SidecarMessage{}
+		if err := websocket.JSON.Receive(c.conn, msg); err != nil {
+			klog.InfoS("Session sidecar disconnected", "pod", c.podId, "err", err)
+			return
+		}
+		s.handleSidecarMessage(c.podId, msg)
+	}
+}
+
+func clientSessions(msg *SidecarMessage) []types.ClientSession {
+	clients := []types.ClientSession{}
+	for _, v := range msg.ClientSessions {
+		clients = append(clients, types.ClientSession{
+			Identifier:    v.Identifier,
+			SourceAddress: v.SourceAddress,
+			JoinTime:      v.JoinTime,
+			LeaveTime:     v.LeaveTime,
+		})
+	}
+	return clients
+}
+
+func (s *SidecarSessionService) handleSidecarMessage(podId string, msg *SidecarMessage) {
+	switch msg.Type {
+	case MessageTypeHeartbeat:
+		s.refreshSessionHeartbeats(podId, msg.OpenSessions)
+	case MessageTypeState:
+		state := internal.SessionState{
+			SessionId:         msg.SessionId,
+			ClientSessions:    clientSessions(msg),
+			MigrationMetadata: msg.Metadata,
+		}
+		switch msg.State {
+		case SessionStateClosed:
+			state.SessionState = types.SessionStateClosed
+		case SessionStateOpen, SessionStateClosing:
+			state.SessionState = types.SessionStateReady
+		case SessionStateInitializing:
+			state.SessionState = types.SessionStateStarting
+		default:
+			klog.InfoS("Ignore unknown session state reported by sidecar", "pod", podId, "session", msg.SessionId, "state", msg.State)
+			return
+		}
+		s.forwardSessionState(msg.SessionId, state)
+		if state.SessionState == types.SessionStateClosed {
+			s.removeSession(msg.SessionId)
+		}
+	default:
+		klog.InfoS("Ignore unknown message type sent by sidecar", "pod", podId, "type", msg.Type)
+	}
+}
+
+func (s *SidecarSessionService) refreshSessionHeartbeats(podId string, sessionIds []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for _, v := range sessionIds {
+		if sess, found := s.sessions[v]; found && sess.pod.Identifier == podId {
+			sess.lastSeen = now
+			sess.consectuivePingFailures = 0
+		}
+	}
+}
+
+func (s *SidecarSessionService) forwardSessionState(sessionId string, state internal.SessionState) {
+	s.mu.Lock()
+	sess, found := s.sessions[sessionId]
+	if found {
+		sess.lastSeen = time.Now()
+		sess.consectuivePingFailures = 0
+	}
+	s.mu.Unlock()
+	if found {
+		sess.stateCallback(state)
+	}
+	// else, node agent lost state callback after restart, wait for it ping this session
+}
+
+func (s *SidecarSessionService) getSession(sessionId string) *sidecarSession {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sessions[sessionId]
+}
+
+func (s *SidecarSessionService) addSession(pod *types.FornaxPod, session *types.FornaxSession, stateCallbackFunc func(internal.SessionState)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[session.Identifier] = &sidecarSession{
+		pod:           pod,
+		session:       session,
+		stateCallback: stateCallbackFunc,
+		lastSeen:      time.Now(),
+	}
+}
+
+func (s *SidecarSessionService) removeSession(sessionId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, sessionId)
+}
+
+func (s *SidecarSessionService) sendHeartbeats() {
+	s.mu.RLock()
+	connections := []*sidecarConnection{}
+	for _, c := range s.connections {
+		connections = append(connections, c)
+	}
+	s.mu.RUnlock()
+	for _, c := range connections {
+		if err := c.send(&SidecarMessage{Type: MessageTypeHeartbeat, PodId: c.podId}); err != nil {
+			klog.ErrorS(err, "Failed to send heartbeat to session sidecar", "pod", c.podId)
+			s.closeConnection(c)
+		}
+	}
+}
+
+func (s *SidecarSessionService) checkAndCleanSessionHeartbeat() {
+	s.mu.RLock()
+	sessions := []*sidecarSession{}
+	for _, v := range s.sessions {
+		sessions = append(sessions, v)
+	}
+	s.mu.RUnlock()
+
+	for _, v := range sessions {
+		if v.consectuivePingFailures > DefaultDeadSessionHeartbeatMissingThreshold {
+			s.removeSession(v.session.Identifier)
+			v.stateCallback(internal.SessionState{
+				SessionId:      v.session.Identifier,
+				SessionState:   types.SessionStateNoHeartbeat,
+				ClientSessions: []types.ClientSession{},
+			})
+			continue
+		}
+		if time.Since(v.lastSeen) > DefaultSidecarHeartbeatPeriod {
+			v.consectuivePingFailures += 1
+			s.PingSession(v.pod, v.session, v.stateCallback)
+		}
+	}
+}
+
+func (s *SidecarSessionService) sendMessageToPod(pod *types.FornaxPod, msg *SidecarMessage) error {
+	c, err := s.getConnection(pod)
+	if err != nil {
+		return err
+	}
+	msg.PodId = pod.Identifier
+	if err := c.send(msg); err != nil {
+		s.closeConnection(c)
+		return sessionservice.SessionStreamDisconnected
+	}
+	return nil
+}
+
+// OpenSession send a open message to pod sidecar
+func (s *SidecarSessionService) OpenSession(pod *types.FornaxPod, session *types.FornaxSession, stateCallbackFunc func(internal.SessionState)) error {
+	msg := &SidecarMessage{
+		Type:        MessageTypeOpen,
+		SessionId:   session.Identifier,
+		RequestId:   util.RequestId(session.Session),
+		SessionData: session.Session.Spec.SessionData,
+		Attachment:  session.Session.Spec.Attachment,
+	}
+	if migration := session.Session.Status.Migration; migration != nil && migration.Phase == fornaxv1.SessionMigrationPhaseMigrating {
+		msg.Metadata = migration.Metadata
+	}
+	if err := s.sendMessageToPod(pod, msg); err != nil {
+		klog.ErrorS(err, "Failed to send open session message to sidecar", "pod", pod.Identifier, "session", session.Identifier, "requestId", util.RequestId(session.Session))
+		return err
+	}
+	if s.getSession(session.Identifier) != nil {
+		return sessionservice.SessionAlreadyExist
+	}
+	s.addSession(pod, session, stateCallbackFunc)
+	return nil
+}
+
+// PingSession send a ping message to pod sidecar, and recreate state callback of session lost after node agent restart
+func (s *SidecarSessionService) PingSession(pod *types.FornaxPod, session *types.FornaxSession, stateCallbackFunc func(internal.SessionState)) error {
+	if s.getSession(session.Identifier) == nil {
+		s.addSession(pod, session, stateCallbackFunc)
+	}
+	return s.sendMessageToPod(pod, &SidecarMessage{
+		Type:      MessageTypePing,
+		SessionId: session.Identifier,
+		RequestId: util.RequestId(session.Session),
+	})
+}
+
+// CloseSession send a close message to pod sidecar
+func (s *SidecarSessionService) CloseSession(pod *types.FornaxPod, session *types.FornaxSession, graceSeconds uint16) error {
+	if s.getSession(session.Identifier) == nil {
+		return sessionservice.SessionNotFound
+	}
+	err := s.sendMessageToPod(pod, &SidecarMessage{
+		Type:         MessageTypeClose,
+		SessionId:    session.Identifier,
+		RequestId:    util.RequestId(session.Session),
+		GraceSeconds: graceSeconds,
+	})
+	if err != nil {
+		klog.ErrorS(err, "Failed to send close session message to sidecar", "pod", pod.Identifier, "session", session.Identifier, "requestId", util.RequestId(session.Session))
+	}
+	return err
+}
+
+// UpdateSessionConfig send current session data, config data and secrets of pod to pod sidecar
+func (s *SidecarSessionService) UpdateSessionConfig(pod *types.FornaxPod, session *types.FornaxSession) error {
+	if s.getSession(session.Identifier) == nil {
+		return sessionservice.SessionNotFound
+	}
+	msg := &SidecarMessage{
+		Type:        MessageTypeConfig,
+		SessionId:   session.Identifier,
+		RequestId:   util.RequestId(session.Session),
+		SessionData: session.Session.Spec.SessionData,
+		Attachment:  session.Session.Spec.Attachment,
+	}
+	if pod.ConfigMap != nil {
+		msg.ConfigData = pod.ConfigMap.Data
+	}
+	if pod.Secret != nil {
+		msg.Secret = &SidecarSecret{Version: pod.Secret.Version, Data: pod.Secret.Data}
+	}
+	if pod.PreviousSecret != nil {
+		msg.PreviousSecret = &SidecarSecret{Version: pod.PreviousSecret.Version, Data: pod.PreviousSecret.Data}
+	}
+	return s.sendMessageToPod(pod, msg)
+}
+
+// DrainSession send a drain message to pod sidecar, session is still open until sidecar report it closed
+func (s *SidecarSessionService) DrainSession(pod *types.FornaxPod, session *types.FornaxSession, reason string, deadline time.Time) error {
+	if s.getSession(session.Identifier) == nil {
+		return sessionservice.SessionNotFound
+	}
+	return s.sendMessageToPod(pod, &SidecarMessage{
+		Type:      MessageTypeDrain,
+		SessionId: session.Identifier,
+		RequestId: util.RequestId(session.Session),
+		Reason:    reason,
+		Deadline:  &deadline,
+	})
+}
+
+// MigrateSession send a migrate message to pod sidecar, sidecar export session metadata in closed state
+func (s *SidecarSessionService) MigrateSession(pod *types.FornaxPod, session *types.FornaxSession, targetPod string, graceSeconds uint16) error {
+	if s.getSession(session.Identifier) == nil {
+		return sessionservice.SessionNotFound
+	}
+	return s.sendMessageToPod(pod, &SidecarMessage{
+		Type:         MessageTypeMigrate,
+		SessionId:    session.Identifier,
+		RequestId:    util.RequestId(session.Session),
+		TargetPod:    targetPod,
+		GraceSeconds: graceSeconds,
+	})
+}
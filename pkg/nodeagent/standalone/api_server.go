@@ -0,0 +1,227 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/klog/v2"
+)
+
+const (
+	NodePath     = "/v1/node"
+	PodsPath     = "/v1/pods"
+	SessionsPath = "/v1/sessions"
+)
+
+// APIServer serve local rest api of a standalone node, pods and sessions are created, listed and deleted as json or yaml,
+// pod and session are addressed by /<namespace>/<name>, a session is opened on pod given by pod query parameter or a idle pod of its application
+type APIServer struct {
+	core *LocalCore
+}
+
+func NewAPIServer(core *LocalCore) *APIServer {
+	return &APIServer{core: core}
+}
+
+func (s *APIServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(NodePath, s.serveNode)
+	mux.HandleFunc(PodsPath, s.servePods)
+	mux.HandleFunc(PodsPath+"/", s.servePod)
+	mux.HandleFunc(SessionsPath, s.serveSessions)
+	mux.HandleFunc(SessionsPath+"/", s.serveSession)
+	return mux
+}
+
+func (s *APIServer) Run(ctx context.Context, address string) error {
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		klog.ErrorS(err, "Standalone node api server failed to listen", "address", address)
+		return err
+	}
+	server := &http.Server{Handler: s.Handler()}
+	go func() {
+		if err := server.Serve(lis); err != nil && err != http.ErrServerClosed {
+			klog.ErrorS(err, "Standalone node api server stopped to serve")
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	klog.InfoS("Standalone node api server started", "address", address)
+	return nil
+}
+
+func (s *APIServer) serveNode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	node, ready := s.core.Node()
+	if node == nil {
+		http.Error(w, ErrNodeNotReady.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, http.StatusOK, struct {
+		Ready bool     `json:"ready"`
+		Node  *v1.Node `json:"node"`
+	}{ready, node})
+}
+
+func (s *APIServer) servePods(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.core.ListPods())
+	case http.MethodPost:
+		pod := &v1.Pod{}
+		if err := yaml.NewYAMLOrJSONDecoder(r.Body, 4096).Decode(pod); err != nil {
+			http.Error(w, fmt.Sprintf("invalid pod: %v", err), http.StatusBadRequest)
+			return
+		}
+		created, err := s.core.CreatePod(pod)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, created)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *APIServer) servePod(w http.ResponseWriter, r *http.Request) {
+	name, err := objectName(r.URL.Path, PodsPath)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		pod, err := s.core.GetPod(name)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, pod)
+	case http.MethodDelete:
+		if err := s.core.TerminatePod(name); err != nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *APIServer) serveSessions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.core.ListSessions())
+	case http.MethodPost:
+		session := &fornaxv1.ApplicationSession{}
+		if err := yaml.NewYAMLOrJSONDecoder(r.Body, 4096).Decode(session); err != nil {
+			http.Error(w, fmt.Sprintf("invalid session: %v", err), http.StatusBadRequest)
+			return
+		}
+		pod := r.URL.Query().Get("pod")
+		if len(pod) > 0 && !strings.Contains(pod, "/") {
+			namespace := session.Namespace
+			if len(namespace) == 0 {
+				namespace = "default"
+			}
+			pod = fmt.Sprintf("%s/%s", namespace, pod)
+		}
+		opened, err := s.core.OpenSession(session, pod)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, opened)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *APIServer) serveSession(w http.ResponseWriter, r *http.Request) {
+	name, err := objectName(r.URL.Path, SessionsPath)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		session, err := s.core.GetSession(name)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, session)
+	case http.MethodDelete:
+		if err := s.core.CloseSession(name); err != nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// objectName return namespace/name of a object path, e.g. /v1/pods/default/echo
+func objectName(path, prefix string) (string, error) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(path, prefix), "/"), "/")
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return "", fmt.Errorf("invalid path %s, expected %s/<namespace>/<name>", path, prefix)
+	}
+	return strings.Join(parts, "/"), nil
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ErrPodNotFound), errors.Is(err, ErrSessionNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrPodExists), errors.Is(err, ErrSessionExists), errors.Is(err, ErrPodNotRunning):
+		status = http.StatusConflict
+	case errors.Is(err, ErrNodeNotReady):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, ErrInvalidObject):
+		status = http.StatusBadRequest
+	}
+	http.Error(w, err.Error(), status)
+}
+
+func writeJSON(w http.ResponseWriter, status int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		klog.ErrorS(err, "Failed to write standalone api response")
+	}
+}
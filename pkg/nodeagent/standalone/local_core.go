@@ -0,0 +1,487 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	default_config "centaurusinfra.io/fornax-serverless/pkg/config"
+	fornaxgrpc "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc"
+	"centaurusinfra.io/fornax-serverless/pkg/nodeagent/fornaxcore"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+
+	"github.com/google/uuid"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+var (
+	ErrNodeNotReady    = errors.New("node is not ready")
+	ErrPodNotFound     = errors.New("pod not found")
+	ErrPodExists       = errors.New("pod already exists")
+	ErrPodNotRunning   = errors.New("pod is not running")
+	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionExists   = errors.New("session already exists")
+	ErrInvalidObject   = errors.New("invalid object")
+)
+
+const (
+	// pod state before node report first steady state of a created pod
+	PodStatePending = "Pending"
+)
+
+var _ fornaxcore.FornaxCoreClient = &LocalCore{}
+
+// PodRecord is last pod and state reported by node, pod spec has host ports allocated by node
+type PodRecord struct {
+	Pod   *v1.Pod `json:"pod"`
+	State string  `json:"state"`
+}
+
+// LocalCore stand in for fornaxcore when node agent run standalone, it play fornaxcore side of node protocol,
+// node is configured as soon as it register, pods and sessions created by local api are sent to node actor as fornaxcore messages
+// and their states are kept from messages node send back, as fornaxcore does
+type LocalCore struct {
+	mu       sync.RWMutex
+	podCIDR  string
+	receiver chan *fornaxgrpc.FornaxCoreMessage
+	node     *v1.Node
+	ready    bool
+	pods     map[string]*PodRecord
+	sessions map[string]*fornaxv1.ApplicationSession
+}
+
+func NewLocalCore(podCIDR string) *LocalCore {
+	return &LocalCore{
+		podCIDR:  podCIDR,
+		pods:     map[string]*PodRecord{},
+		sessions: map[string]*fornaxv1.ApplicationSession{},
+	}
+}
+
+func (c *LocalCore) Start() {}
+
+func (c *LocalCore) Stop() {}
+
+// GetMessage keep channel of fornaxcore actor, messages to node are sent into it
+func (c *LocalCore) GetMessage(receiver string, channel chan *fornaxgrpc.FornaxCoreMessage) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.receiver = channel
+	return nil
+}
+
+// WatchSessions block until context is done, there is no session change from a fornaxcore
+func (c *LocalCore) WatchSessions(ctx context.Context, handler func(event *fornaxgrpc.SessionWatchEvent)) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (c *LocalCore) send(msg *fornaxgrpc.FornaxCoreMessage) error {
+	c.mu.RLock()
+	receiver, node := c.receiver, c.node
+	c.mu.RUnlock()
+	if receiver == nil || node == nil {
+		return ErrNodeNotReady
+	}
+	msg.NodeIdentifier = &fornaxgrpc.NodeIdentifier{Identifier: util.Name(node)}
+	receiver <- msg
+	return nil
+}
+
+// PutMessage handle a message node send to fornaxcore
+func (c *LocalCore) PutMessage(msg *fornaxgrpc.FornaxCoreMessage) error {
+	switch msg.GetMessageType() {
+	case fornaxgrpc.MessageType_NODE_REGISTER:
+		node := msg.GetNodeRegistry().GetNode().DeepCopy()
+		node.Spec.PodCIDR = c.podCIDR
+		node.Spec.PodCIDRs = []string{c.podCIDR}
+		c.mu.Lock()
+		c.node, c.ready = node, false
+		c.mu.Unlock()
+		klog.InfoS("Configure standalone node", "node", util.Name(node), "podCIDR", c.podCIDR)
+		// node actor is waiting for reply in its register loop, do not block fornaxcore actor
+		go c.send(&fornaxgrpc.FornaxCoreMessage{
+			MessageType: fornaxgrpc.MessageType_NODE_CONFIGURATION,
+			MessageBody: &fornaxgrpc.FornaxCoreMessage_NodeConfiguration{
+				NodeConfiguration: &fornaxgrpc.NodeConfiguration{
+					ClusterDomain: default_config.DefaultDomainName,
+					Node:          node.DeepCopy(),
+				},
+			},
+		})
+	case fornaxgrpc.MessageType_NODE_READY:
+		ready := msg.GetNodeReady()
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.node, c.ready = ready.GetNode().DeepCopy(), true
+		for _, v := range ready.GetPodStates() {
+			c.updatePodState(v)
+		}
+		for _, v := range ready.GetSessionStates() {
+			c.updateSessionState(v)
+		}
+		klog.InfoS("Standalone node is ready", "node", util.Name(c.node))
+	case fornaxgrpc.MessageType_NODE_STATE:
+		state := msg.GetNodeState()
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.node = state.GetNode().DeepCopy()
+		for _, v := range state.GetPodStates() {
+			c.updatePodState(v)
+		}
+	case fornaxgrpc.MessageType_POD_STATE:
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.updatePodState(msg.GetPodState())
+	case fornaxgrpc.MessageType_SESSION_STATE:
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.updateSessionState(msg.GetSessionState())
+	}
+	// lease renew, usage and config profile status are not used without fornaxcore
+	return nil
+}
+
+func (c *LocalCore) updatePodState(state *fornaxgrpc.PodState) {
+	if state.GetPod() == nil {
+		return
+	}
+	c.pods[util.Name(state.GetPod())] = &PodRecord{Pod: state.GetPod().DeepCopy(), State: state.GetState().String()}
+	for _, v := range state.GetSessionStates() {
+		c.updateSessionState(v)
+	}
+}
+
+func (c *LocalCore) updateSessionState(state *fornaxgrpc.SessionState) {
+	session := &fornaxv1.ApplicationSession{}
+	if err := json.Unmarshal(state.GetSessionData(), session); err != nil {
+		klog.ErrorS(err, "Failed to unmarshal session state of standalone node")
+		return
+	}
+	c.sessions[util.Name(session)] = session
+}
+
+// Node return node reported by node agent, nil if node has not registered
+func (c *LocalCore) Node() (*v1.Node, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.node.DeepCopy(), c.ready
+}
+
+func (c *LocalCore) ListPods() []*PodRecord {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	pods := []*PodRecord{}
+	for _, v := range c.pods {
+		pods = append(pods, &PodRecord{Pod: v.Pod.DeepCopy(), State: v.State})
+	}
+	sort.Slice(pods, func(i, j int) bool { return util.Name(pods[i].Pod) < util.Name(pods[j].Pod) })
+	return pods
+}
+
+func (c *LocalCore) GetPod(name string) (*PodRecord, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, found := c.pods[name]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrPodNotFound, name)
+	}
+	return &PodRecord{Pod: v.Pod.DeepCopy(), State: v.State}, nil
+}
+
+// CreatePod default pod as fornaxcore build pods of applications and send it to node
+func (c *LocalCore) CreatePod(pod *v1.Pod) (*v1.Pod, error) {
+	if len(pod.Name) == 0 || len(pod.Spec.Containers) == 0 {
+		return nil, fmt.Errorf("%w: pod must have a name and containers", ErrInvalidObject)
+	}
+	pod = defaultPod(pod)
+	name := util.Name(pod)
+	c.mu.Lock()
+	if !c.ready {
+		c.mu.Unlock()
+		return nil, ErrNodeNotReady
+	}
+	if _, found := c.pods[name]; found {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrPodExists, name)
+	}
+	c.pods[name] = &PodRecord{Pod: pod.DeepCopy(), State: PodStatePending}
+	c.mu.Unlock()
+
+	err := c.send(&fornaxgrpc.FornaxCoreMessage{
+		MessageType: fornaxgrpc.MessageType_POD_CREATE,
+		MessageBody: &fornaxgrpc.FornaxCoreMessage_PodCreate{
+			PodCreate: &fornaxgrpc.PodCreate{
+				PodIdentifier: name,
+				Pod:           pod.DeepCopy(),
+				ConfigMap:     &v1.ConfigMap{},
+			},
+		},
+	})
+	if err != nil {
+		c.mu.Lock()
+		delete(c.pods, name)
+		c.mu.Unlock()
+		return nil, err
+	}
+	return pod, nil
+}
+
+// TerminatePod ask node to terminate a pod, a terminated pod is forgotten
+func (c *LocalCore) TerminatePod(name string) error {
+	c.mu.Lock()
+	v, found := c.pods[name]
+	if !found {
+		c.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrPodNotFound, name)
+	}
+	if v.State == fornaxgrpc.PodState_Terminated.String() {
+		delete(c.pods, name)
+		c.mu.Unlock()
+		return nil
+	}
+	c.mu.Unlock()
+	return c.send(&fornaxgrpc.FornaxCoreMessage{
+		MessageType: fornaxgrpc.MessageType_POD_TERMINATE,
+		MessageBody: &fornaxgrpc.FornaxCoreMessage_PodTerminate{
+			PodTerminate: &fornaxgrpc.PodTerminate{PodIdentifier: name},
+		},
+	})
+}
+
+func (c *LocalCore) ListSessions() []*fornaxv1.ApplicationSession {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	sessions := []*fornaxv1.ApplicationSession{}
+	for _, v := range c.sessions {
+		sessions = append(sessions, v.DeepCopy())
+	}
+	sort.Slice(sessions, func(i, j int) bool { return util.Name(sessions[i]) < util.Name(sessions[j]) })
+	return sessions
+}
+
+func (c *LocalCore) GetSession(name string) (*fornaxv1.ApplicationSession, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, found := c.sessions[name]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, name)
+	}
+	return v.DeepCopy(), nil
+}
+
+// OpenSession bind session to a running pod and send it to node, if pod name is empty,
+// a running pod of session application without open session is used
+func (c *LocalCore) OpenSession(session *fornaxv1.ApplicationSession, podName string) (*fornaxv1.ApplicationSession, error) {
+	if len(session.Name) == 0 {
+		return nil, fmt.Errorf("%w: session must have a name", ErrInvalidObject)
+	}
+	session = session.DeepCopy()
+	if len(session.Namespace) == 0 {
+		session.Namespace = metav1.NamespaceDefault
+	}
+	if len(session.UID) == 0 {
+		session.UID = types.UID(uuid.New().String())
+	}
+	if session.CreationTimestamp.IsZero() {
+		session.CreationTimestamp = metav1.Now()
+	}
+	name := util.Name(session)
+
+	c.mu.Lock()
+	if !c.ready {
+		c.mu.Unlock()
+		return nil, ErrNodeNotReady
+	}
+	if _, found := c.sessions[name]; found {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrSessionExists, name)
+	}
+	if len(podName) == 0 {
+		podName = c.findIdlePod(session)
+		if len(podName) == 0 {
+			c.mu.Unlock()
+			return nil, fmt.Errorf("%w: no idle running pod of application %s", ErrPodNotFound, session.Spec.ApplicationName)
+		}
+	}
+	pod, found := c.pods[podName]
+	if !found {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrPodNotFound, podName)
+	}
+	if pod.State != fornaxgrpc.PodState_Running.String() {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s is %s", ErrPodNotRunning, podName, pod.State)
+	}
+	bindSessionToPod(session, pod.Pod)
+	c.sessions[name] = session.DeepCopy()
+	c.mu.Unlock()
+
+	sessionData, err := json.Marshal(session)
+	if err == nil {
+		err = c.send(&fornaxgrpc.FornaxCoreMessage{
+			MessageType:       fornaxgrpc.MessageType_SESSION_OPEN,
+			RequestIdentifier: util.RequestId(session),
+			MessageBody: &fornaxgrpc.FornaxCoreMessage_SessionOpen{
+				SessionOpen: &fornaxgrpc.SessionOpen{
+					SessionIdentifier: name,
+					PodIdentifier:     podName,
+					SessionData:       sessionData,
+				},
+			},
+		})
+	}
+	if err != nil {
+		c.mu.Lock()
+		delete(c.sessions, name)
+		c.mu.Unlock()
+		return nil, err
+	}
+	return session, nil
+}
+
+// findIdlePod return a running pod of session application which has no session bound, caller hold lock
+func (c *LocalCore) findIdlePod(session *fornaxv1.ApplicationSession) string {
+	application := fmt.Sprintf("%s/%s", session.Namespace, session.Spec.ApplicationName)
+	occupied := map[string]bool{}
+	for _, v := range c.sessions {
+		if v.Status.PodReference != nil && !util.SessionInTerminalState(v) {
+			occupied[v.Status.PodReference.Name] = true
+		}
+	}
+	names := []string{}
+	for name, v := range c.pods {
+		if v.State == fornaxgrpc.PodState_Running.String() && v.Pod.Labels[fornaxv1.LabelFornaxCoreApplication] == application && !occupied[name] {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return names[0]
+}
+
+// CloseSession ask node to close a session, a closed session is forgotten
+func (c *LocalCore) CloseSession(name string) error {
+	c.mu.Lock()
+	v, found := c.sessions[name]
+	if !found {
+		c.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, name)
+	}
+	if util.SessionInTerminalState(v) || v.Status.PodReference == nil {
+		delete(c.sessions, name)
+		c.mu.Unlock()
+		return nil
+	}
+	podName := v.Status.PodReference.Name
+	c.mu.Unlock()
+	return c.send(&fornaxgrpc.FornaxCoreMessage{
+		MessageType:       fornaxgrpc.MessageType_SESSION_CLOSE,
+		RequestIdentifier: util.RequestId(v),
+		MessageBody: &fornaxgrpc.FornaxCoreMessage_SessionClose{
+			SessionClose: &fornaxgrpc.SessionClose{SessionIdentifier: name, PodIdentifier: podName},
+		},
+	})
+}
+
+// bindSessionToPod set session status as fornaxcore does when a session is assigned to a pod, endpoints are host ports of pod
+func bindSessionToPod(session *fornaxv1.ApplicationSession, pod *v1.Pod) {
+	session.Status.SessionStatus = fornaxv1.SessionStatusStarting
+	session.Status.AccessEndPoints = nil
+	for _, cont := range pod.Spec.Containers {
+		for _, port := range cont.Ports {
+			session.Status.AccessEndPoints = append(session.Status.AccessEndPoints, fornaxv1.AccessEndPoint{
+				Protocol:  port.Protocol,
+				IPAddress: port.HostIP,
+				Port:      port.HostPort,
+				IPFamily:  util.IPFamilyOf(port.HostIP),
+			})
+		}
+	}
+	session.Status.PodReference = &v1.LocalObjectReference{Name: util.Name(pod)}
+}
+
+// defaultPod set fields fornaxcore set in pods of applications, application of pod is its name if it does not have application label
+func defaultPod(pod *v1.Pod) *v1.Pod {
+	pod = pod.DeepCopy()
+	pod.TypeMeta = metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"}
+	if len(pod.Namespace) == 0 {
+		pod.Namespace = metav1.NamespaceDefault
+	}
+	pod.UID = types.UID(uuid.New().String())
+	pod.ResourceVersion = "0"
+	pod.CreationTimestamp = metav1.NewTime(time.Now())
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	application, found := pod.Labels[fornaxv1.LabelFornaxCoreApplication]
+	if !found {
+		application = pod.Name
+	}
+	if !strings.Contains(application, "/") {
+		application = fmt.Sprintf("%s/%s", pod.Namespace, application)
+	}
+	pod.Labels[fornaxv1.LabelFornaxCoreApplication] = application
+
+	if len(pod.Spec.RestartPolicy) == 0 {
+		pod.Spec.RestartPolicy = v1.RestartPolicyNever
+	}
+	if len(pod.Spec.DNSPolicy) == 0 {
+		pod.Spec.DNSPolicy = v1.DNSNone
+	}
+	if len(pod.Spec.Subdomain) == 0 {
+		pod.Spec.Subdomain = default_config.DefaultDomainName
+	}
+	if len(pod.Spec.ReadinessGates) == 0 {
+		pod.Spec.ReadinessGates = []v1.PodReadinessGate{{ConditionType: v1.ContainersReady}}
+	}
+	if pod.Spec.SecurityContext == nil {
+		pod.Spec.SecurityContext = &v1.PodSecurityContext{}
+	}
+	for i := range pod.Spec.Containers {
+		cont := &pod.Spec.Containers[i]
+		for j := range cont.Ports {
+			if len(cont.Ports[j].Protocol) == 0 {
+				cont.Ports[j].Protocol = v1.ProtocolTCP
+			}
+		}
+		cont.Env = append(cont.Env,
+			v1.EnvVar{Name: fornaxv1.LabelFornaxCorePod, Value: util.Name(pod)},
+			v1.EnvVar{Name: fornaxv1.LabelFornaxCoreApplication, Value: application},
+			v1.EnvVar{Name: fornaxv1.LabelFornaxCoreSessionService, ValueFrom: &v1.EnvVarSource{
+				FieldRef: &v1.ObjectFieldSelector{APIVersion: "v1", FieldPath: "status.hostIP"},
+			}},
+		)
+	}
+	return pod
+}
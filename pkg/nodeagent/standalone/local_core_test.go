@@ -0,0 +1,112 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	fornaxgrpc "centaurusinfra.io/fornax-serverless/pkg/fornaxcore/grpc"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func receive(t *testing.T, ch chan *fornaxgrpc.FornaxCoreMessage, msgType fornaxgrpc.MessageType) *fornaxgrpc.FornaxCoreMessage {
+	select {
+	case msg := <-ch:
+		if msg.GetMessageType() != msgType {
+			t.Fatalf("expected %s message, got %s", msgType, msg.GetMessageType())
+		}
+		return msg
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for %s message", msgType)
+	}
+	return nil
+}
+
+func TestLocalCorePodAndSession(t *testing.T) {
+	core := NewLocalCore("192.168.0.1/24")
+	ch := make(chan *fornaxgrpc.FornaxCoreMessage, 10)
+	core.GetMessage("node", ch)
+
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}
+	core.PutMessage(&fornaxgrpc.FornaxCoreMessage{
+		MessageType: fornaxgrpc.MessageType_NODE_REGISTER,
+		MessageBody: &fornaxgrpc.FornaxCoreMessage_NodeRegistry{NodeRegistry: &fornaxgrpc.NodeRegistry{Node: node}},
+	})
+	config := receive(t, ch, fornaxgrpc.MessageType_NODE_CONFIGURATION)
+	if cidr := config.GetNodeConfiguration().GetNode().Spec.PodCIDR; cidr != "192.168.0.1/24" {
+		t.Errorf("expected node configured with pod cidr 192.168.0.1/24, got %s", cidr)
+	}
+
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "echo"}, Spec: v1.PodSpec{Containers: []v1.Container{{Name: "echo", Ports: []v1.ContainerPort{{ContainerPort: 80}}}}}}
+	if _, err := core.CreatePod(pod); !errors.Is(err, ErrNodeNotReady) {
+		t.Errorf("expected node not ready error before node is ready, got %v", err)
+	}
+	core.PutMessage(&fornaxgrpc.FornaxCoreMessage{
+		MessageType: fornaxgrpc.MessageType_NODE_READY,
+		MessageBody: &fornaxgrpc.FornaxCoreMessage_NodeReady{NodeReady: &fornaxgrpc.NodeReady{Node: config.GetNodeConfiguration().GetNode()}},
+	})
+
+	if _, err := core.CreatePod(pod); err != nil {
+		t.Fatal(err)
+	}
+	created := receive(t, ch, fornaxgrpc.MessageType_POD_CREATE).GetPodCreate().GetPod()
+	if created.Labels[fornaxv1.LabelFornaxCoreApplication] != "default/echo" || created.Spec.Containers[0].Ports[0].Protocol != v1.ProtocolTCP {
+		t.Errorf("expected pod defaulted as application pod, got %v", created)
+	}
+	if _, err := core.CreatePod(pod); !errors.Is(err, ErrPodExists) {
+		t.Errorf("expected pod exists error, got %v", err)
+	}
+
+	session := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Name: "s1"}, Spec: fornaxv1.ApplicationSessionSpec{ApplicationName: "echo"}}
+	if _, err := core.OpenSession(session, ""); !errors.Is(err, ErrPodNotFound) {
+		t.Errorf("expected no idle pod before pod is running, got %v", err)
+	}
+
+	// node report pod running with allocated host port
+	created.Spec.Containers[0].Ports[0].HostIP = "10.0.0.1"
+	created.Spec.Containers[0].Ports[0].HostPort = 1024
+	core.PutMessage(&fornaxgrpc.FornaxCoreMessage{
+		MessageType: fornaxgrpc.MessageType_POD_STATE,
+		MessageBody: &fornaxgrpc.FornaxCoreMessage_PodState{PodState: &fornaxgrpc.PodState{State: fornaxgrpc.PodState_Running, Pod: created}},
+	})
+	opened, err := core.OpenSession(session, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	open := receive(t, ch, fornaxgrpc.MessageType_SESSION_OPEN).GetSessionOpen()
+	if open.GetPodIdentifier() != "default/echo" {
+		t.Errorf("expected session opened on default/echo, got %s", open.GetPodIdentifier())
+	}
+	if len(opened.Status.AccessEndPoints) != 1 || opened.Status.AccessEndPoints[0].Port != 1024 || opened.Status.SessionStatus != fornaxv1.SessionStatusStarting {
+		t.Errorf("expected session bound to pod host port, got %v", opened.Status)
+	}
+
+	// pod is occupied by s1
+	if _, err := core.OpenSession(&fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Name: "s2"}, Spec: session.Spec}, ""); !errors.Is(err, ErrPodNotFound) {
+		t.Errorf("expected no idle pod for second session, got %v", err)
+	}
+
+	if err := core.CloseSession("default/s1"); err != nil {
+		t.Fatal(err)
+	}
+	receive(t, ch, fornaxgrpc.MessageType_SESSION_CLOSE)
+}
@@ -38,6 +38,10 @@ type SessionStore struct {
 	storage.Store
 }
 
+type PodTerminationStore struct {
+	storage.Store
+}
+
 func NewNodeSqliteStore(options *sqlite.SQLiteStoreOptions) (*NodeStore, error) {
 	if store, err := sqlite.NewSqliteStore("Node", options,
 		func(text []byte) (interface{}, error) { return JsonToNode(text) },
@@ -138,6 +142,42 @@ func (s *SessionStore) PutSession(session *types.FornaxSession, revision int64)
 	return nil
 }
 
+func NewPodTerminationSqliteStore(options *sqlite.SQLiteStoreOptions) (*PodTerminationStore, error) {
+	if store, err := sqlite.NewSqliteStore("PodTermination", options,
+		func(text []byte) (interface{}, error) { return JsonToPodTermination(text) },
+		func(obj interface{}) ([]byte, error) {
+			return JsonFromPodTermination(obj.(*types.FornaxPodTermination))
+		}); err != nil {
+		return nil, err
+	} else {
+		return &PodTerminationStore{store}, nil
+	}
+}
+
+func (s *PodTerminationStore) GetPodTermination(identifier string) (*types.FornaxPodTermination, error) {
+	obj, err := s.GetObject(identifier)
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := obj.(*types.FornaxPodTermination); !ok {
+		return nil, fmt.Errorf("%v not a PodTermination object", obj)
+	} else {
+		return v, nil
+	}
+}
+
+func (s *PodTerminationStore) PutPodTermination(termination *types.FornaxPodTermination) error {
+	if termination == nil {
+		return fmt.Errorf("nil pod termination is passed")
+	}
+	err := s.PutObject(termination.PodIdentifier, termination, 0)
+	if err != nil {
+		klog.ErrorS(err, "Failed to save FornaxPodTermination", "name", termination.PodIdentifier)
+		return err
+	}
+	return nil
+}
+
 // use json to store node agent store object for now, consider using protobuf if meet performance issue
 func JsonToPod(data []byte) (*types.FornaxPod, error) {
 	res := types.FornaxPod{}
@@ -190,3 +230,20 @@ func JsonFromSession(obj *types.FornaxSession) ([]byte, error) {
 	}
 	return bytes, nil
 }
+
+func JsonToPodTermination(text []byte) (*types.FornaxPodTermination, error) {
+	res := types.FornaxPodTermination{}
+	if err := json.Unmarshal([]byte(text), &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func JsonFromPodTermination(obj *types.FornaxPodTermination) ([]byte, error) {
+	var bytes []byte
+	var err error
+	if bytes, err = json.Marshal(obj); err != nil {
+		return nil, err
+	}
+	return bytes, nil
+}
@@ -68,6 +68,9 @@ type FornaxContainer struct {
 	ContainerSpec    *v1.Container            `json:"containerSpec,omitempty"`
 	RuntimeContainer *runtime.Container       `json:"runtimeContainer,omitempty"`
 	ContainerStatus  *runtime.ContainerStatus `json:"containerStatus,omitempty"`
+	// times container was restarted in place by restart policy, and how it exited before last restart
+	RestartCount         int32                        `json:"restartCount,omitempty"`
+	LastTerminationState *v1.ContainerStateTerminated `json:"lastTerminationState,omitempty"`
 }
 
 type FornaxNodeWithRevision struct {
@@ -76,16 +79,69 @@ type FornaxNodeWithRevision struct {
 	Revision   int64    `json:"revision,omitempty"`
 }
 
+// a version of application secret delivered to pod
+type FornaxSecret struct {
+	Version string            `json:"version,omitempty"`
+	Data    map[string][]byte `json:"data,omitempty"`
+}
+
+// FornaxPod is saved in pod store, secrets are only kept in memory and never saved, fornaxcore send them again after node agent restart
 type FornaxPod struct {
 	Identifier              string                      `json:"identifier,omitempty"`
 	FornaxPodState          PodState                    `json:"fornaxPodState,omitempty"`
 	Daemon                  bool                        `json:"daemon,omitempty"`
 	Pod                     *v1.Pod                     `json:"pod,omitempty"`
 	ConfigMap               *v1.ConfigMap               `json:"configMap,omitempty"`
+	Secret                  *FornaxSecret               `json:"-"`
+	PreviousSecret          *FornaxSecret               `json:"-"`
 	RuntimePod              *runtime.Pod                `json:"runtimePod,omitempty"`
 	Containers              map[string]*FornaxContainer `json:"containers"`
 	Sessions                map[string]*FornaxSession   `json:"sessions"`
 	LastStateTransitionTime time.Time                   `json:"lastStateTransitionTime,omitempty"`
+	CPUStats                *FornaxPodCPUStats          `json:"cpuStats,omitempty"`
+	OOMKillCount            uint64                      `json:"oomKillCount,omitempty"`
+	UsageSample             *FornaxPodUsageSample       `json:"usageSample,omitempty"`
+}
+
+// cumulative cpu usage of pod cgroup at last sample, cpu used since last sample is shared by open sessions of pod
+type FornaxPodUsageSample struct {
+	CPUUsageTotal uint64    `json:"cpuUsageTotal,omitempty"` // nanoseconds
+	SampleTime    time.Time `json:"sampleTime,omitempty"`
+}
+
+// resource usage attributed to a session since its last usage report
+type FornaxSessionUsage struct {
+	StartTime           time.Time `json:"startTime,omitempty"`
+	CPUUsageNanoSeconds uint64    `json:"cpuUsageNanoSeconds,omitempty"`
+	MemoryByteSeconds   uint64    `json:"memoryByteSeconds,omitempty"`
+	MemoryPeakBytes     uint64    `json:"memoryPeakBytes,omitempty"`
+}
+
+// cpu cfs stats of pod cgroup, read from cpu.stat, throttled ratio is calculated from last two samples
+type FornaxPodCPUStats struct {
+	Periods          uint64    `json:"periods,omitempty"`
+	ThrottledPeriods uint64    `json:"throttledPeriods,omitempty"`
+	ThrottledTime    uint64    `json:"throttledTime,omitempty"` // nanoseconds
+	ThrottledRatio   float64   `json:"throttledRatio,omitempty"`
+	ThrottlingHigh   bool      `json:"throttlingHigh,omitempty"`
+	SampleTime       time.Time `json:"sampleTime,omitempty"`
+}
+
+// total stall time of pod cgroup read from cgroup v2 cpu/memory/io.pressure when session started, used to calculate session quality score
+type FornaxSessionPressureStall struct {
+	CPUStallTotal    uint64    `json:"cpuStallTotal,omitempty"` // microseconds
+	MemoryStallTotal uint64    `json:"memoryStallTotal,omitempty"`
+	IOStallTotal     uint64    `json:"ioStallTotal,omitempty"`
+	SampleTime       time.Time `json:"sampleTime,omitempty"`
+}
+
+// a intent to terminate pod recorded before pod containers are killed, node agent complete termination of journaled pod after restart
+type FornaxPodTermination struct {
+	PodIdentifier      string    `json:"podIdentifier,omitempty"`
+	RuntimePodId       string    `json:"runtimePodId,omitempty"`
+	Force              bool      `json:"force,omitempty"`
+	GracePeriodSeconds int64     `json:"gracePeriodSeconds,omitempty"`
+	RequestedAt        time.Time `json:"requestedAt,omitempty"`
 }
 
 // +enum
@@ -97,11 +153,15 @@ const (
 	SessionStateClosed      SessionState = "Closed"
 	SessionStateClosing     SessionState = "Closing"
 	SessionStateNoHeartbeat SessionState = "NoHeartbeat"
+	SessionStateTimeout     SessionState = "Timeout" // session did not report it's open or closed in time
 )
 
 type ClientSession struct {
-	Identifier  string
-	SessionData map[string]string
+	Identifier    string
+	SessionData   map[string]string
+	SourceAddress string
+	JoinTime      *time.Time
+	LeaveTime     *time.Time
 }
 
 type FornaxSession struct {
@@ -109,6 +169,10 @@ type FornaxSession struct {
 	PodIdentifier  string                       `json:"podIdentifier,omitempty"`
 	Session        *fornaxv1.ApplicationSession `json:"session,omitempty"`
 	ClientSessions map[string]*ClientSession    `json:"clientSessions,omitempty"`
+	PressureStall  *FornaxSessionPressureStall  `json:"pressureStall,omitempty"`
+	Usage          *FornaxSessionUsage          `json:"usage,omitempty"`
+	// when a time limited session is force closed, set when session is open
+	ExpiryTime *time.Time `json:"expiryTime,omitempty"`
 }
 
 func UniquePodName(pod *FornaxPod) string {
@@ -117,7 +181,8 @@ func UniquePodName(pod *FornaxPod) string {
 
 func PodHasOpenSessions(pod *FornaxPod) bool {
 	for _, v := range pod.Sessions {
-		if v.Session.Status.SessionStatus != fornaxv1.SessionStatusClosed || len(v.ClientSessions) > 0 {
+		closed := v.Session.Status.SessionStatus == fornaxv1.SessionStatusClosed || v.Session.Status.SessionStatus == fornaxv1.SessionStatusFailed
+		if !closed || len(v.ClientSessions) > 0 {
 			return true
 		}
 	}
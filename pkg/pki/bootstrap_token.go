@@ -0,0 +1,86 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"os"
+	"regexp"
+	"time"
+)
+
+var (
+	InvalidBootstrapTokenError = errors.New("invalid or expired bootstrap token")
+)
+
+// bootstrap token format is <token id>.<token secret>, same as kubernetes bootstrap token
+var bootstrapTokenRegexp = regexp.MustCompile(`^([a-z0-9]{6})\.([a-z0-9]{16})$`)
+
+// BootstrapToken let a node agent which does not have a certificate yet register and request its first certificate
+type BootstrapToken struct {
+	ID     string `json:"id"`
+	Secret string `json:"secret"`
+	// +optional, token never expire if not set
+	Expiration *time.Time `json:"expiration,omitempty"`
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+type BootstrapTokenConfiguration struct {
+	Tokens []BootstrapToken `json:"tokens"`
+}
+
+// BootstrapTokenAuthenticator authenticate bootstrap tokens in a json file, file is read on every authentication,
+// so, tokens can be added and revoked without restart, registrations are rare
+type BootstrapTokenAuthenticator struct {
+	file string
+}
+
+func NewBootstrapTokenAuthenticator(file string) *BootstrapTokenAuthenticator {
+	return &BootstrapTokenAuthenticator{file: file}
+}
+
+// Authenticate return id of token if token is in file and not expired
+func (a *BootstrapTokenAuthenticator) Authenticate(token string) (string, error) {
+	match := bootstrapTokenRegexp.FindStringSubmatch(token)
+	if match == nil {
+		return "", InvalidBootstrapTokenError
+	}
+	data, err := os.ReadFile(a.file)
+	if err != nil {
+		return "", err
+	}
+	config := &BootstrapTokenConfiguration{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return "", err
+	}
+	for _, v := range config.Tokens {
+		if v.ID != match[1] {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(v.Secret), []byte(match[2])) != 1 {
+			return "", InvalidBootstrapTokenError
+		}
+		if v.Expiration != nil && time.Now().After(*v.Expiration) {
+			return "", InvalidBootstrapTokenError
+		}
+		return v.ID, nil
+	}
+	return "", InvalidBootstrapTokenError
+}
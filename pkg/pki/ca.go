@@ -0,0 +1,310 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultCAValidity          = 10 * 365 * 24 * time.Hour
+	DefaultServerCertValidity  = 365 * 24 * time.Hour
+	DefaultClientCertValidity  = 24 * time.Hour
+	DefaultCertificateBackdate = 5 * time.Minute
+)
+
+var (
+	InvalidCertificateRequestError = errors.New("invalid certificate signing request")
+)
+
+// KeyPair is a pem encoded certificate and its private key
+type KeyPair struct {
+	CertPEM []byte
+	KeyPEM  []byte
+}
+
+// CertificateAuthority sign server and client certificates, it's used by fornaxcore to issue certificates of node agents and applications,
+// and by node agent to issue certificates of its session service and pods
+type CertificateAuthority struct {
+	cert    *x509.Certificate
+	key     crypto.Signer
+	certPEM []byte
+}
+
+// LoadCertificateAuthority load CA certificate and key from pem files
+func LoadCertificateAuthority(certFile, keyFile string) (*CertificateAuthority, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	certs, err := ParseCertificatesPEM(certPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate in %s: %v", certFile, err)
+	}
+	key, err := ParsePrivateKeyPEM(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key in %s: %v", keyFile, err)
+	}
+	return &CertificateAuthority{cert: certs[0], key: key, certPEM: certPEM}, nil
+}
+
+// NewSelfSignedCertificateAuthority generate a CA in memory
+func NewSelfSignedCertificateAuthority(commonName string) (*CertificateAuthority, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := NewSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-1 * time.Hour),
+		NotAfter:              time.Now().Add(DefaultCAValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &CertificateAuthority{cert: cert, key: key, certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}, nil
+}
+
+// LoadOrCreateCertificateAuthority load CA from files, if both files do not exist, a self signed CA is generated and saved into files,
+// so, certificates issued by it are still trusted after restart
+func LoadOrCreateCertificateAuthority(certFile, keyFile, commonName string) (*CertificateAuthority, error) {
+	_, certErr := os.Stat(certFile)
+	_, keyErr := os.Stat(keyFile)
+	if !os.IsNotExist(certErr) || !os.IsNotExist(keyErr) {
+		return LoadCertificateAuthority(certFile, keyFile)
+	}
+	klog.InfoS("CA certificate not found, generate a self signed CA", "cert", certFile, "key", keyFile, "commonName", commonName)
+	ca, err := NewSelfSignedCertificateAuthority(commonName)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := EncodePrivateKeyPEM(ca.key)
+	if err != nil {
+		return nil, err
+	}
+	if err := WriteKeyPair(certFile, keyFile, &KeyPair{CertPEM: ca.certPEM, KeyPEM: keyPEM}); err != nil {
+		return nil, err
+	}
+	return ca, nil
+}
+
+// CertPEM return pem encoded CA certificate
+func (ca *CertificateAuthority) CertPEM() []byte {
+	return ca.certPEM
+}
+
+// CertPool return a pool trusting this CA
+func (ca *CertificateAuthority) CertPool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return pool
+}
+
+// Issue generate a key and sign its certificate, ips and dns names are put in certificate as subject alternative names
+func (ca *CertificateAuthority) Issue(subject pkix.Name, dnsNames []string, ips []net.IP, usages []x509.ExtKeyUsage, validity time.Duration) (*KeyPair, error) {
+	return ca.issue(&x509.Certificate{Subject: subject, DNSNames: dnsNames, IPAddresses: ips, ExtKeyUsage: usages}, validity)
+}
+
+// IssueWithURIs generate a key pair and sign a certificate having uri alternative names, e.g. a spiffe id
+func (ca *CertificateAuthority) IssueWithURIs(subject pkix.Name, dnsNames []string, uris []*url.URL, usages []x509.ExtKeyUsage, validity time.Duration) (*KeyPair, error) {
+	return ca.issue(&x509.Certificate{Subject: subject, DNSNames: dnsNames, URIs: uris, ExtKeyUsage: usages}, validity)
+}
+
+func (ca *CertificateAuthority) issue(template *x509.Certificate, validity time.Duration) (*KeyPair, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	certPEM, err := ca.sign(template, &key.PublicKey, validity)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := EncodePrivateKeyPEM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyPair{CertPEM: certPEM, KeyPEM: keyPEM}, nil
+}
+
+// SignCSR sign a certificate of a pem encoded certificate request, common name of request must be expected common name,
+// alternative names in request are ignored, certificate only prove identity in common name, it's a client certificate if usages are not set
+func (ca *CertificateAuthority) SignCSR(csrPEM []byte, commonName string, validity time.Duration, usages ...x509.ExtKeyUsage) ([]byte, error) {
+	block, _ := pem.Decode(csrPEM)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, InvalidCertificateRequestError
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", InvalidCertificateRequestError, err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("%w: %v", InvalidCertificateRequestError, err)
+	}
+	if csr.Subject.CommonName != commonName {
+		return nil, fmt.Errorf("%w: common name %s is not %s", InvalidCertificateRequestError, csr.Subject.CommonName, commonName)
+	}
+	if len(usages) == 0 {
+		usages = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	}
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: commonName, Organization: csr.Subject.Organization},
+		ExtKeyUsage: usages,
+	}
+	return ca.sign(template, csr.PublicKey, validity)
+}
+
+func (ca *CertificateAuthority) sign(template *x509.Certificate, pub crypto.PublicKey, validity time.Duration) ([]byte, error) {
+	serial, err := NewSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	notAfter := time.Now().Add(validity)
+	if notAfter.After(ca.cert.NotAfter) {
+		notAfter = ca.cert.NotAfter
+	}
+	template.SerialNumber = serial
+	template.NotBefore = time.Now().Add(-DefaultCertificateBackdate)
+	template.NotAfter = notAfter
+	template.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, pub, ca.key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+}
+
+func NewSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+// ParseCertificatesPEM parse all certificates in pem data, first one is leaf certificate
+func ParseCertificatesPEM(data []byte) ([]*x509.Certificate, error) {
+	certs := []*x509.Certificate{}
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no pem certificate found")
+	}
+	return certs, nil
+}
+
+// ParsePrivateKeyPEM parse a pem encoded pkcs8, ec or pkcs1 private key
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no pem key found")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("unsupported key type")
+		}
+		return signer, nil
+	} else if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	} else if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("can not parse key")
+}
+
+func EncodePrivateKeyPEM(key crypto.Signer) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// NewCertificateRequest generate a key and a pem encoded certificate request of common name
+func NewCertificateRequest(subject pkix.Name) (csrPEM, keyPEM []byte, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: subject}, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err = EncodePrivateKeyPEM(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}), keyPEM, nil
+}
+
+// WriteKeyPair save key pair into files, files are replaced by rename, so, readers never see a half written file
+func WriteKeyPair(certFile, keyFile string, pair *KeyPair) error {
+	for _, f := range []struct {
+		name string
+		data []byte
+		mode os.FileMode
+	}{{keyFile, pair.KeyPEM, 0600}, {certFile, pair.CertPEM, 0644}} {
+		if err := os.MkdirAll(filepath.Dir(f.name), 0755); err != nil {
+			return err
+		}
+		tmp := f.name + ".tmp"
+		if err := os.WriteFile(tmp, f.data, f.mode); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp, f.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
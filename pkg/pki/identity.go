@@ -0,0 +1,118 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	// common name of node agent client certificate is prefix + node name
+	NodeCommonNamePrefix = "fornax:node:"
+	NodeOrganization     = "fornax:nodes"
+
+	// common name of pod client certificate of node session service is prefix + pod identifier
+	PodCommonNamePrefix = "fornax:pod:"
+
+	// common name of fornaxcore replica certificate used by store replication is prefix + replica identity
+	ReplicaCommonNamePrefix = "fornax:replica:"
+
+	// common name of fornaxcore client certificate used to call node agent debug service
+	FornaxCoreCommonName = "fornax:fornaxcore"
+)
+
+var (
+	PeerCertificateNotFoundError = errors.New("peer does not have a verified client certificate")
+	UnexpectedCommonNameError    = errors.New("peer certificate does not have expected common name")
+)
+
+func NodeCommonName(node string) string {
+	return NodeCommonNamePrefix + node
+}
+
+func PodCommonName(pod string) string {
+	return PodCommonNamePrefix + pod
+}
+
+func ReplicaCommonName(identity string) string {
+	return ReplicaCommonNamePrefix + identity
+}
+
+// IsReplicaCommonName check if common name is a fornaxcore replica identity
+func IsReplicaCommonName(cn string) bool {
+	return strings.HasPrefix(cn, ReplicaCommonNamePrefix) && len(cn) > len(ReplicaCommonNamePrefix)
+}
+
+// PeerCommonName return common name of verified client certificate of grpc peer
+func PeerCommonName(ctx context.Context) (string, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.AuthInfo == nil {
+		return "", PeerCertificateNotFoundError
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return "", PeerCertificateNotFoundError
+	}
+	return VerifiedCommonName(&tlsInfo.State)
+}
+
+// VerifiedCommonName return common name of verified client certificate of a tls connection, e.g. http.Request.TLS
+func VerifiedCommonName(state *tls.ConnectionState) (string, error) {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return "", PeerCertificateNotFoundError
+	}
+	return state.VerifiedChains[0][0].Subject.CommonName, nil
+}
+
+// PeerIs check if grpc peer's verified client certificate has expected common name
+func PeerIs(ctx context.Context, commonName string) bool {
+	cn, err := PeerCommonName(ctx)
+	return err == nil && cn == commonName
+}
+
+// VerifyServerCommonName return a tls.Config.VerifyConnection which verify server certificate chain against roots and check its common name,
+// it's used with InsecureSkipVerify to connect a server whose certificate prove identity in common name instead of alternative names, e.g. node certificate
+func VerifyServerCommonName(roots *x509.CertPool, commonName string) func(tls.ConnectionState) error {
+	return func(state tls.ConnectionState) error {
+		if len(state.PeerCertificates) == 0 {
+			return PeerCertificateNotFoundError
+		}
+		opts := x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: x509.NewCertPool(),
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}
+		for _, cert := range state.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+		if _, err := state.PeerCertificates[0].Verify(opts); err != nil {
+			return err
+		}
+		if cn := state.PeerCertificates[0].Subject.CommonName; cn != commonName {
+			return fmt.Errorf("%w: %s is not %s", UnexpectedCommonNameError, cn, commonName)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package pki
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSignCSR(t *testing.T) {
+	ca, err := NewSelfSignedCertificateAuthority("test-ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	csr, key, err := NewCertificateRequest(pkix.Name{CommonName: NodeCommonName("node1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ca.SignCSR(csr, NodeCommonName("node2"), time.Hour); err == nil {
+		t.Errorf("expected error of csr of other node")
+	}
+	certPEM, err := ca.SignCSR(csr, NodeCommonName("node1"), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := NewTLSCertificate(&KeyPair{CertPEM: certPEM, KeyPEM: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cert.Leaf.Verify(x509.VerifyOptions{Roots: ca.CertPool(), KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); err != nil {
+		t.Errorf("certificate is not verified by ca, %v", err)
+	}
+}
+
+func TestLoadOrCreateCertificateAuthority(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key")
+	ca, err := LoadOrCreateCertificateAuthority(certFile, keyFile, "test-ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadOrCreateCertificateAuthority(certFile, keyFile, "test-ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(ca.CertPEM()) != string(loaded.CertPEM()) {
+		t.Errorf("expected saved ca is loaded")
+	}
+}
+
+func TestBootstrapToken(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tokens.json")
+	os.WriteFile(file, []byte(`{"tokens":[{"id":"abcdef","secret":"0123456789abcdef"},{"id":"expire","secret":"0123456789abcdef","expiration":"2020-01-01T00:00:00Z"}]}`), 0600)
+	authenticator := NewBootstrapTokenAuthenticator(file)
+	if id, err := authenticator.Authenticate("abcdef.0123456789abcdef"); err != nil || id != "abcdef" {
+		t.Errorf("expected token abcdef, got %s, %v", id, err)
+	}
+	for _, token := range []string{"abcdef.0123456789abcdeg", "expire.0123456789abcdef", "abcdef", "other1.0123456789abcdef"} {
+		if _, err := authenticator.Authenticate(token); err == nil {
+			t.Errorf("expected token %s is rejected", token)
+		}
+	}
+}
+
+func TestRotatingCertificate(t *testing.T) {
+	ca, err := NewSelfSignedCertificateAuthority("test-ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	renewed := 0
+	r, err := NewRotatingCertificate("test", nil, func(current *tls.Certificate) (*KeyPair, error) {
+		renewed += 1
+		return ca.Issue(pkix.Name{CommonName: "test"}, nil, nil, nil, time.Hour)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := r.Current()
+	if renewed != 1 || first == nil {
+		t.Fatalf("expected initial certificate is issued")
+	}
+	if deadline := RotationDeadline(first); !deadline.After(time.Now()) || !deadline.Before(first.Leaf.NotAfter) {
+		t.Errorf("unexpected rotation deadline %v of certificate expiring at %v", deadline, first.Leaf.NotAfter)
+	}
+	if err := r.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	if cert, _ := r.GetCertificate(nil); cert == first {
+		t.Errorf("expected new certificate after rotation")
+	}
+}
+
+func TestSecretBox(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "secret.key")
+	box, err := LoadOrCreateSecretBox(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := box.Seal([]byte("password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(sealed, []byte("password")) {
+		t.Errorf("expected sealed data does not contain plain text")
+	}
+
+	loaded, err := LoadOrCreateSecretBox(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := loaded.Open(sealed); err != nil || string(data) != "password" {
+		t.Errorf("expected data sealed before restart is opened by saved key, got %s, %v", data, err)
+	}
+
+	sealed[len(sealed)-1] ^= 0xff
+	if _, err := loaded.Open(sealed); err == nil {
+		t.Errorf("expected tampered data is rejected")
+	}
+	other, _ := NewSecretBox(bytes.Repeat([]byte{1}, SecretKeySize))
+	sealed, _ = other.Seal([]byte("password"))
+	if _, err := loaded.Open(sealed); err == nil {
+		t.Errorf("expected data sealed by other key is rejected")
+	}
+}
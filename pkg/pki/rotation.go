@@ -0,0 +1,188 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"sync"
+	"time"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// certificate is renewed after this fraction of its lifetime passed
+	DefaultRotationThreshold  = 0.8
+	DefaultRotationRetryDelay = 10 * time.Second
+)
+
+var (
+	CertificateNotReadyError = errors.New("certificate is not ready")
+)
+
+var (
+	certificateRotations = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_pki",
+			Name:           "certificate_rotations_total",
+			Help:           "Number of certificate rotations by certificate name and result",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"name", "result"},
+	)
+	certificateExpiration = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      "fornax_pki",
+			Name:           "certificate_expiration_timestamp_seconds",
+			Help:           "Expiration time of current certificate by certificate name",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"name"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(certificateRotations, certificateExpiration)
+}
+
+// RenewFunc issue a new key pair, current is nil when there is no valid certificate yet
+type RenewFunc func(current *tls.Certificate) (*KeyPair, error)
+
+// RotatingCertificate hold a certificate which is renewed before it expire, it's used in tls config of servers and clients,
+// connections established after rotation use new certificate, existing connections are not affected
+type RotatingCertificate struct {
+	mu    sync.RWMutex
+	name  string
+	cert  *tls.Certificate
+	renew RenewFunc
+}
+
+// NewRotatingCertificate create a rotating certificate using initial key pair, initial is renewed immediately if it's nil or invalid
+func NewRotatingCertificate(name string, initial *KeyPair, renew RenewFunc) (*RotatingCertificate, error) {
+	r := &RotatingCertificate{name: name, renew: renew}
+	if initial != nil {
+		cert, err := NewTLSCertificate(initial)
+		if err == nil && time.Now().Before(cert.Leaf.NotAfter) {
+			r.set(cert)
+			return r, nil
+		}
+		klog.InfoS("Initial certificate is invalid or expired, renew it", "name", name, "err", err)
+	}
+	if err := r.Rotate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// NewTLSCertificate parse key pair into a tls certificate with parsed leaf
+func NewTLSCertificate(pair *KeyPair) (*tls.Certificate, error) {
+	cert, err := tls.X509KeyPair(pair.CertPEM, pair.KeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	certs, err := ParseCertificatesPEM(pair.CertPEM)
+	if err != nil {
+		return nil, err
+	}
+	cert.Leaf = certs[0]
+	return &cert, nil
+}
+
+func (r *RotatingCertificate) set(cert *tls.Certificate) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = cert
+	certificateExpiration.WithLabelValues(r.name).Set(float64(cert.Leaf.NotAfter.Unix()))
+}
+
+// Current return current certificate, it's nil if certificate is expired
+func (r *RotatingCertificate) Current() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.cert == nil || time.Now().After(r.cert.Leaf.NotAfter) {
+		return nil
+	}
+	return r.cert
+}
+
+// Rotate renew certificate now
+func (r *RotatingCertificate) Rotate() error {
+	pair, err := r.renew(r.Current())
+	if err == nil {
+		var cert *tls.Certificate
+		if cert, err = NewTLSCertificate(pair); err == nil {
+			r.set(cert)
+			certificateRotations.WithLabelValues(r.name, "success").Inc()
+			klog.InfoS("Certificate rotated", "name", r.name, "serial", cert.Leaf.SerialNumber.Text(16), "notAfter", cert.Leaf.NotAfter)
+			return nil
+		}
+	}
+	certificateRotations.WithLabelValues(r.name, "failure").Inc()
+	return err
+}
+
+// RotationDeadline return time when certificate should be renewed
+func RotationDeadline(cert *tls.Certificate) time.Time {
+	lifetime := cert.Leaf.NotAfter.Sub(cert.Leaf.NotBefore)
+	return cert.Leaf.NotBefore.Add(time.Duration(float64(lifetime) * DefaultRotationThreshold))
+}
+
+// Run renew certificate before its expiry until context is done, a failed renewal is retried until it succeed
+func (r *RotatingCertificate) Run(ctx context.Context) {
+	for {
+		delay := DefaultRotationRetryDelay
+		if cert := r.Current(); cert != nil {
+			delay = time.Until(RotationDeadline(cert))
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		if err := r.Rotate(); err != nil {
+			klog.ErrorS(err, "Failed to rotate certificate, retry later", "name", r.name)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(DefaultRotationRetryDelay):
+			}
+		}
+	}
+}
+
+// GetCertificate is used as tls.Config.GetCertificate of servers
+func (r *RotatingCertificate) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if cert := r.Current(); cert != nil {
+		return cert, nil
+	}
+	return nil, CertificateNotReadyError
+}
+
+// GetClientCertificate is used as tls.Config.GetClientCertificate of clients,
+// client connect without certificate if it does not have a valid one, server decide if it's allowed
+func (r *RotatingCertificate) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	if cert := r.Current(); cert != nil {
+		return cert, nil
+	}
+	return &tls.Certificate{}, nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	SecretKeySize = 32
+)
+
+var (
+	SealedDataTooShortError = errors.New("sealed data is shorter than nonce")
+)
+
+// SecretBox seal and open secret data with a AES-256-GCM key, every sealed data has its own random nonce prefix
+type SecretBox struct {
+	aead cipher.AEAD
+}
+
+func NewSecretBox(key []byte) (*SecretBox, error) {
+	if len(key) != SecretKeySize {
+		return nil, fmt.Errorf("secret key must be %d bytes, got %d", SecretKeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretBox{aead: aead}, nil
+}
+
+// LoadOrCreateSecretBox load secret key from file, if file does not exist, a random key is generated and saved into file,
+// all replicas of fornaxcore must use same key file, data sealed by other key can not be opened
+func LoadOrCreateSecretBox(keyFile string) (*SecretBox, error) {
+	key, err := os.ReadFile(keyFile)
+	if err == nil {
+		return NewSecretBox(key)
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+	klog.InfoS("Secret key not found, generate a random key", "file", keyFile)
+	key = make([]byte, SecretKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(keyFile), 0700); err != nil {
+		return nil, err
+	}
+	tmp := keyFile + ".tmp"
+	if err := os.WriteFile(tmp, key, 0600); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, keyFile); err != nil {
+		return nil, err
+	}
+	return NewSecretBox(key)
+}
+
+// Seal encrypt data, return nonce followed by cipher text
+func (b *SecretBox) Seal(data []byte) ([]byte, error) {
+	nonce := make([]byte, b.aead.NonceSize(), b.aead.NonceSize()+len(data)+b.aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return b.aead.Seal(nonce, nonce, data, nil), nil
+}
+
+// Open decrypt data sealed by Seal, it fail if data is sealed by other key or tampered
+func (b *SecretBox) Open(sealed []byte) ([]byte, error) {
+	if len(sealed) < b.aead.NonceSize() {
+		return nil, SealedDataTooShortError
+	}
+	nonce, cipherText := sealed[:b.aead.NonceSize()], sealed[b.aead.NonceSize():]
+	return b.aead.Open(nil, nonce, cipherText, nil)
+}
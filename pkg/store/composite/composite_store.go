@@ -27,7 +27,6 @@ import (
 	"k8s.io/klog/v2"
 
 	"centaurusinfra.io/fornax-serverless/pkg/store"
-	"centaurusinfra.io/fornax-serverless/pkg/store/inmemory"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/conversion"
@@ -58,7 +57,7 @@ type CompositeStore struct {
 	mu                sync.Mutex
 	groupResourceKey  string
 	specPersistStore  storage.Interface
-	statusMemoryStore *inmemory.MemoryStore
+	statusMemoryStore store.ApiStorageInterface
 	mergeFunc         func(from runtime.Object, to runtime.Object) error
 	keyFunc           func(obj runtime.Object) (string, error)
 	newFunc           func() runtime.Object
@@ -255,7 +254,7 @@ func (c *CompositeStore) Watch(ctx context.Context, key string, opts storage.Lis
 func NewCompositeStore(
 	groupResource schema.GroupResource,
 	persistStore storage.Interface,
-	memoryStore *inmemory.MemoryStore,
+	memoryStore store.ApiStorageInterface,
 	mergeFunc func(from runtime.Object, to runtime.Object) error,
 	newFunc func() runtime.Object,
 	newListFunc func() runtime.Object,
@@ -0,0 +1,310 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/store"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	"k8s.io/apiserver/pkg/storage/etcd3"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultEtcdDialTimeout = 10 * time.Second
+)
+
+var _ store.ApiStorageInterface = &EtcdStore{}
+
+// EtcdStore implement FornaxStorage using k8s etcd3 storage, fornax extensions are built on top of etcd3 GuaranteedUpdate and Watch,
+// it is used for resources which need durability instead of memory store
+type EtcdStore struct {
+	apistorage.Interface
+	client        *clientv3.Client
+	groupResource schema.GroupResource
+	grvKeyPrefix  string
+	keyFuncMu     sync.RWMutex
+	keyFunc       func(obj runtime.Object) (string, error)
+	newFunc       func() runtime.Object
+	newListFunc   func() runtime.Object
+	indexersMu    sync.RWMutex
+	indexers      cache.Indexers
+}
+
+func NewEtcdStore(groupResource schema.GroupResource, grvKeyPrefix string, config store.ResourceStorageConfiguration, newFunc func() runtime.Object, newListFunc func() runtime.Object) (*EtcdStore, error) {
+	if len(config.EtcdServers) == 0 {
+		return nil, fmt.Errorf("etcd servers are required by etcd store of %s", groupResource.String())
+	}
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   config.EtcdServers,
+		DialTimeout: DefaultEtcdDialTimeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := runtime.NewScheme()
+	if err := fornaxv1.AddToScheme(scheme); err != nil {
+		client.Close()
+		return nil, err
+	}
+	codec := serializer.NewCodecFactory(scheme).LegacyCodec(fornaxv1.SchemeGroupVersion)
+	// objects are compressed in etcd3 value transformer, so, payload sent to etcd and replicated by raft is compressed
+	compressor, err := store.NewPayloadCompressor(groupResource, config.Compression, config.CompressionThresholdBytes)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	klog.InfoS("New etcd store", "resource", groupResource, "servers", config.EtcdServers, "prefix", config.EtcdPrefix, "compression", config.Compression)
+	return &EtcdStore{
+		Interface:     etcd3.New(client, codec, newFunc, config.EtcdPrefix, groupResource, compressor, true, etcd3.NewDefaultLeaseManagerConfig()),
+		client:        client,
+		groupResource: groupResource,
+		grvKeyPrefix:  grvKeyPrefix,
+		newFunc:       newFunc,
+		newListFunc:   newListFunc,
+		indexersMu:    sync.RWMutex{},
+		indexers:      cache.Indexers{},
+	}, nil
+}
+
+func (es *EtcdStore) GroupResource() schema.GroupResource {
+	return es.groupResource
+}
+
+// SetKeyFunc set key func of api server registry, it build storage key of a object
+func (es *EtcdStore) SetKeyFunc(keyFunc func(obj runtime.Object) (string, error)) {
+	es.keyFuncMu.Lock()
+	defer es.keyFuncMu.Unlock()
+	es.keyFunc = keyFunc
+}
+
+// getKey return storage key of object using registry key func, or grv key prefix and namespace/name of object if key func is not set
+func (es *EtcdStore) getKey(obj runtime.Object) (string, error) {
+	es.keyFuncMu.RLock()
+	keyFunc := es.keyFunc
+	es.keyFuncMu.RUnlock()
+	if keyFunc != nil {
+		return keyFunc(obj)
+	}
+	return fmt.Sprintf("%s/%s", es.grvKeyPrefix, util.Name(obj)), nil
+}
+
+func (es *EtcdStore) Stop() error {
+	return es.client.Close()
+}
+
+// WatchWithOldObj implements FornaxStorage
+func (es *EtcdStore) WatchWithOldObj(ctx context.Context, key string, opts apistorage.ListOptions) (store.WatchWithOldObjInterface, error) {
+	// etcd3 watch event does not carry previous object, watcher keep last object of each key to build old object,
+	// when watch from a revision, list objects at that revision first, so, watcher know object state before first event
+	initialObjs := []runtime.Object{}
+	rev, err := store.ParseResourceVersion(opts.ResourceVersion)
+	if err != nil {
+		return nil, err
+	}
+	if rev > 0 {
+		listObj := es.newListFunc()
+		err := es.GetList(ctx, key, apistorage.ListOptions{
+			ResourceVersion:      opts.ResourceVersion,
+			ResourceVersionMatch: metav1.ResourceVersionMatchExact,
+			Predicate:            apistorage.Everything,
+			Recursive:            opts.Recursive,
+		}, listObj)
+		if err != nil {
+			return nil, err
+		}
+		if initialObjs, err = meta.ExtractList(listObj); err != nil {
+			return nil, err
+		}
+	}
+
+	wi, err := es.Watch(ctx, key, opts)
+	if err != nil {
+		return nil, err
+	}
+	watcher := newEtcdStoreWatcher(wi, initialObjs)
+	go watcher.run()
+	return watcher, nil
+}
+
+// EnsureUpdateAndDelete implements FornaxStorage, it update object and delete it if object has delete timestamp and empty finalizer
+func (es *EtcdStore) EnsureUpdateAndDelete(ctx context.Context, key string, ignoreNotFound bool, preconditions *apistorage.Preconditions, updatedObj runtime.Object, output runtime.Object) error {
+	err := es.GuaranteedUpdate(ctx, key, output, ignoreNotFound, preconditions, store.GetTryUpdateFunc(updatedObj), nil)
+	if err != nil {
+		return err
+	}
+
+	if store.ShouldDeleteSpec(output) {
+		return es.Delete(ctx, key, output, preconditions, func(ctx context.Context, obj runtime.Object) error { return nil }, output)
+	}
+
+	return nil
+}
+
+// GetOrCreate implements FornaxStorage, get a object if key exist, if not create it using objToCreate
+func (es *EtcdStore) GetOrCreate(ctx context.Context, key string, objToCreate runtime.Object, out runtime.Object) error {
+	err := es.Create(ctx, key, objToCreate.DeepCopyObject(), out, 0)
+	if apistorage.IsExist(err) {
+		return es.Get(ctx, key, apistorage.GetOptions{IgnoreNotFound: false}, out)
+	}
+	return err
+}
+
+// CreateOrReplace implements FornaxStorage, create object if key does not exist, otherwise replace existing object with objToCreate
+func (es *EtcdStore) CreateOrReplace(ctx context.Context, key string, objToCreate runtime.Object, out runtime.Object) error {
+	return es.GuaranteedUpdate(ctx, key, out, true, nil, func(existing runtime.Object, res apistorage.ResponseMeta) (runtime.Object, *uint64, error) {
+		return objToCreate.DeepCopyObject(), nil, nil
+	}, nil)
+}
+
+// CreateOrUpdate implements FornaxStorage, create object if key does not exist, otherwise merge existing object into obj and update it
+func (es *EtcdStore) CreateOrUpdate(ctx context.Context, key string, obj runtime.Object, out runtime.Object, mergeFunc func(from runtime.Object, to runtime.Object) error) error {
+	return es.GuaranteedUpdate(ctx, key, out, true, nil, func(existing runtime.Object, res apistorage.ResponseMeta) (runtime.Object, *uint64, error) {
+		updatedObj := obj.DeepCopyObject()
+		existingVersion, err := store.GetObjectResourceVersion(existing)
+		if err != nil {
+			return nil, nil, err
+		}
+		// object does not exist if existing object has no revision
+		if existingVersion == 0 {
+			return updatedObj, nil, nil
+		}
+		if mergeFunc == nil {
+			return nil, nil, fmt.Errorf("Do not have merge function to update existing object using provided obj")
+		}
+		if err := mergeFunc(existing, updatedObj); err != nil {
+			return nil, nil, err
+		}
+		return updatedObj, nil, nil
+	}, nil)
+}
+
+// AddIndexers implements FornaxStorage, etcd does not have secondary index, index funcs are kept to filter listed objects
+func (es *EtcdStore) AddIndexers(indexers cache.Indexers) error {
+	es.indexersMu.Lock()
+	defer es.indexersMu.Unlock()
+	for name := range indexers {
+		if _, found := es.indexers[name]; found {
+			return fmt.Errorf("indexer %s already exists", name)
+		}
+	}
+	for name, indexFunc := range indexers {
+		es.indexers[name] = indexFunc
+	}
+	return nil
+}
+
+// ListByIndex implements FornaxStorage, it list all objects under key prefix and return objects having indexed value
+func (es *EtcdStore) ListByIndex(ctx context.Context, key string, indexName, indexedValue string, listObj runtime.Object) error {
+	es.indexersMu.RLock()
+	indexFunc, found := es.indexers[indexName]
+	es.indexersMu.RUnlock()
+	if !found {
+		return apistorage.NewInternalError(fmt.Sprintf("index %s does not exist", indexName))
+	}
+
+	allObjs := es.newListFunc()
+	if err := es.GetList(ctx, key, apistorage.ListOptions{Predicate: apistorage.Everything, Recursive: true}, allObjs); err != nil {
+		return err
+	}
+	objs, err := meta.ExtractList(allObjs)
+	if err != nil {
+		return err
+	}
+	items := []runtime.Object{}
+	for _, obj := range objs {
+		values, err := indexFunc(obj)
+		if err != nil {
+			continue
+		}
+		for _, v := range values {
+			if v == indexedValue {
+				items = append(items, obj)
+				break
+			}
+		}
+	}
+
+	if err := meta.SetList(listObj, items); err != nil {
+		return err
+	}
+	rv, err := meta.NewAccessor().ResourceVersion(allObjs)
+	if err != nil {
+		return err
+	}
+	return meta.NewAccessor().SetResourceVersion(listObj, rv)
+}
+
+// DeleteCollection implements FornaxStorage, it list objects under key prefix and delete them one by one using storage key of each object
+func (es *EtcdStore) DeleteCollection(ctx context.Context, key string, validateDeletion apistorage.ValidateObjectFunc, listObj runtime.Object) error {
+	allObjs := es.newListFunc()
+	if err := es.GetList(ctx, key, apistorage.ListOptions{Predicate: apistorage.Everything, Recursive: true}, allObjs); err != nil {
+		return err
+	}
+	objs, err := meta.ExtractList(allObjs)
+	if err != nil {
+		return err
+	}
+	deleted := []runtime.Object{}
+	deleteErrors := []error{}
+	for _, obj := range objs {
+		objKey, err := es.getKey(obj)
+		if err != nil {
+			return err
+		}
+		out := es.newFunc()
+		if err := es.Delete(ctx, objKey, out, nil, validateDeletion, obj); err != nil {
+			deleteErrors = append(deleteErrors, err)
+			continue
+		}
+		deleted = append(deleted, out)
+	}
+	if err := meta.SetList(listObj, deleted); err != nil {
+		return err
+	}
+	if len(deleteErrors) > 0 {
+		return fmt.Errorf("failed to delete %d objects under %s, errors=%v", len(deleteErrors), key, deleteErrors)
+	}
+	return nil
+}
+
+// SnapshotList implements FornaxStorage, etcd serve a list at exact revision from its mvcc store
+func (es *EtcdStore) SnapshotList(ctx context.Context, key string, rev uint64, listObj runtime.Object) error {
+	opts := apistorage.ListOptions{Predicate: apistorage.Everything, Recursive: true}
+	if rev > 0 {
+		opts.ResourceVersion = strconv.FormatUint(rev, 10)
+		opts.ResourceVersionMatch = metav1.ResourceVersionMatchExact
+	}
+	return es.GetList(ctx, key, opts, listObj)
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"fmt"
+	"testing"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestEtcdStoreObjectKey(t *testing.T) {
+	es := &EtcdStore{grvKeyPrefix: fornaxv1.ApplicationSessionGrvKey}
+	session := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "session-1"}}
+	key, err := es.getKey(session)
+	if err != nil || key != fornaxv1.ApplicationSessionGrvKey+"/ns/session-1" {
+		t.Fatalf("expected key under grv key prefix, got %s, %v", key, err)
+	}
+
+	// registry key func is used when it's set, e.g. a key having resource prefix of api server
+	es.SetKeyFunc(func(obj runtime.Object) (string, error) {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("/registry/sessions/%s/%s", accessor.GetNamespace(), accessor.GetName()), nil
+	})
+	if key, err := es.getKey(session); err != nil || key != "/registry/sessions/ns/session-1" {
+		t.Fatalf("expected key of registry key func, got %s, %v", key, err)
+	}
+}
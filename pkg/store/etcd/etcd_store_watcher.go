@@ -0,0 +1,117 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"sync"
+
+	"centaurusinfra.io/fornax-serverless/pkg/store"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+var _ store.WatchWithOldObjInterface = &etcdStoreWatcher{}
+
+// etcdStoreWatcher convert etcd3 watch events into events with old object,
+// it keep last object of each key received from watch, old object is nil for added event,
+// forwarding stop when watcher is stopped, so, a consumer which stopped reading does not leak forwarding goroutine
+type etcdStoreWatcher struct {
+	stopOnce               sync.Once
+	stopCh                 chan struct{}
+	watcher                watch.Interface
+	lastObjs               map[string]runtime.Object
+	outgoingChanWithOldObj chan store.WatchEventWithOldObj
+}
+
+func newEtcdStoreWatcher(watcher watch.Interface, initialObjs []runtime.Object) *etcdStoreWatcher {
+	wc := &etcdStoreWatcher{
+		stopOnce:               sync.Once{},
+		stopCh:                 make(chan struct{}),
+		watcher:                watcher,
+		lastObjs:               map[string]runtime.Object{},
+		outgoingChanWithOldObj: make(chan store.WatchEventWithOldObj, 500),
+	}
+	for _, v := range initialObjs {
+		wc.lastObjs[util.Name(v)] = v
+	}
+	return wc
+}
+
+func (wc *etcdStoreWatcher) run() {
+	defer close(wc.outgoingChanWithOldObj)
+	for {
+		var event watch.Event
+		var ok bool
+		select {
+		case <-wc.stopCh:
+			return
+		case event, ok = <-wc.watcher.ResultChan():
+			if !ok {
+				return
+			}
+		}
+		if event.Type == watch.Error || event.Type == watch.Bookmark {
+			if !wc.send(store.WatchEventWithOldObj{Type: event.Type, Object: event.Object}) {
+				return
+			}
+			continue
+		}
+		key := util.Name(event.Object)
+		oldObj := wc.lastObjs[key]
+		switch event.Type {
+		case watch.Added:
+			oldObj = nil
+			wc.lastObjs[key] = event.Object
+		case watch.Modified:
+			wc.lastObjs[key] = event.Object
+		case watch.Deleted:
+			delete(wc.lastObjs, key)
+		}
+		if !wc.send(store.WatchEventWithOldObj{
+			Type:      event.Type,
+			Object:    event.Object,
+			OldObject: oldObj,
+		}) {
+			return
+		}
+	}
+}
+
+// send return false if watcher is stopped before event is received
+func (wc *etcdStoreWatcher) send(event store.WatchEventWithOldObj) bool {
+	select {
+	case wc.outgoingChanWithOldObj <- event:
+		return true
+	case <-wc.stopCh:
+		return false
+	}
+}
+
+// ResultChanWithPrevobj implements store.WatchWithOldObjInterface
+func (wc *etcdStoreWatcher) ResultChanWithPrevobj() <-chan store.WatchEventWithOldObj {
+	return wc.outgoingChanWithOldObj
+}
+
+// Stop implements store.WatchWithOldObjInterface
+func (wc *etcdStoreWatcher) Stop() {
+	wc.stopOnce.Do(func() {
+		close(wc.stopCh)
+		wc.watcher.Stop()
+	})
+}
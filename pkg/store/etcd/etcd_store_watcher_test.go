@@ -0,0 +1,72 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"testing"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestEtcdStoreWatcherOldObject(t *testing.T) {
+	initial := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "session-1", ResourceVersion: "1"}}
+	fake := watch.NewFake()
+	wc := newEtcdStoreWatcher(fake, []runtime.Object{initial})
+	go wc.run()
+	defer wc.Stop()
+
+	updated := initial.DeepCopy()
+	updated.ResourceVersion = "2"
+	go fake.Modify(updated)
+	event := <-wc.ResultChanWithPrevobj()
+	if event.Type != watch.Modified || event.OldObject != initial || event.Object != updated {
+		t.Fatalf("expected modified event with initial object as old object, got %v", event)
+	}
+
+	added := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "session-2", ResourceVersion: "3"}}
+	go fake.Add(added)
+	if event := <-wc.ResultChanWithPrevobj(); event.Type != watch.Added || event.OldObject != nil {
+		t.Fatalf("expected added event without old object, got %v", event)
+	}
+}
+
+func TestEtcdStoreWatcherStopUnblockForwarding(t *testing.T) {
+	fake := watch.NewFakeWithChanSize(1000, false)
+	wc := newEtcdStoreWatcher(fake, nil)
+	done := make(chan struct{})
+	go func() {
+		wc.run()
+		close(done)
+	}()
+
+	// consumer never read, forwarding block after outgoing channel is full
+	for i := 0; i < cap(wc.outgoingChanWithOldObj)+10; i++ {
+		fake.Add(&fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "session", ResourceVersion: "1"}})
+	}
+	wc.Stop()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("forwarding goroutine is still blocked after watcher is stopped")
+	}
+	for range wc.ResultChanWithPrevobj() {
+	}
+}
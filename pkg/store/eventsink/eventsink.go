@@ -0,0 +1,172 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventsink
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+
+	"centaurusinfra.io/fornax-serverless/pkg/store"
+)
+
+const (
+	DefaultQueueSize    = 10000
+	DefaultBatchSize    = 100
+	DefaultRetryBackoff = time.Second
+	DefaultMaxRetries   = 3
+)
+
+var (
+	publishedEvents = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_event_sink",
+			Name:           "published_events_total",
+			Help:           "Number of store events published to message bus",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource"},
+	)
+	droppedEvents = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_event_sink",
+			Name:           "dropped_events_total",
+			Help:           "Number of store events dropped because queue is full or message bus is unavailable",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(publishedEvents, droppedEvents)
+}
+
+// Event is a committed change of a object, OldObject is nil for a created object, Object is nil for a deleted object
+type Event struct {
+	Type      watch.EventType `json:"type"`
+	Resource  string          `json:"resource"`
+	Key       string          `json:"key"`
+	Revision  uint64          `json:"revision"`
+	Timestamp time.Time       `json:"timestamp"`
+	Object    runtime.Object  `json:"object,omitempty"`
+	OldObject runtime.Object  `json:"oldObject,omitempty"`
+}
+
+// Publisher send a batch of events to message bus in order, a error means none or part of batch is published,
+// failed batch is retried, consumers dedupe events by key and revision
+type Publisher interface {
+	Publish(ctx context.Context, events []*Event) error
+	Close() error
+}
+
+// NewPublisher create a publisher of configured message bus
+func NewPublisher(groupResource schema.GroupResource, config *store.EventSinkConfiguration) (Publisher, error) {
+	topic := config.Topic
+	if len(topic) == 0 {
+		topic = fmt.Sprintf("fornax.%s", groupResource.Resource)
+	}
+	switch config.Type {
+	case store.EventSinkNats:
+		return NewNatsPublisher(config.Address, topic), nil
+	case store.EventSinkKafkaRest:
+		return NewKafkaRestPublisher(config.Address, topic), nil
+	default:
+		return nil, fmt.Errorf("unsupported event sink %s", config.Type)
+	}
+}
+
+// Journal queue events of a store and publish them in background, Add never block,
+// a batch is retried a few times when message bus is unavailable, then dropped, so, a broken message bus do not grow memory
+type Journal struct {
+	resource  string
+	publisher Publisher
+	queue     chan *Event
+}
+
+func NewJournal(groupResource schema.GroupResource, publisher Publisher, queueSize int) *Journal {
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+	return &Journal{
+		resource:  groupResource.String(),
+		publisher: publisher,
+		queue:     make(chan *Event, queueSize),
+	}
+}
+
+// Add enqueue events, return false if some events are dropped because queue is full
+func (j *Journal) Add(events ...*Event) bool {
+	for i, v := range events {
+		select {
+		case j.queue <- v:
+		default:
+			dropped := len(events) - i
+			droppedEvents.WithLabelValues(j.resource).Add(float64(dropped))
+			klog.InfoS("Event sink queue is full, drop events", "resource", j.resource, "dropped", dropped)
+			return false
+		}
+	}
+	return true
+}
+
+// Run publish queued events until context is done, publisher is closed when it return
+func (j *Journal) Run(ctx context.Context) {
+	defer j.publisher.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-j.queue:
+			batch := []*Event{event}
+		collect:
+			for len(batch) < DefaultBatchSize {
+				select {
+				case v := <-j.queue:
+					batch = append(batch, v)
+				default:
+					break collect
+				}
+			}
+			j.publish(ctx, batch)
+		}
+	}
+}
+
+func (j *Journal) publish(ctx context.Context, batch []*Event) {
+	var err error
+	for i := 0; i < DefaultMaxRetries; i++ {
+		if err = j.publisher.Publish(ctx, batch); err == nil {
+			publishedEvents.WithLabelValues(j.resource).Add(float64(len(batch)))
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(DefaultRetryBackoff):
+		}
+	}
+	droppedEvents.WithLabelValues(j.resource).Add(float64(len(batch)))
+	klog.ErrorS(err, "Failed to publish events to message bus, drop them", "resource", j.resource, "events", len(batch), "firstRevision", batch[0].Revision)
+}
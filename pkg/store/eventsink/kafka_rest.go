@@ -0,0 +1,89 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventsink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	kafkaRestContentType    = "application/vnd.kafka.json.v2+json"
+	kafkaRestRequestTimeout = 10 * time.Second
+)
+
+type kafkaRestRecord struct {
+	Key   string `json:"key"`
+	Value *Event `json:"value"`
+}
+
+type kafkaRestRequest struct {
+	Records []kafkaRestRecord `json:"records"`
+}
+
+// KafkaRestPublisher publish events to a kafka topic through kafka rest proxy v2 api,
+// object key is record key, so, events of a object go to same partition and keep their order
+type KafkaRestPublisher struct {
+	url    string
+	client *http.Client
+}
+
+var _ Publisher = &KafkaRestPublisher{}
+
+func NewKafkaRestPublisher(address, topic string) *KafkaRestPublisher {
+	return &KafkaRestPublisher{
+		url:    fmt.Sprintf("%s/topics/%s", strings.TrimSuffix(address, "/"), topic),
+		client: &http.Client{Timeout: kafkaRestRequestTimeout},
+	}
+}
+
+func (p *KafkaRestPublisher) Publish(ctx context.Context, events []*Event) error {
+	request := kafkaRestRequest{Records: make([]kafkaRestRecord, 0, len(events))}
+	for _, v := range events {
+		request.Records = append(request.Records, kafkaRestRecord{Key: v.Key, Value: v})
+	}
+	data, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	httpRequest.Header.Set("Content-Type", kafkaRestContentType)
+	resp, err := p.client.Do(httpRequest)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("kafka rest proxy returned %s: %s", resp.Status, string(body))
+	}
+	return nil
+}
+
+func (p *KafkaRestPublisher) Close() error {
+	p.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventsink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	natsDialTimeout  = 5 * time.Second
+	natsWriteTimeout = 10 * time.Second
+)
+
+// NatsPublisher publish events to a nats subject using nats core text protocol, one message per event,
+// it only need PUB, so, it talk to server directly instead of pulling in a nats client,
+// connection is dialed lazily and redialed after a error, PING from server is answered by a reader loop
+type NatsPublisher struct {
+	address string
+	subject string
+	mu      sync.Mutex
+	conn    net.Conn
+	writer  *bufio.Writer
+}
+
+var _ Publisher = &NatsPublisher{}
+
+func NewNatsPublisher(address, subject string) *NatsPublisher {
+	return &NatsPublisher{
+		address: strings.TrimPrefix(address, "nats://"),
+		subject: subject,
+	}
+}
+
+func (p *NatsPublisher) connect() error {
+	conn, err := net.DialTimeout("tcp", p.address, natsDialTimeout)
+	if err != nil {
+		return err
+	}
+	reader := bufio.NewReader(conn)
+	conn.SetReadDeadline(time.Now().Add(natsDialTimeout))
+	info, err := reader.ReadString('\n')
+	if err != nil || !strings.HasPrefix(info, "INFO") {
+		conn.Close()
+		return fmt.Errorf("nats server %s did not send INFO: %v", p.address, err)
+	}
+	conn.SetReadDeadline(time.Time{})
+	writer := bufio.NewWriter(conn)
+	if _, err := writer.WriteString("CONNECT {\"verbose\":false,\"pedantic\":false,\"name\":\"fornaxcore\"}\r\n"); err != nil {
+		conn.Close()
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		conn.Close()
+		return err
+	}
+	p.conn = conn
+	p.writer = writer
+	go p.readLoop(conn, reader)
+	return nil
+}
+
+// readLoop answer PING and log server error until connection is closed
+func (p *NatsPublisher) readLoop(conn net.Conn, reader *bufio.Reader) {
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		switch {
+		case strings.HasPrefix(line, "PING"):
+			p.mu.Lock()
+			if p.conn == conn {
+				p.writer.WriteString("PONG\r\n")
+				p.writer.Flush()
+			}
+			p.mu.Unlock()
+		case strings.HasPrefix(line, "-ERR"):
+			klog.InfoS("Nats server returned error", "address", p.address, "error", strings.TrimSpace(line))
+		}
+	}
+}
+
+func (p *NatsPublisher) closeConn() {
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn = nil
+		p.writer = nil
+	}
+}
+
+func (p *NatsPublisher) Publish(ctx context.Context, events []*Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn == nil {
+		if err := p.connect(); err != nil {
+			return err
+		}
+	}
+	p.conn.SetWriteDeadline(time.Now().Add(natsWriteTimeout))
+	for _, v := range events {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(p.writer, "PUB %s %d\r\n", p.subject, len(data)); err != nil {
+			p.closeConn()
+			return err
+		}
+		p.writer.Write(data)
+		if _, err := p.writer.WriteString("\r\n"); err != nil {
+			p.closeConn()
+			return err
+		}
+	}
+	if err := p.writer.Flush(); err != nil {
+		p.closeConn()
+		return err
+	}
+	return nil
+}
+
+func (p *NatsPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closeConn()
+	return nil
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	"k8s.io/klog/v2"
+)
+
+// AccessPartition restrict users and groups to only see objects matching label selector,
+// e.g. team foo can only see applications labeled team.core.fornax-serverless.centaurusinfra.io=foo
+type AccessPartition struct {
+	Name   string   `json:"name"`
+	Users  []string `json:"users,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+
+	// label selector of visible objects
+	LabelSelector string `json:"labelSelector"`
+
+	selector labels.Selector
+}
+
+type AccessPartitionConfiguration struct {
+	Partitions []AccessPartition `json:"partitions,omitempty"`
+}
+
+var (
+	_AccessPartitionsMutex = &sync.RWMutex{}
+	_AccessPartitions      = []AccessPartition{}
+)
+
+// LoadAccessPartitions read access partitions from a json file, nobody is restricted if file does not exist,
+// a user matching no partition and users in system:masters group are not restricted
+func LoadAccessPartitions(file string) error {
+	config := &AccessPartitionConfiguration{}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return fmt.Errorf("invalid access partition configuration %s: %v", file, err)
+	}
+	for i := range config.Partitions {
+		partition := &config.Partitions[i]
+		if partition.selector, err = labels.Parse(partition.LabelSelector); err != nil {
+			return fmt.Errorf("invalid label selector of access partition %s: %v", partition.Name, err)
+		}
+		klog.InfoS("Access partition configured", "name", partition.Name, "users", partition.Users, "groups", partition.Groups, "selector", partition.LabelSelector)
+	}
+	_AccessPartitionsMutex.Lock()
+	defer _AccessPartitionsMutex.Unlock()
+	_AccessPartitions = config.Partitions
+	return nil
+}
+
+func (p *AccessPartition) matchUser(u user.Info) bool {
+	for _, v := range p.Users {
+		if v == u.GetName() {
+			return true
+		}
+	}
+	for _, v := range p.Groups {
+		for _, g := range u.GetGroups() {
+			if v == g {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// accessSelectors return label selectors of partitions matching request user, object is visible if it match any selector,
+// nil means user is not restricted, requests from fornaxcore itself do not have a user
+func accessSelectors(ctx context.Context) []labels.Selector {
+	u, found := request.UserFrom(ctx)
+	if !found {
+		return nil
+	}
+	for _, g := range u.GetGroups() {
+		if g == user.SystemPrivilegedGroup {
+			return nil
+		}
+	}
+	_AccessPartitionsMutex.RLock()
+	defer _AccessPartitionsMutex.RUnlock()
+	var selectors []labels.Selector
+	for i := range _AccessPartitions {
+		if _AccessPartitions[i].matchUser(u) {
+			selectors = append(selectors, _AccessPartitions[i].selector)
+		}
+	}
+	return selectors
+}
+
+func visible(selectors []labels.Selector, obj runtime.Object) bool {
+	if selectors == nil {
+		return true
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	for _, v := range selectors {
+		if v.Matches(labels.Set(accessor.GetLabels())) {
+			return true
+		}
+	}
+	return false
+}
+
+// accessPartitionStore hide objects not visible to request user, invisible object is reported as not found,
+// creating a object or updating it to be invisible to user self is forbidden
+type accessPartitionStore struct {
+	apistorage.Interface
+	groupResource schema.GroupResource
+}
+
+func newAccessPartitionStore(s apistorage.Interface, groupResource schema.GroupResource) *accessPartitionStore {
+	return &accessPartitionStore{
+		Interface:     s,
+		groupResource: groupResource,
+	}
+}
+
+func (s *accessPartitionStore) forbidden(obj runtime.Object) error {
+	name := ""
+	if accessor, err := meta.Accessor(obj); err == nil {
+		name = accessor.GetName()
+	}
+	return apierrors.NewForbidden(s.groupResource, name, fmt.Errorf("object is out of access partitions of user"))
+}
+
+func (s *accessPartitionStore) Create(ctx context.Context, key string, obj, out runtime.Object, ttl uint64) error {
+	if !visible(accessSelectors(ctx), obj) {
+		return s.forbidden(obj)
+	}
+	return s.Interface.Create(ctx, key, obj, out, ttl)
+}
+
+func (s *accessPartitionStore) Get(ctx context.Context, key string, opts apistorage.GetOptions, out runtime.Object) error {
+	if err := s.Interface.Get(ctx, key, opts, out); err != nil {
+		return err
+	}
+	if !visible(accessSelectors(ctx), out) {
+		return apistorage.NewKeyNotFoundError(key, 0)
+	}
+	return nil
+}
+
+func (s *accessPartitionStore) GetList(ctx context.Context, key string, opts apistorage.ListOptions, listObj runtime.Object) error {
+	if err := s.Interface.GetList(ctx, key, opts, listObj); err != nil {
+		return err
+	}
+	selectors := accessSelectors(ctx)
+	if selectors == nil {
+		return nil
+	}
+	items, err := meta.ExtractList(listObj)
+	if err != nil {
+		return err
+	}
+	visibleItems := []runtime.Object{}
+	for _, v := range items {
+		if visible(selectors, v) {
+			visibleItems = append(visibleItems, v)
+		}
+	}
+	return meta.SetList(listObj, visibleItems)
+}
+
+func (s *accessPartitionStore) Watch(ctx context.Context, key string, opts apistorage.ListOptions) (watch.Interface, error) {
+	w, err := s.Interface.Watch(ctx, key, opts)
+	if err != nil {
+		return nil, err
+	}
+	selectors := accessSelectors(ctx)
+	if selectors == nil {
+		return w, nil
+	}
+	return watch.Filter(w, func(in watch.Event) (watch.Event, bool) {
+		if in.Type == watch.Bookmark || in.Type == watch.Error {
+			return in, true
+		}
+		return in, visible(selectors, in.Object)
+	}), nil
+}
+
+func (s *accessPartitionStore) GuaranteedUpdate(ctx context.Context, key string, destination runtime.Object, ignoreNotFound bool, preconditions *apistorage.Preconditions, tryUpdate apistorage.UpdateFunc, cachedExistingObject runtime.Object) error {
+	selectors := accessSelectors(ctx)
+	if selectors == nil {
+		return s.Interface.GuaranteedUpdate(ctx, key, destination, ignoreNotFound, preconditions, tryUpdate, cachedExistingObject)
+	}
+	return s.Interface.GuaranteedUpdate(ctx, key, destination, ignoreNotFound, preconditions, func(existing runtime.Object, res apistorage.ResponseMeta) (runtime.Object, *uint64, error) {
+		if !visible(selectors, existing) {
+			return nil, nil, apistorage.NewKeyNotFoundError(key, 0)
+		}
+		updated, ttl, err := tryUpdate(existing, res)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !visible(selectors, updated) {
+			return nil, nil, s.forbidden(updated)
+		}
+		return updated, ttl, nil
+	}, cachedExistingObject)
+}
+
+func (s *accessPartitionStore) Delete(ctx context.Context, key string, out runtime.Object, preconditions *apistorage.Preconditions, validateDeletion apistorage.ValidateObjectFunc, cachedExistingObject runtime.Object) error {
+	selectors := accessSelectors(ctx)
+	if selectors == nil {
+		return s.Interface.Delete(ctx, key, out, preconditions, validateDeletion, cachedExistingObject)
+	}
+	return s.Interface.Delete(ctx, key, out, preconditions, func(ctx context.Context, obj runtime.Object) error {
+		if !visible(selectors, obj) {
+			return apistorage.NewKeyNotFoundError(key, 0)
+		}
+		return validateDeletion(ctx, obj)
+	}, cachedExistingObject)
+}
@@ -29,10 +29,13 @@ import (
 	"k8s.io/apiserver/pkg/storage/storagebackend"
 	"k8s.io/apiserver/pkg/storage/storagebackend/factory"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
 
 	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
 	fornaxstore "centaurusinfra.io/fornax-serverless/pkg/store"
 	"centaurusinfra.io/fornax-serverless/pkg/store/composite"
+	"centaurusinfra.io/fornax-serverless/pkg/store/etcd"
+	"centaurusinfra.io/fornax-serverless/pkg/store/eventsink"
 	"centaurusinfra.io/fornax-serverless/pkg/store/inmemory"
 	"centaurusinfra.io/fornax-serverless/pkg/store/storage"
 	"centaurusinfra.io/fornax-serverless/pkg/util"
@@ -40,11 +43,57 @@ import (
 
 var (
 	_FornaxInMemoryStoresMutex  = &sync.RWMutex{}
-	_InMemoryResourceStores     = map[string]*inmemory.MemoryStore{}
+	_InMemoryResourceStores     = map[string]inmemory.FornaxMemoryStore{}
+	_InMemoryResourceKinds      = map[string]inMemoryResourceKind{}
+	_EtcdResourceStores         = map[string]*etcd.EtcdStore{}
 	_FornaxCompositeStoresMutex = &sync.RWMutex{}
 	_CompositedResourceStores   = map[string]*composite.CompositeStore{}
+	_FornaxStorageConfiguration = &fornaxstore.FornaxStorageConfiguration{}
+	_ExtensionGroupResources    = map[schema.GroupResource]bool{}
 )
 
+// InitFornaxStorageConfiguration load per resource storage configuration from file and watch file change,
+// it must be called before stores are created, changed configuration is applied to existing memory stores
+func InitFornaxStorageConfiguration(ctx context.Context, file string) error {
+	config, err := fornaxstore.LoadFornaxStorageConfiguration(file)
+	if err != nil {
+		return err
+	}
+	_FornaxInMemoryStoresMutex.Lock()
+	_FornaxStorageConfiguration = config
+	_FornaxInMemoryStoresMutex.Unlock()
+
+	fornaxstore.WatchFornaxStorageConfiguration(ctx, file, config, func(newConfig *fornaxstore.FornaxStorageConfiguration) {
+		_FornaxInMemoryStoresMutex.Lock()
+		defer _FornaxInMemoryStoresMutex.Unlock()
+		_FornaxStorageConfiguration = newConfig
+		for _, si := range _InMemoryResourceStores {
+			si.ApplyConfiguration(newConfig.ForGroupResource(si.GroupResource()))
+		}
+	})
+	return nil
+}
+
+// InitRevisionGuard restore memory revision from revision file of storage configuration, defaultFile is used if configuration does not set it,
+// it must be called after InitFornaxStorageConfiguration and before any memory store is created
+func InitRevisionGuard(defaultFile string) error {
+	_FornaxInMemoryStoresMutex.RLock()
+	file := defaultFile
+	if _FornaxStorageConfiguration != nil && len(_FornaxStorageConfiguration.RevisionFile) > 0 {
+		file = _FornaxStorageConfiguration.RevisionFile
+	}
+	_FornaxInMemoryStoresMutex.RUnlock()
+	klog.InfoS("Guarding memory revision", "file", file)
+	return inmemory.InitRevisionGuard(file)
+}
+
+// inMemoryResourceKind is how objects of a memory store are keyed and created
+type inMemoryResourceKind struct {
+	keyPrefix   string
+	newFunc     func() runtime.Object
+	newListFunc func() runtime.Object
+}
+
 type FornaxRestOptionsFactory struct {
 	OptionsGetter generic.RESTOptionsGetter
 }
@@ -55,6 +104,10 @@ func (f *FornaxRestOptionsFactory) GetRESTOptions(resource schema.GroupResource)
 		options.Decorator = CompositedFornaxApplicationStorageFunc
 	} else if resource == fornaxv1.ApplicationSessionGrv.GroupResource() {
 		options.Decorator = FornaxApplicationSessionStorageFunc
+	} else if resource == fornaxv1.FornaxQuotaGrv.GroupResource() || resource == fornaxv1.NodeOperationGrv.GroupResource() ||
+		resource == fornaxv1.NodeLeaseGrv.GroupResource() || resource == fornaxv1.NodeConfigProfileGrv.GroupResource() ||
+		resource == fornaxv1.SessionUsageGrv.GroupResource() || resource == fornaxv1.FornaxEventGrv.GroupResource() || isExtensionGroupResource(resource) {
+		options.Decorator = RegisteredFornaxStorageFunc
 	} else {
 		return options, fmt.Errorf("unknown resource %v", resource)
 	}
@@ -90,25 +143,108 @@ func JsonFromApplication(obj interface{}) ([]byte, error) {
 	return bytes, nil
 }
 
-func NewFornaxApplicationStatusStorage(ctx context.Context) *inmemory.MemoryStore {
-	return newFornaxStorage(ctx, fornaxv1.ApplicationGrv.GroupResource(), fornaxv1.ApplicationGrvKey, nil, nil)
+func NewFornaxApplicationStatusStorage(ctx context.Context) fornaxstore.ApiStorageInterface {
+	return newFornaxStorage(ctx, fornaxv1.ApplicationGrv.GroupResource(), fornaxv1.ApplicationGrvKey,
+		func() runtime.Object { return &fornaxv1.Application{} },
+		func() runtime.Object { return &fornaxv1.ApplicationList{} })
+}
+
+func NewNodeOperationStorage(ctx context.Context) fornaxstore.ApiStorageInterface {
+	return newFornaxStorage(ctx, fornaxv1.NodeOperationGrv.GroupResource(), fornaxv1.NodeOperationGrvKey,
+		func() runtime.Object { return &fornaxv1.NodeOperation{} },
+		func() runtime.Object { return &fornaxv1.NodeOperationList{} })
+}
+
+func NewNodeLeaseStorage(ctx context.Context) fornaxstore.ApiStorageInterface {
+	return newFornaxStorage(ctx, fornaxv1.NodeLeaseGrv.GroupResource(), fornaxv1.NodeLeaseGrvKey,
+		func() runtime.Object { return &fornaxv1.NodeLease{} },
+		func() runtime.Object { return &fornaxv1.NodeLeaseList{} })
 }
 
-func NewFornaxApplicationSessionStorage(ctx context.Context) *inmemory.MemoryStore {
-	return newFornaxStorage(ctx, fornaxv1.ApplicationSessionGrv.GroupResource(), fornaxv1.ApplicationSessionGrvKey, nil, nil)
+func NewNodeConfigProfileStorage(ctx context.Context) fornaxstore.ApiStorageInterface {
+	return newFornaxStorage(ctx, fornaxv1.NodeConfigProfileGrv.GroupResource(), fornaxv1.NodeConfigProfileGrvKey,
+		func() runtime.Object { return &fornaxv1.NodeConfigProfile{} },
+		func() runtime.Object { return &fornaxv1.NodeConfigProfileList{} })
 }
 
-func newFornaxStorage(ctx context.Context, groupResource schema.GroupResource, grvKey string, newFunc func() runtime.Object, newListFunc func() runtime.Object) *inmemory.MemoryStore {
+func NewSessionUsageStorage(ctx context.Context) fornaxstore.ApiStorageInterface {
+	return newFornaxStorage(ctx, fornaxv1.SessionUsageGrv.GroupResource(), fornaxv1.SessionUsageGrvKey,
+		func() runtime.Object { return &fornaxv1.SessionUsage{} },
+		func() runtime.Object { return &fornaxv1.SessionUsageList{} })
+}
+
+func NewFornaxEventStorage(ctx context.Context) fornaxstore.ApiStorageInterface {
+	return newFornaxStorage(ctx, fornaxv1.FornaxEventGrv.GroupResource(), fornaxv1.FornaxEventGrvKey,
+		func() runtime.Object { return &fornaxv1.FornaxEvent{} },
+		func() runtime.Object { return &fornaxv1.FornaxEventList{} })
+}
+
+func NewFornaxApplicationSessionStorage(ctx context.Context) fornaxstore.ApiStorageInterface {
+	return newFornaxStorage(ctx, fornaxv1.ApplicationSessionGrv.GroupResource(), fornaxv1.ApplicationSessionGrvKey,
+		func() runtime.Object { return &fornaxv1.ApplicationSession{} },
+		func() runtime.Object { return &fornaxv1.ApplicationSessionList{} })
+}
+
+// NewExtensionStorage create store of a extension resource registered with fornax api server, it must be called before api server start
+func NewExtensionStorage(ctx context.Context, grv schema.GroupVersionResource, newFunc func() runtime.Object, newListFunc func() runtime.Object) fornaxstore.ApiStorageInterface {
+	_FornaxInMemoryStoresMutex.Lock()
+	_ExtensionGroupResources[grv.GroupResource()] = true
+	_FornaxInMemoryStoresMutex.Unlock()
+	return newFornaxStorage(ctx, grv.GroupResource(), fmt.Sprintf("/%s/%s", grv.Group, grv.Resource), newFunc, newListFunc)
+}
+
+func isExtensionGroupResource(groupResource schema.GroupResource) bool {
+	_FornaxInMemoryStoresMutex.RLock()
+	defer _FornaxInMemoryStoresMutex.RUnlock()
+	return _ExtensionGroupResources[groupResource]
+}
+
+// newFornaxStorage create a singleton store of a groupResource using backend in storage configuration, default memory store
+func newFornaxStorage(ctx context.Context, groupResource schema.GroupResource, grvKey string, newFunc func() runtime.Object, newListFunc func() runtime.Object) fornaxstore.ApiStorageInterface {
 	_FornaxInMemoryStoresMutex.Lock()
 	defer _FornaxInMemoryStoresMutex.Unlock()
 	key := groupResource.String()
 	if si, found := _InMemoryResourceStores[key]; found {
 		return si
-	} else {
-		si = inmemory.NewMemoryStore(ctx, groupResource, grvKey, newFunc, newListFunc)
-		_InMemoryResourceStores[key] = si
+	}
+	if si, found := _EtcdResourceStores[key]; found {
+		return si
+	}
+
+	config := _FornaxStorageConfiguration.ForGroupResource(groupResource)
+	if config.Backend == fornaxstore.StorageBackendEtcd {
+		si, err := etcd.NewEtcdStore(groupResource, grvKey, config, newFunc, newListFunc)
+		if err != nil {
+			klog.Fatalf("Failed to create etcd store of %s, error: %v", key, err)
+		}
+		_EtcdResourceStores[key] = si
 		return si
 	}
+
+	var si inmemory.FornaxMemoryStore
+	if config.Shards > 1 {
+		si = inmemory.NewShardedMemoryStore(ctx, groupResource, grvKey, newFunc, newListFunc, config)
+	} else {
+		si = inmemory.NewMemoryStore(ctx, groupResource, grvKey, newFunc, newListFunc, config)
+	}
+	if len(config.PersistenceDir) > 0 {
+		if err := si.EnablePersistence(config.PersistenceDir); err != nil {
+			klog.Fatalf("Failed to recover memory store of %s from %s, error: %v", key, config.PersistenceDir, err)
+		}
+	}
+	if config.EventSink != nil {
+		publisher, err := eventsink.NewPublisher(groupResource, config.EventSink)
+		if err != nil {
+			klog.Fatalf("Failed to create event sink of %s, error: %v", key, err)
+		}
+		journal := eventsink.NewJournal(groupResource, publisher, config.EventSink.QueueSize)
+		go journal.Run(ctx)
+		si.SetEventJournal(journal)
+		klog.InfoS("Export memory store events", "resource", key, "sink", config.EventSink.Type, "address", config.EventSink.Address)
+	}
+	_InMemoryResourceStores[key] = si
+	_InMemoryResourceKinds[key] = inMemoryResourceKind{keyPrefix: grvKey, newFunc: newFunc, newListFunc: newListFunc}
+	return si
 }
 
 // this function is provided to k8s api server to get resource storage.Interface
@@ -126,7 +262,7 @@ func CompositedFornaxApplicationStorageFunc(
 	_FornaxCompositeStoresMutex.Lock()
 	defer _FornaxCompositeStoresMutex.Unlock()
 	if s, f := _CompositedResourceStores[key]; f {
-		return s, s.DestroyFunc, nil
+		return newGenerateNameStore(newAccessPartitionStore(s, storageConfig.GroupResource)), s.DestroyFunc, nil
 	}
 
 	specStore, persistStoreDestroyFunc, err := factory.Create(*storageConfig, newFunc)
@@ -134,15 +270,17 @@ func CompositedFornaxApplicationStorageFunc(
 		return specStore, persistStoreDestroyFunc, err
 	}
 	statusStore := NewFornaxApplicationStatusStorage(context.Background())
-	statusStore.CompleteWithFunctions(keyFunc, newFunc, newListFunc, getAttrsFunc, triggerFuncs, indexers)
+	if ms, ok := statusStore.(inmemory.FornaxMemoryStore); ok {
+		ms.CompleteWithFunctions(keyFunc, newFunc, newListFunc, getAttrsFunc, triggerFuncs, indexers)
+	}
 	destroyFunc := func() {
 		persistStoreDestroyFunc()
-		statusStore.Stop()
+		stopFornaxStorage(statusStore)
 	}
 	cStore := composite.NewCompositeStore(storageConfig.GroupResource, specStore, statusStore, applicationStatusAndRevisionMerge, newFunc, newListFunc, keyFunc, destroyFunc)
 	cStore.Run(context.Background())
 	_CompositedResourceStores[key] = cStore
-	return cStore, destroyFunc, nil
+	return newGenerateNameStore(newAccessPartitionStore(cStore, storageConfig.GroupResource)), destroyFunc, nil
 }
 
 func applicationKeyFunc(o runtime.Object) (string, error) {
@@ -186,22 +324,29 @@ func FornaxApplicationSessionStorageFunc(
 	triggerFuncs apistorage.IndexerFuncs,
 	indexers *cache.Indexers) (apistorage.Interface, factory.DestroyFunc, error) {
 
-	var storage *inmemory.MemoryStore
 	_FornaxInMemoryStoresMutex.Lock()
 	key := storageConfig.GroupResource.String()
 	defer _FornaxInMemoryStoresMutex.Unlock()
-	if b, f := _InMemoryResourceStores[key]; !f {
-		return nil, nil, fmt.Errorf("Can not find a regisgered store for %s", key)
-	} else {
-		storage = b
+	if ms, f := _InMemoryResourceStores[key]; f {
+		ms.CompleteWithFunctions(keyFunc, newFunc, newListFunc, getAttrsFunc, triggerFuncs, indexers)
+		quotaStore := newFornaxQuotaStore(ms, storageConfig.GroupResource, fornaxv1.ApplicationSessionGrvKey, applicationSessionLimit)
+		return &sessionBackpressureStore{Interface: newGenerateNameStore(quotaStore)}, func() { ms.Stop() }, nil
 	}
-
-	storage.CompleteWithFunctions(keyFunc, newFunc, newListFunc, getAttrsFunc, triggerFuncs, indexers)
-	destroyFunc := func() {
-		storage.Stop()
+	if es, f := _EtcdResourceStores[key]; f {
+		es.SetKeyFunc(keyFunc)
+		quotaStore := newFornaxQuotaStore(es, storageConfig.GroupResource, fornaxv1.ApplicationSessionGrvKey, applicationSessionLimit)
+		return &sessionBackpressureStore{Interface: newGenerateNameStore(quotaStore)}, func() { es.Stop() }, nil
 	}
+	return nil, nil, fmt.Errorf("Can not find a regisgered store for %s", key)
+}
 
-	return storage, destroyFunc, nil
+func stopFornaxStorage(s fornaxstore.ApiStorageInterface) {
+	switch si := s.(type) {
+	case inmemory.FornaxMemoryStore:
+		si.Stop()
+	case *etcd.EtcdStore:
+		si.Stop()
+	}
 }
 
 func GetApplicationSessionCache(store fornaxstore.ApiStorageInterface, sessionLabel string) (*fornaxv1.ApplicationSession, error) {
@@ -230,9 +375,11 @@ func GetApplicationCache(store fornaxstore.ApiStorageInterface, applicationLabel
 }
 
 func CreateApplicationSession(ctx context.Context, store fornaxstore.ApiStorageInterface, session *fornaxv1.ApplicationSession) (*fornaxv1.ApplicationSession, error) {
+	// session created by fornaxcore does not go through api server, set request id here
+	session.PrepareForCreate(ctx)
 	out := &fornaxv1.ApplicationSession{}
 	key := fmt.Sprintf("%s/%s", fornaxv1.ApplicationSessionGrvKey, util.Name(session))
-	err := store.Create(ctx, key, session, out, uint64(0))
+	err := createWithGenerateName(ctx, store, key, session, out, uint64(0))
 	if err != nil {
 		return nil, err
 	}
@@ -242,7 +389,7 @@ func CreateApplicationSession(ctx context.Context, store fornaxstore.ApiStorageI
 func CreateApplication(ctx context.Context, store fornaxstore.ApiStorageInterface, application *fornaxv1.Application) (*fornaxv1.Application, error) {
 	out := &fornaxv1.Application{}
 	key := fmt.Sprintf("%s/%s", fornaxv1.ApplicationGrvKey, util.Name(application))
-	err := store.Create(ctx, key, application, out, uint64(0))
+	err := createWithGenerateName(ctx, store, key, application, out, uint64(0))
 	if err != nil {
 		return nil, err
 	}
@@ -259,3 +406,29 @@ func UpdateApplicationSession(ctx context.Context, store fornaxstore.ApiStorageI
 
 	return out, nil
 }
+
+// RegisteredFornaxStorageFunc is provided to k8s api server to get storage.Interface of a resource which does not need special decoration,
+// store must be created by its New*Storage func before api server start
+func RegisteredFornaxStorageFunc(
+	storageConfig *storagebackend.ConfigForResource,
+	resourcePrefix string,
+	keyFunc func(obj runtime.Object) (string, error),
+	newFunc func() runtime.Object,
+	newListFunc func() runtime.Object,
+	getAttrsFunc apistorage.AttrFunc,
+	triggerFuncs apistorage.IndexerFuncs,
+	indexers *cache.Indexers) (apistorage.Interface, factory.DestroyFunc, error) {
+
+	_FornaxInMemoryStoresMutex.Lock()
+	key := storageConfig.GroupResource.String()
+	defer _FornaxInMemoryStoresMutex.Unlock()
+	if ms, f := _InMemoryResourceStores[key]; f {
+		ms.CompleteWithFunctions(keyFunc, newFunc, newListFunc, getAttrsFunc, triggerFuncs, indexers)
+		return ms, func() { ms.Stop() }, nil
+	}
+	if es, f := _EtcdResourceStores[key]; f {
+		es.SetKeyFunc(keyFunc)
+		return es, func() { es.Stop() }, nil
+	}
+	return nil, nil, fmt.Errorf("Can not find a regisgered store for %s", key)
+}
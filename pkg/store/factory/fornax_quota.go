@@ -0,0 +1,142 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/fornaxerrors"
+	fornaxstore "centaurusinfra.io/fornax-serverless/pkg/store"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apistorage "k8s.io/apiserver/pkg/storage"
+)
+
+// quotaLimitFunc return limit of a resource in quota spec, nil means not limited by this quota
+type quotaLimitFunc func(spec *fornaxv1.FornaxQuotaSpec) *int64
+
+func applicationSessionLimit(spec *fornaxv1.FornaxQuotaSpec) *int64 {
+	return spec.MaxApplicationSessions
+}
+
+func applicationInstanceLimit(spec *fornaxv1.FornaxQuotaSpec) *int64 {
+	return spec.MaxApplicationInstances
+}
+
+func NewFornaxQuotaStorage(ctx context.Context) fornaxstore.ApiStorageInterface {
+	return newFornaxStorage(ctx, fornaxv1.FornaxQuotaGrv.GroupResource(), fornaxv1.FornaxQuotaGrvKey,
+		func() runtime.Object { return &fornaxv1.FornaxQuota{} },
+		func() runtime.Object { return &fornaxv1.FornaxQuotaList{} })
+}
+
+func getFornaxQuotaStorage() fornaxstore.ApiStorageInterface {
+	_FornaxInMemoryStoresMutex.RLock()
+	defer _FornaxInMemoryStoresMutex.RUnlock()
+	key := fornaxv1.FornaxQuotaGrv.GroupResource().String()
+	if si, found := _InMemoryResourceStores[key]; found {
+		return si
+	}
+	if si, found := _EtcdResourceStores[key]; found {
+		return si
+	}
+	return nil
+}
+
+// namespaceQuotaLimit return smallest limit of all quotas in namespace and name of that quota, math.MaxInt64 if not limited
+func namespaceQuotaLimit(ctx context.Context, namespace string, limitFunc quotaLimitFunc) (int64, string, error) {
+	limit, quotaName := int64(math.MaxInt64), ""
+	quotaStore := getFornaxQuotaStorage()
+	if quotaStore == nil {
+		return limit, quotaName, nil
+	}
+	quotas := &fornaxv1.FornaxQuotaList{}
+	key := fmt.Sprintf("%s/%s", fornaxv1.FornaxQuotaGrvKey, namespace)
+	if err := quotaStore.GetList(ctx, key, apistorage.ListOptions{Predicate: apistorage.Everything, Recursive: true}, quotas); err != nil {
+		return limit, quotaName, err
+	}
+	for _, v := range quotas.Items {
+		if l := limitFunc(&v.Spec); l != nil && *l < limit {
+			limit, quotaName = *l, v.Name
+		}
+	}
+	return limit, quotaName, nil
+}
+
+// RemainingApplicationInstanceQuota return how many more application instances can be created in namespace
+// which already has used instances, math.MaxInt64 if namespace does not have instance quota
+func RemainingApplicationInstanceQuota(ctx context.Context, namespace string, used int64) (int64, error) {
+	limit, _, err := namespaceQuotaLimit(ctx, namespace, applicationInstanceLimit)
+	if err != nil || limit == math.MaxInt64 {
+		return limit, err
+	}
+	if used >= limit {
+		return 0, nil
+	}
+	return limit - used, nil
+}
+
+// fornaxQuotaStore enforce namespace quota of a resource on Create, objects of resource are counted in store,
+// creations are serialized, so, concurrent creations can not exceed quota together
+type fornaxQuotaStore struct {
+	apistorage.Interface
+	mu            sync.Mutex
+	groupResource schema.GroupResource
+	grvKey        string
+	limitFunc     quotaLimitFunc
+}
+
+func newFornaxQuotaStore(s apistorage.Interface, groupResource schema.GroupResource, grvKey string, limitFunc quotaLimitFunc) *fornaxQuotaStore {
+	return &fornaxQuotaStore{
+		Interface:     s,
+		mu:            sync.Mutex{},
+		groupResource: groupResource,
+		grvKey:        grvKey,
+		limitFunc:     limitFunc,
+	}
+}
+
+func (s *fornaxQuotaStore) Create(ctx context.Context, key string, obj, out runtime.Object, ttl uint64) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	namespace := accessor.GetNamespace()
+	limit, quotaName, err := namespaceQuotaLimit(ctx, namespace, s.limitFunc)
+	if err != nil {
+		return err
+	}
+	if limit == math.MaxInt64 {
+		return s.Interface.Create(ctx, key, obj, out, ttl)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	used, err := s.Interface.Count(fmt.Sprintf("%s/%s", s.grvKey, namespace))
+	if err != nil {
+		return err
+	}
+	if used >= limit {
+		return fornaxerrors.NewAPIError(fornaxerrors.QuotaExceeded, s.groupResource, accessor.GetName(), fmt.Errorf("exceeded quota: %s, requested: 1, used: %d, limited: %d", quotaName, used, limit))
+	}
+	return s.Interface.Create(ctx, key, obj, out, ttl)
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package factory
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	"k8s.io/apiserver/pkg/storage/names"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// a object name generated from generateName is regenerated at most this times when it collide with a existing object
+	DefaultGenerateNameRetries = 8
+
+	// names.SimpleNameGenerator append 5 random chars to base which is truncated to 58 chars
+	generatedNameSuffixLength  = 5
+	maxGeneratedNameBaseLength = 63 - generatedNameSuffixLength
+	generatedNameAlphanums     = "bcdfghjklmnpqrstvwxz2456789"
+)
+
+// generateNameStore retry creation of a object whose name is generated from its generateName with a new name when generated name is taken,
+// api server generate name once and return a conflict to client, so, clients like matchmakers do not need to invent unique names
+type generateNameStore struct {
+	apistorage.Interface
+}
+
+func newGenerateNameStore(s apistorage.Interface) *generateNameStore {
+	return &generateNameStore{Interface: s}
+}
+
+func (s *generateNameStore) Create(ctx context.Context, key string, obj, out runtime.Object, ttl uint64) error {
+	return createWithGenerateName(ctx, s.Interface, key, obj, out, ttl)
+}
+
+// isGeneratedName check if name is generated from generateName by api server, a name set by client is never changed,
+// api server ignore generateName if name is set, a client name looks like a generated one only if client set both and guessed a random suffix
+func isGeneratedName(name, generateName string) bool {
+	if len(generateName) == 0 {
+		return false
+	}
+	if len(generateName) > maxGeneratedNameBaseLength {
+		generateName = generateName[:maxGeneratedNameBaseLength]
+	}
+	suffix := strings.TrimPrefix(name, generateName)
+	if len(suffix) != generatedNameSuffixLength || len(suffix)+len(generateName) != len(name) {
+		return false
+	}
+	for _, c := range suffix {
+		if !strings.ContainsRune(generatedNameAlphanums, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// createWithGenerateName create object, if object does not have a name, a name is generated from its generateName,
+// if a generated name collide with a existing object, a new name is generated and creation is retried,
+// key of a object without name is key of its namespace
+func createWithGenerateName(ctx context.Context, s apistorage.Interface, key string, obj, out runtime.Object, ttl uint64) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	generateName := accessor.GetGenerateName()
+	if len(accessor.GetName()) == 0 && len(generateName) > 0 {
+		accessor.SetName(names.SimpleNameGenerator.GenerateName(generateName))
+		key = path.Join(key, accessor.GetName())
+	} else if !isGeneratedName(accessor.GetName(), generateName) {
+		return s.Create(ctx, key, obj, out, ttl)
+	}
+
+	for i := 0; ; i++ {
+		err = s.Create(ctx, key, obj, out, ttl)
+		if err == nil || !apistorage.IsExist(err) || i >= DefaultGenerateNameRetries {
+			return err
+		}
+		name := names.SimpleNameGenerator.GenerateName(generateName)
+		klog.InfoS("Generated name is taken, retry with a new name", "key", key, "name", name)
+		accessor.SetName(name)
+		key = path.Join(path.Dir(key), name)
+	}
+}
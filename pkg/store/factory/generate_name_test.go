@@ -0,0 +1,100 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package factory
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	fornaxstore "centaurusinfra.io/fornax-serverless/pkg/store"
+	"centaurusinfra.io/fornax-serverless/pkg/store/inmemory"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	apistorage "k8s.io/apiserver/pkg/storage"
+)
+
+const testSessionKeyPrefix = "/test/applicationsessions"
+
+func newTestSessionStore(t *testing.T) *inmemory.MemoryStore {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	gr := fornaxv1.ApplicationSessionGrv.GroupResource()
+	return inmemory.NewMemoryStore(ctx, gr, testSessionKeyPrefix,
+		func() runtime.Object { return &fornaxv1.ApplicationSession{} },
+		func() runtime.Object { return &fornaxv1.ApplicationSessionList{} },
+		fornaxstore.DefaultResourceStorageConfiguration(gr))
+}
+
+func TestCreateWithGenerateNameRetryCollision(t *testing.T) {
+	ms := newTestSessionStore(t)
+	s := newGenerateNameStore(ms)
+	ctx := context.Background()
+	taken := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "match-bcdfg"}}
+	if err := ms.Create(ctx, testSessionKeyPrefix+"/ns/match-bcdfg", taken, &fornaxv1.ApplicationSession{}, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// api server generated a name which is taken
+	session := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "match-bcdfg", GenerateName: "match-"}}
+	out := &fornaxv1.ApplicationSession{}
+	if err := s.Create(ctx, testSessionKeyPrefix+"/ns/match-bcdfg", session, out, 0); err != nil {
+		t.Fatal(err)
+	}
+	if out.Name == "match-bcdfg" || !strings.HasPrefix(out.Name, "match-") {
+		t.Errorf("expected a new generated name, got %s", out.Name)
+	}
+	if err := ms.Get(ctx, testSessionKeyPrefix+"/ns/"+out.Name, apistorage.GetOptions{}, &fornaxv1.ApplicationSession{}); err != nil {
+		t.Errorf("expected session is stored using new name, %v", err)
+	}
+
+	// a name set by client is not changed
+	session = &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "match-bcdfg", GenerateName: "other-"}}
+	if err := s.Create(ctx, testSessionKeyPrefix+"/ns/match-bcdfg", session, out, 0); !apistorage.IsExist(err) {
+		t.Errorf("expected key exists error of a name set by client, got %v", err)
+	}
+}
+
+func TestCreateWithGenerateNameWithoutName(t *testing.T) {
+	ms := newTestSessionStore(t)
+	session := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", GenerateName: "match-"}}
+	out, err := CreateApplicationSession(context.Background(), ms, session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isGeneratedName(out.Name, "match-") {
+		t.Errorf("expected a name generated from match-, got %s", out.Name)
+	}
+}
+
+func TestIsGeneratedName(t *testing.T) {
+	long := strings.Repeat("a", 70)
+	for _, c := range []struct {
+		name, generateName string
+		generated          bool
+	}{
+		{"match-x2b4c", "match-", true},
+		{"match-xabcd", "match-", false},
+		{"match-x2b4", "match-", false},
+		{"match", "", false},
+		{long[:58] + "bcdfg", long, true},
+	} {
+		if isGeneratedName(c.name, c.generateName) != c.generated {
+			t.Errorf("expected %s generated from %s is %v", c.name, c.generateName, c.generated)
+		}
+	}
+}
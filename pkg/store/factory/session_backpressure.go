@@ -0,0 +1,63 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factory
+
+import (
+	"context"
+	"sync"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	apistorage "k8s.io/apiserver/pkg/storage"
+)
+
+// SessionBackpressureFunc return backpressure hint of application of a new session
+type SessionBackpressureFunc func(session *fornaxv1.ApplicationSession) *fornaxv1.SessionBackpressure
+
+var (
+	_SessionBackpressureMutex = &sync.RWMutex{}
+	_SessionBackpressureFunc  SessionBackpressureFunc
+)
+
+// SetSessionBackpressureFunc register a func to fill backpressure hint of sessions created from api server
+func SetSessionBackpressureFunc(f SessionBackpressureFunc) {
+	_SessionBackpressureMutex.Lock()
+	defer _SessionBackpressureMutex.Unlock()
+	_SessionBackpressureFunc = f
+}
+
+func getSessionBackpressureFunc() SessionBackpressureFunc {
+	_SessionBackpressureMutex.RLock()
+	defer _SessionBackpressureMutex.RUnlock()
+	return _SessionBackpressureFunc
+}
+
+// sessionBackpressureStore is provided to api server as session storage, it fill backpressure hint in new session,
+// so, create response tell client if application is saturated
+type sessionBackpressureStore struct {
+	apistorage.Interface
+}
+
+func (s *sessionBackpressureStore) Create(ctx context.Context, key string, obj, out runtime.Object, ttl uint64) error {
+	if f := getSessionBackpressureFunc(); f != nil {
+		if session, ok := obj.(*fornaxv1.ApplicationSession); ok {
+			session.Status.Backpressure = f(session)
+		}
+	}
+	return s.Interface.Create(ctx, key, obj, out, ttl)
+}
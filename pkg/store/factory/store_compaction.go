@@ -0,0 +1,136 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	goruntime "runtime"
+	"runtime/debug"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"centaurusinfra.io/fornax-serverless/pkg/store/inmemory"
+)
+
+const (
+	StoreCompactionPath = "/debug/fornaxcore/store/compact"
+
+	DefaultMemoryPressureCheckInterval = 60 * time.Second
+)
+
+// compactUnderMemoryPressure read heap stats once, and force compaction of memory stores whose memory threshold is exceeded,
+// memory of removed slots is given back to os only once after all stores are compacted, return compacted resources
+func compactUnderMemoryPressure() map[string]int {
+	_FornaxInMemoryStoresMutex.RLock()
+	thresholds := map[string]int{}
+	for resource, ms := range _InMemoryResourceStores {
+		if threshold := _FornaxStorageConfiguration.ForGroupResource(ms.GroupResource()).CompactionMemoryThresholdMB; threshold > 0 {
+			thresholds[resource] = threshold
+		}
+	}
+	_FornaxInMemoryStoresMutex.RUnlock()
+	if len(thresholds) == 0 {
+		return nil
+	}
+
+	memStats := goruntime.MemStats{}
+	goruntime.ReadMemStats(&memStats)
+	compacted := map[string]int{}
+	for resource, threshold := range thresholds {
+		if memStats.HeapAlloc <= uint64(threshold)*1024*1024 {
+			continue
+		}
+		_FornaxInMemoryStoresMutex.RLock()
+		ms, found := _InMemoryResourceStores[resource]
+		_FornaxInMemoryStoresMutex.RUnlock()
+		if found {
+			klog.InfoS("Heap is above compaction memory threshold, force compaction", "resource", resource, "heap", memStats.HeapAlloc, "threshold-mb", threshold)
+			compacted[resource] = ms.Compact(inmemory.CompactionTriggerMemoryPressure)
+		}
+	}
+	if len(compacted) > 0 {
+		// give memory of removed slots back to os
+		debug.FreeOSMemory()
+	}
+	return compacted
+}
+
+// RunMemoryPressureCompaction check heap against compaction memory threshold of memory stores periodically until ctx is done
+func RunMemoryPressureCompaction(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			compactUnderMemoryPressure()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// StoreCompactionHandler compact memory stores on demand and return removed slots of each resource,
+// e.g. kubectl create --raw "/debug/fornaxcore/store/compact?resource=applicationsessions.core.fornax-serverless.centaurusinfra.io" -f /dev/null,
+// all memory stores are compacted if resource is not set
+type StoreCompactionHandler struct{}
+
+func NewStoreCompactionHandler() *StoreCompactionHandler {
+	return &StoreCompactionHandler{}
+}
+
+func (h *StoreCompactionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	resource := r.URL.Query().Get("resource")
+
+	stores := map[string]inmemory.FornaxMemoryStore{}
+	_FornaxInMemoryStoresMutex.RLock()
+	if len(resource) > 0 {
+		if ms, found := _InMemoryResourceStores[resource]; found {
+			stores[resource] = ms
+		}
+	} else {
+		for k, v := range _InMemoryResourceStores {
+			stores[k] = v
+		}
+	}
+	_FornaxInMemoryStoresMutex.RUnlock()
+	if len(resource) > 0 && len(stores) == 0 {
+		http.Error(w, fmt.Sprintf("resource %s is not stored in memory", resource), http.StatusNotFound)
+		return
+	}
+
+	removed := map[string]int{}
+	for k, v := range stores {
+		removed[k] = v.Compact(inmemory.CompactionTriggerApi)
+	}
+	klog.InfoS("Compacted memory stores on demand", "removed", removed)
+
+	data, err := json.MarshalIndent(removed, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	fornaxstore "centaurusinfra.io/fornax-serverless/pkg/store"
+	"centaurusinfra.io/fornax-serverless/pkg/store/inmemory"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// registerTestCompactingStore register a memory store holding deleted slots as session store, store configuration use memory threshold
+func registerTestCompactingStore(t *testing.T, thresholdMB int) (*inmemory.MemoryStore, string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	gr := fornaxv1.ApplicationSessionGrv.GroupResource()
+	config := fornaxstore.DefaultResourceStorageConfiguration(gr)
+	config.RetentionSlots = 1
+	config.CompactionMemoryThresholdMB = thresholdMB
+	ms := inmemory.NewMemoryStore(ctx, gr, testSessionKeyPrefix,
+		func() runtime.Object { return &fornaxv1.ApplicationSession{} },
+		func() runtime.Object { return &fornaxv1.ApplicationSessionList{} },
+		config)
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("%s/ns/session-%d", testSessionKeyPrefix, i)
+		session := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: fmt.Sprintf("session-%d", i)}}
+		if err := ms.Create(ctx, key, session, &fornaxv1.ApplicationSession{}, 0); err != nil {
+			t.Fatal(err)
+		}
+		if err := ms.Delete(ctx, key, &fornaxv1.ApplicationSession{}, nil, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_FornaxInMemoryStoresMutex.Lock()
+	oldStores, oldConfig := _InMemoryResourceStores, _FornaxStorageConfiguration
+	_InMemoryResourceStores = map[string]inmemory.FornaxMemoryStore{gr.String(): ms}
+	_FornaxStorageConfiguration = &fornaxstore.FornaxStorageConfiguration{Resources: []fornaxstore.ResourceStorageConfiguration{config}}
+	_FornaxInMemoryStoresMutex.Unlock()
+	t.Cleanup(func() {
+		_FornaxInMemoryStoresMutex.Lock()
+		_InMemoryResourceStores, _FornaxStorageConfiguration = oldStores, oldConfig
+		_FornaxInMemoryStoresMutex.Unlock()
+	})
+	return ms, gr.String()
+}
+
+func TestCompactUnderMemoryPressure(t *testing.T) {
+	_, resource := registerTestCompactingStore(t, 1)
+	// heap of test process is always above 1MB
+	compacted := compactUnderMemoryPressure()
+	if compacted[resource] == 0 {
+		t.Errorf("expected deleted slots of %s removed under memory pressure, got %v", resource, compacted)
+	}
+}
+
+func TestNoCompactionWithoutMemoryThreshold(t *testing.T) {
+	registerTestCompactingStore(t, 0)
+	if compacted := compactUnderMemoryPressure(); len(compacted) != 0 {
+		t.Errorf("expected no compaction without memory threshold, got %v", compacted)
+	}
+}
+
+func TestStoreCompactionHandler(t *testing.T) {
+	_, resource := registerTestCompactingStore(t, 0)
+	server := httptest.NewServer(NewStoreCompactionHandler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "?resource=" + resource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected GET is not allowed, got %d", resp.StatusCode)
+	}
+
+	resp, err = http.Post(server.URL+"?resource=unknown", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected unknown resource not found, got %d", resp.StatusCode)
+	}
+
+	resp, err = http.Post(server.URL+"?resource="+resource, "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	removed := map[string]int{}
+	if err := json.NewDecoder(resp.Body).Decode(&removed); err != nil {
+		t.Fatal(err)
+	}
+	if removed[resource] == 0 {
+		t.Errorf("expected deleted slots of %s removed on demand, got %v", resource, removed)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factory
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apistorage "k8s.io/apiserver/pkg/storage"
+
+	"centaurusinfra.io/fornax-serverless/pkg/store/inmemory"
+)
+
+const (
+	StoreHistoryPath = "/debug/fornaxcore/store/history"
+)
+
+// StoreHistoryHandler serve retained revisions of a object in memory store,
+// e.g. ?resource=applicationsessions.core.fornax-serverless.centaurusinfra.io&name=namespace/name, add &rev=123 to get object as of a revision
+type StoreHistoryHandler struct{}
+
+func NewStoreHistoryHandler() *StoreHistoryHandler {
+	return &StoreHistoryHandler{}
+}
+
+func (h *StoreHistoryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	resource := r.URL.Query().Get("resource")
+	name := r.URL.Query().Get("name")
+	if len(resource) == 0 || len(name) == 0 {
+		http.Error(w, "resource and name query parameters are required, e.g. ?resource=applications.core.fornax-serverless.centaurusinfra.io&name=namespace/name", http.StatusBadRequest)
+		return
+	}
+
+	_FornaxInMemoryStoresMutex.RLock()
+	ms, found := _InMemoryResourceStores[resource]
+	kind := _InMemoryResourceKinds[resource]
+	_FornaxInMemoryStoresMutex.RUnlock()
+	if !found {
+		http.Error(w, fmt.Sprintf("resource %s is not stored in memory", resource), http.StatusNotFound)
+		return
+	}
+	key := fmt.Sprintf("%s/%s", kind.keyPrefix, name)
+
+	var result interface{}
+	if revText := r.URL.Query().Get("rev"); len(revText) > 0 {
+		rev, err := strconv.ParseUint(revText, 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid revision %s: %v", revText, err), http.StatusBadRequest)
+			return
+		}
+		obj := kind.newFunc()
+		if err := ms.GetAtRevision(r.Context(), key, rev, obj); err != nil {
+			writeHistoryError(w, err)
+			return
+		}
+		result = obj
+	} else {
+		revisions, err := ms.History(key)
+		if err != nil {
+			writeHistoryError(w, err)
+			return
+		}
+		result = revisions
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func writeHistoryError(w http.ResponseWriter, err error) {
+	switch {
+	case err == inmemory.HistoryNotEnabledError:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case apistorage.IsNotFound(err):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case apierrors.IsResourceExpired(err):
+		http.Error(w, err.Error(), http.StatusGone)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
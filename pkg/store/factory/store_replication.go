@@ -0,0 +1,357 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+
+	"centaurusinfra.io/fornax-serverless/pkg/store/inmemory"
+	"centaurusinfra.io/fornax-serverless/pkg/util"
+)
+
+const (
+	StoreReplicationPath = "/fornaxcore/replication/watch"
+
+	// time to wait before a follower reconnect leader after replication stream broke
+	DefaultStoreReplicationRetryPeriod = 1 * time.Second
+
+	// replication stream event sent after all objects of snapshot, follower remove objects which are not in snapshot
+	replicationEventSynced = "SYNCED"
+)
+
+var (
+	storeReplicationEvents = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_store_replication",
+			Name:           "events_total",
+			Help:           "Number of leader store events applied by a follower replica",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource", "type"},
+	)
+	storeReplicationResyncs = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_store_replication",
+			Name:           "resyncs_total",
+			Help:           "Number of times a follower replica copied a full snapshot of a leader store",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(storeReplicationEvents, storeReplicationResyncs)
+}
+
+// replicationEvent is a json line of replication stream, resource version is revision of leader store
+type replicationEvent struct {
+	Type            string          `json:"type"`
+	ResourceVersion string          `json:"resourceVersion,omitempty"`
+	Object          json.RawMessage `json:"object,omitempty"`
+}
+
+// StoreReplicationHandler stream changes of a memory store to follower replicas, ?resource=<group resource>&resourceVersion=<rev>,
+// objects of store are sent as ADDED events followed by a SYNCED event if resource version is empty, then watch events after it,
+// 410 is returned if resource version is compacted, follower should copy snapshot again,
+// allow return false when this replica is not leader, its stores are not the source of truth
+type StoreReplicationHandler struct {
+	allow func() bool
+}
+
+func NewStoreReplicationHandler(allow func() bool) *StoreReplicationHandler {
+	return &StoreReplicationHandler{allow: allow}
+}
+
+func (h *StoreReplicationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.allow != nil && !h.allow() {
+		http.Error(w, "fornaxcore replica is not leader", http.StatusServiceUnavailable)
+		return
+	}
+	resource := r.URL.Query().Get("resource")
+	_FornaxInMemoryStoresMutex.RLock()
+	ms, found := _InMemoryResourceStores[resource]
+	kind := _InMemoryResourceKinds[resource]
+	_FornaxInMemoryStoresMutex.RUnlock()
+	if !found {
+		http.Error(w, fmt.Sprintf("resource %s is not stored in memory", resource), http.StatusNotFound)
+		return
+	}
+	if err := serveStoreReplication(r.Context(), w, ms, kind, r.URL.Query().Get("resourceVersion")); err != nil {
+		klog.ErrorS(err, "Store replication stream stopped", "resource", resource, "follower", r.RemoteAddr)
+	}
+}
+
+func serveStoreReplication(ctx context.Context, w http.ResponseWriter, ms inmemory.FornaxMemoryStore, kind inMemoryResourceKind, rv string) error {
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	send := func(eventType string, rv string, obj runtime.Object) error {
+		event := &replicationEvent{Type: eventType, ResourceVersion: rv}
+		if obj != nil {
+			data, err := json.Marshal(obj)
+			if err != nil {
+				return err
+			}
+			event.Object = data
+		}
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	}
+
+	var snapshot []runtime.Object
+	if len(rv) == 0 || rv == "0" {
+		listObj := kind.newListFunc()
+		if err := ms.SnapshotList(ctx, kind.keyPrefix, 0, listObj); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return err
+		}
+		items, err := meta.ExtractList(listObj)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return err
+		}
+		listMeta, err := meta.ListAccessor(listObj)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return err
+		}
+		snapshot, rv = items, listMeta.GetResourceVersion()
+	}
+
+	predicate := apistorage.Everything
+	predicate.AllowWatchBookmarks = true
+	wi, err := ms.WatchWithOldObj(ctx, kind.keyPrefix, apistorage.ListOptions{ResourceVersion: rv, Predicate: predicate, Recursive: true})
+	if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+		http.Error(w, err.Error(), http.StatusGone)
+		return err
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return err
+	}
+	defer wi.Stop()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if snapshot != nil {
+		for _, obj := range snapshot {
+			if err := send(string(watch.Added), "", obj); err != nil {
+				return err
+			}
+		}
+		if err := send(replicationEventSynced, rv, nil); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-wi.ResultChanWithPrevobj():
+			if !ok {
+				return nil
+			}
+			if event.Type == watch.Error {
+				return apierrors.FromObject(event.Object)
+			}
+			eventRv := ""
+			if accessor, err := meta.Accessor(event.Object); err == nil {
+				eventRv = accessor.GetResourceVersion()
+			}
+			if event.Type == watch.Bookmark {
+				if err := send(string(watch.Bookmark), eventRv, nil); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := send(string(event.Type), eventRv, event.Object); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ReplicatedResources return group resources of memory stores which are replicated to followers
+func ReplicatedResources() []string {
+	_FornaxInMemoryStoresMutex.RLock()
+	defer _FornaxInMemoryStoresMutex.RUnlock()
+	resources := []string{}
+	for k := range _InMemoryResourceStores {
+		resources = append(resources, k)
+	}
+	sort.Strings(resources)
+	return resources
+}
+
+// ReplicateMemoryStores apply changes of leader memory stores to memory stores of this replica until ctx is done,
+// leaderURL is base url of leader replication server, e.g. https://10.0.0.1:18002, client must trust leader and present a replica certificate,
+// it return after all stores stopped replicating
+func ReplicateMemoryStores(ctx context.Context, leaderURL string, client *http.Client) {
+	wg := sync.WaitGroup{}
+	for _, resource := range ReplicatedResources() {
+		_FornaxInMemoryStoresMutex.RLock()
+		replica := &storeReplica{
+			resource: resource,
+			store:    _InMemoryResourceStores[resource],
+			kind:     _InMemoryResourceKinds[resource],
+		}
+		_FornaxInMemoryStoresMutex.RUnlock()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			replica.run(ctx, client, leaderURL)
+		}()
+	}
+	klog.InfoS("Replicating memory stores from leader", "leader", leaderURL)
+	wg.Wait()
+	klog.InfoS("Stopped replicating memory stores from leader", "leader", leaderURL)
+}
+
+// storeReplica is a follower copy of a leader memory store, rv is last leader revision applied
+type storeReplica struct {
+	resource string
+	store    inmemory.FornaxMemoryStore
+	kind     inMemoryResourceKind
+	rv       string
+}
+
+func (r *storeReplica) run(ctx context.Context, client *http.Client, leaderURL string) {
+	for {
+		err := r.replicate(ctx, client, leaderURL)
+		if ctx.Err() != nil {
+			return
+		}
+		klog.ErrorS(err, "Store replication from leader broke, retry", "resource", r.resource, "resourceVersion", r.rv)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(DefaultStoreReplicationRetryPeriod):
+		}
+	}
+}
+
+func (r *storeReplica) replicate(ctx context.Context, client *http.Client, leaderURL string) error {
+	query := url.Values{"resource": []string{r.resource}, "resourceVersion": []string{r.rv}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s%s?%s", leaderURL, StoreReplicationPath, query.Encode()), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusGone {
+		r.rv = ""
+		return fmt.Errorf("leader revision of %s is compacted, copy snapshot again", r.resource)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("leader replied %s: %s", resp.Status, string(body))
+	}
+
+	ctx = inmemory.WithReplicatedWrite(ctx)
+	// keys in snapshot, nil when watching from a revision
+	var snapshot map[string]bool
+	if len(r.rv) == 0 {
+		snapshot = map[string]bool{}
+		storeReplicationResyncs.WithLabelValues(r.resource).Inc()
+	}
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		event := &replicationEvent{}
+		if err := decoder.Decode(event); err != nil {
+			return err
+		}
+		switch event.Type {
+		case string(watch.Added), string(watch.Modified), string(watch.Deleted):
+			obj := r.kind.newFunc()
+			if err := json.Unmarshal(event.Object, obj); err != nil {
+				return err
+			}
+			key := fmt.Sprintf("%s/%s", r.kind.keyPrefix, util.Name(obj))
+			if event.Type == string(watch.Deleted) {
+				err = r.store.Delete(ctx, key, r.kind.newFunc(), nil, nil, nil)
+				if apistorage.IsNotFound(err) {
+					err = nil
+				}
+			} else {
+				if snapshot != nil {
+					snapshot[key] = true
+				}
+				err = r.store.CreateOrReplace(ctx, key, obj, r.kind.newFunc())
+			}
+			if err != nil {
+				return fmt.Errorf("failed to apply replicated %s of %s: %w", event.Type, key, err)
+			}
+			storeReplicationEvents.WithLabelValues(r.resource, event.Type).Inc()
+		case replicationEventSynced:
+			if err := r.prune(ctx, snapshot); err != nil {
+				return err
+			}
+			snapshot = nil
+		}
+		// objects of snapshot are older than snapshot revision, it's only recorded when snapshot is synced
+		if snapshot == nil && len(event.ResourceVersion) > 0 {
+			r.rv = event.ResourceVersion
+		}
+	}
+}
+
+// prune delete objects of this replica which are not in leader snapshot, they were deleted while this replica was not replicating
+func (r *storeReplica) prune(ctx context.Context, snapshot map[string]bool) error {
+	listObj := r.kind.newListFunc()
+	if err := r.store.SnapshotList(ctx, r.kind.keyPrefix, 0, listObj); err != nil {
+		return err
+	}
+	items, err := meta.ExtractList(listObj)
+	if err != nil {
+		return err
+	}
+	for _, obj := range items {
+		key := fmt.Sprintf("%s/%s", r.kind.keyPrefix, util.Name(obj))
+		if snapshot[key] {
+			continue
+		}
+		if err := r.store.Delete(ctx, key, r.kind.newFunc(), nil, nil, nil); err != nil && !apistorage.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	fornaxstore "centaurusinfra.io/fornax-serverless/pkg/store"
+	"centaurusinfra.io/fornax-serverless/pkg/store/inmemory"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+var testSessionKind = inMemoryResourceKind{
+	keyPrefix:   testSessionKeyPrefix,
+	newFunc:     func() runtime.Object { return &fornaxv1.ApplicationSession{} },
+	newListFunc: func() runtime.Object { return &fornaxv1.ApplicationSessionList{} },
+}
+
+func createTestReplicatedSession(t *testing.T, s apistorage.Interface, name string) {
+	session := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name}}
+	if err := s.Create(context.Background(), testSessionKeyPrefix+"/ns/"+name, session, &fornaxv1.ApplicationSession{}, 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newTestReplicationStream serve replication events as json lines and close stream
+func newTestReplicationStream(t *testing.T, events ...*replicationEvent) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoder := json.NewEncoder(w)
+		for _, event := range events {
+			encoder.Encode(event)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestReplicationEvent(t *testing.T, eventType, rv, name string) *replicationEvent {
+	event := &replicationEvent{Type: eventType, ResourceVersion: rv}
+	if len(name) > 0 {
+		data, err := json.Marshal(&fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, ResourceVersion: rv}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		event.Object = data
+	}
+	return event
+}
+
+func waitReplicated(t *testing.T, s apistorage.Interface, key string, check func(session *fornaxv1.ApplicationSession, err error) bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		out := &fornaxv1.ApplicationSession{}
+		if check(out, s.Get(context.Background(), key, apistorage.GetOptions{}, out)) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s replicated", key)
+}
+
+func TestStoreReplication(t *testing.T) {
+	leader, follower := newTestSessionStore(t), newTestSessionStore(t)
+	kind := inMemoryResourceKind{
+		keyPrefix:   testSessionKeyPrefix,
+		newFunc:     func() runtime.Object { return &fornaxv1.ApplicationSession{} },
+		newListFunc: func() runtime.Object { return &fornaxv1.ApplicationSessionList{} },
+	}
+	ctx := context.Background()
+	create := func(s apistorage.Interface, name string) {
+		session := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name}}
+		if err := s.Create(ctx, testSessionKeyPrefix+"/ns/"+name, session, &fornaxv1.ApplicationSession{}, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	create(leader, "s1")
+	// s0 was deleted on leader while follower was not replicating
+	create(follower, "s0")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveStoreReplication(r.Context(), w, leader, kind, r.URL.Query().Get("resourceVersion"))
+	}))
+	defer server.Close()
+	replicaCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	replica := &storeReplica{resource: "sessions", store: follower, kind: kind}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		replica.run(replicaCtx, server.Client(), server.URL)
+	}()
+
+	waitReplicated(t, follower, testSessionKeyPrefix+"/ns/s1", func(session *fornaxv1.ApplicationSession, err error) bool { return err == nil })
+	waitReplicated(t, follower, testSessionKeyPrefix+"/ns/s0", func(session *fornaxv1.ApplicationSession, err error) bool { return apistorage.IsNotFound(err) })
+
+	// changes after snapshot are replicated from watch
+	create(leader, "s2")
+	updated := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "s1"}, Status: fornaxv1.ApplicationSessionStatus{SessionStatus: fornaxv1.SessionStatusAvailable}}
+	if err := leader.CreateOrReplace(ctx, testSessionKeyPrefix+"/ns/s1", updated, &fornaxv1.ApplicationSession{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := leader.Delete(ctx, testSessionKeyPrefix+"/ns/s2", &fornaxv1.ApplicationSession{}, nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	waitReplicated(t, follower, testSessionKeyPrefix+"/ns/s1", func(session *fornaxv1.ApplicationSession, err error) bool {
+		return err == nil && session.Status.SessionStatus == fornaxv1.SessionStatusAvailable
+	})
+	waitReplicated(t, follower, testSessionKeyPrefix+"/ns/s2", func(session *fornaxv1.ApplicationSession, err error) bool { return apistorage.IsNotFound(err) })
+	// revision is written by replication goroutine, read it after replication stopped
+	cancel()
+	<-done
+	if len(replica.rv) == 0 {
+		t.Errorf("expected replica recorded leader revision")
+	}
+}
+
+func TestStoreReplicationPruneOnSynced(t *testing.T) {
+	follower := newTestSessionStore(t)
+	ctx := context.Background()
+	createTestReplicatedSession(t, follower, "s0")
+	replica := &storeReplica{resource: "sessions", store: follower, kind: testSessionKind}
+
+	// stream broke before snapshot is synced, objects not in partial snapshot are kept and revision is not recorded
+	server := newTestReplicationStream(t, newTestReplicationEvent(t, string(watch.Added), "", "s1"))
+	if err := replica.replicate(ctx, server.Client(), server.URL); err == nil {
+		t.Fatalf("expected replication stopped at end of stream")
+	}
+	if err := follower.Get(ctx, testSessionKeyPrefix+"/ns/s0", apistorage.GetOptions{}, &fornaxv1.ApplicationSession{}); err != nil {
+		t.Errorf("expected s0 kept before snapshot is synced, got %v", err)
+	}
+	if len(replica.rv) != 0 {
+		t.Errorf("expected no revision recorded before snapshot is synced, got %s", replica.rv)
+	}
+
+	// objects not in snapshot are pruned on SYNCED, revision of events after it are recorded
+	server = newTestReplicationStream(t,
+		newTestReplicationEvent(t, string(watch.Added), "", "s1"),
+		newTestReplicationEvent(t, replicationEventSynced, "7", ""),
+		newTestReplicationEvent(t, string(watch.Added), "8", "s2"),
+	)
+	replica.replicate(ctx, server.Client(), server.URL)
+	if err := follower.Get(ctx, testSessionKeyPrefix+"/ns/s0", apistorage.GetOptions{}, &fornaxv1.ApplicationSession{}); !apistorage.IsNotFound(err) {
+		t.Errorf("expected s0 pruned after snapshot is synced, got %v", err)
+	}
+	for _, name := range []string{"s1", "s2"} {
+		if err := follower.Get(ctx, testSessionKeyPrefix+"/ns/"+name, apistorage.GetOptions{}, &fornaxv1.ApplicationSession{}); err != nil {
+			t.Errorf("expected %s replicated, got %v", name, err)
+		}
+	}
+	if replica.rv != "8" {
+		t.Errorf("expected revision of last event recorded, got %s", replica.rv)
+	}
+}
+
+func TestStoreReplicationResyncOnGone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	gr := fornaxv1.ApplicationSessionGrv.GroupResource()
+	config := fornaxstore.DefaultResourceStorageConfiguration(gr)
+	config.RetentionSlots, config.WatchEventCacheSize = 1, 1
+	leader := inmemory.NewMemoryStore(ctx, gr, testSessionKeyPrefix, testSessionKind.newFunc, testSessionKind.newListFunc, config)
+	// revision follower applied before it stopped replicating
+	createTestReplicatedSession(t, leader, "s1")
+	applied := &fornaxv1.ApplicationSession{}
+	if err := leader.Get(ctx, testSessionKeyPrefix+"/ns/s1", apistorage.GetOptions{}, applied); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("deleted-%d", i)
+		createTestReplicatedSession(t, leader, name)
+		if err := leader.Delete(ctx, testSessionKeyPrefix+"/ns/"+name, &fornaxv1.ApplicationSession{}, nil, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if leader.Compact(inmemory.CompactionTriggerApi) == 0 {
+		t.Fatalf("expected leader revisions compacted")
+	}
+	follower := newTestSessionStore(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveStoreReplication(r.Context(), w, leader, testSessionKind, r.URL.Query().Get("resourceVersion"))
+	}))
+	defer server.Close()
+
+	// deleted events after applied revision are compacted on leader
+	replica := &storeReplica{resource: "resync-sessions", store: follower, kind: testSessionKind, rv: applied.ResourceVersion}
+	if err := replica.replicate(ctx, server.Client(), server.URL); err == nil {
+		t.Fatalf("expected leader reject compacted revision")
+	}
+	if len(replica.rv) != 0 {
+		t.Fatalf("expected compacted revision dropped, got %s", replica.rv)
+	}
+
+	resyncs := func() float64 {
+		v, err := testutil.GetCounterMetricValue(storeReplicationResyncs.WithLabelValues("resync-sessions"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	before := resyncs()
+
+	// next replication copy snapshot again
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		replica.replicate(ctx, server.Client(), server.URL)
+	}()
+	waitReplicated(t, follower, testSessionKeyPrefix+"/ns/s1", func(session *fornaxv1.ApplicationSession, err error) bool { return err == nil })
+	cancel()
+	<-done
+	if got := resyncs() - before; got != 1 {
+		t.Errorf("expected one snapshot copy, got %v", got)
+	}
+	if len(replica.rv) == 0 {
+		t.Errorf("expected snapshot revision recorded")
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factory
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"centaurusinfra.io/fornax-serverless/pkg/store/inmemory"
+	"centaurusinfra.io/fornax-serverless/pkg/watchdog"
+)
+
+// revLockProbe report blocked writers on revision lock of a memory store
+type revLockProbe struct {
+	store inmemory.FornaxMemoryStore
+}
+
+var _ watchdog.LockProbe = &revLockProbe{}
+
+func (p *revLockProbe) Name() string {
+	return fmt.Sprintf("%s/revmu", p.store.GroupResource().String())
+}
+
+func (p *revLockProbe) BlockedFor() time.Duration {
+	return p.store.RevLockBlockedFor()
+}
+
+// storeLockProbes return revision lock probes of current memory stores, sorted by resource
+func storeLockProbes() []watchdog.LockProbe {
+	_FornaxInMemoryStoresMutex.RLock()
+	defer _FornaxInMemoryStoresMutex.RUnlock()
+	resources := []string{}
+	for k := range _InMemoryResourceStores {
+		resources = append(resources, k)
+	}
+	sort.Strings(resources)
+	probes := []watchdog.LockProbe{}
+	for _, v := range resources {
+		probes = append(probes, &revLockProbe{store: _InMemoryResourceStores[v]})
+	}
+	return probes
+}
+
+// AddStoreWatchdogChecks add revision lock of memory stores to watchdog, stores are listed on every sample,
+// so, stores created after watchdog started are also checked,
+// shedding lagging watchers of all memory stores is added as a self heal action
+func AddStoreWatchdogChecks(wd *watchdog.Watchdog) {
+	wd.AddLockProbeSource(storeLockProbes)
+	wd.AddHealAction("shed_watchers", func(reason string) string {
+		_FornaxInMemoryStoresMutex.RLock()
+		defer _FornaxInMemoryStoresMutex.RUnlock()
+		shed := 0
+		for _, v := range _InMemoryResourceStores {
+			shed += v.ShedWatchers()
+		}
+		if shed == 0 {
+			return ""
+		}
+		return fmt.Sprintf("stopped %d lagging watchers", shed)
+	})
+}
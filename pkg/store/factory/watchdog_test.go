@@ -0,0 +1,41 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factory
+
+import (
+	"testing"
+
+	"centaurusinfra.io/fornax-serverless/pkg/watchdog"
+)
+
+func TestStoreWatchdogChecksProbeStoresCreatedLater(t *testing.T) {
+	wd := watchdog.New("test", watchdog.DefaultConfiguration())
+	_FornaxInMemoryStoresMutex.RLock()
+	existing := len(_InMemoryResourceStores)
+	_FornaxInMemoryStoresMutex.RUnlock()
+	AddStoreWatchdogChecks(wd)
+	if probes := wd.LockProbes(); len(probes) != existing {
+		t.Fatalf("expected a probe of each existing store, got %d probes of %d stores", len(probes), existing)
+	}
+
+	// store registered after checks are added
+	_, resource := registerTestCompactingStore(t, 1)
+	probes := wd.LockProbes()
+	if len(probes) != 1 || probes[0].Name() != resource+"/revmu" {
+		t.Errorf("expected revision lock of store created later is probed, got %v", probes)
+	}
+}
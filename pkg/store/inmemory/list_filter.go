@@ -0,0 +1,93 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	apistorage "k8s.io/apiserver/pkg/storage"
+)
+
+// listFilter evaluate list predicate in store before object is appended into list,
+// exact match requirements on labels and indexed fields are used to find candidate objects in store index,
+// label only selector is matched on object labels directly, full attrs are built only for objects passed these checks
+type listFilter struct {
+	pred        apistorage.SelectionPredicate
+	everything  bool
+	labelOnly   bool
+	labelValues map[string]string
+	fieldValues map[string]string
+}
+
+func (ms *MemoryStore) newListFilter(pred apistorage.SelectionPredicate) *listFilter {
+	filter := &listFilter{
+		pred:        pred,
+		everything:  pred.Empty(),
+		labelOnly:   pred.Field == nil || pred.Field.Empty(),
+		labelValues: map[string]string{},
+		fieldValues: map[string]string{},
+	}
+	if filter.everything {
+		return filter
+	}
+	if filter.pred.GetAttrs == nil {
+		if ms.getAttrsFunc != nil {
+			filter.pred.GetAttrs = ms.getAttrsFunc
+		} else {
+			filter.pred.GetAttrs = apistorage.DefaultNamespaceScopedAttr
+		}
+	}
+
+	if pred.Label != nil {
+		if requirements, selectable := pred.Label.Requirements(); selectable {
+			for _, r := range requirements {
+				if value, found := pred.Label.RequiresExactMatch(r.Key()); found {
+					filter.labelValues[r.Key()] = value
+				}
+			}
+		}
+	}
+	if pred.Field != nil {
+		for _, r := range pred.Field.Requirements() {
+			if value, found := pred.Field.RequiresExactMatch(r.Field); found {
+				filter.fieldValues[r.Field] = value
+			}
+		}
+	}
+	return filter
+}
+
+// indexed return true if filter has exact match requirements which could be served by store index
+func (f *listFilter) indexed() bool {
+	return !f.everything && len(f.labelValues)+len(f.fieldValues) > 0
+}
+
+func (f *listFilter) matches(obj runtime.Object) bool {
+	if f.everything {
+		return true
+	}
+	if f.labelOnly {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return false
+		}
+		return f.pred.Label.Matches(labels.Set(accessor.GetLabels()))
+	}
+	matched, err := f.pred.Matches(obj)
+	return err == nil && matched
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var (
+	watcherQueueDepth = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      "fornax_memory_store",
+			Name:           "watcher_queue_depth",
+			Help:           "Number of events queued for watchers of a resource and not consumed yet",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource"},
+	)
+	watcherDroppedEvents = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_memory_store",
+			Name:           "watcher_dropped_events_total",
+			Help:           "Number of events dropped because watcher queue is full",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource"},
+	)
+	slowWatcherClosed = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_memory_store",
+			Name:           "slow_watchers_closed_total",
+			Help:           "Number of watchers closed because watcher queue is full",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource"},
+	)
+	objListSlots = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      "fornax_memory_store",
+			Name:           "obj_list_slots",
+			Help:           "Number of allocated slots in revisioned object list of a resource",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource"},
+	)
+	objListUsedSlots = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      "fornax_memory_store",
+			Name:           "obj_list_used_slots",
+			Help:           "Number of slots holding live objects in revisioned object list of a resource",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource"},
+	)
+	objListSlotUtilization = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      "fornax_memory_store",
+			Name:           "obj_list_slot_utilization",
+			Help:           "Ratio of slots holding live objects to slots used in revisioned object list of a resource, nil and deleted slots are wasted",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource"},
+	)
+	operationDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem:      "fornax_memory_store",
+			Name:           "operation_duration_seconds",
+			Help:           "Latency of memory store operations of a resource",
+			Buckets:        metrics.ExponentialBuckets(0.00001, 2, 16),
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource", "operation"},
+	)
+	operations = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_memory_store",
+			Name:           "operations_total",
+			Help:           "Number of memory store operations of a resource, result is success or error",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource", "operation", "result"},
+	)
+	objectCounts = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      "fornax_memory_store",
+			Name:           "objects",
+			Help:           "Number of live objects of a resource under a key prefix, prefix is first key segment after resource key, e.g. namespace",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource", "prefix"},
+	)
+	watcherCounts = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      "fornax_memory_store",
+			Name:           "watchers",
+			Help:           "Number of active watchers of a resource",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource"},
+	)
+	sentEvents = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_memory_store",
+			Name:           "events_total",
+			Help:           "Number of events sent to watchers of a resource, type is ADDED, MODIFIED or DELETED",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource", "type"},
+	)
+	compactions = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_memory_store",
+			Name:           "compactions_total",
+			Help:           "Number of revisioned object list compactions, trigger is interval or api",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource", "trigger"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(watcherQueueDepth, watcherDroppedEvents, slowWatcherClosed, objListSlots, objListUsedSlots, objListSlotUtilization, compactions,
+		operationDuration, operations, objectCounts, watcherCounts, sentEvents)
+}
+
+const (
+	operationCreate           = "create"
+	operationGet              = "get"
+	operationGetList          = "get_list"
+	operationGuaranteedUpdate = "guaranteed_update"
+	operationDelete           = "delete"
+	operationDeleteCollection = "delete_collection"
+	operationWatch            = "watch"
+)
+
+// observeOperation record latency and result of a operation started at st
+func (ms *MemoryStore) observeOperation(operation string, st time.Time, err error) {
+	resource := ms.groupResource.String()
+	operationDuration.WithLabelValues(resource, operation).Observe(time.Since(st).Seconds())
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	operations.WithLabelValues(resource, operation, result).Inc()
+}
+
+func (ms *MemoryStore) observeEvents(events []*objEvent) {
+	resource := ms.groupResource.String()
+	for _, v := range events {
+		eventType := watch.Modified
+		if v.isCreated {
+			eventType = watch.Added
+		} else if v.isDeleted {
+			eventType = watch.Deleted
+		}
+		sentEvents.WithLabelValues(resource, string(eventType)).Inc()
+	}
+}
+
+// updateObjectCountMetrics count live objects in revSortedObjList by key prefix, caller must hold revmu,
+// prefixes of previous run which do not have objects anymore are removed
+func (ms *MemoryStore) updateObjectCountMetrics() {
+	resource := ms.groupResource.String()
+	counts := map[string]int{}
+	for i := uint64(0); i <= ms.revSortedObjList.lastObjIndex && i < uint64(ms.revSortedObjList.Len()); i++ {
+		if v := ms.revSortedObjList.objs[i]; v != nil && !v.deleted {
+			counts[keyPrefixSegment(ms.grvKeyPrefix, v.key)] += 1
+		}
+	}
+	for prefix := range ms.countedPrefixes {
+		if _, found := counts[prefix]; !found {
+			objectCounts.DeleteLabelValues(resource, prefix)
+		}
+	}
+	ms.countedPrefixes = map[string]bool{}
+	for prefix, count := range counts {
+		objectCounts.WithLabelValues(resource, prefix).Set(float64(count))
+		ms.countedPrefixes[prefix] = true
+	}
+}
+
+// keyPrefixSegment return first key segment after resource key, e.g. namespace of /resource/namespace/name
+func keyPrefixSegment(grvKeyPrefix, key string) string {
+	segments := strings.SplitN(strings.TrimPrefix(strings.TrimPrefix(key, grvKeyPrefix), "/"), "/", 2)
+	if len(segments) < 2 {
+		return ""
+	}
+	return segments[0]
+}
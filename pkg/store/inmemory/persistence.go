@@ -0,0 +1,446 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"centaurusinfra.io/fornax-serverless/pkg/store"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	walFileName      = "wal.log"
+	snapshotFileName = "snapshot.json"
+
+	// snapshot is taken in house keeping when wal file is larger than this size
+	DefaultWALSnapshotThresholdBytes = 64 * 1024 * 1024
+)
+
+type walRecordType string
+
+const (
+	walRecordPut walRecordType = "put"
+	walRecordDel walRecordType = "del"
+)
+
+type walRecord struct {
+	Type walRecordType   `json:"type"`
+	Key  string          `json:"key"`
+	Rev  uint64          `json:"rev"`
+	Obj  json.RawMessage `json:"obj,omitempty"`
+	// compressed object payload, it's used instead of obj when object is compressed
+	Data []byte `json:"data,omitempty"`
+}
+
+// setObj put object payload into record, compressed if it's large enough
+func (r *walRecord) setObj(compressor *store.PayloadCompressor, data []byte) {
+	if compressed := compressor.Compress(data); len(compressed) < len(data) {
+		r.Data = compressed
+	} else {
+		r.Obj = data
+	}
+}
+
+func (r *walRecord) obj(compressor *store.PayloadCompressor) ([]byte, error) {
+	if len(r.Data) > 0 {
+		data, _, err := compressor.Decompress(r.Data)
+		return data, err
+	}
+	return r.Obj, nil
+}
+
+type snapshot struct {
+	Rev     uint64      `json:"rev"`
+	Records []walRecord `json:"records"`
+}
+
+// memoryStorePersistence append every store change into a write ahead log and periodically compact wal into a snapshot,
+// records are written into os file on every change, and file is fsynced by sync policy, after every write for Always,
+// every sync interval for Interval, and only when snapshot is taken or store is stopped for None.
+// replaying is idempotent, a put record older than existing object and a delete record of missing key are ignored,
+// so, a record written into both snapshot and wal is safe
+type memoryStorePersistence struct {
+	mu         sync.Mutex
+	dir        string
+	wal        *os.File
+	walSize    int64
+	compressor *store.PayloadCompressor
+	syncPolicy store.WALSyncPolicy
+	// wal has records not fsynced yet, used by Interval sync policy
+	dirty       bool
+	stopSyncing chan struct{}
+}
+
+// EnablePersistence recover store state from snapshot and wal in dir, then start to log store changes into dir,
+// it must be called after store is created and before store is used
+func (ms *MemoryStore) EnablePersistence(dir string) error {
+	if ms.newFunc == nil {
+		return fmt.Errorf("memory store of %s does not have newFunc to decode persisted object", ms.groupResource.String())
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	compressor, err := store.NewPayloadCompressor(ms.groupResource, ms.config.Compression, ms.config.CompressionThresholdBytes)
+	if err != nil {
+		return err
+	}
+	p := &memoryStorePersistence{
+		mu:         sync.Mutex{},
+		dir:        dir,
+		compressor: compressor,
+		syncPolicy: ms.config.WALSyncPolicy,
+	}
+
+	objs := map[string]*walRecord{}
+	snapRev, err := p.loadSnapshot(objs)
+	if err != nil {
+		return err
+	}
+	// deleted objects are not kept in snapshot, events before snapshot can not be replayed
+	ms.compact(snapRev)
+	if err := p.replayWAL(objs); err != nil {
+		return err
+	}
+	if err := ms.restoreObjects(objs, compressor); err != nil {
+		return err
+	}
+
+	ms.persistence = p
+	klog.InfoS("Memory store recovered from disk", "resource", ms.groupResource.String(), "dir", dir)
+	// compact recovered state into a new snapshot and start a empty wal
+	if err := ms.snapshot(); err != nil {
+		return err
+	}
+	if p.syncPolicy == store.WALSyncPolicyInterval {
+		interval := time.Duration(ms.config.WALSyncIntervalMillis) * time.Millisecond
+		if interval <= 0 {
+			interval = store.DefaultWALSyncIntervalMillis * time.Millisecond
+		}
+		p.stopSyncing = make(chan struct{})
+		go p.syncPeriodically(interval, p.stopSyncing)
+	}
+	return nil
+}
+
+// syncPeriodically group commit wal records written in a interval into one fsync
+func (p *memoryStorePersistence) syncPeriodically(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			if p.dirty && p.wal != nil {
+				if err := p.wal.Sync(); err != nil {
+					klog.ErrorS(err, "Failed to sync wal", "dir", p.dir)
+				}
+				p.dirty = false
+			}
+			p.mu.Unlock()
+		}
+	}
+}
+
+func (p *memoryStorePersistence) loadSnapshot(objs map[string]*walRecord) (uint64, error) {
+	data, err := os.ReadFile(filepath.Join(p.dir, snapshotFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	snap := &snapshot{}
+	if err := json.Unmarshal(data, snap); err != nil {
+		return 0, err
+	}
+	for i := range snap.Records {
+		applyWALRecord(objs, &snap.Records[i])
+	}
+	return snap.Rev, nil
+}
+
+func (p *memoryStorePersistence) replayWAL(objs map[string]*walRecord) error {
+	f, err := os.Open(filepath.Join(p.dir, walFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// a partial line at end of file is a torn write when store crashed, ignore it
+			return nil
+		} else if err != nil {
+			return err
+		}
+		record := &walRecord{}
+		if err := json.Unmarshal(line, record); err != nil {
+			return err
+		}
+		applyWALRecord(objs, record)
+	}
+}
+
+// applyWALRecord keep latest record of a key, delete record is kept as a tombstone,
+// so, a older put record replayed after delete does not recreate object
+func applyWALRecord(objs map[string]*walRecord, record *walRecord) {
+	if existing, found := objs[record.Key]; found && existing.Rev >= record.Rev {
+		return
+	}
+	objs[record.Key] = record
+}
+
+// restoreObjects put recovered objects into kv map and revSortedObjList ordered by revision
+func (ms *MemoryStore) restoreObjects(objs map[string]*walRecord, compressor *store.PayloadCompressor) error {
+	records := make([]*walRecord, 0, len(objs))
+	for _, v := range objs {
+		if v.Type == walRecordPut {
+			records = append(records, v)
+		} else if v.Type == walRecordDel {
+			// deleted objects are not restored, events before them can not be replayed
+			ms.compact(v.Rev)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Rev < records[j].Rev })
+
+	ms.lockRev()
+	defer ms.revmu.Unlock()
+	if uint64(ms.revSortedObjList.Len()) < uint64(len(records))+DefaultObjRevListGrowThreashold {
+		ms.revSortedObjList.grow(uint64(len(records)) + DefaultObjRevListGrowThreashold)
+	}
+	for _, r := range records {
+		obj := ms.newFunc()
+		data, err := r.obj(compressor)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, obj); err != nil {
+			return err
+		}
+		store.SetObjectResourceVersion(obj, r.Rev)
+		index := atomic.AddUint64(&ms.revSortedObjList.lastObjIndex, 1)
+		objWi := &objWithIndex{
+			key:     r.Key,
+			obj:     obj,
+			index:   index,
+			deleted: false,
+		}
+		if err := ms.kvs.put(strings.Split(r.Key, "/"), objWi, r.Rev); err != nil {
+			return err
+		}
+		ms.revSortedObjList.objs[index] = objWi
+		ms.index.update(r.Key, obj)
+
+		// make sure new revision is larger than recovered revision
+		for {
+			cur := atomic.LoadUint64(&_MemoryRev)
+			if cur >= r.Rev || atomic.CompareAndSwapUint64(&_MemoryRev, cur, r.Rev) {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// logEvents append store changes into wal, wal is fsynced once for all events when sync policy is Always,
+// so, a committed change is on disk before it's returned to client
+func (ms *MemoryStore) logEvents(events []*objEvent) {
+	p := ms.persistence
+	lines := []byte{}
+	for _, event := range events {
+		record := &walRecord{
+			Key: event.key,
+			Rev: event.rev,
+		}
+		if event.isDeleted {
+			record.Type = walRecordDel
+		} else {
+			data, err := json.Marshal(event.obj)
+			if err != nil {
+				klog.ErrorS(err, "Failed to encode object into wal", "key", event.key)
+				continue
+			}
+			record.Type = walRecordPut
+			record.setObj(p.compressor, data)
+		}
+		line, err := json.Marshal(record)
+		if err != nil {
+			klog.ErrorS(err, "Failed to encode wal record", "key", event.key)
+			continue
+		}
+		lines = append(lines, line...)
+		lines = append(lines, '\n')
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.wal == nil {
+		return
+	}
+	n, err := p.wal.Write(lines)
+	if err != nil {
+		klog.ErrorS(err, "Failed to write wal records", "resource", ms.groupResource.String())
+	}
+	p.walSize += int64(n)
+	switch p.syncPolicy {
+	case store.WALSyncPolicyInterval:
+		p.dirty = true
+	case store.WALSyncPolicyNone:
+	default:
+		if err := p.wal.Sync(); err != nil {
+			klog.ErrorS(err, "Failed to sync wal", "resource", ms.groupResource.String())
+		}
+	}
+}
+
+// snapshot write all objects into snapshot file and truncate wal,
+// wal is locked during snapshot, so, changes logged after snapshot go into new wal
+func (ms *MemoryStore) snapshot() error {
+	p := ms.persistence
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	snap := &snapshot{
+		Rev:     atomic.LoadUint64(&_MemoryRev),
+		Records: []walRecord{},
+	}
+	ms.revmu.RLock()
+	objs := make([]*objWithIndex, 0, ms.revSortedObjList.lastObjIndex+1)
+	for _, v := range ms.revSortedObjList.objs[0 : ms.revSortedObjList.lastObjIndex+1] {
+		if v != nil && !v.deleted {
+			objs = append(objs, v)
+		}
+	}
+	ms.revmu.RUnlock()
+	for _, v := range objs {
+		rev, err := store.GetObjectResourceVersion(v.obj)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(v.obj)
+		if err != nil {
+			return err
+		}
+		record := walRecord{Type: walRecordPut, Key: v.key, Rev: rev}
+		record.setObj(p.compressor, data)
+		snap.Records = append(snap.Records, record)
+	}
+
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	if err := writeFileSync(filepath.Join(p.dir, snapshotFileName), data); err != nil {
+		return err
+	}
+
+	if p.wal != nil {
+		p.wal.Close()
+	}
+	wal, err := os.OpenFile(filepath.Join(p.dir, walFileName), os.O_CREATE|os.O_TRUNC|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	p.wal = wal
+	p.walSize = 0
+	p.dirty = false
+	// make truncated wal and renamed snapshot durable
+	return syncDir(p.dir)
+}
+
+func (ms *MemoryStore) closePersistence() {
+	p := ms.persistence
+	if p == nil {
+		return
+	}
+	if p.stopSyncing != nil {
+		close(p.stopSyncing)
+		p.stopSyncing = nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.wal != nil {
+		p.wal.Sync()
+		p.wal.Close()
+		p.wal = nil
+	}
+}
+
+func (ms *MemoryStore) snapshotIfWALTooLarge() {
+	p := ms.persistence
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	size := p.walSize
+	p.mu.Unlock()
+	if size > DefaultWALSnapshotThresholdBytes {
+		if err := ms.snapshot(); err != nil {
+			klog.ErrorS(err, "Failed to take memory store snapshot", "resource", ms.groupResource.String())
+		}
+	}
+}
+
+func writeFileSync(file string, data []byte) error {
+	tmpFile := file + ".tmp"
+	f, err := os.OpenFile(tmpFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, file)
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/store"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	apistorage "k8s.io/apiserver/pkg/storage"
+)
+
+func newTestPersistentSessionStore(t *testing.T, dir string, policy store.WALSyncPolicy) *MemoryStore {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	gr := fornaxv1.ApplicationSessionGrv.GroupResource()
+	config := store.DefaultResourceStorageConfiguration(gr)
+	config.WALSyncPolicy = policy
+	ms := NewMemoryStore(ctx, gr, testKeyPrefix,
+		func() runtime.Object { return &fornaxv1.ApplicationSession{} },
+		func() runtime.Object { return &fornaxv1.ApplicationSessionList{} },
+		config)
+	if err := ms.EnablePersistence(dir); err != nil {
+		t.Fatalf("failed to enable persistence: %v", err)
+	}
+	t.Cleanup(ms.closePersistence)
+	return ms
+}
+
+func getTestSession(ms *MemoryStore, name string) (*fornaxv1.ApplicationSession, error) {
+	out := &fornaxv1.ApplicationSession{}
+	err := ms.Get(context.Background(), fmt.Sprintf("%s/ns/%s", testKeyPrefix, name), apistorage.GetOptions{}, out)
+	return out, err
+}
+
+func readWALLines(t *testing.T, dir string) []string {
+	data, err := os.ReadFile(filepath.Join(dir, walFileName))
+	if err != nil {
+		t.Fatalf("failed to read wal: %v", err)
+	}
+	lines := []string{}
+	for _, v := range strings.Split(string(data), "\n") {
+		if len(v) > 0 {
+			lines = append(lines, v)
+		}
+	}
+	return lines
+}
+
+func TestPersistenceRecoverSnapshotAndWAL(t *testing.T) {
+	dir := t.TempDir()
+	ms := newTestPersistentSessionStore(t, dir, store.WALSyncPolicyAlways)
+	createTestSession(t, ms, "session-1")
+	createTestSession(t, ms, "session-2")
+	if err := ms.snapshot(); err != nil {
+		t.Fatalf("failed to take snapshot: %v", err)
+	}
+
+	// changes after snapshot are only in wal
+	session3 := createTestSession(t, ms, "session-3")
+	updated := &fornaxv1.ApplicationSession{}
+	if err := ms.GuaranteedUpdate(context.Background(), testKeyPrefix+"/ns/session-1", updated, false, nil,
+		func(input runtime.Object, res apistorage.ResponseMeta) (runtime.Object, *uint64, error) {
+			session := input.(*fornaxv1.ApplicationSession)
+			session.Labels = map[string]string{"updated": "true"}
+			return session, nil, nil
+		}, nil); err != nil {
+		t.Fatalf("failed to update session: %v", err)
+	}
+	if err := ms.Delete(context.Background(), testKeyPrefix+"/ns/session-2", &fornaxv1.ApplicationSession{}, nil, apistorage.ValidateAllObjectFunc, nil); err != nil {
+		t.Fatalf("failed to delete session: %v", err)
+	}
+	if lines := readWALLines(t, dir); len(lines) != 3 {
+		t.Fatalf("expected 3 wal records after snapshot, got %d", len(lines))
+	}
+
+	recovered := newTestPersistentSessionStore(t, dir, store.WALSyncPolicyAlways)
+	session1, err := getTestSession(recovered, "session-1")
+	if err != nil {
+		t.Fatalf("failed to get recovered session-1: %v", err)
+	}
+	if session1.Labels["updated"] != "true" || session1.ResourceVersion != updated.ResourceVersion {
+		t.Errorf("expected update of session-1 replayed from wal, got labels %v rv %s", session1.Labels, session1.ResourceVersion)
+	}
+	if got, err := getTestSession(recovered, "session-3"); err != nil || got.ResourceVersion != session3.ResourceVersion {
+		t.Errorf("expected session-3 recovered with rv %s, got %v, %v", session3.ResourceVersion, got.ResourceVersion, err)
+	}
+	if _, err := getTestSession(recovered, "session-2"); !apistorage.IsNotFound(err) {
+		t.Errorf("expected deleted session-2 not recovered, got %v", err)
+	}
+}
+
+func TestPersistenceIgnoreTornWALRecord(t *testing.T) {
+	dir := t.TempDir()
+	ms := newTestPersistentSessionStore(t, dir, store.WALSyncPolicyAlways)
+	createTestSession(t, ms, "session-1")
+	createTestSession(t, ms, "session-2")
+
+	// a crash in middle of write leave a partial last line
+	f, err := os.OpenFile(filepath.Join(dir, walFileName), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatalf("failed to open wal: %v", err)
+	}
+	if _, err := f.WriteString(`{"type":"put","key":"/test/applicationsessions/ns/session-3","rev":`); err != nil {
+		t.Fatalf("failed to write partial wal record: %v", err)
+	}
+	f.Close()
+
+	recovered := newTestPersistentSessionStore(t, dir, store.WALSyncPolicyAlways)
+	for _, name := range []string{"session-1", "session-2"} {
+		if _, err := getTestSession(recovered, name); err != nil {
+			t.Errorf("expected %s recovered, got %v", name, err)
+		}
+	}
+	if _, err := getTestSession(recovered, "session-3"); !apistorage.IsNotFound(err) {
+		t.Errorf("expected torn record ignored, got %v", err)
+	}
+}
+
+func TestPersistenceTruncateWALAfterSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	ms := newTestPersistentSessionStore(t, dir, store.WALSyncPolicyAlways)
+	createTestSession(t, ms, "session-1")
+	if lines := readWALLines(t, dir); len(lines) != 1 {
+		t.Fatalf("expected 1 wal record, got %d", len(lines))
+	}
+	if err := ms.snapshot(); err != nil {
+		t.Fatalf("failed to take snapshot: %v", err)
+	}
+	if lines := readWALLines(t, dir); len(lines) != 0 || ms.persistence.walSize != 0 {
+		t.Fatalf("expected empty wal after snapshot, got %d records, size %d", len(lines), ms.persistence.walSize)
+	}
+	if _, err := os.Stat(filepath.Join(dir, snapshotFileName+".tmp")); !os.IsNotExist(err) {
+		t.Errorf("expected temporary snapshot file renamed, got %v", err)
+	}
+
+	createTestSession(t, ms, "session-2")
+	lines := readWALLines(t, dir)
+	if len(lines) != 1 || !strings.Contains(lines[0], "session-2") {
+		t.Fatalf("expected only session-2 in wal after snapshot, got %v", lines)
+	}
+
+	recovered := newTestPersistentSessionStore(t, dir, store.WALSyncPolicyAlways)
+	for _, name := range []string{"session-1", "session-2"} {
+		if _, err := getTestSession(recovered, name); err != nil {
+			t.Errorf("expected %s recovered, got %v", name, err)
+		}
+	}
+	// events before snapshot can not be replayed
+	if err := recovered.checkCompactedRev(1); !apierrors.IsResourceExpired(err) {
+		t.Errorf("expected revision before snapshot expired, got %v", err)
+	}
+}
+
+func TestPersistenceIntervalSyncPolicy(t *testing.T) {
+	dir := t.TempDir()
+	ms := newTestPersistentSessionStore(t, dir, store.WALSyncPolicyInterval)
+	createTestSession(t, ms, "session-1")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		ms.persistence.mu.Lock()
+		dirty := ms.persistence.dirty
+		ms.persistence.mu.Unlock()
+		if !dirty {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected wal synced by interval policy")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if lines := readWALLines(t, dir); len(lines) != 1 {
+		t.Fatalf("expected 1 wal record, got %d", len(lines))
+	}
+}
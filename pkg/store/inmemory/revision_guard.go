@@ -0,0 +1,140 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// revisions are reserved on disk in blocks, so, disk is only written once per block instead of every revision
+	DefaultRevisionReserveBlockSize = uint64(100000)
+
+	// wall clock is allowed to move back this much between two runs
+	DefaultClockSkewTolerance = 5 * time.Second
+)
+
+var (
+	ClockRegressedError = errors.New("wall clock moved back since last run")
+)
+
+// revisionHighWaterMark is saved on disk, any revision allocated by last run is not larger than Revision
+type revisionHighWaterMark struct {
+	Revision  uint64 `json:"revision"`
+	WallClock int64  `json:"wallClock"`
+}
+
+// revisionGuard persist a high water mark of _MemoryRev, when store restart,
+// revision restart from high water mark plus one instead of wall clock, so revision never moves back
+type revisionGuard struct {
+	mu       sync.Mutex
+	file     string
+	reserved uint64
+}
+
+var _RevisionGuard *revisionGuard
+
+// InitRevisionGuard load revision high water mark from file and bump _MemoryRev above it,
+// it refuse to start if wall clock moved back more than DefaultClockSkewTolerance since last run,
+// it must be called before any memory store is created
+func InitRevisionGuard(file string) error {
+	guard := &revisionGuard{
+		mu:   sync.Mutex{},
+		file: file,
+	}
+
+	hwm, err := guard.load()
+	if err != nil {
+		return err
+	}
+	if hwm != nil {
+		now := time.Now()
+		if now.Add(DefaultClockSkewTolerance).UnixNano() < hwm.WallClock {
+			return fmt.Errorf("%w, last run at %s, now %s", ClockRegressedError, time.Unix(0, hwm.WallClock), now)
+		}
+		if atomic.LoadUint64(&_MemoryRev) <= hwm.Revision {
+			atomic.StoreUint64(&_MemoryRev, hwm.Revision+1)
+		}
+		klog.InfoS("Memory revision restored from high water mark", "high water mark", hwm.Revision, "revision", atomic.LoadUint64(&_MemoryRev))
+	}
+
+	if err := guard.reserve(atomic.LoadUint64(&_MemoryRev)); err != nil {
+		return err
+	}
+	_RevisionGuard = guard
+	return nil
+}
+
+func (g *revisionGuard) load() (*revisionHighWaterMark, error) {
+	data, err := os.ReadFile(g.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	hwm := &revisionHighWaterMark{}
+	if err := json.Unmarshal(data, hwm); err != nil {
+		return nil, err
+	}
+	return hwm, nil
+}
+
+// ensureReserved persist a new high water mark if rev reach reserved block
+func (g *revisionGuard) ensureReserved(rev uint64) error {
+	if rev < atomic.LoadUint64(&g.reserved) {
+		return nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if rev < g.reserved {
+		return nil
+	}
+	return g.reserve(rev)
+}
+
+func (g *revisionGuard) reserve(rev uint64) error {
+	hwm := &revisionHighWaterMark{
+		Revision:  rev + DefaultRevisionReserveBlockSize,
+		WallClock: time.Now().UnixNano(),
+	}
+	data, err := json.Marshal(hwm)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(g.file), 0700); err != nil {
+		return err
+	}
+	tmpFile := g.file + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpFile, g.file); err != nil {
+		return err
+	}
+	atomic.StoreUint64(&g.reserved, hwm.Revision)
+	return nil
+}
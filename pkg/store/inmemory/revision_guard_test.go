@@ -0,0 +1,88 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func writeHighWaterMark(t *testing.T, file string, hwm revisionHighWaterMark) {
+	data, _ := json.Marshal(&hwm)
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRevisionGuardRestoreHighWaterMark(t *testing.T) {
+	t.Cleanup(func() { _RevisionGuard = nil })
+	file := filepath.Join(t.TempDir(), "memory_revision")
+	hwm := atomic.LoadUint64(&_MemoryRev) + 10*DefaultRevisionReserveBlockSize
+	writeHighWaterMark(t, file, revisionHighWaterMark{Revision: hwm, WallClock: time.Now().UnixNano()})
+
+	if err := InitRevisionGuard(file); err != nil {
+		t.Fatal(err)
+	}
+	if rev := atomic.LoadUint64(&_MemoryRev); rev <= hwm {
+		t.Errorf("expected revision restarted above high water mark %d, got %d", hwm, rev)
+	}
+	saved, err := _RevisionGuard.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Revision != atomic.LoadUint64(&_MemoryRev)+DefaultRevisionReserveBlockSize {
+		t.Errorf("expected a block reserved above restored revision, got %d", saved.Revision)
+	}
+
+	// a revision reaching reserved block persist next block
+	if err := _RevisionGuard.ensureReserved(saved.Revision); err != nil {
+		t.Fatal(err)
+	}
+	next, _ := _RevisionGuard.load()
+	if next.Revision != saved.Revision+DefaultRevisionReserveBlockSize {
+		t.Errorf("expected next block reserved, got %d", next.Revision)
+	}
+}
+
+func TestRevisionGuardRefuseClockRegression(t *testing.T) {
+	t.Cleanup(func() { _RevisionGuard = nil })
+	file := filepath.Join(t.TempDir(), "memory_revision")
+	writeHighWaterMark(t, file, revisionHighWaterMark{Revision: 1, WallClock: time.Now().Add(time.Hour).UnixNano()})
+	if err := InitRevisionGuard(file); !errors.Is(err, ClockRegressedError) {
+		t.Errorf("expected clock regressed error, got %v", err)
+	}
+}
+
+func TestRevisionGuardWithoutHighWaterMark(t *testing.T) {
+	t.Cleanup(func() { _RevisionGuard = nil })
+	file := filepath.Join(t.TempDir(), "dir", "memory_revision")
+	rev := atomic.LoadUint64(&_MemoryRev)
+	if err := InitRevisionGuard(file); err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadUint64(&_MemoryRev) != rev {
+		t.Errorf("expected revision unchanged on first run")
+	}
+	if _, err := os.Stat(file); err != nil {
+		t.Errorf("expected high water mark saved on first run, %v", err)
+	}
+}
@@ -0,0 +1,735 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"centaurusinfra.io/fornax-serverless/pkg/store"
+	"centaurusinfra.io/fornax-serverless/pkg/store/eventsink"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// virtual nodes of a shard on hash ring
+	shardHashRingReplicas = 64
+)
+
+// FornaxMemoryStore is implemented by MemoryStore and ShardedMemoryStore, store factory use it to manage both
+type FornaxMemoryStore interface {
+	store.ApiStorageInterface
+	GroupResource() schema.GroupResource
+	ApplyConfiguration(config store.ResourceStorageConfiguration)
+	CompleteWithFunctions(
+		keyFunc func(obj runtime.Object) (string, error),
+		newFunc func() runtime.Object,
+		newListFunc func() runtime.Object,
+		getAttrsFunc apistorage.AttrFunc,
+		triggerFuncs apistorage.IndexerFuncs,
+		indexers *cache.Indexers) error
+	EnablePersistence(dir string) error
+	Compact(trigger string) int
+	History(key string) ([]ObjectRevision, error)
+	SetEventJournal(journal *eventsink.Journal)
+	RevLockBlockedFor() time.Duration
+	ShedWatchers() int
+	GetAtRevision(ctx context.Context, key string, rev uint64, out runtime.Object) error
+	Stop() error
+}
+
+var _ FornaxMemoryStore = &MemoryStore{}
+var _ FornaxMemoryStore = &ShardedMemoryStore{}
+
+// eventSink receive events of a memory store after they are sent to store's watchers, it's called while holding watchersMu,
+// revReleased is called after a reserved or unsent revision is released, so, bookmarkRev of store may move forward
+type eventSink interface {
+	addEvents(events []*objEvent)
+	revReleased()
+}
+
+// hashRing is a consistent hash ring of shards, a key belong to first shard clockwise from its hash
+type hashRing struct {
+	hashes []uint32
+	shards map[uint32]int
+}
+
+func hashOf(key string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return h.Sum32()
+}
+
+func newHashRing(shards int) *hashRing {
+	ring := &hashRing{
+		hashes: []uint32{},
+		shards: map[uint32]int{},
+	}
+	for i := 0; i < shards; i++ {
+		for j := 0; j < shardHashRingReplicas; j++ {
+			h := hashOf(fmt.Sprintf("shard-%d-%d", i, j))
+			if _, found := ring.shards[h]; found {
+				continue
+			}
+			ring.shards[h] = i
+			ring.hashes = append(ring.hashes, h)
+		}
+	}
+	sort.Slice(ring.hashes, func(i, j int) bool { return ring.hashes[i] < ring.hashes[j] })
+	return ring
+}
+
+func (r *hashRing) get(key string) int {
+	h := hashOf(key)
+	i := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if i == len(r.hashes) {
+		i = 0
+	}
+	return r.shards[r.hashes[i]]
+}
+
+// ShardedMemoryStore split objects of a resource into multiple memory stores by namespace, each shard has its own locks,
+// revisions are still allocated from global memory revision, so, revision order is kept across shards,
+// a watch or list on a single namespace go to its shard, a watch across namespaces get events of all shards merged in revision order
+type ShardedMemoryStore struct {
+	groupResource schema.GroupResource
+	grvKeyPrefix  string
+	shards        []*MemoryStore
+	ring          *hashRing
+	merger        *shardEventMerger
+	stopChannel   chan interface{}
+}
+
+// NewShardedMemoryStore create config.Shards memory stores of a groupResource
+func NewShardedMemoryStore(ctx context.Context, groupResource schema.GroupResource, grvKeyPrefix string, newFunc func() runtime.Object, newListFunc func() runtime.Object, config store.ResourceStorageConfiguration) *ShardedMemoryStore {
+	klog.InfoS("New a sharded in memory store", "resource", groupResource.String(), "shards", config.Shards)
+	ss := &ShardedMemoryStore{
+		groupResource: groupResource,
+		grvKeyPrefix:  grvKeyPrefix,
+		shards:        []*MemoryStore{},
+		ring:          newHashRing(config.Shards),
+		stopChannel:   make(chan interface{}),
+	}
+	ss.merger = newShardEventMerger(groupResource.String())
+	for i := 0; i < config.Shards; i++ {
+		shard := newMemoryStore(ctx, groupResource, grvKeyPrefix, newFunc, newListFunc, config, ss.merger)
+		ss.shards = append(ss.shards, shard)
+	}
+	ss.merger.shards = ss.shards
+	go ss.merger.run(ctx, ss.stopChannel)
+	return ss
+}
+
+// shardOfKey return shard of key if key is a object key or a prefix of a single namespace,
+// false is returned if key is resource prefix which cover all shards
+func (ss *ShardedMemoryStore) shardOfKey(key string) (*MemoryStore, bool) {
+	rest := strings.TrimPrefix(strings.TrimPrefix(key, ss.grvKeyPrefix), "/")
+	namespace := strings.SplitN(rest, "/", 2)[0]
+	if len(namespace) == 0 {
+		return nil, false
+	}
+	return ss.shards[ss.ring.get(namespace)], true
+}
+
+func (ss *ShardedMemoryStore) shardOfObjectKey(key string) (*MemoryStore, error) {
+	shard, found := ss.shardOfKey(key)
+	if !found {
+		return nil, apistorage.NewInternalErrorf("key %s is not a object key of %s", key, ss.groupResource.String())
+	}
+	return shard, nil
+}
+
+// ApplyConfiguration apply hot reloadable configuration to all shards
+func (ss *ShardedMemoryStore) ApplyConfiguration(config store.ResourceStorageConfiguration) {
+	for _, v := range ss.shards {
+		v.ApplyConfiguration(config)
+	}
+}
+
+// CompleteWithFunctions set functions of all shards
+func (ss *ShardedMemoryStore) CompleteWithFunctions(
+	keyFunc func(obj runtime.Object) (string, error),
+	newFunc func() runtime.Object,
+	newListFunc func() runtime.Object,
+	getAttrsFunc apistorage.AttrFunc,
+	triggerFuncs apistorage.IndexerFuncs,
+	indexers *cache.Indexers) error {
+	for _, v := range ss.shards {
+		if err := v.CompleteWithFunctions(keyFunc, newFunc, newListFunc, getAttrsFunc, triggerFuncs, indexers); err != nil {
+			return err
+		}
+	}
+	ss.merger.setNewFunc(newFunc)
+	return nil
+}
+
+// EnablePersistence persist every shard in its own sub directory of dir
+func (ss *ShardedMemoryStore) EnablePersistence(dir string) error {
+	for i, v := range ss.shards {
+		if err := v.EnablePersistence(filepath.Join(dir, fmt.Sprintf("shard-%d", i))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Compact compact all shards and return total number of removed slots
+func (ss *ShardedMemoryStore) Compact(trigger string) int {
+	removed := 0
+	for _, v := range ss.shards {
+		removed += v.Compact(trigger)
+	}
+	return removed
+}
+
+func (ss *ShardedMemoryStore) GroupResource() schema.GroupResource {
+	return ss.groupResource
+}
+
+func (ss *ShardedMemoryStore) Stop() error {
+	ss.stopChannel <- "stop"
+	for _, v := range ss.shards {
+		v.Stop()
+	}
+	return nil
+}
+
+// Versioner implements k8s storage.Interface
+func (ss *ShardedMemoryStore) Versioner() apistorage.Versioner {
+	return ss.shards[0].Versioner()
+}
+
+// Count implements k8s storage.Interface
+func (ss *ShardedMemoryStore) Count(key string) (int64, error) {
+	if shard, found := ss.shardOfKey(key); found {
+		return shard.Count(key)
+	}
+	count := int64(0)
+	for _, v := range ss.shards {
+		n, err := v.Count(key)
+		if err != nil {
+			return 0, err
+		}
+		count += n
+	}
+	return count, nil
+}
+
+// Create implements k8s storage.Interface
+func (ss *ShardedMemoryStore) Create(ctx context.Context, key string, obj runtime.Object, out runtime.Object, ttl uint64) error {
+	shard, err := ss.shardOfObjectKey(key)
+	if err != nil {
+		return err
+	}
+	return shard.Create(ctx, key, obj, out, ttl)
+}
+
+// Delete implements k8s storage.Interface
+func (ss *ShardedMemoryStore) Delete(ctx context.Context, key string, out runtime.Object, preconditions *apistorage.Preconditions, validateDeletion apistorage.ValidateObjectFunc, cachedExistingObject runtime.Object) error {
+	shard, err := ss.shardOfObjectKey(key)
+	if err != nil {
+		return err
+	}
+	return shard.Delete(ctx, key, out, preconditions, validateDeletion, cachedExistingObject)
+}
+
+// Get implements k8s storage.Interface
+func (ss *ShardedMemoryStore) Get(ctx context.Context, key string, opts apistorage.GetOptions, out runtime.Object) error {
+	shard, err := ss.shardOfObjectKey(key)
+	if err != nil {
+		return err
+	}
+	return shard.Get(ctx, key, opts, out)
+}
+
+// lastSendLockWait return longest wait of shards for watchers lock when events were last sent
+func (ss *ShardedMemoryStore) lastSendLockWait() time.Duration {
+	wait := time.Duration(0)
+	for _, v := range ss.shards {
+		if w := v.lastSendLockWait(); w > wait {
+			wait = w
+		}
+	}
+	return wait
+}
+
+// RevLockBlockedFor return longest time writers have been blocked on revision lock of shards
+func (ss *ShardedMemoryStore) RevLockBlockedFor() time.Duration {
+	blocked := time.Duration(0)
+	for _, v := range ss.shards {
+		if b := v.RevLockBlockedFor(); b > blocked {
+			blocked = b
+		}
+	}
+	return blocked
+}
+
+// ShedWatchers stop lagging watchers of shards and cross shard watchers
+func (ss *ShardedMemoryStore) ShedWatchers() int {
+	shed := 0
+	for _, v := range ss.shards {
+		shed += v.ShedWatchers()
+	}
+	ss.merger.mu.Lock()
+	defer ss.merger.mu.Unlock()
+	return shed + shedLaggingWatchers(ss.merger.watchers)
+}
+
+// SetEventJournal export events of all shards to a message bus, order is only kept within a namespace
+func (ss *ShardedMemoryStore) SetEventJournal(journal *eventsink.Journal) {
+	for _, v := range ss.shards {
+		v.SetEventJournal(journal)
+	}
+}
+
+// History return retained revisions of a object from its shard
+func (ss *ShardedMemoryStore) History(key string) ([]ObjectRevision, error) {
+	shard, err := ss.shardOfObjectKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return shard.History(key)
+}
+
+// GetAtRevision return state of a object as of rev from its shard
+func (ss *ShardedMemoryStore) GetAtRevision(ctx context.Context, key string, rev uint64, out runtime.Object) error {
+	shard, err := ss.shardOfObjectKey(key)
+	if err != nil {
+		return err
+	}
+	return shard.GetAtRevision(ctx, key, rev, out)
+}
+
+// GuaranteedUpdate implements k8s storage.Interface
+func (ss *ShardedMemoryStore) GuaranteedUpdate(ctx context.Context, key string, out runtime.Object, ignoreNotFound bool, preconditions *apistorage.Preconditions, tryUpdate apistorage.UpdateFunc, cachedExistingObject runtime.Object) error {
+	shard, err := ss.shardOfObjectKey(key)
+	if err != nil {
+		return err
+	}
+	return shard.GuaranteedUpdate(ctx, key, out, ignoreNotFound, preconditions, tryUpdate, cachedExistingObject)
+}
+
+// EnsureUpdateAndDelete implements FornaxStorage
+func (ss *ShardedMemoryStore) EnsureUpdateAndDelete(ctx context.Context, key string, ignoreNotFound bool, preconditions *apistorage.Preconditions, updatedObj runtime.Object, output runtime.Object) error {
+	shard, err := ss.shardOfObjectKey(key)
+	if err != nil {
+		return err
+	}
+	return shard.EnsureUpdateAndDelete(ctx, key, ignoreNotFound, preconditions, updatedObj, output)
+}
+
+// CreateOrUpdate implements FornaxStorage
+func (ss *ShardedMemoryStore) CreateOrUpdate(ctx context.Context, key string, obj runtime.Object, out runtime.Object, mergeFunc func(from runtime.Object, to runtime.Object) error) error {
+	shard, err := ss.shardOfObjectKey(key)
+	if err != nil {
+		return err
+	}
+	return shard.CreateOrUpdate(ctx, key, obj, out, mergeFunc)
+}
+
+// GetOrCreate implements FornaxStorage
+func (ss *ShardedMemoryStore) GetOrCreate(ctx context.Context, key string, objToCreate runtime.Object, out runtime.Object) error {
+	shard, err := ss.shardOfObjectKey(key)
+	if err != nil {
+		return err
+	}
+	return shard.GetOrCreate(ctx, key, objToCreate, out)
+}
+
+// CreateOrReplace implements FornaxStorage
+func (ss *ShardedMemoryStore) CreateOrReplace(ctx context.Context, key string, objToCreate runtime.Object, out runtime.Object) error {
+	shard, err := ss.shardOfObjectKey(key)
+	if err != nil {
+		return err
+	}
+	return shard.CreateOrReplace(ctx, key, objToCreate, out)
+}
+
+// AddIndexers implements FornaxStorage, indexes are maintained by every shard
+func (ss *ShardedMemoryStore) AddIndexers(indexers cache.Indexers) error {
+	for _, v := range ss.shards {
+		if err := v.AddIndexers(indexers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeShardLists call listFunc on every shard and put all items into listObj sorted by key,
+// list revision is merged watch revision before listing, watching from it may replay some listed objects but never miss a event
+func (ss *ShardedMemoryStore) mergeShardLists(listObj runtime.Object, listFunc func(shard *MemoryStore, shardListObj runtime.Object) error) error {
+	rev := ss.merger.watermark()
+	items := []runtime.Object{}
+	for _, v := range ss.shards {
+		shardListObj := listObj.DeepCopyObject()
+		if err := meta.SetList(shardListObj, []runtime.Object{}); err != nil {
+			return err
+		}
+		if err := listFunc(v, shardListObj); err != nil {
+			return err
+		}
+		shardItems, err := meta.ExtractList(shardListObj)
+		if err != nil {
+			return err
+		}
+		items = append(items, shardItems...)
+	}
+	keys := make([]string, len(items))
+	for i, v := range items {
+		keys[i], _ = cache.MetaNamespaceKeyFunc(v)
+	}
+	sort.Sort(&objectsByKey{objs: items, keys: keys})
+	if err := meta.SetList(listObj, items); err != nil {
+		return err
+	}
+	return store.UpdateList(listObj, rev, "", nil)
+}
+
+type objectsByKey struct {
+	objs []runtime.Object
+	keys []string
+}
+
+func (o *objectsByKey) Len() int           { return len(o.objs) }
+func (o *objectsByKey) Less(i, j int) bool { return o.keys[i] < o.keys[j] }
+func (o *objectsByKey) Swap(i, j int) {
+	o.objs[i], o.objs[j] = o.objs[j], o.objs[i]
+	o.keys[i], o.keys[j] = o.keys[j], o.keys[i]
+}
+
+// GetList implements k8s storage.Interface, a list across shards with limit or continue is paged by listPages
+func (ss *ShardedMemoryStore) GetList(ctx context.Context, key string, opts apistorage.ListOptions, listObj runtime.Object) error {
+	if shard, found := ss.shardOfKey(key); found {
+		return shard.GetList(ctx, key, opts, listObj)
+	}
+	if opts.Predicate.Limit > 0 || len(opts.Predicate.Continue) > 0 {
+		return ss.listPages(ctx, key, opts, listObj)
+	}
+	return ss.mergeShardLists(listObj, func(shard *MemoryStore, shardListObj runtime.Object) error {
+		return shard.GetList(ctx, key, opts, shardListObj)
+	})
+}
+
+// listPages list a page of every shard at same revision and cut merged items at limit, shards list items in key order,
+// so, continue token of last returned key is valid for every shard, first page is listed at merged watch revision
+func (ss *ShardedMemoryStore) listPages(ctx context.Context, key string, opts apistorage.ListOptions, listObj runtime.Object) error {
+	if len(opts.Predicate.Continue) == 0 && opts.ResourceVersionMatch != metav1.ResourceVersionMatchExact {
+		watermark := ss.merger.watermark()
+		if len(opts.ResourceVersion) > 0 {
+			minRV, err := store.ParseResourceVersion(opts.ResourceVersion)
+			if err != nil {
+				return apierrors.NewBadRequest(fmt.Sprintf("invalid resource version: %v", err))
+			}
+			if minRV > watermark {
+				return apistorage.NewTooLargeResourceVersionError(minRV, watermark, 1)
+			}
+		}
+		opts.ResourceVersion = strconv.FormatUint(watermark, 10)
+		opts.ResourceVersionMatch = metav1.ResourceVersionMatchExact
+	}
+
+	listRev := uint64(0)
+	hasMore := false
+	items := []runtime.Object{}
+	for _, v := range ss.shards {
+		shardListObj := listObj.DeepCopyObject()
+		if err := meta.SetList(shardListObj, []runtime.Object{}); err != nil {
+			return err
+		}
+		if err := v.GetList(ctx, key, opts, shardListObj); err != nil {
+			return err
+		}
+		shardItems, err := meta.ExtractList(shardListObj)
+		if err != nil {
+			return err
+		}
+		listMeta, err := meta.ListAccessor(shardListObj)
+		if err != nil {
+			return err
+		}
+		if len(listMeta.GetContinue()) > 0 {
+			hasMore = true
+		}
+		if listRev, err = store.ParseResourceVersion(listMeta.GetResourceVersion()); err != nil {
+			return err
+		}
+		items = append(items, shardItems...)
+	}
+	keys := make([]string, len(items))
+	for i, v := range items {
+		keys[i], _ = cache.MetaNamespaceKeyFunc(v)
+	}
+	sort.Sort(&objectsByKey{objs: items, keys: keys})
+	if limit := int(opts.Predicate.Limit); limit > 0 && len(items) > limit {
+		items, keys = items[:limit], keys[:limit]
+		hasMore = true
+	}
+	if err := meta.SetList(listObj, items); err != nil {
+		return err
+	}
+
+	continueToken := ""
+	if hasMore && len(keys) > 0 {
+		keyPrefix := key
+		if !strings.HasSuffix(keyPrefix, "/") {
+			keyPrefix += "/"
+		}
+		var err error
+		if continueToken, err = store.EncodeContinue(keyPrefix+keys[len(keys)-1], keyPrefix, listRev); err != nil {
+			return err
+		}
+	}
+	return store.UpdateList(listObj, listRev, continueToken, nil)
+}
+
+// DeleteCollection implements FornaxStorage
+func (ss *ShardedMemoryStore) DeleteCollection(ctx context.Context, key string, validateDeletion apistorage.ValidateObjectFunc, listObj runtime.Object) error {
+	if shard, found := ss.shardOfKey(key); found {
+		return shard.DeleteCollection(ctx, key, validateDeletion, listObj)
+	}
+	return ss.mergeShardLists(listObj, func(shard *MemoryStore, shardListObj runtime.Object) error {
+		return shard.DeleteCollection(ctx, key, validateDeletion, shardListObj)
+	})
+}
+
+// ListByIndex implements FornaxStorage
+func (ss *ShardedMemoryStore) ListByIndex(ctx context.Context, key string, indexName, indexedValue string, listObj runtime.Object) error {
+	if shard, found := ss.shardOfKey(key); found {
+		return shard.ListByIndex(ctx, key, indexName, indexedValue, listObj)
+	}
+	return ss.mergeShardLists(listObj, func(shard *MemoryStore, shardListObj runtime.Object) error {
+		return shard.ListByIndex(ctx, key, indexName, indexedValue, shardListObj)
+	})
+}
+
+// SnapshotList implements FornaxStorage, every shard is listed as of same rev
+func (ss *ShardedMemoryStore) SnapshotList(ctx context.Context, key string, rev uint64, listObj runtime.Object) error {
+	if shard, found := ss.shardOfKey(key); found {
+		return shard.SnapshotList(ctx, key, rev, listObj)
+	}
+	if err := ss.mergeShardLists(listObj, func(shard *MemoryStore, shardListObj runtime.Object) error {
+		return shard.SnapshotList(ctx, key, rev, shardListObj)
+	}); err != nil {
+		return err
+	}
+	if rev > 0 {
+		return store.UpdateList(listObj, rev, "", nil)
+	}
+	return nil
+}
+
+// Watch implements k8s storage.Interface
+func (ss *ShardedMemoryStore) Watch(ctx context.Context, key string, opts apistorage.ListOptions) (watch.Interface, error) {
+	if shard, found := ss.shardOfKey(key); found {
+		return shard.Watch(ctx, key, opts)
+	}
+	return ss.watch(ctx, key, opts, false)
+}
+
+// WatchWithOldObj implements FornaxStorage
+func (ss *ShardedMemoryStore) WatchWithOldObj(ctx context.Context, key string, opts apistorage.ListOptions) (store.WatchWithOldObjInterface, error) {
+	if shard, found := ss.shardOfKey(key); found {
+		return shard.WatchWithOldObj(ctx, key, opts)
+	}
+	return ss.watch(ctx, key, opts, true)
+}
+
+// watch across shards, watcher is registered on merger, missed events are replayed from shards up to merged watermark,
+// later events are delivered by merger in revision order
+func (ss *ShardedMemoryStore) watch(ctx context.Context, key string, opts apistorage.ListOptions, withOldObj bool) (*memoryStoreWatcher, error) {
+	rev, err := store.ParseResourceVersion(opts.ResourceVersion)
+	if err != nil {
+		return nil, err
+	}
+	config := ss.shards[0].config
+	watcher := NewMemoryStoreWatcher(ctx, key, opts, ss.groupResource.String(), config.WatcherQueueSize, config.SlowWatcherPolicy)
+	watcher.sendLockWait = ss.lastSendLockWait
+	watermark := ss.merger.addWatcher(watcher)
+
+	objEvents := []*objEvent{}
+	if rev > 1 {
+		for _, v := range ss.shards {
+			shardEvents, found := v.watchEventCache.getObjEventsAfterRev(watcher.keyPrefix, rev, opts)
+			if !found {
+				if err := v.checkCompactedRev(rev); err != nil {
+					watcher.abort()
+					return nil, err
+				}
+				shardEvents, err = v.getObjEventsAfterRev(key, rev, opts)
+				if err != nil {
+					watcher.abort()
+					return nil, err
+				}
+			}
+			for _, e := range shardEvents {
+				// events after watermark are delivered by merger
+				if e.rev <= watermark {
+					objEvents = append(objEvents, e)
+				}
+			}
+		}
+		sort.SliceStable(objEvents, func(i, j int) bool { return objEvents[i].rev < objEvents[j].rev })
+	} else {
+		rev = watermark
+	}
+	go watcher.run(rev, objEvents, withOldObj)
+	return watcher, nil
+}
+
+// shardEventMerger collect events sent by shards, and release them to cross shard watchers in revision order,
+// a event is released when no shard could still send a event with a lower revision
+type shardEventMerger struct {
+	mu       sync.Mutex
+	resource string
+	shards   []*MemoryStore
+	newFunc  func() runtime.Object
+	events   []*objEvent
+	watchers []*memoryStoreWatcher
+	notify   chan struct{}
+}
+
+func newShardEventMerger(resource string) *shardEventMerger {
+	return &shardEventMerger{
+		mu:       sync.Mutex{},
+		resource: resource,
+		events:   []*objEvent{},
+		watchers: []*memoryStoreWatcher{},
+		notify:   make(chan struct{}, 1),
+	}
+}
+
+// addEvents implements eventSink, events are kept even if there is no cross shard watcher, a watcher added later only replay
+// events at or before watermark from shards, events after watermark sent by a shard before watcher is added are released by merger
+func (m *shardEventMerger) addEvents(events []*objEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, events...)
+	m.signal()
+}
+
+// revReleased implements eventSink
+func (m *shardEventMerger) revReleased() {
+	m.signal()
+}
+
+func (m *shardEventMerger) signal() {
+	select {
+	case m.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (m *shardEventMerger) setNewFunc(newFunc func() runtime.Object) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.newFunc = newFunc
+}
+
+// watermark return lowest bookmark revision of shards, all events at or before it have been sent by shards
+func (m *shardEventMerger) watermark() uint64 {
+	rev := uint64(0)
+	for i, v := range m.shards {
+		if shardRev := v.bookmarkRev(); i == 0 || shardRev < rev {
+			rev = shardRev
+		}
+	}
+	return rev
+}
+
+// addWatcher register a cross shard watcher and return watermark at registration,
+// watcher will receive all events released after watermark
+func (m *shardEventMerger) addWatcher(watcher *memoryStoreWatcher) uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.watchers = append(m.watchers, watcher)
+	return m.watermark()
+}
+
+// release send events at or before watermark to watchers in revision order and remove stopped watchers,
+// released events are dropped if there is no watcher
+func (m *shardEventMerger) release(bookmark bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	watermark := m.watermark()
+	released, kept := []*objEvent{}, []*objEvent{}
+	for _, v := range m.events {
+		if v.rev <= watermark {
+			released = append(released, v)
+		} else {
+			kept = append(kept, v)
+		}
+	}
+	m.events = kept
+	sort.SliceStable(released, func(i, j int) bool { return released[i].rev < released[j].rev })
+
+	var bookmarkEvent *objEvent
+	if bookmark && m.newFunc != nil {
+		obj := m.newFunc()
+		if err := m.shards[0].versioner.UpdateObject(obj, watermark); err != nil {
+			klog.ErrorS(err, "Failed to set revision of bookmark object", "resource", m.resource)
+		} else {
+			bookmarkEvent = &objEvent{obj: obj, rev: watermark, isBookmark: true}
+		}
+	}
+
+	watchers := []*memoryStoreWatcher{}
+	for _, v := range m.watchers {
+		if v.stopped() {
+			continue
+		}
+		for _, event := range released {
+			v.enqueue(event)
+		}
+		if bookmarkEvent != nil && v.allowBookmarks {
+			v.enqueueBookmark(bookmarkEvent)
+		}
+		watchers = append(watchers, v)
+	}
+	m.watchers = watchers
+}
+
+func (m *shardEventMerger) run(ctx context.Context, stopCh <-chan interface{}) {
+	bookmarkTicker := time.NewTicker(DefaultWatchBookmarkInterval)
+	defer bookmarkTicker.Stop()
+	for {
+		select {
+		case <-m.notify:
+			m.release(false)
+		case <-bookmarkTicker.C:
+			m.release(true)
+		case <-stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
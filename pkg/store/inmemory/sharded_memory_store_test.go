@@ -0,0 +1,201 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/store"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	apistorage "k8s.io/apiserver/pkg/storage"
+)
+
+func newTestShardedSessionStore(t *testing.T, shards int) *ShardedMemoryStore {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	gr := fornaxv1.ApplicationSessionGrv.GroupResource()
+	config := store.DefaultResourceStorageConfiguration(gr)
+	config.Shards = shards
+	ss := NewShardedMemoryStore(ctx, gr, testKeyPrefix,
+		func() runtime.Object { return &fornaxv1.ApplicationSession{} },
+		func() runtime.Object { return &fornaxv1.ApplicationSessionList{} },
+		config)
+	t.Cleanup(func() { ss.Stop() })
+	return ss
+}
+
+func createTestShardedSession(t *testing.T, ss *ShardedMemoryStore, namespace, name string) *fornaxv1.ApplicationSession {
+	session := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	out := &fornaxv1.ApplicationSession{}
+	if err := ss.Create(context.Background(), fmt.Sprintf("%s/%s/%s", testKeyPrefix, namespace, name), session, out, 0); err != nil {
+		t.Fatalf("failed to create session %s/%s: %v", namespace, name, err)
+	}
+	return out
+}
+
+func TestHashRingIsStable(t *testing.T) {
+	ring := newHashRing(4)
+	used := map[int]bool{}
+	for i := 0; i < 100; i++ {
+		namespace := fmt.Sprintf("ns-%d", i)
+		shard := ring.get(namespace)
+		if shard < 0 || shard >= 4 {
+			t.Fatalf("shard %d of %s out of range", shard, namespace)
+		}
+		if again := newHashRing(4).get(namespace); again != shard {
+			t.Errorf("expected %s always in shard %d, got %d", namespace, shard, again)
+		}
+		used[shard] = true
+	}
+	if len(used) != 4 {
+		t.Errorf("expected namespaces spread over 4 shards, got %v", used)
+	}
+}
+
+func TestShardOfKey(t *testing.T) {
+	ss := newTestShardedSessionStore(t, 4)
+	shard, found := ss.shardOfKey(testKeyPrefix + "/ns-1/session-1")
+	if !found {
+		t.Fatalf("expected object key in a shard")
+	}
+	if nsShard, found := ss.shardOfKey(testKeyPrefix + "/ns-1"); !found || nsShard != shard {
+		t.Errorf("expected namespace prefix in same shard of its objects")
+	}
+	if _, found := ss.shardOfKey(testKeyPrefix); found {
+		t.Errorf("expected resource prefix cover all shards")
+	}
+	if _, err := ss.shardOfObjectKey(testKeyPrefix + "/"); err == nil {
+		t.Errorf("expected error of resource prefix as object key")
+	}
+}
+
+func TestShardedStoreListAcrossShards(t *testing.T) {
+	ss := newTestShardedSessionStore(t, 4)
+	expected := []string{}
+	for i := 0; i < 8; i++ {
+		session := createTestShardedSession(t, ss, fmt.Sprintf("ns-%d", i), "session")
+		expected = append(expected, session.Namespace+"/"+session.Name)
+	}
+
+	// pages are merged from shards in key order, and never have more items than limit
+	got := []string{}
+	opts := apistorage.ListOptions{Recursive: true, Predicate: apistorage.SelectionPredicate{Label: labels.Everything(), Field: fields.Everything(), Limit: 3}}
+	listRV := ""
+	for pages := 0; ; pages++ {
+		if pages > len(expected) {
+			t.Fatalf("expected list end after %d pages", len(expected))
+		}
+		list := &fornaxv1.ApplicationSessionList{}
+		if err := ss.GetList(context.Background(), testKeyPrefix, opts, list); err != nil {
+			t.Fatal(err)
+		}
+		if len(list.Items) > 3 {
+			t.Fatalf("expected at most 3 items in a page, got %d", len(list.Items))
+		}
+		if len(listRV) == 0 {
+			listRV = list.ResourceVersion
+		} else if list.ResourceVersion != listRV {
+			t.Errorf("expected all pages listed at revision %s, got %s", listRV, list.ResourceVersion)
+		}
+		for _, v := range list.Items {
+			got = append(got, v.Namespace+"/"+v.Name)
+		}
+		if len(list.Continue) == 0 {
+			break
+		}
+		opts.Predicate.Continue = list.Continue
+	}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	opts.Predicate.Continue = "token"
+	if err := ss.GetList(context.Background(), testKeyPrefix, opts, &fornaxv1.ApplicationSessionList{}); !apierrors.IsBadRequest(err) {
+		t.Errorf("expected bad request of invalid continue token across shards, got %v", err)
+	}
+
+	nsList := &fornaxv1.ApplicationSessionList{}
+	opts.Predicate.Continue = ""
+	if err := ss.GetList(context.Background(), testKeyPrefix+"/ns-3", opts, nsList); err != nil {
+		t.Fatal(err)
+	}
+	if len(nsList.Items) != 1 || nsList.Items[0].Namespace != "ns-3" {
+		t.Errorf("expected only session of ns-3, got %v", nsList.Items)
+	}
+}
+
+func TestShardedStoreWatchAcrossShardsInRevisionOrder(t *testing.T) {
+	ss := newTestShardedSessionStore(t, 4)
+	first := createTestShardedSession(t, ss, "ns-0", "session")
+	w, err := ss.Watch(context.Background(), testKeyPrefix, apistorage.ListOptions{ResourceVersion: first.ResourceVersion, Recursive: true, Predicate: apistorage.Everything})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	expected := []string{}
+	for i := 1; i <= 8; i++ {
+		session := createTestShardedSession(t, ss, fmt.Sprintf("ns-%d", i), "session")
+		expected = append(expected, session.ResourceVersion)
+	}
+	got := []string{}
+	for _, e := range receiveWatchEvents(t, w, 8) {
+		got = append(got, e.Object.(*fornaxv1.ApplicationSession).ResourceVersion)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("expected events of all shards in revision order %v, got %v", expected, got)
+	}
+}
+
+func TestShardedStoreWatchGetEventSentBeforeWatcherAdded(t *testing.T) {
+	ss := newTestShardedSessionStore(t, 4)
+	pendingNamespace, sentNamespace := "", ""
+	for i := 0; i < 100 && len(sentNamespace) == 0; i++ {
+		namespace := fmt.Sprintf("ns-%d", i)
+		if len(pendingNamespace) == 0 {
+			pendingNamespace = namespace
+		} else if ss.ring.get(namespace) != ss.ring.get(pendingNamespace) {
+			sentNamespace = namespace
+		}
+	}
+	pendingShard, _ := ss.shardOfKey(testKeyPrefix + "/" + pendingNamespace)
+
+	// a shard hold a pending revision, a later revision sent by other shard is above watermark when watcher is added
+	rev, _, err := pendingShard.reserveRevAndSlot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := createTestShardedSession(t, ss, sentNamespace, "session")
+	w, err := ss.Watch(context.Background(), testKeyPrefix, apistorage.ListOptions{Recursive: true, Predicate: apistorage.Everything})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+	pendingShard.abortRev(rev)
+
+	events := receiveWatchEvents(t, w, 1)
+	if got := events[0].Object.(*fornaxv1.ApplicationSession); got.Namespace != sentNamespace || got.ResourceVersion != session.ResourceVersion {
+		t.Errorf("expected event of %s at revision %s, got %s at %s", sentNamespace, session.ResourceVersion, got.Namespace, got.ResourceVersion)
+	}
+}
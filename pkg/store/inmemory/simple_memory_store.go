@@ -31,6 +31,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"centaurusinfra.io/fornax-serverless/pkg/store"
+	"centaurusinfra.io/fornax-serverless/pkg/store/eventsink"
 	"centaurusinfra.io/fornax-serverless/pkg/util"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -46,22 +47,45 @@ import (
 type MemoryStore struct {
 	versioner        apistorage.Versioner
 	revmu            sync.RWMutex
+	revWriters       writerWaitTracker
 	stopChannel      chan interface{}
 	kvs              *objStoreMap
 	revSortedObjList *objList
 	groupResource    schema.GroupResource
 	grvKeyPrefix     string
+	watchersMu       sync.Mutex
 	watchers         []*memoryStoreWatcher
+	watchEventCache  *watchCache
+	compactedRev     uint64
+	config           store.ResourceStorageConfiguration
+	configChannel    chan store.ResourceStorageConfiguration
+	persistence      *memoryStorePersistence
+	index            *storeIndex
+	ttl              *objectTTL
+	history          *objectHistory
+	journal          *eventsink.Journal
+	// nanoseconds last sendEvents waited for watchers lock
+	sendLockWait int64
+	// highest revision committed into this store, protected by revmu
+	committedRev uint64
+	// revisions reserved but not committed into revSortedObjList yet
+	pendingRevs map[uint64]struct{}
+	// revisions committed but not sent to watchers yet, bookmark must not pass them
+	unsentRevs map[uint64]struct{}
+	// key prefixes reported in object count metrics
+	countedPrefixes map[string]bool
+	// receive sent events when store is a shard of ShardedMemoryStore
+	sink eventSink
 
 	keyFunc      func(obj runtime.Object) (string, error)
 	newFunc      func() runtime.Object
 	newListFunc  func() runtime.Object
 	getAttrsFunc apistorage.AttrFunc
 	triggerFuncs apistorage.IndexerFuncs
-	indexers     *cache.Indexers
 }
 
-// a workgroud to make sure memory revision will not move back, if machine clock does not rewind, need better option
+// a workgroud to make sure memory revision will not move back, if machine clock does not rewind,
+// InitRevisionGuard persist a high water mark to make sure revision does not move back when clock rewind across restarts
 var (
 	_MemoryRev = uint64(2<<61) + uint64(time.Now().UnixNano())
 )
@@ -73,12 +97,21 @@ const (
 	NilSlotShrinkLowThrehold        = 10000
 	NilSlotShrinkHighThrehold       = 20000
 	DefaultHouseKeepingInterval     = 60 * time.Second
+	DefaultWatchBookmarkInterval    = 30 * time.Second
 )
 
 // NewMemoryStore return a singleton storage.Interface for a groupResource
-func NewMemoryStore(ctx context.Context, groupResource schema.GroupResource, grvKeyPrefix string, newFunc func() runtime.Object, newListFunc func() runtime.Object) *MemoryStore {
+func NewMemoryStore(ctx context.Context, groupResource schema.GroupResource, grvKeyPrefix string, newFunc func() runtime.Object, newListFunc func() runtime.Object, config store.ResourceStorageConfiguration) *MemoryStore {
+	return newMemoryStore(ctx, groupResource, grvKeyPrefix, newFunc, newListFunc, config, nil)
+}
+
+func newMemoryStore(ctx context.Context, groupResource schema.GroupResource, grvKeyPrefix string, newFunc func() runtime.Object, newListFunc func() runtime.Object, config store.ResourceStorageConfiguration, sink eventSink) *MemoryStore {
 	key := groupResource.String()
-	klog.InfoS("New or Get a in memory store for", "resource", key)
+	klog.InfoS("New or Get a in memory store for", "resource", key, "config", config)
+	initSize := config.WatchCacheSize
+	if initSize <= DefaultObjRevListGrowThreashold {
+		initSize = DefaultObjRevListInitSize
+	}
 	si := &MemoryStore{
 		versioner:   store.APIObjectVersioner{},
 		revmu:       sync.RWMutex{},
@@ -87,19 +120,45 @@ func NewMemoryStore(ctx context.Context, groupResource schema.GroupResource, grv
 		newListFunc: newListFunc,
 		kvs:         &objStoreMap{mu: sync.RWMutex{}, kvs: map[string]objMapOrObj{}},
 		revSortedObjList: &objList{
-			objs:         make([]*objWithIndex, DefaultObjRevListInitSize),
+			objs:         make([]*objWithIndex, initSize),
 			lastObjIndex: 0,
 		},
-		grvKeyPrefix:  grvKeyPrefix, // resource key prefix, every key should start with it
-		groupResource: groupResource,
-		watchers:      []*memoryStoreWatcher{},
+		grvKeyPrefix:    grvKeyPrefix, // resource key prefix, every key should start with it
+		groupResource:   groupResource,
+		watchers:        []*memoryStoreWatcher{},
+		watchEventCache: newWatchCache(config.WatchEventCacheSize),
+		config:          config,
+		configChannel:   make(chan store.ResourceStorageConfiguration, 1),
+		index:           newStoreIndex(),
+		ttl:             newObjectTTL(),
+		pendingRevs:     map[uint64]struct{}{},
+		unsentRevs:      map[uint64]struct{}{},
+		countedPrefixes: map[string]bool{},
+		sink:            sink,
+	}
+	if config.HistoryRevisions > 0 {
+		si.history = newObjectHistory(config.HistoryRevisions)
 	}
-	ticker := time.NewTicker(DefaultHouseKeepingInterval)
+	store.SetWatchLatencyBudget(key, time.Duration(config.WatchLatencyBudgetMillis)*time.Millisecond)
+	go si.ttl.run(ctx, si.stopChannel, si.expireObject)
+	ticker := time.NewTicker(si.houseKeepingInterval())
+	bookmarkTicker := time.NewTicker(DefaultWatchBookmarkInterval)
 	go func() {
+		defer bookmarkTicker.Stop()
 		for {
 			select {
 			case <-ticker.C:
 				si.houseKeeping()
+				si.snapshotIfWALTooLarge()
+			case <-bookmarkTicker.C:
+				si.sendBookmark()
+			case config := <-si.configChannel:
+				si.lockRev()
+				si.config.CompactionIntervalSeconds = config.CompactionIntervalSeconds
+				si.config.RetentionSlots = config.RetentionSlots
+				si.config.CompactionMemoryThresholdMB = config.CompactionMemoryThresholdMB
+				si.revmu.Unlock()
+				ticker.Reset(si.houseKeepingInterval())
 			case <-si.stopChannel:
 				return
 			case <-ctx.Done():
@@ -110,6 +169,31 @@ func NewMemoryStore(ctx context.Context, groupResource schema.GroupResource, grv
 	return si
 }
 
+// ApplyConfiguration hot reload store configuration, only compaction interval and retention are applied,
+// backend and watch cache size change require restart
+func (ms *MemoryStore) ApplyConfiguration(config store.ResourceStorageConfiguration) {
+	klog.InfoS("Apply storage configuration", "resource", ms.groupResource.String(), "config", config)
+	select {
+	case ms.configChannel <- config:
+	default:
+		// a pending configuration not applied yet, replace it
+		select {
+		case <-ms.configChannel:
+		default:
+		}
+		ms.configChannel <- config
+	}
+}
+
+func (ms *MemoryStore) houseKeepingInterval() time.Duration {
+	ms.revmu.RLock()
+	defer ms.revmu.RUnlock()
+	if ms.config.CompactionIntervalSeconds > 0 {
+		return time.Duration(ms.config.CompactionIntervalSeconds) * time.Second
+	}
+	return DefaultHouseKeepingInterval
+}
+
 // this is ugly, just want to let compatible with k8s api server store initialization
 func (ms *MemoryStore) CompleteWithFunctions(
 	keyFunc func(obj runtime.Object) (string, error),
@@ -124,21 +208,104 @@ func (ms *MemoryStore) CompleteWithFunctions(
 	ms.newListFunc = newListFunc
 	ms.getAttrsFunc = getAttrsFunc
 	ms.triggerFuncs = triggerFuncs
-	ms.indexers = indexers
+	if len(triggerFuncs) > 0 {
+		ms.revmu.RLock()
+		ms.index.addFieldFuncs(triggerFuncs, ms.committedObjs)
+		ms.revmu.RUnlock()
+	}
+	if indexers != nil {
+		// store is shared, indexers could be completed again, only add indexers not registered yet
+		newIndexers := cache.Indexers{}
+		for name, indexFunc := range *indexers {
+			if !ms.index.hasIndex(name) {
+				newIndexers[name] = indexFunc
+			}
+		}
+		return ms.AddIndexers(newIndexers)
+	}
 	return nil
 }
 
+const (
+	compactionTriggerInterval = "interval"
+	// CompactionTriggerApi is trigger of compaction requested from debug endpoint
+	CompactionTriggerApi = "api"
+	// CompactionTriggerMemoryPressure is trigger of compaction forced when go heap is above memory threshold
+	CompactionTriggerMemoryPressure = "memory_pressure"
+)
+
 func (ms *MemoryStore) houseKeeping() {
-	ms.revmu.Lock()
+	ms.shrinkObjList(false, compactionTriggerInterval)
+	if ms.history != nil {
+		ms.history.prune(time.Now().Add(-DefaultDeletedHistoryRetention))
+	}
+}
+
+// Compact shrink revSortedObjList immediately regardless of empty slot threshold, return number of removed slots,
+// memory pressure is checked once for all stores by store factory, which compact stores and give memory back to os
+func (ms *MemoryStore) Compact(trigger string) int {
+	return ms.shrinkObjList(true, trigger)
+}
+
+// shrinkObjList remove nil and deleted slots from revSortedObjList when empty slots are more than high threshold or force is true,
+// it keep low threshold empty slots, return number of removed slots,
+// it's skipped when there are reserved slots not committed yet, they are nil and writers hold their index, next check shrink list
+func (ms *MemoryStore) shrinkObjList(force bool, trigger string) int {
+	ms.lockRev()
 	defer ms.revmu.Unlock()
-	klog.InfoS("Shrink revSortedObjList before", "size", ms.revSortedObjList.Len(), "last index", ms.revSortedObjList.lastObjIndex)
+	if len(ms.pendingRevs) > 0 {
+		klog.V(5).InfoS("Defer shrinking revSortedObjList, writes are in progress", "resource", ms.groupResource, "pending", len(ms.pendingRevs), "trigger", trigger)
+		return 0
+	}
 	st := time.Now().UnixMicro()
 	c, _ := ms.kvs.count([]string{})
-	if ms.revSortedObjList.lastObjIndex > uint64(c+NilSlotShrinkHighThrehold) {
-		ms.revSortedObjList.shrink(uint64(c + NilSlotShrinkLowThrehold))
+	lowThreshold, highThreshold := int64(NilSlotShrinkLowThrehold), int64(NilSlotShrinkHighThrehold)
+	if ms.config.RetentionSlots > 0 {
+		lowThreshold, highThreshold = int64(ms.config.RetentionSlots), int64(2*ms.config.RetentionSlots)
+	}
+	removed := 0
+	if ms.revSortedObjList.lastObjIndex > uint64(c+highThreshold) || (force && ms.revSortedObjList.lastObjIndex > uint64(c+lowThreshold)) {
+		klog.InfoS("Shrink revSortedObjList before", "resource", ms.groupResource, "size", ms.revSortedObjList.Len(), "last index", ms.revSortedObjList.lastObjIndex, "trigger", trigger)
+		lastObjIndex := ms.revSortedObjList.lastObjIndex
+		if compactedRev := ms.revSortedObjList.shrink(uint64(c + lowThreshold)); compactedRev > 0 {
+			ms.compact(compactedRev)
+		}
+		removed = int(lastObjIndex - ms.revSortedObjList.lastObjIndex)
+		compactions.WithLabelValues(ms.groupResource.String(), trigger).Inc()
+		et := time.Now().UnixMicro()
+		klog.InfoS("Shrink revSortedObjList after", "resource", ms.groupResource, "size", ms.revSortedObjList.Len(), "last index", ms.revSortedObjList.lastObjIndex, "removed", removed, "took-micro", et-st)
+	}
+	ms.updateSlotMetrics(c)
+	ms.updateObjectCountMetrics()
+	return removed
+}
+
+// updateSlotMetrics report slot utilization of revSortedObjList, used slots are slots holding live objects
+func (ms *MemoryStore) updateSlotMetrics(liveObjs int64) {
+	resource := ms.groupResource.String()
+	slots := float64(ms.revSortedObjList.lastObjIndex + 1)
+	objListSlots.WithLabelValues(resource).Set(float64(ms.revSortedObjList.Len()))
+	objListUsedSlots.WithLabelValues(resource).Set(float64(liveObjs))
+	objListSlotUtilization.WithLabelValues(resource).Set(float64(liveObjs) / slots)
+}
+
+// compact move compaction point forward to rev, watch or list from a revision older than compaction point get resource expired error,
+// since deleted objects before it were removed and can not be returned as deleted events anymore
+func (ms *MemoryStore) compact(rev uint64) {
+	for {
+		cur := atomic.LoadUint64(&ms.compactedRev)
+		if cur >= rev || atomic.CompareAndSwapUint64(&ms.compactedRev, cur, rev) {
+			return
+		}
 	}
-	et := time.Now().UnixMicro()
-	klog.InfoS("shrink revSortedObjList after", "size", ms.revSortedObjList.Len(), "last index", ms.revSortedObjList.lastObjIndex, "took-micro", et-st)
+}
+
+// checkCompactedRev return resource expired error like etcd if rev is older than compaction point
+func (ms *MemoryStore) checkCompactedRev(rev uint64) error {
+	if compactedRev := atomic.LoadUint64(&ms.compactedRev); rev > 0 && rev < compactedRev {
+		return apierrors.NewResourceExpired(fmt.Sprintf("too old resource version: %d (%d)", rev, compactedRev))
+	}
+	return nil
 }
 
 func (ms *MemoryStore) getKey(obj runtime.Object) (string, error) {
@@ -150,9 +317,14 @@ func (ms *MemoryStore) getKey(obj runtime.Object) (string, error) {
 	}
 }
 
+func (ms *MemoryStore) GroupResource() schema.GroupResource {
+	return ms.groupResource
+}
+
 // Stop cleanup memory
 func (ms *MemoryStore) Stop() error {
 	ms.stopChannel <- "stop"
+	ms.closePersistence()
 	return nil
 }
 
@@ -162,13 +334,10 @@ func (ms *MemoryStore) Count(key string) (int64, error) {
 	return count, err
 }
 
-// Create implements storage.Interface
-func (ms *MemoryStore) Create(ctx context.Context, key string, obj runtime.Object, out runtime.Object, ttl uint64) error {
-	st := time.Now().UnixMicro()
-	defer func() {
-		et := time.Now().UnixMicro()
-		klog.InfoS("Memory store create object", "key", key, "took-micro", et-st)
-	}()
+// Create implements storage.Interface, object created with ttl seconds is deleted automatically when ttl expire
+func (ms *MemoryStore) Create(ctx context.Context, key string, obj runtime.Object, out runtime.Object, ttl uint64) (err error) {
+	st := time.Now()
+	defer func() { ms.observeOperation(operationCreate, st, err) }()
 	outVal, err := conversion.EnforcePtr(out)
 	if err != nil {
 		return err
@@ -182,6 +351,9 @@ func (ms *MemoryStore) Create(ctx context.Context, key string, obj runtime.Objec
 	if o := ms.kvs.get(keys); o != nil {
 		return apistorage.NewKeyExistsError(key, 0)
 	} else {
+		if err := ms.validate(ctx, obj, nil); err != nil {
+			return err
+		}
 		rev, index, err := ms.reserveRevAndSlot()
 		if err != nil {
 			return err
@@ -197,11 +369,9 @@ func (ms *MemoryStore) Create(ctx context.Context, key string, obj runtime.Objec
 		}
 		err = ms.kvs.put(keys, objWi, 0)
 		if err != nil {
+			ms.abortRev(rev)
 			return err
 		}
-		ms.revSortedObjList.objs[index] = objWi
-		outVal.Set(reflect.ValueOf(newObj).Elem())
-
 		event := &objEvent{
 			key:       key,
 			obj:       newObj.DeepCopyObject(),
@@ -210,6 +380,12 @@ func (ms *MemoryStore) Create(ctx context.Context, key string, obj runtime.Objec
 			isDeleted: false,
 			isCreated: true,
 		}
+		ms.commitSlot(nil, objWi, event)
+		if ttl > 0 {
+			// ttl is not persisted, object restored from wal after restart does not expire
+			ms.ttl.add(key, objectUID(newObj), time.Duration(ttl)*time.Second)
+		}
+		outVal.Set(reflect.ValueOf(newObj).Elem())
 		ms.sendEvent(event)
 	}
 	return nil
@@ -218,12 +394,9 @@ func (ms *MemoryStore) Create(ctx context.Context, key string, obj runtime.Objec
 // Delete implements storage.Interface, deleted object is removed from kv map, but still keep in revisonedObjList,
 // deleted object is removed from old poistion in list but append to end of list just like a updated object,
 // so, it ensure watcher can get this deleted obj event if deleted object just happen after watcher's list call and before watch call
-func (ms *MemoryStore) Delete(ctx context.Context, key string, out runtime.Object, preconditions *apistorage.Preconditions, validateDeletion apistorage.ValidateObjectFunc, cachedExistingObject runtime.Object) error {
-	st := time.Now().UnixMicro()
-	defer func() {
-		et := time.Now().UnixMicro()
-		klog.InfoS("Memory store delete object", "key", key, "took-micro", et-st)
-	}()
+func (ms *MemoryStore) Delete(ctx context.Context, key string, out runtime.Object, preconditions *apistorage.Preconditions, validateDeletion apistorage.ValidateObjectFunc, cachedExistingObject runtime.Object) (err error) {
+	st := time.Now()
+	defer func() { ms.observeOperation(operationDelete, st, err) }()
 	outVal, err := conversion.EnforcePtr(out)
 	if err != nil {
 		return err
@@ -240,60 +413,131 @@ func (ms *MemoryStore) Delete(ctx context.Context, key string, out runtime.Objec
 	if existingObj := ms.kvs.get(keys); existingObj == nil {
 		return apistorage.NewKeyNotFoundError(key, 0)
 	} else {
-		currObj := existingObj.obj.DeepCopyObject()
-		_, err := store.GetObjectResourceVersion(currObj)
+		currObj, event, err := ms.deleteExistingObj(ctx, key, existingObj, preconditions, validateDeletion)
 		if err != nil {
-			return apistorage.NewInternalError(err.Error())
+			return err
 		}
+		outVal.Set(reflect.ValueOf(currObj).Elem())
+		ms.sendEvent(event)
+	}
+	return nil
+}
 
-		if preconditions != nil {
-			if err := preconditions.Check(key, currObj); err != nil {
-				return err
-			}
+// deleteExistingObj remove object from kv map and append a deleted object to end of revSortedObjList,
+// return object state before deletion and delete event, caller send event
+func (ms *MemoryStore) deleteExistingObj(ctx context.Context, key string, existingObj *objWithIndex, preconditions *apistorage.Preconditions, validateDeletion apistorage.ValidateObjectFunc) (runtime.Object, *objEvent, error) {
+	currObj := existingObj.obj.DeepCopyObject()
+	currRev, err := store.GetObjectResourceVersion(currObj)
+	if err != nil {
+		return nil, nil, apistorage.NewInternalError(err.Error())
+	}
+
+	if preconditions != nil {
+		if err := preconditions.Check(key, currObj); err != nil {
+			return nil, nil, err
 		}
+	}
 
-		if validateDeletion != nil {
-			if err := validateDeletion(ctx, currObj); err != nil {
-				return err
-			}
+	if validateDeletion != nil {
+		if err := validateDeletion(ctx, currObj); err != nil {
+			return nil, nil, err
 		}
+	}
 
-		rev, index, err := ms.reserveRevAndSlot()
-		if err != nil {
-			return err
+	rev, index, err := ms.reserveRevAndSlot()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deletedObj := currObj.DeepCopyObject()
+	store.SetObjectResourceVersion(deletedObj, rev)
+	deletedObjWi := &objWithIndex{
+		key:     key,
+		obj:     deletedObj,
+		index:   index,
+		deleted: true,
+	}
+	err = ms.kvs.del(strings.Split(key, "/"))
+	if err != nil {
+		ms.abortRev(rev)
+		return nil, nil, err
+	}
+	event := &objEvent{
+		key:       key,
+		obj:       nil,
+		oldObj:    deletedObj.DeepCopyObject(),
+		rev:       rev,
+		isDeleted: true,
+		isCreated: false,
+		prevRev:   currRev,
+	}
+	ms.commitSlot(existingObj, deletedObjWi, event)
+	ms.ttl.remove(key)
+	return currObj, event, nil
+}
+
+// DeleteCollection implements FornaxStorage, delete all objects under key prefix recursively, deleted objects are returned in listObj,
+// object failed validateDeletion is kept, delete events are sent to watchers together after all objects are deleted
+func (ms *MemoryStore) DeleteCollection(ctx context.Context, key string, validateDeletion apistorage.ValidateObjectFunc, listObj runtime.Object) (err error) {
+	st := time.Now()
+	defer func() { ms.observeOperation(operationDeleteCollection, st, err) }()
+	listPtr, err := meta.GetItemsPtr(listObj)
+	if err != nil {
+		return err
+	}
+	listRetVal, err := conversion.EnforcePtr(listPtr)
+	if err != nil || listRetVal.Kind() != reflect.Slice {
+		return fmt.Errorf("need ptr to slice: %v", err)
+	}
+	if !strings.HasSuffix(key, "/") {
+		key += "/"
+	}
+
+	keys := []string{}
+	func() {
+		ms.revmu.RLock()
+		defer ms.revmu.RUnlock()
+		objBufferLen := atomic.LoadUint64(&ms.revSortedObjList.lastObjIndex)
+		for i := uint64(0); i <= objBufferLen && i < uint64(ms.revSortedObjList.Len()); i++ {
+			if v := ms.revSortedObjList.objs[i]; v != nil && !v.deleted && strings.HasPrefix(v.key, key) {
+				keys = append(keys, v.key)
+			}
 		}
+	}()
 
-		deletedObj := currObj.DeepCopyObject()
-		store.SetObjectResourceVersion(deletedObj, rev)
-		deletedObjWi := &objWithIndex{
-			key:     key,
-			obj:     deletedObj,
-			index:   index,
-			deleted: true,
+	events := []*objEvent{}
+	deleteErrors := []error{}
+	for _, k := range keys {
+		existingObj := ms.kvs.get(strings.Split(k, "/"))
+		if existingObj == nil {
+			// deleted by others
+			continue
 		}
-		err = ms.kvs.del(keys)
+		currObj, event, err := ms.deleteExistingObj(ctx, k, existingObj, nil, validateDeletion)
 		if err != nil {
-			return err
+			deleteErrors = append(deleteErrors, err)
+			continue
 		}
-		ms.revSortedObjList.objs[existingObj.index] = nil
-		ms.revSortedObjList.objs[index] = deletedObjWi
-		outVal.Set(reflect.ValueOf(currObj).Elem())
+		rv, _ := store.GetObjectResourceVersion(currObj)
+		store.AppendListItem(listRetVal, currObj, rv, apistorage.Everything)
+		events = append(events, event)
+	}
+	ms.sendEvents(events)
+	klog.InfoS("Memory store delete collection", "key", key, "deleted", len(events), "failed", len(deleteErrors))
 
-		event := &objEvent{
-			key:       key,
-			obj:       nil,
-			oldObj:    deletedObj.DeepCopyObject(),
-			rev:       rev,
-			isDeleted: true,
-			isCreated: false,
-		}
-		ms.sendEvent(event)
+	if err := store.UpdateList(listObj, atomic.LoadUint64(&_MemoryRev), "", nil); err != nil {
+		return err
+	}
+	if len(deleteErrors) > 0 {
+		return fmt.Errorf("failed to delete %d objects under %s, errors=%v", len(deleteErrors), key, deleteErrors)
 	}
 	return nil
 }
 
 // Get implements storage.Interface
-func (ms *MemoryStore) Get(ctx context.Context, key string, opts apistorage.GetOptions, out runtime.Object) error {
+func (ms *MemoryStore) Get(ctx context.Context, key string, opts apistorage.GetOptions, out runtime.Object) (err error) {
+	st := time.Now()
+	defer func() { ms.observeOperation(operationGet, st, err) }()
 	outVal, err := conversion.EnforcePtr(out)
 	if err != nil {
 		return fmt.Errorf("unable to convert output object to pointer: %v", err)
@@ -321,12 +565,13 @@ func (ms *MemoryStore) Get(ctx context.Context, key string, opts apistorage.GetO
 	return nil
 }
 
-// GetList implements k8s storage.Interface,
-// if provided Continue key and revision is not nil, use Continue key to find the object and use this object's index in revisonedObjList as starting position.
-// if this object is deleted already, use Continue rv to search revisonedObjList to do a binary search to find starting position in revisonedObjList
-// if no Continue key provided, use provided ResourceVersion to do a binary search to find find starting positon in revisonedObjList
-// and iterate revisonedObjList from starting position to return a list of object, ignore obj which is marked as deleted.
-func (ms *MemoryStore) GetList(ctx context.Context, key string, opts apistorage.ListOptions, listObj runtime.Object) error {
+// GetList implements k8s storage.Interface, recursive list is a consistent view of objects as of one revision sorted by key,
+// latest committed revision is used unless ResourceVersionMatch is Exact, NotOlderThan only require list revision is not older than ResourceVersion,
+// a page is continued from key after last returned key at revision of first page, just like etcd,
+// so, objects created or changed between pages are not returned, and deleted objects are still returned as of list revision
+func (ms *MemoryStore) GetList(ctx context.Context, key string, opts apistorage.ListOptions, listObj runtime.Object) (err error) {
+	st := time.Now()
+	defer func() { ms.observeOperation(operationGetList, st, err) }()
 	listPtr, err := meta.GetItemsPtr(listObj)
 	if err != nil {
 		return err
@@ -348,127 +593,88 @@ func (ms *MemoryStore) GetList(ctx context.Context, key string, opts apistorage.
 	resourceVersion := opts.ResourceVersion
 	match := opts.ResourceVersionMatch
 	pred := opts.Predicate
-	var fromRV *uint64
-	if len(resourceVersion) > 0 {
-		parsedRV, err := store.ParseResourceVersion(resourceVersion)
-		if err != nil {
-			return apierrors.NewBadRequest(fmt.Sprintf("invalid resource version: %v", err))
-		}
-		fromRV = &parsedRV
-	}
 
-	// returnedRV is returned back to client to indicate the last revision of returned list, intialized it as 1
-	returnedRV := uint64(1)
-	// withRV is used to binary search in revisonedObjList to get starting index to return object list
-	withRV := uint64(0)
-	startingIndex := uint64(0)
-	var continueKey string
+	// listRev is revision of list, 0 means latest committed revision, minRV is minimum revision allowed by NotOlderThan
+	listRev := uint64(0)
+	minRV := uint64(0)
+	continueKey := ""
 	switch {
 	case len(pred.Continue) > 0:
 		if len(resourceVersion) > 0 && resourceVersion != "0" {
 			return apierrors.NewBadRequest("specifying resource version is not allowed when using continue")
 		}
-
 		var continueRV int64
 		continueKey, continueRV, err = store.DecodeContinue(pred.Continue, keyPrefix)
 		if err != nil {
 			return apierrors.NewBadRequest(fmt.Sprintf("invalid continue token: %v", err))
 		}
-		if continueRV > 0 {
-			// withRV is set to continueRV if provided
-			withRV = uint64(continueRV)
-			returnedRV = withRV
-		} else if continueRV == 0 {
+		if continueRV <= 0 {
 			return apierrors.NewBadRequest("0 continue resource version is not allowed when using continue")
 		}
-		// use continueKey to find object from kv map,
-		// if object exist, check if its rv is still same as continue rv,
-		// then use this object index as startIndex to search revisonedObjList to return object list
-		// if object's revision is not same continueRV or object does not exist anymore(deleted)
-		// use continue rv to do binary search in revisonedObjList to find starting index
-		continueKeys := strings.Split(continueKey, "/")
-		if obj := ms.kvs.get(continueKeys); obj != nil {
-			objRV, _ := store.GetObjectResourceVersion(obj.obj)
-			if objRV == withRV {
-				startingIndex = obj.index
-			} else {
-				startingIndex = ms.binarySearchInObjList(withRV)
-			}
+		listRev = uint64(continueRV)
+	case len(resourceVersion) > 0:
+		parsedRV, err := store.ParseResourceVersion(resourceVersion)
+		if err != nil {
+			return apierrors.NewBadRequest(fmt.Sprintf("invalid resource version: %v", err))
 		}
-	default:
-		// no continue key, but with resource version, start to search from provided resource version
-		if fromRV != nil && *fromRV > 0 {
-			withRV = *fromRV
-			returnedRV = *fromRV
-			startingIndex = ms.binarySearchInObjList(*fromRV)
+		switch match {
+		case metav1.ResourceVersionMatchExact:
+			if parsedRV == 0 {
+				return apierrors.NewBadRequest("0 resource version is not allowed when using exact match")
+			}
+			listRev = parsedRV
+		case metav1.ResourceVersionMatchNotOlderThan, "":
+			minRV = parsedRV
+		default:
+			return fmt.Errorf("unknown ResourceVersionMatch value: %v", match)
 		}
 	}
 
-	// can not find valid searching index from list, return empty list
-	if startingIndex >= uint64(ms.revSortedObjList.Len()) {
-		return store.UpdateList(listObj, uint64(returnedRV), "", nil)
+	// selector is evaluated in store, object not matched is not appended into list
+	filter := ms.newListFilter(pred)
+	items, returnedRV, err := ms.listAtRev(keyPrefix, listRev, filter)
+	if err != nil {
+		return err
+	}
+	if minRV > returnedRV {
+		return apistorage.NewTooLargeResourceVersionError(minRV, returnedRV, 1)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].key < items[j].key })
+
+	startingIndex := 0
+	if len(continueKey) > 0 {
+		startingIndex = sort.Search(len(items), func(i int) bool { return items[i].key > continueKey })
 	}
 
-	// range sorted revision list from searching index until meet requested limit or there are no more items
 	limit := int64(math.MaxInt64)
 	if pred.Limit > 0 {
 		limit = pred.Limit
 	}
-	remainingItemCount := int64(0)
 	hasMore := false
+	remainingItemCount := int64(0)
 	lastKey := ""
-	lastRev := withRV
-	err = func() error {
-		ms.revmu.RLock()
-		defer ms.revmu.RUnlock()
-		objBufferLen := atomic.LoadUint64(&ms.revSortedObjList.lastObjIndex)
-		for i := startingIndex; i <= objBufferLen; i++ {
-			v := ms.revSortedObjList.objs[i]
-			// deleted object are also in list, ignore it for GetList call, but deleted object will be returned in watch call
-			if v != nil && v.deleted == false {
-				rv, _ := store.GetObjectResourceVersion(v.obj)
-				lastKey = v.key
-				lastRev = rv
-				if !strings.HasPrefix(lastKey, keyPrefix) {
-					continue
-				}
-				switch match {
-				case metav1.ResourceVersionMatchNotOlderThan:
-					if rv >= withRV {
-						store.AppendListItem(listRetVal, v.obj, rv, pred)
-					}
-				case metav1.ResourceVersionMatchExact:
-					if rv > withRV {
-						store.AppendListItem(listRetVal, v.obj, rv, pred)
-					}
-				case "":
-					if rv > withRV {
-						store.AppendListItem(listRetVal, v.obj, rv, pred)
-					}
-				default:
-					return fmt.Errorf("unknown ResourceVersionMatch value: %v", match)
-				}
-			}
-			// got enough items, check if there are still items in list
-			if int64(listRetVal.Len()) == limit {
-				if i < objBufferLen-1 {
-					remainingItemCount = int64(objBufferLen - 1 - i)
-					hasMore = true
-				}
-				break
-			}
+	for i := startingIndex; i < len(items); i++ {
+		v := items[i]
+		if !filter.matches(v.obj) {
+			continue
 		}
-		return nil
-	}()
-	if err != nil {
-		return err
+		if int64(listRetVal.Len()) == limit {
+			hasMore = true
+			remainingItemCount = int64(len(items) - i)
+			break
+		}
+		if err := store.AppendListItem(listRetVal, v.obj, v.rev, apistorage.Everything); err != nil {
+			return err
+		}
+		lastKey = v.key
 	}
 
 	if hasMore {
-		continueToken, err := store.EncodeContinue(lastKey, keyPrefix, lastRev)
+		continueToken, err := store.EncodeContinue(lastKey, keyPrefix, returnedRV)
 		if err != nil {
 			return err
 		}
+		// remaining count is only exact when every object match
 		if pred.Empty() {
 			return store.UpdateList(listObj, returnedRV, continueToken, &remainingItemCount)
 		} else {
@@ -482,12 +688,9 @@ func (ms *MemoryStore) GetList(ctx context.Context, key string, opts apistorage.
 
 // GuaranteedUpdate implements k8s storage.Interface, updated object will get an new revision,
 // its previous positon in revSortedObjList is set to nil, updated object is appended to end of revSortedObjList
-func (ms *MemoryStore) GuaranteedUpdate(ctx context.Context, key string, out runtime.Object, ignoreNotFound bool, preconditions *apistorage.Preconditions, tryUpdate apistorage.UpdateFunc, cachedExistingObject runtime.Object) error {
-	st := time.Now().UnixMicro()
-	defer func() {
-		et := time.Now().UnixMicro()
-		klog.InfoS("Memory store update object", "key", key, "took-micro", et-st)
-	}()
+func (ms *MemoryStore) GuaranteedUpdate(ctx context.Context, key string, out runtime.Object, ignoreNotFound bool, preconditions *apistorage.Preconditions, tryUpdate apistorage.UpdateFunc, cachedExistingObject runtime.Object) (err error) {
+	st := time.Now()
+	defer func() { ms.observeOperation(operationGuaranteedUpdate, st, err) }()
 	outVal, err := conversion.EnforcePtr(out)
 	if err != nil {
 		return fmt.Errorf("unable to convert output object to pointer: %v", err)
@@ -527,6 +730,9 @@ func (ms *MemoryStore) GuaranteedUpdate(ctx context.Context, key string, out run
 		if err != nil {
 			return err
 		}
+		if err := ms.validate(ctx, ret, currObj); err != nil {
+			return err
+		}
 
 		// bump updated object revsion
 		rev, index, err := ms.reserveRevAndSlot()
@@ -534,6 +740,7 @@ func (ms *MemoryStore) GuaranteedUpdate(ctx context.Context, key string, out run
 			return err
 		}
 		if err := store.SetObjectResourceVersion(ret, rev); err != nil {
+			ms.abortRev(rev)
 			return err
 		}
 
@@ -546,20 +753,19 @@ func (ms *MemoryStore) GuaranteedUpdate(ctx context.Context, key string, out run
 		}
 		err = ms.kvs.put(keys, newObjWi, currRv)
 		if err != nil {
+			ms.abortRev(rev)
 			return err
 		}
-		ms.revSortedObjList.objs[curObjWi.index] = nil
-		ms.revSortedObjList.objs[newObjWi.index] = newObjWi
-		outVal.Set(reflect.ValueOf(ret).Elem())
-
 		event := &objEvent{
 			key:       key,
-			obj:       out.DeepCopyObject(),
+			obj:       ret.DeepCopyObject(),
 			oldObj:    currObj,
 			rev:       rev,
 			isDeleted: false,
 			isCreated: false,
 		}
+		ms.commitSlot(curObjWi, newObjWi, event)
+		outVal.Set(reflect.ValueOf(ret).Elem())
 		ms.sendEvent(event)
 	}
 	return nil
@@ -628,6 +834,9 @@ func (ms *MemoryStore) CreateOrReplace(ctx context.Context, key string, objToCre
 			return apistorage.NewInternalError(err.Error())
 		}
 		newObj := objToCreate.DeepCopyObject()
+		if err := ms.validate(ctx, newObj, currObj); err != nil {
+			return err
+		}
 		rev, index, err := ms.reserveRevAndSlot()
 		if err != nil {
 			return err
@@ -642,11 +851,9 @@ func (ms *MemoryStore) CreateOrReplace(ctx context.Context, key string, objToCre
 
 		err = ms.kvs.put(keys, newObjWi, currRv)
 		if err != nil {
+			ms.abortRev(rev)
 			return err
 		}
-		ms.revSortedObjList.objs[curObjWi.index] = nil
-		ms.revSortedObjList.objs[index] = newObjWi
-		outVal.Set(reflect.ValueOf(newObj).Elem())
 		event := &objEvent{
 			key:       key,
 			obj:       newObj.DeepCopyObject(),
@@ -655,6 +862,8 @@ func (ms *MemoryStore) CreateOrReplace(ctx context.Context, key string, objToCre
 			isDeleted: false,
 			isCreated: false,
 		}
+		ms.commitSlot(curObjWi, newObjWi, event)
+		outVal.Set(reflect.ValueOf(newObj).Elem())
 		ms.sendEvent(event)
 	}
 
@@ -690,22 +899,37 @@ func (ms *MemoryStore) EnsureUpdateAndDelete(ctx context.Context, key string, ig
 	return nil
 }
 
-func (ms *MemoryStore) watch(ctx context.Context, key string, opts apistorage.ListOptions, withOldObj bool) (*memoryStoreWatcher, error) {
+func (ms *MemoryStore) watch(ctx context.Context, key string, opts apistorage.ListOptions, withOldObj bool) (_ *memoryStoreWatcher, err error) {
+	st := time.Now()
+	defer func() { ms.observeOperation(operationWatch, st, err) }()
 	rev, err := store.ParseResourceVersion(opts.ResourceVersion)
 	if err != nil {
 		return nil, err
 	}
-
 	// start to watch new events
-	watcher := NewMemoryStoreWatcher(ctx, key, opts)
+	watcher := NewMemoryStoreWatcher(ctx, key, opts, ms.groupResource.String(), ms.config.WatcherQueueSize, ms.config.SlowWatcherPolicy)
+	watcher.sendLockWait = ms.lastSendLockWait
+	ms.watchersMu.Lock()
 	ms.watchers = append(ms.watchers, watcher)
+	watcherCounts.WithLabelValues(ms.groupResource.String()).Set(float64(len(ms.watchers)))
+	ms.watchersMu.Unlock()
 
 	objEvents := []*objEvent{}
 	if rev > 1 {
-		objEvents, err = ms.getObjEventsAfterRev(key, rev, opts)
-		// find all obj event which are greater than passed rev and call watcher to run with these existing events
-		if err != nil {
-			return nil, err
+		// replay missed events from watch event cache if rev is still covered by cache, cache keep deleted events removed by compaction,
+		// otherwise find all obj which are greater than passed rev and send them as created or deleted events
+		found := false
+		objEvents, found = ms.watchEventCache.getObjEventsAfterRev(watcher.keyPrefix, rev, opts)
+		if !found {
+			if err := ms.checkCompactedRev(rev); err != nil {
+				watcher.abort()
+				return nil, err
+			}
+			objEvents, err = ms.getObjEventsAfterRev(key, rev, opts)
+			if err != nil {
+				watcher.abort()
+				return nil, err
+			}
 		}
 	} else {
 		rev = atomic.LoadUint64(&_MemoryRev)
@@ -790,25 +1014,149 @@ func (ms *MemoryStore) binarySearchInObjList(rv uint64) uint64 {
 	return index
 }
 
-// send objEvent to watchers and remove watcher who has failure to receive event
+// send objEvent to watchers' queues and remove stopped watchers
 func (ms *MemoryStore) sendEvent(event *objEvent) {
-	watchers := []*memoryStoreWatcher{}
+	ms.sendEvents([]*objEvent{event})
+}
+
+// sendEvents send a batch of events in order, watchers are iterated once for whole batch
+func (ms *MemoryStore) sendEvents(events []*objEvent) {
+	if len(events) == 0 {
+		return
+	}
+	if ms.persistence != nil {
+		ms.logEvents(events)
+	}
+	ms.observeEvents(events)
+	func() {
+		st := time.Now()
+		ms.watchersMu.Lock()
+		defer ms.watchersMu.Unlock()
+		atomic.StoreInt64(&ms.sendLockWait, int64(time.Since(st)))
+		watchers := []*memoryStoreWatcher{}
+		for _, v := range ms.watchers {
+			if !v.stopped() {
+				// enqueue never block, a slow watcher can not stall store
+				for _, event := range events {
+					v.enqueue(event)
+				}
+				watchers = append(watchers, v)
+			}
+		}
+		ms.watchers = watchers
+		watcherCounts.WithLabelValues(ms.groupResource.String()).Set(float64(len(ms.watchers)))
+		if ms.sink != nil {
+			ms.sink.addEvents(events)
+		}
+		if ms.journal != nil {
+			ms.journal.Add(ms.journalEvents(events)...)
+		}
+	}()
+
+	ms.lockRev()
+	for _, event := range events {
+		delete(ms.unsentRevs, event.rev)
+	}
+	ms.revmu.Unlock()
+	if ms.sink != nil {
+		ms.sink.revReleased()
+	}
+}
+
+func (ms *MemoryStore) lastSendLockWait() time.Duration {
+	return time.Duration(atomic.LoadInt64(&ms.sendLockWait))
+}
+
+// SetEventJournal export events sent to watchers to a message bus, events are added in same order as watchers receive them
+func (ms *MemoryStore) SetEventJournal(journal *eventsink.Journal) {
+	ms.watchersMu.Lock()
+	defer ms.watchersMu.Unlock()
+	ms.journal = journal
+}
+
+func (ms *MemoryStore) journalEvents(events []*objEvent) []*eventsink.Event {
+	now := time.Now()
+	journalEvents := make([]*eventsink.Event, 0, len(events))
+	for _, v := range events {
+		event := &eventsink.Event{
+			Type:      watch.Modified,
+			Resource:  ms.groupResource.String(),
+			Key:       v.key,
+			Revision:  v.rev,
+			Timestamp: now,
+			Object:    v.obj,
+			OldObject: v.oldObj,
+		}
+		switch {
+		case v.isCreated:
+			event.Type = watch.Added
+		case v.isDeleted:
+			event.Type = watch.Deleted
+			event.Object = nil
+		}
+		journalEvents = append(journalEvents, event)
+	}
+	return journalEvents
+}
+
+// bookmarkRev return highest revision which all events at or before it have been sent to watchers
+func (ms *MemoryStore) bookmarkRev() uint64 {
+	ms.revmu.RLock()
+	defer ms.revmu.RUnlock()
+	bookmarkRev := atomic.LoadUint64(&_MemoryRev)
+	for rev := range ms.pendingRevs {
+		if rev-1 < bookmarkRev {
+			bookmarkRev = rev - 1
+		}
+	}
+	for rev := range ms.unsentRevs {
+		if rev-1 < bookmarkRev {
+			bookmarkRev = rev - 1
+		}
+	}
+	return bookmarkRev
+}
+
+// sendBookmark send a bookmark event with latest sent revision to watchers which allow bookmarks,
+// so, they can resume from this revision after disconnect instead of a older one which may have been compacted
+func (ms *MemoryStore) sendBookmark() {
+	if ms.newFunc == nil {
+		return
+	}
+	rev := ms.bookmarkRev()
+	obj := ms.newFunc()
+	if err := ms.versioner.UpdateObject(obj, rev); err != nil {
+		klog.ErrorS(err, "Failed to set revision of bookmark object", "resource", ms.groupResource)
+		return
+	}
+	event := &objEvent{
+		obj:        obj,
+		rev:        rev,
+		isBookmark: true,
+	}
+	ms.watchersMu.Lock()
+	defer ms.watchersMu.Unlock()
 	for _, v := range ms.watchers {
-		if !v.stopped {
-			v.incomingChan <- event
-			watchers = append(watchers, v)
+		if v.allowBookmarks && !v.stopped() {
+			v.enqueueBookmark(event)
 		}
 	}
-	ms.watchers = watchers
 }
 
 // occupy a revision number and a position in sorted revisioned object list
 // sorted klist has empty slots spreaded when items are deleted and updated,
 // if len of klist is more than a threshold, we want to shrink array to avoid memory waste
 func (ms *MemoryStore) reserveRevAndSlot() (uint64, uint64, error) {
-	ms.revmu.Lock()
+	ms.lockRev()
 	defer ms.revmu.Unlock()
 	rev := atomic.AddUint64(&_MemoryRev, 1)
+	if _RevisionGuard != nil {
+		if err := _RevisionGuard.ensureReserved(rev); err != nil {
+			klog.ErrorS(err, "Failed to persist memory revision high water mark", "revision", rev)
+			return 0, 0, err
+		}
+	}
+	ms.pendingRevs[rev] = struct{}{}
 	uindex := atomic.AddUint64(&ms.revSortedObjList.lastObjIndex, 1)
 	if uint64(ms.revSortedObjList.Len()) < uindex+DefaultObjRevListGrowThreashold {
 		ms.revSortedObjList.grow(DefaultObjRevListGrowThreashold)
@@ -816,6 +1164,46 @@ func (ms *MemoryStore) reserveRevAndSlot() (uint64, uint64, error) {
 	return rev, uindex, nil
 }
 
+// commitSlot move a key from its previous slot to reserved slot of new revision and cache its event in one step,
+// so, a snapshot copy of revSortedObjList never see a key missing or duplicated
+func (ms *MemoryStore) commitSlot(prev *objWithIndex, obj *objWithIndex, event *objEvent) {
+	st := time.Now()
+	ms.lockRev()
+	defer ms.revmu.Unlock()
+	// event is not shared before it's added into watch event cache
+	event.commitTime = time.Now()
+	event.commitLockWait = event.commitTime.Sub(st)
+	if prev != nil {
+		ms.revSortedObjList.objs[prev.index] = nil
+	}
+	ms.revSortedObjList.objs[obj.index] = obj
+	// index is updated in same critical section, so, a list holding rev lock see index consistent with committed slots
+	if obj.deleted {
+		ms.index.delete(obj.key)
+	} else {
+		ms.index.update(obj.key, obj.obj)
+	}
+	if event.rev > ms.committedRev {
+		ms.committedRev = event.rev
+	}
+	ms.watchEventCache.addObjEvents(event)
+	if ms.history != nil {
+		ms.history.add(event)
+	}
+	delete(ms.pendingRevs, event.rev)
+	ms.unsentRevs[event.rev] = struct{}{}
+}
+
+// abortRev release a reserved revision which is not committed because of error, its slot is left empty
+func (ms *MemoryStore) abortRev(rev uint64) {
+	ms.lockRev()
+	delete(ms.pendingRevs, rev)
+	ms.revmu.Unlock()
+	if ms.sink != nil {
+		ms.sink.revReleased()
+	}
+}
+
 func (ms *MemoryStore) getSingleObjectAsList(ctx context.Context, key string, opts apistorage.ListOptions, listObj runtime.Object) error {
 	resourceVersion := opts.ResourceVersion
 	match := opts.ResourceVersionMatch
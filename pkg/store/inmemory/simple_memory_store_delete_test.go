@@ -0,0 +1,118 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	apistorage "k8s.io/apiserver/pkg/storage"
+)
+
+func TestDeleteWithPreconditions(t *testing.T) {
+	ms := newTestSessionStore(t)
+	session := createTestSession(t, ms, "session-1")
+	key := fmt.Sprintf("%s/ns/%s", testKeyPrefix, session.Name)
+
+	wrongUID := session.UID + "-wrong"
+	err := ms.Delete(context.Background(), key, &fornaxv1.ApplicationSession{}, apistorage.NewUIDPreconditions(string(wrongUID)), apistorage.ValidateAllObjectFunc, nil)
+	if err == nil {
+		t.Fatalf("expected delete failed with wrong uid precondition")
+	}
+	if _, err := getTestSession(ms, session.Name); err != nil {
+		t.Fatalf("expected session kept after failed delete, %v", err)
+	}
+
+	out := &fornaxv1.ApplicationSession{}
+	if err := ms.Delete(context.Background(), key, out, nil, apistorage.ValidateAllObjectFunc, nil); err != nil {
+		t.Fatal(err)
+	}
+	if out.ResourceVersion != session.ResourceVersion {
+		t.Errorf("expected deleted object returned with revision %s, got %s", session.ResourceVersion, out.ResourceVersion)
+	}
+	if _, err := getTestSession(ms, session.Name); !apistorage.IsNotFound(err) {
+		t.Errorf("expected not found after delete, got %v", err)
+	}
+	if err := ms.Delete(context.Background(), key, &fornaxv1.ApplicationSession{}, nil, apistorage.ValidateAllObjectFunc, nil); !apistorage.IsNotFound(err) {
+		t.Errorf("expected not found deleting deleted session, got %v", err)
+	}
+}
+
+func TestDeleteCollection(t *testing.T) {
+	ms := newTestSessionStore(t)
+	for i := 0; i < 5; i++ {
+		createTestSession(t, ms, fmt.Sprintf("session-%d", i))
+	}
+	other := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "session-0"}}
+	if err := ms.Create(context.Background(), testKeyPrefix+"/other/session-0", other, &fornaxv1.ApplicationSession{}, 0); err != nil {
+		t.Fatal(err)
+	}
+	w, err := watchTestSessions(ms, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	// session-2 fail validation and is kept
+	validateDeletion := func(ctx context.Context, obj runtime.Object) error {
+		if obj.(*fornaxv1.ApplicationSession).Name == "session-2" {
+			return fmt.Errorf("session-2 can not be deleted")
+		}
+		return nil
+	}
+	deleted := &fornaxv1.ApplicationSessionList{}
+	if err := ms.DeleteCollection(context.Background(), testKeyPrefix+"/ns", validateDeletion, deleted); err == nil {
+		t.Errorf("expected error of object failed validation")
+	}
+	if len(deleted.Items) != 4 {
+		t.Errorf("expected 4 deleted sessions, got %d", len(deleted.Items))
+	}
+
+	events := receiveWatchEvents(t, w, 4)
+	deletedNames := map[string]bool{}
+	for _, e := range events {
+		if e.Type != watch.Deleted {
+			t.Errorf("expected delete event, got %s", e.Type)
+		}
+		deletedNames[e.Object.(*fornaxv1.ApplicationSession).Name] = true
+	}
+	if deletedNames["session-2"] || len(deletedNames) != 4 {
+		t.Errorf("expected delete events of sessions except session-2, got %v", deletedNames)
+	}
+
+	list, err := listTestSessions(ms, "", "", 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Name != "session-2" {
+		t.Errorf("expected only session-2 left in ns, got %v", list.Items)
+	}
+	if _, err := getTestSessionInNamespace(ms, "other", "session-0"); err != nil {
+		t.Errorf("expected session in other namespace kept, %v", err)
+	}
+}
+
+func getTestSessionInNamespace(ms *MemoryStore, namespace, name string) (*fornaxv1.ApplicationSession, error) {
+	out := &fornaxv1.ApplicationSession{}
+	err := ms.Get(context.Background(), fmt.Sprintf("%s/%s/%s", testKeyPrefix, namespace, name), apistorage.GetOptions{}, out)
+	return out, err
+}
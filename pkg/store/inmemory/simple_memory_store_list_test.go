@@ -0,0 +1,250 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/store"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	apistorage "k8s.io/apiserver/pkg/storage"
+)
+
+const testKeyPrefix = "/test/applicationsessions"
+
+func newTestSessionStore(t *testing.T) *MemoryStore {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	gr := fornaxv1.ApplicationSessionGrv.GroupResource()
+	return NewMemoryStore(ctx, gr, testKeyPrefix,
+		func() runtime.Object { return &fornaxv1.ApplicationSession{} },
+		func() runtime.Object { return &fornaxv1.ApplicationSessionList{} },
+		store.DefaultResourceStorageConfiguration(gr))
+}
+
+func createTestSession(t *testing.T, ms *MemoryStore, name string) *fornaxv1.ApplicationSession {
+	session := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name}}
+	out := &fornaxv1.ApplicationSession{}
+	if err := ms.Create(context.Background(), fmt.Sprintf("%s/ns/%s", testKeyPrefix, name), session, out, 0); err != nil {
+		t.Fatalf("failed to create session %s: %v", name, err)
+	}
+	return out
+}
+
+func listTestSessions(ms *MemoryStore, rv string, match metav1.ResourceVersionMatch, limit int64, continueToken string) (*fornaxv1.ApplicationSessionList, error) {
+	list := &fornaxv1.ApplicationSessionList{}
+	opts := apistorage.ListOptions{
+		ResourceVersion:      rv,
+		ResourceVersionMatch: match,
+		Recursive:            true,
+		Predicate: apistorage.SelectionPredicate{
+			Label:    labels.Everything(),
+			Field:    fields.Everything(),
+			Limit:    limit,
+			Continue: continueToken,
+		},
+	}
+	err := ms.GetList(context.Background(), testKeyPrefix+"/ns", opts, list)
+	return list, err
+}
+
+func TestGetListPaginationIsConsistentAcrossWrites(t *testing.T) {
+	ms := newTestSessionStore(t)
+	expected := map[string]string{}
+	for i := 0; i < 10; i++ {
+		session := createTestSession(t, ms, fmt.Sprintf("session-%02d", i*2))
+		expected[session.Name] = session.ResourceVersion
+	}
+
+	list, err := listTestSessions(ms, "", "", 3, "")
+	if err != nil {
+		t.Fatalf("failed to list first page: %v", err)
+	}
+	if list.RemainingItemCount == nil || *list.RemainingItemCount != 7 {
+		t.Fatalf("expected 7 remaining items, got %v", list.RemainingItemCount)
+	}
+	listRV := list.ResourceVersion
+
+	// change store between pages, new objects sort before and after continue key,
+	// an existing object is deleted and another one is updated
+	createTestSession(t, ms, "session-03")
+	createTestSession(t, ms, "session-15")
+	if err := ms.Delete(context.Background(), testKeyPrefix+"/ns/session-12", &fornaxv1.ApplicationSession{}, nil, apistorage.ValidateAllObjectFunc, nil); err != nil {
+		t.Fatalf("failed to delete session: %v", err)
+	}
+	if err := ms.GuaranteedUpdate(context.Background(), testKeyPrefix+"/ns/session-16", &fornaxv1.ApplicationSession{}, false, nil,
+		func(input runtime.Object, res apistorage.ResponseMeta) (runtime.Object, *uint64, error) {
+			session := input.(*fornaxv1.ApplicationSession)
+			session.Labels = map[string]string{"updated": "true"}
+			return session, nil, nil
+		}, nil); err != nil {
+		t.Fatalf("failed to update session: %v", err)
+	}
+
+	got := map[string]string{}
+	lastName := ""
+	for {
+		for _, v := range list.Items {
+			if _, found := got[v.Name]; found {
+				t.Fatalf("session %s is returned twice", v.Name)
+			}
+			if v.Name <= lastName {
+				t.Fatalf("session %s is not sorted after %s", v.Name, lastName)
+			}
+			if len(v.Labels) > 0 {
+				t.Fatalf("session %s is returned with change made after list revision", v.Name)
+			}
+			got[v.Name] = v.ResourceVersion
+			lastName = v.Name
+		}
+		if list.ResourceVersion != listRV {
+			t.Fatalf("expected all pages at revision %s, got %s", listRV, list.ResourceVersion)
+		}
+		if len(list.Continue) == 0 {
+			break
+		}
+		list, err = listTestSessions(ms, "", "", 3, list.Continue)
+		if err != nil {
+			t.Fatalf("failed to list next page: %v", err)
+		}
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d sessions, got %d: %v", len(expected), len(got), got)
+	}
+	for name, rv := range expected {
+		if got[name] != rv {
+			t.Errorf("expected session %s at revision %s, got %q", name, rv, got[name])
+		}
+	}
+}
+
+func TestGetListPaginationWithConcurrentWrites(t *testing.T) {
+	ms := newTestSessionStore(t)
+	for i := 0; i < 50; i++ {
+		createTestSession(t, ms, fmt.Sprintf("session-%03d", i*2))
+	}
+
+	stop := make(chan struct{})
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			name := fmt.Sprintf("session-%03d-%d", (i%50)*2, i)
+			session := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name}}
+			if err := ms.Create(context.Background(), fmt.Sprintf("%s/ns/%s", testKeyPrefix, name), session, &fornaxv1.ApplicationSession{}, 0); err != nil {
+				t.Errorf("failed to create session %s: %v", name, err)
+				return
+			}
+		}
+	}()
+
+	got := []string{}
+	list, err := listTestSessions(ms, "", "", 7, "")
+	if err != nil {
+		t.Fatalf("failed to list first page: %v", err)
+	}
+	listRV := list.ResourceVersion
+	for {
+		for _, v := range list.Items {
+			got = append(got, v.Name)
+		}
+		if len(list.Continue) == 0 {
+			break
+		}
+		list, err = listTestSessions(ms, "", "", 7, list.Continue)
+		if err != nil {
+			t.Fatalf("failed to list next page: %v", err)
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	full, err := listTestSessions(ms, listRV, metav1.ResourceVersionMatchExact, 0, "")
+	if err != nil {
+		t.Fatalf("failed to list at revision %s: %v", listRV, err)
+	}
+	if len(full.Items) != len(got) {
+		t.Fatalf("expected %d sessions at revision %s, got %d pages items", len(full.Items), listRV, len(got))
+	}
+	for i, v := range full.Items {
+		if got[i] != v.Name {
+			t.Fatalf("expected session %s at position %d, got %s", v.Name, i, got[i])
+		}
+	}
+}
+
+func TestGetListResourceVersionMatch(t *testing.T) {
+	ms := newTestSessionStore(t)
+	for i := 0; i < 3; i++ {
+		createTestSession(t, ms, fmt.Sprintf("session-%d", i))
+	}
+	list, err := listTestSessions(ms, "", "", 0, "")
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	rv := list.ResourceVersion
+	createTestSession(t, ms, "session-3")
+
+	list, err = listTestSessions(ms, rv, metav1.ResourceVersionMatchExact, 0, "")
+	if err != nil {
+		t.Fatalf("failed to list at exact revision: %v", err)
+	}
+	if len(list.Items) != 3 || list.ResourceVersion != rv {
+		t.Fatalf("expected 3 sessions at revision %s, got %d at %s", rv, len(list.Items), list.ResourceVersion)
+	}
+
+	list, err = listTestSessions(ms, rv, metav1.ResourceVersionMatchNotOlderThan, 0, "")
+	if err != nil {
+		t.Fatalf("failed to list not older than revision: %v", err)
+	}
+	if len(list.Items) != 4 {
+		t.Fatalf("expected 4 sessions not older than revision %s, got %d", rv, len(list.Items))
+	}
+
+	parsedRV, _ := store.ParseResourceVersion(rv)
+	tooLargeRV := strconv.FormatUint(parsedRV+1000000000, 10)
+	if _, err := listTestSessions(ms, tooLargeRV, metav1.ResourceVersionMatchNotOlderThan, 0, ""); !apistorage.IsTooLargeResourceVersion(err) {
+		t.Fatalf("expected too large resource version error, got %v", err)
+	}
+	if _, err := listTestSessions(ms, "0", metav1.ResourceVersionMatchExact, 0, ""); !apierrors.IsBadRequest(err) {
+		t.Fatalf("expected bad request for exact match of revision 0, got %v", err)
+	}
+
+	page, err := listTestSessions(ms, "", "", 1, "")
+	if err != nil {
+		t.Fatalf("failed to list first page: %v", err)
+	}
+	if _, err := listTestSessions(ms, rv, "", 1, page.Continue); !apierrors.IsBadRequest(err) {
+		t.Fatalf("expected bad request for resource version with continue, got %v", err)
+	}
+}
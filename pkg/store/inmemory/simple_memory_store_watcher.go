@@ -19,39 +19,124 @@ package inmemory
 import (
 	"context"
 	"strings"
+	"sync"
+	"time"
 
 	"centaurusinfra.io/fornax-serverless/pkg/store"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/apiserver/pkg/storage"
 	apistorage "k8s.io/apiserver/pkg/storage"
+	"k8s.io/component-base/metrics"
 	"k8s.io/klog/v2"
 )
 
+// watcherQueue is a bounded event queue of a watcher, store push events into it without blocking,
+// watcher goroutine is notified to drain it
+type watcherQueue struct {
+	mu     sync.Mutex
+	size   int
+	events []*objEvent
+	notify chan struct{}
+	closed bool
+	depth  metrics.GaugeMetric
+}
+
+func newWatcherQueue(size int, depth metrics.GaugeMetric) *watcherQueue {
+	if size <= 0 {
+		size = store.DefaultWatcherQueueSize
+	}
+	return &watcherQueue{
+		mu:     sync.Mutex{},
+		size:   size,
+		events: []*objEvent{},
+		notify: make(chan struct{}, 1),
+		closed: false,
+		depth:  depth,
+	}
+}
+
+// push return false if queue is full, event is not queued
+func (q *watcherQueue) push(event *objEvent) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return true
+	}
+	if len(q.events) >= q.size {
+		return false
+	}
+	q.events = append(q.events, event)
+	q.depth.Inc()
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+func (q *watcherQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.events)
+}
+
+func (q *watcherQueue) popAll() []*objEvent {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	events := q.events
+	q.events = []*objEvent{}
+	q.depth.Add(-float64(len(events)))
+	return events
+}
+
+// close drop queued events, later pushed events are ignored
+func (q *watcherQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.depth.Add(-float64(len(q.events)))
+	q.events = nil
+}
+
+func (q *watcherQueue) isClosed() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.closed
+}
+
 type memoryStoreWatcher struct {
 	ctx                    context.Context
-	stopped                bool
 	recursive              bool
+	resource               string
+	slowWatcherPolicy      store.SlowWatcherPolicy
+	stopOnce               sync.Once
 	stopChannel            chan bool
-	incomingChan           chan *objEvent
+	queue                  *watcherQueue
 	outgoingChan           chan watch.Event
 	outgoingChanWithOldObj chan store.WatchEventWithOldObj
 	keyPrefix              string
 	predicate              apistorage.SelectionPredicate
+	allowBookmarks         bool
+	// return how long store last waited for watchers lock, it's reported with slow events
+	sendLockWait func() time.Duration
 }
 
-func NewMemoryStoreWatcher(ctx context.Context, key string, opts storage.ListOptions) *memoryStoreWatcher {
+func NewMemoryStoreWatcher(ctx context.Context, key string, opts storage.ListOptions, resource string, queueSize int, slowWatcherPolicy store.SlowWatcherPolicy) *memoryStoreWatcher {
 	if opts.Recursive && !strings.HasSuffix(key, "/") {
 		key += "/"
 	}
 	watcher := &memoryStoreWatcher{
 		ctx:                    ctx,
-		stopped:                false,
 		keyPrefix:              key,
 		recursive:              opts.Recursive,
+		resource:               resource,
+		slowWatcherPolicy:      slowWatcherPolicy,
 		predicate:              opts.Predicate,
-		stopChannel:            make(chan bool, 1),
-		incomingChan:           make(chan *objEvent, 500),
+		allowBookmarks:         opts.Predicate.AllowWatchBookmarks,
+		stopOnce:               sync.Once{},
+		stopChannel:            make(chan bool),
+		queue:                  newWatcherQueue(queueSize, watcherQueueDepth.WithLabelValues(resource)),
 		outgoingChan:           make(chan watch.Event, 500),
 		outgoingChanWithOldObj: make(chan store.WatchEventWithOldObj, 500),
 	}
@@ -74,67 +159,143 @@ func (wc *memoryStoreWatcher) acceptAll() bool {
 	return wc.predicate.Empty()
 }
 
-// send existing events, then start consume events in channel, events should be larger than env
+func (wc *memoryStoreWatcher) stopped() bool {
+	return wc.queue.isClosed()
+}
+
+// enqueue is called by store when a event happen, it never block,
+// if queue is full, watcher is stopped or event is dropped according to slow watcher policy
+func (wc *memoryStoreWatcher) enqueue(event *objEvent) {
+	if wc.queue.push(event) {
+		return
+	}
+	if wc.slowWatcherPolicy == store.SlowWatcherPolicyDrop {
+		watcherDroppedEvents.WithLabelValues(wc.resource).Inc()
+		klog.V(4).InfoS("Watcher queue is full, drop event", "resource", wc.resource, "key", wc.keyPrefix, "rev", event.rev)
+		return
+	}
+	klog.InfoS("Watcher queue is full, stop slow watcher", "resource", wc.resource, "key", wc.keyPrefix, "rev", event.rev)
+	slowWatcherClosed.WithLabelValues(wc.resource).Inc()
+	// close queue immediately, so, following events are ignored before watcher goroutine exit
+	wc.queue.close()
+	wc.Stop()
+}
+
+// enqueueBookmark push a bookmark into queue, bookmark is skipped if queue is full, next bookmark will carry a newer revision
+func (wc *memoryStoreWatcher) enqueueBookmark(event *objEvent) {
+	wc.queue.push(event)
+}
+
+// send a event to outgoing channel, return false if watcher is stopped when waiting for consumer,
+// commit time is only passed to consumer for live events, replayed events are not counted in watch latency
+func (wc *memoryStoreWatcher) send(event *objEvent, eventWithOldObj bool, live bool) bool {
+	wcEvent := wc.transformToWatchEvent(event)
+	if wcEvent == nil {
+		return true
+	}
+	if eventWithOldObj {
+		e := wc.transformToWatchEventWithOldObj(wcEvent, event.oldObj)
+		if e == nil {
+			return true
+		}
+		if live && !event.isBookmark {
+			e.CommitTime = event.commitTime
+		}
+		select {
+		case wc.outgoingChanWithOldObj <- *e:
+		case <-wc.stopChannel:
+			return false
+		case <-wc.ctx.Done():
+			return false
+		}
+	} else {
+		select {
+		case wc.outgoingChan <- *wcEvent:
+		case <-wc.stopChannel:
+			return false
+		case <-wc.ctx.Done():
+			return false
+		}
+	}
+	return true
+}
+
+// send existing events, then start consume events in queue, events should be larger than env
 // pasted objEvents should be already sorted according to event's rev
 func (wc *memoryStoreWatcher) run(rev uint64, existingObjEvents []*objEvent, eventWithOldObj bool) {
 	defer func() {
-		if x := recover(); x != nil {
-			klog.InfoS("Watcher stopped", "cause", x)
-		}
+		wc.queue.close()
+		close(wc.outgoingChan)
+		close(wc.outgoingChanWithOldObj)
 	}()
-	startingRev := rev
+	// events replayed from cache could also be in queue, skip them by revision, commits could reach queue slightly out of revision order,
+	// so, a queued event older than latest replayed event is still sent if it was not replayed
+	replayed := map[uint64]bool{}
+	replayedRev := rev
 	for _, event := range existingObjEvents {
-		wcEvent := wc.transformToWatchEvent(event)
-		if wcEvent != nil {
-			if eventWithOldObj {
-				if e := wc.transformToWatchEventWithOldObj(wcEvent, event.oldObj); e != nil {
-					wc.outgoingChanWithOldObj <- *e
-				}
-			} else {
-				wc.outgoingChan <- *wcEvent
-			}
+		replayed[event.rev] = true
+		if event.rev > replayedRev {
+			replayedRev = event.rev
 		}
-	}
-
-	go func() {
-		defer func() {
-			wc.stopped = true
-			close(wc.incomingChan)
-			close(wc.outgoingChan)
-			close(wc.outgoingChanWithOldObj)
-		}()
-		for {
-			select {
-			case <-wc.ctx.Done():
-				return
-			case <-wc.stopChannel:
-				return
-			}
+		if !wc.send(event, eventWithOldObj, false) {
+			return
 		}
-	}()
+	}
 
 	for {
 		select {
-		case event, ok := <-wc.incomingChan:
-			if !ok {
-				return
-			}
-			if event.rev > startingRev {
-				wcEvent := wc.transformToWatchEvent(event)
-				if wcEvent != nil {
-					if eventWithOldObj {
-						if e := wc.transformToWatchEventWithOldObj(wcEvent, event.oldObj); e != nil {
-							wc.outgoingChanWithOldObj <- *e
+		case <-wc.ctx.Done():
+			return
+		case <-wc.stopChannel:
+			return
+		case <-wc.queue.notify:
+			events := wc.queue.popAll()
+			for _, event := range events {
+				if event.isBookmark {
+					if event.rev >= replayedRev {
+						if !wc.send(event, eventWithOldObj, true) {
+							return
 						}
-					} else {
-						wc.outgoingChan <- *wcEvent
 					}
+					continue
+				}
+				if event.rev <= rev {
+					continue
 				}
+				if replayed[event.rev] {
+					delete(replayed, event.rev)
+					continue
+				}
+				if !wc.send(event, eventWithOldObj, true) {
+					return
+				}
+				wc.observeDelivery(event)
 			}
 		}
 	}
 }
 
+// observeDelivery record time from commit to event put into result channel
+func (wc *memoryStoreWatcher) observeDelivery(event *objEvent) {
+	if event.commitTime.IsZero() {
+		return
+	}
+	report := &store.SlowPathReport{
+		Resource:            wc.resource,
+		Stage:               store.WatchLatencyStageDelivery,
+		Key:                 event.key,
+		Revision:            event.rev,
+		Latency:             time.Since(event.commitTime),
+		WatcherQueueDepth:   wc.queue.len(),
+		WatcherChannelDepth: len(wc.outgoingChan) + len(wc.outgoingChanWithOldObj),
+		CommitLockWait:      event.commitLockWait,
+	}
+	if wc.sendLockWait != nil {
+		report.SendLockWait = wc.sendLockWait()
+	}
+	store.ObserveWatchLatency(report)
+}
+
 // ResultChan implements watch.Interface
 func (wc *memoryStoreWatcher) ResultChan() <-chan watch.Event {
 	return wc.outgoingChan
@@ -147,10 +308,24 @@ func (wc *memoryStoreWatcher) ResultChanWithPrevobj() <-chan store.WatchEventWit
 
 // Stop implements watch.Interface
 func (wc *memoryStoreWatcher) Stop() {
-	wc.stopChannel <- true
+	wc.stopOnce.Do(func() {
+		close(wc.stopChannel)
+	})
+}
+
+// abort stop a registered watcher which failed to start, store remove it on next event
+func (wc *memoryStoreWatcher) abort() {
+	wc.Stop()
+	wc.queue.close()
 }
 
 func (wc *memoryStoreWatcher) transformToWatchEvent(e *objEvent) (res *watch.Event) {
+	if e.isBookmark {
+		return &watch.Event{
+			Type:   watch.Bookmark,
+			Object: e.obj,
+		}
+	}
 	if wc.recursive {
 		if !strings.HasPrefix(e.key, wc.keyPrefix) {
 			return nil
@@ -236,6 +411,12 @@ func (wc *memoryStoreWatcher) transformToWatchEventWithOldObj(e *watch.Event, ol
 			Object:    e.Object,
 			OldObject: oldObj,
 		}
+	case watch.Bookmark:
+		return &store.WatchEventWithOldObj{
+			Type:      e.Type,
+			Object:    e.Object,
+			OldObject: nil,
+		}
 	default:
 		return nil
 	}
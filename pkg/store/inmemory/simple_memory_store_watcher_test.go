@@ -0,0 +1,168 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/store"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+	apistorage "k8s.io/apiserver/pkg/storage"
+)
+
+func newTestSessionEvent(rev uint64) *objEvent {
+	session := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: fmt.Sprintf("session-%d", rev), ResourceVersion: strconv.FormatUint(rev, 10)}}
+	return &objEvent{key: fmt.Sprintf("%s/ns/%s", testKeyPrefix, session.Name), obj: session, rev: rev, isCreated: true}
+}
+
+func newTestWatcher(ctx context.Context, queueSize int, policy store.SlowWatcherPolicy) *memoryStoreWatcher {
+	return NewMemoryStoreWatcher(ctx, testKeyPrefix, apistorage.ListOptions{Recursive: true, Predicate: apistorage.Everything}, "test", queueSize, policy)
+}
+
+func TestWatcherQueueIsBounded(t *testing.T) {
+	q := newWatcherQueue(2, watcherQueueDepth.WithLabelValues("test"))
+	if !q.push(newTestSessionEvent(1)) || !q.push(newTestSessionEvent(2)) {
+		t.Fatalf("expected events queued before queue is full")
+	}
+	if q.push(newTestSessionEvent(3)) {
+		t.Errorf("expected push failed when queue is full")
+	}
+	if events := q.popAll(); len(events) != 2 || events[0].rev != 1 || events[1].rev != 2 {
+		t.Errorf("expected queued events 1 and 2, got %v", events)
+	}
+	if !q.push(newTestSessionEvent(3)) {
+		t.Errorf("expected push succeed after queue is drained")
+	}
+
+	// events pushed after queue is closed are ignored
+	q.close()
+	q.push(newTestSessionEvent(4))
+	if events := q.popAll(); len(events) != 0 {
+		t.Errorf("expected no event in closed queue, got %d", len(events))
+	}
+}
+
+func TestSlowWatcherIsClosed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watcher := newTestWatcher(ctx, 2, store.SlowWatcherPolicyClose)
+	for rev := uint64(1); rev <= 3; rev++ {
+		watcher.enqueue(newTestSessionEvent(rev))
+	}
+	if !watcher.stopped() {
+		t.Fatalf("expected slow watcher stopped when queue is full")
+	}
+
+	go watcher.run(0, nil, false)
+	select {
+	case _, ok := <-watcher.ResultChan():
+		if ok {
+			t.Errorf("expected no event sent by stopped watcher")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected result channel of slow watcher closed")
+	}
+}
+
+func TestSlowWatcherDropEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watcher := newTestWatcher(ctx, 2, store.SlowWatcherPolicyDrop)
+	defer watcher.Stop()
+	for rev := uint64(1); rev <= 3; rev++ {
+		watcher.enqueue(newTestSessionEvent(rev))
+	}
+	if watcher.stopped() {
+		t.Fatalf("expected watcher not stopped with drop policy")
+	}
+
+	go watcher.run(0, nil, false)
+	events := receiveWatchEvents(t, watcher, 2)
+	if rv := events[1].Object.(*fornaxv1.ApplicationSession).ResourceVersion; rv != "2" {
+		t.Errorf("expected event 3 dropped, got last event %s", rv)
+	}
+	watcher.enqueue(newTestSessionEvent(4))
+	if rv := receiveWatchEvents(t, watcher, 1)[0].Object.(*fornaxv1.ApplicationSession).ResourceVersion; rv != "4" {
+		t.Errorf("expected event 4 after queue is drained, got %s", rv)
+	}
+}
+
+func watchTestSessionsWithBookmarks(ms *MemoryStore, rv string, allowBookmarks bool) (watch.Interface, error) {
+	return ms.Watch(context.Background(), testKeyPrefix+"/ns", apistorage.ListOptions{
+		ResourceVersion: rv,
+		Recursive:       true,
+		Predicate: apistorage.SelectionPredicate{
+			Label:               labels.Everything(),
+			Field:               fields.Everything(),
+			AllowWatchBookmarks: allowBookmarks,
+		},
+	})
+}
+
+func TestBookmarkRevDoesNotPassPendingRevision(t *testing.T) {
+	ms := newTestSessionStore(t)
+	createTestSession(t, ms, "session-1")
+	rev, _, err := ms.reserveRevAndSlot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bookmarkRev := ms.bookmarkRev(); bookmarkRev >= rev {
+		t.Errorf("expected bookmark revision %d before pending revision %d", bookmarkRev, rev)
+	}
+	ms.abortRev(rev)
+	if bookmarkRev := ms.bookmarkRev(); bookmarkRev < rev {
+		t.Errorf("expected bookmark revision %d pass aborted revision %d", bookmarkRev, rev)
+	}
+}
+
+func TestSendBookmark(t *testing.T) {
+	ms := newTestSessionStore(t)
+	session := createTestSession(t, ms, "session-1")
+	bookmarkWatcher, err := watchTestSessionsWithBookmarks(ms, session.ResourceVersion, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bookmarkWatcher.Stop()
+	plainWatcher, err := watchTestSessionsWithBookmarks(ms, session.ResourceVersion, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer plainWatcher.Stop()
+
+	ms.sendBookmark()
+	e := receiveWatchEvents(t, bookmarkWatcher, 1)[0]
+	if e.Type != watch.Bookmark {
+		t.Fatalf("expected bookmark event, got %s", e.Type)
+	}
+	if rev := mustParseRV(t, e.Object.(*fornaxv1.ApplicationSession).ResourceVersion); rev < mustParseRV(t, session.ResourceVersion) {
+		t.Errorf("expected bookmark revision %d not older than last sent event %s", rev, session.ResourceVersion)
+	}
+
+	// watcher not allowing bookmarks only receive object events
+	createTestSession(t, ms, "session-2")
+	if e := receiveWatchEvents(t, plainWatcher, 1)[0]; e.Type != watch.Added {
+		t.Errorf("expected added event without bookmark, got %s", e.Type)
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"centaurusinfra.io/fornax-serverless/pkg/store"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/apimachinery/pkg/runtime"
+	apistorage "k8s.io/apiserver/pkg/storage"
+)
+
+const (
+	// history of a deleted object is kept for a while, then it's removed by house keeping
+	DefaultDeletedHistoryRetention = 10 * time.Minute
+)
+
+var (
+	HistoryNotEnabledError = errors.New("object history is not enabled, set historyRevisions in storage configuration")
+)
+
+// ObjectRevision is a state of object committed at a revision, Object is last state of object if it's deleted
+type ObjectRevision struct {
+	Revision  uint64         `json:"revision"`
+	Timestamp time.Time      `json:"timestamp"`
+	Created   bool           `json:"created,omitempty"`
+	Deleted   bool           `json:"deleted,omitempty"`
+	Object    runtime.Object `json:"object,omitempty"`
+}
+
+// objectHistory keep last size revisions of every object sorted by revision,
+// objects restored from persistence or created before history is kept have no creation revision
+type objectHistory struct {
+	mu   sync.RWMutex
+	size int
+	revs map[string][]*ObjectRevision
+}
+
+func newObjectHistory(size int) *objectHistory {
+	return &objectHistory{
+		mu:   sync.RWMutex{},
+		size: size,
+		revs: map[string][]*ObjectRevision{},
+	}
+}
+
+func (h *objectHistory) add(event *objEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	revision := &ObjectRevision{
+		Revision:  event.rev,
+		Timestamp: time.Now(),
+		Created:   event.isCreated,
+		Deleted:   event.isDeleted,
+		Object:    event.obj,
+	}
+	if event.isDeleted {
+		revision.Object = event.oldObj
+	}
+	revs := append(h.revs[event.key], revision)
+	if len(revs) > h.size {
+		revs = revs[len(revs)-h.size:]
+	}
+	h.revs[event.key] = revs
+}
+
+func (h *objectHistory) get(key string) []*ObjectRevision {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	revs := h.revs[key]
+	return append(make([]*ObjectRevision, 0, len(revs)), revs...)
+}
+
+// prune remove history of objects deleted before deadline
+func (h *objectHistory) prune(deadline time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for key, revs := range h.revs {
+		if last := revs[len(revs)-1]; last.Deleted && last.Timestamp.Before(deadline) {
+			delete(h.revs, key)
+		}
+	}
+}
+
+// History return retained revisions of a object sorted by revision, it's empty if object is not changed since history is kept
+func (ms *MemoryStore) History(key string) ([]ObjectRevision, error) {
+	if ms.history == nil {
+		return nil, HistoryNotEnabledError
+	}
+	revisions := []ObjectRevision{}
+	for _, v := range ms.history.get(key) {
+		revision := *v
+		revision.Object = v.Object.DeepCopyObject()
+		revisions = append(revisions, revision)
+	}
+	return revisions, nil
+}
+
+// GetAtRevision return state of a object as of rev, not found error is returned if object did not exist or was deleted at rev,
+// resource expired error is returned if rev is older than retained history of object
+func (ms *MemoryStore) GetAtRevision(ctx context.Context, key string, rev uint64, out runtime.Object) error {
+	if ms.history == nil {
+		return HistoryNotEnabledError
+	}
+	outVal, err := conversion.EnforcePtr(out)
+	if err != nil {
+		return err
+	}
+
+	revs := ms.history.get(key)
+	var found *ObjectRevision
+	for i := len(revs) - 1; i >= 0; i-- {
+		if revs[i].Revision <= rev {
+			found = revs[i]
+			break
+		}
+	}
+
+	var obj runtime.Object
+	switch {
+	case found != nil && found.Deleted:
+		return apistorage.NewKeyNotFoundError(key, int64(rev))
+	case found != nil:
+		obj = found.Object
+	case len(revs) > 0 && revs[0].Created:
+		// object is created after rev
+		return apistorage.NewKeyNotFoundError(key, int64(rev))
+	case len(revs) > 0:
+		return apierrors.NewResourceExpired(fmt.Sprintf("revision %d of %s is older than retained history, oldest revision is %d", rev, key, revs[0].Revision))
+	default:
+		// object is not changed since history is kept, current object is state at rev if it's not newer than rev
+		objWi := ms.kvs.get(strings.Split(key, "/"))
+		if objWi == nil {
+			return apistorage.NewKeyNotFoundError(key, int64(rev))
+		}
+		if objRV, _ := store.GetObjectResourceVersion(objWi.obj); objRV > rev {
+			return apierrors.NewResourceExpired(fmt.Sprintf("revision %d of %s is older than retained history", rev, key))
+		}
+		obj = objWi.obj
+	}
+	outVal.Set(reflect.ValueOf(obj.DeepCopyObject()).Elem())
+	return nil
+}
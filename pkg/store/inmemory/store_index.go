@@ -0,0 +1,335 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"centaurusinfra.io/fornax-serverless/pkg/store"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// storeIndex maintain secondary indexes of objects in memory store, it's updated when object is committed or deleted,
+// so, object keys of a indexed value are found without scanning kv map, every label of object is indexed,
+// fields are indexed when field funcs are set, exact label and field selectors are served by these maps
+type storeIndex struct {
+	mu         sync.RWMutex
+	indexers   cache.Indexers
+	fieldFuncs apistorage.IndexerFuncs
+	// index name => indexed value => object keys
+	indices map[string]map[string]sets.String
+	// label name => label value => object keys
+	labels map[string]map[string]sets.String
+	// field name => field value => object keys
+	fields map[string]map[string]sets.String
+	// object key => indexed object, used to remove old values when object is updated or deleted
+	objs map[string]*indexedObj
+}
+
+// indexedObj is latest committed object of a key and values it's indexed by
+type indexedObj struct {
+	obj    runtime.Object
+	values map[string][]string
+	labels map[string]string
+	fields map[string]string
+}
+
+func newStoreIndex() *storeIndex {
+	return &storeIndex{
+		mu:         sync.RWMutex{},
+		indexers:   cache.Indexers{},
+		fieldFuncs: apistorage.IndexerFuncs{},
+		indices:    map[string]map[string]sets.String{},
+		labels:     map[string]map[string]sets.String{},
+		fields:     map[string]map[string]sets.String{},
+		objs:       map[string]*indexedObj{},
+	}
+}
+
+// addIndexers add new index funcs, existing objects are indexed using objs func, a index name can not be registered twice
+func (si *storeIndex) addIndexers(indexers cache.Indexers, objs func(func(key string, obj runtime.Object))) error {
+	si.mu.Lock()
+	defer si.mu.Unlock()
+	for name := range indexers {
+		if _, found := si.indexers[name]; found {
+			return fmt.Errorf("indexer %s already exists", name)
+		}
+	}
+	for name, indexFunc := range indexers {
+		si.indexers[name] = indexFunc
+		si.indices[name] = map[string]sets.String{}
+	}
+	objs(func(key string, obj runtime.Object) {
+		for name, indexFunc := range indexers {
+			si._addValuesNoLock(key, si._indexedObjNoLock(key, obj), name, indexFunc)
+		}
+	})
+	return nil
+}
+
+// addFieldFuncs index fields which are not indexed yet, existing objects are indexed using objs func
+func (si *storeIndex) addFieldFuncs(fieldFuncs apistorage.IndexerFuncs, objs func(func(key string, obj runtime.Object))) {
+	si.mu.Lock()
+	defer si.mu.Unlock()
+	newFieldFuncs := apistorage.IndexerFuncs{}
+	for field, fieldFunc := range fieldFuncs {
+		if _, found := si.fieldFuncs[field]; !found {
+			si.fieldFuncs[field] = fieldFunc
+			si.fields[field] = map[string]sets.String{}
+			newFieldFuncs[field] = fieldFunc
+		}
+	}
+	if len(newFieldFuncs) == 0 {
+		return
+	}
+	objs(func(key string, obj runtime.Object) {
+		io := si._indexedObjNoLock(key, obj)
+		for field, fieldFunc := range newFieldFuncs {
+			si._addFieldNoLock(key, io, field, fieldFunc(obj))
+		}
+	})
+}
+
+func (si *storeIndex) getIndexers() cache.Indexers {
+	si.mu.RLock()
+	defer si.mu.RUnlock()
+	indexers := cache.Indexers{}
+	for name, indexFunc := range si.indexers {
+		indexers[name] = indexFunc
+	}
+	return indexers
+}
+
+func (si *storeIndex) hasIndex(indexName string) bool {
+	si.mu.RLock()
+	defer si.mu.RUnlock()
+	_, found := si.indexers[indexName]
+	return found
+}
+
+// update reindex object of key, old indexed values of this key are removed
+func (si *storeIndex) update(key string, obj runtime.Object) {
+	si.mu.Lock()
+	defer si.mu.Unlock()
+	si._deleteNoLock(key)
+	io := si._indexedObjNoLock(key, obj)
+	if accessor, err := meta.Accessor(obj); err == nil {
+		for name, value := range accessor.GetLabels() {
+			addKey(si.labels, name, value, key)
+			io.labels[name] = value
+		}
+	}
+	for field, fieldFunc := range si.fieldFuncs {
+		si._addFieldNoLock(key, io, field, fieldFunc(obj))
+	}
+	for name, indexFunc := range si.indexers {
+		si._addValuesNoLock(key, io, name, indexFunc)
+	}
+}
+
+func (si *storeIndex) delete(key string) {
+	si.mu.Lock()
+	defer si.mu.Unlock()
+	si._deleteNoLock(key)
+}
+
+// byIndex return object keys which have indexed value in a index
+func (si *storeIndex) byIndex(indexName, indexedValue string) ([]string, error) {
+	si.mu.RLock()
+	defer si.mu.RUnlock()
+	index, found := si.indices[indexName]
+	if !found {
+		return nil, fmt.Errorf("index %s does not exist", indexName)
+	}
+	return index[indexedValue].List(), nil
+}
+
+// exactMatchObjs return latest committed objects which have all required label values and required values of indexed fields,
+// a field is served by indexer "f:<field>" or a field func, served is false if no requirement is indexed
+func (si *storeIndex) exactMatchObjs(labelValues, fieldValues map[string]string) (objs map[string]runtime.Object, served bool) {
+	si.mu.RLock()
+	defer si.mu.RUnlock()
+	keySets := []sets.String{}
+	for name, value := range labelValues {
+		keySets = append(keySets, si.labels[name][value])
+	}
+	for field, value := range fieldValues {
+		if index, found := si.indices["f:"+field]; found {
+			keySets = append(keySets, index[value])
+		} else if index, found := si.fields[field]; found {
+			keySets = append(keySets, index[value])
+		}
+	}
+	if len(keySets) == 0 {
+		return nil, false
+	}
+	sort.Slice(keySets, func(i, j int) bool { return keySets[i].Len() < keySets[j].Len() })
+	objs = map[string]runtime.Object{}
+	for key := range keySets[0] {
+		matched := true
+		for _, keys := range keySets[1:] {
+			if !keys.Has(key) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			objs[key] = si.objs[key].obj
+		}
+	}
+	return objs, true
+}
+
+func (si *storeIndex) _indexedObjNoLock(key string, obj runtime.Object) *indexedObj {
+	io, found := si.objs[key]
+	if !found {
+		io = &indexedObj{obj: obj, values: map[string][]string{}, labels: map[string]string{}, fields: map[string]string{}}
+		si.objs[key] = io
+	}
+	return io
+}
+
+func (si *storeIndex) _addFieldNoLock(key string, io *indexedObj, field, value string) {
+	addKey(si.fields, field, value, key)
+	io.fields[field] = value
+}
+
+func (si *storeIndex) _addValuesNoLock(key string, io *indexedObj, name string, indexFunc cache.IndexFunc) {
+	values, err := indexFunc(io.obj)
+	if err != nil {
+		klog.ErrorS(err, "Failed to index object", "key", key, "index", name)
+		return
+	}
+	if len(values) == 0 {
+		return
+	}
+	for _, value := range values {
+		addKey(si.indices, name, value, key)
+	}
+	io.values[name] = values
+}
+
+func (si *storeIndex) _deleteNoLock(key string) {
+	io, found := si.objs[key]
+	if !found {
+		return
+	}
+	for name, values := range io.values {
+		for _, value := range values {
+			deleteKey(si.indices, name, value, key)
+		}
+	}
+	for name, value := range io.labels {
+		deleteKey(si.labels, name, value, key)
+	}
+	for field, value := range io.fields {
+		deleteKey(si.fields, field, value, key)
+	}
+	delete(si.objs, key)
+}
+
+// addKey add object key into keys of a value in a index map
+func addKey(index map[string]map[string]sets.String, name, value, key string) {
+	values, found := index[name]
+	if !found {
+		values = map[string]sets.String{}
+		index[name] = values
+	}
+	keys, found := values[value]
+	if !found {
+		keys = sets.NewString()
+		values[value] = keys
+	}
+	keys.Insert(key)
+}
+
+// deleteKey remove object key from keys of a value in a index map, empty value is removed
+func deleteKey(index map[string]map[string]sets.String, name, value, key string) {
+	values, found := index[name]
+	if !found {
+		return
+	}
+	if keys, found := values[value]; found {
+		keys.Delete(key)
+		if keys.Len() == 0 {
+			delete(values, value)
+		}
+	}
+}
+
+// AddIndexers implements FornaxStorage, register secondary indexes, objects already in store are indexed immediately
+func (ms *MemoryStore) AddIndexers(indexers cache.Indexers) error {
+	if len(indexers) == 0 {
+		return nil
+	}
+	ms.revmu.RLock()
+	defer ms.revmu.RUnlock()
+	return ms.index.addIndexers(indexers, ms.committedObjs)
+}
+
+// committedObjs call f with every committed object which is not deleted, caller must hold rev lock
+func (ms *MemoryStore) committedObjs(f func(key string, obj runtime.Object)) {
+	objBufferLen := atomic.LoadUint64(&ms.revSortedObjList.lastObjIndex)
+	for i := uint64(0); i <= objBufferLen && i < uint64(ms.revSortedObjList.Len()); i++ {
+		if v := ms.revSortedObjList.objs[i]; v != nil && !v.deleted {
+			f(v.key, v.obj)
+		}
+	}
+}
+
+// ListByIndex implements FornaxStorage, return objects under key prefix which have indexed value in a index,
+// object keys are found in index directly instead of scanning revSortedObjList, list is sorted by key
+func (ms *MemoryStore) ListByIndex(ctx context.Context, key string, indexName, indexedValue string, listObj runtime.Object) error {
+	listPtr, err := meta.GetItemsPtr(listObj)
+	if err != nil {
+		return err
+	}
+	listRetVal, err := conversion.EnforcePtr(listPtr)
+	if err != nil || listRetVal.Kind() != reflect.Slice {
+		return fmt.Errorf("need ptr to slice: %v", err)
+	}
+
+	keys, err := ms.index.byIndex(indexName, indexedValue)
+	if err != nil {
+		return apistorage.NewInternalError(err.Error())
+	}
+	returnedRV := atomic.LoadUint64(&_MemoryRev)
+	for _, k := range keys {
+		if !strings.HasPrefix(k, key) {
+			continue
+		}
+		// object could be deleted after index is read
+		if obj := ms.kvs.get(strings.Split(k, "/")); obj != nil {
+			rv, _ := store.GetObjectResourceVersion(obj.obj)
+			store.AppendListItem(listRetVal, obj.obj, rv, apistorage.Everything)
+		}
+	}
+	return store.UpdateList(listObj, returnedRV, "", nil)
+}
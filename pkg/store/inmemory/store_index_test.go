@@ -0,0 +1,263 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	"k8s.io/client-go/tools/cache"
+)
+
+func testSessionApplicationName(obj runtime.Object) string {
+	return obj.(*fornaxv1.ApplicationSession).Spec.ApplicationName
+}
+
+func testSessionAttrs(obj runtime.Object) (labels.Set, fields.Set, error) {
+	session := obj.(*fornaxv1.ApplicationSession)
+	return labels.Set(session.Labels), fields.Set{"metadata.name": session.Name, "spec.applicationName": session.Spec.ApplicationName}, nil
+}
+
+func newTestIndexedSessionStore(t *testing.T) *MemoryStore {
+	ms := newTestSessionStore(t)
+	if err := ms.CompleteWithFunctions(nil, ms.newFunc, ms.newListFunc, testSessionAttrs, apistorage.IndexerFuncs{"spec.applicationName": testSessionApplicationName}, nil); err != nil {
+		t.Fatal(err)
+	}
+	return ms
+}
+
+func newTestLabeledSession(name, application string, sessionLabels map[string]string) *fornaxv1.ApplicationSession {
+	session := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, Labels: sessionLabels}}
+	session.Spec.ApplicationName = application
+	return session
+}
+
+func createTestLabeledSession(t *testing.T, ms *MemoryStore, name, application string, sessionLabels map[string]string) *fornaxv1.ApplicationSession {
+	out := &fornaxv1.ApplicationSession{}
+	if err := ms.Create(context.Background(), fmt.Sprintf("%s/ns/%s", testKeyPrefix, name), newTestLabeledSession(name, application, sessionLabels), out, 0); err != nil {
+		t.Fatalf("failed to create session %s: %v", name, err)
+	}
+	return out
+}
+
+func updateTestSessionLabels(t *testing.T, ms *MemoryStore, name string, sessionLabels map[string]string) *fornaxv1.ApplicationSession {
+	out := &fornaxv1.ApplicationSession{}
+	err := ms.GuaranteedUpdate(context.Background(), fmt.Sprintf("%s/ns/%s", testKeyPrefix, name), out, false, nil, func(input runtime.Object, res apistorage.ResponseMeta) (runtime.Object, *uint64, error) {
+		session := input.(*fornaxv1.ApplicationSession).DeepCopy()
+		session.Labels = sessionLabels
+		return session, nil, nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to update session %s: %v", name, err)
+	}
+	return out
+}
+
+func listSelectedSessions(t *testing.T, ms *MemoryStore, rv string, labelSelector, fieldSelector string) []string {
+	pred := apistorage.SelectionPredicate{Label: labels.Everything(), Field: fields.Everything()}
+	if labelSelector != "" {
+		pred.Label = labels.SelectorFromSet(labels.Set(parseTestLabels(t, labelSelector)))
+	}
+	if fieldSelector != "" {
+		pred.Field = fields.ParseSelectorOrDie(fieldSelector)
+	}
+	opts := apistorage.ListOptions{Recursive: true, Predicate: pred, ResourceVersion: rv}
+	if rv != "" {
+		opts.ResourceVersionMatch = metav1.ResourceVersionMatchExact
+	}
+	list := &fornaxv1.ApplicationSessionList{}
+	if err := ms.GetList(context.Background(), testKeyPrefix+"/ns", opts, list); err != nil {
+		t.Fatalf("failed to list sessions: %v", err)
+	}
+	names := []string{}
+	for _, v := range list.Items {
+		names = append(names, v.Name)
+	}
+	return names
+}
+
+func parseTestLabels(t *testing.T, selector string) labels.Set {
+	set, err := labels.ConvertSelectorToLabelsMap(selector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return set
+}
+
+func indexedKeys(si *storeIndex, labelValues, fieldValues map[string]string) []string {
+	objs, _ := si.exactMatchObjs(labelValues, fieldValues)
+	keys := []string{}
+	for k := range objs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestStoreIndexMaintainLabelAndFieldIndexes(t *testing.T) {
+	si := newStoreIndex()
+	si.addFieldFuncs(apistorage.IndexerFuncs{"spec.applicationName": testSessionApplicationName}, func(func(key string, obj runtime.Object)) {})
+	si.update("a", newTestLabeledSession("a", "app1", map[string]string{"tier": "web"}))
+	si.update("b", newTestLabeledSession("b", "app1", map[string]string{"tier": "db"}))
+
+	if keys := indexedKeys(si, map[string]string{"tier": "web"}, nil); fmt.Sprint(keys) != "[a]" {
+		t.Errorf("expected [a] labeled tier=web, got %v", keys)
+	}
+	if keys := indexedKeys(si, nil, map[string]string{"spec.applicationName": "app1"}); fmt.Sprint(keys) != "[a b]" {
+		t.Errorf("expected [a b] of app1, got %v", keys)
+	}
+
+	// old label and field values are removed when object is updated
+	si.update("a", newTestLabeledSession("a", "app2", map[string]string{"tier": "db"}))
+	if keys := indexedKeys(si, map[string]string{"tier": "web"}, nil); len(keys) != 0 {
+		t.Errorf("expected no object labeled tier=web, got %v", keys)
+	}
+	if _, found := si.labels["tier"]["web"]; found {
+		t.Errorf("expected empty label value removed from index")
+	}
+	if keys := indexedKeys(si, map[string]string{"tier": "db"}, map[string]string{"spec.applicationName": "app1"}); fmt.Sprint(keys) != "[b]" {
+		t.Errorf("expected [b] labeled tier=db of app1, got %v", keys)
+	}
+
+	si.delete("b")
+	if keys := indexedKeys(si, map[string]string{"tier": "db"}, nil); fmt.Sprint(keys) != "[a]" {
+		t.Errorf("expected [a] labeled tier=db after b is deleted, got %v", keys)
+	}
+	if _, found := si.objs["b"]; found {
+		t.Errorf("expected deleted object removed from index")
+	}
+
+	// a field without index can not be served
+	if _, served := si.exactMatchObjs(nil, map[string]string{"metadata.name": "a"}); served {
+		t.Errorf("expected not indexed field is not served by index")
+	}
+}
+
+func TestGetListServeExactSelectorFromIndex(t *testing.T) {
+	ms := newTestIndexedSessionStore(t)
+	createTestLabeledSession(t, ms, "session-1", "app1", map[string]string{"tier": "web"})
+	createTestLabeledSession(t, ms, "session-2", "app1", map[string]string{"tier": "db"})
+	createTestLabeledSession(t, ms, "session-3", "app2", map[string]string{"tier": "web"})
+	for i := 0; i < 20; i++ {
+		createTestLabeledSession(t, ms, fmt.Sprintf("other-%02d", i), "app3", map[string]string{"tier": "batch"})
+	}
+
+	// only candidates found in index are listed
+	filter := ms.newListFilter(apistorage.SelectionPredicate{Label: labels.SelectorFromSet(labels.Set{"tier": "web"}), Field: fields.Everything()})
+	items, _, err := ms.listAtRev(testKeyPrefix+"/ns", 0, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Errorf("expected 2 candidates from index, got %d", len(items))
+	}
+
+	if names := listSelectedSessions(t, ms, "", "tier=web", ""); fmt.Sprint(names) != "[session-1 session-3]" {
+		t.Errorf("expected sessions labeled tier=web, got %v", names)
+	}
+	if names := listSelectedSessions(t, ms, "", "", "spec.applicationName=app1"); fmt.Sprint(names) != "[session-1 session-2]" {
+		t.Errorf("expected sessions of app1, got %v", names)
+	}
+	if names := listSelectedSessions(t, ms, "", "tier=web", "spec.applicationName=app1"); fmt.Sprint(names) != "[session-1]" {
+		t.Errorf("expected session-1 labeled tier=web of app1, got %v", names)
+	}
+	// not indexed field is matched on candidates
+	if names := listSelectedSessions(t, ms, "", "tier=web", "metadata.name=session-3"); fmt.Sprint(names) != "[session-3]" {
+		t.Errorf("expected session-3, got %v", names)
+	}
+
+	before := updateTestSessionLabels(t, ms, "session-2", map[string]string{"tier": "db"})
+	updateTestSessionLabels(t, ms, "session-1", map[string]string{"tier": "db"})
+	deleteTestSession(t, ms, "session-3")
+	createTestLabeledSession(t, ms, "session-4", "app2", map[string]string{"tier": "web"})
+	if names := listSelectedSessions(t, ms, "", "tier=web", ""); fmt.Sprint(names) != "[session-4]" {
+		t.Errorf("expected session-4 labeled tier=web after label change and delete, got %v", names)
+	}
+	if names := listSelectedSessions(t, ms, "", "tier=db", ""); fmt.Sprint(names) != "[session-1 session-2]" {
+		t.Errorf("expected sessions labeled tier=db after label change, got %v", names)
+	}
+
+	// list as of old revision see objects which matched then, though they do not match now
+	if names := listSelectedSessions(t, ms, before.ResourceVersion, "tier=web", ""); fmt.Sprint(names) != "[session-1 session-3]" {
+		t.Errorf("expected sessions labeled tier=web as of %s, got %v", before.ResourceVersion, names)
+	}
+}
+
+func listTestSessionsByIndex(t *testing.T, ms *MemoryStore, indexName, indexedValue string) []string {
+	list := &fornaxv1.ApplicationSessionList{}
+	if err := ms.ListByIndex(context.Background(), testKeyPrefix+"/ns", indexName, indexedValue, list); err != nil {
+		t.Fatalf("failed to list sessions by index: %v", err)
+	}
+	names := []string{}
+	for _, v := range list.Items {
+		names = append(names, v.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestListByIndex(t *testing.T) {
+	ms := newTestSessionStore(t)
+	createTestLabeledSession(t, ms, "session-1", "app1", map[string]string{"tier": "web"})
+	createTestLabeledSession(t, ms, "session-2", "app2", map[string]string{"tier": "web"})
+
+	// objects already in store are indexed when indexer is added
+	indexers := cache.Indexers{
+		"f:spec.applicationName": func(obj interface{}) ([]string, error) {
+			return []string{obj.(*fornaxv1.ApplicationSession).Spec.ApplicationName}, nil
+		},
+	}
+	if err := ms.AddIndexers(indexers); err != nil {
+		t.Fatal(err)
+	}
+	if err := ms.AddIndexers(indexers); err == nil {
+		t.Errorf("expected error when indexer is added twice")
+	}
+	createTestLabeledSession(t, ms, "session-3", "app1", nil)
+	if names := listTestSessionsByIndex(t, ms, "f:spec.applicationName", "app1"); fmt.Sprint(names) != "[session-1 session-3]" {
+		t.Errorf("expected sessions of app1, got %v", names)
+	}
+
+	out := &fornaxv1.ApplicationSession{}
+	err := ms.GuaranteedUpdate(context.Background(), fmt.Sprintf("%s/ns/%s", testKeyPrefix, "session-1"), out, false, nil, func(input runtime.Object, res apistorage.ResponseMeta) (runtime.Object, *uint64, error) {
+		session := input.(*fornaxv1.ApplicationSession).DeepCopy()
+		session.Spec.ApplicationName = "app2"
+		return session, nil, nil
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deleteTestSession(t, ms, "session-3")
+	if names := listTestSessionsByIndex(t, ms, "f:spec.applicationName", "app1"); len(names) != 0 {
+		t.Errorf("expected no session of app1 after update and delete, got %v", names)
+	}
+	if names := listTestSessionsByIndex(t, ms, "f:spec.applicationName", "app2"); fmt.Sprint(names) != "[session-1 session-2]" {
+		t.Errorf("expected sessions of app2, got %v", names)
+	}
+
+	if err := ms.ListByIndex(context.Background(), testKeyPrefix+"/ns", "f:unknown", "v", &fornaxv1.ApplicationSessionList{}); err == nil {
+		t.Errorf("expected error listing by unknown index")
+	}
+}
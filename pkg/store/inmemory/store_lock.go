@@ -0,0 +1,90 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"sync/atomic"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// writerWaitTracker track writers waiting for a lock, since is when lock last made progress while writers are waiting,
+// i.e. first writer started waiting or a writer acquired lock with others still waiting,
+// a growing blocked duration means no writer get lock, which is how a store deadlock or a stuck lock holder look like
+type writerWaitTracker struct {
+	waiters int32
+	since   int64
+}
+
+func (t *writerWaitTracker) begin() {
+	if atomic.AddInt32(&t.waiters, 1) == 1 {
+		atomic.StoreInt64(&t.since, time.Now().UnixNano())
+	}
+}
+
+func (t *writerWaitTracker) acquired() {
+	if atomic.AddInt32(&t.waiters, -1) > 0 {
+		atomic.StoreInt64(&t.since, time.Now().UnixNano())
+	}
+}
+
+func (t *writerWaitTracker) blockedFor() time.Duration {
+	if atomic.LoadInt32(&t.waiters) <= 0 {
+		return 0
+	}
+	return time.Since(time.Unix(0, atomic.LoadInt64(&t.since)))
+}
+
+// lockRev take revmu write lock, blocked writers are reported to watchdog
+func (ms *MemoryStore) lockRev() {
+	ms.revWriters.begin()
+	ms.revmu.Lock()
+	ms.revWriters.acquired()
+}
+
+// RevLockBlockedFor return how long writers have been blocked on revision lock
+func (ms *MemoryStore) RevLockBlockedFor() time.Duration {
+	return ms.revWriters.blockedFor()
+}
+
+// ShedWatchers stop lagging watchers, whose queue is at least half full, their clients have to watch again from a newer revision,
+// it's a self heal action when store is overloaded, return number of stopped watchers
+func (ms *MemoryStore) ShedWatchers() int {
+	ms.watchersMu.Lock()
+	defer ms.watchersMu.Unlock()
+	shed := shedLaggingWatchers(ms.watchers)
+	if shed > 0 {
+		klog.InfoS("Shed lagging watchers", "resource", ms.groupResource.String(), "watchers", len(ms.watchers), "shed", shed)
+	}
+	return shed
+}
+
+// shedLaggingWatchers stop watchers whose queue is at least half full, caller must hold lock of watchers
+func shedLaggingWatchers(watchers []*memoryStoreWatcher) int {
+	shed := 0
+	for _, v := range watchers {
+		if v.stopped() || v.queue.len()*2 < v.queue.size {
+			continue
+		}
+		slowWatcherClosed.WithLabelValues(v.resource).Inc()
+		v.queue.close()
+		v.Stop()
+		shed += 1
+	}
+	return shed
+}
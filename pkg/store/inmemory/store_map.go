@@ -19,6 +19,7 @@ package inmemory
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"centaurusinfra.io/fornax-serverless/pkg/store"
 
@@ -33,6 +34,13 @@ type objEvent struct {
 	rev       uint64
 	isDeleted bool
 	isCreated bool
+	// revision of object before it's deleted, old object of delete event carry delete revision
+	prevRev uint64
+	// when event is committed and how long commit waited for revision lock, used to measure watch latency
+	commitTime     time.Time
+	commitLockWait time.Duration
+	// bookmark only carry a object with latest revision, it has no key
+	isBookmark bool
 }
 
 type objWithIndex struct {
@@ -52,12 +60,13 @@ func (list *objList) Len() int {
 	return (len(list.objs))
 }
 
-// shrink this list to specified length, by removing nil obj or obj is marked as deleted
-func (list *objList) shrink(length uint64) {
-	diff := uint64(list.Len()) - length
-	if diff <= 0 {
-		return
+// shrink this list to specified length, by removing nil obj or obj is marked as deleted,
+// return largest revision of removed deleted obj, deleted events before this revision can not be replayed anymore
+func (list *objList) shrink(length uint64) (compactedRev uint64) {
+	if uint64(list.Len()) <= length {
+		return 0
 	}
+	diff := uint64(list.Len()) - length
 	newList := make([]*objWithIndex, length)
 	i := uint64(0)
 	lastIndex := uint64(0)
@@ -65,6 +74,11 @@ func (list *objList) shrink(length uint64) {
 		if (v == nil || v.deleted) && diff > 0 {
 			// skip one nil or deleted object, reduce 1 from diff
 			diff -= 1
+			if v != nil {
+				if rev, _ := store.GetObjectResourceVersion(v.obj); rev > compactedRev {
+					compactedRev = rev
+				}
+			}
 			continue
 		} else {
 			if i < length {
@@ -82,6 +96,7 @@ func (list *objList) shrink(length uint64) {
 	}
 	list.objs = newList
 	list.lastObjIndex = lastIndex
+	return compactedRev
 }
 
 func (list *objList) grow(length uint64) {
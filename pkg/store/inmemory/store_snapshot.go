@@ -0,0 +1,228 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+
+	"centaurusinfra.io/fornax-serverless/pkg/store"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/apimachinery/pkg/runtime"
+	apistorage "k8s.io/apiserver/pkg/storage"
+)
+
+// snapshotObjList copy slot pointers of revSortedObjList, objWithIndex is never changed after it's committed,
+// a update put a new objWithIndex into a new slot, so, the copy is a stable view while writes continue on original list,
+// it also return latest revision all writes before it are committed
+func (ms *MemoryStore) snapshotObjList() ([]*objWithIndex, uint64) {
+	ms.revmu.RLock()
+	defer ms.revmu.RUnlock()
+	lastIndex := atomic.LoadUint64(&ms.revSortedObjList.lastObjIndex)
+	if lastIndex >= uint64(ms.revSortedObjList.Len()) {
+		lastIndex = uint64(ms.revSortedObjList.Len()) - 1
+	}
+	objs := make([]*objWithIndex, lastIndex+1)
+	copy(objs, ms.revSortedObjList.objs[0:lastIndex+1])
+	return objs, ms.snapshotRevNoLock()
+}
+
+// snapshotRevNoLock return latest revision all writes before it are committed, caller must hold rev lock
+func (ms *MemoryStore) snapshotRevNoLock() uint64 {
+	snapshotRev := atomic.LoadUint64(&_MemoryRev)
+	for rev := range ms.pendingRevs {
+		if rev-1 < snapshotRev {
+			snapshotRev = rev - 1
+		}
+	}
+	return snapshotRev
+}
+
+// snapshotIndexedObjs return committed objects matching exact requirements of filter found in store index,
+// index is updated when slot is committed, so, it's consistent with revisions when it's read holding rev lock
+func (ms *MemoryStore) snapshotIndexedObjs(filter *listFilter) (objs map[string]runtime.Object, snapshotRev, committedRev uint64, served bool) {
+	ms.revmu.RLock()
+	defer ms.revmu.RUnlock()
+	objs, served = ms.index.exactMatchObjs(filter.labelValues, filter.fieldValues)
+	return objs, ms.snapshotRevNoLock(), ms.committedRev, served
+}
+
+// listItem is state of a object as of a revision
+type listItem struct {
+	key string
+	obj runtime.Object
+	rev uint64
+}
+
+// listAtRev return state of all objects under key prefix as of rev and the revision, latest committed revision is used if rev is 0,
+// world is only locked when slot pointers are copied, objects changed after rev are rewound using watch event cache,
+// resource expired error is returned if rev is older than compaction point or events after rev are evicted from cache,
+// if filter has exact requirements served by store index, only candidate objects found in index are returned, caller still need to match them
+func (ms *MemoryStore) listAtRev(key string, rev uint64, filter *listFilter) ([]*listItem, uint64, error) {
+	if !strings.HasSuffix(key, "/") {
+		key += "/"
+	}
+	if filter != nil && filter.indexed() {
+		if items, listRev, served, err := ms.listIndexedAtRev(key, rev, filter); served {
+			return items, listRev, err
+		}
+	}
+
+	objs, snapshotRev := ms.snapshotObjList()
+	if rev == 0 {
+		rev = snapshotRev
+	}
+	if rev > snapshotRev {
+		return nil, 0, apistorage.NewTooLargeResourceVersionError(rev, snapshotRev, 1)
+	}
+	// compaction could happen after copy, deleted objects removed by it are still in copy, check it after copy,
+	// objects deleted after rev and removed by compaction are recovered from watch event cache if cache still cover rev
+	var firstEvents map[string]*objEvent
+	var seen map[string]bool
+	if err := ms.checkCompactedRev(rev); err != nil {
+		found := false
+		if firstEvents, found = ms.watchEventCache.firstObjEventsAfterRev(key, rev); !found {
+			return nil, 0, err
+		}
+		seen = map[string]bool{}
+	}
+
+	items := []*listItem{}
+	for _, v := range objs {
+		if v == nil || !strings.HasPrefix(v.key, key) {
+			continue
+		}
+		if seen != nil {
+			seen[v.key] = true
+		}
+		objRV, _ := store.GetObjectResourceVersion(v.obj)
+		obj := v.obj
+		if objRV > rev {
+			// object changed after rev, use old object of first event after rev
+			if firstEvents == nil {
+				found := false
+				firstEvents, found = ms.watchEventCache.firstObjEventsAfterRev(key, rev)
+				if !found {
+					return nil, 0, apierrors.NewResourceExpired(fmt.Sprintf("too old resource version: %d, events after it are evicted from watch cache", rev))
+				}
+			}
+			event, found := firstEvents[v.key]
+			if !found {
+				return nil, 0, apistorage.NewInternalError(fmt.Sprintf("can not find event of %s after revision %d", v.key, rev))
+			}
+			if event.isCreated {
+				continue
+			}
+			obj, objRV = objBeforeEvent(event)
+		} else if v.deleted {
+			continue
+		}
+		items = append(items, &listItem{key: v.key, obj: obj, rev: objRV})
+	}
+	for k, event := range firstEvents {
+		if seen == nil || seen[k] || event.isCreated {
+			continue
+		}
+		obj, objRV := objBeforeEvent(event)
+		items = append(items, &listItem{key: k, obj: obj, rev: objRV})
+	}
+	return items, rev, nil
+}
+
+// listIndexedAtRev return candidate objects found in store index as of rev, objects changed after rev are replaced by
+// their old objects in watch event cache whether they are in index or not, served is false if index can not serve filter
+// or watch event cache does not cover rev, then caller fall back to scan slots
+func (ms *MemoryStore) listIndexedAtRev(key string, rev uint64, filter *listFilter) (items []*listItem, listRev uint64, served bool, err error) {
+	objs, snapshotRev, committedRev, served := ms.snapshotIndexedObjs(filter)
+	if !served {
+		return nil, 0, false, nil
+	}
+	if rev == 0 {
+		rev = snapshotRev
+	}
+	if rev > snapshotRev {
+		return nil, 0, true, apistorage.NewTooLargeResourceVersionError(rev, snapshotRev, 1)
+	}
+	var firstEvents map[string]*objEvent
+	if rev < committedRev {
+		found := false
+		if firstEvents, found = ms.watchEventCache.firstObjEventsAfterRev(key, rev); !found {
+			return nil, 0, false, nil
+		}
+	}
+
+	items = []*listItem{}
+	for k, obj := range objs {
+		if !strings.HasPrefix(k, key) {
+			continue
+		}
+		if _, found := firstEvents[k]; found {
+			continue
+		}
+		objRV, _ := store.GetObjectResourceVersion(obj)
+		items = append(items, &listItem{key: k, obj: obj, rev: objRV})
+	}
+	for k, event := range firstEvents {
+		if event.isCreated {
+			continue
+		}
+		obj, objRV := objBeforeEvent(event)
+		items = append(items, &listItem{key: k, obj: obj, rev: objRV})
+	}
+	return items, rev, true, nil
+}
+
+// objBeforeEvent return old object of a event and its revision
+func objBeforeEvent(event *objEvent) (runtime.Object, uint64) {
+	obj := event.oldObj
+	objRV, _ := store.GetObjectResourceVersion(obj)
+	if event.isDeleted && event.prevRev > 0 {
+		// old object of delete event carry delete revision, restore revision before it's deleted
+		obj = obj.DeepCopyObject()
+		objRV = event.prevRev
+	}
+	return obj, objRV
+}
+
+// SnapshotList return all objects under key prefix as of rev, latest committed revision is used if rev is 0
+func (ms *MemoryStore) SnapshotList(ctx context.Context, key string, rev uint64, listObj runtime.Object) error {
+	listPtr, err := meta.GetItemsPtr(listObj)
+	if err != nil {
+		return err
+	}
+	listRetVal, err := conversion.EnforcePtr(listPtr)
+	if err != nil || listRetVal.Kind() != reflect.Slice {
+		return fmt.Errorf("need ptr to slice: %v", err)
+	}
+
+	items, rev, err := ms.listAtRev(key, rev, nil)
+	if err != nil {
+		return err
+	}
+	for _, v := range items {
+		if err := store.AppendListItem(listRetVal, v.obj, v.rev, apistorage.Everything); err != nil {
+			return err
+		}
+	}
+	return store.UpdateList(listObj, rev, "", nil)
+}
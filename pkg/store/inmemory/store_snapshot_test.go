@@ -0,0 +1,102 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apistorage "k8s.io/apiserver/pkg/storage"
+)
+
+func snapshotTestSessions(t *testing.T, ms *MemoryStore, rev uint64) (map[string]string, uint64) {
+	list := &fornaxv1.ApplicationSessionList{}
+	if err := ms.SnapshotList(context.Background(), testKeyPrefix+"/ns", rev, list); err != nil {
+		t.Fatalf("failed to snapshot list sessions as of %d: %v", rev, err)
+	}
+	sessions := map[string]string{}
+	for _, v := range list.Items {
+		sessions[v.Name] = v.ResourceVersion
+	}
+	return sessions, mustParseRV(t, list.ResourceVersion)
+}
+
+func sortedNames(sessions map[string]string) []string {
+	names := []string{}
+	for k := range sessions {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestSnapshotListAtRevision(t *testing.T) {
+	ms := newTestSessionStore(t)
+	s1 := createTestSession(t, ms, "session-1")
+	s2 := createTestSession(t, ms, "session-2")
+	before, rev := snapshotTestSessions(t, ms, 0)
+	if fmt.Sprint(sortedNames(before)) != "[session-1 session-2]" {
+		t.Fatalf("expected session-1 and session-2, got %v", before)
+	}
+
+	// changes after rev are rewound from watch event cache
+	updateTestSessionLabels(t, ms, "session-1", map[string]string{"k": "v"})
+	deleteTestSession(t, ms, "session-2")
+	createTestSession(t, ms, "session-3")
+
+	snapshot, snapshotRev := snapshotTestSessions(t, ms, rev)
+	if snapshotRev != rev {
+		t.Errorf("expected list revision %d, got %d", rev, snapshotRev)
+	}
+	if fmt.Sprint(sortedNames(snapshot)) != "[session-1 session-2]" {
+		t.Errorf("expected sessions as of %d, got %v", rev, snapshot)
+	}
+	if snapshot["session-1"] != s1.ResourceVersion || snapshot["session-2"] != s2.ResourceVersion {
+		t.Errorf("expected revisions of sessions as of %d, got %v", rev, snapshot)
+	}
+
+	current, currentRev := snapshotTestSessions(t, ms, 0)
+	if currentRev <= rev || fmt.Sprint(sortedNames(current)) != "[session-1 session-3]" {
+		t.Errorf("expected current sessions after %d, got %v as of %d", rev, current, currentRev)
+	}
+	if current["session-1"] == s1.ResourceVersion {
+		t.Errorf("expected updated revision of session-1")
+	}
+
+	err := ms.SnapshotList(context.Background(), testKeyPrefix+"/ns", currentRev+1000, &fornaxv1.ApplicationSessionList{})
+	if !apistorage.IsTooLargeResourceVersion(err) {
+		t.Errorf("expected too large resource version error, got %v", err)
+	}
+}
+
+func TestSnapshotListEvictedRevisionExpired(t *testing.T) {
+	ms := newTestCompactingSessionStore(t, 2)
+	createTestSession(t, ms, "session-1")
+	_, rev := snapshotTestSessions(t, ms, 0)
+	for i := 0; i < 5; i++ {
+		updateTestSessionLabels(t, ms, "session-1", map[string]string{"k": fmt.Sprint(i)})
+	}
+	// events after rev are evicted from watch event cache, object can not be rewound
+	err := ms.SnapshotList(context.Background(), testKeyPrefix+"/ns", rev, &fornaxv1.ApplicationSessionList{})
+	if !apierrors.IsResourceExpired(err) {
+		t.Errorf("expected resource expired listing revision evicted from watch event cache, got %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"container/heap"
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+type ttlItem struct {
+	key      string
+	uid      types.UID
+	expireAt time.Time
+	index    int
+}
+
+// ttlHeap is a min heap of object expiration time
+type ttlHeap []*ttlItem
+
+func (h ttlHeap) Len() int { return len(h) }
+
+func (h ttlHeap) Less(i, j int) bool { return h[i].expireAt.Before(h[j].expireAt) }
+
+func (h ttlHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *ttlHeap) Push(x interface{}) {
+	item := x.(*ttlItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *ttlHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
+
+// objectTTL track expiration of objects created with ttl, a object is identified by key and uid,
+// so, a object recreated with same key after deletion does not inherit old expiration
+type objectTTL struct {
+	mu     sync.Mutex
+	items  ttlHeap
+	byKey  map[string]*ttlItem
+	wakeup chan struct{}
+}
+
+func newObjectTTL() *objectTTL {
+	return &objectTTL{
+		mu:     sync.Mutex{},
+		items:  ttlHeap{},
+		byKey:  map[string]*ttlItem{},
+		wakeup: make(chan struct{}, 1),
+	}
+}
+
+func (t *objectTTL) add(key string, uid types.UID, ttl time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	expireAt := time.Now().Add(ttl)
+	if item, found := t.byKey[key]; found {
+		item.uid = uid
+		item.expireAt = expireAt
+		heap.Fix(&t.items, item.index)
+	} else {
+		item := &ttlItem{key: key, uid: uid, expireAt: expireAt}
+		heap.Push(&t.items, item)
+		t.byKey[key] = item
+	}
+	select {
+	case t.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func (t *objectTTL) remove(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if item, found := t.byKey[key]; found {
+		heap.Remove(&t.items, item.index)
+		delete(t.byKey, key)
+	}
+}
+
+// popExpired remove and return all items expired at now, and duration until next expiration, zero if no more item
+func (t *objectTTL) popExpired(now time.Time) ([]*ttlItem, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	expired := []*ttlItem{}
+	for len(t.items) > 0 {
+		item := t.items[0]
+		if item.expireAt.After(now) {
+			return expired, item.expireAt.Sub(now)
+		}
+		heap.Pop(&t.items)
+		delete(t.byKey, item.key)
+		expired = append(expired, item)
+	}
+	return expired, 0
+}
+
+// run call expire func on expired objects until stopped, it sleep until next expiration or a new item is added
+func (t *objectTTL) run(ctx context.Context, stopCh <-chan interface{}, expire func(key string, uid types.UID)) {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+	for {
+		expired, next := t.popExpired(time.Now())
+		for _, v := range expired {
+			expire(v.key, v.uid)
+		}
+		if next == 0 {
+			next = time.Hour
+		}
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(next)
+		select {
+		case <-timer.C:
+		case <-t.wakeup:
+		case <-stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func objectUID(obj runtime.Object) types.UID {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return ""
+	}
+	return accessor.GetUID()
+}
+
+// expireObject delete object of key if it's still the one created with ttl, a Deleted event is sent to watchers
+func (ms *MemoryStore) expireObject(key string, uid types.UID) {
+	existingObj := ms.kvs.get(strings.Split(key, "/"))
+	if existingObj == nil || objectUID(existingObj.obj) != uid {
+		return
+	}
+	_, event, err := ms.deleteExistingObj(context.Background(), key, existingObj, nil, nil)
+	if err != nil {
+		klog.ErrorS(err, "Failed to delete expired object", "key", key)
+		return
+	}
+	klog.InfoS("Deleted expired object", "key", key, "resource", ms.groupResource)
+	ms.sendEvent(event)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	apistorage "k8s.io/apiserver/pkg/storage"
+)
+
+func createTestSessionWithTTL(t *testing.T, ms *MemoryStore, name string, uid types.UID, ttl uint64) {
+	session := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, UID: uid}}
+	if err := ms.Create(context.Background(), fmt.Sprintf("%s/ns/%s", testKeyPrefix, name), session, &fornaxv1.ApplicationSession{}, ttl); err != nil {
+		t.Fatalf("failed to create session %s: %v", name, err)
+	}
+}
+
+func TestObjectTTLPopExpiredInOrder(t *testing.T) {
+	ttl := newObjectTTL()
+	ttl.add("c", "c", 3*time.Second)
+	ttl.add("a", "a", time.Second)
+	ttl.add("b", "b", 2*time.Second)
+	// a ttl added again for same key replace old expiration
+	ttl.add("c", "c2", 500*time.Millisecond)
+	ttl.remove("b")
+
+	now := time.Now()
+	expired, next := ttl.popExpired(now)
+	if len(expired) != 0 || next <= 0 || next > 500*time.Millisecond {
+		t.Errorf("expected nothing expired and next expiration in 500ms, got %d expired and next %s", len(expired), next)
+	}
+	expired, next = ttl.popExpired(now.Add(5 * time.Second))
+	if len(expired) != 2 || expired[0].key != "c" || expired[0].uid != "c2" || expired[1].key != "a" {
+		t.Errorf("expected c then a expired, got %v", expired)
+	}
+	if next != 0 {
+		t.Errorf("expected no more expiration, got %s", next)
+	}
+}
+
+func TestCreateWithTTLExpireObject(t *testing.T) {
+	ms := newTestSessionStore(t)
+	w, err := watchTestSessions(ms, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+	createTestSessionWithTTL(t, ms, "session-1", "uid-1", 1)
+
+	events := receiveWatchEvents(t, w, 2)
+	if events[0].Type != watch.Added || events[1].Type != watch.Deleted {
+		t.Errorf("expected added and deleted events of expired session, got %s and %s", events[0].Type, events[1].Type)
+	}
+	if _, err := getTestSession(ms, "session-1"); !apistorage.IsNotFound(err) {
+		t.Errorf("expected expired session not found, got %v", err)
+	}
+}
+
+func TestRecreatedObjectDoesNotInheritTTL(t *testing.T) {
+	ms := newTestSessionStore(t)
+	createTestSessionWithTTL(t, ms, "session-1", "uid-1", 1)
+	deleteTestSession(t, ms, "session-1")
+	createTestSessionWithTTL(t, ms, "session-1", "uid-2", 0)
+
+	time.Sleep(1500 * time.Millisecond)
+	session, err := getTestSession(ms, "session-1")
+	if err != nil {
+		t.Fatalf("expected recreated session not expired, %v", err)
+	}
+	if session.UID != "uid-2" {
+		t.Errorf("expected recreated session uid-2, got %s", session.UID)
+	}
+}
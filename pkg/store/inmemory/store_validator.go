@@ -0,0 +1,86 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"context"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidatorFunc check invariants of a object before it's committed into memory store, oldObj is nil when object is created,
+// object is not stored if any error is returned
+type ValidatorFunc func(ctx context.Context, obj runtime.Object, oldObj runtime.Object) field.ErrorList
+
+var (
+	_validatorsMu = sync.RWMutex{}
+	_validators   = map[schema.GroupResource][]ValidatorFunc{}
+)
+
+// RegisterValidator add a validator of a GroupResource, it apply to all memory stores of GroupResource including shards,
+// validators are called in registration order on Create, GuaranteedUpdate and CreateOrReplace
+func RegisterValidator(groupResource schema.GroupResource, validator ValidatorFunc) {
+	_validatorsMu.Lock()
+	defer _validatorsMu.Unlock()
+	_validators[groupResource] = append(_validators[groupResource], validator)
+}
+
+type replicatedWriteKey struct{}
+
+// WithReplicatedWrite mark writes of a follower replica applying changes of leader, they are not validated again,
+// leader has validated them and follower does not have state validators depend on
+func WithReplicatedWrite(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replicatedWriteKey{}, true)
+}
+
+func isReplicatedWrite(ctx context.Context) bool {
+	replicated, _ := ctx.Value(replicatedWriteKey{}).(bool)
+	return replicated
+}
+
+// validate run registered validators of store's GroupResource, errors are returned as a Invalid api error
+func (ms *MemoryStore) validate(ctx context.Context, obj runtime.Object, oldObj runtime.Object) error {
+	if isReplicatedWrite(ctx) {
+		return nil
+	}
+	_validatorsMu.RLock()
+	validators := _validators[ms.groupResource]
+	_validatorsMu.RUnlock()
+
+	errorList := field.ErrorList{}
+	for _, validator := range validators {
+		errorList = append(errorList, validator(ctx, obj, oldObj)...)
+	}
+	if len(errorList) == 0 {
+		return nil
+	}
+
+	name := ""
+	if accessor, err := meta.Accessor(obj); err == nil {
+		name = accessor.GetName()
+	}
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if len(kind) == 0 {
+		kind = ms.groupResource.Resource
+	}
+	return apierrors.NewInvalid(schema.GroupKind{Group: ms.groupResource.Group, Kind: kind}, name, errorList)
+}
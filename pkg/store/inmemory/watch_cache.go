@@ -17,42 +17,101 @@ limitations under the License.
 package inmemory
 
 import (
+	"sort"
 	"strings"
 	"sync"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apiserver/pkg/storage"
 )
 
+// watchCache is a bounded ring buffer of recent store events, a watcher reconnecting with a revision still covered by cache
+// replay real added/modified/deleted events, oldest event is overwritten when buffer is full
 type watchCache struct {
-	size    uint64
-	events  []*objEvent
-	cacheMu sync.RWMutex
+	size       uint64
+	events     []*objEvent
+	start      uint64
+	count      uint64
+	evictedRev uint64
+	cacheMu    sync.RWMutex
 }
 
-func (wc *watchCache) getObjEventsAfterRev(key string, rev uint64, opts storage.ListOptions) (objEvents []*objEvent, err error) {
+func newWatchCache(size int) *watchCache {
+	return &watchCache{
+		size:       uint64(size),
+		events:     make([]*objEvent, size),
+		start:      0,
+		count:      0,
+		evictedRev: 0,
+		cacheMu:    sync.RWMutex{},
+	}
+}
+
+// getObjEventsAfterRev return cached events of key after rev sorted by revision,
+// return false if events after rev could have been evicted, caller should fall back to list current objects
+func (wc *watchCache) getObjEventsAfterRev(key string, rev uint64, opts storage.ListOptions) (objEvents []*objEvent, found bool) {
 	wc.cacheMu.RLock()
 	defer wc.cacheMu.RUnlock()
 
-	for _, event := range wc.events {
-		if strings.HasPrefix(event.key, key) && event.rev > rev {
+	if wc.size == 0 || rev < wc.evictedRev {
+		return nil, false
+	}
+
+	objEvents = []*objEvent{}
+	for i := uint64(0); i < wc.count; i++ {
+		event := wc.events[(wc.start+i)%wc.size]
+		if !strings.HasPrefix(event.key, key) {
+			continue
+		}
+		if event.rev > rev || (opts.ResourceVersionMatch == metav1.ResourceVersionMatchNotOlderThan && event.rev == rev) {
 			objEvents = append(objEvents, event)
 		}
 	}
+	// events are added when they are sent to watchers, concurrent updates could add them slightly out of order
+	sort.SliceStable(objEvents, func(i, j int) bool { return objEvents[i].rev < objEvents[j].rev })
 
-	return objEvents, nil
+	return objEvents, true
 }
 
 func (wc *watchCache) addObjEvents(event *objEvent) {
+	if wc.size == 0 {
+		return
+	}
 	wc.cacheMu.Lock()
 	defer wc.cacheMu.Unlock()
-	wc.events = append(wc.events, event)
+	if wc.count < wc.size {
+		wc.events[(wc.start+wc.count)%wc.size] = event
+		wc.count += 1
+		return
+	}
+
+	// buffer is full, overwrite oldest event
+	if oldest := wc.events[wc.start]; oldest.rev > wc.evictedRev {
+		wc.evictedRev = oldest.rev
+	}
+	wc.events[wc.start] = event
+	wc.start = (wc.start + 1) % wc.size
 }
 
-func (wc *watchCache) shrink() {
-	if uint64(len(wc.events)) > wc.size {
-		events := wc.events[0:wc.size]
-		wc.events = events
-		wc.cacheMu.Lock()
-		defer wc.cacheMu.Unlock()
+// firstObjEventsAfterRev return earliest cached event of every key under prefix after rev,
+// return false if events after rev could have been evicted
+func (wc *watchCache) firstObjEventsAfterRev(prefix string, rev uint64) (map[string]*objEvent, bool) {
+	wc.cacheMu.RLock()
+	defer wc.cacheMu.RUnlock()
+
+	if wc.size == 0 || rev < wc.evictedRev {
+		return nil, false
+	}
+
+	firstEvents := map[string]*objEvent{}
+	for i := uint64(0); i < wc.count; i++ {
+		event := wc.events[(wc.start+i)%wc.size]
+		if event.rev <= rev || !strings.HasPrefix(event.key, prefix) {
+			continue
+		}
+		if first, found := firstEvents[event.key]; !found || event.rev < first.rev {
+			firstEvents[event.key] = event
+		}
 	}
+	return firstEvents, true
 }
@@ -0,0 +1,284 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	"centaurusinfra.io/fornax-serverless/pkg/store"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	apistorage "k8s.io/apiserver/pkg/storage"
+)
+
+// newTestCompactingSessionStore return a store which compact every deleted slot and keep watchEventCacheSize events in watch cache
+func newTestCompactingSessionStore(t *testing.T, watchEventCacheSize int) *MemoryStore {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	gr := fornaxv1.ApplicationSessionGrv.GroupResource()
+	config := store.DefaultResourceStorageConfiguration(gr)
+	config.WatchEventCacheSize = watchEventCacheSize
+	config.RetentionSlots = 1
+	return NewMemoryStore(ctx, gr, testKeyPrefix,
+		func() runtime.Object { return &fornaxv1.ApplicationSession{} },
+		func() runtime.Object { return &fornaxv1.ApplicationSessionList{} },
+		config)
+}
+
+func deleteTestSession(t *testing.T, ms *MemoryStore, name string) {
+	if err := ms.Delete(context.Background(), fmt.Sprintf("%s/ns/%s", testKeyPrefix, name), &fornaxv1.ApplicationSession{}, nil, apistorage.ValidateAllObjectFunc, nil); err != nil {
+		t.Fatalf("failed to delete session %s: %v", name, err)
+	}
+}
+
+func watchTestSessions(ms *MemoryStore, rv string) (watch.Interface, error) {
+	return ms.Watch(context.Background(), testKeyPrefix+"/ns", apistorage.ListOptions{
+		ResourceVersion: rv,
+		Recursive:       true,
+		Predicate:       apistorage.Everything,
+	})
+}
+
+func receiveWatchEvents(t *testing.T, w watch.Interface, n int) []watch.Event {
+	events := []watch.Event{}
+	for len(events) < n {
+		select {
+		case e, ok := <-w.ResultChan():
+			if !ok {
+				t.Fatalf("watch closed after %d events, expected %d", len(events), n)
+			}
+			events = append(events, e)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("received %d events, expected %d", len(events), n)
+		}
+	}
+	return events
+}
+
+func TestWatchEvictedRevisionAfterCompactionExpired(t *testing.T) {
+	ms := newTestCompactingSessionStore(t, 2)
+	session := createTestSession(t, ms, "session-1")
+	deleteTestSession(t, ms, "session-1")
+	for i := 2; i < 5; i++ {
+		createTestSession(t, ms, fmt.Sprintf("session-%d", i))
+	}
+	if removed := ms.Compact(CompactionTriggerApi); removed == 0 {
+		t.Fatalf("expected compaction remove deleted slot")
+	}
+
+	// events after revision are evicted from cache and deleted object is removed from object list
+	if _, err := watchTestSessions(ms, session.ResourceVersion); !apierrors.IsResourceExpired(err) {
+		t.Fatalf("expected resource expired, got %v", err)
+	}
+	ms.watchersMu.Lock()
+	defer ms.watchersMu.Unlock()
+	for _, v := range ms.watchers {
+		if !v.stopped() {
+			t.Errorf("expected watcher failed to start is stopped")
+		}
+	}
+}
+
+func TestWatchCompactedRevisionReplayedFromCache(t *testing.T) {
+	ms := newTestCompactingSessionStore(t, 100)
+	session := createTestSession(t, ms, "session-1")
+	createTestSession(t, ms, "session-2")
+	deleteTestSession(t, ms, "session-2")
+	if removed := ms.Compact(CompactionTriggerApi); removed == 0 {
+		t.Fatalf("expected compaction remove deleted slot")
+	}
+	if err := ms.checkCompactedRev(mustParseRV(t, session.ResourceVersion)); !apierrors.IsResourceExpired(err) {
+		t.Fatalf("expected revision compacted, got %v", err)
+	}
+
+	// compaction removed deleted object, cache still cover revision and replay delete event
+	w, err := watchTestSessions(ms, session.ResourceVersion)
+	if err != nil {
+		t.Fatalf("expected watch replayed from cache, got %v", err)
+	}
+	defer w.Stop()
+	events := receiveWatchEvents(t, w, 2)
+	if events[0].Type != watch.Added || events[1].Type != watch.Deleted {
+		t.Fatalf("expected added and deleted events of session-2, got %s, %s", events[0].Type, events[1].Type)
+	}
+	if name := events[1].Object.(*fornaxv1.ApplicationSession).Name; name != "session-2" {
+		t.Errorf("expected deleted session-2, got %s", name)
+	}
+}
+
+func TestListCompactedRevisionFromCache(t *testing.T) {
+	ms := newTestCompactingSessionStore(t, 100)
+	createTestSession(t, ms, "session-1")
+	session := createTestSession(t, ms, "session-2")
+	deleteTestSession(t, ms, "session-2")
+	ms.Compact(CompactionTriggerApi)
+
+	list, err := listTestSessions(ms, session.ResourceVersion, metav1.ResourceVersionMatchExact, 0, "")
+	if err != nil {
+		t.Fatalf("expected list at compacted revision served from cache, got %v", err)
+	}
+	if len(list.Items) != 2 || list.Items[1].Name != "session-2" || list.Items[1].ResourceVersion != session.ResourceVersion {
+		t.Fatalf("expected session-1 and session-2 at revision %s, got %v", session.ResourceVersion, list.Items)
+	}
+}
+
+func TestObjListShrink(t *testing.T) {
+	newObj := func(name string, rev uint64, deleted bool) *objWithIndex {
+		session := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, ResourceVersion: strconv.FormatUint(rev, 10)}}
+		return &objWithIndex{key: name, obj: session, deleted: deleted}
+	}
+	a, b, c := newObj("a", 1, false), newObj("b", 3, true), newObj("c", 4, false)
+	list := &objList{objs: []*objWithIndex{a, nil, b, c, nil}, lastObjIndex: 3}
+
+	compactedRev := list.shrink(3)
+	if compactedRev != 3 {
+		t.Errorf("expected compacted revision of removed deleted object, got %d", compactedRev)
+	}
+	if list.Len() != 3 || list.objs[0] != a || list.objs[1] != c || list.objs[2] != nil {
+		t.Fatalf("expected live objects kept in order, got %v", list.objs)
+	}
+	if a.index != 0 || c.index != 1 || list.lastObjIndex != 1 {
+		t.Errorf("expected indexes updated, got a %d, c %d, last %d", a.index, c.index, list.lastObjIndex)
+	}
+	if rev := list.shrink(5); rev != 0 || list.Len() != 3 {
+		t.Errorf("expected list not shrunk below requested length")
+	}
+}
+
+func TestCompactDeferredWhileSlotReserved(t *testing.T) {
+	ms := newTestCompactingSessionStore(t, 10)
+	for i := 0; i < 5; i++ {
+		createTestSession(t, ms, fmt.Sprintf("session-%d", i))
+		deleteTestSession(t, ms, fmt.Sprintf("session-%d", i))
+	}
+	rev, _, err := ms.reserveRevAndSlot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed := ms.Compact("force"); removed != 0 {
+		t.Errorf("expected compaction deferred while a slot is reserved, removed %d", removed)
+	}
+	ms.abortRev(rev)
+	if removed := ms.Compact("force"); removed == 0 {
+		t.Errorf("expected deleted slots removed after reserved slot is released")
+	}
+}
+
+func TestConcurrentCreateAndCompact(t *testing.T) {
+	ms := newTestCompactingSessionStore(t, 10)
+	done := make(chan struct{})
+	compacted := make(chan struct{})
+	go func() {
+		defer close(compacted)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				ms.Compact("force")
+			}
+		}
+	}()
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				name := fmt.Sprintf("session-%d-%d", w, i)
+				createTestSession(t, ms, name)
+				// deleted slots give compaction something to remove
+				if i%2 == 1 {
+					deleteTestSession(t, ms, name)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(done)
+	<-compacted
+
+	list, err := listTestSessions(ms, "", "", 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 8*25 {
+		t.Fatalf("expected %d sessions after concurrent compaction, got %d", 8*25, len(list.Items))
+	}
+	for w := 0; w < 8; w++ {
+		for i := 0; i < 50; i += 2 {
+			out := &fornaxv1.ApplicationSession{}
+			if err := ms.Get(context.Background(), fmt.Sprintf("%s/ns/session-%d-%d", testKeyPrefix, w, i), apistorage.GetOptions{}, out); err != nil {
+				t.Errorf("expected session-%d-%d found, %v", w, i, err)
+			}
+		}
+	}
+}
+
+func TestWatchCacheEviction(t *testing.T) {
+	wc := newWatchCache(2)
+	for rev := uint64(1); rev <= 3; rev++ {
+		wc.addObjEvents(&objEvent{key: testKeyPrefix + "/ns/a", rev: rev})
+	}
+	if _, found := wc.getObjEventsAfterRev(testKeyPrefix, 0, apistorage.ListOptions{}); found {
+		t.Errorf("expected revision before evicted event not served")
+	}
+	events, found := wc.getObjEventsAfterRev(testKeyPrefix, 1, apistorage.ListOptions{})
+	if !found || len(events) != 2 || events[0].rev != 2 || events[1].rev != 3 {
+		t.Errorf("expected events after evicted revision served, got %v, %v", events, found)
+	}
+}
+
+func TestWatcherReplayOutOfOrderCommit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	newEvent := func(rev uint64) *objEvent {
+		session := &fornaxv1.ApplicationSession{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: fmt.Sprintf("session-%d", rev), ResourceVersion: strconv.FormatUint(rev, 10)}}
+		return &objEvent{key: fmt.Sprintf("%s/ns/%s", testKeyPrefix, session.Name), obj: session, rev: rev, isCreated: true}
+	}
+	watcher := NewMemoryStoreWatcher(ctx, testKeyPrefix, apistorage.ListOptions{Recursive: true, Predicate: apistorage.Everything}, "test", 100, store.SlowWatcherPolicyClose)
+	defer watcher.Stop()
+
+	// revision 10 is replayed, revision 9 is committed later and reach queue after 10
+	go watcher.run(5, []*objEvent{newEvent(10)}, false)
+	for _, rev := range []uint64{9, 10, 11, 4} {
+		watcher.enqueue(newEvent(rev))
+	}
+	got := []string{}
+	for _, e := range receiveWatchEvents(t, watcher, 3) {
+		got = append(got, e.Object.(*fornaxv1.ApplicationSession).ResourceVersion)
+	}
+	if fmt.Sprint(got) != "[10 9 11]" {
+		t.Errorf("expected events 10, 9, 11, got %v", got)
+	}
+}
+
+func mustParseRV(t *testing.T, rv string) uint64 {
+	rev, err := strconv.ParseUint(rv, 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rev
+}
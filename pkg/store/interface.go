@@ -18,17 +18,21 @@ package store
 
 import (
 	"context"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/apiserver/pkg/storage"
 	apistorage "k8s.io/apiserver/pkg/storage"
+	"k8s.io/client-go/tools/cache"
 )
 
 type WatchEventWithOldObj struct {
 	Type      watch.EventType
 	Object    runtime.Object
 	OldObject runtime.Object
+	// when event was committed in store, it's zero for bookmark and events replayed from cache
+	CommitTime time.Time
 }
 
 type WatchWithOldObjInterface interface {
@@ -40,6 +44,17 @@ type ApiStorageInterface interface {
 	apistorage.Interface
 	WatchWithOldObj(ctx context.Context, key string, opts storage.ListOptions) (WatchWithOldObjInterface, error)
 	EnsureUpdateAndDelete(ctx context.Context, key string, ignoreNotFound bool, preconditions *storage.Preconditions, updatedObj runtime.Object, output runtime.Object) error
+	GetOrCreate(ctx context.Context, key string, objToCreate runtime.Object, out runtime.Object) error
+	CreateOrReplace(ctx context.Context, key string, objToCreate runtime.Object, out runtime.Object) error
+	CreateOrUpdate(ctx context.Context, key string, obj runtime.Object, out runtime.Object, mergeFunc func(from runtime.Object, to runtime.Object) error) error
+	// DeleteCollection delete all objects under key prefix, deleted objects are returned in listObj
+	DeleteCollection(ctx context.Context, key string, validateDeletion storage.ValidateObjectFunc, listObj runtime.Object) error
+	// AddIndexers register secondary indexes which are maintained when objects are created, updated or deleted
+	AddIndexers(indexers cache.Indexers) error
+	// ListByIndex list objects under key prefix which have indexedValue in index of indexName
+	ListByIndex(ctx context.Context, key string, indexName, indexedValue string, listObj runtime.Object) error
+	// SnapshotList list all objects under key prefix as of rev, or latest revision if rev is 0, writes are not blocked while listing
+	SnapshotList(ctx context.Context, key string, rev uint64, listObj runtime.Object) error
 }
 
 func IsObjectNotFoundErr(err error) bool {
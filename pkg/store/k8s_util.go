@@ -42,9 +42,13 @@ type continueToken struct {
 
 // AppendListItem decodes and appends the object (if it passes filter) to v, which must be a slice.
 func AppendListItem(v reflect.Value, obj runtime.Object, rev uint64, pred apistorage.SelectionPredicate) error {
-	// being unable to set the version does not prevent the object from being extracted
-	if err := SetObjectResourceVersion(obj, rev); err != nil {
-		return err
+	// being unable to set the version does not prevent the object from being extracted,
+	// obj is shared with store and readers, it's copied before setting a different version and never written in place
+	if objRev, err := GetObjectResourceVersion(obj); err != nil || objRev != rev {
+		obj = obj.DeepCopyObject()
+		if err := SetObjectResourceVersion(obj, rev); err != nil {
+			return err
+		}
 	}
 	if matched, err := pred.Matches(obj); err == nil && matched {
 		v.Set(reflect.Append(v, reflect.ValueOf(obj).Elem()))
@@ -0,0 +1,188 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/storage/value"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+type PayloadCompression string
+
+const (
+	PayloadCompressionNone   PayloadCompression = "none"
+	PayloadCompressionSnappy PayloadCompression = "snappy"
+	PayloadCompressionZstd   PayloadCompression = "zstd"
+
+	DefaultCompressionThresholdBytes = 512
+)
+
+// compressed payload start with a prefix of its compression, json and protobuf objects never start with it,
+// so, a payload without prefix is a uncompressed one written before compression was enabled or smaller than threshold
+var compressedPayloadPrefixes = map[PayloadCompression][]byte{
+	PayloadCompressionSnappy: []byte("fornax:snappy:"),
+	PayloadCompressionZstd:   []byte("fornax:zstd:"),
+}
+
+var (
+	payloadOriginalBytes = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_store_payload",
+			Name:           "original_bytes_total",
+			Help:           "Size of object payloads before compression written into persistent backend of a resource",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource", "compression"},
+	)
+	payloadStoredBytes = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_store_payload",
+			Name:           "stored_bytes_total",
+			Help:           "Size of object payloads after compression written into persistent backend of a resource",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource", "compression"},
+	)
+	payloadCompressionRatio = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem:      "fornax_store_payload",
+			Name:           "compression_ratio",
+			Help:           "Ratio of original size to stored size of compressed object payloads of a resource",
+			Buckets:        []float64{1, 1.5, 2, 3, 4, 6, 8, 12, 16},
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource", "compression"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(payloadOriginalBytes)
+	legacyregistry.MustRegister(payloadStoredBytes)
+	legacyregistry.MustRegister(payloadCompressionRatio)
+}
+
+var _ value.Transformer = &PayloadCompressor{}
+
+// PayloadCompressor compress object payloads of a resource before they are written into persistent backend,
+// it's also a etcd3 value transformer, payloads compressed by any compression are decompressed when read,
+// a payload is stored uncompressed if it's smaller than threshold or compressed one is not smaller
+type PayloadCompressor struct {
+	resource    string
+	compression PayloadCompression
+	threshold   int
+	encoder     *zstd.Encoder
+	decoder     *zstd.Decoder
+}
+
+func NewPayloadCompressor(groupResource schema.GroupResource, compression PayloadCompression, threshold int) (*PayloadCompressor, error) {
+	if len(compression) == 0 {
+		compression = PayloadCompressionNone
+	}
+	if _, found := compressedPayloadPrefixes[compression]; !found && compression != PayloadCompressionNone {
+		return nil, fmt.Errorf("unsupported payload compression %s", compression)
+	}
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	decoder, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	return &PayloadCompressor{
+		resource:    groupResource.String(),
+		compression: compression,
+		threshold:   threshold,
+		encoder:     encoder,
+		decoder:     decoder,
+	}, nil
+}
+
+// Compress return payload of data to write into persistent backend
+func (c *PayloadCompressor) Compress(data []byte) []byte {
+	if c == nil || c.compression == PayloadCompressionNone || len(data) < c.threshold {
+		return data
+	}
+	prefix := compressedPayloadPrefixes[c.compression]
+	out := make([]byte, len(prefix), len(prefix)+len(data))
+	copy(out, prefix)
+	switch c.compression {
+	case PayloadCompressionSnappy:
+		out = append(out, snappy.Encode(nil, data)...)
+	case PayloadCompressionZstd:
+		out = c.encoder.EncodeAll(data, out)
+	}
+	compression := string(c.compression)
+	payloadOriginalBytes.WithLabelValues(c.resource, compression).Add(float64(len(data)))
+	if len(out) >= len(data) {
+		payloadStoredBytes.WithLabelValues(c.resource, compression).Add(float64(len(data)))
+		return data
+	}
+	payloadStoredBytes.WithLabelValues(c.resource, compression).Add(float64(len(out)))
+	payloadCompressionRatio.WithLabelValues(c.resource, compression).Observe(float64(len(data)) / float64(len(out)))
+	return out
+}
+
+// Decompress return original data of a payload read from persistent backend, and compression of payload
+func (c *PayloadCompressor) Decompress(payload []byte) ([]byte, PayloadCompression, error) {
+	for compression, prefix := range compressedPayloadPrefixes {
+		if !bytes.HasPrefix(payload, prefix) {
+			continue
+		}
+		data := payload[len(prefix):]
+		var out []byte
+		var err error
+		switch compression {
+		case PayloadCompressionSnappy:
+			out, err = snappy.Decode(nil, data)
+		case PayloadCompressionZstd:
+			if c == nil {
+				return nil, compression, fmt.Errorf("zstd payload can not be decompressed without compressor")
+			}
+			out, err = c.decoder.DecodeAll(data, nil)
+		}
+		if err != nil {
+			return nil, compression, fmt.Errorf("failed to decompress %s payload, %v", compression, err)
+		}
+		return out, compression, nil
+	}
+	return payload, PayloadCompressionNone, nil
+}
+
+// TransformFromStorage implement value.Transformer, payload compressed by a different compression is stale,
+// so, etcd3 rewrite it using current compression when object is updated
+func (c *PayloadCompressor) TransformFromStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, bool, error) {
+	out, compression, err := c.Decompress(data)
+	if err != nil {
+		return nil, false, err
+	}
+	stale := compression != PayloadCompressionNone && compression != c.compression
+	return out, stale, nil
+}
+
+// TransformToStorage implement value.Transformer
+func (c *PayloadCompressor) TransformToStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, error) {
+	return c.Compress(data), nil
+}
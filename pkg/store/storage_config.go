@@ -0,0 +1,379 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+)
+
+type StorageBackend string
+
+const (
+	StorageBackendMemory StorageBackend = "memory"
+	StorageBackendEtcd   StorageBackend = "etcd"
+)
+
+type SlowWatcherPolicy string
+
+const (
+	// SlowWatcherPolicyClose stop watcher when its queue is full, client re-watch from last received revision
+	SlowWatcherPolicyClose SlowWatcherPolicy = "Close"
+	// SlowWatcherPolicyDrop drop new events when watcher queue is full, client could miss events
+	SlowWatcherPolicyDrop SlowWatcherPolicy = "Drop"
+)
+
+type WALSyncPolicy string
+
+const (
+	// WALSyncPolicyAlways fsync wal after every write, a write is durable when it returns
+	WALSyncPolicyAlways WALSyncPolicy = "Always"
+	// WALSyncPolicyInterval group commit, wal is fsynced every WALSyncIntervalMillis if it was written,
+	// writes in last interval could be lost when machine crash
+	WALSyncPolicyInterval WALSyncPolicy = "Interval"
+	// WALSyncPolicyNone leave wal in os page cache, it's only synced when snapshot is taken or store is stopped
+	WALSyncPolicyNone WALSyncPolicy = "None"
+)
+
+type EventSinkType string
+
+const (
+	// EventSinkNats publish events to a nats subject
+	EventSinkNats EventSinkType = "nats"
+	// EventSinkKafkaRest publish events to a kafka topic through kafka rest proxy
+	EventSinkKafkaRest EventSinkType = "kafka-rest"
+)
+
+// EventSinkConfiguration export committed events of a resource to a external message bus,
+// events are queued and published in commit order, events are dropped when queue is full, store never wait for message bus
+type EventSinkConfiguration struct {
+	Type EventSinkType `json:"type"`
+
+	// nats server host:port, or kafka rest proxy url, e.g. http://kafka-rest:8082
+	Address string `json:"address"`
+
+	// nats subject or kafka topic, default fornax.<resource>
+	// +optional
+	Topic string `json:"topic,omitempty"`
+
+	// max number of events queued for publishing, default 10000
+	// +optional
+	QueueSize int `json:"queueSize,omitempty"`
+}
+
+const (
+	DefaultWatchCacheSize            = 20000
+	DefaultWatcherQueueSize          = 1000
+	DefaultWatchEventCacheSize       = 10000
+	DefaultCompactionIntervalSeconds = 60
+	DefaultRetentionSlots            = 10000
+	DefaultWatchLatencyBudgetMillis  = 100
+	DefaultStorageConfigReloadPeriod = 30 * time.Second
+	DefaultEtcdPrefix                = "/registry/fornaxcore"
+	DefaultWALSyncIntervalMillis     = 10
+)
+
+// ResourceStorageConfiguration tune store of a GroupResource,
+// CompactionIntervalSeconds, RetentionSlots and CompactionMemoryThresholdMB are hot reloadable, other fields only take effect when store is created
+type ResourceStorageConfiguration struct {
+	Group    string `json:"group"`
+	Resource string `json:"resource"`
+
+	// which backend store resource, default memory
+	// +optional
+	Backend StorageBackend `json:"backend,omitempty"`
+
+	// initial number of revisioned object slots kept to replay watch events
+	// +optional
+	WatchCacheSize int `json:"watchCacheSize,omitempty"`
+
+	// number of recent watch events kept in ring buffer, watcher reconnecting with a revision still in buffer replay missed events
+	// +optional
+	WatchEventCacheSize int `json:"watchEventCacheSize,omitempty"`
+
+	// max number of events queued for a watcher, store never block on a watcher, a slow watcher is handled by SlowWatcherPolicy
+	// +optional
+	WatcherQueueSize int `json:"watcherQueueSize,omitempty"`
+
+	// what to do when watcher queue is full, Close or Drop, default Close
+	// +optional
+	SlowWatcherPolicy SlowWatcherPolicy `json:"slowWatcherPolicy,omitempty"`
+
+	// expected max time from commit to event delivered to watcher or handled by controller, slower events are logged with a slow path report, default 100
+	// +optional
+	WatchLatencyBudgetMillis int `json:"watchLatencyBudgetMillis,omitempty"`
+
+	// how often revisioned object list is compacted
+	// +optional
+	CompactionIntervalSeconds int `json:"compactionIntervalSeconds,omitempty"`
+
+	// number of empty slots retained after compaction, compaction happen when empty slots are more than twice of retention
+	// +optional
+	RetentionSlots int `json:"retentionSlots,omitempty"`
+
+	// when go heap is larger than this threshold, compaction remove all empty slots beyond retention regardless of empty slot number, 0 disable it
+	// +optional
+	CompactionMemoryThresholdMB int `json:"compactionMemoryThresholdMB,omitempty"`
+
+	// directory to persist memory store wal and snapshot, memory store is not persisted if it's empty
+	// +optional
+	PersistenceDir string `json:"persistenceDir,omitempty"`
+
+	// when memory store wal is fsynced, Always, Interval or None, default Always
+	// +optional
+	WALSyncPolicy WALSyncPolicy `json:"walSyncPolicy,omitempty"`
+
+	// how often wal is fsynced when WALSyncPolicy is Interval, default 10
+	// +optional
+	WALSyncIntervalMillis int `json:"walSyncIntervalMillis,omitempty"`
+
+	// number of memory store shards, objects are placed on shards by consistent hash of namespace in key,
+	// so, writes of unrelated namespaces do not contend on same lock, default 1 which means not sharded,
+	// it must not be changed when persistence is enabled, objects persisted by a shard are only recovered by same shard
+	// +optional
+	Shards int `json:"shards,omitempty"`
+
+	// number of recent revisions of every object kept in memory for audit and debug, 0 disable history
+	// +optional
+	HistoryRevisions int `json:"historyRevisions,omitempty"`
+
+	// export committed events to a message bus for downstream pipelines, e.g. billing of session lifecycle
+	// +optional
+	EventSink *EventSinkConfiguration `json:"eventSink,omitempty"`
+
+	// etcd endpoints, required when backend is etcd
+	// +optional
+	EtcdServers []string `json:"etcdServers,omitempty"`
+
+	// key prefix of resource in etcd, default /registry/fornaxcore
+	// +optional
+	EtcdPrefix string `json:"etcdPrefix,omitempty"`
+
+	// compress object payloads written into memory store wal and snapshot or etcd, snappy or zstd, default none,
+	// payloads written with any compression are still readable after compression is changed
+	// +optional
+	Compression PayloadCompression `json:"compression,omitempty"`
+
+	// payloads smaller than this size are stored uncompressed, default 512
+	// +optional
+	CompressionThresholdBytes int `json:"compressionThresholdBytes,omitempty"`
+}
+
+// FornaxStorageConfiguration is loaded at fornaxcore startup from a json file
+type FornaxStorageConfiguration struct {
+	// file of memory revision high water mark, it's read once at startup, default /var/lib/fornaxcore/memory_revision
+	// +optional
+	RevisionFile string `json:"revisionFile,omitempty"`
+
+	Resources []ResourceStorageConfiguration `json:"resources,omitempty"`
+}
+
+func DefaultResourceStorageConfiguration(groupResource schema.GroupResource) ResourceStorageConfiguration {
+	return ResourceStorageConfiguration{
+		Group:                     groupResource.Group,
+		Resource:                  groupResource.Resource,
+		Backend:                   StorageBackendMemory,
+		WatchCacheSize:            DefaultWatchCacheSize,
+		WatchEventCacheSize:       DefaultWatchEventCacheSize,
+		WatcherQueueSize:          DefaultWatcherQueueSize,
+		SlowWatcherPolicy:         SlowWatcherPolicyClose,
+		WatchLatencyBudgetMillis:  DefaultWatchLatencyBudgetMillis,
+		CompactionIntervalSeconds: DefaultCompactionIntervalSeconds,
+		RetentionSlots:            DefaultRetentionSlots,
+		WALSyncPolicy:             WALSyncPolicyAlways,
+		WALSyncIntervalMillis:     DefaultWALSyncIntervalMillis,
+		Shards:                    1,
+		EtcdPrefix:                DefaultEtcdPrefix,
+		Compression:               PayloadCompressionNone,
+		CompressionThresholdBytes: DefaultCompressionThresholdBytes,
+	}
+}
+
+// ForGroupResource return configuration of a GroupResource, fields not set use default value
+func (c *FornaxStorageConfiguration) ForGroupResource(groupResource schema.GroupResource) ResourceStorageConfiguration {
+	config := DefaultResourceStorageConfiguration(groupResource)
+	if c == nil {
+		return config
+	}
+	for _, v := range c.Resources {
+		if v.Group != groupResource.Group || v.Resource != groupResource.Resource {
+			continue
+		}
+		if len(v.Backend) > 0 {
+			config.Backend = v.Backend
+		}
+		if v.WatchCacheSize > 0 {
+			config.WatchCacheSize = v.WatchCacheSize
+		}
+		if v.WatchEventCacheSize > 0 {
+			config.WatchEventCacheSize = v.WatchEventCacheSize
+		}
+		if v.WatcherQueueSize > 0 {
+			config.WatcherQueueSize = v.WatcherQueueSize
+		}
+		if len(v.SlowWatcherPolicy) > 0 {
+			config.SlowWatcherPolicy = v.SlowWatcherPolicy
+		}
+		if v.WatchLatencyBudgetMillis > 0 {
+			config.WatchLatencyBudgetMillis = v.WatchLatencyBudgetMillis
+		}
+		if v.CompactionIntervalSeconds > 0 {
+			config.CompactionIntervalSeconds = v.CompactionIntervalSeconds
+		}
+		if v.RetentionSlots > 0 {
+			config.RetentionSlots = v.RetentionSlots
+		}
+		if v.CompactionMemoryThresholdMB > 0 {
+			config.CompactionMemoryThresholdMB = v.CompactionMemoryThresholdMB
+		}
+		if len(v.PersistenceDir) > 0 {
+			config.PersistenceDir = v.PersistenceDir
+		}
+		if len(v.WALSyncPolicy) > 0 {
+			config.WALSyncPolicy = v.WALSyncPolicy
+		}
+		if v.WALSyncIntervalMillis > 0 {
+			config.WALSyncIntervalMillis = v.WALSyncIntervalMillis
+		}
+		if v.Shards > 0 {
+			config.Shards = v.Shards
+		}
+		if v.HistoryRevisions > 0 {
+			config.HistoryRevisions = v.HistoryRevisions
+		}
+		if v.EventSink != nil {
+			config.EventSink = v.EventSink
+		}
+		if len(v.EtcdServers) > 0 {
+			config.EtcdServers = v.EtcdServers
+		}
+		if len(v.EtcdPrefix) > 0 {
+			config.EtcdPrefix = v.EtcdPrefix
+		}
+		if len(v.Compression) > 0 {
+			config.Compression = v.Compression
+		}
+		if v.CompressionThresholdBytes > 0 {
+			config.CompressionThresholdBytes = v.CompressionThresholdBytes
+		}
+	}
+	return config
+}
+
+func (c *FornaxStorageConfiguration) Validate() error {
+	for _, v := range c.Resources {
+		if len(v.Resource) == 0 {
+			return fmt.Errorf("resource name is required in storage configuration")
+		}
+		switch v.Backend {
+		case "", StorageBackendMemory:
+		case StorageBackendEtcd:
+			if len(v.EtcdServers) == 0 {
+				return fmt.Errorf("etcd servers are required by etcd backend of resource %s", schema.GroupResource{Group: v.Group, Resource: v.Resource})
+			}
+		default:
+			return fmt.Errorf("unsupported storage backend %s of resource %s", v.Backend, schema.GroupResource{Group: v.Group, Resource: v.Resource})
+		}
+		switch v.SlowWatcherPolicy {
+		case "", SlowWatcherPolicyClose, SlowWatcherPolicyDrop:
+		default:
+			return fmt.Errorf("unsupported slow watcher policy %s of resource %s", v.SlowWatcherPolicy, schema.GroupResource{Group: v.Group, Resource: v.Resource})
+		}
+		switch v.WALSyncPolicy {
+		case "", WALSyncPolicyAlways, WALSyncPolicyInterval, WALSyncPolicyNone:
+		default:
+			return fmt.Errorf("unsupported wal sync policy %s of resource %s", v.WALSyncPolicy, schema.GroupResource{Group: v.Group, Resource: v.Resource})
+		}
+		if v.EventSink != nil {
+			switch v.EventSink.Type {
+			case EventSinkNats, EventSinkKafkaRest:
+			default:
+				return fmt.Errorf("unsupported event sink %s of resource %s", v.EventSink.Type, schema.GroupResource{Group: v.Group, Resource: v.Resource})
+			}
+			if len(v.EventSink.Address) == 0 || v.EventSink.QueueSize < 0 {
+				return fmt.Errorf("event sink of resource %s require a address and a non negative queue size", schema.GroupResource{Group: v.Group, Resource: v.Resource})
+			}
+		}
+		switch v.Compression {
+		case "", PayloadCompressionNone, PayloadCompressionSnappy, PayloadCompressionZstd:
+		default:
+			return fmt.Errorf("unsupported payload compression %s of resource %s", v.Compression, schema.GroupResource{Group: v.Group, Resource: v.Resource})
+		}
+		if v.WatchCacheSize < 0 || v.WatchEventCacheSize < 0 || v.WatcherQueueSize < 0 || v.CompactionIntervalSeconds < 0 || v.RetentionSlots < 0 || v.CompactionMemoryThresholdMB < 0 || v.Shards < 0 || v.HistoryRevisions < 0 || v.WatchLatencyBudgetMillis < 0 || v.CompressionThresholdBytes < 0 || v.WALSyncIntervalMillis < 0 {
+			return fmt.Errorf("storage configuration of resource %s must not be negative", schema.GroupResource{Group: v.Group, Resource: v.Resource})
+		}
+	}
+	return nil
+}
+
+// LoadFornaxStorageConfiguration read configuration from a json file, return a empty configuration if file does not exist
+func LoadFornaxStorageConfiguration(file string) (*FornaxStorageConfiguration, error) {
+	config := &FornaxStorageConfiguration{}
+	if len(file) == 0 {
+		return config, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// WatchFornaxStorageConfiguration reload configuration file periodically and call onChange when it's changed,
+// invalid configuration is ignored and last good configuration is kept
+func WatchFornaxStorageConfiguration(ctx context.Context, file string, current *FornaxStorageConfiguration, onChange func(*FornaxStorageConfiguration)) {
+	if len(file) == 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(DefaultStorageConfigReloadPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				config, err := LoadFornaxStorageConfiguration(file)
+				if err != nil {
+					klog.ErrorS(err, "Failed to reload storage configuration, keep current configuration", "file", file)
+					continue
+				}
+				if !reflect.DeepEqual(config, current) {
+					klog.InfoS("Storage configuration changed", "file", file)
+					current = config
+					onChange(config)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// WatchLatencyStageDelivery is from store commit to event put into watcher result channel
+	WatchLatencyStageDelivery = "delivery"
+	// WatchLatencyStageHandling is from store commit to event handled by controller
+	WatchLatencyStageHandling = "handling"
+
+	// slow path of a resource and stage is reported at most once in this interval, metric count all of them
+	slowPathReportInterval = time.Second
+)
+
+var (
+	watchLatency = metrics.NewSummaryVec(
+		&metrics.SummaryOpts{
+			Subsystem:      "fornax_watch",
+			Name:           "latency_seconds",
+			Help:           "Latency from store commit to event delivered to watcher or handled by controller, stage is delivery or handling",
+			Objectives:     map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+			MaxAge:         10 * time.Minute,
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource", "stage"},
+	)
+	watchLatencyBudgetExceeded = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_watch",
+			Name:           "latency_budget_exceeded_total",
+			Help:           "Number of events whose watch latency exceeded budget of resource",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource", "stage"},
+	)
+
+	watchLatencyBudgets = sync.Map{}
+	lastSlowPathReports = sync.Map{}
+)
+
+func init() {
+	legacyregistry.MustRegister(watchLatency, watchLatencyBudgetExceeded)
+}
+
+// SlowPathReport describe a event which took longer than watch latency budget to deliver or handle
+type SlowPathReport struct {
+	Resource string
+	Stage    string
+	Key      string
+	Revision uint64
+	Latency  time.Duration
+	// controller which handled event, only set in handling stage
+	Handler string
+	// events queued for watcher and not consumed yet
+	WatcherQueueDepth int
+	// events put into watcher result channel and not received by consumer yet
+	WatcherChannelDepth int
+	// time waited for revision lock when event is committed
+	CommitLockWait time.Duration
+	// time waited for watchers lock when last events were sent to watchers
+	SendLockWait time.Duration
+}
+
+// SetWatchLatencyBudget set budget of a resource, events taking longer are reported as slow path
+func SetWatchLatencyBudget(resource string, budget time.Duration) {
+	watchLatencyBudgets.Store(resource, budget)
+}
+
+func WatchLatencyBudget(resource string) time.Duration {
+	if v, found := watchLatencyBudgets.Load(resource); found {
+		return v.(time.Duration)
+	}
+	return DefaultWatchLatencyBudgetMillis * time.Millisecond
+}
+
+// ObserveWatchLatency record latency of a event and log a slow path report if it exceed budget of resource
+func ObserveWatchLatency(report *SlowPathReport) {
+	watchLatency.WithLabelValues(report.Resource, report.Stage).Observe(report.Latency.Seconds())
+	budget := WatchLatencyBudget(report.Resource)
+	if budget <= 0 || report.Latency <= budget {
+		return
+	}
+	watchLatencyBudgetExceeded.WithLabelValues(report.Resource, report.Stage).Inc()
+
+	now := time.Now()
+	reportKey := report.Resource + "/" + report.Stage
+	if last, found := lastSlowPathReports.Load(reportKey); found && now.Sub(last.(time.Time)) < slowPathReportInterval {
+		return
+	}
+	lastSlowPathReports.Store(reportKey, now)
+	klog.InfoS("Watch latency budget exceeded",
+		"resource", report.Resource,
+		"stage", report.Stage,
+		"key", report.Key,
+		"revision", report.Revision,
+		"latency", report.Latency,
+		"budget", budget,
+		"handler", report.Handler,
+		"watcherQueueDepth", report.WatcherQueueDepth,
+		"watcherChannelDepth", report.WatcherChannelDepth,
+		"commitLockWait", report.CommitLockWait,
+		"sendLockWait", report.SendLockWait)
+}
+
+// ObserveWatchEventHandled is called by controller after a watch event is handled, events without commit time are ignored,
+// e.g. bookmarks and events replayed from cache
+func ObserveWatchEventHandled(resource, handler string, event WatchEventWithOldObj) {
+	if event.CommitTime.IsZero() {
+		return
+	}
+	report := &SlowPathReport{
+		Resource: resource,
+		Stage:    WatchLatencyStageHandling,
+		Latency:  time.Since(event.CommitTime),
+		Handler:  handler,
+	}
+	if accessor, err := meta.Accessor(event.Object); err == nil {
+		report.Key = accessor.GetNamespace() + "/" + accessor.GetName()
+		report.Revision, _ = ParseResourceVersion(accessor.GetResourceVersion())
+	}
+	ObserveWatchLatency(report)
+}
@@ -38,11 +38,13 @@ func SessionIsOpen(session *fornaxv1.ApplicationSession) bool {
 	return session.Status.SessionStatus != fornaxv1.SessionStatusUnspecified &&
 		session.Status.SessionStatus != fornaxv1.SessionStatusPending &&
 		session.Status.SessionStatus != fornaxv1.SessionStatusClosed &&
-		session.Status.SessionStatus != fornaxv1.SessionStatusTimeout
+		session.Status.SessionStatus != fornaxv1.SessionStatusTimeout &&
+		session.Status.SessionStatus != fornaxv1.SessionStatusFailed
 }
 
+// failed session is also closed on node
 func SessionIsClosed(session *fornaxv1.ApplicationSession) bool {
-	return session.Status.SessionStatus == fornaxv1.SessionStatusClosed
+	return session.Status.SessionStatus == fornaxv1.SessionStatusClosed || session.Status.SessionStatus == fornaxv1.SessionStatusFailed
 }
 
 func SessionIsClosing(session *fornaxv1.ApplicationSession) bool {
@@ -58,7 +60,7 @@ func SessionIsStarting(session *fornaxv1.ApplicationSession) bool {
 }
 
 func SessionInTerminalState(session *fornaxv1.ApplicationSession) bool {
-	return session.Status.SessionStatus == fornaxv1.SessionStatusClosed || session.Status.SessionStatus == fornaxv1.SessionStatusTimeout
+	return session.Status.SessionStatus == fornaxv1.SessionStatusClosed || session.Status.SessionStatus == fornaxv1.SessionStatusTimeout || session.Status.SessionStatus == fornaxv1.SessionStatusFailed
 }
 
 func SessionInGracePeriod(session *fornaxv1.ApplicationSession) bool {
@@ -0,0 +1,69 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"net"
+	"sort"
+	"strings"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// IPFamilyOf return IPv4 or IPv6 of a ip address, empty if it's not a valid ip
+func IPFamilyOf(ip string) v1.IPFamily {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ""
+	}
+	if parsed.To4() != nil {
+		return v1.IPv4Protocol
+	}
+	return v1.IPv6Protocol
+}
+
+// ParseIPFamily accept IPv4/IPv6 in any case, and 4/6, empty if it's not a ip family
+func ParseIPFamily(family string) v1.IPFamily {
+	switch strings.ToLower(family) {
+	case "ipv4", "4":
+		return v1.IPv4Protocol
+	case "ipv6", "6":
+		return v1.IPv6Protocol
+	}
+	return ""
+}
+
+// AccessEndPointFamily return ip family of a endpoint, endpoints created before dual stack do not have family set
+func AccessEndPointFamily(endpoint fornaxv1.AccessEndPoint) v1.IPFamily {
+	if len(endpoint.IPFamily) > 0 {
+		return endpoint.IPFamily
+	}
+	return IPFamilyOf(endpoint.IPAddress)
+}
+
+// PreferredAccessEndPoints return a copy of endpoints, endpoints of preferred family come first, order is kept otherwise
+func PreferredAccessEndPoints(endpoints []fornaxv1.AccessEndPoint, family v1.IPFamily) []fornaxv1.AccessEndPoint {
+	sorted := append([]fornaxv1.AccessEndPoint{}, endpoints...)
+	if len(family) == 0 {
+		return sorted
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return AccessEndPointFamily(sorted[i]) == family && AccessEndPointFamily(sorted[j]) != family
+	})
+	return sorted
+}
@@ -20,6 +20,7 @@ import (
 	"strconv"
 	"time"
 
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -66,6 +67,15 @@ func RemoveFinalizer(ometa *metav1.ObjectMeta, finalizer string) {
 	ometa.Finalizers = finalizers
 }
 
+// RequestId return request id annotation of object, it's empty if object does not have one
+func RequestId(obj interface{}) string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return ""
+	}
+	return accessor.GetAnnotations()[fornaxv1.AnnotationFornaxCoreRequestId]
+}
+
 func Name(obj interface{}) string {
 	name, _ := cache.MetaNamespaceKeyFunc(obj)
 	return name
@@ -17,9 +17,24 @@ limitations under the License.
 package util
 
 import (
+	"strconv"
+
+	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
 	v1 "k8s.io/api/core/v1"
 )
 
+// NodeHourlyCost return price of node in label, spot price is used if node is a spot instance, false if node has no valid price label
+func NodeHourlyCost(v1node *v1.Node) (float64, bool) {
+	for _, k := range []string{fornaxv1.LabelFornaxCoreNodeSpotPrice, fornaxv1.LabelFornaxCoreNodeInstanceCost} {
+		if v, found := v1node.GetLabels()[k]; found {
+			if price, err := strconv.ParseFloat(v, 64); err == nil && price >= 0 {
+				return price, true
+			}
+		}
+	}
+	return 0, false
+}
+
 func MergeNodeStatus(oldcopy *v1.Node, newnode *v1.Node) {
 	// keep existing node spec, and use new node status from node agent
 	// set node in unschedulable state since node start registration, wait for node
@@ -35,6 +50,16 @@ func IsNodeCondtionReady(v1node *v1.Node) bool {
 	return false
 }
 
+// IsNodeUnderMemoryPressure tell if node agent reported memory pressure, such a node does not take new pods and sessions
+func IsNodeUnderMemoryPressure(v1node *v1.Node) bool {
+	for _, v := range v1node.Status.Conditions {
+		if v.Type == v1.NodeMemoryPressure && v.Status == v1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
 func IsNodeRunning(v1node *v1.Node) bool {
 	return v1node.Status.Phase == v1.NodeRunning
 }
@@ -17,14 +17,17 @@ limitations under the License.
 package util
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
 	fornaxv1 "centaurusinfra.io/fornax-serverless/pkg/apis/core/v1"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/cache"
 )
@@ -165,6 +168,12 @@ func GetPodResourceList(v1pod *v1.Pod) *v1.ResourceList {
 			pods.Add(*v.Resources.Requests.Pods())
 			resourceList[v1.ResourcePods] = *pods
 		}
+
+		if gpus := ContainerGPUs(&v); gpus > 0 {
+			gpu := resourceList[fornaxv1.ResourceNvidiaGPU]
+			gpu.Add(*resource.NewQuantity(gpus, resource.DecimalSI))
+			resourceList[fornaxv1.ResourceNvidiaGPU] = gpu
+		}
 	}
 
 	return &resourceList
@@ -230,6 +239,110 @@ func PodHasHibernateAnnotation(pod *v1.Pod) bool {
 	return false
 }
 
+const (
+	DefaultContainerRestartInitialBackoff = 10 * time.Second
+	DefaultContainerRestartMaxBackoff     = 300 * time.Second
+)
+
+// PodRestartBackoff return container restart backoff annotated on pod, a missing or invalid value use default
+func PodRestartBackoff(pod *v1.Pod) (initial, max time.Duration) {
+	initial, max = DefaultContainerRestartInitialBackoff, DefaultContainerRestartMaxBackoff
+	value, found := pod.GetAnnotations()[fornaxv1.AnnotationFornaxCoreRestartBackoff]
+	if !found {
+		return initial, max
+	}
+	parts := strings.Split(value, ",")
+	if len(parts) != 2 {
+		return initial, max
+	}
+	if seconds, err := strconv.Atoi(parts[0]); err == nil && seconds > 0 {
+		initial = time.Duration(seconds) * time.Second
+	}
+	if seconds, err := strconv.Atoi(parts[1]); err == nil && seconds > 0 {
+		max = time.Duration(seconds) * time.Second
+	}
+	if max < initial {
+		max = initial
+	}
+	return initial, max
+}
+
+// PodPidsLimit return pids limit annotated on pod, false if not annotated or invalid
+func PodPidsLimit(pod *v1.Pod) (int64, bool) {
+	value, found := pod.GetAnnotations()[fornaxv1.AnnotationFornaxCorePodPidsLimit]
+	if !found {
+		return 0, false
+	}
+	limit, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || limit <= 0 {
+		return 0, false
+	}
+	return limit, true
+}
+
+// PodOOMScoreAdj return oom_score_adj annotated on pod, false if not annotated or out of range
+func PodOOMScoreAdj(pod *v1.Pod) (int64, bool) {
+	value, found := pod.GetAnnotations()[fornaxv1.AnnotationFornaxCoreOOMScoreAdj]
+	if !found {
+		return 0, false
+	}
+	score, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || score < -1000 || score > 1000 {
+		return 0, false
+	}
+	return score, true
+}
+
+// PodWarmUp return warm up annotated on pod, nil if not annotated or invalid
+func PodWarmUp(pod *v1.Pod) *fornaxv1.ApplicationWarmUp {
+	value, found := pod.GetAnnotations()[fornaxv1.AnnotationFornaxCoreWarmUp]
+	if !found {
+		return nil
+	}
+	warmUp := &fornaxv1.ApplicationWarmUp{}
+	if err := json.Unmarshal([]byte(value), warmUp); err != nil {
+		return nil
+	}
+	return warmUp
+}
+
+// PodNetworkPolicy return network policy annotated on pod, nil if not annotated or invalid
+func PodNetworkPolicy(pod *v1.Pod) *fornaxv1.ApplicationNetworkPolicy {
+	value, found := pod.GetAnnotations()[fornaxv1.AnnotationFornaxCoreNetworkPolicy]
+	if !found {
+		return nil
+	}
+	policy := &fornaxv1.ApplicationNetworkPolicy{}
+	if err := json.Unmarshal([]byte(value), policy); err != nil {
+		return nil
+	}
+	return policy
+}
+
+// ContainerGPUs return number of gpus requested by container, limit is used if request is not set, like kubernetes extended resources
+func ContainerGPUs(container *v1.Container) int64 {
+	if quantity, found := container.Resources.Requests[fornaxv1.ResourceNvidiaGPU]; found {
+		return quantity.Value()
+	}
+	if quantity, found := container.Resources.Limits[fornaxv1.ResourceNvidiaGPU]; found {
+		return quantity.Value()
+	}
+	return 0
+}
+
+// PodGPUDevices return gpu device ids of each container annotated on pod by node agent, nil if not annotated or invalid
+func PodGPUDevices(pod *v1.Pod) map[string][]string {
+	value, found := pod.GetAnnotations()[fornaxv1.AnnotationFornaxCoreGPUDevices]
+	if !found {
+		return nil
+	}
+	devices := map[string][]string{}
+	if err := json.Unmarshal([]byte(value), &devices); err != nil {
+		return nil
+	}
+	return devices
+}
+
 func PodHasSessionServiceAnnotation(pod *v1.Pod) bool {
 	if _, found := pod.GetAnnotations()[fornaxv1.AnnotationFornaxCoreSessionServicePod]; found {
 		return true
@@ -237,6 +350,14 @@ func PodHasSessionServiceAnnotation(pod *v1.Pod) bool {
 	return false
 }
 
+// PodSessionRuntime return session runtime annotated on pod, grpc if not annotated
+func PodSessionRuntime(pod *v1.Pod) fornaxv1.SessionRuntime {
+	if runtime, found := pod.GetAnnotations()[fornaxv1.AnnotationFornaxCoreSessionRuntime]; found && len(runtime) > 0 {
+		return fornaxv1.SessionRuntime(runtime)
+	}
+	return fornaxv1.SessionRuntimeGrpc
+}
+
 func GetPodSessionNames(pod *v1.Pod) []string {
 	if label, found := pod.GetLabels()[fornaxv1.LabelFornaxCoreApplicationSession]; found {
 		return strings.Split(label, ",")
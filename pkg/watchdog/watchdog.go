@@ -0,0 +1,330 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package watchdog periodically sample goroutine count, mutex contention and how long writers are blocked on hot locks,
+// it warn before a process stall under load and optionally run self heal actions, e.g. shed watchers of a store
+package watchdog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultIntervalSeconds      = 10
+	DefaultMaxGoroutines        = 100000
+	DefaultMaxLockWaitMillis    = 5000
+	DefaultMutexProfileFraction = 100
+
+	// number of most contended call sites logged with a warning
+	topContendedSites = 3
+
+	checkGoroutines = "goroutines"
+	checkLockWait   = "lock_wait"
+)
+
+var (
+	goroutineCount = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      "fornax_watchdog",
+			Name:           "goroutines",
+			Help:           "Number of goroutines sampled by watchdog",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"component"},
+	)
+	lockBlockedSeconds = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      "fornax_watchdog",
+			Name:           "lock_blocked_seconds",
+			Help:           "How long writers have been blocked on a lock without any writer acquiring it",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"component", "lock"},
+	)
+	mutexContentions = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_watchdog",
+			Name:           "mutex_contentions_total",
+			Help:           "Number of contended mutex acquisitions sampled by mutex profile",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"component"},
+	)
+	warnings = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_watchdog",
+			Name:           "warnings_total",
+			Help:           "Number of watchdog warnings, check is goroutines or lock_wait",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"component", "check"},
+	)
+	selfHeals = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "fornax_watchdog",
+			Name:           "self_heals_total",
+			Help:           "Number of self heal actions run by watchdog",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"component", "action"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(goroutineCount, lockBlockedSeconds, mutexContentions, warnings, selfHeals)
+}
+
+var (
+	InvalidWatchdogConfigurationError = errors.New("watchdog interval and thresholds must not be negative")
+)
+
+// Configuration of watchdog, zero threshold disable its check
+type Configuration struct {
+	IntervalSeconds int `json:"intervalSeconds,omitempty"`
+	// warn when goroutines are more than it
+	MaxGoroutines int `json:"maxGoroutines,omitempty"`
+	// warn when writers are blocked on a lock longer than it
+	MaxLockWaitMillis int `json:"maxLockWaitMillis,omitempty"`
+	// 1/n of mutex contention events are sampled, see runtime.SetMutexProfileFraction, 0 disable mutex profile
+	MutexProfileFraction int `json:"mutexProfileFraction,omitempty"`
+	// run self heal actions when a check warn
+	SelfHeal bool `json:"selfHeal,omitempty"`
+}
+
+func DefaultConfiguration() *Configuration {
+	return &Configuration{
+		IntervalSeconds:      DefaultIntervalSeconds,
+		MaxGoroutines:        DefaultMaxGoroutines,
+		MaxLockWaitMillis:    DefaultMaxLockWaitMillis,
+		MutexProfileFraction: DefaultMutexProfileFraction,
+		SelfHeal:             false,
+	}
+}
+
+// LoadConfiguration read watchdog configuration from a json file, default configuration is returned if file does not exist,
+// fields missing in file keep their default value
+func LoadConfiguration(file string) (*Configuration, error) {
+	config := DefaultConfiguration()
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	if config.IntervalSeconds < 0 || config.MaxGoroutines < 0 || config.MaxLockWaitMillis < 0 || config.MutexProfileFraction < 0 {
+		return nil, InvalidWatchdogConfigurationError
+	}
+	return config, nil
+}
+
+// LockProbe report how long writers have been blocked on a lock, zero if no writer is waiting
+type LockProbe interface {
+	Name() string
+	BlockedFor() time.Duration
+}
+
+// HealAction is run when a check warn and self heal is enabled, it return a short description of what it did, empty if nothing
+type HealAction func(reason string) string
+
+type healer struct {
+	name   string
+	action HealAction
+}
+
+// contendedSite is a call site in mutex profile and its contentions since last sample
+type contendedSite struct {
+	function    string
+	contentions int64
+	cycles      int64
+}
+
+type Watchdog struct {
+	component string
+	config    Configuration
+	probes    []LockProbe
+	// sources of probes of locks created at runtime, they are listed on every sample
+	probeSources []func() []LockProbe
+	healers      []healer
+	// mutex profile counts of last sample, keyed by function
+	lastMutexCounts map[string][2]int64
+}
+
+func New(component string, config *Configuration) *Watchdog {
+	wd := &Watchdog{
+		component:       component,
+		config:          *config,
+		probes:          []LockProbe{},
+		healers:         []healer{},
+		lastMutexCounts: map[string][2]int64{},
+	}
+	if wd.config.IntervalSeconds <= 0 {
+		wd.config.IntervalSeconds = DefaultIntervalSeconds
+	}
+	return wd
+}
+
+// AddLockProbe add a lock to check, it must be called before Run
+func (wd *Watchdog) AddLockProbe(probe LockProbe) {
+	wd.probes = append(wd.probes, probe)
+}
+
+// AddLockProbeSource add a function listing current locks to check, it's called on every sample,
+// so, locks created after Run are also checked, it must be called before Run
+func (wd *Watchdog) AddLockProbeSource(source func() []LockProbe) {
+	wd.probeSources = append(wd.probeSources, source)
+}
+
+// LockProbes return added probes and probes currently listed by probe sources
+func (wd *Watchdog) LockProbes() []LockProbe {
+	probes := append([]LockProbe{}, wd.probes...)
+	for _, source := range wd.probeSources {
+		probes = append(probes, source()...)
+	}
+	return probes
+}
+
+// AddHealAction add a self heal action, it must be called before Run
+func (wd *Watchdog) AddHealAction(name string, action HealAction) {
+	wd.healers = append(wd.healers, healer{name: name, action: action})
+}
+
+// Run sample until context is done
+func (wd *Watchdog) Run(ctx context.Context) {
+	klog.InfoS("Starting watchdog", "component", wd.component, "config", wd.config, "locks", len(wd.probes), "lockSources", len(wd.probeSources), "healActions", len(wd.healers))
+	if wd.config.MutexProfileFraction > 0 {
+		runtime.SetMutexProfileFraction(wd.config.MutexProfileFraction)
+	}
+	ticker := time.NewTicker(time.Duration(wd.config.IntervalSeconds) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			wd.check()
+		}
+	}
+}
+
+func (wd *Watchdog) check() {
+	reasons := []string{}
+	goroutines := runtime.NumGoroutine()
+	goroutineCount.WithLabelValues(wd.component).Set(float64(goroutines))
+	if wd.config.MaxGoroutines > 0 && goroutines > wd.config.MaxGoroutines {
+		warnings.WithLabelValues(wd.component, checkGoroutines).Inc()
+		reasons = append(reasons, checkGoroutines)
+		klog.InfoS("Watchdog warning, too many goroutines", "component", wd.component, "goroutines", goroutines, "max", wd.config.MaxGoroutines)
+	}
+
+	maxLockWait := time.Duration(wd.config.MaxLockWaitMillis) * time.Millisecond
+	for _, v := range wd.LockProbes() {
+		blocked := v.BlockedFor()
+		lockBlockedSeconds.WithLabelValues(wd.component, v.Name()).Set(blocked.Seconds())
+		if maxLockWait > 0 && blocked > maxLockWait {
+			warnings.WithLabelValues(wd.component, checkLockWait).Inc()
+			reasons = append(reasons, checkLockWait)
+			klog.InfoS("Watchdog warning, writers blocked on lock", "component", wd.component, "lock", v.Name(), "blockedFor", blocked, "max", maxLockWait)
+		}
+	}
+
+	sites := wd.sampleMutexProfile()
+	if len(reasons) == 0 {
+		return
+	}
+	for _, v := range sites {
+		klog.InfoS("Watchdog contended mutex", "component", wd.component, "function", v.function, "contentions", v.contentions, "cycles", v.cycles)
+	}
+	if wd.config.SelfHeal {
+		wd.heal(reasons[0])
+	}
+}
+
+func (wd *Watchdog) heal(reason string) {
+	for _, v := range wd.healers {
+		if result := v.action(reason); len(result) > 0 {
+			selfHeals.WithLabelValues(wd.component, v.name).Inc()
+			klog.InfoS("Watchdog self heal", "component", wd.component, "action", v.name, "reason", reason, "result", result)
+		}
+	}
+}
+
+// sampleMutexProfile return most contended call sites since last sample
+func (wd *Watchdog) sampleMutexProfile() []contendedSite {
+	if wd.config.MutexProfileFraction <= 0 {
+		return nil
+	}
+	records := make([]runtime.BlockProfileRecord, 64)
+	for {
+		n, ok := runtime.MutexProfile(records)
+		if ok {
+			records = records[:n]
+			break
+		}
+		records = make([]runtime.BlockProfileRecord, n+16)
+	}
+
+	counts := map[string][2]int64{}
+	for _, r := range records {
+		function := contendedFunction(r.Stack())
+		counts[function] = [2]int64{counts[function][0] + r.Count, counts[function][1] + r.Cycles}
+	}
+	top := []contendedSite{}
+	total := int64(0)
+	for function, c := range counts {
+		last := wd.lastMutexCounts[function]
+		if contentions, cycles := c[0]-last[0], c[1]-last[1]; contentions > 0 {
+			total += contentions
+			top = append(top, contendedSite{function: function, contentions: contentions, cycles: cycles})
+		}
+	}
+	wd.lastMutexCounts = counts
+	mutexContentions.WithLabelValues(wd.component).Add(float64(total))
+
+	sort.Slice(top, func(i, j int) bool { return top[i].cycles > top[j].cycles })
+	if len(top) > topContendedSites {
+		top = top[:topContendedSites]
+	}
+	return top
+}
+
+// contendedFunction return first function outside of runtime and sync in a mutex profile stack, it's where the lock is released
+func contendedFunction(stack []uintptr) string {
+	frames := runtime.CallersFrames(stack)
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "runtime.") && !strings.HasPrefix(frame.Function, "sync.") && len(frame.Function) > 0 {
+			return frame.Function
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}